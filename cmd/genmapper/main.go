@@ -1,28 +1,92 @@
+// Command genmapper generates the ToEntity, MustToEntity and FromEntity mappers of DTO structs.
+//
+// Usage:
+//
+//	genmapper [-output mapper_gen.go] [-package dto] [-config genmapper.json] [-tests] [-check] <dto-file>...
+//
+// It is meant to run through a //go:generate directive in the DTO package, where the output
+// is written next to the DTO files. With -check the file given by -output is compared with
+// the generated mappers instead, and the command exits with status 1 when it is out of date.
+// The -config file declares the converters used by mapper:"conv=name" tags. With -tests the
+// round-trip and fuzz tests of the mappers are also written to the _test.go file of -output.
 package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // FieldMapping описывает как мапить поле
 type FieldMapping struct {
 	FieldName    string
+	JSONName     string // Name of the field in errors, from its json tag
 	DTOType      string
 	EntityName   string
 	EntityType   string
-	IsTimeField  bool
-	IsVOField    bool
-	IsStringCopy bool
-	IsBoolCopy   bool
-	IsIDField    bool
-	VOPackage    string
+	IsTimeField  bool       // mapper:"time" — обязательная метка времени RFC3339 ↔ time.Time
+	IsVOField    bool       // mapper:"vo=Name" — строка ↔ valueobject.Name
+	IsRawVO      bool       // mapper:"vo=Name,raw" — преобразование без валидации
+	Sample       string     // mapper:"vo=Name,sample=value" — корректное значение для тестов -tests
+	IsCopy       bool       // поле без тега скалярного типа копируется как есть
+	IsIDField    bool       // mapper:"id" — идентификатор сущности ↔ valueobject.<Entity>ID
+	IsOptionalAt bool       // mapper:"time,optional" — необязательная метка времени ↔ *time.Time
+	Converter    *Converter // mapper:"json" или mapper:"conv=name" — преобразование функциями
+	NestedDTO    string     // mapper:"dto" — вложенный DTO, например "UserDTO"
+	IsNestedList bool       // mapper:"dto" для среза []*XDTO ↔ []*entity.X
+}
+
+// Converter describes the functions converting a field, declared in the -config file:
+//
+//	{
+//	  "converters": {
+//	    "duration": {
+//	      "dto_type": "string",
+//	      "entity_type": "time.Duration",
+//	      "to_entity": "time.ParseDuration",
+//	      "from_entity": "FormatDuration",
+//	      "sample": "\"1m30s\"",
+//	      "imports": ["time"]
+//	    }
+//	  }
+//	}
+//
+// ToEntity has the signature func(DTOType) (EntityType, error) and FromEntity the signature
+// func(EntityType) DTOType, or func(EntityType) (DTOType, error) with FromEntityErr, in which
+// case the error is discarded.
+type Converter struct {
+	DTOType       string   `json:"dto_type"`    // Type of the DTO fields the converter accepts; empty for any
+	EntityType    string   `json:"entity_type"` // Type of the entity field, for documentation
+	ToEntity      string   `json:"to_entity"`
+	FromEntity    string   `json:"from_entity"`
+	FromEntityErr bool     `json:"from_entity_error"`
+	Sample        string   `json:"sample"`  // Go expression of a valid DTO value, for the tests generated with -tests
+	Imports       []string `json:"imports"` // Import paths of the packages of the functions
+}
+
+// Config is the -config file of the generator
+type Config struct {
+	Converters map[string]Converter `json:"converters"`
+}
+
+// jsonConverters convert the types supported by mapper:"json" to and from JSON columns
+var jsonConverters = map[string]Converter{
+	"[]string":               {EntityType: "string", ToEntity: "SliceToString", FromEntity: "StringToSlice", FromEntityErr: true, Sample: "[]string{genmapperString(r)}"},
+	"map[string]interface{}": {EntityType: "string", ToEntity: "MapToString", FromEntity: "StringToMap", FromEntityErr: true, Sample: "map[string]interface{}{genmapperString(r): genmapperString(r)}"},
+	"map[string]any":         {EntityType: "string", ToEntity: "MapToString", FromEntity: "StringToMap", FromEntityErr: true, Sample: "map[string]any{genmapperString(r): genmapperString(r)}"},
 }
 
 // MapperConfig содержит конфигурацию для генерации маппера
@@ -34,66 +98,183 @@ type MapperConfig struct {
 
 // MapperDefinition описывает один маппер DTO <-> Entity
 type MapperDefinition struct {
-	DTOName      string
-	EntityName   string
-	EntityPkg    string
-	Fields       []FieldMapping
-	HasUpdatedAt bool
+	DTOName    string
+	EntityName string
+	EntityPkg  string
+	Fields     []FieldMapping
+}
+
+// Import paths of the generated code
+const (
+	entityImport      = "github.com/atumaikin/nexflow/internal/domain/entity"
+	valueobjectImport = "github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// copyTypes are the DTO field types copied to the entity without a mapper tag, either as is
+// or as pointers, e.g. *time.Time
+var copyTypes = map[string]bool{
+	"string": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+	"time.Time": true,
 }
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "Usage: genmapper <dto-file>...")
+	output := flag.String("output", "", "write the mappers to this file (default mapper_gen.go next to the DTO files)")
+	pkg := flag.String("package", "", "package of the generated file (default the package of the DTO files)")
+	configFile := flag.String("config", "", "JSON file declaring the converters of mapper:\"conv=name\" tags")
+	tests := flag.Bool("tests", false, "also write round-trip and fuzz tests of the mappers to the _test.go file of -output")
+	check := flag.Bool("check", false, "fail if the files given by -output are not up to date instead of writing them")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: genmapper [-output file] [-package name] [-config file] [-tests] [-check] <dto-file>...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dtoFiles := flag.Args()
+	if len(dtoFiles) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = filepath.Join(filepath.Dir(dtoFiles[0]), "mapper_gen.go")
+	}
+	if *pkg == "" {
+		// Set by go generate
+		*pkg = os.Getenv("GOPACKAGE")
+	}
+
+	var converters map[string]Converter
+	if *configFile != "" {
+		var err error
+		if converters, err = loadConfig(*configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	code, testCode, err := generate(dtoFiles, *pkg, converters, *tests)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	dtoFiles := os.Args[1:]
-	fset := token.NewFileSet()
+	files := map[string][]byte{*output: code}
+	paths := []string{*output}
+	if *tests {
+		testOutput := strings.TrimSuffix(*output, ".go") + "_test.go"
+		files[testOutput] = testCode
+		paths = append(paths, testOutput)
+	}
 
-	config := MapperConfig{
-		PackageName: "dto",
-		Imports: []string{
-			"time",
-			"github.com/atumaikin/nexflow/internal/domain/entity",
-			"github.com/atumaikin/nexflow/internal/domain/valueobject",
-		},
+	for _, path := range paths {
+		if *check {
+			current, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			if !bytes.Equal(current, files[path]) {
+				fmt.Fprintf(os.Stderr, "%s is out of date, run: go generate ./%s\n", path, filepath.ToSlash(filepath.Dir(path)))
+				os.Exit(1)
+			}
+			continue
+		}
+
+		if err := os.WriteFile(path, files[path], 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Generated %s\n", path)
+	}
+}
+
+// loadConfig reads the converters declared in a -config file
+func loadConfig(path string) (map[string]Converter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
 	}
 
+	var errs []error
+	for name, converter := range config.Converters {
+		if name == "" || strings.ContainsAny(name, ", ") {
+			errs = append(errs, fmt.Errorf("invalid converter name %q", name))
+		}
+		if converter.ToEntity == "" || converter.FromEntity == "" {
+			errs = append(errs, fmt.Errorf("converter %q: to_entity and from_entity are required", name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("config %s: %w", path, errors.Join(errs...))
+	}
+	return config.Converters, nil
+}
+
+// generate returns the formatted mappers of the DTO structs of the files and, with tests, their
+// formatted tests. Without a package name, the package of the files is used.
+func generate(dtoFiles []string, pkg string, converters map[string]Converter, tests bool) (code, testCode []byte, err error) {
+	fset := token.NewFileSet()
+	config := MapperConfig{PackageName: pkg}
+
 	// Parse all DTO files
+	var errs []error
 	for _, dtoFile := range dtoFiles {
 		node, err := parser.ParseFile(fset, dtoFile, nil, parser.ParseComments)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing file %s: %v\n", dtoFile, err)
+			errs = append(errs, fmt.Errorf("parsing file %s: %w", dtoFile, err))
+			continue
+		}
+		if config.PackageName == "" {
+			config.PackageName = node.Name.Name
+		} else if pkg == "" && node.Name.Name != config.PackageName {
+			errs = append(errs, fmt.Errorf("%s: package %s differs from package %s; set -package", dtoFile, node.Name.Name, config.PackageName))
 			continue
 		}
 
 		// Find all DTO struct types in this file
-		mappers := findDTOStructs(node)
+		mappers, err := findDTOStructs(node, converters)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dtoFile, err))
+			continue
+		}
 		config.Mappers = append(config.Mappers, mappers...)
 	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	config.Imports = requiredImports(config.Mappers)
 
-	// Generate code
-	code := generateMapperCode(config)
-
-	// Format code
-	formatted, err := format.Source([]byte(code))
+	// Generate and format code
+	code, err = format.Source([]byte(generateMapperCode(config)))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting code: %v\n", err)
-		os.Exit(1)
+		return nil, nil, fmt.Errorf("formatting code: %w", err)
 	}
-
-	// Write to mapper_gen.go
-	outputFile := "mapper_gen.go"
-	if err := os.WriteFile(outputFile, formatted, 0644); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
-		os.Exit(1)
+	if !tests {
+		return code, nil, nil
 	}
 
-	fmt.Printf("Generated %s\n", outputFile)
+	source, err := generateTestCode(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	testCode, err = format.Source([]byte(source))
+	if err != nil {
+		return nil, nil, fmt.Errorf("formatting tests: %w", err)
+	}
+	return code, testCode, nil
 }
 
-func findDTOStructs(node *ast.File) []MapperDefinition {
+// findDTOStructs returns the mappers of the DTO structs of a file, or the errors of all
+// fields whose mapping is not declared or invalid
+func findDTOStructs(node *ast.File, converters map[string]Converter) ([]MapperDefinition, error) {
 	var mappers []MapperDefinition
+	var errs []error
 
 	for _, decl := range node.Decls {
 		genDecl, ok := decl.(*ast.GenDecl)
@@ -119,100 +300,208 @@ func findDTOStructs(node *ast.File) []MapperDefinition {
 				continue
 			}
 
-			// Determine entity name (remove "DTO" suffix)
-			entityName := strings.TrimSuffix(dtoName, "DTO")
-
 			// Extract fields
-			fields := extractFields(structType)
-
-			// Check if has UpdatedAt field
-			hasUpdatedAt := false
-			for _, f := range fields {
-				if f.FieldName == "UpdatedAt" {
-					hasUpdatedAt = true
-					break
-				}
-			}
-
-			mapper := MapperDefinition{
-				DTOName:      dtoName,
-				EntityName:   entityName,
-				EntityPkg:    "entity",
-				Fields:       fields,
-				HasUpdatedAt: hasUpdatedAt,
+			fields, err := extractFields(structType, converters)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", dtoName, err))
+				continue
 			}
 
-			mappers = append(mappers, mapper)
+			mappers = append(mappers, MapperDefinition{
+				DTOName:    dtoName,
+				EntityName: strings.TrimSuffix(dtoName, "DTO"),
+				EntityPkg:  "entity",
+				Fields:     fields,
+			})
 		}
 	}
 
-	return mappers
+	return mappers, errors.Join(errs...)
 }
 
-func extractFields(structType *ast.StructType) []FieldMapping {
+// extractFields determines the mapping of each field from its mapper tag:
+//
+//	ID        string `mapper:"id"`                 // valueobject.<Entity>ID
+//	Status    string `mapper:"vo=TaskStatus"`      // valueobject.MustNewTaskStatus
+//	Channel   string `mapper:"vo=Channel,sample=telegram"` // valid value for -tests
+//	Timezone  string `mapper:"vo=Timezone,raw"`    // valueobject.Timezone, without validation
+//	CreatedAt string `mapper:"time"`               // time.Time, RFC3339
+//	RunAt     string `mapper:"time,optional"`      // *time.Time, empty for nil
+//	Tags      []string `mapper:"json"`             // string holding a JSON array
+//	Owner     *UserDTO `mapper:"dto"`              // *entity.User, also []*UserDTO
+//	Timeout   string `mapper:"conv=duration"`      // converter of the -config file
+//	Internal  string `mapper:"-"`                  // not mapped
+//
+// Fields without a tag are copied if they have a scalar type, e.g. string, bool or int, or
+// are a time.Time, or a pointer to one of these, e.g. *time.Time.
+func extractFields(structType *ast.StructType, converters map[string]Converter) ([]FieldMapping, error) {
 	var fields []FieldMapping
+	var errs []error
 
 	if structType.Fields == nil {
-		return fields
+		return fields, nil
 	}
 
 	for _, field := range structType.Fields.List {
-		if len(field.Names) == 0 {
-			continue
+		typeName := getFieldType(field.Type)
+		tags := structTag(field)
+		tag, hasTag := tags.Lookup("mapper")
+		jsonName, _, _ := strings.Cut(tags.Get("json"), ",")
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			if tag == "-" {
+				continue
+			}
+
+			mapping, err := fieldMapping(name.Name, typeName, tag, hasTag, converters)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("field %s: %w", name.Name, err))
+				continue
+			}
+			mapping.JSONName = jsonName
+			if mapping.JSONName == "" || mapping.JSONName == "-" || len(field.Names) > 1 {
+				mapping.JSONName = toSnakeCase(name.Name)
+			}
+			fields = append(fields, mapping)
 		}
+	}
 
-		fieldName := field.Names[0].Name
+	return fields, errors.Join(errs...)
+}
 
-		// Skip tags like json
-		// Extract type
-		typeName := getFieldType(field.Type)
+// structTag returns the tags of a field
+func structTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
 
-		// Determine mapping strategy
-		mapping := FieldMapping{
-			FieldName:  fieldName,
-			DTOType:    typeName,
-			EntityName: fieldName,
-		}
-
-		// Check field type and determine mapping
-		switch fieldName {
-		case "ID":
-			mapping.IsIDField = true
-			mapping.EntityType = guessEntityIDType(fieldName)
-		case "CreatedAt", "UpdatedAt":
-			mapping.IsTimeField = true
-			mapping.EntityType = "time.Time"
-		case "Channel":
-			mapping.IsVOField = true
-			mapping.EntityType = "valueobject.Channel"
-		case "Role":
-			mapping.IsVOField = true
-			mapping.EntityType = "valueobject.MessageRole"
-		case "Status":
-			mapping.IsVOField = true
-			mapping.EntityType = "valueobject.TaskStatus"
-		case "Version":
-			mapping.IsVOField = true
-			mapping.EntityType = "valueobject.Version"
-		case "CronExpression":
-			mapping.IsVOField = true
-			mapping.EntityType = "valueobject.CronExpression"
-		case "SessionID", "MessageID", "SkillID", "ScheduleID", "TaskID", "UserID":
-			mapping.IsVOField = true
-			mapping.EntityType = guessEntityIDType(fieldName)
-		case "Enabled":
-			mapping.IsBoolCopy = true
-			mapping.EntityType = "bool"
-		default:
-			// Default: string copy
-			mapping.IsStringCopy = true
-			mapping.EntityType = "string"
+// fieldMapping parses the mapper tag of a field
+func fieldMapping(fieldName, typeName, tag string, hasTag bool, converters map[string]Converter) (FieldMapping, error) {
+	mapping := FieldMapping{
+		FieldName:  fieldName,
+		DTOType:    typeName,
+		EntityName: fieldName,
+	}
+
+	if !hasTag {
+		if !copyTypes[strings.TrimPrefix(typeName, "*")] {
+			return mapping, fmt.Errorf("type %s cannot be copied; declare its mapping with a mapper tag", typeName)
 		}
+		mapping.IsCopy = true
+		mapping.EntityType = typeName
+		return mapping, nil
+	}
 
-		fields = append(fields, mapping)
+	switch {
+	case tag == "json":
+		converter, ok := jsonConverters[typeName]
+		if !ok {
+			return mapping, fmt.Errorf("mapper tag %q requires a []string or map[string]interface{} field, got %s", tag, typeName)
+		}
+		mapping.Converter = &converter
+		mapping.EntityType = converter.EntityType
+		return mapping, nil
+	case tag == "dto":
+		elem, isList := strings.CutPrefix(typeName, "[]")
+		dtoName, isPtr := strings.CutPrefix(elem, "*")
+		if !isPtr || !token.IsIdentifier(dtoName) || !strings.HasSuffix(dtoName, "DTO") || dtoName == "DTO" {
+			return mapping, fmt.Errorf("mapper tag %q requires a *XDTO or []*XDTO field, got %s", tag, typeName)
+		}
+		mapping.NestedDTO = dtoName
+		mapping.IsNestedList = isList
+		mapping.EntityType = strings.TrimSuffix(typeName, dtoName) + "entity." + strings.TrimSuffix(dtoName, "DTO")
+		return mapping, nil
+	case strings.HasPrefix(tag, "conv="):
+		name := strings.TrimPrefix(tag, "conv=")
+		converter, ok := converters[name]
+		if !ok {
+			return mapping, fmt.Errorf("unknown converter %q; declare it in the -config file", name)
+		}
+		if converter.DTOType != "" && converter.DTOType != typeName {
+			return mapping, fmt.Errorf("converter %q requires a %s field, got %s", name, converter.DTOType, typeName)
+		}
+		mapping.Converter = &converter
+		mapping.EntityType = converter.EntityType
+		return mapping, nil
 	}
 
-	return fields
+	if typeName != "string" {
+		return mapping, fmt.Errorf("mapper tag %q requires a string field, got %s", tag, typeName)
+	}
+
+	kind, options, _ := strings.Cut(tag, ",")
+	switch {
+	case kind == "id" && options == "":
+		mapping.IsIDField = true
+	case kind == "time" && options == "":
+		mapping.IsTimeField = true
+		mapping.EntityType = "time.Time"
+	case kind == "time" && options == "optional":
+		mapping.IsOptionalAt = true
+		mapping.EntityType = "*time.Time"
+	case strings.HasPrefix(kind, "vo="):
+		voName := strings.TrimPrefix(kind, "vo=")
+		if !token.IsIdentifier(voName) {
+			return mapping, fmt.Errorf("invalid value object %q in mapper tag", voName)
+		}
+		if options != "" {
+			for _, option := range strings.Split(options, ",") {
+				sample, isSample := strings.CutPrefix(option, "sample=")
+				switch {
+				case option == "raw":
+					mapping.IsRawVO = true
+				case isSample && sample != "":
+					mapping.Sample = sample
+				default:
+					return mapping, fmt.Errorf("invalid mapper tag %q", tag)
+				}
+			}
+		}
+		mapping.IsVOField = true
+		mapping.EntityType = "valueobject." + voName
+	default:
+		return mapping, fmt.Errorf("invalid mapper tag %q", tag)
+	}
+	return mapping, nil
+}
+
+// requiredImports returns the imports used by the generated mappers
+func requiredImports(mappers []MapperDefinition) []string {
+	var usesTime, usesVO bool
+	var converterImports []string
+	for _, mapper := range mappers {
+		for _, field := range mapper.Fields {
+			usesTime = usesTime || field.IsTimeField
+			usesVO = usesVO || field.IsIDField || field.IsVOField
+			if field.Converter != nil {
+				converterImports = append(converterImports, field.Converter.Imports...)
+			}
+		}
+	}
+
+	var imports []string
+	if usesTime {
+		imports = append(imports, "time")
+	}
+	imports = append(imports, entityImport)
+	if usesVO {
+		imports = append(imports, valueobjectImport)
+	}
+	for _, imp := range converterImports {
+		if !slices.Contains(imports, imp) {
+			imports = append(imports, imp)
+		}
+	}
+	return imports
 }
 
 func getFieldType(expr ast.Expr) string {
@@ -221,30 +510,19 @@ func getFieldType(expr ast.Expr) string {
 		return t.Name
 	case *ast.SelectorExpr:
 		return fmt.Sprintf("%s.%s", t.X, t.Sel)
-	default:
+	case *ast.StarExpr:
+		return "*" + getFieldType(t.X)
+	case *ast.ArrayType:
+		return "[]" + getFieldType(t.Elt)
+	case *ast.MapType:
+		return fmt.Sprintf("map[%s]%s", getFieldType(t.Key), getFieldType(t.Value))
+	case *ast.InterfaceType:
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return "interface{}"
+		}
 		return "unknown"
-	}
-}
-
-func guessEntityIDType(fieldName string) string {
-	switch fieldName {
-	case "ID":
-		// Context-specific ID based on DTO name
-		return "valueobject.ID" // Will be replaced in template
-	case "UserID":
-		return "valueobject.UserID"
-	case "SessionID":
-		return "valueobject.SessionID"
-	case "MessageID":
-		return "valueobject.MessageID"
-	case "SkillID":
-		return "valueobject.SkillID"
-	case "ScheduleID":
-		return "valueobject.ScheduleID"
-	case "TaskID":
-		return "valueobject.TaskID"
 	default:
-		return fmt.Sprintf("valueobject.%s", strings.TrimSuffix(fieldName, "ID"))
+		return "unknown"
 	}
 }
 
@@ -267,21 +545,174 @@ func generateMapperCode(config MapperConfig) string {
 	// Generate mappers
 	for _, mapper := range config.Mappers {
 		generateToEntity(&buf, mapper)
+		generateMustToEntity(&buf, mapper)
 		generateFromEntity(&buf, mapper)
 	}
 
 	return buf.String()
 }
 
-func generateToEntity(buf *bytes.Buffer, mapper MapperDefinition) {
-	fmt.Fprintf(buf, "// ToEntity converts %s to entity.%s\n", mapper.DTOName, mapper.EntityName)
-	fmt.Fprintf(buf, "func (dto *%s) ToEntity() *entity.%s {\n", mapper.DTOName, mapper.EntityName)
+// timeFields returns the required time fields of a mapper
+func timeFields(mapper MapperDefinition) []FieldMapping {
+	var fields []FieldMapping
+	for _, field := range mapper.Fields {
+		if field.IsTimeField {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
 
-	// Handle CreatedAt/UpdatedAt parsing
-	if mapper.HasUpdatedAt {
+// localName returns the name of the local variable holding a parsed field
+func localName(fieldName string) string {
+	return strings.ToLower(fieldName[:1]) + fieldName[1:]
+}
+
+// generateTimeParsing parses the required time fields into local variables. CreatedAt and
+// UpdatedAt are parsed together by MustParseTimeFieldsWithUpdatedAt.
+func generateTimeParsing(buf *bytes.Buffer, fields []FieldMapping) {
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field.FieldName] = true
+	}
+	pair := names["CreatedAt"] && names["UpdatedAt"]
+	if pair {
 		fmt.Fprintf(buf, "\tcreatedAt, updatedAt := MustParseTimeFieldsWithUpdatedAt(dto.CreatedAt, dto.UpdatedAt)\n")
-	} else {
-		fmt.Fprintf(buf, "\tcreatedAt := MustParseTimeFields(dto.CreatedAt)\n")
+	}
+
+	for _, field := range fields {
+		switch {
+		case pair && (field.FieldName == "CreatedAt" || field.FieldName == "UpdatedAt"):
+		case field.FieldName == "CreatedAt":
+			fmt.Fprintf(buf, "\tcreatedAt := MustParseTimeFields(dto.CreatedAt)\n")
+		default:
+			fmt.Fprintf(buf, "\t%s := MustParseTime(%q, dto.%s)\n", localName(field.FieldName), field.JSONName, field.FieldName)
+		}
+	}
+}
+
+// toSnakeCase converts a field name such as "RunAt" or "UserID" to "run_at" or "user_id"
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// reservedNames are identifiers of the generated code that local variables must not shadow
+var reservedNames = map[string]bool{
+	"dto": true, "entity": true, "valueobject": true, "time": true, "err": true, "fieldErrs": true,
+}
+
+// parsedName returns the name of the local variable holding a field parsed by ToEntity
+func parsedName(fieldName string) string {
+	name := localName(fieldName)
+	if reservedNames[name] || token.Lookup(name).IsKeyword() {
+		name += "Value"
+	}
+	return name
+}
+
+// validatesField reports whether ToEntity parses a field with a function returning an error
+func validatesField(field FieldMapping) bool {
+	return field.IsTimeField || field.IsOptionalAt || (field.IsVOField && !field.IsRawVO) ||
+		field.Converter != nil || field.NestedDTO != ""
+}
+
+// nestedFunc returns the helper of mapper_base.go converting a nested DTO field, e.g.
+// ParseNestedDTO or NestedDTOsFromEntities
+func nestedFunc(field FieldMapping, single, list string) string {
+	if field.IsNestedList {
+		return list
+	}
+	return single
+}
+
+// generateToEntity generates ToEntity, which parses every field with the non-panicking
+// constructors and returns a MappingError listing all invalid fields
+func generateToEntity(buf *bytes.Buffer, mapper MapperDefinition) {
+	fmt.Fprintf(buf, "// ToEntity converts %s to entity.%s.\n", mapper.DTOName, mapper.EntityName)
+	fmt.Fprintf(buf, "// Returns a *MappingError listing the invalid fields.\n")
+	fmt.Fprintf(buf, "func (dto *%s) ToEntity() (*entity.%s, error) {\n", mapper.DTOName, mapper.EntityName)
+
+	validated := false
+	for _, field := range mapper.Fields {
+		if !validatesField(field) {
+			continue
+		}
+		if !validated {
+			fmt.Fprintf(buf, "\tvar fieldErrs []FieldError\n")
+			validated = true
+		}
+
+		name := parsedName(field.FieldName)
+		switch {
+		case field.IsTimeField:
+			fmt.Fprintf(buf, "\t%s, err := ParseTime(%q, dto.%s)\n", name, field.JSONName, field.FieldName)
+		case field.IsOptionalAt:
+			fmt.Fprintf(buf, "\t%s, err := ParseOptionalTimeField(%q, dto.%s)\n", name, field.JSONName, field.FieldName)
+		case field.Converter != nil:
+			fmt.Fprintf(buf, "\t%s, err := %s(dto.%s)\n", name, field.Converter.ToEntity, field.FieldName)
+		case field.NestedDTO != "":
+			fmt.Fprintf(buf, "\t%s, err := %s(dto.%s, (*%s).ToEntity)\n", name,
+				nestedFunc(field, "ParseNestedDTO", "ParseNestedDTOs"), field.FieldName, field.NestedDTO)
+		default:
+			fmt.Fprintf(buf, "\t%s, err := valueobject.New%s(dto.%s)\n", name, strings.TrimPrefix(field.EntityType, "valueobject."), field.FieldName)
+		}
+		fmt.Fprintf(buf, "\tif err != nil {\n")
+		fmt.Fprintf(buf, "\t\tfieldErrs = append(fieldErrs, FieldError{Field: %q, Err: err})\n", field.JSONName)
+		fmt.Fprintf(buf, "\t}\n")
+	}
+	if validated {
+		fmt.Fprintf(buf, "\tif len(fieldErrs) > 0 {\n")
+		fmt.Fprintf(buf, "\t\treturn nil, &MappingError{DTO: %q, Fields: fieldErrs}\n", mapper.DTOName)
+		fmt.Fprintf(buf, "\t}\n")
+	}
+
+	fmt.Fprintf(buf, "\treturn &entity.%s{\n", mapper.EntityName)
+	for _, field := range mapper.Fields {
+		fmt.Fprintf(buf, "\t\t%s: ", field.EntityName)
+		switch {
+		case validatesField(field):
+			fmt.Fprintf(buf, "%s,\n", parsedName(field.FieldName))
+		case field.IsIDField:
+			fmt.Fprintf(buf, "%s(dto.%s),\n", getIDTypeForEntity(mapper.EntityName), field.FieldName)
+		case field.IsVOField:
+			fmt.Fprintf(buf, "%s(dto.%s),\n", field.EntityType, field.FieldName)
+		default:
+			fmt.Fprintf(buf, "dto.%s,\n", field.FieldName)
+		}
+	}
+	fmt.Fprintf(buf, "\t}, nil\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// generateMustToEntity generates MustToEntity, which converts the fields with the Must
+// constructors and panics on the first invalid field
+func generateMustToEntity(buf *bytes.Buffer, mapper MapperDefinition) {
+	fmt.Fprintf(buf, "// MustToEntity converts %s to entity.%s.\n", mapper.DTOName, mapper.EntityName)
+	fmt.Fprintf(buf, "// Panics if a field is invalid. Use this when the DTO is guaranteed to be valid.\n")
+	fmt.Fprintf(buf, "func (dto *%s) MustToEntity() *entity.%s {\n", mapper.DTOName, mapper.EntityName)
+
+	times := timeFields(mapper)
+	generateTimeParsing(buf, times)
+
+	// Converted fields, panicking with the name of the field
+	for _, field := range mapper.Fields {
+		if field.Converter == nil {
+			continue
+		}
+		fmt.Fprintf(buf, "\t%s, err := %s(dto.%s)\n", parsedName(field.FieldName), field.Converter.ToEntity, field.FieldName)
+		fmt.Fprintf(buf, "\tPanicOnFieldError(%q, err)\n", field.JSONName)
 	}
 
 	// Return statement
@@ -289,32 +720,33 @@ func generateToEntity(buf *bytes.Buffer, mapper MapperDefinition) {
 
 	// Fields
 	for _, field := range mapper.Fields {
-		if field.FieldName == "CreatedAt" || field.FieldName == "UpdatedAt" {
-			continue // Already handled
+		if field.IsTimeField {
+			continue // Parsed above
 		}
 
-		fmt.Fprintf(buf, "\t\t%s: ", field.FieldName)
+		fmt.Fprintf(buf, "\t\t%s: ", field.EntityName)
 
 		switch {
 		case field.IsIDField:
-			// Special case for ID - need to determine correct type
-			idType := getIDTypeForEntity(mapper.EntityName)
-			fmt.Fprintf(buf, "%s(dto.%s),\n", idType, field.FieldName)
+			// Entity IDs are generated by the domain, so convert without validation
+			fmt.Fprintf(buf, "%s(dto.%s),\n", getIDTypeForEntity(mapper.EntityName), field.FieldName)
+		case field.IsOptionalAt:
+			fmt.Fprintf(buf, "ParseOptionalTime(dto.%s),\n", field.FieldName)
 		case field.IsVOField:
-			fmt.Fprintf(buf, "%s(dto.%s),\n", getVOConstructor(field.EntityType), field.FieldName)
-		case field.IsStringCopy:
-			fmt.Fprintf(buf, "dto.%s,\n", field.FieldName)
-		case field.IsBoolCopy:
-			fmt.Fprintf(buf, "dto.%s,\n", field.FieldName)
+			fmt.Fprintf(buf, "%s(dto.%s),\n", getVOConstructor(field), field.FieldName)
+		case field.Converter != nil:
+			fmt.Fprintf(buf, "%s,\n", parsedName(field.FieldName))
+		case field.NestedDTO != "":
+			fmt.Fprintf(buf, "%s(dto.%s, (*%s).MustToEntity),\n",
+				nestedFunc(field, "MustParseNestedDTO", "MustParseNestedDTOs"), field.FieldName, field.NestedDTO)
 		default:
 			fmt.Fprintf(buf, "dto.%s,\n", field.FieldName)
 		}
 	}
 
 	// Time fields
-	fmt.Fprintf(buf, "\t\tCreatedAt: createdAt,\n")
-	if mapper.HasUpdatedAt {
-		fmt.Fprintf(buf, "\t\tUpdatedAt: updatedAt,\n")
+	for _, field := range times {
+		fmt.Fprintf(buf, "\t\t%s: %s,\n", field.EntityName, localName(field.FieldName))
 	}
 
 	fmt.Fprintf(buf, "\t}\n")
@@ -322,9 +754,11 @@ func generateToEntity(buf *bytes.Buffer, mapper MapperDefinition) {
 }
 
 func generateFromEntity(buf *bytes.Buffer, mapper MapperDefinition) {
+	receiver := localName(mapper.EntityName)
+
 	fmt.Fprintf(buf, "// FromEntity converts entity.%s to %s\n", mapper.EntityName, mapper.DTOName)
 	fmt.Fprintf(buf, "func %sFromEntity(%s *entity.%s) *%s {\n",
-		mapper.DTOName, strings.ToLower(mapper.EntityName[:1])+mapper.EntityName[1:], mapper.EntityName, mapper.DTOName)
+		mapper.DTOName, receiver, mapper.EntityName, mapper.DTOName)
 
 	fmt.Fprintf(buf, "\treturn &%s{\n", mapper.DTOName)
 
@@ -333,18 +767,21 @@ func generateFromEntity(buf *bytes.Buffer, mapper MapperDefinition) {
 		fmt.Fprintf(buf, "\t\t%s: ", field.FieldName)
 
 		switch {
+		case field.IsOptionalAt:
+			fmt.Fprintf(buf, "FormatOptionalTime(%s.%s),\n", receiver, field.EntityName)
 		case field.IsIDField || field.IsVOField:
-			fmt.Fprintf(buf, "string(%s.%s),\n", strings.ToLower(mapper.EntityName[:1])+mapper.EntityName[1:], field.FieldName)
-		case field.FieldName == "CreatedAt":
-			fmt.Fprintf(buf, "%s.CreatedAt.Format(time.RFC3339),\n", strings.ToLower(mapper.EntityName[:1])+mapper.EntityName[1:])
-		case field.FieldName == "UpdatedAt":
-			fmt.Fprintf(buf, "%s.UpdatedAt.Format(time.RFC3339),\n", strings.ToLower(mapper.EntityName[:1])+mapper.EntityName[1:])
-		case field.IsStringCopy:
-			fmt.Fprintf(buf, "%s.%s,\n", strings.ToLower(mapper.EntityName[:1])+mapper.EntityName[1:], field.FieldName)
-		case field.IsBoolCopy:
-			fmt.Fprintf(buf, "%s.%s,\n", strings.ToLower(mapper.EntityName[:1])+mapper.EntityName[1:], field.FieldName)
+			fmt.Fprintf(buf, "string(%s.%s),\n", receiver, field.EntityName)
+		case field.IsTimeField:
+			fmt.Fprintf(buf, "%s.%s.Format(time.RFC3339),\n", receiver, field.EntityName)
+		case field.Converter != nil && field.Converter.FromEntityErr:
+			fmt.Fprintf(buf, "DiscardErr(%s(%s.%s)),\n", field.Converter.FromEntity, receiver, field.EntityName)
+		case field.Converter != nil:
+			fmt.Fprintf(buf, "%s(%s.%s),\n", field.Converter.FromEntity, receiver, field.EntityName)
+		case field.NestedDTO != "":
+			fmt.Fprintf(buf, "%s(%s.%s, %sFromEntity),\n",
+				nestedFunc(field, "NestedDTOFromEntity", "NestedDTOsFromEntities"), receiver, field.EntityName, field.NestedDTO)
 		default:
-			fmt.Fprintf(buf, "%s.%s,\n", strings.ToLower(mapper.EntityName[:1])+mapper.EntityName[1:], field.FieldName)
+			fmt.Fprintf(buf, "%s.%s,\n", receiver, field.EntityName)
 		}
 	}
 
@@ -353,57 +790,14 @@ func generateFromEntity(buf *bytes.Buffer, mapper MapperDefinition) {
 }
 
 func getIDTypeForEntity(entityName string) string {
-	switch entityName {
-	case "User":
-		return "valueobject.UserID"
-	case "Session":
-		return "valueobject.SessionID"
-	case "Message":
-		return "valueobject.MessageID"
-	case "Task":
-		return "valueobject.TaskID"
-	case "Skill":
-		return "valueobject.SkillID"
-	case "Schedule":
-		return "valueobject.ScheduleID"
-	default:
-		return fmt.Sprintf("valueobject.%sID", entityName)
-	}
+	return fmt.Sprintf("valueobject.%sID", entityName)
 }
 
-func getVOConstructor(entityType string) string {
-	switch entityType {
-	case "valueobject.Channel":
-		return "valueobject.MustNewChannel"
-	case "valueobject.MessageRole":
-		return "valueobject.MustNewMessageRole"
-	case "valueobject.TaskStatus":
-		return "valueobject.MustNewTaskStatus"
-	case "valueobject.Version":
-		return "valueobject.MustNewVersion"
-	case "valueobject.CronExpression":
-		return "valueobject.MustNewCronExpression"
-	case "valueobject.UserID":
-		return "valueobject.MustNewUserID"
-	case "valueobject.SessionID":
-		return "valueobject.MustNewSessionID"
-	case "valueobject.MessageID":
-		return "valueobject.MustNewMessageID"
-	case "valueobject.SkillID":
-		return "valueobject.MustNewSkillID"
-	case "valueobject.ScheduleID":
-		return "valueobject.MustNewScheduleID"
-	case "valueobject.TaskID":
-		return "valueobject.MustNewTaskID"
-	case "valueobject.Role":
-		return "valueobject.MustNewMessageRole"
-	case "valueobject.Status":
-		return "valueobject.MustNewTaskStatus"
-	default:
-		parts := strings.Split(string(entityType), ".")
-		if len(parts) > 1 {
-			return fmt.Sprintf("valueobject.MustNew%s", parts[1])
-		}
-		return fmt.Sprintf("valueobject.MustNew%s", entityType)
+// getVOConstructor returns the function converting a string to the value object of a field:
+// the validating MustNew constructor, or a plain conversion for raw value objects
+func getVOConstructor(field FieldMapping) string {
+	if field.IsRawVO {
+		return field.EntityType
 	}
+	return "valueobject.MustNew" + strings.TrimPrefix(field.EntityType, "valueobject.")
 }
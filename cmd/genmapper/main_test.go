@@ -0,0 +1,245 @@
+package main
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseDTOs parses the DTO structs of src
+func parseDTOs(t *testing.T, src string) ([]MapperDefinition, error) {
+	t.Helper()
+	node, err := parser.ParseFile(token.NewFileSet(), "dto.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	return findDTOStructs(node, nil)
+}
+
+func TestFindDTOStructs_Tags(t *testing.T) {
+	mappers, err := parseDTOs(t, `package dto
+
+type ReminderDTO struct {
+	ID        string `+"`json:\"id\" mapper:\"id\"`"+`
+	UserID    string `+"`json:\"user_id\" mapper:\"vo=UserID\"`"+`
+	Timezone  string `+"`json:\"timezone\" mapper:\"vo=Timezone,raw\"`"+`
+	Text      string `+"`json:\"text\"`"+`
+	Repeat    int    `+"`json:\"repeat\"`"+`
+	Internal  []byte `+"`json:\"-\" mapper:\"-\"`"+`
+	DueAt     string `+"`json:\"due_at\" mapper:\"time\"`"+`
+	SentAt    string `+"`json:\"sent_at\" mapper:\"time,optional\"`"+`
+	CreatedAt string `+"`json:\"created_at\" mapper:\"time\"`"+`
+}
+
+type reminderOptions struct {
+	Raw map[string]any
+}
+`)
+	require.NoError(t, err)
+	require.Len(t, mappers, 1)
+	assert.Equal(t, "Reminder", mappers[0].EntityName)
+
+	fields := map[string]FieldMapping{}
+	for _, field := range mappers[0].Fields {
+		fields[field.FieldName] = field
+	}
+	assert.Len(t, fields, 8, "Internal is skipped")
+	assert.True(t, fields["ID"].IsIDField)
+	assert.Equal(t, FieldMapping{FieldName: "UserID", JSONName: "user_id", DTOType: "string", EntityName: "UserID", EntityType: "valueobject.UserID", IsVOField: true}, fields["UserID"])
+	assert.True(t, fields["Timezone"].IsRawVO)
+	assert.True(t, fields["Repeat"].IsCopy)
+	assert.True(t, fields["DueAt"].IsTimeField)
+	assert.True(t, fields["SentAt"].IsOptionalAt)
+
+	code := generateMapperCode(MapperConfig{PackageName: "dto", Imports: requiredImports(mappers), Mappers: mappers})
+	formatted, err := format.Source([]byte(code))
+	require.NoError(t, err, code)
+	generated := strings.Join(strings.Fields(string(formatted)), " ")
+	for _, want := range []string{
+		`createdAt := MustParseTimeFields(dto.CreatedAt)`,
+		`dueAt := MustParseTime("due_at", dto.DueAt)`,
+		`ID: valueobject.ReminderID(dto.ID),`,
+		`UserID: valueobject.MustNewUserID(dto.UserID),`,
+		`Timezone: valueobject.Timezone(dto.Timezone),`,
+		`SentAt: ParseOptionalTime(dto.SentAt),`,
+		`DueAt: reminder.DueAt.Format(time.RFC3339),`,
+		`"time"`,
+		`func (dto *ReminderDTO) ToEntity() (*entity.Reminder, error) {`,
+		`userID, err := valueobject.NewUserID(dto.UserID)`,
+		`fieldErrs = append(fieldErrs, FieldError{Field: "user_id", Err: err})`,
+		`sentAt, err := ParseOptionalTimeField("sent_at", dto.SentAt)`,
+		`return nil, &MappingError{DTO: "ReminderDTO", Fields: fieldErrs}`,
+		`func (dto *ReminderDTO) MustToEntity() *entity.Reminder {`,
+	} {
+		assert.Contains(t, generated, want)
+	}
+}
+
+func TestFindDTOStructs_Errors(t *testing.T) {
+	_, err := parseDTOs(t, `package dto
+
+type NoteDTO struct {
+	Tags     []string          `+"`json:\"tags\"`"+`
+	Labels   map[string]string `+"`json:\"labels\"`"+`
+	Priority int               `+"`json:\"priority\" mapper:\"vo=Priority\"`"+`
+	Kind     string            `+"`json:\"kind\" mapper:\"enum\"`"+`
+	Due      string            `+"`json:\"due\" mapper:\"time,utc\"`"+`
+}
+`)
+	require.Error(t, err)
+	for _, want := range []string{
+		"NoteDTO: field Tags: type []string cannot be copied; declare its mapping with a mapper tag",
+		"field Labels: type map[string]string cannot be copied",
+		`field Priority: mapper tag "vo=Priority" requires a string field, got int`,
+		`field Kind: invalid mapper tag "enum"`,
+		`field Due: invalid mapper tag "time,utc"`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestFindDTOStructs_Nested(t *testing.T) {
+	converters := map[string]Converter{
+		"duration": {DTOType: "string", EntityType: "time.Duration", ToEntity: "time.ParseDuration", FromEntity: "FormatDuration", Imports: []string{"time"}},
+	}
+	node, err := parser.ParseFile(token.NewFileSet(), "dto.go", `package dto
+
+type ProjectDTO struct {
+	Tags       []string               `+"`json:\"tags\" mapper:\"json\"`"+`
+	Settings   map[string]interface{} `+"`json:\"settings\" mapper:\"json\"`"+`
+	Owner      *UserDTO               `+"`json:\"owner\" mapper:\"dto\"`"+`
+	Members    []*UserDTO             `+"`json:\"members\" mapper:\"dto\"`"+`
+	Timeout    string                 `+"`json:\"timeout\" mapper:\"conv=duration\"`"+`
+	ArchivedAt *time.Time             `+"`json:\"archived_at\"`"+`
+	Quota      *int                   `+"`json:\"quota\"`"+`
+}
+`, parser.ParseComments)
+	require.NoError(t, err)
+	mappers, err := findDTOStructs(node, converters)
+	require.NoError(t, err)
+	require.Len(t, mappers, 1)
+
+	fields := map[string]FieldMapping{}
+	for _, field := range mappers[0].Fields {
+		fields[field.FieldName] = field
+	}
+	assert.Equal(t, "string", fields["Tags"].EntityType)
+	assert.Equal(t, "*entity.User", fields["Owner"].EntityType)
+	assert.Equal(t, "[]*entity.User", fields["Members"].EntityType)
+	assert.True(t, fields["Members"].IsNestedList)
+	assert.Equal(t, "time.Duration", fields["Timeout"].EntityType)
+	assert.True(t, fields["ArchivedAt"].IsCopy)
+	assert.True(t, fields["Quota"].IsCopy)
+	assert.Equal(t, []string{entityImport, "time"}, requiredImports(mappers))
+
+	code := generateMapperCode(MapperConfig{PackageName: "dto", Imports: requiredImports(mappers), Mappers: mappers})
+	formatted, err := format.Source([]byte(code))
+	require.NoError(t, err, code)
+	generated := strings.Join(strings.Fields(string(formatted)), " ")
+	for _, want := range []string{
+		`tags, err := SliceToString(dto.Tags)`,
+		`settings, err := MapToString(dto.Settings)`,
+		`owner, err := ParseNestedDTO(dto.Owner, (*UserDTO).ToEntity)`,
+		`members, err := ParseNestedDTOs(dto.Members, (*UserDTO).ToEntity)`,
+		`timeout, err := time.ParseDuration(dto.Timeout)`,
+		`fieldErrs = append(fieldErrs, FieldError{Field: "timeout", Err: err})`,
+		`PanicOnFieldError("tags", err)`,
+		`Owner: MustParseNestedDTO(dto.Owner, (*UserDTO).MustToEntity),`,
+		`Members: MustParseNestedDTOs(dto.Members, (*UserDTO).MustToEntity),`,
+		`ArchivedAt: dto.ArchivedAt,`,
+		`Tags: DiscardErr(StringToSlice(project.Tags)),`,
+		`Owner: NestedDTOFromEntity(project.Owner, UserDTOFromEntity),`,
+		`Members: NestedDTOsFromEntities(project.Members, UserDTOFromEntity),`,
+		`Timeout: FormatDuration(project.Timeout),`,
+	} {
+		assert.Contains(t, generated, want)
+	}
+}
+
+func TestFindDTOStructs_NestedErrors(t *testing.T) {
+	_, err := parseDTOs(t, `package dto
+
+type ProjectDTO struct {
+	Name    string            `+"`json:\"name\" mapper:\"json\"`"+`
+	Labels  map[string]string `+"`json:\"labels\" mapper:\"json\"`"+`
+	Owner   UserDTO           `+"`json:\"owner\" mapper:\"dto\"`"+`
+	Parent  *Project          `+"`json:\"parent\" mapper:\"dto\"`"+`
+	Timeout string            `+"`json:\"timeout\" mapper:\"conv=duration\"`"+`
+}
+`)
+	require.Error(t, err)
+	for _, want := range []string{
+		`field Name: mapper tag "json" requires a []string or map[string]interface{} field, got string`,
+		`field Labels: mapper tag "json" requires a []string or map[string]interface{} field, got map[string]string`,
+		`field Owner: mapper tag "dto" requires a *XDTO or []*XDTO field, got UserDTO`,
+		`field Parent: mapper tag "dto" requires a *XDTO or []*XDTO field, got *Project`,
+		`field Timeout: unknown converter "duration"; declare it in the -config file`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "genmapper.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"converters": {"duration": {"dto_type": "string", "to_entity": "time.ParseDuration", "from_entity": "FormatDuration", "imports": ["time"]}}}`), 0o644))
+	converters, err := loadConfig(valid)
+	require.NoError(t, err)
+	assert.Equal(t, Converter{DTOType: "string", ToEntity: "time.ParseDuration", FromEntity: "FormatDuration", Imports: []string{"time"}}, converters["duration"])
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"converters": {"duration": {"to_entity": "time.ParseDuration"}}}`), 0o644))
+	_, err = loadConfig(invalid)
+	assert.ErrorContains(t, err, `converter "duration": to_entity and from_entity are required`)
+
+	_, err = loadConfig(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "reading config")
+
+	// A converter of another DTO type is rejected
+	_, err = fieldMapping("Timeout", "int", "conv=duration", true, converters)
+	assert.ErrorContains(t, err, `converter "duration" requires a string field, got int`)
+}
+
+func TestRequiredImports(t *testing.T) {
+	mappers := []MapperDefinition{{Fields: []FieldMapping{{FieldName: "Name", IsCopy: true}}}}
+	assert.Equal(t, []string{entityImport}, requiredImports(mappers))
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"ID":          "id",
+		"RunAt":       "run_at",
+		"UserID":      "user_id",
+		"HTTPTimeout": "http_timeout",
+	} {
+		assert.Equal(t, want, toSnakeCase(name), name)
+	}
+}
+
+func TestGenerate_Package(t *testing.T) {
+	dir := t.TempDir()
+	note := filepath.Join(dir, "note_dto.go")
+	other := filepath.Join(dir, "other.go")
+	require.NoError(t, os.WriteFile(note, []byte("package notes\n\ntype NoteDTO struct {\n\tText string `json:\"text\"`\n}\n"), 0o644))
+	require.NoError(t, os.WriteFile(other, []byte("package other\n"), 0o644))
+
+	code, _, err := generate([]string{note}, "", nil, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "package notes\n")
+	assert.Contains(t, string(code), "func NoteDTOFromEntity(note *entity.Note) *NoteDTO {")
+
+	code, _, err = generate([]string{note}, "mappers", nil, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "package mappers\n")
+
+	_, _, err = generate([]string{note, other}, "", nil, false)
+	assert.ErrorContains(t, err, "package other differs from package notes; set -package")
+
+	_, _, err = generate([]string{filepath.Join(dir, "missing.go")}, "", nil, false)
+	assert.ErrorContains(t, err, "parsing file")
+}
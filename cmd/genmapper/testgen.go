@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Import paths of the generated tests
+const (
+	assertImport  = "github.com/stretchr/testify/assert"
+	requireImport = "github.com/stretchr/testify/require"
+)
+
+// testRuns is the number of random entities each round-trip test converts
+const testRuns = 100
+
+// testReservedNames are identifiers of the generated tests that fuzz arguments must not shadow
+var testReservedNames = map[string]bool{
+	"t": true, "f": true, "r": true, "base": true, "got": true, "again": true, "gotAgain": true, "mappingErr": true,
+}
+
+// testHelpers are the helpers of the generated tests, emitted if a generated function uses them
+var testHelpers = []struct {
+	name string
+	code string
+}{
+	{"genmapperString", `// genmapperString returns a random non-empty string
+func genmapperString(r *rand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_ äöü"
+	b := make([]rune, 1+r.IntN(16))
+	runes := []rune(letters)
+	for i := range b {
+		b[i] = runes[r.IntN(len(runes))]
+	}
+	return string(b)
+}
+`},
+	{"genmapperTime", `// genmapperTime returns a random UTC time with a precision of seconds, which RFC3339 preserves
+func genmapperTime(r *rand.Rand) time.Time {
+	return time.Unix(r.Int64N(4102444800), 0).UTC()
+}
+`},
+	{"genmapperPtr", `// genmapperPtr returns a pointer to value, or nil for some of the calls
+func genmapperPtr[T any](r *rand.Rand, value T) *T {
+	if r.IntN(4) == 0 {
+		return nil
+	}
+	return &value
+}
+`},
+}
+
+// generateTestCode generates the tests of the mappers: for each DTO, a function returning an
+// entity with random values of the mapped fields, a test converting such entities to the DTO
+// and back, and a fuzzer of the fields that ToEntity parses
+func generateTestCode(config MapperConfig) (string, error) {
+	var body bytes.Buffer
+	var errs []error
+	for _, mapper := range config.Mappers {
+		if err := generateRandomEntity(&body, mapper); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", mapper.DTOName, err))
+			continue
+		}
+		generateRoundTripTest(&body, mapper)
+		generateFuzzTest(&body, mapper)
+	}
+	if len(errs) > 0 {
+		return "", fmt.Errorf("generating tests: %w", errors.Join(errs...))
+	}
+
+	code := body.String()
+	var helpers bytes.Buffer
+	usesTime := false
+	for _, helper := range testHelpers {
+		if strings.Contains(code, helper.name+"(") || strings.Contains(code, helper.name+"[") {
+			helpers.WriteString("\n" + helper.code)
+			usesTime = usesTime || helper.name == "genmapperTime"
+		}
+	}
+
+	imports := []string{"math/rand/v2", "testing"}
+	if usesTime {
+		imports = append(imports, "time")
+	}
+	for _, imp := range requiredImports(config.Mappers) {
+		if !slices.Contains(imports, imp) {
+			imports = append(imports, imp)
+		}
+	}
+	imports = append(imports, assertImport, requireImport)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by genmapper; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", config.PackageName)
+	fmt.Fprint(&buf, "import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	fmt.Fprint(&buf, ")\n\n")
+	fmt.Fprintf(&buf, "// genmapperRuns is the number of random entities each round-trip test converts\n")
+	fmt.Fprintf(&buf, "const genmapperRuns = %d\n", testRuns)
+	buf.Write(helpers.Bytes())
+	buf.WriteString("\n")
+	buf.WriteString(code)
+	return buf.String(), nil
+}
+
+// randomEntityFunc returns the name of the function returning random entities of a mapper
+func randomEntityFunc(entityName string) string {
+	return "genmapper" + entityName
+}
+
+// generateRandomEntity generates the function returning an entity with random values of the
+// fields mapped by a DTO. Validated value objects and converted fields use their sample.
+func generateRandomEntity(buf *bytes.Buffer, mapper MapperDefinition) error {
+	values := make([]string, len(mapper.Fields))
+	var errs []error
+	for i, field := range mapper.Fields {
+		value, err := randomValue(mapper, field)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.FieldName, err))
+			continue
+		}
+		values[i] = value
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	fmt.Fprintf(buf, "// %s returns an entity.%s with random values of the fields mapped by %s\n",
+		randomEntityFunc(mapper.EntityName), mapper.EntityName, mapper.DTOName)
+	fmt.Fprintf(buf, "func %s(tb testing.TB, r *rand.Rand) *entity.%s {\n", randomEntityFunc(mapper.EntityName), mapper.EntityName)
+	fmt.Fprintf(buf, "\ttb.Helper()\n")
+	for _, field := range mapper.Fields {
+		if field.Converter == nil {
+			continue
+		}
+		fmt.Fprintf(buf, "\t%s, err := %s(%s)\n", parsedName(field.FieldName), field.Converter.ToEntity, field.Converter.Sample)
+		fmt.Fprintf(buf, "\trequire.NoError(tb, err, %q)\n", "sample of "+field.JSONName)
+	}
+	fmt.Fprintf(buf, "\treturn &entity.%s{\n", mapper.EntityName)
+	for i, field := range mapper.Fields {
+		fmt.Fprintf(buf, "\t\t%s: %s,\n", field.EntityName, values[i])
+	}
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+	return nil
+}
+
+// randomValue returns the expression of a random value of the entity field of a mapping
+func randomValue(mapper MapperDefinition, field FieldMapping) (string, error) {
+	switch {
+	case field.IsIDField:
+		return fmt.Sprintf("%s(genmapperString(r))", getIDTypeForEntity(mapper.EntityName)), nil
+	case field.IsVOField && field.Sample != "":
+		return fmt.Sprintf("%s(%q)", getVOConstructor(field), field.Sample), nil
+	case field.IsVOField && field.IsRawVO:
+		return fmt.Sprintf("%s(genmapperString(r))", field.EntityType), nil
+	case field.IsVOField:
+		return "", fmt.Errorf("set a valid value with mapper:\"vo=%s,sample=...\" to generate tests", strings.TrimPrefix(field.EntityType, "valueobject."))
+	case field.IsTimeField:
+		return "genmapperTime(r)", nil
+	case field.IsOptionalAt:
+		return "genmapperPtr(r, genmapperTime(r))", nil
+	case field.Converter != nil && field.Converter.Sample == "":
+		return "", fmt.Errorf("set the sample of the converter to generate tests")
+	case field.Converter != nil:
+		return parsedName(field.FieldName), nil
+	case field.NestedDTO != "" && field.IsNestedList:
+		entityName := strings.TrimSuffix(field.NestedDTO, "DTO")
+		return fmt.Sprintf("[]*entity.%s{%s(tb, r)}", entityName, randomEntityFunc(entityName)), nil
+	case field.NestedDTO != "":
+		return fmt.Sprintf("%s(tb, r)", randomEntityFunc(strings.TrimSuffix(field.NestedDTO, "DTO"))), nil
+	default:
+		return randomCopyValue(field.DTOType), nil
+	}
+}
+
+// randomCopyValue returns the expression of a random value of a copied type
+func randomCopyValue(typeName string) string {
+	if elem, ok := strings.CutPrefix(typeName, "*"); ok {
+		return fmt.Sprintf("genmapperPtr(r, %s)", randomCopyValue(elem))
+	}
+	switch typeName {
+	case "string":
+		return "genmapperString(r)"
+	case "bool":
+		return "r.IntN(2) == 1"
+	case "time.Time":
+		return "genmapperTime(r)"
+	case "float32", "float64":
+		return fmt.Sprintf("%s(r.IntN(1000)) / 4", typeName)
+	default:
+		return fmt.Sprintf("%s(r.IntN(100))", typeName)
+	}
+}
+
+// generateRoundTripTest generates the test converting random entities to the DTO and back
+// with ToEntity and MustToEntity
+func generateRoundTripTest(buf *bytes.Buffer, mapper MapperDefinition) {
+	fmt.Fprintf(buf, "// Test%s_RoundTrip checks that entity.%s is unchanged by a conversion to %s and back\n",
+		mapper.DTOName, mapper.EntityName, mapper.DTOName)
+	fmt.Fprintf(buf, "func Test%s_RoundTrip(t *testing.T) {\n", mapper.DTOName)
+	fmt.Fprintf(buf, "\tr := rand.New(rand.NewPCG(1, 2))\n")
+	fmt.Fprintf(buf, "\tfor range genmapperRuns {\n")
+	fmt.Fprintf(buf, "\t\twant := %s(t, r)\n", randomEntityFunc(mapper.EntityName))
+	fmt.Fprintf(buf, "\t\tdto := %sFromEntity(want)\n", mapper.DTOName)
+	fmt.Fprintf(buf, "\t\tgot, err := dto.ToEntity()\n")
+	fmt.Fprintf(buf, "\t\trequire.NoError(t, err)\n")
+	fmt.Fprintf(buf, "\t\tassert.Equal(t, want, got)\n")
+	fmt.Fprintf(buf, "\t\tassert.Equal(t, want, dto.MustToEntity())\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// fuzzFields returns the string fields of a DTO that ToEntity parses
+func fuzzFields(mapper MapperDefinition) []FieldMapping {
+	var fields []FieldMapping
+	for _, field := range mapper.Fields {
+		if validatesField(field) && field.DTOType == "string" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// fuzzName returns the name of the fuzz argument of a field
+func fuzzName(fieldName string) string {
+	name := parsedName(fieldName)
+	if testReservedNames[name] {
+		name += "Value"
+	}
+	return name
+}
+
+// generateFuzzTest generates the fuzzer of the fields that ToEntity parses. ToEntity must
+// reject invalid values with a MappingError and without panicking, MustToEntity must accept
+// what ToEntity accepts, and an accepted DTO must convert to the same DTO once normalized.
+func generateFuzzTest(buf *bytes.Buffer, mapper MapperDefinition) {
+	fields := fuzzFields(mapper)
+	if len(fields) == 0 {
+		return
+	}
+
+	names := make([]string, len(fields))
+	seeds := make([]string, len(fields))
+	empty := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = fuzzName(field.FieldName)
+		seeds[i] = "base." + field.FieldName
+		empty[i] = `""`
+	}
+
+	fmt.Fprintf(buf, "// Fuzz%s_ToEntity checks that %s.ToEntity rejects invalid fields without panicking\n", mapper.DTOName, mapper.DTOName)
+	fmt.Fprintf(buf, "// and that the entities it returns convert back to the same DTO\n")
+	fmt.Fprintf(buf, "func Fuzz%s_ToEntity(f *testing.F) {\n", mapper.DTOName)
+	fmt.Fprintf(buf, "\tr := rand.New(rand.NewPCG(1, 2))\n")
+	fmt.Fprintf(buf, "\tbase := %sFromEntity(%s(f, r))\n", mapper.DTOName, randomEntityFunc(mapper.EntityName))
+	fmt.Fprintf(buf, "\tf.Add(%s)\n", strings.Join(seeds, ", "))
+	fmt.Fprintf(buf, "\tf.Add(%s)\n", strings.Join(empty, ", "))
+	fmt.Fprintf(buf, "\tf.Fuzz(func(t *testing.T, %s string) {\n", strings.Join(names, ", "))
+	fmt.Fprintf(buf, "\t\tdto := *base\n")
+	for i, field := range fields {
+		fmt.Fprintf(buf, "\t\tdto.%s = %s\n", field.FieldName, names[i])
+	}
+	fmt.Fprintf(buf, "\t\tgot, err := dto.ToEntity()\n")
+	fmt.Fprintf(buf, "\t\tif err != nil {\n")
+	fmt.Fprintf(buf, "\t\t\tvar mappingErr *MappingError\n")
+	fmt.Fprintf(buf, "\t\t\trequire.ErrorAs(t, err, &mappingErr)\n")
+	fmt.Fprintf(buf, "\t\t\treturn\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t\tassert.NotPanics(t, func() { dto.MustToEntity() })\n")
+	fmt.Fprintf(buf, "\t\tagain := %sFromEntity(got)\n", mapper.DTOName)
+	fmt.Fprintf(buf, "\t\tgotAgain, err := again.ToEntity()\n")
+	fmt.Fprintf(buf, "\t\trequire.NoError(t, err)\n")
+	fmt.Fprintf(buf, "\t\tassert.Equal(t, again, %sFromEntity(gotAgain))\n", mapper.DTOName)
+	fmt.Fprintf(buf, "\t})\n")
+	fmt.Fprintf(buf, "}\n\n")
+}
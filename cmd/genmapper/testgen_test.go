@@ -0,0 +1,81 @@
+package main
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTestCode(t *testing.T) {
+	converters := map[string]Converter{
+		"duration": {ToEntity: "time.ParseDuration", FromEntity: "FormatDuration", Sample: `"1m30s"`, Imports: []string{"time"}},
+	}
+	node, err := parser.ParseFile(token.NewFileSet(), "dto.go", `package dto
+
+type ReminderDTO struct {
+	ID       string   `+"`json:\"id\" mapper:\"id\"`"+`
+	UserID   string   `+"`json:\"user_id\" mapper:\"vo=UserID,sample=user-1\"`"+`
+	Timezone string   `+"`json:\"timezone\" mapper:\"vo=Timezone,raw,sample=UTC\"`"+`
+	Kind     string   `+"`json:\"kind\" mapper:\"vo=ReminderKind,raw\"`"+`
+	Repeat   *int     `+"`json:\"repeat\"`"+`
+	Tags     []string `+"`json:\"tags\" mapper:\"json\"`"+`
+	Snooze   string   `+"`json:\"snooze\" mapper:\"conv=duration\"`"+`
+	Owner    *UserDTO `+"`json:\"owner\" mapper:\"dto\"`"+`
+	DueAt    string   `+"`json:\"due_at\" mapper:\"time\"`"+`
+	SentAt   string   `+"`json:\"sent_at\" mapper:\"time,optional\"`"+`
+}
+`, parser.ParseComments)
+	require.NoError(t, err)
+	mappers, err := findDTOStructs(node, converters)
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", mappers[0].Fields[2].Sample)
+	assert.True(t, mappers[0].Fields[2].IsRawVO)
+
+	code, err := generateTestCode(MapperConfig{PackageName: "dto", Mappers: mappers})
+	require.NoError(t, err)
+	formatted, err := format.Source([]byte(code))
+	require.NoError(t, err, code)
+	generated := strings.Join(strings.Fields(string(formatted)), " ")
+	for _, want := range []string{
+		`"math/rand/v2"`,
+		`"github.com/stretchr/testify/require"`,
+		`func genmapperReminder(tb testing.TB, r *rand.Rand) *entity.Reminder {`,
+		`tags, err := SliceToString([]string{genmapperString(r)})`,
+		`snooze, err := time.ParseDuration("1m30s")`,
+		`ID: valueobject.ReminderID(genmapperString(r)),`,
+		`UserID: valueobject.MustNewUserID("user-1"),`,
+		`Timezone: valueobject.Timezone("UTC"),`,
+		`Kind: valueobject.ReminderKind(genmapperString(r)),`,
+		`Repeat: genmapperPtr(r, int(r.IntN(100))),`,
+		`Owner: genmapperUser(tb, r),`,
+		`SentAt: genmapperPtr(r, genmapperTime(r)),`,
+		`func TestReminderDTO_RoundTrip(t *testing.T) {`,
+		`func FuzzReminderDTO_ToEntity(f *testing.F) {`,
+		`f.Fuzz(func(t *testing.T, userID, snooze, dueAt, sentAt string) {`,
+		`func genmapperPtr[T any](r *rand.Rand, value T) *T {`,
+	} {
+		assert.Contains(t, generated, want)
+	}
+}
+
+func TestGenerateTestCode_MissingSamples(t *testing.T) {
+	mappers, err := parseDTOs(t, `package dto
+
+type NoteDTO struct {
+	Status string `+"`json:\"status\" mapper:\"vo=NoteStatus\"`"+`
+	Text   string `+"`json:\"text\"`"+`
+}
+`)
+	require.NoError(t, err)
+
+	_, err = generateTestCode(MapperConfig{PackageName: "dto", Mappers: mappers})
+	assert.ErrorContains(t, err, `NoteDTO: field Status: set a valid value with mapper:"vo=NoteStatus,sample=..." to generate tests`)
+
+	_, err = parseDTOs(t, "package dto\n\ntype NoteDTO struct {\n\tStatus string `mapper:\"vo=NoteStatus,sample=\"`\n}\n")
+	assert.ErrorContains(t, err, `invalid mapper tag "vo=NoteStatus,sample="`)
+}
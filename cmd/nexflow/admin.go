@@ -0,0 +1,452 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/sqlite"
+	"github.com/atumaikin/nexflow/pkg/client"
+)
+
+const (
+	// apiURLEnv is the URL of the HTTP API used by the admin commands instead of -api
+	apiURLEnv = "NEXFLOW_API_URL"
+
+	// apiKeyEnv is the API key the admin commands authenticate with instead of -api-key
+	apiKeyEnv = "NEXFLOW_API_KEY"
+)
+
+// adminModes describes how the user, skill and schedule subcommands reach the data
+const adminModes = `With -api <url> (or NEXFLOW_API_URL) the command talks to the HTTP API of a
+running server, authenticated by -api-key (or NEXFLOW_API_KEY); otherwise it
+opens the configured database directly.`
+
+// userUsage describes the user subcommand
+const userUsage = `usage: nexflow user <command> [-api <url>] [-api-key <key>]
+
+commands:
+  list                                      list the users
+  create -channel <type> -channel-id <id>   create a user of a channel
+
+` + adminModes
+
+// skillUsage describes the skill subcommand
+const skillUsage = `usage: nexflow skill <command> [-api <url>] [-api-key <key>]
+
+commands:
+  list                      list the registered skills
+  install [flags] <location>
+                            register the skill in the directory <location>; -name
+                            defaults to the directory name, -version to 1.0.0,
+                            -permission may be repeated and -workspace limits the
+                            skill to a workspace
+
+` + adminModes
+
+// scheduleUsage describes the schedule subcommand
+const scheduleUsage = `usage: nexflow schedule run [-api <url>] [-api-key <key>] <id>
+
+Runs a schedule now, even if it is disabled. Through the API the run is started in
+the background of the server; on the database the command waits for the run to finish
+and requires the scheduler to be enabled.
+
+` + adminModes
+
+// adminBackend performs the user, skill and schedule subcommands
+type adminBackend interface {
+	ListUsers(ctx context.Context) ([]*dto.UserDTO, error)
+	CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserDTO, error)
+	ListSkills(ctx context.Context) ([]*dto.SkillDTO, error)
+	CreateSkill(ctx context.Context, req dto.CreateSkillRequest) (*dto.SkillDTO, error)
+
+	// RunSchedule runs a schedule now; it returns true if it waited for the run to finish
+	RunSchedule(ctx context.Context, id string) (bool, error)
+}
+
+// adminFlags holds the flags selecting the backend of an admin subcommand
+type adminFlags struct {
+	apiURL string
+	apiKey string
+}
+
+// newAdminFlagSet creates the flag set of an admin subcommand with the -api and -api-key flags
+func newAdminFlagSet(name string, env *commandEnv) (*flag.FlagSet, *adminFlags) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	opts := &adminFlags{}
+	flags.StringVar(&opts.apiURL, "api", env.getenv(apiURLEnv), "URL of the HTTP API, e.g. http://localhost:8080")
+	flags.StringVar(&opts.apiKey, "api-key", env.getenv(apiKeyEnv), "API key for the HTTP API")
+	return flags, opts
+}
+
+// backend returns the backend selected by the flags
+func (f *adminFlags) backend(env *commandEnv) (adminBackend, error) {
+	if f.apiURL != "" {
+		c, err := client.New(f.apiURL, client.WithAPIKey(f.apiKey), client.WithUserAgent("nexflow-cli"))
+		if err != nil {
+			return nil, err
+		}
+		return &apiBackend{client: c}, nil
+	}
+
+	db, err := env.database()
+	if err != nil {
+		return nil, err
+	}
+	return newDatabaseBackend(env, db)
+}
+
+// runUserCommand runs the user subcommand with the given arguments
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - env: Command environment
+//   - args: Subcommand arguments (without "user")
+//
+// Returns:
+//   - error: Error if the arguments are invalid or the command failed
+func runUserCommand(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing user command\n%s", userUsage)
+	}
+
+	flags, opts := newAdminFlagSet("user "+args[0], env)
+	var channel, channelID string
+	switch args[0] {
+	case "list":
+	case "create":
+		flags.StringVar(&channel, "channel", "", "channel type, e.g. telegram")
+		flags.StringVar(&channelID, "channel-id", "", "channel-specific user identifier")
+	default:
+		return fmt.Errorf("unknown user command: %s\n%s", args[0], userUsage)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n%s", err, userUsage)
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %v\n%s", flags.Args(), userUsage)
+	}
+	if args[0] == "create" && (channel == "" || channelID == "") {
+		return fmt.Errorf("user create requires -channel and -channel-id\n%s", userUsage)
+	}
+
+	backend, err := opts.backend(env)
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "create" {
+		user, err := backend.CreateUser(ctx, dto.CreateUserRequest{Channel: channel, ChannelID: channelID})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(env.out, "user %s created\n", user.ID)
+		return nil
+	}
+
+	users, err := backend.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(env.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCHANNEL\tCHANNEL ID\tCREATED AT")
+	for _, user := range users {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", user.ID, user.Channel, user.ChannelID, user.CreatedAt)
+	}
+	return w.Flush()
+}
+
+// runSkillCommand runs the skill subcommand with the given arguments
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - env: Command environment
+//   - args: Subcommand arguments (without "skill")
+//
+// Returns:
+//   - error: Error if the arguments are invalid or the command failed
+func runSkillCommand(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing skill command\n%s", skillUsage)
+	}
+
+	flags, opts := newAdminFlagSet("skill "+args[0], env)
+	req := dto.CreateSkillRequest{}
+	switch args[0] {
+	case "list":
+	case "install":
+		flags.StringVar(&req.Name, "name", "", "skill name (default the name of the directory)")
+		flags.StringVar(&req.Version, "version", "1.0.0", "skill version")
+		flags.StringVar(&req.Workspace, "workspace", "", "workspace the skill is available in (default all workspaces)")
+		flags.Func("permission", "permission required by the skill; may be repeated", func(s string) error {
+			req.Permissions = append(req.Permissions, s)
+			return nil
+		})
+	default:
+		return fmt.Errorf("unknown skill command: %s\n%s", args[0], skillUsage)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n%s", err, skillUsage)
+	}
+
+	if args[0] == "install" {
+		if flags.NArg() != 1 {
+			return fmt.Errorf("skill install requires a skill location\n%s", skillUsage)
+		}
+		req.Location = flags.Arg(0)
+		if req.Name == "" {
+			req.Name = filepath.Base(req.Location)
+		}
+	} else if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %v\n%s", flags.Args(), skillUsage)
+	}
+
+	backend, err := opts.backend(env)
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "install" {
+		skill, err := backend.CreateSkill(ctx, req)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(env.out, "skill %s %s installed (%s)\n", skill.Name, skill.Version, skill.ID)
+		return nil
+	}
+
+	skills, err := backend.ListSkills(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(env.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tVERSION\tLOCATION\tSTATUS")
+	for _, skill := range skills {
+		status := "enabled"
+		if skill.Disabled {
+			status = "disabled"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", skill.ID, skill.Name, skill.Version, skill.Location, status)
+	}
+	return w.Flush()
+}
+
+// runScheduleCommand runs the schedule subcommand with the given arguments
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - env: Command environment
+//   - args: Subcommand arguments (without "schedule")
+//
+// Returns:
+//   - error: Error if the arguments are invalid or the run failed
+func runScheduleCommand(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) == 0 || args[0] != "run" {
+		return fmt.Errorf("unknown schedule command: %v\n%s", args, scheduleUsage)
+	}
+
+	flags, opts := newAdminFlagSet("schedule run", env)
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n%s", err, scheduleUsage)
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("schedule run requires a schedule id\n%s", scheduleUsage)
+	}
+	id := flags.Arg(0)
+
+	backend, err := opts.backend(env)
+	if err != nil {
+		return err
+	}
+
+	finished, err := backend.RunSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if finished {
+		fmt.Fprintf(env.out, "schedule %s completed\n", id)
+	} else {
+		fmt.Fprintf(env.out, "schedule %s triggered\n", id)
+	}
+	return nil
+}
+
+// apiBackend performs the admin subcommands through the HTTP API of a running server
+type apiBackend struct {
+	client *client.Client
+}
+
+// ListUsers returns all users
+func (b *apiBackend) ListUsers(ctx context.Context) ([]*dto.UserDTO, error) {
+	users, err := b.client.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.UserDTO, 0, len(users))
+	for _, user := range users {
+		result = append(result, userDTOFromClient(user))
+	}
+	return result, nil
+}
+
+// CreateUser creates a user
+func (b *apiBackend) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserDTO, error) {
+	user, err := b.client.CreateUser(ctx, client.CreateUserRequest{Channel: req.Channel, ChannelID: req.ChannelID})
+	if err != nil {
+		return nil, err
+	}
+	return userDTOFromClient(user), nil
+}
+
+// ListSkills returns all registered skills
+func (b *apiBackend) ListSkills(ctx context.Context) ([]*dto.SkillDTO, error) {
+	skills, err := b.client.ListSkills(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.SkillDTO, 0, len(skills))
+	for _, skill := range skills {
+		result = append(result, skillDTOFromClient(skill))
+	}
+	return result, nil
+}
+
+// CreateSkill registers a skill
+func (b *apiBackend) CreateSkill(ctx context.Context, req dto.CreateSkillRequest) (*dto.SkillDTO, error) {
+	skill, err := b.client.CreateSkill(ctx, client.CreateSkillRequest{
+		Name:        req.Name,
+		Version:     req.Version,
+		Location:    req.Location,
+		Permissions: req.Permissions,
+		Metadata:    req.Metadata,
+		Workspace:   req.Workspace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return skillDTOFromClient(skill), nil
+}
+
+// RunSchedule starts a run of a schedule in the background of the server
+func (b *apiBackend) RunSchedule(ctx context.Context, id string) (bool, error) {
+	_, err := b.client.RunScheduleNow(ctx, id)
+	return false, err
+}
+
+// userDTOFromClient converts a user of the API client
+func userDTOFromClient(user *client.User) *dto.UserDTO {
+	return &dto.UserDTO{
+		ID:        user.ID,
+		Channel:   user.Channel,
+		ChannelID: user.ChannelID,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// skillDTOFromClient converts a skill of the API client
+func skillDTOFromClient(skill *client.Skill) *dto.SkillDTO {
+	return &dto.SkillDTO{
+		ID:          skill.ID,
+		Name:        skill.Name,
+		Version:     skill.Version,
+		Location:    skill.Location,
+		Permissions: skill.Permissions,
+		Metadata:    skill.Metadata,
+		Disabled:    skill.Disabled,
+		CreatedAt:   skill.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// databaseBackend performs the admin subcommands directly on the database
+type databaseBackend struct {
+	env    *commandEnv
+	db     database.Database
+	users  *usecase.UserUseCase
+	skills *usecase.SkillUseCase
+}
+
+// newDatabaseBackend creates the use cases of the admin subcommands on the database
+func newDatabaseBackend(env *commandEnv, db database.Database) (*databaseBackend, error) {
+	impl, ok := db.(*database.DB)
+	if !ok {
+		return nil, fmt.Errorf("failed to assert database.Database to *database.DB")
+	}
+
+	return &databaseBackend{
+		env:    env,
+		db:     db,
+		users:  usecase.NewUserUseCase(sqlite.NewUserRepository(impl.Queries), env.logger),
+		skills: usecase.NewSkillUseCase(sqlite.NewSkillRepository(impl.Queries), nil, env.logger),
+	}, nil
+}
+
+// ListUsers returns all users
+func (b *databaseBackend) ListUsers(ctx context.Context) ([]*dto.UserDTO, error) {
+	resp, err := b.users.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Users, nil
+}
+
+// CreateUser creates a user
+func (b *databaseBackend) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserDTO, error) {
+	resp, err := b.users.CreateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.User, nil
+}
+
+// ListSkills returns all registered skills
+func (b *databaseBackend) ListSkills(ctx context.Context) ([]*dto.SkillDTO, error) {
+	resp, err := b.skills.ListSkills(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Skills, nil
+}
+
+// CreateSkill registers a skill
+func (b *databaseBackend) CreateSkill(ctx context.Context, req dto.CreateSkillRequest) (*dto.SkillDTO, error) {
+	resp, err := b.skills.CreateSkill(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Skill, nil
+}
+
+// RunSchedule runs a schedule through the scheduler of the application and waits for the run.
+// The application is set up without being started, since the run needs the LLM provider,
+// the skill runtime and the connectors the output is delivered through.
+func (b *databaseBackend) RunSchedule(ctx context.Context, id string) (bool, error) {
+	container, err := NewDIContainer(b.env.cfg, b.env.logger, b.db, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to initialize DI container: %w", err)
+	}
+
+	sched := container.Scheduler()
+	if sched == nil {
+		return false, fmt.Errorf("scheduler is disabled; set scheduler.enabled or run the schedule with -api")
+	}
+	if err := sched.RunOnce(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
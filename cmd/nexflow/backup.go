@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// backupUsage describes the backup subcommand
+const backupUsage = `usage: nexflow backup [-o <file>] [-compress=true|false]
+
+Without -o the backup is written to the configured backup directory and
+the oldest backups beyond backup.keep are removed.`
+
+// restoreUsage describes the restore subcommand
+const restoreUsage = `usage: nexflow restore <file>
+
+Replaces the database contents with the backup and applies pending migrations.`
+
+// runBackupCommand runs the backup subcommand with the given arguments
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - backup: Database to back up
+//   - cfg: Backup configuration
+//   - logger: Structured logger for logging
+//   - args: Subcommand arguments (without "backup")
+//   - out: Writer for command output
+//
+// Returns:
+//   - error: Error if the arguments are invalid or the backup failed
+func runBackupCommand(ctx context.Context, backup ports.DatabaseBackup, cfg config.BackupConfig, logger logging.Logger, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	output := flags.String("o", "", "backup file")
+	compress := flags.Bool("compress", cfg.Compress, "gzip-compress the backup")
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w\n%s", err, backupUsage)
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %v\n%s", flags.Args(), backupUsage)
+	}
+
+	if *output != "" {
+		if err := backup.Backup(ctx, *output, *compress); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "backup written to %s\n", *output)
+		return nil
+	}
+
+	uc := usecase.NewBackupUseCase(backup, backupConfigFromYAML(cfg), logger)
+	resp, err := uc.CreateBackup(ctx, dto.CreateBackupRequest{Compress: compress})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "backup written to %s (%d bytes)\n", resp.Backup.Name, resp.Backup.Size)
+	return nil
+}
+
+// runRestoreCommand runs the restore subcommand with the given arguments
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - backup: Database to restore into
+//   - args: Subcommand arguments (without "restore")
+//   - out: Writer for command output
+//
+// Returns:
+//   - error: Error if the arguments are invalid or the restore failed
+func runRestoreCommand(ctx context.Context, backup ports.DatabaseBackup, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("restore requires a backup file\n%s", restoreUsage)
+	}
+
+	if err := backup.Restore(ctx, args[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "restored %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockBackup is a mock implementation of ports.DatabaseBackup for testing
+type mockBackup struct {
+	path     string
+	compress bool
+	restored string
+}
+
+func (m *mockBackup) Backup(ctx context.Context, path string, compress bool) error {
+	m.path = path
+	m.compress = compress
+	return os.WriteFile(path, []byte("backup"), 0o600)
+}
+
+func (m *mockBackup) Restore(ctx context.Context, path string) error {
+	m.restored = path
+	return nil
+}
+
+func TestRunBackupCommand(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultBackupConfig()
+	cfg.Dir = filepath.Join(t.TempDir(), "backups")
+
+	backup := &mockBackup{}
+	var out bytes.Buffer
+	require.NoError(t, runBackupCommand(ctx, backup, cfg, logging.NewNoopLogger(), nil, &out))
+	assert.Equal(t, cfg.Dir, filepath.Dir(backup.path))
+	assert.True(t, backup.compress)
+	assert.Contains(t, out.String(), "backup written to nexflow-")
+
+	output := filepath.Join(t.TempDir(), "manual.db")
+	require.NoError(t, runBackupCommand(ctx, backup, cfg, logging.NewNoopLogger(), []string{"-o", output, "-compress=false"}, &out))
+	assert.Equal(t, output, backup.path)
+	assert.False(t, backup.compress)
+
+	assert.Error(t, runBackupCommand(ctx, backup, cfg, logging.NewNoopLogger(), []string{"-unknown"}, &out))
+	assert.Error(t, runBackupCommand(ctx, backup, cfg, logging.NewNoopLogger(), []string{"extra"}, &out))
+}
+
+func TestRunRestoreCommand(t *testing.T) {
+	ctx := context.Background()
+	backup := &mockBackup{}
+	var out bytes.Buffer
+
+	require.NoError(t, runRestoreCommand(ctx, backup, []string{"backup.db"}, &out))
+	assert.Equal(t, "backup.db", backup.restored)
+	assert.Equal(t, "restored backup.db\n", out.String())
+
+	assert.Error(t, runRestoreCommand(ctx, backup, nil, &out))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// command is a subcommand of the nexflow binary
+type command struct {
+	// name selects the command on the command line
+	name string
+
+	// run runs the command with its arguments (without the name)
+	run func(ctx context.Context, env *commandEnv, args []string) error
+}
+
+// commands lists the subcommands of the nexflow binary; serve runs if none is given
+var commands = []command{
+	{name: "serve", run: runServeCommand},
+	{name: "migrate", run: func(ctx context.Context, env *commandEnv, args []string) error {
+		db, err := env.database()
+		if err != nil {
+			return err
+		}
+		return runMigrateCommand(ctx, db, args, env.out)
+	}},
+	{name: "backup", run: func(ctx context.Context, env *commandEnv, args []string) error {
+		db, err := env.database()
+		if err != nil {
+			return err
+		}
+		return runBackupCommand(ctx, db, env.cfg.Backup, env.logger, args, env.out)
+	}},
+	{name: "restore", run: func(ctx context.Context, env *commandEnv, args []string) error {
+		db, err := env.database()
+		if err != nil {
+			return err
+		}
+		return runRestoreCommand(ctx, db, args, env.out)
+	}},
+	{name: "config", run: func(ctx context.Context, env *commandEnv, args []string) error {
+		cfg, err := env.config()
+		if err != nil {
+			return err
+		}
+		return runConfigCommand(cfg, args, env.out)
+	}},
+	{name: "validate-config", run: runValidateConfigCommand},
+	{name: "user", run: runUserCommand},
+	{name: "skill", run: runSkillCommand},
+	{name: "schedule", run: runScheduleCommand},
+	{name: "version", run: runVersionCommand},
+	{name: "init", run: runInitCommand},
+}
+
+// exitError is returned by a command that exits with a specific status
+type exitError struct {
+	code int
+	err  error
+}
+
+// Error returns the error message
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the status the process exits with after a command failed with err
+func exitCode(err error) int {
+	var exit *exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	return 1
+}
+
+// findCommand returns the subcommand with the given name
+func findCommand(name string) (command, bool) {
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == name })
+	if i < 0 {
+		return command{}, false
+	}
+	return commands[i], true
+}
+
+// commandEnv provides a command with the configuration, the logger and the database, each
+// set up on first use, so that e.g. commands talking to the HTTP API need no valid configuration
+type commandEnv struct {
+	opts   *cliOptions
+	getenv func(string) string
+	out    io.Writer
+
+	cfg         *config.Config
+	logger      logging.Logger
+	closeLogger func() error
+	db          database.Database
+}
+
+// newCommandEnv creates the environment of a command
+//
+// Parameters:
+//   - opts: Parsed command line
+//   - getenv: Function environment variables are read with
+//   - out: Writer for command output
+//
+// Returns:
+//   - *commandEnv: Environment, to be closed after the command
+func newCommandEnv(opts *cliOptions, getenv func(string) string, out io.Writer) *commandEnv {
+	return &commandEnv{opts: opts, getenv: getenv, out: out}
+}
+
+// config loads the configuration selected by the command line
+func (e *commandEnv) config() (*config.Config, error) {
+	if e.cfg != nil {
+		return e.cfg, nil
+	}
+
+	cfg, err := config.LoadProfile(e.opts.ConfigPath, e.opts.Profile, e.opts.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	e.cfg = cfg
+	return cfg, nil
+}
+
+// log returns the logger configured by logging, without the database log sink
+func (e *commandEnv) log() (logging.Logger, error) {
+	if e.logger != nil {
+		return e.logger, nil
+	}
+
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	masker, err := newLogMasker(cfg.Logging)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize log masking: %w", err)
+	}
+	logger, closeLogger, err := newLogger(cfg.Logging, masker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	e.logger, e.closeLogger = logger, closeLogger
+	return logger, nil
+}
+
+// database opens the configured database. Migrations are not applied.
+func (e *commandEnv) database() (database.Database, error) {
+	if e.db != nil {
+		return e.db, nil
+	}
+
+	logger, err := e.log()
+	if err != nil {
+		return nil, err
+	}
+	db, err := database.NewDatabase(&e.cfg.Database, database.WithLogger(logging.Named(logger, "db")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	e.db = db
+	return db, nil
+}
+
+// close closes the database and the log file if they were opened
+func (e *commandEnv) close() error {
+	var err error
+	if e.db != nil {
+		err = e.db.Close()
+	}
+	if e.closeLogger != nil {
+		if closeErr := e.closeLogger(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/sqlite"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/version"
+)
+
+// newTestEnv creates a command environment with a valid configuration on a migrated
+// SQLite database in a temporary directory
+func newTestEnv(t *testing.T, overrides ...config.Override) (*commandEnv, *bytes.Buffer) {
+	t.Helper()
+	overrides = append([]config.Override{
+		{Key: "llm.default_provider", Value: "mock"},
+		{Key: "llm.providers.mock.model", Value: "mock"},
+		{Key: "database.path", Value: filepath.Join(t.TempDir(), "nexflow.db")},
+		{Key: "database.migrations_path", Value: "../../migrations"},
+		{Key: "logging.level", Value: "error"},
+	}, overrides...)
+
+	out := &bytes.Buffer{}
+	env := newCommandEnv(&cliOptions{Overrides: overrides}, noEnv, out)
+	t.Cleanup(func() { env.close() })
+	return env, out
+}
+
+// migrate applies the migrations to the database of env
+func migrate(t *testing.T, env *commandEnv) database.Database {
+	t.Helper()
+	db, err := env.database()
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(context.Background()))
+	return db
+}
+
+func TestFindCommand(t *testing.T) {
+	for _, name := range []string{"serve", "migrate", "backup", "restore", "config", "validate-config", "user", "skill", "schedule", "version", "init"} {
+		_, ok := findCommand(name)
+		assert.True(t, ok, name)
+	}
+	_, ok := findCommand("server")
+	assert.False(t, ok)
+}
+
+func TestRunValidateConfigCommand(t *testing.T) {
+	env, out := newTestEnv(t)
+	require.NoError(t, runValidateConfigCommand(context.Background(), env, nil))
+	assert.Equal(t, "environment: configuration is valid\n", out.String())
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 0\n  prot: 8080\ndatabase:\n  type: oracle\n"), 0o600))
+	env, out = newTestEnv(t)
+	err := runValidateConfigCommand(context.Background(), env, []string{path})
+	assert.EqualError(t, err, "configuration is invalid")
+	assert.Equal(t, exitInvalidConfig, exitCode(err))
+	assert.Contains(t, out.String(), path+": configuration is invalid")
+	assert.Contains(t, out.String(), "  - server.port must be between 1 and 65535\n")
+	assert.Contains(t, out.String(), `  - database.type must be "sqlite" or "postgres", got "oracle"`)
+	assert.NotContains(t, out.String(), "unknown key")
+
+	// Unknown keys are reported in strict mode
+	env, out = newTestEnv(t)
+	err = runValidateConfigCommand(context.Background(), env, []string{"-strict", path})
+	assert.Equal(t, exitInvalidConfig, exitCode(err))
+	assert.Contains(t, out.String(), "  - unknown key server.prot\n")
+
+	assert.Equal(t, exitUsage, exitCode(runValidateConfigCommand(context.Background(), env, []string{"a.yml", "b.yml"})))
+	assert.Equal(t, exitUsage, exitCode(runValidateConfigCommand(context.Background(), env, []string{"-verbose"})))
+}
+
+func TestRunValidateConfigCommand_JSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		overrides []config.Override
+		code      int
+		failure   string
+		errPart   string
+	}{
+		{name: "valid", code: 0},
+		{name: "invalid", overrides: []config.Override{{Key: "server.port", Value: "0"}}, code: exitInvalidConfig, failure: "validation", errPart: "server.port"},
+		{name: "unparsable flag", overrides: []config.Override{{Key: "server.port", Value: "http"}}, code: exitConfigParse, failure: "parse", errPart: `invalid integer "http"`},
+		{name: "unparsable file", file: "server: [8080", code: exitConfigParse, failure: "parse", errPart: "failed to parse config file"},
+		{name: "unresolved secret", overrides: []config.Override{{Key: "llm.providers.mock.api_key", Value: "${file:/nonexistent/key}"}}, code: exitConfigSecret, failure: "secret", errPart: "failed to resolve secret ${file:/nonexistent/key}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, out := newTestEnv(t, tt.overrides...)
+			args := []string{"-json"}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.yml")
+				require.NoError(t, os.WriteFile(path, []byte(tt.file), 0o600))
+				args = append(args, path)
+			}
+
+			err := runValidateConfigCommand(context.Background(), env, args)
+			if tt.code == 0 {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.code, exitCode(err))
+			}
+
+			var report configReport
+			require.NoError(t, json.Unmarshal(out.Bytes(), &report), out.String())
+			assert.Equal(t, tt.code == 0, report.Valid)
+			assert.Equal(t, tt.failure, report.Failure)
+			if tt.errPart != "" {
+				require.NotEmpty(t, report.Errors)
+				assert.Contains(t, report.Errors[0], tt.errPart)
+			}
+		})
+	}
+}
+
+func TestRunVersionCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/nexflow/releases/latest", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]any{"tag_name": "v99.0.0", "html_url": "https://github.com/acme/nexflow/releases/tag/v99.0.0"})
+	}))
+	defer server.Close()
+	defer func(url string) { githubAPIURL = url }(githubAPIURL)
+	githubAPIURL = server.URL
+
+	// No configuration is read, so the command works without a valid one
+	out := &bytes.Buffer{}
+	env := newCommandEnv(&cliOptions{}, noEnv, out)
+	require.NoError(t, runVersionCommand(context.Background(), env, nil))
+	assert.Contains(t, out.String(), "nexflow "+version.Get().Version+" (")
+	assert.Nil(t, env.cfg)
+
+	out.Reset()
+	require.NoError(t, runVersionCommand(context.Background(), env, []string{"-json", "-check", "-repository", "acme/nexflow"}))
+	var report versionReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report), out.String())
+	assert.Equal(t, version.Get().GoVersion, report.GoVersion)
+	require.NotNil(t, report.Update)
+	require.NotNil(t, report.Update.Latest)
+	assert.Equal(t, "99.0.0", report.Update.Latest.Version)
+
+	assert.ErrorContains(t, runVersionCommand(context.Background(), env, []string{"extra"}), "unexpected arguments")
+}
+
+func TestRunInitCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ctx := context.Background()
+
+	out := &bytes.Buffer{}
+	env := newCommandEnv(&cliOptions{}, noEnv, out)
+	require.NoError(t, runInitCommand(ctx, env, nil))
+	assert.Contains(t, out.String(), "wrote config.yml\ncreated data, data/backups, skills\n")
+
+	data, err := os.ReadFile("config.yml")
+	require.NoError(t, err)
+	assert.Equal(t, config.StarterTemplate, data)
+	info, err := os.Stat("config.yml")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	for _, dir := range []string{"data", "data/backups", "skills"} {
+		assert.DirExists(t, dir)
+	}
+
+	// An existing file is only replaced with -force
+	require.NoError(t, os.WriteFile("config.yml", []byte("server: {}\n"), 0o600))
+	assert.ErrorContains(t, runInitCommand(ctx, env, nil), "config.yml already exists")
+	require.NoError(t, runInitCommand(ctx, env, []string{"-force"}))
+	data, err = os.ReadFile("config.yml")
+	require.NoError(t, err)
+	assert.Equal(t, config.StarterTemplate, data)
+
+	// The file named by -config is written
+	env = newCommandEnv(&cliOptions{ConfigPath: "nexflow.yml"}, noEnv, out)
+	require.NoError(t, runInitCommand(ctx, env, nil))
+	assert.FileExists(t, "nexflow.yml")
+
+	assert.ErrorContains(t, runInitCommand(ctx, env, []string{"config.yml"}), "unexpected arguments")
+}
+
+func TestAdminCommands_Database(t *testing.T) {
+	ctx := context.Background()
+	env, out := newTestEnv(t, config.Override{Key: "scheduler.enabled", Value: "true"})
+	db := migrate(t, env)
+
+	require.NoError(t, runUserCommand(ctx, env, []string{"create", "-channel", "telegram", "-channel-id", "42"}))
+	assert.Contains(t, out.String(), "user ")
+	assert.ErrorContains(t, runUserCommand(ctx, env, []string{"create", "-channel", "telegram", "-channel-id", "42"}), "user already exists")
+
+	out.Reset()
+	require.NoError(t, runUserCommand(ctx, env, []string{"list"}))
+	assert.Contains(t, out.String(), "CHANNEL ID")
+	assert.Contains(t, out.String(), "telegram")
+
+	out.Reset()
+	require.NoError(t, runSkillCommand(ctx, env, []string{"install", "-version", "2.0.0", "-permission", "net", "/opt/skills/echo"}))
+	assert.Contains(t, out.String(), "skill echo 2.0.0 installed")
+
+	out.Reset()
+	require.NoError(t, runSkillCommand(ctx, env, []string{"list"}))
+	assert.Contains(t, out.String(), "/opt/skills/echo")
+	assert.Contains(t, out.String(), "enabled")
+
+	// The schedule runs synchronously and is recorded in the run history
+	schedule := entity.NewSchedule("echo", "0 9 * * *", "{}")
+	require.NoError(t, sqlite.NewScheduleRepository(db.(*database.DB).Queries).Create(ctx, schedule))
+	out.Reset()
+	require.NoError(t, runScheduleCommand(ctx, env, []string{"run", string(schedule.ID)}))
+	assert.Equal(t, "schedule "+string(schedule.ID)+" completed\n", out.String())
+	runs, err := sqlite.NewScheduleRunRepository(db.(*database.DB).Queries).FindByScheduleID(ctx, string(schedule.ID), 10)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+}
+
+func TestAdminCommands_API(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users":
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "users": []any{
+				map[string]any{"id": "user-1", "channel": "telegram", "channel_id": "42", "created_at": "2026-05-01T10:00:00Z"},
+			}})
+		case "/skills":
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "weather", req["name"])
+			assert.Equal(t, "team", req["workspace"])
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "skill": map[string]any{"id": "skill-1", "name": "weather", "version": "1.0.0"}})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "schedule": map[string]any{"id": "schedule-1"}})
+		}
+	}))
+	defer server.Close()
+
+	// No configuration is loaded in API mode
+	out := &bytes.Buffer{}
+	env := newCommandEnv(&cliOptions{}, func(name string) string {
+		if name == apiKeyEnv {
+			return "secret"
+		}
+		return ""
+	}, out)
+	ctx := context.Background()
+
+	require.NoError(t, runUserCommand(ctx, env, []string{"list", "-api", server.URL}))
+	assert.Contains(t, out.String(), "user-1")
+	assert.Contains(t, out.String(), "2026-05-01T10:00:00Z")
+
+	out.Reset()
+	require.NoError(t, runSkillCommand(ctx, env, []string{"install", "-api", server.URL, "-workspace", "team", "skills/weather"}))
+	assert.Equal(t, "skill weather 1.0.0 installed (skill-1)\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runScheduleCommand(ctx, env, []string{"run", "-api", server.URL, "schedule-1"}))
+	assert.Equal(t, "schedule schedule-1 triggered\n", out.String())
+
+	assert.Equal(t, []string{"GET /users", "POST /skills", "POST /schedules/schedule-1/run-now"}, requests)
+	assert.Nil(t, env.cfg)
+}
+
+func TestAdminCommands_InvalidArguments(t *testing.T) {
+	env, _ := newTestEnv(t)
+	ctx := context.Background()
+
+	for name, err := range map[string]error{
+		"missing user command":   runUserCommand(ctx, env, nil),
+		"unknown user command":   runUserCommand(ctx, env, []string{"delete"}),
+		"user create requires":   runUserCommand(ctx, env, []string{"create", "-channel", "telegram"}),
+		"unknown skill command":  runSkillCommand(ctx, env, []string{"remove"}),
+		"requires a skill":       runSkillCommand(ctx, env, []string{"install"}),
+		"unexpected arguments":   runSkillCommand(ctx, env, []string{"list", "extra"}),
+		"requires a schedule id": runScheduleCommand(ctx, env, []string{"run"}),
+		"unknown schedule":       runScheduleCommand(ctx, env, []string{"list"}),
+	} {
+		assert.ErrorContains(t, err, name)
+	}
+	assert.Nil(t, env.db, "the database is not opened for invalid arguments")
+}
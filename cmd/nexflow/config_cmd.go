@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+)
+
+// configUsage describes the config subcommand
+const configUsage = `usage: nexflow config print
+
+print  print the effective configuration (config file, NEXFLOW_* environment
+       variables and flags merged) as YAML, with secrets masked`
+
+// runConfigCommand runs the config subcommand with the given arguments
+//
+// Parameters:
+//   - cfg: Effective configuration
+//   - args: Subcommand arguments (without "config")
+//   - out: Writer for command output
+//
+// Returns:
+//   - error: Error if the arguments are invalid
+func runConfigCommand(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 || args[0] != "print" {
+		return fmt.Errorf("unknown config command: %v\n%s", args, configUsage)
+	}
+
+	data, err := yaml.Marshal(cfg.Masked())
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	_, err = out.Write(data)
+	return err
+}
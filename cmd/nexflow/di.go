@@ -0,0 +1,1984 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/analytics"
+	"github.com/atumaikin/nexflow/internal/application/cluster"
+	"github.com/atumaikin/nexflow/internal/application/documents"
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/jobqueue"
+	"github.com/atumaikin/nexflow/internal/application/llmhealth"
+	"github.com/atumaikin/nexflow/internal/application/orchestrator"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/application/privacy"
+	"github.com/atumaikin/nexflow/internal/application/retention"
+	"github.com/atumaikin/nexflow/internal/application/router"
+	"github.com/atumaikin/nexflow/internal/application/scheduler"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/application/webhook"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	channelmock "github.com/atumaikin/nexflow/internal/infrastructure/channels/mock"
+	telegramconn "github.com/atumaikin/nexflow/internal/infrastructure/channels/telegram"
+	httpinf "github.com/atumaikin/nexflow/internal/infrastructure/http"
+	llmadapter "github.com/atumaikin/nexflow/internal/infrastructure/llm"
+	anthropic "github.com/atumaikin/nexflow/internal/infrastructure/llm/anthropic"
+	llmmock "github.com/atumaikin/nexflow/internal/infrastructure/llm/mock"
+	ollama "github.com/atumaikin/nexflow/internal/infrastructure/llm/ollama"
+	openai "github.com/atumaikin/nexflow/internal/infrastructure/llm/openai"
+	"github.com/atumaikin/nexflow/internal/infrastructure/llm/tokenizer"
+	"github.com/atumaikin/nexflow/internal/infrastructure/llm/zai"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/sqlite"
+	"github.com/atumaikin/nexflow/internal/infrastructure/skills"
+	skillmock "github.com/atumaikin/nexflow/internal/infrastructure/skills/mock"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/i18n"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+	"github.com/atumaikin/nexflow/internal/shared/shutdown"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
+	"github.com/atumaikin/nexflow/internal/shared/version"
+)
+
+// sessionAttributeExpirySchedule is the cron expression of the job that purges expired session attributes
+const sessionAttributeExpirySchedule = "*/15 * * * *"
+
+// processedMessageExpirySchedule is the cron expression of the job that purges expired records of processed messages
+const processedMessageExpirySchedule = "*/15 * * * *"
+
+// idempotencyKeyExpirySchedule is the cron expression of the job that purges expired idempotency keys
+const idempotencyKeyExpirySchedule = "0 * * * *"
+
+// messageJobExpirySchedule is the cron expression of the job that purges finished asynchronous message jobs
+const messageJobExpirySchedule = "30 * * * *"
+
+// jobExpirySchedule is the cron expression of the job that purges finished jobs of the job queue
+const jobExpirySchedule = "45 * * * *"
+
+// DIContainer holds all application dependencies
+
+// routerConfigFromYAML creates router.Config from shared config.RouterConfig
+func routerConfigFromYAML(cfg config.RouterConfig, channelsCfg config.ChannelsConfig) *router.Config {
+	retryCfg := router.RetryConfig{
+		MaxAttempts:       cfg.RetryMaxAttempts,
+		InitialDelay:     time.Duration(cfg.RetryInitialDelayMs) * time.Millisecond,
+		MaxDelay:         time.Duration(cfg.RetryMaxDelayMs) * time.Millisecond,
+		BackoffMultiplier: cfg.RetryBackoffMultiplier,
+	}
+
+	return &router.Config{
+		MaxMessageLength: cfg.MaxMessageLength,
+		ValidationEnabled: cfg.ValidationEnabled,
+		RetryConfig:     retryCfg,
+		Workers:          cfg.Workers,
+		QueueSize:        cfg.QueueSize,
+		DrainTimeout:     time.Duration(cfg.DrainTimeoutSec) * time.Second,
+		Session: router.SessionPolicy{
+			IdleTimeout: time.Duration(cfg.Session.IdleTimeoutSec) * time.Second,
+			MaxAge:      time.Duration(cfg.Session.MaxAgeSec) * time.Second,
+			MaxMessages: cfg.Session.MaxMessages,
+		},
+		Commands: router.CommandConfig{
+			Prefixes:  cfg.Commands.Prefixes,
+			Admins:    cfg.Commands.Admins,
+			AdminOnly: cfg.Commands.AdminOnly,
+		},
+		Workspaces: channelsCfg.Workspaces(),
+		I18n: router.I18nConfig{
+			DefaultLanguage: cfg.I18n.DefaultLanguage,
+			Languages:       cfg.I18n.Languages,
+			Messages:        i18nMessagesFromYAML(cfg.I18n.Messages),
+		},
+		PostProcessing: postProcessingConfigFromYAML(cfg.PostProcessing),
+		FeedbackButtons: cfg.FeedbackButtons,
+	}
+}
+
+// postProcessingConfigFromYAML creates the router post-processing of the replies by connector
+func postProcessingConfigFromYAML(cfg config.PostProcessingConfig) router.PostProcessingConfig {
+	connectors := make(map[string]router.PostProcessing, len(cfg.Connectors))
+	for name, pp := range cfg.Connectors {
+		connectors[name] = postProcessingFromYAML(pp)
+	}
+	return router.PostProcessingConfig{
+		Default:    postProcessingFromYAML(cfg.Default),
+		Connectors: connectors,
+	}
+}
+
+// postProcessingFromYAML creates the router post-processing of the replies of a connector
+func postProcessingFromYAML(cfg config.ResponsePostProcessing) router.PostProcessing {
+	return router.PostProcessing{
+		StripReasoning: cfg.StripReasoning,
+		MaxLength:      cfg.MaxLength,
+		Citations:      cfg.Citations,
+		Format:         router.ResponseFormat(cfg.Format),
+	}
+}
+
+// i18nMessagesFromYAML converts the configured translations to i18n message maps
+func i18nMessagesFromYAML(messages map[string]map[string]string) map[string]i18n.Messages {
+	converted := make(map[string]i18n.Messages, len(messages))
+	for language, translations := range messages {
+		converted[language] = translations
+	}
+	return converted
+}
+
+// orchestratorConfigFromYAML creates orchestrator.Config from shared config.OrchestratorConfig
+func orchestratorConfigFromYAML(cfg config.OrchestratorConfig) orchestrator.Config {
+	return orchestrator.Config{
+		Planning: orchestrator.PlanningConfig{
+			Enabled:           cfg.Planning.Enabled,
+			MaxSteps:          cfg.Planning.MaxSteps,
+			MinLength:         cfg.Planning.MinLength,
+			MaxParallelSkills: cfg.Planning.MaxParallelSkills,
+			SkillTimeout:      time.Duration(cfg.Planning.SkillTimeoutSec) * time.Second,
+		},
+		Tools: orchestrator.ToolsConfig{
+			Enabled:     cfg.Tools.Enabled,
+			MaxRounds:   cfg.Tools.MaxRounds,
+			MaxParallel: cfg.Tools.MaxParallel,
+			Timeout:     time.Duration(cfg.Tools.TimeoutSec) * time.Second,
+		},
+	}
+}
+
+// schedulerConfigFromYAML creates scheduler.Config from shared config.SchedulerConfig
+func schedulerConfigFromYAML(cfg config.SchedulerConfig) *scheduler.Config {
+	defaults := scheduler.DefaultConfig()
+
+	schedCfg := &scheduler.Config{
+		MaxConcurrent:    cfg.MaxConcurrent,
+		ExecutionTimeout: time.Duration(cfg.ExecutionTimeoutSec) * time.Second,
+	}
+	if schedCfg.MaxConcurrent == 0 {
+		schedCfg.MaxConcurrent = defaults.MaxConcurrent
+	}
+	if schedCfg.ExecutionTimeout == 0 {
+		schedCfg.ExecutionTimeout = defaults.ExecutionTimeout
+	}
+
+	return schedCfg
+}
+
+// retentionConfigFromYAML creates retention.Config from shared config.RetentionConfig
+func retentionConfigFromYAML(cfg config.RetentionConfig) *retention.Config {
+	defaults := retention.DefaultConfig()
+	day := 24 * time.Hour
+
+	retCfg := &retention.Config{
+		Interval:  time.Duration(cfg.IntervalSec) * time.Second,
+		DryRun:    cfg.DryRun,
+		BatchSize: defaults.BatchSize,
+		Messages:  retention.Policy{MaxAge: time.Duration(cfg.Messages.MaxAgeDays) * day, Archive: cfg.Messages.Archive},
+		Tasks:     retention.Policy{MaxAge: time.Duration(cfg.Tasks.MaxAgeDays) * day, Archive: cfg.Tasks.Archive},
+		Logs:      retention.Policy{MaxAge: time.Duration(cfg.Logs.MaxAgeDays) * day, Archive: cfg.Logs.Archive},
+	}
+	if retCfg.Interval == 0 {
+		retCfg.Interval = defaults.Interval
+	}
+
+	return retCfg
+}
+
+// documentsConfigFromYAML creates documents.Config from shared config.DocumentsConfig
+func documentsConfigFromYAML(cfg config.DocumentsConfig) *documents.Config {
+	return &documents.Config{
+		MaxSize:      int64(cfg.MaxFileSizeMB) << 20,
+		ChunkSize:    cfg.ChunkSize,
+		ChunkOverlap: cfg.ChunkOverlap,
+		MaxChunks:    cfg.MaxChunks,
+		TopK:         cfg.TopK,
+	}
+}
+
+// piiConfigFromYAML creates privacy.Config from shared config.PIIConfig
+func piiConfigFromYAML(cfg config.PIIConfig) (*privacy.Config, error) {
+	key, err := cfg.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	piiCfg := &privacy.Config{Mode: privacy.Mode(cfg.Mode), Key: key}
+	if piiCfg.Mode == "" {
+		piiCfg.Mode = privacy.ModeOff
+	}
+	if len(cfg.Workspaces) > 0 {
+		piiCfg.Workspaces = make(map[string]privacy.Mode, len(cfg.Workspaces))
+		for workspace, mode := range cfg.Workspaces {
+			piiCfg.Workspaces[workspace] = privacy.Mode(mode)
+		}
+	}
+
+	return piiCfg, nil
+}
+
+// backupConfigFromYAML creates usecase.BackupConfig from shared config.BackupConfig
+func backupConfigFromYAML(cfg config.BackupConfig) usecase.BackupConfig {
+	return usecase.BackupConfig{
+		Dir:      cfg.Dir,
+		Compress: cfg.Compress,
+		Keep:     cfg.Keep,
+	}
+}
+
+// messageJobConfigFromYAML creates usecase.MessageJobConfig from shared config.MessageJobsConfig
+func messageJobConfigFromYAML(cfg config.MessageJobsConfig) usecase.MessageJobConfig {
+	return usecase.MessageJobConfig{
+		Workers:      cfg.Workers,
+		QueueSize:    cfg.QueueSize,
+		MaxBatchSize: cfg.MaxBatchSize,
+		TTL:          time.Duration(cfg.TTLHours) * time.Hour,
+	}
+}
+
+// skillRetryPolicyFromYAML maps a configured skill retry policy to the ChatUseCase retry policy
+func skillRetryPolicyFromYAML(cfg config.SkillRetryConfig) usecase.SkillRetryPolicy {
+	return usecase.SkillRetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     time.Duration(cfg.BackoffMs) * time.Millisecond,
+		MaxBackoff:  time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+	}
+}
+
+// skillRetryPoliciesFromYAML maps the retry policies overridden by skill, completed by the default policy
+func skillRetryPoliciesFromYAML(cfg config.SkillsConfig) map[string]usecase.SkillRetryPolicy {
+	policies := make(map[string]usecase.SkillRetryPolicy, len(cfg.Retries))
+	for skill := range cfg.Retries {
+		policies[skill] = skillRetryPolicyFromYAML(cfg.RetryFor(skill))
+	}
+	return policies
+}
+
+// promptFragmentsFromYAML maps the configured prompt fragments of workspaces, connectors and skills
+func promptFragmentsFromYAML(cfg config.LLMPromptsConfig) []ports.PromptFragment {
+	var fragments []ports.PromptFragment
+	add := func(scope string, prompts map[string]string) {
+		for name, prompt := range prompts {
+			fragments = append(fragments, ports.PromptFragment{Scope: scope, Name: name, Prompt: prompt})
+		}
+	}
+	add(ports.PromptScopeWorkspace, cfg.Workspaces)
+	add(ports.PromptScopeConnector, cfg.Connectors)
+	add(ports.PromptScopeSkill, cfg.Skills)
+	return fragments
+}
+
+// adminConfigFromYAML maps the configured LLM providers to the AdminUseCase configuration
+func adminConfigFromYAML(cfg config.LLMConfig) usecase.AdminConfig {
+	models := make(map[string]string, len(cfg.Providers))
+	for name, provider := range cfg.Providers {
+		models[name] = provider.Model
+	}
+	return usecase.AdminConfig{LLMModels: models}
+}
+
+// llmProviderLimitsFromYAML maps a configured LLM provider to the limits of the messages it answers
+func llmProviderLimitsFromYAML(cfg config.LLMProvider) llmadapter.ProviderLimits {
+	return llmadapter.ProviderLimits{
+		Model:         cfg.Model,
+		Models:        cfg.Models,
+		Temperature:   cfg.Temperature,
+		MaxTokens:     cfg.MaxTokens,
+		ContextTokens: cfg.ContextTokens,
+	}
+}
+
+// llmHealthConfigFromYAML creates llmhealth.Config from shared config.LLMHealthCheckConfig
+func llmHealthConfigFromYAML(cfg config.LLMHealthCheckConfig) *llmhealth.Config {
+	return &llmhealth.Config{
+		Interval:         time.Duration(cfg.IntervalSec) * time.Second,
+		Timeout:          time.Duration(cfg.TimeoutSec) * time.Second,
+		History:          cfg.History,
+		FailureThreshold: cfg.FailureThreshold,
+	}
+}
+
+// jobQueueConfigFromYAML creates jobqueue.Config from shared config.JobsConfig
+func jobQueueConfigFromYAML(cfg config.JobsConfig) *jobqueue.Config {
+	return &jobqueue.Config{
+		Workers:           cfg.Workers,
+		PollInterval:      time.Duration(cfg.PollIntervalMs) * time.Millisecond,
+		VisibilityTimeout: time.Duration(cfg.VisibilityTimeoutSec) * time.Second,
+		MaxAttempts:       cfg.MaxAttempts,
+		RetryBackoff:      time.Duration(cfg.RetryBackoffMs) * time.Millisecond,
+		MaxRetryBackoff:   time.Duration(cfg.MaxRetryBackoffSec) * time.Second,
+		TTL:               time.Duration(cfg.TTLHours) * time.Hour,
+	}
+}
+
+// clusterConfigFromYAML creates cluster.Config from shared config.ClusterConfig
+func clusterConfigFromYAML(cfg config.ClusterConfig) *cluster.Config {
+	return &cluster.Config{
+		InstanceID:    cfg.InstanceID,
+		LeaseTTL:      time.Duration(cfg.LeaseTTLSec) * time.Second,
+		RenewInterval: time.Duration(cfg.RenewIntervalSec) * time.Second,
+	}
+}
+
+// webhookConfigFromYAML creates webhook.Config from shared config.WebhooksConfig
+func webhookConfigFromYAML(cfg config.WebhooksConfig) *webhook.Config {
+	defaults := webhook.DefaultConfig()
+
+	whCfg := &webhook.Config{
+		Timeout:      time.Duration(cfg.TimeoutSec) * time.Second,
+		MaxAttempts:  cfg.MaxAttempts,
+		RetryBackoff: time.Duration(cfg.RetryBackoffMs) * time.Millisecond,
+		Workers:      cfg.Workers,
+		QueueSize:    cfg.QueueSize,
+	}
+	if whCfg.Timeout == 0 {
+		whCfg.Timeout = defaults.Timeout
+	}
+	if whCfg.MaxAttempts == 0 {
+		whCfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if whCfg.Workers == 0 {
+		whCfg.Workers = defaults.Workers
+	}
+	if whCfg.QueueSize == 0 {
+		whCfg.QueueSize = defaults.QueueSize
+	}
+	for _, endpoint := range cfg.Endpoints {
+		whCfg.Endpoints = append(whCfg.Endpoints, webhook.Endpoint{
+			Name:   endpoint.Name,
+			URL:    endpoint.URL,
+			Secret: endpoint.Secret,
+			Events: endpoint.Events,
+		})
+	}
+
+	return whCfg
+}
+
+// webhookUseCaseConfigFromYAML lists the configured webhook endpoints, without their secrets, for the WebhookUseCase
+func webhookUseCaseConfigFromYAML(cfg config.WebhooksConfig) usecase.WebhookConfig {
+	endpoints := make([]*dto.WebhookEndpointDTO, 0, len(cfg.Endpoints))
+	for _, endpoint := range cfg.Endpoints {
+		endpoints = append(endpoints, &dto.WebhookEndpointDTO{
+			Name:   endpoint.Name,
+			URL:    endpoint.URL,
+			Events: endpoint.Events,
+			Signed: endpoint.Secret != "",
+		})
+	}
+	return usecase.WebhookConfig{Enabled: cfg.Enabled, Endpoints: endpoints}
+}
+
+type DIContainer struct {
+	config  *config.Config
+	logger  logging.Logger
+	db      database.Database
+	sqlDB   *sql.DB
+	queries *database.Queries
+	tracer  *tracing.Tracer // nil when tracing is disabled
+
+	// Event Bus
+	eventBus *eventbus.EventBus
+
+	// Repositories
+	userRepo        repository.UserRepository
+	sessionRepo     repository.SessionRepository
+	messageRepo     repository.MessageRepository
+	taskRepo        repository.TaskRepository
+	skillRepo       repository.SkillRepository
+	scheduleRepo    repository.ScheduleRepository
+	scheduleRunRepo repository.ScheduleRunRepository
+	logRepo         repository.LogRepository
+	attributeRepo   repository.SessionAttributeRepository
+	apiKeyRepo      repository.APIKeyRepository
+	workspaceRepo   repository.WorkspaceRepository
+	prefsRepo       repository.UserPreferencesRepository
+	webhookRepo     repository.WebhookDeliveryRepository
+	analyticsRepo   repository.AnalyticsRepository
+	usageRepo       repository.LLMUsageRepository
+	idempotencyRepo repository.IdempotencyRepository
+	messageJobRepo  repository.MessageJobRepository
+	feedbackRepo    repository.MessageFeedbackRepository
+	erasureRepo     repository.UserDataErasureRepository
+	eventRepo       repository.EventRepository
+	deadLetterRepo  repository.MessageDeadLetterRepository
+	crashRepo       repository.CrashReportRepository
+	documentRepo    repository.DocumentChunkRepository
+
+	// Recovery of panics in the router, connectors, skills and HTTP handlers
+	recoverer *crash.Recoverer
+
+	// Ports
+	llmProvider  ports.LLMProvider
+	llmMetrics   *llmadapter.LLMMetrics
+	llmHealth    *llmhealth.Monitor // Availability checks of the LLM providers (nil if disabled)
+	tokenizer    ports.Tokenizer    // Counts the tokens of LLM requests and replies
+	skillRuntime ports.SkillRuntime
+
+	// Orchestrator
+	orchestrator ports.Orchestrator
+
+	// Connectors
+	telegramConnector channels.Connector
+	telegramBots      []channels.Connector // Further Telegram bots, e.g. one per workspace
+	discordConnector  channels.Connector
+	webConnector      channels.Connector
+	incomingMetrics   *channels.IncomingMetrics // Incoming queue metrics of the connectors
+
+	// Router
+	messageRouter *router.MessageRouter
+	deduplicator  *router.Deduplicator
+
+	// Scheduler
+	scheduler *scheduler.Scheduler
+
+	// Persistent queue of background work
+	jobQueue *jobqueue.Queue
+
+	// Leader election of the instances sharing the database
+	elector *cluster.Elector
+
+	// Data retention
+	retention *retention.Retention
+
+	// Outbound webhooks
+	webhookDispatcher *webhook.Dispatcher
+
+	// Conversation analytics
+	analyticsCollector *analytics.Collector
+	usageRecorder      *analytics.UsageRecorder
+
+	// Questions about the documents sent to the bot
+	documents *documents.Service
+
+	// Check for newer releases
+	updateChecker *version.Checker
+
+	// Use Cases
+	chatUseCase     *usecase.ChatUseCase
+	userUseCase     *usecase.UserUseCase
+	skillUseCase    *usecase.SkillUseCase
+	scheduleUseCase *usecase.ScheduleUseCase
+	backupUseCase   *usecase.BackupUseCase
+	stateUseCase    *usecase.SessionStateUseCase
+	handoffUseCase  *usecase.HandoffUseCase
+	apiKeyUseCase   *usecase.APIKeyUseCase
+	workspaceUseCase *usecase.WorkspaceUseCase
+	prefsUseCase    *usecase.UserPreferencesUseCase
+	userDataUseCase *usecase.UserDataUseCase
+	adminUseCase    *usecase.AdminUseCase
+	webhookUseCase  *usecase.WebhookUseCase
+	analyticsUseCase *usecase.AnalyticsUseCase
+	usageUseCase     *usecase.UsageUseCase
+	feedbackUseCase *usecase.FeedbackUseCase
+	messageJobUseCase *usecase.MessageJobUseCase
+	logUseCase      *usecase.LogUseCase
+	crashReportUseCase *usecase.CrashReportUseCase
+
+	// HTTP Handlers
+	userHandler     *httpinf.UserHandler
+	sessionHandler  *httpinf.SessionHandler
+	messageHandler  *httpinf.MessageHandler
+	messageJobHandler *httpinf.MessageJobHandler
+	chatWSHandler   *httpinf.ChatWSHandler
+	taskHandler     *httpinf.TaskHandler
+	skillHandler    *httpinf.SkillHandler
+	scheduleHandler *httpinf.ScheduleHandler
+	logHandler      *httpinf.LogHandler
+	backupHandler   *httpinf.BackupHandler
+	healthHandler   *httpinf.HealthHandler
+	metricsHandler  *httpinf.MetricsHandler
+	stateHandler    *httpinf.SessionStateHandler
+	handoffHandler  *httpinf.HandoffHandler
+	apiKeyHandler   *httpinf.APIKeyHandler
+	workspaceHandler *httpinf.WorkspaceHandler
+	prefsHandler    *httpinf.UserPreferencesHandler
+	userDataHandler *httpinf.UserDataHandler
+	adminHandler    *httpinf.AdminHandler
+	eventsHandler   *httpinf.EventsHandler
+	loggingHandler  *httpinf.LoggingHandler
+	webhookHandler  *httpinf.WebhookHandler
+	analyticsHandler *httpinf.AnalyticsHandler
+	usageHandler     *httpinf.UsageHandler
+	feedbackHandler *httpinf.FeedbackHandler
+	crashReportHandler *httpinf.CrashReportHandler
+	versionHandler  *httpinf.VersionHandler
+
+	// HTTP API authentication, rate limiting and idempotency keys
+	authenticator *httpinf.Authenticator
+	rateLimiter   *httpinf.RateLimiter
+	idempotency   *httpinf.Idempotency
+}
+
+// NewDIContainer creates and initializes the DI container.
+// tracer records the spans of incoming messages; nil disables tracing.
+func NewDIContainer(cfg *config.Config, logger logging.Logger, db database.Database, tracer *tracing.Tracer) (*DIContainer, error) {
+	// Get underlying SQL DB for repository implementations
+	// Type assertion to get *sql.DB from database.Database
+	dbImpl, ok := db.(*database.DB)
+	if !ok {
+		return nil, fmt.Errorf("failed to assert database.Database to *database.DB")
+	}
+	sqlDB := dbImpl.GetDB()
+
+	container := &DIContainer{
+		config:  cfg,
+		logger:  logger,
+		db:      db,
+		sqlDB:   sqlDB,
+		queries: dbImpl.Queries, // instrumented with query metrics and slow query logging
+		tracer:  tracer,
+	}
+
+	// Initialize Event Bus
+	if err := container.initEventBus(); err != nil {
+		return nil, err
+	}
+
+	// Initialize repositories
+	if err := container.initRepositories(); err != nil {
+		return nil, err
+	}
+
+	// Initialize panic recovery
+	if err := container.initCrashReporting(); err != nil {
+		return nil, err
+	}
+
+	// Initialize the job queue
+	container.initJobQueue()
+
+	// Initialize the leader election
+	container.initCluster()
+
+	// Initialize ports
+	if err := container.initPorts(); err != nil {
+		return nil, err
+	}
+
+	// Initialize connectors
+	if err := container.initConnectors(); err != nil {
+		return nil, err
+	}
+
+	// Initialize message router
+	if err := container.initMessageRouter(); err != nil {
+		return nil, err
+	}
+
+	// Initialize use cases
+	if err := container.initUseCases(); err != nil {
+		return nil, err
+	}
+
+	// Initialize scheduler
+	if err := container.initScheduler(); err != nil {
+		return nil, err
+	}
+
+	// Initialize data retention
+	if err := container.initRetention(); err != nil {
+		return nil, err
+	}
+
+	// Initialize outbound webhooks
+	if err := container.initWebhooks(); err != nil {
+		return nil, err
+	}
+
+	// Initialize conversation analytics
+	container.initAnalytics()
+
+	// Initialize the update check
+	container.initUpdateCheck()
+
+	// Initialize HTTP handlers
+	if err := container.initHandlers(); err != nil {
+		return nil, err
+	}
+
+	return container, nil
+}
+
+// initRepositories initializes all repository implementations
+func (c *DIContainer) initRepositories() error {
+	// User repository
+	c.userRepo = sqlite.NewUserRepository(c.queries)
+
+	// Session repository
+	c.sessionRepo = sqlite.NewSessionRepository(c.queries)
+
+	// Message repository
+	c.messageRepo = sqlite.NewMessageRepository(c.queries)
+
+	// Task repository
+	c.taskRepo = sqlite.NewTaskRepository(c.queries)
+
+	// Skill repository
+	c.skillRepo = sqlite.NewSkillRepository(c.queries)
+
+	// Schedule repository
+	c.scheduleRepo = sqlite.NewScheduleRepository(c.queries)
+
+	// Schedule run history repository
+	c.scheduleRunRepo = sqlite.NewScheduleRunRepository(c.queries)
+
+	// Log repository
+	c.logRepo = sqlite.NewLogRepository(c.queries)
+
+	// Session attribute repository
+	c.attributeRepo = sqlite.NewSessionAttributeRepository(c.queries)
+
+	// API key repository
+	c.apiKeyRepo = sqlite.NewAPIKeyRepository(c.queries)
+
+	// Workspace repository
+	c.workspaceRepo = sqlite.NewWorkspaceRepository(c.queries)
+
+	// User preferences repository
+	c.prefsRepo = sqlite.NewUserPreferencesRepository(c.queries)
+
+	// Webhook delivery log repository
+	c.webhookRepo = sqlite.NewWebhookDeliveryRepository(c.queries)
+
+	// User data erasure audit log repository
+	c.erasureRepo = sqlite.NewUserDataErasureRepository(c.queries)
+
+	// Event store repository
+	c.eventRepo = sqlite.NewEventRepository(c.queries)
+
+	// Dead-letter queue of the message router
+	c.deadLetterRepo = sqlite.NewMessageDeadLetterRepository(c.queries)
+
+	// Analytics counters repository
+	c.analyticsRepo = sqlite.NewAnalyticsRepository(c.queries)
+
+	// LLM usage repository
+	c.usageRepo = sqlite.NewLLMUsageRepository(c.queries)
+
+	// Idempotency keys of write API requests
+	c.idempotencyRepo = sqlite.NewIdempotencyRepository(c.queries)
+
+	// Jobs of the asynchronous message API
+	c.messageJobRepo = sqlite.NewMessageJobRepository(c.queries)
+
+	// Reply feedback repository
+	c.feedbackRepo = sqlite.NewMessageFeedbackRepository(c.queries)
+
+	// Crash log repository
+	c.crashRepo = sqlite.NewCrashReportRepository(c.queries)
+
+	// Document chunk repository
+	c.documentRepo = sqlite.NewDocumentChunkRepository(c.queries)
+
+	// Personal data of messages, stored events and message dead letters is redacted before
+	// they are stored
+	piiCfg, err := piiConfigFromYAML(c.config.PII)
+	if err != nil {
+		return err
+	}
+	if piiCfg.Enabled() {
+		redacting, err := privacy.NewMessageRepository(c.messageRepo, c.sessionRepo, piiCfg)
+		if err != nil {
+			return fmt.Errorf("failed to create message redaction: %w", err)
+		}
+		c.messageRepo = redacting
+		if c.eventRepo, err = c.redactEvents(c.eventRepo); err != nil {
+			return err
+		}
+		redactingLetters, err := privacy.NewMessageDeadLetterRepository(c.deadLetterRepo, c.config.Channels.Workspaces(), piiCfg)
+		if err != nil {
+			return fmt.Errorf("failed to create dead letter redaction: %w", err)
+		}
+		c.deadLetterRepo = redactingLetters
+		c.logger.Info("personal data redaction enabled", "mode", piiCfg.Mode, "workspaces", len(piiCfg.Workspaces))
+	}
+
+	c.logger.Info("repositories initialized successfully")
+	return nil
+}
+
+// initJobQueue initializes the persistent queue the background work of the use cases, the
+// scheduler and the webhooks is run from. Without it, that work is kept in memory.
+func (c *DIContainer) initJobQueue() {
+	if !c.config.Jobs.Enabled {
+		c.logger.Info("job queue disabled, background work is lost on restart")
+		return
+	}
+
+	c.jobQueue = jobqueue.NewQueue(sqlite.NewJobRepository(c.queries), logging.Named(c.logger, "jobs"), jobQueueConfigFromYAML(c.config.Jobs))
+	c.logger.Info("job queue initialized successfully", "workers", c.config.Jobs.Workers)
+}
+
+// initCluster initializes the leader election of the instances sharing the database. Without
+// it, this instance runs everything as if it were the only one.
+func (c *DIContainer) initCluster() {
+	if !c.config.Cluster.Enabled {
+		return
+	}
+
+	c.elector = cluster.NewElector(sqlite.NewLeaseRepository(c.queries), logging.Named(c.logger, "cluster"), clusterConfigFromYAML(c.config.Cluster))
+	if c.jobQueue != nil {
+		c.jobQueue.SetLeadership(c.elector)
+	} else {
+		c.logger.Warn("job queue disabled, messages sent through connectors running on the leader fail on the other instances")
+	}
+	c.logger.Warn("instances of a cluster share a SQLite file and must run on the same host")
+
+	c.logger.Info("leader election initialized successfully", "instance_id", c.elector.InstanceID())
+}
+
+// initCrashReporting initializes the recovery of panics and their reporters
+func (c *DIContainer) initCrashReporting() error {
+	masker, err := newLogMasker(c.config.Logging)
+	if err != nil {
+		return fmt.Errorf("failed to create log masker: %w", err)
+	}
+	c.recoverer, err = newRecoverer(c.config.Observability, c.logger, c.crashRepo, masker)
+	if err != nil {
+		return fmt.Errorf("failed to initialize crash reporting: %w", err)
+	}
+	return nil
+}
+
+// initEventBus initializes the event bus
+func (c *DIContainer) initEventBus() error {
+	// Check if event bus is enabled in configuration
+	if !c.config.EventBus.Enabled {
+		c.logger.Info("event bus disabled in configuration")
+		return nil
+	}
+
+	// Create event bus configuration from application config
+	ebConfig := &eventbus.EventBusConfig{
+		BatchSize:            c.config.EventBus.BatchSize,
+		FlushInterval:        time.Duration(c.config.EventBus.FlushIntervalMs) * time.Millisecond,
+		BufferSize:           c.config.EventBus.BufferSize,
+		SubscriberBufferSize: c.config.EventBus.SubscriberBufferSize,
+		Overflow:             eventbus.OverflowPolicy(c.config.EventBus.OverflowPolicy),
+		Logger:               c.logger,
+	}
+
+	// Retry failing handlers, then keep their events as dead letters
+	retry := c.config.EventBus.HandlerRetry
+	ebConfig.Retry = &eventbus.RetryPolicy{
+		MaxAttempts: retry.MaxAttempts,
+		Backoff:     time.Duration(retry.BackoffMs) * time.Millisecond,
+		MaxBackoff:  time.Duration(retry.MaxBackoffMs) * time.Millisecond,
+	}
+	if c.config.EventBus.DeadLetters.Persist {
+		ebConfig.DeadLetters = sqlite.NewDeadLetterRepository(c.queries)
+		c.logger.Info("persistent dead-letter queue enabled")
+	} else {
+		ebConfig.DeadLetters = eventbus.NewMemoryDeadLetterStore(c.config.EventBus.DeadLetters.Capacity)
+	}
+
+	// Persist events for replay
+	if c.config.EventBus.Persist {
+		events, err := c.redactEvents(sqlite.NewEventRepository(c.queries))
+		if err != nil {
+			return err
+		}
+		ebConfig.Store = eventbus.NewDatabaseEventStore(events, c.logger)
+		c.logger.Info("event store enabled")
+	}
+
+	// Carry events to and from other instances
+	if c.config.EventBus.Backend == config.EventBusBackendNATS {
+		natsConfig := c.config.EventBus.NATS
+		transport := eventbus.NewNATSTransport(eventbus.NATSTransportConfig{
+			URL:            natsConfig.URL,
+			Token:          natsConfig.Token,
+			SubjectPrefix:  natsConfig.SubjectPrefix,
+			Stream:         natsConfig.Stream,
+			ConnectTimeout: time.Duration(natsConfig.ConnectTimeoutSec) * time.Second,
+			Name:           "nexflow",
+			Logger:         c.logger,
+		})
+		if err := transport.Connect(context.Background()); err != nil {
+			return fmt.Errorf("failed to connect event bus to NATS: %w", err)
+		}
+		ebConfig.Transport = transport
+		c.logger.Info("event bus backend enabled", "backend", config.EventBusBackendNATS)
+	}
+
+	// Create event bus
+	c.eventBus = eventbus.NewEventBus(ebConfig)
+
+	// Start event bus
+	if err := c.eventBus.Start(); err != nil {
+		return fmt.Errorf("failed to start event bus: %w", err)
+	}
+
+	c.logger.Info("event bus initialized successfully")
+	return nil
+}
+
+// redactEvents returns the event repository redacting the personal data of stored events if
+// redaction is enabled, otherwise the repository itself
+func (c *DIContainer) redactEvents(events repository.EventRepository) (repository.EventRepository, error) {
+	piiCfg, err := piiConfigFromYAML(c.config.PII)
+	if err != nil {
+		return nil, err
+	}
+	if !piiCfg.Enabled() {
+		return events, nil
+	}
+
+	redacting, err := privacy.NewEventRepository(events, sqlite.NewSessionRepository(c.queries), c.config.Channels.Workspaces(), piiCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event redaction: %w", err)
+	}
+	return redacting, nil
+}
+
+// initPorts initializes all port implementations
+func (c *DIContainer) initPorts() error {
+	// Initialize LLM provider
+	if err := c.initLLMProvider(); err != nil {
+		return fmt.Errorf("failed to initialize LLM provider: %w", err)
+	}
+
+	// Initialize skill runtime
+	if err := c.initSkillRuntime(); err != nil {
+		return fmt.Errorf("failed to initialize skill runtime: %w", err)
+	}
+
+	c.logger.Info("ports initialized successfully")
+	return nil
+}
+
+// initConnectors initializes all channel connectors based on configuration
+func (c *DIContainer) initConnectors() error {
+	c.incomingMetrics = channels.NewIncomingMetrics()
+	spillStore := sqlite.NewSpilledMessageRepository(c.queries)
+
+	// Initialize Telegram connector if enabled
+	if c.config.Channels.Telegram.Enabled {
+		// Get slog logger from interface for Telegram connector
+		slogLogger, ok := logging.Named(c.logger, "telegram").(*logging.SlogLogger)
+		if !ok {
+			c.logger.Warn("logger is not SlogLogger, using mock Telegram connector")
+			c.telegramConnector = channelmock.NewTelegramConnector()
+			c.logger.Info("telegram connector initialized (mock)")
+		} else {
+			// Use real Telegram connector
+			conn := telegramconn.NewConnector(
+				c.config.Channels.Telegram,
+				c.userRepo,
+				c.sessionRepo,
+				slogLogger.GetSlogLogger(),
+			)
+			conn.SetRecoverer(c.recoverer)
+			conn.SetIncoming(spillStore, c.incomingMetrics)
+			c.telegramConnector = conn
+			c.logger.Info("telegram connector initialized (real)")
+		}
+	}
+
+	// Initialize the further Telegram bots, each registered under its own connector name
+	for _, botCfg := range c.config.Channels.TelegramBots {
+		if !botCfg.Enabled {
+			continue
+		}
+		slogLogger, ok := logging.Named(c.logger, botCfg.Name).(*logging.SlogLogger)
+		if !ok {
+			c.logger.Warn("logger is not SlogLogger, skipping Telegram bot", "connector", botCfg.Name)
+			continue
+		}
+		bot := telegramconn.NewConnector(
+			botCfg,
+			c.userRepo,
+			c.sessionRepo,
+			slogLogger.GetSlogLogger(),
+		)
+		bot.SetRecoverer(c.recoverer)
+		bot.SetIncoming(spillStore, c.incomingMetrics)
+		c.telegramBots = append(c.telegramBots, bot)
+		c.logger.Info("telegram bot initialized", "connector", botCfg.Name, "workspace", botCfg.Workspace)
+	}
+
+	// Initialize Discord connector if enabled
+	if c.config.Channels.Discord.Enabled {
+		// For now, use mock connector
+		// TODO: Replace with real Discord connector implementation
+		c.discordConnector = channelmock.NewDiscordConnector()
+		c.logger.Info("discord connector initialized (mock)")
+	}
+
+	// Initialize Web connector if enabled
+	if c.config.Channels.Web.Enabled {
+		// For now, use mock connector
+		// TODO: Replace with real Web connector implementation
+		c.webConnector = channelmock.NewWebConnector()
+		c.logger.Info("web connector initialized (mock)")
+	}
+
+	return nil
+}
+
+// initMessageRouter initializes the message router and registers all connectors
+func (c *DIContainer) initMessageRouter() error {
+	// Duplicate messages are dropped before they reach the orchestrator
+	if dedupCfg := c.config.Router.Dedup; dedupCfg.Enabled {
+		var store repository.ProcessedMessageRepository
+		if dedupCfg.Persist {
+			store = sqlite.NewProcessedMessageRepository(c.queries)
+		}
+		c.deduplicator = router.NewDeduplicator(store, logging.Named(c.logger, "router"), router.DeduplicatorConfig{
+			TTL:       time.Duration(dedupCfg.TTLSeconds) * time.Second,
+			CacheSize: dedupCfg.CacheSize,
+		})
+	}
+
+	// Create message router (chatUseCase will be set in initUseCases)
+	c.messageRouter = router.NewMessageRouter(c.sessionRepo, nil, c.eventBus, logging.Named(c.logger, "router"), routerConfigFromYAML(c.config.Router, c.config.Channels))
+	c.messageRouter.Use(c.routerMiddlewares()...)
+
+	// Register all enabled connectors
+	for _, conn := range c.connectors() {
+		c.messageRouter.RegisterConnector(conn)
+	}
+
+	c.logger.Info("message router initialized")
+	return nil
+}
+
+// connectors returns the enabled channel connectors
+func (c *DIContainer) connectors() []channels.Connector {
+	var connectors []channels.Connector
+	if c.telegramConnector != nil {
+		connectors = append(connectors, c.telegramConnector)
+	}
+	connectors = append(connectors, c.telegramBots...)
+	if c.discordConnector != nil {
+		connectors = append(connectors, c.discordConnector)
+	}
+	if c.webConnector != nil {
+		connectors = append(connectors, c.webConnector)
+	}
+	return connectors
+}
+
+// routerMiddlewares returns the middlewares every incoming message passes through before it
+// reaches the orchestrator, outermost first
+func (c *DIContainer) routerMiddlewares() []router.Middleware {
+	var middlewares []router.Middleware
+	if c.deduplicator != nil {
+		middlewares = append(middlewares, c.deduplicator.Middleware())
+	}
+	return middlewares
+}
+
+// initLLMProvider initializes the configured LLM providers. Messages answer through the default
+// provider unless they select another one by name.
+func (c *DIContainer) initLLMProvider() error {
+	c.tokenizer = tokenizer.New()
+
+	// Check if LLM config is available
+	if c.config.LLM.DefaultProvider == "" {
+		c.logger.Info("no LLM provider configured, using mock")
+		c.llmProvider = llmmock.NewMockLLMProvider()
+		return nil
+	}
+
+	// Get provider config
+	providerName := c.config.LLM.DefaultProvider
+	providerConfig, ok := c.config.LLM.Providers[providerName]
+	if !ok {
+		return fmt.Errorf("LLM provider '%s' not found in configuration", providerName)
+	}
+
+	// Get slog logger from interface
+	slogLogger, ok := logging.Named(c.logger, "llm").(*logging.SlogLogger)
+	if !ok {
+		c.logger.Warn("logger is not SlogLogger, using mock LLM provider")
+		c.llmProvider = llmmock.NewMockLLMProvider()
+		return nil
+	}
+
+	provider, err := newLLMProvider(providerName, providerConfig, slogLogger)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+	if provider == nil {
+		c.logger.Warn("Unknown LLM provider, using mock", "provider", providerName)
+		c.llmProvider = llmmock.NewMockLLMProvider()
+		return nil
+	}
+
+	// Wrap providers with adapters; a provider that cannot be created is left out
+	c.llmMetrics = llmadapter.NewLLMMetrics()
+	providers := llmadapter.NewProviderSet(providerName)
+	providers.SetTokenizer(c.tokenizer)
+	providers.Add(providerName, llmadapter.NewProviderAdapterWithTokenizer(provider, c.llmMetrics, c.tracer, c.tokenizer), llmProviderLimitsFromYAML(providerConfig))
+
+	names := make([]string, 0, len(c.config.LLM.Providers))
+	for name := range c.config.LLM.Providers {
+		if name != providerName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg := c.config.LLM.Providers[name]
+		provider, err := newLLMProvider(name, cfg, slogLogger)
+		if err != nil || provider == nil {
+			c.logger.Warn("LLM provider not available for selection", "provider", name, "error", err)
+			continue
+		}
+		providers.Add(name, llmadapter.NewProviderAdapterWithTokenizer(provider, c.llmMetrics, c.tracer, c.tokenizer), llmProviderLimitsFromYAML(cfg))
+	}
+	c.llmProvider = providers
+
+	// Check every provider, so that messages fail over while the default provider is down
+	if c.config.LLM.HealthCheck.Enabled {
+		c.llmHealth = llmhealth.NewMonitor(logging.Named(c.logger, "llm"), llmHealthConfigFromYAML(c.config.LLM.HealthCheck))
+		for _, name := range providers.Names() {
+			provider, _ := providers.Provider(name)
+			if status, ok := provider.(ports.LLMProviderStatus); ok {
+				c.llmHealth.Add(name, status)
+			}
+		}
+		providers.SetFailover(c.config.LLM.Failover, c.llmHealth)
+	}
+
+	c.logger.Info("LLM provider initialized",
+		"provider", providerName,
+		"model", providerConfig.Model,
+		"providers", providers.Names())
+
+	return nil
+}
+
+// newLLMProvider creates the built-in provider configured under name; it returns nil for unknown names
+func newLLMProvider(name string, cfg config.LLMProvider, logger *logging.SlogLogger) (llmadapter.Provider, error) {
+	switch name {
+	case "openai":
+		return openai.NewProvider(&openai.Config{
+			APIKey:  cfg.APIKey,
+			BaseURL: cfg.BaseURL,
+			Model:   cfg.Model,
+		}, logger.GetSlogLogger())
+	case "anthropic":
+		return anthropic.NewProvider(&anthropic.Config{
+			APIKey:  cfg.APIKey,
+			BaseURL: cfg.BaseURL,
+			Model:   cfg.Model,
+		}, logger.GetSlogLogger())
+	case "ollama":
+		return ollama.NewProvider(&ollama.Config{
+			BaseURL: cfg.BaseURL,
+			Model:   cfg.Model,
+		}, logger.GetSlogLogger())
+	case "zai":
+		return zai.NewProvider(&zai.Config{
+			APIKey:  cfg.APIKey,
+			BaseURL: cfg.BaseURL,
+			Model:   cfg.Model,
+		}, logger.GetSlogLogger())
+	default:
+		return nil, nil
+	}
+}
+
+// initSkillRuntime initializes the skill runtime based on configuration
+func (c *DIContainer) initSkillRuntime() error {
+	// Check if skills config is available
+	if c.config.Skills.Directory == "" {
+		c.logger.Info("no skills directory configured, using mock")
+		c.skillRuntime = skillmock.NewMockSkillRuntime()
+		return nil
+	}
+
+	// Get slog logger from interface
+	slogLogger, ok := c.logger.(*logging.SlogLogger)
+	if !ok {
+		c.logger.Warn("logger is not SlogLogger, using mock skill runtime")
+		c.skillRuntime = skillmock.NewMockSkillRuntime()
+		return nil
+	}
+
+	// Create local skill runtime
+	localRuntime, err := skills.NewLocalRuntime(&skills.Config{
+		Directory:      c.config.Skills.Directory,
+		TimeoutSeconds: c.config.Skills.TimeoutSec,
+		SandboxEnabled: c.config.Skills.SandboxEnabled,
+	}, slogLogger.GetSlogLogger())
+	if err != nil {
+		return fmt.Errorf("failed to create local skill runtime: %w", err)
+	}
+
+	// Wrap runtime with adapter
+	c.skillRuntime = skills.NewRuntimeAdapterWithRecoverer(localRuntime, c.tracer, c.recoverer)
+
+	c.logger.Info("skill runtime initialized",
+		"directory", c.config.Skills.Directory,
+		"timeout", c.config.Skills.TimeoutSec,
+		"sandbox", c.config.Skills.SandboxEnabled)
+
+	return nil
+}
+
+// initUseCases initializes all use cases
+func (c *DIContainer) initUseCases() error {
+	// Chat use case
+	c.chatUseCase = usecase.NewChatUseCase(
+		c.userRepo,
+		c.sessionRepo,
+		c.messageRepo,
+		c.taskRepo,
+		c.llmProvider,
+		c.skillRuntime,
+		c.logger,
+	)
+	c.chatUseCase.SetSkillRepository(c.skillRepo)
+	c.chatUseCase.SetTaskAttemptRepository(sqlite.NewTaskAttemptRepository(c.queries))
+	c.chatUseCase.SetSkillRetryPolicies(skillRetryPolicyFromYAML(c.config.Skills.Retry), skillRetryPoliciesFromYAML(c.config.Skills))
+	c.chatUseCase.SetUserPreferencesRepository(c.prefsRepo)
+	c.chatUseCase.SetSessionAttributeRepository(c.attributeRepo)
+	c.chatUseCase.SetSessionTitles(c.config.LLM.SessionTitles)
+	c.chatUseCase.SetTokenizer(c.tokenizer)
+	if err := c.chatUseCase.SetGlobalInstructions(c.config.LLM.Instructions); err != nil {
+		return fmt.Errorf("invalid llm instructions: %w", err)
+	}
+	for _, fragment := range promptFragmentsFromYAML(c.config.LLM.Prompts) {
+		if err := c.chatUseCase.SetPromptFragment(fragment.Scope, fragment.Name, fragment.Prompt); err != nil {
+			return fmt.Errorf("invalid llm prompt of %s %s: %w", fragment.Scope, fragment.Name, err)
+		}
+	}
+
+	// Documents sent to the bot are read into their session and answered from
+	if c.config.Documents.Enabled {
+		c.documents = documents.NewService(c.documentRepo, documents.NewHashingEmbedder(0), documentsConfigFromYAML(c.config.Documents), logging.Named(c.logger, "documents"))
+		c.chatUseCase.SetDocumentRetriever(c.documents)
+		if c.jobQueue != nil {
+			c.documents.SetJobQueue(c.jobQueue)
+		}
+	}
+	if c.eventBus != nil {
+		c.chatUseCase.SetEventBus(c.eventBus)
+	}
+
+	// Asynchronous message API, answering messages in the background with the chat use case
+	c.messageJobUseCase = usecase.NewMessageJobUseCase(c.chatUseCase, c.messageJobRepo, messageJobConfigFromYAML(c.config.MessageJobs), logging.Named(c.logger, "message_jobs"))
+
+	// Reply feedback use case, rating replies from the router buttons and the API
+	c.feedbackUseCase = usecase.NewFeedbackUseCase(c.feedbackRepo, c.messageRepo, c.sessionRepo, c.logger)
+	if c.eventBus != nil {
+		c.feedbackUseCase.SetEventBus(c.eventBus)
+	}
+
+	// Initialize orchestrator with chat use case
+	c.orchestrator = orchestrator.NewOrchestratorWithConfig(c.chatUseCase, c.logger, c.tracer, orchestratorConfigFromYAML(c.config.Orchestrator))
+	c.messageJobUseCase.SetOrchestrator(c.orchestrator)
+	if c.jobQueue != nil {
+		c.messageJobUseCase.SetJobQueue(c.jobQueue)
+	}
+
+	// Update message router with orchestrator now that it's initialized
+	// We need to recreate the message router with the orchestrator
+	c.messageRouter = router.NewMessageRouter(c.sessionRepo, c.orchestrator, c.eventBus, logging.Named(c.logger, "router"), routerConfigFromYAML(c.config.Router, c.config.Channels))
+	c.messageRouter.SetSessionExporter(c.chatUseCase)
+	c.messageRouter.SetSessionForker(c.chatUseCase)
+	c.messageRouter.SetFeedbackRecorder(c.feedbackUseCase)
+	c.messageRouter.SetTracer(c.tracer)
+	c.messageRouter.SetRecoverer(c.recoverer)
+	c.messageRouter.SetDeadLetterStore(c.deadLetterRepo)
+	c.messageRouter.SetMessageRepository(c.messageRepo)
+	c.messageRouter.SetSkillRepository(c.skillRepo)
+	c.messageRouter.SetSessionAttributeStore(c.attributeRepo)
+	c.messageRouter.SetWorkspaceRepository(c.workspaceRepo)
+	c.messageRouter.SetUserPreferencesRepository(c.prefsRepo)
+	if c.documents != nil {
+		c.messageRouter.SetDocumentIngester(c.documents)
+	}
+	// Exclusive connectors run on the leader; the other instances queue their messages for it
+	if c.elector != nil {
+		c.messageRouter.SetLeadership(c.elector)
+		if c.jobQueue != nil {
+			c.messageRouter.SetJobQueue(c.jobQueue)
+		}
+		c.elector.OnChange(c.messageRouter.LeadershipChanged)
+	}
+	c.messageRouter.Use(c.routerMiddlewares()...)
+
+	// Re-register all connectors
+	for _, conn := range c.connectors() {
+		c.messageRouter.RegisterConnector(conn)
+	}
+
+	// User use case
+	c.userUseCase = usecase.NewUserUseCase(
+		c.userRepo,
+		c.logger,
+	)
+
+	// User preferences use case
+	c.prefsUseCase = usecase.NewUserPreferencesUseCase(c.prefsRepo, c.userRepo, c.logger)
+
+	// User data export and erasure use case
+	c.userDataUseCase = usecase.NewUserDataUseCase(
+		c.userRepo,
+		c.prefsRepo,
+		c.sessionRepo,
+		c.messageRepo,
+		c.taskRepo,
+		c.attributeRepo,
+		c.eventRepo,
+		c.deadLetterRepo,
+		c.erasureRepo,
+		c.logger,
+	)
+	masker, err := newLogMasker(c.config.Logging)
+	if err != nil {
+		return fmt.Errorf("failed to create log masker: %w", err)
+	}
+	c.userDataUseCase.SetMasker(masker)
+	connectorChannels := make(map[string]string)
+	for _, conn := range c.connectors() {
+		connectorChannels[conn.Name()] = channels.ChannelOf(conn)
+	}
+	c.userDataUseCase.SetConnectorChannels(connectorChannels)
+
+	// Skill use case
+	c.skillUseCase = usecase.NewSkillUseCase(
+		c.skillRepo,
+		c.skillRuntime,
+		c.logger,
+	)
+
+	// Schedule use case
+	c.scheduleUseCase = usecase.NewScheduleUseCase(
+		c.scheduleRepo,
+		c.scheduleRunRepo,
+		c.logger,
+	)
+
+	// Backup use case
+	c.backupUseCase = usecase.NewBackupUseCase(
+		c.db,
+		backupConfigFromYAML(c.config.Backup),
+		c.logger,
+	)
+
+	// Session state use case
+	c.stateUseCase = usecase.NewSessionStateUseCase(
+		c.sessionRepo,
+		c.attributeRepo,
+		c.logger,
+	)
+
+	// Operator handoff use case; replies are sent through the message router
+	c.handoffUseCase = usecase.NewHandoffUseCase(
+		c.sessionRepo,
+		c.userRepo,
+		c.messageRepo,
+		c.attributeRepo,
+		c.messageRouter,
+		c.logger,
+	)
+
+	// API key use case
+	c.apiKeyUseCase = usecase.NewAPIKeyUseCase(c.apiKeyRepo, c.logger)
+
+	// Workspace use case
+	c.workspaceUseCase = usecase.NewWorkspaceUseCase(c.workspaceRepo, c.logger)
+
+	// Runtime admin use case
+	c.adminUseCase = usecase.NewAdminUseCase(
+		c.messageRouter,
+		c.llmProvider,
+		adminConfigFromYAML(c.config.LLM),
+		c.logger,
+	)
+	c.adminUseCase.SetInstructionsManager(c.chatUseCase)
+	if c.llmHealth != nil {
+		c.adminUseCase.SetLLMHealth(c.llmHealth)
+	}
+
+	// Outbound webhook admin use case
+	c.webhookUseCase = usecase.NewWebhookUseCase(c.webhookRepo, webhookUseCaseConfigFromYAML(c.config.Webhooks), c.logger)
+
+	// Conversation analytics use case
+	c.analyticsUseCase = usecase.NewAnalyticsUseCase(c.analyticsRepo, c.logger)
+
+	// LLM usage use case
+	c.usageUseCase = usecase.NewUsageUseCase(c.usageRepo, c.logger)
+
+	// Log use case
+	c.logUseCase = usecase.NewLogUseCase(c.logRepo, c.logger)
+
+	// Crash log use case
+	c.crashReportUseCase = usecase.NewCrashReportUseCase(c.crashRepo, c.logger)
+
+	c.logger.Info("use cases initialized successfully")
+	return nil
+}
+
+// initScheduler initializes the schedule execution engine
+func (c *DIContainer) initScheduler() error {
+	if !c.config.Scheduler.Enabled {
+		c.logger.Info("scheduler disabled")
+		if c.config.Backup.Schedule != "" {
+			c.logger.Warn("automatic backups require the scheduler and will not run", "schedule", c.config.Backup.Schedule)
+		}
+		if c.deduplicator != nil && c.config.Router.Dedup.Persist {
+			c.logger.Warn("expired records of processed messages are purged by the scheduler and will be kept")
+		}
+		if c.config.Idempotency.Enabled {
+			c.logger.Warn("expired idempotency keys are purged by the scheduler and will be kept")
+		}
+		c.logger.Warn("finished asynchronous message jobs are purged by the scheduler and will be kept")
+		if c.jobQueue != nil {
+			c.logger.Warn("finished jobs of the job queue are purged by the scheduler and will be kept")
+		}
+		return nil
+	}
+
+	c.scheduler = scheduler.NewScheduler(
+		c.scheduleRepo,
+		c.scheduleRunRepo,
+		c.userRepo,
+		c.sessionRepo,
+		c.orchestrator,
+		c.eventBus,
+		logging.Named(c.logger, "scheduler"),
+		schedulerConfigFromYAML(c.config.Scheduler),
+	)
+
+	// Reload schedules whenever they change through the API
+	c.scheduleUseCase.SetScheduler(c.scheduler)
+
+	// Deliver run output to schedule targets through the connectors
+	c.scheduler.SetMessageSender(c.messageRouter)
+
+	// Due runs are queued, so that they still run after a restart
+	if c.jobQueue != nil {
+		c.scheduler.SetJobQueue(c.jobQueue)
+	}
+
+	// Schedules and system jobs run on the leader; a new leader catches up on missed runs
+	if c.elector != nil {
+		c.scheduler.SetLeadership(c.elector)
+		c.elector.OnChange(func(leader bool) {
+			if leader {
+				c.scheduler.Promote()
+			}
+		})
+	}
+
+	// Automatic database backups
+	if schedule := c.config.Backup.Schedule; schedule != "" {
+		if err := c.scheduler.AddJob("backup", schedule, c.backupUseCase.RunScheduledBackup); err != nil {
+			return fmt.Errorf("failed to schedule backups: %w", err)
+		}
+		c.logger.Info("automatic backups scheduled", "schedule", schedule, "dir", c.config.Backup.Dir)
+	}
+
+	// Expired session attributes are hidden from reads and purged periodically
+	if err := c.scheduler.AddJob("session-attributes-expiry", sessionAttributeExpirySchedule, c.stateUseCase.PurgeExpired); err != nil {
+		return fmt.Errorf("failed to schedule session attribute expiry: %w", err)
+	}
+
+	// Records of processed messages are kept in the database only with router.dedup.persist
+	if c.deduplicator != nil && c.config.Router.Dedup.Persist {
+		if err := c.scheduler.AddJob("processed-messages-expiry", processedMessageExpirySchedule, c.deduplicator.PurgeExpired); err != nil {
+			return fmt.Errorf("failed to schedule processed message expiry: %w", err)
+		}
+	}
+
+	// Finished asynchronous message jobs can be polled for message_jobs.ttl_hours
+	if err := c.scheduler.AddJob("message-jobs-expiry", messageJobExpirySchedule, c.messageJobUseCase.PurgeFinished); err != nil {
+		return fmt.Errorf("failed to schedule message job expiry: %w", err)
+	}
+
+	// Finished jobs of the job queue are kept for jobs.ttl_hours
+	if c.jobQueue != nil {
+		if err := c.scheduler.AddJob("jobs-expiry", jobExpirySchedule, c.jobQueue.PurgeFinished); err != nil {
+			return fmt.Errorf("failed to schedule job expiry: %w", err)
+		}
+	}
+
+	c.logger.Info("scheduler initialized successfully")
+	return nil
+}
+
+// initRetention initializes periodic deletion of old messages, tasks and logs
+func (c *DIContainer) initRetention() error {
+	if !c.config.Retention.Enabled {
+		c.logger.Info("data retention disabled")
+		return nil
+	}
+
+	var archiver retention.Archiver
+	if c.config.Retention.ArchiveDir != "" {
+		archiver = retention.NewFileArchiver(c.config.Retention.ArchiveDir)
+	}
+
+	c.retention = retention.NewRetention(
+		c.messageRepo,
+		c.taskRepo,
+		c.logRepo,
+		archiver,
+		c.logger,
+		retentionConfigFromYAML(c.config.Retention),
+	)
+	if c.elector != nil {
+		c.retention.SetLeadership(c.elector)
+	}
+
+	c.logger.Info("data retention initialized successfully")
+	return nil
+}
+
+// initWebhooks initializes posting selected events to the configured webhook endpoints
+func (c *DIContainer) initWebhooks() error {
+	if !c.config.Webhooks.Enabled {
+		c.logger.Info("outbound webhooks disabled")
+		return nil
+	}
+	if c.eventBus == nil {
+		return fmt.Errorf("outbound webhooks require the event bus to be enabled")
+	}
+
+	c.webhookDispatcher = webhook.NewDispatcher(
+		c.eventBus,
+		c.webhookRepo,
+		c.logger,
+		webhookConfigFromYAML(c.config.Webhooks),
+	)
+	if c.jobQueue != nil {
+		c.webhookDispatcher.SetJobQueue(c.jobQueue)
+	}
+
+	c.logger.Info("outbound webhooks initialized successfully", "endpoints", len(c.config.Webhooks.Endpoints))
+	return nil
+}
+
+// initAnalytics initializes counting messages, active users, tokens, cost and skill executions per day,
+// and recording the LLM usage for billing. Without the event bus there is nothing to count, so the
+// analytics are left disabled.
+func (c *DIContainer) initAnalytics() {
+	c.initUsageRecorder()
+
+	if !c.config.Analytics.Enabled {
+		c.logger.Info("conversation analytics disabled")
+		return
+	}
+	if c.eventBus == nil {
+		c.logger.Warn("conversation analytics require the event bus, analytics disabled")
+		return
+	}
+
+	c.analyticsCollector = analytics.NewCollector(c.eventBus, c.analyticsRepo, c.logger)
+	c.logger.Info("conversation analytics initialized successfully")
+}
+
+// initUsageRecorder initializes recording the tokens and cost of LLM calls per user and workspace
+func (c *DIContainer) initUsageRecorder() {
+	if !c.config.Analytics.Usage {
+		c.logger.Info("llm usage recording disabled")
+		return
+	}
+	if c.eventBus == nil {
+		c.logger.Warn("llm usage recording requires the event bus, usage recording disabled")
+		return
+	}
+
+	c.usageRecorder = analytics.NewUsageRecorder(c.eventBus, c.usageRepo, c.logger)
+	c.logger.Info("llm usage recording initialized successfully")
+}
+
+// initUpdateCheck initializes checking GitHub releases for a version newer than the running one
+func (c *DIContainer) initUpdateCheck() {
+	if !c.config.UpdateCheck.Enabled {
+		return
+	}
+
+	c.updateChecker = version.NewChecker(version.Get().Version, version.CheckerConfig{
+		Repository: c.config.UpdateCheck.Repository,
+		Interval:   time.Duration(c.config.UpdateCheck.IntervalHours) * time.Hour,
+	}, nil, logging.Named(c.logger, "update"))
+}
+
+// initHandlers initializes all HTTP handlers
+func (c *DIContainer) initHandlers() error {
+	// User handler
+	c.userHandler = httpinf.NewUserHandler(c.userUseCase, c.logger)
+
+	// User preferences handler
+	c.prefsHandler = httpinf.NewUserPreferencesHandler(c.prefsUseCase, c.logger)
+
+	// User data handler
+	c.userDataHandler = httpinf.NewUserDataHandler(c.userDataUseCase, c.logger)
+
+	// Session handler
+	c.sessionHandler = httpinf.NewSessionHandler(c.chatUseCase, c.logger)
+
+	// Message handler
+	c.messageHandler = httpinf.NewMessageHandler(c.chatUseCase, c.logger)
+
+	// Asynchronous message handler
+	c.messageJobHandler = httpinf.NewMessageJobHandler(c.messageJobUseCase, c.logger)
+
+	// WebSocket chat handler
+	c.chatWSHandler = httpinf.NewChatWSHandler(c.chatUseCase, c.logger)
+
+	// Task handler
+	c.taskHandler = httpinf.NewTaskHandler(c.chatUseCase, c.logger)
+
+	// Skill handler
+	c.skillHandler = httpinf.NewSkillHandler(c.skillUseCase, c.logger)
+
+	// Schedule handler
+	c.scheduleHandler = httpinf.NewScheduleHandler(c.scheduleUseCase, c.logger)
+
+	// Log handler
+	c.logHandler = httpinf.NewLogHandler(c.logUseCase, c.logger)
+
+	// Backup admin handler
+	c.backupHandler = httpinf.NewBackupHandler(c.backupUseCase, c.logger)
+
+	// Runtime admin handler
+	c.adminHandler = httpinf.NewAdminHandler(c.adminUseCase, c.logger)
+
+	// Live events handler (streams are refused if the event bus is disabled)
+	c.eventsHandler = httpinf.NewEventsHandler(c.eventBus, c.logger)
+
+	// Log level admin handler
+	c.loggingHandler = httpinf.NewLoggingHandler(logging.LevelsOf(c.logger), c.logger)
+
+	// Outbound webhook admin handler
+	c.webhookHandler = httpinf.NewWebhookHandler(c.webhookUseCase, c.logger)
+
+	// Conversation analytics handler
+	c.analyticsHandler = httpinf.NewAnalyticsHandler(c.analyticsUseCase, c.logger)
+
+	// LLM usage handler
+	c.usageHandler = httpinf.NewUsageHandler(c.usageUseCase, c.logger)
+
+	// Reply feedback handler
+	c.feedbackHandler = httpinf.NewFeedbackHandler(c.feedbackUseCase, c.logger)
+
+	// Crash log handler
+	c.crashReportHandler = httpinf.NewCrashReportHandler(c.crashReportUseCase, c.logger)
+
+	// Session state handler
+	c.stateHandler = httpinf.NewSessionStateHandler(c.stateUseCase, c.logger)
+
+	// Operator handoff handler
+	c.handoffHandler = httpinf.NewHandoffHandler(c.handoffUseCase, c.logger)
+
+	// API key admin handler and authentication middleware
+	c.apiKeyHandler = httpinf.NewAPIKeyHandler(c.apiKeyUseCase, c.logger)
+	c.workspaceHandler = httpinf.NewWorkspaceHandler(c.workspaceUseCase, c.logger)
+	c.authenticator = httpinf.NewAuthenticator(c.config.Auth, c.apiKeyUseCase, c.logger)
+	if !c.config.Auth.Enabled {
+		c.logger.Warn("HTTP API authentication is disabled")
+	}
+	c.rateLimiter = httpinf.NewRateLimiter(c.config.RateLimit, c.logger)
+	c.idempotency = httpinf.NewIdempotency(c.config.Idempotency, c.idempotencyRepo, c.logger)
+	if c.scheduler != nil && c.config.Idempotency.Enabled {
+		if err := c.scheduler.AddJob("idempotency-keys-expiry", idempotencyKeyExpirySchedule, c.idempotency.PurgeExpired); err != nil {
+			return fmt.Errorf("failed to schedule idempotency key expiry: %w", err)
+		}
+	}
+
+	// Health handler
+	c.healthHandler = httpinf.NewHealthHandler(c.logger)
+	c.healthHandler.AddCheck("database", c.databaseHealthCheck)
+
+	// Prometheus metrics handler
+	c.metricsHandler = httpinf.NewMetricsHandler(c.logger, c.metricsRegistries()...)
+
+	// Version handler
+	c.versionHandler = httpinf.NewVersionHandler(version.Get(), c.updateChecker)
+
+	c.logger.Info("HTTP handlers initialized successfully")
+	return nil
+}
+
+// Getters for use cases
+func (c *DIContainer) ChatUseCase() *usecase.ChatUseCase {
+	return c.chatUseCase
+}
+
+func (c *DIContainer) UserUseCase() *usecase.UserUseCase {
+	return c.userUseCase
+}
+
+func (c *DIContainer) SkillUseCase() *usecase.SkillUseCase {
+	return c.skillUseCase
+}
+
+func (c *DIContainer) ScheduleUseCase() *usecase.ScheduleUseCase {
+	return c.scheduleUseCase
+}
+
+func (c *DIContainer) BackupUseCase() *usecase.BackupUseCase {
+	return c.backupUseCase
+}
+
+func (c *DIContainer) SessionStateUseCase() *usecase.SessionStateUseCase {
+	return c.stateUseCase
+}
+
+func (c *DIContainer) HandoffUseCase() *usecase.HandoffUseCase {
+	return c.handoffUseCase
+}
+
+func (c *DIContainer) APIKeyUseCase() *usecase.APIKeyUseCase {
+	return c.apiKeyUseCase
+}
+
+func (c *DIContainer) WorkspaceUseCase() *usecase.WorkspaceUseCase {
+	return c.workspaceUseCase
+}
+
+func (c *DIContainer) UserPreferencesUseCase() *usecase.UserPreferencesUseCase {
+	return c.prefsUseCase
+}
+
+func (c *DIContainer) AdminUseCase() *usecase.AdminUseCase {
+	return c.adminUseCase
+}
+
+// EventBus returns the event bus instance
+func (c *DIContainer) EventBus() *eventbus.EventBus {
+	return c.eventBus
+}
+
+// MessageRouter returns the message router instance
+func (c *DIContainer) MessageRouter() *router.MessageRouter {
+	return c.messageRouter
+}
+
+// Scheduler returns the scheduler instance (nil if disabled)
+func (c *DIContainer) Scheduler() *scheduler.Scheduler {
+	return c.scheduler
+}
+
+// Retention returns the data retention service (nil if disabled)
+func (c *DIContainer) Retention() *retention.Retention {
+	return c.retention
+}
+
+// MessageJobs returns the use case answering asynchronous messages in the background
+func (c *DIContainer) MessageJobs() *usecase.MessageJobUseCase {
+	return c.messageJobUseCase
+}
+
+// JobQueue returns the persistent job queue (nil if disabled)
+func (c *DIContainer) JobQueue() *jobqueue.Queue {
+	return c.jobQueue
+}
+
+// LLMHealth returns the availability checks of the LLM providers (nil if disabled)
+func (c *DIContainer) LLMHealth() *llmhealth.Monitor {
+	return c.llmHealth
+}
+
+// Elector returns the leader election of the cluster (nil if disabled)
+func (c *DIContainer) Elector() *cluster.Elector {
+	return c.elector
+}
+
+// WebhookDispatcher returns the outbound webhook dispatcher (nil if disabled)
+func (c *DIContainer) WebhookDispatcher() *webhook.Dispatcher {
+	return c.webhookDispatcher
+}
+
+// AnalyticsCollector returns the conversation analytics collector (nil if disabled)
+func (c *DIContainer) AnalyticsCollector() *analytics.Collector {
+	return c.analyticsCollector
+}
+
+// UsageRecorder returns the LLM usage recorder (nil if disabled)
+func (c *DIContainer) UsageRecorder() *analytics.UsageRecorder {
+	return c.usageRecorder
+}
+
+// UpdateChecker returns the check for newer releases (nil if disabled)
+func (c *DIContainer) UpdateChecker() *version.Checker {
+	return c.updateChecker
+}
+
+// Getters for HTTP handlers
+func (c *DIContainer) UserHandler() *httpinf.UserHandler {
+	return c.userHandler
+}
+
+func (c *DIContainer) SessionHandler() *httpinf.SessionHandler {
+	return c.sessionHandler
+}
+
+func (c *DIContainer) MessageHandler() *httpinf.MessageHandler {
+	return c.messageHandler
+}
+
+func (c *DIContainer) TaskHandler() *httpinf.TaskHandler {
+	return c.taskHandler
+}
+
+func (c *DIContainer) SkillHandler() *httpinf.SkillHandler {
+	return c.skillHandler
+}
+
+func (c *DIContainer) ScheduleHandler() *httpinf.ScheduleHandler {
+	return c.scheduleHandler
+}
+
+func (c *DIContainer) LogHandler() *httpinf.LogHandler {
+	return c.logHandler
+}
+
+func (c *DIContainer) BackupHandler() *httpinf.BackupHandler {
+	return c.backupHandler
+}
+
+func (c *DIContainer) HealthHandler() *httpinf.HealthHandler {
+	return c.healthHandler
+}
+
+func (c *DIContainer) SessionStateHandler() *httpinf.SessionStateHandler {
+	return c.stateHandler
+}
+
+func (c *DIContainer) HandoffHandler() *httpinf.HandoffHandler {
+	return c.handoffHandler
+}
+
+func (c *DIContainer) APIKeyHandler() *httpinf.APIKeyHandler {
+	return c.apiKeyHandler
+}
+
+func (c *DIContainer) WorkspaceHandler() *httpinf.WorkspaceHandler {
+	return c.workspaceHandler
+}
+
+func (c *DIContainer) UserPreferencesHandler() *httpinf.UserPreferencesHandler {
+	return c.prefsHandler
+}
+
+func (c *DIContainer) UserDataHandler() *httpinf.UserDataHandler {
+	return c.userDataHandler
+}
+
+func (c *DIContainer) AdminHandler() *httpinf.AdminHandler {
+	return c.adminHandler
+}
+
+// HTTPHandlers returns all HTTP handlers for route registration
+func (c *DIContainer) HTTPHandlers() httpinf.Handlers {
+	return httpinf.Handlers{
+		User:         c.userHandler,
+		UserPrefs:    c.prefsHandler,
+		UserData:     c.userDataHandler,
+		Session:      c.sessionHandler,
+		SessionState: c.stateHandler,
+		Handoff:      c.handoffHandler,
+		Message:      c.messageHandler,
+		MessageJob:   c.messageJobHandler,
+		ChatWS:       c.chatWSHandler,
+		Task:         c.taskHandler,
+		Skill:        c.skillHandler,
+		Schedule:     c.scheduleHandler,
+		Log:          c.logHandler,
+		Backup:       c.backupHandler,
+		APIKey:       c.apiKeyHandler,
+		Workspace:    c.workspaceHandler,
+		Admin:        c.adminHandler,
+		Events:       c.eventsHandler,
+		Logging:      c.loggingHandler,
+		Webhook:      c.webhookHandler,
+		Analytics:    c.analyticsHandler,
+		Usage:        c.usageHandler,
+		Feedback:     c.feedbackHandler,
+		Crash:        c.crashReportHandler,
+		Health:       c.healthHandler,
+		Metrics:      c.metricsHandler,
+		Version:      c.versionHandler,
+	}
+}
+
+// metricsRegistries returns the metrics registries of all initialized components
+func (c *DIContainer) metricsRegistries() []*metrics.MetricsRegistry {
+	registries := []*metrics.MetricsRegistry{
+		c.messageRouter.Metrics().Registry(),
+		c.rateLimiter.Metrics().Registry(),
+		c.idempotency.Metrics().Registry(),
+		c.recoverer.Metrics().Registry(),
+	}
+	if c.eventBus != nil {
+		registries = append(registries, c.eventBus.Metrics().Registry())
+	}
+	if c.deduplicator != nil {
+		registries = append(registries, c.deduplicator.Metrics().Registry())
+	}
+	if dbImpl, ok := c.db.(*database.DB); ok {
+		registries = append(registries, dbImpl.Metrics().Registry(), dbImpl.Metrics().Queries.Registry())
+	}
+	if c.llmMetrics != nil {
+		registries = append(registries, c.llmMetrics.Registry())
+	}
+	if c.llmHealth != nil {
+		registries = append(registries, c.llmHealth.Metrics().Registry())
+	}
+	if c.scheduler != nil {
+		registries = append(registries, c.scheduler.Metrics().Registry())
+	}
+	if c.retention != nil {
+		registries = append(registries, c.retention.Metrics().Registry())
+	}
+	if c.jobQueue != nil {
+		registries = append(registries, c.jobQueue.Metrics().Registry())
+	}
+	if c.elector != nil {
+		registries = append(registries, c.elector.Metrics().Registry())
+	}
+	if c.webhookDispatcher != nil {
+		registries = append(registries, c.webhookDispatcher.Metrics().Registry())
+	}
+	if c.analyticsCollector != nil {
+		registries = append(registries, c.analyticsCollector.Metrics().Registry())
+	}
+	if c.incomingMetrics != nil {
+		registries = append(registries, c.incomingMetrics.Registry())
+	}
+	return registries
+}
+
+// Authenticator returns the HTTP API authentication middleware
+// Recoverer returns the recoverer of panics, e.g. for the HTTP recovery middleware
+func (c *DIContainer) Recoverer() *crash.Recoverer {
+	return c.recoverer
+}
+
+func (c *DIContainer) Authenticator() *httpinf.Authenticator {
+	return c.authenticator
+}
+
+// RateLimiter returns the HTTP API rate limiting middleware
+func (c *DIContainer) RateLimiter() *httpinf.RateLimiter {
+	return c.rateLimiter
+}
+
+// Idempotency returns the middleware replaying the responses of retried write API requests
+func (c *DIContainer) Idempotency() *httpinf.Idempotency {
+	return c.idempotency
+}
+
+// databaseHealthCheck pings the database and reports the connection pool statistics
+func (c *DIContainer) databaseHealthCheck(ctx context.Context) *dto.HealthCheckDTO {
+	health := c.db.Health(ctx)
+
+	result := &dto.HealthCheckDTO{
+		Status:    dto.HealthStatusOK,
+		LatencyMs: health.Latency.Milliseconds(),
+		Details: map[string]any{
+			"open_connections":     health.Stats.OpenConnections,
+			"in_use_connections":   health.Stats.InUse,
+			"idle_connections":     health.Stats.Idle,
+			"max_open_connections": health.Stats.MaxOpenConnections,
+			"wait_count":           health.Stats.WaitCount,
+		},
+	}
+	if !health.Healthy {
+		result.Status = dto.HealthStatusUnavailable
+		result.Error = health.Error
+	}
+	return result
+}
+
+// Shutdown stops the components of the container in order, see AddShutdownStages
+func (c *DIContainer) Shutdown() error {
+	m := shutdown.NewManager(c.logger)
+	c.AddShutdownStages(m)
+	return m.Run(context.Background()).Err()
+}
+
+// AddShutdownStages adds the stages stopping the components of the container to m, in order:
+// the scheduler so that no new runs start, the router so that no new connector messages are
+// accepted and those in flight, including their skill executions, are drained, the asynchronous
+// messages being answered and the session titles being generated, the health checks of the LLM
+// providers, then the background workers, handing the leadership over once the work of the
+// leader stopped, and the jobs of the job queue they queued, the event bus flushing the queued events and the crash reporters.
+// The database is closed in main.
+func (c *DIContainer) AddShutdownStages(m *shutdown.Manager) {
+	timeout := c.config.Server.Shutdown.StageTimeout()
+
+	if c.scheduler != nil {
+		m.Add("scheduler", timeout, func(context.Context) error { return c.scheduler.Stop() })
+	}
+
+	// The router waits up to its drain timeout for the messages in flight
+	if c.messageRouter != nil {
+		drainTimeout := time.Duration(c.config.Router.DrainTimeoutSec) * time.Second
+		m.Add("router", drainTimeout+timeout, func(context.Context) error { return c.messageRouter.Stop() })
+	}
+
+	// Asynchronous messages being answered are finished until the stage times out; queued ones are failed
+	if c.messageJobUseCase != nil {
+		m.Add("message jobs", timeout, c.messageJobUseCase.Stop)
+	}
+
+	// Session titles being generated are saved before the database is closed
+	if c.chatUseCase != nil {
+		m.Add("session titles", timeout, func(context.Context) error {
+			c.chatUseCase.WaitSessionTitles()
+			return nil
+		})
+	}
+
+	if c.llmHealth != nil {
+		m.Add("llm health", timeout, c.llmHealth.Stop)
+	}
+
+	if c.retention != nil {
+		m.Add("retention", timeout, func(context.Context) error { return c.retention.Stop() })
+	}
+
+	// The lease is released, so that another instance leads without waiting for it to expire
+	if c.elector != nil {
+		m.Add("cluster", timeout, c.elector.Stop)
+	}
+
+	// Outbound webhooks stop before the event bus; undelivered events are logged as failed
+	if c.webhookDispatcher != nil {
+		m.Add("webhooks", timeout, func(context.Context) error { return c.webhookDispatcher.Stop() })
+	}
+
+	// Running jobs are finished until the stage times out; the others are run after a restart
+	if c.jobQueue != nil {
+		m.Add("jobs", timeout, c.jobQueue.Stop)
+	}
+
+	if c.analyticsCollector != nil {
+		m.Add("analytics", timeout, func(context.Context) error { return c.analyticsCollector.Stop() })
+	}
+	if c.usageRecorder != nil {
+		m.Add("usage", timeout, func(context.Context) error { return c.usageRecorder.Stop() })
+	}
+
+	if c.updateChecker != nil {
+		m.Add("update check", timeout, func(context.Context) error { return c.updateChecker.Stop() })
+	}
+
+	if c.config.EventBus.Enabled && c.eventBus != nil {
+		m.Add("event bus", timeout, func(context.Context) error { return c.eventBus.Stop() })
+	}
+
+	// Recovered panics are reported while the database is open
+	if c.recoverer != nil {
+		m.Add("crash reports", timeout, func(context.Context) error {
+			c.recoverer.Wait()
+			return nil
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/atumaikin/nexflow/internal/application/router"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
 	channelmock "github.com/atumaikin/nexflow/internal/infrastructure/channels/mock"
 	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
 	"github.com/atumaikin/nexflow/internal/shared/config"
@@ -26,7 +27,11 @@ func (m *mockSessionRepository) FindByID(ctx context.Context, id string) (*entit
 	return nil, errors.New("not found")
 }
 
-func (m *mockSessionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Session, error) {
+func (m *mockSessionRepository) List(ctx context.Context, opts repository.QueryOptions) ([]*entity.Session, error) {
+	return nil, nil
+}
+
+func (m *mockSessionRepository) FindByUserID(ctx context.Context, userID string, opts repository.QueryOptions) ([]*entity.Session, error) {
 	return nil, nil
 }
 
@@ -89,7 +94,7 @@ func TestDIContainerInitConnectors(t *testing.T) {
 	}
 
 	// Create DI container
-	diContainer, err := NewDIContainer(cfg, logger, db)
+	diContainer, err := NewDIContainer(cfg, logger, db, nil)
 	if err != nil {
 		t.Fatalf("Failed to create DI container: %v", err)
 	}
@@ -174,7 +179,7 @@ func TestDIContainerNoConnectorsEnabled(t *testing.T) {
 	}
 
 	// Create DI container
-	diContainer, err := NewDIContainer(cfg, logger, db)
+	diContainer, err := NewDIContainer(cfg, logger, db, nil)
 	if err != nil {
 		t.Fatalf("Failed to create DI container: %v", err)
 	}
@@ -293,7 +298,7 @@ func TestConnectorGetters(t *testing.T) {
 	}
 
 	// Create DI container
-	diContainer, err := NewDIContainer(cfg, logger, db)
+	diContainer, err := NewDIContainer(cfg, logger, db, nil)
 	if err != nil {
 		t.Fatalf("Failed to create DI container: %v", err)
 	}
@@ -322,3 +327,74 @@ func TestConnectorGetters(t *testing.T) {
 		t.Error("Expected non-existent connector to not exist")
 	}
 }
+
+func TestPostProcessingConfigFromYAML(t *testing.T) {
+	cfg := postProcessingConfigFromYAML(config.PostProcessingConfig{
+		Default: config.ResponsePostProcessing{StripReasoning: true},
+		Connectors: map[string]config.ResponsePostProcessing{
+			"telegram": {MaxLength: 4000, Citations: true, Format: "html"},
+		},
+	})
+
+	if !cfg.Default.StripReasoning {
+		t.Errorf("Expected the default to strip reasoning, got %+v", cfg.Default)
+	}
+	telegram := cfg.Connectors["telegram"]
+	if telegram.MaxLength != 4000 || !telegram.Citations || telegram.Format != router.FormatHTML {
+		t.Errorf("Expected the telegram post-processing, got %+v", telegram)
+	}
+}
+
+func TestOrchestratorConfigFromYAML(t *testing.T) {
+	cfg := orchestratorConfigFromYAML(config.OrchestratorConfig{
+		Planning: config.PlanningConfig{Enabled: true, MaxSteps: 3, MinLength: 40, MaxParallelSkills: 2, SkillTimeoutSec: 30},
+		Tools:    config.ToolsConfig{Enabled: true, MaxRounds: 4, MaxParallel: 3, TimeoutSec: 60},
+	})
+
+	if !cfg.Planning.Enabled || cfg.Planning.MaxSteps != 3 || cfg.Planning.MinLength != 40 {
+		t.Errorf("Expected the planning configuration, got %+v", cfg.Planning)
+	}
+	if cfg.Planning.MaxParallelSkills != 2 || cfg.Planning.SkillTimeout != 30*time.Second {
+		t.Errorf("Expected 2 parallel skills with a 30s timeout, got %+v", cfg.Planning)
+	}
+	if !cfg.Tools.Enabled || cfg.Tools.MaxRounds != 4 || cfg.Tools.MaxParallel != 3 || cfg.Tools.Timeout != time.Minute {
+		t.Errorf("Expected the tools configuration, got %+v", cfg.Tools)
+	}
+}
+
+func TestLLMProviderLimitsFromYAML(t *testing.T) {
+	limits := llmProviderLimitsFromYAML(config.LLMProvider{
+		Model:       "gpt-4o",
+		Models:      []string{"gpt-4o-mini"},
+		Temperature: 0.3,
+		MaxTokens:   2000,
+	})
+
+	if limits.Model != "gpt-4o" || len(limits.Models) != 1 || limits.Models[0] != "gpt-4o-mini" {
+		t.Errorf("Expected the configured models, got %+v", limits)
+	}
+	if limits.Temperature != 0.3 || limits.MaxTokens != 2000 {
+		t.Errorf("Expected temperature 0.3 and max tokens 2000, got %+v", limits)
+	}
+}
+
+func TestSchedulerConfigFromYAML(t *testing.T) {
+	cfg := schedulerConfigFromYAML(config.SchedulerConfig{
+		Enabled:             true,
+		MaxConcurrent:       2,
+		ExecutionTimeoutSec: 30,
+	})
+
+	if cfg.MaxConcurrent != 2 {
+		t.Errorf("Expected MaxConcurrent 2, got %d", cfg.MaxConcurrent)
+	}
+	if cfg.ExecutionTimeout != 30*time.Second {
+		t.Errorf("Expected ExecutionTimeout 30s, got %v", cfg.ExecutionTimeout)
+	}
+
+	// Zero values fall back to scheduler defaults
+	cfg = schedulerConfigFromYAML(config.SchedulerConfig{Enabled: true})
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected valid config with defaults, got %v", err)
+	}
+}
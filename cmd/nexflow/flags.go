@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+)
+
+const (
+	// defaultConfigPath is the configuration file read if neither -config nor NEXFLOW_CONFIG is set
+	defaultConfigPath = "config.yml"
+
+	// configPathEnv names the configuration file instead of -config
+	configPathEnv = "NEXFLOW_CONFIG"
+)
+
+// usage describes the command line of the nexflow binary
+const usage = `usage: nexflow [flags] [command [arguments]]
+
+commands:
+  init                      write a starter config.yml and the data directories
+  serve                     start the server (the default without a command)
+  migrate                   apply or roll back database migrations
+  backup, restore           back up or restore the database
+  config print              print the effective configuration
+  validate-config [file]    report every problem of the configuration
+  user list|create          list or create users
+  skill list|install        list or register skills
+  schedule run <id>         run a schedule now
+  version [-check]          print the version and check for a newer release
+
+The user, skill and schedule commands talk to the HTTP API of a running server with
+-api <url> (or NEXFLOW_API_URL) and open the database directly otherwise.
+
+Configuration is read from config.yml (or -config, NEXFLOW_CONFIG), with the section of
+the profile selected by -profile or NEXFLOW_PROFILE, e.g. -profile=production, and overridden by
+NEXFLOW_* environment variables, e.g. NEXFLOW_SERVER_PORT=8080, and then by flags,
+e.g. -server.port=8080. Keys inside maps and lists are set with -set, e.g.
+-set llm.providers.openai.api_key=sk-... or -set auth.api_keys.0.key=...
+Flags must precede the command.
+
+flags:`
+
+// cliOptions holds the parsed command line of the nexflow binary
+type cliOptions struct {
+	// ConfigPath is the configuration file, empty if none is read
+	ConfigPath string
+
+	// Profile is the configuration profile layered on top of the file, empty for none
+	Profile string
+
+	// Overrides are the configuration keys set by flags, in command-line order
+	Overrides []config.Override
+
+	// Args are the command and its arguments, empty to start the server
+	Args []string
+}
+
+// parseFlags parses the flags preceding the command
+//
+// Parameters:
+//   - args: Command-line arguments (without the program name)
+//   - getenv: Function environment variables are read with
+//   - output: Writer for usage output
+//
+// Returns:
+//   - *cliOptions: Parsed options
+//   - error: Error if the flags are invalid; flag.ErrHelp if help was requested
+func parseFlags(args []string, getenv func(string) string, output io.Writer) (*cliOptions, error) {
+	opts := &cliOptions{}
+	flags := flag.NewFlagSet("nexflow", flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.Usage = func() {
+		fmt.Fprintln(output, usage)
+		flags.PrintDefaults()
+	}
+
+	configPath := flags.String("config", "", "configuration file (default config.yml, or NEXFLOW_CONFIG)")
+	profile := flags.String("profile", "", "configuration profile, e.g. production (or NEXFLOW_PROFILE)")
+	flags.Func("set", "set a configuration key, `key=value`; may be repeated", func(s string) error {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("expected key=value, got %q", s)
+		}
+		opts.Overrides = append(opts.Overrides, config.Override{Key: key, Value: value})
+		return nil
+	})
+	for _, key := range config.Keys() {
+		flags.Func(key, "override "+key, func(value string) error {
+			opts.Overrides = append(opts.Overrides, config.Override{Key: key, Value: value})
+			return nil
+		})
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	opts.Args = flags.Args()
+
+	// A missing default file is not an error, so that the configuration can come from the
+	// environment alone; a file named by -config or NEXFLOW_CONFIG must exist
+	opts.ConfigPath = *configPath
+	if opts.ConfigPath == "" {
+		opts.ConfigPath = getenv(configPathEnv)
+	}
+	if opts.ConfigPath == "" {
+		opts.ConfigPath = defaultConfigPath
+		if _, err := os.Stat(defaultConfigPath); errors.Is(err, fs.ErrNotExist) {
+			opts.ConfigPath = ""
+		}
+	}
+
+	opts.Profile = *profile
+	if opts.Profile == "" {
+		opts.Profile = getenv(config.ProfileEnv)
+	}
+
+	return opts, nil
+}
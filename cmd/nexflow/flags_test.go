@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+)
+
+func noEnv(string) string { return "" }
+
+func TestParseFlags(t *testing.T) {
+	opts, err := parseFlags([]string{
+		"-config", "/etc/nexflow/config.yml",
+		"-server.port=9090",
+		"--set", "llm.providers.openai.api_key=sk-test",
+		"-server.port", "9091",
+		"migrate", "up",
+	}, noEnv, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/etc/nexflow/config.yml", opts.ConfigPath)
+	assert.Equal(t, []config.Override{
+		{Key: "server.port", Value: "9090"},
+		{Key: "llm.providers.openai.api_key", Value: "sk-test"},
+		{Key: "server.port", Value: "9091"},
+	}, opts.Overrides)
+	assert.Equal(t, []string{"migrate", "up"}, opts.Args)
+}
+
+func TestParseFlags_ConfigPath(t *testing.T) {
+	// NEXFLOW_CONFIG names the file if -config is not set
+	opts, err := parseFlags(nil, func(name string) string {
+		if name == configPathEnv {
+			return "/run/config.yml"
+		}
+		return ""
+	}, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, "/run/config.yml", opts.ConfigPath)
+
+	// A missing default file is skipped
+	t.Chdir(t.TempDir())
+	opts, err = parseFlags(nil, noEnv, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Empty(t, opts.ConfigPath)
+	assert.Empty(t, opts.Args)
+}
+
+func TestParseFlags_Profile(t *testing.T) {
+	env := func(name string) string {
+		if name == config.ProfileEnv {
+			return "development"
+		}
+		return ""
+	}
+
+	// NEXFLOW_PROFILE selects the profile if -profile is not set
+	opts, err := parseFlags(nil, env, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, "development", opts.Profile)
+
+	opts, err = parseFlags([]string{"-profile=production"}, env, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, "production", opts.Profile)
+	assert.Empty(t, opts.Overrides)
+}
+
+func TestParseFlags_Invalid(t *testing.T) {
+	var output bytes.Buffer
+	_, err := parseFlags([]string{"-set", "server.port"}, noEnv, &output)
+	assert.Error(t, err)
+
+	_, err = parseFlags([]string{"-server.unknown=1"}, noEnv, &output)
+	assert.Error(t, err)
+
+	output.Reset()
+	_, err = parseFlags([]string{"-help"}, noEnv, &output)
+	assert.ErrorIs(t, err, flag.ErrHelp)
+	assert.True(t, strings.HasPrefix(output.String(), "usage: nexflow"))
+	assert.Contains(t, output.String(), "-server.port")
+}
+
+func TestRunConfigCommand(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		LLM: config.LLMConfig{
+			DefaultProvider: "openai",
+			Providers:       map[string]config.LLMProvider{"openai": {APIKey: "sk-secret", Model: "gpt-4"}},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, runConfigCommand(cfg, []string{"print"}, &out))
+	assert.Contains(t, out.String(), "port: 8080")
+	assert.Contains(t, out.String(), "model: gpt-4")
+	assert.Contains(t, out.String(), "api_key: '******'")
+	assert.NotContains(t, out.String(), "sk-secret")
+
+	assert.Error(t, runConfigCommand(cfg, nil, &out))
+	assert.Error(t, runConfigCommand(cfg, []string{"show"}, &out))
+}
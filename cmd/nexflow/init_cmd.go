@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+)
+
+// initUsage describes the init subcommand
+const initUsage = `usage: nexflow init [-force]
+
+Writes a starter configuration to config.yml (or -config) and creates the data, backup and
+skills directories in the working directory. Migrations and the dashboard are built into
+the binary, so the server then runs from the binary and this one file.
+
+  -force  overwrite an existing configuration file`
+
+// runInitCommand runs the init subcommand with the given arguments.
+// No configuration is read.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - env: Command environment naming the configuration file
+//   - args: Subcommand arguments (without "init")
+//
+// Returns:
+//   - error: Error if the arguments are invalid, the configuration file exists or a file
+//     cannot be written
+func runInitCommand(ctx context.Context, env *commandEnv, args []string) error {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	force := flags.Bool("force", false, "overwrite an existing configuration file")
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w\n%s", err, initUsage)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v\n%s", flags.Args(), initUsage)
+	}
+
+	path := env.opts.ConfigPath
+	if path == "" {
+		path = defaultConfigPath
+	}
+
+	mode := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	// The file holds credentials once edited
+	file, err := os.OpenFile(path, mode, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("configuration file %s already exists; use -force to overwrite it", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create configuration file: %w", err)
+	}
+	if _, err := file.Write(config.StarterTemplate); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write configuration file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write configuration file: %w", err)
+	}
+	fmt.Fprintf(env.out, "wrote %s\n", path)
+
+	dirs := config.StarterDirectories()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	fmt.Fprintf(env.out, "created %s\n", strings.Join(dirs, ", "))
+
+	fmt.Fprintf(env.out, "\nnext: set ANTHROPIC_API_KEY (or edit the llm section of %s), check the configuration\n", path)
+	fmt.Fprintln(env.out, "with nexflow validate-config and start the server with nexflow serve")
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	// Parse the flags preceding the command
+	opts, err := parseFlags(os.Args[1:], os.Getenv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+
+	// Start the server if no command is given
+	name, args := "serve", opts.Args
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", name, usage)
+		os.Exit(2)
+	}
+
+	env := newCommandEnv(opts, os.Getenv, os.Stdout)
+	err = cmd.run(context.Background(), env, args)
+	if closeErr := env.close(); closeErr != nil {
+		log.Printf("Failed to close the %s command: %v", name, closeErr)
+	}
+	if err != nil {
+		log.Printf("%s command failed: %v", name, err)
+		os.Exit(exitCode(err))
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+)
+
+// migrateUsage describes the migrate subcommand
+const migrateUsage = `usage: nexflow migrate <command>
+
+commands:
+  up               apply all pending migrations
+  down             roll back the last migration
+  goto <version>   migrate up or down to the given version (0 = roll back everything)
+  version          print the current migration version and dirty state
+  force <version>  set the version and clear the dirty state without running migrations`
+
+// runMigrateCommand runs the migrate subcommand with the given arguments
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - db: Database migrations are applied to
+//   - args: Subcommand arguments (without "migrate")
+//   - out: Writer for command output
+//
+// Returns:
+//   - error: Error if the arguments are invalid or the migration failed
+func runMigrateCommand(ctx context.Context, db database.Migration, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := db.Migrate(ctx); err != nil {
+			return err
+		}
+	case "down":
+		if err := db.Rollback(ctx); err != nil {
+			return err
+		}
+	case "goto":
+		version, err := parseMigrationVersion(args)
+		if err != nil {
+			return err
+		}
+		if version < 0 {
+			return fmt.Errorf("version must not be negative: %d", version)
+		}
+		if err := db.MigrateTo(ctx, uint(version)); err != nil {
+			return err
+		}
+	case "force":
+		version, err := parseMigrationVersion(args)
+		if err != nil {
+			return err
+		}
+		if err := db.ForceMigrationVersion(ctx, version); err != nil {
+			return err
+		}
+	case "version":
+	default:
+		return fmt.Errorf("unknown migrate command: %s\n%s", args[0], migrateUsage)
+	}
+
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "version: %d, dirty: %t\n", status.Version, status.Dirty)
+	return nil
+}
+
+// parseMigrationVersion parses the version argument of goto and force
+func parseMigrationVersion(args []string) (int, error) {
+	if len(args) != 2 {
+		return 0, fmt.Errorf("%s requires a version argument\n%s", args[0], migrateUsage)
+	}
+
+	version, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q: %w", args[1], err)
+	}
+
+	return version, nil
+}
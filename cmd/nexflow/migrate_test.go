@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+)
+
+// mockMigration is a mock implementation of database.Migration for testing
+type mockMigration struct {
+	calls   []string
+	version uint
+	err     error
+}
+
+func (m *mockMigration) Migrate(ctx context.Context) error {
+	m.calls = append(m.calls, "up")
+	return m.err
+}
+
+func (m *mockMigration) MigrateTo(ctx context.Context, version uint) error {
+	m.calls = append(m.calls, "goto")
+	m.version = version
+	return m.err
+}
+
+func (m *mockMigration) Rollback(ctx context.Context) error {
+	m.calls = append(m.calls, "down")
+	return m.err
+}
+
+func (m *mockMigration) MigrationStatus(ctx context.Context) (database.MigrationStatus, error) {
+	return database.MigrationStatus{Version: m.version}, nil
+}
+
+func (m *mockMigration) ForceMigrationVersion(ctx context.Context, version int) error {
+	m.calls = append(m.calls, "force")
+	m.version = uint(version)
+	return m.err
+}
+
+func TestRunMigrateCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantCalls []string
+		wantOut   string
+		wantErr   bool
+	}{
+		{name: "up", args: []string{"up"}, wantCalls: []string{"up"}, wantOut: "version: 0, dirty: false\n"},
+		{name: "down", args: []string{"down"}, wantCalls: []string{"down"}, wantOut: "version: 0, dirty: false\n"},
+		{name: "goto", args: []string{"goto", "3"}, wantCalls: []string{"goto"}, wantOut: "version: 3, dirty: false\n"},
+		{name: "force", args: []string{"force", "4"}, wantCalls: []string{"force"}, wantOut: "version: 4, dirty: false\n"},
+		{name: "version", args: []string{"version"}, wantOut: "version: 0, dirty: false\n"},
+		{name: "missing command", args: nil, wantErr: true},
+		{name: "unknown command", args: []string{"sideways"}, wantErr: true},
+		{name: "goto without version", args: []string{"goto"}, wantErr: true},
+		{name: "goto negative version", args: []string{"goto", "-1"}, wantErr: true},
+		{name: "force invalid version", args: []string{"force", "abc"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mockMigration{}
+			var out bytes.Buffer
+
+			err := runMigrateCommand(context.Background(), db, tt.args, &out)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, db.calls)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, db.calls)
+			assert.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
+
+func TestRunMigrateCommand_PropagatesErrors(t *testing.T) {
+	db := &mockMigration{err: errors.New("dirty")}
+
+	err := runMigrateCommand(context.Background(), db, []string{"up"}, &bytes.Buffer{})
+
+	assert.Error(t, err)
+}
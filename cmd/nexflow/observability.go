@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
+)
+
+// newTracer creates the tracer exporting spans to the configured OTLP endpoint.
+// It returns nil when tracing is disabled, which disables tracing everywhere.
+func newTracer(cfg config.ObservabilityConfig, logger logging.Logger) *tracing.Tracer {
+	if !cfg.Tracing.Enabled {
+		return nil
+	}
+
+	timeout := time.Duration(cfg.Tracing.TimeoutSec) * time.Second
+	exporter := tracing.NewOTLPExporter(cfg.Tracing.Endpoint, cfg.ServiceName, cfg.Tracing.Headers, &http.Client{Timeout: timeout})
+	return tracing.NewTracer(tracing.Config{
+		Exporter:      exporter,
+		SampleRatio:   cfg.Tracing.SampleRatio,
+		BatchSize:     cfg.Tracing.BatchSize,
+		QueueSize:     cfg.Tracing.QueueSize,
+		FlushInterval: time.Duration(cfg.Tracing.FlushIntervalMs) * time.Millisecond,
+		ExportTimeout: timeout,
+		OnError: func(err error) {
+			logger.Warn("Failed to export traces", "endpoint", cfg.Tracing.Endpoint, "error", err)
+		},
+	})
+}
+
+// newMetricsPusher creates the pusher exporting the registries with the configured exporter.
+// It returns nil when metrics export is disabled; the metrics stay available on /metrics.
+func newMetricsPusher(cfg config.ObservabilityConfig, logger logging.Logger, registries []*metrics.MetricsRegistry) *metrics.Pusher {
+	m := cfg.Metrics
+	if !m.Enabled {
+		return nil
+	}
+
+	var exporter metrics.Exporter
+	var target string
+	switch m.Exporter {
+	case config.MetricsExporterStatsD:
+		exporter = metrics.NewStatsDExporter(m.StatsD.Address, m.StatsD.Prefix, m.StatsD.Tags)
+		target = m.StatsD.Address
+	default:
+		client := &http.Client{Timeout: time.Duration(m.OTLP.TimeoutSec) * time.Second}
+		exporter = metrics.NewOTLPExporter(m.OTLP.Endpoint, cfg.ServiceName, m.OTLP.Headers, client)
+		target = m.OTLP.Endpoint
+	}
+
+	return metrics.NewPusher(exporter, time.Duration(m.IntervalSec)*time.Second, func(err error) {
+		logger.Warn("Failed to export metrics", "exporter", m.Exporter, "target", target, "error", err)
+	}, registries...)
+}
+
+// newRecoverer creates the recoverer of panics, storing them in the crash log with
+// observability.crashes.store and sending them to Sentry if configured
+func newRecoverer(cfg config.ObservabilityConfig, logger logging.Logger, repo repository.CrashReportRepository, masker *logging.Masker) (*crash.Recoverer, error) {
+	recoverer := crash.NewRecoverer(logging.Named(logger, "crash"))
+	recoverer.SetMasker(masker)
+
+	if cfg.Crashes.Store {
+		recoverer.AddReporter(crashRepositoryReporter{repo: repo})
+	}
+	if sentry := cfg.Crashes.Sentry; sentry.Enabled {
+		client := &http.Client{Timeout: time.Duration(sentry.TimeoutSec) * time.Second}
+		reporter, err := crash.NewSentryReporter(sentry.DSN, sentry.Environment, cfg.ServiceName, client)
+		if err != nil {
+			return nil, err
+		}
+		recoverer.AddReporter(reporter)
+	}
+	return recoverer, nil
+}
+
+// newLogMasker creates the masker of the built-in secrets and those of the logging configuration
+func newLogMasker(cfg config.LoggingConfig) (*logging.Masker, error) {
+	return logging.NewMasker(cfg.Masking.Keys, cfg.Masking.Patterns)
+}
+
+// newLogger creates the logger writing to the configured outputs with the configured levels,
+// with secrets masked by masker. Records are also passed to handlers, e.g. the database log
+// handler. The returned function closes the log file.
+func newLogger(cfg config.LoggingConfig, masker *logging.Masker, handlers ...slog.Handler) (logging.Logger, func() error, error) {
+	level, err := logging.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid log level: %w", err)
+	}
+
+	levels := logging.NewLevels(level)
+	for module, moduleLevel := range cfg.Modules {
+		level, err := logging.ParseLevel(moduleLevel)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid log level of module %s: %w", module, err)
+		}
+		levels.Set(module, level)
+	}
+
+	var outputs []slog.Handler
+	if cfg.Output.Console {
+		outputs = append(outputs, logging.NewHandlerWithMasker(os.Stdout, cfg.Format, masker))
+	}
+	closeFile := func() error { return nil }
+	if file := cfg.Output.File; file.Enabled {
+		f, err := logging.OpenRotatingFile(file.Path, logging.RotationConfig{
+			MaxSize:    int64(file.MaxSizeMB) << 20,
+			Interval:   time.Duration(file.RotateIntervalHours) * time.Hour,
+			MaxBackups: file.MaxBackups,
+			MaxAge:     time.Duration(file.MaxAgeDays) * 24 * time.Hour,
+			Compress:   file.Compress,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		outputs = append(outputs, logging.NewHandlerWithMasker(f, cfg.Format, masker))
+		closeFile = f.Close
+	}
+
+	return logging.NewWithHandlers(levels, append(outputs, handlers...)...), closeFile, nil
+}
+
+// newDatabaseLogHandler creates the handler persisting logs to the logs table, with secrets
+// masked by masker. It returns nil when persisting logs is disabled.
+func newDatabaseLogHandler(cfg config.DatabaseLoggingConfig, masker *logging.Masker) *logging.DatabaseHandler {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	level, _ := logging.ParseLevel(cfg.Level)
+	return logging.NewDatabaseHandler(logging.DatabaseHandlerConfig{
+		Level:         level,
+		SampleRatio:   cfg.SampleRatio,
+		BatchSize:     cfg.BatchSize,
+		QueueSize:     cfg.QueueSize,
+		FlushInterval: time.Duration(cfg.FlushIntervalMs) * time.Millisecond,
+		Masker:        masker,
+	})
+}
+
+// logRepositorySink writes log records to the log repository, stopping at the first record
+// that cannot be written
+type logRepositorySink struct {
+	repo repository.LogRepository
+}
+
+// WriteLogs implements logging.LogSink
+func (s logRepositorySink) WriteLogs(ctx context.Context, records []logging.LogRecord) error {
+	for _, record := range records {
+		log := entity.NewLog(valueobject.LogLevel(record.Level), record.Source, record.Message, record.Attrs)
+		log.CreatedAt = record.Time.UTC()
+		if err := s.repo.Create(ctx, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crashRepositoryReporter stores recovered panics in the crash report repository
+type crashRepositoryReporter struct {
+	repo repository.CrashReportRepository
+}
+
+// ReportCrash implements crash.Reporter
+func (r crashRepositoryReporter) ReportCrash(ctx context.Context, report crash.Report) error {
+	crashReport := entity.NewCrashReport(report.Component, report.Operation, report.Panic, report.Stack, report.Attrs)
+	crashReport.CreatedAt = report.Time.UTC()
+	return r.repo.Create(ctx, crashReport)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/sqlite"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func TestDatabaseLogHandler(t *testing.T) {
+	cfg := config.DefaultLoggingConfig()
+	cfg.Database.Level = "warn"
+
+	logs := newDatabaseLogHandler(cfg.Database, logging.DefaultMasker())
+	require.NotNil(t, logs)
+	logger, err := logging.New("info", "json", logs)
+	require.NoError(t, err)
+
+	db, err := database.NewDatabase(&config.DatabaseConfig{
+		Type:           "sqlite",
+		Path:           ":memory:",
+		MigrationsPath: "../../migrations",
+	}, database.WithLogger(logger))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Migrate(context.Background()))
+
+	repo := sqlite.NewLogRepository(db.(*database.DB).Queries)
+	logs.Start(logRepositorySink{repo: repo}, nil)
+	logger.With("component", "scheduler").Warn("Schedule skipped", "schedule_id", "daily")
+	logger.Info("Not persisted")
+	require.NoError(t, logs.Close(context.Background()))
+
+	stored, err := repo.FindByLevel(context.Background(), "warn", repository.QueryOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "scheduler", stored[0].Source)
+	assert.Equal(t, "Schedule skipped", stored[0].Message)
+	assert.Equal(t, "daily", stored[0].GetMetadata()["schedule_id"])
+
+	count, err := repo.CountByLevel(context.Background(), "info")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	cfg.Database.Enabled = false
+	assert.Nil(t, newDatabaseLogHandler(cfg.Database, logging.DefaultMasker()))
+}
+
+func TestNewLogger(t *testing.T) {
+	cfg := config.DefaultLoggingConfig()
+	cfg.Modules = map[string]string{"telegram": "debug"}
+
+	logger, closeLogFile, err := newLogger(cfg, logging.DefaultMasker())
+	require.NoError(t, err)
+	require.NoError(t, closeLogFile())
+	levels := logging.LevelsOf(logger)
+	assert.Equal(t, slog.LevelInfo, levels.Default())
+	assert.Equal(t, []logging.ModuleLevel{{Name: "telegram", Level: slog.LevelDebug}}, levels.Modules())
+
+	cfg.Modules["router"] = "verbose"
+	_, _, err = newLogger(cfg, logging.DefaultMasker())
+	assert.ErrorContains(t, err, "module router")
+}
+
+func TestNewLogger_File(t *testing.T) {
+	cfg := config.DefaultLoggingConfig()
+	cfg.Output.Console = false
+	cfg.Output.File.Enabled = true
+	cfg.Output.File.Path = filepath.Join(t.TempDir(), "logs", "nexflow.log")
+	cfg.Masking.Patterns = []string{`acme_[0-9a-f]{12}`}
+	masker, err := newLogMasker(cfg)
+	require.NoError(t, err)
+
+	logger, closeLogFile, err := newLogger(cfg, masker)
+	require.NoError(t, err)
+	logger.Info("Written to the file", "api_key", "sk-1234567890abcdef")
+	logger.Warn("Key acme_0123456789ab rejected")
+	logger.Debug("Below the level")
+	require.NoError(t, closeLogFile())
+
+	data, err := os.ReadFile(cfg.Output.File.Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"Written to the file"`)
+	assert.NotContains(t, string(data), "sk-1234567890abcdef")
+	assert.NotContains(t, string(data), "acme_0123456789ab")
+	assert.NotContains(t, string(data), "Below the level")
+}
+
+func TestNewRecoverer_StoresCrashes(t *testing.T) {
+	logger, err := logging.New("error", "json")
+	require.NoError(t, err)
+
+	db, err := database.NewDatabase(&config.DatabaseConfig{
+		Type:           "sqlite",
+		Path:           ":memory:",
+		MigrationsPath: "../../migrations",
+	}, database.WithLogger(logger))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Migrate(context.Background()))
+
+	repo := sqlite.NewCrashReportRepository(db.(*database.DB).Queries)
+	cfg := config.DefaultObservabilityConfig()
+	recoverer, err := newRecoverer(cfg, logger, repo, logging.DefaultMasker())
+	require.NoError(t, err)
+
+	func() {
+		defer recoverer.Recover(context.Background(), "router", "route_message", nil, "connector", "telegram", "bot_token", "123456:secret")
+		panic("boom")
+	}()
+	recoverer.Wait()
+
+	stored, err := repo.List(context.Background(), repository.CrashReportFilter{})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "router", stored[0].Component)
+	assert.Equal(t, "boom", stored[0].Panic)
+	assert.Equal(t, "telegram", stored[0].Attributes["connector"])
+	assert.Equal(t, "***", stored[0].Attributes["bot_token"])
+	assert.Contains(t, stored[0].Stack, "TestNewRecoverer_StoresCrashes")
+
+	cfg.Crashes.Sentry = config.SentryConfig{Enabled: true, DSN: "not a dsn", TimeoutSec: 5}
+	_, err = newRecoverer(cfg, logger, repo, logging.DefaultMasker())
+	assert.ErrorContains(t, err, "invalid Sentry DSN")
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	grpcinf "github.com/atumaikin/nexflow/internal/infrastructure/grpc"
+	httpinf "github.com/atumaikin/nexflow/internal/infrastructure/http"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/shutdown"
+	"github.com/atumaikin/nexflow/internal/shared/version"
+)
+
+// serveUsage describes the serve subcommand
+const serveUsage = `usage: nexflow serve
+
+Starts the server; it is also started if no command is given.`
+
+// runServeCommand starts the server and blocks until it receives SIGINT or SIGTERM
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - env: Command environment the configuration is read from
+//   - args: Subcommand arguments (without "serve")
+//
+// Returns:
+//   - error: Error if the arguments or the configuration are invalid
+func runServeCommand(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments: %v\n%s", args, serveUsage)
+	}
+	cfg, err := env.config()
+	if err != nil {
+		return err
+	}
+
+	// Initialize logger; logs are queued for the database until it is available
+	masker, err := newLogMasker(cfg.Logging)
+	if err != nil {
+		return fmt.Errorf("failed to initialize log masking: %w", err)
+	}
+	var logHandlers []slog.Handler
+	databaseLogs := newDatabaseLogHandler(cfg.Logging.Database, masker)
+	if databaseLogs != nil {
+		logHandlers = append(logHandlers, databaseLogs)
+	}
+	logger, closeLogFile, err := newLogger(cfg.Logging, masker, logHandlers...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	build := version.Get()
+	logger.Info("Starting Nexflow server",
+		"version", build.Version,
+		"commit", build.Commit,
+		"build_date", build.BuildDate,
+		"go_version", build.GoVersion,
+		"host", cfg.Server.Host,
+		"port", cfg.Server.Port,
+		"tls", cfg.Server.TLS.Enabled(),
+	)
+
+	// Initialize tracing; without it no spans are recorded
+	tracer := newTracer(cfg.Observability, logger)
+	if tracer != nil {
+		logger.Info("Tracing enabled", "endpoint", cfg.Observability.Tracing.Endpoint, "sample_ratio", cfg.Observability.Tracing.SampleRatio)
+	}
+
+	// Initialize database
+	db, err := database.NewDatabase(&cfg.Database, database.WithLogger(logging.Named(logger, "db")), database.WithTracer(tracer))
+	if err != nil {
+		logger.Error("Failed to initialize database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// Run migrations
+	if err := db.Migrate(ctx); err != nil {
+		logger.Error("Failed to run migrations", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Migrations completed successfully")
+
+	// Initialize DI container
+	diContainer, err := NewDIContainer(cfg, logger, db, tracer)
+	if err != nil {
+		logger.Error("Failed to initialize DI container", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("DI container initialized successfully")
+
+	// Persist logs to the logs table
+	if databaseLogs != nil {
+		databaseLogs.Start(logRepositorySink{repo: diContainer.logRepo}, func(err error) {
+			logger.WarnContext(logging.WithoutDatabase(context.Background()), "Failed to persist logs", "error", err)
+		})
+	}
+
+	// Push metrics to the monitoring backend if configured
+	metricsPusher := newMetricsPusher(cfg.Observability, logger, diContainer.metricsRegistries())
+	if metricsPusher != nil {
+		metricsPusher.Start()
+		logger.Info("Metrics export enabled", "exporter", cfg.Observability.Metrics.Exporter, "interval_sec", cfg.Observability.Metrics.IntervalSec)
+	}
+
+	// Check the LLM providers in the background, so that messages fail over while the default is down
+	if llmHealth := diContainer.LLMHealth(); llmHealth != nil {
+		if err := llmHealth.Start(); err != nil {
+			logger.Error("Failed to start LLM health checks", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("LLM health checks started successfully")
+	}
+
+	// Join the cluster first, so that the components started next know whether this instance leads
+	if elector := diContainer.Elector(); elector != nil {
+		if err := elector.Start(); err != nil {
+			logger.Error("Failed to start leader election", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Leader election started successfully", "leader", elector.IsLeader())
+	}
+
+	// Start message router to begin receiving messages from connectors
+	if err := diContainer.MessageRouter().Start(); err != nil {
+		logger.Error("Failed to start message router", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Message router started successfully")
+
+	// Start answering asynchronous messages in the background
+	if jobs := diContainer.MessageJobs(); jobs != nil {
+		if err := jobs.Start(); err != nil {
+			logger.Error("Failed to start message jobs", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Message jobs started successfully")
+	}
+
+	// Start scheduler to fire enabled schedules
+	if sched := diContainer.Scheduler(); sched != nil {
+		if err := sched.Start(); err != nil {
+			logger.Error("Failed to start scheduler", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Scheduler started successfully")
+	}
+
+	// Start data retention to purge old records
+	if ret := diContainer.Retention(); ret != nil {
+		if err := ret.Start(); err != nil {
+			logger.Error("Failed to start data retention", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Data retention started successfully")
+	}
+
+	// Start outbound webhooks to post selected events
+	if dispatcher := diContainer.WebhookDispatcher(); dispatcher != nil {
+		if err := dispatcher.Start(); err != nil {
+			logger.Error("Failed to start outbound webhooks", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Outbound webhooks started successfully")
+	}
+
+	// Start running the background work queued by the components started above
+	if queue := diContainer.JobQueue(); queue != nil {
+		if err := queue.Start(); err != nil {
+			logger.Error("Failed to start job queue", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Job queue started successfully")
+	}
+
+	// Start conversation analytics to count events per day
+	if collector := diContainer.AnalyticsCollector(); collector != nil {
+		if err := collector.Start(); err != nil {
+			logger.Error("Failed to start conversation analytics", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Conversation analytics started successfully")
+	}
+
+	// Record LLM usage for billing
+	if recorder := diContainer.UsageRecorder(); recorder != nil {
+		if err := recorder.Start(); err != nil {
+			logger.Error("Failed to start llm usage recording", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("LLM usage recording started successfully")
+	}
+
+	// Check GitHub releases for a newer version
+	if checker := diContainer.UpdateChecker(); checker != nil {
+		if err := checker.Start(); err != nil {
+			logger.Error("Failed to start update check", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Access use cases from DI container
+	// chatUseCase := diContainer.ChatUseCase()
+	// userUseCase := diContainer.UserUseCase()
+	// skillUseCase := diContainer.SkillUseCase()
+	// scheduleUseCase := diContainer.ScheduleUseCase()
+
+	// Initialize HTTP server
+	router := httpinf.NewRouter()
+
+	// Register routes, the OpenAPI specification and Swagger UI
+	httpinf.RegisterRoutes(router, diContainer.HTTPHandlers())
+
+	// Apply middleware
+	handler := httpinf.NewHandlerBuilder(router).
+		Use(httpinf.Logging).
+		Use(httpinf.RecoveryWithRecoverer(diContainer.Recoverer())).
+		Use(httpinf.Tracing(tracer)).
+		Use(httpinf.Compress(gzip.DefaultCompression)).
+		Use(httpinf.SecurityHeaders(cfg.Server.SecurityHeaders)).
+		Use(httpinf.CORS(cfg.Server.CORS)).
+		Use(httpinf.RequestID).
+		Use(httpinf.RequestTimeout(cfg.Server.RequestTimeout(), router.Routes())).
+		Use(diContainer.RateLimiter().IPMiddleware).
+		Use(diContainer.Authenticator().Middleware).
+		Use(diContainer.RateLimiter().Middleware).
+		Use(diContainer.Idempotency().Middleware(router.Routes())).
+		Build()
+
+	// Create HTTP server
+	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
+	serverCfg := &httpinf.ServerConfig{
+		Addr:         addr,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+		Handler:      handler,
+		TLSCertFile:  cfg.Server.TLS.CertFile,
+		TLSKeyFile:   cfg.Server.TLS.KeyFile,
+	}
+	if autocert := cfg.Server.TLS.Autocert; autocert.Enabled() {
+		serverCfg.AutocertDomains = autocert.Domains
+		serverCfg.AutocertCacheDir = autocert.CacheDir
+		serverCfg.AutocertEmail = autocert.Email
+	}
+	if cfg.Server.TLS.RedirectPort != 0 {
+		serverCfg.RedirectAddr = fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.TLS.RedirectPort)
+	}
+	httpServer := httpinf.NewServer(serverCfg)
+
+	// Start HTTP server in goroutine
+	serverCtx := context.Background()
+	go func() {
+		if err := httpServer.Start(serverCtx); err != nil {
+			logger.Error("HTTP server error", "error", err)
+		}
+	}()
+
+	// Serve the gRPC API alongside HTTP with the same use cases and credentials
+	var grpcServer *grpcinf.Server
+	if cfg.Server.GRPC.Enabled {
+		certFile, keyFile := cfg.Server.GRPCTLSFiles()
+		grpcServer, err = grpcinf.NewServer(&grpcinf.ServerConfig{
+			Addr:           fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.GRPC.Port),
+			TLSCertFile:    certFile,
+			TLSKeyFile:     keyFile,
+			GetCertificate: httpServer.GetCertificate(),
+			Reflection:     cfg.Server.GRPC.Reflection,
+			RequestTimeout: cfg.Server.RequestTimeout(),
+			Auth:           cfg.Auth,
+			Authenticator:  diContainer.Authenticator(),
+		}, grpcinf.Services{
+			Chat:     diContainer.ChatUseCase(),
+			Skill:    diContainer.SkillUseCase(),
+			Schedule: diContainer.ScheduleUseCase(),
+		}, logging.Named(logger, "grpc"))
+		if err != nil {
+			logger.Error("Failed to create gRPC server", "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			if err := grpcServer.Start(serverCtx); err != nil {
+				logger.Error("gRPC server error", "error", err)
+			}
+		}()
+	}
+
+	logger.Info("Application started successfully, waiting for shutdown signal")
+
+	// Setup graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	// Wait for shutdown signal
+	<-sigChan
+
+	logger.Info("Shutting down gracefully...")
+
+	// Stop accepting requests and messages first, then drain the work in flight and flush
+	// the events, metrics, spans and logs left, each stage with its own timeout
+	shutdownTimeout := cfg.Server.Shutdown.StageTimeout()
+	shutdownManager := shutdown.NewManager(logging.Named(logger, "shutdown"))
+	shutdownManager.Add("http", cfg.Server.Shutdown.HTTPTimeout(), httpServer.Shutdown)
+	if grpcServer != nil {
+		shutdownManager.Add("grpc", cfg.Server.Shutdown.HTTPTimeout(), grpcServer.Shutdown)
+	}
+	diContainer.AddShutdownStages(shutdownManager)
+	if metricsPusher != nil {
+		shutdownManager.Add("metrics", shutdownTimeout, metricsPusher.Stop)
+	}
+	shutdownManager.Add("tracing", shutdownTimeout, tracer.Shutdown)
+
+	// Write the remaining logs before the database is closed
+	if databaseLogs != nil {
+		shutdownManager.Add("database logs", shutdownTimeout, func(ctx context.Context) error {
+			if err := databaseLogs.Close(ctx); err != nil {
+				return fmt.Errorf("%w (%d records dropped)", err, databaseLogs.Dropped())
+			}
+			return nil
+		})
+	}
+	shutdownManager.Run(context.Background())
+
+	if err := closeLogFile(); err != nil {
+		log.Printf("Failed to close the log file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/secrets"
+)
+
+// Exit codes of the validate-config subcommand
+const (
+	// exitInvalidConfig reports a configuration that fails validation
+	exitInvalidConfig = 1
+
+	// exitUsage reports invalid arguments
+	exitUsage = 2
+
+	// exitConfigParse reports a configuration file, environment variable or flag that
+	// cannot be read or parsed
+	exitConfigParse = 3
+
+	// exitConfigSecret reports a secret reference that cannot be resolved
+	exitConfigSecret = 4
+)
+
+// validateConfigUsage describes the validate-config subcommand
+const validateConfigUsage = `usage: nexflow validate-config [-strict] [-json] [file]
+
+Loads the configuration like the server does (file, profile, NEXFLOW_* environment
+variables and flags) and prints every problem found. The file defaults to -config.
+
+  -strict  also report keys of the file that name no configuration key, e.g. typos
+  -json    print the result as JSON
+
+exit codes:
+  0  the configuration is valid
+  1  the configuration is invalid
+  2  the arguments are invalid
+  3  a file, environment variable or flag cannot be read or parsed
+  4  a secret reference cannot be resolved`
+
+// configFailures describe the failures of the validate-config subcommand
+var configFailures = map[string]string{
+	"validation": "configuration is invalid",
+	"parse":      "configuration cannot be loaded",
+	"secret":     "secret reference cannot be resolved",
+}
+
+// configReport is the result of the validate-config subcommand
+type configReport struct {
+	Path    string   `json:"path"`              // Configuration file, empty if none is read
+	Profile string   `json:"profile,omitempty"` // Selected configuration profile
+	Valid   bool     `json:"valid"`
+	Failure string   `json:"failure,omitempty"` // "parse", "secret" or "validation" if not valid
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// runValidateConfigCommand runs the validate-config subcommand with the given arguments
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - env: Command environment the configuration is read from
+//   - args: Subcommand arguments (without "validate-config")
+//
+// Returns:
+//   - error: *exitError with the exit code if the arguments are invalid or the
+//     configuration cannot be loaded or is invalid
+func runValidateConfigCommand(ctx context.Context, env *commandEnv, args []string) error {
+	flags := flag.NewFlagSet("validate-config", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	strict := flags.Bool("strict", false, "report unknown keys")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+
+	if err := flags.Parse(args); err != nil {
+		return &exitError{code: exitUsage, err: fmt.Errorf("%w\n%s", err, validateConfigUsage)}
+	}
+	if flags.NArg() > 1 {
+		return &exitError{code: exitUsage, err: fmt.Errorf("unexpected arguments: %v\n%s", flags.Args()[1:], validateConfigUsage)}
+	}
+	if flags.NArg() == 1 {
+		env.opts.ConfigPath = flags.Arg(0)
+	}
+
+	report, code := validateConfig(env, *strict)
+	if *asJSON {
+		enc := json.NewEncoder(env.out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printConfigReport(env.out, report)
+	}
+
+	if code != 0 {
+		return &exitError{code: code, err: errors.New(configFailures[report.Failure])}
+	}
+	return nil
+}
+
+// validateConfig loads and validates the configuration selected by env and returns the
+// report with the exit code
+func validateConfig(env *commandEnv, strict bool) (*configReport, int) {
+	report := &configReport{Path: env.opts.ConfigPath, Profile: env.opts.Profile}
+
+	_, err := env.config()
+	var invalid config.ValidationErrors
+	var secretErr *secrets.ResolveError
+	switch {
+	case errors.As(err, &invalid):
+		for _, problem := range invalid {
+			report.Errors = append(report.Errors, problem.Error())
+		}
+	case errors.As(err, &secretErr):
+		report.Failure, report.Errors = "secret", []string{secretErr.Error()}
+		return report, exitConfigSecret
+	case err != nil:
+		report.Failure, report.Errors = "parse", []string{err.Error()}
+		return report, exitConfigParse
+	}
+
+	// Unknown keys are reported with the other problems, since the file could be parsed
+	if strict && report.Path != "" {
+		unknown, err := config.UnknownKeys(report.Path, report.Profile)
+		if err != nil {
+			report.Failure, report.Errors = "parse", []string{err.Error()}
+			return report, exitConfigParse
+		}
+		for _, key := range unknown {
+			report.Errors = append(report.Errors, fmt.Sprintf("unknown key %s", key))
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		report.Failure = "validation"
+		return report, exitInvalidConfig
+	}
+	report.Valid = true
+	return report, 0
+}
+
+// printConfigReport prints a report for humans
+func printConfigReport(out io.Writer, report *configReport) {
+	source := report.Path
+	if source == "" {
+		source = "environment"
+	}
+	if report.Profile != "" {
+		source += " (profile " + report.Profile + ")"
+	}
+
+	if report.Valid {
+		fmt.Fprintf(out, "%s: configuration is valid\n", source)
+		return
+	}
+	fmt.Fprintf(out, "%s: %s\n", source, configFailures[report.Failure])
+	for _, problem := range report.Errors {
+		fmt.Fprintf(out, "  - %s\n", problem)
+	}
+}
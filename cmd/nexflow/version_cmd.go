@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/version"
+)
+
+// versionUsage describes the version subcommand
+const versionUsage = `usage: nexflow version [-json] [-check [-repository owner/name]]
+
+Prints the version, commit and build date of the binary.
+
+  -check       also read the latest GitHub release and report whether it is newer
+  -repository  repository releases are read from (default aatumaykin/nexflow)
+  -json        print the result as JSON`
+
+// githubAPIURL is the GitHub API the version subcommand reads releases from
+var githubAPIURL = version.GitHubAPIURL
+
+// versionReport is the result of the version subcommand
+type versionReport struct {
+	version.Info
+	Update *version.UpdateStatus `json:"update,omitempty"` // Set with -check
+}
+
+// runVersionCommand runs the version subcommand with the given arguments.
+// No configuration is read.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - env: Command environment output is written to
+//   - args: Subcommand arguments (without "version")
+//
+// Returns:
+//   - error: Error if the arguments are invalid or the latest release cannot be read
+func runVersionCommand(ctx context.Context, env *commandEnv, args []string) error {
+	flags := flag.NewFlagSet("version", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	check := flags.Bool("check", false, "check for a newer release")
+	repository := flags.String("repository", config.DefaultUpdateCheckConfig().Repository, "GitHub repository")
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w\n%s", err, versionUsage)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v\n%s", flags.Args(), versionUsage)
+	}
+
+	report := versionReport{Info: version.Get()}
+	if *check {
+		checker := version.NewChecker(report.Version, version.CheckerConfig{
+			Repository: *repository,
+			APIURL:     githubAPIURL,
+		}, nil, logging.NewNoopLogger())
+		status, err := checker.Check(ctx)
+		if err != nil {
+			return err
+		}
+		report.Update = &status
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(env.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Fprintln(env.out, report.Info)
+	if report.Update != nil {
+		latest := report.Update.Latest
+		if report.Update.Available {
+			fmt.Fprintf(env.out, "a newer version is available: %s (%s)\n", latest.Version, latest.URL)
+		} else {
+			fmt.Fprintf(env.out, "latest release: %s\n", latest.Version)
+		}
+	}
+	return nil
+}
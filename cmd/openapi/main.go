@@ -0,0 +1,58 @@
+// Command openapi generates the OpenAPI specification of the Nexflow HTTP API.
+//
+// Usage:
+//
+//	openapi [-o docs/openapi.json] [-check]
+//
+// Without -o the specification is written to stdout. With -check the file given by -o
+// is compared with the generated specification instead, and the command exits with
+// status 1 when it is out of date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	httpinf "github.com/atumaikin/nexflow/internal/infrastructure/http"
+)
+
+func main() {
+	output := flag.String("o", "", "write the specification to this file instead of stdout")
+	check := flag.Bool("check", false, "fail if the file given by -o is not up to date")
+	flag.Parse()
+
+	router := httpinf.NewRouter()
+	httpinf.RegisterRoutes(router, httpinf.Handlers{})
+
+	spec, err := httpinf.MarshalOpenAPISpec(router.Routes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate OpenAPI specification: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case *check:
+		if *output == "" {
+			fmt.Fprintln(os.Stderr, "-check requires -o")
+			os.Exit(2)
+		}
+		current, err := os.ReadFile(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", *output, err)
+			os.Exit(1)
+		}
+		if !bytes.Equal(current, spec) {
+			fmt.Fprintf(os.Stderr, "%s is out of date, run: go run ./cmd/openapi -o %s\n", *output, *output)
+			os.Exit(1)
+		}
+	case *output != "":
+		if err := os.WriteFile(*output, spec, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", *output, err)
+			os.Exit(1)
+		}
+	default:
+		os.Stdout.Write(spec)
+	}
+}
@@ -0,0 +1,178 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// eventTypes are the event types the collector counts
+var eventTypes = []string{
+	eventbus.EventConnectorMessage,
+	eventbus.EventLLMResponse,
+	eventbus.EventLLMError,
+	eventbus.EventSkillCompleted,
+	eventbus.EventSkillFailed,
+}
+
+// AnalyticsMetrics holds all metrics for the analytics collector
+type AnalyticsMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	EventsCounted *metrics.Counter
+	EventsFailed  *metrics.Counter
+}
+
+// NewAnalyticsMetrics creates a new AnalyticsMetrics instance
+func NewAnalyticsMetrics() *AnalyticsMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &AnalyticsMetrics{
+		registry: registry,
+
+		EventsCounted: registry.GetCounter("analytics_events_counted_total"),
+		EventsFailed:  registry.GetCounter("analytics_events_failed_total"),
+	}
+}
+
+// Registry returns the registry holding the analytics collector metrics
+func (m *AnalyticsMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Collector counts connector messages, LLM calls and skill executions published on the event
+// bus into daily counters. Events are counted on the UTC day they were published.
+type Collector struct {
+	eventBus *eventbus.EventBus
+	repo     repository.AnalyticsRepository
+	logger   logging.Logger
+	metrics  *AnalyticsMetrics
+
+	mu           sync.Mutex
+	subscription *eventbus.EventSubscription
+}
+
+// NewCollector creates a new Collector instance
+//
+// Parameters:
+//   - eventBus: EventBus the events are taken from
+//   - repo: AnalyticsRepository the counters are kept in
+//   - logger: Structured logger for logging
+//
+// Returns:
+//   - *Collector: Initialized analytics collector
+func NewCollector(eventBus *eventbus.EventBus, repo repository.AnalyticsRepository, logger logging.Logger) *Collector {
+	return &Collector{
+		eventBus: eventBus,
+		repo:     repo,
+		logger:   logger,
+		metrics:  NewAnalyticsMetrics(),
+	}
+}
+
+// Metrics returns the analytics collector metrics
+func (c *Collector) Metrics() *AnalyticsMetrics {
+	return c.metrics
+}
+
+// Start subscribes to the counted event types
+func (c *Collector) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subscription != nil {
+		return fmt.Errorf("analytics collector already started")
+	}
+	if c.eventBus == nil {
+		return fmt.Errorf("analytics require the event bus")
+	}
+
+	// Counters are incremented, so a retried handler would count an event twice
+	c.subscription = c.eventBus.SubscribeWithOptions(eventTypes, eventbus.SubscribeOptions{
+		Name:  "analytics",
+		Retry: &eventbus.RetryPolicy{MaxAttempts: 1},
+	}, c.handleEvent)
+
+	c.logger.Info("analytics collector started")
+	return nil
+}
+
+// Stop unsubscribes from the event bus
+func (c *Collector) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subscription == nil {
+		return nil
+	}
+	c.eventBus.Unsubscribe(c.subscription)
+	c.subscription = nil
+
+	c.logger.Info("analytics collector stopped")
+	return nil
+}
+
+// handleEvent adds an event to the counters of its day
+func (c *Collector) handleEvent(ctx context.Context, event eventbus.Event) error {
+	if err := c.count(ctx, event); err != nil {
+		c.metrics.EventsFailed.Inc()
+		return fmt.Errorf("failed to count %s event: %w", event.Type(), err)
+	}
+	c.metrics.EventsCounted.Inc()
+	return nil
+}
+
+// count increments the counters an event contributes to
+func (c *Collector) count(ctx context.Context, event eventbus.Event) error {
+	day := entity.AnalyticsDay(event.Timestamp())
+
+	switch e := event.(type) {
+	case *eventbus.ConnectorEvent:
+		if err := c.repo.Increment(ctx, day, entity.AnalyticsMessages, e.ConnectorName, 1); err != nil {
+			return err
+		}
+		if e.UserID == "" {
+			return nil
+		}
+		return c.repo.AddActiveUser(ctx, day, e.ConnectorName, e.UserID)
+
+	case *eventbus.LLMPublishedEvent:
+		if e.Type() == eventbus.EventLLMError {
+			return c.repo.Increment(ctx, day, entity.AnalyticsLLMErrors, e.Model, 1)
+		}
+		return c.increment(ctx, day, e.Model, map[entity.AnalyticsMetric]float64{
+			entity.AnalyticsLLMRequests: 1,
+			entity.AnalyticsTokens:      float64(e.Tokens),
+			entity.AnalyticsCost:        e.Cost,
+		})
+
+	case *eventbus.SkillEvent:
+		counts := map[entity.AnalyticsMetric]float64{entity.AnalyticsSkillExecutions: 1}
+		if e.Type() == eventbus.EventSkillFailed {
+			counts[entity.AnalyticsSkillFailures] = 1
+		}
+		return c.increment(ctx, day, e.SkillName, counts)
+	}
+
+	c.logger.Debug("analytics ignored event of unexpected kind", "type", event.Type())
+	return nil
+}
+
+// increment adds the non-zero counts to the counters of a dimension
+func (c *Collector) increment(ctx context.Context, day, dimension string, counts map[entity.AnalyticsMetric]float64) error {
+	for metric, delta := range counts {
+		if delta == 0 {
+			continue
+		}
+		if err := c.repo.Increment(ctx, day, metric, dimension, delta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockAnalyticsRepository is an in-memory repository.AnalyticsRepository
+type mockAnalyticsRepository struct {
+	mu      sync.Mutex
+	values  map[string]float64
+	users   map[string]bool
+	updates int
+}
+
+func newMockAnalyticsRepository() *mockAnalyticsRepository {
+	return &mockAnalyticsRepository{values: map[string]float64{}, users: map[string]bool{}}
+}
+
+func (m *mockAnalyticsRepository) Increment(ctx context.Context, day string, metric entity.AnalyticsMetric, dimension string, delta float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[day+"/"+string(metric)+"/"+dimension] += delta
+	m.updates++
+	return nil
+}
+
+func (m *mockAnalyticsRepository) AddActiveUser(ctx context.Context, day, connector, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[day+"/"+connector+"/"+userID] = true
+	m.updates++
+	return nil
+}
+
+func (m *mockAnalyticsRepository) ListCounters(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAnalyticsRepository) CountActiveUsers(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	return nil, errors.New("not implemented")
+}
+
+// value returns the value of a counter
+func (m *mockAnalyticsRepository) value(day string, metric entity.AnalyticsMetric, dimension string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[day+"/"+string(metric)+"/"+dimension]
+}
+
+// updateCount returns the number of writes to the repository
+func (m *mockAnalyticsRepository) updateCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updates
+}
+
+func newTestEventBus(t *testing.T) *eventbus.EventBus {
+	t.Helper()
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{
+		BatchSize:     10,
+		FlushInterval: 10 * time.Millisecond,
+		Logger:        logging.NewNoopLogger(),
+	})
+	require.NoError(t, bus.Start())
+	t.Cleanup(func() { _ = bus.Stop() })
+	return bus
+}
+
+func TestCollector_CountsEvents(t *testing.T) {
+	bus := newTestEventBus(t)
+	repo := newMockAnalyticsRepository()
+	collector := NewCollector(bus, repo, logging.NewNoopLogger())
+	require.NoError(t, collector.Start())
+	t.Cleanup(func() { _ = collector.Stop() })
+
+	bus.Publish(eventbus.NewConnectorEvent(eventbus.EventConnectorMessage, "telegram", "alice", "chat-1", "Hello", nil))
+	bus.Publish(eventbus.NewConnectorEvent(eventbus.EventConnectorMessage, "telegram", "alice", "chat-1", "Are you there?", nil))
+	bus.Publish(eventbus.NewLLMEvent(eventbus.EventLLMResponse, "openai", "gpt-4o", 120, 0.002, time.Second, nil))
+	bus.Publish(eventbus.NewLLMEvent(eventbus.EventLLMError, "openai", "gpt-4o", 0, 0, time.Second, errors.New("timeout")))
+	bus.Publish(eventbus.NewSkillEvent(eventbus.EventSkillCompleted, "weather", "{}", "sunny", nil, time.Second))
+	bus.Publish(eventbus.NewSkillEvent(eventbus.EventSkillFailed, "weather", "{}", "", errors.New("no city"), time.Second))
+	// Events of other types are not counted
+	bus.Publish(eventbus.NewTaskEvent(eventbus.EventTaskCompleted, "task-1", "session-1", "weather", "completed", "{}", "sunny", ""))
+
+	require.Eventually(t, func() bool { return collector.Metrics().EventsCounted.Get() == 6 }, 5*time.Second, 10*time.Millisecond)
+
+	day := entity.AnalyticsDay(time.Now())
+	assert.Equal(t, 2.0, repo.value(day, entity.AnalyticsMessages, "telegram"))
+	assert.Equal(t, 1.0, repo.value(day, entity.AnalyticsLLMRequests, "gpt-4o"))
+	assert.Equal(t, 120.0, repo.value(day, entity.AnalyticsTokens, "gpt-4o"))
+	assert.Equal(t, 0.002, repo.value(day, entity.AnalyticsCost, "gpt-4o"))
+	assert.Equal(t, 1.0, repo.value(day, entity.AnalyticsLLMErrors, "gpt-4o"))
+	assert.Equal(t, 2.0, repo.value(day, entity.AnalyticsSkillExecutions, "weather"))
+	assert.Equal(t, 1.0, repo.value(day, entity.AnalyticsSkillFailures, "weather"))
+	assert.True(t, repo.users[day+"/telegram/alice"])
+	// 2 messages, 2 active user writes, 3 LLM response counters, 1 LLM error, 3 skill counters
+	assert.Equal(t, 11, repo.updateCount())
+}
+
+func TestCollector_Stop(t *testing.T) {
+	bus := newTestEventBus(t)
+	repo := newMockAnalyticsRepository()
+	collector := NewCollector(bus, repo, logging.NewNoopLogger())
+	require.NoError(t, collector.Start())
+	assert.Error(t, collector.Start())
+	require.NoError(t, collector.Stop())
+	require.NoError(t, collector.Stop())
+
+	bus.Publish(eventbus.NewConnectorEvent(eventbus.EventConnectorMessage, "telegram", "alice", "chat-1", "Hello", nil))
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, repo.updateCount())
+}
+
+func TestCollector_StartWithoutEventBus(t *testing.T) {
+	collector := NewCollector(nil, newMockAnalyticsRepository(), logging.NewNoopLogger())
+	assert.Error(t, collector.Start())
+}
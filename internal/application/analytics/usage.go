@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// UsageRecorder persists the LLM usage events published on the event bus, so that operators
+// can bill the tokens and cost of users and workspaces by month. Usage is recorded for the
+// UTC month the event was published in.
+type UsageRecorder struct {
+	eventBus *eventbus.EventBus
+	repo     repository.LLMUsageRepository
+	logger   logging.Logger
+
+	mu           sync.Mutex
+	subscription *eventbus.EventSubscription
+}
+
+// NewUsageRecorder creates a new UsageRecorder instance
+//
+// Parameters:
+//   - eventBus: EventBus the usage events are taken from
+//   - repo: LLMUsageRepository the usage is kept in
+//   - logger: Structured logger for logging
+//
+// Returns:
+//   - *UsageRecorder: Initialized usage recorder
+func NewUsageRecorder(eventBus *eventbus.EventBus, repo repository.LLMUsageRepository, logger logging.Logger) *UsageRecorder {
+	return &UsageRecorder{
+		eventBus: eventBus,
+		repo:     repo,
+		logger:   logger,
+	}
+}
+
+// Start subscribes to the usage events
+func (r *UsageRecorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.subscription != nil {
+		return fmt.Errorf("usage recorder already started")
+	}
+	if r.eventBus == nil {
+		return fmt.Errorf("usage recording requires the event bus")
+	}
+
+	// Usage is keyed by the usage ID, so a retried handler records an event once
+	r.subscription = r.eventBus.SubscribeWithOptions([]string{eventbus.EventLLMUsage}, eventbus.SubscribeOptions{
+		Name: "usage",
+	}, r.handleEvent)
+
+	r.logger.Info("usage recorder started")
+	return nil
+}
+
+// Stop unsubscribes from the event bus
+func (r *UsageRecorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.subscription == nil {
+		return nil
+	}
+	r.eventBus.Unsubscribe(r.subscription)
+	r.subscription = nil
+
+	r.logger.Info("usage recorder stopped")
+	return nil
+}
+
+// handleEvent saves a usage event
+func (r *UsageRecorder) handleEvent(ctx context.Context, event eventbus.Event) error {
+	e, ok := event.(*eventbus.UsageEvent)
+	if !ok {
+		r.logger.Debug("usage recorder ignored event of unexpected kind", "type", event.Type())
+		return nil
+	}
+	if e.UsageID == "" {
+		r.logger.Warn("usage recorder ignored event without usage ID", "session_id", e.SessionID)
+		return nil
+	}
+
+	usage := &entity.LLMUsage{
+		ID:           e.UsageID,
+		UserID:       valueobject.UserID(e.UserID),
+		WorkspaceID:  valueobject.WorkspaceID(e.WorkspaceID),
+		SessionID:    valueobject.SessionID(e.SessionID),
+		Provider:     e.ProviderName,
+		Model:        e.Model,
+		InputTokens:  e.InputTokens,
+		OutputTokens: e.OutputTokens,
+		TotalTokens:  e.TotalTokens,
+		Cost:         e.Cost,
+		Month:        entity.UsageMonth(e.Timestamp()),
+		CreatedAt:    e.Timestamp().UTC(),
+	}
+	if err := r.repo.Create(ctx, usage); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockUsageRepository is an in-memory repository.LLMUsageRepository
+type mockUsageRepository struct {
+	mu    sync.Mutex
+	usage map[string]*entity.LLMUsage
+}
+
+func (m *mockUsageRepository) Create(ctx context.Context, usage *entity.LLMUsage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.usage[usage.ID]; !ok {
+		m.usage[usage.ID] = usage
+	}
+	return nil
+}
+
+func (m *mockUsageRepository) SumByMonth(ctx context.Context, since, until string) ([]*entity.LLMUsageTotal, error) {
+	return nil, errors.New("not implemented")
+}
+
+// get returns the usage recorded with an ID
+func (m *mockUsageRepository) get(id string) *entity.LLMUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage[id]
+}
+
+func TestUsageRecorder_RecordsUsage(t *testing.T) {
+	bus := newTestEventBus(t)
+	repo := &mockUsageRepository{usage: map[string]*entity.LLMUsage{}}
+	recorder := NewUsageRecorder(bus, repo, logging.NewNoopLogger())
+	require.NoError(t, recorder.Start())
+	t.Cleanup(func() { _ = recorder.Stop() })
+
+	used := eventbus.NewUsageEvent(eventbus.EventLLMUsage, "user-1", "support", "session-1", "openai", "gpt-4o", 120, 30, 150, 0.002)
+	bus.Publish(used)
+	// A redelivered event is recorded once
+	bus.Publish(used)
+
+	require.Eventually(t, func() bool { return repo.get(used.UsageID) != nil }, 5*time.Second, 10*time.Millisecond)
+
+	usage := repo.get(used.UsageID)
+	assert.Equal(t, "user-1", usage.UserID.String())
+	assert.Equal(t, "support", usage.WorkspaceID.String())
+	assert.Equal(t, "session-1", usage.SessionID.String())
+	assert.Equal(t, "openai", usage.Provider)
+	assert.Equal(t, "gpt-4o", usage.Model)
+	assert.Equal(t, []int{120, 30, 150}, []int{usage.InputTokens, usage.OutputTokens, usage.TotalTokens})
+	assert.Equal(t, 0.002, usage.Cost)
+	assert.Equal(t, entity.UsageMonth(used.Timestamp()), usage.Month)
+}
+
+func TestUsageRecorder_Stop(t *testing.T) {
+	bus := newTestEventBus(t)
+	repo := &mockUsageRepository{usage: map[string]*entity.LLMUsage{}}
+	recorder := NewUsageRecorder(bus, repo, logging.NewNoopLogger())
+	require.NoError(t, recorder.Start())
+	assert.Error(t, recorder.Start())
+	require.NoError(t, recorder.Stop())
+	require.NoError(t, recorder.Stop())
+
+	used := eventbus.NewUsageEvent(eventbus.EventLLMUsage, "user-1", "", "session-1", "openai", "gpt-4o", 120, 30, 150, 0.002)
+	bus.Publish(used)
+	time.Sleep(50 * time.Millisecond)
+	assert.Nil(t, repo.get(used.UsageID))
+}
+
+func TestUsageRecorder_StartWithoutEventBus(t *testing.T) {
+	recorder := NewUsageRecorder(nil, &mockUsageRepository{}, logging.NewNoopLogger())
+	assert.Error(t, recorder.Start())
+}
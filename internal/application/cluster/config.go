@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidationError represents a cluster configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Config holds configuration for the leader election
+type Config struct {
+	// InstanceID names this instance in the lease it holds; empty uses the host name, the
+	// process ID and a random suffix
+	InstanceID string
+
+	// LeaseTTL is how long the leadership lasts without being renewed, and so how long the
+	// instances wait for a new leader when the leader stops without releasing it
+	LeaseTTL time.Duration
+
+	// RenewInterval is how often the leader renews its lease and the other instances try to
+	// take it over
+	RenewInterval time.Duration
+}
+
+// DefaultConfig returns the default configuration for the leader election
+func DefaultConfig() *Config {
+	return &Config{
+		LeaseTTL:      15 * time.Second,
+		RenewInterval: 5 * time.Second,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.RenewInterval <= 0 {
+		return &ValidationError{Field: "RenewInterval", Message: "must be positive"}
+	}
+
+	// Leases are stored with second precision, and a failed renewal must be retried at least
+	// once before the lease expires
+	if c.LeaseTTL < 2*time.Second || c.LeaseTTL < 2*c.RenewInterval {
+		return &ValidationError{Field: "LeaseTTL", Message: "must be at least 2s and twice RenewInterval"}
+	}
+
+	return nil
+}
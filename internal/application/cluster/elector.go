@@ -0,0 +1,259 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// leaderLease is the name of the lease held by the leader
+const leaderLease = "leader"
+
+// ClusterMetrics holds all metrics for the leader election
+type ClusterMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	Leader            *metrics.Gauge
+	LeadershipChanges *metrics.Counter
+	LeaseErrors       *metrics.Counter
+}
+
+// NewClusterMetrics creates a new ClusterMetrics instance
+func NewClusterMetrics() *ClusterMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &ClusterMetrics{
+		registry: registry,
+
+		Leader:            registry.GetGauge("cluster_leader"),
+		LeadershipChanges: registry.GetCounter("cluster_leadership_changes_total"),
+		LeaseErrors:       registry.GetCounter("cluster_lease_errors_total"),
+	}
+}
+
+// Registry returns the registry holding the cluster metrics
+func (m *ClusterMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Elector elects the leader of the instances sharing the database with a lease stored in it.
+// Every instance tries to acquire the lease each renew interval; the holder renews it, and the
+// lease of a leader that stopped without releasing it is taken over once it expired. Clocks of
+// the instances must be synchronized to well within the renew interval.
+type Elector struct {
+	repo       repository.LeaseRepository
+	logger     logging.Logger
+	config     *Config
+	metrics    *ClusterMetrics
+	instanceID string
+	now        func() time.Time
+
+	leader atomic.Bool
+
+	// validUntil is when the lease of the leader expires; only used by the campaigns, which
+	// never run concurrently
+	validUntil time.Time
+
+	mu        sync.Mutex
+	listeners []func(leader bool)
+	started   bool
+	stopping  chan struct{}
+	done      chan struct{}
+}
+
+// Compile-time check that Elector implements ports.Leadership
+var _ ports.Leadership = (*Elector)(nil)
+
+// NewElector creates a new Elector instance
+//
+// Parameters:
+//   - repo: LeaseRepository storing the lease of the leader
+//   - logger: Structured logger for logging
+//   - config: Leader election configuration (uses defaults if nil)
+//
+// Returns:
+//   - *Elector: Initialized elector
+func NewElector(repo repository.LeaseRepository, logger logging.Logger, config *Config) *Elector {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	if err := config.Validate(); err != nil {
+		logger.Error("invalid cluster configuration, using defaults", "error", err)
+		instanceID := config.InstanceID
+		config = DefaultConfig()
+		config.InstanceID = instanceID
+	}
+
+	instanceID := config.InstanceID
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+
+	return &Elector{
+		repo:       repo,
+		logger:     logger,
+		config:     config,
+		metrics:    NewClusterMetrics(),
+		instanceID: instanceID,
+		now:        time.Now,
+		stopping:   make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// defaultInstanceID returns a name for this instance, unique even for processes of the same host
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "nexflow"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), valueobject.GenerateID(nil).String()[:8])
+}
+
+// Metrics returns the cluster metrics
+func (e *Elector) Metrics() *ClusterMetrics {
+	return e.metrics
+}
+
+// InstanceID returns the name this instance holds the lease under
+func (e *Elector) InstanceID() string {
+	return e.instanceID
+}
+
+// IsLeader returns true while this instance holds the leadership
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// OnChange registers a function called with the new state whenever this instance gains or
+// loses the leadership. The functions are called one after another by the elector, after
+// IsLeader reports the new state, so they must not block for long. It must be called before
+// Start.
+func (e *Elector) OnChange(fn func(leader bool)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.listeners = append(e.listeners, fn)
+}
+
+// Start tries to acquire the leadership right away, so that the components started next know
+// whether this instance leads, and then keeps campaigning in the background
+func (e *Elector) Start() error {
+	e.mu.Lock()
+	if e.started {
+		e.mu.Unlock()
+		return fmt.Errorf("elector already started")
+	}
+	e.started = true
+	e.mu.Unlock()
+
+	e.campaign()
+	go e.loop()
+
+	e.logger.Info("elector started", "instance_id", e.instanceID, "leader", e.IsLeader())
+	return nil
+}
+
+// Stop stops campaigning, gives up the leadership and releases the lease, so that another
+// instance takes over without waiting for the lease to expire
+func (e *Elector) Stop(ctx context.Context) error {
+	e.mu.Lock()
+	if !e.started {
+		e.mu.Unlock()
+		return nil
+	}
+	e.started = false
+	close(e.stopping)
+	e.mu.Unlock()
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to stop elector: %w", ctx.Err())
+	}
+
+	if e.IsLeader() {
+		e.setLeader(false)
+		if err := e.repo.Release(ctx, leaderLease, e.instanceID); err != nil {
+			return fmt.Errorf("failed to release leadership: %w", err)
+		}
+	}
+
+	e.logger.Info("elector stopped")
+	return nil
+}
+
+// loop campaigns every renew interval until the elector is stopped
+func (e *Elector) loop() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.config.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stopping:
+			return
+		case <-ticker.C:
+			e.campaign()
+		}
+	}
+}
+
+// campaign acquires or renews the lease of the leader. When the lease cannot be written, the
+// leader keeps the leadership as long as its lease surely outlasts the next campaign.
+func (e *Elector) campaign() {
+	now := e.now()
+	expiresAt := now.Add(e.config.LeaseTTL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.RenewInterval)
+	acquired, err := e.repo.Acquire(ctx, leaderLease, e.instanceID, now, expiresAt)
+	cancel()
+
+	switch {
+	case err != nil:
+		e.metrics.LeaseErrors.Inc()
+		e.logger.Error("failed to acquire leadership lease", "error", err)
+		if e.IsLeader() && !now.Add(e.config.RenewInterval).Before(e.validUntil) {
+			e.setLeader(false)
+		}
+	case acquired:
+		e.validUntil = expiresAt
+		e.setLeader(true)
+	default:
+		e.setLeader(false)
+	}
+}
+
+// setLeader records the leadership state and notifies the listeners when it changed
+func (e *Elector) setLeader(leader bool) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+
+	e.metrics.LeadershipChanges.Inc()
+	if leader {
+		e.metrics.Leader.Set(1)
+		e.logger.Info("became the leader", "instance_id", e.instanceID)
+	} else {
+		e.metrics.Leader.Set(0)
+		e.logger.Info("gave up the leadership", "instance_id", e.instanceID)
+	}
+
+	e.mu.Lock()
+	listeners := append([]func(bool){}, e.listeners...)
+	e.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(leader)
+	}
+}
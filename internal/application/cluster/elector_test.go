@@ -0,0 +1,156 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockLeaseRepository is an in-memory repository.LeaseRepository
+type mockLeaseRepository struct {
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+	err     error
+}
+
+func (m *mockLeaseRepository) Acquire(ctx context.Context, name, holder string, now, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.holder != "" && m.holder != holder && now.Before(m.expires) {
+		return false, nil
+	}
+	m.holder, m.expires = holder, expiresAt
+	return true, nil
+}
+
+func (m *mockLeaseRepository) Release(ctx context.Context, name, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.holder == holder {
+		m.holder = ""
+	}
+	return nil
+}
+
+// newTestElector returns an elector of repo whose clock is set by the returned function
+func newTestElector(repo *mockLeaseRepository, instanceID string) (*Elector, func(time.Time)) {
+	e := NewElector(repo, logging.NewNoopLogger(), &Config{
+		InstanceID:    instanceID,
+		LeaseTTL:      15 * time.Second,
+		RenewInterval: 5 * time.Second,
+	})
+	var mu sync.Mutex
+	now := time.Now()
+	e.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	return e, func(t time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = t
+	}
+}
+
+func TestElector_SingleLeader(t *testing.T) {
+	repo := &mockLeaseRepository{}
+	first, _ := newTestElector(repo, "first")
+	second, _ := newTestElector(repo, "second")
+
+	var changes []bool
+	first.OnChange(func(leader bool) { changes = append(changes, leader) })
+
+	first.campaign()
+	second.campaign()
+
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+	assert.Equal(t, []bool{true}, changes)
+
+	// Renewing keeps the leadership without notifying again
+	first.campaign()
+	assert.True(t, first.IsLeader())
+	assert.Equal(t, []bool{true}, changes)
+	assert.Equal(t, int64(1), first.Metrics().Leader.Get())
+}
+
+func TestElector_TakesOverExpiredLease(t *testing.T) {
+	repo := &mockLeaseRepository{}
+	first, setFirstNow := newTestElector(repo, "first")
+	second, setSecondNow := newTestElector(repo, "second")
+	start := time.Now()
+
+	first.campaign()
+	require.True(t, first.IsLeader())
+
+	// Act: the leader stops renewing and its lease expires
+	setSecondNow(start.Add(20 * time.Second))
+	second.campaign()
+
+	assert.True(t, second.IsLeader())
+
+	// The former leader finds its lease taken
+	setFirstNow(start.Add(21 * time.Second))
+	first.campaign()
+	assert.False(t, first.IsLeader())
+}
+
+func TestElector_KeepsLeadershipWhileLeaseIsValid(t *testing.T) {
+	repo := &mockLeaseRepository{}
+	e, setNow := newTestElector(repo, "first")
+	start := time.Now()
+
+	e.campaign()
+	require.True(t, e.IsLeader())
+
+	// Act: the database fails; the lease is valid until start+15s
+	repo.err = errors.New("database is locked")
+	setNow(start.Add(5 * time.Second))
+	e.campaign()
+	assert.True(t, e.IsLeader(), "the lease outlasts the next campaign")
+
+	setNow(start.Add(10 * time.Second))
+	e.campaign()
+	assert.False(t, e.IsLeader(), "the lease may expire before the next campaign")
+	assert.Equal(t, int64(2), e.Metrics().LeaseErrors.Get())
+}
+
+func TestElector_StopReleasesLeadership(t *testing.T) {
+	repo := &mockLeaseRepository{}
+	e, _ := newTestElector(repo, "first")
+	var changes []bool
+	e.OnChange(func(leader bool) { changes = append(changes, leader) })
+
+	require.NoError(t, e.Start())
+	require.True(t, e.IsLeader())
+
+	require.NoError(t, e.Stop(context.Background()))
+
+	assert.False(t, e.IsLeader())
+	assert.Equal(t, []bool{true, false}, changes)
+	assert.Empty(t, repo.holder, "the lease is free for another instance")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, DefaultConfig().Validate())
+
+	config := DefaultConfig()
+	config.RenewInterval = 0
+	assert.Error(t, config.Validate())
+
+	config = DefaultConfig()
+	config.LeaseTTL = config.RenewInterval
+	assert.Error(t, config.Validate())
+}
@@ -0,0 +1,91 @@
+package documents
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Chunk splits text into chunks of at most size characters, each repeating about overlap
+// characters from the end of the previous one. Chunks end at a paragraph break, else at the
+// end of a sentence or line, else between words, as long as one is found in the second half
+// of the chunk. Whitespace is normalized; blank text has no chunks.
+func Chunk(text string, size, overlap int) []string {
+	runes := []rune(normalizeText(text))
+	if size <= 0 {
+		return nil
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			end = len(runes)
+		} else {
+			end = breakPoint(runes, start, end)
+		}
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end == len(runes) {
+			break
+		}
+
+		// Repeat the end of the chunk from the start of a word, always moving forward
+		next := end - overlap
+		for next > start && next < end && !unicode.IsSpace(runes[next-1]) {
+			next++
+		}
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+	return chunks
+}
+
+// breakPoint returns the position to end a chunk of runes[start:end] at
+func breakPoint(runes []rune, start, end int) int {
+	limit := start + (end-start)/2
+	for i := end; i > limit; i-- {
+		if runes[i-1] == '\n' && i >= 2 && runes[i-2] == '\n' {
+			return i
+		}
+	}
+	for i := end; i > limit; i-- {
+		if runes[i-1] == '\n' || (strings.ContainsRune(".!?", runes[i-1]) && unicode.IsSpace(runes[i])) {
+			return i
+		}
+	}
+	for i := end; i > limit; i-- {
+		if unicode.IsSpace(runes[i-1]) {
+			return i
+		}
+	}
+	return end
+}
+
+// normalizeText collapses the spaces within lines and the blank lines between paragraphs
+func normalizeText(text string) string {
+	var b strings.Builder
+	blank := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank = b.Len() > 0
+			continue
+		}
+		if b.Len() > 0 {
+			if blank {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteByte('\n')
+			}
+		}
+		blank = false
+		b.WriteString(line)
+	}
+	return b.String()
+}
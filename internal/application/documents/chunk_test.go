@@ -0,0 +1,54 @@
+package documents
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunk(t *testing.T) {
+	t.Run("blank text has no chunks", func(t *testing.T) {
+		assert.Empty(t, Chunk(" \n\t\n ", 100, 10))
+	})
+
+	t.Run("short text is one normalized chunk", func(t *testing.T) {
+		assert.Equal(t, []string{"One two\n\nThree"}, Chunk("  One   two \r\n\n\n\n Three ", 100, 10))
+	})
+
+	t.Run("chunks end at paragraphs and sentences", func(t *testing.T) {
+		text := "First paragraph is here.\n\nSecond one has two sentences. It goes on and on."
+		chunks := Chunk(text, 40, 0)
+		assert.Equal(t, []string{
+			"First paragraph is here.",
+			"Second one has two sentences.",
+			"It goes on and on.",
+		}, chunks)
+	})
+
+	t.Run("chunks overlap from a word boundary", func(t *testing.T) {
+		words := make([]string, 200)
+		for i := range words {
+			words[i] = "word" + strings.Repeat("x", i%5)
+		}
+		chunks := Chunk(strings.Join(words, " "), 100, 30)
+
+		assert.Greater(t, len(chunks), 1)
+		for i, chunk := range chunks {
+			assert.LessOrEqual(t, utf8.RuneCountInString(chunk), 100)
+			assert.True(t, strings.HasPrefix(chunk, "word"), "chunk %d starts mid-word: %q", i, chunk)
+			if i > 0 {
+				last := chunks[i-1][strings.LastIndex(chunks[i-1], " ")+1:]
+				assert.Contains(t, chunk, last, "chunk %d does not repeat the end of the previous one", i)
+			}
+		}
+		assert.True(t, strings.HasSuffix(chunks[len(chunks)-1], words[len(words)-1]))
+	})
+
+	t.Run("text without spaces is cut at the size", func(t *testing.T) {
+		chunks := Chunk(strings.Repeat("я", 250), 100, 20)
+		assert.Equal(t, 3, len(chunks))
+		assert.Equal(t, 100, utf8.RuneCountInString(chunks[0]))
+	})
+}
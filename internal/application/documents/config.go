@@ -0,0 +1,66 @@
+package documents
+
+import (
+	"fmt"
+)
+
+// ValidationError represents a documents configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Config holds configuration for the ingestion and retrieval of documents
+type Config struct {
+	// MaxSize is the size limit of a document in bytes
+	MaxSize int64
+
+	// ChunkSize is the length of the chunks a document is split into, in characters
+	ChunkSize int
+
+	// ChunkOverlap is the number of characters a chunk repeats from the end of the previous
+	// one, so that a sentence cut by a chunk boundary is whole in one of them
+	ChunkOverlap int
+
+	// MaxChunks is the maximum number of chunks of a document; longer documents are rejected
+	MaxChunks int
+
+	// TopK is the number of chunks passed to the LLM with a question
+	TopK int
+}
+
+// DefaultConfig returns the default configuration for documents
+func DefaultConfig() *Config {
+	return &Config{
+		MaxSize:      10 << 20,
+		ChunkSize:    1000,
+		ChunkOverlap: 150,
+		MaxChunks:    500,
+		TopK:         4,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.MaxSize <= 0 {
+		return &ValidationError{Field: "MaxSize", Message: "must be positive"}
+	}
+	if c.ChunkSize <= 0 {
+		return &ValidationError{Field: "ChunkSize", Message: "must be positive"}
+	}
+	if c.ChunkOverlap < 0 || c.ChunkOverlap >= c.ChunkSize {
+		return &ValidationError{Field: "ChunkOverlap", Message: "must be non-negative and less than ChunkSize"}
+	}
+	if c.MaxChunks <= 0 {
+		return &ValidationError{Field: "MaxChunks", Message: "must be positive"}
+	}
+	if c.TopK <= 0 {
+		return &ValidationError{Field: "TopK", Message: "must be positive"}
+	}
+	return nil
+}
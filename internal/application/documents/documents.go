@@ -0,0 +1,212 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// ingestJobKind is the kind of the jobs of the job queue storing the chunks of documents
+const ingestJobKind = "document_ingest"
+
+// ingestJobPayload is the payload of a job of the job queue storing the chunks of a document
+type ingestJobPayload struct {
+	SessionID  string   `json:"session_id"`
+	DocumentID string   `json:"document_id"`
+	Name       string   `json:"name"`
+	Chunks     []string `json:"chunks"`
+}
+
+// Service reads the documents shared in sessions into chunks with embeddings and finds the
+// chunks relevant to the questions asked about them
+type Service struct {
+	chunks   repository.DocumentChunkRepository
+	embedder Embedder
+	jobQueue ports.JobQueue
+	config   *Config
+	logger   logging.Logger
+}
+
+// Compile-time checks that Service implements the document ports
+var (
+	_ ports.DocumentIngester  = (*Service)(nil)
+	_ ports.DocumentQueuer    = (*Service)(nil)
+	_ ports.DocumentRetriever = (*Service)(nil)
+)
+
+// NewService creates a new Service instance
+//
+// Parameters:
+//   - chunks: DocumentChunkRepository storing the chunks of the documents
+//   - embedder: Embedder for the chunks and the questions
+//   - config: Documents configuration (uses defaults if nil)
+//   - logger: Structured logger for logging
+//
+// Returns:
+//   - *Service: Initialized documents service
+func NewService(chunks repository.DocumentChunkRepository, embedder Embedder, config *Config, logger logging.Logger) *Service {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	if err := config.Validate(); err != nil {
+		logger.Error("invalid documents configuration, using defaults", "error", err)
+		config = DefaultConfig()
+	}
+
+	return &Service{
+		chunks:   chunks,
+		embedder: embedder,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// SetJobQueue sets the persistent job queue the chunks of queued documents are stored from.
+// Without it, QueueDocument stores them immediately.
+func (s *Service) SetJobQueue(queue ports.JobQueue) {
+	s.jobQueue = queue
+	queue.Register(ingestJobKind, s.handleIngestJob)
+}
+
+// MaxDocumentSize returns the size limit of the documents in bytes
+func (s *Service) MaxDocumentSize() int64 {
+	return s.config.MaxSize
+}
+
+// IngestDocument extracts the text of a document, splits it into chunks and stores them with
+// their embeddings in the session
+func (s *Service) IngestDocument(ctx context.Context, sessionID string, doc ports.Document) (int, error) {
+	parts, err := s.split(doc)
+	if err != nil {
+		return 0, err
+	}
+	return s.store(ctx, sessionID, valueobject.GenerateID(nil).String(), doc.Name, parts)
+}
+
+// QueueDocument extracts the text of a document and splits it into chunks, and queues storing
+// them with their embeddings in the session on the job queue
+func (s *Service) QueueDocument(ctx context.Context, sessionID string, doc ports.Document) (int, error) {
+	if s.jobQueue == nil {
+		return s.IngestDocument(ctx, sessionID, doc)
+	}
+
+	parts, err := s.split(doc)
+	if err != nil {
+		return 0, err
+	}
+
+	payload := ingestJobPayload{SessionID: sessionID, DocumentID: valueobject.GenerateID(nil).String(), Name: doc.Name, Chunks: parts}
+	if err := s.jobQueue.Enqueue(ctx, ingestJobKind, payload, ports.JobOptions{Key: "document:" + payload.DocumentID}); err != nil {
+		return 0, fmt.Errorf("failed to queue document: %w", err)
+	}
+
+	s.logger.Info("document queued", "session_id", sessionID, "document_id", payload.DocumentID, "name", doc.Name, "chunks", len(parts))
+	return len(parts), nil
+}
+
+// handleIngestJob stores the chunks of a document queued by QueueDocument
+func (s *Service) handleIngestJob(ctx context.Context, job *entity.Job) error {
+	var payload ingestJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("%w: invalid document: %v", ports.ErrJobPermanent, err)
+	}
+
+	_, err := s.store(ctx, payload.SessionID, payload.DocumentID, payload.Name, payload.Chunks)
+	return err
+}
+
+// split extracts the text of a document and splits it into chunks within the limits
+func (s *Service) split(doc ports.Document) ([]string, error) {
+	if int64(len(doc.Data)) > s.config.MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", ports.ErrDocumentTooLarge, len(doc.Data), s.config.MaxSize)
+	}
+
+	text, err := ExtractText(doc.Name, doc.MimeType, doc.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := Chunk(text, s.config.ChunkSize, s.config.ChunkOverlap)
+	if len(parts) == 0 {
+		return nil, ports.ErrDocumentEmpty
+	}
+	if len(parts) > s.config.MaxChunks {
+		return nil, fmt.Errorf("%w: %d chunks, limit %d", ports.ErrDocumentTooLarge, len(parts), s.config.MaxChunks)
+	}
+	return parts, nil
+}
+
+// store embeds the chunks of a document and stores them in the session
+func (s *Service) store(ctx context.Context, sessionID, documentID, name string, parts []string) (int, error) {
+	embeddings, err := s.embedder.Embed(ctx, parts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed document: %w", err)
+	}
+	if len(embeddings) != len(parts) {
+		return 0, fmt.Errorf("failed to embed document: got %d embeddings for %d chunks", len(embeddings), len(parts))
+	}
+
+	chunks := make([]*entity.DocumentChunk, len(parts))
+	for i, part := range parts {
+		chunks[i] = entity.NewDocumentChunk(sessionID, documentID, name, i, part, embeddings[i])
+		chunks[i].CreatedAt = chunks[0].CreatedAt
+	}
+
+	if err := s.chunks.Create(ctx, chunks); err != nil {
+		return 0, fmt.Errorf("failed to store document: %w", err)
+	}
+
+	s.logger.Info("document ingested", "session_id", sessionID, "document_id", documentID, "name", name, "chunks", len(chunks))
+	return len(chunks), nil
+}
+
+// RetrieveDocuments returns the TopK chunks of the documents of a session most similar to a
+// query, skipping chunks without a word in common with it
+func (s *Service) RetrieveDocuments(ctx context.Context, sessionID, query string) ([]*entity.DocumentChunk, error) {
+	chunks, err := s.chunks.FindBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find document chunks: %w", err)
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	embeddings, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(embeddings) != 1 {
+		return nil, fmt.Errorf("failed to embed query: got %d embeddings", len(embeddings))
+	}
+
+	type scored struct {
+		chunk *entity.DocumentChunk
+		score float64
+	}
+	var results []scored
+	for _, chunk := range chunks {
+		if score := cosine(embeddings[0], chunk.Embedding); score > 0 {
+			results = append(results, scored{chunk: chunk, score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	if len(results) > s.config.TopK {
+		results = results[:s.config.TopK]
+	}
+	relevant := make([]*entity.DocumentChunk, len(results))
+	for i, result := range results {
+		relevant[i] = result.chunk
+	}
+	return relevant, nil
+}
@@ -0,0 +1,206 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockDocumentChunkRepository is an in-memory repository.DocumentChunkRepository
+type mockDocumentChunkRepository struct {
+	chunks []*entity.DocumentChunk
+}
+
+func (m *mockDocumentChunkRepository) Create(ctx context.Context, chunks []*entity.DocumentChunk) error {
+	m.chunks = append(m.chunks, chunks...)
+	return nil
+}
+
+func (m *mockDocumentChunkRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.DocumentChunk, error) {
+	var result []*entity.DocumentChunk
+	for _, chunk := range m.chunks {
+		if string(chunk.SessionID) == sessionID {
+			result = append(result, chunk)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockDocumentChunkRepository) DeleteBySessionID(ctx context.Context, sessionID string) (int64, error) {
+	var kept []*entity.DocumentChunk
+	for _, chunk := range m.chunks {
+		if string(chunk.SessionID) != sessionID {
+			kept = append(kept, chunk)
+		}
+	}
+	deleted := int64(len(m.chunks) - len(kept))
+	m.chunks = kept
+	return deleted, nil
+}
+
+func TestHashingEmbedder(t *testing.T) {
+	embedder := NewHashingEmbedder(0)
+
+	vectors, err := embedder.Embed(context.Background(), []string{
+		"The invoice total is due in March",
+		"When is the invoice due?",
+		"Photosynthesis converts light into chemical energy",
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 4)
+	assert.Len(t, vectors[0], DefaultEmbeddingDimensions)
+
+	assert.InDelta(t, 1, cosine(vectors[0], vectors[0]), 1e-6)
+	assert.Greater(t, cosine(vectors[0], vectors[1]), cosine(vectors[1], vectors[2]))
+	assert.Zero(t, cosine(vectors[0], vectors[3]))
+
+	// Word forms share their stem
+	forms, err := embedder.Embed(context.Background(), []string{"reports", "reported"})
+	require.NoError(t, err)
+	assert.InDelta(t, 1, cosine(forms[0], forms[1]), 1e-6)
+}
+
+func TestService_IngestAndRetrieve(t *testing.T) {
+	// Arrange
+	repo := &mockDocumentChunkRepository{}
+	config := DefaultConfig()
+	config.ChunkSize = 60
+	config.ChunkOverlap = 0
+	config.TopK = 2
+	service := NewService(repo, NewHashingEmbedder(0), config, logging.NewNoopLogger())
+
+	text := "The warranty covers the battery for two years.\n\n" +
+		"Shipping takes five business days within Europe.\n\n" +
+		"Returns are accepted within thirty days of delivery."
+
+	// Act
+	count, err := service.IngestDocument(context.Background(), "session-1", ports.Document{
+		Name: "terms.txt", MimeType: "text/plain", Data: []byte(text),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	require.Len(t, repo.chunks, 3)
+	for i, chunk := range repo.chunks {
+		assert.Equal(t, i, chunk.Position)
+		assert.Equal(t, "terms.txt", chunk.DocumentName)
+		assert.Equal(t, repo.chunks[0].DocumentID, chunk.DocumentID)
+		assert.Equal(t, repo.chunks[0].CreatedAt, chunk.CreatedAt)
+		assert.NotEmpty(t, chunk.Embedding)
+	}
+
+	chunks, err := service.RetrieveDocuments(context.Background(), "session-1", "How long does shipping take?")
+	require.NoError(t, err)
+	require.NotEmpty(t, chunks)
+	assert.LessOrEqual(t, len(chunks), 2)
+	assert.True(t, strings.HasPrefix(chunks[0].Content, "Shipping"), "got %q", chunks[0].Content)
+
+	chunks, err = service.RetrieveDocuments(context.Background(), "session-1", "quantum chromodynamics")
+	require.NoError(t, err)
+	assert.Empty(t, chunks)
+
+	chunks, err = service.RetrieveDocuments(context.Background(), "session-2", "shipping")
+	require.NoError(t, err)
+	assert.Empty(t, chunks)
+}
+
+func TestService_IngestErrors(t *testing.T) {
+	repo := &mockDocumentChunkRepository{}
+	config := DefaultConfig()
+	config.MaxSize = 100
+	config.ChunkSize = 10
+	config.ChunkOverlap = 0
+	config.MaxChunks = 3
+	service := NewService(repo, NewHashingEmbedder(0), config, logging.NewNoopLogger())
+
+	tests := []struct {
+		name    string
+		doc     ports.Document
+		wantErr error
+	}{
+		{name: "too large", doc: ports.Document{Name: "a.txt", Data: []byte(strings.Repeat("a", 101))}, wantErr: ports.ErrDocumentTooLarge},
+		{name: "too many chunks", doc: ports.Document{Name: "a.txt", Data: []byte(strings.Repeat("word ", 15))}, wantErr: ports.ErrDocumentTooLarge},
+		{name: "empty", doc: ports.Document{Name: "a.txt", Data: []byte(" \n ")}, wantErr: ports.ErrDocumentEmpty},
+		{name: "unsupported", doc: ports.Document{Name: "a.png", MimeType: "image/png", Data: []byte("\x89PNG")}, wantErr: ports.ErrUnsupportedDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.IngestDocument(context.Background(), "session-1", tt.doc)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+	assert.Empty(t, repo.chunks)
+	assert.Equal(t, int64(100), service.MaxDocumentSize())
+}
+
+// mockJobQueue keeps the queued jobs until they are run by the test
+type mockJobQueue struct {
+	handler ports.JobHandler
+	jobs    []*entity.Job
+}
+
+func (q *mockJobQueue) Register(kind string, handler ports.JobHandler) {
+	q.handler = handler
+}
+
+func (q *mockJobQueue) Enqueue(ctx context.Context, kind string, payload any, opts ports.JobOptions) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	q.jobs = append(q.jobs, entity.NewJob(kind, opts.Key, string(data), opts.MaxAttempts))
+	return nil
+}
+
+func TestService_QueueDocument(t *testing.T) {
+	repo := &mockDocumentChunkRepository{}
+	config := DefaultConfig()
+	config.ChunkSize = 35
+	config.ChunkOverlap = 0
+	service := NewService(repo, NewHashingEmbedder(0), config, logging.NewNoopLogger())
+	doc := ports.Document{Name: "terms.txt", Data: []byte("The warranty covers the battery.\n\nShipping takes five days.")}
+
+	// Without a job queue the chunks are stored immediately
+	count, err := service.QueueDocument(context.Background(), "session-1", doc)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, repo.chunks, 2)
+
+	queue := &mockJobQueue{}
+	service.SetJobQueue(queue)
+
+	// Invalid documents are rejected before they are queued
+	_, err = service.QueueDocument(context.Background(), "session-2", ports.Document{Name: "a.txt", Data: []byte(" ")})
+	assert.ErrorIs(t, err, ports.ErrDocumentEmpty)
+	assert.Empty(t, queue.jobs)
+
+	count, err = service.QueueDocument(context.Background(), "session-2", doc)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, queue.jobs, 1)
+	assert.Len(t, repo.chunks, 2)
+
+	require.NoError(t, queue.handler(context.Background(), queue.jobs[0]))
+	chunks, err := repo.FindBySessionID(context.Background(), "session-2")
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "terms.txt", chunks[0].DocumentName)
+	assert.Equal(t, "document:"+chunks[0].DocumentID, queue.jobs[0].ID)
+}
+
+func TestNewService_InvalidConfigUsesDefaults(t *testing.T) {
+	service := NewService(&mockDocumentChunkRepository{}, NewHashingEmbedder(0), &Config{}, logging.NewNoopLogger())
+
+	assert.Equal(t, DefaultConfig(), service.config)
+}
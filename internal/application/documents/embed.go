@@ -0,0 +1,128 @@
+package documents
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// DefaultEmbeddingDimensions is the number of dimensions of the vectors of the default embedder
+const DefaultEmbeddingDimensions = 1024
+
+// stemLength is the number of leading characters words are compared by, so that the forms of
+// a word ("report", "reports", "reported") share a dimension
+const stemLength = 6
+
+// Embedder turns texts into vectors whose cosine similarity reflects how related the texts are
+type Embedder interface {
+	// Embed returns one vector per text, in the order of the texts
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// HashingEmbedder is an Embedder that hashes the words of a text into a fixed number of
+// dimensions. It runs locally without a model: texts are related when they share words, not
+// when they only share meaning, which is enough to find the parts of a document a question
+// refers to.
+type HashingEmbedder struct {
+	dimensions int
+}
+
+// NewHashingEmbedder creates a HashingEmbedder with vectors of the given number of dimensions,
+// or DefaultEmbeddingDimensions if it is not positive
+func NewHashingEmbedder(dimensions int) *HashingEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultEmbeddingDimensions
+	}
+	return &HashingEmbedder{dimensions: dimensions}
+}
+
+// Compile-time check that HashingEmbedder implements Embedder
+var _ Embedder = (*HashingEmbedder)(nil)
+
+// Embed returns the normalized word count vectors of the texts
+func (e *HashingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed returns the vector of a text, weighting each word by the logarithm of its count
+func (e *HashingEmbedder) embed(text string) []float32 {
+	counts := make(map[string]int)
+	for _, word := range tokenize(text) {
+		counts[word]++
+	}
+
+	vector := make([]float32, e.dimensions)
+	for word, count := range counts {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(word))
+		sum := h.Sum64()
+
+		// Each word is added to two dimensions, so that a collision with another word in one of
+		// them does not make the texts look related; the signs spread collisions around zero
+		weight := float32(1 + math.Log(float64(count)))
+		for _, half := range []uint32{uint32(sum), uint32(sum >> 32)} {
+			w := weight
+			if half&(1<<31) != 0 {
+				w = -w
+			}
+			vector[int(half%uint32(e.dimensions))] += w
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+	return vector
+}
+
+// tokenize returns the lowercase words of a text cut to their stem, skipping one-letter words
+func tokenize(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		if len(runes) < 2 {
+			continue
+		}
+		if len(runes) > stemLength {
+			runes = runes[:stemLength]
+		}
+		words = append(words, string(runes))
+	}
+	return words
+}
+
+// cosine returns the cosine similarity of two vectors, or 0 for vectors of different or zero
+// length
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,60 @@
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+)
+
+// textExtensions are the file extensions of documents read as plain text
+var textExtensions = map[string]bool{
+	".txt": true, ".text": true, ".md": true, ".markdown": true, ".rst": true,
+	".csv": true, ".tsv": true, ".json": true, ".xml": true, ".yaml": true, ".yml": true,
+	".log": true, ".ini": true, ".toml": true,
+}
+
+// textMimeTypes are the MIME types outside text/* of documents read as plain text
+var textMimeTypes = map[string]bool{
+	"application/json":   true,
+	"application/xml":    true,
+	"application/x-yaml": true,
+	"application/yaml":   true,
+	"application/csv":    true,
+}
+
+// ExtractText returns the text of a PDF or plain text document. The type is taken from the
+// MIME type, the file name extension and the content, in this order of precedence for PDFs.
+// Plain text is expected in UTF-8; invalid sequences are dropped. Other documents, such as
+// images, fail with ports.ErrUnsupportedDocument.
+func ExtractText(name, mimeType string, data []byte) (string, error) {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	ext := strings.ToLower(filepath.Ext(name))
+
+	switch {
+	case mimeType == "application/pdf" || ext == ".pdf" || bytes.HasPrefix(data, []byte("%PDF-")):
+		text, err := extractPDF(data)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ports.ErrUnsupportedDocument, err)
+		}
+		return text, nil
+	case strings.HasPrefix(mimeType, "text/") || textMimeTypes[mimeType] || textExtensions[ext],
+		(mimeType == "" || mimeType == "application/octet-stream") && strings.HasPrefix(http.DetectContentType(data), "text/plain"):
+		return strings.ToValidUTF8(string(data), ""), nil
+	}
+	return "", fmt.Errorf("%w: %s", ports.ErrUnsupportedDocument, describeType(name, mimeType))
+}
+
+// describeType names the type of a document in errors
+func describeType(name, mimeType string) string {
+	if mimeType != "" {
+		return mimeType
+	}
+	if ext := filepath.Ext(name); ext != "" {
+		return ext
+	}
+	return "unknown type"
+}
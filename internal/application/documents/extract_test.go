@@ -0,0 +1,138 @@
+package documents
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+)
+
+// buildPDF returns a PDF file of the given objects, numbered from 1. Objects given as
+// [2]string are a dictionary and the content of its stream, compressed with FlateDecode.
+func buildPDF(t *testing.T, objects ...any) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		switch obj := obj.(type) {
+		case string:
+			b.WriteString(obj)
+		case [2]string:
+			var data bytes.Buffer
+			zw := zlib.NewWriter(&data)
+			_, err := zw.Write([]byte(obj[1]))
+			require.NoError(t, err)
+			require.NoError(t, zw.Close())
+			fmt.Fprintf(&b, "<< %s /Length %d /Filter /FlateDecode >>\nstream\n", obj[0], data.Len())
+			b.Write(data.Bytes())
+			b.WriteString("\nendstream")
+		}
+		b.WriteString("\nendobj\n")
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func TestExtractText_PDF(t *testing.T) {
+	// Pages are listed in the page tree in another order than their objects
+	data := buildPDF(t,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R 3 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 7 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 7 0 R >> >> /Contents [6 0 R] >>",
+		[2]string{"", "BT /F1 12 Tf 72 712 Td (Second page) Tj ET"},
+		[2]string{"", "BT /F1 12 Tf 72 712 Td (Quarterly \\(Q3\\) report) Tj 0 -14 Td [(Revenue) -250 (gr) 20 (ew) -300 (by 12%)] TJ T* (Caf\\351 \\223quoted\\224) Tj ET"},
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+
+	text, err := ExtractText("report.pdf", "application/pdf", data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Quarterly (Q3) report\nRevenue grew by 12%\nCafé “quoted”\n\nSecond page", strings.TrimSpace(text))
+}
+
+func TestExtractText_PDFToUnicode(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0001> <041F>
+<0002> <0440>
+endbfchar
+1 beginbfrange
+<0003> <0005> <0438>
+endbfrange
+endcmap`
+
+	data := buildPDF(t,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources 6 0 R /Contents 4 0 R >>",
+		[2]string{"", "BT /C1 10 Tf 1 0 0 1 72 700 Tm <000100020003000400050002> Tj ET"},
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Arial /Encoding /Identity-H /ToUnicode 7 0 R >>",
+		"<< /Font 8 0 R >>",
+		[2]string{"", cmap},
+		"<< /C1 5 0 R >>",
+	)
+
+	text, err := ExtractText("letter.pdf", "", data)
+	require.NoError(t, err)
+
+	// <0003>-<0005> map to и, й, к
+	assert.Equal(t, "Прийкр", strings.TrimSpace(text))
+}
+
+func TestExtractText_PDFWithoutText(t *testing.T) {
+	data := buildPDF(t,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		[2]string{"", "q 612 0 0 792 0 0 cm /Im1 Do Q"},
+	)
+
+	text, err := ExtractText("scan.pdf", "application/pdf", data)
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(text))
+
+	_, err = ExtractText("broken.pdf", "application/pdf", []byte("not a pdf"))
+	assert.ErrorIs(t, err, ports.ErrUnsupportedDocument)
+}
+
+func TestExtractText_PlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		mimeType string
+		data     string
+		want     string
+		wantErr  error
+	}{
+		{name: "text mime type", fileName: "notes", mimeType: "text/plain; charset=utf-8", data: "hello", want: "hello"},
+		{name: "markdown extension", fileName: "README.md", mimeType: "application/octet-stream", data: "# Title", want: "# Title"},
+		{name: "json mime type", fileName: "data", mimeType: "application/json", data: `{"a":1}`, want: `{"a":1}`},
+		{name: "detected text", fileName: "data", mimeType: "", data: "plain words", want: "plain words"},
+		{name: "invalid utf-8 dropped", fileName: "a.txt", mimeType: "", data: "ok\xff!", want: "ok!"},
+		{name: "image", fileName: "photo.jpg", mimeType: "image/jpeg", data: "\xff\xd8\xff\xe0", wantErr: ports.ErrUnsupportedDocument},
+		{name: "binary", fileName: "blob", mimeType: "", data: "\x00\x01\x02\x03", wantErr: ports.ErrUnsupportedDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := ExtractText(tt.fileName, tt.mimeType, []byte(tt.data))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, text)
+		})
+	}
+}
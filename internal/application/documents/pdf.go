@@ -0,0 +1,768 @@
+package documents
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxPDFStreamSize limits the decompressed size of a PDF stream, against compression bombs
+const maxPDFStreamSize = 32 << 20
+
+// pdfSpaceKerning is the TJ adjustment, in thousandths of the font size, from which a gap
+// between two strings is taken as a space between words
+const pdfSpaceKerning = -200
+
+var (
+	pdfObjectPattern        = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfRefPattern           = regexp.MustCompile(`(\d+)\s+\d+\s+R\b`)
+	pdfTypePattern          = regexp.MustCompile(`/Type\s*/(\w+)`)
+	pdfKidsPattern          = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	pdfContentsPattern      = regexp.MustCompile(`/Contents\s*(\[[^\]]*\]|\d+\s+\d+\s+R\b)`)
+	pdfFontDictPattern      = regexp.MustCompile(`/Font\s*<<((?:[^<>]|<<[^<>]*>>)*)>>`)
+	pdfFontRefPattern       = regexp.MustCompile(`/Font\s+(\d+)\s+\d+\s+R\b`)
+	pdfFontEntryPattern     = regexp.MustCompile(`/([^\s/<>\[\]()]+)\s+(\d+)\s+\d+\s+R\b`)
+	pdfToUnicodePattern     = regexp.MustCompile(`/ToUnicode\s+(\d+)\s+\d+\s+R\b`)
+	pdfCountPattern         = regexp.MustCompile(`/N\s+(\d+)`)
+	pdfFirstPattern         = regexp.MustCompile(`/First\s+(\d+)`)
+	pdfUnsupportedFilter    = regexp.MustCompile(`/(DCTDecode|JPXDecode|CCITTFaxDecode|JBIG2Decode|LZWDecode|RunLengthDecode|ASCII85Decode|ASCIIHexDecode)\b`)
+	pdfCodespacePattern     = regexp.MustCompile(`begincodespacerange\s*<([0-9A-Fa-f]+)>`)
+	pdfBfcharSectionPattern = regexp.MustCompile(`(?s)beginbfchar(.*?)endbfchar`)
+	pdfBfcharPattern        = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]*)>`)
+	pdfBfrangeSection       = regexp.MustCompile(`(?s)beginbfrange(.*?)endbfrange`)
+	pdfBfrangePattern       = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>\s*(<[0-9A-Fa-f]*>|\[[^\]]*\])`)
+	pdfHexPattern           = regexp.MustCompile(`<([0-9A-Fa-f]*)>`)
+)
+
+// pdfObject is an indirect object of a PDF file
+type pdfObject struct {
+	dict   string // Text of the object before its stream
+	stream []byte // Decoded stream; nil without a stream or with an unsupported filter
+}
+
+// pdfFile holds the objects of a PDF file by object number
+type pdfFile struct {
+	objects map[int]*pdfObject
+}
+
+// extractPDF returns the text of the pages of a PDF file. It reads the text operators of the
+// page contents, decoding the strings with the ToUnicode maps of their fonts or else as
+// single-byte Latin text. Text drawn as images, as in scanned documents, is not found.
+func extractPDF(data []byte) (string, error) {
+	pdf := parsePDF(data)
+	if len(pdf.objects) == 0 {
+		return "", errors.New("no PDF objects found")
+	}
+
+	fonts := pdf.fonts()
+	var b strings.Builder
+	for _, content := range pdf.contents() {
+		w := &pdfTextWriter{b: &b}
+		w.writeContent(content, fonts)
+		b.WriteString("\n\n")
+	}
+	return b.String(), nil
+}
+
+// parsePDF reads the objects of a PDF file, including those compressed in object streams.
+// Objects of incremental updates replace the earlier ones.
+func parsePDF(data []byte) *pdfFile {
+	pdf := &pdfFile{objects: make(map[int]*pdfObject)}
+	text := string(data)
+
+	for pos := 0; ; {
+		loc := pdfObjectPattern.FindStringSubmatchIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(text[pos+loc[2] : pos+loc[3]])
+		obj, end := parsePDFObject(text, pos+loc[1])
+		pdf.objects[num] = obj
+		pos = end
+	}
+
+	for _, num := range pdf.objectNumbers() {
+		if obj := pdf.objects[num]; pdfType(obj.dict) == "ObjStm" {
+			pdf.addObjectStream(obj)
+		}
+	}
+	return pdf
+}
+
+// parsePDFObject reads the object starting at start, after its "obj" keyword, and returns it
+// with the position after it
+func parsePDFObject(text string, start int) (*pdfObject, int) {
+	streamAt := strings.Index(text[start:], "stream")
+	endAt := strings.Index(text[start:], "endobj")
+	if streamAt < 0 || (endAt >= 0 && endAt < streamAt) {
+		if endAt < 0 {
+			return &pdfObject{dict: text[start:]}, len(text)
+		}
+		return &pdfObject{dict: text[start : start+endAt]}, start + endAt + len("endobj")
+	}
+
+	dict := text[start : start+streamAt]
+	dataStart := start + streamAt + len("stream")
+	if strings.HasPrefix(text[dataStart:], "\r\n") {
+		dataStart += 2
+	} else if strings.HasPrefix(text[dataStart:], "\n") {
+		dataStart++
+	}
+	dataEnd := strings.Index(text[dataStart:], "endstream")
+	if dataEnd < 0 {
+		return &pdfObject{dict: dict, stream: decodePDFStream(dict, []byte(text[dataStart:]))}, len(text)
+	}
+	raw := strings.TrimSuffix(strings.TrimSuffix(text[dataStart:dataStart+dataEnd], "\n"), "\r")
+	return &pdfObject{dict: dict, stream: decodePDFStream(dict, []byte(raw))}, dataStart + dataEnd + len("endstream")
+}
+
+// decodePDFStream decodes the data of a stream compressed without a filter or with
+// FlateDecode. A truncated stream returns the data decoded before the error.
+func decodePDFStream(dict string, raw []byte) []byte {
+	if !strings.Contains(dict, "/Filter") {
+		return raw
+	}
+	if !strings.Contains(dict, "/FlateDecode") || pdfUnsupportedFilter.MatchString(dict) {
+		return nil
+	}
+
+	var r io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(raw)); err == nil {
+		r = zr
+	} else {
+		r = flate.NewReader(bytes.NewReader(raw))
+	}
+	out, _ := io.ReadAll(io.LimitReader(r, maxPDFStreamSize))
+	return out
+}
+
+// addObjectStream adds the objects compressed in an object stream, unless the file defines
+// them as well
+func (p *pdfFile) addObjectStream(obj *pdfObject) {
+	count, first := pdfInt(obj.dict, pdfCountPattern), pdfInt(obj.dict, pdfFirstPattern)
+	if obj.stream == nil || first <= 0 || first > len(obj.stream) {
+		return
+	}
+
+	header := strings.Fields(string(obj.stream[:first]))
+	for i := 0; i+1 < len(header) && i/2 < count; i += 2 {
+		num, err1 := strconv.Atoi(header[i])
+		offset, err2 := strconv.Atoi(header[i+1])
+		if err1 != nil || err2 != nil {
+			return
+		}
+		start, end := first+offset, len(obj.stream)
+		if i+3 < len(header) {
+			if next, err := strconv.Atoi(header[i+3]); err == nil {
+				end = first + next
+			}
+		}
+		if start < first || start > end || end > len(obj.stream) {
+			return
+		}
+		if _, ok := p.objects[num]; !ok {
+			p.objects[num] = &pdfObject{dict: string(obj.stream[start:end])}
+		}
+	}
+}
+
+// objectNumbers returns the numbers of the objects in ascending order
+func (p *pdfFile) objectNumbers() []int {
+	nums := make([]int, 0, len(p.objects))
+	for num := range p.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// object returns the object with a number given as text, or nil
+func (p *pdfFile) object(num string) *pdfObject {
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return nil
+	}
+	return p.objects[n]
+}
+
+// fonts returns the ToUnicode maps of the fonts of the document by resource name; fonts
+// without one map to nil. Names are collected from all pages, the first font of a name wins.
+func (p *pdfFile) fonts() map[string]*pdfCMap {
+	fonts := make(map[string]*pdfCMap)
+	for _, num := range p.objectNumbers() {
+		dict := p.objects[num].dict
+
+		var entries []string
+		for _, m := range pdfFontDictPattern.FindAllStringSubmatch(dict, -1) {
+			entries = append(entries, m[1])
+		}
+		for _, m := range pdfFontRefPattern.FindAllStringSubmatch(dict, -1) {
+			if obj := p.object(m[1]); obj != nil {
+				entries = append(entries, obj.dict)
+			}
+		}
+
+		for _, entry := range entries {
+			for _, m := range pdfFontEntryPattern.FindAllStringSubmatch(entry, -1) {
+				if _, ok := fonts[m[1]]; ok {
+					continue
+				}
+				fonts[m[1]] = p.toUnicode(p.object(m[2]))
+			}
+		}
+	}
+	return fonts
+}
+
+// toUnicode returns the ToUnicode map of a font, or nil if it has none
+func (p *pdfFile) toUnicode(font *pdfObject) *pdfCMap {
+	if font == nil {
+		return nil
+	}
+	m := pdfToUnicodePattern.FindStringSubmatch(font.dict)
+	if m == nil {
+		return nil
+	}
+	if obj := p.object(m[1]); obj != nil && obj.stream != nil {
+		return parsePDFCMap(string(obj.stream))
+	}
+	return nil
+}
+
+// contents returns the decoded content streams of the pages in page order. Without a page
+// tree, the streams with text operators are returned in object order.
+func (p *pdfFile) contents() [][]byte {
+	var pages []int
+	visited := make(map[int]bool)
+	var walk func(num int)
+	walk = func(num int) {
+		obj := p.objects[num]
+		if obj == nil || visited[num] {
+			return
+		}
+		visited[num] = true
+
+		switch pdfType(obj.dict) {
+		case "Pages":
+			if m := pdfKidsPattern.FindStringSubmatch(obj.dict); m != nil {
+				for _, ref := range pdfRefPattern.FindAllStringSubmatch(m[1], -1) {
+					n, _ := strconv.Atoi(ref[1])
+					walk(n)
+				}
+			}
+		case "Page":
+			pages = append(pages, num)
+		}
+	}
+	for _, num := range p.objectNumbers() {
+		if dict := p.objects[num].dict; pdfType(dict) == "Pages" && !strings.Contains(dict, "/Parent") {
+			walk(num)
+		}
+	}
+
+	var contents [][]byte
+	if len(pages) == 0 {
+		for _, num := range p.objectNumbers() {
+			if stream := p.objects[num].stream; bytes.Contains(stream, []byte("BT")) {
+				contents = append(contents, stream)
+			}
+		}
+		return contents
+	}
+
+	for _, num := range pages {
+		m := pdfContentsPattern.FindStringSubmatch(p.objects[num].dict)
+		if m == nil {
+			continue
+		}
+		refs := pdfRefPattern.FindAllStringSubmatch(m[1], -1)
+		// The contents may refer to an array of streams
+		if len(refs) == 1 {
+			if obj := p.object(refs[0][1]); obj != nil && obj.stream == nil {
+				refs = pdfRefPattern.FindAllStringSubmatch(obj.dict, -1)
+			}
+		}
+
+		var page []byte
+		for _, ref := range refs {
+			if obj := p.object(ref[1]); obj != nil && obj.stream != nil {
+				page = append(append(page, obj.stream...), '\n')
+			}
+		}
+		contents = append(contents, page)
+	}
+	return contents
+}
+
+// pdfType returns the /Type of a dictionary, or an empty string
+func pdfType(dict string) string {
+	if m := pdfTypePattern.FindStringSubmatch(dict); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// pdfInt returns the integer value of a dictionary entry matched by pattern, or 0
+func pdfInt(dict string, pattern *regexp.Regexp) int {
+	if m := pattern.FindStringSubmatch(dict); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
+
+// pdfCMap maps the character codes of a font to text
+type pdfCMap struct {
+	codeLength int // Bytes per character code
+	chars      map[uint32]string
+}
+
+// parsePDFCMap reads the bfchar and bfrange mappings of a ToUnicode CMap
+func parsePDFCMap(text string) *pdfCMap {
+	cmap := &pdfCMap{codeLength: 1, chars: make(map[uint32]string)}
+	if m := pdfCodespacePattern.FindStringSubmatch(text); m != nil && len(m[1]) >= 4 {
+		cmap.codeLength = len(m[1]) / 2
+	}
+
+	for _, section := range pdfBfcharSectionPattern.FindAllStringSubmatch(text, -1) {
+		for _, m := range pdfBfcharPattern.FindAllStringSubmatch(section[1], -1) {
+			cmap.chars[hexCode(m[1])] = utf16Hex(m[2])
+		}
+	}
+	for _, section := range pdfBfrangeSection.FindAllStringSubmatch(text, -1) {
+		for _, m := range pdfBfrangePattern.FindAllStringSubmatch(section[1], -1) {
+			lo, hi := hexCode(m[1]), hexCode(m[2])
+			if hi < lo || hi-lo > 0xFFFF {
+				continue
+			}
+			if strings.HasPrefix(m[3], "[") {
+				for i, dst := range pdfHexPattern.FindAllStringSubmatch(m[3], -1) {
+					if code := lo + uint32(i); code <= hi {
+						cmap.chars[code] = utf16Hex(dst[1])
+					}
+				}
+				continue
+			}
+			base := []rune(utf16Hex(strings.Trim(m[3], "<>")))
+			if len(base) == 0 {
+				continue
+			}
+			for code := lo; code <= hi; code++ {
+				runes := append([]rune(nil), base...)
+				runes[len(runes)-1] += rune(code - lo)
+				cmap.chars[code] = string(runes)
+			}
+		}
+	}
+	return cmap
+}
+
+// decode returns the text of a string drawn with the font of the map. Unmapped single-byte
+// codes are decoded as Latin text, unmapped multi-byte codes are dropped.
+func (c *pdfCMap) decode(s []byte) string {
+	var b strings.Builder
+	for i := 0; i+c.codeLength <= len(s); i += c.codeLength {
+		var code uint32
+		for _, x := range s[i : i+c.codeLength] {
+			code = code<<8 | uint32(x)
+		}
+		if text, ok := c.chars[code]; ok {
+			b.WriteString(text)
+		} else if c.codeLength == 1 {
+			b.WriteString(decodeLatin(s[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// hexCode returns the value of a hexadecimal character code
+func hexCode(hex string) uint32 {
+	n, _ := strconv.ParseUint(hex, 16, 32)
+	return uint32(n)
+}
+
+// utf16Hex decodes text given as hexadecimal UTF-16BE
+func utf16Hex(hex string) string {
+	data := decodeHex(hex)
+	if len(data) == 1 {
+		return string(rune(data[0]))
+	}
+	return decodeUTF16(data)
+}
+
+// decodeHex decodes hexadecimal digits, padding an odd number of digits with 0
+func decodeHex(hex string) []byte {
+	if len(hex)%2 == 1 {
+		hex += "0"
+	}
+	data := make([]byte, 0, len(hex)/2)
+	for i := 0; i+1 < len(hex); i += 2 {
+		n, err := strconv.ParseUint(hex[i:i+2], 16, 8)
+		if err != nil {
+			break
+		}
+		data = append(data, byte(n))
+	}
+	return data
+}
+
+// decodeUTF16 decodes UTF-16BE text
+func decodeUTF16(data []byte) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// winAnsiChars are the characters of the WinAnsi encoding that differ from Latin-1
+var winAnsiChars = map[byte]rune{
+	0x80: '€', 0x85: '…', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x99: '™',
+}
+
+// decodeLatin decodes a string of a simple font as WinAnsi text, dropping control characters
+func decodeLatin(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch r, ok := winAnsiChars[c]; {
+		case ok:
+			b.WriteRune(r)
+		case c == '\t':
+			b.WriteByte(' ')
+		case c >= 0x20 && c != 0x7F && (c < 0x80 || c >= 0xA0):
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// decodePDFString decodes a string drawn with a font
+func decodePDFString(s []byte, font *pdfCMap) string {
+	if font != nil && len(font.chars) > 0 {
+		return font.decode(s)
+	}
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		return decodeUTF16(s[2:])
+	}
+	return decodeLatin(s)
+}
+
+// pdfTextWriter writes the text of content streams, collapsing the whitespace between the
+// strings they draw
+type pdfTextWriter struct {
+	b       *strings.Builder
+	pending string // Whitespace to write before the next text
+	written bool   // Whether any text was written
+}
+
+// text writes a drawn string
+func (w *pdfTextWriter) text(s string) {
+	if s == "" {
+		return
+	}
+	if w.pending != "" && w.written {
+		w.b.WriteString(w.pending)
+	}
+	w.pending = ""
+	w.written = true
+	w.b.WriteString(s)
+}
+
+// space separates the next text with a space
+func (w *pdfTextWriter) space() {
+	if w.pending == "" {
+		w.pending = " "
+	}
+}
+
+// newline puts the next text on a new line
+func (w *pdfTextWriter) newline() {
+	w.pending = "\n"
+}
+
+// pdfOperand is an operand of a content stream operator
+type pdfOperand struct {
+	str   []byte // String value
+	isStr bool
+	num   float64
+	name  string
+	array []pdfOperand
+}
+
+// writeContent writes the text drawn by the text operators of a content stream
+func (w *pdfTextWriter) writeContent(content []byte, fonts map[string]*pdfCMap) {
+	lx := &pdfLexer{data: content}
+	var operands []pdfOperand
+	var arrays [][]pdfOperand
+	var font *pdfCMap
+	lastY, hasY := 0.0, false
+
+	push := func(op pdfOperand) {
+		if len(arrays) > 0 {
+			arrays[len(arrays)-1] = append(arrays[len(arrays)-1], op)
+		} else {
+			operands = append(operands, op)
+		}
+	}
+	lastString := func() []byte {
+		for i := len(operands) - 1; i >= 0; i-- {
+			if operands[i].isStr {
+				return operands[i].str
+			}
+		}
+		return nil
+	}
+
+	for {
+		tok, ok := lx.next()
+		if !ok {
+			return
+		}
+		switch tok.kind {
+		case pdfTokenString:
+			push(pdfOperand{str: tok.value, isStr: true})
+		case pdfTokenNumber:
+			push(pdfOperand{num: tok.num})
+		case pdfTokenName:
+			push(pdfOperand{name: string(tok.value)})
+		case pdfTokenArrayStart:
+			arrays = append(arrays, nil)
+		case pdfTokenArrayEnd:
+			if len(arrays) > 0 {
+				array := arrays[len(arrays)-1]
+				arrays = arrays[:len(arrays)-1]
+				push(pdfOperand{array: array})
+			}
+		case pdfTokenOperator:
+			switch op := string(tok.value); op {
+			case "Tf":
+				if len(operands) >= 2 {
+					font = fonts[operands[len(operands)-2].name]
+				}
+			case "Tj":
+				w.text(decodePDFString(lastString(), font))
+			case "'", "\"":
+				w.newline()
+				w.text(decodePDFString(lastString(), font))
+			case "TJ":
+				if len(operands) > 0 {
+					for _, el := range operands[len(operands)-1].array {
+						if el.isStr {
+							w.text(decodePDFString(el.str, font))
+						} else if el.num < pdfSpaceKerning {
+							w.space()
+						}
+					}
+				}
+			case "Td", "TD":
+				if len(operands) >= 2 && operands[len(operands)-1].num != 0 {
+					w.newline()
+				} else {
+					w.space()
+				}
+			case "Tm":
+				if len(operands) >= 6 {
+					if y := operands[5].num; !hasY || y != lastY {
+						w.newline()
+						lastY, hasY = y, true
+					} else {
+						w.space()
+					}
+				}
+			case "T*", "ET":
+				w.newline()
+			case "BI":
+				lx.skipInlineImage()
+			}
+			operands = operands[:0]
+			arrays = arrays[:0]
+		}
+	}
+}
+
+// Kinds of the tokens of a content stream
+const (
+	pdfTokenOperator = iota
+	pdfTokenNumber
+	pdfTokenString
+	pdfTokenName
+	pdfTokenArrayStart
+	pdfTokenArrayEnd
+	pdfTokenDelimiter
+)
+
+// pdfToken is a token of a content stream
+type pdfToken struct {
+	kind  int
+	value []byte // Bytes of a string, name or operator
+	num   float64
+}
+
+// pdfLexer splits a content stream into tokens
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+// isPDFSpace reports whether c is a PDF whitespace character
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+// isPDFDelimiter reports whether c is a PDF delimiter character
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// next returns the next token, or false at the end of the stream
+func (l *pdfLexer) next() (pdfToken, bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFSpace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		break
+	}
+	if l.pos >= len(l.data) {
+		return pdfToken{}, false
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return pdfToken{kind: pdfTokenString, value: l.literalString()}, true
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<',
+		c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfToken{kind: pdfTokenDelimiter}, true
+	case c == '<':
+		return pdfToken{kind: pdfTokenString, value: l.hexString()}, true
+	case c == '[':
+		l.pos++
+		return pdfToken{kind: pdfTokenArrayStart}, true
+	case c == ']':
+		l.pos++
+		return pdfToken{kind: pdfTokenArrayEnd}, true
+	case c == '/':
+		l.pos++
+		return pdfToken{kind: pdfTokenName, value: l.word()}, true
+	case isPDFDelimiter(c):
+		l.pos++
+		return pdfToken{kind: pdfTokenDelimiter}, true
+	}
+
+	word := l.word()
+	if c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9') {
+		if n, err := strconv.ParseFloat(string(word), 64); err == nil {
+			return pdfToken{kind: pdfTokenNumber, num: n}, true
+		}
+	}
+	return pdfToken{kind: pdfTokenOperator, value: word}, true
+}
+
+// word reads regular characters up to the next whitespace or delimiter
+func (l *pdfLexer) word() []byte {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return l.data[start:l.pos]
+}
+
+// literalString reads a string in parentheses, resolving its escape sequences
+func (l *pdfLexer) literalString() []byte {
+	var s []byte
+	depth := 0
+	for l.pos++; l.pos < len(l.data); l.pos++ {
+		c := l.data[l.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				l.pos++
+				return s
+			}
+			depth--
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.data) {
+				return s
+			}
+			c = l.data[l.pos]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A line continuation
+				if c == '\r' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					n := 0
+					for i := 0; i < 3 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						n = n*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					l.pos--
+					c = byte(n)
+				}
+			}
+		}
+		s = append(s, c)
+	}
+	return s
+}
+
+// hexString reads a string of hexadecimal digits in angle brackets
+func (l *pdfLexer) hexString() []byte {
+	var hex []byte
+	for l.pos++; l.pos < len(l.data) && l.data[l.pos] != '>'; l.pos++ {
+		if c := l.data[l.pos]; (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			hex = append(hex, c)
+		}
+	}
+	l.pos++
+	return decodeHex(string(hex))
+}
+
+// skipInlineImage skips the data of an inline image, up to its EI operator
+func (l *pdfLexer) skipInlineImage() {
+	start := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if start < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	for i := l.pos + start + 2; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && isPDFSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isPDFSpace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
@@ -0,0 +1,148 @@
+package dto
+
+import "time"
+
+// ConnectorDTO represents the runtime state of a channel connector
+type ConnectorDTO struct {
+	Name             string `json:"name"`              // Connector name (telegram, discord, web, etc.)
+	Running          bool   `json:"running"`           // Whether the connector is running
+	Pending          int    `json:"pending"`           // Messages waiting to be picked up by the router
+	MessagesReceived int64  `json:"messages_received"` // Messages received since startup
+}
+
+// ConnectorResponse represents a single connector response
+type ConnectorResponse struct {
+	Success   bool          `json:"success"`
+	Connector *ConnectorDTO `json:"connector,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// ConnectorsResponse represents a list of connectors response
+type ConnectorsResponse struct {
+	Success    bool            `json:"success"`
+	Connectors []*ConnectorDTO `json:"connectors,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// RouterStatsDTO represents the message throughput of the message router
+type RouterStatsDTO struct {
+	MessagesReceived  int64 `json:"messages_received"`  // Messages received from all connectors
+	MessagesProcessed int64 `json:"messages_processed"` // Messages processed successfully
+	MessagesFailed    int64 `json:"messages_failed"`    // Messages that failed processing
+	ValidationFailed  int64 `json:"validation_failed"`  // Messages rejected by validation
+	InFlight          int64 `json:"in_flight"`          // Messages being processed right now
+	Queued            int64 `json:"queued"`             // Messages waiting for a router worker
+	Abandoned         int64 `json:"abandoned"`          // Messages dropped unprocessed when the router stopped
+	QueueDepth        int   `json:"queue_depth"`        // Messages waiting in connector queues
+	Connectors        int   `json:"connectors"`         // Number of registered connectors
+}
+
+// RouterStatsResponse represents a router statistics response
+type RouterStatsResponse struct {
+	Success bool            `json:"success"`
+	Stats   *RouterStatsDTO `json:"stats,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// LLMProviderDTO represents a configured LLM provider
+type LLMProviderDTO struct {
+	Name      string                 `json:"name"`                // Provider name (openai, anthropic, ollama, etc.)
+	Model     string                 `json:"model,omitempty"`     // Configured model
+	Active    bool                   `json:"active"`              // Whether the provider serves requests
+	Available *bool                  `json:"available,omitempty"` // Result of the last availability check; without health checks only set for the active provider
+	Healthy   *bool                  `json:"healthy,omitempty"`   // Whether the provider passes its health checks; only set with health checks
+	History   []*LLMProviderCheckDTO `json:"history,omitempty"`   // Recent health checks, oldest first
+}
+
+// LLMProviderCheckDTO represents a health check of an LLM provider
+type LLMProviderCheckDTO struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Available bool      `json:"available"`
+	LatencyMs int64     `json:"latency_ms"`
+}
+
+// LLMProvidersResponse represents a list of LLM providers response
+type LLMProvidersResponse struct {
+	Success   bool              `json:"success"`
+	Active    string            `json:"active,omitempty"` // Name of the active provider
+	Providers []*LLMProviderDTO `json:"providers,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// UpdateInstructionsRequest represents a request to replace the global custom instructions
+type UpdateInstructionsRequest struct {
+	Instructions string `json:"instructions" yaml:"instructions" validate:"max=4000"` // Empty removes the instructions
+}
+
+// InstructionsResponse represents the global custom instructions passed to the LLM in every session
+type InstructionsResponse struct {
+	Success      bool   `json:"success"`
+	Instructions string `json:"instructions"`
+	Error        string `json:"error,omitempty"`
+}
+
+// PromptFragmentDTO represents a part of the system prompt passed to the LLM within its scope
+type PromptFragmentDTO struct {
+	Scope  string `json:"scope"` // workspace, connector or skill
+	Name   string `json:"name"`  // Workspace ID, connector name or skill name
+	Prompt string `json:"prompt"`
+}
+
+// UpdatePromptFragmentRequest represents a request to replace a prompt fragment
+type UpdatePromptFragmentRequest struct {
+	Prompt string `json:"prompt" yaml:"prompt" validate:"max=4000"` // Empty removes the fragment
+}
+
+// PromptFragmentResponse represents a prompt fragment response
+type PromptFragmentResponse struct {
+	Success  bool               `json:"success"`
+	Fragment *PromptFragmentDTO `json:"fragment,omitempty"` // Omitted when the fragment was removed
+	Error    string             `json:"error,omitempty"`
+}
+
+// PromptFragmentsResponse represents a list of prompt fragments response
+type PromptFragmentsResponse struct {
+	Success   bool                 `json:"success"`
+	Fragments []*PromptFragmentDTO `json:"fragments"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// ReplayMessageRequest represents a request to route a message from the event store again
+type ReplayMessageRequest struct {
+	Deliver bool `json:"deliver"` // Send the replies to the user; by default they are only returned
+}
+
+// MessageReplayResponse represents the outcome of replaying a message from the event store
+type MessageReplayResponse struct {
+	Success   bool     `json:"success"`
+	EventID   string   `json:"event_id,omitempty"`  // Replayed connector.message event
+	Connector string   `json:"connector,omitempty"` // Connector that received the message
+	UserID    string   `json:"user_id,omitempty"`   // User who sent the message
+	Replies   []string `json:"replies,omitempty"`   // Replies to the replayed message
+	Delivered bool     `json:"delivered"`           // Whether the replies were sent to the user
+	Error     string   `json:"error,omitempty"`
+}
+
+// LiveEventDTO represents a runtime event streamed by GET /events or read from the event store by GET /admin/events
+type LiveEventDTO struct {
+	ID         string `json:"id,omitempty"`          // ID in the event store, set for events read by GET /admin/events
+	Type       string `json:"type"`                  // Event type, e.g. "router.message"
+	Timestamp  string `json:"timestamp"`             // ISO 8601 format
+	Connector  string `json:"connector,omitempty"`   // Connector that received the message
+	UserID     string `json:"user_id,omitempty"`     // User the message belongs to
+	SessionID  string `json:"session_id,omitempty"`  // Session the message or schedule run belongs to
+	MessageID  string `json:"message_id,omitempty"`  // Routed message
+	ScheduleID string `json:"schedule_id,omitempty"` // Triggered schedule
+	Skill      string `json:"skill,omitempty"`       // Skill run by the schedule or task
+	Rating     string `json:"rating,omitempty"`      // Rating of a feedback event, "up" or "down"
+	Content    string `json:"content,omitempty"`     // Message content, or the comment of a feedback event
+	Error      string `json:"error,omitempty"`       // Failure description
+	DurationMs int64  `json:"duration_ms,omitempty"` // Duration of a schedule run, skill run or LLM request
+}
+
+// LiveEventsResponse represents a list of stored events response
+type LiveEventsResponse struct {
+	Success bool            `json:"success"`
+	Events  []*LiveEventDTO `json:"events,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
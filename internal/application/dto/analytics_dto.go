@@ -0,0 +1,36 @@
+package dto
+
+// AnalyticsRequest selects the UTC days of the analytics. Without since and until, the last
+// 30 days up to today are returned.
+type AnalyticsRequest struct {
+	Since string `json:"since,omitempty"` // First day, YYYY-MM-DD
+	Until string `json:"until,omitempty"` // Last day, YYYY-MM-DD, inclusive
+}
+
+// AnalyticsCountsDTO represents the counts of a day or, without a day, of a range of days
+type AnalyticsCountsDTO struct {
+	Day                    string             `json:"day,omitempty"`                       // UTC day, YYYY-MM-DD
+	Messages               int64              `json:"messages"`                            // Messages received from users
+	ActiveUsers            int64              `json:"active_users"`                        // Distinct users who sent a message
+	LLMRequests            int64              `json:"llm_requests"`                        // Completed LLM calls
+	LLMErrors              int64              `json:"llm_errors"`                          // Failed LLM calls
+	Tokens                 int64              `json:"tokens"`                              // LLM tokens used
+	Cost                   float64            `json:"cost"`                                // Estimated LLM cost in dollars
+	SkillExecutions        int64              `json:"skill_executions"`                    // Skill executions, successful or not
+	SkillFailures          int64              `json:"skill_failures"`                      // Failed skill executions
+	MessagesByConnector    map[string]int64   `json:"messages_by_connector,omitempty"`     // Messages per connector
+	ActiveUsersByConnector map[string]int64   `json:"active_users_by_connector,omitempty"` // Active users per connector
+	TokensByModel          map[string]int64   `json:"tokens_by_model,omitempty"`           // Tokens per model
+	CostByModel            map[string]float64 `json:"cost_by_model,omitempty"`             // Cost per model
+	SkillExecutionsBySkill map[string]int64   `json:"skill_executions_by_skill,omitempty"` // Executions per skill
+}
+
+// AnalyticsResponse represents an analytics response
+type AnalyticsResponse struct {
+	Success bool                  `json:"success"`
+	Since   string                `json:"since,omitempty"`  // First day of the range
+	Until   string                `json:"until,omitempty"`  // Last day of the range
+	Days    []*AnalyticsCountsDTO `json:"days,omitempty"`   // Counts of every day of the range, oldest first
+	Totals  *AnalyticsCountsDTO   `json:"totals,omitempty"` // Counts of the whole range
+	Error   string                `json:"error,omitempty"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// APIKeyDTO represents an HTTP API key without the secret key itself
+type APIKeyDTO struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Prefix    string `json:"prefix"`               // Leading characters of the key, used to identify it
+	Scope     string `json:"scope"`                // "read" or "admin"
+	CreatedAt string `json:"created_at"`           // ISO 8601 format
+	RevokedAt string `json:"revoked_at,omitempty"` // ISO 8601 format, empty if the key is active
+	Workspace string `json:"workspace,omitempty"`  // Workspace the key is bound to, empty for all workspaces
+}
+
+// CreateAPIKeyRequest represents a request to create an HTTP API key
+type CreateAPIKeyRequest struct {
+	Name      string `json:"name" yaml:"name" validate:"required"`                                         // Human-readable name, e.g. the client using the key
+	Scope     string `json:"scope,omitempty" yaml:"scope,omitempty" validate:"omitempty,oneof=read admin"` // "read" (default) or "admin"
+	Workspace string `json:"workspace,omitempty" yaml:"workspace,omitempty"`                               // Binds the key to a workspace: it only sees the sessions of the workspace and cannot use /admin/
+}
+
+// APIKeyResponse represents a single API key response
+type APIKeyResponse struct {
+	Success bool       `json:"success"`
+	APIKey  *APIKeyDTO `json:"api_key,omitempty"`
+	Key     string     `json:"key,omitempty"` // Plaintext key, only returned when the key is created
+	Error   string     `json:"error,omitempty"`
+}
+
+// APIKeysResponse represents a list of API keys response
+type APIKeysResponse struct {
+	Success bool         `json:"success"`
+	APIKeys []*APIKeyDTO `json:"api_keys,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// APIKeyDTOFromEntity converts entity.APIKey to APIKeyDTO, leaving out the key hash
+func APIKeyDTOFromEntity(apiKey *entity.APIKey) *APIKeyDTO {
+	return &APIKeyDTO{
+		ID:        string(apiKey.ID),
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scope:     string(apiKey.Scope),
+		CreatedAt: apiKey.CreatedAt.Format(time.RFC3339),
+		RevokedAt: FormatOptionalTime(apiKey.RevokedAt),
+		Workspace: string(apiKey.WorkspaceID),
+	}
+}
@@ -0,0 +1,33 @@
+package dto
+
+// BackupDTO represents a database backup file
+type BackupDTO struct {
+	Name       string `json:"name"`       // File name in the backup directory
+	Size       int64  `json:"size"`       // File size in bytes
+	Compressed bool   `json:"compressed"` // Whether the backup is gzip-compressed
+	CreatedAt  string `json:"created_at"` // ISO 8601 format
+}
+
+// CreateBackupRequest represents a request to create a database backup
+type CreateBackupRequest struct {
+	Compress *bool `json:"compress,omitempty"` // Overrides the configured compression (optional)
+}
+
+// RestoreBackupRequest represents a request to restore a database backup
+type RestoreBackupRequest struct {
+	Name string `json:"name" validate:"required"` // File name in the backup directory
+}
+
+// BackupResponse represents a single backup response
+type BackupResponse struct {
+	Success bool       `json:"success"`
+	Backup  *BackupDTO `json:"backup,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// BackupsResponse represents a list of backups response
+type BackupsResponse struct {
+	Success bool         `json:"success"`
+	Backups []*BackupDTO `json:"backups,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
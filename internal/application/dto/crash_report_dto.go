@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// CrashReportDTO represents a recovered panic data transfer object
+type CrashReportDTO struct {
+	ID         string            `json:"id"`
+	Component  string            `json:"component"`            // Subsystem the panic was recovered in, e.g. "router" or "telegram"
+	Operation  string            `json:"operation"`            // Operation that panicked, e.g. "handle_message"
+	Panic      string            `json:"panic"`                // Value passed to panic
+	Stack      string            `json:"stack"`                // Stack trace of the panicking goroutine
+	Attributes map[string]string `json:"attributes,omitempty"` // Context of the operation, e.g. the connector or skill name
+	CreatedAt  string            `json:"created_at"`           // ISO 8601 format
+}
+
+// ListCrashReportsRequest represents a request to list crash reports
+type ListCrashReportsRequest struct {
+	Component string // Only reports of this component; empty for all
+	Limit     int
+	Offset    int
+}
+
+// CrashReportsResponse represents a list of crash reports response
+type CrashReportsResponse struct {
+	Success bool              `json:"success"`
+	Crashes []*CrashReportDTO `json:"crashes,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// CrashReportDTOFromEntity converts entity.CrashReport to CrashReportDTO
+func CrashReportDTOFromEntity(report *entity.CrashReport) *CrashReportDTO {
+	return &CrashReportDTO{
+		ID:         report.ID,
+		Component:  report.Component,
+		Operation:  report.Operation,
+		Panic:      report.Panic,
+		Stack:      report.Stack,
+		Attributes: report.Attributes,
+		CreatedAt:  report.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// DeadLetterDTO represents an event whose handler kept failing
+type DeadLetterDTO struct {
+	ID           string `json:"id"`
+	Subscription string `json:"subscription"` // Name of the subscription whose handler failed
+	EventType    string `json:"event_type"`
+	Payload      string `json:"payload"`         // JSON encoding of the event
+	OccurredAt   string `json:"occurred_at"`     // ISO 8601 format
+	Attempts     int    `json:"attempts"`        // Handler attempts made so far, including redeliveries
+	Error        string `json:"error,omitempty"` // Error returned by the last attempt
+	CreatedAt    string `json:"created_at"`      // ISO 8601 format
+	UpdatedAt    string `json:"updated_at"`      // ISO 8601 format
+}
+
+// MessageDeadLetterDTO represents a connector message the router failed to process
+type MessageDeadLetterDTO struct {
+	ID        string                 `json:"id"`
+	Connector string                 `json:"connector"` // Connector that received the message
+	UserID    string                 `json:"user_id"`
+	ChannelID string                 `json:"channel_id,omitempty"`
+	Content   string                 `json:"content"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"` // Connector metadata, e.g. the Telegram message ID
+	Attempts  int                    `json:"attempts"`           // Processing attempts made so far, including reprocessing
+	Error     string                 `json:"error,omitempty"`    // Error returned by the last attempt
+	CreatedAt string                 `json:"created_at"`         // ISO 8601 format
+	UpdatedAt string                 `json:"updated_at"`         // ISO 8601 format
+}
+
+// DeadLetterResponse represents the outcome of redelivering or deleting a dead letter
+type DeadLetterResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DeadLettersResponse represents a list of dead letters response
+type DeadLettersResponse struct {
+	Success     bool             `json:"success"`
+	DeadLetters []*DeadLetterDTO `json:"dead_letters,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// MessageDeadLettersResponse represents a list of message dead letters response
+type MessageDeadLettersResponse struct {
+	Success     bool                    `json:"success"`
+	DeadLetters []*MessageDeadLetterDTO `json:"dead_letters,omitempty"`
+	Error       string                  `json:"error,omitempty"`
+}
+
+// DeadLetterDTOFromEntity converts entity.DeadLetter to DeadLetterDTO
+func DeadLetterDTOFromEntity(letter *entity.DeadLetter) *DeadLetterDTO {
+	return &DeadLetterDTO{
+		ID:           letter.ID,
+		Subscription: letter.Subscription,
+		EventType:    letter.EventType,
+		Payload:      letter.Payload,
+		OccurredAt:   letter.OccurredAt.Format(time.RFC3339),
+		Attempts:     letter.Attempts,
+		Error:        letter.Error,
+		CreatedAt:    letter.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    letter.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// MessageDeadLetterDTOFromEntity converts entity.MessageDeadLetter to MessageDeadLetterDTO
+func MessageDeadLetterDTOFromEntity(letter *entity.MessageDeadLetter) *MessageDeadLetterDTO {
+	return &MessageDeadLetterDTO{
+		ID:        letter.ID,
+		Connector: letter.Connector,
+		UserID:    letter.UserID,
+		ChannelID: letter.ChannelID,
+		Content:   letter.Content,
+		Metadata:  letter.GetMetadata(),
+		Attempts:  letter.Attempts,
+		Error:     letter.Error,
+		CreatedAt: letter.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: letter.UpdatedAt.Format(time.RFC3339),
+	}
+}
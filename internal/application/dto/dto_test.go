@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 	"github.com/atumaikin/nexflow/internal/domain/valueobject"
@@ -34,7 +35,8 @@ func TestSessionDTO_ToEntity(t *testing.T) {
 		UpdatedAt: updated.Format(time.RFC3339),
 	}
 
-	entity := dto.ToEntity()
+	entity, err := dto.ToEntity()
+	require.NoError(t, err)
 	assert.NotNil(t, entity)
 	assert.Equal(t, valueobject.SessionID("session-1"), entity.ID)
 	assert.Equal(t, valueobject.UserID("user-1"), entity.UserID)
@@ -72,7 +74,8 @@ func TestMessageDTO_ToEntity(t *testing.T) {
 		CreatedAt: created.Format(time.RFC3339),
 	}
 
-	entity := dto.ToEntity()
+	entity, err := dto.ToEntity()
+	require.NoError(t, err)
 	assert.NotNil(t, entity)
 	assert.Equal(t, valueobject.MessageID("msg-1"), entity.ID)
 	assert.Equal(t, valueobject.SessionID("session-1"), entity.SessionID)
@@ -116,7 +119,8 @@ func TestTaskDTO_ToEntity(t *testing.T) {
 		UpdatedAt: updated.Format(time.RFC3339),
 	}
 
-	entity := dto.ToEntity()
+	entity, err := dto.ToEntity()
+	require.NoError(t, err)
 	assert.NotNil(t, entity)
 	assert.Equal(t, valueobject.TaskID("task-1"), entity.ID)
 	assert.Equal(t, valueobject.SessionID("session-1"), entity.SessionID)
@@ -167,7 +171,8 @@ func TestUserDTO_ToEntity(t *testing.T) {
 		CreatedAt: created.Format(time.RFC3339),
 	}
 
-	entity := dto.ToEntity()
+	entity, err := dto.ToEntity()
+	require.NoError(t, err)
 	assert.NotNil(t, entity)
 	assert.Equal(t, valueobject.UserID("user-1"), entity.ID)
 	assert.Equal(t, valueobject.MustNewChannel("telegram"), entity.Channel)
@@ -206,7 +211,8 @@ func TestSkillDTO_ToEntity(t *testing.T) {
 		CreatedAt:   created.Format(time.RFC3339),
 	}
 
-	entity := dto.ToEntity()
+	entity, err := dto.ToEntity()
+	require.NoError(t, err)
 	assert.NotNil(t, entity)
 	assert.Equal(t, valueobject.SkillID("skill-1"), entity.ID)
 	assert.Equal(t, "TestSkill", entity.Name)
@@ -253,7 +259,8 @@ func TestScheduleDTO_ToEntity(t *testing.T) {
 		CreatedAt:      created.Format(time.RFC3339),
 	}
 
-	entity := dto.ToEntity()
+	entity, err := dto.ToEntity()
+	require.NoError(t, err)
 	assert.NotNil(t, entity)
 	assert.Equal(t, valueobject.ScheduleID("schedule-1"), entity.ID)
 	assert.Equal(t, "skill-1", entity.Skill)
@@ -284,6 +291,23 @@ func TestScheduleDTOFromEntity(t *testing.T) {
 	assert.Equal(t, created.Format(time.RFC3339), dto.CreatedAt)
 }
 
+func TestScheduleDTO_OneShotRoundTrip(t *testing.T) {
+	runAt := getTestTime().Add(24 * time.Hour)
+	schedule := entity.NewOneShotSchedule("reminder", runAt, "{}")
+
+	dto := ScheduleDTOFromEntity(schedule)
+	assert.Equal(t, "once", dto.Type)
+	assert.Equal(t, "", dto.CronExpression)
+	assert.Equal(t, runAt.Format(time.RFC3339), dto.RunAt)
+
+	back := dto.MustToEntity()
+	assert.Equal(t, valueobject.ScheduleTypeOnce, back.Type)
+	assert.True(t, back.CronExpression.IsEmpty())
+	if assert.NotNil(t, back.RunAt) {
+		assert.True(t, runAt.Equal(*back.RunAt))
+	}
+}
+
 // ========== Utility Functions Tests ==========
 
 func TestMapToString(t *testing.T) {
@@ -1027,3 +1051,86 @@ func TestSuccessSkillExecutionResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 15, 9, 30, 0, 0, time.UTC)
+
+	cursor := EncodeCursor(createdAt, "msg-1")
+	gotTime, gotID, err := DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.True(t, createdAt.Equal(gotTime))
+	assert.Equal(t, "msg-1", gotID)
+
+	for _, invalid := range []string{"", "%%%", EncodeCursor(createdAt, "")} {
+		_, _, err := DecodeCursor(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	}
+}
+
+// ========== Invalid DTO Tests ==========
+
+func TestToEntity_InvalidFields(t *testing.T) {
+	dto := &TaskDTO{
+		ID:        "task-1",
+		SessionID: "session-1",
+		Status:    "paused",
+		CreatedAt: "yesterday",
+		UpdatedAt: getTestTime().Format(time.RFC3339),
+	}
+
+	task, err := dto.ToEntity()
+	assert.Nil(t, task)
+	var mappingErr *MappingError
+	require.ErrorAs(t, err, &mappingErr)
+	assert.Equal(t, "TaskDTO", mappingErr.DTO)
+	require.Len(t, mappingErr.Fields, 2)
+	assert.Equal(t, "status", mappingErr.Fields[0].Field)
+	assert.Equal(t, "created_at", mappingErr.Fields[1].Field)
+	assert.Contains(t, err.Error(), "invalid TaskDTO: status: ")
+	assert.Contains(t, err.Error(), "created_at: failed to parse created_at timestamp 'yesterday'")
+
+	assert.Panics(t, func() { dto.MustToEntity() })
+}
+
+func TestToEntity_InvalidOptionalTime(t *testing.T) {
+	dto := ScheduleDTOFromEntity(entity.NewOneShotSchedule("reminder", getTestTime(), "{}"))
+	dto.RunAt = "tomorrow"
+
+	_, err := dto.ToEntity()
+	var mappingErr *MappingError
+	require.ErrorAs(t, err, &mappingErr)
+	require.Len(t, mappingErr.Fields, 1)
+	assert.Equal(t, "run_at", mappingErr.Fields[0].Field)
+
+	// The Must variant keeps ignoring an invalid optional timestamp
+	assert.Nil(t, dto.MustToEntity().RunAt)
+}
+
+func TestParseNestedDTOs(t *testing.T) {
+	valid := &UserDTO{ID: "user-1", Channel: "telegram", ChannelID: "12345", CreatedAt: getTestTime().Format(time.RFC3339)}
+	invalid := &UserDTO{ID: "user-2", Channel: "telegram", ChannelID: "67890", CreatedAt: "yesterday"}
+
+	users, err := ParseNestedDTOs([]*UserDTO{valid, nil}, (*UserDTO).ToEntity)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, valueobject.UserID("user-1"), users[0].ID)
+	assert.Nil(t, users[1])
+
+	_, err = ParseNestedDTOs([]*UserDTO{valid, invalid}, (*UserDTO).ToEntity)
+	assert.ErrorContains(t, err, "element 1: invalid UserDTO: created_at: ")
+
+	user, err := ParseNestedDTO(nil, (*UserDTO).ToEntity)
+	require.NoError(t, err)
+	assert.Nil(t, user)
+	assert.Nil(t, MustParseNestedDTOs(nil, (*UserDTO).MustToEntity))
+
+	dtos := NestedDTOsFromEntities(users, UserDTOFromEntity)
+	assert.Equal(t, []*UserDTO{valid, nil}, dtos)
+	assert.Nil(t, NestedDTOFromEntity(nil, UserDTOFromEntity))
+}
+
+func TestPanicOnFieldError(t *testing.T) {
+	assert.NotPanics(t, func() { PanicOnFieldError("tags", nil) })
+	assert.PanicsWithValue(t, FieldError{Field: "tags", Err: assert.AnError}, func() { PanicOnFieldError("tags", assert.AnError) })
+	assert.Equal(t, []string{}, DiscardErr(StringToSlice("not json")))
+}
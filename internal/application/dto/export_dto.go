@@ -0,0 +1,29 @@
+package dto
+
+// Session export formats
+const (
+	ExportFormatJSON     = "json"
+	ExportFormatMarkdown = "markdown"
+)
+
+// SessionTranscriptDTO represents the JSON export of a session with its messages and tasks
+type SessionTranscriptDTO struct {
+	Session    *SessionDTO   `json:"session"`
+	Messages   []*MessageDTO `json:"messages"`    // Oldest first
+	Tasks      []*TaskDTO    `json:"tasks"`       // Oldest first, including skill outputs
+	ExportedAt string        `json:"exported_at"` // ISO 8601 format
+}
+
+// SessionExportDTO represents a rendered session transcript file
+type SessionExportDTO struct {
+	FileName    string `json:"file_name"`    // Suggested file name, e.g. "session-<id>.md"
+	ContentType string `json:"content_type"` // MIME type of the content
+	Content     []byte `json:"-"`            // Rendered transcript
+}
+
+// SessionExportResponse represents a session export response
+type SessionExportResponse struct {
+	Success bool              `json:"success"`
+	Export  *SessionExportDTO `json:"export,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
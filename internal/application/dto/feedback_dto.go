@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// MessageFeedbackDTO represents the rating of an assistant message by the user of its session
+type MessageFeedbackDTO struct {
+	MessageID string `json:"message_id"`
+	SessionID string `json:"session_id"`
+	UserID    string `json:"user_id"`
+	Rating    string `json:"rating"`            // "up" or "down"
+	Comment   string `json:"comment,omitempty"` // Optional explanation of the rating
+	CreatedAt string `json:"created_at"`        // ISO 8601 format
+	UpdatedAt string `json:"updated_at"`        // ISO 8601 format, when the message was last rated
+}
+
+// RecordFeedbackRequest represents the rating of an assistant message. Rating a message
+// again replaces the earlier rating and comment.
+type RecordFeedbackRequest struct {
+	Rating  string `json:"rating" yaml:"rating" validate:"required,oneof=up down"`         // "up" or "down"
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty" validate:"max=2000"` // Optional explanation of the rating
+}
+
+// FeedbackResponse represents a message feedback response
+type FeedbackResponse struct {
+	Success  bool                `json:"success"`
+	Feedback *MessageFeedbackDTO `json:"feedback,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// FeedbackCountsDTO represents the ratings of a day or, without a day, of a range of days
+type FeedbackCountsDTO struct {
+	Day     string  `json:"day,omitempty"` // UTC day, YYYY-MM-DD
+	Up      int64   `json:"up"`            // Replies rated up
+	Down    int64   `json:"down"`          // Replies rated down
+	UpRatio float64 `json:"up_ratio"`      // Share of the ratings that are up, 0 without ratings
+}
+
+// FeedbackAnalyticsResponse represents the daily reply ratings
+type FeedbackAnalyticsResponse struct {
+	Success bool                 `json:"success"`
+	Since   string               `json:"since,omitempty"`  // First day of the range
+	Until   string               `json:"until,omitempty"`  // Last day of the range
+	Days    []*FeedbackCountsDTO `json:"days,omitempty"`   // Ratings of every day of the range, oldest first
+	Totals  *FeedbackCountsDTO   `json:"totals,omitempty"` // Ratings of the whole range
+	Error   string               `json:"error,omitempty"`
+}
+
+// MessageFeedbackDTOFromEntity converts entity.MessageFeedback to MessageFeedbackDTO
+func MessageFeedbackDTOFromEntity(feedback *entity.MessageFeedback) *MessageFeedbackDTO {
+	return &MessageFeedbackDTO{
+		MessageID: feedback.MessageID.String(),
+		SessionID: feedback.SessionID.String(),
+		UserID:    feedback.UserID.String(),
+		Rating:    string(feedback.Rating),
+		Comment:   feedback.Comment,
+		CreatedAt: feedback.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: feedback.UpdatedAt.Format(time.RFC3339),
+	}
+}
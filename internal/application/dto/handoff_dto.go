@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// SessionHandoffDTO represents an operator takeover of a session
+type SessionHandoffDTO struct {
+	SessionID string `json:"session_id"`
+	Operator  string `json:"operator"`   // Operator who took over the session
+	Mode      string `json:"mode"`       // "forward" or "pause"
+	StartedAt string `json:"started_at"` // ISO 8601 format
+}
+
+// StartHandoffRequest represents a request to take over a session
+type StartHandoffRequest struct {
+	Operator string `json:"operator" yaml:"operator" validate:"required"`                                  // Operator taking over the session
+	Mode     string `json:"mode,omitempty" yaml:"mode,omitempty" validate:"omitempty,oneof=forward pause"` // "forward" (default) forwards user messages to the operator, "pause" only records them
+}
+
+// HandoffReplyRequest represents an operator reply relayed to the user of a handed-off session
+type HandoffReplyRequest struct {
+	Content string `json:"content" yaml:"content" validate:"required"`
+}
+
+// HandoffResponse represents a session handoff response
+type HandoffResponse struct {
+	Success bool               `json:"success"`
+	Handoff *SessionHandoffDTO `json:"handoff,omitempty"`
+	Message *MessageDTO        `json:"message,omitempty"` // Operator reply sent to the user
+	Error   string             `json:"error,omitempty"`
+}
+
+// SessionHandoffDTOFromEntity converts entity.SessionHandoff to SessionHandoffDTO
+func SessionHandoffDTOFromEntity(sessionID string, handoff *entity.SessionHandoff) *SessionHandoffDTO {
+	return &SessionHandoffDTO{
+		SessionID: sessionID,
+		Operator:  handoff.Operator,
+		Mode:      string(handoff.Mode),
+		StartedAt: handoff.StartedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,21 @@
+package dto
+
+// Health statuses reported by health checks
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthCheckDTO represents the result of a single health check
+type HealthCheckDTO struct {
+	Status    string         `json:"status"`            // "ok" or "unavailable"
+	LatencyMs int64          `json:"latency_ms"`        // Time the check took in milliseconds
+	Error     string         `json:"error,omitempty"`   // Failure reason (unavailable checks only)
+	Details   map[string]any `json:"details,omitempty"` // Check-specific details such as pool statistics
+}
+
+// HealthResponse represents the response of the health endpoint
+type HealthResponse struct {
+	Status string                     `json:"status"` // "ok" when all checks pass, "unavailable" otherwise
+	Checks map[string]*HealthCheckDTO `json:"checks"` // Results by check name
+}
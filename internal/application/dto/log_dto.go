@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
 // LogDTO represents a log data transfer object
 type LogDTO struct {
 	ID        string `json:"id"`
@@ -12,12 +18,20 @@ type LogDTO struct {
 
 // CreateLogRequest represents a request to create a log
 type CreateLogRequest struct {
-	Level    string                 `json:"level" yaml:"level"`   // "debug", "info", "warn", "error"
-	Source   string                 `json:"source" yaml:"source"` // Source component/module
-	Message  string                 `json:"message" yaml:"message"`
+	Level    string                 `json:"level" yaml:"level" validate:"required,oneof=debug info warn error"` // "debug", "info", "warn", "error"
+	Source   string                 `json:"source" yaml:"source" validate:"required"`                           // Source component/module
+	Message  string                 `json:"message" yaml:"message" validate:"required"`
 	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
+// ListLogsRequest represents a request to list logs
+type ListLogsRequest struct {
+	Level  string // Only logs with this level; empty for all
+	Source string // Only logs from this source; empty for all
+	Limit  int
+	Offset int
+}
+
 // LogResponse represents a log response
 type LogResponse struct {
 	Success bool    `json:"success"`
@@ -31,3 +45,36 @@ type LogsResponse struct {
 	Logs    []*LogDTO `json:"logs,omitempty"`
 	Error   string    `json:"error,omitempty"`
 }
+
+// LogDTOFromEntity converts entity.Log to LogDTO
+func LogDTOFromEntity(log *entity.Log) *LogDTO {
+	return &LogDTO{
+		ID:        string(log.ID),
+		Level:     string(log.Level),
+		Source:    log.Source,
+		Message:   log.Message,
+		Metadata:  log.Metadata,
+		CreatedAt: log.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ModuleLogLevelDTO represents the log level of a subsystem
+type ModuleLogLevelDTO struct {
+	Name      string `json:"name"`      // e.g. "router", "telegram", "llm", "db"
+	Level     string `json:"level"`     // "debug", "info", "warn", "error"
+	Inherited bool   `json:"inherited"` // Whether the module follows the default level
+}
+
+// UpdateLogLevelsRequest represents a request to change log levels at runtime
+type UpdateLogLevelsRequest struct {
+	Level   string            `json:"level,omitempty" yaml:"level,omitempty" validate:"omitempty,oneof=debug info warn error"` // Default level of all modules without a level of their own
+	Modules map[string]string `json:"modules,omitempty" yaml:"modules,omitempty"`                                              // Levels by module; an empty level makes the module follow the default level
+}
+
+// LogLevelsResponse represents the current log levels
+type LogLevelsResponse struct {
+	Success bool                `json:"success"`
+	Level   string              `json:"level,omitempty"` // Default level
+	Modules []ModuleLogLevelDTO `json:"modules,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
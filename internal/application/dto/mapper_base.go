@@ -1,12 +1,54 @@
-//go:generate go run github.com/atumaikin/nexflow/cmd/genmapper user_dto.go session_dto.go message_dto.go task_dto.go skill_dto.go schedule_dto.go
+//go:generate go run github.com/atumaikin/nexflow/cmd/genmapper -output mapper_gen.go -tests user_dto.go session_dto.go message_dto.go task_dto.go skill_dto.go schedule_dto.go
 
 package dto
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// FieldError is the error of a DTO field that cannot be converted to the entity field
+type FieldError struct {
+	Field string // JSON name of the field, e.g. "created_at"
+	Err   error
+}
+
+// Error implements error
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the error of the field
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MappingError is returned by the generated ToEntity methods when fields of a DTO are invalid,
+// e.g. so that HTTP handlers can reject the request listing all invalid fields
+type MappingError struct {
+	DTO    string
+	Fields []FieldError
+}
+
+// Error implements error
+func (e *MappingError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		msgs = append(msgs, field.Error())
+	}
+	return fmt.Sprintf("invalid %s: %s", e.DTO, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the fields
+func (e *MappingError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		errs = append(errs, field)
+	}
+	return errs
+}
+
 // ParseTimeFields parses a single timestamp string to time.Time
 // Returns zero time and error if parsing fails
 func ParseTimeFields(createdAtStr string) (time.Time, error) {
@@ -71,3 +113,145 @@ func FormatTimeFields(createdAt time.Time) string {
 func FormatTimeFieldsWithUpdatedAt(createdAt, updatedAt time.Time) (string, string) {
 	return createdAt.Format(time.RFC3339), updatedAt.Format(time.RFC3339)
 }
+
+// ParseTime parses a required RFC3339 timestamp of the named field
+func ParseTime(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("%s timestamp is empty", field)
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %s timestamp '%s': %w", field, value, err)
+	}
+	return t, nil
+}
+
+// MustParseTime parses a required RFC3339 timestamp of the named field.
+// Panics if parsing fails. Use this when the timestamp is guaranteed to be valid.
+func MustParseTime(field, value string) time.Time {
+	t, err := ParseTime(field, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ParseOptionalTime parses an optional RFC3339 timestamp string.
+// Returns nil if the string is empty or cannot be parsed.
+func ParseOptionalTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ParseOptionalTimeField parses an optional RFC3339 timestamp of the named field.
+// Returns nil for an empty string and an error if the timestamp is invalid.
+func ParseOptionalTimeField(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := ParseTime(field, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FormatOptionalTime formats an optional time.Time to RFC3339 string.
+// Returns an empty string for nil.
+func FormatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// PanicOnFieldError panics with a FieldError if err is not nil. The generated MustToEntity
+// methods use it for fields converted by functions returning an error.
+func PanicOnFieldError(field string, err error) {
+	if err != nil {
+		panic(FieldError{Field: field, Err: err})
+	}
+}
+
+// DiscardErr returns value, dropping the error of a conversion. The generated FromEntity
+// functions use it for converters that fail on invalid entity fields, e.g. StringToSlice,
+// which returns an empty slice for a column that is not valid JSON.
+func DiscardErr[T any](value T, _ error) T {
+	return value
+}
+
+// ParseNestedDTO converts a nested DTO with its ToEntity method.
+// Returns nil for a nil DTO.
+func ParseNestedDTO[D, E any](dto *D, toEntity func(*D) (*E, error)) (*E, error) {
+	if dto == nil {
+		return nil, nil
+	}
+	return toEntity(dto)
+}
+
+// MustParseNestedDTO converts a nested DTO with its MustToEntity method.
+// Returns nil for a nil DTO.
+func MustParseNestedDTO[D, E any](dto *D, mustToEntity func(*D) *E) *E {
+	if dto == nil {
+		return nil
+	}
+	return mustToEntity(dto)
+}
+
+// ParseNestedDTOs converts a slice of nested DTOs with their ToEntity method.
+// Nil elements stay nil; the error names the index of the first invalid element.
+func ParseNestedDTOs[D, E any](dtos []*D, toEntity func(*D) (*E, error)) ([]*E, error) {
+	if dtos == nil {
+		return nil, nil
+	}
+	entities := make([]*E, len(dtos))
+	for i, dto := range dtos {
+		entity, err := ParseNestedDTO(dto, toEntity)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		entities[i] = entity
+	}
+	return entities, nil
+}
+
+// MustParseNestedDTOs converts a slice of nested DTOs with their MustToEntity method.
+// Nil elements stay nil.
+func MustParseNestedDTOs[D, E any](dtos []*D, mustToEntity func(*D) *E) []*E {
+	if dtos == nil {
+		return nil
+	}
+	entities := make([]*E, len(dtos))
+	for i, dto := range dtos {
+		entities[i] = MustParseNestedDTO(dto, mustToEntity)
+	}
+	return entities
+}
+
+// NestedDTOFromEntity converts a nested entity with a generated FromEntity function.
+// Returns nil for a nil entity.
+func NestedDTOFromEntity[E, D any](entity *E, fromEntity func(*E) *D) *D {
+	if entity == nil {
+		return nil
+	}
+	return fromEntity(entity)
+}
+
+// NestedDTOsFromEntities converts a slice of nested entities with a generated FromEntity
+// function. Nil elements stay nil.
+func NestedDTOsFromEntities[E, D any](entities []*E, fromEntity func(*E) *D) []*D {
+	if entities == nil {
+		return nil
+	}
+	dtos := make([]*D, len(entities))
+	for i, entity := range entities {
+		dtos[i] = NestedDTOFromEntity(entity, fromEntity)
+	}
+	return dtos
+}
@@ -242,3 +242,21 @@ func TestRFC3339ConsistencyWithUpdatedAt(t *testing.T) {
 		t.Errorf("Format then Parse updatedAt: got %v, want %v", parsedUpdatedAt, originalUpdatedAt)
 	}
 }
+
+func TestOptionalTime(t *testing.T) {
+	if got := ParseOptionalTime(""); got != nil {
+		t.Errorf("ParseOptionalTime(\"\") = %v, want nil", got)
+	}
+	if got := ParseOptionalTime("not-a-timestamp"); got != nil {
+		t.Errorf("ParseOptionalTime(invalid) = %v, want nil", got)
+	}
+	if got := FormatOptionalTime(nil); got != "" {
+		t.Errorf("FormatOptionalTime(nil) = %q, want empty string", got)
+	}
+
+	original := time.Date(2024, 1, 30, 10, 0, 0, 0, time.UTC)
+	parsed := ParseOptionalTime(FormatOptionalTime(&original))
+	if parsed == nil || !parsed.Equal(original) {
+		t.Errorf("Format then Parse: got %v, want %v", parsed, original)
+	}
+}
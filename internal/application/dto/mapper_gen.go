@@ -8,8 +8,32 @@ import (
 	"time"
 )
 
-// ToEntity converts UserDTO to entity.User
-func (dto *UserDTO) ToEntity() *entity.User {
+// ToEntity converts UserDTO to entity.User.
+// Returns a *MappingError listing the invalid fields.
+func (dto *UserDTO) ToEntity() (*entity.User, error) {
+	var fieldErrs []FieldError
+	channel, err := valueobject.NewChannel(dto.Channel)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "channel", Err: err})
+	}
+	createdAt, err := ParseTime("created_at", dto.CreatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "created_at", Err: err})
+	}
+	if len(fieldErrs) > 0 {
+		return nil, &MappingError{DTO: "UserDTO", Fields: fieldErrs}
+	}
+	return &entity.User{
+		ID:        valueobject.UserID(dto.ID),
+		Channel:   channel,
+		ChannelID: dto.ChannelID,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// MustToEntity converts UserDTO to entity.User.
+// Panics if a field is invalid. Use this when the DTO is guaranteed to be valid.
+func (dto *UserDTO) MustToEntity() *entity.User {
 	createdAt := MustParseTimeFields(dto.CreatedAt)
 	return &entity.User{
 		ID:        valueobject.UserID(dto.ID),
@@ -29,29 +53,95 @@ func UserDTOFromEntity(user *entity.User) *UserDTO {
 	}
 }
 
-// ToEntity converts SessionDTO to entity.Session
-func (dto *SessionDTO) ToEntity() *entity.Session {
+// ToEntity converts SessionDTO to entity.Session.
+// Returns a *MappingError listing the invalid fields.
+func (dto *SessionDTO) ToEntity() (*entity.Session, error) {
+	var fieldErrs []FieldError
+	userID, err := valueobject.NewUserID(dto.UserID)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "user_id", Err: err})
+	}
+	createdAt, err := ParseTime("created_at", dto.CreatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "created_at", Err: err})
+	}
+	updatedAt, err := ParseTime("updated_at", dto.UpdatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "updated_at", Err: err})
+	}
+	if len(fieldErrs) > 0 {
+		return nil, &MappingError{DTO: "SessionDTO", Fields: fieldErrs}
+	}
+	return &entity.Session{
+		ID:          valueobject.SessionID(dto.ID),
+		UserID:      userID,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+		Pinned:      dto.Pinned,
+		Title:       dto.Title,
+		WorkspaceID: valueobject.WorkspaceID(dto.WorkspaceID),
+	}, nil
+}
+
+// MustToEntity converts SessionDTO to entity.Session.
+// Panics if a field is invalid. Use this when the DTO is guaranteed to be valid.
+func (dto *SessionDTO) MustToEntity() *entity.Session {
 	createdAt, updatedAt := MustParseTimeFieldsWithUpdatedAt(dto.CreatedAt, dto.UpdatedAt)
 	return &entity.Session{
-		ID:        valueobject.SessionID(dto.ID),
-		UserID:    valueobject.MustNewUserID(dto.UserID),
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:          valueobject.SessionID(dto.ID),
+		UserID:      valueobject.MustNewUserID(dto.UserID),
+		Pinned:      dto.Pinned,
+		Title:       dto.Title,
+		WorkspaceID: valueobject.WorkspaceID(dto.WorkspaceID),
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
 	}
 }
 
 // FromEntity converts entity.Session to SessionDTO
 func SessionDTOFromEntity(session *entity.Session) *SessionDTO {
 	return &SessionDTO{
-		ID:        string(session.ID),
-		UserID:    string(session.UserID),
-		CreatedAt: session.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: session.UpdatedAt.Format(time.RFC3339),
+		ID:          string(session.ID),
+		UserID:      string(session.UserID),
+		CreatedAt:   session.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   session.UpdatedAt.Format(time.RFC3339),
+		Pinned:      session.Pinned,
+		Title:       session.Title,
+		WorkspaceID: string(session.WorkspaceID),
 	}
 }
 
-// ToEntity converts MessageDTO to entity.Message
-func (dto *MessageDTO) ToEntity() *entity.Message {
+// ToEntity converts MessageDTO to entity.Message.
+// Returns a *MappingError listing the invalid fields.
+func (dto *MessageDTO) ToEntity() (*entity.Message, error) {
+	var fieldErrs []FieldError
+	sessionID, err := valueobject.NewSessionID(dto.SessionID)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "session_id", Err: err})
+	}
+	role, err := valueobject.NewMessageRole(dto.Role)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "role", Err: err})
+	}
+	createdAt, err := ParseTime("created_at", dto.CreatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "created_at", Err: err})
+	}
+	if len(fieldErrs) > 0 {
+		return nil, &MappingError{DTO: "MessageDTO", Fields: fieldErrs}
+	}
+	return &entity.Message{
+		ID:        valueobject.MessageID(dto.ID),
+		SessionID: sessionID,
+		Role:      role,
+		Content:   dto.Content,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// MustToEntity converts MessageDTO to entity.Message.
+// Panics if a field is invalid. Use this when the DTO is guaranteed to be valid.
+func (dto *MessageDTO) MustToEntity() *entity.Message {
 	createdAt := MustParseTimeFields(dto.CreatedAt)
 	return &entity.Message{
 		ID:        valueobject.MessageID(dto.ID),
@@ -73,39 +163,119 @@ func MessageDTOFromEntity(message *entity.Message) *MessageDTO {
 	}
 }
 
-// ToEntity converts TaskDTO to entity.Task
-func (dto *TaskDTO) ToEntity() *entity.Task {
+// ToEntity converts TaskDTO to entity.Task.
+// Returns a *MappingError listing the invalid fields.
+func (dto *TaskDTO) ToEntity() (*entity.Task, error) {
+	var fieldErrs []FieldError
+	sessionID, err := valueobject.NewSessionID(dto.SessionID)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "session_id", Err: err})
+	}
+	status, err := valueobject.NewTaskStatus(dto.Status)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "status", Err: err})
+	}
+	createdAt, err := ParseTime("created_at", dto.CreatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "created_at", Err: err})
+	}
+	updatedAt, err := ParseTime("updated_at", dto.UpdatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "updated_at", Err: err})
+	}
+	startedAt, err := ParseOptionalTimeField("started_at", dto.StartedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "started_at", Err: err})
+	}
+	finishedAt, err := ParseOptionalTimeField("finished_at", dto.FinishedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "finished_at", Err: err})
+	}
+	if len(fieldErrs) > 0 {
+		return nil, &MappingError{DTO: "TaskDTO", Fields: fieldErrs}
+	}
+	return &entity.Task{
+		ID:         valueobject.TaskID(dto.ID),
+		SessionID:  sessionID,
+		Skill:      dto.Skill,
+		Input:      dto.Input,
+		Output:     dto.Output,
+		Status:     status,
+		Error:      dto.Error,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+	}, nil
+}
+
+// MustToEntity converts TaskDTO to entity.Task.
+// Panics if a field is invalid. Use this when the DTO is guaranteed to be valid.
+func (dto *TaskDTO) MustToEntity() *entity.Task {
 	createdAt, updatedAt := MustParseTimeFieldsWithUpdatedAt(dto.CreatedAt, dto.UpdatedAt)
 	return &entity.Task{
-		ID:        valueobject.TaskID(dto.ID),
-		SessionID: valueobject.MustNewSessionID(dto.SessionID),
-		Skill:     dto.Skill,
-		Input:     dto.Input,
-		Output:    dto.Output,
-		Status:    valueobject.MustNewTaskStatus(dto.Status),
-		Error:     dto.Error,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:         valueobject.TaskID(dto.ID),
+		SessionID:  valueobject.MustNewSessionID(dto.SessionID),
+		Skill:      dto.Skill,
+		Input:      dto.Input,
+		Output:     dto.Output,
+		Status:     valueobject.MustNewTaskStatus(dto.Status),
+		Error:      dto.Error,
+		StartedAt:  ParseOptionalTime(dto.StartedAt),
+		FinishedAt: ParseOptionalTime(dto.FinishedAt),
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
 	}
 }
 
 // FromEntity converts entity.Task to TaskDTO
 func TaskDTOFromEntity(task *entity.Task) *TaskDTO {
 	return &TaskDTO{
-		ID:        string(task.ID),
-		SessionID: string(task.SessionID),
-		Skill:     task.Skill,
-		Input:     task.Input,
-		Output:    task.Output,
-		Status:    string(task.Status),
-		Error:     task.Error,
-		CreatedAt: task.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: task.UpdatedAt.Format(time.RFC3339),
+		ID:         string(task.ID),
+		SessionID:  string(task.SessionID),
+		Skill:      task.Skill,
+		Input:      task.Input,
+		Output:     task.Output,
+		Status:     string(task.Status),
+		Error:      task.Error,
+		CreatedAt:  task.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  task.UpdatedAt.Format(time.RFC3339),
+		StartedAt:  FormatOptionalTime(task.StartedAt),
+		FinishedAt: FormatOptionalTime(task.FinishedAt),
 	}
 }
 
-// ToEntity converts SkillDTO to entity.Skill
-func (dto *SkillDTO) ToEntity() *entity.Skill {
+// ToEntity converts SkillDTO to entity.Skill.
+// Returns a *MappingError listing the invalid fields.
+func (dto *SkillDTO) ToEntity() (*entity.Skill, error) {
+	var fieldErrs []FieldError
+	version, err := valueobject.NewVersion(dto.Version)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "version", Err: err})
+	}
+	createdAt, err := ParseTime("created_at", dto.CreatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "created_at", Err: err})
+	}
+	if len(fieldErrs) > 0 {
+		return nil, &MappingError{DTO: "SkillDTO", Fields: fieldErrs}
+	}
+	return &entity.Skill{
+		ID:          valueobject.SkillID(dto.ID),
+		Name:        dto.Name,
+		Version:     version,
+		Location:    dto.Location,
+		Permissions: dto.Permissions,
+		Metadata:    dto.Metadata,
+		Disabled:    dto.Disabled,
+		WorkspaceID: valueobject.WorkspaceID(dto.WorkspaceID),
+		CreatedAt:   createdAt,
+	}, nil
+}
+
+// MustToEntity converts SkillDTO to entity.Skill.
+// Panics if a field is invalid. Use this when the DTO is guaranteed to be valid.
+func (dto *SkillDTO) MustToEntity() *entity.Skill {
 	createdAt := MustParseTimeFields(dto.CreatedAt)
 	return &entity.Skill{
 		ID:          valueobject.SkillID(dto.ID),
@@ -114,6 +284,8 @@ func (dto *SkillDTO) ToEntity() *entity.Skill {
 		Location:    dto.Location,
 		Permissions: dto.Permissions,
 		Metadata:    dto.Metadata,
+		Disabled:    dto.Disabled,
+		WorkspaceID: valueobject.WorkspaceID(dto.WorkspaceID),
 		CreatedAt:   createdAt,
 	}
 }
@@ -127,31 +299,86 @@ func SkillDTOFromEntity(skill *entity.Skill) *SkillDTO {
 		Location:    skill.Location,
 		Permissions: skill.Permissions,
 		Metadata:    skill.Metadata,
+		Disabled:    skill.Disabled,
+		WorkspaceID: string(skill.WorkspaceID),
 		CreatedAt:   skill.CreatedAt.Format(time.RFC3339),
 	}
 }
 
-// ToEntity converts ScheduleDTO to entity.Schedule
-func (dto *ScheduleDTO) ToEntity() *entity.Schedule {
+// ToEntity converts ScheduleDTO to entity.Schedule.
+// Returns a *MappingError listing the invalid fields.
+func (dto *ScheduleDTO) ToEntity() (*entity.Schedule, error) {
+	var fieldErrs []FieldError
+	createdAt, err := ParseTime("created_at", dto.CreatedAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "created_at", Err: err})
+	}
+	runAt, err := ParseOptionalTimeField("run_at", dto.RunAt)
+	if err != nil {
+		fieldErrs = append(fieldErrs, FieldError{Field: "run_at", Err: err})
+	}
+	if len(fieldErrs) > 0 {
+		return nil, &MappingError{DTO: "ScheduleDTO", Fields: fieldErrs}
+	}
+	return &entity.Schedule{
+		ID:              valueobject.ScheduleID(dto.ID),
+		Skill:           dto.Skill,
+		Prompt:          dto.Prompt,
+		SessionID:       dto.SessionID,
+		CronExpression:  valueobject.CronExpression(dto.CronExpression),
+		Input:           dto.Input,
+		Enabled:         dto.Enabled,
+		Timezone:        valueobject.Timezone(dto.Timezone),
+		JitterSeconds:   dto.JitterSeconds,
+		CreatedAt:       createdAt,
+		Type:            valueobject.ScheduleType(dto.Type),
+		RunAt:           runAt,
+		MissedRunPolicy: valueobject.MissedRunPolicy(dto.MissedRunPolicy),
+		TargetConnector: dto.TargetConnector,
+		TargetUserID:    dto.TargetUserID,
+	}, nil
+}
+
+// MustToEntity converts ScheduleDTO to entity.Schedule.
+// Panics if a field is invalid. Use this when the DTO is guaranteed to be valid.
+func (dto *ScheduleDTO) MustToEntity() *entity.Schedule {
 	createdAt := MustParseTimeFields(dto.CreatedAt)
 	return &entity.Schedule{
-		ID:             valueobject.ScheduleID(dto.ID),
-		Skill:          dto.Skill,
-		CronExpression: valueobject.MustNewCronExpression(dto.CronExpression),
-		Input:          dto.Input,
-		Enabled:        dto.Enabled,
-		CreatedAt:      createdAt,
+		ID:              valueobject.ScheduleID(dto.ID),
+		Skill:           dto.Skill,
+		Prompt:          dto.Prompt,
+		SessionID:       dto.SessionID,
+		CronExpression:  valueobject.CronExpression(dto.CronExpression),
+		Input:           dto.Input,
+		Enabled:         dto.Enabled,
+		Timezone:        valueobject.Timezone(dto.Timezone),
+		JitterSeconds:   dto.JitterSeconds,
+		Type:            valueobject.ScheduleType(dto.Type),
+		RunAt:           ParseOptionalTime(dto.RunAt),
+		MissedRunPolicy: valueobject.MissedRunPolicy(dto.MissedRunPolicy),
+		TargetConnector: dto.TargetConnector,
+		TargetUserID:    dto.TargetUserID,
+		CreatedAt:       createdAt,
 	}
 }
 
 // FromEntity converts entity.Schedule to ScheduleDTO
 func ScheduleDTOFromEntity(schedule *entity.Schedule) *ScheduleDTO {
 	return &ScheduleDTO{
-		ID:             string(schedule.ID),
-		Skill:          schedule.Skill,
-		CronExpression: string(schedule.CronExpression),
-		Input:          schedule.Input,
-		Enabled:        schedule.Enabled,
-		CreatedAt:      schedule.CreatedAt.Format(time.RFC3339),
+		ID:              string(schedule.ID),
+		Skill:           schedule.Skill,
+		Prompt:          schedule.Prompt,
+		SessionID:       schedule.SessionID,
+		CronExpression:  string(schedule.CronExpression),
+		Input:           schedule.Input,
+		Enabled:         schedule.Enabled,
+		Timezone:        string(schedule.Timezone),
+		JitterSeconds:   schedule.JitterSeconds,
+		CreatedAt:       schedule.CreatedAt.Format(time.RFC3339),
+		Type:            string(schedule.Type),
+		RunAt:           FormatOptionalTime(schedule.RunAt),
+		MissedRunPolicy: string(schedule.MissedRunPolicy),
+		TargetConnector: schedule.TargetConnector,
+		TargetUserID:    schedule.TargetUserID,
 	}
 }
@@ -0,0 +1,367 @@
+// Code generated by genmapper; DO NOT EDIT.
+
+package dto
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// genmapperRuns is the number of random entities each round-trip test converts
+const genmapperRuns = 100
+
+// genmapperString returns a random non-empty string
+func genmapperString(r *rand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_ äöü"
+	b := make([]rune, 1+r.IntN(16))
+	runes := []rune(letters)
+	for i := range b {
+		b[i] = runes[r.IntN(len(runes))]
+	}
+	return string(b)
+}
+
+// genmapperTime returns a random UTC time with a precision of seconds, which RFC3339 preserves
+func genmapperTime(r *rand.Rand) time.Time {
+	return time.Unix(r.Int64N(4102444800), 0).UTC()
+}
+
+// genmapperPtr returns a pointer to value, or nil for some of the calls
+func genmapperPtr[T any](r *rand.Rand, value T) *T {
+	if r.IntN(4) == 0 {
+		return nil
+	}
+	return &value
+}
+
+// genmapperUser returns an entity.User with random values of the fields mapped by UserDTO
+func genmapperUser(tb testing.TB, r *rand.Rand) *entity.User {
+	tb.Helper()
+	return &entity.User{
+		ID:        valueobject.UserID(genmapperString(r)),
+		Channel:   valueobject.MustNewChannel("telegram"),
+		ChannelID: genmapperString(r),
+		CreatedAt: genmapperTime(r),
+	}
+}
+
+// TestUserDTO_RoundTrip checks that entity.User is unchanged by a conversion to UserDTO and back
+func TestUserDTO_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range genmapperRuns {
+		want := genmapperUser(t, r)
+		dto := UserDTOFromEntity(want)
+		got, err := dto.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, dto.MustToEntity())
+	}
+}
+
+// FuzzUserDTO_ToEntity checks that UserDTO.ToEntity rejects invalid fields without panicking
+// and that the entities it returns convert back to the same DTO
+func FuzzUserDTO_ToEntity(f *testing.F) {
+	r := rand.New(rand.NewPCG(1, 2))
+	base := UserDTOFromEntity(genmapperUser(f, r))
+	f.Add(base.Channel, base.CreatedAt)
+	f.Add("", "")
+	f.Fuzz(func(t *testing.T, channel, createdAt string) {
+		dto := *base
+		dto.Channel = channel
+		dto.CreatedAt = createdAt
+		got, err := dto.ToEntity()
+		if err != nil {
+			var mappingErr *MappingError
+			require.ErrorAs(t, err, &mappingErr)
+			return
+		}
+		assert.NotPanics(t, func() { dto.MustToEntity() })
+		again := UserDTOFromEntity(got)
+		gotAgain, err := again.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, again, UserDTOFromEntity(gotAgain))
+	})
+}
+
+// genmapperSession returns an entity.Session with random values of the fields mapped by SessionDTO
+func genmapperSession(tb testing.TB, r *rand.Rand) *entity.Session {
+	tb.Helper()
+	return &entity.Session{
+		ID:          valueobject.SessionID(genmapperString(r)),
+		UserID:      valueobject.MustNewUserID("user-1"),
+		CreatedAt:   genmapperTime(r),
+		UpdatedAt:   genmapperTime(r),
+		Pinned:      r.IntN(2) == 1,
+		Title:       genmapperString(r),
+		WorkspaceID: valueobject.WorkspaceID(genmapperString(r)),
+	}
+}
+
+// TestSessionDTO_RoundTrip checks that entity.Session is unchanged by a conversion to SessionDTO and back
+func TestSessionDTO_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range genmapperRuns {
+		want := genmapperSession(t, r)
+		dto := SessionDTOFromEntity(want)
+		got, err := dto.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, dto.MustToEntity())
+	}
+}
+
+// FuzzSessionDTO_ToEntity checks that SessionDTO.ToEntity rejects invalid fields without panicking
+// and that the entities it returns convert back to the same DTO
+func FuzzSessionDTO_ToEntity(f *testing.F) {
+	r := rand.New(rand.NewPCG(1, 2))
+	base := SessionDTOFromEntity(genmapperSession(f, r))
+	f.Add(base.UserID, base.CreatedAt, base.UpdatedAt)
+	f.Add("", "", "")
+	f.Fuzz(func(t *testing.T, userID, createdAt, updatedAt string) {
+		dto := *base
+		dto.UserID = userID
+		dto.CreatedAt = createdAt
+		dto.UpdatedAt = updatedAt
+		got, err := dto.ToEntity()
+		if err != nil {
+			var mappingErr *MappingError
+			require.ErrorAs(t, err, &mappingErr)
+			return
+		}
+		assert.NotPanics(t, func() { dto.MustToEntity() })
+		again := SessionDTOFromEntity(got)
+		gotAgain, err := again.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, again, SessionDTOFromEntity(gotAgain))
+	})
+}
+
+// genmapperMessage returns an entity.Message with random values of the fields mapped by MessageDTO
+func genmapperMessage(tb testing.TB, r *rand.Rand) *entity.Message {
+	tb.Helper()
+	return &entity.Message{
+		ID:        valueobject.MessageID(genmapperString(r)),
+		SessionID: valueobject.MustNewSessionID("session-1"),
+		Role:      valueobject.MustNewMessageRole("user"),
+		Content:   genmapperString(r),
+		CreatedAt: genmapperTime(r),
+	}
+}
+
+// TestMessageDTO_RoundTrip checks that entity.Message is unchanged by a conversion to MessageDTO and back
+func TestMessageDTO_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range genmapperRuns {
+		want := genmapperMessage(t, r)
+		dto := MessageDTOFromEntity(want)
+		got, err := dto.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, dto.MustToEntity())
+	}
+}
+
+// FuzzMessageDTO_ToEntity checks that MessageDTO.ToEntity rejects invalid fields without panicking
+// and that the entities it returns convert back to the same DTO
+func FuzzMessageDTO_ToEntity(f *testing.F) {
+	r := rand.New(rand.NewPCG(1, 2))
+	base := MessageDTOFromEntity(genmapperMessage(f, r))
+	f.Add(base.SessionID, base.Role, base.CreatedAt)
+	f.Add("", "", "")
+	f.Fuzz(func(t *testing.T, sessionID, role, createdAt string) {
+		dto := *base
+		dto.SessionID = sessionID
+		dto.Role = role
+		dto.CreatedAt = createdAt
+		got, err := dto.ToEntity()
+		if err != nil {
+			var mappingErr *MappingError
+			require.ErrorAs(t, err, &mappingErr)
+			return
+		}
+		assert.NotPanics(t, func() { dto.MustToEntity() })
+		again := MessageDTOFromEntity(got)
+		gotAgain, err := again.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, again, MessageDTOFromEntity(gotAgain))
+	})
+}
+
+// genmapperTask returns an entity.Task with random values of the fields mapped by TaskDTO
+func genmapperTask(tb testing.TB, r *rand.Rand) *entity.Task {
+	tb.Helper()
+	return &entity.Task{
+		ID:         valueobject.TaskID(genmapperString(r)),
+		SessionID:  valueobject.MustNewSessionID("session-1"),
+		Skill:      genmapperString(r),
+		Input:      genmapperString(r),
+		Output:     genmapperString(r),
+		Status:     valueobject.MustNewTaskStatus("running"),
+		Error:      genmapperString(r),
+		CreatedAt:  genmapperTime(r),
+		UpdatedAt:  genmapperTime(r),
+		StartedAt:  genmapperPtr(r, genmapperTime(r)),
+		FinishedAt: genmapperPtr(r, genmapperTime(r)),
+	}
+}
+
+// TestTaskDTO_RoundTrip checks that entity.Task is unchanged by a conversion to TaskDTO and back
+func TestTaskDTO_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range genmapperRuns {
+		want := genmapperTask(t, r)
+		dto := TaskDTOFromEntity(want)
+		got, err := dto.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, dto.MustToEntity())
+	}
+}
+
+// FuzzTaskDTO_ToEntity checks that TaskDTO.ToEntity rejects invalid fields without panicking
+// and that the entities it returns convert back to the same DTO
+func FuzzTaskDTO_ToEntity(f *testing.F) {
+	r := rand.New(rand.NewPCG(1, 2))
+	base := TaskDTOFromEntity(genmapperTask(f, r))
+	f.Add(base.SessionID, base.Status, base.CreatedAt, base.UpdatedAt, base.StartedAt, base.FinishedAt)
+	f.Add("", "", "", "", "", "")
+	f.Fuzz(func(t *testing.T, sessionID, status, createdAt, updatedAt, startedAt, finishedAt string) {
+		dto := *base
+		dto.SessionID = sessionID
+		dto.Status = status
+		dto.CreatedAt = createdAt
+		dto.UpdatedAt = updatedAt
+		dto.StartedAt = startedAt
+		dto.FinishedAt = finishedAt
+		got, err := dto.ToEntity()
+		if err != nil {
+			var mappingErr *MappingError
+			require.ErrorAs(t, err, &mappingErr)
+			return
+		}
+		assert.NotPanics(t, func() { dto.MustToEntity() })
+		again := TaskDTOFromEntity(got)
+		gotAgain, err := again.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, again, TaskDTOFromEntity(gotAgain))
+	})
+}
+
+// genmapperSkill returns an entity.Skill with random values of the fields mapped by SkillDTO
+func genmapperSkill(tb testing.TB, r *rand.Rand) *entity.Skill {
+	tb.Helper()
+	return &entity.Skill{
+		ID:          valueobject.SkillID(genmapperString(r)),
+		Name:        genmapperString(r),
+		Version:     valueobject.MustNewVersion("1.0.0"),
+		Location:    genmapperString(r),
+		Permissions: genmapperString(r),
+		Metadata:    genmapperString(r),
+		Disabled:    r.IntN(2) == 1,
+		WorkspaceID: valueobject.WorkspaceID(genmapperString(r)),
+		CreatedAt:   genmapperTime(r),
+	}
+}
+
+// TestSkillDTO_RoundTrip checks that entity.Skill is unchanged by a conversion to SkillDTO and back
+func TestSkillDTO_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range genmapperRuns {
+		want := genmapperSkill(t, r)
+		dto := SkillDTOFromEntity(want)
+		got, err := dto.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, dto.MustToEntity())
+	}
+}
+
+// FuzzSkillDTO_ToEntity checks that SkillDTO.ToEntity rejects invalid fields without panicking
+// and that the entities it returns convert back to the same DTO
+func FuzzSkillDTO_ToEntity(f *testing.F) {
+	r := rand.New(rand.NewPCG(1, 2))
+	base := SkillDTOFromEntity(genmapperSkill(f, r))
+	f.Add(base.Version, base.CreatedAt)
+	f.Add("", "")
+	f.Fuzz(func(t *testing.T, version, createdAt string) {
+		dto := *base
+		dto.Version = version
+		dto.CreatedAt = createdAt
+		got, err := dto.ToEntity()
+		if err != nil {
+			var mappingErr *MappingError
+			require.ErrorAs(t, err, &mappingErr)
+			return
+		}
+		assert.NotPanics(t, func() { dto.MustToEntity() })
+		again := SkillDTOFromEntity(got)
+		gotAgain, err := again.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, again, SkillDTOFromEntity(gotAgain))
+	})
+}
+
+// genmapperSchedule returns an entity.Schedule with random values of the fields mapped by ScheduleDTO
+func genmapperSchedule(tb testing.TB, r *rand.Rand) *entity.Schedule {
+	tb.Helper()
+	return &entity.Schedule{
+		ID:              valueobject.ScheduleID(genmapperString(r)),
+		Skill:           genmapperString(r),
+		Prompt:          genmapperString(r),
+		SessionID:       genmapperString(r),
+		CronExpression:  valueobject.CronExpression(genmapperString(r)),
+		Input:           genmapperString(r),
+		Enabled:         r.IntN(2) == 1,
+		Timezone:        valueobject.Timezone(genmapperString(r)),
+		JitterSeconds:   int(r.IntN(100)),
+		CreatedAt:       genmapperTime(r),
+		Type:            valueobject.ScheduleType(genmapperString(r)),
+		RunAt:           genmapperPtr(r, genmapperTime(r)),
+		MissedRunPolicy: valueobject.MissedRunPolicy(genmapperString(r)),
+		TargetConnector: genmapperString(r),
+		TargetUserID:    genmapperString(r),
+	}
+}
+
+// TestScheduleDTO_RoundTrip checks that entity.Schedule is unchanged by a conversion to ScheduleDTO and back
+func TestScheduleDTO_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range genmapperRuns {
+		want := genmapperSchedule(t, r)
+		dto := ScheduleDTOFromEntity(want)
+		got, err := dto.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, dto.MustToEntity())
+	}
+}
+
+// FuzzScheduleDTO_ToEntity checks that ScheduleDTO.ToEntity rejects invalid fields without panicking
+// and that the entities it returns convert back to the same DTO
+func FuzzScheduleDTO_ToEntity(f *testing.F) {
+	r := rand.New(rand.NewPCG(1, 2))
+	base := ScheduleDTOFromEntity(genmapperSchedule(f, r))
+	f.Add(base.CreatedAt, base.RunAt)
+	f.Add("", "")
+	f.Fuzz(func(t *testing.T, createdAt, runAt string) {
+		dto := *base
+		dto.CreatedAt = createdAt
+		dto.RunAt = runAt
+		got, err := dto.ToEntity()
+		if err != nil {
+			var mappingErr *MappingError
+			require.ErrorAs(t, err, &mappingErr)
+			return
+		}
+		assert.NotPanics(t, func() { dto.MustToEntity() })
+		again := ScheduleDTOFromEntity(got)
+		gotAgain, err := again.ToEntity()
+		require.NoError(t, err)
+		assert.Equal(t, again, ScheduleDTOFromEntity(gotAgain))
+	})
+}
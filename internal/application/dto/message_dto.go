@@ -2,11 +2,11 @@ package dto
 
 // MessageDTO represents a message data transfer object
 type MessageDTO struct {
-	ID        string `json:"id"`
-	SessionID string `json:"session_id"`
-	Role      string `json:"role"` // "user", "assistant", "system"
+	ID        string `json:"id" mapper:"id"`
+	SessionID string `json:"session_id" mapper:"vo=SessionID,sample=session-1"`
+	Role      string `json:"role" mapper:"vo=MessageRole,sample=user"` // "user", "assistant", "system"
 	Content   string `json:"content"`
-	CreatedAt string `json:"created_at"` // ISO 8601 format
+	CreatedAt string `json:"created_at" mapper:"time"` // ISO 8601 format
 }
 
 // CreateMessageRequest represents a request to create a message
@@ -16,6 +16,13 @@ type CreateMessageRequest struct {
 	Content   string `json:"content" yaml:"content"`
 }
 
+// SearchMessagesRequest represents a full-text search over message history
+type SearchMessagesRequest struct {
+	Query     string `json:"q"`                    // Words to look for; every word has to match
+	UserID    string `json:"user_id,omitempty"`    // Search only the sessions of this user
+	SessionID string `json:"session_id,omitempty"` // Search only this session
+}
+
 // MessageResponse represents a message response
 type MessageResponse struct {
 	Success bool        `json:"success"`
@@ -25,28 +32,40 @@ type MessageResponse struct {
 
 // MessagesResponse represents a list of messages response
 type MessagesResponse struct {
-	Success  bool          `json:"success"`
-	Messages []*MessageDTO `json:"messages,omitempty"`
-	Error    string        `json:"error,omitempty"`
+	Success    bool          `json:"success"`
+	Messages   []*MessageDTO `json:"messages,omitempty"`
+	NextCursor string        `json:"next_cursor,omitempty"` // Cursor for the next page, empty on the last page
+	Error      string        `json:"error,omitempty"`
 }
 
 // ChatMessage represents a chat message for LLM interaction
 type ChatMessage struct {
 	Role    string `json:"role"` // "user", "assistant", "system"
-	Content string `json:"content"`
+	Content string `json:"content" validate:"required"`
 }
 
 // SendMessageRequest represents a request to send a message (for chat flow)
 type SendMessageRequest struct {
-	UserID  string         `json:"user_id" yaml:"user_id"`
+	UserID  string         `json:"user_id" yaml:"user_id" validate:"required"`
 	Message ChatMessage    `json:"message" yaml:"message"`
 	Options MessageOptions `json:"options,omitempty" yaml:"options,omitempty"`
 }
 
+// StreamMessageRequest represents a request to send a message and stream the reply
+type StreamMessageRequest struct {
+	SessionID string         `json:"session_id,omitempty"` // Existing session to continue; empty starts a new session
+	UserID    string         `json:"user_id,omitempty"`    // Web user the new session is created for
+	Content   string         `json:"content" validate:"required"`
+	Options   MessageOptions `json:"options,omitempty"`
+}
+
 // MessageOptions represents message options
 type MessageOptions struct {
-	Model     string `json:"model,omitempty" yaml:"model,omitempty"`
-	MaxTokens int    `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
+	Provider    string   `json:"provider,omitempty" yaml:"provider,omitempty"` // Configured LLM provider to answer with; empty selects the default provider
+	Model       string   `json:"model,omitempty" yaml:"model,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty" validate:"min=0"`
+	Temperature *float64 `json:"temperature,omitempty" yaml:"temperature,omitempty" validate:"omitempty,min=0,max=2"` // Sampling temperature; empty uses the one of the provider
+	SessionID   string   `json:"session_id,omitempty" yaml:"session_id,omitempty"`                                    // Existing session to continue; empty starts a new session
 }
 
 // SendMessageResponse represents a response to send message
@@ -56,3 +75,18 @@ type SendMessageResponse struct {
 	Messages []*MessageDTO `json:"messages,omitempty"` // Full conversation
 	Error    string        `json:"error,omitempty"`
 }
+
+// Chat stream event types sent to WebSocket chat clients
+const (
+	ChatEventToken = "token"
+	ChatEventDone  = "done"
+	ChatEventError = "error"
+)
+
+// ChatStreamEvent represents an event sent to a WebSocket chat client
+type ChatStreamEvent struct {
+	Type    string      `json:"type"`              // "token", "done" or "error"
+	Content string      `json:"content,omitempty"` // Chunk of the reply (token events)
+	Message *MessageDTO `json:"message,omitempty"` // Saved reply (done events)
+	Error   string      `json:"error,omitempty"`   // Failure reason (error events)
+}
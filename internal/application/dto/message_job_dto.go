@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// MessageJobDTO represents a message sent through the asynchronous message API
+type MessageJobDTO struct {
+	ID         string `json:"id"`
+	SessionID  string `json:"session_id"`
+	Status     string `json:"status"`                // "pending", "running", "completed" or "failed"
+	Content    string `json:"content"`               // Content of the user message
+	MessageID  string `json:"message_id,omitempty"`  // ID of the reply once completed
+	Reply      string `json:"reply,omitempty"`       // Content of the reply once completed
+	Error      string `json:"error,omitempty"`       // Why the job failed
+	CreatedAt  string `json:"created_at"`            // ISO 8601 format
+	UpdatedAt  string `json:"updated_at"`            // ISO 8601 format
+	FinishedAt string `json:"finished_at,omitempty"` // ISO 8601 format, once completed or failed
+	BatchID    string `json:"batch_id,omitempty"`    // ID of the batch of the job, if any
+}
+
+// MessageJobResponse represents a message job response
+type MessageJobResponse struct {
+	Success bool           `json:"success"`
+	Job     *MessageJobDTO `json:"job,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// BatchMessageItem is a prompt of a batch message request
+type BatchMessageItem struct {
+	Content   string `json:"content"`              // Content of the user message
+	SessionID string `json:"session_id,omitempty"` // Existing session to continue; empty answers the prompt in a new session
+}
+
+// SendMessageBatchRequest represents a request to answer a batch of prompts in the background
+type SendMessageBatchRequest struct {
+	UserID  string             `json:"user_id" validate:"required"`
+	Items   []BatchMessageItem `json:"items" validate:"required"`
+	Options MessageOptions     `json:"options,omitempty"` // Options of every prompt; session_id is taken from the items
+}
+
+// MessageBatchDTO represents a batch of message jobs with the status of every prompt
+type MessageBatchDTO struct {
+	ID        string           `json:"id"`
+	Status    string           `json:"status"` // "running" until every job is finished, then "completed"
+	Total     int              `json:"total"`
+	Pending   int              `json:"pending"`
+	Running   int              `json:"running"`
+	Completed int              `json:"completed"`
+	Failed    int              `json:"failed"`
+	Items     []*MessageJobDTO `json:"items"` // Jobs in the order of the prompts
+}
+
+// MessageBatchResponse represents a message batch response
+type MessageBatchResponse struct {
+	Success bool             `json:"success"`
+	Batch   *MessageBatchDTO `json:"batch,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// MessageJobDTOFromEntity converts entity.MessageJob to MessageJobDTO
+func MessageJobDTOFromEntity(job *entity.MessageJob) *MessageJobDTO {
+	result := &MessageJobDTO{
+		ID:        job.ID,
+		SessionID: job.SessionID.String(),
+		Status:    string(job.Status),
+		Content:   job.Content,
+		MessageID: job.MessageID.String(),
+		Reply:     job.Reply,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: job.UpdatedAt.Format(time.RFC3339),
+		BatchID:   job.BatchID,
+	}
+	if job.FinishedAt != nil {
+		result.FinishedAt = job.FinishedAt.Format(time.RFC3339)
+	}
+	return result
+}
+
+// MessageBatchDTOFromEntities converts the jobs of a batch to MessageBatchDTO
+func MessageBatchDTOFromEntities(batchID string, jobs []*entity.MessageJob) *MessageBatchDTO {
+	result := &MessageBatchDTO{
+		ID:     batchID,
+		Status: "completed",
+		Total:  len(jobs),
+		Items:  make([]*MessageJobDTO, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		switch job.Status {
+		case entity.MessageJobPending:
+			result.Pending++
+		case entity.MessageJobRunning:
+			result.Running++
+		case entity.MessageJobCompleted:
+			result.Completed++
+		case entity.MessageJobFailed:
+			result.Failed++
+		}
+		if !job.Status.IsFinished() {
+			result.Status = "running"
+		}
+		result.Items = append(result.Items, MessageJobDTOFromEntity(job))
+	}
+	return result
+}
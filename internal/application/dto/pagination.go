@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest represents pagination and time filter parameters for list requests
+type PageRequest struct {
+	Limit  int    `json:"limit,omitempty"`  // Maximum number of items to return (0 = server default)
+	Offset int    `json:"offset,omitempty"` // Number of items to skip
+	Since  string `json:"since,omitempty"`  // ISO 8601, keep items created at or after this time
+	Until  string `json:"until,omitempty"`  // ISO 8601, keep items created before this time
+	Cursor string `json:"cursor,omitempty"` // Opaque cursor returned as next_cursor by the previous page
+}
+
+// EncodeCursor builds an opaque cursor pointing to the item created at createdAt with the given ID.
+func EncodeCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor.
+func DecodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
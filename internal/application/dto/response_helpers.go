@@ -90,6 +90,14 @@ func ErrorTaskResponse(err error) *TasksResponse {
 	}
 }
 
+// SuccessTaskResponse creates a success response for single Task operations
+func SuccessTaskResponse(task *TaskDTO) *TaskResponse {
+	return &TaskResponse{
+		Success: true,
+		Task:    task,
+	}
+}
+
 // SuccessTasksResponse creates a success response for Task list operations
 func SuccessTasksResponse(tasks []*TaskDTO) *TasksResponse {
 	return &TasksResponse{
@@ -162,6 +170,22 @@ func SuccessSchedulesResponse(schedules []*ScheduleDTO) *SchedulesResponse {
 	}
 }
 
+// ErrorScheduleRunsResponse creates an error response for ScheduleRuns list operations
+func ErrorScheduleRunsResponse(err error) *ScheduleRunsResponse {
+	return &ScheduleRunsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessScheduleRunsResponse creates a success response for ScheduleRuns list operations
+func SuccessScheduleRunsResponse(runs []*ScheduleRunDTO) *ScheduleRunsResponse {
+	return &ScheduleRunsResponse{
+		Success: true,
+		Runs:    runs,
+	}
+}
+
 // ErrorSkillExecutionResponse creates an error response for SkillExecution operations
 func ErrorSkillExecutionResponse(err error) *SkillExecutionResponse {
 	return &SkillExecutionResponse{
@@ -194,3 +218,493 @@ func SuccessSendMessageResponse(message *MessageDTO, messages []*MessageDTO) *Se
 		Messages: messages,
 	}
 }
+
+// ErrorBackupResponse creates an error response for Backup operations
+func ErrorBackupResponse(err error) *BackupResponse {
+	return &BackupResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessBackupResponse creates a success response for Backup operations
+func SuccessBackupResponse(backup *BackupDTO) *BackupResponse {
+	return &BackupResponse{
+		Success: true,
+		Backup:  backup,
+	}
+}
+
+// ErrorBackupsResponse creates an error response for Backups list operations
+func ErrorBackupsResponse(err error) *BackupsResponse {
+	return &BackupsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessBackupsResponse creates a success response for Backups list operations
+func SuccessBackupsResponse(backups []*BackupDTO) *BackupsResponse {
+	return &BackupsResponse{
+		Success: true,
+		Backups: backups,
+	}
+}
+
+// ErrorSessionAttributeResponse creates an error response for SessionAttribute operations
+func ErrorSessionAttributeResponse(err error) *SessionAttributeResponse {
+	return &SessionAttributeResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessSessionAttributeResponse creates a success response for SessionAttribute operations
+func SuccessSessionAttributeResponse(attr *SessionAttributeDTO) *SessionAttributeResponse {
+	return &SessionAttributeResponse{
+		Success:   true,
+		Attribute: attr,
+	}
+}
+
+// ErrorSessionAttributesResponse creates an error response for SessionAttributes list operations
+func ErrorSessionAttributesResponse(err error) *SessionAttributesResponse {
+	return &SessionAttributesResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessSessionAttributesResponse creates a success response for SessionAttributes list operations
+func SuccessSessionAttributesResponse(attrs []*SessionAttributeDTO) *SessionAttributesResponse {
+	return &SessionAttributesResponse{
+		Success:    true,
+		Attributes: attrs,
+	}
+}
+
+// ErrorSessionExportResponse creates an error response for SessionExport operations
+func ErrorSessionExportResponse(err error) *SessionExportResponse {
+	return &SessionExportResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessSessionExportResponse creates a success response for SessionExport operations
+func SuccessSessionExportResponse(export *SessionExportDTO) *SessionExportResponse {
+	return &SessionExportResponse{
+		Success: true,
+		Export:  export,
+	}
+}
+
+// ErrorHandoffResponse creates an error response for session handoff operations
+func ErrorHandoffResponse(err error) *HandoffResponse {
+	return &HandoffResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessHandoffResponse creates a success response for session handoff operations
+func SuccessHandoffResponse(handoff *SessionHandoffDTO) *HandoffResponse {
+	return &HandoffResponse{
+		Success: true,
+		Handoff: handoff,
+	}
+}
+
+// ErrorWorkspaceResponse creates an error response for Workspace operations
+func ErrorWorkspaceResponse(err error) *WorkspaceResponse {
+	return &WorkspaceResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessWorkspaceResponse creates a success response for Workspace operations
+func SuccessWorkspaceResponse(workspace *WorkspaceDTO) *WorkspaceResponse {
+	return &WorkspaceResponse{
+		Success:   true,
+		Workspace: workspace,
+	}
+}
+
+// ErrorWorkspacesResponse creates an error response for Workspaces list operations
+func ErrorWorkspacesResponse(err error) *WorkspacesResponse {
+	return &WorkspacesResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessWorkspacesResponse creates a success response for Workspaces list operations
+func SuccessWorkspacesResponse(workspaces []*WorkspaceDTO) *WorkspacesResponse {
+	return &WorkspacesResponse{
+		Success:    true,
+		Workspaces: workspaces,
+	}
+}
+
+// ErrorUserPreferencesResponse creates an error response for UserPreferences operations
+func ErrorUserPreferencesResponse(err error) *UserPreferencesResponse {
+	return &UserPreferencesResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessUserPreferencesResponse creates a success response for UserPreferences operations
+func SuccessUserPreferencesResponse(prefs *UserPreferencesDTO) *UserPreferencesResponse {
+	return &UserPreferencesResponse{
+		Success:     true,
+		Preferences: prefs,
+	}
+}
+
+// ErrorFeedbackResponse creates an error response for message feedback operations
+func ErrorFeedbackResponse(err error) *FeedbackResponse {
+	return &FeedbackResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessFeedbackResponse creates a success response for message feedback operations
+func SuccessFeedbackResponse(feedback *MessageFeedbackDTO) *FeedbackResponse {
+	return &FeedbackResponse{
+		Success:  true,
+		Feedback: feedback,
+	}
+}
+
+// ErrorMessageJobResponse creates an error response for message job operations
+func ErrorMessageJobResponse(err error) *MessageJobResponse {
+	return &MessageJobResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessMessageJobResponse creates a success response for message job operations
+func SuccessMessageJobResponse(job *MessageJobDTO) *MessageJobResponse {
+	return &MessageJobResponse{
+		Success: true,
+		Job:     job,
+	}
+}
+
+// ErrorMessageBatchResponse creates an error response for message batch operations
+func ErrorMessageBatchResponse(err error) *MessageBatchResponse {
+	return &MessageBatchResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessMessageBatchResponse creates a success response for message batch operations
+func SuccessMessageBatchResponse(batch *MessageBatchDTO) *MessageBatchResponse {
+	return &MessageBatchResponse{
+		Success: true,
+		Batch:   batch,
+	}
+}
+
+// ErrorFeedbackAnalyticsResponse creates an error response for feedback analytics operations
+func ErrorFeedbackAnalyticsResponse(err error) *FeedbackAnalyticsResponse {
+	return &FeedbackAnalyticsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// ErrorAnalyticsResponse creates an error response for Analytics operations
+func ErrorAnalyticsResponse(err error) *AnalyticsResponse {
+	return &AnalyticsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// ErrorUsageResponse creates an error response for LLM usage operations
+func ErrorUsageResponse(err error) *UsageResponse {
+	return &UsageResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// ErrorUserDataExportResponse creates an error response for user data export operations
+func ErrorUserDataExportResponse(err error) *UserDataExportResponse {
+	return &UserDataExportResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessUserDataExportResponse creates a success response for user data export operations
+func SuccessUserDataExportResponse(export *SessionExportDTO) *UserDataExportResponse {
+	return &UserDataExportResponse{
+		Success: true,
+		Export:  export,
+	}
+}
+
+// ErrorUserDataErasureResponse creates an error response for user data erasure operations
+func ErrorUserDataErasureResponse(err error) *UserDataErasureResponse {
+	return &UserDataErasureResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessUserDataErasureResponse creates a success response for user data erasure operations
+func SuccessUserDataErasureResponse(erasure *UserDataErasureDTO) *UserDataErasureResponse {
+	return &UserDataErasureResponse{
+		Success: true,
+		Erasure: erasure,
+	}
+}
+
+// ErrorUserDataErasuresResponse creates an error response for user data erasure list operations
+func ErrorUserDataErasuresResponse(err error) *UserDataErasuresResponse {
+	return &UserDataErasuresResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessUserDataErasuresResponse creates a success response for user data erasure list operations
+func SuccessUserDataErasuresResponse(erasures []*UserDataErasureDTO) *UserDataErasuresResponse {
+	return &UserDataErasuresResponse{
+		Success:  true,
+		Erasures: erasures,
+	}
+}
+
+// ErrorAPIKeyResponse creates an error response for APIKey operations
+func ErrorAPIKeyResponse(err error) *APIKeyResponse {
+	return &APIKeyResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessAPIKeyResponse creates a success response for APIKey operations
+func SuccessAPIKeyResponse(apiKey *APIKeyDTO) *APIKeyResponse {
+	return &APIKeyResponse{
+		Success: true,
+		APIKey:  apiKey,
+	}
+}
+
+// ErrorAPIKeysResponse creates an error response for APIKeys list operations
+func ErrorAPIKeysResponse(err error) *APIKeysResponse {
+	return &APIKeysResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessAPIKeysResponse creates a success response for APIKeys list operations
+func SuccessAPIKeysResponse(apiKeys []*APIKeyDTO) *APIKeysResponse {
+	return &APIKeysResponse{
+		Success: true,
+		APIKeys: apiKeys,
+	}
+}
+
+// ErrorConnectorResponse creates an error response for Connector operations
+func ErrorConnectorResponse(err error) *ConnectorResponse {
+	return &ConnectorResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessConnectorResponse creates a success response for Connector operations
+func SuccessConnectorResponse(connector *ConnectorDTO) *ConnectorResponse {
+	return &ConnectorResponse{
+		Success:   true,
+		Connector: connector,
+	}
+}
+
+// SuccessConnectorsResponse creates a success response for Connectors list operations
+func SuccessConnectorsResponse(connectors []*ConnectorDTO) *ConnectorsResponse {
+	return &ConnectorsResponse{
+		Success:    true,
+		Connectors: connectors,
+	}
+}
+
+// SuccessRouterStatsResponse creates a success response for router statistics
+func SuccessRouterStatsResponse(stats *RouterStatsDTO) *RouterStatsResponse {
+	return &RouterStatsResponse{
+		Success: true,
+		Stats:   stats,
+	}
+}
+
+// SuccessLLMProvidersResponse creates a success response for LLM provider list operations
+func SuccessLLMProvidersResponse(active string, providers []*LLMProviderDTO) *LLMProvidersResponse {
+	return &LLMProvidersResponse{
+		Success:   true,
+		Active:    active,
+		Providers: providers,
+	}
+}
+
+// SuccessWebhookEndpointsResponse creates a success response for webhook endpoint list operations
+func SuccessWebhookEndpointsResponse(enabled bool, endpoints []*WebhookEndpointDTO) *WebhookEndpointsResponse {
+	return &WebhookEndpointsResponse{
+		Success:   true,
+		Enabled:   enabled,
+		Endpoints: endpoints,
+	}
+}
+
+// ErrorWebhookDeliveriesResponse creates an error response for webhook delivery list operations
+func ErrorWebhookDeliveriesResponse(err error) *WebhookDeliveriesResponse {
+	return &WebhookDeliveriesResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessWebhookDeliveriesResponse creates a success response for webhook delivery list operations
+func SuccessWebhookDeliveriesResponse(deliveries []*WebhookDeliveryDTO) *WebhookDeliveriesResponse {
+	return &WebhookDeliveriesResponse{
+		Success:    true,
+		Deliveries: deliveries,
+	}
+}
+
+// ErrorMessageDeadLettersResponse creates an error response for message dead letter list operations
+func ErrorMessageDeadLettersResponse(err error) *MessageDeadLettersResponse {
+	return &MessageDeadLettersResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessMessageDeadLettersResponse creates a success response for message dead letter list operations
+func SuccessMessageDeadLettersResponse(letters []*MessageDeadLetterDTO) *MessageDeadLettersResponse {
+	return &MessageDeadLettersResponse{
+		Success:     true,
+		DeadLetters: letters,
+	}
+}
+
+// ErrorMessageReplayResponse creates an error response for message replay operations
+func ErrorMessageReplayResponse(err error) *MessageReplayResponse {
+	return &MessageReplayResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// ErrorDeadLetterResponse creates an error response for dead letter operations
+func ErrorDeadLetterResponse(err error) *DeadLetterResponse {
+	return &DeadLetterResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// ErrorInstructionsResponse creates an error response for global instructions operations
+func ErrorInstructionsResponse(err error) *InstructionsResponse {
+	return &InstructionsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessInstructionsResponse creates a success response for global instructions operations
+func SuccessInstructionsResponse(instructions string) *InstructionsResponse {
+	return &InstructionsResponse{
+		Success:      true,
+		Instructions: instructions,
+	}
+}
+
+// ErrorPromptFragmentResponse creates an error response for prompt fragment operations
+func ErrorPromptFragmentResponse(err error) *PromptFragmentResponse {
+	return &PromptFragmentResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessPromptFragmentResponse creates a success response for prompt fragment operations
+func SuccessPromptFragmentResponse(fragment *PromptFragmentDTO) *PromptFragmentResponse {
+	return &PromptFragmentResponse{
+		Success:  true,
+		Fragment: fragment,
+	}
+}
+
+// ErrorPromptFragmentsResponse creates an error response for prompt fragments list operations
+func ErrorPromptFragmentsResponse(err error) *PromptFragmentsResponse {
+	return &PromptFragmentsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessPromptFragmentsResponse creates a success response for prompt fragments list operations
+func SuccessPromptFragmentsResponse(fragments []*PromptFragmentDTO) *PromptFragmentsResponse {
+	return &PromptFragmentsResponse{
+		Success:   true,
+		Fragments: fragments,
+	}
+}
+
+// ErrorLogResponse creates an error response for log operations
+func ErrorLogResponse(err error) *LogResponse {
+	return &LogResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessLogResponse creates a success response for log operations
+func SuccessLogResponse(log *LogDTO) *LogResponse {
+	return &LogResponse{
+		Success: true,
+		Log:     log,
+	}
+}
+
+// ErrorLogsResponse creates an error response for log list operations
+func ErrorLogsResponse(err error) *LogsResponse {
+	return &LogsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessLogsResponse creates a success response for log list operations
+func SuccessLogsResponse(logs []*LogDTO) *LogsResponse {
+	return &LogsResponse{
+		Success: true,
+		Logs:    logs,
+	}
+}
+
+// ErrorCrashReportsResponse creates an error response for crash report list operations
+func ErrorCrashReportsResponse(err error) *CrashReportsResponse {
+	return &CrashReportsResponse{
+		Success: false,
+		Error:   fmt.Sprintf("operation failed: %v", err),
+	}
+}
+
+// SuccessCrashReportsResponse creates a success response for crash report list operations
+func SuccessCrashReportsResponse(crashes []*CrashReportDTO) *CrashReportsResponse {
+	return &CrashReportsResponse{
+		Success: true,
+		Crashes: crashes,
+	}
+}
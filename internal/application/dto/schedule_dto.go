@@ -2,26 +2,48 @@ package dto
 
 // ScheduleDTO represents a schedule data transfer object
 type ScheduleDTO struct {
-	ID             string `json:"id"`
-	Skill          string `json:"skill"`           // Name of the skill to execute
-	CronExpression string `json:"cron_expression"` // Cron syntax (e.g., "0 * * * *")
-	Input          string `json:"input"`           // Input parameters (JSON)
-	Enabled        bool   `json:"enabled"`         // Whether schedule is active
-	CreatedAt      string `json:"created_at"`      // ISO 8601 format
+	ID              string `json:"id" mapper:"id"`
+	Skill           string `json:"skill"`                                             // Name of the skill to execute; empty for prompt schedules
+	Prompt          string `json:"prompt,omitempty"`                                  // LLM prompt template sent instead of executing a skill
+	SessionID       string `json:"session_id,omitempty"`                              // System session the prompts of the schedule are sent in
+	CronExpression  string `json:"cron_expression" mapper:"vo=CronExpression,raw"`    // Cron syntax (e.g., "0 * * * *")
+	Input           string `json:"input"`                                             // Input parameters (JSON)
+	Enabled         bool   `json:"enabled"`                                           // Whether schedule is active
+	Timezone        string `json:"timezone" mapper:"vo=Timezone,raw"`                 // IANA timezone (e.g., "Europe/Moscow")
+	JitterSeconds   int    `json:"jitter_seconds"`                                    // Maximum random start delay in seconds
+	CreatedAt       string `json:"created_at" mapper:"time"`                          // ISO 8601 format
+	Type            string `json:"type" mapper:"vo=ScheduleType,raw"`                 // "cron" (recurring) or "once" (one-shot)
+	RunAt           string `json:"run_at,omitempty" mapper:"time,optional"`           // ISO 8601 fire time of a one-shot schedule
+	MissedRunPolicy string `json:"missed_run_policy" mapper:"vo=MissedRunPolicy,raw"` // "skip", "run_once" or "run_all"
+	TargetConnector string `json:"target_connector,omitempty"`                        // Connector the run output is delivered to (e.g., "telegram")
+	TargetUserID    string `json:"target_user_id,omitempty"`                          // Connector-specific user or chat ID
 }
 
 // CreateScheduleRequest represents a request to create a schedule
 type CreateScheduleRequest struct {
-	Skill          string                 `json:"skill" yaml:"skill"`
-	CronExpression string                 `json:"cron_expression" yaml:"cron_expression"`
-	Input          map[string]interface{} `json:"input" yaml:"input"`
+	Skill           string                 `json:"skill,omitempty" yaml:"skill,omitempty" validate:"required_without=Prompt"`
+	Prompt          string                 `json:"prompt,omitempty" yaml:"prompt,omitempty"` // LLM prompt template to send instead of executing a skill
+	CronExpression  string                 `json:"cron_expression" yaml:"cron_expression" validate:"required_without=RunAt,cron"`
+	Input           map[string]interface{} `json:"input" yaml:"input"`
+	Timezone        string                 `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	JitterSeconds   int                    `json:"jitter_seconds,omitempty" yaml:"jitter_seconds,omitempty" validate:"min=0,max=3600"`
+	RunAt           string                 `json:"run_at,omitempty" yaml:"run_at,omitempty" validate:"omitempty,rfc3339"`                                           // RFC3339 time for a one-shot schedule (instead of cron_expression)
+	MissedRunPolicy string                 `json:"missed_run_policy,omitempty" yaml:"missed_run_policy,omitempty" validate:"omitempty,oneof=skip run_once run_all"` // "skip" (default), "run_once" or "run_all"
+	TargetConnector string                 `json:"target_connector,omitempty" yaml:"target_connector,omitempty"`                                                    // Connector the run output is delivered to
+	TargetUserID    string                 `json:"target_user_id,omitempty" yaml:"target_user_id,omitempty"`                                                        // Connector-specific user or chat ID
 }
 
 // UpdateScheduleRequest represents a request to update a schedule
 type UpdateScheduleRequest struct {
-	CronExpression string                 `json:"cron_expression,omitempty" yaml:"cron_expression,omitempty"`
-	Input          map[string]interface{} `json:"input,omitempty" yaml:"input,omitempty"`
-	Enabled        *bool                  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	CronExpression  string                 `json:"cron_expression,omitempty" yaml:"cron_expression,omitempty" validate:"omitempty,cron"`
+	Prompt          string                 `json:"prompt,omitempty" yaml:"prompt,omitempty"` // New prompt template of a prompt schedule
+	Input           map[string]interface{} `json:"input,omitempty" yaml:"input,omitempty"`
+	Enabled         *bool                  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Timezone        string                 `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	JitterSeconds   *int                   `json:"jitter_seconds,omitempty" yaml:"jitter_seconds,omitempty" validate:"omitempty,min=0,max=3600"`
+	MissedRunPolicy string                 `json:"missed_run_policy,omitempty" yaml:"missed_run_policy,omitempty" validate:"omitempty,oneof=skip run_once run_all"`
+	TargetConnector *string                `json:"target_connector,omitempty" yaml:"target_connector,omitempty"` // Set both target fields to empty strings to disable delivery
+	TargetUserID    *string                `json:"target_user_id,omitempty" yaml:"target_user_id,omitempty"`
 }
 
 // ScheduleResponse represents a schedule response
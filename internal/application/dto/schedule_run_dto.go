@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// ScheduleRunDTO represents a schedule run data transfer object
+type ScheduleRunDTO struct {
+	ID          string `json:"id"`
+	ScheduleID  string `json:"schedule_id"`
+	Status      string `json:"status"`                // "running", "completed", "failed"
+	ScheduledAt string `json:"scheduled_at"`          // ISO 8601 activation time the run was planned for
+	StartedAt   string `json:"started_at"`            // ISO 8601 format
+	FinishedAt  string `json:"finished_at,omitempty"` // ISO 8601 format, empty while running
+	Output      string `json:"output,omitempty"`      // Skill output if the run completed
+	Error       string `json:"error,omitempty"`       // Error message if the run failed
+}
+
+// ScheduleRunsResponse represents a list of schedule runs response
+type ScheduleRunsResponse struct {
+	Success bool              `json:"success"`
+	Runs    []*ScheduleRunDTO `json:"runs,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// ScheduleRunDTOFromEntity converts entity.ScheduleRun to ScheduleRunDTO.
+// Runs have no created_at timestamp, so this mapper is written by hand instead of generated.
+func ScheduleRunDTOFromEntity(run *entity.ScheduleRun) *ScheduleRunDTO {
+	return &ScheduleRunDTO{
+		ID:          string(run.ID),
+		ScheduleID:  string(run.ScheduleID),
+		Status:      string(run.Status),
+		ScheduledAt: run.ScheduledAt.Format(time.RFC3339),
+		StartedAt:   run.StartedAt.Format(time.RFC3339),
+		FinishedAt:  FormatOptionalTime(run.FinishedAt),
+		Output:      run.Output,
+		Error:       run.Error,
+	}
+}
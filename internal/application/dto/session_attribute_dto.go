@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// SessionAttributeDTO represents a session attribute data transfer object
+type SessionAttributeDTO struct {
+	SessionID string          `json:"session_id"`
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`                // Arbitrary JSON value
+	ExpiresAt string          `json:"expires_at,omitempty"` // ISO 8601 expiry time, empty if the attribute never expires
+	CreatedAt string          `json:"created_at"`           // ISO 8601 format
+	UpdatedAt string          `json:"updated_at"`           // ISO 8601 format
+}
+
+// SetSessionAttributeRequest represents a request to set a session attribute
+type SetSessionAttributeRequest struct {
+	Value      json.RawMessage `json:"value" yaml:"value" validate:"required"`                              // Arbitrary JSON value
+	TTLSeconds int             `json:"ttl_seconds,omitempty" yaml:"ttl_seconds,omitempty" validate:"min=0"` // Expire the attribute after this many seconds (0 = never)
+}
+
+// SessionAttributeResponse represents a single session attribute response
+type SessionAttributeResponse struct {
+	Success   bool                 `json:"success"`
+	Attribute *SessionAttributeDTO `json:"attribute,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// SessionAttributesResponse represents a list of session attributes response
+type SessionAttributesResponse struct {
+	Success    bool                   `json:"success"`
+	Attributes []*SessionAttributeDTO `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// SessionAttributeDTOFromEntity converts entity.SessionAttribute to SessionAttributeDTO.
+// Attributes have no ID and a raw JSON value, so this mapper is written by hand instead of generated.
+func SessionAttributeDTOFromEntity(attr *entity.SessionAttribute) *SessionAttributeDTO {
+	return &SessionAttributeDTO{
+		SessionID: string(attr.SessionID),
+		Key:       attr.Key,
+		Value:     json.RawMessage(attr.Value),
+		ExpiresAt: FormatOptionalTime(attr.ExpiresAt),
+		CreatedAt: attr.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: attr.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -2,15 +2,18 @@ package dto
 
 // SessionDTO represents a session data transfer object.
 type SessionDTO struct {
-	ID        string `json:"id"`         // Unique identifier for the session
-	UserID    string `json:"user_id"`    // ID of the user who owns the session
-	CreatedAt string `json:"created_at"` // ISO 8601 format timestamp when the session was created
-	UpdatedAt string `json:"updated_at"` // ISO 8601 format timestamp when the session was last updated
+	ID          string `json:"id" mapper:"id"`                           // Unique identifier for the session
+	UserID      string `json:"user_id" mapper:"vo=UserID,sample=user-1"` // ID of the user who owns the session
+	CreatedAt   string `json:"created_at" mapper:"time"`                 // ISO 8601 format timestamp when the session was created
+	UpdatedAt   string `json:"updated_at" mapper:"time"`                 // ISO 8601 format timestamp when the session was last updated
+	Pinned      bool   `json:"pinned"`                                   // Whether the session is excluded from data retention
+	Title       string `json:"title"`                                    // Short summary of the conversation, empty until generated
+	WorkspaceID string `json:"workspace_id" mapper:"vo=WorkspaceID,raw"` // Workspace of the connector the session was started through
 }
 
 // CreateSessionRequest represents a request to create a new session.
 type CreateSessionRequest struct {
-	UserID string `json:"user_id" yaml:"user_id"` // ID of the user who will own the session
+	UserID string `json:"user_id" yaml:"user_id" validate:"required"` // ID of the user who will own the session
 }
 
 // UpdateSessionRequest represents a request to update an existing session.
@@ -27,7 +30,8 @@ type SessionResponse struct {
 
 // SessionsResponse represents a response containing multiple sessions.
 type SessionsResponse struct {
-	Success  bool          `json:"success"`            // Whether the operation was successful
-	Sessions []*SessionDTO `json:"sessions,omitempty"` // List of sessions (if successful)
-	Error    string        `json:"error,omitempty"`    // Error message (if failed)
+	Success    bool          `json:"success"`               // Whether the operation was successful
+	Sessions   []*SessionDTO `json:"sessions,omitempty"`    // List of sessions (if successful)
+	NextCursor string        `json:"next_cursor,omitempty"` // Cursor for the next page, empty on the last page
+	Error      string        `json:"error,omitempty"`       // Error message (if failed)
 }
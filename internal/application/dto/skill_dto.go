@@ -2,22 +2,25 @@ package dto
 
 // SkillDTO represents a skill data transfer object
 type SkillDTO struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`        // Unique skill name
-	Version     string `json:"version"`     // Skill version
-	Location    string `json:"location"`    // Path to skill directory
-	Permissions string `json:"permissions"` // JSON array of required permissions
-	Metadata    string `json:"metadata"`    // JSON metadata (timeout, etc.)
-	CreatedAt   string `json:"created_at"`  // ISO 8601 format
+	ID          string `json:"id" mapper:"id"`
+	Name        string `json:"name"`                                               // Unique skill name
+	Version     string `json:"version" mapper:"vo=Version,sample=1.0.0"`           // Skill version
+	Location    string `json:"location"`                                           // Path to skill directory
+	Permissions string `json:"permissions"`                                        // JSON array of required permissions
+	Metadata    string `json:"metadata"`                                           // JSON metadata (timeout, etc.)
+	Disabled    bool   `json:"disabled"`                                           // Disabled skills cannot be executed
+	WorkspaceID string `json:"workspace_id,omitempty" mapper:"vo=WorkspaceID,raw"` // Workspace the skill is available in, empty for all workspaces
+	CreatedAt   string `json:"created_at" mapper:"time"`                           // ISO 8601 format
 }
 
 // CreateSkillRequest represents a request to create a skill
 type CreateSkillRequest struct {
-	Name        string                 `json:"name" yaml:"name"`
-	Version     string                 `json:"version" yaml:"version"`
-	Location    string                 `json:"location" yaml:"location"`
+	Name        string                 `json:"name" yaml:"name" validate:"required"`
+	Version     string                 `json:"version" yaml:"version" validate:"required"`
+	Location    string                 `json:"location" yaml:"location" validate:"required"`
 	Permissions []string               `json:"permissions" yaml:"permissions"`
 	Metadata    map[string]interface{} `json:"metadata" yaml:"metadata"`
+	Workspace   string                 `json:"workspace,omitempty" yaml:"workspace,omitempty"` // Limits the skill to a workspace, empty for all workspaces
 }
 
 // UpdateSkillRequest represents a request to update a skill
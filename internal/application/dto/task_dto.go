@@ -2,15 +2,17 @@ package dto
 
 // TaskDTO represents a task data transfer object
 type TaskDTO struct {
-	ID        string `json:"id"`
-	SessionID string `json:"session_id"`
-	Skill     string `json:"skill"`      // Name of the skill to execute
-	Input     string `json:"input"`      // Input parameters (JSON)
-	Output    string `json:"output"`     // Output result (JSON)
-	Status    string `json:"status"`     // "pending", "running", "completed", "failed"
-	Error     string `json:"error"`      // Error message if failed
-	CreatedAt string `json:"created_at"` // ISO 8601 format
-	UpdatedAt string `json:"updated_at"` // ISO 8601 format
+	ID         string `json:"id" mapper:"id"`
+	SessionID  string `json:"session_id" mapper:"vo=SessionID,sample=session-1"`
+	Skill      string `json:"skill"`                                        // Name of the skill to execute
+	Input      string `json:"input"`                                        // Input parameters (JSON)
+	Output     string `json:"output"`                                       // Output result (JSON)
+	Status     string `json:"status" mapper:"vo=TaskStatus,sample=running"` // "pending", "running", "completed", "failed", "cancelled"
+	Error      string `json:"error"`                                        // Error message if failed, or the reason the task was cancelled
+	CreatedAt  string `json:"created_at" mapper:"time"`                     // ISO 8601 format
+	UpdatedAt  string `json:"updated_at" mapper:"time"`                     // ISO 8601 format
+	StartedAt  string `json:"started_at,omitempty" mapper:"time,optional"`  // ISO 8601 format, empty while pending
+	FinishedAt string `json:"finished_at,omitempty" mapper:"time,optional"` // ISO 8601 format, empty until the task completes, fails or is cancelled
 }
 
 // CreateTaskRequest represents a request to create a task
@@ -36,7 +38,8 @@ type TaskResponse struct {
 
 // TasksResponse represents a list of tasks response
 type TasksResponse struct {
-	Success bool       `json:"success"`
-	Tasks   []*TaskDTO `json:"tasks,omitempty"`
-	Error   string     `json:"error,omitempty"`
+	Success    bool       `json:"success"`
+	Tasks      []*TaskDTO `json:"tasks,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"` // Cursor for the next page, empty on the last page
+	Error      string     `json:"error,omitempty"`
 }
@@ -0,0 +1,37 @@
+package dto
+
+// UsageRequest selects the UTC months of the LLM usage and how it is grouped. Without since and
+// until, the last 12 months up to the current one are returned.
+type UsageRequest struct {
+	Since     string `json:"since,omitempty"`     // First month, YYYY-MM
+	Until     string `json:"until,omitempty"`     // Last month, YYYY-MM, inclusive
+	GroupBy   string `json:"group_by,omitempty"`  // workspace (default), user, provider or model
+	Workspace string `json:"workspace,omitempty"` // Only usage of this workspace
+}
+
+// UsageTotalsDTO represents the LLM usage of a group, a month or a range of months
+type UsageTotalsDTO struct {
+	Requests     int64   `json:"requests"`      // Completed LLM calls
+	InputTokens  int64   `json:"input_tokens"`  // Prompt tokens
+	OutputTokens int64   `json:"output_tokens"` // Completion tokens
+	TotalTokens  int64   `json:"total_tokens"`  // All tokens
+	Cost         float64 `json:"cost"`          // Estimated cost in dollars
+}
+
+// UsageMonthDTO represents the LLM usage of a month, in total and per group
+type UsageMonthDTO struct {
+	Month  string                     `json:"month"`            // UTC month, YYYY-MM
+	Totals *UsageTotalsDTO            `json:"totals"`           // Usage of the month
+	Groups map[string]*UsageTotalsDTO `json:"groups,omitempty"` // Usage per workspace, user, provider or model
+}
+
+// UsageResponse represents a monthly LLM usage response
+type UsageResponse struct {
+	Success bool             `json:"success"`
+	Since   string           `json:"since,omitempty"`    // First month of the range
+	Until   string           `json:"until,omitempty"`    // Last month of the range
+	GroupBy string           `json:"group_by,omitempty"` // Grouping of the months
+	Months  []*UsageMonthDTO `json:"months,omitempty"`   // Usage of every month of the range, oldest first
+	Totals  *UsageTotalsDTO  `json:"totals,omitempty"`   // Usage of the whole range
+	Error   string           `json:"error,omitempty"`
+}
@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// UserDataExportDTO represents the "user.json" document of a user data export
+type UserDataExportDTO struct {
+	User        *UserDTO                `json:"user"`
+	Preferences *UserPreferencesDTO     `json:"preferences,omitempty"`
+	Sessions    []string                `json:"sessions"`     // IDs of the sessions, each exported as "sessions/<id>.json"
+	Events      []*UserEventExportDTO   `json:"events"`       // Stored events about the user or their sessions
+	DeadLetters []*MessageDeadLetterDTO `json:"dead_letters"` // Messages of the user the router failed to process
+	ExportedAt  string                  `json:"exported_at"`  // ISO 8601 format
+}
+
+// UserEventExportDTO represents a stored event in a user data export
+type UserEventExportDTO struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`     // Event fields
+	OccurredAt string          `json:"occurred_at"` // ISO 8601 format
+}
+
+// UserSessionExportDTO represents a "sessions/<id>.json" document of a user data export:
+// the session transcript with the session attributes
+type UserSessionExportDTO struct {
+	*SessionTranscriptDTO
+	Attributes []*SessionAttributeDTO `json:"attributes"`
+}
+
+// UserDataExportResponse represents a user data export response.
+// The export is a ZIP archive of the user document and the session documents.
+type UserDataExportResponse struct {
+	Success bool              `json:"success"`
+	Export  *SessionExportDTO `json:"export,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// EraseUserDataRequest represents a request to erase the data of a user
+type EraseUserDataRequest struct {
+	Reason      string // Reason recorded in the audit record, e.g. a ticket number
+	RequestedBy string // SHA-256 of the credential of the request, recorded in the audit record
+}
+
+// UserDataErasureDTO represents the audit record of a user data erasure
+type UserDataErasureDTO struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	RequestedBy string `json:"requested_by,omitempty"` // SHA-256 of the credential the erasure was requested with
+	Reason      string `json:"reason,omitempty"`
+	Sessions    int    `json:"sessions"`    // Sessions erased
+	Messages    int    `json:"messages"`    // Messages erased
+	Tasks       int    `json:"tasks"`       // Tasks erased
+	Attributes  int    `json:"attributes"`  // Session attributes erased
+	Preferences bool   `json:"preferences"` // Whether the user's preferences were erased
+	Verified    bool   `json:"verified"`    // Whether no data of the user was found after the erasure
+	CreatedAt   string `json:"created_at"`  // ISO 8601 format
+}
+
+// UserDataErasureResponse represents a user data erasure response
+type UserDataErasureResponse struct {
+	Success bool                `json:"success"`
+	Erasure *UserDataErasureDTO `json:"erasure,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
+
+// ListUserDataErasuresRequest represents a request to list user data erasures
+type ListUserDataErasuresRequest struct {
+	UserID string // Only erasures of this user; empty for all
+	Limit  int
+	Offset int
+}
+
+// UserDataErasuresResponse represents a list of user data erasures response
+type UserDataErasuresResponse struct {
+	Success  bool                  `json:"success"`
+	Erasures []*UserDataErasureDTO `json:"erasures,omitempty"`
+	Error    string                `json:"error,omitempty"`
+}
+
+// UserDataErasureDTOFromEntity converts entity.UserDataErasure to UserDataErasureDTO
+func UserDataErasureDTOFromEntity(erasure *entity.UserDataErasure) *UserDataErasureDTO {
+	return &UserDataErasureDTO{
+		ID:          erasure.ID,
+		UserID:      erasure.UserID,
+		RequestedBy: erasure.RequestedBy,
+		Reason:      erasure.Reason,
+		Sessions:    erasure.Sessions,
+		Messages:    erasure.Messages,
+		Tasks:       erasure.Tasks,
+		Attributes:  erasure.Attributes,
+		Preferences: erasure.Preferences,
+		Verified:    erasure.Verified,
+		CreatedAt:   erasure.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// UserEventExportDTOFromEntity converts entity.StoredEvent to UserEventExportDTO.
+// A payload that is not JSON is exported as a JSON string.
+func UserEventExportDTOFromEntity(event *entity.StoredEvent) *UserEventExportDTO {
+	payload := json.RawMessage(event.Payload)
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(event.Payload)
+	}
+	return &UserEventExportDTO{
+		ID:         event.ID,
+		Type:       event.Type,
+		Payload:    payload,
+		OccurredAt: event.OccurredAt.Format(time.RFC3339),
+	}
+}
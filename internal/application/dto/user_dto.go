@@ -2,16 +2,16 @@ package dto
 
 // UserDTO represents a user data transfer object.
 type UserDTO struct {
-	ID        string `json:"id"`         // Unique identifier for the user
-	Channel   string `json:"channel"`    // Channel type: "telegram", "discord", "web", etc.
-	ChannelID string `json:"channel_id"` // Channel-specific user identifier
-	CreatedAt string `json:"created_at"` // ISO 8601 format timestamp when the user was created
+	ID        string `json:"id" mapper:"id"`                              // Unique identifier for the user
+	Channel   string `json:"channel" mapper:"vo=Channel,sample=telegram"` // Channel type: "telegram", "discord", "web", etc.
+	ChannelID string `json:"channel_id"`                                  // Channel-specific user identifier
+	CreatedAt string `json:"created_at" mapper:"time"`                    // ISO 8601 format timestamp when the user was created
 }
 
 // CreateUserRequest represents a request to create a new user.
 type CreateUserRequest struct {
-	Channel   string `json:"channel" yaml:"channel"`       // Channel type
-	ChannelID string `json:"channel_id" yaml:"channel_id"` // Channel-specific user identifier
+	Channel   string `json:"channel" yaml:"channel" validate:"required"`       // Channel type
+	ChannelID string `json:"channel_id" yaml:"channel_id" validate:"required"` // Channel-specific user identifier
 }
 
 // UpdateUserRequest represents a request to update an existing user.
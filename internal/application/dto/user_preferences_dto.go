@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// UserPreferencesDTO represents the preferences a user chose for their replies
+type UserPreferencesDTO struct {
+	UserID       string `json:"user_id"`
+	Language     string `json:"language,omitempty"`     // Language code of the replies, e.g. "ru"
+	Model        string `json:"model,omitempty"`        // LLM model used unless a request names one
+	Timezone     string `json:"timezone,omitempty"`     // IANA time zone, e.g. "Europe/Berlin"
+	Verbosity    string `json:"verbosity,omitempty"`    // "concise", "normal" or "detailed"
+	Instructions string `json:"instructions,omitempty"` // Custom instructions passed to the LLM in every session
+	UpdatedAt    string `json:"updated_at"`             // ISO 8601 format
+}
+
+// UpdateUserPreferencesRequest represents a request to replace the preferences of a user.
+// Omitted fields are cleared.
+type UpdateUserPreferencesRequest struct {
+	Language     string `json:"language,omitempty" yaml:"language,omitempty"`
+	Model        string `json:"model,omitempty" yaml:"model,omitempty"`
+	Timezone     string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	Verbosity    string `json:"verbosity,omitempty" yaml:"verbosity,omitempty" validate:"omitempty,oneof=concise normal detailed"`
+	Instructions string `json:"instructions,omitempty" yaml:"instructions,omitempty" validate:"omitempty,max=4000"`
+}
+
+// UserPreferencesResponse represents a user preferences response
+type UserPreferencesResponse struct {
+	Success     bool                `json:"success"`
+	Preferences *UserPreferencesDTO `json:"preferences,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// UserPreferencesDTOFromEntity converts entity.UserPreferences to UserPreferencesDTO
+func UserPreferencesDTOFromEntity(prefs *entity.UserPreferences) *UserPreferencesDTO {
+	return &UserPreferencesDTO{
+		UserID:       string(prefs.UserID),
+		Language:     prefs.Language,
+		Model:        prefs.Model,
+		Timezone:     prefs.Timezone,
+		Verbosity:    string(prefs.Verbosity),
+		Instructions: prefs.Instructions,
+		UpdatedAt:    prefs.UpdatedAt.Format(time.RFC3339),
+	}
+}
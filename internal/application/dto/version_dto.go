@@ -0,0 +1,19 @@
+package dto
+
+// UpdateStatusDTO represents the result of the last check for a newer release
+type UpdateStatusDTO struct {
+	Available     bool   `json:"available"`                // A newer release than the running version exists
+	LatestVersion string `json:"latest_version,omitempty"` // Latest release, empty until a check succeeds
+	ReleaseURL    string `json:"release_url,omitempty"`    // Release page of the latest release
+	CheckedAt     string `json:"checked_at,omitempty"`     // ISO 8601 format timestamp of the last check
+	Error         string `json:"error,omitempty"`          // Failure reason of the last check
+}
+
+// VersionResponse represents the response of the version endpoint
+type VersionResponse struct {
+	Version   string           `json:"version"`              // Version of the server, "dev" for development builds
+	Commit    string           `json:"commit,omitempty"`     // Git commit the server was built from
+	BuildDate string           `json:"build_date,omitempty"` // ISO 8601 format timestamp of the build
+	GoVersion string           `json:"go_version"`           // Go version the server was built with
+	Update    *UpdateStatusDTO `json:"update,omitempty"`     // Update check result, omitted if update_check is disabled
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// WebhookEndpointDTO represents a configured webhook endpoint without its secret
+type WebhookEndpointDTO struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"` // Event types posted to the endpoint
+	Signed bool     `json:"signed"` // Whether the payloads are signed with a secret
+}
+
+// WebhookEndpointsResponse represents a list of webhook endpoints response
+type WebhookEndpointsResponse struct {
+	Success   bool                  `json:"success"`
+	Enabled   bool                  `json:"enabled"` // Whether outbound webhooks are enabled
+	Endpoints []*WebhookEndpointDTO `json:"endpoints,omitempty"`
+	Error     string                `json:"error,omitempty"`
+}
+
+// WebhookDeliveryDTO represents a delivery of an event to a webhook endpoint
+type WebhookDeliveryDTO struct {
+	ID             string `json:"id"` // Also sent in the X-Nexflow-Delivery header
+	Endpoint       string `json:"endpoint"`
+	URL            string `json:"url"`
+	EventType      string `json:"event_type"`
+	Payload        string `json:"payload"`                   // JSON body posted to the endpoint
+	Status         string `json:"status"`                    // "pending", "delivered" or "failed"
+	Attempts       int    `json:"attempts"`                  // Attempts made so far
+	ResponseStatus int    `json:"response_status,omitempty"` // HTTP status of the last response
+	Error          string `json:"error,omitempty"`           // Why the last attempt failed
+	CreatedAt      string `json:"created_at"`                // ISO 8601 format
+	UpdatedAt      string `json:"updated_at"`                // ISO 8601 format
+}
+
+// ListWebhookDeliveriesRequest represents a request to list webhook deliveries
+type ListWebhookDeliveriesRequest struct {
+	Endpoint string // Only deliveries to this endpoint; empty for all
+	Status   string // Only deliveries with this status; empty for all
+	Limit    int
+	Offset   int
+}
+
+// WebhookDeliveriesResponse represents a list of webhook deliveries response
+type WebhookDeliveriesResponse struct {
+	Success    bool                  `json:"success"`
+	Deliveries []*WebhookDeliveryDTO `json:"deliveries,omitempty"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// WebhookDeliveryDTOFromEntity converts entity.WebhookDelivery to WebhookDeliveryDTO
+func WebhookDeliveryDTOFromEntity(delivery *entity.WebhookDelivery) *WebhookDeliveryDTO {
+	return &WebhookDeliveryDTO{
+		ID:             delivery.ID,
+		Endpoint:       delivery.Endpoint,
+		URL:            delivery.URL,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      delivery.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// WorkspaceDTO represents a workspace (tenant) of the deployment
+type WorkspaceDTO struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MaxTokens int    `json:"max_tokens"` // LLM token budget of a reply, 0 for the router default
+	CreatedAt string `json:"created_at"` // ISO 8601 format
+}
+
+// CreateWorkspaceRequest represents a request to create a workspace
+type CreateWorkspaceRequest struct {
+	ID        string `json:"id" yaml:"id" validate:"required"`                                            // Identifier referenced by the connector configuration, e.g. "support"
+	Name      string `json:"name" yaml:"name" validate:"required"`                                        // Human-readable name
+	MaxTokens int    `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty" validate:"omitempty,min=0"` // LLM token budget of a reply, 0 for the router default
+}
+
+// UpdateWorkspaceRequest represents a request to update a workspace
+type UpdateWorkspaceRequest struct {
+	Name      string `json:"name" yaml:"name" validate:"required"`
+	MaxTokens int    `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty" validate:"omitempty,min=0"`
+}
+
+// WorkspaceResponse represents a single workspace response
+type WorkspaceResponse struct {
+	Success   bool          `json:"success"`
+	Workspace *WorkspaceDTO `json:"workspace,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// WorkspacesResponse represents a list of workspaces response
+type WorkspacesResponse struct {
+	Success    bool            `json:"success"`
+	Workspaces []*WorkspaceDTO `json:"workspaces,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// WorkspaceDTOFromEntity converts entity.Workspace to WorkspaceDTO
+func WorkspaceDTOFromEntity(workspace *entity.Workspace) *WorkspaceDTO {
+	return &WorkspaceDTO{
+		ID:        string(workspace.ID),
+		Name:      workspace.Name,
+		MaxTokens: workspace.MaxTokens,
+		CreatedAt: workspace.CreatedAt.Format(time.RFC3339),
+	}
+}
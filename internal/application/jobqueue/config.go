@@ -0,0 +1,90 @@
+package jobqueue
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidationError represents a job queue configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Config holds configuration for the job queue
+type Config struct {
+	// Workers is the number of jobs run concurrently by this process
+	Workers int
+
+	// PollInterval is how often idle workers look for due jobs queued by other processes or
+	// retried; jobs queued by this process wake them immediately
+	PollInterval time.Duration
+
+	// VisibilityTimeout is the lease of a running job. It is renewed while the job runs; a job
+	// whose lease expires, e.g. because its process stopped, is run again.
+	VisibilityTimeout time.Duration
+
+	// MaxAttempts is how many times a job is attempted before it fails, unless set per job
+	MaxAttempts int
+
+	// RetryBackoff is the delay before the first retry; it doubles with every retry
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the delay between retries
+	MaxRetryBackoff time.Duration
+
+	// TTL is how long finished jobs are kept
+	TTL time.Duration
+}
+
+// DefaultConfig returns the default configuration for the job queue
+func DefaultConfig() *Config {
+	return &Config{
+		Workers:           4,
+		PollInterval:      time.Second,
+		VisibilityTimeout: 5 * time.Minute,
+		MaxAttempts:       3,
+		RetryBackoff:      5 * time.Second,
+		MaxRetryBackoff:   10 * time.Minute,
+		TTL:               24 * time.Hour,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.Workers <= 0 {
+		return &ValidationError{Field: "Workers", Message: "must be positive"}
+	}
+
+	if c.PollInterval <= 0 {
+		return &ValidationError{Field: "PollInterval", Message: "must be positive"}
+	}
+
+	// The lease is renewed at a third of the visibility timeout, which needs some precision
+	if c.VisibilityTimeout < 3*time.Second {
+		return &ValidationError{Field: "VisibilityTimeout", Message: "must be at least 3s"}
+	}
+
+	if c.MaxAttempts <= 0 {
+		return &ValidationError{Field: "MaxAttempts", Message: "must be positive"}
+	}
+
+	if c.RetryBackoff < 0 {
+		return &ValidationError{Field: "RetryBackoff", Message: "must not be negative"}
+	}
+
+	if c.MaxRetryBackoff < c.RetryBackoff {
+		return &ValidationError{Field: "MaxRetryBackoff", Message: "must not be less than RetryBackoff"}
+	}
+
+	if c.TTL <= 0 {
+		return &ValidationError{Field: "TTL", Message: "must be positive"}
+	}
+
+	return nil
+}
@@ -0,0 +1,386 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// updateTimeout limits saving the outcome of a job, which is done after its context is cancelled
+const updateTimeout = 5 * time.Second
+
+// JobQueueMetrics holds all metrics for the job queue
+type JobQueueMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	JobsEnqueued  *metrics.Counter
+	JobsCompleted *metrics.Counter
+	JobsFailed    *metrics.Counter
+	JobsRetried   *metrics.Counter
+	LeasesLost    *metrics.Counter
+	JobDuration   *metrics.Histogram
+}
+
+// NewJobQueueMetrics creates a new JobQueueMetrics instance
+func NewJobQueueMetrics() *JobQueueMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &JobQueueMetrics{
+		registry: registry,
+
+		JobsEnqueued:  registry.GetCounter("job_queue_jobs_enqueued_total"),
+		JobsCompleted: registry.GetCounter("job_queue_jobs_completed_total"),
+		JobsFailed:    registry.GetCounter("job_queue_jobs_failed_total"),
+		JobsRetried:   registry.GetCounter("job_queue_jobs_retried_total"),
+		LeasesLost:    registry.GetCounter("job_queue_leases_lost_total"),
+		JobDuration:   registry.GetHistogram("job_queue_job_duration_seconds", metrics.DefaultBuckets()),
+	}
+}
+
+// Registry returns the registry holding the job queue metrics
+func (m *JobQueueMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Queue runs the jobs stored in the job repository with a pool of workers. A worker claims a
+// due job for the visibility timeout and renews the lease while the handler of its kind runs,
+// so that the job of a process that stopped is run again by another worker once its lease
+// expires. Failed attempts are retried with exponential backoff.
+type Queue struct {
+	repo    repository.JobRepository
+	logger  logging.Logger
+	config  *Config
+	metrics *JobQueueMetrics
+	owner   string
+	wake    chan struct{}
+	now     func() time.Time
+
+	leadership ports.Leadership
+
+	mu       sync.RWMutex
+	handlers map[string]ports.JobHandler
+	started  bool
+	stopping chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// Compile-time check that Queue implements ports.JobQueue
+var _ ports.JobQueue = (*Queue)(nil)
+
+// NewQueue creates a new Queue instance
+//
+// Parameters:
+//   - repo: JobRepository storing the jobs
+//   - logger: Structured logger for logging
+//   - config: Job queue configuration (uses defaults if nil)
+//
+// Returns:
+//   - *Queue: Initialized job queue
+func NewQueue(repo repository.JobRepository, logger logging.Logger, config *Config) *Queue {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	if err := config.Validate(); err != nil {
+		logger.Error("invalid job queue configuration, using defaults", "error", err)
+		config = DefaultConfig()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Queue{
+		repo:     repo,
+		logger:   logger,
+		config:   config,
+		metrics:  NewJobQueueMetrics(),
+		owner:    workerOwner(),
+		wake:     make(chan struct{}, config.Workers),
+		now:      time.Now,
+		handlers: make(map[string]ports.JobHandler),
+		stopping: make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// workerOwner returns the name the workers of this process hold leases under,
+// unique even for processes of the same host
+func workerOwner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "nexflow"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), valueobject.GenerateID(nil).String()[:8])
+}
+
+// Metrics returns the job queue metrics
+func (q *Queue) Metrics() *JobQueueMetrics {
+	return q.metrics
+}
+
+// SetLeadership makes the queue run leader-only jobs only while this instance is the leader.
+// Without leadership, e.g. for a single instance, every job is run. It must be called before
+// Start.
+func (q *Queue) SetLeadership(leadership ports.Leadership) {
+	q.leadership = leadership
+}
+
+// Register sets the handler of a kind of job
+func (q *Queue) Register(kind string, handler ports.JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = handler
+}
+
+// Enqueue stores a job of a kind with its payload encoded as JSON and wakes a worker.
+// A job whose key is already queued is left as is.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any, opts ports.JobOptions) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", kind, err)
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = q.config.MaxAttempts
+	}
+
+	job := entity.NewJob(kind, opts.Key, string(data), maxAttempts)
+	job.LeaderOnly = opts.LeaderOnly
+	if err := q.repo.Create(ctx, job); err != nil {
+		if opts.Key != "" && errors.Is(err, repository.ErrConflict) {
+			q.logger.WithContext(ctx).Debug("job already queued", "kind", kind, "job_id", job.ID)
+			return nil
+		}
+		return fmt.Errorf("failed to queue %s job: %w", kind, err)
+	}
+	q.metrics.JobsEnqueued.Inc()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+		// Every worker is already woken
+	}
+	return nil
+}
+
+// Start starts the workers. Jobs queued before, also by a previous process, are run once due.
+func (q *Queue) Start() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.started {
+		return fmt.Errorf("job queue already started")
+	}
+
+	for i := 0; i < q.config.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	q.started = true
+
+	q.logger.Info("job queue started", "workers", q.config.Workers, "owner", q.owner)
+	return nil
+}
+
+// Stop stops claiming jobs and waits for the workers to finish the jobs they are running; the
+// handlers still running when ctx is done are cancelled, and their jobs are released to be run
+// again.
+func (q *Queue) Stop(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.started {
+		q.mu.Unlock()
+		return nil
+	}
+	q.started = false
+	close(q.stopping)
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+	}
+	q.cancel()
+
+	q.logger.Info("job queue stopped")
+	return nil
+}
+
+// PurgeFinished deletes the jobs that finished longer than the TTL ago
+func (q *Queue) PurgeFinished(ctx context.Context) error {
+	n, err := q.repo.DeleteFinishedBefore(ctx, q.now().Add(-q.config.TTL))
+	if err != nil {
+		return fmt.Errorf("failed to purge finished jobs: %w", err)
+	}
+	if n > 0 {
+		q.logger.WithContext(ctx).Debug("purged finished jobs", "count", n)
+	}
+	return nil
+}
+
+// worker runs due jobs until the queue is stopped, waiting for the poll interval or a newly
+// queued job whenever none is due
+func (q *Queue) worker() {
+	defer q.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-q.stopping:
+			return
+		default:
+		}
+
+		if q.runNext() {
+			continue
+		}
+
+		timer.Reset(q.config.PollInterval)
+		select {
+		case <-q.stopping:
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// runNext claims a due job and runs it. Returns false if no job is due.
+func (q *Queue) runNext() bool {
+	now := q.now()
+	leader := q.leadership == nil || q.leadership.IsLeader()
+	job, err := q.repo.Claim(q.ctx, q.owner, leader, now, now.Add(q.config.VisibilityTimeout))
+	if errors.Is(err, repository.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		if q.ctx.Err() == nil {
+			q.logger.Error("failed to claim job", "error", err)
+		}
+		return false
+	}
+
+	q.run(job)
+	return true
+}
+
+// run runs the handler of a claimed job while renewing its lease, and saves the outcome
+func (q *Queue) run(job *entity.Job) {
+	q.mu.RLock()
+	handler := q.handlers[job.Kind]
+	q.mu.RUnlock()
+
+	ctx, cancel := context.WithCancel(q.ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		q.renew(ctx, cancel, *job)
+	}()
+
+	start := time.Now()
+	var err error
+	if handler == nil {
+		err = fmt.Errorf("%w: no handler for jobs of kind %q", ports.ErrJobPermanent, job.Kind)
+	} else {
+		err = handler(ctx, job)
+	}
+	q.metrics.JobDuration.Observe(time.Since(start).Seconds())
+
+	cancel()
+	<-renewed
+	q.finish(job, err)
+}
+
+// renew extends the lease of a running job every third of the visibility timeout until ctx is
+// done. The handler is cancelled when the lease was lost to another worker.
+func (q *Queue) renew(ctx context.Context, cancel context.CancelFunc, lease entity.Job) {
+	ticker := time.NewTicker(q.config.VisibilityTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		lockedUntil := q.now().Add(q.config.VisibilityTimeout)
+		lease.LockedUntil = &lockedUntil
+		err := q.repo.Update(ctx, &lease, q.owner)
+		switch {
+		case err == nil:
+		case errors.Is(err, repository.ErrNotFound):
+			q.metrics.LeasesLost.Inc()
+			q.logger.Warn("lease of running job lost, cancelling it", "kind", lease.Kind, "job_id", lease.ID)
+			cancel()
+			return
+		case ctx.Err() == nil:
+			q.logger.Error("failed to renew lease of job", "kind", lease.Kind, "job_id", lease.ID, "error", err)
+		}
+	}
+}
+
+// finish saves the outcome of an attempt: the job is completed, retried later or failed.
+// A job interrupted by a stop is released to be run again right away.
+func (q *Queue) finish(job *entity.Job, err error) {
+	logger := q.logger.With("kind", job.Kind, "job_id", job.ID, "attempt", job.Attempts)
+
+	switch {
+	case err == nil:
+		job.Complete()
+		q.metrics.JobsCompleted.Inc()
+		logger.Debug("job completed")
+	case q.ctx.Err() != nil:
+		job.Retry("interrupted by shutdown: "+err.Error(), q.now())
+		logger.Info("job interrupted by shutdown, released", "error", err)
+	case errors.Is(err, ports.ErrJobPermanent) || !job.CanRetry():
+		job.Fail(err.Error())
+		q.metrics.JobsFailed.Inc()
+		logger.Warn("job failed", "error", err)
+	default:
+		delay := q.delay(job.Attempts)
+		job.Retry(err.Error(), q.now().Add(delay))
+		q.metrics.JobsRetried.Inc()
+		logger.Warn("job attempt failed, retrying", "error", err, "delay", delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancel()
+	if err := q.repo.Update(ctx, job, q.owner); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			q.metrics.LeasesLost.Inc()
+		}
+		logger.Error("failed to save job", "status", job.Status, "error", err)
+	}
+}
+
+// delay returns the backoff before the retry following an attempt, doubling from RetryBackoff
+// and capped by MaxRetryBackoff
+func (q *Queue) delay(attempt int) time.Duration {
+	delay := q.config.RetryBackoff
+	for i := 1; i < attempt && delay < q.config.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, q.config.MaxRetryBackoff)
+}
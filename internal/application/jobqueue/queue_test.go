@@ -0,0 +1,372 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockJobRepository is an in-memory repository.JobRepository
+type mockJobRepository struct {
+	mu   sync.Mutex
+	jobs map[string]entity.Job
+}
+
+func newMockJobRepository() *mockJobRepository {
+	return &mockJobRepository{jobs: map[string]entity.Job{}}
+}
+
+func (m *mockJobRepository) Create(ctx context.Context, job *entity.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.jobs[job.ID]; ok {
+		return repository.ErrConflict
+	}
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *mockJobRepository) FindByID(ctx context.Context, id string) (*entity.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &job, nil
+}
+
+func (m *mockJobRepository) Claim(ctx context.Context, owner string, leader bool, now, lockedUntil time.Time) (*entity.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []entity.Job
+	for _, job := range m.jobs {
+		if job.LeaderOnly && !leader {
+			continue
+		}
+		if (job.Status == entity.JobPending && !job.RunAt.After(now)) ||
+			(job.Status == entity.JobRunning && !job.LockedUntil.After(now)) {
+			due = append(due, job)
+		}
+	}
+	if len(due) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].RunAt.Before(due[j].RunAt) })
+
+	job := due[0]
+	job.Status = entity.JobRunning
+	job.Attempts++
+	job.LockedBy = owner
+	job.LockedUntil = &lockedUntil
+	m.jobs[job.ID] = job
+	return &job, nil
+}
+
+func (m *mockJobRepository) Update(ctx context.Context, job *entity.Job, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.jobs[job.ID]
+	if !ok || stored.Status != entity.JobRunning || stored.LockedBy != owner {
+		return repository.ErrNotFound
+	}
+	stored.Status, stored.Error, stored.RunAt = job.Status, job.Error, job.RunAt
+	stored.LockedBy, stored.LockedUntil, stored.FinishedAt = job.LockedBy, job.LockedUntil, job.FinishedAt
+	m.jobs[job.ID] = stored
+	return nil
+}
+
+func (m *mockJobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for id, job := range m.jobs {
+		if job.Status.IsFinished() && !job.FinishedAt.After(before) {
+			delete(m.jobs, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// get returns a copy of a stored job
+func (m *mockJobRepository) get(id string) entity.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.jobs[id]
+}
+
+// only returns the single stored job
+func (m *mockJobRepository) only(t *testing.T) entity.Job {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Len(t, m.jobs, 1)
+	for _, job := range m.jobs {
+		return job
+	}
+	return entity.Job{}
+}
+
+func testConfig() *Config {
+	return &Config{
+		Workers:           2,
+		PollInterval:      10 * time.Millisecond,
+		VisibilityTimeout: 3 * time.Second,
+		MaxAttempts:       3,
+		RetryBackoff:      0,
+		MaxRetryBackoff:   0,
+		TTL:               time.Hour,
+	}
+}
+
+// startQueue starts a queue over repo that is stopped at the end of the test
+func startQueue(t *testing.T, repo *mockJobRepository, config *Config, kind string, handler ports.JobHandler) *Queue {
+	t.Helper()
+	q := NewQueue(repo, logging.NewNoopLogger(), config)
+	if handler != nil {
+		q.Register(kind, handler)
+	}
+	require.NoError(t, q.Start())
+	t.Cleanup(func() { _ = q.Stop(context.Background()) })
+	return q
+}
+
+// waitFinished waits until the single job of repo is finished
+func waitFinished(t *testing.T, repo *mockJobRepository) entity.Job {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return repo.only(t).Status.IsFinished()
+	}, 2*time.Second, 5*time.Millisecond)
+	return repo.only(t)
+}
+
+func TestQueue_RunsJob(t *testing.T) {
+	repo := newMockJobRepository()
+	payloads := make(chan string, 1)
+	q := startQueue(t, repo, testConfig(), "greet", func(ctx context.Context, job *entity.Job) error {
+		payloads <- job.Payload
+		return nil
+	})
+
+	require.NoError(t, q.Enqueue(context.Background(), "greet", map[string]string{"name": "Ada"}, ports.JobOptions{}))
+
+	job := waitFinished(t, repo)
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Equal(t, 3, job.MaxAttempts)
+	assert.Empty(t, job.LockedBy)
+	assert.JSONEq(t, `{"name": "Ada"}`, <-payloads)
+	assert.Equal(t, int64(1), q.Metrics().JobsCompleted.Get())
+}
+
+func TestQueue_RetriesFailedAttempt(t *testing.T) {
+	repo := newMockJobRepository()
+	var calls atomic.Int32
+	q := startQueue(t, repo, testConfig(), "flaky", func(ctx context.Context, job *entity.Job) error {
+		if calls.Add(1) < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+
+	require.NoError(t, q.Enqueue(context.Background(), "flaky", nil, ports.JobOptions{}))
+
+	job := waitFinished(t, repo)
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, 3, job.Attempts)
+	assert.Equal(t, int64(2), q.Metrics().JobsRetried.Get())
+}
+
+func TestQueue_FailsAfterLastAttempt(t *testing.T) {
+	repo := newMockJobRepository()
+	q := startQueue(t, repo, testConfig(), "broken", func(ctx context.Context, job *entity.Job) error {
+		return errors.New("connection reset")
+	})
+
+	require.NoError(t, q.Enqueue(context.Background(), "broken", nil, ports.JobOptions{MaxAttempts: 2}))
+
+	job := waitFinished(t, repo)
+	assert.Equal(t, entity.JobFailed, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, "connection reset", job.Error)
+	assert.NotNil(t, job.FinishedAt)
+}
+
+func TestQueue_PermanentErrorNotRetried(t *testing.T) {
+	repo := newMockJobRepository()
+	q := startQueue(t, repo, testConfig(), "invalid", func(ctx context.Context, job *entity.Job) error {
+		return fmt.Errorf("%w: invalid payload", ports.ErrJobPermanent)
+	})
+
+	require.NoError(t, q.Enqueue(context.Background(), "invalid", nil, ports.JobOptions{}))
+
+	job := waitFinished(t, repo)
+	assert.Equal(t, entity.JobFailed, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+}
+
+func TestQueue_UnknownKindFails(t *testing.T) {
+	repo := newMockJobRepository()
+	q := startQueue(t, repo, testConfig(), "", nil)
+
+	require.NoError(t, q.Enqueue(context.Background(), "unknown", nil, ports.JobOptions{}))
+
+	job := waitFinished(t, repo)
+	assert.Equal(t, entity.JobFailed, job.Status)
+	assert.Contains(t, job.Error, `no handler for jobs of kind "unknown"`)
+}
+
+func TestQueue_KeyQueuedOnce(t *testing.T) {
+	repo := newMockJobRepository()
+	q := NewQueue(repo, logging.NewNoopLogger(), testConfig())
+
+	require.NoError(t, q.Enqueue(context.Background(), "schedule_run", nil, ports.JobOptions{Key: "schedule:1:100"}))
+	require.NoError(t, q.Enqueue(context.Background(), "schedule_run", nil, ports.JobOptions{Key: "schedule:1:100"}))
+
+	assert.Equal(t, "schedule:1:100", repo.only(t).ID)
+	assert.Equal(t, int64(1), q.Metrics().JobsEnqueued.Get())
+}
+
+func TestQueue_RunsJobOfStoppedProcess(t *testing.T) {
+	// A job left running by a process that stopped is run again once its lease expired
+	repo := newMockJobRepository()
+	job := entity.NewJob("resume", "", "{}", 1)
+	expired := time.Now().Add(-time.Minute)
+	job.Status, job.Attempts, job.LockedBy, job.LockedUntil = entity.JobRunning, 1, "stopped-process", &expired
+	require.NoError(t, repo.Create(context.Background(), job))
+
+	startQueue(t, repo, testConfig(), "resume", func(ctx context.Context, job *entity.Job) error {
+		return nil
+	})
+
+	finished := waitFinished(t, repo)
+	assert.Equal(t, entity.JobCompleted, finished.Status)
+	assert.Equal(t, 2, finished.Attempts)
+}
+
+func TestQueue_StopReleasesInterruptedJob(t *testing.T) {
+	repo := newMockJobRepository()
+	running := make(chan struct{})
+	q := NewQueue(repo, logging.NewNoopLogger(), testConfig())
+	q.Register("slow", func(ctx context.Context, job *entity.Job) error {
+		close(running)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, q.Start())
+	require.NoError(t, q.Enqueue(context.Background(), "slow", nil, ports.JobOptions{}))
+	<-running
+
+	// Act: the stop times out, cancelling the handler
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, q.Stop(ctx))
+
+	// Assert: the job is due again for the next process
+	job := repo.only(t)
+	assert.Equal(t, entity.JobPending, job.Status)
+	assert.Contains(t, job.Error, "interrupted by shutdown")
+	assert.Empty(t, job.LockedBy)
+}
+
+// leadership is a ports.Leadership switched by the test
+type leadership struct {
+	leader atomic.Bool
+}
+
+func (l *leadership) IsLeader() bool {
+	return l.leader.Load()
+}
+
+func TestQueue_LeaderOnlyJob(t *testing.T) {
+	repo := newMockJobRepository()
+	ran := make(chan struct{}, 1)
+	l := &leadership{}
+	q := NewQueue(repo, logging.NewNoopLogger(), testConfig())
+	q.SetLeadership(l)
+	q.Register("send", func(ctx context.Context, job *entity.Job) error {
+		ran <- struct{}{}
+		return nil
+	})
+	require.NoError(t, q.Start())
+	t.Cleanup(func() { _ = q.Stop(context.Background()) })
+
+	require.NoError(t, q.Enqueue(context.Background(), "send", nil, ports.JobOptions{LeaderOnly: true}))
+	assert.True(t, repo.only(t).LeaderOnly)
+
+	select {
+	case <-ran:
+		t.Fatal("leader-only job run by a follower")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Act: the instance becomes the leader
+	l.leader.Store(true)
+
+	job := waitFinished(t, repo)
+	assert.Equal(t, entity.JobCompleted, job.Status)
+}
+
+func TestQueue_PurgeFinished(t *testing.T) {
+	repo := newMockJobRepository()
+	q := NewQueue(repo, logging.NewNoopLogger(), testConfig())
+
+	old := entity.NewJob("done", "old", "{}", 1)
+	old.Complete()
+	finishedAt := time.Now().Add(-2 * time.Hour)
+	old.FinishedAt = &finishedAt
+	recent := entity.NewJob("done", "recent", "{}", 1)
+	recent.Complete()
+	pending := entity.NewJob("done", "pending", "{}", 1)
+	for _, job := range []*entity.Job{old, recent, pending} {
+		require.NoError(t, repo.Create(context.Background(), job))
+	}
+
+	require.NoError(t, q.PurgeFinished(context.Background()))
+
+	assert.Empty(t, repo.get("old").ID)
+	assert.Equal(t, "recent", repo.get("recent").ID)
+	assert.Equal(t, "pending", repo.get("pending").ID)
+}
+
+func TestQueue_Delay(t *testing.T) {
+	config := testConfig()
+	config.RetryBackoff, config.MaxRetryBackoff = time.Second, 5*time.Second
+	q := NewQueue(newMockJobRepository(), logging.NewNoopLogger(), config)
+
+	assert.Equal(t, time.Second, q.delay(1))
+	assert.Equal(t, 2*time.Second, q.delay(2))
+	assert.Equal(t, 4*time.Second, q.delay(3))
+	assert.Equal(t, 5*time.Second, q.delay(4), "capped by MaxRetryBackoff")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, DefaultConfig().Validate())
+
+	config := DefaultConfig()
+	config.Workers = 0
+	assert.Error(t, config.Validate())
+
+	config = DefaultConfig()
+	config.VisibilityTimeout = time.Second
+	assert.Error(t, config.Validate())
+
+	config = DefaultConfig()
+	config.MaxRetryBackoff = time.Second
+	assert.Error(t, config.Validate())
+}
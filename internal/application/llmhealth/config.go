@@ -0,0 +1,64 @@
+package llmhealth
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidationError represents a health check configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Config holds configuration for the availability checks of the LLM providers
+type Config struct {
+	// Interval is how often every provider is checked
+	Interval time.Duration
+
+	// Timeout limits a check; a provider that does not answer in time is unavailable
+	Timeout time.Duration
+
+	// History is the number of checks kept per provider
+	History int
+
+	// FailureThreshold is the number of consecutive failed checks after which a provider is
+	// unhealthy; it is healthy again after its next successful check
+	FailureThreshold int
+}
+
+// DefaultConfig returns the default configuration for the health checks
+func DefaultConfig() *Config {
+	return &Config{
+		Interval:         time.Minute,
+		Timeout:          10 * time.Second,
+		History:          20,
+		FailureThreshold: 2,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.Interval <= 0 {
+		return &ValidationError{Field: "Interval", Message: "must be positive"}
+	}
+
+	if c.Timeout <= 0 {
+		return &ValidationError{Field: "Timeout", Message: "must be positive"}
+	}
+
+	if c.History <= 0 {
+		return &ValidationError{Field: "History", Message: "must be positive"}
+	}
+
+	if c.FailureThreshold <= 0 {
+		return &ValidationError{Field: "FailureThreshold", Message: "must be positive"}
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package llmhealth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// HealthMetrics holds the availability metrics of the LLM providers, labelled by provider name
+type HealthMetrics struct {
+	registry *metrics.MetricsRegistry
+}
+
+// NewHealthMetrics creates a new HealthMetrics instance
+func NewHealthMetrics() *HealthMetrics {
+	return &HealthMetrics{registry: metrics.NewMetricsRegistry()}
+}
+
+// Available returns the gauge set to 1 while a provider passes its checks
+func (m *HealthMetrics) Available(provider string) *metrics.Gauge {
+	return m.registry.GetGauge(fmt.Sprintf("llm_provider_available{provider=%q}", provider))
+}
+
+// FailedChecks returns the failed check counter of a provider
+func (m *HealthMetrics) FailedChecks(provider string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("llm_provider_checks_failed_total{provider=%q}", provider))
+}
+
+// CheckDuration returns the check duration histogram of a provider
+func (m *HealthMetrics) CheckDuration(provider string) *metrics.Histogram {
+	return m.registry.GetHistogram(fmt.Sprintf("llm_provider_check_duration_seconds{provider=%q}", provider), metrics.DefaultBuckets())
+}
+
+// Registry returns the registry holding the health metrics
+func (m *HealthMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Monitor checks the availability of every added LLM provider each interval and keeps the
+// recent checks. A provider is unhealthy once it failed the configured number of consecutive
+// checks, and healthy again after its next successful check.
+type Monitor struct {
+	logger  logging.Logger
+	config  *Config
+	metrics *HealthMetrics
+	now     func() time.Time
+
+	mu        sync.RWMutex
+	providers map[string]ports.LLMProviderStatus
+	history   map[string][]ports.LLMProviderCheck
+	failures  map[string]int
+	started   bool
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// Compile-time check that Monitor implements ports.LLMProviderHealth
+var _ ports.LLMProviderHealth = (*Monitor)(nil)
+
+// NewMonitor creates a new Monitor instance
+//
+// Parameters:
+//   - logger: Structured logger for logging
+//   - config: Health check configuration (uses defaults if nil)
+//
+// Returns:
+//   - *Monitor: Initialized monitor without providers
+func NewMonitor(logger logging.Logger, config *Config) *Monitor {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	if err := config.Validate(); err != nil {
+		logger.Error("invalid LLM health check configuration, using defaults", "error", err)
+		config = DefaultConfig()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Monitor{
+		logger:    logger,
+		config:    config,
+		metrics:   NewHealthMetrics(),
+		now:       time.Now,
+		providers: make(map[string]ports.LLMProviderStatus),
+		history:   make(map[string][]ports.LLMProviderCheck),
+		failures:  make(map[string]int),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+}
+
+// Metrics returns the health metrics
+func (m *Monitor) Metrics() *HealthMetrics {
+	return m.metrics
+}
+
+// Add adds a provider to check under name. It must be called before Start.
+func (m *Monitor) Add(name string, provider ports.LLMProviderStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providers[name] = provider
+}
+
+// Start checks the providers right away in the background, and then each interval
+func (m *Monitor) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started {
+		return fmt.Errorf("LLM health monitor already started")
+	}
+	m.started = true
+	go m.loop()
+
+	m.logger.Info("LLM health monitor started", "providers", len(m.providers), "interval", m.config.Interval)
+	return nil
+}
+
+// Stop stops the checks, cancelling those running
+func (m *Monitor) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.started {
+		m.mu.Unlock()
+		return nil
+	}
+	m.started = false
+	m.mu.Unlock()
+
+	m.cancel()
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to stop LLM health monitor: %w", ctx.Err())
+	}
+
+	m.logger.Info("LLM health monitor stopped")
+	return nil
+}
+
+// loop checks the providers each interval until the monitor is stopped
+func (m *Monitor) loop() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(m.ctx)
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks all providers concurrently and records the outcomes. Checks interrupted by ctx
+// are not recorded.
+func (m *Monitor) Check(ctx context.Context) {
+	m.mu.RLock()
+	providers := make(map[string]ports.LLMProviderStatus, len(m.providers))
+	for name, provider := range m.providers {
+		providers[name] = provider
+	}
+	m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for name, provider := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.check(ctx, name, provider)
+		}()
+	}
+	wg.Wait()
+}
+
+// check checks a provider within the timeout and records the outcome
+func (m *Monitor) check(ctx context.Context, name string, provider ports.LLMProviderStatus) {
+	checkCtx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+
+	checkedAt := m.now()
+	start := time.Now()
+	available := provider.IsAvailable(checkCtx)
+	latency := time.Since(start)
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.metrics.CheckDuration(name).Observe(latency.Seconds())
+	m.record(name, ports.LLMProviderCheck{CheckedAt: checkedAt, Available: available, Latency: latency})
+}
+
+// record adds a check to the history of a provider and updates its health
+func (m *Monitor) record(name string, check ports.LLMProviderCheck) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history := append(m.history[name], check)
+	if len(history) > m.config.History {
+		history = slices.Clone(history[len(history)-m.config.History:])
+	}
+	m.history[name] = history
+
+	wasHealthy := m.failures[name] < m.config.FailureThreshold
+	if check.Available {
+		m.failures[name] = 0
+		m.metrics.Available(name).Set(1)
+		if !wasHealthy {
+			m.logger.Info("LLM provider recovered", "provider", name, "latency", check.Latency)
+		}
+		return
+	}
+
+	m.failures[name]++
+	m.metrics.Available(name).Set(0)
+	m.metrics.FailedChecks(name).Inc()
+	if wasHealthy && m.failures[name] >= m.config.FailureThreshold {
+		m.logger.Warn("LLM provider unavailable", "provider", name, "failed_checks", m.failures[name])
+	}
+}
+
+// Healthy implements ports.LLMProviderHealth.Healthy
+func (m *Monitor) Healthy(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.failures[name] < m.config.FailureThreshold
+}
+
+// History implements ports.LLMProviderHealth.History
+func (m *Monitor) History(name string) []ports.LLMProviderCheck {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.history[name])
+}
@@ -0,0 +1,110 @@
+package llmhealth
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockProvider is a ports.LLMProviderStatus whose availability is set by the test
+type mockProvider struct {
+	name      string
+	available atomic.Bool
+	checks    atomic.Int32
+}
+
+func (p *mockProvider) Name() string {
+	return p.name
+}
+
+func (p *mockProvider) IsAvailable(ctx context.Context) bool {
+	p.checks.Add(1)
+	return p.available.Load()
+}
+
+func newTestMonitor(history int) *Monitor {
+	return NewMonitor(logging.NewNoopLogger(), &Config{
+		Interval:         time.Minute,
+		Timeout:          time.Second,
+		History:          history,
+		FailureThreshold: 2,
+	})
+}
+
+func TestMonitor_FailureThreshold(t *testing.T) {
+	m := newTestMonitor(10)
+	provider := &mockProvider{name: "openai"}
+	m.Add("openai", provider)
+
+	assert.True(t, m.Healthy("openai"), "a provider not checked yet should be healthy")
+
+	m.Check(context.Background())
+	assert.True(t, m.Healthy("openai"), "a single failed check should not make a provider unhealthy")
+
+	m.Check(context.Background())
+	assert.False(t, m.Healthy("openai"))
+	assert.Equal(t, int64(0), m.Metrics().Available("openai").Get())
+	assert.Equal(t, int64(2), m.Metrics().FailedChecks("openai").Get())
+
+	provider.available.Store(true)
+	m.Check(context.Background())
+	assert.True(t, m.Healthy("openai"), "a successful check should make a provider healthy again")
+	assert.Equal(t, int64(1), m.Metrics().Available("openai").Get())
+}
+
+func TestMonitor_History(t *testing.T) {
+	m := newTestMonitor(2)
+	provider := &mockProvider{name: "ollama"}
+	m.Add("ollama", provider)
+
+	for _, available := range []bool{false, true, true} {
+		provider.available.Store(available)
+		m.Check(context.Background())
+	}
+
+	history := m.History("ollama")
+	require.Len(t, history, 2, "only the configured number of checks should be kept")
+	assert.True(t, history[0].Available)
+	assert.True(t, history[1].Available)
+	assert.False(t, history[0].CheckedAt.After(history[1].CheckedAt), "checks should be oldest first")
+	assert.Empty(t, m.History("anthropic"))
+}
+
+func TestMonitor_StartChecksAllProviders(t *testing.T) {
+	m := newTestMonitor(10)
+	first := &mockProvider{name: "openai"}
+	second := &mockProvider{name: "anthropic"}
+	second.available.Store(true)
+	m.Add("openai", first)
+	m.Add("anthropic", second)
+
+	require.NoError(t, m.Start())
+	assert.Error(t, m.Start())
+	assert.Eventually(t, func() bool {
+		return len(m.History("openai")) == 1 && len(m.History("anthropic")) == 1
+	}, time.Second, 10*time.Millisecond, "every provider should be checked right after starting")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, m.Stop(ctx))
+	assert.Equal(t, int32(1), first.checks.Load())
+	assert.Equal(t, int32(1), second.checks.Load())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+
+	config := DefaultConfig()
+	config.FailureThreshold = 0
+	assert.Error(t, config.Validate())
+
+	config = DefaultConfig()
+	config.Timeout = 0
+	assert.Error(t, config.Validate())
+}
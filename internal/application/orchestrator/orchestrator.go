@@ -2,11 +2,14 @@ package orchestrator
 
 import (
 	"context"
+	"time"
+	"unicode/utf8"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/application/usecase"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 // Orchestrator coordinates message processing, LLM interaction, and skill execution.
@@ -14,8 +17,48 @@ import (
 type Orchestrator struct {
 	chatUseCase *usecase.ChatUseCase
 	logger      logging.Logger
+	tracer      *tracing.Tracer
+	config      Config
 }
 
+// Config holds the configuration of the orchestrator
+type Config struct {
+	Planning PlanningConfig
+	Tools    ToolsConfig
+}
+
+// PlanningConfig configures multi-step planning of messages. A planned message is broken
+// down by the LLM into steps, each executed as a task with its progress reported to the user,
+// and answered with a reply synthesized from the results of the steps.
+type PlanningConfig struct {
+	Enabled   bool // Plan messages instead of answering them with a single LLM call
+	MaxSteps  int  // Maximum number of steps of a plan
+	MinLength int  // Shorter messages are answered directly; 0 plans every message
+
+	// MaxParallelSkills is the number of consecutive skill steps of a plan run at the same time
+	MaxParallelSkills int
+
+	// SkillTimeout is the combined timeout of the skill steps run together; 0 for none
+	SkillTimeout time.Duration
+}
+
+// ToolsConfig configures the agent loop of messages that are not planned. The LLM is offered
+// the enabled skills as tools, and the tool calls of its replies run as skill executions.
+type ToolsConfig struct {
+	Enabled     bool          // Offer the skills to the LLM as tools
+	MaxRounds   int           // LLM replies whose tool calls are run before the LLM must answer
+	MaxParallel int           // Tool calls of a reply run at the same time
+	Timeout     time.Duration // Combined timeout of the tool calls of a reply; 0 for none
+}
+
+// Defaults of PlanningConfig and ToolsConfig
+const (
+	DefaultMaxPlanSteps      = 5 // Maximum number of steps of a plan
+	DefaultMaxParallelSkills = 4 // Skill steps run at the same time
+	DefaultMaxToolRounds     = 5 // LLM replies whose tool calls are run
+	DefaultMaxParallelTools  = 4 // Tool calls run at the same time
+)
+
 // NewOrchestrator creates a new Orchestrator instance.
 //
 // Parameters:
@@ -25,13 +68,42 @@ type Orchestrator struct {
 // Returns:
 //   - ports.Orchestrator: Initialized orchestrator
 func NewOrchestrator(chatUseCase *usecase.ChatUseCase, logger logging.Logger) ports.Orchestrator {
+	return NewOrchestratorWithTracer(chatUseCase, logger, nil)
+}
+
+// NewOrchestratorWithTracer creates a new Orchestrator recording a span for every processed
+// message. A nil tracer disables tracing.
+func NewOrchestratorWithTracer(chatUseCase *usecase.ChatUseCase, logger logging.Logger, tracer *tracing.Tracer) ports.Orchestrator {
+	return NewOrchestratorWithConfig(chatUseCase, logger, tracer, Config{})
+}
+
+// NewOrchestratorWithConfig creates a new Orchestrator with a tracer, which may be nil, and
+// the configuration of planning and tools.
+func NewOrchestratorWithConfig(chatUseCase *usecase.ChatUseCase, logger logging.Logger, tracer *tracing.Tracer, config Config) ports.Orchestrator {
+	if config.Planning.MaxSteps <= 0 {
+		config.Planning.MaxSteps = DefaultMaxPlanSteps
+	}
+	if config.Planning.MaxParallelSkills <= 0 {
+		config.Planning.MaxParallelSkills = DefaultMaxParallelSkills
+	}
+	if config.Tools.MaxRounds <= 0 {
+		config.Tools.MaxRounds = DefaultMaxToolRounds
+	}
+	if config.Tools.MaxParallel <= 0 {
+		config.Tools.MaxParallel = DefaultMaxParallelTools
+	}
 	return &Orchestrator{
 		chatUseCase: chatUseCase,
 		logger:      logger,
+		tracer:      tracer,
+		config:      config,
 	}
 }
 
 // ProcessMessage processes an incoming message and returns AI response.
+// With planning enabled, messages of at least PlanningConfig.MinLength characters are
+// answered by ChatUseCase.SendPlannedMessage, which reports the steps with ports.ReportProgress.
+// With tools enabled, other messages are answered by ChatUseCase.SendToolMessage.
 //
 // Parameters:
 //   - ctx: Context for the operation
@@ -43,7 +115,10 @@ func NewOrchestrator(chatUseCase *usecase.ChatUseCase, logger logging.Logger) po
 //   - *dto.SendMessageResponse: Response containing AI message and conversation history
 //   - error: Error if operation failed
 func (o *Orchestrator) ProcessMessage(ctx context.Context, userID, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
-	o.logger.Info("orchestrator: processing message", "user_id", userID, "content_length", len(content))
+	ctx, span := o.tracer.Start(ctx, "orchestrator.process_message", tracing.SpanKindInternal, "session_id", options.SessionID)
+	defer span.End()
+
+	o.logger.InfoContext(ctx, "orchestrator: processing message", "user_id", userID, "content_length", len(content))
 
 	// Create send message request
 	req := dto.SendMessageRequest{
@@ -55,21 +130,45 @@ func (o *Orchestrator) ProcessMessage(ctx context.Context, userID, content strin
 		Options: options,
 	}
 
-	// Delegate to ChatUseCase for message processing
-	resp, err := o.chatUseCase.SendMessage(ctx, req)
+	// Delegate to ChatUseCase for message processing; long messages are planned in steps
+	var resp *dto.SendMessageResponse
+	var err error
+	switch {
+	case o.shouldPlan(content):
+		resp, err = o.chatUseCase.SendPlannedMessage(ctx, req, usecase.PlanOptions{
+			MaxSteps:          o.config.Planning.MaxSteps,
+			MaxParallelSkills: o.config.Planning.MaxParallelSkills,
+			SkillTimeout:      o.config.Planning.SkillTimeout,
+		})
+	case o.config.Tools.Enabled:
+		resp, err = o.chatUseCase.SendToolMessage(ctx, req, usecase.ToolOptions{
+			MaxRounds:   o.config.Tools.MaxRounds,
+			MaxParallel: o.config.Tools.MaxParallel,
+			Timeout:     o.config.Tools.Timeout,
+		})
+	default:
+		resp, err = o.chatUseCase.SendMessage(ctx, req)
+	}
 	if err != nil {
-		o.logger.Error("orchestrator: failed to process message", "user_id", userID, "error", err)
+		span.RecordError(err)
+		o.logger.ErrorContext(ctx, "orchestrator: failed to process message", "user_id", userID, "error", err)
 		return nil, err
 	}
 
 	if resp.Message != nil {
-		o.logger.Info("orchestrator: message processed successfully", "user_id", userID, "session_id", resp.Message.SessionID)
+		o.logger.InfoContext(ctx, "orchestrator: message processed successfully", "user_id", userID, "session_id", resp.Message.SessionID)
 	} else {
-		o.logger.Info("orchestrator: message processed successfully", "user_id", userID)
+		o.logger.InfoContext(ctx, "orchestrator: message processed successfully", "user_id", userID)
 	}
 	return resp, nil
 }
 
+// shouldPlan reports whether a message is answered through a plan
+func (o *Orchestrator) shouldPlan(content string) bool {
+	planning := o.config.Planning
+	return planning.Enabled && utf8.RuneCountInString(content) >= planning.MinLength
+}
+
 // GetConversation retrieves conversation history for a session.
 //
 // Parameters:
@@ -82,7 +181,7 @@ func (o *Orchestrator) ProcessMessage(ctx context.Context, userID, content strin
 func (o *Orchestrator) GetConversation(ctx context.Context, sessionID string) (*dto.MessagesResponse, error) {
 	o.logger.Info("orchestrator: getting conversation", "session_id", sessionID)
 
-	resp, err := o.chatUseCase.GetConversation(ctx, sessionID)
+	resp, err := o.chatUseCase.GetConversation(ctx, sessionID, dto.PageRequest{})
 	if err != nil {
 		o.logger.Error("orchestrator: failed to get conversation", "session_id", sessionID, "error", err)
 		return nil, err
@@ -103,7 +202,7 @@ func (o *Orchestrator) GetConversation(ctx context.Context, sessionID string) (*
 func (o *Orchestrator) GetUserSessions(ctx context.Context, userID string) (*dto.SessionsResponse, error) {
 	o.logger.Info("orchestrator: getting user sessions", "user_id", userID)
 
-	resp, err := o.chatUseCase.GetUserSessions(ctx, userID)
+	resp, err := o.chatUseCase.GetUserSessions(ctx, userID, dto.PageRequest{})
 	if err != nil {
 		o.logger.Error("orchestrator: failed to get user sessions", "user_id", userID, "error", err)
 		return nil, err
@@ -168,7 +267,7 @@ func (o *Orchestrator) ExecuteSkill(ctx context.Context, sessionID, skillName st
 func (o *Orchestrator) GetSessionTasks(ctx context.Context, sessionID string) (*dto.TasksResponse, error) {
 	o.logger.Info("orchestrator: getting session tasks", "session_id", sessionID)
 
-	resp, err := o.chatUseCase.GetSessionTasks(ctx, sessionID)
+	resp, err := o.chatUseCase.GetSessionTasks(ctx, sessionID, dto.PageRequest{})
 	if err != nil {
 		o.logger.Error("orchestrator: failed to get session tasks", "session_id", sessionID, "error", err)
 		return nil, err
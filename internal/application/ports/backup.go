@@ -0,0 +1,29 @@
+package ports
+
+import "context"
+
+// DatabaseBackup defines the interface for online database backups.
+// Backups are consistent snapshots taken while the database stays in use.
+type DatabaseBackup interface {
+	// Backup writes a copy of the database to a file.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - path: Destination file, replaced atomically when the backup is complete
+	//   - compress: Whether to gzip-compress the backup
+	//
+	// Returns:
+	//   - error: Error if the backup failed
+	Backup(ctx context.Context, path string, compress bool) error
+
+	// Restore replaces the contents of the database with a backup.
+	// Compressed backups are detected automatically.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - path: Backup file created by Backup
+	//
+	// Returns:
+	//   - error: Error if the backup is invalid or the restore failed
+	Restore(ctx context.Context, path string) error
+}
@@ -0,0 +1,9 @@
+package ports
+
+// Leadership tells whether this instance leads the instances sharing the database. Work that
+// must not be done twice, such as running schedules or consuming the updates of a Telegram bot,
+// is only done by the leader.
+type Leadership interface {
+	// IsLeader returns true while this instance holds the leadership
+	IsLeader() bool
+}
@@ -34,3 +34,10 @@ type Connector interface {
 	// Name returns the connector name.
 	Name() string
 }
+
+// MessageSender defines the interface for pushing outgoing messages to users.
+// It delivers messages that are not replies to an incoming event, such as scheduled run results.
+type MessageSender interface {
+	// SendMessage sends a text message to the user through the named connector.
+	SendMessage(ctx context.Context, connector, userID, message string) error
+}
@@ -0,0 +1,65 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// Errors of the document ingestion
+var (
+	// ErrUnsupportedDocument is returned for documents whose text cannot be extracted,
+	// such as images
+	ErrUnsupportedDocument = errors.New("unsupported document type")
+
+	// ErrDocumentTooLarge is returned for documents over the size limit
+	ErrDocumentTooLarge = errors.New("document too large")
+
+	// ErrDocumentEmpty is returned for documents without text, such as scanned PDFs
+	ErrDocumentEmpty = errors.New("document has no text")
+)
+
+// Document is a file shared by a user in a session
+type Document struct {
+	Name     string // File name, e.g. "report.pdf"
+	MimeType string // MIME type reported by the channel; may be empty
+	Data     []byte // Content of the file
+}
+
+// DocumentIngester defines the interface for reading the documents shared by users into the
+// memory of their session. It backs the document messages of the router.
+type DocumentIngester interface {
+	// IngestDocument extracts the text of a document, splits it into chunks and stores them
+	// with their embeddings in the session.
+	//
+	// Returns:
+	//   - int: Number of chunks stored
+	//   - error: ErrUnsupportedDocument, ErrDocumentTooLarge, ErrDocumentEmpty, or an error
+	//     if the chunks could not be stored
+	IngestDocument(ctx context.Context, sessionID string, doc Document) (int, error)
+
+	// MaxDocumentSize returns the size limit of the documents in bytes
+	MaxDocumentSize() int64
+}
+
+// DocumentQueuer is implemented by document ingesters that can store the chunks of a document
+// in the background, e.g. on a job queue, so that a large document is not embedded while its
+// user waits
+type DocumentQueuer interface {
+	// QueueDocument extracts the text of a document and splits it into chunks like
+	// IngestDocument, and queues storing them with their embeddings in the session.
+	//
+	// Returns:
+	//   - int: Number of chunks queued
+	//   - error: The errors of IngestDocument
+	QueueDocument(ctx context.Context, sessionID string, doc Document) (int, error)
+}
+
+// DocumentRetriever defines the interface for finding the parts of the documents of a session
+// relevant to a question
+type DocumentRetriever interface {
+	// RetrieveDocuments returns the chunks of the documents of a session closest to a query,
+	// most relevant first; none if the session has no documents
+	RetrieveDocuments(ctx context.Context, sessionID, query string) ([]*entity.DocumentChunk, error)
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+)
+
+// SessionExporter defines the interface for exporting session transcripts.
+// It backs the /export chat command.
+type SessionExporter interface {
+	// ExportSession renders a transcript of a session with its messages and tasks.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - sessionID: ID of the session to export
+	//   - format: "json" or "markdown"
+	//
+	// Returns:
+	//   - *dto.SessionExportResponse: Rendered transcript, or an error response for invalid formats
+	//   - error: Error if the session could not be read
+	ExportSession(ctx context.Context, sessionID, format string) (*dto.SessionExportResponse, error)
+}
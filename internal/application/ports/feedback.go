@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+)
+
+// FeedbackRecorder defines the interface for rating assistant replies.
+// It backs the feedback buttons under replies and the /feedback chat command.
+type FeedbackRecorder interface {
+	// RecordFeedback rates an assistant message, replacing an earlier rating of the message.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - messageID: ID of the rated assistant message
+	//   - userID: ID of the user rating the message; only messages of their sessions can be rated
+	//   - req: Rating and optional comment
+	//
+	// Returns:
+	//   - *dto.FeedbackResponse: The saved feedback, or an error response for an invalid rating
+	//   - error: Error if the message was not found or the feedback could not be saved
+	RecordFeedback(ctx context.Context, messageID, userID string, req dto.RecordFeedbackRequest) (*dto.FeedbackResponse, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+)
+
+// SessionForker defines the interface for forking sessions.
+// It backs the /fork chat command.
+type SessionForker interface {
+	// ForkSession creates a new session holding a copy of the history of a session
+	// up to and including a message.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - sessionID: ID of the session to fork
+	//   - fromMessageID: ID of the last message to copy; empty copies the whole history
+	//
+	// Returns:
+	//   - *dto.SessionResponse: The new session, or an error response if the message is not in the session
+	//   - error: Error if the session could not be read or created
+	ForkSession(ctx context.Context, sessionID, fromMessageID string) (*dto.SessionResponse, error)
+}
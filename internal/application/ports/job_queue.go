@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// ErrJobPermanent is wrapped by the error of a job handler that failed for a reason retrying
+// cannot fix, e.g. an invalid payload; the job fails without further attempts
+var ErrJobPermanent = errors.New("permanent job error")
+
+// JobHandler does the work of a job. It returns nil when the job is done; an error fails the
+// attempt, which is retried later unless it wraps ErrJobPermanent or was the last one. The
+// context is cancelled when the lease of the job is lost or the queue stops.
+type JobHandler func(ctx context.Context, job *entity.Job) error
+
+// JobOptions are the options of a queued job
+type JobOptions struct {
+	// Key identifies the work, so that it is only queued once; empty queues every job
+	Key string
+
+	// MaxAttempts is the number of attempts before the job fails; 0 uses the queue default
+	MaxAttempts int
+
+	// LeaderOnly restricts the job to the leader of a cluster, e.g. for work needing a connector
+	// that runs on the leader only
+	LeaderOnly bool
+}
+
+// JobQueue defines the interface for the persistent queue of background work. Jobs are stored
+// before they are run, so that they survive restarts, and are run by the workers of any process
+// sharing the database.
+type JobQueue interface {
+	// Register sets the handler of a kind of job. Handlers must be registered before the
+	// queue is started.
+	Register(kind string, handler JobHandler)
+
+	// Enqueue stores a job of a kind with its payload encoded as JSON, due now.
+	//
+	// Parameters:
+	//   - ctx: Context for storing the job
+	//   - kind: Kind of the job, selecting its handler
+	//   - payload: Input of the handler, encoded as JSON
+	//   - opts: Key and attempts of the job
+	//
+	// Returns:
+	//   - error: Error if the payload cannot be encoded or the job cannot be stored; a job whose
+	//     key is already queued is not an error
+	Enqueue(ctx context.Context, kind string, payload any, opts JobOptions) error
+}
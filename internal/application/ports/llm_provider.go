@@ -2,25 +2,39 @@ package ports
 
 import (
 	"context"
+	"errors"
+)
+
+var (
+	// ErrLLMProviderNotFound is returned when a request selects a provider that is not configured
+	ErrLLMProviderNotFound = errors.New("llm provider not found")
+
+	// ErrLLMModelNotAllowed is returned when a request selects a model its provider does not allow
+	ErrLLMModelNotAllowed = errors.New("llm model not allowed")
 )
 
 // Message represents a chat message in a conversation.
 type Message struct {
-	Role    string `json:"role"`    // Message role: "user", "assistant", "system"
-	Content string `json:"content"` // Message content
+	Role       string     `json:"role"`                   // Message role: "user", "assistant", "system", "tool"
+	Content    string     `json:"content"`                // Message content; the result of the call for a "tool" message
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // Tool calls requested by an assistant message
+	ToolCallID string     `json:"tool_call_id,omitempty"` // ID of the tool call a "tool" message answers
 }
 
 // CompletionRequest represents a request for LLM completion.
 type CompletionRequest struct {
-	Messages  []Message `json:"messages"`             // Conversation history messages
-	Model     string    `json:"model,omitempty"`      // Model to use for completion
-	MaxTokens int       `json:"max_tokens,omitempty"` // Maximum tokens in the response
+	Messages    []Message `json:"messages"`              // Conversation history messages
+	Provider    string    `json:"provider,omitempty"`    // Configured provider to use; empty selects the default provider
+	Model       string    `json:"model,omitempty"`       // Model to use for completion
+	MaxTokens   int       `json:"max_tokens,omitempty"`  // Maximum tokens in the response
+	Temperature *float64  `json:"temperature,omitempty"` // Sampling temperature; nil uses the one of the provider
 }
 
 // CompletionResponse represents an LLM completion response.
 type CompletionResponse struct {
-	Message Message `json:"message"` // Generated message
-	Tokens  Tokens  `json:"tokens"`  // Token usage information
+	Message   Message    `json:"message"`              // Generated message
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"` // Tools the LLM asks to call before it answers, in order
+	Tokens    Tokens     `json:"tokens"`               // Token usage information
 }
 
 // Tokens represents token usage information for the completion.
@@ -32,6 +46,7 @@ type Tokens struct {
 
 // ToolCall represents a tool/function call made by the LLM.
 type ToolCall struct {
+	ID        string                 `json:"id"`        // ID of the call, repeated in the "tool" message with its result
 	Name      string                 `json:"name"`      // Name of the tool to call
 	Arguments map[string]interface{} `json:"arguments"` // Arguments to pass to the tool
 }
@@ -43,6 +58,8 @@ type LLMProvider interface {
 	Generate(ctx context.Context, req CompletionRequest) (*CompletionResponse, error)
 
 	// GenerateWithTools generates a completion with tool/function calling support.
+	// The tools the LLM asks to call are returned in CompletionResponse.ToolCalls; providers
+	// without tool support answer like Generate.
 	GenerateWithTools(ctx context.Context, req CompletionRequest, tools []ToolDefinition) (*CompletionResponse, error)
 
 	// Stream generates a streaming completion, returning a channel of text chunks.
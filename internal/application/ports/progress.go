@@ -0,0 +1,30 @@
+package ports
+
+import "context"
+
+// Progress is an intermediate status update of a message being answered, such as the step of
+// a plan that is being executed.
+type Progress struct {
+	Step        int    // Number of the current step, starting at 1
+	Total       int    // Number of steps
+	Description string // What the step does, e.g. "Search the web for flights"
+}
+
+// ProgressFunc receives the progress of a message being answered
+type ProgressFunc func(ctx context.Context, progress Progress)
+
+// progressContextKey is the context key of the ProgressFunc of a message
+type progressContextKey struct{}
+
+// WithProgress returns a context whose progress is reported to fn. The message router sets it
+// to push status updates to the user while the orchestrator answers a message.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressContextKey{}, fn)
+}
+
+// ReportProgress reports progress to the ProgressFunc of the context, if any
+func ReportProgress(ctx context.Context, progress Progress) {
+	if fn, _ := ctx.Value(progressContextKey{}).(ProgressFunc); fn != nil {
+		fn(ctx, progress)
+	}
+}
@@ -0,0 +1,50 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// Scopes of the prompt fragments, from the broadest to the narrowest
+const (
+	PromptScopeWorkspace = "workspace" // Sessions of a workspace
+	PromptScopeConnector = "connector" // Messages received through a connector, e.g. telegram
+	PromptScopeSkill     = "skill"     // LLM calls planning with a skill or answering from its output
+)
+
+// ErrInvalidPromptScope is returned for a prompt fragment of an unknown scope
+var ErrInvalidPromptScope = errors.New("prompt scope must be workspace, connector or skill")
+
+// PromptFragment is a part of the system prompt passed to the LLM only within its scope, such as
+// "Keep replies under 3000 characters." for the messages of the telegram connector
+type PromptFragment struct {
+	Scope  string // PromptScopeWorkspace, PromptScopeConnector or PromptScopeSkill
+	Name   string // Workspace ID, connector name or skill name the fragment applies to
+	Prompt string
+}
+
+// ValidPromptScope reports whether scope is one of the prompt fragment scopes
+func ValidPromptScope(scope string) bool {
+	switch scope {
+	case PromptScopeWorkspace, PromptScopeConnector, PromptScopeSkill:
+		return true
+	default:
+		return false
+	}
+}
+
+// connectorContextKey is the context key of the connector a message was received through
+type connectorContextKey struct{}
+
+// WithConnector returns a context of a message received through a connector. The message
+// router sets it so that the prompt fragments of the connector apply to the reply.
+func WithConnector(ctx context.Context, connector string) context.Context {
+	return context.WithValue(ctx, connectorContextKey{}, connector)
+}
+
+// ConnectorFromContext returns the connector a message was received through, or an empty string
+// for messages of the HTTP API
+func ConnectorFromContext(ctx context.Context) string {
+	connector, _ := ctx.Value(connectorContextKey{}).(string)
+	return connector
+}
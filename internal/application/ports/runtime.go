@@ -0,0 +1,208 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+var (
+	// ErrConnectorNotFound is returned when no connector with the given name is registered
+	ErrConnectorNotFound = errors.New("connector not found")
+
+	// ErrConnectorState is returned when a connector cannot be started or stopped in its current state
+	ErrConnectorState = errors.New("invalid connector state")
+
+	// ErrDeadLettersDisabled is returned when the message router has no dead-letter store
+	ErrDeadLettersDisabled = errors.New("message dead letters are disabled")
+
+	// ErrReprocessFailed is returned when a dead-lettered message cannot be processed again
+	ErrReprocessFailed = errors.New("reprocessing failed")
+
+	// ErrReplayDisabled is returned when the message router has no event store to replay messages from
+	ErrReplayDisabled = errors.New("message replay requires the event store")
+
+	// ErrNotReplayable is returned when a stored event is not a message received by a connector
+	ErrNotReplayable = errors.New("event is not a received message")
+
+	// ErrInstructionsDisabled is returned when no InstructionsManager is set
+	ErrInstructionsDisabled = errors.New("global instructions are disabled")
+)
+
+// ConnectorStatus describes the runtime state of a registered channel connector.
+type ConnectorStatus struct {
+	Name             string // Connector name (telegram, discord, web, etc.)
+	Running          bool   // Whether the connector is running
+	Pending          int    // Messages received by the connector but not yet picked up by the router
+	MessagesReceived int64  // Messages received from the connector since startup
+}
+
+// RouterStats describes the message throughput of the message router.
+type RouterStats struct {
+	MessagesReceived  int64 // Messages received from all connectors
+	MessagesProcessed int64 // Messages processed successfully
+	MessagesFailed    int64 // Messages that failed processing
+	ValidationFailed  int64 // Messages rejected by validation
+	InFlight          int64 // Messages being processed right now
+	Queued            int64 // Messages waiting for a router worker
+	Abandoned         int64 // Messages dropped unprocessed when the router stopped
+	QueueDepth        int   // Messages waiting in connector queues
+	Connectors        int   // Number of registered connectors
+}
+
+// ConnectorManager defines the interface for inspecting and controlling channel connectors at runtime.
+// It backs the admin API.
+type ConnectorManager interface {
+	// ConnectorStatuses returns the status of all registered connectors, sorted by name
+	ConnectorStatuses() []ConnectorStatus
+
+	// StartConnector starts a stopped connector and resumes routing its messages.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - name: Name of the connector
+	//
+	// Returns:
+	//   - error: ErrConnectorNotFound, ErrConnectorState if it is already running, or a start failure
+	StartConnector(ctx context.Context, name string) error
+
+	// StopConnector stops a running connector without stopping the router.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - name: Name of the connector
+	//
+	// Returns:
+	//   - error: ErrConnectorNotFound, ErrConnectorState if it is not running, or a stop failure
+	StopConnector(ctx context.Context, name string) error
+
+	// Stats returns the message throughput of the router
+	Stats() RouterStats
+}
+
+// MessageDeadLetterManager is implemented by message routers that keep the messages they failed
+// to process. It backs the dead-letter endpoints of the admin API.
+type MessageDeadLetterManager interface {
+	// ListDeadLetters retrieves the messages the router failed to process, newest first
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - filter: Connector and page of the dead letters
+	//
+	// Returns:
+	//   - []*entity.MessageDeadLetter: Matching dead letters
+	//   - error: ErrDeadLettersDisabled, or an error if reading the dead letters failed
+	ListDeadLetters(ctx context.Context, filter repository.MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error)
+
+	// ReprocessDeadLetter routes a dead-lettered message once more and sends the reply to the user.
+	// The dead letter is deleted on success and updated with the new error on failure.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - id: ID of the dead letter
+	//
+	// Returns:
+	//   - error: repository.ErrNotFound for an unknown ID, ErrDeadLettersDisabled, or ErrReprocessFailed
+	ReprocessDeadLetter(ctx context.Context, id string) error
+
+	// DeleteDeadLetter discards a dead-lettered message without processing it
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - id: ID of the dead letter
+	//
+	// Returns:
+	//   - error: repository.ErrNotFound for an unknown ID, ErrDeadLettersDisabled, or a store error
+	DeleteDeadLetter(ctx context.Context, id string) error
+}
+
+// MessageReplay describes a message received by a connector that was routed once more
+type MessageReplay struct {
+	EventID   string   // ID of the replayed connector.message event
+	Connector string   // Connector that received the message
+	UserID    string   // User who sent the message
+	Replies   []string // Replies to the message, in the order they were produced
+	Delivered bool     // Whether the replies were sent to the user
+}
+
+// MessageReplayer is implemented by message routers that can route the messages kept in the
+// event store again, e.g. after fixing a broken skill. It backs the replay endpoint of the admin API.
+type MessageReplayer interface {
+	// ReplayMessage routes the message of a stored connector.message event once more, marked as
+	// a replay in its metadata. Without deliver, the replies are only returned, so that the user
+	// does not get a second answer to the message.
+	//
+	// Parameters:
+	//   - ctx: Context for the operation
+	//   - eventID: ID of the stored event
+	//   - deliver: Whether to send the replies to the user
+	//
+	// Returns:
+	//   - *MessageReplay: Replayed message and its replies
+	//   - error: repository.ErrNotFound for an unknown ID, ErrReplayDisabled, ErrNotReplayable,
+	//     or ErrReprocessFailed if the connector is not running or processing fails
+	ReplayMessage(ctx context.Context, eventID string, deliver bool) (*MessageReplay, error)
+}
+
+// LLMProviderStatus is implemented by LLM providers that can report their name and availability.
+type LLMProviderStatus interface {
+	// Name returns the name of the provider
+	Name() string
+
+	// IsAvailable checks if the provider is reachable
+	IsAvailable(ctx context.Context) bool
+}
+
+// LLMProviderCheck is the outcome of an availability check of an LLM provider
+type LLMProviderCheck struct {
+	CheckedAt time.Time
+	Available bool
+	Latency   time.Duration
+}
+
+// LLMProviderHealth reports the health of the LLM providers from periodic availability checks.
+// It backs the LLM providers endpoint of the admin API and the failover of the default provider.
+type LLMProviderHealth interface {
+	// Healthy returns false while a provider fails its checks; a provider that was not
+	// checked yet is healthy
+	Healthy(name string) bool
+
+	// History returns the recent checks of a provider, oldest first
+	History(name string) []LLMProviderCheck
+}
+
+// InstructionsManager holds the global custom instructions passed to the LLM in every session,
+// and the prompt fragments passed within their scope. It backs the instructions and prompts
+// endpoints of the admin API.
+type InstructionsManager interface {
+	// GlobalInstructions returns the global instructions, or an empty string if none are set
+	GlobalInstructions() string
+
+	// SetGlobalInstructions replaces the global instructions; empty instructions remove them
+	//
+	// Parameters:
+	//   - instructions: New instructions
+	//
+	// Returns:
+	//   - error: entity.ErrInstructionsTooLong if the instructions are too long
+	SetGlobalInstructions(instructions string) error
+
+	// PromptFragments returns the prompt fragments, sorted by scope and name
+	PromptFragments() []PromptFragment
+
+	// SetPromptFragment replaces the prompt fragment of a scope and name; an empty prompt
+	// removes it
+	//
+	// Parameters:
+	//   - scope: PromptScopeWorkspace, PromptScopeConnector or PromptScopeSkill
+	//   - name: Workspace ID, connector name or skill name
+	//   - prompt: New prompt fragment
+	//
+	// Returns:
+	//   - error: ErrInvalidPromptScope for an unknown scope, or entity.ErrInstructionsTooLong
+	//     if the prompt is too long
+	SetPromptFragment(scope, name, prompt string) error
+}
@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSchedulerDisabled is returned when a schedule is run on demand without a scheduler
+var ErrSchedulerDisabled = errors.New("scheduler is disabled")
+
+// Scheduler defines the interface for the schedule execution engine.
+// It fires enabled schedules according to their cron expressions.
+type Scheduler interface {
+	// Start loads enabled schedules and begins firing them.
+	//
+	// Returns:
+	//   - error: Error if the scheduler could not be started
+	Start() error
+
+	// Stop stops the scheduler and waits for running executions to finish.
+	//
+	// Returns:
+	//   - error: Error if stopping failed
+	Stop() error
+
+	// Reload requests the scheduler to reload schedules from storage.
+	// It is safe to call at any time and never blocks.
+	Reload()
+
+	// RunNow starts an immediate run of a schedule in the background,
+	// even if the schedule is disabled.
+	//
+	// Parameters:
+	//   - ctx: Context for loading the schedule
+	//   - scheduleID: ID of the schedule to run
+	//
+	// Returns:
+	//   - error: Error if the scheduler is not running, the schedule does not exist
+	//     or a run of the schedule is already in progress
+	RunNow(ctx context.Context, scheduleID string) error
+}
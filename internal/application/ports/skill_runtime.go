@@ -2,6 +2,19 @@ package ports
 
 import (
 	"context"
+	"errors"
+)
+
+var (
+	// ErrSkillDisabled is returned when a disabled skill is asked to run
+	ErrSkillDisabled = errors.New("skill is disabled")
+
+	// ErrTaskCancelled is the cause of the context of a skill execution whose task was cancelled
+	ErrTaskCancelled = errors.New("task cancelled")
+
+	// ErrSkillRetryable is wrapped by the error of a skill execution that failed for a transient
+	// reason, e.g. a timeout, and may succeed when retried
+	ErrSkillRetryable = errors.New("retryable skill error")
 )
 
 // SkillExecution represents the result of a skill execution.
@@ -15,14 +28,17 @@ type SkillExecution struct {
 // Skills are reusable components that perform specific tasks.
 type SkillRuntime interface {
 	// Execute runs a skill with the given input parameters.
+	// A skill that failed is reported as an unsuccessful execution, which is terminal. An error
+	// wrapping ErrSkillRetryable reports a transient failure after which the skill may be
+	// executed again; any other error is terminal.
 	Execute(ctx context.Context, skillName string, input map[string]interface{}) (*SkillExecution, error)
 
 	// Validate checks if a skill is valid (permissions, configuration, etc.).
-	Validate(skillName string) error
+	Validate(ctx context.Context, skillName string) error
 
 	// List returns all available skill names.
-	List() ([]string, error)
+	List(ctx context.Context) ([]string, error)
 
 	// GetSkill returns skill details by name.
-	GetSkill(skillName string) (map[string]interface{}, error)
+	GetSkill(ctx context.Context, skillName string) (map[string]interface{}, error)
 }
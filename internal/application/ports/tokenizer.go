@@ -0,0 +1,12 @@
+package ports
+
+// Tokenizer counts the tokens of texts as LLM models do. Counts of models whose encoding is
+// unknown are estimates.
+type Tokenizer interface {
+	// Count returns the number of tokens of a text for a model
+	Count(model, text string) int
+
+	// CountMessages returns the number of input tokens of a conversation for a model, including
+	// the tokens framing each message and the reply
+	CountMessages(model string, messages []Message) int
+}
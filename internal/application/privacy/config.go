@@ -0,0 +1,101 @@
+package privacy
+
+import (
+	"fmt"
+)
+
+// KeySize is the size of the AES-256 key personal data is encrypted with
+const KeySize = 32
+
+// Mode selects how personal data in messages is stored
+type Mode string
+
+// Redaction modes
+const (
+	// ModeOff stores messages as they are
+	ModeOff Mode = "off"
+
+	// ModeMask replaces personal data with placeholders such as "[email]"; the data is lost
+	ModeMask Mode = "mask"
+
+	// ModeEncrypt stores personal data encrypted; it is decrypted when messages are read
+	ModeEncrypt Mode = "encrypt"
+)
+
+// Valid returns true if the mode is a known redaction mode
+func (m Mode) Valid() bool {
+	return m == ModeOff || m == ModeMask || m == ModeEncrypt
+}
+
+// ValidationError represents a privacy configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Config holds configuration for the redaction of personal data in stored messages
+type Config struct {
+	// Mode is the redaction mode of workspaces without an override
+	Mode Mode
+
+	// Workspaces overrides the mode per workspace ID
+	Workspaces map[string]Mode
+
+	// Key is the AES-256 key used by ModeEncrypt (nil = encryption unavailable)
+	Key []byte
+}
+
+// DefaultConfig returns the default configuration, which stores messages as they are
+func DefaultConfig() *Config {
+	return &Config{Mode: ModeOff}
+}
+
+// ModeFor returns the redaction mode of a workspace
+func (c *Config) ModeFor(workspace string) Mode {
+	if mode, ok := c.Workspaces[workspace]; ok {
+		return mode
+	}
+	return c.Mode
+}
+
+// Enabled returns true if messages of any workspace are redacted, or if encrypted personal
+// data may have to be decrypted
+func (c *Config) Enabled() bool {
+	if c.Mode != ModeOff || c.Key != nil {
+		return true
+	}
+	for _, mode := range c.Workspaces {
+		if mode != ModeOff {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	encrypts := c.Mode == ModeEncrypt
+	if !c.Mode.Valid() {
+		return &ValidationError{Field: "Mode", Message: "must be off, mask or encrypt"}
+	}
+	for workspace, mode := range c.Workspaces {
+		if !mode.Valid() {
+			return &ValidationError{Field: fmt.Sprintf("Workspaces[%s]", workspace), Message: "must be off, mask or encrypt"}
+		}
+		encrypts = encrypts || mode == ModeEncrypt
+	}
+
+	if c.Key != nil && len(c.Key) != KeySize {
+		return &ValidationError{Field: "Key", Message: fmt.Sprintf("must be %d bytes", KeySize)}
+	}
+	if encrypts && c.Key == nil {
+		return &ValidationError{Field: "Key", Message: "is required by the encrypt mode"}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// eventTextFields are the fields of stored event payloads that hold text of users and replies,
+// such as the content of messages and the input of skills
+var eventTextFields = []string{"content", "input", "output", "comment"}
+
+// EventRepository redacts the personal data in the text fields of stored events before they
+// are appended, according to the mode of the workspace of the event's session or, for events
+// of a connector without a session, of the connector, and decrypts it when events are read,
+// e.g. for a replay.
+type EventRepository struct {
+	repository.EventRepository
+
+	sessions   repository.SessionRepository
+	connectors map[string]string
+	redactor   *Redactor
+	config     *Config
+}
+
+// Compile-time check that EventRepository implements repository.EventRepository
+var _ repository.EventRepository = (*EventRepository)(nil)
+
+// NewEventRepository creates a new EventRepository
+//
+// Parameters:
+//   - events: EventRepository the events are stored in
+//   - sessions: SessionRepository the workspace of an event's session is read from
+//   - connectors: Workspace of the sessions of each connector, by connector name
+//   - config: Redaction modes and encryption key
+//
+// Returns:
+//   - *EventRepository: Redacting event repository
+//   - error: Error if the configuration is invalid
+func NewEventRepository(events repository.EventRepository, sessions repository.SessionRepository, connectors map[string]string, config *Config) (*EventRepository, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	redactor, err := NewRedactor(config.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventRepository{
+		EventRepository: events,
+		sessions:        sessions,
+		connectors:      connectors,
+		redactor:        redactor,
+		config:          config,
+	}, nil
+}
+
+// Append redacts and saves events in order. The events passed in are not modified, except for
+// the sequence numbers assigned to them.
+func (r *EventRepository) Append(ctx context.Context, events ...*entity.StoredEvent) error {
+	redacted := make([]*entity.StoredEvent, 0, len(events))
+	for _, event := range events {
+		payload, err := r.redact(ctx, event.Payload)
+		if err != nil {
+			return err
+		}
+		copied := *event
+		copied.Payload = payload
+		redacted = append(redacted, &copied)
+	}
+
+	if err := r.EventRepository.Append(ctx, redacted...); err != nil {
+		return err
+	}
+	for i, event := range events {
+		event.Seq = redacted[i].Seq
+	}
+	return nil
+}
+
+// GetByID retrieves a stored event by ID
+func (r *EventRepository) GetByID(ctx context.Context, id string) (*entity.StoredEvent, error) {
+	event, err := r.EventRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(event)
+	return event, nil
+}
+
+// List retrieves the events matching a filter, in sequence order
+func (r *EventRepository) List(ctx context.Context, filter repository.EventFilter) ([]*entity.StoredEvent, error) {
+	events, err := r.EventRepository.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(events...)
+	return events, nil
+}
+
+// ListBySubject retrieves the events about the users or sessions of a subject, in sequence order
+func (r *EventRepository) ListBySubject(ctx context.Context, subject repository.EventSubject) ([]*entity.StoredEvent, error) {
+	events, err := r.EventRepository.ListBySubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(events...)
+	return events, nil
+}
+
+// redact returns a payload with the personal data of its text fields redacted. Payloads that
+// are not JSON objects are kept as they are.
+func (r *EventRepository) redact(ctx context.Context, payload string) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(payload), &fields); err != nil {
+		return payload, nil
+	}
+
+	mode, err := r.modeFor(ctx, fields)
+	if err != nil {
+		return "", err
+	}
+	if mode == ModeOff {
+		return payload, nil
+	}
+
+	changed := false
+	for _, name := range eventTextFields {
+		var text string
+		if err := json.Unmarshal(fields[name], &text); err != nil || text == "" {
+			continue
+		}
+		redacted, err := r.redactor.Redact(text, mode)
+		if err != nil {
+			return "", fmt.Errorf("failed to redact event: %w", err)
+		}
+		if redacted == text {
+			continue
+		}
+		if fields[name], err = json.Marshal(redacted); err != nil {
+			return "", fmt.Errorf("failed to redact event: %w", err)
+		}
+		changed = true
+	}
+	if !changed {
+		return payload, nil
+	}
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to redact event: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// modeFor returns the redaction mode of the workspace of the session of an event or, without
+// a session, of its connector, which router events name as their source
+func (r *EventRepository) modeFor(ctx context.Context, fields map[string]json.RawMessage) (Mode, error) {
+	if len(r.config.Workspaces) == 0 {
+		return r.config.Mode, nil
+	}
+
+	var sessionID, connector string
+	_ = json.Unmarshal(fields["session_id"], &sessionID)
+	if sessionID != "" {
+		session, err := r.sessions.FindByID(ctx, sessionID)
+		if err != nil {
+			return "", fmt.Errorf("failed to find session of event: %w", err)
+		}
+		return r.config.ModeFor(string(session.WorkspaceID)), nil
+	}
+
+	if json.Unmarshal(fields["connector"], &connector) != nil || connector == "" {
+		_ = json.Unmarshal(fields["source"], &connector)
+	}
+	return connectorMode(r.config, r.connectors, connector), nil
+}
+
+// reveal decrypts the personal data of events. Encrypted values are JSON-safe, so they are
+// decrypted in the payload text.
+func (r *EventRepository) reveal(events ...*entity.StoredEvent) {
+	for _, event := range events {
+		event.Payload = r.redactor.Reveal(event.Payload)
+	}
+}
+
+// connectorMode returns the redaction mode of the workspace of a connector's sessions
+func connectorMode(config *Config, connectors map[string]string, connector string) Mode {
+	workspace := connectors[connector]
+	if workspace == "" {
+		workspace = valueobject.DefaultWorkspaceID.String()
+	}
+	return config.ModeFor(workspace)
+}
@@ -0,0 +1,81 @@
+package privacy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// fakeEventRepository stores events in memory
+type fakeEventRepository struct {
+	repository.EventRepository
+	events []*entity.StoredEvent
+}
+
+func (f *fakeEventRepository) Append(ctx context.Context, events ...*entity.StoredEvent) error {
+	for _, event := range events {
+		event.Seq = int64(len(f.events) + 1)
+		stored := *event
+		f.events = append(f.events, &stored)
+	}
+	return nil
+}
+
+func (f *fakeEventRepository) List(ctx context.Context, filter repository.EventFilter) ([]*entity.StoredEvent, error) {
+	events := make([]*entity.StoredEvent, 0, len(f.events))
+	for _, event := range f.events {
+		copied := *event
+		events = append(events, &copied)
+	}
+	return events, nil
+}
+
+func TestEventRepository_RedactsPerWorkspace(t *testing.T) {
+	ctx := context.Background()
+	support := newSession("support")
+	inner := &fakeEventRepository{}
+	sessions := &fakeSessionRepository{sessions: map[string]*entity.Session{string(support.ID): support}}
+	repo, err := NewEventRepository(inner, sessions, map[string]string{"helpdesk": "support"}, &Config{
+		Mode:       ModeMask,
+		Workspaces: map[string]Mode{"support": ModeEncrypt},
+		Key:        testKey(),
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	bySession := entity.NewStoredEvent("router.message", `{"kind":"router","session_id":"`+string(support.ID)+`","content":"I'm bob@example.com"}`, now)
+	byConnector := entity.NewStoredEvent("connector.message", `{"kind":"connector","connector":"helpdesk","user_id":"42","content":"call +1 555 123 4567"}`, now)
+	byDefault := entity.NewStoredEvent("connector.message", `{"kind":"connector","connector":"telegram","content":"I'm bob@example.com","tokens":3}`, now)
+	plain := entity.NewStoredEvent("legacy", `not json with bob@example.com`, now)
+	require.NoError(t, repo.Append(ctx, bySession, byConnector, byDefault, plain))
+
+	// The caller's events are not modified, apart from their sequence numbers
+	assert.Contains(t, bySession.Payload, "bob@example.com")
+	assert.Equal(t, int64(4), plain.Seq)
+
+	require.Len(t, inner.events, 4)
+	assert.Contains(t, inner.events[0].Payload, "[pii:")
+	assert.NotContains(t, inner.events[0].Payload, "bob@example.com")
+	assert.Contains(t, inner.events[1].Payload, "[pii:")
+	assert.JSONEq(t, `{"kind":"connector","connector":"telegram","content":"I'm [email]","tokens":3}`, inner.events[2].Payload)
+	assert.Equal(t, plain.Payload, inner.events[3].Payload)
+
+	// Encrypted data is decrypted when read, masked data is lost
+	events, err := repo.List(ctx, repository.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	assert.JSONEq(t, bySession.Payload, events[0].Payload)
+	assert.JSONEq(t, byConnector.Payload, events[1].Payload)
+	assert.Contains(t, events[2].Payload, "[email]")
+
+	// An event of an unknown session is not stored unredacted
+	missing := entity.NewStoredEvent("router.message", `{"session_id":"missing","content":"bob@example.com"}`, now)
+	assert.Error(t, repo.Append(ctx, missing))
+	assert.Len(t, inner.events, 4)
+}
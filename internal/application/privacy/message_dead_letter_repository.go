@@ -0,0 +1,98 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// MessageDeadLetterRepository redacts the personal data of the messages the router failed to
+// process before they are stored, according to the mode of the workspace of the connector
+// that received them, and decrypts it when dead letters are read, e.g. to reprocess them.
+type MessageDeadLetterRepository struct {
+	repository.MessageDeadLetterRepository
+
+	connectors map[string]string
+	redactor   *Redactor
+	config     *Config
+}
+
+// Compile-time check that MessageDeadLetterRepository implements repository.MessageDeadLetterRepository
+var _ repository.MessageDeadLetterRepository = (*MessageDeadLetterRepository)(nil)
+
+// NewMessageDeadLetterRepository creates a new MessageDeadLetterRepository
+//
+// Parameters:
+//   - letters: MessageDeadLetterRepository the dead letters are stored in
+//   - connectors: Workspace of the sessions of each connector, by connector name
+//   - config: Redaction modes and encryption key
+//
+// Returns:
+//   - *MessageDeadLetterRepository: Redacting dead-letter repository
+//   - error: Error if the configuration is invalid
+func NewMessageDeadLetterRepository(letters repository.MessageDeadLetterRepository, connectors map[string]string, config *Config) (*MessageDeadLetterRepository, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	redactor, err := NewRedactor(config.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessageDeadLetterRepository{
+		MessageDeadLetterRepository: letters,
+		connectors:                  connectors,
+		redactor:                    redactor,
+		config:                      config,
+	}, nil
+}
+
+// Create redacts and saves a new message dead letter. The dead letter passed in is not modified.
+func (r *MessageDeadLetterRepository) Create(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	mode := connectorMode(r.config, r.connectors, letter.Connector)
+	if mode == ModeOff {
+		return r.MessageDeadLetterRepository.Create(ctx, letter)
+	}
+
+	content, err := r.redactor.Redact(letter.Content, mode)
+	if err != nil {
+		return fmt.Errorf("failed to redact message dead letter: %w", err)
+	}
+
+	redacted := *letter
+	redacted.Content = content
+	return r.MessageDeadLetterRepository.Create(ctx, &redacted)
+}
+
+// GetByID retrieves a message dead letter by its ID
+func (r *MessageDeadLetterRepository) GetByID(ctx context.Context, id string) (*entity.MessageDeadLetter, error) {
+	letter, err := r.MessageDeadLetterRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(letter)
+	return letter, nil
+}
+
+// List retrieves the message dead letters matching a filter, newest first
+func (r *MessageDeadLetterRepository) List(ctx context.Context, filter repository.MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error) {
+	letters, err := r.MessageDeadLetterRepository.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(letters...)
+	return letters, nil
+}
+
+// reveal decrypts the personal data of message dead letters
+func (r *MessageDeadLetterRepository) reveal(letters ...*entity.MessageDeadLetter) {
+	for _, letter := range letters {
+		letter.Content = r.redactor.Reveal(letter.Content)
+	}
+}
@@ -0,0 +1,65 @@
+package privacy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// fakeMessageDeadLetterRepository stores message dead letters in memory
+type fakeMessageDeadLetterRepository struct {
+	repository.MessageDeadLetterRepository
+	letters []*entity.MessageDeadLetter
+}
+
+func (f *fakeMessageDeadLetterRepository) Create(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	stored := *letter
+	f.letters = append(f.letters, &stored)
+	return nil
+}
+
+func (f *fakeMessageDeadLetterRepository) GetByID(ctx context.Context, id string) (*entity.MessageDeadLetter, error) {
+	for _, letter := range f.letters {
+		if letter.ID == id {
+			copied := *letter
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func TestMessageDeadLetterRepository_RedactsPerConnector(t *testing.T) {
+	ctx := context.Background()
+	inner := &fakeMessageDeadLetterRepository{}
+	repo, err := NewMessageDeadLetterRepository(inner, map[string]string{"helpdesk": "support"}, &Config{
+		Mode:       ModeMask,
+		Workspaces: map[string]Mode{"support": ModeEncrypt},
+		Key:        testKey(),
+	})
+	require.NoError(t, err)
+
+	support := entity.NewMessageDeadLetter("helpdesk", "42", "100", "I'm bob@example.com", nil, "timeout")
+	require.NoError(t, repo.Create(ctx, support))
+	other := entity.NewMessageDeadLetter("telegram", "43", "101", "I'm bob@example.com", nil, "timeout")
+	require.NoError(t, repo.Create(ctx, other))
+
+	// The caller's dead letter is not modified
+	assert.Equal(t, "I'm bob@example.com", support.Content)
+
+	require.Len(t, inner.letters, 2)
+	assert.Contains(t, inner.letters[0].Content, "[pii:")
+	assert.Equal(t, "I'm [email]", inner.letters[1].Content)
+
+	// Encrypted data is decrypted for reprocessing, masked data is lost
+	found, err := repo.GetByID(ctx, support.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "I'm bob@example.com", found.Content)
+	found, err = repo.GetByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "I'm [email]", found.Content)
+}
@@ -0,0 +1,126 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// MessageRepository redacts the personal data of messages before they are stored, according
+// to the mode of the workspace of their session, and decrypts it when messages are read.
+// Messages read for the retention archive are returned as stored, so that archives do not
+// hold decrypted personal data.
+type MessageRepository struct {
+	repository.MessageRepository
+
+	sessions repository.SessionRepository
+	redactor *Redactor
+	config   *Config
+}
+
+// Compile-time check that MessageRepository implements repository.MessageRepository
+var _ repository.MessageRepository = (*MessageRepository)(nil)
+
+// NewMessageRepository creates a new MessageRepository
+//
+// Parameters:
+//   - messages: MessageRepository the messages are stored in
+//   - sessions: SessionRepository the workspace of a message's session is read from
+//   - config: Redaction modes and encryption key
+//
+// Returns:
+//   - *MessageRepository: Redacting message repository
+//   - error: Error if the configuration is invalid
+func NewMessageRepository(messages repository.MessageRepository, sessions repository.SessionRepository, config *Config) (*MessageRepository, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	redactor, err := NewRedactor(config.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessageRepository{
+		MessageRepository: messages,
+		sessions:          sessions,
+		redactor:          redactor,
+		config:            config,
+	}, nil
+}
+
+// Create redacts and saves a new message. The message passed in is not modified.
+func (r *MessageRepository) Create(ctx context.Context, message *entity.Message) error {
+	mode, err := r.modeFor(ctx, message)
+	if err != nil {
+		return err
+	}
+	if mode == ModeOff {
+		return r.MessageRepository.Create(ctx, message)
+	}
+
+	content, err := r.redactor.Redact(message.Content, mode)
+	if err != nil {
+		return fmt.Errorf("failed to redact message: %w", err)
+	}
+
+	redacted := *message
+	redacted.Content = content
+	return r.MessageRepository.Create(ctx, &redacted)
+}
+
+// FindByID retrieves a message by ID
+func (r *MessageRepository) FindByID(ctx context.Context, id string) (*entity.Message, error) {
+	message, err := r.MessageRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(message)
+	return message, nil
+}
+
+// FindBySessionID retrieves messages for a session, oldest first
+func (r *MessageRepository) FindBySessionID(ctx context.Context, sessionID string, opts repository.QueryOptions) ([]*entity.Message, error) {
+	messages, err := r.MessageRepository.FindBySessionID(ctx, sessionID, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(messages...)
+	return messages, nil
+}
+
+// Search retrieves messages whose content matches the search, newest first.
+// Redacted personal data cannot be searched for.
+func (r *MessageRepository) Search(ctx context.Context, search repository.MessageSearch, opts repository.QueryOptions) ([]*entity.Message, error) {
+	messages, err := r.MessageRepository.Search(ctx, search, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.reveal(messages...)
+	return messages, nil
+}
+
+// modeFor returns the redaction mode of the workspace of a message's session
+func (r *MessageRepository) modeFor(ctx context.Context, message *entity.Message) (Mode, error) {
+	if len(r.config.Workspaces) == 0 {
+		return r.config.Mode, nil
+	}
+
+	session, err := r.sessions.FindByID(ctx, string(message.SessionID))
+	if err != nil {
+		return "", fmt.Errorf("failed to find session of message: %w", err)
+	}
+	return r.config.ModeFor(string(session.WorkspaceID)), nil
+}
+
+// reveal decrypts the personal data of messages
+func (r *MessageRepository) reveal(messages ...*entity.Message) {
+	for _, message := range messages {
+		message.Content = r.redactor.Reveal(message.Content)
+	}
+}
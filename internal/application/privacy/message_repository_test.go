@@ -0,0 +1,103 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// fakeMessageRepository stores messages in memory
+type fakeMessageRepository struct {
+	repository.MessageRepository
+	messages []*entity.Message
+}
+
+func (f *fakeMessageRepository) Create(ctx context.Context, message *entity.Message) error {
+	stored := *message
+	f.messages = append(f.messages, &stored)
+	return nil
+}
+
+func (f *fakeMessageRepository) FindBySessionID(ctx context.Context, sessionID string, opts repository.QueryOptions) ([]*entity.Message, error) {
+	var messages []*entity.Message
+	for _, message := range f.messages {
+		if string(message.SessionID) == sessionID {
+			copied := *message
+			messages = append(messages, &copied)
+		}
+	}
+	return messages, nil
+}
+
+// fakeSessionRepository finds sessions by ID
+type fakeSessionRepository struct {
+	repository.SessionRepository
+	sessions map[string]*entity.Session
+}
+
+func (f *fakeSessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	if session, ok := f.sessions[id]; ok {
+		return session, nil
+	}
+	return nil, fmt.Errorf("session %w: %s", repository.ErrNotFound, id)
+}
+
+func newSession(workspace string) *entity.Session {
+	session := entity.NewSession("user-1")
+	session.WorkspaceID = valueobject.MustNewWorkspaceID(workspace)
+	return session
+}
+
+func TestMessageRepository_RedactsPerWorkspace(t *testing.T) {
+	ctx := context.Background()
+	support, sales := newSession("support"), newSession("sales")
+	inner := &fakeMessageRepository{}
+	sessions := &fakeSessionRepository{sessions: map[string]*entity.Session{
+		string(support.ID): support,
+		string(sales.ID):   sales,
+	}}
+	repo, err := NewMessageRepository(inner, sessions, &Config{
+		Mode:       ModeMask,
+		Workspaces: map[string]Mode{"support": ModeEncrypt},
+		Key:        testKey(),
+	})
+	require.NoError(t, err)
+
+	supportMessage := entity.NewUserMessage(string(support.ID), "I'm bob@example.com")
+	require.NoError(t, repo.Create(ctx, supportMessage))
+	salesMessage := entity.NewUserMessage(string(sales.ID), "I'm bob@example.com")
+	require.NoError(t, repo.Create(ctx, salesMessage))
+
+	// The caller's message is not modified
+	assert.Equal(t, "I'm bob@example.com", supportMessage.Content)
+
+	require.Len(t, inner.messages, 2)
+	assert.Contains(t, inner.messages[0].Content, "[pii:")
+	assert.Equal(t, "I'm [email]", inner.messages[1].Content)
+
+	// Encrypted data is decrypted when read, masked data is lost
+	messages, err := repo.FindBySessionID(ctx, string(support.ID), repository.QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "I'm bob@example.com", messages[0].Content)
+
+	messages, err = repo.FindBySessionID(ctx, string(sales.ID), repository.QueryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "I'm [email]", messages[0].Content)
+
+	// A message of an unknown session is not stored unredacted
+	assert.Error(t, repo.Create(ctx, entity.NewUserMessage("missing", "bob@example.com")))
+	assert.Len(t, inner.messages, 2)
+}
+
+func TestNewMessageRepository_InvalidConfig(t *testing.T) {
+	_, err := NewMessageRepository(&fakeMessageRepository{}, &fakeSessionRepository{}, &Config{Mode: ModeEncrypt})
+	assert.Error(t, err)
+}
@@ -0,0 +1,243 @@
+package privacy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind is a kind of personal data
+type Kind string
+
+// Kinds of personal data the redactor detects
+const (
+	KindEmail Kind = "email"
+	KindPhone Kind = "phone"
+	KindCard  Kind = "card"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	cardPattern  = regexp.MustCompile(`\d(?:[ -]?\d){12,18}`)
+	// Phone numbers are a country code or groups of digits separated by spaces, dots or dashes,
+	// some in parentheses, or a country code followed by the digits of the number
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}|\(\d{1,5}\)|\d{1,5})(?:[ .-]?\(\d{1,5}\)|[ .-]\d{1,5}){2,6}|\+\d{10,15}`)
+
+	// datePattern matches phone number candidates starting with a date, e.g. "2024-01-15 10"
+	datePattern = regexp.MustCompile(`^(?:\d{4}[-./]\d{1,2}[-./]\d{1,2}|\d{1,2}[-./]\d{1,2}[-./]\d{4})(?:\D|$)`)
+
+	// tokenPattern matches personal data encrypted by the redactor, e.g. "[pii:...]"
+	tokenPattern = regexp.MustCompile(`\[pii:([A-Za-z0-9_-]+)\]`)
+)
+
+// Match is personal data found in a text
+type Match struct {
+	Kind  Kind
+	Start int // Byte offset of the first character
+	End   int // Byte offset after the last character
+}
+
+// Find returns the personal data in a text, in the order it appears. E-mail addresses,
+// card numbers passing the Luhn check and phone numbers of 10 to 15 digits, either grouped or
+// after a "+", are detected; dates, IP addresses and version numbers are not taken for phone
+// numbers, and personal data encrypted by the redactor is skipped.
+func Find(text string) []Match {
+	var matches []Match
+	taken := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.End && m.Start < end {
+				return true
+			}
+		}
+		return false
+	}
+	add := func(kind Kind, pattern *regexp.Regexp, valid func(string) bool) {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			if !taken(loc[0], loc[1]) && (valid == nil || valid(text[loc[0]:loc[1]])) {
+				matches = append(matches, Match{Kind: kind, Start: loc[0], End: loc[1]})
+			}
+		}
+	}
+
+	// Encrypted data is found first so that its base64 text is not taken for a phone number
+	add("", tokenPattern, nil)
+	add(KindEmail, emailPattern, nil)
+	add(KindCard, cardPattern, luhnValid)
+	add(KindPhone, phonePattern, phoneValid)
+
+	found := matches[:0]
+	for _, m := range matches {
+		if m.Kind != "" {
+			found = append(found, m)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+	return found
+}
+
+// Redactor masks or encrypts the personal data in texts
+type Redactor struct {
+	aead cipher.AEAD
+}
+
+// NewRedactor creates a new Redactor. The key enables encryption and has to be KeySize
+// bytes; without it, only masking is available.
+func NewRedactor(key []byte) (*Redactor, error) {
+	if key == nil {
+		return &Redactor{}, nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &Redactor{aead: aead}, nil
+}
+
+// Redact returns the text with its personal data masked or encrypted according to the mode
+func (r *Redactor) Redact(text string, mode Mode) (string, error) {
+	switch mode {
+	case ModeMask:
+		return r.replace(text, func(kind Kind, _ string) (string, error) {
+			return "[" + string(kind) + "]", nil
+		})
+	case ModeEncrypt:
+		if r.aead == nil {
+			return "", fmt.Errorf("encryption of personal data requires an encryption key")
+		}
+		return r.replace(text, func(_ Kind, value string) (string, error) {
+			return r.encrypt(value)
+		})
+	default:
+		return text, nil
+	}
+}
+
+// Reveal returns the text with the personal data encrypted by Redact decrypted. Data that
+// cannot be decrypted, e.g. because the key changed, is left encrypted.
+func (r *Redactor) Reveal(text string) string {
+	if r.aead == nil || !strings.Contains(text, "[pii:") {
+		return text
+	}
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		value, err := r.decrypt(tokenPattern.FindStringSubmatch(token)[1])
+		if err != nil {
+			return token
+		}
+		return value
+	})
+}
+
+// replace replaces the personal data in a text with the result of fn
+func (r *Redactor) replace(text string, fn func(kind Kind, value string) (string, error)) (string, error) {
+	matches := Find(text)
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		replacement, err := fn(m.Kind, text[m.Start:m.End])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text[last:m.Start])
+		b.WriteString(replacement)
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
+
+// encrypt encrypts a value into a "[pii:...]" token holding the nonce and the ciphertext
+func (r *Redactor) encrypt(value string) (string, error) {
+	nonce := make([]byte, r.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := r.aead.Seal(nonce, nonce, []byte(value), nil)
+	return "[pii:" + base64.RawURLEncoding.EncodeToString(sealed) + "]", nil
+}
+
+// decrypt decrypts the base64 payload of a "[pii:...]" token
+func (r *Redactor) decrypt(payload string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+	size := r.aead.NonceSize()
+	if len(sealed) < size {
+		return "", fmt.Errorf("encrypted value too short")
+	}
+	value, err := r.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// luhnValid returns true if the digits of a number pass the Luhn check used by card numbers
+func luhnValid(number string) bool {
+	sum, count := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if count%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		count++
+	}
+	return count >= 13 && sum%10 == 0
+}
+
+// phoneValid returns true if a phone number candidate has 10 to 15 digits and is neither a
+// date nor, like IP addresses and version numbers, groups of digits separated by dots only
+// that are four groups of up to three digits or have a single digit after the first group
+func phoneValid(number string) bool {
+	n := countDigits(number)
+	if n < 10 || n > 15 || datePattern.MatchString(number) {
+		return false
+	}
+	if strings.ContainsAny(number, " -()+") {
+		return true
+	}
+
+	groups := strings.Split(number, ".")
+	quad := len(groups) == 4
+	for i, group := range groups {
+		if len(group) > 3 {
+			quad = false
+		}
+		if i > 0 && len(group) == 1 {
+			return false
+		}
+	}
+	return !quad
+}
+
+// countDigits returns the number of digits in a string
+func countDigits(s string) int {
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n
+}
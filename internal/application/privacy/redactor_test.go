@@ -0,0 +1,108 @@
+package privacy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey() []byte {
+	return bytes.Repeat([]byte{7}, KeySize)
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Kind
+	}{
+		{name: "email", text: "write to alice.smith+bot@example.co.uk please", want: []Kind{KindEmail}},
+		{name: "international phone", text: "call +1 (555) 123-4567 tonight", want: []Kind{KindPhone}},
+		{name: "local phone", text: "my number is 8 916 123 45 67", want: []Kind{KindPhone}},
+		{name: "card with spaces", text: "card 4111 1111 1111 1111 exp 12/29", want: []Kind{KindCard}},
+		{name: "card without separators", text: "5500005555555559", want: []Kind{KindCard}},
+		{name: "all kinds in order", text: "bob@example.com, 4111-1111-1111-1111, +44 20 7946 0958", want: []Kind{KindEmail, KindCard, KindPhone}},
+		{name: "dotted phone", text: "call 555.123.4567", want: []Kind{KindPhone}},
+		{name: "phone without separators", text: "call +79161234567", want: []Kind{KindPhone}},
+		{name: "date and short numbers", text: "meet on 2024-01-15 at 10:30, room 42", want: nil},
+		{name: "date and time", text: "2024-01-15 10:30", want: nil},
+		{name: "european date and time", text: "15.01.2024 10 30 00", want: nil},
+		{name: "ip address", text: "server 192.168.100.200 is down", want: nil},
+		{name: "version", text: "upgraded to 10.2.1.44551", want: nil},
+		{name: "order number", text: "order 123456789012", want: nil},
+		{name: "order number with dashes", text: "order 2024-123456-78", want: nil},
+		{name: "number failing the luhn check", text: "order 4111 1111 1111 1112", want: nil},
+		{name: "encrypted data", text: "[pii:ABC123456789012345]", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kinds []Kind
+			for _, m := range Find(tt.text) {
+				kinds = append(kinds, m.Kind)
+			}
+			assert.Equal(t, tt.want, kinds)
+		})
+	}
+}
+
+func TestRedactor_Mask(t *testing.T) {
+	redactor, err := NewRedactor(nil)
+	require.NoError(t, err)
+
+	masked, err := redactor.Redact("I'm bob@example.com, card 4111 1111 1111 1111, phone +1 555 123 4567.", ModeMask)
+	require.NoError(t, err)
+	assert.Equal(t, "I'm [email], card [card], phone [phone].", masked)
+
+	kept, err := redactor.Redact("Call me on 2024-01-15 10:30 at 8 916 123 45 67", ModeMask)
+	require.NoError(t, err)
+	assert.Equal(t, "Call me on 2024-01-15 10:30 at [phone]", kept)
+
+	_, err = redactor.Redact("bob@example.com", ModeEncrypt)
+	assert.Error(t, err)
+}
+
+func TestRedactor_EncryptReveal(t *testing.T) {
+	redactor, err := NewRedactor(testKey())
+	require.NoError(t, err)
+
+	text := "Send the invoice to bob@example.com or call +1 555 123 4567"
+	encrypted, err := redactor.Redact(text, ModeEncrypt)
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "bob@example.com")
+	assert.NotContains(t, encrypted, "555")
+	assert.True(t, strings.HasPrefix(encrypted, "Send the invoice to [pii:"))
+	assert.Equal(t, text, redactor.Reveal(encrypted))
+
+	// Encrypted data is not encrypted again
+	again, err := redactor.Redact(encrypted, ModeEncrypt)
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, again)
+
+	// A different key leaves the data encrypted
+	other, err := NewRedactor(bytes.Repeat([]byte{8}, KeySize))
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, other.Reveal(encrypted))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+	assert.False(t, DefaultConfig().Enabled())
+
+	config := &Config{Mode: ModeMask, Workspaces: map[string]Mode{"support": ModeEncrypt}}
+	assert.Error(t, config.Validate(), "encrypt mode requires a key")
+
+	config.Key = testKey()
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, ModeEncrypt, config.ModeFor("support"))
+	assert.Equal(t, ModeMask, config.ModeFor("default"))
+
+	config.Key = []byte("short")
+	assert.Error(t, config.Validate())
+
+	config = &Config{Mode: "hide"}
+	assert.Error(t, config.Validate())
+}
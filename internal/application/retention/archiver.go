@@ -0,0 +1,71 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Archiver exports records before they are deleted by data retention
+type Archiver interface {
+	// Archive stores records of the given kind ("messages", "tasks" or "logs")
+	Archive(ctx context.Context, kind string, records []any) error
+}
+
+// FileArchiver appends records as JSON lines to one file per kind and day,
+// named "<kind>-<YYYY-MM-DD>.jsonl" in the archive directory
+type FileArchiver struct {
+	dir string
+	now func() time.Time
+	mu  sync.Mutex
+}
+
+// Compile-time check that FileArchiver implements Archiver
+var _ Archiver = (*FileArchiver)(nil)
+
+// NewFileArchiver creates a new FileArchiver writing to dir
+func NewFileArchiver(dir string) *FileArchiver {
+	return &FileArchiver{dir: dir, now: time.Now}
+}
+
+// Archive appends the records to the archive file of the current day
+func (a *FileArchiver) Archive(ctx context.Context, kind string, records []any) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(a.dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.jsonl", kind, a.now().UTC().Format("2006-01-02"))
+	f, err := os.OpenFile(filepath.Join(a.dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %w", err)
+	}
+
+	enc := json.NewEncoder(f)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write archive record: %w", err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close archive file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package retention
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidationError represents a retention configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Policy defines how long records of one kind are kept
+type Policy struct {
+	// MaxAge is how long records are kept; zero keeps records forever
+	MaxAge time.Duration
+
+	// Archive exports records to the archiver before they are deleted
+	Archive bool
+}
+
+// Enabled returns true if records of this kind are ever purged
+func (p Policy) Enabled() bool {
+	return p.MaxAge > 0
+}
+
+// Config holds configuration for the retention service
+type Config struct {
+	// Interval is how often a retention pass runs
+	Interval time.Duration
+
+	// DryRun only counts the records that would be purged, without archiving or deleting them
+	DryRun bool
+
+	// BatchSize is the number of records read per page while archiving
+	BatchSize int
+
+	// Messages is the retention policy for messages
+	Messages Policy
+
+	// Tasks is the retention policy for tasks
+	Tasks Policy
+
+	// Logs is the retention policy for logs
+	Logs Policy
+}
+
+// DefaultConfig returns the default configuration for the retention service.
+// All policies keep records forever until a max age is configured.
+func DefaultConfig() *Config {
+	return &Config{
+		Interval:  24 * time.Hour,
+		BatchSize: 500,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.Interval <= 0 {
+		return &ValidationError{Field: "Interval", Message: "must be positive"}
+	}
+
+	if c.BatchSize <= 0 {
+		return &ValidationError{Field: "BatchSize", Message: "must be positive"}
+	}
+
+	policies := []struct {
+		field  string
+		policy Policy
+	}{
+		{"Messages", c.Messages},
+		{"Tasks", c.Tasks},
+		{"Logs", c.Logs},
+	}
+	for _, p := range policies {
+		if p.policy.MaxAge < 0 {
+			return &ValidationError{Field: p.field + ".MaxAge", Message: "must not be negative"}
+		}
+	}
+
+	return nil
+}
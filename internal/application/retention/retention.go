@@ -0,0 +1,382 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// Record kinds handled by the retention service
+const (
+	KindMessages = "messages"
+	KindTasks    = "tasks"
+	KindLogs     = "logs"
+)
+
+// RetentionMetrics holds all metrics for the retention service
+type RetentionMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	MessagesPurged  *metrics.Counter
+	TasksPurged     *metrics.Counter
+	LogsPurged      *metrics.Counter
+	RecordsArchived *metrics.Counter
+	DryRunMatched   *metrics.Counter
+	RunsFailed      *metrics.Counter
+}
+
+// NewRetentionMetrics creates a new RetentionMetrics instance
+func NewRetentionMetrics() *RetentionMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &RetentionMetrics{
+		registry: registry,
+
+		MessagesPurged:  registry.GetCounter("retention_messages_purged_total"),
+		TasksPurged:     registry.GetCounter("retention_tasks_purged_total"),
+		LogsPurged:      registry.GetCounter("retention_logs_purged_total"),
+		RecordsArchived: registry.GetCounter("retention_records_archived_total"),
+		DryRunMatched:   registry.GetCounter("retention_dry_run_matched_total"),
+		RunsFailed:      registry.GetCounter("retention_runs_failed_total"),
+	}
+}
+
+// Registry returns the registry holding the retention service metrics
+func (m *RetentionMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Result describes what a retention pass did with one kind of records
+type Result struct {
+	Kind     string
+	Cutoff   time.Time
+	Matched  int
+	Archived int
+	Deleted  int
+	DryRun   bool
+}
+
+// target binds a record kind to its repository and policy
+type target struct {
+	kind   string
+	policy Policy
+	purged *metrics.Counter
+	count  func(ctx context.Context, before time.Time) (int, error)
+	find   func(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]any, *repository.Cursor, error)
+	delete func(ctx context.Context, before time.Time) (int, error)
+}
+
+// Retention periodically deletes messages, tasks and logs older than the
+// configured max age of their kind, optionally exporting them to an archiver
+// first. Messages and tasks of pinned sessions are never purged. In dry-run
+// mode records are only counted.
+type Retention struct {
+	messageRepo repository.MessageRepository
+	taskRepo    repository.TaskRepository
+	logRepo     repository.LogRepository
+	archiver    Archiver
+	logger      logging.Logger
+	config      *Config
+	metrics     *RetentionMetrics
+	now         func() time.Time
+	leadership  ports.Leadership
+
+	mu      sync.Mutex
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewRetention creates a new Retention instance
+//
+// Parameters:
+//   - messageRepo: MessageRepository for purging messages (optional)
+//   - taskRepo: TaskRepository for purging tasks (optional)
+//   - logRepo: LogRepository for purging logs (optional)
+//   - archiver: Archiver for exporting records before deletion (optional)
+//   - logger: Structured logger for logging
+//   - config: Retention configuration (uses defaults if nil)
+//
+// Returns:
+//   - *Retention: Initialized retention service
+func NewRetention(
+	messageRepo repository.MessageRepository,
+	taskRepo repository.TaskRepository,
+	logRepo repository.LogRepository,
+	archiver Archiver,
+	logger logging.Logger,
+	config *Config,
+) *Retention {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	if err := config.Validate(); err != nil {
+		logger.Error("invalid retention configuration, using defaults", "error", err)
+		config = DefaultConfig()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Retention{
+		messageRepo: messageRepo,
+		taskRepo:    taskRepo,
+		logRepo:     logRepo,
+		archiver:    archiver,
+		logger:      logger,
+		config:      config,
+		metrics:     NewRetentionMetrics(),
+		now:         time.Now,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Metrics returns the retention metrics
+func (r *Retention) Metrics() *RetentionMetrics {
+	return r.metrics
+}
+
+// SetLeadership makes the periodic passes run only while this instance is the leader, so that
+// instances sharing the database do not archive the same records twice. It must be called
+// before Start.
+func (r *Retention) SetLeadership(leadership ports.Leadership) {
+	r.leadership = leadership
+}
+
+// Start runs a retention pass immediately and then once per interval
+func (r *Retention) Start() error {
+	r.mu.Lock()
+	if r.started {
+		r.mu.Unlock()
+		return fmt.Errorf("retention already started")
+	}
+	r.started = true
+	r.mu.Unlock()
+
+	r.logger.Info("starting retention", "interval", r.config.Interval, "dry_run", r.config.DryRun)
+
+	r.wg.Add(1)
+	go r.loop()
+
+	return nil
+}
+
+// Stop stops the retention loop and waits for a running pass to finish
+func (r *Retention) Stop() error {
+	r.logger.Info("stopping retention")
+
+	r.cancel()
+	r.wg.Wait()
+
+	r.logger.Info("retention stopped")
+
+	return nil
+}
+
+func (r *Retention) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if r.leadership == nil || r.leadership.IsLeader() {
+			if _, err := r.RunOnce(r.ctx); err != nil && r.ctx.Err() == nil {
+				r.logger.Error("retention pass failed", "error", err)
+			}
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs a single retention pass over all kinds with a max age.
+// A failure for one kind does not stop the others.
+//
+// Returns:
+//   - []Result: What was done for each processed kind
+//   - error: Combined error of all failed kinds
+func (r *Retention) RunOnce(ctx context.Context) ([]Result, error) {
+	now := r.now()
+
+	var results []Result
+	var errs []error
+
+	for _, t := range r.targets() {
+		if !t.policy.Enabled() {
+			continue
+		}
+
+		result, err := r.purge(ctx, t, now.Add(-t.policy.MaxAge))
+		if err != nil {
+			r.metrics.RunsFailed.Inc()
+			errs = append(errs, fmt.Errorf("%s: %w", t.kind, err))
+			continue
+		}
+		results = append(results, result)
+
+		r.logger.Info("retention pass completed",
+			"kind", result.Kind,
+			"cutoff", result.Cutoff,
+			"matched", result.Matched,
+			"archived", result.Archived,
+			"deleted", result.Deleted,
+			"dry_run", result.DryRun,
+		)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// purge archives and deletes the records of one kind created before cutoff
+func (r *Retention) purge(ctx context.Context, t target, cutoff time.Time) (Result, error) {
+	result := Result{Kind: t.kind, Cutoff: cutoff, DryRun: r.config.DryRun}
+
+	matched, err := t.count(ctx, cutoff)
+	if err != nil {
+		return result, fmt.Errorf("failed to count records: %w", err)
+	}
+	result.Matched = matched
+
+	if r.config.DryRun {
+		r.metrics.DryRunMatched.Add(int64(matched))
+		return result, nil
+	}
+
+	if matched == 0 {
+		return result, nil
+	}
+
+	if t.policy.Archive {
+		archived, err := r.archive(ctx, t, cutoff)
+		result.Archived = archived
+		if err != nil {
+			return result, err
+		}
+	}
+
+	deleted, err := t.delete(ctx, cutoff)
+	if err != nil {
+		return result, fmt.Errorf("failed to delete records: %w", err)
+	}
+	result.Deleted = deleted
+	t.purged.Add(int64(deleted))
+
+	return result, nil
+}
+
+// archive exports all records of one kind created before cutoff, page by page
+func (r *Retention) archive(ctx context.Context, t target, cutoff time.Time) (int, error) {
+	if r.archiver == nil {
+		return 0, fmt.Errorf("archiving is enabled but no archiver is configured")
+	}
+
+	opts := repository.QueryOptions{Limit: r.config.BatchSize}
+	archived := 0
+
+	for {
+		records, last, err := t.find(ctx, cutoff, opts)
+		if err != nil {
+			return archived, fmt.Errorf("failed to read records: %w", err)
+		}
+
+		if len(records) == 0 {
+			return archived, nil
+		}
+
+		if err := r.archiver.Archive(ctx, t.kind, records); err != nil {
+			return archived, fmt.Errorf("failed to archive records: %w", err)
+		}
+		archived += len(records)
+		r.metrics.RecordsArchived.Add(int64(len(records)))
+
+		if len(records) < opts.Limit {
+			return archived, nil
+		}
+		opts.After = last
+	}
+}
+
+// targets returns the kinds backed by a repository
+func (r *Retention) targets() []target {
+	var targets []target
+
+	if r.messageRepo != nil {
+		targets = append(targets, target{
+			kind:   KindMessages,
+			policy: r.config.Messages,
+			purged: r.metrics.MessagesPurged,
+			count:  r.messageRepo.CountOlderThan,
+			find: func(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]any, *repository.Cursor, error) {
+				messages, err := r.messageRepo.FindOlderThan(ctx, before, opts)
+				return page(messages, err, func(m *entity.Message) repository.Cursor {
+					return repository.Cursor{CreatedAt: m.CreatedAt, ID: string(m.ID)}
+				})
+			},
+			delete: r.messageRepo.DeleteOlderThan,
+		})
+	}
+
+	if r.taskRepo != nil {
+		targets = append(targets, target{
+			kind:   KindTasks,
+			policy: r.config.Tasks,
+			purged: r.metrics.TasksPurged,
+			count:  r.taskRepo.CountOlderThan,
+			find: func(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]any, *repository.Cursor, error) {
+				tasks, err := r.taskRepo.FindOlderThan(ctx, before, opts)
+				return page(tasks, err, func(t *entity.Task) repository.Cursor {
+					return repository.Cursor{CreatedAt: t.CreatedAt, ID: string(t.ID)}
+				})
+			},
+			delete: r.taskRepo.DeleteOlderThan,
+		})
+	}
+
+	if r.logRepo != nil {
+		targets = append(targets, target{
+			kind:   KindLogs,
+			policy: r.config.Logs,
+			purged: r.metrics.LogsPurged,
+			count:  r.logRepo.CountOlderThan,
+			find: func(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]any, *repository.Cursor, error) {
+				logs, err := r.logRepo.FindOlderThan(ctx, before, opts)
+				return page(logs, err, func(l *entity.Log) repository.Cursor {
+					return repository.Cursor{CreatedAt: l.CreatedAt, ID: string(l.ID)}
+				})
+			},
+			delete: r.logRepo.DeleteOlderThan,
+		})
+	}
+
+	return targets
+}
+
+// page converts a page of records to archivable values and returns the cursor of the last one
+func page[T any](records []T, err error, cursor func(T) repository.Cursor) ([]any, *repository.Cursor, error) {
+	if err != nil || len(records) == 0 {
+		return nil, nil, err
+	}
+
+	values := make([]any, len(records))
+	for i, record := range records {
+		values[i] = record
+	}
+
+	last := cursor(records[len(records)-1])
+	return values, &last, nil
+}
@@ -0,0 +1,314 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockMessageRepository is an in-memory mock of the retention methods of repository.MessageRepository
+type mockMessageRepository struct {
+	repository.MessageRepository
+	messages  []*entity.Message
+	deleteErr error
+	deletes   int
+}
+
+func (m *mockMessageRepository) older(before time.Time) []*entity.Message {
+	var result []*entity.Message
+	for _, msg := range m.messages {
+		if msg.CreatedAt.Before(before) {
+			result = append(result, msg)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func (m *mockMessageRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	return len(m.older(before)), nil
+}
+
+func (m *mockMessageRepository) FindOlderThan(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]*entity.Message, error) {
+	var result []*entity.Message
+	for _, msg := range m.older(before) {
+		if opts.After != nil {
+			if msg.CreatedAt.Before(opts.After.CreatedAt) ||
+				(msg.CreatedAt.Equal(opts.After.CreatedAt) && string(msg.ID) <= opts.After.ID) {
+				continue
+			}
+		}
+		result = append(result, msg)
+		if opts.Limit > 0 && len(result) == opts.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (m *mockMessageRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	m.deletes++
+	if m.deleteErr != nil {
+		return 0, m.deleteErr
+	}
+	var kept []*entity.Message
+	for _, msg := range m.messages {
+		if !msg.CreatedAt.Before(before) {
+			kept = append(kept, msg)
+		}
+	}
+	deleted := len(m.messages) - len(kept)
+	m.messages = kept
+	return deleted, nil
+}
+
+// mockLogRepository is a mock of the retention methods of repository.LogRepository
+type mockLogRepository struct {
+	repository.LogRepository
+	count int
+	err   error
+}
+
+func (m *mockLogRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	return m.count, m.err
+}
+
+func (m *mockLogRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	return m.count, m.err
+}
+
+// mockArchiver records archived batches
+type mockArchiver struct {
+	batches [][]any
+	kinds   []string
+	err     error
+}
+
+func (m *mockArchiver) Archive(ctx context.Context, kind string, records []any) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.kinds = append(m.kinds, kind)
+	m.batches = append(m.batches, records)
+	return nil
+}
+
+var testNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestMessages(ages ...time.Duration) []*entity.Message {
+	messages := make([]*entity.Message, len(ages))
+	for i, age := range ages {
+		msg := entity.NewUserMessage("session-1", "hello")
+		msg.ID = valueobject.MessageID(strings.Repeat("m", i+1))
+		msg.CreatedAt = testNow.Add(-age)
+		messages[i] = msg
+	}
+	return messages
+}
+
+func newTestRetention(messageRepo repository.MessageRepository, logRepo repository.LogRepository, archiver Archiver, config *Config) *Retention {
+	r := NewRetention(messageRepo, nil, logRepo, archiver, logging.NewNoopLogger(), config)
+	r.now = func() time.Time { return testNow }
+	return r
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+
+	config := DefaultConfig()
+	config.Interval = 0
+	assert.Error(t, config.Validate())
+
+	config = DefaultConfig()
+	config.BatchSize = 0
+	assert.Error(t, config.Validate())
+
+	config = DefaultConfig()
+	config.Tasks.MaxAge = -time.Hour
+	var validationErr *ValidationError
+	require.ErrorAs(t, config.Validate(), &validationErr)
+	assert.Equal(t, "Tasks.MaxAge", validationErr.Field)
+}
+
+func TestNewRetention_InvalidConfigUsesDefaults(t *testing.T) {
+	r := newTestRetention(nil, nil, nil, &Config{})
+	assert.Equal(t, DefaultConfig(), r.config)
+}
+
+func TestRetention_RunOnceDeletesOldRecords(t *testing.T) {
+	day := 24 * time.Hour
+	messageRepo := &mockMessageRepository{messages: newTestMessages(40*day, 31*day, 10*day)}
+
+	config := DefaultConfig()
+	config.Messages = Policy{MaxAge: 30 * day}
+	r := newTestRetention(messageRepo, nil, nil, config)
+
+	results, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.Equal(t, KindMessages, results[0].Kind)
+	assert.Equal(t, testNow.Add(-30*day), results[0].Cutoff)
+	assert.Equal(t, 2, results[0].Matched)
+	assert.Equal(t, 2, results[0].Deleted)
+	assert.Equal(t, 0, results[0].Archived)
+	assert.Len(t, messageRepo.messages, 1)
+	assert.Equal(t, int64(2), r.Metrics().MessagesPurged.Get())
+}
+
+func TestRetention_RunOnceDryRun(t *testing.T) {
+	day := 24 * time.Hour
+	messageRepo := &mockMessageRepository{messages: newTestMessages(40*day, 31*day, 10*day)}
+	archiver := &mockArchiver{}
+
+	config := DefaultConfig()
+	config.DryRun = true
+	config.Messages = Policy{MaxAge: 30 * day, Archive: true}
+	r := newTestRetention(messageRepo, nil, archiver, config)
+
+	results, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.True(t, results[0].DryRun)
+	assert.Equal(t, 2, results[0].Matched)
+	assert.Equal(t, 0, results[0].Deleted)
+	assert.Len(t, messageRepo.messages, 3)
+	assert.Zero(t, messageRepo.deletes)
+	assert.Empty(t, archiver.batches)
+	assert.Equal(t, int64(2), r.Metrics().DryRunMatched.Get())
+	assert.Zero(t, r.Metrics().MessagesPurged.Get())
+}
+
+func TestRetention_RunOnceArchivesInBatches(t *testing.T) {
+	day := 24 * time.Hour
+	messageRepo := &mockMessageRepository{messages: newTestMessages(35*day, 34*day, 33*day, 32*day, 31*day, day)}
+	archiver := &mockArchiver{}
+
+	config := DefaultConfig()
+	config.BatchSize = 2
+	config.Messages = Policy{MaxAge: 30 * day, Archive: true}
+	r := newTestRetention(messageRepo, nil, archiver, config)
+
+	results, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.Equal(t, 5, results[0].Archived)
+	assert.Equal(t, 5, results[0].Deleted)
+	require.Len(t, archiver.batches, 3)
+	assert.Len(t, archiver.batches[0], 2)
+	assert.Len(t, archiver.batches[2], 1)
+	assert.Equal(t, []string{KindMessages, KindMessages, KindMessages}, archiver.kinds)
+	assert.Equal(t, int64(5), r.Metrics().RecordsArchived.Get())
+
+	var ids []string
+	for _, batch := range archiver.batches {
+		for _, record := range batch {
+			ids = append(ids, string(record.(*entity.Message).ID))
+		}
+	}
+	assert.Equal(t, []string{"m", "mm", "mmm", "mmmm", "mmmmm"}, ids)
+}
+
+func TestRetention_ArchiveFailureKeepsRecords(t *testing.T) {
+	day := 24 * time.Hour
+	messageRepo := &mockMessageRepository{messages: newTestMessages(40 * day)}
+
+	config := DefaultConfig()
+	config.Messages = Policy{MaxAge: 30 * day, Archive: true}
+	r := newTestRetention(messageRepo, nil, &mockArchiver{err: errors.New("disk full")}, config)
+
+	_, err := r.RunOnce(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+	assert.Zero(t, messageRepo.deletes)
+	assert.Len(t, messageRepo.messages, 1)
+	assert.Equal(t, int64(1), r.Metrics().RunsFailed.Get())
+}
+
+func TestRetention_ArchiveWithoutArchiver(t *testing.T) {
+	day := 24 * time.Hour
+	messageRepo := &mockMessageRepository{messages: newTestMessages(40 * day)}
+
+	config := DefaultConfig()
+	config.Messages = Policy{MaxAge: 30 * day, Archive: true}
+	r := newTestRetention(messageRepo, nil, nil, config)
+
+	_, err := r.RunOnce(context.Background())
+	require.Error(t, err)
+	assert.Zero(t, messageRepo.deletes)
+}
+
+func TestRetention_FailureDoesNotStopOtherKinds(t *testing.T) {
+	day := 24 * time.Hour
+	messageRepo := &mockMessageRepository{messages: newTestMessages(40 * day)}
+	logRepo := &mockLogRepository{err: errors.New("database is locked")}
+
+	config := DefaultConfig()
+	config.Messages = Policy{MaxAge: 30 * day}
+	config.Logs = Policy{MaxAge: 7 * day}
+	r := newTestRetention(messageRepo, logRepo, nil, config)
+
+	results, err := r.RunOnce(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), KindLogs)
+	require.Len(t, results, 1)
+	assert.Equal(t, KindMessages, results[0].Kind)
+	assert.Empty(t, messageRepo.messages)
+}
+
+func TestRetention_RunOnceSkipsKindsWithoutMaxAge(t *testing.T) {
+	logRepo := &mockLogRepository{err: errors.New("should not be called")}
+	r := newTestRetention(&mockMessageRepository{}, logRepo, nil, DefaultConfig())
+
+	results, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestRetention_StartTwice(t *testing.T) {
+	r := newTestRetention(&mockMessageRepository{}, nil, nil, DefaultConfig())
+
+	require.NoError(t, r.Start())
+	defer r.Stop()
+
+	assert.Error(t, r.Start())
+}
+
+func TestFileArchiver_Archive(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	archiver := NewFileArchiver(dir)
+	archiver.now = func() time.Time { return testNow }
+
+	messages := newTestMessages(time.Hour, 2*time.Hour)
+	require.NoError(t, archiver.Archive(context.Background(), KindMessages, []any{messages[0]}))
+	require.NoError(t, archiver.Archive(context.Background(), KindMessages, []any{messages[1]}))
+	require.NoError(t, archiver.Archive(context.Background(), KindMessages, nil))
+
+	data, err := os.ReadFile(filepath.Join(dir, "messages-2026-06-01.jsonl"))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"id":"m"`)
+	assert.Contains(t, lines[1], `"id":"mm"`)
+}
@@ -0,0 +1,308 @@
+package router
+
+import (
+	"context"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// sessionsListLimit is the number of sessions listed by the /sessions chat command
+const sessionsListLimit = 10
+
+// registerBuiltinCommands registers the chat commands every router answers
+func (r *MessageRouter) registerBuiltinCommands() {
+	builtins := []Command{
+		{Name: CancelCommand, Description: "Cancel the reply being generated", Handler: r.handleCancelCommand},
+		{Name: HelpCommand, Description: "List the available commands", Handler: r.handleHelpCommand},
+		{Name: NewSessionCommand, Description: "Start a new session", Handler: r.handleNewSessionCommand},
+		{Name: ResetCommand, Description: "Clear the conversation of the current session", Handler: r.handleResetCommand},
+		{Name: ForkCommand, Description: "Continue in a copy of the current session", Usage: "[message_id]", Handler: r.handleForkCommand},
+		{Name: FeedbackCommand, Description: "Rate a reply", Usage: "<message_id> up|down [comment]", Handler: r.handleFeedbackCommand},
+		{Name: ExportCommand, Description: "Send a transcript of the current session", Usage: "[json|markdown]", Handler: r.handleExportCommand},
+		{Name: SessionsCommand, Description: "List your recent sessions", Handler: r.handleSessionsCommand},
+		{Name: InstructCommand, Description: "Show or set your custom instructions", Usage: "[session] [<text>|clear]", Handler: r.handleInstructCommand},
+		{Name: SettingsCommand, Description: "Show or change your settings", Usage: "[<name> <value>|reset]", Handler: r.handleSettingsCommand},
+		{Name: SkillsCommand, Description: "List the available skills", Handler: r.handleSkillsCommand},
+		{Name: UsageCommand, Description: "Show the usage of the current session", Handler: r.handleUsageCommand},
+	}
+	for _, command := range builtins {
+		r.commands[command.Name] = &command
+	}
+}
+
+// SetSkillRepository sets the repository of the skills listed by the /skills chat command.
+// Enabled skills whose metadata names a "command" can also be run as chat commands; the
+// optional "command_usage" and "command_admin_only" metadata describe and restrict them.
+func (r *MessageRouter) SetSkillRepository(skills repository.SkillRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.skills = skills
+}
+
+// skillRepository returns the skill repository, or nil if none is set
+func (r *MessageRouter) skillRepository() repository.SkillRepository {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.skills
+}
+
+// skillCommands returns the chat commands registered by enabled skills available in the
+// workspace of a connector
+func (r *MessageRouter) skillCommands(ctx context.Context, connector string) []*Command {
+	skills := r.skillRepository()
+	if skills == nil {
+		return nil
+	}
+
+	list, err := skills.List(ctx)
+	if err != nil {
+		r.logger.Warn("failed to list skill commands", "error", err)
+		return nil
+	}
+	workspace := r.workspace(connector)
+
+	var commands []*Command
+	for _, skill := range list {
+		metadata := skill.GetMetadata()
+		name, _ := metadata["command"].(string)
+		if !skill.IsEnabled() || !skill.IsAvailableIn(workspace) || name == "" {
+			continue
+		}
+
+		description, _ := metadata["description"].(string)
+		usage, _ := metadata["command_usage"].(string)
+		adminOnly, _ := metadata["command_admin_only"].(bool)
+		commands = append(commands, &Command{
+			Name:        strings.ToLower(name),
+			Description: description,
+			Usage:       usage,
+			AdminOnly:   adminOnly,
+			Handler:     r.skillCommandHandler(skill.Name),
+		})
+	}
+	return commands
+}
+
+// skillCommandHandler returns the handler running a skill with the command arguments as input
+func (r *MessageRouter) skillCommandHandler(skillName string) CommandHandler {
+	return func(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+		if r.orchestrator == nil {
+			return nil, NewCommandError(r.Translate(call.Language, msgSkillsUnavailable), nil)
+		}
+
+		input := map[string]interface{}{
+			"args": call.Args,
+			"text": strings.Join(call.Args, " "),
+		}
+		resp, err := r.orchestrator.ExecuteSkill(ctx, call.Session.ID.String(), skillName, input)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, NewCommandError(r.Translate(call.Language, msgSkillFailed, call.Prefix+call.Name, resp.Error), nil)
+		}
+
+		output := resp.Output
+		if output == "" {
+			output = r.Translate(call.Language, msgSkillDone)
+		}
+		return &channels.Response{Content: output}, nil
+	}
+}
+
+// handleHelpCommand lists the commands the user may run
+func (r *MessageRouter) handleHelpCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	var b strings.Builder
+	b.WriteString(r.Translate(call.Language, msgHelpTitle))
+	for _, command := range r.availableCommands(ctx, call.Connector, call.Message.UserID) {
+		b.WriteString("\n" + formatCommand(call.Prefix, command))
+		description := command.Description
+		if translated, ok := r.catalog.Lookup(call.Language, commandDescriptionKey(command.Name)); ok {
+			description = translated
+		}
+		if description != "" {
+			b.WriteString(" - " + description)
+		}
+	}
+	return &channels.Response{Content: b.String()}, nil
+}
+
+// handleNewSessionCommand starts a new session, which the following messages of the user continue
+func (r *MessageRouter) handleNewSessionCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	session, err := r.startSession(ctx, call.Connector, call.Session.UserID.String())
+	if err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgSessionFailed), err)
+	}
+
+	return &channels.Response{
+		Content: r.Translate(call.Language, msgNewSessionDone),
+		Metadata: map[string]interface{}{
+			"session_id": session.ID.String(),
+		},
+	}, nil
+}
+
+// handleForkCommand starts a session with the history of the current one up to a message,
+// which the following messages of the user continue
+func (r *MessageRouter) handleForkCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	r.mu.RLock()
+	forker := r.forker
+	r.mu.RUnlock()
+
+	if forker == nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgForkUnavailable), nil)
+	}
+
+	fromMessageID := ""
+	if len(call.Args) > 0 {
+		fromMessageID = call.Args[0]
+	}
+
+	resp, err := forker.ForkSession(ctx, call.Session.ID.String(), fromMessageID)
+	if err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgForkFailed), err)
+	}
+	if !resp.Success {
+		return nil, ErrCommandUsage
+	}
+
+	return &channels.Response{
+		Content: r.Translate(call.Language, msgForkDone),
+		Metadata: map[string]interface{}{
+			"session_id": resp.Session.ID,
+		},
+	}, nil
+}
+
+// handleResetCommand deletes the messages of the current session
+func (r *MessageRouter) handleResetCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	messages := r.messageRepository()
+	if messages == nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgResetUnavailable), nil)
+	}
+
+	if err := messages.DeleteBySessionID(ctx, call.Session.ID.String()); err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgResetFailed), err)
+	}
+	return &channels.Response{Content: r.Translate(call.Language, msgResetDone)}, nil
+}
+
+// handleExportCommand sends the session transcript to the user as a document
+func (r *MessageRouter) handleExportCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	r.mu.RLock()
+	exporter := r.exporter
+	r.mu.RUnlock()
+
+	if exporter == nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgExportUnavailable), nil)
+	}
+
+	format := dto.ExportFormatMarkdown
+	if len(call.Args) > 0 {
+		format = strings.ToLower(call.Args[0])
+	}
+
+	resp, err := exporter.ExportSession(ctx, call.Session.ID.String(), format)
+	if err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgExportFailed), err)
+	}
+	if !resp.Success {
+		return nil, ErrCommandUsage
+	}
+
+	// Connectors without file support can fall back to the text content
+	return &channels.Response{
+		Type:    channels.ResponseTypeDocument,
+		Content: string(resp.Export.Content),
+		Caption: r.Translate(call.Language, msgExportCaption),
+		Media: &channels.MediaContent{
+			FileData: resp.Export.Content,
+			FileName: resp.Export.FileName,
+		},
+	}, nil
+}
+
+// handleSkillsCommand lists the enabled skills available in the workspace of the connector
+func (r *MessageRouter) handleSkillsCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	skills := r.skillRepository()
+	if skills == nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgSkillsUnavailable), nil)
+	}
+
+	list, err := skills.List(ctx)
+	if err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgSkillsFailed), err)
+	}
+
+	workspace := r.workspace(call.Connector)
+	var b strings.Builder
+	for _, skill := range list {
+		if !skill.IsEnabled() || !skill.IsAvailableIn(workspace) {
+			continue
+		}
+		b.WriteString("\n" + skill.Name)
+		if description, _ := skill.GetMetadata()["description"].(string); description != "" {
+			b.WriteString(" - " + description)
+		}
+	}
+	if b.Len() == 0 {
+		return &channels.Response{Content: r.Translate(call.Language, msgSkillsNone)}, nil
+	}
+	return &channels.Response{Content: r.Translate(call.Language, msgSkillsTitle) + b.String()}, nil
+}
+
+// handleSessionsCommand lists the most recent sessions of the user in the workspace of the
+// connector, newest first, by title or by ID until a title is generated
+func (r *MessageRouter) handleSessionsCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	opts := repository.QueryOptions{Limit: sessionsListLimit, Workspace: r.workspace(call.Connector).String()}
+	sessions, err := r.sessionRepo.FindByUserID(ctx, call.Session.UserID.String(), opts)
+	if err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgSessionsFailed), err)
+	}
+
+	var b strings.Builder
+	b.WriteString(r.Translate(call.Language, msgSessionsTitle))
+	for _, session := range sessions {
+		title := session.Title
+		if title == "" {
+			title = r.Translate(call.Language, msgSessionsUntitled, session.ID)
+		}
+		b.WriteString("\n" + r.Translate(call.Language, msgSessionsItem, title, session.UpdatedAt.UTC().Format("2006-01-02 15:04 UTC")))
+		if session.ID == call.Session.ID {
+			b.WriteString(" " + r.Translate(call.Language, msgSessionsCurrent))
+		}
+	}
+	return &channels.Response{Content: b.String()}, nil
+}
+
+// handleUsageCommand shows the age and size of the current session against the session policy
+func (r *MessageRouter) handleUsageCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	session := call.Session
+	policy := r.config.Session
+
+	started := session.CreatedAt.UTC().Format("2006-01-02 15:04 UTC")
+	lines := []string{r.Translate(call.Language, msgUsageStarted, started)}
+	if policy.MaxAge > 0 {
+		lines[0] = r.Translate(call.Language, msgUsageStartedExpires, started, policy.MaxAge)
+	}
+
+	if messages := r.messageRepository(); messages != nil {
+		found, err := messages.FindBySessionID(ctx, session.ID.String(), repository.QueryOptions{})
+		if err != nil {
+			return nil, NewCommandError(r.Translate(call.Language, msgUsageFailed), err)
+		}
+		if policy.MaxMessages > 0 {
+			lines = append(lines, r.Translate(call.Language, msgUsageMessagesMax, len(found), policy.MaxMessages))
+		} else {
+			lines = append(lines, r.Translate(call.Language, msgUsageMessages, len(found)))
+		}
+	}
+
+	if sessions, err := r.sessionRepo.FindByUserID(ctx, session.UserID.String(), repository.QueryOptions{}); err == nil {
+		lines = append(lines, r.Translate(call.Language, msgUsageSessions, len(sessions)))
+	}
+	return &channels.Response{Content: strings.Join(lines, "\n")}, nil
+}
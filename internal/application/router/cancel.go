@@ -0,0 +1,80 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// ErrGenerationCancelled is the cause of the context of a message whose generation was
+// cancelled with the /cancel chat command
+var ErrGenerationCancelled = errors.New("generation cancelled by the user")
+
+// generation is a message of a user being answered by the orchestrator
+type generation struct {
+	cancel context.CancelCauseFunc
+}
+
+// startGeneration registers the generation of a reply to a user of a connector, so that the
+// /cancel command can abort it along with the LLM calls and skills it runs.
+//
+// Returns:
+//   - context.Context: Context to generate the reply with
+//   - func(): Unregisters the generation; call it once the reply is generated
+func (r *MessageRouter) startGeneration(ctx context.Context, connector, userID string) (context.Context, func()) {
+	genCtx, cancel := context.WithCancelCause(ctx)
+	gen := &generation{cancel: cancel}
+	key := generationKey(connector, userID)
+
+	r.generationsMu.Lock()
+	r.generations[key] = append(r.generations[key], gen)
+	r.generationsMu.Unlock()
+
+	return genCtx, func() {
+		r.generationsMu.Lock()
+		defer r.generationsMu.Unlock()
+		gens := r.generations[key]
+		for i, g := range gens {
+			if g == gen {
+				gens = append(gens[:i], gens[i+1:]...)
+				break
+			}
+		}
+		if len(gens) == 0 {
+			delete(r.generations, key)
+		} else {
+			r.generations[key] = gens
+		}
+		cancel(nil)
+	}
+}
+
+// cancelGenerations aborts the generations in flight for a user of a connector.
+//
+// Returns:
+//   - int: Number of cancelled generations
+func (r *MessageRouter) cancelGenerations(connector, userID string) int {
+	r.generationsMu.Lock()
+	defer r.generationsMu.Unlock()
+
+	gens := r.generations[generationKey(connector, userID)]
+	for _, gen := range gens {
+		gen.cancel(ErrGenerationCancelled)
+	}
+	return len(gens)
+}
+
+// generationKey identifies the generations of a user of a connector
+func generationKey(connector, userID string) string {
+	return connector + ":" + userID
+}
+
+// handleCancelCommand aborts the replies being generated for the user. The router is handling
+// messages of a user concurrently, so /cancel is answered while the generation is in flight.
+func (r *MessageRouter) handleCancelCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	if r.cancelGenerations(call.Connector, call.Message.UserID) == 0 {
+		return &channels.Response{Content: r.Translate(call.Language, msgCancelNothing)}, nil
+	}
+	return &channels.Response{Content: r.Translate(call.Language, msgCancelDone)}, nil
+}
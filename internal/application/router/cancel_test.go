@@ -0,0 +1,64 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// blockingOrchestrator answers no message until its context is done
+type blockingOrchestrator struct {
+	*mockOrchestrator
+	started chan struct{}
+	err     chan error
+}
+
+func (o *blockingOrchestrator) ProcessMessage(ctx context.Context, userID string, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
+	close(o.started)
+	<-ctx.Done()
+	o.err <- context.Cause(ctx)
+	return nil, ctx.Err()
+}
+
+func TestCancelCommand(t *testing.T) {
+	orchestrator := &blockingOrchestrator{mockOrchestrator: newMockOrchestrator(), started: make(chan struct{}), err: make(chan error, 1)}
+	router := &commandTestRouter{
+		MessageRouter: NewMessageRouter(newMockSessionRepository(), orchestrator, nil, logging.NewNoopLogger(), DefaultConfig()),
+		conn:          newMockConnector("telegram"),
+	}
+
+	if reply := router.sendText(t, "/cancel"); reply.Content != "Nothing to cancel." {
+		t.Errorf("Expected nothing to cancel, got %q", reply.Content)
+	}
+
+	handled := make(chan error, 1)
+	go func() {
+		handled <- router.handleMessage(context.Background(), &Request{Connector: router.conn.Name(), Conn: router.conn, Message: &channels.Message{UserID: "user-123", Content: "Write a long story"}})
+	}()
+	select {
+	case <-orchestrator.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the generation to start")
+	}
+
+	if reply := router.sendText(t, "/cancel"); reply.Content != "Cancelled." {
+		t.Errorf("Expected the generation to be cancelled, got %q", reply.Content)
+	}
+	if cause := <-orchestrator.err; !errors.Is(cause, ErrGenerationCancelled) {
+		t.Errorf("Expected the generation context to be cancelled by the user, got %v", cause)
+	}
+	if err := <-handled; err != nil {
+		t.Errorf("Expected a cancelled generation not to fail, got %v", err)
+	}
+	if len(router.conn.sent) != 2 {
+		t.Errorf("Expected no reply to the cancelled message, got %d replies", len(router.conn.sent))
+	}
+	if n := router.cancelGenerations(router.conn.Name(), "user-123"); n != 0 {
+		t.Errorf("Expected the generation to be unregistered, got %d", n)
+	}
+}
@@ -0,0 +1,122 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// sendJobKind is the kind of the jobs sending a message through a connector running on the leader
+const sendJobKind = "connector_send"
+
+// sendJobPayload is the payload of a job sending a message through a connector
+type sendJobPayload struct {
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+	Message   string `json:"message"`
+}
+
+// SetLeadership makes the router run the exclusive connectors, such as Telegram bots, only
+// while this instance is the leader; the others are on standby until LeadershipChanged starts
+// them. Without leadership, all connectors run. It must be called before Start.
+func (r *MessageRouter) SetLeadership(leadership ports.Leadership) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leadership = leadership
+}
+
+// SetJobQueue sets the job queue handing the messages sent through connectors on standby to
+// the leader running them. Without a queue, sending through a connector on standby fails.
+func (r *MessageRouter) SetJobQueue(queue ports.JobQueue) {
+	r.mu.Lock()
+	r.jobQueue = queue
+	r.mu.Unlock()
+	queue.Register(sendJobKind, r.handleSendJob)
+}
+
+// runsHere returns true if a connector runs on this instance. The caller must hold r.mu.
+func (r *MessageRouter) runsHere(conn channels.Connector) bool {
+	return r.leadership == nil || r.leadership.IsLeader() || !channels.IsExclusive(conn)
+}
+
+// onStandby returns true if a connector waits for this instance to lead
+func (r *MessageRouter) onStandby(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.standby[name]
+}
+
+// LeadershipChanged starts the exclusive connectors when this instance becomes the leader and
+// puts them on standby when it loses the leadership. Connectors stopped through StopConnector
+// stay stopped. Before Start, the leadership is only taken into account by Start.
+func (r *MessageRouter) LeadershipChanged(leader bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started {
+		return
+	}
+
+	for name, conn := range r.connectors {
+		if !channels.IsExclusive(conn) || r.stopped[name] || r.standby[name] != leader {
+			continue
+		}
+
+		if leader {
+			if err := r.restartConnector(name, conn); err != nil {
+				r.logger.Error("failed to start connector as the leader", "connector", name, "error", err)
+				continue
+			}
+			delete(r.standby, name)
+			r.logger.Info("connector started as the leader", "connector", name)
+			continue
+		}
+
+		if err := r.haltConnector(r.ctx, name, conn); err != nil {
+			r.logger.Error("failed to stop connector after losing the leadership", "connector", name, "error", err)
+		}
+		r.standby[name] = true
+		r.logger.Info("connector on standby until this instance leads", "connector", name)
+	}
+}
+
+// queueSend queues a message for a connector on standby to be sent by the leader
+func (r *MessageRouter) queueSend(ctx context.Context, connectorName, userID, message string) error {
+	r.mu.RLock()
+	queue := r.jobQueue
+	r.mu.RUnlock()
+	if queue == nil {
+		return fmt.Errorf("connector %s runs on the leader only", connectorName)
+	}
+
+	payload := sendJobPayload{Connector: connectorName, UserID: userID, Message: message}
+	if err := queue.Enqueue(ctx, sendJobKind, payload, ports.JobOptions{LeaderOnly: true}); err != nil {
+		r.routerMetrics.MessagesFailed.Inc()
+		return fmt.Errorf("failed to queue message for %s: %w", connectorName, err)
+	}
+
+	r.logger.Info("message queued for the leader", "connector", connectorName, "user_id", userID)
+	return nil
+}
+
+// handleSendJob sends a message queued by another instance. The job is retried while the
+// connector is on standby, e.g. because the leadership moved since it was claimed.
+func (r *MessageRouter) handleSendJob(ctx context.Context, job *entity.Job) error {
+	var payload sendJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("%w: invalid message: %v", ports.ErrJobPermanent, err)
+	}
+
+	if _, exists := r.GetConnector(payload.Connector); !exists {
+		return fmt.Errorf("%w: connector not registered: %s", ports.ErrJobPermanent, payload.Connector)
+	}
+	if r.onStandby(payload.Connector) {
+		return fmt.Errorf("connector %s is on standby", payload.Connector)
+	}
+
+	return r.SendMessage(ctx, payload.Connector, payload.UserID, payload.Message)
+}
@@ -0,0 +1,110 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockExclusiveConnector is a connector that runs on the leader only
+type mockExclusiveConnector struct {
+	*mockConnector
+}
+
+func (m *mockExclusiveConnector) Exclusive() bool {
+	return true
+}
+
+// mockLeadership is a ports.Leadership switched by the test
+type mockLeadership struct {
+	leader bool
+}
+
+func (m *mockLeadership) IsLeader() bool {
+	return m.leader
+}
+
+// mockJobQueue keeps the queued jobs until they are run by the test
+type mockJobQueue struct {
+	handler ports.JobHandler
+	jobs    []*entity.Job
+}
+
+func (q *mockJobQueue) Register(kind string, handler ports.JobHandler) {
+	q.handler = handler
+}
+
+func (q *mockJobQueue) Enqueue(ctx context.Context, kind string, payload any, opts ports.JobOptions) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	job := entity.NewJob(kind, opts.Key, string(data), opts.MaxAttempts)
+	job.LeaderOnly = opts.LeaderOnly
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func TestLeadershipChanged(t *testing.T) {
+	router := NewMessageRouter(newMockSessionRepository(), nil, nil, logging.NewNoopLogger(), DefaultConfig())
+	bot := &mockExclusiveConnector{mockConnector: newMockConnector("telegram")}
+	web := newMockConnector("web")
+	router.RegisterConnector(bot)
+	router.RegisterConnector(web)
+
+	leadership := &mockLeadership{}
+	queue := &mockJobQueue{}
+	router.SetLeadership(leadership)
+	router.SetJobQueue(queue)
+	ctx := context.Background()
+
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+	if bot.IsRunning() || !web.IsRunning() {
+		t.Fatalf("Expected only the shared connector to run on a follower, got bot %v, web %v", bot.IsRunning(), web.IsRunning())
+	}
+	if err := router.StartConnector(ctx, "telegram"); !errors.Is(err, ports.ErrConnectorState) {
+		t.Errorf("Expected ErrConnectorState for a connector on standby, got %v", err)
+	}
+
+	// A message for the connector on standby is queued for the leader
+	if err := router.SendMessage(ctx, "telegram", "42", "Reminder"); err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	if len(queue.jobs) != 1 || !queue.jobs[0].LeaderOnly || queue.jobs[0].Kind != sendJobKind {
+		t.Fatalf("Expected a leader-only job, got %+v", queue.jobs)
+	}
+	if err := queue.handler(ctx, queue.jobs[0]); err == nil {
+		t.Error("Expected the job to be retried while the connector is on standby")
+	}
+
+	// The instance becomes the leader
+	leadership.leader = true
+	router.LeadershipChanged(true)
+	if !bot.IsRunning() {
+		t.Fatal("Expected the connector to run on the leader")
+	}
+	if err := queue.handler(ctx, queue.jobs[0]); err != nil {
+		t.Fatalf("Failed to run the job: %v", err)
+	}
+	if len(bot.sent) != 1 || bot.sent[0].Content != "Reminder" {
+		t.Errorf("Expected the queued message to be sent, got %+v", bot.sent)
+	}
+
+	// The instance loses the leadership
+	leadership.leader = false
+	router.LeadershipChanged(false)
+	if bot.IsRunning() || !web.IsRunning() {
+		t.Errorf("Expected the connector on standby again, got bot %v, web %v", bot.IsRunning(), web.IsRunning())
+	}
+
+	if err := router.Stop(); err != nil {
+		t.Fatalf("Failed to stop router: %v", err)
+	}
+}
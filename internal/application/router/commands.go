@@ -0,0 +1,392 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// Names of the built-in chat commands, without the command prefix
+const (
+	// CancelCommand aborts the replies being generated for the user, including the skills they run
+	CancelCommand = "cancel"
+
+	// HelpCommand lists the commands the user may run
+	HelpCommand = "help"
+
+	// ExportCommand sends a transcript of the current session as a document.
+	// An optional argument selects the format: "/export json" or "/export markdown" (default).
+	ExportCommand = "export"
+
+	// FeedbackCommand rates a reply of the assistant: "/feedback <message id> up|down [comment]".
+	// The feedback buttons under replies run it.
+	FeedbackCommand = "feedback"
+
+	// ForkCommand starts a new session with a copy of the history of the current one, up to the
+	// message given as argument or all of it: "/fork" or "/fork <message id>"
+	ForkCommand = "fork"
+
+	// InstructCommand shows and sets the custom instructions passed to the LLM: "/instruct",
+	// "/instruct <text>" for all sessions of the user, "/instruct session <text>" for the current
+	// session only, and "clear" as text to remove them
+	InstructCommand = "instruct"
+
+	// NewSessionCommand starts a new session; the following messages no longer see the earlier conversation.
+	NewSessionCommand = "new"
+
+	// ResetCommand clears the conversation history of the current session
+	ResetCommand = "reset"
+
+	// SessionsCommand lists the recent sessions of the user in the workspace of the connector,
+	// by title
+	SessionsCommand = "sessions"
+
+	// SettingsCommand shows and changes the preferences of the user: "/settings",
+	// "/settings <name> <value>" or "/settings reset"
+	SettingsCommand = "settings"
+
+	// SkillsCommand lists the enabled skills
+	SkillsCommand = "skills"
+
+	// UsageCommand shows the usage of the current session
+	UsageCommand = "usage"
+)
+
+// ErrCommandUsage is returned by a command handler for invalid arguments; the user is answered
+// with the usage of the command
+var ErrCommandUsage = errors.New("invalid command arguments")
+
+// CommandError is returned by a command handler to answer the user with Reply as an error
+type CommandError struct {
+	Reply string
+	Err   error
+}
+
+// Error implements the error interface
+func (e *CommandError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Reply, e.Err)
+	}
+	return e.Reply
+}
+
+// Unwrap returns the underlying error
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// NewCommandError creates a new CommandError
+func NewCommandError(reply string, err error) *CommandError {
+	return &CommandError{Reply: reply, Err: err}
+}
+
+// CommandCall is a chat command sent by a user
+type CommandCall struct {
+	Connector string
+	Conn      channels.Connector
+	Message   *channels.Message
+	User      *entity.User
+	Session   *entity.Session
+	Name      string   // Command name without the prefix, lower case
+	Args      []string // Arguments after the command name
+	Prefix    string   // Command prefix of the connector, to render commands in replies
+	Language  string   // Language of the replies, see MessageRouter.Translate
+}
+
+// CommandHandler runs a chat command and returns the reply to send, or nil to send none
+type CommandHandler func(ctx context.Context, call *CommandCall) (*channels.Response, error)
+
+// Command is a chat command answered by the router instead of the orchestrator
+type Command struct {
+	// Name is the command name without the prefix, e.g. "help"
+	Name string
+
+	// Description is shown by the help command
+	Description string
+
+	// Usage describes the arguments, e.g. "[json|markdown]"
+	Usage string
+
+	// AdminOnly restricts the command to the admins of Config.Commands
+	AdminOnly bool
+
+	// Handler runs the command
+	Handler CommandHandler
+}
+
+// CommandParser adapts the router to the command syntax of a connector
+type CommandParser interface {
+	// ParseCommand returns the command name (lower case, without the prefix) and arguments of a
+	// message, or false if the message is not a command
+	ParseCommand(msg *channels.Message) (name string, args []string, ok bool)
+
+	// Prefix returns the prefix commands start with, to render commands in replies
+	Prefix() string
+}
+
+// PrefixCommandParser parses commands that start with a prefix, such as "/help" or "!help"
+type PrefixCommandParser struct {
+	prefix string
+}
+
+// NewPrefixCommandParser creates a parser for commands starting with prefix
+func NewPrefixCommandParser(prefix string) *PrefixCommandParser {
+	return &PrefixCommandParser{prefix: prefix}
+}
+
+// ParseCommand implements CommandParser
+func (p *PrefixCommandParser) ParseCommand(msg *channels.Message) (string, []string, bool) {
+	fields := strings.Fields(msg.Content)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], p.prefix) {
+		return "", nil, false
+	}
+
+	// Telegram appends the bot name to commands in group chats: "/export@nexflow_bot"
+	name, _, _ := strings.Cut(strings.TrimPrefix(fields[0], p.prefix), "@")
+	if name == "" {
+		return "", nil, false
+	}
+	return strings.ToLower(name), fields[1:], true
+}
+
+// Prefix implements CommandParser
+func (p *PrefixCommandParser) Prefix() string {
+	return p.prefix
+}
+
+// TelegramCommandParser parses the commands the Telegram connector marks in the message
+// metadata, and other messages as "/" commands
+type TelegramCommandParser struct {
+	PrefixCommandParser
+}
+
+// NewTelegramCommandParser creates a parser for Telegram commands
+func NewTelegramCommandParser() *TelegramCommandParser {
+	return &TelegramCommandParser{PrefixCommandParser: PrefixCommandParser{prefix: "/"}}
+}
+
+// ParseCommand implements CommandParser
+func (p *TelegramCommandParser) ParseCommand(msg *channels.Message) (string, []string, bool) {
+	if msg.Metadata["message_type"] != "command" {
+		return p.PrefixCommandParser.ParseCommand(msg)
+	}
+
+	name, _ := msg.Metadata["command"].(string)
+	if name == "" {
+		return "", nil, false
+	}
+	args, _ := msg.Metadata["command_args"].(string)
+	return strings.ToLower(name), strings.Fields(args), true
+}
+
+// RegisterCommand adds a chat command, replacing a command of the same name
+//
+// Parameters:
+//   - command: Command to register
+func (r *MessageRouter) RegisterCommand(command Command) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	command.Name = strings.ToLower(command.Name)
+	r.commands[command.Name] = &command
+}
+
+// SetCommandParser sets the command syntax of a connector, overriding the prefix of
+// Config.Commands and the default parser
+//
+// Parameters:
+//   - connector: Name of the connector
+//   - parser: Parser for the commands of the connector
+func (r *MessageRouter) SetCommandParser(connector string, parser CommandParser) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.parsers[connector] = parser
+}
+
+// commandParser returns the command parser of a connector: the one set through SetCommandParser,
+// the configured prefix, the Telegram parser for the telegram connector or "/" commands
+func (r *MessageRouter) commandParser(connector string) CommandParser {
+	r.mu.RLock()
+	parser, ok := r.parsers[connector]
+	r.mu.RUnlock()
+	if ok {
+		return parser
+	}
+
+	if prefix, ok := r.config.Commands.Prefixes[connector]; ok {
+		return NewPrefixCommandParser(prefix)
+	}
+	if connector == "telegram" {
+		return NewTelegramCommandParser()
+	}
+	return NewPrefixCommandParser("/")
+}
+
+// command returns a registered command or the command of a skill available to a connector
+func (r *MessageRouter) command(ctx context.Context, connector, name string) (*Command, bool) {
+	r.mu.RLock()
+	command, ok := r.commands[name]
+	r.mu.RUnlock()
+	if ok {
+		return command, true
+	}
+
+	for _, command := range r.skillCommands(ctx, connector) {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return nil, false
+}
+
+// availableCommands returns the commands a user may run, sorted by name
+func (r *MessageRouter) availableCommands(ctx context.Context, connector, userID string) []*Command {
+	r.mu.RLock()
+	commands := make([]*Command, 0, len(r.commands))
+	for _, command := range r.commands {
+		commands = append(commands, command)
+	}
+	r.mu.RUnlock()
+
+	for _, command := range r.skillCommands(ctx, connector) {
+		if !slices.ContainsFunc(commands, func(c *Command) bool { return c.Name == command.Name }) {
+			commands = append(commands, command)
+		}
+	}
+
+	allowed := commands[:0]
+	for _, command := range commands {
+		if r.commandAllowed(command, connector, userID) {
+			allowed = append(allowed, command)
+		}
+	}
+	slices.SortFunc(allowed, func(a, b *Command) int { return strings.Compare(a.Name, b.Name) })
+	return allowed
+}
+
+// commandAllowed reports whether a user may run a command. Admin-only commands, including
+// those listed in Config.Commands.AdminOnly, are restricted to the admins of the connector.
+func (r *MessageRouter) commandAllowed(command *Command, connector, userID string) bool {
+	if !command.AdminOnly && !slices.Contains(r.config.Commands.AdminOnly, command.Name) {
+		return true
+	}
+	return slices.Contains(r.config.Commands.Admins[connector], userID)
+}
+
+// handleCommand handles chat commands that are answered by the router instead of the orchestrator.
+// Messages that are no command or an unknown one are left to the orchestrator.
+//
+// Returns:
+//   - bool: True if the message was a command and has been handled
+func (r *MessageRouter) handleCommand(ctx context.Context, connectorName string, conn channels.Connector, msg *channels.Message, user *entity.User, session *entity.Session) bool {
+	parser := r.commandParser(connectorName)
+	name, args, ok := parser.ParseCommand(msg)
+	if !ok {
+		return false
+	}
+
+	command, ok := r.command(ctx, connectorName, name)
+	if !ok {
+		return false
+	}
+
+	r.routerMetrics.CommandsHandled.Inc()
+	if !r.commandAllowed(command, connectorName, msg.UserID) {
+		r.routerMetrics.CommandsDenied.Inc()
+		r.logger.Warn("command denied",
+			"connector", connectorName,
+			"user_id", msg.UserID,
+			"command", name,
+		)
+		r.sendErrorResponse(ctx, conn, msg.UserID, r.Translate(r.language(ctx, connectorName, user), msgCommandDenied, parser.Prefix()+name))
+		return true
+	}
+
+	call := &CommandCall{
+		Connector: connectorName,
+		Conn:      conn,
+		Message:   msg,
+		User:      user,
+		Session:   session,
+		Name:      name,
+		Args:      args,
+		Prefix:    parser.Prefix(),
+		Language:  r.language(ctx, connectorName, user),
+	}
+
+	response, err := command.Handler(ctx, call)
+	if err != nil {
+		r.handleCommandError(ctx, call, command, err)
+		return true
+	}
+	if response == nil {
+		return true
+	}
+
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]interface{})
+	}
+	if _, ok := response.Metadata["session_id"]; !ok {
+		response.Metadata["session_id"] = session.ID.String()
+	}
+
+	if err := conn.SendResponse(ctx, msg.UserID, response); err != nil {
+		r.routerMetrics.MessagesFailed.Inc()
+		r.logger.Error("failed to send command reply",
+			"connector", connectorName,
+			"user_id", msg.UserID,
+			"session_id", session.ID,
+			"command", name,
+			"error", err,
+		)
+		return true
+	}
+
+	r.routerMetrics.MessagesProcessed.Inc()
+	r.logger.Info("command handled",
+		"connector", connectorName,
+		"user_id", msg.UserID,
+		"session_id", session.ID,
+		"command", name,
+	)
+	return true
+}
+
+// handleCommandError answers a failed command with its usage, the reply of a CommandError
+// or a generic error message
+func (r *MessageRouter) handleCommandError(ctx context.Context, call *CommandCall, command *Command, err error) {
+	var cmdErr *CommandError
+	switch {
+	case errors.Is(err, ErrCommandUsage):
+		r.sendErrorResponse(ctx, call.Conn, call.Message.UserID, r.Translate(call.Language, msgCommandUsage, formatCommand(call.Prefix, command)))
+		return
+	case errors.As(err, &cmdErr) && cmdErr.Err == nil:
+		r.sendErrorResponse(ctx, call.Conn, call.Message.UserID, cmdErr.Reply)
+		return
+	}
+
+	r.logger.Error("command failed",
+		"connector", call.Connector,
+		"user_id", call.Message.UserID,
+		"session_id", call.Session.ID,
+		"command", call.Name,
+		"error", err,
+	)
+	if cmdErr != nil {
+		r.sendErrorResponse(ctx, call.Conn, call.Message.UserID, cmdErr.Reply)
+		return
+	}
+	r.sendErrorResponse(ctx, call.Conn, call.Message.UserID, r.Translate(call.Language, msgCommandFailed, call.Prefix+call.Name))
+}
+
+// formatCommand renders a command with its usage, e.g. "/export [json|markdown]"
+func formatCommand(prefix string, command *Command) string {
+	if command.Usage == "" {
+		return prefix + command.Name
+	}
+	return prefix + command.Name + " " + command.Usage
+}
@@ -0,0 +1,357 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockSkillRepository is a mock implementation of repository.SkillRepository for testing
+type mockSkillRepository struct {
+	skills []*entity.Skill
+}
+
+func (m *mockSkillRepository) Create(ctx context.Context, skill *entity.Skill) error {
+	m.skills = append(m.skills, skill)
+	return nil
+}
+
+func (m *mockSkillRepository) FindByID(ctx context.Context, id string) (*entity.Skill, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockSkillRepository) FindByName(ctx context.Context, name string) (*entity.Skill, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockSkillRepository) List(ctx context.Context) ([]*entity.Skill, error) {
+	return m.skills, nil
+}
+
+func (m *mockSkillRepository) Update(ctx context.Context, skill *entity.Skill) error {
+	return nil
+}
+
+func (m *mockSkillRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+// commandTestRouter sends messages of a single user straight to the router handler
+type commandTestRouter struct {
+	*MessageRouter
+	conn         *mockConnector
+	orchestrator *mockOrchestrator
+}
+
+func newCommandTestRouter(config *Config) *commandTestRouter {
+	orchestrator := newMockOrchestrator()
+	return &commandTestRouter{
+		MessageRouter: NewMessageRouter(newMockSessionRepository(), orchestrator, nil, logging.NewNoopLogger(), config),
+		conn:          newMockConnector("telegram"),
+		orchestrator:  orchestrator,
+	}
+}
+
+// send handles a message and returns the reply
+func (r *commandTestRouter) send(t *testing.T, msg *channels.Message) *channels.Response {
+	t.Helper()
+	sent := len(r.conn.sent)
+	_ = r.handleMessage(context.Background(), &Request{Connector: r.conn.Name(), Conn: r.conn, Message: msg})
+	if len(r.conn.sent) != sent+1 {
+		t.Fatalf("Expected one reply to %q, got %d", msg.Content, len(r.conn.sent)-sent)
+	}
+	return r.conn.sent[sent]
+}
+
+func (r *commandTestRouter) sendText(t *testing.T, content string) *channels.Response {
+	t.Helper()
+	return r.send(t, &channels.Message{UserID: "user-123", Content: content})
+}
+
+func TestCommandParsers(t *testing.T) {
+	tests := []struct {
+		name         string
+		parser       CommandParser
+		msg          *channels.Message
+		expectedName string
+		expectedArgs []string
+		expectedOK   bool
+	}{
+		{"slash", NewPrefixCommandParser("/"), &channels.Message{Content: "/Export json"}, "export", []string{"json"}, true},
+		{"bot suffix", NewPrefixCommandParser("/"), &channels.Message{Content: "/help@nexflow_bot"}, "help", []string{}, true},
+		{"text", NewPrefixCommandParser("/"), &channels.Message{Content: "hello /help"}, "", nil, false},
+		{"prefix only", NewPrefixCommandParser("/"), &channels.Message{Content: "/ help"}, "", nil, false},
+		{"bang", NewPrefixCommandParser("!"), &channels.Message{Content: "!usage"}, "usage", []string{}, true},
+		{"bang ignores slash", NewPrefixCommandParser("!"), &channels.Message{Content: "/usage"}, "", nil, false},
+		{
+			"telegram metadata",
+			NewTelegramCommandParser(),
+			&channels.Message{Content: "/export@nexflow_bot json", Metadata: map[string]interface{}{
+				"message_type": "command", "command": "export", "command_args": "json",
+			}},
+			"export", []string{"json"}, true,
+		},
+		{"telegram text", NewTelegramCommandParser(), &channels.Message{Content: "/new"}, "new", []string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, ok := tt.parser.ParseCommand(tt.msg)
+			if ok != tt.expectedOK || name != tt.expectedName {
+				t.Errorf("Expected command '%s' (%v), got '%s' (%v)", tt.expectedName, tt.expectedOK, name, ok)
+			}
+			if ok && !slices.Equal(args, tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	config := DefaultConfig()
+	config.Commands.AdminOnly = []string{ResetCommand}
+	config.Commands.Admins = map[string][]string{"telegram": {"admin-1"}}
+	router := newCommandTestRouter(config)
+
+	reply := router.sendText(t, "/help")
+	if router.orchestrator.called {
+		t.Error("Expected /help not to reach the orchestrator")
+	}
+	for _, line := range []string{"/export [json|markdown] - Send a transcript of the current session", "/new - Start a new session", "/usage"} {
+		if !strings.Contains(reply.Content, line) {
+			t.Errorf("Expected help to contain %q, got:\n%s", line, reply.Content)
+		}
+	}
+	if strings.Contains(reply.Content, "/reset") {
+		t.Errorf("Expected help to hide admin-only commands, got:\n%s", reply.Content)
+	}
+
+	reply = router.send(t, &channels.Message{UserID: "admin-1", Content: "/help"})
+	if !strings.Contains(reply.Content, "/reset") {
+		t.Errorf("Expected help to list admin-only commands to admins, got:\n%s", reply.Content)
+	}
+}
+
+func TestCommand_Permissions(t *testing.T) {
+	config := DefaultConfig()
+	config.Commands.Admins = map[string][]string{"telegram": {"admin-1"}}
+	router := newCommandTestRouter(config)
+
+	called := false
+	router.RegisterCommand(Command{Name: "Shutdown", AdminOnly: true, Handler: func(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+		called = true
+		return nil, nil
+	}})
+
+	reply := router.sendText(t, "/shutdown")
+	if called || reply.Metadata["error"] != true || reply.Content != "Sorry, you are not allowed to use /shutdown." {
+		t.Errorf("Expected the command to be denied, got %+v", reply)
+	}
+	if denied := router.Metrics().CommandsDenied.Get(); denied != 1 {
+		t.Errorf("Expected 1 denied command, got %d", denied)
+	}
+
+	_ = router.handleMessage(context.Background(), &Request{Connector: "telegram", Conn: router.conn, Message: &channels.Message{UserID: "admin-1", Content: "/shutdown"}})
+	if !called {
+		t.Error("Expected the command to run for an admin")
+	}
+}
+
+func TestCommand_UnknownReachesOrchestrator(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+
+	reply := router.sendText(t, "/start")
+	if !router.orchestrator.called || reply.Content != "Response: /start" {
+		t.Errorf("Expected an unknown command to reach the orchestrator, got %+v", reply)
+	}
+}
+
+func TestCommand_ConnectorPrefix(t *testing.T) {
+	config := DefaultConfig()
+	config.Commands.Prefixes = map[string]string{"discord": "!"}
+	router := newCommandTestRouter(config)
+	router.conn = newMockConnector("discord")
+
+	router.SetSessionExporter(&mockSessionExporter{})
+
+	reply := router.sendText(t, "!export pdf")
+	if reply.Content != "Usage: !export [json|markdown]" {
+		t.Errorf("Expected the command to be handled with the connector prefix, got %q", reply.Content)
+	}
+	if router.orchestrator.called {
+		t.Error("Expected !export not to reach the orchestrator")
+	}
+
+	reply = router.sendText(t, "/help")
+	if !router.orchestrator.called {
+		t.Errorf("Expected /help to reach the orchestrator on a connector with a different prefix, got %q", reply.Content)
+	}
+
+	invalid := DefaultConfig()
+	invalid.Commands.Prefixes = map[string]string{"discord": ""}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected error for an empty command prefix")
+	}
+}
+
+func TestResetAndUsageCommands(t *testing.T) {
+	config := DefaultConfig()
+	config.Session.MaxMessages = 50
+	router := newCommandTestRouter(config)
+	messages := &mockMessageRepository{}
+
+	// Without a message repository, reset is not available
+	if reply := router.sendText(t, "/reset"); reply.Content != "Sorry, reset is not available." {
+		t.Errorf("Expected reset to be unavailable, got %q", reply.Content)
+	}
+
+	router.SetMessageRepository(messages)
+	ctx := context.Background()
+	var session *entity.Session
+	for _, s := range router.sessionRepo.(*mockSessionRepository).sessions {
+		session = s
+	}
+	for _, content := range []string{"Hello", "Hi there!"} {
+		_ = messages.Create(ctx, entity.NewUserMessage(session.ID.String(), content))
+	}
+
+	reply := router.sendText(t, "/usage")
+	if !strings.Contains(reply.Content, "Messages: 2 of 50") || !strings.Contains(reply.Content, "Sessions: 1") {
+		t.Errorf("Expected usage of the session, got:\n%s", reply.Content)
+	}
+	if reply.Metadata["session_id"] != session.ID.String() {
+		t.Errorf("Expected the session ID in the reply, got %+v", reply.Metadata)
+	}
+
+	if reply := router.sendText(t, "/reset"); reply.Content != "Conversation cleared." {
+		t.Errorf("Expected the conversation to be cleared, got %q", reply.Content)
+	}
+	if remaining, _ := messages.FindBySessionID(ctx, session.ID.String(), repository.QueryOptions{}); len(remaining) != 0 {
+		t.Errorf("Expected no messages after reset, got %d", len(remaining))
+	}
+}
+
+func TestSessionsCommand(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+	ctx := context.Background()
+	sessions := router.sessionRepo.(*mockSessionRepository)
+
+	current, _ := sessions.FindByID(ctx, router.sendText(t, "/usage").Metadata["session_id"].(string))
+	older := entity.NewSession(current.UserID.String())
+	older.CreatedAt = older.CreatedAt.Add(-time.Hour)
+	older.Title = "Trip to Lisbon"
+	_ = sessions.Create(ctx, older)
+	other := entity.NewSession(current.UserID.String())
+	other.WorkspaceID = "acme"
+	other.Title = "Quarterly report"
+	_ = sessions.Create(ctx, other)
+
+	reply := router.sendText(t, "/sessions")
+	lines := strings.Split(reply.Content, "\n")
+	if len(lines) != 3 || lines[0] != "Your recent sessions:" {
+		t.Fatalf("Expected the two sessions of the workspace, got:\n%s", reply.Content)
+	}
+	if !strings.HasPrefix(lines[1], fmt.Sprintf("Session %s - ", current.ID)) || !strings.HasSuffix(lines[1], " (current)") {
+		t.Errorf("Expected the current session by ID first, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "Trip to Lisbon - ") || strings.HasSuffix(lines[2], " (current)") {
+		t.Errorf("Expected the older session by title, got %q", lines[2])
+	}
+	if strings.Contains(reply.Content, "Quarterly report") {
+		t.Errorf("Expected sessions of other workspaces not to be listed, got:\n%s", reply.Content)
+	}
+}
+
+// mockSessionForker is a mock implementation of ports.SessionForker for testing
+type mockSessionForker struct {
+	sessionID     string
+	fromMessageID string
+}
+
+func (m *mockSessionForker) ForkSession(ctx context.Context, sessionID, fromMessageID string) (*dto.SessionResponse, error) {
+	m.sessionID = sessionID
+	m.fromMessageID = fromMessageID
+	if fromMessageID == "missing" {
+		return dto.ErrorSessionResponse(errors.New("message is not in the session")), nil
+	}
+	return dto.SuccessSessionResponse(&dto.SessionDTO{ID: "fork-1"}), nil
+}
+
+func TestForkCommand(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+
+	if reply := router.sendText(t, "/fork"); reply.Content != "Sorry, forking is not available." {
+		t.Errorf("Expected forking to be unavailable, got %q", reply.Content)
+	}
+
+	forker := &mockSessionForker{}
+	router.SetSessionForker(forker)
+
+	reply := router.sendText(t, "/fork msg-2")
+	if router.orchestrator.called {
+		t.Error("Expected /fork not to reach the orchestrator")
+	}
+	if reply.Metadata["session_id"] != "fork-1" {
+		t.Errorf("Expected the forked session ID in the reply, got %+v", reply.Metadata)
+	}
+	if forker.sessionID == "" || forker.fromMessageID != "msg-2" {
+		t.Errorf("Expected the current session to be forked from msg-2, got %q from %q", forker.sessionID, forker.fromMessageID)
+	}
+
+	if reply := router.sendText(t, "/fork missing"); reply.Content != "Usage: /fork [message_id]" {
+		t.Errorf("Expected the usage for a message outside the session, got %q", reply.Content)
+	}
+}
+
+func TestSkillCommands(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+
+	if reply := router.sendText(t, "/skills"); reply.Content != "Sorry, skills are not available." {
+		t.Errorf("Expected skills to be unavailable, got %q", reply.Content)
+	}
+
+	weather := entity.NewSkill("weather", "1.0.0", "/skills/weather", nil, map[string]interface{}{
+		"description": "Current weather", "command": "weather", "command_usage": "<city>",
+	})
+	deploy := entity.NewSkill("deploy", "1.0.0", "/skills/deploy", nil, map[string]interface{}{
+		"command": "deploy", "command_admin_only": true,
+	})
+	disabled := entity.NewSkill("legacy", "1.0.0", "/skills/legacy", nil, map[string]interface{}{"command": "legacy"})
+	disabled.Disable()
+	router.SetSkillRepository(&mockSkillRepository{skills: []*entity.Skill{weather, deploy, disabled}})
+
+	reply := router.sendText(t, "/skills")
+	if reply.Content != "Available skills:\nweather - Current weather\ndeploy" {
+		t.Errorf("Expected the enabled skills, got:\n%s", reply.Content)
+	}
+
+	reply = router.sendText(t, "/help")
+	if !strings.Contains(reply.Content, "/weather <city> - Current weather") || strings.Contains(reply.Content, "/deploy") {
+		t.Errorf("Expected help to list the allowed skill commands, got:\n%s", reply.Content)
+	}
+
+	reply = router.sendText(t, "/weather Berlin")
+	if router.orchestrator.called || reply.Content != "skill executed" {
+		t.Errorf("Expected the skill to be executed, got %+v", reply)
+	}
+
+	if reply := router.sendText(t, "/deploy"); reply.Metadata["error"] != true {
+		t.Errorf("Expected the admin-only skill command to be denied, got %+v", reply)
+	}
+
+	// Commands of disabled skills are left to the orchestrator
+	router.sendText(t, "/legacy")
+	if !router.orchestrator.called {
+		t.Error("Expected the command of a disabled skill to reach the orchestrator")
+	}
+}
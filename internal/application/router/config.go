@@ -2,7 +2,10 @@ package router
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/i18n"
 )
 
 // ValidationError represents a validation error
@@ -33,6 +36,83 @@ type Config struct {
 
 	// ValidationEnabled enables/disables message validation
 	ValidationEnabled bool
+
+	// Workers is the number of messages handled at the same time (default 16)
+	Workers int
+
+	// QueueSize is the number of validated messages waiting for a worker (default 256).
+	// When the queue is full, further messages wait in the connector buffers.
+	QueueSize int
+
+	// DrainTimeout is how long Stop waits for queued and in-flight messages (default 30s)
+	DrainTimeout time.Duration
+
+	// Session decides when a user's most recent session expires and the next message
+	// starts a new one
+	Session SessionPolicy
+
+	// Commands configures the chat commands answered by the router
+	Commands CommandConfig
+
+	// Workspaces maps connector names to the workspace their sessions belong to.
+	// Connectors not listed belong to the default workspace.
+	Workspaces map[string]string
+
+	// I18n selects the language of the system messages sent to users
+	I18n I18nConfig
+
+	// PostProcessing selects by connector how the replies of the LLM are transformed before
+	// they are sent, e.g. to strip reasoning or format them for the connector
+	PostProcessing PostProcessingConfig
+
+	// FeedbackButtons appends thumbs up and down buttons to the replies of the LLM, which rate
+	// the reply through the /feedback command. Only connectors showing buttons display them,
+	// and only when a feedback recorder is set (see MessageRouter.SetFeedbackRecorder).
+	FeedbackButtons bool
+}
+
+// I18nConfig holds the language selection and translations of the system messages, such as
+// error replies and the replies of chat commands. Users are answered in their preferred
+// language (see MessageRouter.SetUserPreferencesRepository), or else in the language of the
+// connector.
+type I18nConfig struct {
+	// DefaultLanguage is the language of connectors without one (default "en")
+	DefaultLanguage string
+
+	// Languages sets the language by connector name, e.g. "ru" for a Russian Telegram bot
+	Languages map[string]string
+
+	// Messages adds or replaces translations by language code and message key, e.g.
+	// {"ru": {"reset.done": "Готово."}}. Languages without a message fall back to
+	// DefaultLanguage and then to English.
+	Messages map[string]i18n.Messages
+}
+
+// CommandConfig holds the command syntax and permissions of the chat commands
+type CommandConfig struct {
+	// Prefixes sets the command prefix by connector name, e.g. "!" for Discord.
+	// Connectors without a prefix use "/"; Telegram also reads the commands it marks in the metadata.
+	Prefixes map[string]string
+
+	// Admins lists by connector name the connector user IDs allowed to run admin-only commands
+	Admins map[string][]string
+
+	// AdminOnly lists the names of further commands restricted to admins, e.g. "reset"
+	AdminOnly []string
+}
+
+// SessionPolicy holds the expiry conditions of a session. A zero value disables the
+// condition; with all of them disabled a session is continued until the user sends /new.
+type SessionPolicy struct {
+	// IdleTimeout expires a session that has not been updated for this long
+	IdleTimeout time.Duration
+
+	// MaxAge expires a session this long after it was created
+	MaxAge time.Duration
+
+	// MaxMessages expires a session holding this many messages, counting the replies.
+	// It requires a message repository, see MessageRouter.SetMessageRepository.
+	MaxMessages int
 }
 
 // RetryConfig holds retry configuration
@@ -55,12 +135,18 @@ func DefaultConfig() *Config {
 	return &Config{
 		MaxMessageLength:  10000, // 10,000 characters
 		ValidationEnabled: true,
+		Workers:           16,
+		QueueSize:         256,
+		DrainTimeout:      30 * time.Second,
 		RetryConfig: RetryConfig{
 			MaxAttempts:       3,
 			InitialDelay:      100 * time.Millisecond,
 			MaxDelay:          5 * time.Second,
 			BackoffMultiplier: 2.0,
 		},
+		PostProcessing: PostProcessingConfig{
+			Default: PostProcessing{StripReasoning: true},
+		},
 	}
 }
 
@@ -70,6 +156,45 @@ func (c *Config) Validate() error {
 		return NewValidationError("MaxMessageLength must be positive")
 	}
 
+	if c.Workers < 0 {
+		return NewValidationError("Workers must be non-negative")
+	}
+
+	if c.QueueSize < 0 {
+		return NewValidationError("QueueSize must be non-negative")
+	}
+
+	if c.DrainTimeout < 0 {
+		return NewValidationError("DrainTimeout must be non-negative")
+	}
+
+	if c.Session.IdleTimeout < 0 {
+		return NewValidationError("Session.IdleTimeout must be non-negative")
+	}
+
+	if c.Session.MaxAge < 0 {
+		return NewValidationError("Session.MaxAge must be non-negative")
+	}
+
+	if c.Session.MaxMessages < 0 {
+		return NewValidationError("Session.MaxMessages must be non-negative")
+	}
+
+	for connector, prefix := range c.Commands.Prefixes {
+		if prefix == "" || strings.ContainsAny(prefix, " \t\n") {
+			return NewValidationError(fmt.Sprintf("Commands.Prefixes[%s] must be non-empty and without spaces", connector))
+		}
+	}
+
+	for connector, pp := range c.PostProcessing.Connectors {
+		if err := pp.validate("PostProcessing.Connectors[" + connector + "]"); err != nil {
+			return err
+		}
+	}
+	if err := c.PostProcessing.Default.validate("PostProcessing.Default"); err != nil {
+		return err
+	}
+
 	if c.RetryConfig.MaxAttempts < 0 {
 		return NewValidationError("MaxAttempts must be non-negative")
 	}
@@ -96,3 +221,20 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// withPoolDefaults returns a copy of the configuration with the default worker pool
+// settings in place of zero values
+func (c *Config) withPoolDefaults() *Config {
+	defaults := DefaultConfig()
+	copied := *c
+	if copied.Workers == 0 {
+		copied.Workers = defaults.Workers
+	}
+	if copied.QueueSize == 0 {
+		copied.QueueSize = defaults.QueueSize
+	}
+	if copied.DrainTimeout == 0 {
+		copied.DrainTimeout = defaults.DrainTimeout
+	}
+	return &copied
+}
@@ -127,6 +127,53 @@ func TestConfig_Validate_InvalidBackoffMultiplier(t *testing.T) {
 	assert.Contains(t, err.Error(), "BackoffMultiplier")
 }
 
+func TestConfig_Validate_InvalidSessionPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SessionPolicy
+		field  string
+	}{
+		{"idle timeout", SessionPolicy{IdleTimeout: -1}, "Session.IdleTimeout"},
+		{"max age", SessionPolicy{MaxAge: -1}, "Session.MaxAge"},
+		{"max messages", SessionPolicy{MaxMessages: -1}, "Session.MaxMessages"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Session = tt.policy
+
+			err := config.Validate()
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.field)
+		})
+	}
+}
+
+func TestConfig_Validate_InvalidPostProcessing(t *testing.T) {
+	tests := []struct {
+		name           string
+		postProcessing PostProcessingConfig
+		field          string
+	}{
+		{"default max length", PostProcessingConfig{Default: PostProcessing{MaxLength: -1}}, "PostProcessing.Default.MaxLength"},
+		{"connector format", PostProcessingConfig{Connectors: map[string]PostProcessing{"telegram": {Format: "rtf"}}}, "PostProcessing.Connectors[telegram].Format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.PostProcessing = tt.postProcessing
+
+			err := config.Validate()
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.field)
+		})
+	}
+}
+
 func TestMessageValidator_Validate_NilMessage(t *testing.T) {
 	logger := logging.NewNoopLogger()
 	config := DefaultConfig()
@@ -0,0 +1,150 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// SetDeadLetterStore sets the store for messages the router fails to process.
+// Without a store, failed messages are only answered with an error reply and the
+// dead-letter methods return ports.ErrDeadLettersDisabled.
+func (r *MessageRouter) SetDeadLetterStore(store repository.MessageDeadLetterRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deadLetters = store
+}
+
+// deadLetterStore returns the dead-letter store, or nil if none is set
+func (r *MessageRouter) deadLetterStore() repository.MessageDeadLetterRepository {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.deadLetters
+}
+
+// deadLetter stores a message whose processing failed, so that it can be reprocessed later
+func (r *MessageRouter) deadLetter(req *Request, processErr error) {
+	store := r.deadLetterStore()
+	if store == nil {
+		return
+	}
+
+	msg := req.Message
+	letter := entity.NewMessageDeadLetter(req.Connector, msg.UserID, msg.ChannelID, msg.Content, msg.Metadata, processErr.Error())
+
+	// The handler context may already be cancelled when the router stops
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := store.Create(ctx, letter); err != nil {
+		r.logger.Error("failed to store message dead letter",
+			"connector", req.Connector,
+			"user_id", msg.UserID,
+			"error", err,
+		)
+		return
+	}
+
+	r.routerMetrics.MessagesDeadLettered.Inc()
+	r.logger.Warn("message dead-lettered",
+		"connector", req.Connector,
+		"user_id", msg.UserID,
+		"dead_letter_id", letter.ID,
+	)
+}
+
+// ListDeadLetters retrieves the messages the router failed to process, newest first
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - filter: Connector and page of the dead letters
+//
+// Returns:
+//   - []*entity.MessageDeadLetter: Matching dead letters
+//   - error: ports.ErrDeadLettersDisabled without a store, or the error of the store
+func (r *MessageRouter) ListDeadLetters(ctx context.Context, filter repository.MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error) {
+	store := r.deadLetterStore()
+	if store == nil {
+		return nil, ports.ErrDeadLettersDisabled
+	}
+	return store.List(ctx, filter)
+}
+
+// DeleteDeadLetter discards a dead-lettered message without processing it
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - id: ID of the dead letter
+//
+// Returns:
+//   - error: repository.ErrNotFound for an unknown ID, ports.ErrDeadLettersDisabled without a store,
+//     or the error of the store
+func (r *MessageRouter) DeleteDeadLetter(ctx context.Context, id string) error {
+	store := r.deadLetterStore()
+	if store == nil {
+		return ports.ErrDeadLettersDisabled
+	}
+	return store.Delete(ctx, id)
+}
+
+// ReprocessDeadLetter routes a dead-lettered message once more through the connector that
+// received it and sends the reply to the user. The middlewares are skipped, so that the
+// deduplicator does not drop the message. The dead letter is deleted on success and updated
+// with the new error on failure; the user gets no error reply for a failed attempt.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - id: ID of the dead letter
+//
+// Returns:
+//   - error: repository.ErrNotFound for an unknown ID, ports.ErrDeadLettersDisabled without a store,
+//     or ports.ErrReprocessFailed if the connector is not running or processing fails again
+func (r *MessageRouter) ReprocessDeadLetter(ctx context.Context, id string) error {
+	store := r.deadLetterStore()
+	if store == nil {
+		return ports.ErrDeadLettersDisabled
+	}
+
+	letter, err := store.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	conn, exists := r.GetConnector(letter.Connector)
+	if !exists {
+		return fmt.Errorf("%w: connector %s is not registered", ports.ErrReprocessFailed, letter.Connector)
+	}
+	if !conn.IsRunning() {
+		return fmt.Errorf("%w: connector %s is not running", ports.ErrReprocessFailed, letter.Connector)
+	}
+	if r.orchestrator == nil {
+		return fmt.Errorf("%w: orchestrator not available", ports.ErrReprocessFailed)
+	}
+
+	req := &Request{
+		Connector: letter.Connector,
+		Conn:      conn,
+		Message: &channels.Message{
+			UserID:    letter.UserID,
+			ChannelID: letter.ChannelID,
+			Content:   letter.Content,
+			Metadata:  letter.GetMetadata(),
+		},
+	}
+	if _, err := r.routeMessageRecovered(ctx, req); err != nil {
+		letter.RecordReprocess(err.Error())
+		if updateErr := store.Update(ctx, letter); updateErr != nil {
+			r.logger.Error("failed to update message dead letter", "dead_letter_id", id, "error", updateErr)
+		}
+		return fmt.Errorf("%w: %v", ports.ErrReprocessFailed, err)
+	}
+
+	r.routerMetrics.DeadLettersReprocessed.Inc()
+	r.logger.Info("message dead letter reprocessed", "dead_letter_id", id, "connector", letter.Connector)
+	return store.Delete(ctx, id)
+}
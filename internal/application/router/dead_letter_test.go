@@ -0,0 +1,297 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockMessageDeadLetterStore is a mock implementation of repository.MessageDeadLetterRepository for testing
+type mockMessageDeadLetterStore struct {
+	mu      sync.Mutex
+	letters []*entity.MessageDeadLetter
+}
+
+func (m *mockMessageDeadLetterStore) Create(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *letter
+	m.letters = append(m.letters, &copied)
+	return nil
+}
+
+func (m *mockMessageDeadLetterStore) GetByID(ctx context.Context, id string) (*entity.MessageDeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, letter := range m.letters {
+		if letter.ID == id {
+			copied := *letter
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("message dead letter %w: %s", repository.ErrNotFound, id)
+}
+
+func (m *mockMessageDeadLetterStore) List(ctx context.Context, filter repository.MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	letters := make([]*entity.MessageDeadLetter, 0, len(m.letters))
+	for _, letter := range m.letters {
+		if filter.Connector == "" || letter.Connector == filter.Connector {
+			letters = append(letters, letter)
+		}
+	}
+	return letters, nil
+}
+
+func (m *mockMessageDeadLetterStore) Update(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, stored := range m.letters {
+		if stored.ID == letter.ID {
+			copied := *letter
+			m.letters[i] = &copied
+			return nil
+		}
+	}
+	return fmt.Errorf("message dead letter %w: %s", repository.ErrNotFound, letter.ID)
+}
+
+func (m *mockMessageDeadLetterStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, letter := range m.letters {
+		if letter.ID == id {
+			m.letters = slices.Delete(m.letters, i, i+1)
+			return nil
+		}
+	}
+	return fmt.Errorf("message dead letter %w: %s", repository.ErrNotFound, id)
+}
+
+func (m *mockMessageDeadLetterStore) DeleteByUser(ctx context.Context, connector, userID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	before := len(m.letters)
+	m.letters = slices.DeleteFunc(m.letters, func(letter *entity.MessageDeadLetter) bool {
+		return letter.Connector == connector && letter.UserID == userID
+	})
+	return before - len(m.letters), nil
+}
+
+// failingOrchestrator fails every message while err is set
+type failingOrchestrator struct {
+	*mockOrchestrator
+	err error
+}
+
+func (o *failingOrchestrator) ProcessMessage(ctx context.Context, userID string, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	return o.mockOrchestrator.ProcessMessage(ctx, userID, content, options)
+}
+
+func newDeadLetterTestRouter(t *testing.T) (*MessageRouter, *mockConnector, *failingOrchestrator, *mockMessageDeadLetterStore) {
+	t.Helper()
+	orchestrator := &failingOrchestrator{mockOrchestrator: newMockOrchestrator(), err: errors.New("llm unavailable")}
+	router := NewMessageRouter(newMockSessionRepository(), orchestrator, nil, logging.NewNoopLogger(), DefaultConfig())
+	store := &mockMessageDeadLetterStore{}
+	router.SetDeadLetterStore(store)
+
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+	if err := conn.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start connector: %v", err)
+	}
+	return router, conn, orchestrator, store
+}
+
+func TestHandleMessage_DeadLettersFailedMessages(t *testing.T) {
+	ctx := context.Background()
+	router, conn, _, store := newDeadLetterTestRouter(t)
+
+	req := &Request{
+		Connector: "telegram",
+		Conn:      conn,
+		Message: &channels.Message{
+			UserID:    "user-123",
+			ChannelID: "100",
+			Content:   "Hello",
+			Metadata:  map[string]interface{}{"message_id": 7},
+		},
+	}
+	if err := router.handleMessage(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(conn.sent) != 1 || conn.sent[0].Metadata["error"] != true {
+		t.Errorf("Expected an error reply, got %+v", conn.sent)
+	}
+	if len(store.letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(store.letters))
+	}
+	letter := store.letters[0]
+	if letter.Connector != "telegram" || letter.UserID != "user-123" || letter.ChannelID != "100" || letter.Content != "Hello" {
+		t.Errorf("Expected the original message in the dead letter, got %+v", letter)
+	}
+	if letter.Metadata != `{"message_id":7}` || letter.Error != "llm unavailable" || letter.Attempts != 1 {
+		t.Errorf("Expected metadata, error and one attempt in the dead letter, got %+v", letter)
+	}
+	if count := router.Metrics().MessagesDeadLettered.Get(); count != 1 {
+		t.Errorf("Expected 1 dead-lettered message, got %d", count)
+	}
+
+	// Handled messages are not dead-lettered
+	router.orchestrator.(*failingOrchestrator).err = nil
+	_ = router.handleMessage(ctx, req)
+	if len(store.letters) != 1 {
+		t.Errorf("Expected no new dead letter, got %d", len(store.letters))
+	}
+}
+
+func TestReprocessDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	router, conn, orchestrator, store := newDeadLetterTestRouter(t)
+
+	letter := entity.NewMessageDeadLetter("telegram", "user-123", "100", "Hello", nil, "llm unavailable")
+	_ = store.Create(ctx, letter)
+
+	// Failing again updates the dead letter without replying to the user
+	err := router.ReprocessDeadLetter(ctx, letter.ID)
+	if !errors.Is(err, ports.ErrReprocessFailed) {
+		t.Fatalf("Expected ErrReprocessFailed, got %v", err)
+	}
+	if stored, _ := store.GetByID(ctx, letter.ID); stored.Attempts != 2 {
+		t.Errorf("Expected 2 attempts after a failed reprocessing, got %d", stored.Attempts)
+	}
+	if len(conn.sent) != 0 {
+		t.Errorf("Expected no reply for a failed reprocessing, got %d", len(conn.sent))
+	}
+
+	orchestrator.err = nil
+	if err := router.ReprocessDeadLetter(ctx, letter.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(conn.sent) != 1 || conn.sent[0].Content != "Response: Hello" {
+		t.Errorf("Expected the reply to be sent to the user, got %+v", conn.sent)
+	}
+	if _, err := store.GetByID(ctx, letter.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected the reprocessed dead letter to be deleted, got %v", err)
+	}
+	if count := router.Metrics().DeadLettersReprocessed.Get(); count != 1 {
+		t.Errorf("Expected 1 reprocessed dead letter, got %d", count)
+	}
+
+	if err := router.ReprocessDeadLetter(ctx, letter.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown dead letter, got %v", err)
+	}
+}
+
+func TestReprocessDeadLetter_ConnectorUnavailable(t *testing.T) {
+	ctx := context.Background()
+	router, conn, orchestrator, store := newDeadLetterTestRouter(t)
+	orchestrator.err = nil
+
+	unknown := entity.NewMessageDeadLetter("discord", "user-123", "", "Hello", nil, "boom")
+	stopped := entity.NewMessageDeadLetter("telegram", "user-123", "", "Hello", nil, "boom")
+	_ = store.Create(ctx, unknown)
+	_ = store.Create(ctx, stopped)
+	_ = conn.Stop(ctx)
+
+	for _, letter := range []*entity.MessageDeadLetter{unknown, stopped} {
+		if err := router.ReprocessDeadLetter(ctx, letter.ID); !errors.Is(err, ports.ErrReprocessFailed) {
+			t.Errorf("Expected ErrReprocessFailed for connector %s, got %v", letter.Connector, err)
+		}
+	}
+	if letters, _ := router.ListDeadLetters(ctx, repository.MessageDeadLetterFilter{}); len(letters) != 2 {
+		t.Errorf("Expected the dead letters to be kept, got %d", len(letters))
+	}
+
+	if err := router.DeleteDeadLetter(ctx, unknown.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if letters, _ := router.ListDeadLetters(ctx, repository.MessageDeadLetterFilter{Connector: "discord"}); len(letters) != 0 {
+		t.Errorf("Expected the discarded dead letter to be removed, got %d", len(letters))
+	}
+}
+
+func TestDeadLetters_Disabled(t *testing.T) {
+	ctx := context.Background()
+	orchestrator := &failingOrchestrator{mockOrchestrator: newMockOrchestrator(), err: errors.New("llm unavailable")}
+	router := NewMessageRouter(newMockSessionRepository(), orchestrator, nil, logging.NewNoopLogger(), DefaultConfig())
+	conn := newMockConnector("telegram")
+
+	// Without a store, a failed message is only answered with an error reply
+	_ = router.handleMessage(ctx, &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Hello"}})
+	if len(conn.sent) != 1 || router.Metrics().MessagesDeadLettered.Get() != 0 {
+		t.Errorf("Expected an error reply and no dead letter, got %d replies", len(conn.sent))
+	}
+
+	if _, err := router.ListDeadLetters(ctx, repository.MessageDeadLetterFilter{}); !errors.Is(err, ports.ErrDeadLettersDisabled) {
+		t.Errorf("Expected ErrDeadLettersDisabled, got %v", err)
+	}
+	if err := router.ReprocessDeadLetter(ctx, "letter-1"); !errors.Is(err, ports.ErrDeadLettersDisabled) {
+		t.Errorf("Expected ErrDeadLettersDisabled, got %v", err)
+	}
+	if err := router.DeleteDeadLetter(ctx, "letter-1"); !errors.Is(err, ports.ErrDeadLettersDisabled) {
+		t.Errorf("Expected ErrDeadLettersDisabled, got %v", err)
+	}
+}
+
+// panickingOrchestrator panics on every message
+type panickingOrchestrator struct {
+	*mockOrchestrator
+}
+
+func (o *panickingOrchestrator) ProcessMessage(ctx context.Context, userID string, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
+	panic("orchestrator bug")
+}
+
+func TestHandleMessage_RecoversPanics(t *testing.T) {
+	ctx := context.Background()
+	router, conn, _, store := newDeadLetterTestRouter(t)
+	router.orchestrator = &panickingOrchestrator{mockOrchestrator: newMockOrchestrator()}
+	recoverer := crash.NewRecoverer(logging.NewNoopLogger())
+	router.SetRecoverer(recoverer)
+
+	req := &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Hello"}}
+	if err := router.handleMessage(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(conn.sent) != 1 || conn.sent[0].Content != router.Translate("", msgProcessingFailed) {
+		t.Errorf("Expected the processing error reply, got %+v", conn.sent)
+	}
+	if len(store.letters) != 1 || store.letters[0].Error != "panic: orchestrator bug" {
+		t.Fatalf("Expected the panic in a dead letter, got %+v", store.letters)
+	}
+	if count := recoverer.Metrics().Recovered("router").Get(); count != 1 {
+		t.Errorf("Expected 1 recovered panic, got %d", count)
+	}
+
+	// A panicking middleware fails the message without stopping the worker
+	router.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) error {
+			panic("middleware bug")
+		}
+	})
+	router.runRequest(&Request{Connector: "telegram", Conn: conn, Message: req.Message, span: router.startSpan("telegram", req.Message)})
+	if count := router.Metrics().MessagesFailed.Get(); count != 1 {
+		t.Errorf("Expected 1 failed message, got %d", count)
+	}
+	if count := recoverer.Metrics().Recovered("router").Get(); count != 2 {
+		t.Errorf("Expected 2 recovered panics, got %d", count)
+	}
+}
@@ -0,0 +1,250 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// DeduplicatorConfig holds configuration for Deduplicator
+type DeduplicatorConfig struct {
+	// TTL is how long a handled message is remembered; a redelivery after it is handled again
+	TTL time.Duration
+
+	// CacheSize is the maximum number of messages remembered in memory; the oldest ones are
+	// forgotten first
+	CacheSize int
+}
+
+// DefaultDeduplicatorConfig returns the default configuration for Deduplicator
+func DefaultDeduplicatorConfig() DeduplicatorConfig {
+	return DeduplicatorConfig{
+		TTL:       10 * time.Minute,
+		CacheSize: 10000,
+	}
+}
+
+// DeduplicatorMetrics holds the metrics of a Deduplicator
+type DeduplicatorMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	MessagesDuplicate *metrics.Counter
+	StoreErrors       *metrics.Counter
+}
+
+// NewDeduplicatorMetrics creates a new DeduplicatorMetrics instance
+func NewDeduplicatorMetrics() *DeduplicatorMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &DeduplicatorMetrics{
+		registry: registry,
+
+		MessagesDuplicate: registry.GetCounter("router_messages_duplicate_total"),
+		StoreErrors:       registry.GetCounter("router_dedup_store_errors_total"),
+	}
+}
+
+// Registry returns the registry holding the Deduplicator metrics
+func (m *DeduplicatorMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// dedupEntry is a remembered message in the order it was handled
+type dedupEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// Deduplicator drops messages that a connector delivers more than once, e.g. when a Telegram
+// update is redelivered after a webhook retry or a reconnect. Messages are remembered in memory
+// and, with a store, in the database, so duplicates are also detected after a restart and
+// across instances sharing the database.
+type Deduplicator struct {
+	store   repository.ProcessedMessageRepository
+	logger  logging.Logger
+	config  DeduplicatorConfig
+	metrics *DeduplicatorMetrics
+	now     func() time.Time
+
+	mu    sync.Mutex
+	seen  map[string]time.Time
+	order []dedupEntry // Oldest first
+}
+
+// NewDeduplicator creates a new Deduplicator
+//
+// Parameters:
+//   - store: Repository of processed messages, or nil to remember messages only in memory
+//   - logger: Logger for the deduplicator
+//   - config: Deduplicator configuration (zero fields are replaced by defaults)
+//
+// Returns:
+//   - *Deduplicator: Initialized deduplicator
+func NewDeduplicator(store repository.ProcessedMessageRepository, logger logging.Logger, config DeduplicatorConfig) *Deduplicator {
+	defaults := DefaultDeduplicatorConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaults.CacheSize
+	}
+
+	return &Deduplicator{
+		store:   store,
+		logger:  logger,
+		config:  config,
+		metrics: NewDeduplicatorMetrics(),
+		now:     utils.Now,
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// Metrics returns the deduplicator metrics
+func (d *Deduplicator) Metrics() *DeduplicatorMetrics {
+	return d.metrics
+}
+
+// MessageKey returns the key identifying a message of a connector, or an empty string if the
+// connector does not report message IDs. Telegram callback queries are identified by the
+// callback ID, since every button press refers to the same message.
+//
+// Parameters:
+//   - connector: Name of the connector that received the message
+//   - msg: Received message
+//
+// Returns:
+//   - string: Deduplication key of the message
+func MessageKey(connector string, msg *channels.Message) string {
+	if id, ok := msg.Metadata["callback_id"]; ok && fmt.Sprint(id) != "" {
+		return fmt.Sprintf("%s:%s:callback:%v", connector, msg.ChannelID, id)
+	}
+	if id, ok := msg.Metadata["message_id"]; ok && fmt.Sprint(id) != "" {
+		return fmt.Sprintf("%s:%s:%v", connector, msg.ChannelID, id)
+	}
+	return ""
+}
+
+// Middleware returns the router middleware that drops duplicate messages.
+// Messages without an ID are passed on. A message whose handling fails is forgotten, so that
+// its redelivery is handled again. When the store fails, the message is handled rather than
+// risking to drop it.
+func (d *Deduplicator) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) error {
+			key := MessageKey(req.Connector, req.Message)
+			if key == "" {
+				return next(ctx, req)
+			}
+
+			if !d.claim(key) {
+				d.dropDuplicate(req, key)
+				return nil
+			}
+
+			if d.store != nil {
+				first, err := d.store.Record(ctx, key, d.config.TTL)
+				if err != nil {
+					d.metrics.StoreErrors.Inc()
+					d.logger.Warn("failed to check processed message, handling it", "key", key, "error", err)
+				} else if !first {
+					d.dropDuplicate(req, key)
+					return nil
+				}
+			}
+
+			if err := next(ctx, req); err != nil {
+				d.forget(ctx, key)
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+// PurgeExpired removes the expired records of processed messages from the store.
+// It is meant to run as a scheduler job and does nothing without a store.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//
+// Returns:
+//   - error: Error if the records could not be removed
+func (d *Deduplicator) PurgeExpired(ctx context.Context) error {
+	if d.store == nil {
+		return nil
+	}
+
+	purged, err := d.store.DeleteExpired(ctx, d.now())
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		d.logger.Info("expired processed messages purged", "count", purged)
+	}
+	return nil
+}
+
+// claim remembers a message and reports whether it was not remembered yet
+func (d *Deduplicator) claim(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if expiresAt, ok := d.seen[key]; ok && expiresAt.After(now) {
+		return false
+	}
+
+	expiresAt := now.Add(d.config.TTL)
+	d.seen[key] = expiresAt
+	d.order = append(d.order, dedupEntry{key: key, expiresAt: expiresAt})
+	d.evict(now)
+	return true
+}
+
+// evict forgets expired messages and, when the cache is over its size, the oldest ones.
+// Must be called with d.mu held.
+func (d *Deduplicator) evict(now time.Time) {
+	for len(d.order) > 0 {
+		oldest := d.order[0]
+		if oldest.expiresAt.After(now) && len(d.seen) <= d.config.CacheSize {
+			return
+		}
+		// Forgotten or re-remembered messages leave stale entries behind
+		if expiresAt, ok := d.seen[oldest.key]; ok && expiresAt.Equal(oldest.expiresAt) {
+			delete(d.seen, oldest.key)
+		}
+		d.order = d.order[1:]
+	}
+}
+
+// forget removes a message from the cache and the store
+func (d *Deduplicator) forget(ctx context.Context, key string) {
+	d.mu.Lock()
+	delete(d.seen, key)
+	d.mu.Unlock()
+
+	if d.store == nil {
+		return
+	}
+	if err := d.store.Forget(ctx, key); err != nil {
+		d.metrics.StoreErrors.Inc()
+		d.logger.Warn("failed to forget processed message", "key", key, "error", err)
+	}
+}
+
+// dropDuplicate counts and logs a message that was already handled
+func (d *Deduplicator) dropDuplicate(req *Request, key string) {
+	d.metrics.MessagesDuplicate.Inc()
+	d.logger.Info("duplicate message dropped",
+		"connector", req.Connector,
+		"user_id", req.Message.UserID,
+		"key", key,
+	)
+}
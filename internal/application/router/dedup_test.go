@@ -0,0 +1,199 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockProcessedMessageStore is a mock implementation of repository.ProcessedMessageRepository for testing
+type mockProcessedMessageStore struct {
+	mu      sync.Mutex
+	records map[string]bool
+	err     error
+}
+
+func newMockProcessedMessageStore() *mockProcessedMessageStore {
+	return &mockProcessedMessageStore{records: make(map[string]bool)}
+}
+
+func (m *mockProcessedMessageStore) Record(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.records[key] {
+		return false, nil
+	}
+	m.records[key] = true
+	return true, nil
+}
+
+func (m *mockProcessedMessageStore) Forget(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, key)
+	return nil
+}
+
+func (m *mockProcessedMessageStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// countingHandler counts the messages that reach it and fails while err is set
+type countingHandler struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (h *countingHandler) handle(ctx context.Context, req *Request) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	return h.err
+}
+
+func telegramRequest(messageID int) *Request {
+	return &Request{
+		Connector: "telegram",
+		Message: &channels.Message{
+			UserID:    "user-123",
+			ChannelID: "100",
+			Content:   "Hello",
+			Metadata:  map[string]interface{}{"message_id": messageID},
+		},
+	}
+}
+
+func TestMessageKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		expected string
+	}{
+		{"message", map[string]interface{}{"message_id": 42}, "telegram:100:42"},
+		{"callback", map[string]interface{}{"message_id": 42, "callback_id": "cb-1"}, "telegram:100:callback:cb-1"},
+		{"no id", map[string]interface{}{"chat_id": 100}, ""},
+		{"no metadata", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &channels.Message{ChannelID: "100", Metadata: tt.metadata}
+			if key := MessageKey("telegram", msg); key != tt.expected {
+				t.Errorf("Expected key '%s', got '%s'", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestDeduplicator_DropsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	dedup := NewDeduplicator(nil, logging.NewNoopLogger(), DeduplicatorConfig{TTL: time.Minute})
+	handler := &countingHandler{}
+	chain := Chain(handler.handle, dedup.Middleware())
+
+	for _, req := range []*Request{telegramRequest(1), telegramRequest(1), telegramRequest(2)} {
+		if err := chain(ctx, req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Messages without an ID are never dropped
+	noID := telegramRequest(0)
+	noID.Message.Metadata = nil
+	_ = chain(ctx, noID)
+	_ = chain(ctx, noID)
+
+	if handler.calls != 4 {
+		t.Errorf("Expected 4 handled messages, got %d", handler.calls)
+	}
+	if duplicates := dedup.Metrics().MessagesDuplicate.Get(); duplicates != 1 {
+		t.Errorf("Expected 1 duplicate, got %d", duplicates)
+	}
+}
+
+func TestDeduplicator_ExpiryAndCacheSize(t *testing.T) {
+	ctx := context.Background()
+	dedup := NewDeduplicator(nil, logging.NewNoopLogger(), DeduplicatorConfig{TTL: time.Minute, CacheSize: 2})
+	now := time.Now()
+	dedup.now = func() time.Time { return now }
+	handler := &countingHandler{}
+	chain := Chain(handler.handle, dedup.Middleware())
+
+	// The cache holds two messages, so the oldest one is forgotten
+	for _, id := range []int{1, 2, 3, 1} {
+		_ = chain(ctx, telegramRequest(id))
+	}
+	if handler.calls != 4 {
+		t.Errorf("Expected the evicted message to be handled again, got %d handled messages", handler.calls)
+	}
+
+	_ = chain(ctx, telegramRequest(3))
+	if handler.calls != 4 {
+		t.Errorf("Expected the remembered message to be dropped, got %d handled messages", handler.calls)
+	}
+
+	// After the TTL the message is handled again
+	now = now.Add(2 * time.Minute)
+	_ = chain(ctx, telegramRequest(3))
+	if handler.calls != 5 {
+		t.Errorf("Expected the expired message to be handled again, got %d handled messages", handler.calls)
+	}
+}
+
+func TestDeduplicator_ForgetsFailedMessages(t *testing.T) {
+	ctx := context.Background()
+	store := newMockProcessedMessageStore()
+	dedup := NewDeduplicator(store, logging.NewNoopLogger(), DefaultDeduplicatorConfig())
+	handler := &countingHandler{err: errors.New("orchestrator unavailable")}
+	chain := Chain(handler.handle, dedup.Middleware())
+
+	if err := chain(ctx, telegramRequest(1)); err == nil {
+		t.Fatal("Expected the handler error")
+	}
+	if store.records["telegram:100:1"] {
+		t.Error("Expected the failed message to be forgotten by the store")
+	}
+
+	handler.err = nil
+	if err := chain(ctx, telegramRequest(1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if handler.calls != 2 {
+		t.Errorf("Expected the redelivered message to be handled, got %d handled messages", handler.calls)
+	}
+}
+
+func TestDeduplicator_Store(t *testing.T) {
+	ctx := context.Background()
+	store := newMockProcessedMessageStore()
+	handler := &countingHandler{}
+
+	// A message handled before a restart is only known to the store
+	store.records["telegram:100:1"] = true
+	dedup := NewDeduplicator(store, logging.NewNoopLogger(), DefaultDeduplicatorConfig())
+	chain := Chain(handler.handle, dedup.Middleware())
+
+	_ = chain(ctx, telegramRequest(1))
+	if handler.calls != 0 {
+		t.Errorf("Expected the message recorded in the store to be dropped, got %d handled messages", handler.calls)
+	}
+
+	// Store failures let messages through
+	store.err = errors.New("database is locked")
+	_ = chain(ctx, telegramRequest(2))
+	if handler.calls != 1 {
+		t.Errorf("Expected the message to be handled when the store fails, got %d handled messages", handler.calls)
+	}
+	if errs := dedup.Metrics().StoreErrors.Get(); errs != 1 {
+		t.Errorf("Expected 1 store error, got %d", errs)
+	}
+}
@@ -0,0 +1,134 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// SetDocumentIngester sets the ingester reading the documents sent by users into the memory of
+// their session, so that the orchestrator can answer questions about them. Without it, document
+// messages are passed to the orchestrator like other messages.
+func (r *MessageRouter) SetDocumentIngester(ingester ports.DocumentIngester) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.documents = ingester
+}
+
+// handleDocument reads the document of a message into the session when the connector can
+// download it. A document with a caption returns the caption as the message to answer, so that
+// the question is answered with the document; without a caption, the router confirms the
+// document was read, and its chunks may be stored in the background when the ingester can queue
+// them. Photos are answered as unsupported, their text cannot be extracted.
+// Returns true when the message was handled.
+func (r *MessageRouter) handleDocument(ctx context.Context, connectorName string, conn channels.Connector, msg *channels.Message, user *entity.User, session *entity.Session) (*channels.Message, bool) {
+	r.mu.RLock()
+	ingester := r.documents
+	r.mu.RUnlock()
+
+	downloader, ok := conn.(channels.FileDownloader)
+	if ingester == nil || !ok {
+		return msg, false
+	}
+
+	language := r.language(ctx, connectorName, user)
+	messageType, _ := msg.Metadata["message_type"].(string)
+	switch messageType {
+	case "document":
+	case "photo":
+		r.sendErrorResponse(ctx, conn, msg.UserID, r.Translate(language, msgDocumentUnsupported))
+		return msg, true
+	default:
+		return msg, false
+	}
+
+	fileID, _ := msg.Metadata["document_file_id"].(string)
+	name, _ := msg.Metadata["document_file_name"].(string)
+	mimeType, _ := msg.Metadata["document_mime_type"].(string)
+	if fileID == "" {
+		return msg, false
+	}
+
+	logger := r.logger.WithContext(ctx)
+	caption, _ := msg.Metadata["caption"].(string)
+	maxSize := ingester.MaxDocumentSize()
+	data, err := downloader.DownloadFile(ctx, fileID, maxSize)
+	var count int
+	if err == nil {
+		doc := ports.Document{Name: name, MimeType: mimeType, Data: data}
+		// A question asked with the document needs its chunks stored before it is answered
+		if queuer, ok := ingester.(ports.DocumentQueuer); ok && strings.TrimSpace(caption) == "" {
+			count, err = queuer.QueueDocument(ctx, session.ID.String(), doc)
+		} else {
+			count, err = ingester.IngestDocument(ctx, session.ID.String(), doc)
+		}
+	}
+	if err != nil {
+		switch reply := documentErrorMessage(err); reply {
+		case msgDocumentTooLarge:
+			r.sendErrorResponse(ctx, conn, msg.UserID, r.Translate(language, reply, (maxSize+(1<<20)-1)>>20))
+			return msg, true
+		case msgDocumentUnsupported, msgDocumentEmpty:
+			r.sendErrorResponse(ctx, conn, msg.UserID, r.Translate(language, reply))
+			return msg, true
+		}
+		logger.Error("failed to read document",
+			"connector", connectorName,
+			"user_id", msg.UserID,
+			"session_id", session.ID,
+			"name", name,
+			"error", err,
+		)
+		r.sendErrorResponse(ctx, conn, msg.UserID, r.Translate(language, msgDocumentFailed))
+		return msg, true
+	}
+
+	logger.Info("document read",
+		"connector", connectorName,
+		"user_id", msg.UserID,
+		"session_id", session.ID,
+		"name", name,
+		"chunks", count,
+	)
+
+	if strings.TrimSpace(caption) != "" {
+		question := *msg
+		question.Content = caption
+		return &question, false
+	}
+
+	response := &channels.Response{
+		Content:  r.Translate(language, msgDocumentRead, name, count),
+		Metadata: map[string]interface{}{"session_id": session.ID.String()},
+	}
+	addTraceID(ctx, response.Metadata)
+	if err := conn.SendResponse(ctx, msg.UserID, response); err != nil {
+		r.routerMetrics.MessagesFailed.Inc()
+		logger.Error("failed to send document reply",
+			"connector", connectorName,
+			"user_id", msg.UserID,
+			"session_id", session.ID,
+			"error", err,
+		)
+		return msg, true
+	}
+	r.routerMetrics.MessagesProcessed.Inc()
+	return msg, true
+}
+
+// documentErrorMessage returns the key of the reply to a document that could not be read
+func documentErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, ports.ErrUnsupportedDocument):
+		return msgDocumentUnsupported
+	case errors.Is(err, ports.ErrDocumentTooLarge), errors.Is(err, channels.ErrFileTooLarge):
+		return msgDocumentTooLarge
+	case errors.Is(err, ports.ErrDocumentEmpty):
+		return msgDocumentEmpty
+	}
+	return msgDocumentFailed
+}
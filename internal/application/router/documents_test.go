@@ -0,0 +1,172 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockDocumentIngester records the documents it is given
+type mockDocumentIngester struct {
+	documents []ports.Document
+	sessions  []string
+	err       error
+}
+
+func (m *mockDocumentIngester) IngestDocument(ctx context.Context, sessionID string, doc ports.Document) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.documents = append(m.documents, doc)
+	m.sessions = append(m.sessions, sessionID)
+	return 3, nil
+}
+
+func (m *mockDocumentIngester) MaxDocumentSize() int64 {
+	return 2 << 20
+}
+
+// mockDownloadingConnector is a mockConnector serving files by ID
+type mockDownloadingConnector struct {
+	*mockConnector
+	files map[string][]byte
+}
+
+func (m *mockDownloadingConnector) DownloadFile(ctx context.Context, fileID string, maxSize int64) ([]byte, error) {
+	data, ok := m.files[fileID]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", fileID)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, channels.ErrFileTooLarge
+	}
+	return data, nil
+}
+
+func documentMessage(fileID, caption string) *channels.Message {
+	metadata := map[string]interface{}{
+		"message_type":       "document",
+		"document_file_id":   fileID,
+		"document_file_name": "report.pdf",
+		"document_mime_type": "application/pdf",
+	}
+	if caption != "" {
+		metadata["caption"] = caption
+	}
+	return &channels.Message{UserID: "user-123", Content: "[Document] FileID: " + fileID, Metadata: metadata}
+}
+
+func TestHandleDocument(t *testing.T) {
+	orchestrator := newMockOrchestrator()
+	router := NewMessageRouter(newMockSessionRepository(), orchestrator, nil, logging.NewNoopLogger(), DefaultConfig())
+	conn := &mockDownloadingConnector{
+		mockConnector: newMockConnector("telegram"),
+		files:         map[string][]byte{"file-1": []byte("%PDF-1.7"), "big": make([]byte, 3<<20)},
+	}
+	send := func(msg *channels.Message) *channels.Response {
+		t.Helper()
+		sent := len(conn.sent)
+		_ = router.handleMessage(context.Background(), &Request{Connector: conn.Name(), Conn: conn, Message: msg})
+		if len(conn.sent) != sent+1 {
+			t.Fatalf("Expected one reply, got %d", len(conn.sent)-sent)
+		}
+		return conn.sent[sent]
+	}
+
+	// Without an ingester, documents go to the orchestrator
+	if reply := send(documentMessage("file-1", "")); reply.Content != "Response: [Document] FileID: file-1" {
+		t.Errorf("Expected the orchestrator reply, got %q", reply.Content)
+	}
+
+	ingester := &mockDocumentIngester{}
+	router.SetDocumentIngester(ingester)
+	orchestrator.called = false
+
+	if reply := send(documentMessage("file-1", "")); reply.Content != "I have read report.pdf (3 parts). Ask me anything about it." {
+		t.Errorf("Expected the document to be read, got %q", reply.Content)
+	}
+	if orchestrator.called {
+		t.Error("Expected a document without a caption not to reach the orchestrator")
+	}
+	if len(ingester.documents) != 1 || ingester.documents[0].Name != "report.pdf" || ingester.documents[0].MimeType != "application/pdf" || string(ingester.documents[0].Data) != "%PDF-1.7" {
+		t.Fatalf("Expected the downloaded document to be ingested, got %+v", ingester.documents)
+	}
+	if ingester.sessions[0] == "" {
+		t.Error("Expected the document to be ingested into the session")
+	}
+
+	// The caption is answered as a question about the document
+	if reply := send(documentMessage("file-1", "What is the total?")); reply.Content != "Response: What is the total?" {
+		t.Errorf("Expected the caption to be answered, got %q", reply.Content)
+	}
+	if len(ingester.documents) != 2 {
+		t.Errorf("Expected the captioned document to be ingested, got %d documents", len(ingester.documents))
+	}
+
+	if reply := send(documentMessage("big", "")); reply.Content != "Sorry, the document is too large; I can read documents of up to 2 MB." {
+		t.Errorf("Expected a too large error, got %q", reply.Content)
+	}
+	if reply := send(documentMessage("missing", "")); reply.Content != "Sorry, I encountered an error reading the document." {
+		t.Errorf("Expected a download error, got %q", reply.Content)
+	}
+
+	ingester.err = fmt.Errorf("%w: image/png", ports.ErrUnsupportedDocument)
+	if reply := send(documentMessage("file-1", "")); reply.Content != "Sorry, I can only read PDF and text documents." {
+		t.Errorf("Expected an unsupported document error, got %q", reply.Content)
+	}
+	ingester.err = ports.ErrDocumentEmpty
+	if reply := send(documentMessage("file-1", "")); reply.Content != "Sorry, I found no text in the document. Scanned documents cannot be read." {
+		t.Errorf("Expected an empty document error, got %q", reply.Content)
+	}
+
+	photo := &channels.Message{UserID: "user-123", Content: "[Photo]", Metadata: map[string]interface{}{"message_type": "photo", "photo_file_id": "photo-1"}}
+	if reply := send(photo); reply.Content != "Sorry, I can only read PDF and text documents." {
+		t.Errorf("Expected photos to be unsupported, got %q", reply.Content)
+	}
+
+	// Text messages are not affected
+	orchestrator.called = false
+	if reply := send(&channels.Message{UserID: "user-123", Content: "hello", Metadata: map[string]interface{}{"message_type": "text"}}); reply.Content != "Response: hello" || !orchestrator.called {
+		t.Errorf("Expected text messages to reach the orchestrator, got %q", reply.Content)
+	}
+}
+
+// mockDocumentQueuer is a mockDocumentIngester that can queue documents
+type mockDocumentQueuer struct {
+	mockDocumentIngester
+	queued []ports.Document
+}
+
+func (m *mockDocumentQueuer) QueueDocument(ctx context.Context, sessionID string, doc ports.Document) (int, error) {
+	m.queued = append(m.queued, doc)
+	return 3, nil
+}
+
+func TestHandleDocument_Queued(t *testing.T) {
+	router := NewMessageRouter(newMockSessionRepository(), newMockOrchestrator(), nil, logging.NewNoopLogger(), DefaultConfig())
+	conn := &mockDownloadingConnector{
+		mockConnector: newMockConnector("telegram"),
+		files:         map[string][]byte{"file-1": []byte("%PDF-1.7")},
+	}
+	ingester := &mockDocumentQueuer{}
+	router.SetDocumentIngester(ingester)
+
+	// Documents without a caption are queued
+	_ = router.handleMessage(context.Background(), &Request{Connector: conn.Name(), Conn: conn, Message: documentMessage("file-1", "")})
+	if len(ingester.queued) != 1 || len(ingester.documents) != 0 {
+		t.Fatalf("Expected the document to be queued, got %d queued and %d ingested", len(ingester.queued), len(ingester.documents))
+	}
+	if reply := conn.sent[len(conn.sent)-1]; reply.Content != "I have read report.pdf (3 parts). Ask me anything about it." {
+		t.Errorf("Expected the document to be read, got %q", reply.Content)
+	}
+
+	// A caption is answered with the document, which is stored first
+	_ = router.handleMessage(context.Background(), &Request{Connector: conn.Name(), Conn: conn, Message: documentMessage("file-1", "What is the total?")})
+	if len(ingester.queued) != 1 || len(ingester.documents) != 1 {
+		t.Errorf("Expected the captioned document to be ingested, got %d queued and %d ingested", len(ingester.queued), len(ingester.documents))
+	}
+}
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// SetFeedbackRecorder sets the recorder of the ratings given with the /feedback chat command
+// and, when Config.FeedbackButtons is set, the buttons under replies. Without it, the command
+// replies that feedback is not available and no buttons are shown.
+func (r *MessageRouter) SetFeedbackRecorder(recorder ports.FeedbackRecorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.feedback = recorder
+}
+
+// feedbackRecorder returns the feedback recorder, or nil if none is set
+func (r *MessageRouter) feedbackRecorder() ports.FeedbackRecorder {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.feedback
+}
+
+// addFeedbackButtons appends the thumbs up and down buttons rating a reply to its response
+func (r *MessageRouter) addFeedbackButtons(connectorName, messageID string, response *channels.Response) {
+	if !r.config.FeedbackButtons || messageID == "" || r.feedbackRecorder() == nil {
+		return
+	}
+
+	command := r.commandParser(connectorName).Prefix() + FeedbackCommand + " " + messageID + " "
+	response.Buttons = append(response.Buttons,
+		channels.InlineButton{Text: "👍", Data: command + string(entity.FeedbackUp)},
+		channels.InlineButton{Text: "👎", Data: command + string(entity.FeedbackDown)},
+	)
+}
+
+// handleFeedbackCommand rates a reply in a session of the user, with the remaining arguments
+// as comment
+func (r *MessageRouter) handleFeedbackCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	recorder := r.feedbackRecorder()
+	if recorder == nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgFeedbackUnavailable), nil)
+	}
+	if len(call.Args) < 2 {
+		return nil, ErrCommandUsage
+	}
+
+	resp, err := recorder.RecordFeedback(ctx, call.Args[0], call.User.ID.String(), dto.RecordFeedbackRequest{
+		Rating:  strings.ToLower(call.Args[1]),
+		Comment: strings.Join(call.Args[2:], " "),
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCommandUsage
+	}
+	if err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgFeedbackFailed), err)
+	}
+	if !resp.Success {
+		return nil, ErrCommandUsage
+	}
+
+	return &channels.Response{Content: r.Translate(call.Language, msgFeedbackDone)}, nil
+}
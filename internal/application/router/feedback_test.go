@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// mockFeedbackRecorder records the ratings it is given
+type mockFeedbackRecorder struct {
+	messageID string
+	userID    string
+	req       dto.RecordFeedbackRequest
+}
+
+func (m *mockFeedbackRecorder) RecordFeedback(ctx context.Context, messageID, userID string, req dto.RecordFeedbackRequest) (*dto.FeedbackResponse, error) {
+	if messageID == "missing" {
+		return nil, fmt.Errorf("message %w", repository.ErrNotFound)
+	}
+	if req.Rating != "up" && req.Rating != "down" {
+		return dto.ErrorFeedbackResponse(fmt.Errorf("invalid rating %q", req.Rating)), nil
+	}
+	m.messageID, m.userID, m.req = messageID, userID, req
+	return dto.SuccessFeedbackResponse(&dto.MessageFeedbackDTO{MessageID: messageID, Rating: req.Rating}), nil
+}
+
+func TestFeedbackButtons(t *testing.T) {
+	config := DefaultConfig()
+	config.FeedbackButtons = true
+	router := newCommandTestRouter(config)
+
+	// Without a recorder, replies have no buttons
+	if reply := router.sendText(t, "hello"); len(reply.Buttons) != 0 {
+		t.Errorf("Expected no buttons without a recorder, got %+v", reply.Buttons)
+	}
+
+	router.SetFeedbackRecorder(&mockFeedbackRecorder{})
+	reply := router.sendText(t, "hello")
+	if len(reply.Buttons) != 2 || reply.Buttons[0].Data != "/feedback msg-123 up" || reply.Buttons[1].Data != "/feedback msg-123 down" {
+		t.Errorf("Expected the rating buttons of the reply, got %+v", reply.Buttons)
+	}
+
+	// The buttons are opt-in
+	router = newCommandTestRouter(DefaultConfig())
+	router.SetFeedbackRecorder(&mockFeedbackRecorder{})
+	if reply := router.sendText(t, "hello"); len(reply.Buttons) != 0 {
+		t.Errorf("Expected no buttons by default, got %+v", reply.Buttons)
+	}
+}
+
+func TestFeedbackCommand(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+
+	if reply := router.sendText(t, "/feedback msg-123 up"); reply.Content != "Sorry, feedback is not available." {
+		t.Errorf("Expected feedback to be unavailable, got %q", reply.Content)
+	}
+
+	recorder := &mockFeedbackRecorder{}
+	router.SetFeedbackRecorder(recorder)
+
+	if reply := router.sendText(t, "/feedback msg-123 DOWN too vague"); reply.Content != "Thanks for your feedback!" {
+		t.Errorf("Expected the feedback to be saved, got %q", reply.Content)
+	}
+	if router.orchestrator.called {
+		t.Error("Expected /feedback not to reach the orchestrator")
+	}
+	if recorder.messageID != "msg-123" || recorder.userID == "" || recorder.req.Rating != "down" || recorder.req.Comment != "too vague" {
+		t.Errorf("Expected the rating of the user, got %q by %q: %+v", recorder.messageID, recorder.userID, recorder.req)
+	}
+
+	for _, command := range []string{"/feedback msg-123", "/feedback msg-123 meh", "/feedback missing up"} {
+		if reply := router.sendText(t, command); reply.Content != "Usage: /feedback <message_id> up|down [comment]" {
+			t.Errorf("Expected the usage for %q, got %q", command, reply.Content)
+		}
+	}
+}
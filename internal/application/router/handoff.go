@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+)
+
+// SetSessionAttributeStore sets the store of session attributes holding operator handoffs and
+// the instructions of /instruct session. Without it, all messages are answered by the orchestrator.
+func (r *MessageRouter) SetSessionAttributeStore(attributes repository.SessionAttributeRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attributes = attributes
+}
+
+// sessionHandoff returns the operator handoff of a session, or nil if the session is not handed off
+func (r *MessageRouter) sessionHandoff(ctx context.Context, session *entity.Session) *entity.SessionHandoff {
+	r.mu.RLock()
+	attributes := r.attributes
+	r.mu.RUnlock()
+	if attributes == nil {
+		return nil
+	}
+
+	attr, err := attributes.Get(ctx, session.ID.String(), entity.SessionHandoffKey)
+	if err != nil {
+		// Rather answer with the LLM than leave the user without a reply on a failed lookup
+		if !errors.Is(err, repository.ErrNotFound) {
+			r.logger.Warn("failed to read session handoff", "session_id", session.ID, "error", err)
+		}
+		return nil
+	}
+
+	handoff, err := entity.SessionHandoffFromAttribute(attr)
+	if err != nil {
+		r.logger.Warn("ignoring invalid session handoff", "session_id", session.ID, "error", err)
+		return nil
+	}
+	return handoff
+}
+
+// handleHandoff records the messages of handed-off sessions instead of passing them to the
+// orchestrator. In forward mode, the message is published as an EventRouterHandoff event for
+// the operator; operator replies are sent through SendMessage.
+//
+// Returns:
+//   - bool: True if the session is handed off and the message has been handled
+func (r *MessageRouter) handleHandoff(ctx context.Context, connectorName string, msg *channels.Message, session *entity.Session) bool {
+	handoff := r.sessionHandoff(ctx, session)
+	if handoff == nil {
+		return false
+	}
+
+	message := entity.NewUserMessage(session.ID.String(), msg.Content)
+	if messages := r.messageRepository(); messages != nil {
+		if err := messages.Create(ctx, message); err != nil {
+			r.logger.Error("failed to save handed-off message",
+				"connector", connectorName,
+				"user_id", msg.UserID,
+				"session_id", session.ID,
+				"error", err,
+			)
+		}
+	}
+
+	// Keep the session from expiring while the operator is answering
+	session.UpdateTimestamp()
+	if err := r.sessionRepo.Update(ctx, session); err != nil {
+		r.logger.Warn("failed to update handed-off session", "session_id", session.ID, "error", err)
+	}
+
+	if handoff.Mode == entity.HandoffModeForward && r.eventBus != nil {
+		r.eventBus.Publish(eventbus.NewRouterEvent(
+			eventbus.EventRouterHandoff,
+			message.ID.String(),
+			session.ID.String(),
+			msg.UserID,
+			msg.Content,
+			connectorName,
+			nil,
+		))
+	}
+
+	r.routerMetrics.MessagesHandedOff.Inc()
+	r.logger.Info("message handed off",
+		"connector", connectorName,
+		"user_id", msg.UserID,
+		"session_id", session.ID,
+		"operator", handoff.Operator,
+		"mode", handoff.Mode,
+	)
+	return true
+}
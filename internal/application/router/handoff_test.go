@@ -0,0 +1,156 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockSessionAttributeRepository is a mock implementation of repository.SessionAttributeRepository for testing
+type mockSessionAttributeRepository struct {
+	attributes map[string]*entity.SessionAttribute
+	err        error
+}
+
+func newMockSessionAttributeRepository() *mockSessionAttributeRepository {
+	return &mockSessionAttributeRepository{attributes: make(map[string]*entity.SessionAttribute)}
+}
+
+func (m *mockSessionAttributeRepository) Get(ctx context.Context, sessionID, key string) (*entity.SessionAttribute, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	attr, ok := m.attributes[sessionID+"/"+key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return attr, nil
+}
+
+func (m *mockSessionAttributeRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.SessionAttribute, error) {
+	return nil, nil
+}
+
+func (m *mockSessionAttributeRepository) Set(ctx context.Context, attr *entity.SessionAttribute) error {
+	m.attributes[string(attr.SessionID)+"/"+attr.Key] = attr
+	return nil
+}
+
+func (m *mockSessionAttributeRepository) Delete(ctx context.Context, sessionID, key string) (bool, error) {
+	_, ok := m.attributes[sessionID+"/"+key]
+	delete(m.attributes, sessionID+"/"+key)
+	return ok, nil
+}
+
+func (m *mockSessionAttributeRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestHandoff_ForwardsMessagesToOperator(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := newMockSessionRepository()
+	orchestrator := newMockOrchestrator()
+	eventBus := eventbus.NewEventBus(nil)
+	if err := eventBus.Start(); err != nil {
+		t.Fatalf("Failed to start event bus: %v", err)
+	}
+	defer eventBus.Stop()
+	config := DefaultConfig()
+	config.Session.IdleTimeout = time.Hour
+	router := NewMessageRouter(sessionRepo, orchestrator, eventBus, logging.NewNoopLogger(), config)
+	conn := newMockConnector("telegram")
+	attributes := newMockSessionAttributeRepository()
+	messages := &mockMessageRepository{}
+	router.SetSessionAttributeStore(attributes)
+	router.SetMessageRepository(messages)
+
+	events := make(chan eventbus.Event, 10)
+	subscription := eventBus.Subscribe([]string{eventbus.EventRouterHandoff}, func(ctx context.Context, e eventbus.Event) error {
+		events <- e
+		return nil
+	})
+	defer eventBus.Unsubscribe(subscription)
+
+	request := &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "I need a human"}}
+	_ = router.handleMessage(ctx, request)
+	var session *entity.Session
+	for _, s := range sessionRepo.sessions {
+		session = s
+	}
+
+	// An idle session is kept while it is handed off
+	session.UpdatedAt = session.UpdatedAt.Add(-2 * time.Hour)
+	_ = attributes.Set(ctx, entity.NewSessionHandoff("alice", entity.HandoffModeForward).ToAttribute(session.ID.String()))
+
+	orchestrator.called = false
+	sent := len(conn.sent)
+	_ = router.handleMessage(ctx, request)
+	_ = router.handleMessage(ctx, &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "/new"}})
+
+	if orchestrator.called {
+		t.Error("Expected handed-off messages not to reach the orchestrator")
+	}
+	if len(conn.sent) != sent {
+		t.Errorf("Expected no replies while handed off, got %d", len(conn.sent)-sent)
+	}
+	if len(sessionRepo.sessions) != 1 {
+		t.Errorf("Expected the handed-off session to be continued, got %d sessions", len(sessionRepo.sessions))
+	}
+	if len(messages.messages) != 2 || messages.messages[0].Content != "I need a human" {
+		t.Errorf("Expected the handed-off messages to be saved, got %d", len(messages.messages))
+	}
+	if handedOff := router.Metrics().MessagesHandedOff.Get(); handedOff != 2 {
+		t.Errorf("Expected 2 handed-off messages, got %d", handedOff)
+	}
+
+	select {
+	case e := <-events:
+		event := e.(*eventbus.RouterEvent)
+		if event.SessionID != session.ID.String() || event.Content != "I need a human" || event.Source != "telegram" {
+			t.Errorf("Unexpected handoff event: %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a handoff event for the operator")
+	}
+
+	// Once the handoff ends, the orchestrator answers again
+	_, _ = attributes.Delete(ctx, session.ID.String(), entity.SessionHandoffKey)
+	_ = router.handleMessage(ctx, request)
+	if !orchestrator.called {
+		t.Error("Expected the orchestrator to answer after the handoff ended")
+	}
+}
+
+func TestHandoff_Pause(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := newMockSessionRepository()
+	orchestrator := newMockOrchestrator()
+	router := NewMessageRouter(sessionRepo, orchestrator, nil, logging.NewNoopLogger(), DefaultConfig())
+	conn := newMockConnector("telegram")
+	attributes := newMockSessionAttributeRepository()
+	router.SetSessionAttributeStore(attributes)
+
+	user, _ := conn.CreateUser(ctx, "user-123")
+	session := entity.NewSession(user.ID.String())
+	_ = sessionRepo.Create(ctx, session)
+	_ = attributes.Set(ctx, entity.NewSessionHandoff("alice", entity.HandoffModePause).ToAttribute(session.ID.String()))
+
+	_ = router.handleMessage(ctx, &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Hello"}})
+	if orchestrator.called || len(conn.sent) != 0 {
+		t.Error("Expected a paused session not to be answered")
+	}
+
+	// A failed lookup leaves the message to the orchestrator
+	attributes.err = errors.New("database is locked")
+	_ = router.handleMessage(ctx, &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Hello"}})
+	if !orchestrator.called {
+		t.Error("Expected the orchestrator to answer when the handoff cannot be read")
+	}
+}
@@ -0,0 +1,130 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// Arguments of the /instruct command
+const (
+	instructSession = "session" // Selects the instructions of the current session
+	instructClear   = "clear"   // Removes the instructions
+)
+
+// handleInstructCommand shows the custom instructions of the user and the current session,
+// sets those of the user with "/instruct <text>" and those of the session with
+// "/instruct session <text>". "clear" as text removes them. User instructions are kept with the
+// preferences of the user, session instructions as an attribute of the session.
+func (r *MessageRouter) handleInstructCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	r.mu.RLock()
+	preferences, attributes := r.preferences, r.attributes
+	r.mu.RUnlock()
+
+	args := call.Args
+	session := len(args) > 0 && strings.ToLower(args[0]) == instructSession
+	if session {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return r.showInstructions(ctx, call, preferences, attributes)
+	}
+
+	instructions := strings.TrimSpace(strings.Join(args, " "))
+	if len(args) == 1 && strings.ToLower(args[0]) == instructClear {
+		instructions = ""
+	}
+	if err := entity.ValidateInstructions(instructions); err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgInstructTooLong, entity.MaxInstructionsLength), nil)
+	}
+
+	if session {
+		if attributes == nil {
+			return nil, NewCommandError(r.Translate(call.Language, msgInstructUnavailable), nil)
+		}
+		if err := setSessionInstructions(ctx, attributes, call.Session, instructions); err != nil {
+			return nil, NewCommandError(r.Translate(call.Language, msgInstructSaveFailed), err)
+		}
+	} else {
+		if preferences == nil {
+			return nil, NewCommandError(r.Translate(call.Language, msgInstructUnavailable), nil)
+		}
+		if err := setUserInstructions(ctx, preferences, call.User.ID.String(), instructions); err != nil {
+			return nil, NewCommandError(r.Translate(call.Language, msgInstructSaveFailed), err)
+		}
+	}
+
+	if instructions == "" {
+		return &channels.Response{Content: r.Translate(call.Language, msgInstructCleared)}, nil
+	}
+	return &channels.Response{Content: r.Translate(call.Language, msgInstructSaved)}, nil
+}
+
+// showInstructions lists the custom instructions of the user and the current session
+func (r *MessageRouter) showInstructions(ctx context.Context, call *CommandCall, preferences repository.UserPreferencesRepository, attributes repository.SessionAttributeRepository) (*channels.Response, error) {
+	if preferences == nil && attributes == nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgInstructUnavailable), nil)
+	}
+
+	none := r.Translate(call.Language, msgInstructNone)
+	user, session := none, none
+	if preferences != nil {
+		prefs, err := preferences.Get(ctx, call.User.ID.String())
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, NewCommandError(r.Translate(call.Language, msgInstructReadFailed), err)
+		}
+		if prefs != nil && prefs.Instructions != "" {
+			user = prefs.Instructions
+		}
+	}
+	if attributes != nil {
+		attr, err := attributes.Get(ctx, call.Session.ID.String(), entity.SessionInstructionsKey)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, NewCommandError(r.Translate(call.Language, msgInstructReadFailed), err)
+		}
+		if attr != nil {
+			if instructions, err := entity.SessionInstructionsFromAttribute(attr); err == nil && instructions != "" {
+				session = instructions
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(r.Translate(call.Language, msgInstructUser, user) + "\n")
+	b.WriteString(r.Translate(call.Language, msgInstructSession, session) + "\n\n")
+	b.WriteString(r.Translate(call.Language, msgInstructHint, call.Prefix+InstructCommand))
+	return &channels.Response{Content: b.String()}, nil
+}
+
+// setUserInstructions saves the custom instructions of a user with their preferences
+func setUserInstructions(ctx context.Context, preferences repository.UserPreferencesRepository, userID, instructions string) error {
+	prefs, err := preferences.Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		prefs, err = entity.NewUserPreferences(userID), nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := prefs.Set(entity.PreferenceInstructions, instructions); err != nil {
+		return err
+	}
+	return preferences.Save(ctx, prefs)
+}
+
+// setSessionInstructions stores the custom instructions of a session, or deletes them if empty
+func setSessionInstructions(ctx context.Context, attributes repository.SessionAttributeRepository, session *entity.Session, instructions string) error {
+	if instructions == "" {
+		_, err := attributes.Delete(ctx, session.ID.String(), entity.SessionInstructionsKey)
+		return err
+	}
+
+	attr, err := entity.SessionInstructionsAttribute(session.ID.String(), instructions)
+	if err != nil {
+		return err
+	}
+	return attributes.Set(ctx, attr)
+}
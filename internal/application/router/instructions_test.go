@@ -0,0 +1,72 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+func TestInstructCommand(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+
+	// Without a preferences repository and an attribute store, instructions are not available
+	if reply := router.sendText(t, "/instruct"); reply.Content != "Sorry, custom instructions are not available." {
+		t.Errorf("Expected instructions to be unavailable, got %q", reply.Content)
+	}
+
+	preferences := &mockUserPreferencesRepository{prefs: map[string]*entity.UserPreferences{}}
+	attributes := newMockSessionAttributeRepository()
+	router.SetUserPreferencesRepository(preferences)
+	router.SetSessionAttributeStore(attributes)
+
+	reply := router.sendText(t, "/instruct")
+	if !strings.Contains(reply.Content, "Your instructions: none") || !strings.Contains(reply.Content, "Instructions of this session: none") {
+		t.Errorf("Expected no instructions, got:\n%s", reply.Content)
+	}
+
+	if reply := router.sendText(t, "/instruct Answer in haiku"); reply.Content != "Instructions saved." {
+		t.Errorf("Expected the user instructions to be saved, got %q", reply.Content)
+	}
+	if reply := router.sendText(t, "/instruct session Be brief"); reply.Content != "Instructions saved." {
+		t.Errorf("Expected the session instructions to be saved, got %q", reply.Content)
+	}
+
+	for _, prefs := range preferences.prefs {
+		if prefs.Instructions != "Answer in haiku" {
+			t.Errorf("Expected the user instructions to be stored, got %q", prefs.Instructions)
+		}
+	}
+	if len(attributes.attributes) != 1 {
+		t.Fatalf("Expected one session attribute, got %d", len(attributes.attributes))
+	}
+	for _, attr := range attributes.attributes {
+		if instructions, err := entity.SessionInstructionsFromAttribute(attr); err != nil || instructions != "Be brief" {
+			t.Errorf("Expected the session instructions to be stored, got %q (%v)", instructions, err)
+		}
+	}
+
+	reply = router.sendText(t, "/instruct")
+	if !strings.Contains(reply.Content, "Your instructions: Answer in haiku") || !strings.Contains(reply.Content, "Instructions of this session: Be brief") {
+		t.Errorf("Expected both instructions, got:\n%s", reply.Content)
+	}
+
+	if reply := router.sendText(t, "/instruct "+strings.Repeat("a", entity.MaxInstructionsLength+1)); !strings.Contains(reply.Content, "at most 4000 characters") {
+		t.Errorf("Expected a too long error, got %q", reply.Content)
+	}
+
+	if reply := router.sendText(t, "/instruct session clear"); reply.Content != "Instructions removed." {
+		t.Errorf("Expected the session instructions to be removed, got %q", reply.Content)
+	}
+	if len(attributes.attributes) != 0 {
+		t.Errorf("Expected the session attribute to be deleted, got %d", len(attributes.attributes))
+	}
+	if reply := router.sendText(t, "/instruct clear"); reply.Content != "Instructions removed." {
+		t.Errorf("Expected the user instructions to be removed, got %q", reply.Content)
+	}
+	for _, prefs := range preferences.prefs {
+		if prefs.Instructions != "" {
+			t.Errorf("Expected the user instructions to be cleared, got %q", prefs.Instructions)
+		}
+	}
+}
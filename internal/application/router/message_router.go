@@ -2,21 +2,30 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
 	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/i18n"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
 	"github.com/atumaikin/nexflow/internal/shared/metrics"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 // RouterMetrics holds all metrics for the MessageRouter
 type RouterMetrics struct {
+	registry *metrics.MetricsRegistry
+
 	MessagesReceived          *metrics.Counter
 	MessagesProcessed         *metrics.Counter
 	MessagesFailed            *metrics.Counter
@@ -25,6 +34,16 @@ type RouterMetrics struct {
 	MessageProcessingDuration *metrics.Histogram
 	ResponseSentDuration      *metrics.Histogram
 	ConnectorsActive          *metrics.Counter
+	MessagesInFlight          *metrics.Gauge
+	MessagesQueued            *metrics.Gauge
+	MessagesAbandoned         *metrics.Counter
+	MessagesDeadLettered      *metrics.Counter
+	DeadLettersReprocessed    *metrics.Counter
+	MessagesReplayed          *metrics.Counter
+	SessionsExpired           *metrics.Counter
+	CommandsHandled           *metrics.Counter
+	CommandsDenied            *metrics.Counter
+	MessagesHandedOff         *metrics.Counter
 }
 
 // NewRouterMetrics creates a new RouterMetrics instance
@@ -33,6 +52,8 @@ func NewRouterMetrics() *RouterMetrics {
 	buckets := metrics.DefaultBuckets()
 
 	return &RouterMetrics{
+		registry: registry,
+
 		MessagesReceived:          registry.GetCounter("router_messages_received_total"),
 		MessagesProcessed:         registry.GetCounter("router_messages_processed_total"),
 		MessagesFailed:            registry.GetCounter("router_messages_failed_total"),
@@ -41,27 +62,95 @@ func NewRouterMetrics() *RouterMetrics {
 		MessageProcessingDuration: registry.GetHistogram("router_message_processing_duration_seconds", buckets),
 		ResponseSentDuration:      registry.GetHistogram("router_response_sent_duration_seconds", buckets),
 		ConnectorsActive:          registry.GetCounter("router_connectors_active"),
+		MessagesInFlight:          registry.GetGauge("router_messages_in_flight"),
+		MessagesQueued:            registry.GetGauge("router_messages_queued"),
+		MessagesAbandoned:         registry.GetCounter("router_messages_abandoned_total"),
+		MessagesDeadLettered:      registry.GetCounter("router_messages_dead_lettered_total"),
+		DeadLettersReprocessed:    registry.GetCounter("router_dead_letters_reprocessed_total"),
+		MessagesReplayed:          registry.GetCounter("router_messages_replayed_total"),
+		SessionsExpired:           registry.GetCounter("router_sessions_expired_total"),
+		CommandsHandled:           registry.GetCounter("router_commands_total"),
+		CommandsDenied:            registry.GetCounter("router_commands_denied_total"),
+		MessagesHandedOff:         registry.GetCounter("router_messages_handed_off_total"),
 	}
 }
 
+// ConnectorMessagesReceived returns the counter of messages received from a connector
+func (m *RouterMetrics) ConnectorMessagesReceived(connector string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("router_connector_messages_received_total{connector=%q}", connector))
+}
+
+// Registry returns the registry holding the MessageRouter metrics
+func (m *RouterMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
 // MessageRouter routes incoming messages from connectors to Orchestrator
 // and sends responses back through appropriate connector
 type MessageRouter struct {
 	connectors    map[string]channels.Connector
 	sessionRepo   repository.SessionRepository
 	orchestrator  ports.Orchestrator
+	exporter      ports.SessionExporter
+	forker        ports.SessionForker
+	feedback      ports.FeedbackRecorder // Rates replies for the /feedback command and buttons
+	deadLetters   repository.MessageDeadLetterRepository
+	messages      repository.MessageRepository // Session messages for the session policy and handoffs
+	skills        repository.SkillRepository
+	workspaces    repository.WorkspaceRepository        // Token budgets of the workspaces
+	attributes    repository.SessionAttributeRepository // Holds the operator handoffs of sessions
+	preferences   repository.UserPreferencesRepository  // Preferences changed by the /settings command
+	documents     ports.DocumentIngester                // Reads the documents sent by users into their session
+	catalog       *i18n.Catalog                         // Translations of the system messages
+	commands      map[string]*Command                   // Chat commands by name
+	parsers       map[string]CommandParser              // Command syntax by connector
 	eventBus      *eventbus.EventBus
 	logger        logging.Logger
 	config        *Config
 	validator     *MessageValidator
 	retryHandler  *RetryHandler
 	routerMetrics *RouterMetrics
+	tracer        *tracing.Tracer                 // Traces the messages; nil disables tracing
+	recoverer     atomic.Pointer[crash.Recoverer] // Recovers panics while handling messages
+	middlewares   []Middleware
+	handler       Handler // handleMessage wrapped in the middlewares
 	mu            sync.RWMutex
-	ctx           context.Context
+	ctx           context.Context // Cancelled when the router stops accepting messages
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
+	workCtx       context.Context // Passed to handlers; cancelled once draining ends
+	workCancel    context.CancelFunc
+	queue         chan *Request // Messages waiting for a worker
+	workers       sync.WaitGroup
+
+	generationsMu sync.Mutex
+	generations   map[string][]*generation // Replies in flight by connector and user, for /cancel
+
+	processors []ResponseProcessor // Run on the replies after their post-processing
+
+	started    bool
+	processing map[string]*connectorProcessing
+	stopped    map[string]bool // Connectors stopped through StopConnector
+
+	leadership ports.Leadership // Runs the exclusive connectors on the leader only; nil runs them all
+	jobQueue   ports.JobQueue   // Hands messages for standby connectors to the leader
+	standby    map[string]bool  // Exclusive connectors waiting for this instance to lead
 }
 
+// connectorProcessing tracks the goroutine routing the messages of a connector
+type connectorProcessing struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Compile-time checks that MessageRouter implements the application ports
+var (
+	_ ports.MessageSender            = (*MessageRouter)(nil)
+	_ ports.ConnectorManager         = (*MessageRouter)(nil)
+	_ ports.MessageDeadLetterManager = (*MessageRouter)(nil)
+	_ ports.MessageReplayer          = (*MessageRouter)(nil)
+)
+
 // NewMessageRouter creates a new MessageRouter instance
 //
 // Parameters:
@@ -80,11 +169,13 @@ func NewMessageRouter(sessionRepo repository.SessionRepository, orchestrator por
 // NewMessageRouterWithMetrics creates a new MessageRouter with custom metrics
 func NewMessageRouterWithMetrics(sessionRepo repository.SessionRepository, orchestrator ports.Orchestrator, eventBus *eventbus.EventBus, logger logging.Logger, config *Config, routerMetrics *RouterMetrics) *MessageRouter {
 	ctx, cancel := context.WithCancel(context.Background())
+	workCtx, workCancel := context.WithCancel(context.Background())
 
 	// Use default config if not provided
 	if config == nil {
 		config = DefaultConfig()
 	}
+	config = config.withPoolDefaults()
 
 	// Validate configuration
 	if err := config.Validate(); err != nil {
@@ -101,7 +192,7 @@ func NewMessageRouterWithMetrics(sessionRepo repository.SessionRepository, orche
 		routerMetrics = NewRouterMetrics()
 	}
 
-	return &MessageRouter{
+	r := &MessageRouter{
 		connectors:    make(map[string]channels.Connector),
 		sessionRepo:   sessionRepo,
 		orchestrator:  orchestrator,
@@ -113,10 +204,62 @@ func NewMessageRouterWithMetrics(sessionRepo repository.SessionRepository, orche
 		routerMetrics: routerMetrics,
 		ctx:           ctx,
 		cancel:        cancel,
+		workCtx:       workCtx,
+		workCancel:    workCancel,
+		queue:         make(chan *Request, config.QueueSize),
+		processing:    make(map[string]*connectorProcessing),
+		stopped:       make(map[string]bool),
+		standby:       make(map[string]bool),
+		commands:      make(map[string]*Command),
+		parsers:       make(map[string]CommandParser),
+		catalog:       newCatalog(config.I18n),
+		generations:   make(map[string][]*generation),
 	}
+	r.handler = r.handleMessage
+	r.recoverer.Store(crash.NewRecoverer(logger))
+	r.registerBuiltinCommands()
+	return r
+}
+
+// SetSessionExporter sets the exporter used by the /export chat command.
+// Without an exporter, the command replies that export is not available.
+func (r *MessageRouter) SetSessionExporter(exporter ports.SessionExporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exporter = exporter
+}
+
+// SetSessionForker sets the forker used by the /fork chat command.
+// Without a forker, the command replies that forking is not available.
+func (r *MessageRouter) SetSessionForker(forker ports.SessionForker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.forker = forker
 }
 
-// RegisterConnector registers a connector with the router
+// SetTracer sets the tracer recording a span for every incoming message, which the
+// orchestrator, LLM providers, skills and database calls add their spans to.
+// Without a tracer, messages are not traced.
+func (r *MessageRouter) SetTracer(tracer *tracing.Tracer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tracer = tracer
+}
+
+// SetRecoverer sets the recoverer of panics while handling messages and receiving them from
+// connectors, e.g. to report them to the crash log. By default, panics are only logged.
+func (r *MessageRouter) SetRecoverer(recoverer *crash.Recoverer) {
+	r.recoverer.Store(recoverer)
+}
+
+// crashRecoverer returns the recoverer of panics. It does not lock r.mu, as it is called by
+// the workers while Stop holds it.
+func (r *MessageRouter) crashRecoverer() *crash.Recoverer {
+	return r.recoverer.Load()
+}
+
+// RegisterConnector registers a connector with the router and its channel with
+// valueobject.RegisterChannel, so that users of the connector can be created
 //
 // Parameters:
 //   - connector: Connector to register
@@ -125,6 +268,10 @@ func (r *MessageRouter) RegisterConnector(connector channels.Connector) {
 	defer r.mu.Unlock()
 
 	name := connector.Name()
+	// The channel of a new connector, such as slack, becomes known to valueobject.Channel
+	if _, err := valueobject.RegisterChannel(channels.ChannelOf(connector)); err != nil {
+		r.logger.Error("invalid connector channel, its users cannot be created", "connector", name, "error", err)
+	}
 	r.connectors[name] = connector
 	r.routerMetrics.ConnectorsActive.Inc()
 
@@ -162,19 +309,30 @@ func (r *MessageRouter) Start() error {
 
 	r.logger.Info("starting message router")
 
-	// Start all connectors
+	// Start all connectors, except the exclusive ones unless this instance leads
 	for name, conn := range r.connectors {
+		if !r.runsHere(conn) {
+			r.standby[name] = true
+			r.logger.Info("connector on standby until this instance leads", "connector", name)
+			continue
+		}
 		if err := conn.Start(r.ctx); err != nil {
 			return fmt.Errorf("failed to start connector %s: %w", name, err)
 		}
 		r.logger.Info("connector started", "connector", name)
 	}
 
-	// Start message processing for each connector
+	// Start the workers, then message processing for each connector
+	if !r.started {
+		r.startWorkers()
+	}
 	for name, conn := range r.connectors {
-		r.wg.Add(1)
-		go r.processMessages(name, conn)
+		if r.standby[name] {
+			continue
+		}
+		r.startProcessing(name, conn)
 	}
+	r.started = true
 
 	r.logger.Info("message router started")
 
@@ -190,21 +348,33 @@ func (r *MessageRouter) Start() error {
 	return nil
 }
 
-// Stop stops the message router and all connectors gracefully
+// Stop stops the message router and all connectors gracefully.
+// The router stops accepting messages, then waits up to the drain timeout for the workers to
+// handle the queued messages and those in flight; connectors are stopped afterwards so that
+// the replies can still be sent.
+//
+// Returns:
+//   - error: ErrDrainTimeout with the number of abandoned messages if the drain timeout expired
 func (r *MessageRouter) Stop() error {
 	r.logger.Info("stopping message router")
 
-	// Cancel context to signal all goroutines to stop
+	// Cancel context to stop accepting messages
 	r.cancel()
 
-	// Wait for all goroutines to finish
+	// Wait for the connector goroutines to finish
 	r.wg.Wait()
 
+	abandoned := r.drain()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.started = false
 
-	// Stop all connectors
+	// Stop all connectors, except those already stopped through StopConnector or on standby
 	for name, conn := range r.connectors {
+		if r.stopped[name] || r.standby[name] {
+			continue
+		}
 		if err := conn.Stop(r.ctx); err != nil {
 			r.logger.Error("failed to stop connector", "connector", name, "error", err)
 		}
@@ -221,33 +391,70 @@ func (r *MessageRouter) Stop() error {
 		r.eventBus.Publish(event)
 	}
 
+	if abandoned > 0 {
+		return fmt.Errorf("%w: %d messages abandoned", ErrDrainTimeout, abandoned)
+	}
 	return nil
 }
 
-// processMessages processes incoming messages from a connector
-func (r *MessageRouter) processMessages(connectorName string, conn channels.Connector) {
+// startProcessing starts routing the messages of a connector. The caller must hold r.mu.
+func (r *MessageRouter) startProcessing(name string, conn channels.Connector) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	p := &connectorProcessing{cancel: cancel, done: make(chan struct{})}
+	r.processing[name] = p
+
+	r.wg.Add(1)
+	go func() {
+		defer close(p.done)
+		r.processMessages(ctx, name, conn)
+	}()
+}
+
+// processMessages processes incoming messages from a connector until ctx is cancelled.
+// When processing panics, it is restarted after panicRestartDelay.
+func (r *MessageRouter) processMessages(ctx context.Context, connectorName string, conn channels.Connector) {
 	defer r.wg.Done()
 
 	r.logger.Info("started processing messages", "connector", connectorName)
 
-	for {
+	for !r.receiveMessages(ctx, connectorName, conn) {
 		select {
-		case <-r.ctx.Done():
+		case <-ctx.Done():
 			r.logger.Info("stopped processing messages", "connector", connectorName)
 			return
+		case <-time.After(panicRestartDelay):
+			r.logger.Warn("restarting message processing after a panic", "connector", connectorName)
+		}
+	}
+}
+
+// receiveMessages validates the incoming messages of a connector and hands them to the workers.
+// It reports true when processing ended, and false when it panicked.
+func (r *MessageRouter) receiveMessages(ctx context.Context, connectorName string, conn channels.Connector) (done bool) {
+	defer r.crashRecoverer().Recover(ctx, "router", "receive_messages", nil, "connector", connectorName)
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("stopped processing messages", "connector", connectorName)
+			return true
 
 		case msg, ok := <-conn.Incoming():
 			if !ok {
 				r.logger.Info("connector channel closed", "connector", connectorName)
-				return
+				return true
 			}
 
 			// Record message received
 			r.routerMetrics.MessagesReceived.Inc()
+			r.routerMetrics.ConnectorMessagesReceived(connectorName).Inc()
+			span := r.startSpan(connectorName, msg)
 
 			// Validate message before processing
 			if err := r.validator.Validate(msg); err != nil {
 				r.routerMetrics.MessageValidationFailed.Inc()
+				span.RecordError(err)
+				span.End()
 
 				r.logger.Error("message validation failed",
 					"connector", connectorName,
@@ -271,8 +478,7 @@ func (r *MessageRouter) processMessages(connectorName string, conn channels.Conn
 				}
 
 				// Send error response to user
-				r.sendErrorResponse(r.ctx, conn, msg.UserID,
-					"Sorry, your message could not be processed. Please check the format and try again.")
+				r.sendErrorResponse(tracing.ContextWithSpan(r.ctx, span), conn, msg.UserID, r.Translate(r.connectorLanguage(connectorName), msgInvalidMessage))
 
 				continue
 			}
@@ -298,40 +504,72 @@ func (r *MessageRouter) processMessages(connectorName string, conn channels.Conn
 				r.eventBus.Publish(event)
 			}
 
-			// Process the message in a separate goroutine to avoid blocking
-			go func() {
-				// Wrap handling with metrics
-				err := metrics.RecordDurationWithError(r.routerMetrics.MessageProcessingDuration, func() error {
-					r.handleMessage(connectorName, conn, msg)
-					return nil
-				})
-
-				// Record processing metrics
-				if err != nil {
-					r.routerMetrics.MessagesFailed.Inc()
-					r.logger.Error("message processing failed",
-						"connector", connectorName,
-						"user_id", msg.UserID,
-						"error", err,
-					)
-				} else {
-					r.routerMetrics.MessagesProcessed.Inc()
-				}
-			}()
+			// Hand the message to the workers; a full queue holds up this connector
+			if !r.enqueue(ctx, &Request{Connector: connectorName, Conn: conn, Message: msg, span: span}) {
+				r.routerMetrics.MessagesAbandoned.Inc()
+				span.RecordError(ctx.Err())
+				span.End()
+				r.logger.Warn("message processing stopped, message abandoned", "connector", connectorName, "user_id", msg.UserID)
+				return true
+			}
 		}
 	}
 }
 
-// handleMessage handles a single message from a connector
-func (r *MessageRouter) handleMessage(connectorName string, conn channels.Connector, msg *channels.Message) {
-	ctx := r.ctx
-	var err error
+// startSpan starts the span of a message received by a connector, continuing the trace of
+// the traceparent in its metadata if there is one
+func (r *MessageRouter) startSpan(connectorName string, msg *channels.Message) *tracing.Span {
+	r.mu.RLock()
+	tracer := r.tracer
+	r.mu.RUnlock()
+
+	traceparent, _ := msg.Metadata[tracing.TraceparentHeader].(string)
+	_, span := tracer.Start(tracing.Extract(context.Background(), traceparent), "router.message", tracing.SpanKindConsumer,
+		"messaging.system", connectorName,
+		"messaging.message.body.size", len(msg.Content),
+	)
+	return span
+}
 
+// handleMessage routes a single message from a connector to the orchestrator and sends the reply.
+// It is the innermost handler of the middleware chain; failures are answered with an error
+// reply and the message is dead-lettered rather than the error returned.
+func (r *MessageRouter) handleMessage(ctx context.Context, req *Request) error {
 	// Check if orchestrator is available (nil check for testing)
 	if r.orchestrator == nil {
-		r.logger.Warn("orchestrator not available, skipping message processing", "connector", connectorName, "user_id", msg.UserID)
-		return
+		r.logger.Warn("orchestrator not available, skipping message processing", "connector", req.Connector, "user_id", req.Message.UserID)
+		return nil
+	}
+
+	if reply, err := r.routeMessageRecovered(ctx, req); err != nil {
+		tracing.SpanFromContext(ctx).RecordError(err)
+		r.sendErrorResponse(ctx, req.Conn, req.Message.UserID, reply)
+		r.deadLetter(req, err)
 	}
+	return nil
+}
+
+// routeMessageRecovered runs routeMessage, turning a panic into an error answered with the
+// generic processing error reply
+func (r *MessageRouter) routeMessageRecovered(ctx context.Context, req *Request) (reply string, err error) {
+	defer func() {
+		var panicErr *crash.PanicError
+		if errors.As(err, &panicErr) {
+			reply = r.Translate(r.connectorLanguage(req.Connector), msgProcessingFailed)
+		}
+	}()
+	defer r.crashRecoverer().Recover(ctx, "router", "route_message", &err, "connector", req.Connector)
+
+	return r.routeMessage(ctx, req)
+}
+
+// routeMessage finds the user and session of a message and passes the message to a chat command
+// or the orchestrator, sending the reply through the connector. When processing fails, it returns
+// the error along with the reply to send to the user instead.
+func (r *MessageRouter) routeMessage(ctx context.Context, req *Request) (string, error) {
+	connectorName, conn, msg := req.Connector, req.Conn, req.Message
+	logger := r.logger.WithContext(ctx)
+	var err error
 
 	// Get or create user with retry
 	var user *entity.User
@@ -339,72 +577,79 @@ func (r *MessageRouter) handleMessage(connectorName string, conn channels.Connec
 		var err error
 		user, err = conn.GetUser(ctx, msg.UserID)
 		if err != nil {
-			r.logger.Info("user not found, creating new user", "connector", connectorName, "user_id", msg.UserID, "error", err)
+			logger.Info("user not found, creating new user", "connector", connectorName, "user_id", msg.UserID, "error", err)
 			user, err = conn.CreateUser(ctx, msg.UserID)
 		}
 		return err
 	})
 
 	if err != nil {
-		r.logger.Error("failed to get or create user",
+		logger.Error("failed to get or create user",
 			"connector", connectorName,
 			"user_id", msg.UserID,
 			"error", err,
 		)
-		r.sendErrorResponse(ctx, conn, msg.UserID, "Sorry, I encountered an error processing your request.")
-		return
+		return r.Translate(r.connectorLanguage(connectorName), msgProcessingFailed), err
 	}
 
 	// Get or create session with retry
 	var session *entity.Session
 	err = r.retryHandler.Do(ctx, "get_or_create_session", func() error {
-		sessions, err := r.sessionRepo.FindByUserID(ctx, string(user.ID))
-		if err != nil || len(sessions) == 0 {
-			// No session exists, create a new one
-			newSession := entity.NewSession(string(user.ID))
-			if err := r.sessionRepo.Create(ctx, newSession); err != nil {
-				return fmt.Errorf("failed to create session: %w", err)
-			}
-			session = newSession
-			r.logger.Info("session created",
-				"connector", connectorName,
-				"session_id", session.ID,
-				"user_id", user.ID,
-			)
-			return nil
-		}
-
-		// Use the most recent session (last in the list)
-		session = sessions[len(sessions)-1]
-		return nil
+		var err error
+		session, err = r.currentSession(ctx, connectorName, string(user.ID))
+		return err
 	})
 
 	if err != nil {
-		r.logger.Error("failed to get or create session",
+		logger.Error("failed to get or create session",
 			"connector", connectorName,
 			"user_id", msg.UserID,
 			"error", err,
 		)
-		r.sendErrorResponse(ctx, conn, msg.UserID, "Sorry, I encountered an error creating a session.")
-		return
+		return r.Translate(r.language(ctx, connectorName, user), msgSessionFailed), err
 	}
 
-	// Prepare message options with session ID
-	options := dto.MessageOptions{
-		MaxTokens: 1000,
+	if r.handleHandoff(ctx, connectorName, msg, session) {
+		return "", nil
+	}
+
+	if r.handleCommand(ctx, connectorName, conn, msg, user, session) {
+		return "", nil
+	}
+
+	msg, handled := r.handleDocument(ctx, connectorName, conn, msg, user, session)
+	if handled {
+		return "", nil
 	}
 
-	// Process message through Orchestrator
-	resp, err := r.orchestrator.ProcessMessage(ctx, string(user.ID), msg.Content, options)
+	// Prepare message options with session ID
+	options := r.messageOptions(ctx, msg, session)
+
+	// Process message through Orchestrator; /cancel aborts it
+	genCtx, finish := r.startGeneration(ctx, connectorName, msg.UserID)
+	genCtx = ports.WithConnector(genCtx, connectorName)
+	genCtx = ports.WithProgress(genCtx, func(ctx context.Context, progress ports.Progress) {
+		r.sendProgress(ctx, conn, msg.UserID, r.language(ctx, connectorName, user), session, progress)
+	})
+	resp, err := r.orchestrator.ProcessMessage(genCtx, string(user.ID), msg.Content, options)
+	cancelled := errors.Is(context.Cause(genCtx), ErrGenerationCancelled)
+	finish()
+	if cancelled {
+		logger.Info("generation cancelled by the user",
+			"connector", connectorName,
+			"user_id", msg.UserID,
+			"session_id", session.ID,
+		)
+		return "", nil
+	}
 	if err != nil {
-		r.logger.Error("failed to process message",
+		logger.Error("failed to process message",
 			"connector", connectorName,
 			"user_id", msg.UserID,
 			"session_id", session.ID,
 			"error", err,
 		)
-		r.sendErrorResponse(ctx, conn, msg.UserID, "Sorry, I encountered an error generating a response.")
-		return
+		return r.Translate(r.language(ctx, connectorName, user), msgResponseFailed), err
 	}
 
 	// Send response back through connector
@@ -418,13 +663,16 @@ func (r *MessageRouter) handleMessage(connectorName string, conn channels.Connec
 				},
 			}
 
-			// Add session ID to response metadata
+			// Add session ID and trace ID to response metadata
 			if session.ID != "" {
 				response.Metadata["session_id"] = session.ID.String()
 			}
+			addTraceID(ctx, response.Metadata)
+			r.postProcess(ctx, connectorName, r.language(ctx, connectorName, user), response)
+			r.addFeedbackButtons(connectorName, resp.Message.ID, response)
 
 			if err := conn.SendResponse(ctx, msg.UserID, response); err != nil {
-				r.logger.Error("failed to send response",
+				logger.Error("failed to send response",
 					"connector", connectorName,
 					"user_id", msg.UserID,
 					"session_id", session.ID,
@@ -446,7 +694,7 @@ func (r *MessageRouter) handleMessage(connectorName string, conn channels.Connec
 					r.eventBus.Publish(event)
 				}
 			} else {
-				r.logger.Info("response sent",
+				logger.Info("response sent",
 					"connector", connectorName,
 					"user_id", msg.UserID,
 					"session_id", session.ID,
@@ -469,6 +717,30 @@ func (r *MessageRouter) handleMessage(connectorName string, conn channels.Connec
 			}
 		}
 	}
+
+	return "", nil
+}
+
+// sendProgress sends a status update of a message being answered, such as "Step 2/4: ...",
+// to the user. A failure to send it is logged and does not stop the answer.
+func (r *MessageRouter) sendProgress(ctx context.Context, conn channels.Connector, userID, language string, session *entity.Session, progress ports.Progress) {
+	response := &channels.Response{
+		Content: r.Translate(language, msgPlanStep, progress.Step, progress.Total, progress.Description),
+		Metadata: map[string]interface{}{
+			"progress":   true,
+			"session_id": session.ID.String(),
+		},
+	}
+	addTraceID(ctx, response.Metadata)
+
+	if err := conn.SendResponse(ctx, userID, response); err != nil {
+		r.logger.Error("failed to send progress",
+			"connector", conn.Name(),
+			"user_id", userID,
+			"session_id", session.ID,
+			"error", err,
+		)
+	}
 }
 
 // sendErrorResponse sends an error message to user through connector
@@ -479,6 +751,7 @@ func (r *MessageRouter) sendErrorResponse(ctx context.Context, conn channels.Con
 			"error": true,
 		},
 	}
+	addTraceID(ctx, response.Metadata)
 
 	err := conn.SendResponse(ctx, userID, response)
 	if err != nil {
@@ -493,6 +766,53 @@ func (r *MessageRouter) sendErrorResponse(ctx context.Context, conn channels.Con
 	}
 }
 
+// addTraceID adds the trace ID of the message to the metadata of a response, so that replies
+// can be correlated with the trace and the log lines of the message
+func addTraceID(ctx context.Context, metadata map[string]interface{}) {
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		metadata["trace_id"] = sc.TraceID.String()
+	}
+}
+
+// SendMessage pushes a text message to a user through the named connector.
+// It is used for messages that are not replies to an incoming message,
+// such as the output of scheduled skill executions. A message for a connector on standby
+// because it runs on the leader only is queued for the leader.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - connectorName: Name of the registered connector
+//   - userID: Connector-specific user or chat ID
+//   - message: Text content to send
+//
+// Returns:
+//   - error: Error if the connector is unknown or sending failed
+func (r *MessageRouter) SendMessage(ctx context.Context, connectorName, userID, message string) error {
+	conn, exists := r.GetConnector(connectorName)
+	if !exists {
+		return fmt.Errorf("connector not registered: %s", connectorName)
+	}
+
+	if r.onStandby(connectorName) {
+		return r.queueSend(ctx, connectorName, userID, message)
+	}
+
+	response := &channels.Response{
+		Type:    channels.ResponseTypeText,
+		Content: message,
+	}
+
+	if err := conn.SendResponse(ctx, userID, response); err != nil {
+		r.routerMetrics.MessagesFailed.Inc()
+		return fmt.Errorf("failed to send message via %s: %w", connectorName, err)
+	}
+
+	r.routerMetrics.MessagesProcessed.Inc()
+	r.logger.Info("message sent", "connector", connectorName, "user_id", userID)
+
+	return nil
+}
+
 // GetConnector returns a connector by name
 //
 // Parameters:
@@ -523,3 +843,154 @@ func (r *MessageRouter) ListConnectors() []string {
 	}
 	return names
 }
+
+// Metrics returns the router metrics
+//
+// Returns:
+//   - *RouterMetrics: Router metrics
+func (r *MessageRouter) Metrics() *RouterMetrics {
+	return r.routerMetrics
+}
+
+// ConnectorStatuses returns the status of all registered connectors, sorted by name
+//
+// Returns:
+//   - []ports.ConnectorStatus: Connector statuses
+func (r *MessageRouter) ConnectorStatuses() []ports.ConnectorStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]ports.ConnectorStatus, 0, len(r.connectors))
+	for name, conn := range r.connectors {
+		statuses = append(statuses, ports.ConnectorStatus{
+			Name:             name,
+			Running:          conn.IsRunning(),
+			Pending:          len(conn.Incoming()),
+			MessagesReceived: r.routerMetrics.ConnectorMessagesReceived(name).Get(),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// StartConnector starts a connector stopped through StopConnector and resumes routing its messages.
+// The connector runs with the router's context, not ctx, so it outlives the calling request.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - name: Name of the connector
+//
+// Returns:
+//   - error: ports.ErrConnectorNotFound, ports.ErrConnectorState, or a start failure
+func (r *MessageRouter) StartConnector(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conn, exists := r.connectors[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", ports.ErrConnectorNotFound, name)
+	}
+	if !r.started {
+		return fmt.Errorf("%w: message router is not started", ports.ErrConnectorState)
+	}
+	if conn.IsRunning() {
+		return fmt.Errorf("%w: connector %s is already running", ports.ErrConnectorState, name)
+	}
+	if !r.runsHere(conn) {
+		return fmt.Errorf("%w: connector %s runs on the leader only", ports.ErrConnectorState, name)
+	}
+
+	if err := r.restartConnector(name, conn); err != nil {
+		return err
+	}
+	delete(r.stopped, name)
+
+	r.logger.Info("connector started", "connector", name)
+	return nil
+}
+
+// restartConnector starts a connector and routes its messages from scratch, since it may have
+// replaced its incoming channel. The caller must hold r.mu.
+func (r *MessageRouter) restartConnector(name string, conn channels.Connector) error {
+	if err := conn.Start(r.ctx); err != nil {
+		return fmt.Errorf("failed to start connector %s: %w", name, err)
+	}
+
+	if p, ok := r.processing[name]; ok {
+		p.cancel()
+		<-p.done
+	}
+	r.startProcessing(name, conn)
+	return nil
+}
+
+// StopConnector stops a running connector without stopping the router.
+// Messages already handed to the orchestrator are still answered.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - name: Name of the connector
+//
+// Returns:
+//   - error: ports.ErrConnectorNotFound, ports.ErrConnectorState, or a stop failure
+func (r *MessageRouter) StopConnector(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conn, exists := r.connectors[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", ports.ErrConnectorNotFound, name)
+	}
+	if !conn.IsRunning() {
+		return fmt.Errorf("%w: connector %s is not running", ports.ErrConnectorState, name)
+	}
+
+	if err := r.haltConnector(ctx, name, conn); err != nil {
+		return err
+	}
+	r.stopped[name] = true
+
+	r.logger.Info("connector stopped", "connector", name)
+	return nil
+}
+
+// haltConnector stops routing the messages of a connector, then the connector itself. The
+// caller must hold r.mu.
+func (r *MessageRouter) haltConnector(ctx context.Context, name string, conn channels.Connector) error {
+	if p, ok := r.processing[name]; ok {
+		p.cancel()
+		<-p.done
+		delete(r.processing, name)
+	}
+
+	if err := conn.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop connector %s: %w", name, err)
+	}
+	return nil
+}
+
+// Stats returns the message throughput of the router
+//
+// Returns:
+//   - ports.RouterStats: Router statistics
+func (r *MessageRouter) Stats() ports.RouterStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	queueDepth := 0
+	for _, conn := range r.connectors {
+		queueDepth += len(conn.Incoming())
+	}
+
+	return ports.RouterStats{
+		MessagesReceived:  r.routerMetrics.MessagesReceived.Get(),
+		MessagesProcessed: r.routerMetrics.MessagesProcessed.Get(),
+		MessagesFailed:    r.routerMetrics.MessagesFailed.Get(),
+		ValidationFailed:  r.routerMetrics.MessageValidationFailed.Get(),
+		InFlight:          r.routerMetrics.MessagesInFlight.Get(),
+		Queued:            r.routerMetrics.MessagesQueued.Get(),
+		Abandoned:         r.routerMetrics.MessagesAbandoned.Get(),
+		QueueDepth:        queueDepth,
+		Connectors:        len(r.connectors),
+	}
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
 	"github.com/atumaikin/nexflow/internal/shared/eventbus"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 var ErrUserNotFound = errors.New("user not found")
@@ -39,13 +47,28 @@ func (m *mockSessionRepository) FindByID(ctx context.Context, id string) (*entit
 	return nil, errors.New("session not found")
 }
 
-func (m *mockSessionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Session, error) {
+func (m *mockSessionRepository) List(ctx context.Context, opts repository.QueryOptions) ([]*entity.Session, error) {
+	sessions := make([]*entity.Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (m *mockSessionRepository) FindByUserID(ctx context.Context, userID string, opts repository.QueryOptions) ([]*entity.Session, error) {
 	var sessions []*entity.Session
 	for _, session := range m.sessions {
-		if session.UserID.String() == userID {
+		if session.UserID.String() == userID && (opts.Workspace == "" || session.WorkspaceID.String() == opts.Workspace) {
 			sessions = append(sessions, session)
 		}
 	}
+	// Like the database, list the newest sessions first
+	slices.SortFunc(sessions, func(a, b *entity.Session) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if opts.Limit > 0 && len(sessions) > opts.Limit {
+		sessions = sessions[:opts.Limit]
+	}
 	return sessions, nil
 }
 
@@ -70,8 +93,10 @@ type mockConnector struct {
 	name      string
 	incoming  chan *channels.Message
 	responses []*channels.Message
+	sent      []*channels.Response
 	users     map[string]*entity.User
 	started   bool
+	closed    bool
 }
 
 func newMockConnector(name string) *mockConnector {
@@ -88,6 +113,11 @@ func (m *mockConnector) Name() string {
 }
 
 func (m *mockConnector) Start(ctx context.Context) error {
+	// Like the Telegram connector, a restarted connector gets a new incoming channel
+	if m.closed {
+		m.incoming = make(chan *channels.Message, 100)
+		m.closed = false
+	}
 	m.started = true
 	return nil
 }
@@ -95,6 +125,7 @@ func (m *mockConnector) Start(ctx context.Context) error {
 func (m *mockConnector) Stop(ctx context.Context) error {
 	m.started = false
 	close(m.incoming)
+	m.closed = true
 	return nil
 }
 
@@ -105,6 +136,7 @@ func (m *mockConnector) SendResponse(ctx context.Context, userID string, respons
 		Metadata: response.Metadata,
 	}
 	m.responses = append(m.responses, msg)
+	m.sent = append(m.sent, response)
 	return nil
 }
 
@@ -189,6 +221,24 @@ func TestRegisterConnector(t *testing.T) {
 	}
 }
 
+func TestRegisterConnector_RegistersChannel(t *testing.T) {
+	router := NewMessageRouter(newMockSessionRepository(), nil, nil, logging.NewNoopLogger(), DefaultConfig())
+
+	router.RegisterConnector(newMockConnector("slack"))
+
+	channel, err := valueobject.NewChannel("slack")
+	if err != nil {
+		t.Fatalf("Expected the channel of the connector to be known, got %v", err)
+	}
+	user := entity.NewUser("slack", "U123")
+	if user.Channel != channel {
+		t.Errorf("Expected a slack user, got %q", user.Channel)
+	}
+	if _, err := valueobject.NewChannel("matrix"); err == nil {
+		t.Error("Expected the channel of an unregistered connector to stay unknown")
+	}
+}
+
 func TestUnregisterConnector(t *testing.T) {
 	logger := logging.NewNoopLogger()
 	eventBus := eventbus.NewEventBus(nil)
@@ -308,9 +358,11 @@ func TestConnectorConcurrency(t *testing.T) {
 
 // mockOrchestrator is a mock implementation of ports.Orchestrator for testing
 type mockOrchestrator struct {
-	responses map[string]*dto.SendMessageResponse
-	errors    map[string]error
-	called    bool
+	responses     map[string]*dto.SendMessageResponse
+	errors        map[string]error
+	called        bool
+	lastOptions   dto.MessageOptions
+	lastConnector string // Connector of the context of the last message
 }
 
 func newMockOrchestrator() *mockOrchestrator {
@@ -322,6 +374,8 @@ func newMockOrchestrator() *mockOrchestrator {
 
 func (m *mockOrchestrator) ProcessMessage(ctx context.Context, userID string, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
 	m.called = true
+	m.lastOptions = options
+	m.lastConnector = ports.ConnectorFromContext(ctx)
 	if err, exists := m.errors[userID]; exists {
 		return nil, err
 	}
@@ -426,7 +480,199 @@ func TestHandleMessageWithOrchestrator(t *testing.T) {
 	}
 }
 
+// spanRecorder is a tracing.Exporter keeping the exported spans for testing
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []tracing.SpanData
+}
+
+func (e *spanRecorder) ExportSpans(ctx context.Context, spans []tracing.SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// TestHandleMessageTracing tests that a message continues the trace of its traceparent and that
+// the reply carries the trace ID
+func TestHandleMessageTracing(t *testing.T) {
+	logger := logging.NewNoopLogger()
+	router := NewMessageRouter(newMockSessionRepository(), newMockOrchestrator(), nil, logger, DefaultConfig())
+	recorder := &spanRecorder{}
+	tracer := tracing.NewTracer(tracing.Config{Exporter: recorder, SampleRatio: 1})
+	defer tracer.Shutdown(context.Background())
+	router.SetTracer(tracer)
+
+	conn := newMockConnector("web")
+	router.RegisterConnector(conn)
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+
+	conn.incoming <- &channels.Message{
+		UserID:   "user-123",
+		Content:  "Hello",
+		Metadata: map[string]interface{}{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}
+	time.Sleep(500 * time.Millisecond)
+	router.Stop()
+
+	responses := conn.GetResponses()
+	if len(responses) == 0 {
+		t.Fatal("Expected at least one response")
+	}
+	if responses[0].Metadata["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the trace ID in the response metadata, got %v", responses[0].Metadata)
+	}
+
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.spans) != 1 || recorder.spans[0].Name != "router.message" || recorder.spans[0].Parent.String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected the span of the message as child of the traceparent, got %+v", recorder.spans)
+	}
+}
+
+// mockSessionExporter is a mock implementation of ports.SessionExporter for testing
+type mockSessionExporter struct {
+	sessionID string
+	format    string
+}
+
+func (m *mockSessionExporter) ExportSession(ctx context.Context, sessionID, format string) (*dto.SessionExportResponse, error) {
+	m.sessionID = sessionID
+	m.format = format
+	if format != dto.ExportFormatJSON && format != dto.ExportFormatMarkdown {
+		return dto.ErrorSessionExportResponse(errors.New("invalid format")), nil
+	}
+	return dto.SuccessSessionExportResponse(&dto.SessionExportDTO{
+		FileName: "session-" + sessionID + ".md",
+		Content:  []byte("# Session " + sessionID),
+	}), nil
+}
+
+// TestHandleExportCommand tests that /export sends the session transcript as a document
+func TestHandleExportCommand(t *testing.T) {
+	logger := logging.NewNoopLogger()
+	sessionRepo := newMockSessionRepository()
+	orchestrator := newMockOrchestrator()
+	exporter := &mockSessionExporter{}
+	router := NewMessageRouter(sessionRepo, orchestrator, nil, logger, DefaultConfig())
+	router.SetSessionExporter(exporter)
+
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+	defer router.Stop()
+
+	conn.SendMessage("user-123", "/export")
+	time.Sleep(300 * time.Millisecond)
+
+	conn.SendMessage("user-123", "/export@nexflow_bot pdf")
+	time.Sleep(300 * time.Millisecond)
+
+	if orchestrator.called {
+		t.Error("Expected /export not to reach the orchestrator")
+	}
+	if len(conn.sent) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(conn.sent))
+	}
+
+	doc := conn.sent[0]
+	if doc.Type != channels.ResponseTypeDocument {
+		t.Errorf("Expected document response, got %q", doc.Type)
+	}
+	if doc.Media == nil || doc.Media.FileName != "session-"+exporter.sessionID+".md" {
+		t.Errorf("Unexpected document media: %+v", doc.Media)
+	}
+
+	if exporter.format != "pdf" {
+		t.Errorf("Expected format argument to be passed to the exporter, got %q", exporter.format)
+	}
+	if conn.sent[1].Metadata["error"] != true {
+		t.Error("Expected error response for an invalid format")
+	}
+}
+
 // TestHandleMessageUserCreation tests message handling when user doesn't exist
+// planningOrchestrator reports the steps of a plan before it answers
+type planningOrchestrator struct {
+	*mockOrchestrator
+}
+
+func (o *planningOrchestrator) ProcessMessage(ctx context.Context, userID string, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
+	ports.ReportProgress(ctx, ports.Progress{Step: 1, Total: 2, Description: "Search for flights"})
+	ports.ReportProgress(ctx, ports.Progress{Step: 2, Total: 2, Description: "Pick a hotel"})
+	return o.mockOrchestrator.ProcessMessage(ctx, userID, content, options)
+}
+
+func TestHandleMessage_SendsProgress(t *testing.T) {
+	router := NewMessageRouter(newMockSessionRepository(), &planningOrchestrator{newMockOrchestrator()}, nil, logging.NewNoopLogger(), DefaultConfig())
+	conn := newMockConnector("telegram")
+
+	err := router.handleMessage(context.Background(), &Request{Connector: conn.Name(), Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Plan a trip"}})
+	if err != nil {
+		t.Fatalf("handleMessage failed: %v", err)
+	}
+
+	if len(conn.sent) != 3 {
+		t.Fatalf("Expected two progress messages and the answer, got %d replies", len(conn.sent))
+	}
+	for i, want := range []string{"Step 1/2: Search for flights", "Step 2/2: Pick a hotel", "Response: Plan a trip"} {
+		if conn.sent[i].Content != want {
+			t.Errorf("Expected reply %d to be %q, got %q", i, want, conn.sent[i].Content)
+		}
+	}
+	if conn.sent[0].Metadata["progress"] != true || conn.sent[2].Metadata["progress"] != nil {
+		t.Errorf("Expected only the progress messages to be marked, got %+v and %+v", conn.sent[0].Metadata, conn.sent[2].Metadata)
+	}
+}
+
+func TestHandleMessage_LLMOptionsFromMetadata(t *testing.T) {
+	temperature := 0.3
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     dto.MessageOptions
+	}{
+		{"no overrides", nil, dto.MessageOptions{MaxTokens: defaultMaxTokens}},
+		{"overrides", map[string]interface{}{
+			MetadataLLMProvider:    "ollama",
+			MetadataLLMModel:       "llama3",
+			MetadataLLMTemperature: "0.3",
+			MetadataLLMMaxTokens:   float64(200),
+		}, dto.MessageOptions{Provider: "ollama", Model: "llama3", MaxTokens: 200, Temperature: &temperature}},
+		{"out of range overrides are ignored", map[string]interface{}{
+			MetadataLLMTemperature: 3.5,
+			MetadataLLMMaxTokens:   defaultMaxTokens * 10,
+		}, dto.MessageOptions{MaxTokens: defaultMaxTokens}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orchestrator := newMockOrchestrator()
+			router := NewMessageRouter(newMockSessionRepository(), orchestrator, nil, logging.NewNoopLogger(), DefaultConfig())
+			conn := newMockConnector("telegram")
+
+			err := router.handleMessage(context.Background(), &Request{Connector: conn.Name(), Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Hi", Metadata: tt.metadata}})
+			if err != nil {
+				t.Fatalf("handleMessage failed: %v", err)
+			}
+
+			got := orchestrator.lastOptions
+			got.SessionID = ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected options %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestHandleMessageUserCreation(t *testing.T) {
 	logger := logging.NewNoopLogger()
 	eventBus := eventbus.NewEventBus(nil)
@@ -468,7 +714,7 @@ func TestHandleMessageUserCreation(t *testing.T) {
 	}
 
 	// Verify session was created (using actual user ID, not channel ID)
-	sessions, err := sessionRepo.FindByUserID(context.Background(), string(user.ID))
+	sessions, err := sessionRepo.FindByUserID(context.Background(), string(user.ID), repository.QueryOptions{})
 	if err != nil {
 		t.Fatalf("Failed to find sessions: %v", err)
 	}
@@ -595,6 +841,33 @@ func TestSendErrorResponse(t *testing.T) {
 	}
 }
 
+// TestSendMessage tests pushing a message through a registered connector
+func TestSendMessage(t *testing.T) {
+	logger := logging.NewNoopLogger()
+	router := NewMessageRouter(newMockSessionRepository(), newMockOrchestrator(), nil, logger, DefaultConfig())
+
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+
+	ctx := context.Background()
+
+	if err := router.SendMessage(ctx, "telegram", "chat-42", "Good morning!"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	responses := conn.GetResponses()
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 response, got %d", len(responses))
+	}
+	if responses[0].UserID != "chat-42" || responses[0].Content != "Good morning!" {
+		t.Errorf("Unexpected response: %+v", responses[0])
+	}
+
+	if err := router.SendMessage(ctx, "discord", "chat-42", "Good morning!"); err == nil {
+		t.Error("Expected error for unregistered connector")
+	}
+}
+
 // TestMessageValidation tests that invalid messages are rejected
 func TestMessageValidation(t *testing.T) {
 	logger := logging.NewNoopLogger()
@@ -731,3 +1004,198 @@ func TestListConnectorsEmpty(t *testing.T) {
 		t.Errorf("Expected 0 connectors, got %d", len(names))
 	}
 }
+
+func TestStartStopConnector(t *testing.T) {
+	logger := logging.NewNoopLogger()
+	eventBus := eventbus.NewEventBus(nil)
+	sessionRepo := newMockSessionRepository()
+	router := NewMessageRouter(sessionRepo, nil, eventBus, logger, DefaultConfig())
+
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+	ctx := context.Background()
+
+	if err := router.StartConnector(ctx, "telegram"); !errors.Is(err, ports.ErrConnectorState) {
+		t.Errorf("Expected ErrConnectorState before the router is started, got %v", err)
+	}
+	if err := router.StopConnector(ctx, "unknown"); !errors.Is(err, ports.ErrConnectorNotFound) {
+		t.Errorf("Expected ErrConnectorNotFound, got %v", err)
+	}
+
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+	if err := router.StartConnector(ctx, "telegram"); !errors.Is(err, ports.ErrConnectorState) {
+		t.Errorf("Expected ErrConnectorState for a running connector, got %v", err)
+	}
+
+	if err := router.StopConnector(ctx, "telegram"); err != nil {
+		t.Fatalf("Failed to stop connector: %v", err)
+	}
+	if conn.IsRunning() {
+		t.Error("Expected connector to be stopped")
+	}
+	if err := router.StopConnector(ctx, "telegram"); !errors.Is(err, ports.ErrConnectorState) {
+		t.Errorf("Expected ErrConnectorState for a stopped connector, got %v", err)
+	}
+
+	if err := router.StartConnector(ctx, "telegram"); err != nil {
+		t.Fatalf("Failed to restart connector: %v", err)
+	}
+
+	// Messages are routed again after the restart
+	conn.SendMessage("user1", "hello")
+	deadline := time.Now().Add(time.Second)
+	for router.Stats().MessagesReceived == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	statuses := router.ConnectorStatuses()
+	if len(statuses) != 1 || !statuses[0].Running || statuses[0].MessagesReceived != 1 {
+		t.Errorf("Unexpected connector statuses: %+v", statuses)
+	}
+
+	if err := router.Stop(); err != nil {
+		t.Fatalf("Failed to stop router: %v", err)
+	}
+}
+
+func TestRouterStats(t *testing.T) {
+	logger := logging.NewNoopLogger()
+	eventBus := eventbus.NewEventBus(nil)
+	sessionRepo := newMockSessionRepository()
+	router := NewMessageRouter(sessionRepo, nil, eventBus, logger, DefaultConfig())
+
+	conn1 := newMockConnector("telegram")
+	conn2 := newMockConnector("discord")
+	router.RegisterConnector(conn1)
+	router.RegisterConnector(conn2)
+
+	// Without a running router, messages wait in the connector queues
+	conn1.SendMessage("user1", "one")
+	conn1.SendMessage("user1", "two")
+	conn2.SendMessage("user2", "three")
+
+	stats := router.Stats()
+	if stats.Connectors != 2 {
+		t.Errorf("Expected 2 connectors, got %d", stats.Connectors)
+	}
+	if stats.QueueDepth != 3 {
+		t.Errorf("Expected queue depth 3, got %d", stats.QueueDepth)
+	}
+
+	statuses := router.ConnectorStatuses()
+	if len(statuses) != 2 || statuses[0].Name != "discord" || statuses[1].Pending != 2 {
+		t.Errorf("Unexpected connector statuses: %+v", statuses)
+	}
+}
+
+// TestChain_Order tests that middlewares run outermost first
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) error {
+				calls = append(calls, name+" before")
+				err := next(ctx, req)
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+	}
+	handler := func(ctx context.Context, req *Request) error {
+		calls = append(calls, "handler")
+		return nil
+	}
+
+	if err := Chain(handler, trace("first"), trace("second"))(context.Background(), &Request{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"first before", "second before", "handler", "second after", "first after"}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+}
+
+// TestUse_MiddlewareShortCircuits tests that a middleware can answer a message without the orchestrator
+func TestUse_MiddlewareShortCircuits(t *testing.T) {
+	logger := logging.NewNoopLogger()
+	orchestrator := newMockOrchestrator()
+	router := NewMessageRouter(newMockSessionRepository(), orchestrator, eventbus.NewEventBus(nil), logger, DefaultConfig())
+
+	var mu sync.Mutex
+	var seen []string
+	router.Use(
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) error {
+				mu.Lock()
+				seen = append(seen, req.Connector+":"+req.Message.Content)
+				mu.Unlock()
+				return next(ctx, req)
+			}
+		},
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) error {
+				if strings.Contains(req.Message.Content, "spam") {
+					return req.Reply(ctx, "Message rejected")
+				}
+				return next(ctx, req)
+			}
+		},
+	)
+
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+	defer router.Stop()
+
+	conn.SendMessage("user-123", "buy spam now")
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	if len(seen) != 1 || seen[0] != "telegram:buy spam now" {
+		t.Errorf("Expected the first middleware to see the message, got %v", seen)
+	}
+	mu.Unlock()
+	if orchestrator.called {
+		t.Error("Expected the orchestrator not to be called")
+	}
+	responses := conn.GetResponses()
+	if len(responses) != 1 || responses[0].Content != "Message rejected" {
+		t.Errorf("Expected the middleware reply, got %v", responses)
+	}
+	if processed := router.routerMetrics.MessagesProcessed.Get(); processed != 1 {
+		t.Errorf("Expected 1 processed message, got %d", processed)
+	}
+}
+
+// TestUse_MiddlewareError tests that a middleware error counts the message as failed
+func TestUse_MiddlewareError(t *testing.T) {
+	logger := logging.NewNoopLogger()
+	router := NewMessageRouter(newMockSessionRepository(), newMockOrchestrator(), eventbus.NewEventBus(nil), logger, DefaultConfig())
+	router.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) error {
+			return errors.New("language detection unavailable")
+		}
+	})
+
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+	defer router.Stop()
+
+	conn.SendMessage("user-123", "Hello")
+	time.Sleep(200 * time.Millisecond)
+
+	if failed := router.routerMetrics.MessagesFailed.Get(); failed != 1 {
+		t.Errorf("Expected 1 failed message, got %d", failed)
+	}
+	if processed := router.routerMetrics.MessagesProcessed.Get(); processed != 0 {
+		t.Errorf("Expected no processed messages, got %d", processed)
+	}
+}
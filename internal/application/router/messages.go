@@ -0,0 +1,352 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/i18n"
+)
+
+// Keys of the system messages the router sends to users
+const (
+	msgInvalidMessage   = "error.invalid_message"
+	msgProcessingFailed = "error.processing"
+	msgSessionFailed    = "error.session"
+	msgResponseFailed   = "error.response"
+
+	msgCommandDenied = "command.denied"
+	msgCommandUsage  = "command.usage"
+	msgCommandFailed = "command.failed"
+
+	msgHelpTitle = "help.title"
+
+	msgCancelDone    = "cancel.done"
+	msgCancelNothing = "cancel.nothing"
+	msgPlanStep      = "plan.step"
+	msgSources       = "reply.sources"
+
+	msgNewSessionDone = "new.done"
+
+	msgResetUnavailable = "reset.unavailable"
+	msgResetFailed      = "reset.failed"
+	msgResetDone        = "reset.done"
+
+	msgForkUnavailable = "fork.unavailable"
+	msgForkFailed      = "fork.failed"
+	msgForkDone        = "fork.done"
+
+	msgFeedbackUnavailable = "feedback.unavailable"
+	msgFeedbackFailed      = "feedback.failed"
+	msgFeedbackDone        = "feedback.done"
+
+	msgExportUnavailable = "export.unavailable"
+	msgExportFailed      = "export.failed"
+	msgExportCaption     = "export.caption"
+
+	msgSkillsUnavailable = "skills.unavailable"
+	msgSkillsFailed      = "skills.failed"
+	msgSkillsNone        = "skills.none"
+	msgSkillsTitle       = "skills.title"
+	msgSkillFailed       = "skill.failed"
+	msgSkillDone         = "skill.done"
+
+	msgSessionsFailed   = "sessions.failed"
+	msgSessionsTitle    = "sessions.title"
+	msgSessionsItem     = "sessions.item"
+	msgSessionsUntitled = "sessions.untitled"
+	msgSessionsCurrent  = "sessions.current"
+
+	msgUsageFailed         = "usage.failed"
+	msgUsageStarted        = "usage.started"
+	msgUsageStartedExpires = "usage.started_expires"
+	msgUsageMessages       = "usage.messages"
+	msgUsageMessagesMax    = "usage.messages_max"
+	msgUsageSessions       = "usage.sessions"
+
+	msgInstructUnavailable = "instruct.unavailable"
+	msgInstructReadFailed  = "instruct.read_failed"
+	msgInstructSaveFailed  = "instruct.save_failed"
+	msgInstructTooLong     = "instruct.too_long"
+	msgInstructSaved       = "instruct.saved"
+	msgInstructCleared     = "instruct.cleared"
+	msgInstructUser        = "instruct.user"
+	msgInstructSession     = "instruct.session"
+	msgInstructNone        = "instruct.none"
+	msgInstructHint        = "instruct.hint"
+
+	msgDocumentRead        = "document.read"
+	msgDocumentUnsupported = "document.unsupported"
+	msgDocumentTooLarge    = "document.too_large"
+	msgDocumentEmpty       = "document.empty"
+	msgDocumentFailed      = "document.failed"
+
+	msgSettingsUnavailable      = "settings.unavailable"
+	msgSettingsReadFailed       = "settings.read_failed"
+	msgSettingsSaveFailed       = "settings.save_failed"
+	msgSettingsResetFailed      = "settings.reset_failed"
+	msgSettingsSaved            = "settings.saved"
+	msgSettingsResetDone        = "settings.reset"
+	msgSettingsTitle            = "settings.title"
+	msgSettingsDefault          = "settings.default"
+	msgSettingsHint             = "settings.hint"
+	msgSettingsResetButton      = "settings.reset_button"
+	msgSettingsInvalidLanguage  = "settings.invalid_language"
+	msgSettingsInvalidTimezone  = "settings.invalid_timezone"
+	msgSettingsInvalidVerbosity = "settings.invalid_verbosity"
+)
+
+// commandDescriptionKey returns the key of the translated help description of a command
+func commandDescriptionKey(name string) string {
+	return "command." + name + ".description"
+}
+
+// defaultMessages holds the built-in translations of the system messages
+var defaultMessages = map[string]i18n.Messages{
+	"en": {
+		msgInvalidMessage:   "Sorry, your message could not be processed. Please check the format and try again.",
+		msgProcessingFailed: "Sorry, I encountered an error processing your request.",
+		msgSessionFailed:    "Sorry, I encountered an error creating a session.",
+		msgResponseFailed:   "Sorry, I encountered an error generating a response.",
+
+		msgCommandDenied: "Sorry, you are not allowed to use %s.",
+		msgCommandUsage:  "Usage: %s",
+		msgCommandFailed: "Sorry, I encountered an error running %s.",
+
+		msgHelpTitle:                             "Available commands:",
+		commandDescriptionKey(CancelCommand):     "Cancel the reply being generated",
+		commandDescriptionKey(HelpCommand):       "List the available commands",
+		commandDescriptionKey(NewSessionCommand): "Start a new session",
+		commandDescriptionKey(ResetCommand):      "Clear the conversation of the current session",
+		commandDescriptionKey(ForkCommand):       "Continue in a copy of the current session",
+		commandDescriptionKey(FeedbackCommand):   "Rate a reply",
+		commandDescriptionKey(ExportCommand):     "Send a transcript of the current session",
+		commandDescriptionKey(SessionsCommand):   "List your recent sessions",
+		commandDescriptionKey(InstructCommand):   "Show or set your custom instructions",
+		commandDescriptionKey(SettingsCommand):   "Show or change your settings",
+		commandDescriptionKey(SkillsCommand):     "List the available skills",
+		commandDescriptionKey(UsageCommand):      "Show the usage of the current session",
+
+		msgNewSessionDone: "Started a new session.",
+
+		msgCancelDone:    "Cancelled.",
+		msgCancelNothing: "Nothing to cancel.",
+		msgPlanStep:      "Step %d/%d: %s",
+		msgSources:       "Sources:",
+
+		msgResetUnavailable: "Sorry, reset is not available.",
+		msgResetFailed:      "Sorry, I encountered an error clearing the conversation.",
+		msgResetDone:        "Conversation cleared.",
+
+		msgForkUnavailable: "Sorry, forking is not available.",
+		msgForkFailed:      "Sorry, I encountered an error forking the session.",
+		msgForkDone:        "Started a copy of the session; the original is kept as it is.",
+
+		msgFeedbackUnavailable: "Sorry, feedback is not available.",
+		msgFeedbackFailed:      "Sorry, I encountered an error saving your feedback.",
+		msgFeedbackDone:        "Thanks for your feedback!",
+
+		msgExportUnavailable: "Sorry, export is not available.",
+		msgExportFailed:      "Sorry, I encountered an error exporting the session.",
+		msgExportCaption:     "Session transcript",
+
+		msgSkillsUnavailable: "Sorry, skills are not available.",
+		msgSkillsFailed:      "Sorry, I encountered an error listing the skills.",
+		msgSkillsNone:        "No skills are available.",
+		msgSkillsTitle:       "Available skills:",
+		msgSkillFailed:       "Sorry, %s failed: %s",
+		msgSkillDone:         "Done.",
+
+		msgSessionsFailed:   "Sorry, I encountered an error listing your sessions.",
+		msgSessionsTitle:    "Your recent sessions:",
+		msgSessionsItem:     "%s - %s",
+		msgSessionsUntitled: "Session %s",
+		msgSessionsCurrent:  "(current)",
+
+		msgUsageFailed:         "Sorry, I encountered an error reading the session usage.",
+		msgUsageStarted:        "Session started: %s",
+		msgUsageStartedExpires: "Session started: %s (expires after %s)",
+		msgUsageMessages:       "Messages: %d",
+		msgUsageMessagesMax:    "Messages: %d of %d",
+		msgUsageSessions:       "Sessions: %d",
+
+		msgInstructUnavailable: "Sorry, custom instructions are not available.",
+		msgInstructReadFailed:  "Sorry, I encountered an error reading your instructions.",
+		msgInstructSaveFailed:  "Sorry, I encountered an error saving your instructions.",
+		msgInstructTooLong:     "Sorry, instructions must be at most %d characters.",
+		msgInstructSaved:       "Instructions saved.",
+		msgInstructCleared:     "Instructions removed.",
+		msgInstructUser:        "Your instructions: %s",
+		msgInstructSession:     "Instructions of this session: %s",
+		msgInstructNone:        "none",
+		msgInstructHint:        "Set them with %[1]s <text> for all your sessions or %[1]s session <text> for this one; clear removes them.",
+
+		msgDocumentRead:        "I have read %s (%d parts). Ask me anything about it.",
+		msgDocumentUnsupported: "Sorry, I can only read PDF and text documents.",
+		msgDocumentTooLarge:    "Sorry, the document is too large; I can read documents of up to %d MB.",
+		msgDocumentEmpty:       "Sorry, I found no text in the document. Scanned documents cannot be read.",
+		msgDocumentFailed:      "Sorry, I encountered an error reading the document.",
+
+		msgSettingsUnavailable:      "Sorry, settings are not available.",
+		msgSettingsReadFailed:       "Sorry, I encountered an error reading your settings.",
+		msgSettingsSaveFailed:       "Sorry, I encountered an error saving your settings.",
+		msgSettingsResetFailed:      "Sorry, I encountered an error resetting your settings.",
+		msgSettingsSaved:            "Settings saved.",
+		msgSettingsResetDone:        "Settings reset.",
+		msgSettingsTitle:            "Your settings:",
+		msgSettingsDefault:          "default",
+		msgSettingsHint:             "Change a setting with %[1]s <name> <value>, clear it with %[1]s <name>.",
+		msgSettingsResetButton:      "Reset",
+		msgSettingsInvalidLanguage:  "Sorry, the language must be a language code such as en or pt-BR.",
+		msgSettingsInvalidTimezone:  "Sorry, the timezone must be an IANA time zone such as Europe/Berlin.",
+		msgSettingsInvalidVerbosity: "Sorry, the verbosity must be concise, normal or detailed.",
+	},
+	"ru": {
+		msgInvalidMessage:   "Извините, не удалось обработать сообщение. Проверьте формат и попробуйте ещё раз.",
+		msgProcessingFailed: "Извините, при обработке запроса произошла ошибка.",
+		msgSessionFailed:    "Извините, не удалось создать сессию.",
+		msgResponseFailed:   "Извините, не удалось сформировать ответ.",
+
+		msgCommandDenied: "Извините, вам недоступна команда %s.",
+		msgCommandUsage:  "Использование: %s",
+		msgCommandFailed: "Извините, при выполнении %s произошла ошибка.",
+
+		msgHelpTitle:                             "Доступные команды:",
+		commandDescriptionKey(CancelCommand):     "Отменить формируемый ответ",
+		commandDescriptionKey(HelpCommand):       "Список доступных команд",
+		commandDescriptionKey(NewSessionCommand): "Начать новую сессию",
+		commandDescriptionKey(ResetCommand):      "Очистить переписку текущей сессии",
+		commandDescriptionKey(ForkCommand):       "Продолжить в копии текущей сессии",
+		commandDescriptionKey(FeedbackCommand):   "Оценить ответ",
+		commandDescriptionKey(ExportCommand):     "Прислать транскрипт текущей сессии",
+		commandDescriptionKey(SessionsCommand):   "Список последних сессий",
+		commandDescriptionKey(InstructCommand):   "Показать или задать свои инструкции",
+		commandDescriptionKey(SettingsCommand):   "Показать или изменить настройки",
+		commandDescriptionKey(SkillsCommand):     "Список доступных skills",
+		commandDescriptionKey(UsageCommand):      "Использование текущей сессии",
+
+		msgNewSessionDone: "Начата новая сессия.",
+
+		msgCancelDone:    "Отменено.",
+		msgCancelNothing: "Нечего отменять.",
+		msgPlanStep:      "Шаг %d/%d: %s",
+		msgSources:       "Источники:",
+
+		msgResetUnavailable: "Извините, очистка недоступна.",
+		msgResetFailed:      "Извините, не удалось очистить переписку.",
+		msgResetDone:        "Переписка очищена.",
+
+		msgForkUnavailable: "Извините, копирование сессии недоступно.",
+		msgForkFailed:      "Извините, не удалось скопировать сессию.",
+		msgForkDone:        "Начата копия сессии; исходная сессия сохранена.",
+
+		msgFeedbackUnavailable: "Извините, оценка ответов недоступна.",
+		msgFeedbackFailed:      "Извините, не удалось сохранить оценку.",
+		msgFeedbackDone:        "Спасибо за оценку!",
+
+		msgExportUnavailable: "Извините, экспорт недоступен.",
+		msgExportFailed:      "Извините, не удалось экспортировать сессию.",
+		msgExportCaption:     "Транскрипт сессии",
+
+		msgSkillsUnavailable: "Извините, skills недоступны.",
+		msgSkillsFailed:      "Извините, не удалось получить список skills.",
+		msgSkillsNone:        "Нет доступных skills.",
+		msgSkillsTitle:       "Доступные skills:",
+		msgSkillFailed:       "Извините, команда %s завершилась ошибкой: %s",
+		msgSkillDone:         "Готово.",
+
+		msgSessionsFailed:   "Извините, не удалось получить список сессий.",
+		msgSessionsTitle:    "Ваши последние сессии:",
+		msgSessionsItem:     "%s - %s",
+		msgSessionsUntitled: "Сессия %s",
+		msgSessionsCurrent:  "(текущая)",
+
+		msgUsageFailed:         "Извините, не удалось получить использование сессии.",
+		msgUsageStarted:        "Сессия начата: %s",
+		msgUsageStartedExpires: "Сессия начата: %s (срок жизни %s)",
+		msgUsageMessages:       "Сообщений: %d",
+		msgUsageMessagesMax:    "Сообщений: %d из %d",
+		msgUsageSessions:       "Сессий: %d",
+
+		msgInstructUnavailable: "Извините, инструкции недоступны.",
+		msgInstructReadFailed:  "Извините, не удалось прочитать инструкции.",
+		msgInstructSaveFailed:  "Извините, не удалось сохранить инструкции.",
+		msgInstructTooLong:     "Извините, инструкции должны быть не длиннее %d символов.",
+		msgInstructSaved:       "Инструкции сохранены.",
+		msgInstructCleared:     "Инструкции удалены.",
+		msgInstructUser:        "Ваши инструкции: %s",
+		msgInstructSession:     "Инструкции этой сессии: %s",
+		msgInstructNone:        "нет",
+		msgInstructHint:        "Задать их: %[1]s <text> для всех ваших сессий или %[1]s session <text> для этой; clear удаляет их.",
+
+		msgDocumentRead:        "Документ %s прочитан (частей: %d). Задавайте вопросы по нему.",
+		msgDocumentUnsupported: "Извините, я умею читать только PDF и текстовые документы.",
+		msgDocumentTooLarge:    "Извините, документ слишком большой; я читаю документы размером до %d МБ.",
+		msgDocumentEmpty:       "Извините, в документе не найден текст. Отсканированные документы не читаются.",
+		msgDocumentFailed:      "Извините, не удалось прочитать документ.",
+
+		msgSettingsUnavailable:      "Извините, настройки недоступны.",
+		msgSettingsReadFailed:       "Извините, не удалось прочитать настройки.",
+		msgSettingsSaveFailed:       "Извините, не удалось сохранить настройки.",
+		msgSettingsResetFailed:      "Извините, не удалось сбросить настройки.",
+		msgSettingsSaved:            "Настройки сохранены.",
+		msgSettingsResetDone:        "Настройки сброшены.",
+		msgSettingsTitle:            "Ваши настройки:",
+		msgSettingsDefault:          "по умолчанию",
+		msgSettingsHint:             "Изменить настройку: %[1]s <name> <value>, сбросить: %[1]s <name>.",
+		msgSettingsResetButton:      "Сбросить",
+		msgSettingsInvalidLanguage:  "Извините, язык задаётся кодом, например en или pt-BR.",
+		msgSettingsInvalidTimezone:  "Извините, часовой пояс задаётся в формате IANA, например Europe/Berlin.",
+		msgSettingsInvalidVerbosity: "Извините, подробность может быть concise, normal или detailed.",
+	},
+}
+
+// newCatalog creates the catalog of the system messages with the translations of the configuration
+func newCatalog(config I18nConfig) *i18n.Catalog {
+	catalog := i18n.NewCatalog(config.DefaultLanguage)
+	for language, messages := range defaultMessages {
+		catalog.Add(language, messages)
+	}
+	for language, messages := range config.Messages {
+		catalog.Add(language, messages)
+	}
+	return catalog
+}
+
+// Translate returns a system message in a language, formatted with args. Chat commands
+// registered with RegisterCommand can use it with CommandCall.Language for their replies.
+func (r *MessageRouter) Translate(language, key string, args ...interface{}) string {
+	return r.catalog.Translate(language, key, args...)
+}
+
+// connectorLanguage returns the language of the system messages sent through a connector
+func (r *MessageRouter) connectorLanguage(connectorName string) string {
+	if language := r.config.I18n.Languages[connectorName]; language != "" {
+		return language
+	}
+	return r.config.I18n.DefaultLanguage
+}
+
+// language returns the language of the system messages sent to a user: their preferred
+// language, or else the language of the connector
+func (r *MessageRouter) language(ctx context.Context, connectorName string, user *entity.User) string {
+	r.mu.RLock()
+	preferences := r.preferences
+	r.mu.RUnlock()
+	if preferences == nil || user == nil {
+		return r.connectorLanguage(connectorName)
+	}
+
+	prefs, err := preferences.Get(ctx, user.ID.String())
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			r.logger.Warn("failed to read user preferences", "user_id", user.ID, "error", err)
+		}
+		return r.connectorLanguage(connectorName)
+	}
+	if prefs.Language == "" {
+		return r.connectorLanguage(connectorName)
+	}
+	return prefs.Language
+}
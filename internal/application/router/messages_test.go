@@ -0,0 +1,62 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/i18n"
+)
+
+func TestDefaultMessages_Complete(t *testing.T) {
+	for language, messages := range defaultMessages {
+		for key := range defaultMessages[i18n.DefaultLanguage] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("Missing %s translation of %s", language, key)
+			}
+		}
+	}
+}
+
+func TestMessages_ConnectorLanguage(t *testing.T) {
+	config := DefaultConfig()
+	config.I18n = I18nConfig{
+		Languages: map[string]string{"telegram": "ru"},
+		Messages:  map[string]i18n.Messages{"ru": {msgResetUnavailable: "Очистка выключена."}},
+	}
+	router := newCommandTestRouter(config)
+
+	if reply := router.sendText(t, "/reset"); reply.Content != "Очистка выключена." {
+		t.Errorf("Expected the configured translation, got %q", reply.Content)
+	}
+	if reply := router.sendText(t, "/help"); !strings.HasPrefix(reply.Content, "Доступные команды:") ||
+		!strings.Contains(reply.Content, "/new - Начать новую сессию") {
+		t.Errorf("Expected the help in Russian, got:\n%s", reply.Content)
+	}
+	if reply := router.sendText(t, "/export pdf"); reply.Content != "Извините, экспорт недоступен." {
+		t.Errorf("Expected the export error in Russian, got %q", reply.Content)
+	}
+}
+
+func TestMessages_UserLanguage(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+	router.SetUserPreferencesRepository(&mockUserPreferencesRepository{prefs: map[string]*entity.UserPreferences{}})
+
+	if reply := router.sendText(t, "/new"); reply.Content != "Started a new session." {
+		t.Errorf("Expected English by default, got %q", reply.Content)
+	}
+
+	// The change is confirmed in the new language
+	if reply := router.sendText(t, "/settings language ru"); !strings.HasPrefix(reply.Content, "Настройки сохранены.") {
+		t.Errorf("Expected the settings reply in Russian, got:\n%s", reply.Content)
+	}
+	if reply := router.sendText(t, "/new"); reply.Content != "Начата новая сессия." {
+		t.Errorf("Expected the preferred language, got %q", reply.Content)
+	}
+
+	// Languages without translations fall back to English
+	router.sendText(t, "/settings language de")
+	if reply := router.sendText(t, "/new"); reply.Content != "Started a new session." {
+		t.Errorf("Expected the English fallback, got %q", reply.Content)
+	}
+}
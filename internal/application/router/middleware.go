@@ -0,0 +1,80 @@
+package router
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
+)
+
+// Request is a validated message from a connector on its way to the orchestrator
+type Request struct {
+	// Connector is the name of the connector that received the message
+	Connector string
+
+	// Conn is the connector that received the message; replies are sent through it
+	Conn channels.Connector
+
+	// Message is the received message. Middlewares may change it, e.g. to filter its
+	// content or to add metadata for the middlewares and handler further down the chain.
+	Message *channels.Message
+
+	// span traces the message from its receipt until it is handled
+	span *tracing.Span
+}
+
+// Reply sends a text response to the sender of the message, bypassing the orchestrator
+func (req *Request) Reply(ctx context.Context, content string) error {
+	return req.Conn.SendResponse(ctx, req.Message.UserID, &channels.Response{
+		Type:    channels.ResponseTypeText,
+		Content: content,
+	})
+}
+
+// Handler handles a message received from a connector.
+// An error is logged and counted as a failed message; the handler is responsible for
+// replying to the user.
+type Handler func(ctx context.Context, req *Request) error
+
+// Middleware wraps a handler to add behaviour before or after it, such as content
+// filtering, language detection, throttling or analytics. A middleware that does not
+// call next stops the message from reaching the orchestrator.
+type Middleware func(next Handler) Handler
+
+// Chain wraps a handler in middlewares. The first middleware is the outermost one,
+// so it sees the message first and the outcome last.
+//
+// Parameters:
+//   - handler: Innermost handler
+//   - middlewares: Middlewares in the order they see the message
+//
+// Returns:
+//   - Handler: Handler running the middlewares and then handler
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Use appends middlewares to the chain every received message passes through before
+// it is routed to the orchestrator. Middlewares run in the order they were added.
+// Messages already being handled keep the chain they started with.
+//
+// Parameters:
+//   - middlewares: Middlewares to append
+func (r *MessageRouter) Use(middlewares ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middlewares = append(r.middlewares, middlewares...)
+	r.handler = Chain(r.handleMessage, r.middlewares...)
+	r.logger.Info("router middlewares registered", "total_middlewares", len(r.middlewares))
+}
+
+// messageHandler returns the handler at the top of the middleware chain
+func (r *MessageRouter) messageHandler() Handler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.handler
+}
@@ -0,0 +1,68 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// Metadata entries through which a connector overrides the LLM options of a message
+const (
+	MetadataLLMProvider    = "llm_provider"    // Configured provider to answer with
+	MetadataLLMModel       = "llm_model"       // Model to answer with
+	MetadataLLMTemperature = "llm_temperature" // Sampling temperature, between 0 and 2
+	MetadataLLMMaxTokens   = "llm_max_tokens"  // Token budget of the reply, capped at that of the workspace
+)
+
+// maxLLMTemperature is the highest temperature a message may set
+const maxLLMTemperature = 2.0
+
+// messageOptions returns the LLM options of a message in a session: the token budget of the
+// workspace of the session, with the overrides found in the metadata of the message.
+// Overrides that are not valid are ignored.
+func (r *MessageRouter) messageOptions(ctx context.Context, msg *channels.Message, session *entity.Session) dto.MessageOptions {
+	options := dto.MessageOptions{
+		MaxTokens: r.maxTokens(ctx, session.WorkspaceID),
+		SessionID: session.ID.String(),
+	}
+	if len(msg.Metadata) == 0 {
+		return options
+	}
+
+	options.Provider, _ = msg.Metadata[MetadataLLMProvider].(string)
+	options.Model, _ = msg.Metadata[MetadataLLMModel].(string)
+	if temperature, ok := metadataNumber(msg.Metadata[MetadataLLMTemperature]); ok && temperature >= 0 && temperature <= maxLLMTemperature {
+		options.Temperature = &temperature
+	}
+	if maxTokens, ok := metadataNumber(msg.Metadata[MetadataLLMMaxTokens]); ok && maxTokens > 0 && int(maxTokens) < options.MaxTokens {
+		options.MaxTokens = int(maxTokens)
+	}
+	return options
+}
+
+// metadataNumber reads a number from a metadata value, which connectors may store as a number
+// of any type or as a string
+func metadataNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case nil:
+		return 0, false
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	default:
+		n, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		return n, err == nil
+	}
+}
@@ -0,0 +1,221 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// ResponseFormat is the formatting of the replies sent through a connector
+type ResponseFormat string
+
+// Formats of the replies
+const (
+	// FormatMarkdown sends the Markdown of the LLM as it is
+	FormatMarkdown ResponseFormat = "markdown"
+
+	// FormatPlain strips the Markdown, for connectors that show text only
+	FormatPlain ResponseFormat = "plain"
+
+	// FormatHTML converts the Markdown to the HTML subset of Telegram (b, i, code, pre, a)
+	// and sets the "parse_mode" metadata of the response to "HTML"
+	FormatHTML ResponseFormat = "html"
+)
+
+// IsValid checks if the format is known; the empty format means FormatMarkdown
+func (f ResponseFormat) IsValid() bool {
+	switch f {
+	case "", FormatMarkdown, FormatPlain, FormatHTML:
+		return true
+	}
+	return false
+}
+
+// PostProcessingConfig selects by connector how the replies of the LLM are transformed
+// before they are sent
+type PostProcessingConfig struct {
+	// Default applies to the connectors not listed in Connectors
+	Default PostProcessing
+
+	// Connectors sets the post-processing by connector name, replacing Default
+	Connectors map[string]PostProcessing
+}
+
+// PostProcessing is the post-processing of the replies sent through a connector. The steps
+// run in the order of the fields.
+type PostProcessing struct {
+	// StripReasoning removes reasoning blocks such as <think>...</think> from the reply
+	StripReasoning bool
+
+	// MaxLength cuts replies longer than this many characters; 0 leaves them whole
+	MaxLength int
+
+	// Citations replaces the Markdown links of the reply with numbered citations listed under it
+	Citations bool
+
+	// Format converts the Markdown of the reply (default FormatMarkdown)
+	Format ResponseFormat
+}
+
+// validate checks that the post-processing named field is valid
+func (pp PostProcessing) validate(field string) error {
+	if pp.MaxLength < 0 {
+		return NewValidationError(field + ".MaxLength must be non-negative")
+	}
+	if !pp.Format.IsValid() {
+		return NewValidationError(fmt.Sprintf("%s.Format must be one of markdown, plain, html, got %q", field, pp.Format))
+	}
+	return nil
+}
+
+// ResponseProcessor transforms a reply of the LLM before it is sent through a connector
+type ResponseProcessor func(ctx context.Context, connectorName string, response *channels.Response)
+
+// AddResponseProcessor adds a processor run on every reply of the LLM, after the configured
+// post-processing of its connector. Processors run in the order they were added.
+func (r *MessageRouter) AddResponseProcessor(processor ResponseProcessor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processors = append(r.processors, processor)
+}
+
+// postProcess applies the post-processing of a connector and the added processors to a reply.
+// Citations are introduced in language.
+func (r *MessageRouter) postProcess(ctx context.Context, connectorName, language string, response *channels.Response) {
+	pp, ok := r.config.PostProcessing.Connectors[connectorName]
+	if !ok {
+		pp = r.config.PostProcessing.Default
+	}
+
+	content := response.Content
+	if pp.StripReasoning {
+		content = stripReasoning(content)
+	}
+	if pp.MaxLength > 0 {
+		content = truncate(content, pp.MaxLength)
+	}
+	if pp.Citations {
+		content = addCitations(content, r.Translate(language, msgSources))
+	}
+	switch pp.Format {
+	case FormatPlain:
+		content = formatMarkdown(content, FormatPlain)
+	case FormatHTML:
+		content = formatMarkdown(content, FormatHTML)
+		response.Metadata["parse_mode"] = "HTML"
+	}
+	response.Content = content
+
+	r.mu.RLock()
+	processors := r.processors
+	r.mu.RUnlock()
+	for _, processor := range processors {
+		processor(ctx, connectorName, response)
+	}
+}
+
+var (
+	reasoningPattern  = regexp.MustCompile(`(?is)<(?:think|thinking|reasoning)>.*?</(?:think|thinking|reasoning)>`)
+	linkPattern       = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^)\s]+)\)`)
+	codeBlockPattern  = regexp.MustCompile("(?s)```[\\w+-]*\\n?(.*?)```")
+	inlineCodePattern = regexp.MustCompile("`([^`\\n]+)`")
+	headingPattern    = regexp.MustCompile(`(?m)^#{1,6}[ \t]+(.+)$`)
+	boldPattern       = regexp.MustCompile(`\*\*([^*\n]+)\*\*|__([^_\n]+)__`)
+	italicPattern     = regexp.MustCompile(`\*([^*\s](?:[^*\n]*[^*\s])?)\*`)
+)
+
+// stripReasoning removes the reasoning blocks some models put before their answer
+func stripReasoning(content string) string {
+	return strings.TrimSpace(reasoningPattern.ReplaceAllString(content, ""))
+}
+
+// truncate cuts content to at most maxLength characters, marking the cut with an ellipsis
+func truncate(content string, maxLength int) string {
+	runes := []rune(content)
+	if len(runes) <= maxLength {
+		return content
+	}
+	return strings.TrimRightFunc(string(runes[:maxLength-1]), unicode.IsSpace) + "…"
+}
+
+// addCitations replaces the links of content with their text and a citation number, and lists
+// the linked URLs under title. A URL linked twice keeps its number.
+func addCitations(content, title string) string {
+	var urls []string
+	numbers := make(map[string]int)
+	content = linkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		match := linkPattern.FindStringSubmatch(link)
+		n, ok := numbers[match[2]]
+		if !ok {
+			urls = append(urls, match[2])
+			n = len(urls)
+			numbers[match[2]] = n
+		}
+		return fmt.Sprintf("%s [%d]", match[1], n)
+	})
+	if len(urls) == 0 {
+		return content
+	}
+
+	var b strings.Builder
+	b.WriteString(content + "\n\n" + title)
+	for i, url := range urls {
+		fmt.Fprintf(&b, "\n[%d] %s", i+1, url)
+	}
+	return b.String()
+}
+
+// formatMarkdown converts the Markdown of content to format. The text of code is kept as it is.
+func formatMarkdown(content string, format ResponseFormat) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range codeBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+		b.WriteString(formatInline(content[last:m[0]], format))
+		code := content[m[2]:m[3]]
+		if format == FormatHTML {
+			code = "<pre>" + html.EscapeString(strings.TrimSuffix(code, "\n")) + "</pre>"
+		}
+		b.WriteString(code)
+		last = m[1]
+	}
+	b.WriteString(formatInline(content[last:], format))
+	return b.String()
+}
+
+// formatInline converts the Markdown of text without code blocks to format
+func formatInline(text string, format ResponseFormat) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range inlineCodePattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(formatText(text[last:m[0]], format))
+		code := text[m[2]:m[3]]
+		if format == FormatHTML {
+			code = "<code>" + html.EscapeString(code) + "</code>"
+		}
+		b.WriteString(code)
+		last = m[1]
+	}
+	b.WriteString(formatText(text[last:], format))
+	return b.String()
+}
+
+// formatText converts the links, headings and emphasis of text without code to format
+func formatText(text string, format ResponseFormat) string {
+	if format != FormatHTML {
+		text = linkPattern.ReplaceAllString(text, "$1 ($2)")
+		text = headingPattern.ReplaceAllString(text, "$1")
+		text = boldPattern.ReplaceAllString(text, "$1$2")
+		return italicPattern.ReplaceAllString(text, "$1")
+	}
+
+	text = html.EscapeString(text)
+	text = linkPattern.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = headingPattern.ReplaceAllString(text, "<b>$1</b>")
+	text = boldPattern.ReplaceAllString(text, "<b>$1$2</b>")
+	return italicPattern.ReplaceAllString(text, "<i>$1</i>")
+}
@@ -0,0 +1,120 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func TestStripReasoning(t *testing.T) {
+	got := stripReasoning("<think>The user wants a greeting.\nSay hi.</think>\n\nHi! <REASONING>done</REASONING>there")
+	if got != "Hi! there" {
+		t.Errorf("Expected the reasoning to be stripped, got %q", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		content   string
+		maxLength int
+		want      string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"Привет, мир", 8, "Привет,…"},
+		{"cut at a space", 7, "cut at…"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.content, tt.maxLength); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.content, tt.maxLength, got, tt.want)
+		}
+	}
+}
+
+func TestAddCitations(t *testing.T) {
+	got := addCitations("See [the docs](https://example.com/docs), [the API](https://example.com/api) and [docs](https://example.com/docs).", "Sources:")
+	want := "See the docs [1], the API [2] and docs [1].\n\nSources:\n[1] https://example.com/docs\n[2] https://example.com/api"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	if got := addCitations("No links here.", "Sources:"); got != "No links here." {
+		t.Errorf("Expected text without links to be kept, got %q", got)
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	content := "# Result\n**Bold** and *italic* with `a<b`, 2 * 3 * 4 and [a link](https://example.com?a=1&b=2).\n```go\nif a < b {}\n```"
+
+	tests := []struct {
+		format ResponseFormat
+		want   string
+	}{
+		{FormatPlain, "Result\nBold and italic with a<b, 2 * 3 * 4 and a link (https://example.com?a=1&b=2).\nif a < b {}\n"},
+		{FormatHTML, "<b>Result</b>\n<b>Bold</b> and <i>italic</i> with <code>a&lt;b</code>, 2 * 3 * 4 and " +
+			"<a href=\"https://example.com?a=1&amp;b=2\">a link</a>.\n<pre>if a &lt; b {}</pre>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			if got := formatMarkdown(content, tt.format); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleMessage_PostProcessesReplies(t *testing.T) {
+	config := DefaultConfig()
+	config.PostProcessing.Connectors = map[string]PostProcessing{
+		"telegram": {StripReasoning: true, Citations: true, Format: FormatHTML},
+	}
+	orchestrator := newMockOrchestrator()
+	router := NewMessageRouter(newMockSessionRepository(), orchestrator, nil, logging.NewNoopLogger(), config)
+
+	var processed []string
+	router.AddResponseProcessor(func(ctx context.Context, connectorName string, response *channels.Response) {
+		processed = append(processed, connectorName)
+		response.Content += "\n— NexFlow"
+	})
+
+	tests := []struct {
+		connector     string
+		content       string
+		want          string
+		wantParseMode interface{}
+	}{
+		{"telegram", "<think>Link the docs</think>**Read** [the docs](https://example.com)",
+			"Response: <b>Read</b> the docs [1]\n\nSources:\n[1] https://example.com\n— NexFlow", "HTML"},
+		// Connectors without their own post-processing use the default, which only strips reasoning
+		{"discord", "<think>Link the docs</think>**Read** [the docs](https://example.com)",
+			"Response: **Read** [the docs](https://example.com)\n— NexFlow", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.connector, func(t *testing.T) {
+			conn := newMockConnector(tt.connector)
+			err := router.handleMessage(context.Background(), &Request{Connector: conn.Name(), Conn: conn, Message: &channels.Message{UserID: "user-123", Content: tt.content}})
+			if err != nil {
+				t.Fatalf("handleMessage failed: %v", err)
+			}
+
+			if len(conn.sent) != 1 {
+				t.Fatalf("Expected one reply, got %d", len(conn.sent))
+			}
+			if conn.sent[0].Content != tt.want {
+				t.Errorf("Expected reply %q, got %q", tt.want, conn.sent[0].Content)
+			}
+			if conn.sent[0].Metadata["parse_mode"] != tt.wantParseMode {
+				t.Errorf("Expected parse mode %v, got %v", tt.wantParseMode, conn.sent[0].Metadata["parse_mode"])
+			}
+		})
+	}
+
+	if len(processed) != 2 || processed[0] != "telegram" || processed[1] != "discord" {
+		t.Errorf("Expected the added processor to run for both connectors, got %v", processed)
+	}
+}
@@ -0,0 +1,120 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// settingsReset is the /settings argument deleting the preferences of the user
+const settingsReset = "reset"
+
+// SetUserPreferencesRepository sets the repository of the user preferences shown and changed by
+// the /settings and /instruct chat commands
+func (r *MessageRouter) SetUserPreferencesRepository(preferences repository.UserPreferencesRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.preferences = preferences
+}
+
+// handleSettingsCommand shows the preferences of the user, sets one with
+// "/settings <name> <value>" or deletes them with "/settings reset".
+// An empty value clears a preference.
+func (r *MessageRouter) handleSettingsCommand(ctx context.Context, call *CommandCall) (*channels.Response, error) {
+	r.mu.RLock()
+	preferences := r.preferences
+	r.mu.RUnlock()
+	if preferences == nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgSettingsUnavailable), nil)
+	}
+	userID := call.User.ID.String()
+
+	if len(call.Args) == 1 && strings.ToLower(call.Args[0]) == settingsReset {
+		if _, err := preferences.Delete(ctx, userID); err != nil {
+			return nil, NewCommandError(r.Translate(call.Language, msgSettingsResetFailed), err)
+		}
+		call.Language = r.connectorLanguage(call.Connector)
+		return r.settingsResponse(call, r.Translate(call.Language, msgSettingsResetDone), entity.NewUserPreferences(userID)), nil
+	}
+
+	prefs, err := preferences.Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		prefs, err = entity.NewUserPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgSettingsReadFailed), err)
+	}
+	if len(call.Args) == 0 {
+		return r.settingsResponse(call, "", prefs), nil
+	}
+
+	name := strings.ToLower(call.Args[0])
+	if err := prefs.Set(name, strings.Join(call.Args[1:], " ")); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidLanguage):
+			return nil, NewCommandError(r.Translate(call.Language, msgSettingsInvalidLanguage), nil)
+		case errors.Is(err, entity.ErrInvalidTimezone):
+			return nil, NewCommandError(r.Translate(call.Language, msgSettingsInvalidTimezone), nil)
+		case errors.Is(err, entity.ErrInvalidVerbosity):
+			return nil, NewCommandError(r.Translate(call.Language, msgSettingsInvalidVerbosity), nil)
+		}
+		return nil, ErrCommandUsage
+	}
+	if err := preferences.Save(ctx, prefs); err != nil {
+		return nil, NewCommandError(r.Translate(call.Language, msgSettingsSaveFailed), err)
+	}
+	// Answer a language change in the new language
+	if name == entity.PreferenceLanguage {
+		call.Language = prefs.Language
+		if call.Language == "" {
+			call.Language = r.connectorLanguage(call.Connector)
+		}
+	}
+	return r.settingsResponse(call, r.Translate(call.Language, msgSettingsSaved), prefs), nil
+}
+
+// settingsResponse lists the preferences of the user, with buttons choosing the verbosity and
+// resetting them on connectors supporting inline buttons
+func (r *MessageRouter) settingsResponse(call *CommandCall, notice string, prefs *entity.UserPreferences) *channels.Response {
+	var b strings.Builder
+	if notice != "" {
+		b.WriteString(notice + "\n")
+	}
+	b.WriteString(r.Translate(call.Language, msgSettingsTitle))
+	for _, setting := range []struct{ name, value string }{
+		{entity.PreferenceLanguage, prefs.Language},
+		{entity.PreferenceModel, prefs.Model},
+		{entity.PreferenceTimezone, prefs.Timezone},
+		{entity.PreferenceVerbosity, string(prefs.Verbosity)},
+	} {
+		value := setting.value
+		if value == "" {
+			value = r.Translate(call.Language, msgSettingsDefault)
+		}
+		fmt.Fprintf(&b, "\n%s: %s", setting.name, value)
+	}
+	b.WriteString("\n\n" + r.Translate(call.Language, msgSettingsHint, call.Prefix+SettingsCommand))
+
+	buttons := make([]channels.InlineButton, 0, len(entity.Verbosities)+1)
+	for _, verbosity := range entity.Verbosities {
+		text := string(verbosity)
+		if verbosity == prefs.Verbosity {
+			text = "✓ " + text
+		}
+		buttons = append(buttons, channels.InlineButton{
+			Text: text,
+			Data: fmt.Sprintf("%s%s %s %s", call.Prefix, SettingsCommand, entity.PreferenceVerbosity, verbosity),
+		})
+	}
+	buttons = append(buttons, channels.InlineButton{
+		Text: r.Translate(call.Language, msgSettingsResetButton),
+		Data: call.Prefix + SettingsCommand + " " + settingsReset,
+	})
+
+	return &channels.Response{Content: b.String(), Buttons: buttons}
+}
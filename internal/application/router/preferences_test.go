@@ -0,0 +1,92 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+)
+
+// mockUserPreferencesRepository is a mock implementation of repository.UserPreferencesRepository for testing
+type mockUserPreferencesRepository struct {
+	prefs map[string]*entity.UserPreferences
+}
+
+func (m *mockUserPreferencesRepository) Get(ctx context.Context, userID string) (*entity.UserPreferences, error) {
+	prefs, ok := m.prefs[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return prefs, nil
+}
+
+func (m *mockUserPreferencesRepository) Save(ctx context.Context, prefs *entity.UserPreferences) error {
+	m.prefs[prefs.UserID.String()] = prefs
+	return nil
+}
+
+func (m *mockUserPreferencesRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	_, ok := m.prefs[userID]
+	delete(m.prefs, userID)
+	return ok, nil
+}
+
+func TestSettingsCommand(t *testing.T) {
+	router := newCommandTestRouter(DefaultConfig())
+
+	// Without a preferences repository, settings are not available
+	if reply := router.sendText(t, "/settings"); reply.Content != "Sorry, settings are not available." {
+		t.Errorf("Expected settings to be unavailable, got %q", reply.Content)
+	}
+
+	preferences := &mockUserPreferencesRepository{prefs: map[string]*entity.UserPreferences{}}
+	router.SetUserPreferencesRepository(preferences)
+
+	reply := router.sendText(t, "/settings")
+	if !strings.Contains(reply.Content, "language: default") {
+		t.Errorf("Expected the default settings, got:\n%s", reply.Content)
+	}
+	if len(reply.Buttons) != len(entity.Verbosities)+1 {
+		t.Fatalf("Expected a button per verbosity and a reset button, got %+v", reply.Buttons)
+	}
+
+	reply = router.sendText(t, "/settings timezone Europe/Berlin")
+	if !strings.HasPrefix(reply.Content, "Settings saved.") || !strings.Contains(reply.Content, "timezone: Europe/Berlin") {
+		t.Errorf("Expected the timezone to be saved, got:\n%s", reply.Content)
+	}
+
+	// Telegram sends the data of a pressed button as the message content
+	reply = router.send(t, &channels.Message{
+		UserID:   "user-123",
+		Content:  reply.Buttons[0].Data,
+		Metadata: map[string]interface{}{"message_type": "callback_query"},
+	})
+	if !strings.Contains(reply.Content, "verbosity: concise") || reply.Buttons[0].Text != "✓ concise" {
+		t.Errorf("Expected the concise verbosity to be chosen, got:\n%s\n%+v", reply.Content, reply.Buttons)
+	}
+
+	var userID string
+	for id, prefs := range preferences.prefs {
+		userID = id
+		if prefs.Timezone != "Europe/Berlin" || prefs.Verbosity != entity.VerbosityConcise {
+			t.Errorf("Expected the saved preferences, got %+v", prefs)
+		}
+	}
+
+	if reply := router.sendText(t, "/settings timezone Mars/Olympus"); !strings.Contains(reply.Content, "IANA time zone") {
+		t.Errorf("Expected an invalid timezone error, got %q", reply.Content)
+	}
+	if reply := router.sendText(t, "/settings color blue"); !strings.HasPrefix(reply.Content, "Usage: /settings") {
+		t.Errorf("Expected the usage for an unknown setting, got %q", reply.Content)
+	}
+
+	if reply := router.sendText(t, "/settings reset"); !strings.HasPrefix(reply.Content, "Settings reset.") {
+		t.Errorf("Expected the settings to be reset, got %q", reply.Content)
+	}
+	if _, ok := preferences.prefs[userID]; ok {
+		t.Error("Expected the preferences to be deleted")
+	}
+}
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+)
+
+// Metadata keys marking a replayed message and the responses to it, so that connectors and
+// middlewares can tell them from live traffic
+const (
+	MetadataReplay        = "replay"
+	MetadataReplayEventID = "replay_event_id"
+)
+
+// ReplayMessage routes the message of a stored connector.message event once more through the
+// connector that received it, e.g. after fixing a broken skill. The message and its responses
+// carry MetadataReplay and MetadataReplayEventID. The middlewares are skipped, so that the
+// deduplicator does not drop the message. Without deliver, the responses are collected but not
+// sent, so that the user does not get a second answer; a failure is not answered or dead-lettered.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - eventID: ID of the stored event
+//   - deliver: Whether to send the responses to the user
+//
+// Returns:
+//   - *ports.MessageReplay: Replayed message and the responses to it, progress updates excluded
+//   - error: repository.ErrNotFound for an unknown ID, ports.ErrReplayDisabled without an event store,
+//     ports.ErrNotReplayable for another event type, or ports.ErrReprocessFailed if the connector
+//     is not running or processing fails
+func (r *MessageRouter) ReplayMessage(ctx context.Context, eventID string, deliver bool) (*ports.MessageReplay, error) {
+	if r.eventBus == nil || !r.eventBus.HasStore() {
+		return nil, ports.ErrReplayDisabled
+	}
+
+	event, err := r.eventBus.StoredEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	received, ok := event.(*eventbus.ConnectorEvent)
+	if !ok || received.Type() != eventbus.EventConnectorMessage {
+		return nil, fmt.Errorf("%w: event %s is a %s event", ports.ErrNotReplayable, eventID, event.Type())
+	}
+
+	conn, exists := r.GetConnector(received.ConnectorName)
+	if !exists {
+		return nil, fmt.Errorf("%w: connector %s is not registered", ports.ErrReprocessFailed, received.ConnectorName)
+	}
+	if !conn.IsRunning() {
+		return nil, fmt.Errorf("%w: connector %s is not running", ports.ErrReprocessFailed, received.ConnectorName)
+	}
+	if r.orchestrator == nil {
+		return nil, fmt.Errorf("%w: orchestrator not available", ports.ErrReprocessFailed)
+	}
+
+	replay := &replayConnector{Connector: conn, eventID: eventID, deliver: deliver}
+	req := &Request{
+		Connector: received.ConnectorName,
+		Conn:      replay,
+		Message: &channels.Message{
+			UserID:    received.UserID,
+			ChannelID: received.ChannelID,
+			Content:   received.Message,
+			Metadata: map[string]interface{}{
+				MetadataReplay:        true,
+				MetadataReplayEventID: eventID,
+			},
+		},
+	}
+	if _, err := r.routeMessageRecovered(ctx, req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ports.ErrReprocessFailed, err)
+	}
+
+	r.routerMetrics.MessagesReplayed.Inc()
+	r.logger.Info("message replayed",
+		"event_id", eventID,
+		"connector", received.ConnectorName,
+		"user_id", received.UserID,
+		"delivered", deliver,
+	)
+
+	return &ports.MessageReplay{
+		EventID:   eventID,
+		Connector: received.ConnectorName,
+		UserID:    received.UserID,
+		Replies:   replay.collected(),
+		Delivered: deliver,
+	}, nil
+}
+
+// replayConnector marks the responses to a replayed message and collects them, sending them
+// through the connector only if they are delivered
+type replayConnector struct {
+	channels.Connector
+	eventID string
+	deliver bool
+
+	mu      sync.Mutex
+	replies []string
+}
+
+// SendResponse marks and collects a response, and sends it if the replay is delivered
+func (c *replayConnector) SendResponse(ctx context.Context, userID string, response *channels.Response) error {
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]interface{})
+	}
+	response.Metadata[MetadataReplay] = true
+	response.Metadata[MetadataReplayEventID] = c.eventID
+
+	if progress, _ := response.Metadata["progress"].(bool); !progress {
+		c.mu.Lock()
+		c.replies = append(c.replies, response.Content)
+		c.mu.Unlock()
+	}
+
+	if !c.deliver {
+		return nil
+	}
+	return c.Connector.SendResponse(ctx, userID, response)
+}
+
+// collected returns the responses collected so far
+func (c *replayConnector) collected() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.replies...)
+}
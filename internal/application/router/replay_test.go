@@ -0,0 +1,118 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockEventStore is a mock implementation of eventbus.EventStore keeping events by ID
+type mockEventStore struct {
+	events map[string]eventbus.Event
+}
+
+func (s *mockEventStore) Append(ctx context.Context, events []eventbus.Event) error {
+	return nil
+}
+
+func (s *mockEventStore) Replay(ctx context.Context, from, to time.Time, types []string, handler eventbus.EventHandler) error {
+	return nil
+}
+
+func (s *mockEventStore) Get(ctx context.Context, id string) (eventbus.Event, error) {
+	event, ok := s.events[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return event, nil
+}
+
+func newReplayTestRouter(t *testing.T) (*MessageRouter, *mockConnector, *mockOrchestrator) {
+	t.Helper()
+	store := &mockEventStore{events: map[string]eventbus.Event{
+		"event-1": eventbus.NewConnectorEvent(eventbus.EventConnectorMessage, "telegram", "user-123", "100", "Summarize my inbox", nil),
+		"event-2": eventbus.NewRouterEvent(eventbus.EventRouterMessage, "msg-1", "session-1", "user-123", "Done", "telegram", nil),
+		"event-3": eventbus.NewConnectorEvent(eventbus.EventConnectorMessage, "discord", "user-456", "200", "Hello", nil),
+	}}
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{BatchSize: 1, FlushInterval: time.Second, Logger: logging.NewNoopLogger(), Store: store})
+
+	orchestrator := newMockOrchestrator()
+	router := NewMessageRouter(newMockSessionRepository(), orchestrator, bus, logging.NewNoopLogger(), DefaultConfig())
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+	if err := conn.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start connector: %v", err)
+	}
+	return router, conn, orchestrator
+}
+
+func TestReplayMessage_WithoutDelivery(t *testing.T) {
+	router, conn, orchestrator := newReplayTestRouter(t)
+
+	replay, err := router.ReplayMessage(context.Background(), "event-1", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !orchestrator.called {
+		t.Error("Expected the message to be processed by the orchestrator")
+	}
+	if len(conn.sent) != 0 {
+		t.Errorf("Expected no response to be sent to the user, got %+v", conn.sent)
+	}
+	if replay.EventID != "event-1" || replay.Connector != "telegram" || replay.UserID != "user-123" || replay.Delivered {
+		t.Errorf("Unexpected replay: %+v", replay)
+	}
+	if len(replay.Replies) != 1 || replay.Replies[0] != "Response: Summarize my inbox" {
+		t.Errorf("Expected the reply to be returned, got %v", replay.Replies)
+	}
+	if count := router.Metrics().MessagesReplayed.Get(); count != 1 {
+		t.Errorf("Expected 1 replayed message, got %d", count)
+	}
+}
+
+func TestReplayMessage_Delivered(t *testing.T) {
+	router, conn, _ := newReplayTestRouter(t)
+
+	replay, err := router.ReplayMessage(context.Background(), "event-1", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !replay.Delivered || len(conn.sent) != 1 {
+		t.Fatalf("Expected the reply to be sent to the user, got %+v", conn.sent)
+	}
+	response := conn.sent[0]
+	if response.Content != "Response: Summarize my inbox" {
+		t.Errorf("Unexpected response content: %q", response.Content)
+	}
+	if response.Metadata[MetadataReplay] != true || response.Metadata[MetadataReplayEventID] != "event-1" {
+		t.Errorf("Expected the response to be marked as a replay, got %v", response.Metadata)
+	}
+}
+
+func TestReplayMessage_Errors(t *testing.T) {
+	router, _, _ := newReplayTestRouter(t)
+	ctx := context.Background()
+
+	if _, err := router.ReplayMessage(ctx, "missing", false); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := router.ReplayMessage(ctx, "event-2", false); !errors.Is(err, ports.ErrNotReplayable) {
+		t.Errorf("Expected ErrNotReplayable for a router event, got %v", err)
+	}
+	if _, err := router.ReplayMessage(ctx, "event-3", false); !errors.Is(err, ports.ErrReprocessFailed) {
+		t.Errorf("Expected ErrReprocessFailed for an unregistered connector, got %v", err)
+	}
+
+	withoutStore := NewMessageRouter(newMockSessionRepository(), newMockOrchestrator(), nil, logging.NewNoopLogger(), DefaultConfig())
+	if _, err := withoutStore.ReplayMessage(ctx, "event-1", false); !errors.Is(err, ports.ErrReplayDisabled) {
+		t.Errorf("Expected ErrReplayDisabled without an event store, got %v", err)
+	}
+}
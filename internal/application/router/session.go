@@ -0,0 +1,103 @@
+package router
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// Reasons for a session to expire, as logged by the router
+const (
+	sessionExpiredIdle     = "idle_timeout"
+	sessionExpiredAge      = "max_age"
+	sessionExpiredMessages = "max_messages"
+)
+
+// SetMessageRepository sets the repository used to count the messages of a session for
+// SessionPolicy.MaxMessages. Without it, sessions do not expire by message count.
+func (r *MessageRouter) SetMessageRepository(messages repository.MessageRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = messages
+}
+
+// messageRepository returns the message repository, or nil if none is set
+func (r *MessageRouter) messageRepository() repository.MessageRepository {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.messages
+}
+
+// currentSession returns the most recent session of a user in the workspace of the connector, or a
+// new session if the user has none or the most recent one has expired under the session policy
+// and is not handed off
+func (r *MessageRouter) currentSession(ctx context.Context, connectorName, userID string) (*entity.Session, error) {
+	opts := repository.QueryOptions{Limit: 1, Workspace: r.workspace(connectorName).String()}
+	sessions, err := r.sessionRepo.FindByUserID(ctx, userID, opts)
+	if err == nil && len(sessions) > 0 {
+		// Sessions are listed newest first
+		session := sessions[0]
+		reason := r.sessionExpiry(ctx, session)
+		// An operator who has taken over a session keeps it until the handoff ends
+		if reason == "" || r.sessionHandoff(ctx, session) != nil {
+			return session, nil
+		}
+
+		r.routerMetrics.SessionsExpired.Inc()
+		r.logger.Info("session expired",
+			"connector", connectorName,
+			"session_id", session.ID,
+			"user_id", userID,
+			"reason", reason,
+		)
+	}
+
+	return r.startSession(ctx, connectorName, userID)
+}
+
+// sessionExpiry returns why a session has expired under the session policy, or an empty
+// string if it can be continued
+func (r *MessageRouter) sessionExpiry(ctx context.Context, session *entity.Session) string {
+	policy := r.config.Session
+	now := utils.Now()
+
+	if policy.MaxAge > 0 && now.Sub(session.CreatedAt) >= policy.MaxAge {
+		return sessionExpiredAge
+	}
+	if policy.IdleTimeout > 0 && now.Sub(session.UpdatedAt) >= policy.IdleTimeout {
+		return sessionExpiredIdle
+	}
+
+	messages := r.messageRepository()
+	if policy.MaxMessages > 0 && messages != nil {
+		found, err := messages.FindBySessionID(ctx, session.ID.String(), repository.QueryOptions{Limit: policy.MaxMessages})
+		if err != nil {
+			// Rather continue the session than start a new one on a failed lookup
+			r.logger.Warn("failed to count session messages", "session_id", session.ID, "error", err)
+		} else if len(found) >= policy.MaxMessages {
+			return sessionExpiredMessages
+		}
+	}
+
+	return ""
+}
+
+// startSession creates a new session for a user in the workspace of the connector
+func (r *MessageRouter) startSession(ctx context.Context, connectorName, userID string) (*entity.Session, error) {
+	session := entity.NewSession(userID)
+	session.WorkspaceID = r.workspace(connectorName)
+	if err := r.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	r.logger.Info("session created",
+		"connector", connectorName,
+		"session_id", session.ID,
+		"user_id", userID,
+		"workspace", session.WorkspaceID,
+	)
+	return session, nil
+}
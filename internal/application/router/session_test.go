@@ -0,0 +1,194 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockMessageRepository is a mock implementation of repository.MessageRepository for testing
+type mockMessageRepository struct {
+	messages []*entity.Message
+	err      error
+}
+
+func (m *mockMessageRepository) Create(ctx context.Context, message *entity.Message) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func (m *mockMessageRepository) FindByID(ctx context.Context, id string) (*entity.Message, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (m *mockMessageRepository) FindBySessionID(ctx context.Context, sessionID string, opts repository.QueryOptions) ([]*entity.Message, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var messages []*entity.Message
+	for _, message := range m.messages {
+		if string(message.SessionID) == sessionID {
+			messages = append(messages, message)
+		}
+	}
+	if opts.Limit > 0 && len(messages) > opts.Limit {
+		messages = messages[:opts.Limit]
+	}
+	return messages, nil
+}
+
+func (m *mockMessageRepository) Search(ctx context.Context, search repository.MessageSearch, opts repository.QueryOptions) ([]*entity.Message, error) {
+	return nil, nil
+}
+
+func (m *mockMessageRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockMessageRepository) FindOlderThan(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]*entity.Message, error) {
+	return nil, nil
+}
+
+func (m *mockMessageRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockMessageRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockMessageRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	m.messages = slices.DeleteFunc(m.messages, func(message *entity.Message) bool {
+		return string(message.SessionID) == sessionID
+	})
+	return nil
+}
+
+func TestCurrentSession_Policy(t *testing.T) {
+	ctx := context.Background()
+	hour := time.Hour
+
+	tests := []struct {
+		name       string
+		policy     SessionPolicy
+		age        time.Duration
+		idle       time.Duration
+		messages   int
+		countErr   error
+		expectNew  bool
+		expectedBy string
+	}{
+		{"no policy", SessionPolicy{}, 100 * hour, 100 * hour, 100, nil, false, ""},
+		{"fresh session", SessionPolicy{IdleTimeout: hour, MaxAge: 24 * hour, MaxMessages: 10}, hour, 0, 4, nil, false, ""},
+		{"idle", SessionPolicy{IdleTimeout: hour}, 2 * hour, 2 * hour, 0, nil, true, sessionExpiredIdle},
+		{"too old", SessionPolicy{MaxAge: 24 * hour}, 25 * hour, 0, 0, nil, true, sessionExpiredAge},
+		{"too many messages", SessionPolicy{MaxMessages: 4}, 0, 0, 4, nil, true, sessionExpiredMessages},
+		{"count fails", SessionPolicy{MaxMessages: 4}, 0, 0, 4, errors.New("database is locked"), false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := newMockSessionRepository()
+			config := DefaultConfig()
+			config.Session = tt.policy
+			router := NewMessageRouter(sessionRepo, nil, nil, logging.NewNoopLogger(), config)
+
+			session := entity.NewSession("user-1")
+			session.CreatedAt = session.CreatedAt.Add(-tt.age)
+			session.UpdatedAt = session.UpdatedAt.Add(-tt.idle)
+			_ = sessionRepo.Create(ctx, session)
+
+			messageRepo := &mockMessageRepository{err: tt.countErr}
+			for i := 0; i < tt.messages; i++ {
+				_ = messageRepo.Create(ctx, entity.NewUserMessage(session.ID.String(), "Hello"))
+			}
+			router.SetMessageRepository(messageRepo)
+
+			if reason := router.sessionExpiry(ctx, session); reason != tt.expectedBy {
+				t.Errorf("Expected expiry reason '%s', got '%s'", tt.expectedBy, reason)
+			}
+
+			current, err := router.currentSession(ctx, "telegram", "user-1")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if isNew := current.ID != session.ID; isNew != tt.expectNew {
+				t.Errorf("Expected new session: %v, got session %s", tt.expectNew, current.ID)
+			}
+			if expired := router.Metrics().SessionsExpired.Get(); (expired == 1) != tt.expectNew {
+				t.Errorf("Expected %v expired sessions counted, got %d", tt.expectNew, expired)
+			}
+		})
+	}
+}
+
+func TestCurrentSession_MaxMessagesWithoutRepository(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := newMockSessionRepository()
+	config := DefaultConfig()
+	config.Session.MaxMessages = 1
+	router := NewMessageRouter(sessionRepo, nil, nil, logging.NewNoopLogger(), config)
+
+	first, err := router.currentSession(ctx, "telegram", "user-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Without a message repository, sessions do not expire by message count
+	current, _ := router.currentSession(ctx, "telegram", "user-1")
+	if current.ID != first.ID {
+		t.Errorf("Expected session %s to be continued, got %s", first.ID, current.ID)
+	}
+}
+
+func TestNewSessionCommand(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := newMockSessionRepository()
+	orchestrator := newMockOrchestrator()
+	router := NewMessageRouter(sessionRepo, orchestrator, nil, logging.NewNoopLogger(), DefaultConfig())
+	conn := newMockConnector("telegram")
+
+	request := func(content string) *Request {
+		return &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: content}}
+	}
+
+	// The orchestrator continues the session picked by the router
+	_ = router.handleMessage(ctx, request("Hello"))
+	if len(sessionRepo.sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(sessionRepo.sessions))
+	}
+	var first *entity.Session
+	for _, session := range sessionRepo.sessions {
+		first = session
+	}
+	if orchestrator.lastOptions.SessionID != first.ID.String() {
+		t.Errorf("Expected session %s to be passed to the orchestrator, got '%s'", first.ID, orchestrator.lastOptions.SessionID)
+	}
+	// Keep the new session distinguishable from the first one
+	first.CreatedAt = first.CreatedAt.Add(-time.Minute)
+
+	orchestrator.called = false
+	_ = router.handleMessage(ctx, request("/new@nexflow_bot"))
+	if orchestrator.called {
+		t.Error("Expected /new not to reach the orchestrator")
+	}
+	if len(sessionRepo.sessions) != 2 {
+		t.Fatalf("Expected a new session, got %d sessions", len(sessionRepo.sessions))
+	}
+	last := conn.sent[len(conn.sent)-1]
+	if last.Content != "Started a new session." || last.Metadata["session_id"] == first.ID.String() {
+		t.Errorf("Expected a confirmation with the new session, got %+v", last)
+	}
+
+	_ = router.handleMessage(ctx, request("Hello again"))
+	if orchestrator.lastOptions.SessionID != last.Metadata["session_id"] {
+		t.Errorf("Expected the new session to be continued, got '%s'", orchestrator.lastOptions.SessionID)
+	}
+}
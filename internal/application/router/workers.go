@@ -0,0 +1,141 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
+)
+
+// ErrDrainTimeout is returned by Stop when messages were still queued or being handled
+// when the drain timeout expired
+var ErrDrainTimeout = errors.New("router drain timeout expired")
+
+// panicRestartDelay is the pause before the messages of a connector are received again after
+// receiving them panicked
+var panicRestartDelay = time.Second
+
+// workerStopGrace bounds the wait for the workers to return after the drain timeout expired
+// and the context of the messages in flight was cancelled
+var workerStopGrace = 5 * time.Second
+
+// startWorkers starts the workers handling queued messages. The caller must hold r.mu.
+func (r *MessageRouter) startWorkers() {
+	for i := 0; i < r.config.Workers; i++ {
+		r.workers.Add(1)
+		go r.runWorker()
+	}
+	r.logger.Info("router workers started", "workers", r.config.Workers, "queue_size", r.config.QueueSize)
+}
+
+// runWorker handles queued messages one at a time. Once the router stops accepting messages,
+// it handles the messages still queued and exits.
+func (r *MessageRouter) runWorker() {
+	defer r.workers.Done()
+
+	for {
+		select {
+		case req := <-r.queue:
+			r.runRequest(req)
+		case <-r.ctx.Done():
+			for {
+				select {
+				case req := <-r.queue:
+					r.runRequest(req)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// enqueue hands a message to the workers, waiting while the queue is full so that the
+// connector buffers further messages. It reports false if ctx was cancelled meanwhile.
+func (r *MessageRouter) enqueue(ctx context.Context, req *Request) bool {
+	select {
+	case r.queue <- req:
+		r.routerMetrics.MessagesQueued.Add(1)
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// runRequest passes a queued message through the middlewares with metrics. A panic of a
+// middleware fails the message. Messages still queued when the drain timeout expires are skipped.
+func (r *MessageRouter) runRequest(req *Request) {
+	r.routerMetrics.MessagesQueued.Add(-1)
+	defer req.span.End()
+	if r.workCtx.Err() != nil {
+		req.span.RecordError(r.workCtx.Err())
+		return
+	}
+
+	r.routerMetrics.MessagesInFlight.Add(1)
+	defer r.routerMetrics.MessagesInFlight.Add(-1)
+
+	ctx := tracing.ContextWithSpan(r.workCtx, req.span)
+	handler := r.messageHandler()
+	recoverer := r.crashRecoverer()
+	err := metrics.RecordDurationWithError(r.routerMetrics.MessageProcessingDuration, func() (err error) {
+		defer recoverer.Recover(ctx, "router", "handle_message", &err, "connector", req.Connector)
+		return handler(ctx, req)
+	})
+
+	// Record processing metrics
+	if err != nil {
+		req.span.RecordError(err)
+		r.routerMetrics.MessagesFailed.Inc()
+		r.logger.ErrorContext(ctx, "message processing failed",
+			"connector", req.Connector,
+			"user_id", req.Message.UserID,
+			"error", err,
+		)
+	} else {
+		r.routerMetrics.MessagesProcessed.Inc()
+	}
+}
+
+// drain waits for the workers to handle the queued messages and those in flight. When the
+// drain timeout expires first, the messages left are abandoned: queued ones are skipped and
+// the context of those in flight is cancelled, and drain waits up to workerStopGrace for the
+// workers to return, so that no message is handled after the connectors are stopped.
+//
+// Returns:
+//   - int: Number of abandoned messages
+func (r *MessageRouter) drain() int {
+	defer r.workCancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.workers.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(r.config.DrainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return 0
+	case <-timer.C:
+	}
+
+	abandoned := len(r.queue) + int(r.routerMetrics.MessagesInFlight.Get())
+	r.routerMetrics.MessagesAbandoned.Add(int64(abandoned))
+	r.logger.Warn("router drain timeout expired, abandoning messages",
+		"timeout", r.config.DrainTimeout,
+		"abandoned", abandoned,
+	)
+
+	r.workCancel()
+	select {
+	case <-done:
+	case <-time.After(workerStopGrace):
+		r.logger.Warn("router workers still running after cancellation", "grace_period", workerStopGrace)
+	}
+	return abandoned
+}
@@ -0,0 +1,218 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// waitForStats polls the router statistics until cond holds
+func waitForStats(t *testing.T, router *MessageRouter, what string, cond func(ports.RouterStats) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond(router.Stats()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s, stats: %+v", what, router.Stats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// gate holds up every message until it is opened and records the highest number of
+// messages held at the same time
+type gate struct {
+	open chan struct{}
+
+	mu      sync.Mutex
+	active  int
+	maxSeen int
+}
+
+func newGate() *gate {
+	return &gate{open: make(chan struct{})}
+}
+
+func (g *gate) middleware(next Handler) Handler {
+	return func(ctx context.Context, req *Request) error {
+		g.mu.Lock()
+		g.active++
+		g.maxSeen = max(g.maxSeen, g.active)
+		g.mu.Unlock()
+
+		defer func() {
+			g.mu.Lock()
+			g.active--
+			g.mu.Unlock()
+		}()
+
+		select {
+		case <-g.open:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func newPoolTestRouter(t *testing.T, workers, queueSize int, drainTimeout time.Duration) (*MessageRouter, *mockConnector) {
+	t.Helper()
+	config := DefaultConfig()
+	config.Workers = workers
+	config.QueueSize = queueSize
+	config.DrainTimeout = drainTimeout
+
+	router := NewMessageRouter(newMockSessionRepository(), nil, eventbus.NewEventBus(nil), logging.NewNoopLogger(), config)
+	conn := newMockConnector("telegram")
+	router.RegisterConnector(conn)
+	return router, conn
+}
+
+func TestWorkers_BoundConcurrency(t *testing.T) {
+	router, conn := newPoolTestRouter(t, 2, 1, time.Second)
+	g := newGate()
+	router.Use(g.middleware)
+
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		conn.SendMessage("user-123", "hello")
+	}
+
+	// Two messages are handled, one is queued and the others wait in the connector
+	waitForStats(t, router, "full worker pool", func(s ports.RouterStats) bool {
+		return s.InFlight == 2 && s.Queued == 1
+	})
+	time.Sleep(20 * time.Millisecond)
+	if stats := router.Stats(); stats.QueueDepth != 1 || stats.MessagesReceived != 4 {
+		t.Errorf("Expected one message to wait in the connector, got %+v", stats)
+	}
+
+	close(g.open)
+	waitForStats(t, router, "all messages", func(s ports.RouterStats) bool {
+		return s.MessagesProcessed == 5
+	})
+	if g.maxSeen != 2 {
+		t.Errorf("Expected at most 2 messages handled at the same time, got %d", g.maxSeen)
+	}
+
+	if err := router.Stop(); err != nil {
+		t.Fatalf("Failed to stop router: %v", err)
+	}
+}
+
+func TestStop_DrainsQueuedMessages(t *testing.T) {
+	router, conn := newPoolTestRouter(t, 1, 10, 5*time.Second)
+	g := newGate()
+	router.Use(g.middleware)
+
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		conn.SendMessage("user-123", "hello")
+	}
+	waitForStats(t, router, "queued messages", func(s ports.RouterStats) bool {
+		return s.InFlight == 1 && s.Queued == 2
+	})
+
+	time.AfterFunc(50*time.Millisecond, func() { close(g.open) })
+	if err := router.Stop(); err != nil {
+		t.Fatalf("Failed to stop router: %v", err)
+	}
+
+	stats := router.Stats()
+	if stats.MessagesProcessed != 3 || stats.Abandoned != 0 {
+		t.Errorf("Expected the queued messages to be handled before stopping, got %+v", stats)
+	}
+	if conn.IsRunning() {
+		t.Error("Expected connector to be stopped after draining")
+	}
+}
+
+func TestStop_DrainTimeout(t *testing.T) {
+	router, conn := newPoolTestRouter(t, 1, 10, 50*time.Millisecond)
+	g := newGate()
+	router.Use(g.middleware)
+
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		conn.SendMessage("user-123", "hello")
+	}
+	waitForStats(t, router, "queued messages", func(s ports.RouterStats) bool {
+		return s.InFlight == 1 && s.Queued == 2
+	})
+
+	err := router.Stop()
+	if !errors.Is(err, ErrDrainTimeout) {
+		t.Fatalf("Expected ErrDrainTimeout, got %v", err)
+	}
+	if abandoned := router.Stats().Abandoned; abandoned != 3 {
+		t.Errorf("Expected 3 abandoned messages, got %d", abandoned)
+	}
+
+	// The message in flight sees its context cancelled and the queued ones are skipped before
+	// Stop returns
+	if stats := router.Stats(); stats.InFlight != 0 || stats.Queued != 0 {
+		t.Errorf("Expected the workers to have returned, got %+v", stats)
+	}
+	if stats := router.Stats(); stats.MessagesProcessed != 0 || stats.MessagesFailed != 1 {
+		t.Errorf("Expected only the cancelled message to fail, got %+v", stats)
+	}
+}
+
+func TestStop_DrainTimeoutBoundsWorkerStop(t *testing.T) {
+	grace := workerStopGrace
+	workerStopGrace = 50 * time.Millisecond
+	defer func() { workerStopGrace = grace }()
+
+	router, conn := newPoolTestRouter(t, 1, 10, 50*time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	// The handler ignores the cancellation of its context
+	router.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) error {
+			<-release
+			return nil
+		}
+	})
+
+	if err := router.Start(); err != nil {
+		t.Fatalf("Failed to start router: %v", err)
+	}
+	conn.SendMessage("user-123", "hello")
+	waitForStats(t, router, "message in flight", func(s ports.RouterStats) bool { return s.InFlight == 1 })
+
+	start := time.Now()
+	if err := router.Stop(); !errors.Is(err, ErrDrainTimeout) {
+		t.Fatalf("Expected ErrDrainTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Stop to give up on the worker after the grace period, took %v", elapsed)
+	}
+}
+
+func TestConfig_PoolDefaults(t *testing.T) {
+	router := NewMessageRouter(newMockSessionRepository(), nil, nil, logging.NewNoopLogger(), &Config{MaxMessageLength: 100})
+	defaults := DefaultConfig()
+	if router.config.Workers != defaults.Workers || router.config.QueueSize != defaults.QueueSize || router.config.DrainTimeout != defaults.DrainTimeout {
+		t.Errorf("Expected default worker pool settings, got %+v", router.config)
+	}
+
+	invalid := DefaultConfig()
+	invalid.Workers = -1
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected error for negative Workers")
+	}
+}
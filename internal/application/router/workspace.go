@@ -0,0 +1,51 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// defaultMaxTokens is the LLM token budget of a reply in workspaces that do not set one
+const defaultMaxTokens = 1000
+
+// SetWorkspaceRepository sets the repository of the workspaces whose token budgets limit the
+// replies in their sessions. Without it, all replies use the default budget.
+func (r *MessageRouter) SetWorkspaceRepository(workspaces repository.WorkspaceRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.workspaces = workspaces
+}
+
+// workspace returns the workspace a connector is mapped to in Config.Workspaces
+func (r *MessageRouter) workspace(connectorName string) valueobject.WorkspaceID {
+	if workspace := r.config.Workspaces[connectorName]; workspace != "" {
+		return valueobject.WorkspaceID(workspace)
+	}
+	return valueobject.DefaultWorkspaceID
+}
+
+// maxTokens returns the LLM token budget of a reply in a workspace
+func (r *MessageRouter) maxTokens(ctx context.Context, workspaceID valueobject.WorkspaceID) int {
+	r.mu.RLock()
+	workspaces := r.workspaces
+	r.mu.RUnlock()
+	if workspaces == nil || workspaceID.IsEmpty() {
+		return defaultMaxTokens
+	}
+
+	workspace, err := workspaces.FindByID(ctx, workspaceID.String())
+	if err != nil {
+		// Rather answer with the default budget than not at all on a failed lookup
+		if !errors.Is(err, repository.ErrNotFound) {
+			r.logger.Warn("failed to read workspace", "workspace", workspaceID, "error", err)
+		}
+		return defaultMaxTokens
+	}
+	if workspace.MaxTokens <= 0 {
+		return defaultMaxTokens
+	}
+	return workspace.MaxTokens
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockWorkspaceRepository is a mock implementation of repository.WorkspaceRepository for testing
+type mockWorkspaceRepository struct {
+	workspaces map[string]*entity.Workspace
+}
+
+func (m *mockWorkspaceRepository) Create(ctx context.Context, workspace *entity.Workspace) error {
+	m.workspaces[workspace.ID.String()] = workspace
+	return nil
+}
+
+func (m *mockWorkspaceRepository) FindByID(ctx context.Context, id string) (*entity.Workspace, error) {
+	workspace, ok := m.workspaces[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return workspace, nil
+}
+
+func (m *mockWorkspaceRepository) List(ctx context.Context) ([]*entity.Workspace, error) {
+	return nil, nil
+}
+
+func (m *mockWorkspaceRepository) Update(ctx context.Context, workspace *entity.Workspace) error {
+	return nil
+}
+
+func (m *mockWorkspaceRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestWorkspaces_ScopeSessions(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := newMockSessionRepository()
+	orchestrator := newMockOrchestrator()
+	config := DefaultConfig()
+	config.Workspaces = map[string]string{"telegram-support": "support"}
+	router := NewMessageRouter(sessionRepo, orchestrator, nil, logging.NewNoopLogger(), config)
+	support := entity.NewWorkspace("support", "Support")
+	support.MaxTokens = 300
+	router.SetWorkspaceRepository(&mockWorkspaceRepository{workspaces: map[string]*entity.Workspace{"support": support}})
+
+	// Both bots see the same Telegram user
+	conn := newMockConnector("telegram")
+	supportConn := newMockConnector("telegram-support")
+	supportConn.users = conn.users
+
+	_ = router.handleMessage(ctx, &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Hello"}})
+	defaultSessionID := orchestrator.lastOptions.SessionID
+	if defaultSessionID == "" {
+		t.Fatal("Expected a session for the message")
+	}
+	if orchestrator.lastOptions.MaxTokens != defaultMaxTokens {
+		t.Errorf("Expected the default token budget, got %d", orchestrator.lastOptions.MaxTokens)
+	}
+	if workspace := sessionRepo.sessions[defaultSessionID].WorkspaceID; workspace != "default" {
+		t.Errorf("Expected the session in the default workspace, got '%s'", workspace)
+	}
+
+	_ = router.handleMessage(ctx, &Request{Connector: "telegram-support", Conn: supportConn, Message: &channels.Message{UserID: "user-123", Content: "Hello"}})
+	supportSessionID := orchestrator.lastOptions.SessionID
+	if supportSessionID == defaultSessionID {
+		t.Fatal("Expected the support bot to start its own session")
+	}
+	if workspace := sessionRepo.sessions[supportSessionID].WorkspaceID; workspace != "support" {
+		t.Errorf("Expected the session in the support workspace, got '%s'", workspace)
+	}
+	if orchestrator.lastOptions.MaxTokens != 300 {
+		t.Errorf("Expected the token budget of the workspace, got %d", orchestrator.lastOptions.MaxTokens)
+	}
+	if orchestrator.lastConnector != "telegram-support" {
+		t.Errorf("Expected the connector of the message in the context, got '%s'", orchestrator.lastConnector)
+	}
+
+	// Each bot continues the session of its workspace
+	_ = router.handleMessage(ctx, &Request{Connector: "telegram", Conn: conn, Message: &channels.Message{UserID: "user-123", Content: "Hello again"}})
+	if orchestrator.lastOptions.SessionID != defaultSessionID {
+		t.Errorf("Expected session %s to be continued, got '%s'", defaultSessionID, orchestrator.lastOptions.SessionID)
+	}
+}
+
+func TestWorkspaces_ScopeSkills(t *testing.T) {
+	config := DefaultConfig()
+	config.Workspaces = map[string]string{"telegram": "support"}
+	router := newCommandTestRouter(config)
+
+	shared := entity.NewSkill("weather", "1.0.0", "/skills/weather", nil, map[string]interface{}{"command": "weather"})
+	supportOnly := entity.NewSkill("tickets", "1.0.0", "/skills/tickets", nil, map[string]interface{}{"command": "tickets"})
+	supportOnly.WorkspaceID = "support"
+	salesOnly := entity.NewSkill("leads", "1.0.0", "/skills/leads", nil, map[string]interface{}{"command": "leads"})
+	salesOnly.WorkspaceID = "sales"
+	router.SetSkillRepository(&mockSkillRepository{skills: []*entity.Skill{shared, supportOnly, salesOnly}})
+
+	reply := router.sendText(t, "/skills")
+	if reply.Content != "Available skills:\nweather\ntickets" {
+		t.Errorf("Expected the skills of the workspace, got:\n%s", reply.Content)
+	}
+
+	reply = router.sendText(t, "/help")
+	if !strings.Contains(reply.Content, "/tickets") || strings.Contains(reply.Content, "/leads") {
+		t.Errorf("Expected help to list the skill commands of the workspace, got:\n%s", reply.Content)
+	}
+
+	// Skill commands of other workspaces are passed to the orchestrator like any message
+	_ = router.sendText(t, "/leads")
+	if !router.orchestrator.called {
+		t.Error("Expected a skill command of another workspace not to be run")
+	}
+}
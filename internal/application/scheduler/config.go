@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidationError represents a scheduler configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Config holds configuration for the Scheduler
+type Config struct {
+	// MaxConcurrent is the maximum number of schedules executed at the same time
+	MaxConcurrent int
+
+	// ExecutionTimeout limits how long a single scheduled skill execution may run
+	ExecutionTimeout time.Duration
+}
+
+// DefaultConfig returns the default configuration for the Scheduler
+func DefaultConfig() *Config {
+	return &Config{
+		MaxConcurrent:    4,
+		ExecutionTimeout: 5 * time.Minute,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.MaxConcurrent <= 0 {
+		return &ValidationError{Field: "MaxConcurrent", Message: "must be positive"}
+	}
+
+	if c.ExecutionTimeout <= 0 {
+		return &ValidationError{Field: "ExecutionTimeout", Message: "must be positive"}
+	}
+
+	return nil
+}
@@ -0,0 +1,1111 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// SystemUserChannelID is the channel-specific ID of the system user
+// that owns sessions used for scheduled executions
+const SystemUserChannelID = "scheduler"
+
+// idleWait is how long the loop sleeps when there is nothing scheduled
+const idleWait = time.Hour
+
+// maxCatchUpRuns limits how many missed activations of a schedule are run on startup
+const maxCatchUpRuns = 100
+
+// runJobKind is the kind of the jobs of the job queue running schedules
+const runJobKind = "schedule_run"
+
+// promptSessionTitleLength is the length in characters of the prompt the title of a prompt
+// schedule's session is made of
+const promptSessionTitleLength = 60
+
+// SchedulerMetrics holds all metrics for the Scheduler
+type SchedulerMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	SchedulesLoaded   *metrics.Counter
+	RunsTriggered     *metrics.Counter
+	RunsSucceeded     *metrics.Counter
+	RunsFailed        *metrics.Counter
+	RunsSkipped       *metrics.Counter
+	RunsMissed        *metrics.Counter
+	DeliveriesSent    *metrics.Counter
+	DeliveriesFailed  *metrics.Counter
+	JobsSucceeded     *metrics.Counter
+	JobsFailed        *metrics.Counter
+	ExecutionDuration *metrics.Histogram
+}
+
+// NewSchedulerMetrics creates a new SchedulerMetrics instance
+func NewSchedulerMetrics() *SchedulerMetrics {
+	registry := metrics.NewMetricsRegistry()
+	buckets := metrics.DefaultBuckets()
+
+	return &SchedulerMetrics{
+		registry: registry,
+
+		SchedulesLoaded:   registry.GetCounter("scheduler_schedules_loaded"),
+		RunsTriggered:     registry.GetCounter("scheduler_runs_triggered_total"),
+		RunsSucceeded:     registry.GetCounter("scheduler_runs_succeeded_total"),
+		RunsFailed:        registry.GetCounter("scheduler_runs_failed_total"),
+		RunsSkipped:       registry.GetCounter("scheduler_runs_skipped_total"),
+		RunsMissed:        registry.GetCounter("scheduler_runs_missed_total"),
+		DeliveriesSent:    registry.GetCounter("scheduler_deliveries_sent_total"),
+		DeliveriesFailed:  registry.GetCounter("scheduler_deliveries_failed_total"),
+		JobsSucceeded:     registry.GetCounter("scheduler_jobs_succeeded_total"),
+		JobsFailed:        registry.GetCounter("scheduler_jobs_failed_total"),
+		ExecutionDuration: registry.GetHistogram("scheduler_execution_duration_seconds", buckets),
+	}
+}
+
+// Registry returns the registry holding the Scheduler metrics
+func (m *SchedulerMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// entry is a loaded schedule together with its parsed cron spec.
+// One-shot entries have no spec and fire once at the schedule's run time.
+type entry struct {
+	schedule *entity.Schedule
+	spec     *valueobject.CronSchedule
+	location *time.Location
+	// base is the next activation time according to the cron expression,
+	// next is base plus the random jitter and is when the run actually starts
+	base time.Time
+	next time.Time
+}
+
+// runJobPayload is the payload of a job of the job queue running activations of a schedule
+type runJobPayload struct {
+	ScheduleID   string      `json:"schedule_id"`
+	ScheduledAts []time.Time `json:"scheduled_ats"`
+}
+
+// JobFunc is the function a system job runs
+type JobFunc func(ctx context.Context) error
+
+// job is a system job registered in code rather than stored as a schedule,
+// such as automatic backups. Jobs are evaluated in UTC and have no run history.
+type job struct {
+	name string
+	spec *valueobject.CronSchedule
+	run  JobFunc
+	next time.Time
+}
+
+// Scheduler fires enabled schedules according to their cron expressions,
+// or once at their run time for one-shot schedules, which are deleted after firing.
+// Each run executes the schedule's skill through the Orchestrator, which
+// records the resulting task in a session owned by the system user, or sends
+// the schedule's rendered prompt to the LLM in a session of its own, owned by
+// the system user too, and is stored in the schedule's run history. On startup, activations missed while
+// the server was down are handled according to each schedule's missed run policy.
+// The output of successful runs of schedules with a target is delivered to the
+// target user through the message sender.
+type Scheduler struct {
+	scheduleRepo repository.ScheduleRepository
+	runRepo      repository.ScheduleRunRepository
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	orchestrator ports.Orchestrator
+	eventBus     *eventbus.EventBus
+	logger       logging.Logger
+	config       *Config
+	metrics      *SchedulerMetrics
+	sender       ports.MessageSender
+	jobQueue     ports.JobQueue
+	leadership   ports.Leadership
+
+	entries   map[string]*entry
+	jobs      map[string]*job
+	running   map[string]bool
+	sessionID string
+	sem       chan struct{}
+	reloadCh  chan struct{}
+	now       func() time.Time
+	jitter    func(max time.Duration) time.Duration
+
+	mu      sync.Mutex
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// Compile-time check that Scheduler implements ports.Scheduler
+var _ ports.Scheduler = (*Scheduler)(nil)
+
+// NewScheduler creates a new Scheduler instance
+//
+// Parameters:
+//   - scheduleRepo: ScheduleRepository for loading schedules
+//   - runRepo: ScheduleRunRepository for the run history
+//   - userRepo: UserRepository for the system user
+//   - sessionRepo: SessionRepository for the system session
+//   - orchestrator: Orchestrator for executing skills
+//   - eventBus: EventBus for publishing events (optional)
+//   - logger: Structured logger for logging
+//   - config: Scheduler configuration (uses defaults if nil)
+//
+// Returns:
+//   - *Scheduler: Initialized scheduler
+func NewScheduler(
+	scheduleRepo repository.ScheduleRepository,
+	runRepo repository.ScheduleRunRepository,
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	orchestrator ports.Orchestrator,
+	eventBus *eventbus.EventBus,
+	logger logging.Logger,
+	config *Config,
+) *Scheduler {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	if err := config.Validate(); err != nil {
+		logger.Error("invalid scheduler configuration, using defaults", "error", err)
+		config = DefaultConfig()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		scheduleRepo: scheduleRepo,
+		runRepo:      runRepo,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		orchestrator: orchestrator,
+		eventBus:     eventBus,
+		logger:       logger,
+		config:       config,
+		metrics:      NewSchedulerMetrics(),
+		entries:      make(map[string]*entry),
+		jobs:         make(map[string]*job),
+		running:      make(map[string]bool),
+		sem:          make(chan struct{}, config.MaxConcurrent),
+		reloadCh:     make(chan struct{}, 1),
+		now:          time.Now,
+		jitter:       randomJitter,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Metrics returns the scheduler metrics
+func (s *Scheduler) Metrics() *SchedulerMetrics {
+	return s.metrics
+}
+
+// SetMessageSender sets the sender used to deliver run output to schedule targets.
+// Without a sender, schedule targets are ignored.
+func (s *Scheduler) SetMessageSender(sender ports.MessageSender) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sender = sender
+}
+
+// SetJobQueue sets the persistent job queue the schedule runs are queued to instead of being
+// run in the background, so that runs that were due when the server stopped still happen after
+// a restart. It must be called before Start.
+func (s *Scheduler) SetJobQueue(queue ports.JobQueue) {
+	s.jobQueue = queue
+	queue.Register(runJobKind, s.handleRunJob)
+}
+
+// SetLeadership makes the scheduler run schedules and system jobs only while this instance is
+// the leader, so that instances sharing the database do not run them twice. The other
+// instances keep track of the activations without running them. It must be called before Start.
+func (s *Scheduler) SetLeadership(leadership ports.Leadership) {
+	s.leadership = leadership
+}
+
+// isLeader returns true if this instance runs the schedules
+func (s *Scheduler) isLeader() bool {
+	return s.leadership == nil || s.leadership.IsLeader()
+}
+
+// AddJob registers a system job that runs on a cron expression evaluated in UTC.
+// Jobs share the concurrency limit with schedules but not the execution timeout,
+// and a job is skipped while its previous run is still in progress.
+//
+// Parameters:
+//   - name: Unique job name
+//   - cronExpr: Cron expression with 5 or 6 fields or an alias such as "@daily"
+//   - run: Function executed on each activation
+//
+// Returns:
+//   - error: Error if the name is taken or the expression is malformed
+func (s *Scheduler) AddJob(name, cronExpr string, run JobFunc) error {
+	spec, err := valueobject.ParseCronSchedule(cronExpr)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression for job %s: %w", name, err)
+	}
+
+	next := spec.Next(s.now().UTC())
+	if next.IsZero() {
+		return fmt.Errorf("job %s never fires", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %s already registered", name)
+	}
+	s.jobs[name] = &job{name: name, spec: spec, run: run, next: next}
+
+	// Wake the loop so it accounts for the new job
+	select {
+	case s.reloadCh <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Start loads enabled schedules, handles runs missed while the server
+// was down and starts the scheduling loop
+//
+// Returns:
+//   - error: Error if schedules could not be loaded
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return fmt.Errorf("scheduler already started")
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	s.logger.Info("starting scheduler")
+
+	if err := s.load(s.ctx); err != nil {
+		return fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	if s.isLeader() {
+		s.catchUp(s.ctx, s.now())
+	}
+
+	s.wg.Add(1)
+	go s.loop()
+
+	s.logger.Info("scheduler started", "schedules", s.entryCount())
+
+	return nil
+}
+
+// Stop stops the scheduling loop and waits for running executions to finish
+func (s *Scheduler) Stop() error {
+	s.logger.Info("stopping scheduler")
+
+	s.cancel()
+	s.wg.Wait()
+
+	s.logger.Info("scheduler stopped")
+
+	return nil
+}
+
+// Promote handles the activations missed while no instance was the leader, like Start does
+// for those missed while the server was down, and reloads the schedules so that one-shot
+// schedules that became due meanwhile fire. It is called when this instance becomes the leader.
+func (s *Scheduler) Promote() {
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if !started || s.ctx.Err() != nil {
+		return
+	}
+
+	s.logger.Info("running schedules as the leader")
+	s.catchUp(s.ctx, s.now())
+	s.Reload()
+}
+
+// Reload requests the scheduler to reload schedules from the repository
+func (s *Scheduler) Reload() {
+	select {
+	case s.reloadCh <- struct{}{}:
+	default:
+		// A reload is already pending
+	}
+}
+
+// RunNow starts an immediate run of a schedule in the background, even if it is disabled.
+// The run is recorded in the run history like any scheduled run; one-shot schedules
+// are deleted afterwards.
+func (s *Scheduler) RunNow(ctx context.Context, scheduleID string) error {
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+
+	if !started || s.ctx.Err() != nil {
+		return fmt.Errorf("scheduler is not running")
+	}
+
+	schedule, err := s.scheduleRepo.FindByID(ctx, scheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to find schedule: %w", err)
+	}
+
+	s.mu.Lock()
+	if s.running[scheduleID] {
+		s.mu.Unlock()
+		s.metrics.RunsSkipped.Inc()
+		return fmt.Errorf("schedule %s is already running", scheduleID)
+	}
+	s.running[scheduleID] = true
+	s.mu.Unlock()
+
+	s.logger.Info("schedule run requested", "schedule_id", scheduleID, "skill", schedule.Skill)
+	s.dispatch(schedule, []time.Time{s.now()})
+
+	return nil
+}
+
+// RunOnce runs a schedule immediately and waits for the run to finish, even if the schedule
+// is disabled or the scheduler is not started, e.g. from the command line. The run is
+// recorded in the run history like any scheduled run; one-shot schedules are deleted afterwards.
+func (s *Scheduler) RunOnce(ctx context.Context, scheduleID string) error {
+	schedule, err := s.scheduleRepo.FindByID(ctx, scheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to find schedule: %w", err)
+	}
+
+	s.mu.Lock()
+	if s.running[scheduleID] {
+		s.mu.Unlock()
+		s.metrics.RunsSkipped.Inc()
+		return fmt.Errorf("schedule %s is already running", scheduleID)
+	}
+	s.running[scheduleID] = true
+	s.mu.Unlock()
+	defer s.markDone(scheduleID)
+
+	s.logger.Info("schedule run requested", "schedule_id", scheduleID, "skill", schedule.Skill)
+	err = s.execute(ctx, schedule, s.now())
+
+	if schedule.IsOneShot() {
+		s.removeOneShot(ctx, schedule)
+	}
+	return err
+}
+
+// loop waits for the next due schedule or a reload request
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	for {
+		timer := time.NewTimer(s.untilNext())
+
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+
+		case <-s.reloadCh:
+			timer.Stop()
+			if err := s.load(s.ctx); err != nil {
+				s.logger.Error("failed to reload schedules", "error", err)
+			}
+
+		case <-timer.C:
+			s.runDue(s.now())
+		}
+	}
+}
+
+// load replaces the in-memory entries with the enabled schedules from storage
+func (s *Scheduler) load(ctx context.Context) error {
+	schedules, err := s.scheduleRepo.FindEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	entries := make(map[string]*entry, len(schedules))
+
+	s.mu.Lock()
+	running := make(map[string]bool, len(s.running))
+	for id := range s.running {
+		running[id] = true
+	}
+	s.mu.Unlock()
+
+	for _, schedule := range schedules {
+		if schedule.IsOneShot() {
+			if running[string(schedule.ID)] {
+				// Already fired and about to be deleted
+				continue
+			}
+			e, ok := s.oneShotEntry(schedule)
+			if !ok {
+				continue
+			}
+			entries[string(schedule.ID)] = e
+			continue
+		}
+
+		spec, err := schedule.CronExpression.Parse()
+		if err != nil {
+			s.logger.Error("skipping schedule with invalid cron expression",
+				"schedule_id", schedule.ID,
+				"cron", schedule.CronExpression.String(),
+				"error", err,
+			)
+			continue
+		}
+
+		e := &entry{
+			schedule: schedule,
+			spec:     spec,
+			location: schedule.Location(),
+		}
+		if !s.advance(e, now) {
+			s.logger.Warn("schedule never fires, skipping", "schedule_id", schedule.ID, "cron", schedule.CronExpression.String())
+			continue
+		}
+
+		entries[string(schedule.ID)] = e
+	}
+
+	s.mu.Lock()
+	s.entries = entries
+	s.mu.Unlock()
+
+	s.metrics.SchedulesLoaded.Reset()
+	s.metrics.SchedulesLoaded.Add(int64(len(entries)))
+
+	s.logger.Debug("schedules loaded", "count", len(entries))
+
+	return nil
+}
+
+// untilNext returns the duration until the earliest due entry
+func (s *Scheduler) untilNext() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var earliest time.Time
+	for _, e := range s.entries {
+		if earliest.IsZero() || e.next.Before(earliest) {
+			earliest = e.next
+		}
+	}
+	for _, j := range s.jobs {
+		if earliest.IsZero() || j.next.Before(earliest) {
+			earliest = j.next
+		}
+	}
+
+	if earliest.IsZero() {
+		return idleWait
+	}
+
+	d := earliest.Sub(s.now())
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// entryCount returns the number of loaded entries
+func (s *Scheduler) entryCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// runDue starts executions for all entries due at the given time
+// and advances their next activation time. Instances other than the leader only advance them.
+func (s *Scheduler) runDue(now time.Time) {
+	type dueRun struct {
+		schedule    *entity.Schedule
+		scheduledAt time.Time
+	}
+
+	leader := s.isLeader()
+
+	s.mu.Lock()
+	due := make([]dueRun, 0)
+	for id, e := range s.entries {
+		if e.next.After(now) {
+			continue
+		}
+		scheduledAt := e.base
+		if e.spec == nil || !s.advance(e, now) {
+			// One-shot entries fire once; cron entries without further
+			// activations are no longer tracked either
+			delete(s.entries, id)
+		}
+		if !leader {
+			continue
+		}
+
+		if s.running[id] {
+			s.metrics.RunsSkipped.Inc()
+			s.logger.Warn("previous run still in progress, skipping", "schedule_id", id)
+			continue
+		}
+		s.running[id] = true
+		due = append(due, dueRun{schedule: e.schedule, scheduledAt: scheduledAt})
+	}
+
+	dueJobs := make([]*job, 0)
+	for _, j := range s.jobs {
+		if j.next.After(now) {
+			continue
+		}
+		j.next = j.spec.Next(now.UTC())
+		if !leader {
+			continue
+		}
+
+		id := jobRunningID(j.name)
+		if s.running[id] {
+			s.metrics.RunsSkipped.Inc()
+			s.logger.Warn("previous job run still in progress, skipping", "job", j.name)
+			continue
+		}
+		s.running[id] = true
+		dueJobs = append(dueJobs, j)
+	}
+	s.mu.Unlock()
+
+	for _, run := range due {
+		s.dispatch(run.schedule, []time.Time{run.scheduledAt})
+	}
+	for _, j := range dueJobs {
+		s.dispatchJob(j)
+	}
+}
+
+// jobRunningID is the key of a job in the running set, distinct from schedule IDs
+func jobRunningID(name string) string {
+	return "job:" + name
+}
+
+// dispatchJob runs a system job in the background.
+// The caller must have marked the job as running.
+func (s *Scheduler) dispatchJob(j *job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.markDone(jobRunningID(j.name))
+
+		select {
+		case s.sem <- struct{}{}:
+		case <-s.ctx.Done():
+			return
+		}
+		defer func() { <-s.sem }()
+
+		s.logger.Info("running scheduled job", "job", j.name)
+		if err := j.run(s.ctx); err != nil {
+			s.metrics.JobsFailed.Inc()
+			s.logger.Error("scheduled job failed", "job", j.name, "error", err)
+			return
+		}
+		s.metrics.JobsSucceeded.Inc()
+	}()
+}
+
+// dispatch executes the given activations of a schedule one after another
+// in the background, or queues them as one job of the job queue. The caller must have marked
+// the schedule as running.
+func (s *Scheduler) dispatch(schedule *entity.Schedule, scheduledAts []time.Time) {
+	if s.jobQueue != nil {
+		s.queueRun(schedule, scheduledAts)
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.markDone(string(schedule.ID))
+
+		for _, scheduledAt := range scheduledAts {
+			select {
+			case s.sem <- struct{}{}:
+			case <-s.ctx.Done():
+				return
+			}
+
+			err := s.execute(s.ctx, schedule, scheduledAt)
+			<-s.sem
+
+			if err != nil {
+				s.logger.Error("scheduled execution failed", "schedule_id", schedule.ID, "skill", schedule.Skill, "error", err)
+			}
+		}
+
+		if schedule.IsOneShot() {
+			s.removeOneShot(s.ctx, schedule)
+		}
+	}()
+}
+
+// queueRun queues activations of a schedule to the job queue. The job is keyed by the schedule
+// and its last activation, so that an activation is queued once.
+func (s *Scheduler) queueRun(schedule *entity.Schedule, scheduledAts []time.Time) {
+	scheduleID := string(schedule.ID)
+	defer s.markDone(scheduleID)
+
+	key := fmt.Sprintf("schedule:%s:%d", scheduleID, scheduledAts[len(scheduledAts)-1].Unix())
+	payload := runJobPayload{ScheduleID: scheduleID, ScheduledAts: scheduledAts}
+	if err := s.jobQueue.Enqueue(s.ctx, runJobKind, payload, ports.JobOptions{Key: key, MaxAttempts: 1}); err != nil {
+		s.metrics.RunsFailed.Inc()
+		s.logger.Error("failed to queue schedule run", "schedule_id", scheduleID, "skill", schedule.Skill, "error", err)
+	}
+}
+
+// handleRunJob executes the activations of a schedule queued to the job queue one after
+// another, like dispatch. The run is skipped when the schedule was deleted or is already running.
+func (s *Scheduler) handleRunJob(ctx context.Context, job *entity.Job) error {
+	var payload runJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("%w: invalid schedule run: %v", ports.ErrJobPermanent, err)
+	}
+
+	schedule, err := s.scheduleRepo.FindByID(ctx, payload.ScheduleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		s.logger.Info("schedule deleted before its run, skipping", "schedule_id", payload.ScheduleID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find schedule: %w", err)
+	}
+
+	s.mu.Lock()
+	if s.running[payload.ScheduleID] {
+		s.mu.Unlock()
+		s.metrics.RunsSkipped.Inc()
+		s.logger.Warn("previous run still in progress, skipping", "schedule_id", payload.ScheduleID)
+		return nil
+	}
+	s.running[payload.ScheduleID] = true
+	s.mu.Unlock()
+	defer s.markDone(payload.ScheduleID)
+
+	for _, scheduledAt := range payload.ScheduledAts {
+		select {
+		case s.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		err := s.execute(ctx, schedule, scheduledAt)
+		<-s.sem
+
+		if err != nil {
+			s.logger.Error("scheduled execution failed", "schedule_id", schedule.ID, "skill", schedule.Skill, "error", err)
+		}
+	}
+
+	if schedule.IsOneShot() {
+		s.removeOneShot(ctx, schedule)
+	}
+	return nil
+}
+
+// catchUp handles cron activations missed while the server was down,
+// according to each schedule's missed run policy
+func (s *Scheduler) catchUp(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	entries := make([]*entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		if e.spec != nil {
+			entries = append(entries, e)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		id := string(e.schedule.ID)
+		missed, total := s.missedRuns(ctx, e, now)
+		if total == 0 {
+			continue
+		}
+
+		policy := e.schedule.MissedRunPolicy
+		s.logger.Info("missed schedule runs detected", "schedule_id", id, "missed", total, "policy", policy.String())
+
+		switch policy {
+		case valueobject.MissedRunAll:
+		case valueobject.MissedRunOnce:
+			missed = missed[len(missed)-1:]
+		default:
+			missed = nil
+		}
+		s.metrics.RunsMissed.Add(int64(total - len(missed)))
+
+		if len(missed) == 0 {
+			continue
+		}
+
+		s.mu.Lock()
+		if s.running[id] {
+			s.mu.Unlock()
+			continue
+		}
+		s.running[id] = true
+		s.mu.Unlock()
+
+		s.dispatch(e.schedule, missed)
+	}
+}
+
+// missedRuns returns the activations of an entry between its last recorded run
+// (or its creation if it never ran) and now, together with their total count.
+// At most maxCatchUpRuns of the most recent activations are returned.
+func (s *Scheduler) missedRuns(ctx context.Context, e *entry, now time.Time) ([]time.Time, int) {
+	from := e.schedule.CreatedAt
+	if last, err := s.runRepo.FindLatest(ctx, string(e.schedule.ID)); err == nil && last != nil {
+		from = last.ScheduledAt
+	}
+
+	var missed []time.Time
+	total := 0
+	for t := e.spec.Next(from.In(e.location)); !t.IsZero() && !t.After(now); t = e.spec.Next(t) {
+		total++
+		missed = append(missed, t)
+		if len(missed) > maxCatchUpRuns {
+			missed = missed[1:]
+		}
+	}
+
+	return missed, total
+}
+
+// oneShotEntry creates an entry that fires once at the schedule's run time.
+// Run times already in the past fire on the next tick.
+// Returns false if the schedule has no run time.
+func (s *Scheduler) oneShotEntry(schedule *entity.Schedule) (*entry, bool) {
+	if schedule.RunAt == nil {
+		s.logger.Warn("skipping one-shot schedule without run time", "schedule_id", schedule.ID)
+		return nil, false
+	}
+
+	e := &entry{
+		schedule: schedule,
+		location: schedule.Location(),
+		base:     *schedule.RunAt,
+		next:     *schedule.RunAt,
+	}
+	if max := schedule.Jitter(); max > 0 {
+		e.next = e.base.Add(s.jitter(max))
+	}
+	return e, true
+}
+
+// removeOneShot deletes a fired one-shot schedule from storage
+func (s *Scheduler) removeOneShot(ctx context.Context, schedule *entity.Schedule) {
+	if err := s.scheduleRepo.Delete(ctx, string(schedule.ID)); err != nil {
+		s.logger.Error("failed to delete one-shot schedule", "schedule_id", schedule.ID, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.entries, string(schedule.ID))
+	s.mu.Unlock()
+
+	s.logger.Info("one-shot schedule removed", "schedule_id", schedule.ID, "skill", schedule.Skill)
+}
+
+// advance computes the next activation of an entry after the given time,
+// evaluated in the schedule's timezone and delayed by a random jitter.
+// Returns false if the schedule never fires again.
+func (s *Scheduler) advance(e *entry, after time.Time) bool {
+	base := e.spec.Next(after.In(e.location))
+	if base.IsZero() {
+		return false
+	}
+
+	e.base = base
+	e.next = base
+	if max := e.schedule.Jitter(); max > 0 {
+		e.next = base.Add(s.jitter(max))
+	}
+	return true
+}
+
+// randomJitter returns a random duration in [0, max)
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
+
+// markDone clears the running flag of a schedule
+func (s *Scheduler) markDone(id string) {
+	s.mu.Lock()
+	delete(s.running, id)
+	s.mu.Unlock()
+}
+
+// execute runs a single activation of a schedule through the orchestrator
+// and records it in the schedule's run history
+func (s *Scheduler) execute(ctx context.Context, schedule *entity.Schedule, scheduledAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.ExecutionTimeout)
+	defer cancel()
+
+	s.metrics.RunsTriggered.Inc()
+	scheduleID := string(schedule.ID)
+
+	run := entity.NewScheduleRun(scheduleID, scheduledAt)
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		s.logger.Error("failed to record schedule run", "schedule_id", scheduleID, "error", err)
+	}
+
+	var sessionID string
+	var err error
+	if schedule.IsPrompt() {
+		sessionID, err = s.promptSession(ctx, schedule)
+	} else {
+		sessionID, err = s.systemSession(ctx)
+	}
+	if err != nil {
+		s.metrics.RunsFailed.Inc()
+		s.finishRun(ctx, run, "", err)
+		s.publish(eventbus.EventScheduleFailed, scheduleID, schedule.Skill, "", "", err, 0)
+		return fmt.Errorf("failed to get system session: %w", err)
+	}
+
+	s.logger.Info("schedule triggered", "schedule_id", scheduleID, "skill", schedule.Skill, "session_id", sessionID)
+	s.publish(eventbus.EventScheduleTriggered, scheduleID, schedule.Skill, sessionID, "", nil, 0)
+
+	start := time.Now()
+	var output string
+	err = metrics.RecordDurationWithError(s.metrics.ExecutionDuration, func() error {
+		var err error
+		if schedule.IsPrompt() {
+			output, err = s.sendPrompt(ctx, schedule, sessionID, scheduledAt)
+		} else {
+			output, err = s.executeSkill(ctx, schedule, sessionID)
+		}
+		return err
+	})
+	duration := time.Since(start)
+
+	if err != nil {
+		s.metrics.RunsFailed.Inc()
+		s.finishRun(ctx, run, "", err)
+		s.publish(eventbus.EventScheduleFailed, scheduleID, schedule.Skill, sessionID, "", err, duration)
+		return err
+	}
+
+	s.metrics.RunsSucceeded.Inc()
+	s.finishRun(ctx, run, output, nil)
+	s.logger.Info("schedule completed", "schedule_id", scheduleID, "skill", schedule.Skill, "duration", duration)
+	s.publish(eventbus.EventScheduleCompleted, scheduleID, schedule.Skill, sessionID, output, nil, duration)
+
+	s.deliver(ctx, schedule, output)
+
+	return nil
+}
+
+// executeSkill executes the skill of a schedule and returns its output
+func (s *Scheduler) executeSkill(ctx context.Context, schedule *entity.Schedule, sessionID string) (string, error) {
+	resp, err := s.orchestrator.ExecuteSkill(ctx, sessionID, schedule.Skill, schedule.GetInput())
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success {
+		return "", fmt.Errorf("skill execution failed: %s", resp.Error)
+	}
+	return resp.Output, nil
+}
+
+// sendPrompt sends the prompt of a schedule, rendered for the run scheduled at scheduledAt,
+// to the LLM in the schedule's session and returns the reply
+func (s *Scheduler) sendPrompt(ctx context.Context, schedule *entity.Schedule, sessionID string, scheduledAt time.Time) (string, error) {
+	prompt, err := schedule.RenderPrompt(scheduledAt)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.orchestrator.ProcessMessage(ctx, SystemUserChannelID, prompt, dto.MessageOptions{SessionID: sessionID})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success || resp.Message == nil {
+		return "", fmt.Errorf("prompt failed: %s", resp.Error)
+	}
+	return resp.Message.Content, nil
+}
+
+// deliver pushes the output of a run to the schedule's target user.
+// Delivery failures are logged and counted but do not fail the run.
+func (s *Scheduler) deliver(ctx context.Context, schedule *entity.Schedule, output string) {
+	if !schedule.HasTarget() || output == "" {
+		return
+	}
+
+	s.mu.Lock()
+	sender := s.sender
+	s.mu.Unlock()
+
+	if sender == nil {
+		s.logger.Warn("schedule target ignored, no message sender configured", "schedule_id", schedule.ID)
+		return
+	}
+
+	if err := sender.SendMessage(ctx, schedule.TargetConnector, schedule.TargetUserID, output); err != nil {
+		s.metrics.DeliveriesFailed.Inc()
+		s.logger.Error("failed to deliver schedule output",
+			"schedule_id", schedule.ID,
+			"connector", schedule.TargetConnector,
+			"user_id", schedule.TargetUserID,
+			"error", err,
+		)
+		return
+	}
+
+	s.metrics.DeliveriesSent.Inc()
+}
+
+// finishRun stores the result of a run. The run is saved even if the
+// execution context has expired, so timed out runs are recorded as failed.
+func (s *Scheduler) finishRun(ctx context.Context, run *entity.ScheduleRun, output string, runErr error) {
+	if runErr != nil {
+		run.SetFailed(runErr.Error())
+	} else {
+		run.SetCompleted(output)
+	}
+
+	if err := s.runRepo.Update(context.WithoutCancel(ctx), run); err != nil {
+		s.logger.Error("failed to update schedule run", "schedule_id", run.ScheduleID, "run_id", run.ID, "error", err)
+	}
+}
+
+// systemSession returns the session used for skill executions,
+// creating the system user and session on first use
+func (s *Scheduler) systemSession(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	sessionID := s.sessionID
+	s.mu.Unlock()
+
+	if sessionID != "" {
+		return sessionID, nil
+	}
+
+	user, err := s.systemUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// The sessions of prompt schedules are titled, the one of skill executions is not
+	sessions, err := s.sessionRepo.FindByUserID(ctx, string(user.ID), repository.QueryOptions{})
+	if err == nil {
+		for _, session := range sessions {
+			if session.Title == "" {
+				sessionID = string(session.ID)
+				break
+			}
+		}
+	}
+	if sessionID == "" {
+		session := entity.NewSession(string(user.ID))
+		if err := s.sessionRepo.Create(ctx, session); err != nil {
+			return "", fmt.Errorf("failed to create system session: %w", err)
+		}
+		sessionID = string(session.ID)
+	}
+
+	s.mu.Lock()
+	s.sessionID = sessionID
+	s.mu.Unlock()
+
+	return sessionID, nil
+}
+
+// promptSession returns the session the prompts of a schedule are sent in, so that each run
+// sees the replies to the earlier ones. The session is created on the first run, titled after
+// the prompt, and its ID is stored with the schedule.
+func (s *Scheduler) promptSession(ctx context.Context, schedule *entity.Schedule) (string, error) {
+	if schedule.SessionID != "" {
+		if _, err := s.sessionRepo.FindByID(ctx, schedule.SessionID); err == nil {
+			return schedule.SessionID, nil
+		}
+		s.logger.Warn("session of prompt schedule not found, creating a new one", "schedule_id", schedule.ID, "session_id", schedule.SessionID)
+	}
+
+	user, err := s.systemUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	session := entity.NewSession(string(user.ID))
+	session.Title = promptSessionTitle(schedule.Prompt)
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return "", fmt.Errorf("failed to create system session: %w", err)
+	}
+	schedule.SessionID = string(session.ID)
+
+	// The stored schedule may have changed since it was loaded
+	stored, err := s.scheduleRepo.FindByID(ctx, string(schedule.ID))
+	if err == nil {
+		stored.SessionID = schedule.SessionID
+		err = s.scheduleRepo.Update(ctx, stored)
+	}
+	if err != nil {
+		s.logger.Error("failed to store session of prompt schedule", "schedule_id", schedule.ID, "error", err)
+	}
+
+	return schedule.SessionID, nil
+}
+
+// promptSessionTitle returns the title of the session of a prompt schedule: the first line of
+// its prompt, cut to promptSessionTitleLength
+func promptSessionTitle(prompt string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	if runes := []rune(line); len(runes) > promptSessionTitleLength {
+		line = strings.TrimSpace(string(runes[:promptSessionTitleLength])) + "…"
+	}
+	return "Schedule: " + line
+}
+
+// systemUser returns the system user owning the sessions of scheduled executions,
+// creating it on first use
+func (s *Scheduler) systemUser(ctx context.Context) (*entity.User, error) {
+	user, err := s.userRepo.FindByChannel(ctx, string(valueobject.ChannelSystem), SystemUserChannelID)
+	if err == nil {
+		return user, nil
+	}
+
+	user = entity.NewUser(string(valueobject.ChannelSystem), SystemUserChannelID)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create system user: %w", err)
+	}
+	s.logger.Info("system user created", "user_id", user.ID)
+	return user, nil
+}
+
+// publish publishes a schedule event if an event bus is configured
+func (s *Scheduler) publish(eventType, scheduleID, skill, sessionID, output string, err error, duration time.Duration) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(eventbus.NewScheduleEvent(eventType, scheduleID, skill, sessionID, output, err, duration))
+}
@@ -0,0 +1,1057 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockScheduleRepository is a mock implementation of repository.ScheduleRepository for testing
+type mockScheduleRepository struct {
+	mu        sync.Mutex
+	schedules []*entity.Schedule
+	deleted   []string
+	err       error
+	calls     int
+}
+
+func (m *mockScheduleRepository) Create(ctx context.Context, schedule *entity.Schedule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schedules = append(m.schedules, schedule)
+	return nil
+}
+
+func (m *mockScheduleRepository) FindByID(ctx context.Context, id string) (*entity.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, schedule := range m.schedules {
+		if string(schedule.ID) == id {
+			return schedule, nil
+		}
+	}
+	return nil, fmt.Errorf("schedule %w: %s", repository.ErrNotFound, id)
+}
+
+func (m *mockScheduleRepository) FindBySkill(ctx context.Context, skill string) ([]*entity.Schedule, error) {
+	return nil, nil
+}
+
+func (m *mockScheduleRepository) List(ctx context.Context) ([]*entity.Schedule, error) {
+	return m.schedules, nil
+}
+
+func (m *mockScheduleRepository) Update(ctx context.Context, schedule *entity.Schedule) error {
+	return nil
+}
+
+func (m *mockScheduleRepository) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, id)
+	for i, s := range m.schedules {
+		if string(s.ID) == id {
+			m.schedules = append(m.schedules[:i], m.schedules[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockScheduleRepository) deletedIDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.deleted...)
+}
+
+func (m *mockScheduleRepository) FindEnabled(ctx context.Context) ([]*entity.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	enabled := make([]*entity.Schedule, 0, len(m.schedules))
+	for _, s := range m.schedules {
+		if s.IsEnabled() {
+			enabled = append(enabled, s)
+		}
+	}
+	return enabled, nil
+}
+
+func (m *mockScheduleRepository) findEnabledCalls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+// mockUserRepository is a mock implementation of repository.UserRepository for testing
+type mockUserRepository struct {
+	mu    sync.Mutex
+	users []*entity.User
+}
+
+func (m *mockUserRepository) Create(ctx context.Context, user *entity.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users = append(m.users, user)
+	return nil
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockUserRepository) FindByChannel(ctx context.Context, channel, channelID string) (*entity.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.users {
+		if string(u.Channel) == channel && u.ChannelID == channelID {
+			return u, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockUserRepository) List(ctx context.Context) ([]*entity.User, error) {
+	return m.users, nil
+}
+
+func (m *mockUserRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+// mockSessionRepository is a mock implementation of repository.SessionRepository for testing
+type mockSessionRepository struct {
+	mu       sync.Mutex
+	sessions []*entity.Session
+}
+
+func (m *mockSessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions = append(m.sessions, session)
+	return nil
+}
+
+func (m *mockSessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.sessions {
+		if string(s.ID) == id {
+			return s, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockSessionRepository) List(ctx context.Context, opts repository.QueryOptions) ([]*entity.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*entity.Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (m *mockSessionRepository) FindByUserID(ctx context.Context, userID string, opts repository.QueryOptions) ([]*entity.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*entity.Session, 0)
+	for _, s := range m.sessions {
+		if string(s.UserID) == userID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockSessionRepository) Update(ctx context.Context, session *entity.Session) error {
+	return nil
+}
+
+func (m *mockSessionRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+// executeCall records a single ExecuteSkill call
+type executeCall struct {
+	sessionID string
+	skill     string
+	input     map[string]interface{}
+}
+
+// mockScheduleRunRepository is a mock implementation of repository.ScheduleRunRepository for testing
+type mockScheduleRunRepository struct {
+	mu   sync.Mutex
+	runs []*entity.ScheduleRun
+}
+
+func (m *mockScheduleRunRepository) Create(ctx context.Context, run *entity.ScheduleRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *run
+	m.runs = append(m.runs, &copied)
+	return nil
+}
+
+func (m *mockScheduleRunRepository) Update(ctx context.Context, run *entity.ScheduleRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, r := range m.runs {
+		if r.ID == run.ID {
+			copied := *run
+			m.runs[i] = &copied
+			return nil
+		}
+	}
+	return errors.New("schedule run not found")
+}
+
+func (m *mockScheduleRunRepository) FindByScheduleID(ctx context.Context, scheduleID string, limit int) ([]*entity.ScheduleRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []*entity.ScheduleRun
+	for _, r := range m.runs {
+		if string(r.ScheduleID) == scheduleID {
+			runs = append(runs, r)
+		}
+	}
+	return runs, nil
+}
+
+func (m *mockScheduleRunRepository) FindLatest(ctx context.Context, scheduleID string) (*entity.ScheduleRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var latest *entity.ScheduleRun
+	for _, r := range m.runs {
+		if string(r.ScheduleID) == scheduleID && (latest == nil || r.ScheduledAt.After(latest.ScheduledAt)) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("schedule run not found")
+	}
+	return latest, nil
+}
+
+func (m *mockScheduleRunRepository) list() []*entity.ScheduleRun {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*entity.ScheduleRun(nil), m.runs...)
+}
+
+// mockMessageSender is a mock implementation of ports.MessageSender for testing
+type mockMessageSender struct {
+	mu       sync.Mutex
+	messages []sentMessage
+	err      error
+}
+
+type sentMessage struct {
+	connector string
+	userID    string
+	message   string
+}
+
+func (m *mockMessageSender) SendMessage(ctx context.Context, connector, userID, message string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.messages = append(m.messages, sentMessage{connector: connector, userID: userID, message: message})
+	return nil
+}
+
+// promptCall records a single ProcessMessage call
+type promptCall struct {
+	sessionID string
+	content   string
+}
+
+// mockOrchestrator is a mock implementation of ports.Orchestrator for testing
+type mockOrchestrator struct {
+	mu          sync.Mutex
+	calls       []executeCall
+	prompts     []promptCall
+	executeFunc func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error)
+	promptErr   string
+}
+
+func (m *mockOrchestrator) ProcessMessage(ctx context.Context, userID, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompts = append(m.prompts, promptCall{sessionID: options.SessionID, content: content})
+	if m.promptErr != "" {
+		return &dto.SendMessageResponse{Success: false, Error: m.promptErr}, nil
+	}
+	return &dto.SendMessageResponse{Success: true, Message: &dto.MessageDTO{SessionID: options.SessionID, Role: "assistant", Content: "reply: " + content}}, nil
+}
+
+func (m *mockOrchestrator) GetConversation(ctx context.Context, sessionID string) (*dto.MessagesResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrchestrator) GetUserSessions(ctx context.Context, userID string) (*dto.SessionsResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrchestrator) CreateSession(ctx context.Context, req dto.CreateSessionRequest) (*dto.SessionResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrchestrator) ExecuteSkill(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, executeCall{sessionID: sessionID, skill: skillName, input: input})
+	fn := m.executeFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, sessionID, skillName, input)
+	}
+	return &dto.SkillExecutionResponse{Success: true, Output: "ok"}, nil
+}
+
+func (m *mockOrchestrator) GetSessionTasks(ctx context.Context, sessionID string) (*dto.TasksResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrchestrator) callCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// newTestScheduler creates a scheduler with mock dependencies
+func newTestScheduler(schedules ...*entity.Schedule) (*Scheduler, *mockScheduleRepository, *mockOrchestrator) {
+	scheduleRepo := &mockScheduleRepository{schedules: schedules}
+	orch := &mockOrchestrator{}
+	s := NewScheduler(scheduleRepo, &mockScheduleRunRepository{}, &mockUserRepository{}, &mockSessionRepository{}, orch, nil, logging.NewNoopLogger(), nil)
+	return s, scheduleRepo, orch
+}
+
+func TestNewScheduler_InvalidConfigUsesDefaults(t *testing.T) {
+	s := NewScheduler(&mockScheduleRepository{}, &mockScheduleRunRepository{}, &mockUserRepository{}, &mockSessionRepository{}, &mockOrchestrator{}, nil, logging.NewNoopLogger(), &Config{})
+
+	assert.Equal(t, DefaultConfig(), s.config)
+}
+
+func TestScheduler_LoadSkipsDisabled(t *testing.T) {
+	enabled := entity.NewSchedule("weather", "0 9 * * *", "{}")
+	disabled := entity.NewSchedule("news", "0 10 * * *", "{}")
+	disabled.Disable()
+
+	s, _, _ := newTestScheduler(enabled, disabled)
+
+	require.NoError(t, s.load(context.Background()))
+
+	assert.Equal(t, 1, s.entryCount())
+	assert.Equal(t, int64(1), s.Metrics().SchedulesLoaded.Get())
+}
+
+func TestScheduler_StartFailsOnRepositoryError(t *testing.T) {
+	s, repo, _ := newTestScheduler()
+	repo.err = errors.New("db down")
+
+	err := s.Start()
+
+	assert.Error(t, err)
+}
+
+func TestScheduler_ExecuteCreatesSystemSession(t *testing.T) {
+	schedule := entity.NewSchedule("weather", "* * * * *", `{"city":"Moscow"}`)
+	s, _, orch := newTestScheduler(schedule)
+
+	require.NoError(t, s.execute(context.Background(), schedule, time.Now()))
+	require.NoError(t, s.execute(context.Background(), schedule, time.Now()))
+
+	require.Equal(t, 2, orch.callCount())
+	assert.Equal(t, "weather", orch.calls[0].skill)
+	assert.Equal(t, "Moscow", orch.calls[0].input["city"])
+	assert.NotEmpty(t, orch.calls[0].sessionID)
+	assert.Equal(t, orch.calls[0].sessionID, orch.calls[1].sessionID)
+
+	users := s.userRepo.(*mockUserRepository).users
+	require.Len(t, users, 1)
+	assert.True(t, users[0].Channel.IsSystem())
+	assert.Equal(t, SystemUserChannelID, users[0].ChannelID)
+
+	assert.Equal(t, int64(2), s.Metrics().RunsSucceeded.Get())
+}
+
+func TestScheduler_ExecuteFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error)
+	}{
+		{
+			name: "orchestrator error",
+			fn: func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+				return nil, errors.New("boom")
+			},
+		},
+		{
+			name: "skill reports failure",
+			fn: func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+				return &dto.SkillExecutionResponse{Success: false, Error: "bad input"}, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := entity.NewSchedule("weather", "* * * * *", "{}")
+			s, _, orch := newTestScheduler(schedule)
+			orch.executeFunc = tt.fn
+
+			err := s.execute(context.Background(), schedule, time.Now())
+
+			assert.Error(t, err)
+			assert.Equal(t, int64(1), s.Metrics().RunsFailed.Get())
+
+			runs := s.runRepo.(*mockScheduleRunRepository).list()
+			require.Len(t, runs, 1)
+			assert.True(t, runs[0].Status.IsFailed())
+			assert.NotEmpty(t, runs[0].Error)
+			assert.NotNil(t, runs[0].FinishedAt)
+		})
+	}
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	schedule := entity.NewSchedule("weather", "* * * * *", "{}")
+	s, _, orch := newTestScheduler(schedule)
+
+	now := time.Date(2024, time.January, 15, 10, 0, 30, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.load(context.Background()))
+
+	// Not due yet
+	s.runDue(now)
+	s.wg.Wait()
+	assert.Equal(t, 0, orch.callCount())
+
+	// Due at the next minute
+	due := time.Date(2024, time.January, 15, 10, 1, 0, 0, time.UTC)
+	s.runDue(due)
+	s.wg.Wait()
+	assert.Equal(t, 1, orch.callCount())
+
+	// Next activation advanced past the run
+	s.mu.Lock()
+	next := s.entries[string(schedule.ID)].next
+	s.mu.Unlock()
+	assert.Equal(t, time.Date(2024, time.January, 15, 10, 2, 0, 0, time.UTC), next)
+}
+
+// mockJobQueue keeps the queued jobs until they are run by the test
+type mockJobQueue struct {
+	handler ports.JobHandler
+	jobs    []*entity.Job
+}
+
+func (q *mockJobQueue) Register(kind string, handler ports.JobHandler) {
+	q.handler = handler
+}
+
+func (q *mockJobQueue) Enqueue(ctx context.Context, kind string, payload any, opts ports.JobOptions) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	for _, job := range q.jobs {
+		if job.ID == opts.Key {
+			return nil
+		}
+	}
+	q.jobs = append(q.jobs, entity.NewJob(kind, opts.Key, string(data), opts.MaxAttempts))
+	return nil
+}
+
+func TestScheduler_RunDueQueuesJob(t *testing.T) {
+	schedule := entity.NewOneShotSchedule("reminder", time.Date(2024, time.January, 15, 10, 1, 0, 0, time.UTC), "{}")
+	s, repo, orch := newTestScheduler(schedule)
+	queue := &mockJobQueue{}
+	s.SetJobQueue(queue)
+
+	now := time.Date(2024, time.January, 15, 10, 1, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.load(context.Background()))
+
+	// The due run is queued, not executed
+	s.runDue(now)
+	s.wg.Wait()
+	require.Len(t, queue.jobs, 1)
+	assert.Equal(t, runJobKind, queue.jobs[0].Kind)
+	assert.Equal(t, "schedule:"+string(schedule.ID)+":1705312860", queue.jobs[0].ID)
+	assert.Equal(t, 0, orch.callCount())
+
+	// The job executes the run and consumes the one-shot schedule
+	require.NoError(t, queue.handler(context.Background(), queue.jobs[0]))
+	assert.Equal(t, 1, orch.callCount())
+	assert.Equal(t, []string{string(schedule.ID)}, repo.deletedIDs())
+
+	// A job of a deleted schedule is skipped
+	require.NoError(t, queue.handler(context.Background(), queue.jobs[0]))
+	assert.Equal(t, 1, orch.callCount())
+}
+
+func TestScheduler_RunDueSkipsOverlappingRun(t *testing.T) {
+	schedule := entity.NewSchedule("slow", "* * * * *", "{}")
+	s, _, orch := newTestScheduler(schedule)
+
+	release := make(chan struct{})
+	orch.executeFunc = func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+		<-release
+		return &dto.SkillExecutionResponse{Success: true}, nil
+	}
+
+	now := time.Date(2024, time.January, 15, 10, 0, 30, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.load(context.Background()))
+
+	s.runDue(now.Add(time.Minute))
+	assert.Eventually(t, func() bool { return orch.callCount() == 1 }, time.Second, 5*time.Millisecond)
+
+	// Previous run is still in progress
+	s.runDue(now.Add(2 * time.Minute))
+	assert.Equal(t, int64(1), s.Metrics().RunsSkipped.Get())
+
+	close(release)
+	s.wg.Wait()
+	assert.Equal(t, 1, orch.callCount())
+}
+
+func TestScheduler_RunDueJobs(t *testing.T) {
+	s, _, _ := newTestScheduler()
+
+	now := time.Date(2024, time.January, 15, 2, 59, 30, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var runs int
+	require.NoError(t, s.AddJob("backup", "0 3 * * *", func(ctx context.Context) error {
+		runs++
+		return nil
+	}))
+	require.NoError(t, s.AddJob("failing", "0 3 * * *", func(ctx context.Context) error {
+		return errors.New("disk full")
+	}))
+
+	assert.Error(t, s.AddJob("backup", "0 4 * * *", func(ctx context.Context) error { return nil }))
+	assert.Error(t, s.AddJob("invalid", "not a cron", func(ctx context.Context) error { return nil }))
+	assert.Equal(t, 30*time.Second, s.untilNext())
+
+	s.runDue(now)
+	s.wg.Wait()
+	assert.Equal(t, 0, runs)
+
+	due := time.Date(2024, time.January, 15, 3, 0, 0, 0, time.UTC)
+	s.runDue(due)
+	s.wg.Wait()
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, int64(1), s.Metrics().JobsSucceeded.Get())
+	assert.Equal(t, int64(1), s.Metrics().JobsFailed.Get())
+
+	s.mu.Lock()
+	next := s.jobs["backup"].next
+	s.mu.Unlock()
+	assert.Equal(t, time.Date(2024, time.January, 16, 3, 0, 0, 0, time.UTC), next)
+}
+
+// leadership is a ports.Leadership switched by the test
+type leadership struct {
+	leader bool
+}
+
+func (l *leadership) IsLeader() bool {
+	return l.leader
+}
+
+func TestScheduler_RunDueOnLeaderOnly(t *testing.T) {
+	schedule := entity.NewSchedule("weather", "* * * * *", "{}")
+	s, _, orch := newTestScheduler(schedule)
+	l := &leadership{}
+	s.SetLeadership(l)
+
+	var jobRuns int
+	now := time.Date(2024, time.January, 15, 10, 0, 30, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.AddJob("backup", "* * * * *", func(ctx context.Context) error {
+		jobRuns++
+		return nil
+	}))
+	require.NoError(t, s.load(context.Background()))
+
+	// A follower only advances the activations
+	s.runDue(time.Date(2024, time.January, 15, 10, 1, 0, 0, time.UTC))
+	s.wg.Wait()
+	assert.Equal(t, 0, orch.callCount())
+	assert.Equal(t, 0, jobRuns)
+
+	s.mu.Lock()
+	next := s.entries[string(schedule.ID)].next
+	s.mu.Unlock()
+	assert.Equal(t, time.Date(2024, time.January, 15, 10, 2, 0, 0, time.UTC), next)
+
+	// The leader runs them
+	l.leader = true
+	s.runDue(time.Date(2024, time.January, 15, 10, 2, 0, 0, time.UTC))
+	s.wg.Wait()
+	assert.Equal(t, 1, orch.callCount())
+	assert.Equal(t, 1, jobRuns)
+}
+
+func TestScheduler_ReloadPicksUpChanges(t *testing.T) {
+	s, repo, _ := newTestScheduler()
+
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	assert.Equal(t, 0, s.entryCount())
+
+	require.NoError(t, repo.Create(context.Background(), entity.NewSchedule("weather", "0 9 * * *", "{}")))
+	s.Reload()
+
+	assert.Eventually(t, func() bool { return s.entryCount() == 1 }, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, repo.findEnabledCalls(), 2)
+}
+
+func TestScheduler_StartTwice(t *testing.T) {
+	s, _, _ := newTestScheduler()
+
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	assert.Error(t, s.Start())
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	schedule := entity.NewSchedule("weather", "0 9 * * *", "{}")
+	schedule.Disable()
+	s, _, orch := newTestScheduler(schedule)
+
+	assert.Error(t, s.RunNow(context.Background(), string(schedule.ID)), "scheduler not started")
+
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	require.NoError(t, s.RunNow(context.Background(), string(schedule.ID)))
+	assert.Eventually(t, func() bool { return orch.callCount() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return len(s.runRepo.(*mockScheduleRunRepository).list()) == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.Error(t, s.RunNow(context.Background(), "missing"))
+}
+
+func TestScheduler_RunNowSkipsRunningSchedule(t *testing.T) {
+	schedule := entity.NewSchedule("weather", "0 9 * * *", "{}")
+	s, _, orch := newTestScheduler(schedule)
+
+	release := make(chan struct{})
+	orch.executeFunc = func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+		<-release
+		return &dto.SkillExecutionResponse{Success: true, Output: "ok"}, nil
+	}
+
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	require.NoError(t, s.RunNow(context.Background(), string(schedule.ID)))
+	assert.Error(t, s.RunNow(context.Background(), string(schedule.ID)))
+	assert.Equal(t, int64(1), s.Metrics().RunsSkipped.Get())
+
+	close(release)
+}
+
+func TestScheduler_RunNowConsumesOneShot(t *testing.T) {
+	schedule := entity.NewOneShotSchedule("reminder", time.Now().Add(time.Hour), "{}")
+	s, repo, orch := newTestScheduler(schedule)
+
+	require.NoError(t, s.Start())
+	defer s.Stop()
+	require.Equal(t, 1, s.entryCount())
+
+	require.NoError(t, s.RunNow(context.Background(), string(schedule.ID)))
+
+	assert.Eventually(t, func() bool { return len(repo.deletedIDs()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return s.entryCount() == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, orch.callCount())
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	schedule := entity.NewOneShotSchedule("reminder", time.Now().Add(time.Hour), "{}")
+	s, repo, orch := newTestScheduler(schedule)
+
+	require.NoError(t, s.RunOnce(context.Background(), string(schedule.ID)), "runs without Start")
+	assert.Equal(t, 1, orch.callCount())
+	assert.Len(t, s.runRepo.(*mockScheduleRunRepository).list(), 1)
+	assert.Equal(t, []string{string(schedule.ID)}, repo.deletedIDs())
+
+	orch.executeFunc = func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+		return nil, errors.New("boom")
+	}
+	other := entity.NewSchedule("weather", "0 9 * * *", "{}")
+	require.NoError(t, repo.Create(context.Background(), other))
+	assert.ErrorContains(t, s.RunOnce(context.Background(), string(other.ID)), "boom")
+
+	assert.Error(t, s.RunOnce(context.Background(), "missing"))
+}
+
+func TestScheduler_ReloadNeverBlocks(t *testing.T) {
+	s, _, _ := newTestScheduler()
+
+	// Not started: repeated reloads must not block
+	for i := 0; i < 10; i++ {
+		s.Reload()
+	}
+}
+
+func TestScheduler_LoadUsesScheduleTimezone(t *testing.T) {
+	schedule := entity.NewSchedule("reminder", "0 9 * * *", "{}")
+	schedule.SetTimezone(valueobject.MustNewTimezone("Europe/Moscow"))
+	s, _, _ := newTestScheduler(schedule)
+
+	// 05:00 UTC is 08:00 in Moscow, so the run is due at 09:00 Moscow (06:00 UTC)
+	now := time.Date(2024, time.January, 15, 5, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.load(context.Background()))
+
+	s.mu.Lock()
+	next := s.entries[string(schedule.ID)].next
+	s.mu.Unlock()
+	assert.True(t, next.Equal(time.Date(2024, time.January, 15, 6, 0, 0, 0, time.UTC)), "got %v", next)
+}
+
+func TestScheduler_LoadAppliesJitter(t *testing.T) {
+	schedule := entity.NewSchedule("bulk", "0 * * * *", "{}")
+	schedule.SetJitter(120)
+	s, _, _ := newTestScheduler(schedule)
+
+	var gotMax time.Duration
+	s.jitter = func(max time.Duration) time.Duration {
+		gotMax = max
+		return 45 * time.Second
+	}
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.load(context.Background()))
+
+	s.mu.Lock()
+	e := s.entries[string(schedule.ID)]
+	s.mu.Unlock()
+	assert.Equal(t, 120*time.Second, gotMax)
+	assert.Equal(t, time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC), e.base)
+	assert.Equal(t, time.Date(2024, time.January, 15, 11, 0, 45, 0, time.UTC), e.next)
+}
+
+func TestScheduler_OneShotFiresOnceAndIsDeleted(t *testing.T) {
+	runAt := time.Date(2024, time.January, 15, 10, 5, 0, 0, time.UTC)
+	schedule := entity.NewOneShotSchedule("reminder", runAt, `{"text":"stand up"}`)
+	s, repo, orch := newTestScheduler(schedule)
+
+	now := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.load(context.Background()))
+	require.Equal(t, 1, s.entryCount())
+	assert.Equal(t, 5*time.Minute, s.untilNext())
+
+	// Not due yet
+	s.runDue(now)
+	s.wg.Wait()
+	assert.Equal(t, 0, orch.callCount())
+
+	s.runDue(runAt)
+	s.wg.Wait()
+	assert.Equal(t, 1, orch.callCount())
+	assert.Equal(t, "stand up", orch.calls[0].input["text"])
+	assert.Equal(t, 0, s.entryCount())
+	assert.Equal(t, []string{string(schedule.ID)}, repo.deletedIDs())
+
+	// Never fires again
+	s.runDue(runAt.Add(time.Hour))
+	s.wg.Wait()
+	assert.Equal(t, 1, orch.callCount())
+}
+
+func TestScheduler_OneShotDeletedAfterFailure(t *testing.T) {
+	runAt := time.Date(2024, time.January, 15, 10, 5, 0, 0, time.UTC)
+	schedule := entity.NewOneShotSchedule("reminder", runAt, "{}")
+	s, repo, orch := newTestScheduler(schedule)
+	orch.executeFunc = func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+		return nil, errors.New("boom")
+	}
+
+	s.now = func() time.Time { return runAt.Add(-time.Minute) }
+	require.NoError(t, s.load(context.Background()))
+
+	s.runDue(runAt)
+	s.wg.Wait()
+
+	assert.Equal(t, int64(1), s.Metrics().RunsFailed.Get())
+	assert.Equal(t, []string{string(schedule.ID)}, repo.deletedIDs())
+}
+
+func TestScheduler_OneShotInPastFiresImmediately(t *testing.T) {
+	runAt := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	schedule := entity.NewOneShotSchedule("reminder", runAt, "{}")
+	s, _, _ := newTestScheduler(schedule)
+
+	s.now = func() time.Time { return runAt.Add(time.Hour) }
+	require.NoError(t, s.load(context.Background()))
+
+	assert.Equal(t, time.Duration(0), s.untilNext())
+}
+
+func TestScheduler_LoadSkipsRunningOneShot(t *testing.T) {
+	schedule := entity.NewOneShotSchedule("reminder", time.Now().Add(time.Hour), "{}")
+	missing := entity.NewOneShotSchedule("broken", time.Now().Add(time.Hour), "{}")
+	missing.RunAt = nil
+	s, _, _ := newTestScheduler(schedule, missing)
+
+	s.running[string(schedule.ID)] = true
+	require.NoError(t, s.load(context.Background()))
+
+	assert.Equal(t, 0, s.entryCount())
+}
+
+func TestScheduler_ExecuteRecordsRun(t *testing.T) {
+	schedule := entity.NewSchedule("weather", "* * * * *", "{}")
+	s, _, _ := newTestScheduler(schedule)
+	scheduledAt := time.Date(2024, time.January, 15, 10, 1, 0, 0, time.UTC)
+
+	require.NoError(t, s.execute(context.Background(), schedule, scheduledAt))
+
+	runs := s.runRepo.(*mockScheduleRunRepository).list()
+	require.Len(t, runs, 1)
+	assert.Equal(t, string(schedule.ID), string(runs[0].ScheduleID))
+	assert.True(t, runs[0].Status.IsCompleted())
+	assert.Equal(t, "ok", runs[0].Output)
+	assert.True(t, scheduledAt.Equal(runs[0].ScheduledAt))
+	assert.True(t, runs[0].IsFinished())
+}
+
+func TestScheduler_ExecuteDeliversToTarget(t *testing.T) {
+	schedule := entity.NewSchedule("digest", "0 8 * * *", "{}")
+	schedule.SetTarget("telegram", "chat-42")
+	s, _, _ := newTestScheduler(schedule)
+	sender := &mockMessageSender{}
+	s.SetMessageSender(sender)
+
+	require.NoError(t, s.execute(context.Background(), schedule, time.Now()))
+
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, sentMessage{connector: "telegram", userID: "chat-42", message: "ok"}, sender.messages[0])
+	assert.Equal(t, int64(1), s.Metrics().DeliveriesSent.Get())
+}
+
+func TestScheduler_ExecuteDeliveryFailureDoesNotFailRun(t *testing.T) {
+	schedule := entity.NewSchedule("digest", "0 8 * * *", "{}")
+	schedule.SetTarget("telegram", "chat-42")
+	s, _, _ := newTestScheduler(schedule)
+	s.SetMessageSender(&mockMessageSender{err: errors.New("telegram unavailable")})
+
+	require.NoError(t, s.execute(context.Background(), schedule, time.Now()))
+
+	assert.Equal(t, int64(1), s.Metrics().RunsSucceeded.Get())
+	assert.Equal(t, int64(1), s.Metrics().DeliveriesFailed.Get())
+}
+
+func TestScheduler_ExecuteSkipsDelivery(t *testing.T) {
+	schedule := entity.NewSchedule("digest", "0 8 * * *", "{}")
+	s, _, orch := newTestScheduler(schedule)
+	sender := &mockMessageSender{}
+	s.SetMessageSender(sender)
+
+	// No target
+	require.NoError(t, s.execute(context.Background(), schedule, time.Now()))
+
+	// Failed run
+	schedule.SetTarget("telegram", "chat-42")
+	orch.executeFunc = func(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+		return &dto.SkillExecutionResponse{Success: false, Error: "boom"}, nil
+	}
+	require.Error(t, s.execute(context.Background(), schedule, time.Now()))
+
+	assert.Empty(t, sender.messages)
+}
+
+func TestScheduler_ExecutePrompt(t *testing.T) {
+	skill := entity.NewSchedule("weather", "* * * * *", "{}")
+	schedule := entity.NewPromptSchedule("Plan my {{.Weekday}}, focusing on {{.Input.topic}}", "0 8 * * *", `{"topic":"sports"}`)
+	schedule.SetTarget("telegram", "chat-42")
+	s, _, orch := newTestScheduler(skill, schedule)
+	sender := &mockMessageSender{}
+	s.SetMessageSender(sender)
+	scheduledAt := time.Date(2024, time.January, 15, 8, 0, 0, 0, time.UTC)
+
+	require.NoError(t, s.execute(context.Background(), schedule, scheduledAt))
+	require.NoError(t, s.execute(context.Background(), schedule, scheduledAt.Add(24*time.Hour)))
+
+	assert.Zero(t, orch.callCount())
+	require.Len(t, orch.prompts, 2)
+	assert.Equal(t, "Plan my Monday, focusing on sports", orch.prompts[0].content)
+	assert.Equal(t, "Plan my Tuesday, focusing on sports", orch.prompts[1].content)
+
+	// Runs of the schedule share a titled session, stored with the schedule
+	require.NotEmpty(t, schedule.SessionID)
+	assert.Equal(t, schedule.SessionID, orch.prompts[0].sessionID)
+	assert.Equal(t, schedule.SessionID, orch.prompts[1].sessionID)
+	session, err := s.sessionRepo.FindByID(context.Background(), schedule.SessionID)
+	require.NoError(t, err)
+	assert.Equal(t, "Schedule: Plan my {{.Weekday}}, focusing on {{.Input.topic}}", session.Title)
+
+	require.Len(t, sender.messages, 2)
+	assert.Equal(t, sentMessage{connector: "telegram", userID: "chat-42", message: "reply: Plan my Monday, focusing on sports"}, sender.messages[0])
+
+	runs := s.runRepo.(*mockScheduleRunRepository).list()
+	require.Len(t, runs, 2)
+	assert.Equal(t, "reply: Plan my Monday, focusing on sports", runs[0].Output)
+
+	// Skill executions do not use the session of a prompt schedule
+	require.NoError(t, s.execute(context.Background(), skill, scheduledAt))
+	require.Equal(t, 1, orch.callCount())
+	assert.NotEqual(t, schedule.SessionID, orch.calls[0].sessionID)
+}
+
+func TestScheduler_ExecutePromptFailure(t *testing.T) {
+	schedule := entity.NewPromptSchedule("Summarize the news", "0 8 * * *", "{}")
+	schedule.SetTarget("telegram", "chat-42")
+	s, _, orch := newTestScheduler(schedule)
+	sender := &mockMessageSender{}
+	s.SetMessageSender(sender)
+	orch.promptErr = "provider unavailable"
+
+	err := s.execute(context.Background(), schedule, time.Now())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "provider unavailable")
+	assert.Equal(t, int64(1), s.Metrics().RunsFailed.Get())
+	assert.Empty(t, sender.messages)
+}
+
+func TestPromptSessionTitle(t *testing.T) {
+	assert.Equal(t, "Schedule: Summarize the news", promptSessionTitle("  Summarize the news\nin three bullets"))
+	assert.Equal(t, "Schedule: "+strings.Repeat("a", promptSessionTitleLength)+"…", promptSessionTitle(strings.Repeat("a", 100)))
+}
+
+func TestScheduler_CatchUp(t *testing.T) {
+	now := time.Date(2024, time.January, 15, 10, 0, 30, 0, time.UTC)
+	lastRun := time.Date(2024, time.January, 15, 6, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		policy     valueobject.MissedRunPolicy
+		wantRuns   []time.Time
+		wantMissed int64
+	}{
+		{
+			name:       "skip",
+			policy:     valueobject.MissedRunSkip,
+			wantRuns:   nil,
+			wantMissed: 4,
+		},
+		{
+			name:       "run once",
+			policy:     valueobject.MissedRunOnce,
+			wantRuns:   []time.Time{time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)},
+			wantMissed: 3,
+		},
+		{
+			name:   "run all",
+			policy: valueobject.MissedRunAll,
+			wantRuns: []time.Time{
+				time.Date(2024, time.January, 15, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, time.January, 15, 8, 0, 0, 0, time.UTC),
+				time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC),
+			},
+			wantMissed: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := entity.NewSchedule("weather", "0 * * * *", "{}")
+			schedule.SetMissedRunPolicy(tt.policy)
+			s, _, orch := newTestScheduler(schedule)
+			s.now = func() time.Time { return now }
+
+			runRepo := s.runRepo.(*mockScheduleRunRepository)
+			previous := entity.NewScheduleRun(string(schedule.ID), lastRun)
+			previous.SetCompleted("ok")
+			require.NoError(t, runRepo.Create(context.Background(), previous))
+
+			require.NoError(t, s.load(context.Background()))
+			s.catchUp(context.Background(), now)
+			s.wg.Wait()
+
+			assert.Equal(t, len(tt.wantRuns), orch.callCount())
+			assert.Equal(t, tt.wantMissed, s.Metrics().RunsMissed.Get())
+
+			runs := runRepo.list()[1:]
+			require.Len(t, runs, len(tt.wantRuns))
+			for i, want := range tt.wantRuns {
+				assert.True(t, want.Equal(runs[i].ScheduledAt), "run %d scheduled at %v, want %v", i, runs[i].ScheduledAt, want)
+			}
+		})
+	}
+}
+
+func TestScheduler_CatchUpWithoutHistoryUsesCreatedAt(t *testing.T) {
+	now := time.Date(2024, time.January, 15, 10, 0, 30, 0, time.UTC)
+	schedule := entity.NewSchedule("weather", "0 * * * *", "{}")
+	schedule.CreatedAt = time.Date(2024, time.January, 15, 8, 30, 0, 0, time.UTC)
+	schedule.SetMissedRunPolicy(valueobject.MissedRunAll)
+	s, _, orch := newTestScheduler(schedule)
+
+	require.NoError(t, s.load(context.Background()))
+	s.catchUp(context.Background(), now)
+	s.wg.Wait()
+
+	assert.Equal(t, 2, orch.callCount())
+}
+
+func TestScheduler_CatchUpLimitsRuns(t *testing.T) {
+	now := time.Date(2024, time.January, 15, 10, 0, 30, 0, time.UTC)
+	schedule := entity.NewSchedule("weather", "* * * * *", "{}")
+	schedule.CreatedAt = now.Add(-24 * time.Hour)
+	schedule.SetMissedRunPolicy(valueobject.MissedRunAll)
+	s, _, orch := newTestScheduler(schedule)
+
+	require.NoError(t, s.load(context.Background()))
+	s.catchUp(context.Background(), now)
+	s.wg.Wait()
+
+	assert.Equal(t, maxCatchUpRuns, orch.callCount())
+	assert.Equal(t, int64(24*60-maxCatchUpRuns), s.Metrics().RunsMissed.Get())
+
+	runs := s.runRepo.(*mockScheduleRunRepository).list()
+	assert.True(t, time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC).Equal(runs[len(runs)-1].ScheduledAt))
+}
+
+func TestRandomJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), randomJitter(0))
+
+	for i := 0; i < 100; i++ {
+		d := randomJitter(time.Second)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, time.Second)
+	}
+}
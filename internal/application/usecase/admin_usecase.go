@@ -0,0 +1,363 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// AdminConfig holds configuration for the AdminUseCase
+type AdminConfig struct {
+	// LLMModels maps the configured LLM provider names to their models
+	LLMModels map[string]string
+}
+
+// AdminUseCase handles inspecting and controlling the runtime: connectors, the message router and the LLM provider
+type AdminUseCase struct {
+	connectors   ports.ConnectorManager
+	llmProvider  ports.LLMProvider
+	llmHealth    ports.LLMProviderHealth
+	instructions ports.InstructionsManager
+	config       AdminConfig
+	logger       logging.Logger
+}
+
+// NewAdminUseCase creates a new AdminUseCase
+func NewAdminUseCase(connectors ports.ConnectorManager, llmProvider ports.LLMProvider, config AdminConfig, logger logging.Logger) *AdminUseCase {
+	return &AdminUseCase{
+		connectors:  connectors,
+		llmProvider: llmProvider,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// SetInstructionsManager sets the holder of the global custom instructions.
+// Without it, the instructions methods return ports.ErrInstructionsDisabled.
+func (uc *AdminUseCase) SetInstructionsManager(instructions ports.InstructionsManager) {
+	uc.instructions = instructions
+}
+
+// SetLLMHealth sets the health checks of the LLM providers listed by ListLLMProviders.
+// Without them, only the availability of the active provider is checked, on every listing.
+func (uc *AdminUseCase) SetLLMHealth(health ports.LLMProviderHealth) {
+	uc.llmHealth = health
+}
+
+// ListConnectors returns the status of all registered connectors, sorted by name
+func (uc *AdminUseCase) ListConnectors(ctx context.Context) (*dto.ConnectorsResponse, error) {
+	statuses := uc.connectors.ConnectorStatuses()
+
+	dtos := make([]*dto.ConnectorDTO, len(statuses))
+	for i, status := range statuses {
+		dtos[i] = connectorDTO(status)
+	}
+
+	return dto.SuccessConnectorsResponse(dtos), nil
+}
+
+// GetConnector returns the status of a connector
+func (uc *AdminUseCase) GetConnector(ctx context.Context, name string) (*dto.ConnectorResponse, error) {
+	status, ok := uc.findConnector(name)
+	if !ok {
+		return dto.ErrorConnectorResponse(fmt.Errorf("%w: %s", ports.ErrConnectorNotFound, name)), nil
+	}
+
+	return dto.SuccessConnectorResponse(connectorDTO(status)), nil
+}
+
+// StartConnector starts a stopped connector.
+// If the connector cannot be started in its current state, the response carries its status
+// along with the error; an unknown connector yields a response without a connector.
+func (uc *AdminUseCase) StartConnector(ctx context.Context, name string) (*dto.ConnectorResponse, error) {
+	return uc.changeConnector(ctx, name, "start", uc.connectors.StartConnector)
+}
+
+// StopConnector stops a running connector.
+// If the connector cannot be stopped in its current state, the response carries its status
+// along with the error; an unknown connector yields a response without a connector.
+func (uc *AdminUseCase) StopConnector(ctx context.Context, name string) (*dto.ConnectorResponse, error) {
+	return uc.changeConnector(ctx, name, "stop", uc.connectors.StopConnector)
+}
+
+// changeConnector applies a start or stop operation to a connector and returns its new status
+func (uc *AdminUseCase) changeConnector(ctx context.Context, name, action string, apply func(context.Context, string) error) (*dto.ConnectorResponse, error) {
+	err := apply(ctx, name)
+	switch {
+	case errors.Is(err, ports.ErrConnectorNotFound):
+		return dto.ErrorConnectorResponse(err), nil
+	case errors.Is(err, ports.ErrConnectorState):
+		resp := dto.ErrorConnectorResponse(err)
+		if status, ok := uc.findConnector(name); ok {
+			resp.Connector = connectorDTO(status)
+		}
+		return resp, nil
+	case err != nil:
+		return handleConnectorError(err, "failed to "+action+" connector")
+	}
+
+	uc.logger.Info("connector state changed", "connector", name, "action", action)
+
+	status, ok := uc.findConnector(name)
+	if !ok {
+		return dto.ErrorConnectorResponse(fmt.Errorf("%w: %s", ports.ErrConnectorNotFound, name)), nil
+	}
+	return dto.SuccessConnectorResponse(connectorDTO(status)), nil
+}
+
+// findConnector looks up the status of a connector by name
+func (uc *AdminUseCase) findConnector(name string) (ports.ConnectorStatus, bool) {
+	for _, status := range uc.connectors.ConnectorStatuses() {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return ports.ConnectorStatus{}, false
+}
+
+// GetRouterStats returns the message throughput of the message router
+func (uc *AdminUseCase) GetRouterStats(ctx context.Context) (*dto.RouterStatsResponse, error) {
+	stats := uc.connectors.Stats()
+
+	return dto.SuccessRouterStatsResponse(&dto.RouterStatsDTO{
+		MessagesReceived:  stats.MessagesReceived,
+		MessagesProcessed: stats.MessagesProcessed,
+		MessagesFailed:    stats.MessagesFailed,
+		ValidationFailed:  stats.ValidationFailed,
+		InFlight:          stats.InFlight,
+		Queued:            stats.Queued,
+		Abandoned:         stats.Abandoned,
+		QueueDepth:        stats.QueueDepth,
+		Connectors:        stats.Connectors,
+	}), nil
+}
+
+// ListDeadLetters returns the messages the router failed to process, newest first
+func (uc *AdminUseCase) ListDeadLetters(ctx context.Context, filter repository.MessageDeadLetterFilter) (*dto.MessageDeadLettersResponse, error) {
+	manager, err := uc.deadLetterManager()
+	if err != nil {
+		return handleMessageDeadLettersError(err, "failed to list dead letters")
+	}
+
+	letters, err := manager.ListDeadLetters(ctx, filter)
+	if err != nil {
+		return handleMessageDeadLettersError(err, "failed to list dead letters")
+	}
+
+	dtos := make([]*dto.MessageDeadLetterDTO, len(letters))
+	for i, letter := range letters {
+		dtos[i] = dto.MessageDeadLetterDTOFromEntity(letter)
+	}
+
+	return dto.SuccessMessageDeadLettersResponse(dtos), nil
+}
+
+// ReprocessDeadLetter routes a dead-lettered message once more and deletes the dead letter on success
+func (uc *AdminUseCase) ReprocessDeadLetter(ctx context.Context, id string) (*dto.DeadLetterResponse, error) {
+	manager, err := uc.deadLetterManager()
+	if err != nil {
+		return handleDeadLetterError(err, "failed to reprocess dead letter")
+	}
+
+	if err := manager.ReprocessDeadLetter(ctx, id); err != nil {
+		return handleDeadLetterError(err, "failed to reprocess dead letter")
+	}
+
+	uc.logger.Info("dead letter reprocessed", "dead_letter_id", id)
+
+	return &dto.DeadLetterResponse{Success: true}, nil
+}
+
+// DeleteDeadLetter discards a dead-lettered message without processing it
+func (uc *AdminUseCase) DeleteDeadLetter(ctx context.Context, id string) (*dto.DeadLetterResponse, error) {
+	manager, err := uc.deadLetterManager()
+	if err != nil {
+		return handleDeadLetterError(err, "failed to delete dead letter")
+	}
+
+	if err := manager.DeleteDeadLetter(ctx, id); err != nil {
+		return handleDeadLetterError(err, "failed to delete dead letter")
+	}
+
+	uc.logger.Info("dead letter discarded", "dead_letter_id", id)
+
+	return &dto.DeadLetterResponse{Success: true}, nil
+}
+
+// deadLetterManager returns the dead-letter queue of the connector manager, if it keeps one
+func (uc *AdminUseCase) deadLetterManager() (ports.MessageDeadLetterManager, error) {
+	manager, ok := uc.connectors.(ports.MessageDeadLetterManager)
+	if !ok {
+		return nil, ports.ErrDeadLettersDisabled
+	}
+	return manager, nil
+}
+
+// ReplayMessage routes a message from the event store again, e.g. after fixing a broken skill.
+// Unless req.Deliver is set, the replies are returned without being sent to the user.
+func (uc *AdminUseCase) ReplayMessage(ctx context.Context, eventID string, req dto.ReplayMessageRequest) (*dto.MessageReplayResponse, error) {
+	replayer, ok := uc.connectors.(ports.MessageReplayer)
+	if !ok {
+		return handleMessageReplayError(ports.ErrReplayDisabled, "failed to replay message")
+	}
+
+	replay, err := replayer.ReplayMessage(ctx, eventID, req.Deliver)
+	if err != nil {
+		return handleMessageReplayError(err, "failed to replay message")
+	}
+
+	uc.logger.Info("message replayed", "event_id", eventID, "delivered", replay.Delivered)
+
+	return &dto.MessageReplayResponse{
+		Success:   true,
+		EventID:   replay.EventID,
+		Connector: replay.Connector,
+		UserID:    replay.UserID,
+		Replies:   replay.Replies,
+		Delivered: replay.Delivered,
+	}, nil
+}
+
+// ListLLMProviders returns the configured LLM providers, sorted by name, with their recent
+// health checks. Without health checks, the availability of the active provider is checked.
+func (uc *AdminUseCase) ListLLMProviders(ctx context.Context) (*dto.LLMProvidersResponse, error) {
+	active := ""
+	var available *bool
+	if status, ok := uc.llmProvider.(ports.LLMProviderStatus); ok {
+		active = status.Name()
+		if uc.llmHealth == nil {
+			isAvailable := status.IsAvailable(ctx)
+			available = &isAvailable
+		}
+	}
+
+	providers := make([]*dto.LLMProviderDTO, 0, len(uc.config.LLMModels)+1)
+	for name, model := range uc.config.LLMModels {
+		provider := &dto.LLMProviderDTO{Name: name, Model: model}
+		if name == active {
+			provider.Active = true
+			provider.Available = available
+		}
+		providers = append(providers, provider)
+	}
+	// The active provider may be a fallback that is not in the configuration, such as the mock provider
+	if _, configured := uc.config.LLMModels[active]; active != "" && !configured {
+		providers = append(providers, &dto.LLMProviderDTO{Name: active, Active: true, Available: available})
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name < providers[j].Name })
+	if uc.llmHealth != nil {
+		for _, provider := range providers {
+			uc.addLLMHealth(provider)
+		}
+	}
+
+	return dto.SuccessLLMProvidersResponse(active, providers), nil
+}
+
+// addLLMHealth adds the health and recent checks of a provider to its DTO
+func (uc *AdminUseCase) addLLMHealth(provider *dto.LLMProviderDTO) {
+	healthy := uc.llmHealth.Healthy(provider.Name)
+	provider.Healthy = &healthy
+
+	history := uc.llmHealth.History(provider.Name)
+	if len(history) == 0 {
+		return
+	}
+	provider.History = make([]*dto.LLMProviderCheckDTO, len(history))
+	for i, check := range history {
+		provider.History[i] = &dto.LLMProviderCheckDTO{
+			CheckedAt: check.CheckedAt,
+			Available: check.Available,
+			LatencyMs: check.Latency.Milliseconds(),
+		}
+	}
+	available := history[len(history)-1].Available
+	provider.Available = &available
+}
+
+// connectorDTO converts a connector status to a DTO
+func connectorDTO(status ports.ConnectorStatus) *dto.ConnectorDTO {
+	return &dto.ConnectorDTO{
+		Name:             status.Name,
+		Running:          status.Running,
+		Pending:          status.Pending,
+		MessagesReceived: status.MessagesReceived,
+	}
+}
+
+// GetInstructions returns the global custom instructions passed to the LLM in every session
+func (uc *AdminUseCase) GetInstructions(ctx context.Context) (*dto.InstructionsResponse, error) {
+	if uc.instructions == nil {
+		return handleInstructionsError(ports.ErrInstructionsDisabled, "failed to get instructions")
+	}
+
+	return dto.SuccessInstructionsResponse(uc.instructions.GlobalInstructions()), nil
+}
+
+// UpdateInstructions replaces the global custom instructions. They last until the server
+// restarts, when the configured instructions apply again.
+func (uc *AdminUseCase) UpdateInstructions(ctx context.Context, req dto.UpdateInstructionsRequest) (*dto.InstructionsResponse, error) {
+	if uc.instructions == nil {
+		return handleInstructionsError(ports.ErrInstructionsDisabled, "failed to update instructions")
+	}
+
+	if err := uc.instructions.SetGlobalInstructions(req.Instructions); err != nil {
+		return dto.ErrorInstructionsResponse(err), nil
+	}
+
+	uc.logger.Info("global instructions changed", "length", len(req.Instructions))
+	return dto.SuccessInstructionsResponse(uc.instructions.GlobalInstructions()), nil
+}
+
+// ListPromptFragments returns the prompt fragments passed to the LLM within their scope,
+// sorted by scope and name
+func (uc *AdminUseCase) ListPromptFragments(ctx context.Context) (*dto.PromptFragmentsResponse, error) {
+	if uc.instructions == nil {
+		return handlePromptFragmentsError(ports.ErrInstructionsDisabled, "failed to list prompt fragments")
+	}
+
+	fragments := uc.instructions.PromptFragments()
+	fragmentDTOs := make([]*dto.PromptFragmentDTO, 0, len(fragments))
+	for _, fragment := range fragments {
+		fragmentDTOs = append(fragmentDTOs, promptFragmentDTO(fragment))
+	}
+	return dto.SuccessPromptFragmentsResponse(fragmentDTOs), nil
+}
+
+// UpdatePromptFragment replaces the prompt fragment of a workspace, connector or skill; an
+// empty prompt removes it. The change lasts until the server restarts, when the configured
+// fragments apply again.
+func (uc *AdminUseCase) UpdatePromptFragment(ctx context.Context, scope, name string, req dto.UpdatePromptFragmentRequest) (*dto.PromptFragmentResponse, error) {
+	if uc.instructions == nil {
+		return handlePromptFragmentError(ports.ErrInstructionsDisabled, "failed to update prompt fragment")
+	}
+
+	name = strings.TrimSpace(name)
+	if err := uc.instructions.SetPromptFragment(scope, name, req.Prompt); err != nil {
+		return dto.ErrorPromptFragmentResponse(err), nil
+	}
+
+	uc.logger.Info("prompt fragment changed", "scope", scope, "name", name, "length", len(req.Prompt))
+	for _, fragment := range uc.instructions.PromptFragments() {
+		if fragment.Scope == scope && fragment.Name == name {
+			return dto.SuccessPromptFragmentResponse(promptFragmentDTO(fragment)), nil
+		}
+	}
+	return dto.SuccessPromptFragmentResponse(nil), nil
+}
+
+// promptFragmentDTO converts a prompt fragment to its DTO
+func promptFragmentDTO(fragment ports.PromptFragment) *dto.PromptFragmentDTO {
+	return &dto.PromptFragmentDTO{
+		Scope:  fragment.Scope,
+		Name:   fragment.Name,
+		Prompt: fragment.Prompt,
+	}
+}
@@ -0,0 +1,414 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// stubConnectorManager is an in-memory implementation of ports.ConnectorManager
+type stubConnectorManager struct {
+	connectors map[string]*ports.ConnectorStatus
+	stats      ports.RouterStats
+	err        error
+}
+
+func newStubConnectorManager(names ...string) *stubConnectorManager {
+	m := &stubConnectorManager{connectors: make(map[string]*ports.ConnectorStatus)}
+	for _, name := range names {
+		m.connectors[name] = &ports.ConnectorStatus{Name: name, Running: true}
+	}
+	return m
+}
+
+func (m *stubConnectorManager) ConnectorStatuses() []ports.ConnectorStatus {
+	statuses := make([]ports.ConnectorStatus, 0, len(m.connectors))
+	for _, name := range []string{"discord", "telegram", "web"} {
+		if status, ok := m.connectors[name]; ok {
+			statuses = append(statuses, *status)
+		}
+	}
+	return statuses
+}
+
+func (m *stubConnectorManager) StartConnector(ctx context.Context, name string) error {
+	return m.setRunning(name, true)
+}
+
+func (m *stubConnectorManager) StopConnector(ctx context.Context, name string) error {
+	return m.setRunning(name, false)
+}
+
+func (m *stubConnectorManager) setRunning(name string, running bool) error {
+	status, ok := m.connectors[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ports.ErrConnectorNotFound, name)
+	}
+	if m.err != nil {
+		return m.err
+	}
+	if status.Running == running {
+		return fmt.Errorf("%w: %s", ports.ErrConnectorState, name)
+	}
+	status.Running = running
+	return nil
+}
+
+func (m *stubConnectorManager) Stats() ports.RouterStats {
+	return m.stats
+}
+
+// stubDeadLetterManager is a connector manager that also keeps message dead letters
+type stubDeadLetterManager struct {
+	*stubConnectorManager
+	letters      []*entity.MessageDeadLetter
+	reprocessErr error
+}
+
+func (m *stubDeadLetterManager) ListDeadLetters(ctx context.Context, filter repository.MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error) {
+	return m.letters, nil
+}
+
+func (m *stubDeadLetterManager) ReprocessDeadLetter(ctx context.Context, id string) error {
+	if m.reprocessErr != nil {
+		return m.reprocessErr
+	}
+	return m.DeleteDeadLetter(ctx, id)
+}
+
+func (m *stubDeadLetterManager) DeleteDeadLetter(ctx context.Context, id string) error {
+	for i, letter := range m.letters {
+		if letter.ID == id {
+			m.letters = append(m.letters[:i], m.letters[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("message dead letter %w: %s", repository.ErrNotFound, id)
+}
+
+// stubMessageReplayer is a connector manager that also replays messages from the event store
+type stubMessageReplayer struct {
+	*stubConnectorManager
+	replay *ports.MessageReplay
+	err    error
+}
+
+func (m *stubMessageReplayer) ReplayMessage(ctx context.Context, eventID string, deliver bool) (*ports.MessageReplay, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	replay := *m.replay
+	replay.EventID = eventID
+	replay.Delivered = deliver
+	return &replay, nil
+}
+
+// stubLLMHealth is a ports.LLMProviderHealth returning fixed checks; a provider is healthy while
+// its last check succeeded
+type stubLLMHealth map[string][]ports.LLMProviderCheck
+
+func (h stubLLMHealth) Healthy(name string) bool {
+	checks := h[name]
+	return len(checks) == 0 || checks[len(checks)-1].Available
+}
+
+func (h stubLLMHealth) History(name string) []ports.LLMProviderCheck {
+	return h[name]
+}
+
+// stubStatusLLMProvider is an LLM provider that reports its name and availability
+type stubStatusLLMProvider struct {
+	MockLLMProvider
+	name      string
+	available bool
+}
+
+func (p *stubStatusLLMProvider) Name() string { return p.name }
+
+func (p *stubStatusLLMProvider) IsAvailable(ctx context.Context) bool { return p.available }
+
+func TestAdminUseCase_Connectors(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	manager := newStubConnectorManager("telegram", "web")
+	uc := NewAdminUseCase(manager, new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+
+	// Act & Assert: list
+	list, err := uc.ListConnectors(ctx)
+	require.NoError(t, err)
+	require.Len(t, list.Connectors, 2)
+	assert.Equal(t, "telegram", list.Connectors[0].Name)
+
+	// Stop a running connector
+	resp, err := uc.StopConnector(ctx, "telegram")
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.False(t, resp.Connector.Running)
+
+	// Stopping it again reports the current state
+	resp, err = uc.StopConnector(ctx, "telegram")
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	require.NotNil(t, resp.Connector)
+	assert.False(t, resp.Connector.Running)
+
+	// Unknown connectors have no state
+	resp, err = uc.StartConnector(ctx, "discord")
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Nil(t, resp.Connector)
+
+	get, err := uc.GetConnector(ctx, "discord")
+	require.NoError(t, err)
+	assert.False(t, get.Success)
+
+	// Other failures are internal errors
+	manager.err = errors.New("bot token revoked")
+	resp, err = uc.StartConnector(ctx, "telegram")
+	assert.Error(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "bot token revoked")
+}
+
+func TestAdminUseCase_GetRouterStats(t *testing.T) {
+	// Arrange
+	manager := newStubConnectorManager("telegram")
+	manager.stats = ports.RouterStats{MessagesReceived: 5, MessagesProcessed: 3, MessagesFailed: 1, QueueDepth: 2, Connectors: 1}
+	uc := NewAdminUseCase(manager, new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+
+	// Act
+	resp, err := uc.GetRouterStats(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, int64(5), resp.Stats.MessagesReceived)
+	assert.Equal(t, int64(3), resp.Stats.MessagesProcessed)
+	assert.Equal(t, 2, resp.Stats.QueueDepth)
+}
+
+func TestAdminUseCase_DeadLetters(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	first := entity.NewMessageDeadLetter("telegram", "tg:42", "100", "Hello", map[string]interface{}{"message_id": 7}, "llm unavailable")
+	second := entity.NewMessageDeadLetter("telegram", "tg:43", "101", "Hi", nil, "timeout")
+	manager := &stubDeadLetterManager{
+		stubConnectorManager: newStubConnectorManager("telegram"),
+		letters:              []*entity.MessageDeadLetter{first, second},
+	}
+	uc := NewAdminUseCase(manager, new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+
+	// Act & Assert: list
+	list, err := uc.ListDeadLetters(ctx, repository.MessageDeadLetterFilter{})
+	require.NoError(t, err)
+	require.Len(t, list.DeadLetters, 2)
+	assert.Equal(t, "Hello", list.DeadLetters[0].Content)
+	assert.Equal(t, float64(7), list.DeadLetters[0].Metadata["message_id"])
+
+	// Reprocess
+	resp, err := uc.ReprocessDeadLetter(ctx, first.ID)
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	manager.reprocessErr = fmt.Errorf("%w: llm unavailable", ports.ErrReprocessFailed)
+	resp, err = uc.ReprocessDeadLetter(ctx, second.ID)
+	assert.ErrorIs(t, err, ports.ErrReprocessFailed)
+	assert.False(t, resp.Success)
+
+	// Discard
+	resp, err = uc.DeleteDeadLetter(ctx, second.ID)
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	_, err = uc.DeleteDeadLetter(ctx, second.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestAdminUseCase_DeadLettersDisabled(t *testing.T) {
+	uc := NewAdminUseCase(newStubConnectorManager(), new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+
+	_, err := uc.ListDeadLetters(context.Background(), repository.MessageDeadLetterFilter{})
+	assert.ErrorIs(t, err, ports.ErrDeadLettersDisabled)
+
+	_, err = uc.ReprocessDeadLetter(context.Background(), "letter-1")
+	assert.ErrorIs(t, err, ports.ErrDeadLettersDisabled)
+}
+
+func TestAdminUseCase_ReplayMessage(t *testing.T) {
+	ctx := context.Background()
+	replayer := &stubMessageReplayer{
+		stubConnectorManager: newStubConnectorManager("telegram"),
+		replay:               &ports.MessageReplay{Connector: "telegram", UserID: "tg:42", Replies: []string{"Here is your digest"}},
+	}
+	uc := NewAdminUseCase(replayer, new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+
+	resp, err := uc.ReplayMessage(ctx, "event-1", dto.ReplayMessageRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "event-1", resp.EventID)
+	assert.Equal(t, "telegram", resp.Connector)
+	assert.Equal(t, []string{"Here is your digest"}, resp.Replies)
+	assert.False(t, resp.Delivered)
+
+	resp, err = uc.ReplayMessage(ctx, "event-1", dto.ReplayMessageRequest{Deliver: true})
+	require.NoError(t, err)
+	assert.True(t, resp.Delivered)
+
+	replayer.err = fmt.Errorf("%w: event event-2 is a router.message event", ports.ErrNotReplayable)
+	resp, err = uc.ReplayMessage(ctx, "event-2", dto.ReplayMessageRequest{})
+	assert.ErrorIs(t, err, ports.ErrNotReplayable)
+	assert.False(t, resp.Success)
+
+	uc = NewAdminUseCase(newStubConnectorManager(), new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+	_, err = uc.ReplayMessage(ctx, "event-1", dto.ReplayMessageRequest{})
+	assert.ErrorIs(t, err, ports.ErrReplayDisabled)
+}
+
+func TestAdminUseCase_Instructions(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	chat := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), logging.NewNoopLogger())
+	uc := NewAdminUseCase(newStubConnectorManager(), new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+
+	_, err := uc.GetInstructions(ctx)
+	assert.ErrorIs(t, err, ports.ErrInstructionsDisabled)
+
+	uc.SetInstructionsManager(chat)
+
+	// Act & Assert: update
+	resp, err := uc.UpdateInstructions(ctx, dto.UpdateInstructionsRequest{Instructions: "Never share secrets."})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "Never share secrets.", chat.GlobalInstructions())
+
+	// Get
+	resp, err = uc.GetInstructions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Never share secrets.", resp.Instructions)
+
+	// Too long instructions are rejected and keep the current ones
+	resp, err = uc.UpdateInstructions(ctx, dto.UpdateInstructionsRequest{Instructions: strings.Repeat("a", entity.MaxInstructionsLength+1)})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "Never share secrets.", chat.GlobalInstructions())
+}
+
+func TestAdminUseCase_PromptFragments(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	chat := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), logging.NewNoopLogger())
+	uc := NewAdminUseCase(newStubConnectorManager(), new(MockLLMProvider), AdminConfig{}, logging.NewNoopLogger())
+
+	_, err := uc.ListPromptFragments(ctx)
+	assert.ErrorIs(t, err, ports.ErrInstructionsDisabled)
+
+	uc.SetInstructionsManager(chat)
+
+	// Act & Assert: update
+	resp, err := uc.UpdatePromptFragment(ctx, ports.PromptScopeConnector, "telegram", dto.UpdatePromptFragmentRequest{Prompt: "Keep replies under 3000 characters."})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, &dto.PromptFragmentDTO{Scope: "connector", Name: "telegram", Prompt: "Keep replies under 3000 characters."}, resp.Fragment)
+
+	// List
+	list, err := uc.ListPromptFragments(ctx)
+	require.NoError(t, err)
+	require.Len(t, list.Fragments, 1)
+	assert.Equal(t, "telegram", list.Fragments[0].Name)
+
+	// Unknown scopes are rejected
+	resp, err = uc.UpdatePromptFragment(ctx, "channel", "telegram", dto.UpdatePromptFragmentRequest{Prompt: "Be brief."})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+
+	// An empty prompt removes the fragment
+	resp, err = uc.UpdatePromptFragment(ctx, ports.PromptScopeConnector, "telegram", dto.UpdatePromptFragmentRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Nil(t, resp.Fragment)
+	assert.Empty(t, chat.PromptFragments())
+}
+
+func TestAdminUseCase_ListLLMProviders(t *testing.T) {
+	config := AdminConfig{LLMModels: map[string]string{"openai": "gpt-4o", "ollama": "llama3"}}
+
+	t.Run("configured provider is active", func(t *testing.T) {
+		provider := &stubStatusLLMProvider{name: "openai", available: false}
+		uc := NewAdminUseCase(newStubConnectorManager(), provider, config, logging.NewNoopLogger())
+
+		resp, err := uc.ListLLMProviders(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "openai", resp.Active)
+		require.Len(t, resp.Providers, 2)
+		assert.Equal(t, "ollama", resp.Providers[0].Name)
+		assert.False(t, resp.Providers[0].Active)
+		assert.Nil(t, resp.Providers[0].Available)
+		assert.True(t, resp.Providers[1].Active)
+		require.NotNil(t, resp.Providers[1].Available)
+		assert.False(t, *resp.Providers[1].Available)
+	})
+
+	t.Run("fallback provider is listed", func(t *testing.T) {
+		provider := &stubStatusLLMProvider{name: "mock", available: true}
+		uc := NewAdminUseCase(newStubConnectorManager(), provider, config, logging.NewNoopLogger())
+
+		resp, err := uc.ListLLMProviders(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "mock", resp.Active)
+		require.Len(t, resp.Providers, 3)
+		assert.Equal(t, "mock", resp.Providers[0].Name)
+		assert.True(t, resp.Providers[0].Active)
+	})
+
+	t.Run("providers with health checks", func(t *testing.T) {
+		provider := &stubStatusLLMProvider{name: "openai", available: true}
+		uc := NewAdminUseCase(newStubConnectorManager(), provider, config, logging.NewNoopLogger())
+		checkedAt := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+		uc.SetLLMHealth(stubLLMHealth{"ollama": {
+			{CheckedAt: checkedAt, Available: true, Latency: 40 * time.Millisecond},
+			{CheckedAt: checkedAt.Add(time.Minute), Available: false, Latency: 10 * time.Second},
+		}})
+
+		resp, err := uc.ListLLMProviders(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, resp.Providers, 2)
+		ollama, openai := resp.Providers[0], resp.Providers[1]
+		require.NotNil(t, ollama.Available)
+		assert.False(t, *ollama.Available, "availability should be the last check")
+		require.NotNil(t, ollama.Healthy)
+		assert.False(t, *ollama.Healthy)
+		require.Len(t, ollama.History, 2)
+		assert.Equal(t, checkedAt, ollama.History[0].CheckedAt)
+		assert.Equal(t, int64(40), ollama.History[0].LatencyMs)
+
+		assert.Nil(t, openai.Available, "a provider not checked yet should have no availability")
+		require.NotNil(t, openai.Healthy)
+		assert.True(t, *openai.Healthy)
+		assert.Empty(t, openai.History)
+	})
+
+	t.Run("provider without status", func(t *testing.T) {
+		uc := NewAdminUseCase(newStubConnectorManager(), new(MockLLMProvider), config, logging.NewNoopLogger())
+
+		resp, err := uc.ListLLMProviders(context.Background())
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Active)
+		assert.Len(t, resp.Providers, 2)
+	})
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+const (
+	// DefaultAnalyticsDays is the number of days returned when no range is requested
+	DefaultAnalyticsDays = 30
+
+	// MaxAnalyticsDays limits the number of days of an analytics request
+	MaxAnalyticsDays = 366
+)
+
+// Errors returned by the AnalyticsUseCase
+var (
+	ErrInvalidAnalyticsDay   = errors.New("since and until must be days in the format YYYY-MM-DD")
+	ErrInvalidAnalyticsRange = fmt.Errorf("until must not be before since, and the range must not exceed %d days", MaxAnalyticsDays)
+)
+
+// AnalyticsUseCase reads the daily counters kept by the analytics collector
+type AnalyticsUseCase struct {
+	analyticsRepo repository.AnalyticsRepository
+	logger        logging.Logger
+}
+
+// NewAnalyticsUseCase creates a new AnalyticsUseCase
+func NewAnalyticsUseCase(analyticsRepo repository.AnalyticsRepository, logger logging.Logger) *AnalyticsUseCase {
+	return &AnalyticsUseCase{
+		analyticsRepo: analyticsRepo,
+		logger:        logger,
+	}
+}
+
+// GetDailyAnalytics returns the counts of every day of the requested range, including days
+// without activity, and the totals of the range
+func (uc *AnalyticsUseCase) GetDailyAnalytics(ctx context.Context, req dto.AnalyticsRequest) (*dto.AnalyticsResponse, error) {
+	since, until, err := analyticsRange(req)
+	if err != nil {
+		return dto.ErrorAnalyticsResponse(err), nil
+	}
+
+	counters, err := uc.analyticsRepo.ListCounters(ctx, since, until)
+	if err != nil {
+		return handleAnalyticsError(err, "failed to list analytics counters")
+	}
+	activeUsers, err := uc.analyticsRepo.CountActiveUsers(ctx, since, until)
+	if err != nil {
+		return handleAnalyticsError(err, "failed to count active users")
+	}
+
+	resp := &dto.AnalyticsResponse{Success: true, Since: since, Until: until, Totals: &dto.AnalyticsCountsDTO{}}
+	days := make(map[string]*dto.AnalyticsCountsDTO)
+	for day := since; day <= until; day = nextAnalyticsDay(day) {
+		counts := &dto.AnalyticsCountsDTO{Day: day}
+		days[day] = counts
+		resp.Days = append(resp.Days, counts)
+	}
+
+	for _, counter := range counters {
+		if counts, ok := days[counter.Day]; ok {
+			addAnalyticsCounter(counts, counter)
+			addAnalyticsCounter(resp.Totals, counter)
+		}
+	}
+	for _, counter := range activeUsers {
+		counts := resp.Totals
+		if counter.Day != "" {
+			if counts = days[counter.Day]; counts == nil {
+				continue
+			}
+		}
+		addAnalyticsCounter(counts, counter)
+	}
+
+	return resp, nil
+}
+
+// GetAnalyticsSummary returns the totals of the requested range
+func (uc *AnalyticsUseCase) GetAnalyticsSummary(ctx context.Context, req dto.AnalyticsRequest) (*dto.AnalyticsResponse, error) {
+	resp, err := uc.GetDailyAnalytics(ctx, req)
+	if resp != nil {
+		resp.Days = nil
+	}
+	return resp, err
+}
+
+// analyticsRange returns the first and last day of a request, defaulting to the last
+// DefaultAnalyticsDays days up to today
+func analyticsRange(req dto.AnalyticsRequest) (string, string, error) {
+	until := utils.Now().UTC().Truncate(24 * time.Hour)
+	if req.Until != "" {
+		parsed, err := time.Parse(entity.AnalyticsDayLayout, req.Until)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidAnalyticsDay, req.Until)
+		}
+		until = parsed
+	}
+
+	since := until.AddDate(0, 0, 1-DefaultAnalyticsDays)
+	if req.Since != "" {
+		parsed, err := time.Parse(entity.AnalyticsDayLayout, req.Since)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidAnalyticsDay, req.Since)
+		}
+		since = parsed
+	}
+
+	if until.Before(since) || until.Sub(since) >= MaxAnalyticsDays*24*time.Hour {
+		return "", "", ErrInvalidAnalyticsRange
+	}
+	return entity.AnalyticsDay(since), entity.AnalyticsDay(until), nil
+}
+
+// nextAnalyticsDay returns the day after a day in entity.AnalyticsDayLayout
+func nextAnalyticsDay(day string) string {
+	t, _ := time.Parse(entity.AnalyticsDayLayout, day)
+	return entity.AnalyticsDay(t.AddDate(0, 0, 1))
+}
+
+// addAnalyticsCounter adds a counter to the counts it belongs to. Counters without a
+// dimension, such as LLM calls of the default model, only add to the overall counts.
+func addAnalyticsCounter(counts *dto.AnalyticsCountsDTO, counter *entity.AnalyticsCounter) {
+	value := int64(counter.Value)
+	switch counter.Metric {
+	case entity.AnalyticsMessages:
+		counts.Messages += value
+		addAnalyticsDimension(&counts.MessagesByConnector, counter.Dimension, value)
+	case entity.AnalyticsActiveUsers:
+		// Active users are counted per day and connector by the repository; they do not add up
+		if counter.Dimension == "" {
+			counts.ActiveUsers = value
+		} else {
+			addAnalyticsDimension(&counts.ActiveUsersByConnector, counter.Dimension, value)
+		}
+	case entity.AnalyticsLLMRequests:
+		counts.LLMRequests += value
+	case entity.AnalyticsLLMErrors:
+		counts.LLMErrors += value
+	case entity.AnalyticsTokens:
+		counts.Tokens += value
+		addAnalyticsDimension(&counts.TokensByModel, counter.Dimension, value)
+	case entity.AnalyticsCost:
+		counts.Cost += counter.Value
+		addAnalyticsDimension(&counts.CostByModel, counter.Dimension, counter.Value)
+	case entity.AnalyticsSkillExecutions:
+		counts.SkillExecutions += value
+		addAnalyticsDimension(&counts.SkillExecutionsBySkill, counter.Dimension, value)
+	case entity.AnalyticsSkillFailures:
+		counts.SkillFailures += value
+	}
+}
+
+// addAnalyticsDimension adds a value to the entry of a dimension, creating the map if needed
+func addAnalyticsDimension[V int64 | float64](values *map[string]V, dimension string, value V) {
+	if dimension == "" {
+		return
+	}
+	if *values == nil {
+		*values = make(map[string]V)
+	}
+	(*values)[dimension] += value
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockAnalyticsRepository is a mock implementation of AnalyticsRepository
+type MockAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRepository) Increment(ctx context.Context, day string, metric entity.AnalyticsMetric, dimension string, delta float64) error {
+	args := m.Called(ctx, day, metric, dimension, delta)
+	return args.Error(0)
+}
+
+func (m *MockAnalyticsRepository) AddActiveUser(ctx context.Context, day, connector, userID string) error {
+	args := m.Called(ctx, day, connector, userID)
+	return args.Error(0)
+}
+
+func (m *MockAnalyticsRepository) ListCounters(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	args := m.Called(ctx, since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.AnalyticsCounter), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) CountActiveUsers(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	args := m.Called(ctx, since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.AnalyticsCounter), args.Error(1)
+}
+
+func TestAnalyticsUseCase_GetDailyAnalytics(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := new(MockAnalyticsRepository)
+	uc := NewAnalyticsUseCase(repo, logging.NewNoopLogger())
+	repo.On("ListCounters", ctx, "2024-01-15", "2024-01-17").Return([]*entity.AnalyticsCounter{
+		{Day: "2024-01-15", Metric: entity.AnalyticsMessages, Dimension: "telegram", Value: 3},
+		{Day: "2024-01-15", Metric: entity.AnalyticsTokens, Dimension: "gpt-4o", Value: 500},
+		{Day: "2024-01-15", Metric: entity.AnalyticsCost, Dimension: "gpt-4o", Value: 0.01},
+		{Day: "2024-01-17", Metric: entity.AnalyticsMessages, Dimension: "discord", Value: 1},
+		{Day: "2024-01-17", Metric: entity.AnalyticsSkillExecutions, Dimension: "weather", Value: 2},
+		{Day: "2024-01-17", Metric: entity.AnalyticsSkillFailures, Dimension: "weather", Value: 1},
+	}, nil)
+	repo.On("CountActiveUsers", ctx, "2024-01-15", "2024-01-17").Return([]*entity.AnalyticsCounter{
+		{Day: "2024-01-15", Metric: entity.AnalyticsActiveUsers, Dimension: "telegram", Value: 2},
+		{Day: "2024-01-15", Metric: entity.AnalyticsActiveUsers, Value: 2},
+		{Day: "2024-01-17", Metric: entity.AnalyticsActiveUsers, Dimension: "discord", Value: 1},
+		{Day: "2024-01-17", Metric: entity.AnalyticsActiveUsers, Value: 1},
+		{Metric: entity.AnalyticsActiveUsers, Dimension: "discord", Value: 1},
+		{Metric: entity.AnalyticsActiveUsers, Dimension: "telegram", Value: 2},
+		{Metric: entity.AnalyticsActiveUsers, Value: 3},
+	}, nil)
+
+	// Act
+	resp, err := uc.GetDailyAnalytics(ctx, dto.AnalyticsRequest{Since: "2024-01-15", Until: "2024-01-17"})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	require.Len(t, resp.Days, 3)
+	assert.Equal(t, &dto.AnalyticsCountsDTO{
+		Day:                    "2024-01-15",
+		Messages:               3,
+		ActiveUsers:            2,
+		Tokens:                 500,
+		Cost:                   0.01,
+		MessagesByConnector:    map[string]int64{"telegram": 3},
+		ActiveUsersByConnector: map[string]int64{"telegram": 2},
+		TokensByModel:          map[string]int64{"gpt-4o": 500},
+		CostByModel:            map[string]float64{"gpt-4o": 0.01},
+	}, resp.Days[0])
+	assert.Equal(t, &dto.AnalyticsCountsDTO{Day: "2024-01-16"}, resp.Days[1])
+	assert.Equal(t, int64(2), resp.Days[2].SkillExecutions)
+
+	assert.Equal(t, int64(4), resp.Totals.Messages)
+	assert.Equal(t, int64(3), resp.Totals.ActiveUsers)
+	assert.Equal(t, map[string]int64{"discord": 1, "telegram": 2}, resp.Totals.ActiveUsersByConnector)
+	assert.Equal(t, map[string]int64{"discord": 1, "telegram": 3}, resp.Totals.MessagesByConnector)
+	assert.Equal(t, int64(1), resp.Totals.SkillFailures)
+}
+
+func TestAnalyticsUseCase_GetAnalyticsSummary(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockAnalyticsRepository)
+	uc := NewAnalyticsUseCase(repo, logging.NewNoopLogger())
+	repo.On("ListCounters", ctx, mock.Anything, mock.Anything).Return([]*entity.AnalyticsCounter{}, nil)
+	repo.On("CountActiveUsers", ctx, mock.Anything, mock.Anything).Return([]*entity.AnalyticsCounter{}, nil)
+
+	resp, err := uc.GetAnalyticsSummary(ctx, dto.AnalyticsRequest{})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Nil(t, resp.Days)
+	assert.NotNil(t, resp.Totals)
+	// Without a range, the last 30 days are summed
+	since := repo.Calls[0].Arguments.String(1)
+	until := repo.Calls[0].Arguments.String(2)
+	assert.Equal(t, until, resp.Until)
+	assert.Equal(t, since, resp.Since)
+	assert.Equal(t, until, nextAnalyticsDayN(since, DefaultAnalyticsDays-1))
+}
+
+func TestAnalyticsUseCase_GetDailyAnalytics_Invalid(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockAnalyticsRepository)
+	uc := NewAnalyticsUseCase(repo, logging.NewNoopLogger())
+
+	resp, err := uc.GetDailyAnalytics(ctx, dto.AnalyticsRequest{Since: "15.01.2024"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, ErrInvalidAnalyticsDay.Error())
+
+	resp, err = uc.GetDailyAnalytics(ctx, dto.AnalyticsRequest{Since: "2024-01-17", Until: "2024-01-15"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+
+	resp, err = uc.GetDailyAnalytics(ctx, dto.AnalyticsRequest{Since: "2023-01-01", Until: "2024-12-31"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, ErrInvalidAnalyticsRange.Error())
+
+	repo.AssertNotCalled(t, "ListCounters")
+}
+
+func TestAnalyticsUseCase_GetDailyAnalytics_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockAnalyticsRepository)
+	uc := NewAnalyticsUseCase(repo, logging.NewNoopLogger())
+	repo.On("ListCounters", ctx, "2024-01-15", "2024-01-15").Return(nil, errors.New("database is locked"))
+
+	resp, err := uc.GetDailyAnalytics(ctx, dto.AnalyticsRequest{Since: "2024-01-15", Until: "2024-01-15"})
+
+	assert.Error(t, err)
+	assert.False(t, resp.Success)
+}
+
+// nextAnalyticsDayN returns the day n days after a day
+func nextAnalyticsDayN(day string, n int) string {
+	for i := 0; i < n; i++ {
+		day = nextAnalyticsDay(day)
+	}
+	return day
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// ErrInvalidAPIKey is returned when a presented API key is unknown or revoked
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyUseCase handles the HTTP API keys managed through the admin API
+type APIKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	logger     logging.Logger
+}
+
+// NewAPIKeyUseCase creates a new APIKeyUseCase
+func NewAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, logger logging.Logger) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		logger:     logger,
+	}
+}
+
+// CreateKey generates a new API key. The plaintext key is only returned in this response.
+func (uc *APIKeyUseCase) CreateKey(ctx context.Context, req dto.CreateAPIKeyRequest) (*dto.APIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return dto.ErrorAPIKeyResponse(fmt.Errorf("name is required")), nil
+	}
+
+	scope := valueobject.APIKeyScopeRead
+	if req.Scope != "" {
+		var err error
+		if scope, err = valueobject.NewAPIKeyScope(req.Scope); err != nil {
+			return dto.ErrorAPIKeyResponse(fmt.Errorf("%w: %q", err, req.Scope)), nil
+		}
+	}
+
+	workspace := valueobject.WorkspaceID(req.Workspace)
+	if !workspace.IsEmpty() && !workspace.IsValid() {
+		return dto.ErrorAPIKeyResponse(fmt.Errorf("%w: %q", ErrInvalidWorkspaceID, req.Workspace)), nil
+	}
+
+	apiKey, key := entity.NewAPIKey(name, scope)
+	apiKey.WorkspaceID = workspace
+	if err := uc.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return handleAPIKeyError(err, "failed to create api key")
+	}
+
+	uc.logger.Info("api key created", "id", apiKey.ID, "name", apiKey.Name, "scope", apiKey.Scope, "workspace", apiKey.WorkspaceID)
+
+	resp := dto.SuccessAPIKeyResponse(dto.APIKeyDTOFromEntity(apiKey))
+	resp.Key = key
+	return resp, nil
+}
+
+// ListKeys returns all API keys, including revoked ones, oldest first
+func (uc *APIKeyUseCase) ListKeys(ctx context.Context) (*dto.APIKeysResponse, error) {
+	apiKeys, err := uc.apiKeyRepo.List(ctx)
+	if err != nil {
+		return dto.ErrorAPIKeysResponse(err), err
+	}
+
+	apiKeyDTOs := make([]*dto.APIKeyDTO, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		apiKeyDTOs = append(apiKeyDTOs, dto.APIKeyDTOFromEntity(apiKey))
+	}
+
+	return dto.SuccessAPIKeysResponse(apiKeyDTOs), nil
+}
+
+// RevokeKey revokes an active API key. Revoked keys are kept for auditing.
+func (uc *APIKeyUseCase) RevokeKey(ctx context.Context, id string) (*dto.APIKeyResponse, error) {
+	revoked, err := uc.apiKeyRepo.Revoke(ctx, id, utils.Now())
+	if err != nil {
+		return handleAPIKeyError(err, "failed to revoke api key")
+	}
+	if !revoked {
+		return dto.ErrorAPIKeyResponse(fmt.Errorf("api key not found: %s", id)), nil
+	}
+
+	uc.logger.Info("api key revoked", "id", id)
+
+	return &dto.APIKeyResponse{Success: true}, nil
+}
+
+// Authenticate returns the scope of an active API key and the workspace it is bound to,
+// empty for keys of all workspaces. Unknown and revoked keys are rejected with ErrInvalidAPIKey.
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, key string) (valueobject.APIKeyScope, valueobject.WorkspaceID, error) {
+	apiKey, err := uc.apiKeyRepo.GetByHash(ctx, entity.HashAPIKey(key))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidAPIKey, err)
+	}
+	if apiKey.IsRevoked() {
+		return "", "", fmt.Errorf("%w: key %s is revoked", ErrInvalidAPIKey, apiKey.ID)
+	}
+
+	return apiKey.Scope, apiKey.WorkspaceID, nil
+}
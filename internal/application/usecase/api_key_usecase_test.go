@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, apiKey *entity.APIKey) error {
+	args := m.Called(ctx, apiKey)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestAPIKeyUseCase_CreateKey(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockAPIKeyRepository)
+	mockLogger := new(MockLogger)
+	uc := NewAPIKeyUseCase(mockRepo, mockLogger)
+
+	var saved *entity.APIKey
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.APIKey")).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*entity.APIKey)
+	}).Return(nil)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	resp, err := uc.CreateKey(ctx, dto.CreateAPIKeyRequest{Name: "dashboard", Scope: "admin"})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, "admin", resp.APIKey.Scope)
+	assert.NotEmpty(t, resp.Key)
+	require.NotNil(t, saved)
+	assert.Equal(t, entity.HashAPIKey(resp.Key), saved.KeyHash)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyUseCase_CreateKey_Validation(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockAPIKeyRepository)
+	uc := NewAPIKeyUseCase(mockRepo, new(MockLogger))
+
+	// Act
+	noName, err := uc.CreateKey(ctx, dto.CreateAPIKeyRequest{Name: " "})
+	require.NoError(t, err)
+	badScope, err := uc.CreateKey(ctx, dto.CreateAPIKeyRequest{Name: "ci", Scope: "owner"})
+	require.NoError(t, err)
+	badWorkspace, err := uc.CreateKey(ctx, dto.CreateAPIKeyRequest{Name: "ci", Workspace: "support team"})
+	require.NoError(t, err)
+
+	// Assert
+	assert.False(t, noName.Success)
+	assert.False(t, badScope.Success)
+	assert.Contains(t, badScope.Error, "invalid api key scope")
+	assert.False(t, badWorkspace.Success)
+	assert.Contains(t, badWorkspace.Error, ErrInvalidWorkspaceID.Error())
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyUseCase_RevokeKey(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockAPIKeyRepository)
+	mockLogger := new(MockLogger)
+	uc := NewAPIKeyUseCase(mockRepo, mockLogger)
+
+	mockRepo.On("Revoke", ctx, "key-1", mock.AnythingOfType("time.Time")).Return(true, nil)
+	mockRepo.On("Revoke", ctx, "missing", mock.AnythingOfType("time.Time")).Return(false, nil)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	resp, err := uc.RevokeKey(ctx, "key-1")
+	require.NoError(t, err)
+	missing, err := uc.RevokeKey(ctx, "missing")
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, resp.Success)
+	assert.False(t, missing.Success)
+	assert.Contains(t, missing.Error, "api key not found")
+}
+
+func TestAPIKeyUseCase_Authenticate(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockAPIKeyRepository)
+	uc := NewAPIKeyUseCase(mockRepo, new(MockLogger))
+
+	active, activeKey := entity.NewAPIKey("ci", valueobject.APIKeyScopeRead)
+	revoked, revokedKey := entity.NewAPIKey("old", valueobject.APIKeyScopeAdmin)
+	revoked.Revoke()
+
+	mockRepo.On("GetByHash", ctx, active.KeyHash).Return(active, nil)
+	mockRepo.On("GetByHash", ctx, revoked.KeyHash).Return(revoked, nil)
+	mockRepo.On("GetByHash", ctx, entity.HashAPIKey("unknown")).Return(nil, errors.New("api key not found"))
+
+	// Act
+	scope, workspace, err := uc.Authenticate(ctx, activeKey)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.APIKeyScopeRead, scope)
+	assert.True(t, workspace.IsEmpty())
+
+	_, _, err = uc.Authenticate(ctx, revokedKey)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, _, err = uc.Authenticate(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Backup file names are "nexflow-<UTC timestamp>.backup", with ".gz" when compressed.
+// The timestamp format sorts lexically in creation order.
+const (
+	backupPrefix          = "nexflow-"
+	backupExtension       = ".backup"
+	backupGzipExtension   = ".gz"
+	backupTimestampFormat = "20060102T150405Z"
+)
+
+// ErrInvalidBackupName is returned when a backup name does not refer to a file in the backup directory
+var ErrInvalidBackupName = errors.New("invalid backup name")
+
+// BackupConfig holds configuration for the BackupUseCase
+type BackupConfig struct {
+	// Dir is the directory backups are written to
+	Dir string
+
+	// Compress gzip-compresses backups unless a request overrides it
+	Compress bool
+
+	// Keep is the number of newest backups kept after a new backup is created (0 = keep all)
+	Keep int
+}
+
+// BackupUseCase handles creating, listing and restoring database backups
+type BackupUseCase struct {
+	backup ports.DatabaseBackup
+	config BackupConfig
+	logger logging.Logger
+	now    func() time.Time
+}
+
+// NewBackupUseCase creates a new BackupUseCase
+func NewBackupUseCase(backup ports.DatabaseBackup, config BackupConfig, logger logging.Logger) *BackupUseCase {
+	return &BackupUseCase{
+		backup: backup,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// CreateBackup writes a new backup to the backup directory and removes the oldest
+// backups beyond the configured number to keep
+func (uc *BackupUseCase) CreateBackup(ctx context.Context, req dto.CreateBackupRequest) (*dto.BackupResponse, error) {
+	compress := uc.config.Compress
+	if req.Compress != nil {
+		compress = *req.Compress
+	}
+
+	if err := os.MkdirAll(uc.config.Dir, 0o750); err != nil {
+		return handleBackupError(err, "failed to create backup directory")
+	}
+
+	name := backupPrefix + uc.now().UTC().Format(backupTimestampFormat) + backupExtension
+	if compress {
+		name += backupGzipExtension
+	}
+	path := filepath.Join(uc.config.Dir, name)
+
+	if err := uc.backup.Backup(ctx, path, compress); err != nil {
+		return handleBackupError(err, "failed to create backup")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return handleBackupError(err, "failed to read backup")
+	}
+
+	uc.prune()
+
+	return dto.SuccessBackupResponse(backupDTO(info)), nil
+}
+
+// ListBackups returns the backups in the backup directory, newest first
+func (uc *BackupUseCase) ListBackups(ctx context.Context) (*dto.BackupsResponse, error) {
+	backups, err := uc.list()
+	if err != nil {
+		return dto.ErrorBackupsResponse(fmt.Errorf("failed to list backups: %w", err)), fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	dtos := make([]*dto.BackupDTO, len(backups))
+	for i, info := range backups {
+		dtos[i] = backupDTO(info)
+	}
+
+	return dto.SuccessBackupsResponse(dtos), nil
+}
+
+// RestoreBackup replaces the database contents with a backup from the backup directory
+func (uc *BackupUseCase) RestoreBackup(ctx context.Context, req dto.RestoreBackupRequest) (*dto.BackupResponse, error) {
+	if !isBackupName(req.Name) {
+		return dto.ErrorBackupResponse(fmt.Errorf("%w: %q", ErrInvalidBackupName, req.Name)), nil
+	}
+
+	path := filepath.Join(uc.config.Dir, req.Name)
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return dto.ErrorBackupResponse(fmt.Errorf("backup not found: %s", req.Name)), nil
+		}
+		return handleBackupError(err, "failed to read backup")
+	}
+
+	uc.logger.Warn("restoring database backup", "name", req.Name)
+
+	if err := uc.backup.Restore(ctx, path); err != nil {
+		return handleBackupError(err, "failed to restore backup")
+	}
+
+	return dto.SuccessBackupResponse(backupDTO(info)), nil
+}
+
+// RunScheduledBackup creates a backup with the configured compression.
+// It is registered as a scheduler job for automatic backups.
+func (uc *BackupUseCase) RunScheduledBackup(ctx context.Context) error {
+	resp, err := uc.CreateBackup(ctx, dto.CreateBackupRequest{})
+	if err != nil {
+		return err
+	}
+
+	uc.logger.Info("scheduled backup created", "name", resp.Backup.Name, "size", resp.Backup.Size)
+	return nil
+}
+
+// list returns the backup files in the backup directory, newest first
+func (uc *BackupUseCase) list() ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(uc.config.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !isBackupName(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, info)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name() > backups[j].Name()
+	})
+
+	return backups, nil
+}
+
+// prune removes the oldest backups beyond the configured number to keep
+func (uc *BackupUseCase) prune() {
+	if uc.config.Keep <= 0 {
+		return
+	}
+
+	backups, err := uc.list()
+	if err != nil {
+		uc.logger.Error("failed to list backups for pruning", "error", err)
+		return
+	}
+
+	for i := uc.config.Keep; i < len(backups); i++ {
+		if err := os.Remove(filepath.Join(uc.config.Dir, backups[i].Name())); err != nil {
+			uc.logger.Error("failed to remove old backup", "name", backups[i].Name(), "error", err)
+			continue
+		}
+		uc.logger.Info("removed old backup", "name", backups[i].Name())
+	}
+}
+
+// isBackupName reports whether name is a plain backup file name without path elements
+func isBackupName(name string) bool {
+	if name == "" || filepath.Base(name) != name || !strings.HasPrefix(name, backupPrefix) {
+		return false
+	}
+	return strings.HasSuffix(name, backupExtension) || strings.HasSuffix(name, backupExtension+backupGzipExtension)
+}
+
+// backupDTO converts backup file info to BackupDTO
+func backupDTO(info os.FileInfo) *dto.BackupDTO {
+	return &dto.BackupDTO{
+		Name:       info.Name(),
+		Size:       info.Size(),
+		Compressed: strings.HasSuffix(info.Name(), backupGzipExtension),
+		CreatedAt:  info.ModTime().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockDatabaseBackup is a mock implementation of ports.DatabaseBackup that writes placeholder files
+type MockDatabaseBackup struct {
+	backups  []string
+	restored []string
+	err      error
+}
+
+func (m *MockDatabaseBackup) Backup(ctx context.Context, path string, compress bool) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.backups = append(m.backups, path)
+	return os.WriteFile(path, []byte("backup"), 0o600)
+}
+
+func (m *MockDatabaseBackup) Restore(ctx context.Context, path string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.restored = append(m.restored, path)
+	return nil
+}
+
+func newTestBackupUseCase(t *testing.T, backup *MockDatabaseBackup, keep int) (*BackupUseCase, *time.Time) {
+	t.Helper()
+	now := time.Date(2024, time.January, 15, 3, 0, 0, 0, time.UTC)
+	uc := NewBackupUseCase(backup, BackupConfig{Dir: filepath.Join(t.TempDir(), "backups"), Compress: true, Keep: keep}, logging.NewNoopLogger())
+	uc.now = func() time.Time { return now }
+	return uc, &now
+}
+
+func TestBackupUseCase_CreateBackup(t *testing.T) {
+	ctx := context.Background()
+	backup := &MockDatabaseBackup{}
+	uc, _ := newTestBackupUseCase(t, backup, 0)
+
+	resp, err := uc.CreateBackup(ctx, dto.CreateBackupRequest{})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, "nexflow-20240115T030000Z.backup.gz", resp.Backup.Name)
+	assert.True(t, resp.Backup.Compressed)
+	assert.Equal(t, int64(len("backup")), resp.Backup.Size)
+
+	compress := false
+	resp, err = uc.CreateBackup(ctx, dto.CreateBackupRequest{Compress: &compress})
+	require.NoError(t, err)
+	assert.Equal(t, "nexflow-20240115T030000Z.backup", resp.Backup.Name)
+	assert.False(t, resp.Backup.Compressed)
+}
+
+func TestBackupUseCase_CreateBackupFailure(t *testing.T) {
+	uc, _ := newTestBackupUseCase(t, &MockDatabaseBackup{err: errors.New("disk full")}, 0)
+
+	resp, err := uc.CreateBackup(context.Background(), dto.CreateBackupRequest{})
+	require.Error(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "disk full")
+}
+
+func TestBackupUseCase_PrunesOldBackups(t *testing.T) {
+	ctx := context.Background()
+	uc, now := newTestBackupUseCase(t, &MockDatabaseBackup{}, 2)
+
+	for i := 0; i < 3; i++ {
+		_, err := uc.CreateBackup(ctx, dto.CreateBackupRequest{})
+		require.NoError(t, err)
+		*now = now.Add(24 * time.Hour)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(uc.config.Dir, "notes.txt"), []byte("keep"), 0o600))
+
+	resp, err := uc.ListBackups(ctx)
+	require.NoError(t, err)
+	require.Len(t, resp.Backups, 2)
+	assert.Equal(t, "nexflow-20240117T030000Z.backup.gz", resp.Backups[0].Name)
+	assert.Equal(t, "nexflow-20240116T030000Z.backup.gz", resp.Backups[1].Name)
+	assert.FileExists(t, filepath.Join(uc.config.Dir, "notes.txt"))
+}
+
+func TestBackupUseCase_ListBackupsWithoutDirectory(t *testing.T) {
+	uc, _ := newTestBackupUseCase(t, &MockDatabaseBackup{}, 0)
+
+	resp, err := uc.ListBackups(context.Background())
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Empty(t, resp.Backups)
+}
+
+func TestBackupUseCase_RestoreBackup(t *testing.T) {
+	ctx := context.Background()
+	backup := &MockDatabaseBackup{}
+	uc, _ := newTestBackupUseCase(t, backup, 0)
+
+	created, err := uc.CreateBackup(ctx, dto.CreateBackupRequest{})
+	require.NoError(t, err)
+
+	resp, err := uc.RestoreBackup(ctx, dto.RestoreBackupRequest{Name: created.Backup.Name})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, []string{filepath.Join(uc.config.Dir, created.Backup.Name)}, backup.restored)
+
+	for _, name := range []string{"", "../nexflow-20240115T030000Z.backup", "config.yml", "nexflow-missing.backup"} {
+		resp, err := uc.RestoreBackup(ctx, dto.RestoreBackupRequest{Name: name})
+		require.NoError(t, err, name)
+		assert.False(t, resp.Success, name)
+	}
+	assert.Len(t, backup.restored, 1)
+}
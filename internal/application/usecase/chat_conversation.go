@@ -2,13 +2,25 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
 )
 
-// GetConversation retrieves conversation history for a session
-func (uc *ChatUseCase) GetConversation(ctx context.Context, sessionID string) (*dto.MessagesResponse, error) {
-	messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID)
+// GetConversation retrieves a page of conversation history for a session, oldest first
+func (uc *ChatUseCase) GetConversation(ctx context.Context, sessionID string, page dto.PageRequest) (*dto.MessagesResponse, error) {
+	opts, err := queryOptionsFromPage(page)
+	if err != nil {
+		return dto.ErrorMessageResponse(err), nil
+	}
+
+	if err := uc.checkSessionWorkspace(ctx, sessionID); err != nil {
+		return handleMessagesError(err, "failed to find session")
+	}
+
+	messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID, opts)
 	if err != nil {
 		return handleMessagesError(err, "failed to get conversation")
 	}
@@ -18,5 +30,42 @@ func (uc *ChatUseCase) GetConversation(ctx context.Context, sessionID string) (*
 		messageDTOs = append(messageDTOs, dto.MessageDTOFromEntity(msg))
 	}
 
-	return dto.SuccessMessagesResponse(messageDTOs), nil
+	resp := dto.SuccessMessagesResponse(messageDTOs)
+	if n := len(messages); n > 0 {
+		resp.NextCursor = nextPageCursor(n, opts, messages[n-1].CreatedAt, string(messages[n-1].ID))
+	}
+	return resp, nil
+}
+
+// SearchMessages retrieves a page of messages whose content matches the query, newest first
+func (uc *ChatUseCase) SearchMessages(ctx context.Context, req dto.SearchMessagesRequest, page dto.PageRequest) (*dto.MessagesResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return dto.ErrorMessageResponse(fmt.Errorf("query is required")), nil
+	}
+
+	opts, err := queryOptionsFromPage(page)
+	if err != nil {
+		return dto.ErrorMessageResponse(err), nil
+	}
+
+	messages, err := uc.messageRepo.Search(ctx, repository.MessageSearch{
+		Query:     req.Query,
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+		Workspace: string(WorkspaceFromContext(ctx)),
+	}, opts)
+	if err != nil {
+		return handleMessagesError(err, "failed to search messages")
+	}
+
+	messageDTOs := make([]*dto.MessageDTO, 0, len(messages))
+	for _, msg := range messages {
+		messageDTOs = append(messageDTOs, dto.MessageDTOFromEntity(msg))
+	}
+
+	resp := dto.SuccessMessagesResponse(messageDTOs)
+	if n := len(messages); n > 0 {
+		resp.NextCursor = nextPageCursor(n, opts, messages[n-1].CreatedAt, string(messages[n-1].ID))
+	}
+	return resp, nil
 }
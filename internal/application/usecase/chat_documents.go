@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// documentExcerptsIntro introduces the excerpts of the documents of a session to the LLM
+const documentExcerptsIntro = "The user shared documents in this conversation. These excerpts of them may help answer the last message; cite the document when you use them and say so when they do not contain the answer."
+
+// SetDocumentRetriever sets the retriever of the documents shared in sessions. When set, the
+// excerpts of the documents of a session closest to the last user message are passed to the LLM.
+func (uc *ChatUseCase) SetDocumentRetriever(retriever ports.DocumentRetriever) {
+	uc.documents = retriever
+}
+
+// documentExcerpts returns the excerpts of the documents of a session relevant to the last user
+// message of the conversation, or an empty string if there are none
+func (uc *ChatUseCase) documentExcerpts(ctx context.Context, session *entity.Session, messages []ports.Message) string {
+	if uc.documents == nil {
+		return ""
+	}
+
+	var query string
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			query = messages[i].Content
+			break
+		}
+	}
+	if strings.TrimSpace(query) == "" {
+		return ""
+	}
+
+	chunks, err := uc.documents.RetrieveDocuments(ctx, string(session.ID), query)
+	if err != nil {
+		uc.logger.Warn("failed to retrieve documents", "session_id", session.ID, "error", err)
+		return ""
+	}
+	if len(chunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(documentExcerptsIntro)
+	for _, chunk := range chunks {
+		fmt.Fprintf(&b, "\n\n[%s, part %d]\n%s", chunk.DocumentName, chunk.Position+1, chunk.Content)
+	}
+	return b.String()
+}
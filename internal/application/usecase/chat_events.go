@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+)
+
+// SetEventBus sets the event bus LLM calls, skill executions and task transitions are published to.
+// Without it, no events are published.
+func (uc *ChatUseCase) SetEventBus(eventBus *eventbus.EventBus) {
+	uc.eventBus = eventBus
+}
+
+// SetTokenizer sets the tokenizer counting the tokens of streamed completions, which providers
+// do not report. Without it, they are estimated at four characters per token.
+func (uc *ChatUseCase) SetTokenizer(tokenizer ports.Tokenizer) {
+	uc.tokenizer = tokenizer
+}
+
+// publishLLMEvent publishes an EventLLMResponse event for a completed LLM call of a session, or an
+// EventLLMError event if err is set. The cost is estimated by the provider from the tokens used.
+// A completed call is also published as an EventLLMUsage event with the user and workspace of
+// the session, for billing.
+func (uc *ChatUseCase) publishLLMEvent(session *entity.Session, options dto.MessageOptions, tokens ports.Tokens, duration time.Duration, err error) {
+	if uc.eventBus == nil {
+		return
+	}
+
+	eventType := eventbus.EventLLMResponse
+	cost := 0.0
+	if err != nil {
+		eventType = eventbus.EventLLMError
+	} else if estimate, costErr := uc.llmProvider.EstimateCost(ports.CompletionRequest{Model: options.Model, MaxTokens: tokens.TotalTokens}); costErr == nil {
+		cost = estimate
+	}
+
+	event := eventbus.NewLLMEvent(eventType, uc.providerName(), options.Model, tokens.TotalTokens, cost, duration, err)
+	event.SetMetadataValue("session_id", session.ID.String())
+	uc.eventBus.Publish(event)
+
+	if err != nil {
+		return
+	}
+	provider := options.Provider
+	if provider == "" {
+		provider = uc.providerName()
+	}
+	uc.eventBus.Publish(eventbus.NewUsageEvent(eventbus.EventLLMUsage, session.UserID.String(), session.WorkspaceID.String(), session.ID.String(),
+		provider, options.Model, tokens.InputTokens, tokens.OutputTokens, tokens.TotalTokens, cost))
+}
+
+// publishSkillEvent publishes an EventSkillCompleted event for a successful skill execution,
+// or an EventSkillFailed event otherwise
+func (uc *ChatUseCase) publishSkillEvent(sessionID, skillName, input, output string, err error, duration time.Duration) {
+	if uc.eventBus == nil {
+		return
+	}
+
+	eventType := eventbus.EventSkillCompleted
+	if err != nil {
+		eventType = eventbus.EventSkillFailed
+	}
+
+	event := eventbus.NewSkillEvent(eventType, skillName, input, output, err, duration)
+	event.SetMetadataValue("session_id", sessionID)
+	uc.eventBus.Publish(event)
+}
+
+// publishTaskEvents publishes the status transitions recorded on the task as task events.
+// Each event carries the time the task spent in its previous status.
+func (uc *ChatUseCase) publishTaskEvents(task *entity.Task) {
+	changes := task.PullEvents()
+	if uc.eventBus == nil {
+		return
+	}
+
+	for _, change := range changes {
+		event := eventbus.NewTaskEvent(taskEventType(change.To), string(task.ID), string(task.SessionID), task.Skill, string(change.To), task.Input, task.Output, task.Error)
+		event.Duration = change.Elapsed
+		uc.eventBus.Publish(event)
+	}
+}
+
+// taskEventType returns the event type published when a task moves to the status
+func taskEventType(status valueobject.TaskStatus) string {
+	switch status {
+	case valueobject.TaskStatusRunning:
+		return eventbus.EventTaskStarted
+	case valueobject.TaskStatusCompleted:
+		return eventbus.EventTaskCompleted
+	case valueobject.TaskStatusCancelled:
+		return eventbus.EventTaskCancelled
+	default:
+		return eventbus.EventTaskFailed
+	}
+}
+
+// providerName returns the name of the LLM provider if it reports one
+func (uc *ChatUseCase) providerName() string {
+	if named, ok := uc.llmProvider.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return ""
+}
+
+// completionTokens counts the tokens of a streamed completion of a model
+func (uc *ChatUseCase) completionTokens(model string, messages []ports.Message, reply string) ports.Tokens {
+	if uc.tokenizer == nil {
+		return estimateTokens(messages, reply)
+	}
+
+	tokens := ports.Tokens{InputTokens: uc.tokenizer.CountMessages(model, messages), OutputTokens: uc.tokenizer.Count(model, reply)}
+	tokens.TotalTokens = tokens.InputTokens + tokens.OutputTokens
+	return tokens
+}
+
+// estimateTokens roughly estimates the tokens of a streamed completion, which providers
+// do not report, at four characters per token
+func estimateTokens(messages []ports.Message, reply string) ports.Tokens {
+	chars := 0
+	for _, message := range messages {
+		chars += len(message.Content)
+	}
+	tokens := ports.Tokens{InputTokens: (chars + 3) / 4, OutputTokens: (len(reply) + 3) / 4}
+	tokens.TotalTokens = tokens.InputTokens + tokens.OutputTokens
+	return tokens
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// ErrInvalidExportFormat is returned when a session export format is not supported
+var ErrInvalidExportFormat = errors.New("format must be json or markdown")
+
+// ExportSession renders a transcript of a session with all its messages and tasks,
+// including skill outputs. An empty format defaults to JSON.
+func (uc *ChatUseCase) ExportSession(ctx context.Context, sessionID, format string) (*dto.SessionExportResponse, error) {
+	if format == "" {
+		format = dto.ExportFormatJSON
+	}
+	if format != dto.ExportFormatJSON && format != dto.ExportFormatMarkdown {
+		return dto.ErrorSessionExportResponse(fmt.Errorf("%w: %q", ErrInvalidExportFormat, format)), nil
+	}
+
+	session, err := uc.findSession(ctx, sessionID)
+	if err != nil {
+		return handleSessionExportError(err, "failed to find session")
+	}
+
+	messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{})
+	if err != nil {
+		return handleSessionExportError(err, "failed to get session messages")
+	}
+
+	tasks, err := uc.taskRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{})
+	if err != nil {
+		return handleSessionExportError(err, "failed to get session tasks")
+	}
+
+	transcript := &dto.SessionTranscriptDTO{
+		Session:    dto.SessionDTOFromEntity(session),
+		Messages:   make([]*dto.MessageDTO, 0, len(messages)),
+		Tasks:      make([]*dto.TaskDTO, 0, len(tasks)),
+		ExportedAt: utils.FormatTimeRFC3339(utils.Now()),
+	}
+	for _, msg := range messages {
+		transcript.Messages = append(transcript.Messages, dto.MessageDTOFromEntity(msg))
+	}
+	// Tasks are listed newest first; the transcript reads oldest first
+	for i := len(tasks) - 1; i >= 0; i-- {
+		transcript.Tasks = append(transcript.Tasks, dto.TaskDTOFromEntity(tasks[i]))
+	}
+
+	if format == dto.ExportFormatMarkdown {
+		return dto.SuccessSessionExportResponse(&dto.SessionExportDTO{
+			FileName:    "session-" + sessionID + ".md",
+			ContentType: "text/markdown; charset=utf-8",
+			Content:     []byte(renderTranscriptMarkdown(transcript)),
+		}), nil
+	}
+
+	content, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return handleSessionExportError(err, "failed to encode transcript")
+	}
+
+	return dto.SuccessSessionExportResponse(&dto.SessionExportDTO{
+		FileName:    "session-" + sessionID + ".json",
+		ContentType: "application/json",
+		Content:     content,
+	}), nil
+}
+
+// renderTranscriptMarkdown renders a session transcript as a Markdown document
+func renderTranscriptMarkdown(t *dto.SessionTranscriptDTO) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Session %s\n\n", t.Session.ID)
+	fmt.Fprintf(&b, "- User: %s\n", t.Session.UserID)
+	fmt.Fprintf(&b, "- Created: %s\n", t.Session.CreatedAt)
+	fmt.Fprintf(&b, "- Exported: %s\n", t.ExportedAt)
+
+	b.WriteString("\n## Messages\n")
+	if len(t.Messages) == 0 {
+		b.WriteString("\nNo messages.\n")
+	}
+	for _, msg := range t.Messages {
+		fmt.Fprintf(&b, "\n### %s · %s\n\n%s\n", msg.Role, msg.CreatedAt, msg.Content)
+	}
+
+	if len(t.Tasks) > 0 {
+		b.WriteString("\n## Tasks\n")
+	}
+	for _, task := range t.Tasks {
+		fmt.Fprintf(&b, "\n### %s · %s · %s\n", task.Skill, task.Status, task.CreatedAt)
+		writeMarkdownBlock(&b, "Input", task.Input)
+		writeMarkdownBlock(&b, "Output", task.Output)
+		writeMarkdownBlock(&b, "Error", task.Error)
+	}
+
+	return b.String()
+}
+
+// writeMarkdownBlock writes a labelled fenced code block, skipping empty content
+func writeMarkdownBlock(b *strings.Builder, label, content string) {
+	if content == "" {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n\n```\n%s\n```\n", label, strings.TrimRight(content, "\n"))
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// SetSessionAttributeRepository sets the repository of session attributes. When set, the custom
+// instructions of a session (the entity.SessionInstructionsKey attribute) are passed to the LLM.
+func (uc *ChatUseCase) SetSessionAttributeRepository(attrRepo repository.SessionAttributeRepository) {
+	uc.attrRepo = attrRepo
+}
+
+// SetGlobalInstructions sets the custom instructions passed to the LLM in every session, before
+// those of the user and the session. Empty instructions remove them.
+func (uc *ChatUseCase) SetGlobalInstructions(instructions string) error {
+	instructions = strings.TrimSpace(instructions)
+	if err := entity.ValidateInstructions(instructions); err != nil {
+		return err
+	}
+
+	uc.instructionsMu.Lock()
+	defer uc.instructionsMu.Unlock()
+	uc.instructions = instructions
+	return nil
+}
+
+// GlobalInstructions returns the custom instructions passed to the LLM in every session
+func (uc *ChatUseCase) GlobalInstructions() string {
+	uc.instructionsMu.RLock()
+	defer uc.instructionsMu.RUnlock()
+	return uc.instructions
+}
+
+// sessionInstructions returns the custom instructions of a session, or an empty string if it
+// has none or they cannot be read
+func (uc *ChatUseCase) sessionInstructions(ctx context.Context, session *entity.Session) string {
+	if uc.attrRepo == nil {
+		return ""
+	}
+
+	attr, err := uc.attrRepo.Get(ctx, session.ID.String(), entity.SessionInstructionsKey)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			uc.logger.Warn("failed to read session instructions", "session_id", session.ID, "error", err)
+		}
+		return ""
+	}
+
+	instructions, err := entity.SessionInstructionsFromAttribute(attr)
+	if err != nil {
+		uc.logger.Warn("ignoring invalid session instructions", "session_id", session.ID, "error", err)
+		return ""
+	}
+	return instructions
+}
+
+// joinInstructions joins the non-empty parts of a system prompt in order, so later parts refine
+// the earlier ones
+func joinInstructions(parts []string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// MaxPageLimit is the largest page size a list request may ask for.
+const MaxPageLimit = 500
+
+// queryOptionsFromPage converts a page request into repository query options.
+// Limits above MaxPageLimit are clamped; a zero limit returns every item.
+func queryOptionsFromPage(page dto.PageRequest) (repository.QueryOptions, error) {
+	if page.Limit < 0 {
+		return repository.QueryOptions{}, fmt.Errorf("limit must not be negative")
+	}
+	if page.Offset < 0 {
+		return repository.QueryOptions{}, fmt.Errorf("offset must not be negative")
+	}
+
+	opts := repository.QueryOptions{
+		Limit:  min(page.Limit, MaxPageLimit),
+		Offset: page.Offset,
+	}
+
+	var err error
+	if opts.Since, err = parsePageTime(page.Since); err != nil {
+		return repository.QueryOptions{}, fmt.Errorf("invalid since: %w", err)
+	}
+	if opts.Until, err = parsePageTime(page.Until); err != nil {
+		return repository.QueryOptions{}, fmt.Errorf("invalid until: %w", err)
+	}
+
+	if page.Cursor != "" {
+		createdAt, id, err := dto.DecodeCursor(page.Cursor)
+		if err != nil {
+			return repository.QueryOptions{}, err
+		}
+		opts.After = &repository.Cursor{CreatedAt: createdAt, ID: id}
+	}
+
+	return opts, nil
+}
+
+// parsePageTime parses an optional RFC3339 time filter.
+func parsePageTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// nextPageCursor returns the cursor for the page after the last item, or an empty
+// string if the page was not full and therefore is the last one.
+func nextPageCursor(count int, opts repository.QueryOptions, createdAt time.Time, id string) string {
+	if opts.Limit == 0 || count < opts.Limit {
+		return ""
+	}
+	return dto.EncodeCursor(createdAt, id)
+}
@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// PlanStepSkill is the skill name of the tasks of plan steps carried out by the LLM itself
+const PlanStepSkill = "plan_step"
+
+// ErrSkillStepsTimeout is the cause of the cancellation of the skill steps of a plan that did
+// not finish within PlanOptions.SkillTimeout
+var ErrSkillStepsTimeout = errors.New("skill steps timed out")
+
+// PlanOptions configures the plans of SendPlannedMessage
+type PlanOptions struct {
+	MaxSteps          int           // Maximum number of steps of a plan; longer plans are cut
+	MaxParallelSkills int           // Skill steps run at the same time; 0 or 1 runs them one after another
+	SkillTimeout      time.Duration // Combined timeout of the skill steps run together; 0 for none
+}
+
+// planStep is a step of a plan generated by the LLM
+type planStep struct {
+	Description string                 `json:"description"`
+	Skill       string                 `json:"skill,omitempty"` // Skill running the step; empty for steps of the LLM
+	Input       map[string]interface{} `json:"input,omitempty"`
+}
+
+// planStepResult is the outcome of a step of a plan
+type planStepResult struct {
+	step   planStep
+	output string
+	err    error
+}
+
+// SendPlannedMessage answers a message like SendMessage, but first asks the LLM to break it
+// down into a plan of at most plan.MaxSteps steps. Each step runs as a task, through its skill
+// or by the LLM, and is reported with ports.ReportProgress before it starts; a failed step does
+// not stop the plan. Consecutive skill steps do not depend on each other, so up to
+// plan.MaxParallelSkills of them run at the same time. The final answer is synthesized from
+// the results of the steps, in the order of the plan, with the prompt fragments of the skills run.
+// Messages the LLM plans as a single step, or whose plan cannot be parsed, are answered directly.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - req: SendMessageRequest containing message and LLM options
+//   - plan: Limits of the plan and of the skill steps run together
+//
+// Returns:
+//   - *dto.SendMessageResponse: Response containing the final answer and conversation history
+//   - error: Error if operation failed
+func (uc *ChatUseCase) SendPlannedMessage(ctx context.Context, req dto.SendMessageRequest, plan PlanOptions) (*dto.SendMessageResponse, error) {
+	session, err := uc.resolveSendSession(ctx, req)
+	if err != nil {
+		return handleSendError(err, "failed to get session")
+	}
+
+	if _, err := uc.saveUserMessage(ctx, session, req.Message.Content); err != nil {
+		return handleSendError(err, "failed to save user message")
+	}
+
+	llmMessages, err := uc.getConversationHistory(ctx, session)
+	if err != nil {
+		return handleSendError(err, "failed to get conversation history")
+	}
+
+	llmMessages, options := uc.applyPreferences(ctx, session, llmMessages, req.Options)
+	steps, err := uc.planSteps(ctx, session, llmMessages, options, plan.MaxSteps)
+	if err != nil {
+		if ctx.Err() != nil {
+			return handleSendError(err, "failed to plan response")
+		}
+		uc.logger.Warn("failed to plan response, answering directly", "session_id", session.ID, "error", err)
+	}
+
+	messages := llmMessages
+	if len(steps) > 1 {
+		results, err := uc.executePlan(ctx, session, llmMessages, options, steps, plan)
+		if err != nil {
+			return handleSendError(err, "failed to execute plan")
+		}
+		messages = withSystemMessage(llmMessages, joinInstructions([]string{synthesisPrompt(results), uc.skillPrompts(stepSkills(results))}))
+	}
+
+	llmResp, err := uc.callLLM(ctx, session, messages, options)
+	if err != nil {
+		return handleSendError(err, "failed to generate response")
+	}
+	return uc.finishSend(ctx, session, llmResp.Message.Content)
+}
+
+// planSteps asks the LLM for the plan of the last message of a conversation, passing the prompt
+// fragments of the skills the plan may use
+func (uc *ChatUseCase) planSteps(ctx context.Context, session *entity.Session, messages []ports.Message, options dto.MessageOptions, maxSteps int) ([]planStep, error) {
+	skills := uc.planSkills(ctx, session)
+	names := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		names = append(names, skill.Name)
+	}
+	prompt := joinInstructions([]string{planPrompt(maxSteps, skills), uc.skillPrompts(names)})
+	resp, err := uc.callLLM(ctx, session, withSystemMessage(messages, prompt), options)
+	if err != nil {
+		return nil, err
+	}
+	return parsePlan(resp.Message.Content, maxSteps)
+}
+
+// stepSkills returns the skills run by the steps of a plan
+func stepSkills(results []planStepResult) []string {
+	var skills []string
+	for _, result := range results {
+		if result.step.Skill != "" {
+			skills = append(skills, result.step.Skill)
+		}
+	}
+	return skills
+}
+
+// planSkills returns the enabled skills of the workspace of the session, for the plan to use
+func (uc *ChatUseCase) planSkills(ctx context.Context, session *entity.Session) []*entity.Skill {
+	if uc.skillRepo == nil {
+		return nil
+	}
+	all, err := uc.skillRepo.List(ctx)
+	if err != nil {
+		uc.logger.Warn("failed to list skills for a plan", "error", err)
+		return nil
+	}
+
+	skills := make([]*entity.Skill, 0, len(all))
+	for _, skill := range all {
+		if skill.IsEnabled() && skill.IsAvailableIn(session.WorkspaceID) {
+			skills = append(skills, skill)
+		}
+	}
+	return skills
+}
+
+// executePlan runs the steps of a plan in order, each run of consecutive skill steps together.
+// It stops only when ctx is done.
+func (uc *ChatUseCase) executePlan(ctx context.Context, session *entity.Session, messages []ports.Message, options dto.MessageOptions, steps []planStep, plan PlanOptions) ([]planStepResult, error) {
+	results := make([]planStepResult, 0, len(steps))
+	for i := 0; i < len(steps); {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		end := i + 1
+		if steps[i].Skill != "" {
+			for end < len(steps) && steps[end].Skill != "" {
+				end++
+			}
+			results = append(results, uc.executeSkillSteps(ctx, session, steps, i, end, plan)...)
+		} else {
+			ports.ReportProgress(ctx, ports.Progress{Step: i + 1, Total: len(steps), Description: steps[i].Description})
+			result := planStepResult{step: steps[i]}
+			result.output, result.err = uc.answerPlanStep(ctx, session, messages, options, steps[i], i+1, len(steps), results)
+			results = append(results, result)
+		}
+
+		for k, result := range results[i:end] {
+			if result.err != nil {
+				uc.logger.Warn("plan step failed", "session_id", session.ID, "step", i+k+1, "skill", result.step.Skill, "error", result.err)
+			}
+		}
+		i = end
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// executeSkillSteps runs the skill steps steps[start:end] of a plan, at most
+// plan.MaxParallelSkills at the same time, and returns their results in the order of the plan.
+// Steps still running after plan.SkillTimeout are cancelled with ErrSkillStepsTimeout.
+func (uc *ChatUseCase) executeSkillSteps(ctx context.Context, session *entity.Session, steps []planStep, start, end int, plan PlanOptions) []planStepResult {
+	results := make([]planStepResult, end-start)
+	begin := func(i int) {
+		ports.ReportProgress(ctx, ports.Progress{Step: start + i + 1, Total: len(steps), Description: steps[start+i].Description})
+	}
+	runConcurrently(ctx, end-start, plan.MaxParallelSkills, plan.SkillTimeout, ErrSkillStepsTimeout, begin, func(ctx context.Context, i int) {
+		result := planStepResult{step: steps[start+i]}
+		result.output, result.err = uc.executeSkillStep(ctx, session, result.step)
+		results[i] = result
+	})
+	return results
+}
+
+// runConcurrently calls run for 0 to n-1, at most parallel calls at the same time, and waits
+// for all of them. begin, if set, is called before each call is started, in order. Calls still
+// running after timeout are cancelled with cause; a timeout of 0 has none.
+func runConcurrently(ctx context.Context, n, parallel int, timeout time.Duration, cause error, begin func(i int), run func(ctx context.Context, i int)) {
+	batchCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() { cancel(cause) })
+		defer timer.Stop()
+	}
+
+	slots := make(chan struct{}, max(parallel, 1))
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		slots <- struct{}{}
+		if begin != nil {
+			begin(i)
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			run(batchCtx, i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// executeSkillStep runs a step of a plan through its skill
+func (uc *ChatUseCase) executeSkillStep(ctx context.Context, session *entity.Session, step planStep) (string, error) {
+	resp, err := uc.ExecuteSkill(ctx, session.ID.String(), step.Skill, step.Input)
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success {
+		return "", errors.New(resp.Error)
+	}
+	return resp.Output, nil
+}
+
+// answerPlanStep carries out a step of a plan by the LLM, as a task of PlanStepSkill that
+// CancelTask can cancel like a skill execution
+func (uc *ChatUseCase) answerPlanStep(ctx context.Context, session *entity.Session, messages []ports.Message, options dto.MessageOptions, step planStep, number, total int, previous []planStepResult) (string, error) {
+	input, err := json.Marshal(map[string]interface{}{"description": step.Description, "step": number, "total": total})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal plan step: %w", err)
+	}
+
+	task := entity.NewTask(session.ID.String(), PlanStepSkill, string(input))
+	execCtx, finish := uc.trackTask(ctx, task.ID)
+	defer finish()
+	if err := uc.taskRepo.Create(ctx, task); err != nil {
+		return "", fmt.Errorf("failed to create task: %w", err)
+	}
+	uc.saveTaskTransition(ctx, task, task.Start())
+
+	resp, err := uc.callLLM(execCtx, session, withSystemMessage(messages, stepPrompt(step, number, total, previous)), options)
+	if cause := cancellationCause(execCtx); cause != nil {
+		uc.saveTaskTransition(context.WithoutCancel(ctx), task, task.Cancel(cause.Error()))
+		return "", cause
+	}
+	if err != nil {
+		uc.saveTaskTransition(ctx, task, task.Fail(fmt.Sprintf("plan step failed: %v", err)))
+		return "", err
+	}
+
+	output, _ := json.Marshal(map[string]string{"result": resp.Message.Content})
+	uc.saveTaskTransition(ctx, task, task.Complete(string(output)))
+	return resp.Message.Content, nil
+}
+
+// planPrompt is the system message asking the LLM for a plan
+func planPrompt(maxSteps int, skills []*entity.Skill) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Break the user's last message down into a plan of at most %d steps. ", maxSteps)
+	b.WriteString(`Reply only with JSON of the form {"steps": [{"description": "...", "skill": "...", "input": {...}}]}. `)
+	b.WriteString("A message that needs no plan has a single step.")
+	if len(skills) == 0 {
+		b.WriteString(" Leave out skill and input.")
+		return b.String()
+	}
+
+	b.WriteString(" Set skill and input only for steps that run one of these skills:")
+	for _, skill := range skills {
+		b.WriteString("\n- " + skill.Name)
+		if description, _ := skill.GetMetadata()["description"].(string); description != "" {
+			b.WriteString(": " + description)
+		}
+	}
+	return b.String()
+}
+
+// parsePlan reads the steps of a plan from the reply of the LLM, which may wrap the JSON in
+// text or a code block. Steps without a description are dropped and the plan is cut to maxSteps.
+func parsePlan(content string, maxSteps int) ([]planStep, error) {
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("plan is not JSON: %q", content)
+	}
+
+	var plan struct {
+		Steps []planStep `json:"steps"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+
+	steps := make([]planStep, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		step.Description = strings.TrimSpace(step.Description)
+		if step.Description != "" {
+			steps = append(steps, step)
+		}
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("plan has no steps")
+	}
+	if maxSteps > 0 && len(steps) > maxSteps {
+		steps = steps[:maxSteps]
+	}
+	return steps, nil
+}
+
+// stepPrompt is the system message asking the LLM to carry out a step of a plan
+func stepPrompt(step planStep, number, total int, previous []planStepResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are carrying out step %d of %d of a plan for the user's last message: %s\n", number, total, step.Description)
+	if len(previous) > 0 {
+		b.WriteString("Results of the previous steps:\n")
+		writeStepResults(&b, previous)
+	}
+	b.WriteString("Reply with the result of this step only.")
+	return b.String()
+}
+
+// synthesisPrompt is the system message asking the LLM for the final answer from the results
+// of the steps of a plan
+func synthesisPrompt(results []planStepResult) string {
+	var b strings.Builder
+	b.WriteString("The user's last message was worked on in steps with these results:\n")
+	writeStepResults(&b, results)
+	b.WriteString("Write the final answer to the user's last message from these results.")
+	return b.String()
+}
+
+// writeStepResults lists the results of steps of a plan, one numbered step per paragraph
+func writeStepResults(b *strings.Builder, results []planStepResult) {
+	for i, result := range results {
+		fmt.Fprintf(b, "%d. %s\n", i+1, result.step.Description)
+		if result.err != nil {
+			fmt.Fprintf(b, "Failed: %v\n", result.err)
+		} else {
+			b.WriteString(result.output + "\n")
+		}
+	}
+}
+
+// withSystemMessage returns a copy of a conversation with a system message appended
+func withSystemMessage(messages []ports.Message, content string) []ports.Message {
+	out := make([]ports.Message, len(messages), len(messages)+1)
+	copy(out, messages)
+	return append(out, ports.Message{Role: "system", Content: content})
+}
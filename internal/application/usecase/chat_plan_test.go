@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// lastMessageContains matches completion requests whose last message contains text
+func lastMessageContains(text string) interface{} {
+	return mock.MatchedBy(func(req ports.CompletionRequest) bool {
+		return len(req.Messages) > 0 && strings.Contains(req.Messages[len(req.Messages)-1].Content, text)
+	})
+}
+
+func completion(content string) *ports.CompletionResponse {
+	return &ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: content}}
+}
+
+// newPlanTestUseCase returns a chat use case with a session that has one user message
+func newPlanTestUseCase(t *testing.T) (*ChatUseCase, *entity.Session, *MockLLMProvider, *MockTaskRepository, *MockSkillRuntime) {
+	t.Helper()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockTaskRepo := new(MockTaskRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockSkillRuntime := new(MockSkillRuntime)
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, mockTaskRepo, mockLLMProvider, mockSkillRuntime, logging.NewNoopLogger())
+
+	session := entity.NewSession("user-1")
+	mockSessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(session, nil)
+	mockSessionRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Session")).Return(nil)
+	mockMessageRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", mock.Anything, string(session.ID)).
+		Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Plan a trip to Rome")}, nil)
+	return uc, session, mockLLMProvider, mockTaskRepo, mockSkillRuntime
+}
+
+func TestChatUseCase_SendPlannedMessage(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, mockTaskRepo, mockSkillRuntime := newPlanTestUseCase(t)
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeSkill, "flights", "Give prices in EUR."))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeSkill, "hotels", "Mention the star rating."))
+
+	var tasks []*entity.Task
+	mockTaskRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Task")).Run(func(args mock.Arguments) {
+		tasks = append(tasks, args.Get(1).(*entity.Task))
+	}).Return(nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Task")).Return(nil)
+
+	plan := "```json\n" + `{"steps": [
+		{"description": "Search for flights", "skill": "flights", "input": {"to": "Rome"}},
+		{"description": "Pick a hotel"},
+		{"description": "Book a table"}
+	]}` + "\n```"
+	mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Break the user's last message down")).Return(completion(plan), nil)
+	mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("step 2 of 2")).Return(completion("Hotel Roma"), nil)
+	mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Write the final answer")).Return(completion("Fly FL123 and stay at Hotel Roma."), nil)
+	mockSkillRuntime.On("Execute", mock.Anything, "flights", map[string]interface{}{"to": "Rome"}).
+		Return(&ports.SkillExecution{Success: true, Output: `{"flight": "FL123"}`}, nil)
+
+	var progress []ports.Progress
+	ctx := ports.WithProgress(context.Background(), func(ctx context.Context, p ports.Progress) {
+		progress = append(progress, p)
+	})
+
+	// Act
+	resp, err := uc.SendPlannedMessage(ctx, dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Plan a trip to Rome"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}, PlanOptions{MaxSteps: 2})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, "Fly FL123 and stay at Hotel Roma.", resp.Message.Content)
+	assert.Equal(t, []ports.Progress{
+		{Step: 1, Total: 2, Description: "Search for flights"},
+		{Step: 2, Total: 2, Description: "Pick a hotel"},
+	}, progress)
+
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "flights", tasks[0].Skill)
+	assert.Equal(t, PlanStepSkill, tasks[1].Skill)
+	for _, task := range tasks {
+		assert.Equal(t, valueobject.TaskStatusCompleted, task.Status)
+	}
+	assert.Equal(t, "Hotel Roma", tasks[1].GetOutput()["result"])
+
+	// The final answer is synthesized from the results of both steps, with the prompt fragment
+	// of the skill run only
+	mockLLMProvider.AssertCalled(t, "Generate", mock.Anything, mock.MatchedBy(func(req ports.CompletionRequest) bool {
+		last := req.Messages[len(req.Messages)-1].Content
+		return strings.Contains(last, `{"flight": "FL123"}`) && strings.Contains(last, "Hotel Roma") &&
+			strings.HasSuffix(last, "\n\nGive prices in EUR.") && !strings.Contains(last, "star rating")
+	}))
+}
+
+// recordTasks records the tasks created in a task repository by steps that may run concurrently
+func recordTasks(mockTaskRepo *MockTaskRepository) func() []*entity.Task {
+	var mu sync.Mutex
+	var tasks []*entity.Task
+	mockTaskRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Task")).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		tasks = append(tasks, args.Get(1).(*entity.Task))
+	}).Return(nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Task")).Return(nil)
+	return func() []*entity.Task {
+		mu.Lock()
+		defer mu.Unlock()
+		return tasks
+	}
+}
+
+func TestChatUseCase_SendPlannedMessage_RunsSkillStepsInParallel(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, mockTaskRepo, mockSkillRuntime := newPlanTestUseCase(t)
+	recordTasks(mockTaskRepo)
+
+	plan := `{"steps": [
+		{"description": "Search for flights", "skill": "flights"},
+		{"description": "Search for hotels", "skill": "hotels"}
+	]}`
+	mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Break the user's last message down")).Return(completion(plan), nil)
+	mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Write the final answer")).Return(completion("Fly and stay."), nil)
+
+	// Each skill waits until the other one has started, so the steps only finish if they run together
+	var started sync.WaitGroup
+	started.Add(2)
+	bothStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(bothStarted)
+	}()
+	waitForOther := func(delay time.Duration) func(mock.Arguments) {
+		return func(mock.Arguments) {
+			started.Done()
+			select {
+			case <-bothStarted:
+				time.Sleep(delay)
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	// The first step finishes last, and its result still comes first
+	mockSkillRuntime.On("Execute", mock.Anything, "flights", mock.Anything).Run(waitForOther(20*time.Millisecond)).
+		Return(&ports.SkillExecution{Success: true, Output: "FL123"}, nil)
+	mockSkillRuntime.On("Execute", mock.Anything, "hotels", mock.Anything).Run(waitForOther(0)).
+		Return(&ports.SkillExecution{Success: true, Output: "Hotel Roma"}, nil)
+
+	// Act
+	start := time.Now()
+	resp, err := uc.SendPlannedMessage(context.Background(), dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Plan a trip to Rome"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}, PlanOptions{MaxSteps: 5, MaxParallelSkills: 2})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Fly and stay.", resp.Message.Content)
+	assert.Less(t, time.Since(start), time.Second, "the skill steps did not run in parallel")
+	mockLLMProvider.AssertCalled(t, "Generate", mock.Anything,
+		lastMessageContains("1. Search for flights\nFL123\n2. Search for hotels\nHotel Roma\n"))
+}
+
+func TestChatUseCase_SendPlannedMessage_SkillTimeout(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, mockTaskRepo, mockSkillRuntime := newPlanTestUseCase(t)
+	tasks := recordTasks(mockTaskRepo)
+
+	plan := `{"steps": [
+		{"description": "Search for flights", "skill": "flights"},
+		{"description": "Search for hotels", "skill": "hotels"}
+	]}`
+	mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Break the user's last message down")).Return(completion(plan), nil)
+	mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Write the final answer")).Return(completion("No flights found."), nil)
+	mockSkillRuntime.On("Execute", mock.Anything, "flights", mock.Anything).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, context.Canceled)
+	mockSkillRuntime.On("Execute", mock.Anything, "hotels", mock.Anything).
+		Return(&ports.SkillExecution{Success: true, Output: "Hotel Roma"}, nil)
+
+	// Act
+	resp, err := uc.SendPlannedMessage(context.Background(), dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Plan a trip to Rome"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}, PlanOptions{MaxSteps: 5, MaxParallelSkills: 2, SkillTimeout: 20 * time.Millisecond})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "No flights found.", resp.Message.Content)
+	mockLLMProvider.AssertCalled(t, "Generate", mock.Anything, lastMessageContains("Failed: skill execution cancelled: "+ErrSkillStepsTimeout.Error()))
+
+	statuses := make(map[string]valueobject.TaskStatus)
+	for _, task := range tasks() {
+		statuses[task.Skill] = task.Status
+	}
+	assert.Equal(t, map[string]valueobject.TaskStatus{
+		"flights": valueobject.TaskStatusCancelled,
+		"hotels":  valueobject.TaskStatusCompleted,
+	}, statuses)
+}
+func TestChatUseCase_SendPlannedMessage_AnswersSimpleMessagesDirectly(t *testing.T) {
+	tests := []struct {
+		name string
+		plan string
+	}{
+		{"single step", `{"steps": [{"description": "Say hello"}]}`},
+		{"no JSON", "I would just answer this."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			uc, session, mockLLMProvider, mockTaskRepo, _ := newPlanTestUseCase(t)
+			mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Break the user's last message down")).Return(completion(tt.plan), nil)
+			mockLLMProvider.On("Generate", mock.Anything, lastMessageContains("Plan a trip to Rome")).Return(completion("Rome is lovely in May."), nil)
+
+			var progress []ports.Progress
+			ctx := ports.WithProgress(context.Background(), func(ctx context.Context, p ports.Progress) {
+				progress = append(progress, p)
+			})
+
+			// Act
+			resp, err := uc.SendPlannedMessage(ctx, dto.SendMessageRequest{
+				Message: dto.ChatMessage{Role: "user", Content: "Plan a trip to Rome"},
+				Options: dto.MessageOptions{SessionID: string(session.ID)},
+			}, PlanOptions{MaxSteps: 5})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "Rome is lovely in May.", resp.Message.Content)
+			assert.Empty(t, progress)
+			mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	steps, err := parsePlan(`Here is the plan: {"steps": [{"description": " One "}, {"description": ""}, {"description": "Two", "skill": "echo"}]}`, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []planStep{{Description: "One"}, {Description: "Two", Skill: "echo"}}, steps)
+
+	steps, err = parsePlan(`{"steps": [{"description": "1"}, {"description": "2"}, {"description": "3"}]}`, 2)
+	require.NoError(t, err)
+	assert.Len(t, steps, 2)
+
+	for _, content := range []string{"no plan", `{"steps": []}`, `{"steps": "many"}`} {
+		_, err := parsePlan(content, 5)
+		assert.Error(t, err, content)
+	}
+}
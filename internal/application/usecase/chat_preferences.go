@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// SetUserPreferencesRepository sets the repository of user preferences.
+// When set, the preferences of the session user are passed to the LLM as a system message,
+// and their preferred model is used unless the request names a model or a provider.
+func (uc *ChatUseCase) SetUserPreferencesRepository(prefsRepo repository.UserPreferencesRepository) {
+	uc.prefsRepo = prefsRepo
+}
+
+// applyPreferences prepends the custom instructions and preferences in effect for a session,
+// followed by the excerpts of its documents relevant to the last user message, to the
+// conversation and fills in the preferred model of the session user. The system prompt is
+// merged from the broadest parts to the narrowest: the global instructions, the prompt
+// fragments of the workspace of the session and of the connector of the message, the
+// instructions of the user and of the session, and the preferences of the user.
+// Failed lookups leave out what they would have added.
+func (uc *ChatUseCase) applyPreferences(ctx context.Context, session *entity.Session, messages []ports.Message, options dto.MessageOptions) ([]ports.Message, dto.MessageOptions) {
+	var prefs *entity.UserPreferences
+	if uc.prefsRepo != nil {
+		var err error
+		prefs, err = uc.prefsRepo.Get(ctx, string(session.UserID))
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			uc.logger.Warn("failed to read user preferences", "user_id", session.UserID, "error", err)
+		}
+	}
+
+	parts := []string{
+		uc.GlobalInstructions(),
+		uc.promptFragment(ports.PromptScopeWorkspace, session.WorkspaceID.String()),
+		uc.promptFragment(ports.PromptScopeConnector, ports.ConnectorFromContext(ctx)),
+	}
+	if prefs != nil {
+		// The preferred model belongs to the default provider
+		if options.Model == "" && options.Provider == "" {
+			options.Model = prefs.Model
+		}
+		parts = append(parts, prefs.Instructions)
+	}
+	parts = append(parts, uc.sessionInstructions(ctx, session))
+	if prefs != nil {
+		parts = append(parts, prefs.SystemPrompt(utils.Now()))
+	}
+	parts = append(parts, uc.documentExcerpts(ctx, session, messages))
+
+	if prompt := joinInstructions(parts); prompt != "" {
+		messages = append([]ports.Message{{Role: "system", Content: prompt}}, messages...)
+	}
+	return messages, options
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// promptKey identifies a prompt fragment by its scope and name
+type promptKey struct {
+	scope string
+	name  string
+}
+
+// SetPromptFragment sets the prompt fragment passed to the LLM within a scope: in the sessions
+// of a workspace, for the messages of a connector, or when planning with a skill and answering
+// from its output. An empty prompt removes the fragment.
+func (uc *ChatUseCase) SetPromptFragment(scope, name, prompt string) error {
+	if !ports.ValidPromptScope(scope) {
+		return fmt.Errorf("%w, got %q", ports.ErrInvalidPromptScope, scope)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("prompt fragment name is required")
+	}
+	prompt = strings.TrimSpace(prompt)
+	if err := entity.ValidateInstructions(prompt); err != nil {
+		return err
+	}
+
+	uc.instructionsMu.Lock()
+	defer uc.instructionsMu.Unlock()
+	key := promptKey{scope: scope, name: name}
+	if prompt == "" {
+		delete(uc.prompts, key)
+		return nil
+	}
+	if uc.prompts == nil {
+		uc.prompts = make(map[promptKey]string)
+	}
+	uc.prompts[key] = prompt
+	return nil
+}
+
+// PromptFragments returns the prompt fragments, sorted by scope and name
+func (uc *ChatUseCase) PromptFragments() []ports.PromptFragment {
+	uc.instructionsMu.RLock()
+	fragments := make([]ports.PromptFragment, 0, len(uc.prompts))
+	for key, prompt := range uc.prompts {
+		fragments = append(fragments, ports.PromptFragment{Scope: key.scope, Name: key.name, Prompt: prompt})
+	}
+	uc.instructionsMu.RUnlock()
+
+	slices.SortFunc(fragments, func(a, b ports.PromptFragment) int {
+		if c := strings.Compare(a.Scope, b.Scope); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return fragments
+}
+
+// promptFragment returns the prompt fragment of a scope and name, or an empty string if none is set
+func (uc *ChatUseCase) promptFragment(scope, name string) string {
+	if name == "" {
+		return ""
+	}
+	uc.instructionsMu.RLock()
+	defer uc.instructionsMu.RUnlock()
+	return uc.prompts[promptKey{scope: scope, name: name}]
+}
+
+// skillPrompts joins the prompt fragments of skills in the order of the skill names, so that
+// the prompt does not depend on the order the skills were listed or run in
+func (uc *ChatUseCase) skillPrompts(names []string) string {
+	names = slices.Compact(slices.Sorted(slices.Values(names)))
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, uc.promptFragment(ports.PromptScopeSkill, name))
+	}
+	return joinInstructions(parts)
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// SkillRetryPolicy configures how often a skill failing with a retryable error is executed
+type SkillRetryPolicy struct {
+	MaxAttempts int           // Number of executions before the task fails; 0 executes the skill once
+	Backoff     time.Duration // Delay before the first retry, doubled with every further retry
+	MaxBackoff  time.Duration // Cap of the delay between retries; 0 means no cap
+}
+
+// delay returns how long to wait before the given retry, starting at 1
+func (p SkillRetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// SetTaskAttemptRepository sets the repository the attempts of tasks are recorded in.
+// Without it, skills are still retried but their attempts are only logged.
+func (uc *ChatUseCase) SetTaskAttemptRepository(attemptRepo repository.TaskAttemptRepository) {
+	uc.attemptRepo = attemptRepo
+}
+
+// SetSkillRetryPolicies sets the retry policy of skills failing with ports.ErrSkillRetryable:
+// the policy of a skill in bySkill, or defaults for the others
+func (uc *ChatUseCase) SetSkillRetryPolicies(defaults SkillRetryPolicy, bySkill map[string]SkillRetryPolicy) {
+	uc.retryMu.Lock()
+	defer uc.retryMu.Unlock()
+	uc.retryDefault = defaults
+	uc.retryBySkill = bySkill
+}
+
+// skillRetryPolicy returns the retry policy of a skill
+func (uc *ChatUseCase) skillRetryPolicy(skillName string) SkillRetryPolicy {
+	uc.retryMu.RLock()
+	defer uc.retryMu.RUnlock()
+	if policy, ok := uc.retryBySkill[skillName]; ok {
+		return policy
+	}
+	return uc.retryDefault
+}
+
+// executeSkillAttempts executes the skill of a task until it succeeds, fails with an error
+// that is not retryable, runs out of attempts or is cancelled, waiting out the backoff of
+// the retry policy of the skill between attempts. Every attempt is recorded against the task.
+// The outcome of the last attempt is returned.
+func (uc *ChatUseCase) executeSkillAttempts(ctx, execCtx context.Context, task *entity.Task, input map[string]interface{}) (*ports.SkillExecution, error) {
+	policy := uc.skillRetryPolicy(task.Skill)
+	for attempt := 1; ; attempt++ {
+		record := entity.NewTaskAttempt(task.ID, attempt)
+		execution, err := uc.skillRuntime.Execute(execCtx, task.Skill, input)
+		retryable := errors.Is(err, ports.ErrSkillRetryable)
+
+		switch cause := cancellationCause(execCtx); {
+		case cause != nil && (err != nil || !execution.Success):
+			record.Finish(valueobject.TaskStatusCancelled, cause.Error(), false)
+		case err != nil:
+			record.Finish(valueobject.TaskStatusFailed, err.Error(), retryable)
+		case !execution.Success:
+			record.Finish(valueobject.TaskStatusFailed, execution.Error, false)
+		default:
+			record.Finish(valueobject.TaskStatusCompleted, "", false)
+		}
+		uc.saveTaskAttempt(ctx, record)
+
+		if !record.Retryable || attempt >= policy.MaxAttempts {
+			return execution, err
+		}
+
+		delay := policy.delay(attempt)
+		uc.logger.Warn("retrying skill after retryable error",
+			"task_id", task.ID, "skill", task.Skill, "attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-execCtx.Done():
+			// Cancelled while waiting: the caller stores the task as cancelled
+			timer.Stop()
+			return execution, err
+		}
+	}
+}
+
+// saveTaskAttempt records a finished attempt of a task; failures are only logged
+func (uc *ChatUseCase) saveTaskAttempt(ctx context.Context, attempt *entity.TaskAttempt) {
+	if uc.attemptRepo == nil {
+		return
+	}
+	// The attempt is recorded even if the context of the request is gone
+	if err := uc.attemptRepo.Create(context.WithoutCancel(ctx), attempt); err != nil {
+		uc.logger.Error("failed to record task attempt", "task_id", attempt.TaskID, "attempt", attempt.Attempt, "error", err)
+	}
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTaskAttemptRepository records the attempts of tasks in memory
+type fakeTaskAttemptRepository struct {
+	mu       sync.Mutex
+	attempts []*entity.TaskAttempt
+}
+
+func (r *fakeTaskAttemptRepository) Create(ctx context.Context, attempt *entity.TaskAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *fakeTaskAttemptRepository) FindByTaskID(ctx context.Context, taskID string) ([]*entity.TaskAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var attempts []*entity.TaskAttempt
+	for _, attempt := range r.attempts {
+		if string(attempt.TaskID) == taskID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+func newRetryTestChatUseCase(t *testing.T, policy SkillRetryPolicy) (*ChatUseCase, *MockSkillRuntime, *fakeTaskAttemptRepository, *entity.Task) {
+	t.Helper()
+	mockTaskRepo := new(MockTaskRepository)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockLogger := new(MockLogger)
+	attempts := &fakeTaskAttemptRepository{}
+
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), mockTaskRepo, new(MockLLMProvider), mockSkillRuntime, mockLogger)
+	uc.SetTaskAttemptRepository(attempts)
+	uc.SetSkillRetryPolicies(SkillRetryPolicy{MaxAttempts: 1}, map[string]SkillRetryPolicy{"fetch": policy})
+
+	task := &entity.Task{}
+	mockTaskRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Task")).Run(func(args mock.Arguments) {
+		*task = *args.Get(1).(*entity.Task)
+	}).Return(nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Task")).Run(func(args mock.Arguments) {
+		*task = *args.Get(1).(*entity.Task)
+	}).Return(nil)
+	mockLogger.On("Warn", mock.Anything, mock.Anything).Return().Maybe()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+	return uc, mockSkillRuntime, attempts, task
+}
+
+func TestChatUseCase_ExecuteSkill_RetriesRetryableError(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, runtime, attempts, task := newRetryTestChatUseCase(t, SkillRetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+	retryable := fmt.Errorf("%w: skill timed out", ports.ErrSkillRetryable)
+	runtime.On("Execute", mock.Anything, "fetch", map[string]interface{}(nil)).Return(nil, retryable).Once()
+	runtime.On("Execute", mock.Anything, "fetch", map[string]interface{}(nil)).Return(&ports.SkillExecution{Success: true, Output: `{"ok": true}`}, nil).Once()
+
+	// Act
+	resp, err := uc.ExecuteSkill(ctx, "session-1", "fetch", nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, valueobject.TaskStatusCompleted, task.Status)
+	recorded, _ := attempts.FindByTaskID(ctx, string(task.ID))
+	require.Len(t, recorded, 2)
+	assert.Equal(t, 1, recorded[0].Attempt)
+	assert.Equal(t, valueobject.TaskStatusFailed, recorded[0].Status)
+	assert.True(t, recorded[0].Retryable)
+	assert.Contains(t, recorded[0].Error, "skill timed out")
+	assert.Equal(t, 2, recorded[1].Attempt)
+	assert.Equal(t, valueobject.TaskStatusCompleted, recorded[1].Status)
+	runtime.AssertExpectations(t)
+}
+
+func TestChatUseCase_ExecuteSkill_RetriesExhausted(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, runtime, attempts, task := newRetryTestChatUseCase(t, SkillRetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+	runtime.On("Execute", mock.Anything, "fetch", map[string]interface{}(nil)).Return(nil, fmt.Errorf("%w: skill timed out", ports.ErrSkillRetryable))
+
+	// Act
+	resp, err := uc.ExecuteSkill(ctx, "session-1", "fetch", nil)
+
+	// Assert
+	require.ErrorIs(t, err, ports.ErrSkillRetryable)
+	assert.False(t, resp.Success)
+	assert.Equal(t, valueobject.TaskStatusFailed, task.Status)
+	runtime.AssertNumberOfCalls(t, "Execute", 2)
+	recorded, _ := attempts.FindByTaskID(ctx, string(task.ID))
+	assert.Len(t, recorded, 2)
+}
+
+func TestChatUseCase_ExecuteSkill_TerminalErrorNotRetried(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, runtime, attempts, task := newRetryTestChatUseCase(t, SkillRetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+	runtime.On("Execute", mock.Anything, "fetch", map[string]interface{}(nil)).Return(nil, errors.New("skill not found"))
+
+	// Act
+	_, err := uc.ExecuteSkill(ctx, "session-1", "fetch", nil)
+
+	// Assert
+	require.Error(t, err)
+	assert.Equal(t, valueobject.TaskStatusFailed, task.Status)
+	runtime.AssertNumberOfCalls(t, "Execute", 1)
+	recorded, _ := attempts.FindByTaskID(ctx, string(task.ID))
+	require.Len(t, recorded, 1)
+	assert.False(t, recorded[0].Retryable)
+}
+
+func TestChatUseCase_ExecuteSkill_CancelledDuringBackoff(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	uc, runtime, _, task := newRetryTestChatUseCase(t, SkillRetryPolicy{MaxAttempts: 3, Backoff: time.Hour})
+	runtime.On("Execute", mock.Anything, "fetch", map[string]interface{}(nil)).Run(func(mock.Arguments) {
+		// Cancelled once the attempt failed, while waiting for the retry
+		time.AfterFunc(10*time.Millisecond, cancel)
+	}).Return(nil, fmt.Errorf("%w: skill timed out", ports.ErrSkillRetryable))
+
+	// Act
+	_, err := uc.ExecuteSkill(ctx, "session-1", "fetch", nil)
+
+	// Assert
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, valueobject.TaskStatusCancelled, task.Status)
+	runtime.AssertNumberOfCalls(t, "Execute", 1)
+}
+
+func TestSkillRetryPolicy_Delay(t *testing.T) {
+	policy := SkillRetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, 5*time.Second, policy.delay(4), "capped by MaxBackoff")
+	assert.Equal(t, 8*time.Second, SkillRetryPolicy{Backoff: time.Second}.delay(4), "no cap")
+}
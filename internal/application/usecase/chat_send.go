@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
 )
 
 // SendMessage processes a user message and returns AI response
@@ -17,14 +19,9 @@ import (
 //   - *dto.SendMessageResponse: Response containing AI message and conversation history
 //   - error: Error if operation failed
 func (uc *ChatUseCase) SendMessage(ctx context.Context, req dto.SendMessageRequest) (*dto.SendMessageResponse, error) {
-	user, err := uc.findOrCreateUser(ctx, req.UserID)
-	if err != nil {
-		return handleSendError(err, "failed to get user")
-	}
-
-	session, err := uc.createSession(ctx, user)
+	session, err := uc.resolveSendSession(ctx, req)
 	if err != nil {
-		return handleSendError(err, "failed to create session")
+		return handleSendError(err, "failed to get session")
 	}
 
 	_, err = uc.saveUserMessage(ctx, session, req.Message.Content)
@@ -37,13 +34,25 @@ func (uc *ChatUseCase) SendMessage(ctx context.Context, req dto.SendMessageReque
 		return handleSendError(err, "failed to get conversation history")
 	}
 
-	llmResp, err := uc.callLLM(ctx, llmMessages, req.Options)
+	llmMessages, options := uc.applyPreferences(ctx, session, llmMessages, req.Options)
+	llmResp, err := uc.callLLM(ctx, session, llmMessages, options)
 	if err != nil {
 		return handleSendError(err, "failed to generate response")
 	}
 
+	return uc.finishSend(ctx, session, llmResp.Message.Content)
+}
+
+// finishSend saves the reply to a message as an assistant message and returns it with the
+// updated conversation. Like a stream, a request cancelled while its reply was generated,
+// e.g. by a client that disconnected, fails without saving the reply.
+func (uc *ChatUseCase) finishSend(ctx context.Context, session *entity.Session, reply string) (*dto.SendMessageResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return handleSendError(err, "failed to generate response")
+	}
+
 	// Save assistant message
-	assistantMessage, err := uc.saveAssistantMessage(ctx, session, llmResp.Message.Content)
+	assistantMessage, err := uc.saveAssistantMessage(ctx, session, reply)
 	if err != nil {
 		uc.logger.Error("failed to save assistant message", "error", err)
 	}
@@ -52,17 +61,57 @@ func (uc *ChatUseCase) SendMessage(ctx context.Context, req dto.SendMessageReque
 	if err := uc.updateSession(ctx, session); err != nil {
 		uc.logger.Error("failed to update session", "error", err)
 	}
+	uc.generateSessionTitle(ctx, session)
 
 	// Build response
 	return uc.buildSendMessageResponse(ctx, session, assistantMessage)
 }
 
-// callLLM calls LLM provider with conversation history
-func (uc *ChatUseCase) callLLM(ctx context.Context, messages []ports.Message, options dto.MessageOptions) (*ports.CompletionResponse, error) {
-	llmReq := ports.CompletionRequest{
-		Messages:  messages,
-		Model:     options.Model,
-		MaxTokens: options.MaxTokens,
+// resolveSendSession returns the session named in the options, or a new session for the web user
+func (uc *ChatUseCase) resolveSendSession(ctx context.Context, req dto.SendMessageRequest) (*entity.Session, error) {
+	if req.Options.SessionID != "" {
+		return uc.findSession(ctx, req.Options.SessionID)
+	}
+
+	user, err := uc.findOrCreateUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.createSession(ctx, user)
+}
+
+// completionRequest builds the LLM request of a conversation answered with the given options
+func completionRequest(messages []ports.Message, options dto.MessageOptions) ports.CompletionRequest {
+	return ports.CompletionRequest{
+		Messages:    messages,
+		Provider:    options.Provider,
+		Model:       options.Model,
+		MaxTokens:   options.MaxTokens,
+		Temperature: options.Temperature,
+	}
+}
+
+// callLLM calls LLM provider with conversation history and publishes the outcome
+func (uc *ChatUseCase) callLLM(ctx context.Context, session *entity.Session, messages []ports.Message, options dto.MessageOptions) (*ports.CompletionResponse, error) {
+	return uc.callLLMWithTools(ctx, session, messages, options, nil)
+}
+
+// callLLMWithTools calls LLM provider like callLLM, offering it the given tools to call
+func (uc *ChatUseCase) callLLMWithTools(ctx context.Context, session *entity.Session, messages []ports.Message, options dto.MessageOptions, tools []ports.ToolDefinition) (*ports.CompletionResponse, error) {
+	llmReq := completionRequest(messages, options)
+
+	start := time.Now()
+	var resp *ports.CompletionResponse
+	var err error
+	if len(tools) > 0 {
+		resp, err = uc.llmProvider.GenerateWithTools(ctx, llmReq, tools)
+	} else {
+		resp, err = uc.llmProvider.Generate(ctx, llmReq)
+	}
+	var tokens ports.Tokens
+	if resp != nil {
+		tokens = resp.Tokens
 	}
-	return uc.llmProvider.Generate(ctx, llmReq)
+	uc.publishLLMEvent(session, options, tokens, time.Since(start), err)
+	return resp, err
 }
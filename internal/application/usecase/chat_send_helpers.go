@@ -7,6 +7,7 @@ import (
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
 )
 
 // findOrCreateUser finds existing user or creates new one
@@ -25,6 +26,9 @@ func (uc *ChatUseCase) findOrCreateUser(ctx context.Context, userID string) (*en
 // createSession creates a new session for the user
 func (uc *ChatUseCase) createSession(ctx context.Context, user *entity.User) (*entity.Session, error) {
 	session := entity.NewSession(string(user.ID))
+	if workspace := WorkspaceFromContext(ctx); !workspace.IsEmpty() {
+		session.WorkspaceID = workspace
+	}
 	if err := uc.sessionRepo.Create(ctx, session); err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
@@ -42,7 +46,7 @@ func (uc *ChatUseCase) saveUserMessage(ctx context.Context, session *entity.Sess
 
 // getConversationHistory retrieves conversation history in LLM format
 func (uc *ChatUseCase) getConversationHistory(ctx context.Context, session *entity.Session) ([]ports.Message, error) {
-	messages, err := uc.messageRepo.FindBySessionID(ctx, string(session.ID))
+	messages, err := uc.messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get conversation history: %w", err)
 	}
@@ -75,7 +79,7 @@ func (uc *ChatUseCase) updateSession(ctx context.Context, session *entity.Sessio
 // buildSendMessageResponse builds response with updated conversation history
 func (uc *ChatUseCase) buildSendMessageResponse(ctx context.Context, session *entity.Session, assistantMessage *entity.Message) (*dto.SendMessageResponse, error) {
 	// Get updated messages for response
-	updatedMessages, err := uc.messageRepo.FindBySessionID(ctx, string(session.ID))
+	updatedMessages, err := uc.messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{})
 	if err != nil {
 		uc.logger.Error("failed to get updated messages", "error", err)
 	}
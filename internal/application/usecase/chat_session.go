@@ -2,14 +2,27 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 )
 
-// GetUserSessions retrieves all sessions for a user
-func (uc *ChatUseCase) GetUserSessions(ctx context.Context, userID string) (*dto.SessionsResponse, error) {
-	sessions, err := uc.sessionRepo.FindByUserID(ctx, userID)
+// ErrForkMessageNotFound is returned when the message a session is forked from is not in the session
+var ErrForkMessageNotFound = errors.New("message is not in the session")
+
+// ListSessions retrieves a page of sessions of all users, newest first
+func (uc *ChatUseCase) ListSessions(ctx context.Context, page dto.PageRequest) (*dto.SessionsResponse, error) {
+	opts, err := queryOptionsFromPage(page)
+	if err != nil {
+		return dto.ErrorSessionsResponse(err), nil
+	}
+
+	opts.Workspace = string(WorkspaceFromContext(ctx))
+	sessions, err := uc.sessionRepo.List(ctx, opts)
 	if err != nil {
 		return dto.ErrorSessionsResponse(err), err
 	}
@@ -19,15 +32,141 @@ func (uc *ChatUseCase) GetUserSessions(ctx context.Context, userID string) (*dto
 		sessionDTOs = append(sessionDTOs, dto.SessionDTOFromEntity(session))
 	}
 
-	return dto.SuccessSessionsResponse(sessionDTOs), nil
+	resp := dto.SuccessSessionsResponse(sessionDTOs)
+	if n := len(sessions); n > 0 {
+		resp.NextCursor = nextPageCursor(n, opts, sessions[n-1].CreatedAt, string(sessions[n-1].ID))
+	}
+	return resp, nil
+}
+
+// GetUserSessions retrieves a page of sessions for a user, newest first
+func (uc *ChatUseCase) GetUserSessions(ctx context.Context, userID string, page dto.PageRequest) (*dto.SessionsResponse, error) {
+	opts, err := queryOptionsFromPage(page)
+	if err != nil {
+		return dto.ErrorSessionsResponse(err), nil
+	}
+
+	opts.Workspace = string(WorkspaceFromContext(ctx))
+	sessions, err := uc.sessionRepo.FindByUserID(ctx, userID, opts)
+	if err != nil {
+		return dto.ErrorSessionsResponse(err), err
+	}
+
+	sessionDTOs := make([]*dto.SessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		sessionDTOs = append(sessionDTOs, dto.SessionDTOFromEntity(session))
+	}
+
+	resp := dto.SuccessSessionsResponse(sessionDTOs)
+	if n := len(sessions); n > 0 {
+		resp.NextCursor = nextPageCursor(n, opts, sessions[n-1].CreatedAt, string(sessions[n-1].ID))
+	}
+	return resp, nil
 }
 
 // CreateSession creates a new session for a user
 func (uc *ChatUseCase) CreateSession(ctx context.Context, req dto.CreateSessionRequest) (*dto.SessionResponse, error) {
 	session := entity.NewSession(req.UserID)
+	if workspace := WorkspaceFromContext(ctx); !workspace.IsEmpty() {
+		session.WorkspaceID = workspace
+	}
 	if err := uc.sessionRepo.Create(ctx, session); err != nil {
 		return handleSessionError(err, "failed to create session")
 	}
 
 	return dto.SuccessSessionResponse(dto.SessionDTOFromEntity(session)), nil
 }
+
+// SetSessionPinned pins or unpins a session. Messages and tasks of pinned sessions
+// are never removed by data retention.
+func (uc *ChatUseCase) SetSessionPinned(ctx context.Context, sessionID string, pinned bool) (*dto.SessionResponse, error) {
+	session, err := uc.findSession(ctx, sessionID)
+	if err != nil {
+		return handleSessionError(err, "failed to find session")
+	}
+
+	if pinned {
+		session.Pin()
+	} else {
+		session.Unpin()
+	}
+
+	if err := uc.sessionRepo.Update(ctx, session); err != nil {
+		return handleSessionError(err, "failed to update session")
+	}
+
+	return dto.SuccessSessionResponse(dto.SessionDTOFromEntity(session)), nil
+}
+
+// ForkSession creates a new session of the same user and workspace holding a copy of the
+// history of a session up to and including a message, so the conversation can take another
+// direction from there. An empty fromMessageID copies the whole history.
+func (uc *ChatUseCase) ForkSession(ctx context.Context, sessionID, fromMessageID string) (*dto.SessionResponse, error) {
+	session, err := uc.findSession(ctx, sessionID)
+	if err != nil {
+		return handleSessionError(err, "failed to find session")
+	}
+
+	messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{})
+	if err != nil {
+		return handleSessionError(err, "failed to get session messages")
+	}
+	if fromMessageID != "" {
+		end := -1
+		for i, msg := range messages {
+			if msg.ID.String() == fromMessageID {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return dto.ErrorSessionResponse(fmt.Errorf("%w: %s", ErrForkMessageNotFound, fromMessageID)), nil
+		}
+		messages = messages[:end+1]
+	}
+
+	fork := entity.NewSession(session.UserID.String())
+	fork.WorkspaceID = session.WorkspaceID
+	if err := uc.sessionRepo.Create(ctx, fork); err != nil {
+		return handleSessionError(err, "failed to create session")
+	}
+
+	// Copies keep the creation time of the originals, so the history reads in the same order
+	for _, msg := range messages {
+		copied := &entity.Message{
+			ID:        valueobject.NewGeneratedMessageID(),
+			SessionID: fork.ID,
+			Role:      msg.Role,
+			Content:   msg.Content,
+			CreatedAt: msg.CreatedAt,
+		}
+		if err := uc.messageRepo.Create(ctx, copied); err != nil {
+			return handleSessionError(err, "failed to copy session messages")
+		}
+	}
+
+	return dto.SuccessSessionResponse(dto.SessionDTOFromEntity(fork)), nil
+}
+
+// findSession retrieves a session by ID. Sessions outside the workspace the context is
+// restricted to are reported as not found.
+func (uc *ChatUseCase) findSession(ctx context.Context, sessionID string) (*entity.Session, error) {
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if workspace := WorkspaceFromContext(ctx); !workspace.IsEmpty() && session.WorkspaceID != workspace {
+		return nil, fmt.Errorf("session %w: %s", repository.ErrNotFound, sessionID)
+	}
+	return session, nil
+}
+
+// checkSessionWorkspace reports a session outside the workspace the context is restricted
+// to as not found. Unrestricted contexts are not checked.
+func (uc *ChatUseCase) checkSessionWorkspace(ctx context.Context, sessionID string) error {
+	if WorkspaceFromContext(ctx).IsEmpty() {
+		return nil
+	}
+	_, err := uc.findSession(ctx, sessionID)
+	return err
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// StreamMessage sends a message to a session and streams the LLM reply.
+// onToken is called for every chunk of the reply as it is generated; an error from onToken
+// stops the stream. The complete reply is saved as an assistant message and returned.
+func (uc *ChatUseCase) StreamMessage(ctx context.Context, req dto.StreamMessageRequest, onToken func(token string) error) (*dto.SendMessageResponse, error) {
+	if strings.TrimSpace(req.Content) == "" {
+		return dto.ErrorSendMessageResponse(fmt.Errorf("content is required")), nil
+	}
+	if req.SessionID == "" && req.UserID == "" {
+		return dto.ErrorSendMessageResponse(fmt.Errorf("session_id or user_id is required")), nil
+	}
+
+	session, err := uc.resolveStreamSession(ctx, req)
+	if err != nil {
+		return handleSendError(err, "failed to get session")
+	}
+
+	if _, err := uc.saveUserMessage(ctx, session, req.Content); err != nil {
+		return handleSendError(err, "failed to save user message")
+	}
+
+	llmMessages, err := uc.getConversationHistory(ctx, session)
+	if err != nil {
+		return handleSendError(err, "failed to get conversation history")
+	}
+
+	llmMessages, options := uc.applyPreferences(ctx, session, llmMessages, req.Options)
+	start := time.Now()
+	tokens, err := uc.llmProvider.Stream(ctx, completionRequest(llmMessages, options))
+	if err != nil {
+		uc.publishLLMEvent(session, options, ports.Tokens{}, time.Since(start), err)
+		return handleSendError(err, "failed to generate response")
+	}
+
+	var reply strings.Builder
+	for token := range tokens {
+		reply.WriteString(token)
+		if err := onToken(token); err != nil {
+			// Drain the stream so the provider goroutine can finish
+			for range tokens {
+			}
+			return handleSendError(err, "failed to stream response")
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		uc.publishLLMEvent(session, options, ports.Tokens{}, time.Since(start), err)
+		return handleSendError(err, "failed to generate response")
+	}
+	uc.publishLLMEvent(session, options, uc.completionTokens(options.Model, llmMessages, reply.String()), time.Since(start), nil)
+
+	assistantMessage, err := uc.saveAssistantMessage(ctx, session, reply.String())
+	if err != nil {
+		return handleSendError(err, "failed to save assistant message")
+	}
+
+	if err := uc.updateSession(ctx, session); err != nil {
+		uc.logger.Error("failed to update session", "error", err)
+	}
+	uc.generateSessionTitle(ctx, session)
+
+	return dto.SuccessSendMessageResponse(dto.MessageDTOFromEntity(assistantMessage), nil), nil
+}
+
+// resolveStreamSession returns the requested session, or a new session for the web user
+func (uc *ChatUseCase) resolveStreamSession(ctx context.Context, req dto.StreamMessageRequest) (*entity.Session, error) {
+	if req.SessionID != "" {
+		return uc.findSession(ctx, req.SessionID)
+	}
+
+	user, err := uc.findOrCreateUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.createSession(ctx, user)
+}
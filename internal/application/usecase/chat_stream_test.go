@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+func streamTokens(tokens ...string) <-chan string {
+	ch := make(chan string, len(tokens))
+	for _, token := range tokens {
+		ch <- token
+	}
+	close(ch)
+	return ch
+}
+
+func TestChatUseCase_StreamMessage_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), mockLogger)
+
+	session := entity.NewSession("user-1")
+	var saved []*entity.Message
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockSessionRepo.On("Update", ctx, mock.AnythingOfType("*entity.Session")).Return(nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(1).(*entity.Message)) }).
+		Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Hello")}, nil)
+	mockLLMProvider.On("Stream", ctx, mock.AnythingOfType("ports.CompletionRequest")).Return(streamTokens("Hi", " there", "!"), nil)
+	mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	var received []string
+
+	// Act
+	resp, err := uc.StreamMessage(ctx, dto.StreamMessageRequest{SessionID: string(session.ID), Content: "Hello"}, func(token string) error {
+		received = append(received, token)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, []string{"Hi", " there", "!"}, received)
+	assert.Equal(t, "assistant", resp.Message.Role)
+	assert.Equal(t, "Hi there!", resp.Message.Content)
+	require.Len(t, saved, 2)
+	assert.Equal(t, "Hello", saved[0].Content)
+	assert.Equal(t, "Hi there!", saved[1].Content)
+	mockLLMProvider.AssertExpectations(t)
+}
+
+func TestChatUseCase_StreamMessage_ClientError(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), mockLogger)
+
+	session := entity.NewSession("user-1")
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil).Once()
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{}, nil)
+	mockLLMProvider.On("Stream", ctx, mock.AnythingOfType("ports.CompletionRequest")).Return(streamTokens("a", "b", "c"), nil)
+	mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	resp, err := uc.StreamMessage(ctx, dto.StreamMessageRequest{SessionID: string(session.ID), Content: "Hello"}, func(token string) error {
+		return errors.New("client disconnected")
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.False(t, resp.Success)
+	// Only the user message is saved
+	mockMessageRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestChatUseCase_StreamMessage_Validation(t *testing.T) {
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	tests := []struct {
+		name    string
+		req     dto.StreamMessageRequest
+		wantErr string
+	}{
+		{name: "empty content", req: dto.StreamMessageRequest{SessionID: "s1", Content: "  "}, wantErr: "content is required"},
+		{name: "no session or user", req: dto.StreamMessageRequest{Content: "Hello"}, wantErr: "session_id or user_id is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.StreamMessage(context.Background(), tt.req, func(string) error { return nil })
+
+			require.NoError(t, err)
+			assert.False(t, resp.Success)
+			assert.Contains(t, resp.Error, tt.wantErr)
+		})
+	}
+}
+
+// wordTokenizer is a ports.Tokenizer counting a token per word, and none framing messages
+type wordTokenizer struct{}
+
+func (wordTokenizer) Count(model, text string) int {
+	return len(strings.Fields(text))
+}
+
+func (t wordTokenizer) CountMessages(model string, messages []ports.Message) int {
+	tokens := 0
+	for _, message := range messages {
+		tokens += t.Count(model, message.Content)
+	}
+	return tokens
+}
+
+func TestChatUseCase_CompletionTokens(t *testing.T) {
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+	messages := []ports.Message{{Role: "user", Content: "how are you today"}}
+
+	assert.Equal(t, ports.Tokens{InputTokens: 5, OutputTokens: 3, TotalTokens: 8}, uc.completionTokens("gpt-4o", messages, "fine, thanks"),
+		"without a tokenizer, tokens should be estimated at four characters per token")
+
+	uc.SetTokenizer(wordTokenizer{})
+	assert.Equal(t, ports.Tokens{InputTokens: 4, OutputTokens: 2, TotalTokens: 6}, uc.completionTokens("gpt-4o", messages, "fine, thanks"))
+}
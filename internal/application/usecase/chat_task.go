@@ -3,46 +3,61 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 )
 
-// ExecuteSkill executes a skill based on LLM response
+// ExecuteSkill executes a skill based on LLM response.
+// Skills that are registered but disabled are refused with ports.ErrSkillDisabled.
+// When ctx is cancelled or the task is cancelled through CancelTask, the skill is stopped
+// and the task is stored as cancelled. A skill failing with ports.ErrSkillRetryable is executed
+// again according to its retry policy, and every execution is recorded as an attempt of the task.
 func (uc *ChatUseCase) ExecuteSkill(ctx context.Context, sessionID, skillName string, input map[string]interface{}) (*dto.SkillExecutionResponse, error) {
+	if err := uc.checkSkillEnabled(ctx, skillName); err != nil {
+		return handleSkillExecutionError(err, "skill cannot be executed")
+	}
+
 	inputJSON, err := json.Marshal(input)
 	if err != nil {
 		return handleSkillExecutionError(err, "failed to marshal skill input")
 	}
 
+	// The task is tracked before it is stored, so CancelTask never finds it untracked
 	task := entity.NewTask(sessionID, skillName, string(inputJSON))
+	execCtx, finish := uc.trackTask(ctx, task.ID)
+	defer finish()
 	if err := uc.taskRepo.Create(ctx, task); err != nil {
 		return handleSkillExecutionError(err, "failed to create task")
 	}
+	uc.saveTaskTransition(ctx, task, task.Start())
 
-	execution, err := uc.skillRuntime.Execute(ctx, skillName, input)
+	start := time.Now()
+	execution, err := uc.executeSkillAttempts(ctx, execCtx, task, input)
+	if cause := cancellationCause(execCtx); cause != nil && (err != nil || !execution.Success) {
+		// The outcome is stored even if the context of the request is gone
+		uc.publishSkillEvent(sessionID, skillName, string(inputJSON), "", cause, time.Since(start))
+		uc.saveTaskTransition(context.WithoutCancel(ctx), task, task.Cancel(cause.Error()))
+		return handleSkillExecutionError(cause, "skill execution cancelled")
+	}
 	if err != nil {
-		task.SetFailed(fmt.Sprintf("skill execution failed: %v", err))
-		if err := uc.taskRepo.Update(ctx, task); err != nil {
-			uc.logger.Error("failed to update task status", "error", err)
-		}
+		uc.publishSkillEvent(sessionID, skillName, string(inputJSON), "", err, time.Since(start))
+		uc.saveTaskTransition(ctx, task, task.Fail(fmt.Sprintf("skill execution failed: %v", err)))
 		return handleSkillExecutionError(err, "skill execution failed")
 	}
 
-	task.SetRunning()
-	if err := uc.taskRepo.Update(ctx, task); err != nil {
-		uc.logger.Error("failed to update task status", "error", err)
-	}
-
 	if execution.Success {
-		task.SetCompleted(execution.Output)
+		uc.saveTaskTransition(ctx, task, task.Complete(execution.Output))
+		uc.publishSkillEvent(sessionID, skillName, string(inputJSON), execution.Output, nil, time.Since(start))
 	} else {
-		task.SetFailed(execution.Error)
-	}
-
-	if err := uc.taskRepo.Update(ctx, task); err != nil {
-		uc.logger.Error("failed to update task completion", "error", err)
+		uc.saveTaskTransition(ctx, task, task.Fail(execution.Error))
+		uc.publishSkillEvent(sessionID, skillName, string(inputJSON), "", errors.New(execution.Error), time.Since(start))
 	}
 
 	return &dto.SkillExecutionResponse{
@@ -52,9 +67,145 @@ func (uc *ChatUseCase) ExecuteSkill(ctx context.Context, sessionID, skillName st
 	}, nil
 }
 
-// GetSessionTasks retrieves all tasks for a session
-func (uc *ChatUseCase) GetSessionTasks(ctx context.Context, sessionID string) (*dto.TasksResponse, error) {
-	tasks, err := uc.taskRepo.FindBySessionID(ctx, sessionID)
+// CancelTask cancels a pending or running task. A skill execution of this process is stopped
+// through its context, and CancelTask waits until the execution has stored the cancelled task;
+// other tasks, such as those left running by a stopped server, are marked cancelled directly.
+// Tasks that already finished are refused with valueobject.ErrInvalidTaskTransition.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - taskID: ID of the task to cancel
+//
+// Returns:
+//   - *dto.TaskResponse: The cancelled task
+//   - error: Error if the task does not exist, already finished or could not be stored
+func (uc *ChatUseCase) CancelTask(ctx context.Context, taskID string) (*dto.TaskResponse, error) {
+	task, err := uc.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return handleTaskError(err, "failed to find task")
+	}
+	if err := uc.checkSessionWorkspace(ctx, string(task.SessionID)); err != nil {
+		return handleTaskError(err, "failed to find task")
+	}
+
+	if run := uc.runningTask(task.ID); run != nil {
+		run.cancel(ports.ErrTaskCancelled)
+		select {
+		case <-run.done:
+		case <-ctx.Done():
+			return handleTaskError(ctx.Err(), "failed to wait for the task to stop")
+		}
+
+		task, err = uc.taskRepo.FindByID(ctx, taskID)
+		if err != nil {
+			return handleTaskError(err, "failed to find task")
+		}
+		if !task.IsCancelled() {
+			err := fmt.Errorf("%w: task %s finished as %s before it was cancelled", valueobject.ErrInvalidTaskTransition, task.ID, task.Status)
+			return handleTaskError(err, "failed to cancel task")
+		}
+		return dto.SuccessTaskResponse(dto.TaskDTOFromEntity(task)), nil
+	}
+
+	if err := task.Cancel(ports.ErrTaskCancelled.Error()); err != nil {
+		return handleTaskError(err, "failed to cancel task")
+	}
+	if err := uc.taskRepo.Update(ctx, task); err != nil {
+		return handleTaskError(err, "failed to update task")
+	}
+	uc.publishTaskEvents(task)
+
+	return dto.SuccessTaskResponse(dto.TaskDTOFromEntity(task)), nil
+}
+
+// runningTask is a skill execution of this process that CancelTask can stop
+type runningTask struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{} // Closed once the execution has stored the outcome of the task
+}
+
+// trackTask registers the skill execution of a task for CancelTask.
+//
+// Returns:
+//   - context.Context: Context to run the skill with, cancelled with ports.ErrTaskCancelled by CancelTask
+//   - func(): Unregisters the execution; call it once the outcome of the task is stored
+func (uc *ChatUseCase) trackTask(ctx context.Context, id valueobject.TaskID) (context.Context, func()) {
+	execCtx, cancel := context.WithCancelCause(ctx)
+	run := &runningTask{cancel: cancel, done: make(chan struct{})}
+
+	uc.runningMu.Lock()
+	uc.running[id] = run
+	uc.runningMu.Unlock()
+
+	return execCtx, func() {
+		uc.runningMu.Lock()
+		delete(uc.running, id)
+		uc.runningMu.Unlock()
+		cancel(nil)
+		close(run.done)
+	}
+}
+
+// runningTask returns the skill execution of a task running in this process, or nil
+func (uc *ChatUseCase) runningTask(id valueobject.TaskID) *runningTask {
+	uc.runningMu.Lock()
+	defer uc.runningMu.Unlock()
+	return uc.running[id]
+}
+
+// cancellationCause returns why a context was cancelled, or nil if it was not cancelled
+// or only timed out
+func cancellationCause(ctx context.Context) error {
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	return context.Cause(ctx)
+}
+
+// saveTaskTransition stores the task after a status transition and publishes the transition.
+// A transition rejected by the task state machine is logged and nothing is stored.
+func (uc *ChatUseCase) saveTaskTransition(ctx context.Context, task *entity.Task, transitionErr error) {
+	if transitionErr != nil {
+		uc.logger.Error("invalid task status transition", "task_id", task.ID, "error", transitionErr)
+		return
+	}
+	if err := uc.taskRepo.Update(ctx, task); err != nil {
+		uc.logger.Error("failed to update task status", "task_id", task.ID, "status", task.Status, "error", err)
+	}
+	uc.publishTaskEvents(task)
+}
+
+// checkSkillEnabled returns ports.ErrSkillDisabled if the skill is registered and disabled.
+// Skills that are not registered are left to the skill runtime.
+func (uc *ChatUseCase) checkSkillEnabled(ctx context.Context, skillName string) error {
+	if uc.skillRepo == nil {
+		return nil
+	}
+
+	skill, err := uc.skillRepo.FindByName(ctx, skillName)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	case !skill.IsEnabled():
+		return fmt.Errorf("%w: %s", ports.ErrSkillDisabled, skillName)
+	}
+	return nil
+}
+
+// GetSessionTasks retrieves a page of tasks for a session, newest first
+func (uc *ChatUseCase) GetSessionTasks(ctx context.Context, sessionID string, page dto.PageRequest) (*dto.TasksResponse, error) {
+	opts, err := queryOptionsFromPage(page)
+	if err != nil {
+		return dto.ErrorTaskResponse(err), nil
+	}
+
+	if err := uc.checkSessionWorkspace(ctx, sessionID); err != nil {
+		return handleTasksError(err, "failed to find session")
+	}
+
+	tasks, err := uc.taskRepo.FindBySessionID(ctx, sessionID, opts)
 	if err != nil {
 		return handleTasksError(err, "failed to get session tasks")
 	}
@@ -64,5 +215,9 @@ func (uc *ChatUseCase) GetSessionTasks(ctx context.Context, sessionID string) (*
 		taskDTOs = append(taskDTOs, dto.TaskDTOFromEntity(task))
 	}
 
-	return dto.SuccessTasksResponse(taskDTOs), nil
+	resp := dto.SuccessTasksResponse(taskDTOs)
+	if n := len(tasks); n > 0 {
+		resp.NextCursor = nextPageCursor(n, opts, tasks[n-1].CreatedAt, string(tasks[n-1].ID))
+	}
+	return resp, nil
 }
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+const (
+	// titlePrompt asks the LLM for the title of a conversation
+	titlePrompt = "Write a title of at most six words for the following conversation, in its language. " +
+		"Reply with the title only, without quotes or punctuation at the end."
+
+	// titleMessages is the number of messages from the start of a conversation its title is
+	// generated from, and titleMessageLength the length in characters each is cut to
+	titleMessages      = 4
+	titleMessageLength = 500
+
+	// titleMaxTokens limits the reply of the LLM to a title
+	titleMaxTokens = 32
+
+	// titleTimeout limits the generation of a title, which outlives the request that triggered it
+	titleTimeout = 30 * time.Second
+
+	// MaxSessionTitleLength is the maximum length in characters of a generated session title
+	MaxSessionTitleLength = 80
+)
+
+// SetSessionTitles enables generating a title for each session after its first exchange.
+// Titles are generated in the background with the default LLM provider and are not regenerated
+// once set.
+func (uc *ChatUseCase) SetSessionTitles(enabled bool) {
+	uc.titlesMu.Lock()
+	defer uc.titlesMu.Unlock()
+	uc.titles = enabled
+}
+
+// WaitSessionTitles waits for the session titles being generated, e.g. before shutdown
+func (uc *ChatUseCase) WaitSessionTitles() {
+	uc.titlesWG.Wait()
+}
+
+// generateSessionTitle starts generating the title of a session without one, unless titles
+// are disabled or a title is already being generated for it
+func (uc *ChatUseCase) generateSessionTitle(ctx context.Context, session *entity.Session) {
+	if session.Title != "" {
+		return
+	}
+
+	uc.titlesMu.Lock()
+	if !uc.titles || uc.titling[session.ID] {
+		uc.titlesMu.Unlock()
+		return
+	}
+	if uc.titling == nil {
+		uc.titling = make(map[valueobject.SessionID]bool)
+	}
+	uc.titling[session.ID] = true
+	uc.titlesWG.Add(1)
+	uc.titlesMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), titleTimeout)
+	go func() {
+		defer uc.titlesWG.Done()
+		defer cancel()
+		defer func() {
+			uc.titlesMu.Lock()
+			delete(uc.titling, session.ID)
+			uc.titlesMu.Unlock()
+		}()
+
+		if err := uc.titleSession(ctx, session.ID.String()); err != nil {
+			uc.logger.Warn("failed to generate session title", "session_id", session.ID, "error", err)
+		}
+	}()
+}
+
+// titleSession generates the title of a session from the start of its conversation and saves it
+func (uc *ChatUseCase) titleSession(ctx context.Context, sessionID string) error {
+	messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{Limit: titleMessages})
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var conversation strings.Builder
+	for _, msg := range messages {
+		conversation.WriteString(string(msg.Role) + ": " + truncateRunes(msg.Content, titleMessageLength) + "\n")
+	}
+
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	resp, err := uc.callLLM(ctx, session, []ports.Message{
+		{Role: "system", Content: titlePrompt},
+		{Role: "user", Content: conversation.String()},
+	}, dto.MessageOptions{MaxTokens: titleMaxTokens})
+	if err != nil {
+		return err
+	}
+
+	title := sessionTitle(resp.Message.Content)
+	if title == "" {
+		return nil
+	}
+
+	// The session may have changed, e.g. been pinned, while the title was generated
+	session, err = uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.Title != "" {
+		return nil
+	}
+	session.Title = title
+	return uc.sessionRepo.Update(ctx, session)
+}
+
+// sessionTitle cleans the reply of the LLM to a title: its first line without surrounding
+// quotes, markup or a final period, cut to MaxSessionTitleLength
+func sessionTitle(reply string) string {
+	reply = strings.TrimSpace(reply)
+	if line, _, found := strings.Cut(reply, "\n"); found {
+		reply = line
+	}
+	title := strings.Join(strings.Fields(reply), " ")
+	title = strings.TrimPrefix(title, "Title:")
+	title = strings.Trim(title, " \"'`*#«»“”.")
+	return truncateRunes(title, MaxSessionTitleLength)
+}
+
+// truncateRunes cuts s to at most n characters
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:n]))
+}
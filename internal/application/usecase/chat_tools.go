@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// ErrToolCallsTimeout is the cause of the cancellation of the tool calls of an LLM reply that
+// did not finish within ToolOptions.Timeout
+var ErrToolCallsTimeout = errors.New("tool calls timed out")
+
+// ToolOptions configures the agent loop of SendToolMessage
+type ToolOptions struct {
+	MaxRounds   int           // LLM replies whose tool calls are run; the next reply must answer
+	MaxParallel int           // Tool calls run at the same time; 0 or 1 runs them one after another
+	Timeout     time.Duration // Combined timeout of the tool calls of a reply; 0 for none
+}
+
+// SendToolMessage answers a message like SendMessage, but offers the LLM the enabled skills of
+// the workspace as tools. The tool calls of a reply run as skill executions, up to
+// tools.MaxParallel of them at the same time, each reported with ports.ReportProgress before it
+// starts; a failed call is passed to the LLM as its result. The results are sent back in the
+// order of the calls, and the LLM is called again until it answers without tool calls or
+// tools.MaxRounds replies were run, after which it answers without tools.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - req: SendMessageRequest containing message and LLM options
+//   - tools: Limits of the agent loop and of the tool calls run together
+//
+// Returns:
+//   - *dto.SendMessageResponse: Response containing the final answer and conversation history
+//   - error: Error if operation failed
+func (uc *ChatUseCase) SendToolMessage(ctx context.Context, req dto.SendMessageRequest, tools ToolOptions) (*dto.SendMessageResponse, error) {
+	session, err := uc.resolveSendSession(ctx, req)
+	if err != nil {
+		return handleSendError(err, "failed to get session")
+	}
+
+	if _, err := uc.saveUserMessage(ctx, session, req.Message.Content); err != nil {
+		return handleSendError(err, "failed to save user message")
+	}
+
+	llmMessages, err := uc.getConversationHistory(ctx, session)
+	if err != nil {
+		return handleSendError(err, "failed to get conversation history")
+	}
+
+	llmMessages, options := uc.applyPreferences(ctx, session, llmMessages, req.Options)
+	llmResp, err := uc.runToolLoop(ctx, session, llmMessages, options, tools)
+	if err != nil {
+		return handleSendError(err, "failed to generate response")
+	}
+	return uc.finishSend(ctx, session, llmResp.Message.Content)
+}
+
+// runToolLoop calls the LLM with the skills of the session as tools and runs the tool calls of
+// its replies until it answers. Messages of the conversation are not modified.
+func (uc *ChatUseCase) runToolLoop(ctx context.Context, session *entity.Session, messages []ports.Message, options dto.MessageOptions, tools ToolOptions) (*ports.CompletionResponse, error) {
+	skills := uc.planSkills(ctx, session)
+	if len(skills) == 0 {
+		return uc.callLLM(ctx, session, messages, options)
+	}
+	definitions := toolDefinitions(skills)
+
+	messages = slices.Clone(messages)
+	for round := 0; round < max(tools.MaxRounds, 1); round++ {
+		resp, err := uc.callLLMWithTools(ctx, session, messages, options, definitions)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.ToolCalls) == 0 {
+			return resp, nil
+		}
+
+		results := uc.executeToolCalls(ctx, session, resp.ToolCalls, definitions, tools)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		messages = append(messages, ports.Message{Role: "assistant", Content: resp.Message.Content, ToolCalls: resp.ToolCalls})
+		for i, call := range resp.ToolCalls {
+			messages = append(messages, ports.Message{Role: "tool", Content: results[i], ToolCallID: call.ID})
+		}
+	}
+
+	uc.logger.Warn("LLM kept calling tools, answering without them", "session_id", session.ID, "rounds", tools.MaxRounds)
+	return uc.callLLM(ctx, session, messages, options)
+}
+
+// executeToolCalls runs the tool calls of an LLM reply, at most tools.MaxParallel at the same
+// time, and returns their results in the order of the calls. Calls still running after
+// tools.Timeout are cancelled with ErrToolCallsTimeout.
+func (uc *ChatUseCase) executeToolCalls(ctx context.Context, session *entity.Session, calls []ports.ToolCall, definitions []ports.ToolDefinition, tools ToolOptions) []string {
+	results := make([]string, len(calls))
+	begin := func(i int) {
+		ports.ReportProgress(ctx, ports.Progress{Step: i + 1, Total: len(calls), Description: calls[i].Name})
+	}
+	runConcurrently(ctx, len(calls), tools.MaxParallel, tools.Timeout, ErrToolCallsTimeout, begin, func(ctx context.Context, i int) {
+		output, err := uc.executeToolCall(ctx, session, calls[i], definitions)
+		if err != nil {
+			uc.logger.Warn("tool call failed", "session_id", session.ID, "tool", calls[i].Name, "error", err)
+			output = fmt.Sprintf("Error: %v", err)
+		}
+		results[i] = output
+	})
+	return results
+}
+
+// executeToolCall runs a tool call through the skill of the same name, which must be one of
+// the tools offered to the LLM
+func (uc *ChatUseCase) executeToolCall(ctx context.Context, session *entity.Session, call ports.ToolCall, definitions []ports.ToolDefinition) (string, error) {
+	if !slices.ContainsFunc(definitions, func(definition ports.ToolDefinition) bool { return definition.Name == call.Name }) {
+		return "", fmt.Errorf("unknown tool %q", call.Name)
+	}
+	resp, err := uc.ExecuteSkill(ctx, session.ID.String(), call.Name, call.Arguments)
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success {
+		return "", errors.New(resp.Error)
+	}
+	return resp.Output, nil
+}
+
+// toolDefinitions returns the skills as tools of the LLM, described by their "description"
+// metadata and taking the JSON Schema of their "parameters" metadata as arguments
+func toolDefinitions(skills []*entity.Skill) []ports.ToolDefinition {
+	definitions := make([]ports.ToolDefinition, 0, len(skills))
+	for _, skill := range skills {
+		metadata := skill.GetMetadata()
+		description, _ := metadata["description"].(string)
+		definitions = append(definitions, ports.ToolDefinition{
+			Name:        skill.Name,
+			Description: description,
+			Parameters:  metadata["parameters"],
+		})
+	}
+	return definitions
+}
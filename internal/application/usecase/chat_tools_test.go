@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// newToolTestUseCase returns a chat use case like newPlanTestUseCase whose workspace has the
+// enabled skill "weather"
+func newToolTestUseCase(t *testing.T) (*ChatUseCase, *entity.Session, *MockLLMProvider, *MockSkillRuntime) {
+	t.Helper()
+	uc, session, mockLLMProvider, mockTaskRepo, mockSkillRuntime := newPlanTestUseCase(t)
+	recordTasks(mockTaskRepo)
+
+	weather := entity.NewSkill("weather", "1.0.0", "/skills/weather", nil, map[string]interface{}{
+		"description": "Current weather of a city",
+		"parameters":  map[string]interface{}{"type": "object", "required": []interface{}{"city"}},
+	})
+	mockSkillRepo := new(MockSkillRepository)
+	mockSkillRepo.On("List", mock.Anything).Return([]*entity.Skill{weather}, nil)
+	mockSkillRepo.On("FindByName", mock.Anything, "weather").Return(weather, nil)
+	uc.SetSkillRepository(mockSkillRepo)
+	return uc, session, mockLLMProvider, mockSkillRuntime
+}
+
+// toolCalls returns a reply of the LLM calling the weather tool for each city
+func toolCalls(cities ...string) *ports.CompletionResponse {
+	resp := &ports.CompletionResponse{Message: ports.Message{Role: "assistant"}}
+	for _, city := range cities {
+		resp.ToolCalls = append(resp.ToolCalls, ports.ToolCall{ID: "call-" + city, Name: "weather", Arguments: map[string]interface{}{"city": city}})
+	}
+	resp.Message.ToolCalls = resp.ToolCalls
+	return resp
+}
+
+// toolResults returns the "tool" messages at the end of a completion request
+func toolResults(req ports.CompletionRequest) []ports.Message {
+	var results []ports.Message
+	for i := len(req.Messages) - 1; i >= 0 && req.Messages[i].Role == "tool"; i-- {
+		results = append([]ports.Message{req.Messages[i]}, results...)
+	}
+	return results
+}
+
+func sendToolMessage(uc *ChatUseCase, ctx context.Context, session *entity.Session, tools ToolOptions) (*dto.SendMessageResponse, error) {
+	return uc.SendToolMessage(ctx, dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Weather in Rome and Paris?"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}, tools)
+}
+
+func TestChatUseCase_SendToolMessage_RunsToolCallsInParallel(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, mockSkillRuntime := newToolTestUseCase(t)
+
+	var requests []ports.CompletionRequest
+	record := func(args mock.Arguments) { requests = append(requests, args.Get(1).(ports.CompletionRequest)) }
+	offered := mock.MatchedBy(func(tools []ports.ToolDefinition) bool {
+		return len(tools) == 1 && tools[0].Name == "weather" && tools[0].Description == "Current weather of a city"
+	})
+	mockLLMProvider.On("GenerateWithTools", mock.Anything, mock.Anything, offered).Run(record).Return(toolCalls("Rome", "Paris"), nil).Once()
+	mockLLMProvider.On("GenerateWithTools", mock.Anything, mock.Anything, offered).Run(record).Return(completion("Sunny in Rome, rainy in Paris."), nil).Once()
+
+	// Each call waits until the other one has started, so the calls only finish if they run together
+	var started sync.WaitGroup
+	started.Add(2)
+	bothStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(bothStarted)
+	}()
+	waitForOther := func(delay time.Duration) func(mock.Arguments) {
+		return func(mock.Arguments) {
+			started.Done()
+			select {
+			case <-bothStarted:
+				time.Sleep(delay)
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	// The first call finishes last, and its result still comes first
+	mockSkillRuntime.On("Execute", mock.Anything, "weather", map[string]interface{}{"city": "Rome"}).Run(waitForOther(20*time.Millisecond)).
+		Return(&ports.SkillExecution{Success: true, Output: "Sunny"}, nil)
+	mockSkillRuntime.On("Execute", mock.Anything, "weather", map[string]interface{}{"city": "Paris"}).Run(waitForOther(0)).
+		Return(&ports.SkillExecution{Success: true, Output: "Rainy"}, nil)
+
+	var progress []ports.Progress
+	ctx := ports.WithProgress(context.Background(), func(ctx context.Context, p ports.Progress) {
+		progress = append(progress, p)
+	})
+
+	// Act
+	start := time.Now()
+	resp, err := sendToolMessage(uc, ctx, session, ToolOptions{MaxRounds: 3, MaxParallel: 2})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny in Rome, rainy in Paris.", resp.Message.Content)
+	assert.Less(t, time.Since(start), time.Second, "the tool calls did not run in parallel")
+	assert.Equal(t, []ports.Progress{
+		{Step: 1, Total: 2, Description: "weather"},
+		{Step: 2, Total: 2, Description: "weather"},
+	}, progress)
+
+	require.Len(t, requests, 2)
+	assistant := requests[1].Messages[len(requests[1].Messages)-3]
+	assert.Equal(t, toolCalls("Rome", "Paris").ToolCalls, assistant.ToolCalls)
+	assert.Equal(t, []ports.Message{
+		{Role: "tool", Content: "Sunny", ToolCallID: "call-Rome"},
+		{Role: "tool", Content: "Rainy", ToolCallID: "call-Paris"},
+	}, toolResults(requests[1]))
+	assert.Len(t, requests[0].Messages, len(requests[1].Messages)-3, "the conversation of the first call was modified")
+}
+
+func TestChatUseCase_SendToolMessage_Timeout(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, mockSkillRuntime := newToolTestUseCase(t)
+
+	var final ports.CompletionRequest
+	mockLLMProvider.On("GenerateWithTools", mock.Anything, mock.Anything, mock.Anything).Return(toolCalls("Rome", "Paris"), nil).Once()
+	mockLLMProvider.On("GenerateWithTools", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		final = args.Get(1).(ports.CompletionRequest)
+	}).Return(completion("Rainy in Paris."), nil).Once()
+	mockSkillRuntime.On("Execute", mock.Anything, "weather", map[string]interface{}{"city": "Rome"}).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, context.Canceled)
+	mockSkillRuntime.On("Execute", mock.Anything, "weather", map[string]interface{}{"city": "Paris"}).
+		Return(&ports.SkillExecution{Success: true, Output: "Rainy"}, nil)
+
+	// Act
+	resp, err := sendToolMessage(uc, context.Background(), session, ToolOptions{MaxRounds: 3, MaxParallel: 2, Timeout: 20 * time.Millisecond})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Rainy in Paris.", resp.Message.Content)
+	assert.Equal(t, []ports.Message{
+		{Role: "tool", Content: "Error: skill execution cancelled: " + ErrToolCallsTimeout.Error(), ToolCallID: "call-Rome"},
+		{Role: "tool", Content: "Rainy", ToolCallID: "call-Paris"},
+	}, toolResults(final))
+}
+
+func TestChatUseCase_SendToolMessage_UnknownTool(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, mockSkillRuntime := newToolTestUseCase(t)
+
+	var final ports.CompletionRequest
+	shell := &ports.CompletionResponse{ToolCalls: []ports.ToolCall{{ID: "call-1", Name: "shell", Arguments: map[string]interface{}{"cmd": "ls"}}}}
+	mockLLMProvider.On("GenerateWithTools", mock.Anything, mock.Anything, mock.Anything).Return(shell, nil).Once()
+	mockLLMProvider.On("GenerateWithTools", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		final = args.Get(1).(ports.CompletionRequest)
+	}).Return(completion("I cannot do that."), nil).Once()
+
+	// Act
+	resp, err := sendToolMessage(uc, context.Background(), session, ToolOptions{MaxRounds: 3})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "I cannot do that.", resp.Message.Content)
+	assert.Equal(t, []ports.Message{{Role: "tool", Content: `Error: unknown tool "shell"`, ToolCallID: "call-1"}}, toolResults(final))
+	mockSkillRuntime.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChatUseCase_SendToolMessage_MaxRounds(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, mockSkillRuntime := newToolTestUseCase(t)
+	mockLLMProvider.On("GenerateWithTools", mock.Anything, mock.Anything, mock.Anything).Return(toolCalls("Rome"), nil)
+	mockLLMProvider.On("Generate", mock.Anything, mock.Anything).Return(completion("Sunny in Rome."), nil)
+	mockSkillRuntime.On("Execute", mock.Anything, "weather", mock.Anything).Return(&ports.SkillExecution{Success: true, Output: "Sunny"}, nil)
+
+	// Act
+	resp, err := sendToolMessage(uc, context.Background(), session, ToolOptions{MaxRounds: 2})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny in Rome.", resp.Message.Content)
+	mockLLMProvider.AssertNumberOfCalls(t, "GenerateWithTools", 2)
+	mockLLMProvider.AssertNumberOfCalls(t, "Generate", 1)
+	mockSkillRuntime.AssertNumberOfCalls(t, "Execute", 2)
+}
+
+func TestChatUseCase_SendToolMessage_WithoutSkills(t *testing.T) {
+	// Arrange
+	uc, session, mockLLMProvider, _, _ := newPlanTestUseCase(t)
+	mockLLMProvider.On("Generate", mock.Anything, mock.Anything).Return(completion("Sunny, probably."), nil)
+
+	// Act
+	resp, err := sendToolMessage(uc, context.Background(), session, ToolOptions{MaxRounds: 3})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny, probably.", resp.Message.Content)
+	mockLLMProvider.AssertNotCalled(t, "GenerateWithTools", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,8 +1,12 @@
 package usecase
 
 import (
+	"sync"
+
 	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
 )
 
@@ -12,9 +16,32 @@ type ChatUseCase struct {
 	sessionRepo  repository.SessionRepository
 	messageRepo  repository.MessageRepository
 	taskRepo     repository.TaskRepository
+	attemptRepo  repository.TaskAttemptRepository // Records every execution of the skill of a task
 	llmProvider  ports.LLMProvider
 	skillRuntime ports.SkillRuntime
+	skillRepo    repository.SkillRepository
+	prefsRepo    repository.UserPreferencesRepository
+	attrRepo     repository.SessionAttributeRepository // Holds the custom instructions of sessions
+	documents    ports.DocumentRetriever               // Finds the excerpts of the documents of sessions
+	tokenizer    ports.Tokenizer                       // Counts the tokens of streamed completions
+	eventBus     *eventbus.EventBus
 	logger       logging.Logger
+
+	instructionsMu sync.RWMutex
+	instructions   string               // Global custom instructions set by an admin
+	prompts        map[promptKey]string // Prompt fragments by scope and name
+
+	runningMu sync.Mutex
+	running   map[valueobject.TaskID]*runningTask // Skill executions of this process, cancelled by CancelTask
+
+	retryMu      sync.RWMutex
+	retryDefault SkillRetryPolicy            // Retry policy of skills without their own
+	retryBySkill map[string]SkillRetryPolicy // Retry policies by skill name
+
+	titlesMu sync.Mutex
+	titles   bool                           // Whether session titles are generated
+	titling  map[valueobject.SessionID]bool // Sessions whose title is being generated
+	titlesWG sync.WaitGroup
 }
 
 // NewChatUseCase creates a new ChatUseCase with all required dependencies
@@ -47,5 +74,12 @@ func NewChatUseCase(
 		llmProvider:  llmProvider,
 		skillRuntime: skillRuntime,
 		logger:       logger,
+		running:      make(map[valueobject.TaskID]*runningTask),
 	}
 }
+
+// SetSkillRepository sets the repository of registered skills.
+// When set, ExecuteSkill refuses to run skills that are disabled.
+func (uc *ChatUseCase) SetSkillRepository(skillRepo repository.SkillRepository) {
+	uc.skillRepo = skillRepo
+}
@@ -2,12 +2,19 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -31,7 +38,15 @@ func (m *MockSessionRepository) FindByID(ctx context.Context, id string) (*entit
 	return args.Get(0).(*entity.Session), args.Error(1)
 }
 
-func (m *MockSessionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Session, error) {
+func (m *MockSessionRepository) List(ctx context.Context, opts repository.QueryOptions) ([]*entity.Session, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindByUserID(ctx context.Context, userID string, opts repository.QueryOptions) ([]*entity.Session, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -67,7 +82,7 @@ func (m *MockMessageRepository) FindByID(ctx context.Context, id string) (*entit
 	return args.Get(0).(*entity.Message), args.Error(1)
 }
 
-func (m *MockMessageRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.Message, error) {
+func (m *MockMessageRepository) FindBySessionID(ctx context.Context, sessionID string, opts repository.QueryOptions) ([]*entity.Message, error) {
 	args := m.Called(ctx, sessionID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -75,6 +90,32 @@ func (m *MockMessageRepository) FindBySessionID(ctx context.Context, sessionID s
 	return args.Get(0).([]*entity.Message), args.Error(1)
 }
 
+func (m *MockMessageRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindOlderThan(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]*entity.Message, error) {
+	args := m.Called(ctx, before, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageRepository) Search(ctx context.Context, search repository.MessageSearch, opts repository.QueryOptions) ([]*entity.Message, error) {
+	args := m.Called(ctx, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
 func (m *MockMessageRepository) Update(ctx context.Context, message *entity.Message) error {
 	args := m.Called(ctx, message)
 	return args.Error(0)
@@ -108,7 +149,7 @@ func (m *MockTaskRepository) FindByID(ctx context.Context, id string) (*entity.T
 	return args.Get(0).(*entity.Task), args.Error(1)
 }
 
-func (m *MockTaskRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.Task, error) {
+func (m *MockTaskRepository) FindBySessionID(ctx context.Context, sessionID string, opts repository.QueryOptions) ([]*entity.Task, error) {
 	args := m.Called(ctx, sessionID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -116,6 +157,24 @@ func (m *MockTaskRepository) FindBySessionID(ctx context.Context, sessionID stri
 	return args.Get(0).([]*entity.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindOlderThan(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]*entity.Task, error) {
+	args := m.Called(ctx, before, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTaskRepository) Update(ctx context.Context, task *entity.Task) error {
 	args := m.Called(ctx, task)
 	return args.Error(0)
@@ -173,21 +232,21 @@ func (m *MockSkillRuntime) Execute(ctx context.Context, skillName string, input
 	return args.Get(0).(*ports.SkillExecution), args.Error(1)
 }
 
-func (m *MockSkillRuntime) Validate(skillName string) error {
-	args := m.Called(skillName)
+func (m *MockSkillRuntime) Validate(ctx context.Context, skillName string) error {
+	args := m.Called(ctx, skillName)
 	return args.Error(0)
 }
 
-func (m *MockSkillRuntime) List() ([]string, error) {
-	args := m.Called()
+func (m *MockSkillRuntime) List(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]string), args.Error(1)
 }
 
-func (m *MockSkillRuntime) GetSkill(skillName string) (map[string]interface{}, error) {
-	args := m.Called(skillName)
+func (m *MockSkillRuntime) GetSkill(ctx context.Context, skillName string) (map[string]interface{}, error) {
+	args := m.Called(ctx, skillName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -263,6 +322,485 @@ func TestChatUseCase_SendMessage_Success(t *testing.T) {
 	mockLLMProvider.AssertExpectations(t)
 }
 
+func TestChatUseCase_SendMessage_ContinuesSession(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(mockUserRepo, mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), mockLogger)
+
+	session := entity.NewSession("user-1")
+	earlier := entity.NewUserMessage(string(session.ID), "Earlier")
+	req := dto.SendMessageRequest{
+		UserID:  "user-1",
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}
+
+	llmResp := &ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Hi there!"}}
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{earlier}, nil)
+	mockLLMProvider.On("Generate", ctx, mock.AnythingOfType("ports.CompletionRequest")).Return(llmResp, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, string(session.ID), resp.Message.SessionID)
+	mockUserRepo.AssertNotCalled(t, "FindByChannel", mock.Anything, mock.Anything, mock.Anything)
+	mockSessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestChatUseCase_SendMessage_GeneratesSessionTitle(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), mockLogger)
+	uc.SetSessionTitles(true)
+
+	session := entity.NewSession("user-1")
+	stored := *session
+	exchange := []*entity.Message{
+		entity.NewUserMessage(string(session.ID), "What should I see in Lisbon?"),
+		entity.NewAssistantMessage(string(session.ID), "Start with Alfama."),
+	}
+	req := dto.SendMessageRequest{
+		UserID:  "user-1",
+		Message: dto.ChatMessage{Role: "user", Content: "What should I see in Lisbon?"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}
+
+	isTitleRequest := func(req ports.CompletionRequest) bool { return req.MaxTokens == titleMaxTokens }
+	mockSessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(session, nil).Once()
+	mockSessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(&stored, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", mock.Anything, string(session.ID)).Return(exchange, nil)
+	mockLLMProvider.On("Generate", mock.Anything, mock.MatchedBy(isTitleRequest)).
+		Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "\"Sightseeing in Lisbon.\"\n"}}, nil).Once()
+	mockLLMProvider.On("Generate", ctx, mock.AnythingOfType("ports.CompletionRequest")).
+		Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Start with Alfama."}}, nil).Once()
+	mockSessionRepo.On("Update", ctx, session).Return(nil).Once()
+	mockSessionRepo.On("Update", mock.Anything, mock.MatchedBy(func(s *entity.Session) bool { return s.Title == "Sightseeing in Lisbon" })).Return(nil).Once()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+	uc.WaitSessionTitles()
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockSessionRepo.AssertExpectations(t)
+	mockLLMProvider.AssertExpectations(t)
+	mockLogger.AssertNotCalled(t, "Warn", mock.Anything, mock.Anything)
+}
+
+func TestSessionTitle(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  string
+	}{
+		{reply: "Sightseeing in Lisbon", want: "Sightseeing in Lisbon"},
+		{reply: "  \"Sightseeing  in Lisbon.\"\nHope this helps!", want: "Sightseeing in Lisbon"},
+		{reply: "**«Поездка в Лиссабон»**", want: "Поездка в Лиссабон"},
+		{reply: "Title: Weekly report", want: "Weekly report"},
+		{reply: "\n\n", want: ""},
+		{reply: strings.Repeat("word ", 40), want: strings.TrimSpace(strings.Repeat("word ", 16))},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sessionTitle(tt.reply), "reply %q", tt.reply)
+	}
+}
+
+func TestChatUseCase_SendMessage_Cancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), mockLogger)
+
+	session := entity.NewSession("user-1")
+	req := dto.SendMessageRequest{
+		UserID:  "user-1",
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil).Once()
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{}, nil)
+	// The client disconnects while the reply is generated
+	mockLLMProvider.On("Generate", ctx, mock.AnythingOfType("ports.CompletionRequest")).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Hi there!"}}, nil)
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+
+	// Assert
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, resp.Success)
+	mockMessageRepo.AssertNumberOfCalls(t, "Create", 1)
+	mockSessionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestChatUseCase_SendMessage_UserPreferences(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockPrefsRepo := new(MockUserPreferencesRepository)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), mockLogger)
+	uc.SetUserPreferencesRepository(mockPrefsRepo)
+
+	session := entity.NewSession("user-1")
+	prefs := entity.NewUserPreferences("user-1")
+	require.NoError(t, prefs.Set(entity.PreferenceLanguage, "ru"))
+	require.NoError(t, prefs.Set(entity.PreferenceModel, "gpt-4o"))
+	req := dto.SendMessageRequest{
+		UserID:  "user-1",
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}
+
+	llmResp := &ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Привет!"}}
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Hello")}, nil)
+	mockPrefsRepo.On("Get", ctx, "user-1").Return(prefs, nil)
+	mockLLMProvider.On("Generate", ctx, mock.MatchedBy(func(req ports.CompletionRequest) bool {
+		return req.Model == "gpt-4o" && len(req.Messages) == 2 &&
+			req.Messages[0].Role == "system" && strings.Contains(req.Messages[0].Content, `"ru"`)
+	})).Return(llmResp, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockLLMProvider.AssertExpectations(t)
+}
+
+func TestChatUseCase_SendMessage_Instructions(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockPrefsRepo := new(MockUserPreferencesRepository)
+	mockAttrRepo := new(MockSessionAttributeRepository)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), logging.NewNoopLogger())
+	uc.SetUserPreferencesRepository(mockPrefsRepo)
+	uc.SetSessionAttributeRepository(mockAttrRepo)
+	require.NoError(t, uc.SetGlobalInstructions("  Never share secrets.  "))
+
+	session := entity.NewSession("user-1")
+	prefs := entity.NewUserPreferences("user-1")
+	require.NoError(t, prefs.Set(entity.PreferenceInstructions, "Answer in haiku."))
+	attr, err := entity.SessionInstructionsAttribute(string(session.ID), "Be brief.")
+	require.NoError(t, err)
+	req := dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Hello")}, nil)
+	mockPrefsRepo.On("Get", ctx, "user-1").Return(prefs, nil)
+	mockAttrRepo.On("Get", ctx, string(session.ID), entity.SessionInstructionsKey).Return(attr, nil)
+	mockLLMProvider.On("Generate", ctx, mock.MatchedBy(func(req ports.CompletionRequest) bool {
+		// Global, user and session instructions come in this order
+		return len(req.Messages) == 2 && req.Messages[0].Role == "system" &&
+			strings.HasPrefix(req.Messages[0].Content, "Never share secrets.\n\nAnswer in haiku.\n\nBe brief.")
+	})).Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Hi"}}, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "Never share secrets.", uc.GlobalInstructions())
+	mockLLMProvider.AssertExpectations(t)
+	mockAttrRepo.AssertExpectations(t)
+}
+
+func TestChatUseCase_SendMessage_PromptFragments(t *testing.T) {
+	// Arrange
+	ctx := ports.WithConnector(context.Background(), "telegram")
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), logging.NewNoopLogger())
+	require.NoError(t, uc.SetGlobalInstructions("Never share secrets."))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeConnector, "telegram", "Keep replies under 3000 characters."))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeConnector, "discord", "Use Discord markdown."))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeWorkspace, "support", "Answer as the support team."))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeSkill, "weather", "Give temperatures in Celsius."))
+
+	session := entity.NewSession("user-1")
+	session.WorkspaceID = "support"
+	req := dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Hello")}, nil)
+	mockLLMProvider.On("Generate", ctx, mock.MatchedBy(func(req ports.CompletionRequest) bool {
+		// Global instructions, then the fragments of the workspace and the connector; those of
+		// other connectors and of skills are left out
+		return len(req.Messages) == 2 && req.Messages[0].Role == "system" &&
+			req.Messages[0].Content == "Never share secrets.\n\nAnswer as the support team.\n\nKeep replies under 3000 characters."
+	})).Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Hi"}}, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockLLMProvider.AssertExpectations(t)
+}
+
+func TestChatUseCase_SetPromptFragment(t *testing.T) {
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), logging.NewNoopLogger())
+
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeWorkspace, "support", " Answer as the support team. "))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeConnector, "telegram", "Keep replies short."))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeConnector, "discord", "Use Discord markdown."))
+	assert.Equal(t, []ports.PromptFragment{
+		{Scope: ports.PromptScopeConnector, Name: "discord", Prompt: "Use Discord markdown."},
+		{Scope: ports.PromptScopeConnector, Name: "telegram", Prompt: "Keep replies short."},
+		{Scope: ports.PromptScopeWorkspace, Name: "support", Prompt: "Answer as the support team."},
+	}, uc.PromptFragments())
+
+	assert.ErrorIs(t, uc.SetPromptFragment("channel", "telegram", "Be brief."), ports.ErrInvalidPromptScope)
+	assert.Error(t, uc.SetPromptFragment(ports.PromptScopeSkill, " ", "Be brief."))
+	assert.ErrorIs(t, uc.SetPromptFragment(ports.PromptScopeSkill, "weather", strings.Repeat("a", entity.MaxInstructionsLength+1)), entity.ErrInstructionsTooLong)
+
+	// An empty prompt removes the fragment
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeConnector, "telegram", ""))
+	require.NoError(t, uc.SetPromptFragment(ports.PromptScopeConnector, "discord", "  "))
+	assert.Equal(t, []ports.PromptFragment{
+		{Scope: ports.PromptScopeWorkspace, Name: "support", Prompt: "Answer as the support team."},
+	}, uc.PromptFragments())
+}
+
+// mockDocumentRetriever returns fixed chunks and records the queries
+type mockDocumentRetriever struct {
+	chunks  []*entity.DocumentChunk
+	queries []string
+}
+
+func (m *mockDocumentRetriever) RetrieveDocuments(ctx context.Context, sessionID, query string) ([]*entity.DocumentChunk, error) {
+	m.queries = append(m.queries, query)
+	return m.chunks, nil
+}
+
+func TestChatUseCase_SendMessage_DocumentExcerpts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), logging.NewNoopLogger())
+	require.NoError(t, uc.SetGlobalInstructions("Never share secrets."))
+
+	session := entity.NewSession("user-1")
+	retriever := &mockDocumentRetriever{chunks: []*entity.DocumentChunk{
+		entity.NewDocumentChunk(string(session.ID), "doc-1", "invoice.pdf", 2, "Total due: 120 EUR", nil),
+	}}
+	uc.SetDocumentRetriever(retriever)
+	req := dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "What is the total?"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	}
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "What is the total?")}, nil)
+	mockLLMProvider.On("Generate", ctx, mock.MatchedBy(func(req ports.CompletionRequest) bool {
+		// The excerpts come after the instructions
+		return len(req.Messages) == 2 && req.Messages[0].Role == "system" &&
+			strings.HasPrefix(req.Messages[0].Content, "Never share secrets.\n\n"+documentExcerptsIntro) &&
+			strings.HasSuffix(req.Messages[0].Content, "[invoice.pdf, part 3]\nTotal due: 120 EUR")
+	})).Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "120 EUR"}}, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, []string{"What is the total?"}, retriever.queries)
+	mockLLMProvider.AssertExpectations(t)
+}
+
+func TestChatUseCase_SetGlobalInstructions_TooLong(t *testing.T) {
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), logging.NewNoopLogger())
+
+	err := uc.SetGlobalInstructions(strings.Repeat("a", entity.MaxInstructionsLength+1))
+
+	assert.ErrorIs(t, err, entity.ErrInstructionsTooLong)
+	assert.Empty(t, uc.GlobalInstructions())
+}
+
+func TestChatUseCase_SendMessage_LLMOptions(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockPrefsRepo := new(MockUserPreferencesRepository)
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, new(MockTaskRepository), mockLLMProvider, new(MockSkillRuntime), logging.NewNoopLogger())
+	uc.SetUserPreferencesRepository(mockPrefsRepo)
+
+	session := entity.NewSession("user-1")
+	prefs := entity.NewUserPreferences("user-1")
+	require.NoError(t, prefs.Set(entity.PreferenceModel, "gpt-4o"))
+	temperature := 0.2
+	req := dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID), Provider: "ollama", MaxTokens: 300, Temperature: &temperature},
+	}
+
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Hello")}, nil)
+	mockPrefsRepo.On("Get", ctx, "user-1").Return(prefs, nil)
+	mockLLMProvider.On("Generate", ctx, mock.MatchedBy(func(req ports.CompletionRequest) bool {
+		// The preferred model belongs to the default provider and is not applied
+		return req.Provider == "ollama" && req.Model == "" && req.MaxTokens == 300 &&
+			req.Temperature != nil && *req.Temperature == 0.2
+	})).Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Hi"}}, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+
+	// Act
+	resp, err := uc.SendMessage(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockLLMProvider.AssertExpectations(t)
+}
+
+func TestChatUseCase_PublishesLLMAndSkillEvents(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockTaskRepo := new(MockTaskRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockLogger := new(MockLogger)
+
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{BatchSize: 10, FlushInterval: 10 * time.Millisecond, Logger: logging.NewNoopLogger()})
+	require.NoError(t, bus.Start())
+	defer func() { _ = bus.Stop() }()
+	events := make(chan eventbus.Event, 10)
+	bus.SubscribeWithOptions([]string{eventbus.EventLLMResponse, eventbus.EventLLMUsage, eventbus.EventSkillFailed}, eventbus.SubscribeOptions{Name: "test"},
+		func(ctx context.Context, event eventbus.Event) error {
+			events <- event
+			return nil
+		})
+
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, mockMessageRepo, mockTaskRepo, mockLLMProvider, mockSkillRuntime, mockLogger)
+	uc.SetEventBus(bus)
+
+	session := entity.NewSession("user-1")
+	session.WorkspaceID = "support"
+	llmResp := &ports.CompletionResponse{
+		Message: ports.Message{Role: "assistant", Content: "Hi!"},
+		Tokens:  ports.Tokens{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+	}
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{}, nil)
+	mockLLMProvider.On("Generate", ctx, mock.Anything).Return(llmResp, nil)
+	mockLLMProvider.On("EstimateCost", ports.CompletionRequest{Model: "gpt-4o", MaxTokens: 15}).Return(0.003, nil)
+	mockTaskRepo.On("Create", ctx, mock.AnythingOfType("*entity.Task")).Return(nil)
+	mockTaskRepo.On("Update", ctx, mock.AnythingOfType("*entity.Task")).Return(nil)
+	mockSkillRuntime.On("Execute", mock.Anything, "weather", map[string]interface{}{}).Return(&ports.SkillExecution{Success: false, Error: "no city"}, nil)
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+
+	// Act
+	_, err := uc.SendMessage(ctx, dto.SendMessageRequest{
+		UserID:  "user-1",
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID), Model: "gpt-4o"},
+	})
+	require.NoError(t, err)
+	_, err = uc.ExecuteSkill(ctx, string(session.ID), "weather", map[string]interface{}{})
+	require.NoError(t, err)
+
+	// Assert
+	received := map[string]eventbus.Event{}
+	for len(received) < 3 {
+		select {
+		case event := <-events:
+			received[event.Type()] = event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d of 3 events", len(received))
+		}
+	}
+	llmEvent := received[eventbus.EventLLMResponse].(*eventbus.LLMPublishedEvent)
+	assert.Equal(t, "gpt-4o", llmEvent.Model)
+	assert.Equal(t, 15, llmEvent.Tokens)
+	assert.Equal(t, 0.003, llmEvent.Cost)
+	sessionID, _ := llmEvent.GetMetadataValue("session_id")
+	assert.Equal(t, string(session.ID), sessionID)
+	usageEvent := received[eventbus.EventLLMUsage].(*eventbus.UsageEvent)
+	assert.NotEmpty(t, usageEvent.UsageID)
+	assert.Equal(t, "user-1", usageEvent.UserID)
+	assert.Equal(t, "support", usageEvent.WorkspaceID)
+	assert.Equal(t, string(session.ID), usageEvent.SessionID)
+	assert.Equal(t, "gpt-4o", usageEvent.Model)
+	assert.Equal(t, []int{10, 5, 15}, []int{usageEvent.InputTokens, usageEvent.OutputTokens, usageEvent.TotalTokens})
+	assert.Equal(t, 0.003, usageEvent.Cost)
+	skillEvent := received[eventbus.EventSkillFailed].(*eventbus.SkillEvent)
+	assert.Equal(t, "weather", skillEvent.SkillName)
+	assert.EqualError(t, skillEvent.Error, "no city")
+}
+
 func TestChatUseCase_GetConversation_Success(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -285,7 +823,7 @@ func TestChatUseCase_GetConversation_Success(t *testing.T) {
 	mockMessageRepo.On("FindBySessionID", ctx, sessionID).Return(messages, nil)
 
 	// Act
-	resp, err := uc.GetConversation(ctx, sessionID)
+	resp, err := uc.GetConversation(ctx, sessionID, dto.PageRequest{})
 
 	// Assert
 	require.NoError(t, err)
@@ -294,6 +832,72 @@ func TestChatUseCase_GetConversation_Success(t *testing.T) {
 	mockMessageRepo.AssertExpectations(t)
 }
 
+func TestChatUseCase_GetConversation_NextCursor(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockMessageRepo := new(MockMessageRepository)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), mockMessageRepo, new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	sessionID := "session-1"
+	messages := []*entity.Message{
+		entity.NewUserMessage(sessionID, "Hello"),
+		entity.NewAssistantMessage(sessionID, "Hi there!"),
+	}
+	mockMessageRepo.On("FindBySessionID", ctx, sessionID).Return(messages, nil)
+
+	// Act
+	full, err := uc.GetConversation(ctx, sessionID, dto.PageRequest{Limit: 2})
+	require.NoError(t, err)
+	partial, err := uc.GetConversation(ctx, sessionID, dto.PageRequest{Limit: 3})
+	require.NoError(t, err)
+
+	// Assert
+	require.NotEmpty(t, full.NextCursor)
+	createdAt, id, err := dto.DecodeCursor(full.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, string(messages[1].ID), id)
+	assert.True(t, messages[1].CreatedAt.Truncate(time.Second).Equal(createdAt))
+	assert.Empty(t, partial.NextCursor)
+}
+
+func TestChatUseCase_GetConversation_InvalidPage(t *testing.T) {
+	ctx := context.Background()
+	mockMessageRepo := new(MockMessageRepository)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), mockMessageRepo, new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	for _, page := range []dto.PageRequest{
+		{Cursor: "not-a-cursor"},
+		{Since: "yesterday"},
+		{Limit: -1},
+	} {
+		resp, err := uc.GetConversation(ctx, "session-1", page)
+		require.NoError(t, err)
+		assert.False(t, resp.Success)
+		assert.NotEmpty(t, resp.Error)
+	}
+	mockMessageRepo.AssertNotCalled(t, "FindBySessionID")
+}
+
+func TestChatUseCase_SearchMessages(t *testing.T) {
+	ctx := context.Background()
+	mockMessageRepo := new(MockMessageRepository)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), mockMessageRepo, new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	search := repository.MessageSearch{Query: "weather", UserID: "user-1"}
+	messages := []*entity.Message{entity.NewUserMessage("session-1", "What's the weather?")}
+	mockMessageRepo.On("Search", ctx, search).Return(messages, nil)
+
+	resp, err := uc.SearchMessages(ctx, dto.SearchMessagesRequest{Query: "weather", UserID: "user-1"}, dto.PageRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Len(t, resp.Messages, 1)
+
+	resp, err = uc.SearchMessages(ctx, dto.SearchMessagesRequest{Query: "   "}, dto.PageRequest{})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	mockMessageRepo.AssertNumberOfCalls(t, "Search", 1)
+}
+
 func TestChatUseCase_GetUserSessions_Success(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -316,12 +920,35 @@ func TestChatUseCase_GetUserSessions_Success(t *testing.T) {
 	mockSessionRepo.On("FindByUserID", ctx, userID).Return(sessions, nil)
 
 	// Act
-	resp, err := uc.GetUserSessions(ctx, userID)
+	resp, err := uc.GetUserSessions(ctx, userID, dto.PageRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Len(t, resp.Sessions, 2)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestChatUseCase_ListSessions_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	sessions := []*entity.Session{
+		entity.NewSession("user-1"),
+		entity.NewSession("user-2"),
+	}
+	mockSessionRepo.On("List", ctx, repository.QueryOptions{Limit: 2}).Return(sessions, nil)
+
+	// Act
+	resp, err := uc.ListSessions(ctx, dto.PageRequest{Limit: 2})
 
 	// Assert
 	require.NoError(t, err)
 	assert.True(t, resp.Success)
 	assert.Len(t, resp.Sessions, 2)
+	assert.NotEmpty(t, resp.NextCursor)
 	mockSessionRepo.AssertExpectations(t)
 }
 
@@ -355,6 +982,116 @@ func TestChatUseCase_CreateSession_Success(t *testing.T) {
 	mockSessionRepo.AssertExpectations(t)
 }
 
+func TestChatUseCase_SetSessionPinned(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockTaskRepo := new(MockTaskRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(mockUserRepo, mockSessionRepo, mockMessageRepo, mockTaskRepo, mockLLMProvider, mockSkillRuntime, mockLogger)
+
+	session := entity.NewSession("user-1")
+	mockSessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	mockSessionRepo.On("Update", ctx, session).Return(nil)
+
+	// Act
+	resp, err := uc.SetSessionPinned(ctx, string(session.ID), true)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.True(t, resp.Session.Pinned)
+	assert.True(t, session.IsPinned())
+
+	resp, err = uc.SetSessionPinned(ctx, string(session.ID), false)
+	require.NoError(t, err)
+	assert.False(t, resp.Session.Pinned)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestChatUseCase_ForkSession(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockTaskRepo := new(MockTaskRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(mockUserRepo, mockSessionRepo, mockMessageRepo, mockTaskRepo, mockLLMProvider, mockSkillRuntime, mockLogger)
+
+	session := entity.NewSession("user-1")
+	session.WorkspaceID = valueobject.WorkspaceID("sales")
+	sessionID := string(session.ID)
+	messages := []*entity.Message{
+		entity.NewUserMessage(sessionID, "Plan a trip to Rome"),
+		entity.NewAssistantMessage(sessionID, "Fly on Friday."),
+		entity.NewUserMessage(sessionID, "Too expensive"),
+	}
+
+	var copied []*entity.Message
+	mockSessionRepo.On("FindByID", ctx, sessionID).Return(session, nil)
+	mockSessionRepo.On("Create", ctx, mock.AnythingOfType("*entity.Session")).Return(nil)
+	mockMessageRepo.On("FindBySessionID", ctx, sessionID).Return(messages, nil)
+	mockMessageRepo.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Run(func(args mock.Arguments) {
+		copied = append(copied, args.Get(1).(*entity.Message))
+	}).Return(nil)
+
+	// Act
+	resp, err := uc.ForkSession(ctx, sessionID, string(messages[1].ID))
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.NotEqual(t, sessionID, resp.Session.ID)
+	assert.Equal(t, "user-1", resp.Session.UserID)
+	assert.Equal(t, "sales", resp.Session.WorkspaceID)
+	require.Len(t, copied, 2, "messages after the fork point should not be copied")
+	for i, msg := range copied {
+		assert.Equal(t, resp.Session.ID, string(msg.SessionID))
+		assert.NotEqual(t, messages[i].ID, msg.ID)
+		assert.Equal(t, messages[i].Role, msg.Role)
+		assert.Equal(t, messages[i].Content, msg.Content)
+		assert.Equal(t, messages[i].CreatedAt, msg.CreatedAt)
+	}
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestChatUseCase_ForkSession_MessageNotInSession(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockTaskRepo := new(MockTaskRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(mockUserRepo, mockSessionRepo, mockMessageRepo, mockTaskRepo, mockLLMProvider, mockSkillRuntime, mockLogger)
+
+	session := entity.NewSession("user-1")
+	sessionID := string(session.ID)
+	mockSessionRepo.On("FindByID", ctx, sessionID).Return(session, nil)
+	mockMessageRepo.On("FindBySessionID", ctx, sessionID).Return([]*entity.Message{entity.NewUserMessage(sessionID, "Hello")}, nil)
+
+	// Act
+	resp, err := uc.ForkSession(ctx, sessionID, "msg-unknown")
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, ErrForkMessageNotFound.Error())
+	mockSessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestChatUseCase_ExecuteSkill_Success(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -378,7 +1115,7 @@ func TestChatUseCase_ExecuteSkill_Success(t *testing.T) {
 		Error:   "",
 	}
 
-	mockSkillRuntime.On("Execute", ctx, skillName, input).Return(skillExecResult, nil)
+	mockSkillRuntime.On("Execute", mock.Anything, skillName, input).Return(skillExecResult, nil)
 	mockTaskRepo.On("Create", ctx, mock.AnythingOfType("*entity.Task")).Return(nil)
 	mockTaskRepo.On("Update", ctx, mock.AnythingOfType("*entity.Task")).Return(nil)
 	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
@@ -393,6 +1130,184 @@ func TestChatUseCase_ExecuteSkill_Success(t *testing.T) {
 	mockTaskRepo.AssertExpectations(t)
 }
 
+func TestChatUseCase_ExecuteSkill_TaskTransitions(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockTaskRepo := new(MockTaskRepository)
+	mockSkillRuntime := new(MockSkillRuntime)
+
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{BatchSize: 10, FlushInterval: 10 * time.Millisecond, Logger: logging.NewNoopLogger()})
+	require.NoError(t, bus.Start())
+	defer func() { _ = bus.Stop() }()
+	events := make(chan *eventbus.TaskEvent, 10)
+	bus.SubscribeWithOptions([]string{eventbus.EventTaskStarted, eventbus.EventTaskCompleted}, eventbus.SubscribeOptions{Name: "test"},
+		func(ctx context.Context, event eventbus.Event) error {
+			events <- event.(*eventbus.TaskEvent)
+			return nil
+		})
+
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), mockTaskRepo, new(MockLLMProvider), mockSkillRuntime, new(MockLogger))
+	uc.SetEventBus(bus)
+
+	var task *entity.Task
+	var statuses []valueobject.TaskStatus
+	mockTaskRepo.On("Create", ctx, mock.AnythingOfType("*entity.Task")).Run(func(args mock.Arguments) {
+		task = args.Get(1).(*entity.Task)
+	}).Return(nil)
+	mockTaskRepo.On("Update", ctx, mock.AnythingOfType("*entity.Task")).Run(func(args mock.Arguments) {
+		statuses = append(statuses, args.Get(1).(*entity.Task).Status)
+	}).Return(nil)
+	mockSkillRuntime.On("Execute", mock.Anything, "echo", map[string]interface{}(nil)).Return(&ports.SkillExecution{Success: true, Output: `{"ok": true}`}, nil)
+
+	// Act
+	_, err := uc.ExecuteSkill(ctx, "session-1", "echo", nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []valueobject.TaskStatus{valueobject.TaskStatusRunning, valueobject.TaskStatusCompleted}, statuses)
+	require.NotNil(t, task.StartedAt)
+	require.NotNil(t, task.FinishedAt)
+
+	for _, want := range []string{eventbus.EventTaskStarted, eventbus.EventTaskCompleted} {
+		select {
+		case event := <-events:
+			assert.Equal(t, want, event.Type())
+			assert.Equal(t, string(task.ID), event.TaskID)
+			assert.Equal(t, "echo", event.SkillName)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("did not receive %s", want)
+		}
+	}
+}
+
+func TestChatUseCase_ExecuteSkill_Disabled(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockTaskRepo := new(MockTaskRepository)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockSkillRepo := new(MockSkillRepository)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), mockTaskRepo, new(MockLLMProvider), mockSkillRuntime, new(MockLogger))
+	uc.SetSkillRepository(mockSkillRepo)
+
+	skill := entity.NewSkill("my-skill", "1.0.0", "/skills/my-skill", nil, nil)
+	skill.Disable()
+	mockSkillRepo.On("FindByName", ctx, "my-skill").Return(skill, nil)
+
+	// Act
+	resp, err := uc.ExecuteSkill(ctx, "session-1", "my-skill", nil)
+
+	// Assert
+	require.ErrorIs(t, err, ports.ErrSkillDisabled)
+	assert.False(t, resp.Success)
+	mockSkillRuntime.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything)
+	mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestChatUseCase_ExecuteSkill_UnregisteredSkill(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockTaskRepo := new(MockTaskRepository)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockSkillRepo := new(MockSkillRepository)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), mockTaskRepo, new(MockLLMProvider), mockSkillRuntime, new(MockLogger))
+	uc.SetSkillRepository(mockSkillRepo)
+
+	mockSkillRepo.On("FindByName", ctx, "my-skill").Return(nil, repository.ErrNotFound)
+	mockSkillRuntime.On("Execute", mock.Anything, "my-skill", map[string]interface{}(nil)).Return(&ports.SkillExecution{Success: true, Output: "{}"}, nil)
+	mockTaskRepo.On("Create", ctx, mock.AnythingOfType("*entity.Task")).Return(nil)
+	mockTaskRepo.On("Update", ctx, mock.AnythingOfType("*entity.Task")).Return(nil)
+
+	// Act
+	resp, err := uc.ExecuteSkill(ctx, "session-1", "my-skill", nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockSkillRuntime.AssertExpectations(t)
+}
+
+func TestChatUseCase_CancelTask_Pending(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockTaskRepo := new(MockTaskRepository)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), mockTaskRepo, new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	task := entity.NewTask("session-1", "echo", "{}")
+	mockTaskRepo.On("FindByID", ctx, string(task.ID)).Return(task, nil)
+	mockTaskRepo.On("Update", ctx, task).Return(nil)
+
+	// Act
+	resp, err := uc.CancelTask(ctx, string(task.ID))
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "cancelled", resp.Task.Status)
+	assert.True(t, task.IsCancelled())
+	assert.Equal(t, "task cancelled", task.Error)
+	mockTaskRepo.AssertExpectations(t)
+}
+
+func TestChatUseCase_CancelTask_Finished(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockTaskRepo := new(MockTaskRepository)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), mockTaskRepo, new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	task := entity.NewTask("session-1", "echo", "{}")
+	require.NoError(t, task.Start())
+	require.NoError(t, task.Complete(`{"ok": true}`))
+	mockTaskRepo.On("FindByID", ctx, string(task.ID)).Return(task, nil)
+
+	// Act
+	resp, err := uc.CancelTask(ctx, string(task.ID))
+
+	// Assert
+	require.Error(t, err)
+	assert.ErrorIs(t, err, valueobject.ErrInvalidTaskTransition)
+	assert.False(t, resp.Success)
+	mockTaskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestChatUseCase_CancelTask_Running(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockTaskRepo := new(MockTaskRepository)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockLogger := new(MockLogger)
+	uc := NewChatUseCase(new(MockUserRepository), new(MockSessionRepository), new(MockMessageRepository), mockTaskRepo, new(MockLLMProvider), mockSkillRuntime, mockLogger)
+
+	tasks := make(chan *entity.Task, 1)
+	mockTaskRepo.On("Create", ctx, mock.AnythingOfType("*entity.Task")).Run(func(args mock.Arguments) {
+		tasks <- args.Get(1).(*entity.Task)
+	}).Return(nil)
+	mockTaskRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Task")).Return(nil)
+	// The runtime reports a cancelled execution as a failure rather than an error
+	mockSkillRuntime.On("Execute", mock.Anything, "sleep", map[string]interface{}(nil)).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(&ports.SkillExecution{Success: false, Error: "context canceled"}, nil)
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+
+	executed := make(chan error, 1)
+	go func() {
+		_, err := uc.ExecuteSkill(ctx, "session-1", "sleep", nil)
+		executed <- err
+	}()
+	task := <-tasks
+	mockTaskRepo.On("FindByID", ctx, string(task.ID)).Return(task, nil)
+
+	// Act
+	resp, err := uc.CancelTask(ctx, string(task.ID))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", resp.Task.Status)
+	execErr := <-executed
+	require.Error(t, execErr)
+	assert.ErrorIs(t, execErr, ports.ErrTaskCancelled)
+	assert.NotNil(t, task.FinishedAt)
+}
+
 func TestChatUseCase_GetSessionTasks_Success(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -409,14 +1324,15 @@ func TestChatUseCase_GetSessionTasks_Success(t *testing.T) {
 	sessionID := "session-1"
 	task1 := entity.NewTask(sessionID, "skill1", "{}")
 	task2 := entity.NewTask(sessionID, "skill2", "{}")
-	task2.SetCompleted(`{"result": "ok"}`)
+	require.NoError(t, task2.Start())
+	require.NoError(t, task2.Complete(`{"result": "ok"}`))
 
 	tasks := []*entity.Task{task1, task2}
 
 	mockTaskRepo.On("FindBySessionID", ctx, sessionID).Return(tasks, nil)
 
 	// Act
-	resp, err := uc.GetSessionTasks(ctx, sessionID)
+	resp, err := uc.GetSessionTasks(ctx, sessionID, dto.PageRequest{})
 
 	// Assert
 	require.NoError(t, err)
@@ -424,3 +1340,78 @@ func TestChatUseCase_GetSessionTasks_Success(t *testing.T) {
 	assert.Len(t, resp.Tasks, 2)
 	mockTaskRepo.AssertExpectations(t)
 }
+
+func TestChatUseCase_ExportSession(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMessageRepo := new(MockMessageRepository)
+	mockTaskRepo := new(MockTaskRepository)
+	mockLLMProvider := new(MockLLMProvider)
+	mockSkillRuntime := new(MockSkillRuntime)
+	mockLogger := new(MockLogger)
+
+	uc := NewChatUseCase(mockUserRepo, mockSessionRepo, mockMessageRepo, mockTaskRepo, mockLLMProvider, mockSkillRuntime, mockLogger)
+
+	session := entity.NewSession("user-1")
+	sessionID := string(session.ID)
+	messages := []*entity.Message{
+		entity.NewUserMessage(sessionID, "What is the weather?"),
+		entity.NewAssistantMessage(sessionID, "It is sunny."),
+	}
+	older := entity.NewTask(sessionID, "weather", `{"city": "Berlin"}`)
+	require.NoError(t, older.Start())
+	require.NoError(t, older.Complete(`{"forecast": "sunny"}`))
+	newer := entity.NewTask(sessionID, "translate", "{}")
+	require.NoError(t, newer.Fail("timeout"))
+
+	mockSessionRepo.On("FindByID", ctx, sessionID).Return(session, nil)
+	mockMessageRepo.On("FindBySessionID", ctx, sessionID).Return(messages, nil)
+	mockTaskRepo.On("FindBySessionID", ctx, sessionID).Return([]*entity.Task{newer, older}, nil)
+
+	// Act
+	jsonResp, err := uc.ExportSession(ctx, sessionID, "")
+	require.NoError(t, err)
+	mdResp, err := uc.ExportSession(ctx, sessionID, dto.ExportFormatMarkdown)
+	require.NoError(t, err)
+
+	// Assert
+	require.True(t, jsonResp.Success)
+	assert.Equal(t, "session-"+sessionID+".json", jsonResp.Export.FileName)
+	assert.Equal(t, "application/json", jsonResp.Export.ContentType)
+
+	var transcript dto.SessionTranscriptDTO
+	require.NoError(t, json.Unmarshal(jsonResp.Export.Content, &transcript))
+	assert.Equal(t, sessionID, transcript.Session.ID)
+	require.Len(t, transcript.Messages, 2)
+	require.Len(t, transcript.Tasks, 2)
+	assert.Equal(t, "weather", transcript.Tasks[0].Skill, "tasks should be oldest first")
+	assert.Equal(t, `{"forecast": "sunny"}`, transcript.Tasks[0].Output)
+
+	require.True(t, mdResp.Success)
+	assert.Equal(t, "session-"+sessionID+".md", mdResp.Export.FileName)
+	md := string(mdResp.Export.Content)
+	assert.Contains(t, md, "# Session "+sessionID)
+	assert.Contains(t, md, "It is sunny.")
+	assert.Contains(t, md, "### weather · completed")
+	assert.Contains(t, md, `{"forecast": "sunny"}`)
+	assert.Contains(t, md, "timeout")
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestChatUseCase_ExportSession_InvalidFormat(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	uc := NewChatUseCase(new(MockUserRepository), mockSessionRepo, new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), new(MockLogger))
+
+	// Act
+	resp, err := uc.ExportSession(ctx, "session-1", "pdf")
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "format must be json or markdown")
+	mockSessionRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
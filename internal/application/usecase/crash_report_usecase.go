@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Limits for the number of crash reports returned by ListCrashReports
+const (
+	defaultCrashReportsLimit = 50
+	maxCrashReportsLimit     = 500
+)
+
+// CrashReportUseCase handles the crash log: the panics recovered in the router, connectors,
+// skills and HTTP handlers
+type CrashReportUseCase struct {
+	crashRepo repository.CrashReportRepository
+	logger    logging.Logger
+}
+
+// NewCrashReportUseCase creates a new CrashReportUseCase
+func NewCrashReportUseCase(crashRepo repository.CrashReportRepository, logger logging.Logger) *CrashReportUseCase {
+	return &CrashReportUseCase{
+		crashRepo: crashRepo,
+		logger:    logger,
+	}
+}
+
+// ListCrashReports returns the stored crash reports, newest first.
+// A non-positive limit uses the default; limits above the maximum are capped.
+func (uc *CrashReportUseCase) ListCrashReports(ctx context.Context, req dto.ListCrashReportsRequest) (*dto.CrashReportsResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultCrashReportsLimit
+	}
+	limit = min(limit, maxCrashReportsLimit)
+
+	reports, err := uc.crashRepo.List(ctx, repository.CrashReportFilter{
+		Component: req.Component,
+		Limit:     limit,
+		Offset:    req.Offset,
+	})
+	if err != nil {
+		return dto.ErrorCrashReportsResponse(err), err
+	}
+
+	reportDTOs := make([]*dto.CrashReportDTO, 0, len(reports))
+	for _, report := range reports {
+		reportDTOs = append(reportDTOs, dto.CrashReportDTOFromEntity(report))
+	}
+
+	return dto.SuccessCrashReportsResponse(reportDTOs), nil
+}
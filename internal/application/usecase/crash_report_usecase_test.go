@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// MockCrashReportRepository is a mock of the methods of CrashReportRepository used by the CrashReportUseCase
+type MockCrashReportRepository struct {
+	repository.CrashReportRepository
+	mock.Mock
+}
+
+func (m *MockCrashReportRepository) List(ctx context.Context, filter repository.CrashReportFilter) ([]*entity.CrashReport, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.CrashReport), args.Error(1)
+}
+
+func TestCrashReportUseCase_ListCrashReports(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockCrashReportRepository)
+	uc := NewCrashReportUseCase(mockRepo, new(MockLogger))
+
+	report := entity.NewCrashReport("router", "route_message", "boom", "goroutine 1 [running]:", map[string]string{"connector": "telegram"})
+	mockRepo.On("List", ctx, repository.CrashReportFilter{Component: "router", Limit: defaultCrashReportsLimit, Offset: 2}).
+		Return([]*entity.CrashReport{report}, nil)
+	mockRepo.On("List", ctx, repository.CrashReportFilter{Limit: maxCrashReportsLimit}).Return([]*entity.CrashReport{}, nil)
+
+	resp, err := uc.ListCrashReports(ctx, dto.ListCrashReportsRequest{Component: "router", Offset: 2})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	require.Len(t, resp.Crashes, 1)
+	assert.Equal(t, report.ID, resp.Crashes[0].ID)
+	assert.Equal(t, "boom", resp.Crashes[0].Panic)
+	assert.Equal(t, "telegram", resp.Crashes[0].Attributes["connector"])
+
+	resp, err = uc.ListCrashReports(ctx, dto.ListCrashReportsRequest{Limit: 10000})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCrashReportUseCase_ListCrashReports_Error(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockCrashReportRepository)
+	uc := NewCrashReportUseCase(mockRepo, new(MockLogger))
+
+	mockRepo.On("List", ctx, mock.Anything).Return(nil, errors.New("database locked"))
+
+	resp, err := uc.ListCrashReports(ctx, dto.ListCrashReportsRequest{})
+	require.Error(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "database locked")
+}
@@ -41,7 +41,123 @@ func handleTasksError(err error, message string) (*dto.TasksResponse, error) {
 	return dto.ErrorTaskResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
 }
 
+// handleTaskError handles errors in single Task operations
+func handleTaskError(err error, message string) (*dto.TaskResponse, error) {
+	wrapped := fmt.Errorf("%s: %w", message, err)
+	return &dto.TaskResponse{Success: false, Error: fmt.Sprintf("operation failed: %v", wrapped)}, wrapped
+}
+
 // handleSkillExecutionError handles errors in SkillExecution use case
 func handleSkillExecutionError(err error, message string) (*dto.SkillExecutionResponse, error) {
 	return dto.ErrorSkillExecutionResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
 }
+
+// handleBackupError handles errors in Backup use case
+func handleBackupError(err error, message string) (*dto.BackupResponse, error) {
+	return dto.ErrorBackupResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleSessionAttributeError handles errors in SessionState use case
+func handleSessionAttributeError(err error, message string) (*dto.SessionAttributeResponse, error) {
+	return dto.ErrorSessionAttributeResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleSessionExportError handles errors in session export
+func handleSessionExportError(err error, message string) (*dto.SessionExportResponse, error) {
+	return dto.ErrorSessionExportResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleHandoffError handles errors in Handoff use case
+func handleHandoffError(err error, message string) (*dto.HandoffResponse, error) {
+	return dto.ErrorHandoffResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleAPIKeyError handles errors in APIKey use case
+func handleAPIKeyError(err error, message string) (*dto.APIKeyResponse, error) {
+	return dto.ErrorAPIKeyResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleConnectorError handles errors in Admin use case connector operations
+func handleConnectorError(err error, message string) (*dto.ConnectorResponse, error) {
+	return dto.ErrorConnectorResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleMessageDeadLettersError handles errors in Admin use case dead letter listing
+func handleMessageDeadLettersError(err error, message string) (*dto.MessageDeadLettersResponse, error) {
+	return dto.ErrorMessageDeadLettersResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleDeadLetterError handles errors in Admin use case dead letter operations
+func handleDeadLetterError(err error, message string) (*dto.DeadLetterResponse, error) {
+	return dto.ErrorDeadLetterResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleMessageReplayError handles errors in Admin use case message replay operations
+func handleMessageReplayError(err error, message string) (*dto.MessageReplayResponse, error) {
+	return dto.ErrorMessageReplayResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleWorkspaceError handles errors in Workspace use case
+func handleWorkspaceError(err error, message string) (*dto.WorkspaceResponse, error) {
+	return dto.ErrorWorkspaceResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleUserPreferencesError handles errors in UserPreferences use case
+func handleUserPreferencesError(err error, message string) (*dto.UserPreferencesResponse, error) {
+	return dto.ErrorUserPreferencesResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleAnalyticsError handles errors in Analytics use case
+func handleAnalyticsError(err error, message string) (*dto.AnalyticsResponse, error) {
+	return dto.ErrorAnalyticsResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleUsageError handles errors in Usage use case
+func handleUsageError(err error, message string) (*dto.UsageResponse, error) {
+	return dto.ErrorUsageResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleFeedbackError handles errors in Feedback use case
+func handleFeedbackError(err error, message string) (*dto.FeedbackResponse, error) {
+	return dto.ErrorFeedbackResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleFeedbackAnalyticsError handles errors in feedback analytics
+func handleFeedbackAnalyticsError(err error, message string) (*dto.FeedbackAnalyticsResponse, error) {
+	return dto.ErrorFeedbackAnalyticsResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleUserDataExportError handles errors in user data export
+func handleUserDataExportError(err error, message string) (*dto.UserDataExportResponse, error) {
+	return dto.ErrorUserDataExportResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleUserDataErasureError handles errors in user data erasure
+func handleUserDataErasureError(err error, message string) (*dto.UserDataErasureResponse, error) {
+	return dto.ErrorUserDataErasureResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleInstructionsError handles errors in Admin use case global instructions
+func handleInstructionsError(err error, message string) (*dto.InstructionsResponse, error) {
+	return dto.ErrorInstructionsResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handlePromptFragmentError handles errors of prompt fragments in Admin use case
+func handlePromptFragmentError(err error, message string) (*dto.PromptFragmentResponse, error) {
+	return dto.ErrorPromptFragmentResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handlePromptFragmentsError handles errors of prompt fragment lists in Admin use case
+func handlePromptFragmentsError(err error, message string) (*dto.PromptFragmentsResponse, error) {
+	return dto.ErrorPromptFragmentsResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleMessageJobError handles errors in MessageJob use case
+func handleMessageJobError(err error, message string) (*dto.MessageJobResponse, error) {
+	return dto.ErrorMessageJobResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
+
+// handleMessageBatchError handles errors of message batches in MessageJob use case
+func handleMessageBatchError(err error, message string) (*dto.MessageBatchResponse, error) {
+	return dto.ErrorMessageBatchResponse(fmt.Errorf("%s: %w", message, err)), fmt.Errorf("%s: %w", message, err)
+}
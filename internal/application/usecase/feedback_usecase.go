@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Errors returned by the FeedbackUseCase
+var (
+	ErrInvalidFeedbackRating  = errors.New("rating must be up or down")
+	ErrFeedbackCommentTooLong = fmt.Errorf("comment must be at most %d characters", entity.MaxFeedbackCommentLength)
+	ErrFeedbackNotReply       = errors.New("only assistant messages can be rated")
+)
+
+// FeedbackUseCase records the ratings users give to assistant replies, such as the thumbs up
+// and down buttons of a Telegram reply, and counts them per day. Every rating is published as
+// an EventFeedbackReceived event carrying the rated reply, e.g. for fine-tuning pipelines.
+type FeedbackUseCase struct {
+	feedbackRepo repository.MessageFeedbackRepository
+	messageRepo  repository.MessageRepository
+	sessionRepo  repository.SessionRepository
+	eventBus     *eventbus.EventBus
+	logger       logging.Logger
+}
+
+// NewFeedbackUseCase creates a new FeedbackUseCase
+func NewFeedbackUseCase(
+	feedbackRepo repository.MessageFeedbackRepository,
+	messageRepo repository.MessageRepository,
+	sessionRepo repository.SessionRepository,
+	logger logging.Logger,
+) *FeedbackUseCase {
+	return &FeedbackUseCase{
+		feedbackRepo: feedbackRepo,
+		messageRepo:  messageRepo,
+		sessionRepo:  sessionRepo,
+		logger:       logger,
+	}
+}
+
+// SetEventBus sets the event bus ratings are published to. Without it, no events are published.
+func (uc *FeedbackUseCase) SetEventBus(eventBus *eventbus.EventBus) {
+	uc.eventBus = eventBus
+}
+
+// RecordFeedback rates an assistant message, replacing an earlier rating of the message.
+// A non-empty userID restricts the rating to replies in the sessions of that user, as for
+// chat commands; other messages, and messages outside the workspace the context is
+// restricted to, are reported as not found.
+func (uc *FeedbackUseCase) RecordFeedback(ctx context.Context, messageID, userID string, req dto.RecordFeedbackRequest) (*dto.FeedbackResponse, error) {
+	rating := entity.FeedbackRating(req.Rating)
+	if !rating.IsValid() {
+		return dto.ErrorFeedbackResponse(ErrInvalidFeedbackRating), nil
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if utf8.RuneCountInString(comment) > entity.MaxFeedbackCommentLength {
+		return dto.ErrorFeedbackResponse(ErrFeedbackCommentTooLong), nil
+	}
+
+	message, err := uc.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return handleFeedbackError(err, "failed to find message")
+	}
+	session, err := uc.sessionRepo.FindByID(ctx, string(message.SessionID))
+	if err != nil {
+		return handleFeedbackError(err, "failed to find session")
+	}
+	workspace := WorkspaceFromContext(ctx)
+	if (userID != "" && string(session.UserID) != userID) || (!workspace.IsEmpty() && session.WorkspaceID != workspace) {
+		return handleFeedbackError(fmt.Errorf("message %w: %s", repository.ErrNotFound, messageID), "failed to find message")
+	}
+	if !message.IsFromAssistant() {
+		return dto.ErrorFeedbackResponse(ErrFeedbackNotReply), nil
+	}
+
+	feedback := entity.NewMessageFeedback(messageID, string(session.ID), string(session.UserID), rating, comment)
+	if err := uc.feedbackRepo.Save(ctx, feedback); err != nil {
+		return handleFeedbackError(err, "failed to save feedback")
+	}
+
+	uc.logger.Info("reply rated",
+		"message_id", messageID,
+		"session_id", session.ID,
+		"user_id", session.UserID,
+		"rating", rating,
+	)
+	uc.publishFeedbackEvent(ctx, feedback, message)
+
+	return dto.SuccessFeedbackResponse(dto.MessageFeedbackDTOFromEntity(feedback)), nil
+}
+
+// GetFeedbackAnalytics returns the ratings of every day of the requested range, including
+// days without ratings, and the totals of the range. A rating counts on the day it was last
+// changed.
+func (uc *FeedbackUseCase) GetFeedbackAnalytics(ctx context.Context, req dto.AnalyticsRequest) (*dto.FeedbackAnalyticsResponse, error) {
+	since, until, err := analyticsRange(req)
+	if err != nil {
+		return dto.ErrorFeedbackAnalyticsResponse(err), nil
+	}
+
+	counters, err := uc.feedbackRepo.CountByDay(ctx, since, until)
+	if err != nil {
+		return handleFeedbackAnalyticsError(err, "failed to count feedback")
+	}
+
+	resp := &dto.FeedbackAnalyticsResponse{Success: true, Since: since, Until: until, Totals: &dto.FeedbackCountsDTO{}}
+	days := make(map[string]*dto.FeedbackCountsDTO)
+	for day := since; day <= until; day = nextAnalyticsDay(day) {
+		counts := &dto.FeedbackCountsDTO{Day: day}
+		days[day] = counts
+		resp.Days = append(resp.Days, counts)
+	}
+
+	for _, counter := range counters {
+		if counts, ok := days[counter.Day]; ok {
+			addFeedbackCounter(counts, counter)
+			addFeedbackCounter(resp.Totals, counter)
+		}
+	}
+	for _, counts := range append(resp.Days, resp.Totals) {
+		if rated := counts.Up + counts.Down; rated > 0 {
+			counts.UpRatio = float64(counts.Up) / float64(rated)
+		}
+	}
+
+	return resp, nil
+}
+
+// publishFeedbackEvent publishes an EventFeedbackReceived event for a rated reply, with the
+// user message it answered
+func (uc *FeedbackUseCase) publishFeedbackEvent(ctx context.Context, feedback *entity.MessageFeedback, reply *entity.Message) {
+	if uc.eventBus == nil {
+		return
+	}
+
+	uc.eventBus.Publish(eventbus.NewFeedbackEvent(
+		eventbus.EventFeedbackReceived,
+		feedback.MessageID.String(),
+		feedback.SessionID.String(),
+		feedback.UserID.String(),
+		string(feedback.Rating),
+		feedback.Comment,
+		uc.prompt(ctx, reply),
+		reply.Content,
+	))
+}
+
+// prompt returns the last user message before a reply in its session, or "" if there is none
+// or the session could not be read
+func (uc *FeedbackUseCase) prompt(ctx context.Context, reply *entity.Message) string {
+	messages, err := uc.messageRepo.FindBySessionID(ctx, string(reply.SessionID), repository.QueryOptions{})
+	if err != nil {
+		uc.logger.Warn("failed to read the prompt of a rated reply", "message_id", reply.ID, "error", err)
+		return ""
+	}
+
+	prompt := ""
+	for _, message := range messages {
+		if message.ID == reply.ID {
+			break
+		}
+		if message.IsFromUser() {
+			prompt = message.Content
+		}
+	}
+	return prompt
+}
+
+// addFeedbackCounter adds an AnalyticsFeedback counter to the counts of its rating
+func addFeedbackCounter(counts *dto.FeedbackCountsDTO, counter *entity.AnalyticsCounter) {
+	switch entity.FeedbackRating(counter.Dimension) {
+	case entity.FeedbackUp:
+		counts.Up += int64(counter.Value)
+	case entity.FeedbackDown:
+		counts.Down += int64(counter.Value)
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockMessageFeedbackRepository is a mock implementation of MessageFeedbackRepository
+type MockMessageFeedbackRepository struct {
+	mock.Mock
+}
+
+func (m *MockMessageFeedbackRepository) Save(ctx context.Context, feedback *entity.MessageFeedback) error {
+	args := m.Called(ctx, feedback)
+	return args.Error(0)
+}
+
+func (m *MockMessageFeedbackRepository) FindByMessageID(ctx context.Context, messageID string) (*entity.MessageFeedback, error) {
+	args := m.Called(ctx, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.MessageFeedback), args.Error(1)
+}
+
+func (m *MockMessageFeedbackRepository) CountByDay(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	args := m.Called(ctx, since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.AnalyticsCounter), args.Error(1)
+}
+
+func TestFeedbackUseCase_RecordFeedback(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	feedbackRepo := new(MockMessageFeedbackRepository)
+	messageRepo := new(MockMessageRepository)
+	sessionRepo := new(MockSessionRepository)
+
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{BatchSize: 10, FlushInterval: 10 * time.Millisecond, Logger: logging.NewNoopLogger()})
+	require.NoError(t, bus.Start())
+	defer func() { _ = bus.Stop() }()
+	events := make(chan eventbus.Event, 1)
+	bus.SubscribeWithOptions([]string{eventbus.EventFeedbackReceived}, eventbus.SubscribeOptions{Name: "test"},
+		func(ctx context.Context, event eventbus.Event) error {
+			events <- event
+			return nil
+		})
+
+	uc := NewFeedbackUseCase(feedbackRepo, messageRepo, sessionRepo, logging.NewNoopLogger())
+	uc.SetEventBus(bus)
+
+	session := entity.NewSession("user-1")
+	question := entity.NewUserMessage(string(session.ID), "What is Go?")
+	reply := entity.NewAssistantMessage(string(session.ID), "A programming language.")
+	messageRepo.On("FindByID", ctx, string(reply.ID)).Return(reply, nil)
+	messageRepo.On("FindBySessionID", ctx, string(session.ID)).Return([]*entity.Message{question, reply}, nil)
+	sessionRepo.On("FindByID", ctx, string(session.ID)).Return(session, nil)
+	feedbackRepo.On("Save", ctx, mock.AnythingOfType("*entity.MessageFeedback")).Return(nil)
+
+	// Act
+	resp, err := uc.RecordFeedback(ctx, string(reply.ID), "user-1", dto.RecordFeedbackRequest{Rating: "down", Comment: "  Too short  "})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, string(reply.ID), resp.Feedback.MessageID)
+	assert.Equal(t, "user-1", resp.Feedback.UserID)
+	assert.Equal(t, "down", resp.Feedback.Rating)
+	assert.Equal(t, "Too short", resp.Feedback.Comment)
+
+	select {
+	case event := <-events:
+		feedbackEvent := event.(*eventbus.FeedbackEvent)
+		assert.Equal(t, string(reply.ID), feedbackEvent.MessageID)
+		assert.Equal(t, "down", feedbackEvent.Rating)
+		assert.Equal(t, "What is Go?", feedbackEvent.Prompt)
+		assert.Equal(t, "A programming language.", feedbackEvent.Response)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a feedback event")
+	}
+}
+
+func TestFeedbackUseCase_RecordFeedback_Rejected(t *testing.T) {
+	ctx := context.Background()
+	feedbackRepo := new(MockMessageFeedbackRepository)
+	messageRepo := new(MockMessageRepository)
+	sessionRepo := new(MockSessionRepository)
+	uc := NewFeedbackUseCase(feedbackRepo, messageRepo, sessionRepo, logging.NewNoopLogger())
+
+	session := entity.NewSession("user-1")
+	question := entity.NewUserMessage(string(session.ID), "What is Go?")
+	reply := entity.NewAssistantMessage(string(session.ID), "A programming language.")
+	messageRepo.On("FindByID", mock.Anything, string(question.ID)).Return(question, nil)
+	messageRepo.On("FindByID", mock.Anything, string(reply.ID)).Return(reply, nil)
+	messageRepo.On("FindByID", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+	sessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(session, nil)
+
+	t.Run("invalid rating", func(t *testing.T) {
+		resp, err := uc.RecordFeedback(ctx, string(reply.ID), "", dto.RecordFeedbackRequest{Rating: "meh"})
+		require.NoError(t, err)
+		assert.False(t, resp.Success)
+		assert.Contains(t, resp.Error, ErrInvalidFeedbackRating.Error())
+	})
+
+	t.Run("comment too long", func(t *testing.T) {
+		resp, err := uc.RecordFeedback(ctx, string(reply.ID), "", dto.RecordFeedbackRequest{Rating: "up", Comment: strings.Repeat("я", entity.MaxFeedbackCommentLength+1)})
+		require.NoError(t, err)
+		assert.Contains(t, resp.Error, ErrFeedbackCommentTooLong.Error())
+	})
+
+	t.Run("user message", func(t *testing.T) {
+		resp, err := uc.RecordFeedback(ctx, string(question.ID), "", dto.RecordFeedbackRequest{Rating: "up"})
+		require.NoError(t, err)
+		assert.Contains(t, resp.Error, ErrFeedbackNotReply.Error())
+	})
+
+	t.Run("missing message", func(t *testing.T) {
+		_, err := uc.RecordFeedback(ctx, "missing", "", dto.RecordFeedbackRequest{Rating: "up"})
+		assert.True(t, errors.Is(err, repository.ErrNotFound))
+	})
+
+	t.Run("message of another user", func(t *testing.T) {
+		_, err := uc.RecordFeedback(ctx, string(reply.ID), "user-2", dto.RecordFeedbackRequest{Rating: "up"})
+		assert.True(t, errors.Is(err, repository.ErrNotFound))
+	})
+
+	t.Run("message of another workspace", func(t *testing.T) {
+		_, err := uc.RecordFeedback(WithWorkspace(ctx, "support"), string(reply.ID), "", dto.RecordFeedbackRequest{Rating: "up"})
+		assert.True(t, errors.Is(err, repository.ErrNotFound))
+	})
+
+	feedbackRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestFeedbackUseCase_GetFeedbackAnalytics(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	feedbackRepo := new(MockMessageFeedbackRepository)
+	uc := NewFeedbackUseCase(feedbackRepo, new(MockMessageRepository), new(MockSessionRepository), logging.NewNoopLogger())
+	feedbackRepo.On("CountByDay", ctx, "2024-01-15", "2024-01-17").Return([]*entity.AnalyticsCounter{
+		{Day: "2024-01-15", Metric: entity.AnalyticsFeedback, Dimension: "up", Value: 3},
+		{Day: "2024-01-15", Metric: entity.AnalyticsFeedback, Dimension: "down", Value: 1},
+		{Day: "2024-01-17", Metric: entity.AnalyticsFeedback, Dimension: "down", Value: 2},
+	}, nil)
+
+	// Act
+	resp, err := uc.GetFeedbackAnalytics(ctx, dto.AnalyticsRequest{Since: "2024-01-15", Until: "2024-01-17"})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	require.Len(t, resp.Days, 3)
+	assert.Equal(t, &dto.FeedbackCountsDTO{Day: "2024-01-15", Up: 3, Down: 1, UpRatio: 0.75}, resp.Days[0])
+	assert.Equal(t, &dto.FeedbackCountsDTO{Day: "2024-01-16"}, resp.Days[1])
+	assert.Equal(t, &dto.FeedbackCountsDTO{Day: "2024-01-17", Down: 2}, resp.Days[2])
+	assert.Equal(t, &dto.FeedbackCountsDTO{Up: 3, Down: 3, UpRatio: 0.5}, resp.Totals)
+}
+
+func TestFeedbackUseCase_GetFeedbackAnalytics_Errors(t *testing.T) {
+	ctx := context.Background()
+	feedbackRepo := new(MockMessageFeedbackRepository)
+	uc := NewFeedbackUseCase(feedbackRepo, new(MockMessageRepository), new(MockSessionRepository), logging.NewNoopLogger())
+
+	resp, err := uc.GetFeedbackAnalytics(ctx, dto.AnalyticsRequest{Since: "2024-01-17", Until: "2024-01-15"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+
+	feedbackRepo.On("CountByDay", ctx, "2024-01-15", "2024-01-15").Return(nil, errors.New("db down"))
+	_, err = uc.GetFeedbackAnalytics(ctx, dto.AnalyticsRequest{Since: "2024-01-15", Until: "2024-01-15"})
+	assert.Error(t, err)
+}
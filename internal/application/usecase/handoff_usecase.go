@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Errors returned by the HandoffUseCase
+var (
+	ErrInvalidHandoffOperator = errors.New("operator is required")
+	ErrInvalidHandoffMode     = errors.New("mode must be forward or pause")
+
+	// ErrSessionNotHandedOff is returned for operator replies to a session no operator has taken over
+	ErrSessionNotHandedOff = fmt.Errorf("%w: session is not handed off", repository.ErrConflict)
+)
+
+// HandoffUseCase handles operator takeovers of sessions. While a session is handed off, the
+// router records user messages instead of passing them to the LLM, and the operator answers
+// through Reply. Handoffs are stored as a session attribute.
+type HandoffUseCase struct {
+	sessionRepo   repository.SessionRepository
+	userRepo      repository.UserRepository
+	messageRepo   repository.MessageRepository
+	attributeRepo repository.SessionAttributeRepository
+	sender        ports.MessageSender
+	logger        logging.Logger
+}
+
+// NewHandoffUseCase creates a new HandoffUseCase
+func NewHandoffUseCase(
+	sessionRepo repository.SessionRepository,
+	userRepo repository.UserRepository,
+	messageRepo repository.MessageRepository,
+	attributeRepo repository.SessionAttributeRepository,
+	sender ports.MessageSender,
+	logger logging.Logger,
+) *HandoffUseCase {
+	return &HandoffUseCase{
+		sessionRepo:   sessionRepo,
+		userRepo:      userRepo,
+		messageRepo:   messageRepo,
+		attributeRepo: attributeRepo,
+		sender:        sender,
+		logger:        logger,
+	}
+}
+
+// StartHandoff hands a session over to an operator, replacing an earlier handoff
+func (uc *HandoffUseCase) StartHandoff(ctx context.Context, sessionID string, req dto.StartHandoffRequest) (*dto.HandoffResponse, error) {
+	if req.Operator == "" {
+		return dto.ErrorHandoffResponse(ErrInvalidHandoffOperator), nil
+	}
+	mode := entity.HandoffModeForward
+	if req.Mode != "" {
+		mode = entity.HandoffMode(req.Mode)
+	}
+	if !mode.IsValid() {
+		return dto.ErrorHandoffResponse(ErrInvalidHandoffMode), nil
+	}
+
+	if _, err := uc.sessionRepo.FindByID(ctx, sessionID); err != nil {
+		return handleHandoffError(err, "failed to find session")
+	}
+
+	handoff := entity.NewSessionHandoff(req.Operator, mode)
+	if err := uc.attributeRepo.Set(ctx, handoff.ToAttribute(sessionID)); err != nil {
+		return handleHandoffError(err, "failed to start handoff")
+	}
+
+	uc.logger.Info("session handed off", "session_id", sessionID, "operator", handoff.Operator, "mode", handoff.Mode)
+	return dto.SuccessHandoffResponse(dto.SessionHandoffDTOFromEntity(sessionID, handoff)), nil
+}
+
+// GetHandoff retrieves the handoff of a session
+func (uc *HandoffUseCase) GetHandoff(ctx context.Context, sessionID string) (*dto.HandoffResponse, error) {
+	handoff, err := uc.findHandoff(ctx, sessionID)
+	if err != nil {
+		return handleHandoffError(err, "failed to find handoff")
+	}
+
+	return dto.SuccessHandoffResponse(dto.SessionHandoffDTOFromEntity(sessionID, handoff)), nil
+}
+
+// EndHandoff returns a session to the LLM
+func (uc *HandoffUseCase) EndHandoff(ctx context.Context, sessionID string) (*dto.HandoffResponse, error) {
+	deleted, err := uc.attributeRepo.Delete(ctx, sessionID, entity.SessionHandoffKey)
+	if err != nil {
+		return handleHandoffError(err, "failed to end handoff")
+	}
+	if !deleted {
+		return dto.ErrorHandoffResponse(fmt.Errorf("session is not handed off: %s", sessionID)), nil
+	}
+
+	uc.logger.Info("session handoff ended", "session_id", sessionID)
+	return &dto.HandoffResponse{Success: true}, nil
+}
+
+// Reply relays an operator reply to the user of a handed-off session through the user's connector
+// and records it as an assistant message of the session
+func (uc *HandoffUseCase) Reply(ctx context.Context, sessionID string, req dto.HandoffReplyRequest) (*dto.HandoffResponse, error) {
+	handoff, err := uc.findHandoff(ctx, sessionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return handleHandoffError(ErrSessionNotHandedOff, "failed to send reply")
+	}
+	if err != nil {
+		return handleHandoffError(err, "failed to find handoff")
+	}
+
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return handleHandoffError(err, "failed to find session")
+	}
+	user, err := uc.userRepo.FindByID(ctx, string(session.UserID))
+	if err != nil {
+		return handleHandoffError(err, "failed to find user")
+	}
+
+	if err := uc.sender.SendMessage(ctx, string(user.Channel), user.ChannelID, req.Content); err != nil {
+		return handleHandoffError(err, "failed to send reply")
+	}
+
+	message := entity.NewAssistantMessage(sessionID, req.Content)
+	if err := uc.messageRepo.Create(ctx, message); err != nil {
+		return handleHandoffError(err, "failed to save reply")
+	}
+
+	session.UpdateTimestamp()
+	if err := uc.sessionRepo.Update(ctx, session); err != nil {
+		uc.logger.Warn("failed to update session", "session_id", sessionID, "error", err)
+	}
+
+	uc.logger.Info("operator reply sent", "session_id", sessionID, "operator", handoff.Operator, "channel", user.Channel)
+	resp := dto.SuccessHandoffResponse(dto.SessionHandoffDTOFromEntity(sessionID, handoff))
+	resp.Message = dto.MessageDTOFromEntity(message)
+	return resp, nil
+}
+
+// findHandoff returns the handoff of a session, or repository.ErrNotFound if it is not handed off
+func (uc *HandoffUseCase) findHandoff(ctx context.Context, sessionID string) (*entity.SessionHandoff, error) {
+	attr, err := uc.attributeRepo.Get(ctx, sessionID, entity.SessionHandoffKey)
+	if err != nil {
+		return nil, err
+	}
+	return entity.SessionHandoffFromAttribute(attr)
+}
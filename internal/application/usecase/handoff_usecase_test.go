@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockMessageSender is a mock implementation of ports.MessageSender
+type MockMessageSender struct {
+	mock.Mock
+}
+
+func (m *MockMessageSender) SendMessage(ctx context.Context, connector, userID, message string) error {
+	args := m.Called(ctx, connector, userID, message)
+	return args.Error(0)
+}
+
+type handoffTestMocks struct {
+	sessions   *MockSessionRepository
+	users      *MockUserRepository
+	messages   *MockMessageRepository
+	attributes *MockSessionAttributeRepository
+	sender     *MockMessageSender
+}
+
+func newHandoffTestUseCase() (*HandoffUseCase, *handoffTestMocks) {
+	m := &handoffTestMocks{
+		sessions:   new(MockSessionRepository),
+		users:      new(MockUserRepository),
+		messages:   new(MockMessageRepository),
+		attributes: new(MockSessionAttributeRepository),
+		sender:     new(MockMessageSender),
+	}
+	uc := NewHandoffUseCase(m.sessions, m.users, m.messages, m.attributes, m.sender, logging.NewNoopLogger())
+	return uc, m
+}
+
+func TestHandoffUseCase_StartHandoff(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newHandoffTestUseCase()
+	session := entity.NewSession("user-1")
+	m.sessions.On("FindByID", ctx, session.ID.String()).Return(session, nil)
+	m.attributes.On("Set", ctx, mock.MatchedBy(func(attr *entity.SessionAttribute) bool {
+		return attr.Key == entity.SessionHandoffKey && string(attr.SessionID) == session.ID.String()
+	})).Return(nil)
+
+	// Act
+	resp, err := uc.StartHandoff(ctx, session.ID.String(), dto.StartHandoffRequest{Operator: "alice"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "alice", resp.Handoff.Operator)
+	assert.Equal(t, "forward", resp.Handoff.Mode)
+	m.attributes.AssertExpectations(t)
+}
+
+func TestHandoffUseCase_StartHandoff_Invalid(t *testing.T) {
+	ctx := context.Background()
+	uc, m := newHandoffTestUseCase()
+
+	resp, err := uc.StartHandoff(ctx, "session-1", dto.StartHandoffRequest{Operator: "alice", Mode: "mute"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, ErrInvalidHandoffMode.Error())
+
+	resp, err = uc.StartHandoff(ctx, "session-1", dto.StartHandoffRequest{})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+
+	m.sessions.On("FindByID", ctx, "missing").Return(nil, repository.ErrNotFound)
+	_, err = uc.StartHandoff(ctx, "missing", dto.StartHandoffRequest{Operator: "alice"})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestHandoffUseCase_Reply(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newHandoffTestUseCase()
+	user := entity.NewUser("telegram", "12345")
+	session := entity.NewSession(user.ID.String())
+	sessionID := session.ID.String()
+	m.attributes.On("Get", ctx, sessionID, entity.SessionHandoffKey).
+		Return(entity.NewSessionHandoff("alice", entity.HandoffModeForward).ToAttribute(sessionID), nil)
+	m.sessions.On("FindByID", ctx, sessionID).Return(session, nil)
+	m.sessions.On("Update", ctx, session).Return(nil)
+	m.users.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	m.sender.On("SendMessage", ctx, "telegram", "12345", "Let me check that for you").Return(nil)
+	m.messages.On("Create", ctx, mock.AnythingOfType("*entity.Message")).Return(nil)
+
+	// Act
+	resp, err := uc.Reply(ctx, sessionID, dto.HandoffReplyRequest{Content: "Let me check that for you"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "assistant", resp.Message.Role)
+	assert.Equal(t, "Let me check that for you", resp.Message.Content)
+	m.sender.AssertExpectations(t)
+	m.messages.AssertExpectations(t)
+}
+
+func TestHandoffUseCase_Reply_NotHandedOff(t *testing.T) {
+	ctx := context.Background()
+	uc, m := newHandoffTestUseCase()
+	m.attributes.On("Get", ctx, "session-1", entity.SessionHandoffKey).Return(nil, repository.ErrNotFound)
+
+	resp, err := uc.Reply(ctx, "session-1", dto.HandoffReplyRequest{Content: "Hello"})
+
+	assert.ErrorIs(t, err, ErrSessionNotHandedOff)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	assert.False(t, resp.Success)
+	m.sender.AssertNotCalled(t, "SendMessage")
+}
+
+func TestHandoffUseCase_Reply_SendFails(t *testing.T) {
+	ctx := context.Background()
+	uc, m := newHandoffTestUseCase()
+	user := entity.NewUser("discord", "67890")
+	session := entity.NewSession(user.ID.String())
+	sessionID := session.ID.String()
+	m.attributes.On("Get", ctx, sessionID, entity.SessionHandoffKey).
+		Return(entity.NewSessionHandoff("alice", entity.HandoffModePause).ToAttribute(sessionID), nil)
+	m.sessions.On("FindByID", ctx, sessionID).Return(session, nil)
+	m.users.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	m.sender.On("SendMessage", ctx, "discord", "67890", "Hello").Return(errors.New("connector not registered: discord"))
+
+	_, err := uc.Reply(ctx, sessionID, dto.HandoffReplyRequest{Content: "Hello"})
+
+	assert.Error(t, err)
+	m.messages.AssertNotCalled(t, "Create")
+}
+
+func TestHandoffUseCase_EndHandoff(t *testing.T) {
+	ctx := context.Background()
+	uc, m := newHandoffTestUseCase()
+	m.attributes.On("Delete", ctx, "session-1", entity.SessionHandoffKey).Return(true, nil).Once()
+	m.attributes.On("Delete", ctx, "session-1", entity.SessionHandoffKey).Return(false, nil).Once()
+
+	resp, err := uc.EndHandoff(ctx, "session-1")
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	resp, err = uc.EndHandoff(ctx, "session-1")
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Limits for the number of logs returned by ListLogs
+const (
+	defaultLogsLimit = 100
+	maxLogsLimit     = 1000
+)
+
+// LogUseCase handles the logs table: entries written through the API and the application logs
+// persisted by logging.DatabaseHandler
+type LogUseCase struct {
+	logRepo repository.LogRepository
+	logger  logging.Logger
+}
+
+// NewLogUseCase creates a new LogUseCase
+func NewLogUseCase(logRepo repository.LogRepository, logger logging.Logger) *LogUseCase {
+	return &LogUseCase{
+		logRepo: logRepo,
+		logger:  logger,
+	}
+}
+
+// CreateLog stores a log entry
+func (uc *LogUseCase) CreateLog(ctx context.Context, req dto.CreateLogRequest) (*dto.LogResponse, error) {
+	level := valueobject.LogLevel(req.Level)
+	if !level.IsValid() {
+		return dto.ErrorLogResponse(fmt.Errorf("%w: %q", valueobject.ErrInvalidLogLevel, req.Level)), nil
+	}
+
+	log := entity.NewLog(level, req.Source, req.Message, req.Metadata)
+	if err := uc.logRepo.Create(ctx, log); err != nil {
+		return dto.ErrorLogResponse(err), err
+	}
+
+	return dto.SuccessLogResponse(dto.LogDTOFromEntity(log)), nil
+}
+
+// ListLogs returns the stored logs, newest first.
+// A non-positive limit uses the default; limits above the maximum are capped.
+func (uc *LogUseCase) ListLogs(ctx context.Context, req dto.ListLogsRequest) (*dto.LogsResponse, error) {
+	if req.Level != "" && !valueobject.LogLevel(req.Level).IsValid() {
+		return dto.ErrorLogsResponse(fmt.Errorf("%w: %q", valueobject.ErrInvalidLogLevel, req.Level)), nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLogsLimit
+	}
+	limit = min(limit, maxLogsLimit)
+
+	logs, err := uc.logRepo.List(ctx, repository.LogFilter{
+		Level:  req.Level,
+		Source: req.Source,
+		Limit:  limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		return dto.ErrorLogsResponse(err), err
+	}
+
+	logDTOs := make([]*dto.LogDTO, 0, len(logs))
+	for _, log := range logs {
+		logDTOs = append(logDTOs, dto.LogDTOFromEntity(log))
+	}
+
+	return dto.SuccessLogsResponse(logDTOs), nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// MockLogRepository is a mock of the methods of LogRepository used by the LogUseCase
+type MockLogRepository struct {
+	repository.LogRepository
+	mock.Mock
+}
+
+func (m *MockLogRepository) Create(ctx context.Context, log *entity.Log) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockLogRepository) List(ctx context.Context, filter repository.LogFilter) ([]*entity.Log, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Log), args.Error(1)
+}
+
+func TestLogUseCase_CreateLog(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockLogRepository)
+	uc := NewLogUseCase(mockRepo, new(MockLogger))
+
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(log *entity.Log) bool {
+		return log.Level == valueobject.LogLevelWarn && log.Source == "client" && log.Message == "slow response"
+	})).Return(nil)
+
+	resp, err := uc.CreateLog(ctx, dto.CreateLogRequest{Level: "warn", Source: "client", Message: "slow response"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.NotEmpty(t, resp.Log.ID)
+	assert.Equal(t, "warn", resp.Log.Level)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLogUseCase_ListLogs(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockLogRepository)
+	uc := NewLogUseCase(mockRepo, new(MockLogger))
+
+	log := entity.NewLog(valueobject.LogLevelError, "telegram", "connector failed", nil)
+	mockRepo.On("List", ctx, repository.LogFilter{Level: "error", Source: "telegram", Limit: defaultLogsLimit, Offset: 5}).
+		Return([]*entity.Log{log}, nil)
+
+	resp, err := uc.ListLogs(ctx, dto.ListLogsRequest{Level: "error", Source: "telegram", Offset: 5})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	require.Len(t, resp.Logs, 1)
+	assert.Equal(t, string(log.ID), resp.Logs[0].ID)
+	assert.Equal(t, "connector failed", resp.Logs[0].Message)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLogUseCase_ListLogs_CapsLimit(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockLogRepository)
+	uc := NewLogUseCase(mockRepo, new(MockLogger))
+
+	mockRepo.On("List", ctx, repository.LogFilter{Limit: maxLogsLimit}).Return([]*entity.Log{}, nil)
+
+	resp, err := uc.ListLogs(ctx, dto.ListLogsRequest{Limit: 10000})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLogUseCase_ListLogs_InvalidLevel(t *testing.T) {
+	mockRepo := new(MockLogRepository)
+	uc := NewLogUseCase(mockRepo, new(MockLogger))
+
+	resp, err := uc.ListLogs(context.Background(), dto.ListLogsRequest{Level: "verbose"})
+
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "invalid log level")
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestLogUseCase_ListLogs_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockLogRepository)
+	uc := NewLogUseCase(mockRepo, new(MockLogger))
+
+	mockRepo.On("List", ctx, mock.Anything).Return(nil, errors.New("database is locked"))
+
+	resp, err := uc.ListLogs(ctx, dto.ListLogsRequest{})
+
+	assert.Error(t, err)
+	assert.False(t, resp.Success)
+}
@@ -0,0 +1,470 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Errors returned by the MessageJobUseCase
+var (
+	ErrMessageJobQueueFull = errors.New("too many messages are waiting to be answered, retry later")
+	ErrMessageJobsStopped  = errors.New("messages are not accepted while the server shuts down")
+	ErrInvalidMessageBatch = errors.New("invalid message batch")
+)
+
+// Reasons of failed jobs that were never answered
+const (
+	messageJobInterrupted = "interrupted by a restart before the message was answered"
+	messageJobShutdown    = "not answered before shutdown"
+)
+
+// messageJobKind is the kind of the jobs of the job queue answering asynchronous messages
+const messageJobKind = "message_job"
+
+// MessageJobConfig configures the background answering of asynchronous messages
+type MessageJobConfig struct {
+	Workers      int           // Number of messages answered at the same time
+	QueueSize    int           // Number of messages waiting for a worker
+	MaxBatchSize int           // Maximum number of prompts of a batch
+	TTL          time.Duration // How long finished jobs are kept for polling
+}
+
+// queuedMessageJob is a job waiting for a worker with the message it answers
+type queuedMessageJob struct {
+	ctx context.Context
+	job *entity.MessageJob
+	req dto.SendMessageRequest
+}
+
+// messageJobPayload is the payload of a job of the job queue answering an asynchronous message.
+// The workspace of the request is restored from it, the rest of the request context is not kept.
+type messageJobPayload struct {
+	JobID     string                  `json:"job_id"`
+	Workspace valueobject.WorkspaceID `json:"workspace,omitempty"`
+	Request   dto.SendMessageRequest  `json:"request"`
+}
+
+// MessageJobUseCase answers messages in the background, so that HTTP clients get a job ID
+// immediately and poll it for the reply instead of holding the connection during the LLM call.
+// Messages are answered by the orchestrator like connector messages, or by the ChatUseCase
+// without one.
+type MessageJobUseCase struct {
+	chat         *ChatUseCase
+	orchestrator ports.Orchestrator
+	jobRepo      repository.MessageJobRepository
+	jobQueue     ports.JobQueue
+	config       MessageJobConfig
+	logger       logging.Logger
+	queue        chan queuedMessageJob
+	now          func() time.Time
+
+	mu       sync.Mutex
+	started  bool
+	stopping chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewMessageJobUseCase creates a new MessageJobUseCase answering messages with chat
+func NewMessageJobUseCase(chat *ChatUseCase, jobRepo repository.MessageJobRepository, config MessageJobConfig, logger logging.Logger) *MessageJobUseCase {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MessageJobUseCase{
+		chat:     chat,
+		jobRepo:  jobRepo,
+		config:   config,
+		logger:   logger,
+		queue:    make(chan queuedMessageJob, config.QueueSize),
+		now:      time.Now,
+		stopping: make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetOrchestrator sets the orchestrator answering the messages, so that they are planned and
+// traced like connector messages. It must be called before Start.
+func (uc *MessageJobUseCase) SetOrchestrator(orchestrator ports.Orchestrator) {
+	uc.orchestrator = orchestrator
+}
+
+// SetJobQueue sets the persistent job queue the messages are answered from instead of the
+// in-memory queue, so that messages accepted before a restart are still answered. The workers
+// and the queue size of the configuration are then unused. It must be called before Start.
+func (uc *MessageJobUseCase) SetJobQueue(queue ports.JobQueue) {
+	uc.jobQueue = queue
+	queue.Register(messageJobKind, uc.handleJob)
+}
+
+// Start fails the jobs left unfinished by a previous process and starts the workers.
+// With a job queue, unfinished jobs are answered by the queue instead.
+func (uc *MessageJobUseCase) Start() error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.started {
+		return fmt.Errorf("message jobs already started")
+	}
+
+	if uc.jobQueue != nil {
+		uc.started = true
+		uc.logger.Info("message jobs started", "queue", "persistent")
+		return nil
+	}
+
+	n, err := uc.jobRepo.FailUnfinished(uc.ctx, messageJobInterrupted, uc.now())
+	if err != nil {
+		return fmt.Errorf("failed to fail interrupted message jobs: %w", err)
+	}
+	if n > 0 {
+		uc.logger.Warn("failed message jobs interrupted by a restart", "count", n)
+	}
+
+	for i := 0; i < uc.config.Workers; i++ {
+		uc.wg.Add(1)
+		go uc.worker()
+	}
+	uc.started = true
+
+	uc.logger.Info("message jobs started", "workers", uc.config.Workers)
+	return nil
+}
+
+// Stop stops accepting messages and waits for the workers to finish the messages they are
+// answering; the LLM calls still running when ctx is done are cancelled. Queued messages are
+// failed.
+func (uc *MessageJobUseCase) Stop(ctx context.Context) error {
+	uc.mu.Lock()
+	if !uc.started {
+		uc.mu.Unlock()
+		return nil
+	}
+	uc.started = false
+	close(uc.stopping)
+	uc.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		uc.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		uc.cancel()
+		<-done
+	}
+	uc.cancel()
+
+	for {
+		select {
+		case queued := <-uc.queue:
+			uc.fail(queued.ctx, queued.job, messageJobShutdown)
+		default:
+			uc.logger.Info("message jobs stopped")
+			return nil
+		}
+	}
+}
+
+// SendMessageAsync creates a job answering a message and queues it. The session is resolved
+// immediately, so that the job can be polled by session and a missing session is reported
+// before the message is accepted.
+func (uc *MessageJobUseCase) SendMessageAsync(ctx context.Context, req dto.SendMessageRequest) (*dto.MessageJobResponse, error) {
+	uc.mu.Lock()
+	started := uc.started
+	uc.mu.Unlock()
+	if !started {
+		return dto.ErrorMessageJobResponse(ErrMessageJobsStopped), ErrMessageJobsStopped
+	}
+
+	session, err := uc.chat.resolveSendSession(ctx, req)
+	if err != nil {
+		return handleMessageJobError(err, "failed to get session")
+	}
+
+	job := entity.NewMessageJob(session.ID.String(), req.Message.Content)
+	if err := uc.jobRepo.Create(ctx, job); err != nil {
+		return handleMessageJobError(err, "failed to create message job")
+	}
+
+	// The response is built before the worker can update the job
+	resp := dto.SuccessMessageJobResponse(dto.MessageJobDTOFromEntity(job))
+	req.Options.SessionID = session.ID.String()
+	if err := uc.enqueue(queuedMessageJob{ctx: context.WithoutCancel(ctx), job: job, req: req}); err != nil {
+		return dto.ErrorMessageJobResponse(err), err
+	}
+
+	return resp, nil
+}
+
+// SendMessageBatch creates a job answering every prompt of a batch and queues them together,
+// for offline workloads such as evaluations or bulk classification. Prompts without a session
+// are answered in a new session of the user each, so that they do not see each other. The batch
+// is rejected as a whole when the queue cannot hold all of its prompts.
+func (uc *MessageJobUseCase) SendMessageBatch(ctx context.Context, req dto.SendMessageBatchRequest) (*dto.MessageBatchResponse, error) {
+	if err := uc.validateBatch(req); err != nil {
+		return dto.ErrorMessageBatchResponse(err), nil
+	}
+
+	uc.mu.Lock()
+	started := uc.started
+	uc.mu.Unlock()
+	if !started {
+		return dto.ErrorMessageBatchResponse(ErrMessageJobsStopped), ErrMessageJobsStopped
+	}
+
+	// Sessions are resolved first, so that a missing session rejects the batch without jobs
+	batchID := valueobject.GenerateID(nil).String()
+	queued := make([]queuedMessageJob, 0, len(req.Items))
+	for i, item := range req.Items {
+		itemReq := dto.SendMessageRequest{
+			UserID:  req.UserID,
+			Message: dto.ChatMessage{Role: "user", Content: item.Content},
+			Options: req.Options,
+		}
+		itemReq.Options.SessionID = item.SessionID
+		session, err := uc.chat.resolveSendSession(ctx, itemReq)
+		if err != nil {
+			return handleMessageBatchError(err, fmt.Sprintf("failed to get session of item %d", i))
+		}
+		itemReq.Options.SessionID = session.ID.String()
+		queued = append(queued, queuedMessageJob{
+			ctx: context.WithoutCancel(ctx),
+			job: entity.NewBatchMessageJob(batchID, i, session.ID.String(), item.Content),
+			req: itemReq,
+		})
+	}
+
+	jobs := make([]*entity.MessageJob, 0, len(queued))
+	for _, q := range queued {
+		if err := uc.jobRepo.Create(ctx, q.job); err != nil {
+			for _, job := range jobs {
+				uc.fail(q.ctx, job, "batch not accepted")
+			}
+			return handleMessageBatchError(err, "failed to create message job")
+		}
+		jobs = append(jobs, q.job)
+	}
+
+	// The response is built before the workers can update the jobs
+	resp := dto.SuccessMessageBatchResponse(dto.MessageBatchDTOFromEntities(batchID, jobs))
+	if err := uc.enqueue(queued...); err != nil {
+		return dto.ErrorMessageBatchResponse(err), err
+	}
+
+	uc.logger.WithContext(ctx).Info("message batch queued", "batch_id", batchID, "user_id", req.UserID, "items", len(jobs))
+	return resp, nil
+}
+
+// GetMessageBatch returns the jobs of a batch with the replies of the answered prompts
+func (uc *MessageJobUseCase) GetMessageBatch(ctx context.Context, id string) (*dto.MessageBatchResponse, error) {
+	jobs, err := uc.jobRepo.FindByBatchID(ctx, id)
+	if err != nil {
+		return handleMessageBatchError(err, "failed to find message batch")
+	}
+	if len(jobs) == 0 {
+		return handleMessageBatchError(fmt.Errorf("message batch %w: %s", repository.ErrNotFound, id), "failed to find message batch")
+	}
+
+	checked := make(map[string]bool)
+	for _, job := range jobs {
+		sessionID := job.SessionID.String()
+		if checked[sessionID] {
+			continue
+		}
+		if err := uc.chat.checkSessionWorkspace(ctx, sessionID); err != nil {
+			return handleMessageBatchError(fmt.Errorf("message batch %w: %s", repository.ErrNotFound, id), "failed to find message batch")
+		}
+		checked[sessionID] = true
+	}
+	return dto.SuccessMessageBatchResponse(dto.MessageBatchDTOFromEntities(id, jobs)), nil
+}
+
+// validateBatch checks the number of prompts of a batch and their content
+func (uc *MessageJobUseCase) validateBatch(req dto.SendMessageBatchRequest) error {
+	if len(req.Items) == 0 || len(req.Items) > uc.config.MaxBatchSize {
+		return fmt.Errorf("%w: items must contain 1 to %d prompts, got %d", ErrInvalidMessageBatch, uc.config.MaxBatchSize, len(req.Items))
+	}
+	for i, item := range req.Items {
+		if strings.TrimSpace(item.Content) == "" {
+			return fmt.Errorf("%w: items[%d].content is required", ErrInvalidMessageBatch, i)
+		}
+	}
+	return nil
+}
+
+// enqueue queues jobs for the workers, all or none. The jobs are failed when the use case is
+// stopped or the queue cannot hold them. With a job queue, the jobs that could not be stored are
+// failed, those stored before are still answered.
+func (uc *MessageJobUseCase) enqueue(queued ...queuedMessageJob) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.jobQueue != nil && uc.started {
+		for i, q := range queued {
+			payload := messageJobPayload{JobID: q.job.ID, Workspace: WorkspaceFromContext(q.ctx), Request: q.req}
+			if err := uc.jobQueue.Enqueue(q.ctx, messageJobKind, payload, ports.JobOptions{MaxAttempts: 1}); err != nil {
+				for _, rest := range queued[i:] {
+					uc.fail(rest.ctx, rest.job, "not queued")
+				}
+				return fmt.Errorf("failed to queue message job: %w", err)
+			}
+		}
+		return nil
+	}
+
+	var err error
+	switch {
+	case !uc.started:
+		err = ErrMessageJobsStopped
+	case cap(uc.queue)-len(uc.queue) < len(queued):
+		err = ErrMessageJobQueueFull
+	}
+	if err != nil {
+		reason := messageJobShutdown
+		if errors.Is(err, ErrMessageJobQueueFull) {
+			reason = "queue full"
+		}
+		for _, q := range queued {
+			uc.fail(q.ctx, q.job, reason)
+		}
+		return err
+	}
+
+	// Only enqueue sends to the queue, under the lock, so the free capacity cannot shrink
+	for _, q := range queued {
+		uc.queue <- q
+	}
+	return nil
+}
+
+// GetMessageJob returns a job with the reply once its message is answered
+func (uc *MessageJobUseCase) GetMessageJob(ctx context.Context, id string) (*dto.MessageJobResponse, error) {
+	job, err := uc.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		return handleMessageJobError(err, "failed to find message job")
+	}
+	if err := uc.chat.checkSessionWorkspace(ctx, job.SessionID.String()); err != nil {
+		return handleMessageJobError(fmt.Errorf("message job %w: %s", repository.ErrNotFound, id), "failed to find message job")
+	}
+	return dto.SuccessMessageJobResponse(dto.MessageJobDTOFromEntity(job)), nil
+}
+
+// PurgeFinished deletes the jobs that finished longer than the TTL ago
+func (uc *MessageJobUseCase) PurgeFinished(ctx context.Context) error {
+	n, err := uc.jobRepo.DeleteFinishedBefore(ctx, uc.now().Add(-uc.config.TTL))
+	if err != nil {
+		return fmt.Errorf("failed to purge finished message jobs: %w", err)
+	}
+	if n > 0 {
+		uc.logger.WithContext(ctx).Debug("purged finished message jobs", "count", n)
+	}
+	return nil
+}
+
+// worker answers queued messages until the use case is stopped
+func (uc *MessageJobUseCase) worker() {
+	defer uc.wg.Done()
+	for {
+		// A stop takes precedence over the queued messages, which are failed by Stop
+		select {
+		case <-uc.stopping:
+			return
+		default:
+		}
+		select {
+		case <-uc.stopping:
+			return
+		case queued := <-uc.queue:
+			uc.process(uc.ctx, queued)
+		}
+	}
+}
+
+// handleJob answers the message of a job of the job queue, unless it was already answered,
+// e.g. before the lease of the queue job expired
+func (uc *MessageJobUseCase) handleJob(ctx context.Context, job *entity.Job) error {
+	var payload messageJobPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("%w: invalid message job payload: %v", ports.ErrJobPermanent, err)
+	}
+
+	messageJob, err := uc.jobRepo.FindByID(ctx, payload.JobID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find message job: %w", err)
+	}
+	if messageJob.Status.IsFinished() {
+		return nil
+	}
+
+	jobCtx := context.WithoutCancel(ctx)
+	if !payload.Workspace.IsEmpty() {
+		jobCtx = WithWorkspace(jobCtx, payload.Workspace)
+	}
+	uc.process(ctx, queuedMessageJob{ctx: jobCtx, job: messageJob, req: payload.Request})
+	return nil
+}
+
+// process answers the message of a job and saves the outcome. The LLM call is cancelled
+// when stop is done.
+func (uc *MessageJobUseCase) process(stop context.Context, queued queuedMessageJob) {
+	job := queued.job
+	logger := uc.logger.WithContext(queued.ctx)
+
+	job.Start()
+	if err := uc.jobRepo.Update(queued.ctx, job); err != nil {
+		logger.Error("failed to update message job", "job_id", job.ID, "error", err)
+	}
+
+	ctx, cancel := context.WithCancel(queued.ctx)
+	stopped := context.AfterFunc(stop, cancel)
+	var resp *dto.SendMessageResponse
+	var err error
+	if uc.orchestrator != nil {
+		resp, err = uc.orchestrator.ProcessMessage(ctx, queued.req.UserID, queued.req.Message.Content, queued.req.Options)
+	} else {
+		resp, err = uc.chat.SendMessage(ctx, queued.req)
+	}
+	stopped()
+	cancel()
+
+	switch {
+	case err != nil:
+		job.Fail(err.Error())
+	case !resp.Success:
+		job.Fail(resp.Error)
+	case resp.Message == nil:
+		job.Fail("no reply was generated")
+	default:
+		job.Complete(resp.Message.ID, resp.Message.Content)
+	}
+	if err := uc.jobRepo.Update(queued.ctx, job); err != nil {
+		logger.Error("failed to update message job", "job_id", job.ID, "error", err)
+		return
+	}
+	logger.Info("message job finished", "job_id", job.ID, "session_id", job.SessionID, "status", job.Status)
+}
+
+// fail records a job that will not be answered as failed
+func (uc *MessageJobUseCase) fail(ctx context.Context, job *entity.MessageJob, reason string) {
+	job.Fail(reason)
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		uc.logger.WithContext(ctx).Error("failed to update message job", "job_id", job.ID, "error", err)
+	}
+}
@@ -0,0 +1,423 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// memoryMessageJobRepository is an in-memory repository.MessageJobRepository
+type memoryMessageJobRepository struct {
+	mu   sync.Mutex
+	jobs map[string]entity.MessageJob
+}
+
+func newMemoryMessageJobRepository() *memoryMessageJobRepository {
+	return &memoryMessageJobRepository{jobs: make(map[string]entity.MessageJob)}
+}
+
+func (m *memoryMessageJobRepository) Create(ctx context.Context, job *entity.MessageJob) error {
+	return m.Update(ctx, job)
+}
+
+func (m *memoryMessageJobRepository) FindByID(ctx context.Context, id string) (*entity.MessageJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("message job %w: %s", repository.ErrNotFound, id)
+	}
+	return &job, nil
+}
+
+func (m *memoryMessageJobRepository) FindByBatchID(ctx context.Context, batchID string) ([]*entity.MessageJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var jobs []*entity.MessageJob
+	for _, job := range m.jobs {
+		if job.BatchID == batchID {
+			job := job
+			jobs = append(jobs, &job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].BatchIndex < jobs[j].BatchIndex })
+	return jobs, nil
+}
+
+func (m *memoryMessageJobRepository) Update(ctx context.Context, job *entity.MessageJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *memoryMessageJobRepository) FailUnfinished(ctx context.Context, reason string, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for id, job := range m.jobs {
+		if !job.Status.IsFinished() {
+			job.Fail(reason)
+			m.jobs[id] = job
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memoryMessageJobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for id, job := range m.jobs {
+		if job.FinishedAt != nil && !job.FinishedAt.After(before) {
+			delete(m.jobs, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// waitForMessageJob polls a job until it is finished
+func waitForMessageJob(t *testing.T, uc *MessageJobUseCase, id string) *dto.MessageJobDTO {
+	t.Helper()
+	var job *dto.MessageJobDTO
+	require.Eventually(t, func() bool {
+		resp, err := uc.GetMessageJob(context.Background(), id)
+		require.NoError(t, err)
+		job = resp.Job
+		return entity.MessageJobStatus(job.Status).IsFinished()
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestMessageJobUseCase_SendMessageAsync(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	sessionRepo := new(MockSessionRepository)
+	messageRepo := new(MockMessageRepository)
+	llmProvider := new(MockLLMProvider)
+	chat := NewChatUseCase(new(MockUserRepository), sessionRepo, messageRepo, new(MockTaskRepository), llmProvider, new(MockSkillRuntime), logging.NewNoopLogger())
+	jobRepo := newMemoryMessageJobRepository()
+
+	interrupted := entity.NewMessageJob("session-0", "Before the restart")
+	require.NoError(t, jobRepo.Create(ctx, interrupted))
+
+	uc := NewMessageJobUseCase(chat, jobRepo, MessageJobConfig{Workers: 1, QueueSize: 10, TTL: time.Hour}, logging.NewNoopLogger())
+	require.NoError(t, uc.Start())
+	defer func() { _ = uc.Stop(ctx) }()
+
+	session := entity.NewSession("user-1")
+	sessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(session, nil)
+	sessionRepo.On("FindByID", mock.Anything, "missing").Return(nil, fmt.Errorf("session %w", repository.ErrNotFound))
+	sessionRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	messageRepo.On("FindBySessionID", mock.Anything, mock.Anything).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Hello")}, nil)
+	llmProvider.On("Generate", mock.Anything, mock.Anything).Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Hi there!"}}, nil).Once()
+	llmProvider.On("Generate", mock.Anything, mock.Anything).Return(nil, errors.New("provider unavailable")).Once()
+
+	// Act
+	resp, err := uc.SendMessageAsync(ctx, dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Hello"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, string(session.ID), resp.Job.SessionID)
+	assert.Equal(t, "Hello", resp.Job.Content)
+
+	job := waitForMessageJob(t, uc, resp.Job.ID)
+	assert.Equal(t, string(entity.MessageJobCompleted), job.Status)
+	assert.Equal(t, "Hi there!", job.Reply)
+	assert.NotEmpty(t, job.MessageID)
+	assert.NotEmpty(t, job.FinishedAt)
+
+	// Failed LLM calls fail the job
+	resp, err = uc.SendMessageAsync(ctx, dto.SendMessageRequest{
+		Message: dto.ChatMessage{Role: "user", Content: "Again"},
+		Options: dto.MessageOptions{SessionID: string(session.ID)},
+	})
+	require.NoError(t, err)
+	job = waitForMessageJob(t, uc, resp.Job.ID)
+	assert.Equal(t, string(entity.MessageJobFailed), job.Status)
+	assert.Contains(t, job.Error, "provider unavailable")
+
+	// Missing sessions are reported before a job is created
+	_, err = uc.SendMessageAsync(ctx, dto.SendMessageRequest{Options: dto.MessageOptions{SessionID: "missing"}})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Len(t, jobRepo.jobs, 3)
+
+	// Jobs left by a previous process are failed on start
+	restarted, err := jobRepo.FindByID(ctx, interrupted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageJobFailed, restarted.Status)
+	assert.Equal(t, messageJobInterrupted, restarted.Error)
+}
+
+// fakeJobQueue keeps the queued jobs until they are run by the test
+type fakeJobQueue struct {
+	handlers map[string]ports.JobHandler
+	jobs     []*entity.Job
+}
+
+func newFakeJobQueue() *fakeJobQueue {
+	return &fakeJobQueue{handlers: make(map[string]ports.JobHandler)}
+}
+
+func (q *fakeJobQueue) Register(kind string, handler ports.JobHandler) {
+	q.handlers[kind] = handler
+}
+
+func (q *fakeJobQueue) Enqueue(ctx context.Context, kind string, payload any, opts ports.JobOptions) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	q.jobs = append(q.jobs, entity.NewJob(kind, opts.Key, string(data), opts.MaxAttempts))
+	return nil
+}
+
+// run runs the queued jobs with the handlers of their kinds, e.g. after a restart
+func (q *fakeJobQueue) run(t *testing.T) {
+	t.Helper()
+	jobs := q.jobs
+	q.jobs = nil
+	for _, job := range jobs {
+		require.NoError(t, q.handlers[job.Kind](context.Background(), job))
+	}
+}
+
+func TestMessageJobUseCase_JobQueue(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	sessionRepo := new(MockSessionRepository)
+	messageRepo := new(MockMessageRepository)
+	llmProvider := new(MockLLMProvider)
+	chat := NewChatUseCase(new(MockUserRepository), sessionRepo, messageRepo, new(MockTaskRepository), llmProvider, new(MockSkillRuntime), logging.NewNoopLogger())
+	jobRepo := newMemoryMessageJobRepository()
+
+	// A job accepted before a restart is still answered by the queue
+	accepted := entity.NewMessageJob("session-0", "Before the restart")
+	require.NoError(t, jobRepo.Create(ctx, accepted))
+
+	queue := newFakeJobQueue()
+	uc := NewMessageJobUseCase(chat, jobRepo, MessageJobConfig{Workers: 1, QueueSize: 1, TTL: time.Hour}, logging.NewNoopLogger())
+	uc.SetJobQueue(queue)
+	require.NoError(t, uc.Start())
+	defer func() { _ = uc.Stop(ctx) }()
+
+	session := entity.NewSession("user-1")
+	session.WorkspaceID = "team-a"
+	sessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(session, nil)
+	sessionRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	messageRepo.On("FindBySessionID", mock.Anything, mock.Anything).Return([]*entity.Message{entity.NewUserMessage(string(session.ID), "Hello")}, nil)
+	llmProvider.On("Generate", mock.Anything, mock.Anything).Return(&ports.CompletionResponse{Message: ports.Message{Role: "assistant", Content: "Hi there!"}}, nil)
+
+	// Act: the queue size of the in-memory queue does not apply
+	wsCtx := WithWorkspace(ctx, "team-a")
+	req := dto.SendMessageRequest{Message: dto.ChatMessage{Role: "user", Content: "Hello"}, Options: dto.MessageOptions{SessionID: string(session.ID)}}
+	first, err := uc.SendMessageAsync(wsCtx, req)
+	require.NoError(t, err)
+	second, err := uc.SendMessageAsync(wsCtx, req)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, queue.jobs, 2)
+	assert.Equal(t, messageJobKind, queue.jobs[0].Kind)
+	assert.Contains(t, queue.jobs[0].Payload, `"workspace":"team-a"`)
+	restarted, err := jobRepo.FindByID(ctx, accepted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageJobPending, restarted.Status)
+
+	queue.run(t)
+	for _, id := range []string{first.Job.ID, second.Job.ID} {
+		job, err := jobRepo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, entity.MessageJobCompleted, job.Status)
+		assert.Equal(t, "Hi there!", job.Reply)
+	}
+}
+
+func TestMessageJobUseCase_WorkspaceIsolation(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := new(MockSessionRepository)
+	chat := NewChatUseCase(new(MockUserRepository), sessionRepo, new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), logging.NewNoopLogger())
+	jobRepo := newMemoryMessageJobRepository()
+	uc := NewMessageJobUseCase(chat, jobRepo, MessageJobConfig{Workers: 1, QueueSize: 1, TTL: time.Hour}, logging.NewNoopLogger())
+
+	session := entity.NewSession("user-1")
+	session.WorkspaceID = valueobject.WorkspaceID("acme")
+	sessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(session, nil)
+	job := entity.NewMessageJob(string(session.ID), "Hello")
+	require.NoError(t, jobRepo.Create(ctx, job))
+
+	resp, err := uc.GetMessageJob(WithWorkspace(ctx, "acme"), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, resp.Job.ID)
+
+	_, err = uc.GetMessageJob(WithWorkspace(ctx, "other"), job.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestMessageJobUseCase_QueueAndStop(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := new(MockSessionRepository)
+	messageRepo := new(MockMessageRepository)
+	llmProvider := new(MockLLMProvider)
+	chat := NewChatUseCase(new(MockUserRepository), sessionRepo, messageRepo, new(MockTaskRepository), llmProvider, new(MockSkillRuntime), logging.NewNoopLogger())
+	jobRepo := newMemoryMessageJobRepository()
+	uc := NewMessageJobUseCase(chat, jobRepo, MessageJobConfig{Workers: 1, QueueSize: 1, TTL: time.Hour}, logging.NewNoopLogger())
+
+	session := entity.NewSession("user-1")
+	sessionRepo.On("FindByID", mock.Anything, string(session.ID)).Return(session, nil)
+	sessionRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	messageRepo.On("FindBySessionID", mock.Anything, mock.Anything).Return([]*entity.Message{}, nil)
+
+	// The first message blocks the only worker until the LLM call is cancelled
+	generating := make(chan struct{})
+	llmProvider.On("Generate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(generating)
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, context.Canceled).Once()
+
+	req := dto.SendMessageRequest{Message: dto.ChatMessage{Role: "user", Content: "Hello"}, Options: dto.MessageOptions{SessionID: string(session.ID)}}
+	_, err := uc.SendMessageAsync(ctx, req)
+	assert.ErrorIs(t, err, ErrMessageJobsStopped)
+
+	require.NoError(t, uc.Start())
+	running, err := uc.SendMessageAsync(ctx, req)
+	require.NoError(t, err)
+	<-generating
+	queued, err := uc.SendMessageAsync(ctx, req)
+	require.NoError(t, err)
+
+	_, err = uc.SendMessageAsync(ctx, req)
+	assert.ErrorIs(t, err, ErrMessageJobQueueFull)
+
+	// Stopping cancels the running message once the deadline passes and fails the queued one
+	stopCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	require.NoError(t, uc.Stop(stopCtx))
+
+	job, err := jobRepo.FindByID(ctx, running.Job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageJobFailed, job.Status)
+	job, err = jobRepo.FindByID(ctx, queued.Job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageJobFailed, job.Status)
+	assert.Equal(t, messageJobShutdown, job.Error)
+
+	// Finished jobs are purged after the TTL
+	uc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	require.NoError(t, uc.PurgeFinished(ctx))
+	assert.Empty(t, jobRepo.jobs)
+}
+
+// stubOrchestrator answers messages with their content in upper case
+type stubOrchestrator struct {
+	ports.Orchestrator
+
+	mu    sync.Mutex
+	users []string
+}
+
+func (o *stubOrchestrator) ProcessMessage(ctx context.Context, userID, content string, options dto.MessageOptions) (*dto.SendMessageResponse, error) {
+	o.mu.Lock()
+	o.users = append(o.users, userID)
+	o.mu.Unlock()
+	if content == "fail" {
+		return nil, errors.New("provider unavailable")
+	}
+	return &dto.SendMessageResponse{
+		Success: true,
+		Message: &dto.MessageDTO{ID: "reply-" + content, SessionID: options.SessionID, Role: "assistant", Content: strings.ToUpper(content)},
+	}, nil
+}
+
+func TestMessageJobUseCase_SendMessageBatch(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(MockSessionRepository)
+	chat := NewChatUseCase(userRepo, sessionRepo, new(MockMessageRepository), new(MockTaskRepository), new(MockLLMProvider), new(MockSkillRuntime), logging.NewNoopLogger())
+	jobRepo := newMemoryMessageJobRepository()
+	uc := NewMessageJobUseCase(chat, jobRepo, MessageJobConfig{Workers: 2, QueueSize: 3, MaxBatchSize: 3, TTL: time.Hour}, logging.NewNoopLogger())
+	orchestrator := &stubOrchestrator{}
+	uc.SetOrchestrator(orchestrator)
+	require.NoError(t, uc.Start())
+	defer func() { _ = uc.Stop(ctx) }()
+
+	existing := entity.NewSession("user-1")
+	userRepo.On("FindByChannel", mock.Anything, "web", "user-1").Return(entity.NewUser("web", "user-1"), nil)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	sessionRepo.On("FindByID", mock.Anything, string(existing.ID)).Return(existing, nil)
+	sessionRepo.On("FindByID", mock.Anything, "missing").Return(nil, fmt.Errorf("session %w", repository.ErrNotFound))
+
+	// Act
+	resp, err := uc.SendMessageBatch(ctx, dto.SendMessageBatchRequest{
+		UserID: "user-1",
+		Items: []dto.BatchMessageItem{
+			{Content: "positive"},
+			{Content: "fail"},
+			{Content: "negative", SessionID: string(existing.ID)},
+		},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, 3, resp.Batch.Total)
+	assert.Equal(t, 3, resp.Batch.Pending)
+	assert.Equal(t, "running", resp.Batch.Status)
+	assert.NotEqual(t, resp.Batch.Items[0].SessionID, resp.Batch.Items[1].SessionID, "prompts without a session get a session each")
+	assert.Equal(t, string(existing.ID), resp.Batch.Items[2].SessionID)
+
+	var batch *dto.MessageBatchDTO
+	require.Eventually(t, func() bool {
+		got, err := uc.GetMessageBatch(ctx, resp.Batch.ID)
+		require.NoError(t, err)
+		batch = got.Batch
+		return batch.Status == "completed"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 2, batch.Completed)
+	assert.Equal(t, 1, batch.Failed)
+	assert.Equal(t, "POSITIVE", batch.Items[0].Reply)
+	assert.Contains(t, batch.Items[1].Error, "provider unavailable")
+	assert.Equal(t, "NEGATIVE", batch.Items[2].Reply)
+	assert.Equal(t, resp.Batch.ID, batch.Items[2].BatchID)
+	assert.Equal(t, []string{"user-1", "user-1", "user-1"}, orchestrator.users)
+
+	// Invalid batches are rejected before any job is created
+	for _, items := range [][]dto.BatchMessageItem{nil, {{Content: "a"}, {Content: "b"}, {Content: "c"}, {Content: "d"}}, {{Content: " "}}} {
+		resp, err := uc.SendMessageBatch(ctx, dto.SendMessageBatchRequest{UserID: "user-1", Items: items})
+		require.NoError(t, err)
+		assert.False(t, resp.Success)
+		assert.Contains(t, resp.Error, ErrInvalidMessageBatch.Error())
+	}
+	_, err = uc.SendMessageBatch(ctx, dto.SendMessageBatchRequest{UserID: "user-1", Items: []dto.BatchMessageItem{{Content: "a"}, {Content: "b", SessionID: "missing"}}})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Len(t, jobRepo.jobs, 3)
+
+	_, err = uc.GetMessageBatch(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
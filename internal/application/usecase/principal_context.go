@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// principalContextKey is the context key of the authenticated caller of a request
+type principalContextKey struct{}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Scope      valueobject.APIKeyScope // Scope of the credential
+	Workspace  valueobject.WorkspaceID // Workspace the credential is bound to, empty for all workspaces
+	Credential string                  // SHA-256 of the credential, empty for local requests without one
+}
+
+// WithPrincipal returns a context carrying the authenticated caller of a request.
+// It is set by the HTTP authentication; requests handled with authentication
+// disabled have no principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller of a request,
+// or false if the context carries none
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
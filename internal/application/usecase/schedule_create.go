@@ -2,9 +2,14 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
 )
 
 // CreateSchedule creates a new schedule
@@ -14,13 +19,70 @@ func (uc *ScheduleUseCase) CreateSchedule(ctx context.Context, req dto.CreateSch
 		return handleScheduleError(err, "failed to marshal input")
 	}
 
-	schedule := entity.NewSchedule(req.Skill, req.CronExpression, inputJSON)
+	schedule, err := newScheduleFromRequest(req, inputJSON)
+	if err != nil {
+		return handleScheduleError(err, "invalid schedule")
+	}
+
+	if err := setScheduleTiming(schedule, req.Timezone, &req.JitterSeconds); err != nil {
+		return handleScheduleError(err, "invalid schedule timing")
+	}
+
+	if err := setMissedRunPolicy(schedule, req.MissedRunPolicy); err != nil {
+		return handleScheduleError(err, "invalid missed run policy")
+	}
+
+	if err := setScheduleTarget(schedule, req.TargetConnector, req.TargetUserID); err != nil {
+		return handleScheduleError(err, "invalid schedule target")
+	}
 
 	if err := uc.scheduleRepo.Create(ctx, schedule); err != nil {
 		return handleScheduleError(err, "failed to create schedule")
 	}
 
+	uc.notifyScheduler()
 	uc.logger.Info("schedule created", "schedule_id", schedule.ID, "skill", schedule.Skill)
 
 	return dto.SuccessScheduleResponse(dto.ScheduleDTOFromEntity(schedule)), nil
 }
+
+// newScheduleFromRequest builds a cron or one-shot schedule depending on the request.
+// Exactly one of skill and prompt must be set, and exactly one of cron_expression and
+// run_at; run_at must be in the future.
+func newScheduleFromRequest(req dto.CreateScheduleRequest, inputJSON string) (*entity.Schedule, error) {
+	if req.Prompt != "" {
+		if req.Skill != "" {
+			return nil, errors.New("skill and prompt are mutually exclusive")
+		}
+		if err := entity.ValidateSchedulePrompt(req.Prompt); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.RunAt == "" {
+		if _, err := valueobject.NewCronExpression(req.CronExpression); err != nil {
+			return nil, err
+		}
+		if req.Prompt != "" {
+			return entity.NewPromptSchedule(req.Prompt, req.CronExpression, inputJSON), nil
+		}
+		return entity.NewSchedule(req.Skill, req.CronExpression, inputJSON), nil
+	}
+
+	if req.CronExpression != "" {
+		return nil, errors.New("cron_expression and run_at are mutually exclusive")
+	}
+
+	runAt, err := time.Parse(time.RFC3339, req.RunAt)
+	if err != nil {
+		return nil, fmt.Errorf("run_at must be an RFC3339 timestamp: %w", err)
+	}
+	if !runAt.After(utils.Now()) {
+		return nil, fmt.Errorf("run_at must be in the future, got %s", req.RunAt)
+	}
+
+	if req.Prompt != "" {
+		return entity.NewOneShotPromptSchedule(req.Prompt, runAt, inputJSON), nil
+	}
+	return entity.NewOneShotSchedule(req.Skill, runAt, inputJSON), nil
+}
@@ -17,6 +17,7 @@ func (uc *ScheduleUseCase) DeleteSchedule(ctx context.Context, id string) (*dto.
 		return handleScheduleError(err, "failed to delete schedule")
 	}
 
+	uc.notifyScheduler()
 	uc.logger.Info("schedule deleted", "schedule_id", schedule.ID, "skill", schedule.Skill)
 
 	return dto.SuccessScheduleResponse(dto.ScheduleDTOFromEntity(schedule)), nil
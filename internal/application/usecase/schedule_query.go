@@ -6,6 +6,12 @@ import (
 	"github.com/atumaikin/nexflow/internal/application/dto"
 )
 
+// Limits for the number of runs returned by GetScheduleRuns
+const (
+	defaultScheduleRunsLimit = 20
+	maxScheduleRunsLimit     = 100
+)
+
 // GetScheduleByID retrieves a schedule by ID
 func (uc *ScheduleUseCase) GetScheduleByID(ctx context.Context, id string) (*dto.ScheduleResponse, error) {
 	schedule, err := uc.scheduleRepo.FindByID(ctx, id)
@@ -60,3 +66,30 @@ func (uc *ScheduleUseCase) GetSchedulesBySkill(ctx context.Context, skill string
 
 	return dto.SuccessSchedulesResponse(scheduleDTOs), nil
 }
+
+// GetScheduleRuns retrieves the most recent runs of a schedule, newest first.
+// A non-positive limit uses the default; limits above the maximum are capped.
+func (uc *ScheduleUseCase) GetScheduleRuns(ctx context.Context, id string, limit int) (*dto.ScheduleRunsResponse, error) {
+	if _, err := uc.scheduleRepo.FindByID(ctx, id); err != nil {
+		return dto.ErrorScheduleRunsResponse(err), err
+	}
+
+	if limit <= 0 {
+		limit = defaultScheduleRunsLimit
+	}
+	if limit > maxScheduleRunsLimit {
+		limit = maxScheduleRunsLimit
+	}
+
+	runs, err := uc.scheduleRunRepo.FindByScheduleID(ctx, id, limit)
+	if err != nil {
+		return dto.ErrorScheduleRunsResponse(err), err
+	}
+
+	runDTOs := make([]*dto.ScheduleRunDTO, 0, len(runs))
+	for _, run := range runs {
+		runDTOs = append(runDTOs, dto.ScheduleRunDTOFromEntity(run))
+	}
+
+	return dto.SuccessScheduleRunsResponse(runDTOs), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
 )
 
 // ToggleSchedule enables or disables a schedule
@@ -23,6 +24,7 @@ func (uc *ScheduleUseCase) ToggleSchedule(ctx context.Context, id string, req dt
 		return handleScheduleError(err, "failed to toggle schedule")
 	}
 
+	uc.notifyScheduler()
 	uc.logger.Info("schedule toggled", "schedule_id", schedule.ID, "enabled", schedule.Enabled)
 
 	return dto.SuccessScheduleResponse(dto.ScheduleDTOFromEntity(schedule)), nil
@@ -37,3 +39,33 @@ func (uc *ScheduleUseCase) EnableSchedule(ctx context.Context, id string) (*dto.
 func (uc *ScheduleUseCase) DisableSchedule(ctx context.Context, id string) (*dto.ScheduleResponse, error) {
 	return uc.ToggleSchedule(ctx, id, dto.ToggleScheduleRequest{Enabled: false})
 }
+
+// PauseSchedule temporarily stops a schedule from firing without deleting it
+func (uc *ScheduleUseCase) PauseSchedule(ctx context.Context, id string) (*dto.ScheduleResponse, error) {
+	return uc.ToggleSchedule(ctx, id, dto.ToggleScheduleRequest{Enabled: false})
+}
+
+// ResumeSchedule lets a paused schedule fire again from its next activation
+func (uc *ScheduleUseCase) ResumeSchedule(ctx context.Context, id string) (*dto.ScheduleResponse, error) {
+	return uc.ToggleSchedule(ctx, id, dto.ToggleScheduleRequest{Enabled: true})
+}
+
+// RunScheduleNow triggers an immediate run of a schedule, even if it is paused
+func (uc *ScheduleUseCase) RunScheduleNow(ctx context.Context, id string) (*dto.ScheduleResponse, error) {
+	schedule, err := uc.scheduleRepo.FindByID(ctx, id)
+	if err != nil {
+		return handleScheduleError(err, "schedule not found")
+	}
+
+	if uc.scheduler == nil {
+		return handleScheduleError(ports.ErrSchedulerDisabled, "failed to run schedule")
+	}
+
+	if err := uc.scheduler.RunNow(ctx, id); err != nil {
+		return handleScheduleError(err, "failed to run schedule")
+	}
+
+	uc.logger.Info("schedule triggered manually", "schedule_id", schedule.ID, "skill", schedule.Skill)
+
+	return dto.SuccessScheduleResponse(dto.ScheduleDTOFromEntity(schedule)), nil
+}
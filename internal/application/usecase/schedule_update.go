@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
@@ -24,6 +25,7 @@ func (uc *ScheduleUseCase) UpdateSchedule(ctx context.Context, id string, req dt
 		return handleScheduleError(err, "failed to update schedule")
 	}
 
+	uc.notifyScheduler()
 	uc.logger.Info("schedule updated", "schedule_id", schedule.ID, "skill", schedule.Skill)
 
 	return dto.SuccessScheduleResponse(dto.ScheduleDTOFromEntity(schedule)), nil
@@ -31,9 +33,26 @@ func (uc *ScheduleUseCase) UpdateSchedule(ctx context.Context, id string, req dt
 
 // updateScheduleFields updates schedule fields from request
 func (uc *ScheduleUseCase) updateScheduleFields(schedule *entity.Schedule, req dto.UpdateScheduleRequest) error {
-	// Update cron expression
+	// Update cron expression; setting one turns a one-shot schedule into a recurring one
 	if req.CronExpression != "" {
-		schedule.CronExpression = valueobject.MustNewCronExpression(req.CronExpression)
+		cron, err := valueobject.NewCronExpression(req.CronExpression)
+		if err != nil {
+			return err
+		}
+		schedule.CronExpression = cron
+		schedule.Type = valueobject.ScheduleTypeCron
+		schedule.RunAt = nil
+	}
+
+	// Update prompt; skill schedules cannot be turned into prompt schedules
+	if req.Prompt != "" {
+		if !schedule.IsPrompt() {
+			return errors.New("prompt can only be changed on prompt schedules")
+		}
+		if err := entity.ValidateSchedulePrompt(req.Prompt); err != nil {
+			return err
+		}
+		schedule.Prompt = req.Prompt
 	}
 
 	// Update input
@@ -45,6 +64,30 @@ func (uc *ScheduleUseCase) updateScheduleFields(schedule *entity.Schedule, req d
 		schedule.Input = inputJSON
 	}
 
+	// Update timezone and jitter
+	if err := setScheduleTiming(schedule, req.Timezone, req.JitterSeconds); err != nil {
+		return err
+	}
+
+	// Update missed run policy
+	if err := setMissedRunPolicy(schedule, req.MissedRunPolicy); err != nil {
+		return err
+	}
+
+	// Update delivery target
+	if req.TargetConnector != nil || req.TargetUserID != nil {
+		connector, userID := schedule.TargetConnector, schedule.TargetUserID
+		if req.TargetConnector != nil {
+			connector = *req.TargetConnector
+		}
+		if req.TargetUserID != nil {
+			userID = *req.TargetUserID
+		}
+		if err := setScheduleTarget(schedule, connector, userID); err != nil {
+			return err
+		}
+	}
+
 	// Update enabled status
 	if req.Enabled != nil {
 		if *req.Enabled {
@@ -56,3 +99,52 @@ func (uc *ScheduleUseCase) updateScheduleFields(schedule *entity.Schedule, req d
 
 	return nil
 }
+
+// setScheduleTiming validates and applies timezone and jitter settings.
+// Empty timezone and nil jitter leave the current values unchanged.
+func setScheduleTiming(schedule *entity.Schedule, timezone string, jitterSeconds *int) error {
+	if timezone != "" {
+		tz, err := valueobject.NewTimezone(timezone)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, timezone)
+		}
+		schedule.SetTimezone(tz)
+	}
+
+	if jitterSeconds != nil {
+		if *jitterSeconds < 0 || *jitterSeconds > entity.MaxJitterSeconds {
+			return fmt.Errorf("jitter_seconds must be between 0 and %d, got %d", entity.MaxJitterSeconds, *jitterSeconds)
+		}
+		schedule.SetJitter(*jitterSeconds)
+	}
+
+	return nil
+}
+
+// setMissedRunPolicy validates and applies the missed run policy.
+// An empty policy leaves the current value unchanged.
+func setMissedRunPolicy(schedule *entity.Schedule, policy string) error {
+	if policy == "" {
+		return nil
+	}
+
+	p, err := valueobject.NewMissedRunPolicy(policy)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, policy)
+	}
+	schedule.SetMissedRunPolicy(p)
+
+	return nil
+}
+
+// setScheduleTarget validates and applies the connector and user the run output is delivered to.
+// Both values must be set together; two empty values disable delivery.
+func setScheduleTarget(schedule *entity.Schedule, connector, userID string) error {
+	if (connector == "") != (userID == "") {
+		return errors.New("target_connector and target_user_id must be set together")
+	}
+
+	schedule.SetTarget(connector, userID)
+
+	return nil
+}
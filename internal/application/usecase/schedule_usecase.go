@@ -1,23 +1,40 @@
 package usecase
 
 import (
+	"github.com/atumaikin/nexflow/internal/application/ports"
 	"github.com/atumaikin/nexflow/internal/domain/repository"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
 )
 
 // ScheduleUseCase handles schedule-related business logic
 type ScheduleUseCase struct {
-	scheduleRepo repository.ScheduleRepository
-	logger       logging.Logger
+	scheduleRepo    repository.ScheduleRepository
+	scheduleRunRepo repository.ScheduleRunRepository
+	scheduler       ports.Scheduler
+	logger          logging.Logger
 }
 
 // NewScheduleUseCase creates a new ScheduleUseCase
 func NewScheduleUseCase(
 	scheduleRepo repository.ScheduleRepository,
+	scheduleRunRepo repository.ScheduleRunRepository,
 	logger logging.Logger,
 ) *ScheduleUseCase {
 	return &ScheduleUseCase{
-		scheduleRepo: scheduleRepo,
-		logger:       logger,
+		scheduleRepo:    scheduleRepo,
+		scheduleRunRepo: scheduleRunRepo,
+		logger:          logger,
+	}
+}
+
+// SetScheduler sets the scheduler that is notified about schedule changes
+func (uc *ScheduleUseCase) SetScheduler(scheduler ports.Scheduler) {
+	uc.scheduler = scheduler
+}
+
+// notifyScheduler asks the scheduler (if any) to reload schedules
+func (uc *ScheduleUseCase) notifyScheduler() {
+	if uc.scheduler != nil {
+		uc.scheduler.Reload()
 	}
 }
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockScheduleRepository is a mock implementation of ScheduleRepository
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepository) Create(ctx context.Context, schedule *entity.Schedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) FindByID(ctx context.Context, id string) (*entity.Schedule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepository) FindBySkill(ctx context.Context, skill string) ([]*entity.Schedule, error) {
+	args := m.Called(ctx, skill)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepository) List(ctx context.Context) ([]*entity.Schedule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepository) Update(ctx context.Context, schedule *entity.Schedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) FindEnabled(ctx context.Context) ([]*entity.Schedule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Schedule), args.Error(1)
+}
+
+// stubScheduler records the schedules run on demand and the reloads
+type stubScheduler struct {
+	runs    []string
+	reloads int
+	runErr  error
+}
+
+func (s *stubScheduler) Start() error { return nil }
+
+func (s *stubScheduler) Stop() error { return nil }
+
+func (s *stubScheduler) Reload() { s.reloads++ }
+
+func (s *stubScheduler) RunNow(ctx context.Context, scheduleID string) error {
+	if s.runErr != nil {
+		return s.runErr
+	}
+	s.runs = append(s.runs, scheduleID)
+	return nil
+}
+
+func newScheduleTestUseCase() (*ScheduleUseCase, *MockScheduleRepository) {
+	repo := new(MockScheduleRepository)
+	return NewScheduleUseCase(repo, nil, logging.NewNoopLogger()), repo
+}
+
+func TestScheduleUseCase_PauseResume(t *testing.T) {
+	ctx := context.Background()
+	uc, repo := newScheduleTestUseCase()
+	scheduler := &stubScheduler{}
+	uc.SetScheduler(scheduler)
+	schedule := entity.NewSchedule("backup", "0 3 * * *", "{}")
+	repo.On("FindByID", ctx, string(schedule.ID)).Return(schedule, nil)
+	repo.On("Update", ctx, schedule).Return(nil)
+
+	resp, err := uc.PauseSchedule(ctx, string(schedule.ID))
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.False(t, resp.Schedule.Enabled)
+
+	// Pausing a paused schedule keeps it paused
+	resp, err = uc.PauseSchedule(ctx, string(schedule.ID))
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.False(t, schedule.Enabled)
+
+	resp, err = uc.ResumeSchedule(ctx, string(schedule.ID))
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.True(t, resp.Schedule.Enabled)
+	assert.Equal(t, 3, scheduler.reloads, "every change should reload the scheduler")
+}
+
+func TestScheduleUseCase_NotFound(t *testing.T) {
+	ctx := context.Background()
+	uc, repo := newScheduleTestUseCase()
+	uc.SetScheduler(&stubScheduler{})
+	repo.On("FindByID", ctx, "missing").Return(nil, fmt.Errorf("%w: schedule missing", repository.ErrNotFound))
+
+	for name, call := range map[string]func(context.Context, string) error{
+		"pause":   func(ctx context.Context, id string) error { _, err := uc.PauseSchedule(ctx, id); return err },
+		"resume":  func(ctx context.Context, id string) error { _, err := uc.ResumeSchedule(ctx, id); return err },
+		"run-now": func(ctx context.Context, id string) error { _, err := uc.RunScheduleNow(ctx, id); return err },
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, call(ctx, "missing"), repository.ErrNotFound)
+		})
+	}
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestScheduleUseCase_RunScheduleNow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("scheduler disabled", func(t *testing.T) {
+		uc, repo := newScheduleTestUseCase()
+		schedule := entity.NewSchedule("backup", "0 3 * * *", "{}")
+		repo.On("FindByID", ctx, string(schedule.ID)).Return(schedule, nil)
+
+		resp, err := uc.RunScheduleNow(ctx, string(schedule.ID))
+		assert.ErrorIs(t, err, ports.ErrSchedulerDisabled)
+		assert.False(t, resp.Success)
+	})
+
+	t.Run("paused schedule", func(t *testing.T) {
+		uc, repo := newScheduleTestUseCase()
+		scheduler := &stubScheduler{}
+		uc.SetScheduler(scheduler)
+		schedule := entity.NewSchedule("backup", "0 3 * * *", "{}")
+		schedule.Disable()
+		repo.On("FindByID", ctx, string(schedule.ID)).Return(schedule, nil)
+
+		resp, err := uc.RunScheduleNow(ctx, string(schedule.ID))
+		require.NoError(t, err)
+		assert.True(t, resp.Success)
+		assert.False(t, resp.Schedule.Enabled, "running a paused schedule should not resume it")
+		assert.Equal(t, []string{string(schedule.ID)}, scheduler.runs)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("scheduler error", func(t *testing.T) {
+		uc, repo := newScheduleTestUseCase()
+		uc.SetScheduler(&stubScheduler{runErr: errors.New("schedule is already running")})
+		schedule := entity.NewSchedule("backup", "0 3 * * *", "{}")
+		repo.On("FindByID", ctx, string(schedule.ID)).Return(schedule, nil)
+
+		resp, err := uc.RunScheduleNow(ctx, string(schedule.ID))
+		assert.Error(t, err)
+		assert.False(t, resp.Success)
+	})
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// MaxSessionAttributeKeyLength is the maximum length of a session attribute key
+const MaxSessionAttributeKeyLength = 128
+
+// Validation errors returned in responses of the SessionStateUseCase
+var (
+	ErrInvalidAttributeKey   = errors.New("invalid attribute key")
+	ErrInvalidAttributeValue = errors.New("attribute value must be valid JSON")
+	ErrInvalidAttributeTTL   = errors.New("ttl_seconds must be non-negative")
+)
+
+// SessionStateUseCase handles per-session key-value state used by skills and the orchestrator,
+// such as the current workflow step or user preferences
+type SessionStateUseCase struct {
+	sessionRepo   repository.SessionRepository
+	attributeRepo repository.SessionAttributeRepository
+	logger        logging.Logger
+}
+
+// NewSessionStateUseCase creates a new SessionStateUseCase
+func NewSessionStateUseCase(sessionRepo repository.SessionRepository, attributeRepo repository.SessionAttributeRepository, logger logging.Logger) *SessionStateUseCase {
+	return &SessionStateUseCase{
+		sessionRepo:   sessionRepo,
+		attributeRepo: attributeRepo,
+		logger:        logger,
+	}
+}
+
+// GetAttribute retrieves an unexpired attribute of a session
+func (uc *SessionStateUseCase) GetAttribute(ctx context.Context, sessionID, key string) (*dto.SessionAttributeResponse, error) {
+	attr, err := uc.attributeRepo.Get(ctx, sessionID, key)
+	if err != nil {
+		return handleSessionAttributeError(err, "failed to find session attribute")
+	}
+
+	return dto.SuccessSessionAttributeResponse(dto.SessionAttributeDTOFromEntity(attr)), nil
+}
+
+// ListAttributes retrieves all unexpired attributes of a session, ordered by key
+func (uc *SessionStateUseCase) ListAttributes(ctx context.Context, sessionID string) (*dto.SessionAttributesResponse, error) {
+	attrs, err := uc.attributeRepo.FindBySessionID(ctx, sessionID)
+	if err != nil {
+		return dto.ErrorSessionAttributesResponse(err), err
+	}
+
+	attrDTOs := make([]*dto.SessionAttributeDTO, 0, len(attrs))
+	for _, attr := range attrs {
+		attrDTOs = append(attrDTOs, dto.SessionAttributeDTOFromEntity(attr))
+	}
+
+	return dto.SuccessSessionAttributesResponse(attrDTOs), nil
+}
+
+// SetAttribute creates or replaces an attribute of a session.
+// A positive TTL makes the attribute expire; setting it again without a TTL removes the expiry.
+func (uc *SessionStateUseCase) SetAttribute(ctx context.Context, sessionID, key string, req dto.SetSessionAttributeRequest) (*dto.SessionAttributeResponse, error) {
+	if err := validateAttributeKey(key); err != nil {
+		return dto.ErrorSessionAttributeResponse(err), nil
+	}
+	if len(req.Value) == 0 || !json.Valid(req.Value) {
+		return dto.ErrorSessionAttributeResponse(ErrInvalidAttributeValue), nil
+	}
+	if req.TTLSeconds < 0 {
+		return dto.ErrorSessionAttributeResponse(ErrInvalidAttributeTTL), nil
+	}
+
+	if _, err := uc.sessionRepo.FindByID(ctx, sessionID); err != nil {
+		return handleSessionAttributeError(err, "failed to find session")
+	}
+
+	attr := entity.NewSessionAttribute(sessionID, key, string(req.Value), time.Duration(req.TTLSeconds)*time.Second)
+	if err := uc.attributeRepo.Set(ctx, attr); err != nil {
+		return handleSessionAttributeError(err, "failed to set session attribute")
+	}
+
+	return dto.SuccessSessionAttributeResponse(dto.SessionAttributeDTOFromEntity(attr)), nil
+}
+
+// DeleteAttribute removes an attribute of a session
+func (uc *SessionStateUseCase) DeleteAttribute(ctx context.Context, sessionID, key string) (*dto.SessionAttributeResponse, error) {
+	deleted, err := uc.attributeRepo.Delete(ctx, sessionID, key)
+	if err != nil {
+		return handleSessionAttributeError(err, "failed to delete session attribute")
+	}
+	if !deleted {
+		return dto.ErrorSessionAttributeResponse(fmt.Errorf("session attribute not found: %s", key)), nil
+	}
+
+	return &dto.SessionAttributeResponse{Success: true}, nil
+}
+
+// PurgeExpired removes expired attributes of all sessions.
+// It is registered as a scheduler job; expired attributes are hidden from reads before they are purged.
+func (uc *SessionStateUseCase) PurgeExpired(ctx context.Context) error {
+	purged, err := uc.attributeRepo.DeleteExpired(ctx, utils.Now())
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		uc.logger.Info("purged expired session attributes", "count", purged)
+	}
+	return nil
+}
+
+// validateAttributeKey checks that an attribute key is non-empty and not too long
+func validateAttributeKey(key string) error {
+	if key == "" || len(key) > MaxSessionAttributeKeyLength {
+		return fmt.Errorf("%w: must be 1 to %d bytes", ErrInvalidAttributeKey, MaxSessionAttributeKeyLength)
+	}
+	return nil
+}
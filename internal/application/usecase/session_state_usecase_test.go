@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// MockSessionAttributeRepository is a mock implementation of SessionAttributeRepository
+type MockSessionAttributeRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionAttributeRepository) Get(ctx context.Context, sessionID, key string) (*entity.SessionAttribute, error) {
+	args := m.Called(ctx, sessionID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SessionAttribute), args.Error(1)
+}
+
+func (m *MockSessionAttributeRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.SessionAttribute, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.SessionAttribute), args.Error(1)
+}
+
+func (m *MockSessionAttributeRepository) Set(ctx context.Context, attr *entity.SessionAttribute) error {
+	args := m.Called(ctx, attr)
+	return args.Error(0)
+}
+
+func (m *MockSessionAttributeRepository) Delete(ctx context.Context, sessionID, key string) (bool, error) {
+	args := m.Called(ctx, sessionID, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionAttributeRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestSessionStateUseCase_SetAttribute(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSessionRepo := new(MockSessionRepository)
+	mockAttrRepo := new(MockSessionAttributeRepository)
+	uc := NewSessionStateUseCase(mockSessionRepo, mockAttrRepo, new(MockLogger))
+
+	session := entity.NewSession("user-1")
+	sessionID := string(session.ID)
+	mockSessionRepo.On("FindByID", ctx, sessionID).Return(session, nil)
+	mockAttrRepo.On("Set", ctx, mock.MatchedBy(func(attr *entity.SessionAttribute) bool {
+		return attr.Key == "step" && attr.Value == `{"name":"confirm"}` && attr.ExpiresAt != nil
+	})).Return(nil)
+
+	// Act
+	resp, err := uc.SetAttribute(ctx, sessionID, "step", dto.SetSessionAttributeRequest{
+		Value:      json.RawMessage(`{"name":"confirm"}`),
+		TTLSeconds: 60,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, "step", resp.Attribute.Key)
+	assert.JSONEq(t, `{"name":"confirm"}`, string(resp.Attribute.Value))
+	assert.NotEmpty(t, resp.Attribute.ExpiresAt)
+	mockSessionRepo.AssertExpectations(t)
+	mockAttrRepo.AssertExpectations(t)
+}
+
+func TestSessionStateUseCase_SetAttribute_Validation(t *testing.T) {
+	ctx := context.Background()
+	uc := NewSessionStateUseCase(new(MockSessionRepository), new(MockSessionAttributeRepository), new(MockLogger))
+
+	tests := []struct {
+		name    string
+		key     string
+		req     dto.SetSessionAttributeRequest
+		wantErr error
+	}{
+		{"empty key", "", dto.SetSessionAttributeRequest{Value: json.RawMessage(`1`)}, ErrInvalidAttributeKey},
+		{"missing value", "step", dto.SetSessionAttributeRequest{}, ErrInvalidAttributeValue},
+		{"invalid JSON", "step", dto.SetSessionAttributeRequest{Value: json.RawMessage(`{`)}, ErrInvalidAttributeValue},
+		{"negative ttl", "step", dto.SetSessionAttributeRequest{Value: json.RawMessage(`1`), TTLSeconds: -1}, ErrInvalidAttributeTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.SetAttribute(ctx, "session-1", tt.key, tt.req)
+			require.NoError(t, err)
+			assert.False(t, resp.Success)
+			assert.Contains(t, resp.Error, tt.wantErr.Error())
+		})
+	}
+}
+
+func TestSessionStateUseCase_DeleteAttribute(t *testing.T) {
+	ctx := context.Background()
+	mockAttrRepo := new(MockSessionAttributeRepository)
+	uc := NewSessionStateUseCase(new(MockSessionRepository), mockAttrRepo, new(MockLogger))
+
+	mockAttrRepo.On("Delete", ctx, "session-1", "step").Return(true, nil).Once()
+	mockAttrRepo.On("Delete", ctx, "session-1", "step").Return(false, nil).Once()
+
+	resp, err := uc.DeleteAttribute(ctx, "session-1", "step")
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	resp, err = uc.DeleteAttribute(ctx, "session-1", "step")
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "not found")
+	mockAttrRepo.AssertExpectations(t)
+}
+
+func TestSessionStateUseCase_PurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	mockAttrRepo := new(MockSessionAttributeRepository)
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
+	uc := NewSessionStateUseCase(new(MockSessionRepository), mockAttrRepo, mockLogger)
+
+	mockAttrRepo.On("DeleteExpired", ctx, mock.AnythingOfType("time.Time")).Return(int64(3), nil).Once()
+	require.NoError(t, uc.PurgeExpired(ctx))
+
+	mockAttrRepo.On("DeleteExpired", ctx, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("db down")).Once()
+	assert.Error(t, uc.PurgeExpired(ctx))
+	mockAttrRepo.AssertExpectations(t)
+}
@@ -2,14 +2,21 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 )
 
 // CreateSkill creates a new skill
 func (uc *SkillUseCase) CreateSkill(ctx context.Context, req dto.CreateSkillRequest) (*dto.SkillResponse, error) {
+	if req.Workspace != "" && !valueobject.WorkspaceID(req.Workspace).IsValid() {
+		return dto.ErrorSkillResponse(fmt.Errorf("%w: %q", ErrInvalidWorkspaceID, req.Workspace)), nil
+	}
+
 	newSkill := entity.NewSkill(req.Name, req.Version, req.Location, req.Permissions, req.Metadata)
+	newSkill.WorkspaceID = valueobject.WorkspaceID(req.Workspace)
 
 	if err := uc.skillRepo.Create(ctx, newSkill); err != nil {
 		return handleSkillError(err, "failed to create skill")
@@ -2,16 +2,21 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
 )
 
 // ExecuteSkill executes a skill with given input parameters
 func (uc *SkillUseCase) ExecuteSkill(ctx context.Context, req dto.SkillExecutionRequest) (*dto.SkillExecutionResponse, error) {
-	_, err := uc.skillRepo.FindByName(ctx, req.Skill)
+	skill, err := uc.skillRepo.FindByName(ctx, req.Skill)
 	if err != nil {
 		return handleSkillExecutionError(err, "skill not found")
 	}
+	if !skill.IsEnabled() {
+		return handleSkillExecutionError(fmt.Errorf("%w: %s", ports.ErrSkillDisabled, req.Skill), "skill cannot be executed")
+	}
 
 	result, err := uc.skillRuntime.Execute(ctx, req.Skill, req.Input)
 	if err != nil {
@@ -29,15 +34,15 @@ func (uc *SkillUseCase) ExecuteSkill(ctx context.Context, req dto.SkillExecution
 
 // ValidateSkill validates a skill
 func (uc *SkillUseCase) ValidateSkill(ctx context.Context, skillName string) error {
-	return uc.skillRuntime.Validate(skillName)
+	return uc.skillRuntime.Validate(ctx, skillName)
 }
 
 // ListAvailableSkills returns list of available skill names
 func (uc *SkillUseCase) ListAvailableSkills(ctx context.Context) ([]string, error) {
-	return uc.skillRuntime.List()
+	return uc.skillRuntime.List(ctx)
 }
 
 // GetSkillDetails returns detailed skill information
 func (uc *SkillUseCase) GetSkillDetails(ctx context.Context, skillName string) (map[string]interface{}, error) {
-	return uc.skillRuntime.GetSkill(skillName)
+	return uc.skillRuntime.GetSkill(ctx, skillName)
 }
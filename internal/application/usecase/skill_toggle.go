@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+)
+
+// DisableSkill disables a skill: it stays registered but cannot be executed
+func (uc *SkillUseCase) DisableSkill(ctx context.Context, id string) (*dto.SkillResponse, error) {
+	return uc.toggleSkill(ctx, id, false)
+}
+
+// EnableSkill enables a disabled skill
+func (uc *SkillUseCase) EnableSkill(ctx context.Context, id string) (*dto.SkillResponse, error) {
+	return uc.toggleSkill(ctx, id, true)
+}
+
+// toggleSkill enables or disables a skill
+func (uc *SkillUseCase) toggleSkill(ctx context.Context, id string, enabled bool) (*dto.SkillResponse, error) {
+	skill, err := uc.skillRepo.FindByID(ctx, id)
+	if err != nil {
+		return handleSkillError(err, "skill not found")
+	}
+
+	if enabled {
+		skill.Enable()
+	} else {
+		skill.Disable()
+	}
+
+	if err := uc.skillRepo.Update(ctx, skill); err != nil {
+		return handleSkillError(err, "failed to toggle skill")
+	}
+
+	uc.logger.Info("skill toggled", "skill_id", skill.ID, "name", skill.Name, "enabled", enabled)
+
+	return dto.SuccessSkillResponse(dto.SkillDTOFromEntity(skill)), nil
+}
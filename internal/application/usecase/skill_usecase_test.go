@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSkillRepository is a mock implementation of SkillRepository
+type MockSkillRepository struct {
+	mock.Mock
+}
+
+func (m *MockSkillRepository) Create(ctx context.Context, skill *entity.Skill) error {
+	args := m.Called(ctx, skill)
+	return args.Error(0)
+}
+
+func (m *MockSkillRepository) FindByID(ctx context.Context, id string) (*entity.Skill, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Skill), args.Error(1)
+}
+
+func (m *MockSkillRepository) FindByName(ctx context.Context, name string) (*entity.Skill, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Skill), args.Error(1)
+}
+
+func (m *MockSkillRepository) List(ctx context.Context) ([]*entity.Skill, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Skill), args.Error(1)
+}
+
+func (m *MockSkillRepository) Update(ctx context.Context, skill *entity.Skill) error {
+	args := m.Called(ctx, skill)
+	return args.Error(0)
+}
+
+func (m *MockSkillRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestSkillUseCase_DisableEnableSkill(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSkillRepo := new(MockSkillRepository)
+	uc := NewSkillUseCase(mockSkillRepo, new(MockSkillRuntime), logging.NewNoopLogger())
+
+	skill := entity.NewSkill("weather", "1.0.0", "/skills/weather", nil, nil)
+	mockSkillRepo.On("FindByID", ctx, string(skill.ID)).Return(skill, nil)
+	mockSkillRepo.On("Update", ctx, skill).Return(nil)
+
+	// Act
+	resp, err := uc.DisableSkill(ctx, string(skill.ID))
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.True(t, resp.Skill.Disabled)
+	assert.False(t, skill.IsEnabled())
+
+	// Act
+	resp, err = uc.EnableSkill(ctx, string(skill.ID))
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, resp.Skill.Disabled)
+	assert.True(t, skill.IsEnabled())
+	mockSkillRepo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestSkillUseCase_ExecuteSkill_Disabled(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockSkillRepo := new(MockSkillRepository)
+	mockSkillRuntime := new(MockSkillRuntime)
+	uc := NewSkillUseCase(mockSkillRepo, mockSkillRuntime, logging.NewNoopLogger())
+
+	skill := entity.NewSkill("weather", "1.0.0", "/skills/weather", nil, nil)
+	skill.Disable()
+	mockSkillRepo.On("FindByName", ctx, "weather").Return(skill, nil)
+
+	// Act
+	resp, err := uc.ExecuteSkill(ctx, dto.SkillExecutionRequest{Skill: "weather"})
+
+	// Assert
+	require.ErrorIs(t, err, ports.ErrSkillDisabled)
+	assert.False(t, resp.Success)
+	mockSkillRuntime.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSkillUseCase_CreateSkill_Workspace(t *testing.T) {
+	ctx := context.Background()
+	mockSkillRepo := new(MockSkillRepository)
+	uc := NewSkillUseCase(mockSkillRepo, new(MockSkillRuntime), logging.NewNoopLogger())
+	mockSkillRepo.On("Create", ctx, mock.MatchedBy(func(skill *entity.Skill) bool {
+		return skill.WorkspaceID == "support"
+	})).Return(nil)
+
+	resp, err := uc.CreateSkill(ctx, dto.CreateSkillRequest{Name: "tickets", Version: "1.0.0", Location: "/skills/tickets", Workspace: "support"})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "support", resp.Skill.WorkspaceID)
+
+	resp, err = uc.CreateSkill(ctx, dto.CreateSkillRequest{Name: "tickets", Version: "1.0.0", Location: "/skills/tickets", Workspace: "customer support"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, ErrInvalidWorkspaceID.Error())
+	mockSkillRepo.AssertNumberOfCalls(t, "Create", 1)
+}
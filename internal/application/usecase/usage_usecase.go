@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+const (
+	// DefaultUsageMonths is the number of months returned when no range is requested
+	DefaultUsageMonths = 12
+
+	// MaxUsageMonths limits the number of months of a usage request
+	MaxUsageMonths = 36
+)
+
+// Groupings of the monthly LLM usage
+const (
+	UsageByWorkspace = "workspace"
+	UsageByUser      = "user"
+	UsageByProvider  = "provider"
+	UsageByModel     = "model"
+)
+
+// Errors returned by the UsageUseCase
+var (
+	ErrInvalidUsageMonth   = errors.New("since and until must be months in the format YYYY-MM")
+	ErrInvalidUsageRange   = fmt.Errorf("until must not be before since, and the range must not exceed %d months", MaxUsageMonths)
+	ErrInvalidUsageGroupBy = errors.New("group_by must be workspace, user, provider or model")
+)
+
+// UsageUseCase reads the LLM usage recorded for billing, by month
+type UsageUseCase struct {
+	usageRepo repository.LLMUsageRepository
+	logger    logging.Logger
+}
+
+// NewUsageUseCase creates a new UsageUseCase
+func NewUsageUseCase(usageRepo repository.LLMUsageRepository, logger logging.Logger) *UsageUseCase {
+	return &UsageUseCase{
+		usageRepo: usageRepo,
+		logger:    logger,
+	}
+}
+
+// GetMonthlyUsage returns the LLM requests, tokens and cost of every month of the requested
+// range, including months without usage, grouped by workspace, user, provider or model, and the
+// totals of the range. Usage of sessions without a workspace belongs to the default workspace.
+func (uc *UsageUseCase) GetMonthlyUsage(ctx context.Context, req dto.UsageRequest) (*dto.UsageResponse, error) {
+	since, until, err := usageRange(req)
+	if err != nil {
+		return dto.ErrorUsageResponse(err), nil
+	}
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = UsageByWorkspace
+	}
+	switch groupBy {
+	case UsageByWorkspace, UsageByUser, UsageByProvider, UsageByModel:
+	default:
+		return dto.ErrorUsageResponse(fmt.Errorf("%w: %q", ErrInvalidUsageGroupBy, req.GroupBy)), nil
+	}
+
+	totals, err := uc.usageRepo.SumByMonth(ctx, since, until)
+	if err != nil {
+		return handleUsageError(err, "failed to sum llm usage")
+	}
+
+	resp := &dto.UsageResponse{Success: true, Since: since, Until: until, GroupBy: groupBy, Totals: &dto.UsageTotalsDTO{}}
+	months := make(map[string]*dto.UsageMonthDTO)
+	for month := since; month <= until; month = nextUsageMonth(month) {
+		usage := &dto.UsageMonthDTO{Month: month, Totals: &dto.UsageTotalsDTO{}}
+		months[month] = usage
+		resp.Months = append(resp.Months, usage)
+	}
+
+	for _, total := range totals {
+		workspace := usageWorkspace(total.WorkspaceID)
+		if req.Workspace != "" && workspace != req.Workspace {
+			continue
+		}
+		month, ok := months[total.Month]
+		if !ok {
+			continue
+		}
+
+		var group string
+		switch groupBy {
+		case UsageByWorkspace:
+			group = workspace
+		case UsageByUser:
+			group = total.UserID.String()
+		case UsageByProvider:
+			group = total.Provider
+		case UsageByModel:
+			group = total.Model
+		}
+		if month.Groups == nil {
+			month.Groups = make(map[string]*dto.UsageTotalsDTO)
+		}
+		if month.Groups[group] == nil {
+			month.Groups[group] = &dto.UsageTotalsDTO{}
+		}
+
+		addUsageTotal(month.Groups[group], total)
+		addUsageTotal(month.Totals, total)
+		addUsageTotal(resp.Totals, total)
+	}
+
+	return resp, nil
+}
+
+// usageRange returns the first and last month of a request, defaulting to the last
+// DefaultUsageMonths months up to the current one
+func usageRange(req dto.UsageRequest) (string, string, error) {
+	now := utils.Now().UTC()
+	until := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if req.Until != "" {
+		parsed, err := time.Parse(entity.UsageMonthLayout, req.Until)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidUsageMonth, req.Until)
+		}
+		until = parsed
+	}
+
+	since := until.AddDate(0, 1-DefaultUsageMonths, 0)
+	if req.Since != "" {
+		parsed, err := time.Parse(entity.UsageMonthLayout, req.Since)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidUsageMonth, req.Since)
+		}
+		since = parsed
+	}
+
+	if until.Before(since) || !until.Before(since.AddDate(0, MaxUsageMonths, 0)) {
+		return "", "", ErrInvalidUsageRange
+	}
+	return entity.UsageMonth(since), entity.UsageMonth(until), nil
+}
+
+// nextUsageMonth returns the month after a month in entity.UsageMonthLayout
+func nextUsageMonth(month string) string {
+	t, _ := time.Parse(entity.UsageMonthLayout, month)
+	return entity.UsageMonth(t.AddDate(0, 1, 0))
+}
+
+// usageWorkspace returns the workspace usage is billed to
+func usageWorkspace(workspaceID valueobject.WorkspaceID) string {
+	if workspaceID.IsEmpty() {
+		return valueobject.DefaultWorkspaceID.String()
+	}
+	return workspaceID.String()
+}
+
+// addUsageTotal adds the usage of a month, workspace, user, provider and model to a total
+func addUsageTotal(totals *dto.UsageTotalsDTO, total *entity.LLMUsageTotal) {
+	totals.Requests += total.Requests
+	totals.InputTokens += total.InputTokens
+	totals.OutputTokens += total.OutputTokens
+	totals.TotalTokens += total.TotalTokens
+	totals.Cost += total.Cost
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockLLMUsageRepository is a mock implementation of LLMUsageRepository
+type MockLLMUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockLLMUsageRepository) Create(ctx context.Context, usage *entity.LLMUsage) error {
+	args := m.Called(ctx, usage)
+	return args.Error(0)
+}
+
+func (m *MockLLMUsageRepository) SumByMonth(ctx context.Context, since, until string) ([]*entity.LLMUsageTotal, error) {
+	args := m.Called(ctx, since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.LLMUsageTotal), args.Error(1)
+}
+
+func testUsageTotals() []*entity.LLMUsageTotal {
+	return []*entity.LLMUsageTotal{
+		{Month: "2024-01", WorkspaceID: "support", UserID: "user-1", Provider: "openai", Model: "gpt-4o", Requests: 2, InputTokens: 100, OutputTokens: 50, TotalTokens: 150, Cost: 0.5},
+		{Month: "2024-01", WorkspaceID: "", UserID: "user-2", Provider: "openai", Model: "gpt-4o-mini", Requests: 1, InputTokens: 20, OutputTokens: 10, TotalTokens: 30, Cost: 0.25},
+		{Month: "2024-03", WorkspaceID: "support", UserID: "user-2", Provider: "anthropic", Model: "claude", Requests: 1, InputTokens: 5, OutputTokens: 5, TotalTokens: 10, Cost: 1},
+	}
+}
+
+func TestUsageUseCase_GetMonthlyUsage(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := new(MockLLMUsageRepository)
+	uc := NewUsageUseCase(repo, logging.NewNoopLogger())
+	repo.On("SumByMonth", ctx, "2024-01", "2024-03").Return(testUsageTotals(), nil)
+
+	// Act
+	resp, err := uc.GetMonthlyUsage(ctx, dto.UsageRequest{Since: "2024-01", Until: "2024-03"})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, UsageByWorkspace, resp.GroupBy)
+	require.Len(t, resp.Months, 3)
+	assert.Equal(t, &dto.UsageMonthDTO{
+		Month:  "2024-01",
+		Totals: &dto.UsageTotalsDTO{Requests: 3, InputTokens: 120, OutputTokens: 60, TotalTokens: 180, Cost: 0.75},
+		Groups: map[string]*dto.UsageTotalsDTO{
+			"support": {Requests: 2, InputTokens: 100, OutputTokens: 50, TotalTokens: 150, Cost: 0.5},
+			"default": {Requests: 1, InputTokens: 20, OutputTokens: 10, TotalTokens: 30, Cost: 0.25},
+		},
+	}, resp.Months[0])
+	assert.Equal(t, &dto.UsageMonthDTO{Month: "2024-02", Totals: &dto.UsageTotalsDTO{}}, resp.Months[1])
+	assert.Equal(t, &dto.UsageTotalsDTO{Requests: 4, InputTokens: 125, OutputTokens: 65, TotalTokens: 190, Cost: 1.75}, resp.Totals)
+}
+
+func TestUsageUseCase_GetMonthlyUsage_GroupByAndWorkspace(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLLMUsageRepository)
+	uc := NewUsageUseCase(repo, logging.NewNoopLogger())
+	repo.On("SumByMonth", ctx, "2024-01", "2024-03").Return(testUsageTotals(), nil)
+
+	resp, err := uc.GetMonthlyUsage(ctx, dto.UsageRequest{Since: "2024-01", Until: "2024-03", GroupBy: UsageByUser, Workspace: "support"})
+
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, map[string]*dto.UsageTotalsDTO{"user-1": {Requests: 2, InputTokens: 100, OutputTokens: 50, TotalTokens: 150, Cost: 0.5}}, resp.Months[0].Groups)
+	assert.Equal(t, map[string]*dto.UsageTotalsDTO{"user-2": {Requests: 1, InputTokens: 5, OutputTokens: 5, TotalTokens: 10, Cost: 1}}, resp.Months[2].Groups)
+	assert.Equal(t, int64(3), resp.Totals.Requests)
+
+	resp, err = uc.GetMonthlyUsage(ctx, dto.UsageRequest{Since: "2024-01", Until: "2024-03", GroupBy: UsageByModel})
+	require.NoError(t, err)
+	assert.Len(t, resp.Months[0].Groups, 2)
+	assert.Equal(t, int64(30), resp.Months[0].Groups["gpt-4o-mini"].TotalTokens)
+}
+
+func TestUsageUseCase_GetMonthlyUsage_DefaultRange(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLLMUsageRepository)
+	uc := NewUsageUseCase(repo, logging.NewNoopLogger())
+	repo.On("SumByMonth", ctx, mock.Anything, mock.Anything).Return([]*entity.LLMUsageTotal{}, nil)
+
+	resp, err := uc.GetMonthlyUsage(ctx, dto.UsageRequest{Until: "2024-03"})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	// Without since, the 12 months up to until are returned
+	assert.Equal(t, "2023-04", resp.Since)
+	assert.Len(t, resp.Months, DefaultUsageMonths)
+}
+
+func TestUsageUseCase_GetMonthlyUsage_Invalid(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLLMUsageRepository)
+	uc := NewUsageUseCase(repo, logging.NewNoopLogger())
+
+	resp, err := uc.GetMonthlyUsage(ctx, dto.UsageRequest{Since: "2024-1"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, ErrInvalidUsageMonth.Error())
+
+	resp, err = uc.GetMonthlyUsage(ctx, dto.UsageRequest{Since: "2024-03", Until: "2024-01"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Error, ErrInvalidUsageRange.Error())
+
+	resp, err = uc.GetMonthlyUsage(ctx, dto.UsageRequest{Since: "2021-01", Until: "2024-01"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Error, ErrInvalidUsageRange.Error())
+
+	resp, err = uc.GetMonthlyUsage(ctx, dto.UsageRequest{GroupBy: "team"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Error, ErrInvalidUsageGroupBy.Error())
+
+	repo.AssertNotCalled(t, "SumByMonth")
+}
+
+func TestUsageUseCase_GetMonthlyUsage_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLLMUsageRepository)
+	uc := NewUsageUseCase(repo, logging.NewNoopLogger())
+	repo.On("SumByMonth", ctx, "2024-01", "2024-01").Return(nil, errors.New("database is locked"))
+
+	resp, err := uc.GetMonthlyUsage(ctx, dto.UsageRequest{Since: "2024-01", Until: "2024-01"})
+
+	assert.Error(t, err)
+	assert.False(t, resp.Success)
+}
@@ -0,0 +1,467 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// Limits for the number of erasures returned by ListErasures
+const (
+	defaultUserDataErasuresLimit = 50
+	maxUserDataErasuresLimit     = 500
+)
+
+// MaxErasureReasonLength limits the reason recorded for a user data erasure
+const MaxErasureReasonLength = 500
+
+// ErrUserDataRemains is returned when data of a user is still found after an erasure
+var ErrUserDataRemains = errors.New("user data remains after the erasure")
+
+// UserDataUseCase exports and erases all data kept about a user: the user, their preferences,
+// their sessions with the messages, tasks and session attributes, the stored events about them
+// and the dead letters of their messages
+type UserDataUseCase struct {
+	userRepo       repository.UserRepository
+	prefsRepo      repository.UserPreferencesRepository
+	sessionRepo    repository.SessionRepository
+	messageRepo    repository.MessageRepository
+	taskRepo       repository.TaskRepository
+	attributeRepo  repository.SessionAttributeRepository
+	eventRepo      repository.EventRepository
+	deadLetterRepo repository.MessageDeadLetterRepository
+	erasureRepo    repository.UserDataErasureRepository
+	channels       map[string]string // Channel of the users of each connector, by connector name
+	masker         *logging.Masker
+	logger         logging.Logger
+}
+
+// NewUserDataUseCase creates a new UserDataUseCase
+func NewUserDataUseCase(
+	userRepo repository.UserRepository,
+	prefsRepo repository.UserPreferencesRepository,
+	sessionRepo repository.SessionRepository,
+	messageRepo repository.MessageRepository,
+	taskRepo repository.TaskRepository,
+	attributeRepo repository.SessionAttributeRepository,
+	eventRepo repository.EventRepository,
+	deadLetterRepo repository.MessageDeadLetterRepository,
+	erasureRepo repository.UserDataErasureRepository,
+	logger logging.Logger,
+) *UserDataUseCase {
+	return &UserDataUseCase{
+		userRepo:       userRepo,
+		prefsRepo:      prefsRepo,
+		sessionRepo:    sessionRepo,
+		messageRepo:    messageRepo,
+		taskRepo:       taskRepo,
+		attributeRepo:  attributeRepo,
+		eventRepo:      eventRepo,
+		deadLetterRepo: deadLetterRepo,
+		erasureRepo:    erasureRepo,
+		masker:         logging.DefaultMasker(),
+		logger:         logger,
+	}
+}
+
+// SetMasker sets the masker of secrets in the reasons of erasure audit records, e.g. with the
+// keys and patterns of the logging configuration. Without it, the built-in secrets are masked.
+func (uc *UserDataUseCase) SetMasker(masker *logging.Masker) {
+	uc.masker = masker
+}
+
+// SetConnectorChannels sets the channel of the users of each connector, by connector name, so
+// that the events and message dead letters of a user are found for every connector of their
+// channel. Without it, the channel of a user is taken as the name of their only connector.
+func (uc *UserDataUseCase) SetConnectorChannels(channels map[string]string) {
+	uc.channels = channels
+}
+
+// ExportUserData returns a ZIP archive of the data of a user: "user.json" with the user, their
+// preferences, the stored events about them and the dead letters of their messages, and a
+// "sessions/<id>.json" transcript with the attributes of every session
+func (uc *UserDataUseCase) ExportUserData(ctx context.Context, userID string) (*dto.UserDataExportResponse, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return handleUserDataExportError(err, "failed to find user")
+	}
+
+	exportedAt := utils.FormatTimeRFC3339(utils.Now())
+	doc := &dto.UserDataExportDTO{
+		User:        dto.UserDTOFromEntity(user),
+		Sessions:    []string{},
+		Events:      []*dto.UserEventExportDTO{},
+		DeadLetters: []*dto.MessageDeadLetterDTO{},
+		ExportedAt:  exportedAt,
+	}
+	prefs, err := uc.prefsRepo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return handleUserDataExportError(err, "failed to get preferences")
+	}
+	if prefs != nil {
+		doc.Preferences = dto.UserPreferencesDTOFromEntity(prefs)
+	}
+
+	sessions, err := uc.sessionRepo.FindByUserID(ctx, userID, repository.QueryOptions{})
+	if err != nil {
+		return handleUserDataExportError(err, "failed to get user sessions")
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, session := range sessions {
+		sessionDoc, err := uc.exportSession(ctx, session, exportedAt)
+		if err != nil {
+			return handleUserDataExportError(err, "failed to export session")
+		}
+		if err := writeZipJSON(archive, "sessions/"+string(session.ID)+".json", sessionDoc); err != nil {
+			return handleUserDataExportError(err, "failed to write export")
+		}
+		doc.Sessions = append(doc.Sessions, string(session.ID))
+	}
+
+	connectors := uc.connectorsOf(user)
+	events, err := uc.eventRepo.ListBySubject(ctx, userDataSubject(user, connectors, doc.Sessions))
+	if err != nil {
+		return handleUserDataExportError(err, "failed to get events")
+	}
+	for _, event := range events {
+		doc.Events = append(doc.Events, dto.UserEventExportDTOFromEntity(event))
+	}
+	letters, err := uc.listDeadLetters(ctx, user, connectors, 0)
+	if err != nil {
+		return handleUserDataExportError(err, "failed to get message dead letters")
+	}
+	for _, letter := range letters {
+		doc.DeadLetters = append(doc.DeadLetters, dto.MessageDeadLetterDTOFromEntity(letter))
+	}
+
+	if err := writeZipJSON(archive, "user.json", doc); err != nil {
+		return handleUserDataExportError(err, "failed to write export")
+	}
+	if err := archive.Close(); err != nil {
+		return handleUserDataExportError(err, "failed to write export")
+	}
+
+	uc.logger.Info("user data exported",
+		"user_id", userID,
+		"sessions", len(sessions),
+		"events", len(events),
+		"dead_letters", len(letters),
+	)
+
+	return dto.SuccessUserDataExportResponse(&dto.SessionExportDTO{
+		FileName:    "user-" + userID + ".zip",
+		ContentType: "application/zip",
+		Content:     buf.Bytes(),
+	}), nil
+}
+
+// EraseUserData deletes the user, their preferences, their sessions with the messages, tasks
+// and session attributes, the stored events about them and the dead letters of their messages,
+// checks that none of them are left and saves an audit record of the erasure, with secrets in
+// the reason masked. If data is left, the audit record is saved as unverified and
+// ErrUserDataRemains is returned.
+func (uc *UserDataUseCase) EraseUserData(ctx context.Context, userID string, req dto.EraseUserDataRequest) (*dto.UserDataErasureResponse, error) {
+	if len(req.Reason) > MaxErasureReasonLength {
+		return dto.ErrorUserDataErasureResponse(fmt.Errorf("reason must not exceed %d characters", MaxErasureReasonLength)), nil
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return handleUserDataErasureError(err, "failed to find user")
+	}
+
+	sessions, err := uc.sessionRepo.FindByUserID(ctx, userID, repository.QueryOptions{})
+	if err != nil {
+		return handleUserDataErasureError(err, "failed to get user sessions")
+	}
+
+	erasure := entity.NewUserDataErasure(userID, req.RequestedBy, uc.masker.MaskString(req.Reason))
+	sessionIDs := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if err := uc.eraseSession(ctx, string(session.ID), erasure); err != nil {
+			return handleUserDataErasureError(err, "failed to erase session")
+		}
+		sessionIDs = append(sessionIDs, string(session.ID))
+	}
+
+	connectors := uc.connectorsOf(user)
+	subject := userDataSubject(user, connectors, sessionIDs)
+	events, err := uc.eventRepo.DeleteBySubject(ctx, subject)
+	if err != nil {
+		return handleUserDataErasureError(err, "failed to erase events")
+	}
+	letters := 0
+	if user.ChannelID != "" {
+		for _, connector := range connectors {
+			deleted, err := uc.deadLetterRepo.DeleteByUser(ctx, connector, user.ChannelID)
+			if err != nil {
+				return handleUserDataErasureError(err, "failed to erase message dead letters")
+			}
+			letters += deleted
+		}
+	}
+
+	if erasure.Preferences, err = uc.prefsRepo.Delete(ctx, userID); err != nil {
+		return handleUserDataErasureError(err, "failed to erase preferences")
+	}
+	if err := uc.userRepo.Delete(ctx, userID); err != nil {
+		return handleUserDataErasureError(err, "failed to erase user")
+	}
+
+	verifyErr := uc.verifyErased(ctx, user, connectors, subject)
+	erasure.Verified = verifyErr == nil
+	if err := uc.erasureRepo.Create(ctx, erasure); err != nil {
+		return handleUserDataErasureError(err, "failed to save erasure audit record")
+	}
+	if verifyErr != nil {
+		uc.logger.Error("user data erasure incomplete", "user_id", userID, "erasure_id", erasure.ID, "error", verifyErr)
+		return handleUserDataErasureError(verifyErr, "failed to verify erasure")
+	}
+
+	uc.logger.Info("user data erased",
+		"user_id", userID,
+		"erasure_id", erasure.ID,
+		"sessions", erasure.Sessions,
+		"messages", erasure.Messages,
+		"tasks", erasure.Tasks,
+		"attributes", erasure.Attributes,
+		"events", events,
+		"dead_letters", letters,
+	)
+
+	return dto.SuccessUserDataErasureResponse(dto.UserDataErasureDTOFromEntity(erasure)), nil
+}
+
+// ListErasures returns the audit records of user data erasures, newest first.
+// A non-positive limit uses the default; limits above the maximum are capped.
+func (uc *UserDataUseCase) ListErasures(ctx context.Context, req dto.ListUserDataErasuresRequest) (*dto.UserDataErasuresResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultUserDataErasuresLimit
+	}
+	limit = min(limit, maxUserDataErasuresLimit)
+
+	erasures, err := uc.erasureRepo.List(ctx, repository.UserDataErasureFilter{
+		UserID: req.UserID,
+		Limit:  limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		return dto.ErrorUserDataErasuresResponse(err), err
+	}
+
+	erasureDTOs := make([]*dto.UserDataErasureDTO, 0, len(erasures))
+	for _, erasure := range erasures {
+		erasureDTOs = append(erasureDTOs, dto.UserDataErasureDTOFromEntity(erasure))
+	}
+
+	return dto.SuccessUserDataErasuresResponse(erasureDTOs), nil
+}
+
+// exportSession returns the transcript of a session with its attributes
+func (uc *UserDataUseCase) exportSession(ctx context.Context, session *entity.Session, exportedAt string) (*dto.UserSessionExportDTO, error) {
+	sessionID := string(session.ID)
+	messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := uc.attributeRepo.FindBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &dto.UserSessionExportDTO{
+		SessionTranscriptDTO: &dto.SessionTranscriptDTO{
+			Session:    dto.SessionDTOFromEntity(session),
+			Messages:   make([]*dto.MessageDTO, 0, len(messages)),
+			Tasks:      make([]*dto.TaskDTO, 0, len(tasks)),
+			ExportedAt: exportedAt,
+		},
+		Attributes: make([]*dto.SessionAttributeDTO, 0, len(attributes)),
+	}
+	for _, msg := range messages {
+		doc.Messages = append(doc.Messages, dto.MessageDTOFromEntity(msg))
+	}
+	// Tasks are listed newest first; the transcript reads oldest first
+	for i := len(tasks) - 1; i >= 0; i-- {
+		doc.Tasks = append(doc.Tasks, dto.TaskDTOFromEntity(tasks[i]))
+	}
+	for _, attr := range attributes {
+		doc.Attributes = append(doc.Attributes, dto.SessionAttributeDTOFromEntity(attr))
+	}
+	return doc, nil
+}
+
+// eraseSession deletes a session with its messages, tasks and attributes and adds them to the
+// counts of the erasure
+func (uc *UserDataUseCase) eraseSession(ctx context.Context, sessionID string, erasure *entity.UserDataErasure) error {
+	messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{})
+	if err != nil {
+		return err
+	}
+	if err := uc.messageRepo.DeleteBySessionID(ctx, sessionID); err != nil {
+		return err
+	}
+	erasure.Messages += len(messages)
+
+	tasks, err := uc.taskRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := uc.taskRepo.Delete(ctx, string(task.ID)); err != nil {
+			return err
+		}
+	}
+	erasure.Tasks += len(tasks)
+
+	attributes, err := uc.attributeRepo.FindBySessionID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attributes {
+		if _, err := uc.attributeRepo.Delete(ctx, sessionID, attr.Key); err != nil {
+			return err
+		}
+	}
+	erasure.Attributes += len(attributes)
+
+	if err := uc.sessionRepo.Delete(ctx, sessionID); err != nil {
+		return err
+	}
+	erasure.Sessions++
+	return nil
+}
+
+// verifyErased checks that neither the user nor any of their data is found any more
+func (uc *UserDataUseCase) verifyErased(ctx context.Context, user *entity.User, connectors []string, subject repository.EventSubject) error {
+	userID := user.ID.String()
+	if _, err := uc.userRepo.FindByID(ctx, userID); !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: user still found", ErrUserDataRemains)
+	}
+	if _, err := uc.prefsRepo.Get(ctx, userID); !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: preferences still found", ErrUserDataRemains)
+	}
+
+	sessions, err := uc.sessionRepo.FindByUserID(ctx, userID, repository.QueryOptions{})
+	if err != nil {
+		return err
+	}
+	if len(sessions) > 0 {
+		return fmt.Errorf("%w: %d sessions still found", ErrUserDataRemains, len(sessions))
+	}
+
+	for _, sessionID := range subject.SessionIDs {
+		messages, err := uc.messageRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{Limit: 1})
+		if err != nil {
+			return err
+		}
+		tasks, err := uc.taskRepo.FindBySessionID(ctx, sessionID, repository.QueryOptions{Limit: 1})
+		if err != nil {
+			return err
+		}
+		attributes, err := uc.attributeRepo.FindBySessionID(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if len(messages) > 0 || len(tasks) > 0 || len(attributes) > 0 {
+			return fmt.Errorf("%w: records of session %s still found", ErrUserDataRemains, sessionID)
+		}
+	}
+
+	events, err := uc.eventRepo.ListBySubject(ctx, subject)
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		return fmt.Errorf("%w: %d events still found", ErrUserDataRemains, len(events))
+	}
+	letters, err := uc.listDeadLetters(ctx, user, connectors, 1)
+	if err != nil {
+		return err
+	}
+	if len(letters) > 0 {
+		return fmt.Errorf("%w: message dead letters still found", ErrUserDataRemains)
+	}
+	return nil
+}
+
+// connectorsOf returns the names of the connectors whose users are on the channel of a user
+func (uc *UserDataUseCase) connectorsOf(user *entity.User) []string {
+	channel := user.Channel.String()
+	var connectors []string
+	for name, connectorChannel := range uc.channels {
+		if connectorChannel == channel {
+			connectors = append(connectors, name)
+		}
+	}
+	if len(connectors) == 0 {
+		return []string{channel}
+	}
+	slices.Sort(connectors)
+	return connectors
+}
+
+// listDeadLetters returns the dead letters of the messages of a user received by the
+// connectors, up to limit per connector (0 = no limit)
+func (uc *UserDataUseCase) listDeadLetters(ctx context.Context, user *entity.User, connectors []string, limit int) ([]*entity.MessageDeadLetter, error) {
+	var letters []*entity.MessageDeadLetter
+	if user.ChannelID == "" {
+		return letters, nil
+	}
+	for _, connector := range connectors {
+		found, err := uc.deadLetterRepo.List(ctx, repository.MessageDeadLetterFilter{
+			Connector: connector,
+			UserID:    user.ChannelID,
+			Limit:     limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, found...)
+	}
+	return letters, nil
+}
+
+// userDataSubject selects the stored events about a user and their sessions. Events name the
+// user by their ID or, like the router events, by their ID on the channel of one of the
+// connectors.
+func userDataSubject(user *entity.User, connectors []string, sessionIDs []string) repository.EventSubject {
+	subject := repository.EventSubject{UserIDs: []string{user.ID.String()}, SessionIDs: sessionIDs}
+	if user.ChannelID != "" {
+		for _, connector := range connectors {
+			subject.ChannelUsers = append(subject.ChannelUsers, repository.ChannelUser{Connector: connector, UserID: user.ChannelID})
+		}
+	}
+	return subject
+}
+
+// writeZipJSON adds a JSON document to a ZIP archive
+func writeZipJSON(archive *zip.Writer, name string, v any) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
@@ -0,0 +1,398 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// MockUserDataErasureRepository is a mock implementation of UserDataErasureRepository
+type MockUserDataErasureRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserDataErasureRepository) Create(ctx context.Context, erasure *entity.UserDataErasure) error {
+	args := m.Called(ctx, erasure)
+	return args.Error(0)
+}
+
+func (m *MockUserDataErasureRepository) List(ctx context.Context, filter repository.UserDataErasureFilter) ([]*entity.UserDataErasure, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.UserDataErasure), args.Error(1)
+}
+
+// MockEventRepository is a mock implementation of EventRepository
+type MockEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventRepository) Append(ctx context.Context, events ...*entity.StoredEvent) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func (m *MockEventRepository) GetByID(ctx context.Context, id string) (*entity.StoredEvent, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StoredEvent), args.Error(1)
+}
+
+func (m *MockEventRepository) List(ctx context.Context, filter repository.EventFilter) ([]*entity.StoredEvent, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StoredEvent), args.Error(1)
+}
+
+func (m *MockEventRepository) ListBySubject(ctx context.Context, subject repository.EventSubject) ([]*entity.StoredEvent, error) {
+	args := m.Called(ctx, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StoredEvent), args.Error(1)
+}
+
+func (m *MockEventRepository) DeleteBySubject(ctx context.Context, subject repository.EventSubject) (int, error) {
+	args := m.Called(ctx, subject)
+	return args.Int(0), args.Error(1)
+}
+
+// MockMessageDeadLetterRepository is a mock implementation of MessageDeadLetterRepository
+type MockMessageDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockMessageDeadLetterRepository) Create(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	args := m.Called(ctx, letter)
+	return args.Error(0)
+}
+
+func (m *MockMessageDeadLetterRepository) GetByID(ctx context.Context, id string) (*entity.MessageDeadLetter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.MessageDeadLetter), args.Error(1)
+}
+
+func (m *MockMessageDeadLetterRepository) List(ctx context.Context, filter repository.MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.MessageDeadLetter), args.Error(1)
+}
+
+func (m *MockMessageDeadLetterRepository) Update(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	args := m.Called(ctx, letter)
+	return args.Error(0)
+}
+
+func (m *MockMessageDeadLetterRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMessageDeadLetterRepository) DeleteByUser(ctx context.Context, connector, userID string) (int, error) {
+	args := m.Called(ctx, connector, userID)
+	return args.Int(0), args.Error(1)
+}
+
+type userDataMocks struct {
+	users       *MockUserRepository
+	prefs       *MockUserPreferencesRepository
+	sessions    *MockSessionRepository
+	messages    *MockMessageRepository
+	tasks       *MockTaskRepository
+	attributes  *MockSessionAttributeRepository
+	events      *MockEventRepository
+	deadLetters *MockMessageDeadLetterRepository
+	erasures    *MockUserDataErasureRepository
+}
+
+func newUserDataUseCase() (*UserDataUseCase, *userDataMocks) {
+	m := &userDataMocks{
+		users:       new(MockUserRepository),
+		prefs:       new(MockUserPreferencesRepository),
+		sessions:    new(MockSessionRepository),
+		messages:    new(MockMessageRepository),
+		tasks:       new(MockTaskRepository),
+		attributes:  new(MockSessionAttributeRepository),
+		events:      new(MockEventRepository),
+		deadLetters: new(MockMessageDeadLetterRepository),
+		erasures:    new(MockUserDataErasureRepository),
+	}
+	uc := NewUserDataUseCase(m.users, m.prefs, m.sessions, m.messages, m.tasks, m.attributes, m.events, m.deadLetters, m.erasures, logging.NewNoopLogger())
+	return uc, m
+}
+
+// readZip returns the files of a ZIP archive by name
+func readZip(t *testing.T, content []byte) map[string][]byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		files[f.Name] = data
+	}
+	return files
+}
+
+func TestUserDataUseCase_ExportUserData(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newUserDataUseCase()
+	user := entity.NewUser("telegram", "12345")
+	userID := user.ID.String()
+	session := entity.NewSession(userID)
+	sessionID := string(session.ID)
+	prefs := entity.NewUserPreferences(userID)
+	prefs.Language = "ru"
+	m.users.On("FindByID", ctx, userID).Return(user, nil)
+	m.prefs.On("Get", ctx, userID).Return(prefs, nil)
+	m.sessions.On("FindByUserID", ctx, userID).Return([]*entity.Session{session}, nil)
+	m.messages.On("FindBySessionID", ctx, sessionID).Return([]*entity.Message{
+		entity.NewUserMessage(sessionID, "Hello"),
+	}, nil)
+	m.tasks.On("FindBySessionID", ctx, sessionID).Return([]*entity.Task{}, nil)
+	m.attributes.On("FindBySessionID", ctx, sessionID).Return([]*entity.SessionAttribute{
+		entity.NewSessionAttribute(sessionID, "city", `"Berlin"`, 0),
+	}, nil)
+	subject := repository.EventSubject{
+		UserIDs:      []string{userID},
+		ChannelUsers: []repository.ChannelUser{{Connector: "telegram", UserID: "12345"}},
+		SessionIDs:   []string{sessionID},
+	}
+	m.events.On("ListBySubject", ctx, subject).Return([]*entity.StoredEvent{
+		entity.NewStoredEvent("router.message", `{"user_id":"12345","content":"Hello"}`, utils.Now()),
+	}, nil)
+	m.deadLetters.On("List", ctx, repository.MessageDeadLetterFilter{Connector: "telegram", UserID: "12345"}).Return([]*entity.MessageDeadLetter{
+		entity.NewMessageDeadLetter("telegram", "12345", "100", "Hello", nil, "llm unavailable"),
+	}, nil)
+
+	// Act
+	resp, err := uc.ExportUserData(ctx, userID)
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, "user-"+userID+".zip", resp.Export.FileName)
+	assert.Equal(t, "application/zip", resp.Export.ContentType)
+
+	files := readZip(t, resp.Export.Content)
+	require.Len(t, files, 2)
+
+	var doc dto.UserDataExportDTO
+	require.NoError(t, json.Unmarshal(files["user.json"], &doc))
+	assert.Equal(t, userID, doc.User.ID)
+	assert.Equal(t, "ru", doc.Preferences.Language)
+	assert.Equal(t, []string{sessionID}, doc.Sessions)
+	require.Len(t, doc.Events, 1)
+	assert.JSONEq(t, `{"user_id":"12345","content":"Hello"}`, string(doc.Events[0].Payload))
+	require.Len(t, doc.DeadLetters, 1)
+	assert.Equal(t, "Hello", doc.DeadLetters[0].Content)
+
+	var sessionDoc dto.UserSessionExportDTO
+	require.NoError(t, json.Unmarshal(files["sessions/"+sessionID+".json"], &sessionDoc))
+	assert.Equal(t, sessionID, sessionDoc.Session.ID)
+	require.Len(t, sessionDoc.Messages, 1)
+	assert.Equal(t, "Hello", sessionDoc.Messages[0].Content)
+	require.Len(t, sessionDoc.Attributes, 1)
+	assert.JSONEq(t, `"Berlin"`, string(sessionDoc.Attributes[0].Value))
+}
+
+func TestUserDataUseCase_ExportUserData_NotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newUserDataUseCase()
+	m.users.On("FindByID", ctx, "missing").Return(nil, repository.ErrNotFound)
+
+	// Act
+	resp, err := uc.ExportUserData(ctx, "missing")
+
+	// Assert
+	require.ErrorIs(t, err, repository.ErrNotFound)
+	assert.False(t, resp.Success)
+}
+
+func TestUserDataUseCase_EraseUserData(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newUserDataUseCase()
+	user := entity.NewUser("telegram", "12345")
+	userID := user.ID.String()
+	session := entity.NewSession(userID)
+	sessionID := string(session.ID)
+	task := entity.NewTask(sessionID, "weather", "{}")
+	attr := entity.NewSessionAttribute(sessionID, "city", `"Berlin"`, 0)
+
+	m.users.On("FindByID", ctx, userID).Return(user, nil).Once()
+	m.sessions.On("FindByUserID", ctx, userID).Return([]*entity.Session{session}, nil).Once()
+	m.messages.On("FindBySessionID", ctx, sessionID).Return([]*entity.Message{
+		entity.NewUserMessage(sessionID, "Hello"),
+		entity.NewAssistantMessage(sessionID, "Hi"),
+	}, nil).Once()
+	m.messages.On("DeleteBySessionID", ctx, sessionID).Return(nil)
+	m.tasks.On("FindBySessionID", ctx, sessionID).Return([]*entity.Task{task}, nil).Once()
+	m.tasks.On("Delete", ctx, string(task.ID)).Return(nil)
+	m.attributes.On("FindBySessionID", ctx, sessionID).Return([]*entity.SessionAttribute{attr}, nil).Once()
+	m.attributes.On("Delete", ctx, sessionID, "city").Return(true, nil)
+	m.sessions.On("Delete", ctx, sessionID).Return(nil)
+	subject := repository.EventSubject{
+		UserIDs:      []string{userID},
+		ChannelUsers: []repository.ChannelUser{{Connector: "telegram", UserID: "12345"}},
+		SessionIDs:   []string{sessionID},
+	}
+	m.events.On("DeleteBySubject", ctx, subject).Return(3, nil)
+	m.deadLetters.On("DeleteByUser", ctx, "telegram", "12345").Return(1, nil)
+	m.prefs.On("Delete", ctx, userID).Return(true, nil)
+	m.users.On("Delete", ctx, userID).Return(nil)
+
+	// Verification finds nothing
+	m.users.On("FindByID", ctx, userID).Return(nil, repository.ErrNotFound)
+	m.prefs.On("Get", ctx, userID).Return(nil, repository.ErrNotFound)
+	m.sessions.On("FindByUserID", ctx, userID).Return([]*entity.Session{}, nil)
+	m.messages.On("FindBySessionID", ctx, sessionID).Return([]*entity.Message{}, nil)
+	m.tasks.On("FindBySessionID", ctx, sessionID).Return([]*entity.Task{}, nil)
+	m.attributes.On("FindBySessionID", ctx, sessionID).Return([]*entity.SessionAttribute{}, nil)
+	m.events.On("ListBySubject", ctx, subject).Return([]*entity.StoredEvent{}, nil)
+	m.deadLetters.On("List", ctx, repository.MessageDeadLetterFilter{Connector: "telegram", UserID: "12345", Limit: 1}).Return([]*entity.MessageDeadLetter{}, nil)
+
+	m.erasures.On("Create", ctx, mock.MatchedBy(func(erasure *entity.UserDataErasure) bool {
+		return erasure.UserID == userID && erasure.RequestedBy == "abc" && erasure.Verified
+	})).Return(nil)
+
+	// Act
+	resp, err := uc.EraseUserData(ctx, userID, dto.EraseUserDataRequest{Reason: "ticket 42", RequestedBy: "abc"})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, 1, resp.Erasure.Sessions)
+	assert.Equal(t, 2, resp.Erasure.Messages)
+	assert.Equal(t, 1, resp.Erasure.Tasks)
+	assert.Equal(t, 1, resp.Erasure.Attributes)
+	assert.True(t, resp.Erasure.Preferences)
+	assert.True(t, resp.Erasure.Verified)
+	assert.Equal(t, "ticket 42", resp.Erasure.Reason)
+	m.users.AssertExpectations(t)
+	m.events.AssertExpectations(t)
+	m.deadLetters.AssertExpectations(t)
+	m.erasures.AssertExpectations(t)
+}
+
+func TestUserDataUseCase_EraseUserData_MasksReason(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newUserDataUseCase()
+	masker, err := logging.NewMasker(nil, []string{`TICKET-\d+`})
+	require.NoError(t, err)
+	uc.SetMasker(masker)
+	user := entity.NewUser("telegram", "12345")
+	userID := user.ID.String()
+
+	m.users.On("FindByID", ctx, userID).Return(user, nil).Once()
+	m.sessions.On("FindByUserID", ctx, userID).Return([]*entity.Session{}, nil)
+	m.events.On("DeleteBySubject", ctx, mock.Anything).Return(0, nil)
+	m.deadLetters.On("DeleteByUser", ctx, "telegram", "12345").Return(0, nil)
+	m.prefs.On("Delete", ctx, userID).Return(false, nil)
+	m.users.On("Delete", ctx, userID).Return(nil)
+	m.users.On("FindByID", ctx, userID).Return(nil, repository.ErrNotFound)
+	m.prefs.On("Get", ctx, userID).Return(nil, repository.ErrNotFound)
+	m.events.On("ListBySubject", ctx, mock.Anything).Return([]*entity.StoredEvent{}, nil)
+	m.deadLetters.On("List", ctx, mock.Anything).Return([]*entity.MessageDeadLetter{}, nil)
+	m.erasures.On("Create", ctx, mock.Anything).Return(nil)
+
+	// Act
+	resp, err := uc.EraseUserData(ctx, userID, dto.EraseUserDataRequest{Reason: "TICKET-12345, user pasted sk-1234567890abcdefghij"})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.NotContains(t, resp.Erasure.Reason, "sk-1234567890abcdefghij")
+	assert.NotContains(t, resp.Erasure.Reason, "TICKET-12345")
+	assert.Contains(t, resp.Erasure.Reason, ", user pasted sk")
+	m.erasures.AssertExpectations(t)
+}
+
+func TestUserDataUseCase_EraseUserData_Unverified(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newUserDataUseCase()
+	user := entity.NewUser("telegram", "12345")
+	userID := user.ID.String()
+	m.users.On("FindByID", ctx, userID).Return(user, nil)
+	m.sessions.On("FindByUserID", ctx, userID).Return([]*entity.Session{}, nil)
+	m.events.On("DeleteBySubject", ctx, mock.Anything).Return(0, nil)
+	m.deadLetters.On("DeleteByUser", ctx, "telegram", "12345").Return(0, nil)
+	m.prefs.On("Delete", ctx, userID).Return(false, nil)
+	m.users.On("Delete", ctx, userID).Return(nil)
+	m.erasures.On("Create", ctx, mock.MatchedBy(func(erasure *entity.UserDataErasure) bool {
+		return !erasure.Verified
+	})).Return(nil)
+
+	// Act
+	resp, err := uc.EraseUserData(ctx, userID, dto.EraseUserDataRequest{})
+
+	// Assert
+	require.ErrorIs(t, err, ErrUserDataRemains)
+	assert.False(t, resp.Success)
+	m.erasures.AssertExpectations(t)
+}
+
+func TestUserDataUseCase_EraseUserData_NotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newUserDataUseCase()
+	m.users.On("FindByID", ctx, "missing").Return(nil, repository.ErrNotFound)
+
+	// Act
+	resp, err := uc.EraseUserData(ctx, "missing", dto.EraseUserDataRequest{})
+
+	// Assert
+	require.ErrorIs(t, err, repository.ErrNotFound)
+	assert.False(t, resp.Success)
+	m.erasures.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserDataUseCase_ListErasures(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	uc, m := newUserDataUseCase()
+	erasure := entity.NewUserDataErasure("user-1", "abc", "")
+	m.erasures.On("List", ctx, repository.UserDataErasureFilter{UserID: "user-1", Limit: maxUserDataErasuresLimit}).
+		Return([]*entity.UserDataErasure{erasure}, nil)
+
+	// Act
+	resp, err := uc.ListErasures(ctx, dto.ListUserDataErasuresRequest{UserID: "user-1", Limit: 10000})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	require.Len(t, resp.Erasures, 1)
+	assert.Equal(t, erasure.ID, resp.Erasures[0].ID)
+}
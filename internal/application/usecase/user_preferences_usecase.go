@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// UserPreferencesUseCase handles the preferences users chose for their replies
+type UserPreferencesUseCase struct {
+	prefsRepo repository.UserPreferencesRepository
+	userRepo  repository.UserRepository
+	logger    logging.Logger
+}
+
+// NewUserPreferencesUseCase creates a new UserPreferencesUseCase
+func NewUserPreferencesUseCase(
+	prefsRepo repository.UserPreferencesRepository,
+	userRepo repository.UserRepository,
+	logger logging.Logger,
+) *UserPreferencesUseCase {
+	return &UserPreferencesUseCase{
+		prefsRepo: prefsRepo,
+		userRepo:  userRepo,
+		logger:    logger,
+	}
+}
+
+// GetPreferences retrieves the preferences of a user. Users who never set a preference get
+// empty preferences.
+func (uc *UserPreferencesUseCase) GetPreferences(ctx context.Context, userID string) (*dto.UserPreferencesResponse, error) {
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return handleUserPreferencesError(err, "failed to find user")
+	}
+
+	prefs, err := uc.prefsRepo.Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		prefs, err = entity.NewUserPreferences(userID), nil
+	}
+	if err != nil {
+		return handleUserPreferencesError(err, "failed to get preferences")
+	}
+
+	return dto.SuccessUserPreferencesResponse(dto.UserPreferencesDTOFromEntity(prefs)), nil
+}
+
+// UpdatePreferences replaces the preferences of a user
+func (uc *UserPreferencesUseCase) UpdatePreferences(ctx context.Context, userID string, req dto.UpdateUserPreferencesRequest) (*dto.UserPreferencesResponse, error) {
+	prefs := entity.NewUserPreferences(userID)
+	for _, field := range []struct{ name, value string }{
+		{entity.PreferenceLanguage, req.Language},
+		{entity.PreferenceModel, req.Model},
+		{entity.PreferenceTimezone, req.Timezone},
+		{entity.PreferenceVerbosity, req.Verbosity},
+		{entity.PreferenceInstructions, req.Instructions},
+	} {
+		if err := prefs.Set(field.name, field.value); err != nil {
+			return dto.ErrorUserPreferencesResponse(err), nil
+		}
+	}
+
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return handleUserPreferencesError(err, "failed to find user")
+	}
+
+	if err := uc.prefsRepo.Save(ctx, prefs); err != nil {
+		return handleUserPreferencesError(err, "failed to save preferences")
+	}
+
+	uc.logger.Info("user preferences updated", "user_id", userID)
+	return dto.SuccessUserPreferencesResponse(dto.UserPreferencesDTOFromEntity(prefs)), nil
+}
+
+// DeletePreferences resets the preferences of a user to the deployment defaults
+func (uc *UserPreferencesUseCase) DeletePreferences(ctx context.Context, userID string) (*dto.UserPreferencesResponse, error) {
+	if _, err := uc.prefsRepo.Delete(ctx, userID); err != nil {
+		return handleUserPreferencesError(err, "failed to delete preferences")
+	}
+
+	uc.logger.Info("user preferences reset", "user_id", userID)
+	return &dto.UserPreferencesResponse{Success: true}, nil
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockUserPreferencesRepository is a mock implementation of UserPreferencesRepository
+type MockUserPreferencesRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserPreferencesRepository) Get(ctx context.Context, userID string) (*entity.UserPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserPreferences), args.Error(1)
+}
+
+func (m *MockUserPreferencesRepository) Save(ctx context.Context, prefs *entity.UserPreferences) error {
+	args := m.Called(ctx, prefs)
+	return args.Error(0)
+}
+
+func (m *MockUserPreferencesRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestUserPreferencesUseCase_GetPreferences_Empty(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	prefsRepo := new(MockUserPreferencesRepository)
+	userRepo := new(MockUserRepository)
+	uc := NewUserPreferencesUseCase(prefsRepo, userRepo, logging.NewNoopLogger())
+	user := entity.NewUser("telegram", "12345")
+	userRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	prefsRepo.On("Get", ctx, user.ID.String()).Return(nil, repository.ErrNotFound)
+
+	// Act
+	resp, err := uc.GetPreferences(ctx, user.ID.String())
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, user.ID.String(), resp.Preferences.UserID)
+	assert.Empty(t, resp.Preferences.Language)
+}
+
+func TestUserPreferencesUseCase_UpdatePreferences(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	prefsRepo := new(MockUserPreferencesRepository)
+	userRepo := new(MockUserRepository)
+	uc := NewUserPreferencesUseCase(prefsRepo, userRepo, logging.NewNoopLogger())
+	user := entity.NewUser("telegram", "12345")
+	userRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	prefsRepo.On("Save", ctx, mock.MatchedBy(func(prefs *entity.UserPreferences) bool {
+		return prefs.Language == "ru" && prefs.Timezone == "Europe/Berlin" && prefs.Verbosity == entity.VerbosityConcise
+	})).Return(nil)
+
+	// Act
+	resp, err := uc.UpdatePreferences(ctx, user.ID.String(), dto.UpdateUserPreferencesRequest{
+		Language:  "ru",
+		Timezone:  "Europe/Berlin",
+		Verbosity: "concise",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "concise", resp.Preferences.Verbosity)
+	prefsRepo.AssertExpectations(t)
+}
+
+func TestUserPreferencesUseCase_UpdatePreferences_Invalid(t *testing.T) {
+	ctx := context.Background()
+	prefsRepo := new(MockUserPreferencesRepository)
+	userRepo := new(MockUserRepository)
+	uc := NewUserPreferencesUseCase(prefsRepo, userRepo, logging.NewNoopLogger())
+
+	resp, err := uc.UpdatePreferences(ctx, "user-1", dto.UpdateUserPreferencesRequest{Timezone: "Mars/Olympus"})
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, entity.ErrInvalidTimezone.Error())
+
+	userRepo.On("FindByID", ctx, "missing").Return(nil, repository.ErrNotFound)
+	_, err = uc.UpdatePreferences(ctx, "missing", dto.UpdateUserPreferencesRequest{Language: "en"})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	prefsRepo.AssertNotCalled(t, "Save")
+}
+
+func TestUserPreferencesUseCase_DeletePreferences(t *testing.T) {
+	ctx := context.Background()
+	prefsRepo := new(MockUserPreferencesRepository)
+	uc := NewUserPreferencesUseCase(prefsRepo, new(MockUserRepository), logging.NewNoopLogger())
+	prefsRepo.On("Delete", ctx, "user-1").Return(false, nil)
+
+	resp, err := uc.DeletePreferences(ctx, "user-1")
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Limits for the number of deliveries returned by ListDeliveries
+const (
+	defaultWebhookDeliveriesLimit = 50
+	maxWebhookDeliveriesLimit     = 500
+)
+
+// WebhookConfig holds configuration for the WebhookUseCase
+type WebhookConfig struct {
+	// Enabled reports whether outbound webhooks are enabled
+	Enabled bool
+
+	// Endpoints are the configured webhook endpoints
+	Endpoints []*dto.WebhookEndpointDTO
+}
+
+// WebhookUseCase handles inspecting outbound webhooks: the configured endpoints and the delivery log
+type WebhookUseCase struct {
+	deliveryRepo repository.WebhookDeliveryRepository
+	config       WebhookConfig
+	logger       logging.Logger
+}
+
+// NewWebhookUseCase creates a new WebhookUseCase
+func NewWebhookUseCase(deliveryRepo repository.WebhookDeliveryRepository, config WebhookConfig, logger logging.Logger) *WebhookUseCase {
+	return &WebhookUseCase{
+		deliveryRepo: deliveryRepo,
+		config:       config,
+		logger:       logger,
+	}
+}
+
+// ListEndpoints returns the configured webhook endpoints
+func (uc *WebhookUseCase) ListEndpoints(ctx context.Context) (*dto.WebhookEndpointsResponse, error) {
+	return dto.SuccessWebhookEndpointsResponse(uc.config.Enabled, uc.config.Endpoints), nil
+}
+
+// ListDeliveries returns the logged webhook deliveries, newest first.
+// A non-positive limit uses the default; limits above the maximum are capped.
+func (uc *WebhookUseCase) ListDeliveries(ctx context.Context, req dto.ListWebhookDeliveriesRequest) (*dto.WebhookDeliveriesResponse, error) {
+	switch req.Status {
+	case "", entity.WebhookDeliveryPending, entity.WebhookDeliveryDelivered, entity.WebhookDeliveryFailed:
+	default:
+		return dto.ErrorWebhookDeliveriesResponse(fmt.Errorf("invalid delivery status: %q", req.Status)), nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultWebhookDeliveriesLimit
+	}
+	limit = min(limit, maxWebhookDeliveriesLimit)
+
+	deliveries, err := uc.deliveryRepo.List(ctx, repository.WebhookDeliveryFilter{
+		Endpoint: req.Endpoint,
+		Status:   req.Status,
+		Limit:    limit,
+		Offset:   req.Offset,
+	})
+	if err != nil {
+		return dto.ErrorWebhookDeliveriesResponse(err), err
+	}
+
+	deliveryDTOs := make([]*dto.WebhookDeliveryDTO, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryDTOs = append(deliveryDTOs, dto.WebhookDeliveryDTOFromEntity(delivery))
+	}
+
+	return dto.SuccessWebhookDeliveriesResponse(deliveryDTOs), nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// MockWebhookDeliveryRepository is a mock implementation of WebhookDeliveryRepository
+type MockWebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) List(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WebhookDelivery), args.Error(1)
+}
+
+func TestWebhookUseCase_ListEndpoints(t *testing.T) {
+	endpoints := []*dto.WebhookEndpointDTO{{Name: "ci", URL: "https://ci.example.com/hook", Events: []string{"task.completed"}, Signed: true}}
+	uc := NewWebhookUseCase(new(MockWebhookDeliveryRepository), WebhookConfig{Enabled: true, Endpoints: endpoints}, new(MockLogger))
+
+	resp, err := uc.ListEndpoints(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.True(t, resp.Enabled)
+	assert.Equal(t, endpoints, resp.Endpoints)
+}
+
+func TestWebhookUseCase_ListDeliveries(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWebhookDeliveryRepository)
+	uc := NewWebhookUseCase(mockRepo, WebhookConfig{Enabled: true}, new(MockLogger))
+
+	delivery := entity.NewWebhookDelivery("ci", "https://ci.example.com/hook", "task.completed", `{"id":"1"}`)
+	delivery.SetDelivered(200)
+	mockRepo.On("List", ctx, repository.WebhookDeliveryFilter{Endpoint: "ci", Status: entity.WebhookDeliveryDelivered, Limit: defaultWebhookDeliveriesLimit, Offset: 10}).
+		Return([]*entity.WebhookDelivery{delivery}, nil)
+
+	resp, err := uc.ListDeliveries(ctx, dto.ListWebhookDeliveriesRequest{Endpoint: "ci", Status: entity.WebhookDeliveryDelivered, Offset: 10})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	require.Len(t, resp.Deliveries, 1)
+	assert.Equal(t, delivery.ID, resp.Deliveries[0].ID)
+	assert.Equal(t, entity.WebhookDeliveryDelivered, resp.Deliveries[0].Status)
+	assert.Equal(t, 200, resp.Deliveries[0].ResponseStatus)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookUseCase_ListDeliveries_CapsLimit(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWebhookDeliveryRepository)
+	uc := NewWebhookUseCase(mockRepo, WebhookConfig{}, new(MockLogger))
+
+	mockRepo.On("List", ctx, repository.WebhookDeliveryFilter{Limit: maxWebhookDeliveriesLimit}).Return([]*entity.WebhookDelivery{}, nil)
+
+	resp, err := uc.ListDeliveries(ctx, dto.ListWebhookDeliveriesRequest{Limit: 10000})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookUseCase_ListDeliveries_InvalidStatus(t *testing.T) {
+	mockRepo := new(MockWebhookDeliveryRepository)
+	uc := NewWebhookUseCase(mockRepo, WebhookConfig{}, new(MockLogger))
+
+	resp, err := uc.ListDeliveries(context.Background(), dto.ListWebhookDeliveriesRequest{Status: "lost"})
+
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "invalid delivery status")
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestWebhookUseCase_ListDeliveries_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWebhookDeliveryRepository)
+	uc := NewWebhookUseCase(mockRepo, WebhookConfig{}, new(MockLogger))
+
+	mockRepo.On("List", ctx, mock.Anything).Return(nil, errors.New("database is locked"))
+
+	resp, err := uc.ListDeliveries(ctx, dto.ListWebhookDeliveriesRequest{})
+
+	assert.Error(t, err)
+	assert.False(t, resp.Success)
+}
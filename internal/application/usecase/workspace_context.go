@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// workspaceContextKey is the context key of the workspace a request is restricted to
+type workspaceContextKey struct{}
+
+// WithWorkspace restricts the use cases called with the returned context to a workspace:
+// the sessions they create belong to it, and sessions of other workspaces are neither
+// listed nor found. It is set for requests authenticated with a workspace-bound API key.
+func WithWorkspace(ctx context.Context, workspace valueobject.WorkspaceID) context.Context {
+	return context.WithValue(ctx, workspaceContextKey{}, workspace)
+}
+
+// WorkspaceFromContext returns the workspace a context is restricted to,
+// or an empty ID if it may access all workspaces
+func WorkspaceFromContext(ctx context.Context) valueobject.WorkspaceID {
+	workspace, _ := ctx.Value(workspaceContextKey{}).(valueobject.WorkspaceID)
+	return workspace
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Errors returned by the WorkspaceUseCase
+var (
+	ErrInvalidWorkspaceID = errors.New("workspace id may only contain letters, digits, '-' and '_'")
+
+	// ErrDefaultWorkspace is returned when deleting the default workspace
+	ErrDefaultWorkspace = fmt.Errorf("%w: the default workspace cannot be deleted", repository.ErrConflict)
+)
+
+// WorkspaceUseCase handles the workspaces (tenants) managed through the admin API.
+// Connectors are mapped to workspaces in the configuration; a workspace sets the LLM
+// token budget of the replies in its sessions.
+type WorkspaceUseCase struct {
+	workspaceRepo repository.WorkspaceRepository
+	logger        logging.Logger
+}
+
+// NewWorkspaceUseCase creates a new WorkspaceUseCase
+func NewWorkspaceUseCase(workspaceRepo repository.WorkspaceRepository, logger logging.Logger) *WorkspaceUseCase {
+	return &WorkspaceUseCase{
+		workspaceRepo: workspaceRepo,
+		logger:        logger,
+	}
+}
+
+// CreateWorkspace creates a new workspace
+func (uc *WorkspaceUseCase) CreateWorkspace(ctx context.Context, req dto.CreateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	if !valueobject.WorkspaceID(req.ID).IsValid() {
+		return dto.ErrorWorkspaceResponse(fmt.Errorf("%w: %q", ErrInvalidWorkspaceID, req.ID)), nil
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return dto.ErrorWorkspaceResponse(fmt.Errorf("name is required")), nil
+	}
+
+	workspace := entity.NewWorkspace(req.ID, name)
+	workspace.MaxTokens = req.MaxTokens
+	if err := uc.workspaceRepo.Create(ctx, workspace); err != nil {
+		return handleWorkspaceError(err, "failed to create workspace")
+	}
+
+	uc.logger.Info("workspace created", "id", workspace.ID, "name", workspace.Name)
+	return dto.SuccessWorkspaceResponse(dto.WorkspaceDTOFromEntity(workspace)), nil
+}
+
+// GetWorkspace retrieves a workspace by ID
+func (uc *WorkspaceUseCase) GetWorkspace(ctx context.Context, id string) (*dto.WorkspaceResponse, error) {
+	workspace, err := uc.workspaceRepo.FindByID(ctx, id)
+	if err != nil {
+		return handleWorkspaceError(err, "failed to find workspace")
+	}
+
+	return dto.SuccessWorkspaceResponse(dto.WorkspaceDTOFromEntity(workspace)), nil
+}
+
+// ListWorkspaces returns all workspaces, oldest first
+func (uc *WorkspaceUseCase) ListWorkspaces(ctx context.Context) (*dto.WorkspacesResponse, error) {
+	workspaces, err := uc.workspaceRepo.List(ctx)
+	if err != nil {
+		return dto.ErrorWorkspacesResponse(err), err
+	}
+
+	workspaceDTOs := make([]*dto.WorkspaceDTO, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		workspaceDTOs = append(workspaceDTOs, dto.WorkspaceDTOFromEntity(workspace))
+	}
+
+	return dto.SuccessWorkspacesResponse(workspaceDTOs), nil
+}
+
+// UpdateWorkspace updates the name and token budget of a workspace
+func (uc *WorkspaceUseCase) UpdateWorkspace(ctx context.Context, id string, req dto.UpdateWorkspaceRequest) (*dto.WorkspaceResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return dto.ErrorWorkspaceResponse(fmt.Errorf("name is required")), nil
+	}
+
+	workspace, err := uc.workspaceRepo.FindByID(ctx, id)
+	if err != nil {
+		return handleWorkspaceError(err, "failed to find workspace")
+	}
+
+	workspace.Name = name
+	workspace.MaxTokens = req.MaxTokens
+	if err := uc.workspaceRepo.Update(ctx, workspace); err != nil {
+		return handleWorkspaceError(err, "failed to update workspace")
+	}
+
+	uc.logger.Info("workspace updated", "id", workspace.ID, "max_tokens", workspace.MaxTokens)
+	return dto.SuccessWorkspaceResponse(dto.WorkspaceDTOFromEntity(workspace)), nil
+}
+
+// DeleteWorkspace deletes a workspace. Sessions of the workspace are kept; connectors still
+// mapped to it start their sessions in it with the router's token budget.
+func (uc *WorkspaceUseCase) DeleteWorkspace(ctx context.Context, id string) (*dto.WorkspaceResponse, error) {
+	if valueobject.WorkspaceID(id) == valueobject.DefaultWorkspaceID {
+		return handleWorkspaceError(ErrDefaultWorkspace, "failed to delete workspace")
+	}
+
+	if err := uc.workspaceRepo.Delete(ctx, id); err != nil {
+		return handleWorkspaceError(err, "failed to delete workspace")
+	}
+
+	uc.logger.Info("workspace deleted", "id", id)
+	return &dto.WorkspaceResponse{Success: true}, nil
+}
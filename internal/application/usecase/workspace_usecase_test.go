@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MockWorkspaceRepository is a mock implementation of WorkspaceRepository
+type MockWorkspaceRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkspaceRepository) Create(ctx context.Context, workspace *entity.Workspace) error {
+	args := m.Called(ctx, workspace)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) FindByID(ctx context.Context, id string) (*entity.Workspace, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) List(ctx context.Context) ([]*entity.Workspace, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) Update(ctx context.Context, workspace *entity.Workspace) error {
+	args := m.Called(ctx, workspace)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestWorkspaceUseCase_CreateWorkspace(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockWorkspaceRepository)
+	uc := NewWorkspaceUseCase(mockRepo, logging.NewNoopLogger())
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(w *entity.Workspace) bool {
+		return w.ID == "support" && w.MaxTokens == 2000
+	})).Return(nil)
+
+	// Act
+	resp, err := uc.CreateWorkspace(ctx, dto.CreateWorkspaceRequest{ID: "support", Name: " Support ", MaxTokens: 2000})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.Equal(t, "Support", resp.Workspace.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWorkspaceUseCase_CreateWorkspace_Validation(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWorkspaceRepository)
+	uc := NewWorkspaceUseCase(mockRepo, logging.NewNoopLogger())
+
+	badID, err := uc.CreateWorkspace(ctx, dto.CreateWorkspaceRequest{ID: "support team", Name: "Support"})
+	require.NoError(t, err)
+	noName, err := uc.CreateWorkspace(ctx, dto.CreateWorkspaceRequest{ID: "support"})
+	require.NoError(t, err)
+
+	assert.False(t, badID.Success)
+	assert.Contains(t, badID.Error, ErrInvalidWorkspaceID.Error())
+	assert.False(t, noName.Success)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestWorkspaceUseCase_UpdateWorkspace(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWorkspaceRepository)
+	uc := NewWorkspaceUseCase(mockRepo, logging.NewNoopLogger())
+	workspace := entity.NewWorkspace("support", "Support")
+	mockRepo.On("FindByID", ctx, "support").Return(workspace, nil)
+	mockRepo.On("FindByID", ctx, "missing").Return(nil, repository.ErrNotFound)
+	mockRepo.On("Update", ctx, workspace).Return(nil)
+
+	resp, err := uc.UpdateWorkspace(ctx, "support", dto.UpdateWorkspaceRequest{Name: "Customer Support", MaxTokens: 500})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, 500, resp.Workspace.MaxTokens)
+
+	_, err = uc.UpdateWorkspace(ctx, "missing", dto.UpdateWorkspaceRequest{Name: "Missing"})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestWorkspaceUseCase_DeleteWorkspace(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWorkspaceRepository)
+	uc := NewWorkspaceUseCase(mockRepo, logging.NewNoopLogger())
+	mockRepo.On("Delete", ctx, "support").Return(nil)
+
+	resp, err := uc.DeleteWorkspace(ctx, "support")
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	_, err = uc.DeleteWorkspace(ctx, "default")
+	assert.ErrorIs(t, err, ErrDefaultWorkspace)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	mockRepo.AssertNotCalled(t, "Delete", ctx, "default")
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ValidationError represents a webhook configuration validation error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
+}
+
+// Endpoint is an external URL that receives the events of the selected types
+type Endpoint struct {
+	// Name identifies the endpoint in the delivery log
+	Name string
+
+	// URL is the http or https URL the events are posted to
+	URL string
+
+	// Secret signs the payloads; empty sends them unsigned
+	Secret string
+
+	// Events are the event types posted to the endpoint, e.g. "task.completed" or "connector.*"
+	Events []string
+}
+
+// Config holds configuration for the webhook dispatcher
+type Config struct {
+	// Endpoints are the endpoints events are posted to
+	Endpoints []Endpoint
+
+	// Timeout limits a single delivery attempt
+	Timeout time.Duration
+
+	// MaxAttempts is how many times a delivery is attempted before it fails
+	MaxAttempts int
+
+	// RetryBackoff is the delay before the first retry; it doubles with every retry
+	RetryBackoff time.Duration
+
+	// Workers is the number of deliveries sent concurrently
+	Workers int
+
+	// QueueSize is the number of deliveries waiting to be sent; further deliveries fail immediately
+	QueueSize int
+}
+
+// DefaultConfig returns the default configuration for the webhook dispatcher
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:      10 * time.Second,
+		MaxAttempts:  5,
+		RetryBackoff: time.Second,
+		Workers:      4,
+		QueueSize:    1000,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.Timeout <= 0 {
+		return &ValidationError{Field: "Timeout", Message: "must be positive"}
+	}
+
+	if c.MaxAttempts <= 0 {
+		return &ValidationError{Field: "MaxAttempts", Message: "must be positive"}
+	}
+
+	if c.RetryBackoff < 0 {
+		return &ValidationError{Field: "RetryBackoff", Message: "must not be negative"}
+	}
+
+	if c.Workers <= 0 {
+		return &ValidationError{Field: "Workers", Message: "must be positive"}
+	}
+
+	if c.QueueSize <= 0 {
+		return &ValidationError{Field: "QueueSize", Message: "must be positive"}
+	}
+
+	names := make(map[string]bool, len(c.Endpoints))
+	for i, endpoint := range c.Endpoints {
+		field := fmt.Sprintf("Endpoints[%d]", i)
+		if endpoint.Name == "" {
+			return &ValidationError{Field: field + ".Name", Message: "is required"}
+		}
+		if names[endpoint.Name] {
+			return &ValidationError{Field: field + ".Name", Message: "must be unique"}
+		}
+		names[endpoint.Name] = true
+
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ValidationError{Field: field + ".URL", Message: "must be an http or https URL"}
+		}
+
+		if len(endpoint.Events) == 0 {
+			return &ValidationError{Field: field + ".Events", Message: "must list at least one event type"}
+		}
+	}
+
+	return nil
+}
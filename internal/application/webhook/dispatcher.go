@@ -0,0 +1,490 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// Headers of webhook requests
+const (
+	HeaderEvent     = "X-Nexflow-Event"     // Event type
+	HeaderDelivery  = "X-Nexflow-Delivery"  // Delivery ID, the same for all attempts
+	HeaderTimestamp = "X-Nexflow-Timestamp" // Unix time of the attempt, in seconds
+	HeaderSignature = "X-Nexflow-Signature" // "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>"
+)
+
+// signaturePrefix prefixes the hex signature in HeaderSignature
+const signaturePrefix = "sha256="
+
+// maxErrorBody is the number of response body bytes kept in the error of a rejected delivery
+const maxErrorBody = 256
+
+// deliveryJobKind is the kind of the jobs of the job queue sending deliveries
+const deliveryJobKind = "webhook_delivery"
+
+// WebhookMetrics holds all metrics for the webhook dispatcher
+type WebhookMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	DeliveriesSucceeded *metrics.Counter
+	DeliveriesFailed    *metrics.Counter
+	DeliveriesDropped   *metrics.Counter
+	Retries             *metrics.Counter
+	AttemptDuration     *metrics.Histogram
+}
+
+// NewWebhookMetrics creates a new WebhookMetrics instance
+func NewWebhookMetrics() *WebhookMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &WebhookMetrics{
+		registry: registry,
+
+		DeliveriesSucceeded: registry.GetCounter("webhook_deliveries_succeeded_total"),
+		DeliveriesFailed:    registry.GetCounter("webhook_deliveries_failed_total"),
+		DeliveriesDropped:   registry.GetCounter("webhook_deliveries_dropped_total"),
+		Retries:             registry.GetCounter("webhook_delivery_retries_total"),
+		AttemptDuration:     registry.GetHistogram("webhook_attempt_duration_seconds", metrics.DefaultBuckets()),
+	}
+}
+
+// Registry returns the registry holding the webhook dispatcher metrics
+func (m *WebhookMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Body is the JSON body posted to webhook endpoints
+type Body struct {
+	ID         string          `json:"id"`          // Delivery ID, also sent in HeaderDelivery
+	Type       string          `json:"type"`        // Event type
+	OccurredAt time.Time       `json:"occurred_at"` // Timestamp when the event was published
+	Payload    json.RawMessage `json:"payload"`     // Event fields, as in the event store
+}
+
+// job is a delivery waiting to be sent
+type job struct {
+	endpoint Endpoint
+	delivery *entity.WebhookDelivery
+}
+
+// Dispatcher posts the events of the configured types to webhook endpoints.
+// Every delivery is recorded in the delivery log, retried with exponential backoff
+// on network errors, 429 and 5xx responses, and fails on other responses.
+type Dispatcher struct {
+	eventBus     *eventbus.EventBus
+	deliveryRepo repository.WebhookDeliveryRepository
+	client       *http.Client
+	logger       logging.Logger
+	config       *Config
+	metrics      *WebhookMetrics
+	queue        chan job
+	jobQueue     ports.JobQueue
+
+	mu           sync.Mutex
+	started      bool
+	subscription *eventbus.EventSubscription
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewDispatcher creates a new Dispatcher instance
+//
+// Parameters:
+//   - eventBus: EventBus the events are taken from
+//   - deliveryRepo: WebhookDeliveryRepository for the delivery log (optional)
+//   - logger: Structured logger for logging
+//   - config: Webhook configuration (uses defaults if nil)
+//
+// Returns:
+//   - *Dispatcher: Initialized webhook dispatcher
+func NewDispatcher(
+	eventBus *eventbus.EventBus,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	logger logging.Logger,
+	config *Config,
+) *Dispatcher {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	if err := config.Validate(); err != nil {
+		logger.Error("invalid webhook configuration, no webhooks are sent", "error", err)
+		config = DefaultConfig()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		eventBus:     eventBus,
+		deliveryRepo: deliveryRepo,
+		client:       &http.Client{Timeout: config.Timeout},
+		logger:       logger,
+		config:       config,
+		metrics:      NewWebhookMetrics(),
+		queue:        make(chan job, config.QueueSize),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Metrics returns the webhook dispatcher metrics
+func (d *Dispatcher) Metrics() *WebhookMetrics {
+	return d.metrics
+}
+
+// Endpoints returns the configured endpoints
+func (d *Dispatcher) Endpoints() []Endpoint {
+	return d.config.Endpoints
+}
+
+// SetJobQueue sets the persistent job queue the deliveries are sent from instead of the
+// in-memory queue, so that deliveries pending at a restart are still sent. Retries are then
+// delayed by the backoff of the job queue. It must be called before Start.
+func (d *Dispatcher) SetJobQueue(queue ports.JobQueue) {
+	d.jobQueue = queue
+	queue.Register(deliveryJobKind, d.handleJob)
+}
+
+// Start subscribes to the event types of all endpoints and starts the delivery workers
+func (d *Dispatcher) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started {
+		return fmt.Errorf("webhook dispatcher already started")
+	}
+	if d.eventBus == nil {
+		return fmt.Errorf("webhooks require the event bus")
+	}
+
+	if d.jobQueue == nil {
+		for i := 0; i < d.config.Workers; i++ {
+			d.wg.Add(1)
+			go d.worker()
+		}
+	}
+
+	if types := d.eventTypes(); len(types) > 0 {
+		// Deliveries are retried by the dispatcher itself; a failing handler would create them twice
+		d.subscription = d.eventBus.SubscribeWithOptions(types, eventbus.SubscribeOptions{
+			Name:  "webhooks",
+			Retry: &eventbus.RetryPolicy{MaxAttempts: 1},
+		}, d.handleEvent)
+	}
+	d.started = true
+
+	d.logger.Info("webhook dispatcher started", "endpoints", len(d.config.Endpoints))
+	return nil
+}
+
+// Stop unsubscribes from the event bus and waits for the workers.
+// Deliveries still queued or being retried are recorded as failed.
+func (d *Dispatcher) Stop() error {
+	d.mu.Lock()
+	if !d.started {
+		d.mu.Unlock()
+		return nil
+	}
+	d.started = false
+	if d.subscription != nil {
+		d.eventBus.Unsubscribe(d.subscription)
+		d.subscription = nil
+	}
+	d.mu.Unlock()
+
+	d.cancel()
+	d.wg.Wait()
+
+	for {
+		select {
+		case j := <-d.queue:
+			d.fail(j, 0, "not sent before shutdown")
+		default:
+			d.logger.Info("webhook dispatcher stopped")
+			return nil
+		}
+	}
+}
+
+// eventTypes returns the event types of all endpoints
+func (d *Dispatcher) eventTypes() []string {
+	var types []string
+	for _, endpoint := range d.config.Endpoints {
+		for _, eventType := range endpoint.Events {
+			if !slices.Contains(types, eventType) {
+				types = append(types, eventType)
+			}
+		}
+	}
+	return types
+}
+
+// selects reports whether the endpoint receives events of a type
+func (e Endpoint) selects(eventType string) bool {
+	return slices.ContainsFunc(e.Events, func(pattern string) bool {
+		return eventbus.MatchEventType(pattern, eventType)
+	})
+}
+
+// handleEvent creates a delivery of the event for every endpoint selecting its type
+func (d *Dispatcher) handleEvent(ctx context.Context, event eventbus.Event) error {
+	if d.ctx.Err() != nil {
+		return nil
+	}
+
+	payload, err := eventbus.MarshalPayload(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event for webhooks: %w", err)
+	}
+
+	for _, endpoint := range d.config.Endpoints {
+		if !endpoint.selects(event.Type()) {
+			continue
+		}
+
+		delivery := entity.NewWebhookDelivery(endpoint.Name, endpoint.URL, event.Type(), "")
+		body, err := json.Marshal(Body{
+			ID:         delivery.ID,
+			Type:       event.Type(),
+			OccurredAt: event.Timestamp(),
+			Payload:    payload,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook body: %w", err)
+		}
+		delivery.Payload = string(body)
+
+		d.record(ctx, delivery, true)
+
+		j := job{endpoint: endpoint, delivery: delivery}
+		if d.jobQueue != nil {
+			if err := d.jobQueue.Enqueue(ctx, deliveryJobKind, delivery, ports.JobOptions{MaxAttempts: d.config.MaxAttempts}); err != nil {
+				d.metrics.DeliveriesDropped.Inc()
+				d.fail(j, 0, "delivery not queued: "+err.Error())
+			}
+			continue
+		}
+		select {
+		case d.queue <- j:
+		default:
+			d.metrics.DeliveriesDropped.Inc()
+			d.fail(j, 0, "delivery queue is full")
+		}
+	}
+	return nil
+}
+
+// worker sends queued deliveries until the dispatcher stops
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case j := <-d.queue:
+			d.deliver(j)
+		}
+	}
+}
+
+// handleJob makes an attempt at a delivery of the job queue. A failed attempt is retried by the
+// queue unless it is not worth retrying or the last one, which fails the delivery.
+func (d *Dispatcher) handleJob(ctx context.Context, queued *entity.Job) error {
+	var delivery entity.WebhookDelivery
+	if err := json.Unmarshal([]byte(queued.Payload), &delivery); err != nil {
+		return fmt.Errorf("%w: invalid webhook delivery: %v", ports.ErrJobPermanent, err)
+	}
+
+	i := slices.IndexFunc(d.config.Endpoints, func(e Endpoint) bool { return e.Name == delivery.Endpoint })
+	if i < 0 {
+		d.fail(job{endpoint: Endpoint{Name: delivery.Endpoint}, delivery: &delivery}, 0, "endpoint is no longer configured")
+		return fmt.Errorf("%w: endpoint %q is no longer configured", ports.ErrJobPermanent, delivery.Endpoint)
+	}
+	j := job{endpoint: d.config.Endpoints[i], delivery: &delivery}
+
+	// Attempts of jobs run again after a restart are counted as well
+	delivery.Attempts = queued.Attempts - 1
+	status, retryable, err := d.attempt(ctx, j)
+	switch {
+	case err == nil:
+		d.delivered(j, status)
+		return nil
+	case ctx.Err() != nil:
+		return err
+	case !retryable || !queued.CanRetry():
+		d.fail(j, status, err.Error())
+		return fmt.Errorf("%w: %v", ports.ErrJobPermanent, err)
+	}
+
+	j.delivery.RecordAttempt(status, err.Error())
+	d.record(ctx, j.delivery, false)
+	d.metrics.Retries.Inc()
+	return err
+}
+
+// deliver sends a delivery, retrying failed attempts with exponential backoff
+func (d *Dispatcher) deliver(j job) {
+	delay := d.config.RetryBackoff
+	for {
+		status, retryable, err := d.attempt(d.ctx, j)
+		switch {
+		case err == nil:
+			d.delivered(j, status)
+			return
+		case !retryable || j.delivery.Attempts+1 >= d.config.MaxAttempts:
+			d.fail(j, status, err.Error())
+			return
+		}
+
+		j.delivery.RecordAttempt(status, err.Error())
+		d.record(context.Background(), j.delivery, false)
+		d.metrics.Retries.Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-d.ctx.Done():
+			timer.Stop()
+			d.fail(j, status, "interrupted by shutdown: "+err.Error())
+			return
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// attempt posts a delivery once.
+// It returns the response status (0 without a response) and whether a failure is worth retrying.
+func (d *Dispatcher) attempt(ctx context.Context, j job) (int, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	body := []byte(j.delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "nexflow-webhooks")
+	req.Header.Set(HeaderEvent, j.delivery.EventType)
+	req.Header.Set(HeaderDelivery, j.delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if j.endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(j.endpoint.Secret, timestamp, body))
+	}
+
+	var resp *http.Response
+	err = metrics.RecordDurationWithError(d.metrics.AttemptDuration, func() error {
+		var err error
+		resp, err = d.client.Do(req)
+		return err
+	})
+	if err != nil {
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return resp.StatusCode, false, nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	if len(bytes.TrimSpace(snippet)) > 0 {
+		err = fmt.Errorf("%w: %s", err, bytes.TrimSpace(snippet))
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return resp.StatusCode, retryable, err
+}
+
+// delivered records a delivery as delivered
+func (d *Dispatcher) delivered(j job, status int) {
+	j.delivery.SetDelivered(status)
+	d.record(context.Background(), j.delivery, false)
+	d.metrics.DeliveriesSucceeded.Inc()
+	d.logger.Debug("webhook delivered",
+		"endpoint", j.endpoint.Name,
+		"event_type", j.delivery.EventType,
+		"delivery_id", j.delivery.ID,
+		"attempts", j.delivery.Attempts,
+	)
+}
+
+// fail records a delivery as failed
+func (d *Dispatcher) fail(j job, status int, message string) {
+	j.delivery.SetFailed(status, message)
+	d.record(context.Background(), j.delivery, false)
+	d.metrics.DeliveriesFailed.Inc()
+	d.logger.Warn("webhook delivery failed",
+		"endpoint", j.endpoint.Name,
+		"event_type", j.delivery.EventType,
+		"delivery_id", j.delivery.ID,
+		"attempts", j.delivery.Attempts,
+		"error", message,
+	)
+}
+
+// record saves a delivery to the delivery log, if one is configured.
+// A failed write is logged; the delivery is still sent.
+func (d *Dispatcher) record(ctx context.Context, delivery *entity.WebhookDelivery, create bool) {
+	if d.deliveryRepo == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	var err error
+	if create {
+		err = d.deliveryRepo.Create(ctx, delivery)
+	} else {
+		err = d.deliveryRepo.Update(ctx, delivery)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		d.logger.Error("failed to record webhook delivery",
+			"delivery_id", delivery.ID,
+			"error", err,
+		)
+	}
+}
+
+// Sign returns the HeaderSignature value of a body sent at a Unix timestamp.
+// Receivers recompute it with the shared secret and compare it with hmac.Equal.
+//
+// Parameters:
+//   - secret: Secret of the endpoint
+//   - timestamp: Value of HeaderTimestamp
+//   - body: Request body
+//
+// Returns:
+//   - string: "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
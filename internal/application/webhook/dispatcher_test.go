@@ -0,0 +1,364 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// mockDeliveryRepository is an in-memory repository.WebhookDeliveryRepository
+type mockDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries map[string]entity.WebhookDelivery
+	updates    int
+}
+
+func newMockDeliveryRepository() *mockDeliveryRepository {
+	return &mockDeliveryRepository{deliveries: map[string]entity.WebhookDelivery{}}
+}
+
+func (m *mockDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (m *mockDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.deliveries[delivery.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	m.deliveries[delivery.ID] = *delivery
+	m.updates++
+	return nil
+}
+
+func (m *mockDeliveryRepository) List(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	return nil, errors.New("not implemented")
+}
+
+// finished returns the finished deliveries
+func (m *mockDeliveryRepository) finished() []entity.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []entity.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.IsFinished() {
+			result = append(result, delivery)
+		}
+	}
+	return result
+}
+
+func newTestEventBus(t *testing.T) *eventbus.EventBus {
+	t.Helper()
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{
+		BatchSize:     10,
+		FlushInterval: 10 * time.Millisecond,
+		Logger:        logging.NewNoopLogger(),
+	})
+	require.NoError(t, bus.Start())
+	t.Cleanup(func() { _ = bus.Stop() })
+	return bus
+}
+
+func newTestDispatcher(t *testing.T, bus *eventbus.EventBus, repo repository.WebhookDeliveryRepository, endpoints ...Endpoint) *Dispatcher {
+	t.Helper()
+	config := DefaultConfig()
+	config.Endpoints = endpoints
+	config.RetryBackoff = 10 * time.Millisecond
+	config.MaxAttempts = 3
+	config.Timeout = time.Second
+
+	d := NewDispatcher(bus, repo, logging.NewNoopLogger(), config)
+	require.NoError(t, d.Start())
+	t.Cleanup(func() { _ = d.Stop() })
+	return d
+}
+
+func waitForFinished(t *testing.T, repo *mockDeliveryRepository, count int) []entity.WebhookDelivery {
+	t.Helper()
+	require.Eventually(t, func() bool { return len(repo.finished()) == count }, 5*time.Second, 10*time.Millisecond)
+	return repo.finished()
+}
+
+func TestDispatcher_DeliversSignedEvents(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	bus := newTestEventBus(t)
+	repo := newMockDeliveryRepository()
+	d := newTestDispatcher(t, bus, repo, Endpoint{
+		Name:   "ci",
+		URL:    server.URL,
+		Secret: "webhook-secret",
+		Events: []string{eventbus.EventTaskCompleted},
+	})
+
+	bus.Publish(eventbus.NewTaskEvent(eventbus.EventTaskStarted, "task-0", "session-1", "echo", "running", "{}", "", ""))
+	bus.Publish(eventbus.NewTaskEvent(eventbus.EventTaskCompleted, "task-1", "session-1", "echo", "completed", "{}", "done", ""))
+
+	var req request
+	select {
+	case req = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	assert.Equal(t, eventbus.EventTaskCompleted, req.header.Get(HeaderEvent))
+
+	timestamp, err := strconv.ParseInt(req.header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, hmac.Equal([]byte(Sign("webhook-secret", timestamp, req.body)), []byte(req.header.Get(HeaderSignature))))
+
+	var body Body
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	assert.Equal(t, req.header.Get(HeaderDelivery), body.ID)
+	assert.Equal(t, eventbus.EventTaskCompleted, body.Type)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(body.Payload, &payload))
+	assert.Equal(t, "task-1", payload["task_id"])
+	assert.Equal(t, "done", payload["output"])
+
+	deliveries := waitForFinished(t, repo, 1)
+	assert.Equal(t, body.ID, deliveries[0].ID)
+	assert.Equal(t, entity.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, int64(1), d.Metrics().DeliveriesSucceeded.Get())
+
+	// Events of other types are not posted
+	select {
+	case extra := <-requests:
+		t.Fatalf("unexpected webhook for %s", extra.header.Get(HeaderEvent))
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDispatcher_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bus := newTestEventBus(t)
+	repo := newMockDeliveryRepository()
+	d := newTestDispatcher(t, bus, repo, Endpoint{Name: "ci", URL: server.URL, Events: []string{eventbus.EventConnectorError}})
+
+	bus.Publish(eventbus.NewConnectorEvent(eventbus.EventConnectorError, "telegram", "", "", "", errors.New("connection reset")))
+
+	deliveries := waitForFinished(t, repo, 1)
+	assert.Equal(t, entity.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, int64(2), d.Metrics().Retries.Get())
+}
+
+func TestDispatcher_FailsAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "overloaded", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	bus := newTestEventBus(t)
+	repo := newMockDeliveryRepository()
+	d := newTestDispatcher(t, bus, repo, Endpoint{Name: "ci", URL: server.URL, Events: []string{eventbus.EventRouterMessage}})
+
+	bus.Publish(eventbus.NewRouterEvent(eventbus.EventRouterMessage, "message-1", "session-1", "user-1", "hi", "web", nil))
+
+	deliveries := waitForFinished(t, repo, 1)
+	assert.Equal(t, entity.WebhookDeliveryFailed, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusBadGateway, deliveries[0].ResponseStatus)
+	assert.Equal(t, "unexpected status 502: overloaded", deliveries[0].Error)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, int64(1), d.Metrics().DeliveriesFailed.Get())
+}
+
+// mockJobQueue passes the queued jobs to the test
+type mockJobQueue struct {
+	handler ports.JobHandler
+	jobs    chan *entity.Job
+}
+
+func (q *mockJobQueue) Register(kind string, handler ports.JobHandler) {
+	q.handler = handler
+}
+
+func (q *mockJobQueue) Enqueue(ctx context.Context, kind string, payload any, opts ports.JobOptions) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	q.jobs <- entity.NewJob(kind, opts.Key, string(data), opts.MaxAttempts)
+	return nil
+}
+
+func TestDispatcher_JobQueue(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bus := newTestEventBus(t)
+	repo := newMockDeliveryRepository()
+	queue := &mockJobQueue{jobs: make(chan *entity.Job, 1)}
+	config := DefaultConfig()
+	config.Endpoints = []Endpoint{{Name: "ci", URL: server.URL, Events: []string{eventbus.EventRouterMessage}}}
+	d := NewDispatcher(bus, repo, logging.NewNoopLogger(), config)
+	d.SetJobQueue(queue)
+	require.NoError(t, d.Start())
+	t.Cleanup(func() { _ = d.Stop() })
+
+	bus.Publish(eventbus.NewRouterEvent(eventbus.EventRouterMessage, "message-1", "session-1", "user-1", "hi", "web", nil))
+
+	var job *entity.Job
+	select {
+	case job = <-queue.jobs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was not queued")
+	}
+	assert.Equal(t, deliveryJobKind, job.Kind)
+	assert.Equal(t, config.MaxAttempts, job.MaxAttempts)
+
+	// The failed attempt is left to the queue to retry
+	job.Attempts = 1
+	require.Error(t, queue.handler(context.Background(), job))
+	assert.Empty(t, repo.finished())
+
+	job.Attempts = 2
+	require.NoError(t, queue.handler(context.Background(), job))
+	deliveries := repo.finished()
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, entity.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, int64(1), d.Metrics().Retries.Get())
+}
+
+func TestDispatcher_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	bus := newTestEventBus(t)
+	repo := newMockDeliveryRepository()
+	newTestDispatcher(t, bus, repo, Endpoint{Name: "ci", URL: server.URL, Events: []string{eventbus.EventRouterMessage}})
+
+	bus.Publish(eventbus.NewRouterEvent(eventbus.EventRouterMessage, "message-1", "session-1", "user-1", "hi", "web", nil))
+
+	deliveries := waitForFinished(t, repo, 1)
+	assert.Equal(t, entity.WebhookDeliveryFailed, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDispatcher_FansOutToEndpoints(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	bus := newTestEventBus(t)
+	repo := newMockDeliveryRepository()
+	newTestDispatcher(t, bus, repo,
+		Endpoint{Name: "ci", URL: server.URL + "/ci", Events: []string{eventbus.EventTaskCompleted}},
+		Endpoint{Name: "audit", URL: server.URL + "/audit", Events: []string{eventbus.EventTaskCompleted, eventbus.EventConnectorError}},
+	)
+
+	bus.Publish(eventbus.NewTaskEvent(eventbus.EventTaskCompleted, "task-1", "session-1", "echo", "completed", "{}", "done", ""))
+
+	deliveries := waitForFinished(t, repo, 2)
+	endpoints := []string{deliveries[0].Endpoint, deliveries[1].Endpoint}
+	assert.ElementsMatch(t, []string{"ci", "audit"}, endpoints)
+	assert.NotEqual(t, deliveries[0].ID, deliveries[1].ID)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestDispatcher_StartWithoutEventBus(t *testing.T) {
+	d := NewDispatcher(nil, nil, logging.NewNoopLogger(), nil)
+	assert.Error(t, d.Start())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	config := DefaultConfig()
+	require.NoError(t, config.Validate())
+
+	config.Endpoints = []Endpoint{{Name: "ci", URL: "https://example.com/hook", Events: []string{"task.completed"}}}
+	require.NoError(t, config.Validate())
+
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+	}{
+		{"missing name", Endpoint{URL: "https://example.com/hook", Events: []string{"task.completed"}}},
+		{"duplicate name", Endpoint{Name: "ci", URL: "https://example.com/other", Events: []string{"task.completed"}}},
+		{"unsupported scheme", Endpoint{Name: "ftp", URL: "ftp://example.com/hook", Events: []string{"task.completed"}}},
+		{"no events", Endpoint{Name: "empty", URL: "https://example.com/hook"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := DefaultConfig()
+			invalid.Endpoints = append(append([]Endpoint(nil), config.Endpoints...), tt.endpoint)
+			assert.Error(t, invalid.Validate())
+		})
+	}
+
+	invalid := DefaultConfig()
+	invalid.MaxAttempts = 0
+	assert.Error(t, invalid.Validate())
+}
+
+func TestEndpoint_SelectsPatterns(t *testing.T) {
+	endpoint := Endpoint{Name: "ops", URL: "https://ops.example.com/hook", Events: []string{"connector.*", eventbus.EventTaskFailed}}
+
+	assert.True(t, endpoint.selects(eventbus.EventConnectorError))
+	assert.True(t, endpoint.selects(eventbus.EventTaskFailed))
+	assert.False(t, endpoint.selects(eventbus.EventTaskCompleted))
+}
@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// AnalyticsDayLayout is the layout of the UTC days analytics counters are kept for
+const AnalyticsDayLayout = "2006-01-02"
+
+// AnalyticsMetric names a quantity counted by the analytics
+type AnalyticsMetric string
+
+const (
+	// AnalyticsMessages counts the messages received, by connector
+	AnalyticsMessages AnalyticsMetric = "messages"
+
+	// AnalyticsActiveUsers counts the users who sent a message, by connector
+	AnalyticsActiveUsers AnalyticsMetric = "active_users"
+
+	// AnalyticsLLMRequests counts the completed LLM calls, by model
+	AnalyticsLLMRequests AnalyticsMetric = "llm_requests"
+
+	// AnalyticsLLMErrors counts the failed LLM calls, by model
+	AnalyticsLLMErrors AnalyticsMetric = "llm_errors"
+
+	// AnalyticsTokens counts the LLM tokens used, by model
+	AnalyticsTokens AnalyticsMetric = "tokens"
+
+	// AnalyticsCost sums the estimated LLM cost in dollars, by model
+	AnalyticsCost AnalyticsMetric = "cost"
+
+	// AnalyticsSkillExecutions counts the skill executions, successful or not, by skill
+	AnalyticsSkillExecutions AnalyticsMetric = "skill_executions"
+
+	// AnalyticsSkillFailures counts the failed skill executions, by skill
+	AnalyticsSkillFailures AnalyticsMetric = "skill_failures"
+
+	// AnalyticsFeedback counts the rated replies, by rating. It is counted from the
+	// MessageFeedback of the day, so that a changed rating moves to the new one.
+	AnalyticsFeedback AnalyticsMetric = "feedback"
+)
+
+// AnalyticsCounter is the value of a metric on a day, broken down by a dimension such as the
+// connector, model or skill. Counters read back for a range of days may leave Day or Dimension
+// empty for totals.
+type AnalyticsCounter struct {
+	Day       string          `json:"day"`       // UTC day in AnalyticsDayLayout
+	Metric    AnalyticsMetric `json:"metric"`    // Counted quantity
+	Dimension string          `json:"dimension"` // Connector, model or skill the value is counted for
+	Value     float64         `json:"value"`     // Count or sum on the day
+}
+
+// AnalyticsDay returns the UTC day of t in AnalyticsDayLayout
+func AnalyticsDay(t time.Time) string {
+	return t.UTC().Format(AnalyticsDayLayout)
+}
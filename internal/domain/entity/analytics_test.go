@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	assert.Equal(t, "2026-03-01", AnalyticsDay(time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC)))
+	// 00:30 in Berlin is still the previous day in UTC
+	assert.Equal(t, "2026-02-28", AnalyticsDay(time.Date(2026, 3, 1, 0, 30, 0, 0, berlin)))
+}
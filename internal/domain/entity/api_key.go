@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// APIKeyPrefix starts every generated API key, so keys are recognizable in configs and logs.
+const APIKeyPrefix = "nfx_"
+
+// apiKeyDisplayLength is the number of leading key characters kept to identify a key
+const apiKeyDisplayLength = len(APIKeyPrefix) + 6
+
+// APIKey represents a credential for the HTTP API.
+// Only a SHA-256 hash of the key is stored; the key itself is shown once when it is created.
+type APIKey struct {
+	ID          valueobject.APIKeyID    `json:"id"`                     // Unique identifier for the key
+	Name        string                  `json:"name"`                   // Human-readable name, e.g. the client using the key
+	KeyHash     string                  `json:"-"`                      // Hex-encoded SHA-256 hash of the key
+	Prefix      string                  `json:"prefix"`                 // Leading characters of the key, used to identify it
+	Scope       valueobject.APIKeyScope `json:"scope"`                  // What the key is allowed to do
+	WorkspaceID valueobject.WorkspaceID `json:"workspace_id,omitempty"` // Workspace the key is bound to (empty = all workspaces)
+	CreatedAt   time.Time               `json:"created_at"`             // Timestamp when the key was created
+	RevokedAt   *time.Time              `json:"revoked_at,omitempty"`   // Timestamp when the key was revoked (nil = active)
+}
+
+// NewAPIKey generates a new active API key with the specified name and scope.
+// It returns the key entity and the plaintext key, which cannot be recovered later.
+func NewAPIKey(name string, scope valueobject.APIKeyScope) (*APIKey, string) {
+	key := APIKeyPrefix + rand.Text()
+	return &APIKey{
+		ID:        valueobject.NewGeneratedAPIKeyID(),
+		Name:      name,
+		KeyHash:   HashAPIKey(key),
+		Prefix:    key[:apiKeyDisplayLength],
+		Scope:     scope,
+		CreatedAt: utils.Now(),
+	}, key
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash a key is stored and looked up by.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Revoke marks the key as revoked. Revoked keys are rejected by authentication.
+func (k *APIKey) Revoke() {
+	now := utils.Now()
+	k.RevokedAt = &now
+}
+
+// IsRevoked returns true if the key has been revoked.
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	// Act
+	apiKey, key := NewAPIKey("ci", valueobject.APIKeyScopeRead)
+	_, other := NewAPIKey("ci", valueobject.APIKeyScopeRead)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(key, APIKeyPrefix))
+	assert.NotEqual(t, key, other)
+	assert.Equal(t, "ci", apiKey.Name)
+	assert.Equal(t, valueobject.APIKeyScopeRead, apiKey.Scope)
+	assert.Equal(t, HashAPIKey(key), apiKey.KeyHash)
+	assert.NotContains(t, apiKey.KeyHash, key)
+	assert.True(t, strings.HasPrefix(key, apiKey.Prefix))
+	assert.Less(t, len(apiKey.Prefix), len(key))
+	assert.WithinDuration(t, time.Now(), apiKey.CreatedAt, time.Second)
+	assert.False(t, apiKey.IsRevoked())
+}
+
+func TestAPIKey_Revoke(t *testing.T) {
+	// Arrange
+	apiKey, _ := NewAPIKey("ci", valueobject.APIKeyScopeAdmin)
+
+	// Act
+	apiKey.Revoke()
+
+	// Assert
+	assert.True(t, apiKey.IsRevoked())
+	assert.WithinDuration(t, time.Now(), *apiKey.RevokedAt, time.Second)
+}
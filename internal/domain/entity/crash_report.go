@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// CrashReport is the record of a panic recovered by the application, e.g. in a router worker,
+// a connector update loop or a skill execution.
+type CrashReport struct {
+	ID         string            `json:"id"`         // Unique identifier
+	Component  string            `json:"component"`  // Subsystem the panic was recovered in, e.g. "router" or "telegram"
+	Operation  string            `json:"operation"`  // Operation that panicked, e.g. "handle_message"
+	Panic      string            `json:"panic"`      // Value passed to panic
+	Stack      string            `json:"stack"`      // Stack trace of the panicking goroutine
+	Attributes map[string]string `json:"attributes"` // Context of the operation, e.g. the connector or skill name
+	CreatedAt  time.Time         `json:"created_at"` // Timestamp when the panic was recovered
+}
+
+// NewCrashReport creates the record of a panic recovered in the specified component and operation.
+func NewCrashReport(component, operation, panicValue, stack string, attributes map[string]string) *CrashReport {
+	return &CrashReport{
+		ID:         valueobject.GenerateID(nil).String(),
+		Component:  component,
+		Operation:  operation,
+		Panic:      panicValue,
+		Stack:      stack,
+		Attributes: attributes,
+		CreatedAt:  utils.Now(),
+	}
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCrashReport(t *testing.T) {
+	// Act
+	report := NewCrashReport("router", "handle_message", "boom", "goroutine 1 [running]:", map[string]string{"connector": "telegram"})
+
+	// Assert
+	assert.NotEmpty(t, report.ID)
+	assert.Equal(t, "router", report.Component)
+	assert.Equal(t, "handle_message", report.Operation)
+	assert.Equal(t, "boom", report.Panic)
+	assert.Equal(t, "goroutine 1 [running]:", report.Stack)
+	assert.Equal(t, map[string]string{"connector": "telegram"}, report.Attributes)
+	assert.WithinDuration(t, time.Now(), report.CreatedAt, time.Second)
+}
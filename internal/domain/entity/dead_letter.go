@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// DeadLetter represents an event whose handler kept failing after all retries.
+// Dead letters are kept until they are redelivered successfully or deleted.
+type DeadLetter struct {
+	ID           string    `json:"id"`           // Unique identifier
+	Subscription string    `json:"subscription"` // Name of the subscription whose handler failed
+	EventType    string    `json:"event_type"`   // Type of the event, e.g. "task.completed"
+	Payload      string    `json:"payload"`      // JSON encoding of the event
+	OccurredAt   time.Time `json:"occurred_at"`  // Timestamp when the event was published
+	Attempts     int       `json:"attempts"`     // Number of handler attempts made so far
+	Error        string    `json:"error"`        // Error returned by the last attempt
+	CreatedAt    time.Time `json:"created_at"`   // Timestamp when the event was dead-lettered
+	UpdatedAt    time.Time `json:"updated_at"`   // Timestamp of the last redelivery attempt
+}
+
+// NewDeadLetter creates a dead letter for an event after the given number of failed attempts.
+func NewDeadLetter(subscription, eventType, payload string, occurredAt time.Time, attempts int, err string) *DeadLetter {
+	now := utils.Now()
+	return &DeadLetter{
+		ID:           valueobject.GenerateID(nil).String(),
+		Subscription: subscription,
+		EventType:    eventType,
+		Payload:      payload,
+		OccurredAt:   occurredAt,
+		Attempts:     attempts,
+		Error:        err,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// RecordRedelivery records a failed redelivery attempt.
+func (d *DeadLetter) RecordRedelivery(err string) {
+	d.Attempts++
+	d.Error = err
+	d.UpdatedAt = utils.Now()
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeadLetter(t *testing.T) {
+	// Arrange
+	occurredAt := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+
+	// Act
+	letter := NewDeadLetter("webhooks", "task.completed", `{"task_id":"task-1"}`, occurredAt, 3, "boom")
+
+	// Assert
+	require.NotEmpty(t, letter.ID)
+	assert.Equal(t, "webhooks", letter.Subscription)
+	assert.Equal(t, "task.completed", letter.EventType)
+	assert.Equal(t, `{"task_id":"task-1"}`, letter.Payload)
+	assert.Equal(t, occurredAt, letter.OccurredAt)
+	assert.Equal(t, 3, letter.Attempts)
+	assert.Equal(t, "boom", letter.Error)
+	assert.WithinDuration(t, time.Now(), letter.CreatedAt, time.Second)
+}
+
+func TestDeadLetter_RecordRedelivery(t *testing.T) {
+	// Arrange
+	letter := NewDeadLetter("webhooks", "task.completed", "{}", time.Now(), 3, "boom")
+
+	// Act
+	letter.RecordRedelivery("still failing")
+
+	// Assert
+	assert.Equal(t, 4, letter.Attempts)
+	assert.Equal(t, "still failing", letter.Error)
+	assert.False(t, letter.UpdatedAt.Before(letter.CreatedAt))
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// DocumentChunk is a piece of the text of a document shared in a session, such as a PDF sent
+// to the Telegram bot. Chunks with embeddings close to a question are passed to the LLM to
+// answer questions about the document.
+type DocumentChunk struct {
+	ID           string                `json:"id"`            // Unique identifier
+	SessionID    valueobject.SessionID `json:"session_id"`    // ID of the session the document was shared in
+	DocumentID   string                `json:"document_id"`   // ID shared by the chunks of a document
+	DocumentName string                `json:"document_name"` // File name of the document
+	Position     int                   `json:"position"`      // Position of the chunk in the document, from 0
+	Content      string                `json:"content"`       // Text of the chunk
+	Embedding    []float32             `json:"-"`             // Embedding of the text used for retrieval
+	CreatedAt    time.Time             `json:"created_at"`    // Timestamp when the document was shared
+}
+
+// NewDocumentChunk creates the chunk at position of a document shared in a session
+func NewDocumentChunk(sessionID, documentID, documentName string, position int, content string, embedding []float32) *DocumentChunk {
+	return &DocumentChunk{
+		ID:           valueobject.GenerateID(nil).String(),
+		SessionID:    valueobject.SessionID(sessionID),
+		DocumentID:   documentID,
+		DocumentName: documentName,
+		Position:     position,
+		Content:      content,
+		Embedding:    embedding,
+		CreatedAt:    utils.Now(),
+	}
+}
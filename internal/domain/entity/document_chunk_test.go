@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDocumentChunk(t *testing.T) {
+	// Act
+	chunk := NewDocumentChunk("session-1", "doc-1", "report.pdf", 2, "Revenue grew by 12%.", []float32{0.6, 0.8})
+
+	// Assert
+	assert.NotEmpty(t, chunk.ID)
+	assert.Equal(t, "session-1", chunk.SessionID.String())
+	assert.Equal(t, "doc-1", chunk.DocumentID)
+	assert.Equal(t, "report.pdf", chunk.DocumentName)
+	assert.Equal(t, 2, chunk.Position)
+	assert.Equal(t, "Revenue grew by 12%.", chunk.Content)
+	assert.Equal(t, []float32{0.6, 0.8}, chunk.Embedding)
+	assert.WithinDuration(t, time.Now(), chunk.CreatedAt, time.Second)
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord is the response of a write API request sent with an Idempotency-Key header.
+// A retry of the request with the same key gets the recorded response instead of repeating the
+// request; a request with the same key but another method, path or body is rejected. While the
+// request is handled, the record of its key is pending and has no response.
+type IdempotencyRecord struct {
+	Key         string    `json:"key"`          // SHA-256 of the client and the Idempotency-Key header
+	RequestHash string    `json:"request_hash"` // SHA-256 of the method, path, query and body of the request
+	StatusCode  int       `json:"status_code"`  // HTTP status code of the response; 0 while pending
+	ContentType string    `json:"content_type"` // Content type of the response
+	Body        []byte    `json:"body"`         // Body of the response
+	ExpiresAt   time.Time `json:"expires_at"`   // Time after which the key can be reused
+	CreatedAt   time.Time `json:"created_at"`   // Timestamp when the response was recorded
+}
+
+// IsExpired returns true if the record expired at or before now
+func (r *IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !r.ExpiresAt.After(now)
+}
+
+// IsPending returns true if the request of the record is still being handled
+func (r *IdempotencyRecord) IsPending() bool {
+	return r.StatusCode == 0
+}
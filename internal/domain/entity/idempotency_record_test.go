@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRecord_IsExpired(t *testing.T) {
+	now := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	record := &IdempotencyRecord{ExpiresAt: now}
+
+	assert.True(t, record.IsExpired(now))
+	assert.False(t, record.IsExpired(now.Add(-time.Second)))
+}
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// JobStatus is the status of a background job
+type JobStatus string
+
+const (
+	// JobPending is a job waiting for a worker, or for its next attempt
+	JobPending JobStatus = "pending"
+
+	// JobRunning is a job claimed by a worker until its lease expires
+	JobRunning JobStatus = "running"
+
+	// JobCompleted is a job whose handler succeeded
+	JobCompleted JobStatus = "completed"
+
+	// JobFailed is a job whose handler failed on its last attempt
+	JobFailed JobStatus = "failed"
+)
+
+// IsFinished returns true if the job is completed or failed
+func (s JobStatus) IsFinished() bool {
+	return s == JobCompleted || s == JobFailed
+}
+
+// Job is background work stored in the job queue, so that it survives restarts. A worker claims
+// a due job for a lease; a job whose lease expires, e.g. because its process stopped, is
+// claimed again. Failed attempts are retried at a later RunAt until MaxAttempts is reached.
+type Job struct {
+	ID          string     `json:"id"`                     // Unique identifier, or the key of the work it does
+	Kind        string     `json:"kind"`                   // Kind of work, selecting the handler
+	Payload     string     `json:"payload"`                // Input of the handler in JSON format
+	Status      JobStatus  `json:"status"`                 // Pending, running, completed or failed
+	Attempts    int        `json:"attempts"`               // Number of times the job was claimed
+	MaxAttempts int        `json:"max_attempts"`           // Number of attempts before the job fails
+	Error       string     `json:"error"`                  // Error of the last failed attempt
+	RunAt       time.Time  `json:"run_at"`                 // Timestamp from which the job may be claimed
+	LockedBy    string     `json:"locked_by"`              // Worker holding the lease of a running job
+	LockedUntil *time.Time `json:"locked_until,omitempty"` // Timestamp when the lease of a running job expires
+	CreatedAt   time.Time  `json:"created_at"`             // Timestamp when the job was created
+	UpdatedAt   time.Time  `json:"updated_at"`             // Timestamp when the job was last updated
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`  // Timestamp when the job was completed or failed
+	LeaderOnly  bool       `json:"leader_only"`            // Whether only the leader of a cluster may claim the job
+}
+
+// NewJob creates a pending job of a kind that is due now. A non-empty key is used as the ID of
+// the job, so that the same work is only queued once.
+func NewJob(kind, key, payload string, maxAttempts int) *Job {
+	id := key
+	if id == "" {
+		id = valueobject.GenerateID(nil).String()
+	}
+	now := utils.Now()
+	return &Job{
+		ID:          id,
+		Kind:        kind,
+		Payload:     payload,
+		Status:      JobPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// CanRetry returns true if the job has attempts left
+func (j *Job) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
+
+// Complete marks the job as completed and releases its lease
+func (j *Job) Complete() {
+	j.finish(JobCompleted)
+	j.Error = ""
+}
+
+// Fail marks the job as failed for good and releases its lease
+func (j *Job) Fail(reason string) {
+	j.finish(JobFailed)
+	j.Error = reason
+}
+
+// Retry releases the lease of a failed attempt and makes the job due again at runAt
+func (j *Job) Retry(reason string, runAt time.Time) {
+	j.Status = JobPending
+	j.Error = reason
+	j.RunAt = runAt
+	j.LockedBy = ""
+	j.LockedUntil = nil
+	j.UpdatedAt = utils.Now()
+}
+
+// finish sets a final status and releases the lease
+func (j *Job) finish(status JobStatus) {
+	now := utils.Now()
+	j.Status = status
+	j.LockedBy = ""
+	j.LockedUntil = nil
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
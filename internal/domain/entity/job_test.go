@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJob(t *testing.T) {
+	job := NewJob("webhook_delivery", "", `{"id": "1"}`, 3)
+
+	require.NotEmpty(t, job.ID)
+	assert.Equal(t, "webhook_delivery", job.Kind)
+	assert.Equal(t, JobPending, job.Status)
+	assert.Equal(t, 3, job.MaxAttempts)
+	assert.WithinDuration(t, time.Now(), job.RunAt, time.Second)
+	assert.True(t, job.CanRetry())
+
+	// A key is the ID of the job
+	assert.Equal(t, "schedule:1", NewJob("schedule_run", "schedule:1", "{}", 1).ID)
+}
+
+func TestJob_Lifecycle(t *testing.T) {
+	job := NewJob("schedule_run", "", "{}", 2)
+	lockedUntil := time.Now().Add(time.Minute)
+	job.Status, job.Attempts, job.LockedBy, job.LockedUntil = JobRunning, 1, "worker-1", &lockedUntil
+
+	runAt := time.Now().Add(time.Second)
+	job.Retry("connection reset", runAt)
+	assert.Equal(t, JobPending, job.Status)
+	assert.Equal(t, "connection reset", job.Error)
+	assert.Equal(t, runAt, job.RunAt)
+	assert.Empty(t, job.LockedBy)
+	assert.Nil(t, job.LockedUntil)
+	assert.True(t, job.CanRetry())
+
+	job.Status, job.Attempts = JobRunning, 2
+	assert.False(t, job.CanRetry())
+	job.Fail("connection reset")
+	assert.Equal(t, JobFailed, job.Status)
+	assert.True(t, job.Status.IsFinished())
+	require.NotNil(t, job.FinishedAt)
+
+	completed := NewJob("schedule_run", "", "{}", 1)
+	completed.Complete()
+	assert.Equal(t, JobCompleted, completed.Status)
+	assert.Empty(t, completed.Error)
+	assert.NotNil(t, completed.FinishedAt)
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// UsageMonthLayout is the layout of the UTC months LLM usage is aggregated by
+const UsageMonthLayout = "2006-01"
+
+// LLMUsage is the tokens and estimated cost of one completed LLM call, attributed to the user
+// and workspace of the session it answered. Usage is kept for billing: it is not deleted with
+// the sessions or the data of the user.
+type LLMUsage struct {
+	ID           string                  `json:"id"`            // ID of the usage event
+	UserID       valueobject.UserID      `json:"user_id"`       // Owner of the session
+	WorkspaceID  valueobject.WorkspaceID `json:"workspace_id"`  // Workspace of the session
+	SessionID    valueobject.SessionID   `json:"session_id"`    // Session the call answered
+	Provider     string                  `json:"provider"`      // Name of the LLM provider
+	Model        string                  `json:"model"`         // Model requested, empty for the provider default
+	InputTokens  int                     `json:"input_tokens"`  // Tokens of the prompt
+	OutputTokens int                     `json:"output_tokens"` // Tokens of the completion
+	TotalTokens  int                     `json:"total_tokens"`  // Tokens billed for the call
+	Cost         float64                 `json:"cost"`          // Estimated cost in dollars
+	Month        string                  `json:"month"`         // UTC month of the call in UsageMonthLayout
+	CreatedAt    time.Time               `json:"created_at"`    // Time of the call
+}
+
+// LLMUsageTotal sums the LLM usage of a month for a workspace, user, provider and model
+type LLMUsageTotal struct {
+	Month        string                  `json:"month"`
+	WorkspaceID  valueobject.WorkspaceID `json:"workspace_id"`
+	UserID       valueobject.UserID      `json:"user_id"`
+	Provider     string                  `json:"provider"`
+	Model        string                  `json:"model"`
+	Requests     int64                   `json:"requests"` // Number of LLM calls
+	InputTokens  int64                   `json:"input_tokens"`
+	OutputTokens int64                   `json:"output_tokens"`
+	TotalTokens  int64                   `json:"total_tokens"`
+	Cost         float64                 `json:"cost"`
+}
+
+// UsageMonth returns the UTC month of t in UsageMonthLayout
+func UsageMonth(t time.Time) string {
+	return t.UTC().Format(UsageMonthLayout)
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageMonth(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	assert.Equal(t, "2026-03", UsageMonth(time.Date(2026, 3, 31, 23, 30, 0, 0, time.UTC)))
+	// 00:30 on the 1st in Berlin is still the previous month in UTC
+	assert.Equal(t, "2026-02", UsageMonth(time.Date(2026, 3, 1, 0, 30, 0, 0, berlin)))
+}
@@ -22,7 +22,7 @@ type Log struct {
 // The log entry is assigned a unique ID and the current timestamp.
 func NewLog(level valueobject.LogLevel, source, message string, metadata map[string]interface{}) *Log {
 	return &Log{
-		ID:        valueobject.LogID(utils.GenerateID()),
+		ID:        valueobject.NewGeneratedLogID(),
 		Level:     level,
 		Source:    source,
 		Message:   message,
@@ -20,7 +20,7 @@ type Message struct {
 // NewUserMessage creates a new user message in the specified session.
 func NewUserMessage(sessionID, content string) *Message {
 	return &Message{
-		ID:        valueobject.MessageID(utils.GenerateID()),
+		ID:        valueobject.NewGeneratedMessageID(),
 		SessionID: valueobject.MustNewSessionID(sessionID),
 		Role:      valueobject.RoleUser,
 		Content:   content,
@@ -31,7 +31,7 @@ func NewUserMessage(sessionID, content string) *Message {
 // NewAssistantMessage creates a new assistant (AI) message in the specified session.
 func NewAssistantMessage(sessionID, content string) *Message {
 	return &Message{
-		ID:        valueobject.MessageID(utils.GenerateID()),
+		ID:        valueobject.NewGeneratedMessageID(),
 		SessionID: valueobject.MustNewSessionID(sessionID),
 		Role:      valueobject.RoleAssistant,
 		Content:   content,
@@ -42,7 +42,7 @@ func NewAssistantMessage(sessionID, content string) *Message {
 // NewSystemMessage creates a new system message in the specified session.
 func NewSystemMessage(sessionID, content string) *Message {
 	return &Message{
-		ID:        valueobject.MessageID(utils.GenerateID()),
+		ID:        valueobject.NewGeneratedMessageID(),
 		SessionID: valueobject.MustNewSessionID(sessionID),
 		Role:      valueobject.RoleSystem,
 		Content:   content,
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// MessageDeadLetter represents a connector message the router failed to process.
+// Message dead letters are kept until they are reprocessed successfully or deleted.
+type MessageDeadLetter struct {
+	ID        string    `json:"id"`         // Unique identifier
+	Connector string    `json:"connector"`  // Name of the connector that received the message
+	UserID    string    `json:"user_id"`    // User ID on the channel
+	ChannelID string    `json:"channel_id"` // Channel ID, e.g. the Telegram chat ID
+	Content   string    `json:"content"`    // Message text
+	Metadata  string    `json:"metadata"`   // Connector metadata in JSON format
+	Attempts  int       `json:"attempts"`   // Number of processing attempts made so far
+	Error     string    `json:"error"`      // Error returned by the last attempt
+	CreatedAt time.Time `json:"created_at"` // Timestamp when the message was dead-lettered
+	UpdatedAt time.Time `json:"updated_at"` // Timestamp of the last reprocessing attempt
+}
+
+// NewMessageDeadLetter creates a dead letter for a message whose first processing attempt failed.
+func NewMessageDeadLetter(connector, userID, channelID, content string, metadata map[string]interface{}, err string) *MessageDeadLetter {
+	now := utils.Now()
+	return &MessageDeadLetter{
+		ID:        valueobject.GenerateID(nil).String(),
+		Connector: connector,
+		UserID:    userID,
+		ChannelID: channelID,
+		Content:   content,
+		Metadata:  utils.MarshalJSON(metadata),
+		Attempts:  1,
+		Error:     err,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// RecordReprocess records a failed reprocessing attempt.
+func (d *MessageDeadLetter) RecordReprocess(err string) {
+	d.Attempts++
+	d.Error = err
+	d.UpdatedAt = utils.Now()
+}
+
+// GetMetadata parses and returns the connector metadata as a map.
+// Returns nil if parsing fails or metadata is empty.
+func (d *MessageDeadLetter) GetMetadata() map[string]interface{} {
+	return utils.UnmarshalJSONToMap(d.Metadata)
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageDeadLetter(t *testing.T) {
+	// Act
+	letter := NewMessageDeadLetter("telegram", "tg:42", "100", "Hello", map[string]interface{}{"message_id": 7}, "boom")
+
+	// Assert
+	require.NotEmpty(t, letter.ID)
+	assert.Equal(t, "telegram", letter.Connector)
+	assert.Equal(t, "tg:42", letter.UserID)
+	assert.Equal(t, "100", letter.ChannelID)
+	assert.Equal(t, "Hello", letter.Content)
+	assert.Equal(t, `{"message_id":7}`, letter.Metadata)
+	assert.Equal(t, map[string]interface{}{"message_id": float64(7)}, letter.GetMetadata())
+	assert.Equal(t, 1, letter.Attempts)
+	assert.Equal(t, "boom", letter.Error)
+	assert.WithinDuration(t, time.Now(), letter.CreatedAt, time.Second)
+}
+
+func TestMessageDeadLetter_RecordReprocess(t *testing.T) {
+	// Arrange
+	letter := NewMessageDeadLetter("telegram", "tg:42", "100", "Hello", nil, "boom")
+
+	// Act
+	letter.RecordReprocess("still failing")
+
+	// Assert
+	assert.Equal(t, 2, letter.Attempts)
+	assert.Equal(t, "still failing", letter.Error)
+	assert.False(t, letter.UpdatedAt.Before(letter.CreatedAt))
+}
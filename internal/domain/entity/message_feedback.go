@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// FeedbackRating is the rating a user gives to a reply
+type FeedbackRating string
+
+const (
+	// FeedbackUp rates a reply as helpful (thumbs up)
+	FeedbackUp FeedbackRating = "up"
+
+	// FeedbackDown rates a reply as unhelpful (thumbs down)
+	FeedbackDown FeedbackRating = "down"
+)
+
+// MaxFeedbackCommentLength limits the number of characters of a feedback comment
+const MaxFeedbackCommentLength = 2000
+
+// IsValid returns true if the rating is known
+func (r FeedbackRating) IsValid() bool {
+	return r == FeedbackUp || r == FeedbackDown
+}
+
+// MessageFeedback is the rating of an assistant message by the user of its session, e.g. from
+// the thumbs up and down buttons under a Telegram reply. A message has at most one feedback;
+// rating it again replaces the earlier rating. Feedback is deleted with its message.
+type MessageFeedback struct {
+	ID        string                `json:"id"`         // Unique identifier
+	MessageID valueobject.MessageID `json:"message_id"` // ID of the rated assistant message
+	SessionID valueobject.SessionID `json:"session_id"` // ID of the session of the message
+	UserID    valueobject.UserID    `json:"user_id"`    // ID of the user who rated the message
+	Rating    FeedbackRating        `json:"rating"`     // Up or down
+	Comment   string                `json:"comment"`    // Optional explanation of the rating
+	Day       string                `json:"day"`        // UTC day of the rating in AnalyticsDayLayout
+	CreatedAt time.Time             `json:"created_at"` // Timestamp when the message was first rated
+	UpdatedAt time.Time             `json:"updated_at"` // Timestamp when the message was last rated
+}
+
+// NewMessageFeedback creates the feedback of a user on an assistant message of a session
+func NewMessageFeedback(messageID, sessionID, userID string, rating FeedbackRating, comment string) *MessageFeedback {
+	now := utils.Now()
+	return &MessageFeedback{
+		ID:        valueobject.GenerateID(nil).String(),
+		MessageID: valueobject.MessageID(messageID),
+		SessionID: valueobject.SessionID(sessionID),
+		UserID:    valueobject.UserID(userID),
+		Rating:    rating,
+		Comment:   comment,
+		Day:       AnalyticsDay(now),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
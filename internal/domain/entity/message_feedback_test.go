@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMessageFeedback(t *testing.T) {
+	// Act
+	feedback := NewMessageFeedback("msg-1", "session-1", "user-1", FeedbackDown, "Too long")
+
+	// Assert
+	assert.NotEmpty(t, feedback.ID)
+	assert.Equal(t, "msg-1", feedback.MessageID.String())
+	assert.Equal(t, "session-1", feedback.SessionID.String())
+	assert.Equal(t, "user-1", feedback.UserID.String())
+	assert.Equal(t, FeedbackDown, feedback.Rating)
+	assert.Equal(t, "Too long", feedback.Comment)
+	assert.Equal(t, AnalyticsDay(feedback.CreatedAt), feedback.Day)
+	assert.Equal(t, feedback.CreatedAt, feedback.UpdatedAt)
+	assert.WithinDuration(t, time.Now(), feedback.CreatedAt, time.Second)
+}
+
+func TestFeedbackRating_IsValid(t *testing.T) {
+	assert.True(t, FeedbackUp.IsValid())
+	assert.True(t, FeedbackDown.IsValid())
+	assert.False(t, FeedbackRating("meh").IsValid())
+	assert.False(t, FeedbackRating("").IsValid())
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// MessageJobStatus is the status of a message job
+type MessageJobStatus string
+
+const (
+	// MessageJobPending is a job waiting for a worker
+	MessageJobPending MessageJobStatus = "pending"
+
+	// MessageJobRunning is a job whose message is being answered
+	MessageJobRunning MessageJobStatus = "running"
+
+	// MessageJobCompleted is a job whose message was answered
+	MessageJobCompleted MessageJobStatus = "completed"
+
+	// MessageJobFailed is a job whose message could not be answered
+	MessageJobFailed MessageJobStatus = "failed"
+)
+
+// IsFinished returns true if the job is completed or failed
+func (s MessageJobStatus) IsFinished() bool {
+	return s == MessageJobCompleted || s == MessageJobFailed
+}
+
+// MessageJob is a message sent to a session through the asynchronous message API. The message
+// is answered in the background and the client polls the job until it is completed with the
+// reply or failed. Jobs created by the batch message API share a batch ID.
+type MessageJob struct {
+	ID         string                `json:"id"`                    // Unique identifier
+	SessionID  valueobject.SessionID `json:"session_id"`            // ID of the session the message is sent to
+	Content    string                `json:"content"`               // Content of the user message
+	Status     MessageJobStatus      `json:"status"`                // Pending, running, completed or failed
+	MessageID  valueobject.MessageID `json:"message_id"`            // ID of the reply once completed
+	Reply      string                `json:"reply"`                 // Content of the reply once completed
+	Error      string                `json:"error"`                 // Why the job failed
+	CreatedAt  time.Time             `json:"created_at"`            // Timestamp when the job was created
+	UpdatedAt  time.Time             `json:"updated_at"`            // Timestamp when the job was last updated
+	FinishedAt *time.Time            `json:"finished_at,omitempty"` // Timestamp when the job was completed or failed
+	BatchID    string                `json:"batch_id,omitempty"`    // ID of the batch of the job, if any
+	BatchIndex int                   `json:"batch_index"`           // Position of the message in its batch
+}
+
+// NewMessageJob creates a pending job answering a message in a session
+func NewMessageJob(sessionID, content string) *MessageJob {
+	now := utils.Now()
+	return &MessageJob{
+		ID:        valueobject.GenerateID(nil).String(),
+		SessionID: valueobject.SessionID(sessionID),
+		Content:   content,
+		Status:    MessageJobPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NewBatchMessageJob creates a pending job answering the message at a position of a batch
+func NewBatchMessageJob(batchID string, index int, sessionID, content string) *MessageJob {
+	job := NewMessageJob(sessionID, content)
+	job.BatchID = batchID
+	job.BatchIndex = index
+	return job
+}
+
+// Start marks the job as running
+func (j *MessageJob) Start() {
+	j.Status = MessageJobRunning
+	j.UpdatedAt = utils.Now()
+}
+
+// Complete marks the job as completed with the reply to its message
+func (j *MessageJob) Complete(messageID, reply string) {
+	j.finish(MessageJobCompleted)
+	j.MessageID = valueobject.MessageID(messageID)
+	j.Reply = reply
+}
+
+// Fail marks the job as failed with the reason
+func (j *MessageJob) Fail(reason string) {
+	j.finish(MessageJobFailed)
+	j.Error = reason
+}
+
+func (j *MessageJob) finish(status MessageJobStatus) {
+	now := utils.Now()
+	j.Status = status
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
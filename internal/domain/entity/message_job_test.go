@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageJob_Lifecycle(t *testing.T) {
+	job := NewMessageJob("session-1", "Hello")
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, MessageJobPending, job.Status)
+	assert.Nil(t, job.FinishedAt)
+	assert.False(t, job.Status.IsFinished())
+
+	job.Start()
+	assert.Equal(t, MessageJobRunning, job.Status)
+	assert.False(t, job.Status.IsFinished())
+
+	job.Complete("message-1", "Hi there")
+	assert.Equal(t, MessageJobCompleted, job.Status)
+	assert.Equal(t, "message-1", job.MessageID.String())
+	assert.Equal(t, "Hi there", job.Reply)
+	assert.NotNil(t, job.FinishedAt)
+	assert.True(t, job.Status.IsFinished())
+
+	failed := NewMessageJob("session-1", "Hello")
+	failed.Fail("llm unavailable")
+	assert.Equal(t, MessageJobFailed, failed.Status)
+	assert.Equal(t, "llm unavailable", failed.Error)
+	assert.True(t, failed.Status.IsFinished())
+
+	batched := NewBatchMessageJob("batch-1", 3, "session-1", "Classify this")
+	assert.Equal(t, "batch-1", batched.BatchID)
+	assert.Equal(t, 3, batched.BatchIndex)
+	assert.Equal(t, MessageJobPending, batched.Status)
+}
@@ -7,26 +7,61 @@ import (
 	"github.com/atumaikin/nexflow/internal/shared/utils"
 )
 
-// Schedule represents a cron-based scheduled task.
-// Schedules allow automatic skill execution at specific times defined by cron expressions.
+// MaxJitterSeconds is the upper bound for a schedule's random start delay.
+const MaxJitterSeconds = 3600
+
+// Schedule represents a scheduled task.
+// Schedules allow automatic skill execution at specific times defined by cron expressions,
+// or once at a fixed time for one-shot schedules such as reminders. Prompt schedules send an
+// LLM prompt template instead of executing a skill.
 type Schedule struct {
-	ID             valueobject.ScheduleID     `json:"id"`              // Unique identifier for the schedule
-	Skill          string                     `json:"skill"`           // Name of the skill to execute
-	CronExpression valueobject.CronExpression `json:"cron_expression"` // Cron syntax (e.g., "0 * * * *")
-	Input          string                     `json:"input"`           // Input parameters in JSON format
-	Enabled        bool                       `json:"enabled"`         // Whether the schedule is active
-	CreatedAt      time.Time                  `json:"created_at"`      // Timestamp when the schedule was created
+	ID              valueobject.ScheduleID      `json:"id"`                // Unique identifier for the schedule
+	Skill           string                      `json:"skill"`             // Name of the skill to execute (empty for prompt schedules)
+	CronExpression  valueobject.CronExpression  `json:"cron_expression"`   // Cron syntax (e.g., "0 * * * *")
+	Input           string                      `json:"input"`             // Input parameters in JSON format
+	Enabled         bool                        `json:"enabled"`           // Whether the schedule is active
+	Timezone        valueobject.Timezone        `json:"timezone"`          // IANA timezone the cron expression is evaluated in
+	JitterSeconds   int                         `json:"jitter_seconds"`    // Maximum random delay added to each run (0 = none)
+	CreatedAt       time.Time                   `json:"created_at"`        // Timestamp when the schedule was created
+	Type            valueobject.ScheduleType    `json:"type"`              // Schedule type: "cron" (recurring) or "once" (one-shot)
+	RunAt           *time.Time                  `json:"run_at,omitempty"`  // Time a one-shot schedule fires (nil for cron schedules)
+	MissedRunPolicy valueobject.MissedRunPolicy `json:"missed_run_policy"` // What to do with runs missed while the server was down
+	TargetConnector string                      `json:"target_connector"`  // Connector the run output is delivered to (empty = no delivery)
+	TargetUserID    string                      `json:"target_user_id"`    // Connector-specific user or chat ID the run output is delivered to
+	Prompt          string                      `json:"prompt"`            // LLM prompt template sent instead of executing a skill
+	SessionID       string                      `json:"session_id"`        // System session the prompt is answered in (empty until the first run)
 }
 
 // NewSchedule creates a new enabled schedule for the specified skill with a cron expression and input.
+// The schedule is evaluated in UTC without jitter and skips missed runs until configured otherwise.
 func NewSchedule(skill, cronExpression, input string) *Schedule {
 	return &Schedule{
-		ID:             valueobject.ScheduleID(utils.GenerateID()),
-		Skill:          skill,
-		CronExpression: valueobject.MustNewCronExpression(cronExpression),
-		Input:          input,
-		Enabled:        true,
-		CreatedAt:      utils.Now(),
+		ID:              valueobject.NewGeneratedScheduleID(),
+		Skill:           skill,
+		CronExpression:  valueobject.MustNewCronExpression(cronExpression),
+		Input:           input,
+		Enabled:         true,
+		Timezone:        valueobject.TimezoneUTC,
+		CreatedAt:       utils.Now(),
+		Type:            valueobject.ScheduleTypeCron,
+		MissedRunPolicy: valueobject.MissedRunSkip,
+	}
+}
+
+// NewOneShotSchedule creates a new enabled schedule that runs the skill once at runAt.
+// One-shot schedules have no cron expression and are deleted after they fire.
+func NewOneShotSchedule(skill string, runAt time.Time, input string) *Schedule {
+	runAt = runAt.UTC()
+	return &Schedule{
+		ID:              valueobject.NewGeneratedScheduleID(),
+		Skill:           skill,
+		Input:           input,
+		Enabled:         true,
+		Timezone:        valueobject.TimezoneUTC,
+		CreatedAt:       utils.Now(),
+		Type:            valueobject.ScheduleTypeOnce,
+		RunAt:           &runAt,
+		MissedRunPolicy: valueobject.MissedRunSkip,
 	}
 }
 
@@ -45,6 +80,58 @@ func (s *Schedule) IsEnabled() bool {
 	return s.Enabled
 }
 
+// IsOneShot returns true if the schedule runs once at a fixed time.
+func (s *Schedule) IsOneShot() bool {
+	return s.Type.IsOnce()
+}
+
+// SetMissedRunPolicy sets what the scheduler does with runs missed while the server was down.
+func (s *Schedule) SetMissedRunPolicy(policy valueobject.MissedRunPolicy) {
+	s.MissedRunPolicy = policy
+}
+
+// SetTarget sets the connector and user or chat ID the output of each run is delivered to.
+// Empty values disable delivery.
+func (s *Schedule) SetTarget(connector, userID string) {
+	s.TargetConnector = connector
+	s.TargetUserID = userID
+}
+
+// HasTarget returns true if the output of each run is delivered to a connector.
+func (s *Schedule) HasTarget() bool {
+	return s.TargetConnector != "" && s.TargetUserID != ""
+}
+
+// SetTimezone sets the timezone the cron expression is evaluated in.
+func (s *Schedule) SetTimezone(tz valueobject.Timezone) {
+	s.Timezone = tz
+}
+
+// SetJitter sets the maximum random start delay, clamped to [0, MaxJitterSeconds].
+func (s *Schedule) SetJitter(seconds int) {
+	if seconds < 0 {
+		seconds = 0
+	}
+	if seconds > MaxJitterSeconds {
+		seconds = MaxJitterSeconds
+	}
+	s.JitterSeconds = seconds
+}
+
+// Location returns the location used to evaluate the cron expression.
+// A "CRON_TZ=" prefix in the expression takes precedence over the schedule timezone.
+func (s *Schedule) Location() *time.Location {
+	if tz := s.CronExpression.Timezone(); !tz.IsEmpty() {
+		return tz.Location()
+	}
+	return s.Timezone.Location()
+}
+
+// Jitter returns the maximum random start delay as a duration.
+func (s *Schedule) Jitter() time.Duration {
+	return time.Duration(s.JitterSeconds) * time.Second
+}
+
 // BelongsToSkill returns true if the schedule belongs to the specified skill.
 func (s *Schedule) BelongsToSkill(skill string) bool {
 	return s.Skill == skill
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxSchedulePromptLength is the maximum length in characters of the prompt of a schedule
+const MaxSchedulePromptLength = 4000
+
+// SchedulePromptData is what the prompt template of a schedule is executed with, e.g.
+// "Summarize the messages of {{.Date}}" or "Remind me about {{.Input.topic}}"
+type SchedulePromptData struct {
+	Time    time.Time              // Time the run was scheduled at, in the schedule location
+	Date    string                 // Date of Time as 2006-01-02
+	Weekday string                 // Weekday of Time, e.g. "Monday"
+	Input   map[string]interface{} // Input parameters of the schedule
+}
+
+// ValidateSchedulePrompt checks that a prompt is a valid template within MaxSchedulePromptLength
+func ValidateSchedulePrompt(prompt string) error {
+	if strings.TrimSpace(prompt) == "" {
+		return errors.New("prompt must not be empty")
+	}
+	if n := utf8.RuneCountInString(prompt); n > MaxSchedulePromptLength {
+		return fmt.Errorf("prompt must be at most %d characters, got %d", MaxSchedulePromptLength, n)
+	}
+	if _, err := parseSchedulePrompt(prompt); err != nil {
+		return fmt.Errorf("invalid prompt template: %w", err)
+	}
+	return nil
+}
+
+// NewPromptSchedule creates a new enabled schedule that sends the prompt template on a cron schedule.
+func NewPromptSchedule(prompt, cronExpression, input string) *Schedule {
+	schedule := NewSchedule("", cronExpression, input)
+	schedule.Prompt = prompt
+	return schedule
+}
+
+// NewOneShotPromptSchedule creates a new enabled schedule that sends the prompt template once at runAt.
+func NewOneShotPromptSchedule(prompt string, runAt time.Time, input string) *Schedule {
+	schedule := NewOneShotSchedule("", runAt, input)
+	schedule.Prompt = prompt
+	return schedule
+}
+
+// IsPrompt returns true if the schedule sends an LLM prompt instead of executing a skill.
+func (s *Schedule) IsPrompt() bool {
+	return s.Prompt != ""
+}
+
+// RenderPrompt executes the prompt template for a run scheduled at scheduledAt
+func (s *Schedule) RenderPrompt(scheduledAt time.Time) (string, error) {
+	tmpl, err := parseSchedulePrompt(s.Prompt)
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt template: %w", err)
+	}
+
+	local := scheduledAt.In(s.Location())
+	input := s.GetInput()
+	if input == nil {
+		input = map[string]interface{}{}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, SchedulePromptData{
+		Time:    local,
+		Date:    local.Format(time.DateOnly),
+		Weekday: local.Weekday().String(),
+		Input:   input,
+	}); err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+	return b.String(), nil
+}
+
+// parseSchedulePrompt parses a prompt template; a missing input parameter is not an error
+func parseSchedulePrompt(prompt string) (*template.Template, error) {
+	return template.New("prompt").Option("missingkey=zero").Parse(prompt)
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPromptSchedule(t *testing.T) {
+	schedule := NewPromptSchedule("Summarize the news", "0 8 * * *", "{}")
+
+	assert.True(t, schedule.IsPrompt())
+	assert.Empty(t, schedule.Skill)
+	assert.Equal(t, valueobject.ScheduleTypeCron, schedule.Type)
+
+	oneShot := NewOneShotPromptSchedule("Remind me to call mom", time.Now().Add(time.Hour), "{}")
+	assert.True(t, oneShot.IsPrompt())
+	assert.True(t, oneShot.IsOneShot())
+
+	assert.False(t, NewSchedule("weather", "0 8 * * *", "{}").IsPrompt())
+}
+
+func TestValidateSchedulePrompt(t *testing.T) {
+	assert.NoError(t, ValidateSchedulePrompt("Summarize the news of {{.Date}}"))
+	assert.Error(t, ValidateSchedulePrompt("  "))
+	assert.Error(t, ValidateSchedulePrompt("Summarize {{.Date"))
+	assert.Error(t, ValidateSchedulePrompt(strings.Repeat("a", MaxSchedulePromptLength+1)))
+}
+
+func TestSchedule_RenderPrompt(t *testing.T) {
+	schedule := NewPromptSchedule("{{.Weekday}} {{.Date}} {{.Time.Format \"15:04\"}}: news about {{.Input.topic}}", "0 8 * * *", `{"topic": "Go"}`)
+	schedule.Timezone = valueobject.Timezone("Europe/Moscow")
+
+	prompt, err := schedule.RenderPrompt(time.Date(2024, time.January, 15, 21, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "Tuesday 2024-01-16 00:30: news about Go", prompt)
+}
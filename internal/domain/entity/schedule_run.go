@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// ScheduleRun represents a single execution of a schedule.
+// Runs form the schedule's history and are used to detect activations missed while the server was down.
+type ScheduleRun struct {
+	ID          valueobject.ScheduleRunID `json:"id"`                    // Unique identifier for the run
+	ScheduleID  valueobject.ScheduleID    `json:"schedule_id"`           // ID of the schedule this run belongs to
+	Status      valueobject.TaskStatus    `json:"status"`                // Run status: "running", "completed", "failed"
+	ScheduledAt time.Time                 `json:"scheduled_at"`          // Activation time the run was planned for
+	StartedAt   time.Time                 `json:"started_at"`            // Timestamp when the run started
+	FinishedAt  *time.Time                `json:"finished_at,omitempty"` // Timestamp when the run finished (nil while running)
+	Output      string                    `json:"output"`                // Skill output if the run completed
+	Error       string                    `json:"error"`                 // Error message if the run failed
+}
+
+// NewScheduleRun creates a new running run of the schedule for the given activation time.
+func NewScheduleRun(scheduleID string, scheduledAt time.Time) *ScheduleRun {
+	return &ScheduleRun{
+		ID:          valueobject.NewGeneratedScheduleRunID(),
+		ScheduleID:  valueobject.ScheduleID(scheduleID),
+		Status:      valueobject.TaskStatusRunning,
+		ScheduledAt: scheduledAt.UTC(),
+		StartedAt:   utils.Now(),
+	}
+}
+
+// SetCompleted marks the run as completed with the skill output.
+func (r *ScheduleRun) SetCompleted(output string) {
+	r.Status = valueobject.TaskStatusCompleted
+	r.Output = output
+	r.finish()
+}
+
+// SetFailed marks the run as failed with an error message.
+func (r *ScheduleRun) SetFailed(err string) {
+	r.Status = valueobject.TaskStatusFailed
+	r.Error = err
+	r.finish()
+}
+
+// IsFinished returns true if the run has completed or failed.
+func (r *ScheduleRun) IsFinished() bool {
+	return r.FinishedAt != nil
+}
+
+// Duration returns how long the run took, or zero if it has not finished.
+func (r *ScheduleRun) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// finish records the finish timestamp.
+func (r *ScheduleRun) finish() {
+	now := utils.Now()
+	r.FinishedAt = &now
+}
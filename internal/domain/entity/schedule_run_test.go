@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScheduleRun(t *testing.T) {
+	// Arrange
+	scheduledAt := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	// Act
+	run := NewScheduleRun("schedule-1", scheduledAt)
+
+	// Assert
+	require.NotEmpty(t, run.ID)
+	assert.Equal(t, valueobject.ScheduleID("schedule-1"), run.ScheduleID)
+	assert.Equal(t, valueobject.TaskStatusRunning, run.Status)
+	assert.True(t, scheduledAt.Equal(run.ScheduledAt))
+	assert.Equal(t, time.UTC, run.ScheduledAt.Location())
+	assert.WithinDuration(t, time.Now(), run.StartedAt, time.Second)
+	assert.False(t, run.IsFinished())
+	assert.Equal(t, time.Duration(0), run.Duration())
+}
+
+func TestScheduleRun_SetCompleted(t *testing.T) {
+	// Arrange
+	run := NewScheduleRun("schedule-1", time.Now())
+
+	// Act
+	run.SetCompleted("done")
+
+	// Assert
+	assert.Equal(t, valueobject.TaskStatusCompleted, run.Status)
+	assert.Equal(t, "done", run.Output)
+	assert.True(t, run.IsFinished())
+	assert.GreaterOrEqual(t, run.Duration(), time.Duration(0))
+}
+
+func TestScheduleRun_SetFailed(t *testing.T) {
+	// Arrange
+	run := NewScheduleRun("schedule-1", time.Now())
+
+	// Act
+	run.SetFailed("boom")
+
+	// Assert
+	assert.Equal(t, valueobject.TaskStatusFailed, run.Status)
+	assert.Equal(t, "boom", run.Error)
+	assert.True(t, run.IsFinished())
+}
@@ -19,9 +19,94 @@ func TestNewSchedule(t *testing.T) {
 	assert.Equal(t, valueobject.CronExpression("0 * * * *"), schedule.CronExpression)
 	assert.Equal(t, `{"param": "value"}`, schedule.Input)
 	assert.True(t, schedule.Enabled)
+	assert.Equal(t, valueobject.TimezoneUTC, schedule.Timezone)
+	assert.Equal(t, 0, schedule.JitterSeconds)
+	assert.Equal(t, valueobject.ScheduleTypeCron, schedule.Type)
+	assert.Nil(t, schedule.RunAt)
+	assert.False(t, schedule.IsOneShot())
+	assert.Equal(t, valueobject.MissedRunSkip, schedule.MissedRunPolicy)
 	assert.WithinDuration(t, time.Now(), schedule.CreatedAt, time.Second)
 }
 
+func TestNewOneShotSchedule(t *testing.T) {
+	// Arrange
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	runAt := time.Date(2030, time.March, 1, 12, 0, 0, 0, loc)
+
+	// Act
+	schedule := NewOneShotSchedule("reminder", runAt, `{"text": "call mom"}`)
+
+	// Assert
+	require.NotEmpty(t, schedule.ID)
+	assert.Equal(t, "reminder", schedule.Skill)
+	assert.True(t, schedule.CronExpression.IsEmpty())
+	assert.Equal(t, valueobject.ScheduleTypeOnce, schedule.Type)
+	assert.True(t, schedule.IsOneShot())
+	assert.True(t, schedule.Enabled)
+	require.NotNil(t, schedule.RunAt)
+	assert.True(t, runAt.Equal(*schedule.RunAt))
+	assert.Equal(t, time.UTC, schedule.RunAt.Location())
+}
+
+func TestSchedule_Location(t *testing.T) {
+	// Arrange
+	schedule := NewSchedule("skill", "0 9 * * *", "{}")
+
+	// Assert default
+	assert.Equal(t, time.UTC, schedule.Location())
+
+	// Schedule timezone
+	schedule.SetTimezone(valueobject.MustNewTimezone("Europe/Moscow"))
+	assert.Equal(t, "Europe/Moscow", schedule.Location().String())
+
+	// CRON_TZ prefix takes precedence
+	schedule.CronExpression = valueobject.MustNewCronExpression("CRON_TZ=Asia/Tokyo 0 9 * * *")
+	assert.Equal(t, "Asia/Tokyo", schedule.Location().String())
+}
+
+func TestSchedule_SetMissedRunPolicy(t *testing.T) {
+	schedule := NewSchedule("skill", "0 * * * *", "{}")
+
+	schedule.SetMissedRunPolicy(valueobject.MissedRunAll)
+
+	assert.Equal(t, valueobject.MissedRunAll, schedule.MissedRunPolicy)
+}
+
+func TestSchedule_SetTarget(t *testing.T) {
+	schedule := NewSchedule("digest", "0 8 * * *", "{}")
+	assert.False(t, schedule.HasTarget())
+
+	schedule.SetTarget("telegram", "123456")
+	assert.True(t, schedule.HasTarget())
+	assert.Equal(t, "telegram", schedule.TargetConnector)
+	assert.Equal(t, "123456", schedule.TargetUserID)
+
+	schedule.SetTarget("telegram", "")
+	assert.False(t, schedule.HasTarget())
+}
+
+func TestSchedule_SetJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int
+		want    int
+	}{
+		{"zero", 0, 0},
+		{"regular", 90, 90},
+		{"negative clamped", -5, 0},
+		{"too large clamped", MaxJitterSeconds + 1, MaxJitterSeconds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := NewSchedule("skill", "0 * * * *", "{}")
+			schedule.SetJitter(tt.seconds)
+			assert.Equal(t, tt.want, schedule.JitterSeconds)
+			assert.Equal(t, time.Duration(tt.want)*time.Second, schedule.Jitter())
+		})
+	}
+}
+
 func TestSchedule_Enable(t *testing.T) {
 	// Arrange
 	schedule := NewSchedule("skill", "0 * * * *", "{}")
@@ -10,20 +10,24 @@ import (
 // Session represents a conversation session between a user and the AI.
 // A session contains all messages exchanged during a conversation.
 type Session struct {
-	ID        valueobject.SessionID `json:"id"`         // Unique identifier for the session
-	UserID    valueobject.UserID    `json:"user_id"`    // ID of the user who owns this session
-	CreatedAt time.Time             `json:"created_at"` // Timestamp when the session was created
-	UpdatedAt time.Time             `json:"updated_at"` // Timestamp when the session was last updated
+	ID          valueobject.SessionID   `json:"id"`           // Unique identifier for the session
+	UserID      valueobject.UserID      `json:"user_id"`      // ID of the user who owns this session
+	WorkspaceID valueobject.WorkspaceID `json:"workspace_id"` // Workspace of the connector the session was started through
+	CreatedAt   time.Time               `json:"created_at"`   // Timestamp when the session was created
+	UpdatedAt   time.Time               `json:"updated_at"`   // Timestamp when the session was last updated
+	Pinned      bool                    `json:"pinned"`       // Pinned sessions are excluded from data retention
+	Title       string                  `json:"title"`        // Short summary of the conversation, empty until generated
 }
 
-// NewSession creates a new session for the specified user.
+// NewSession creates a new session for the specified user in the default workspace.
 func NewSession(userID string) *Session {
 	now := utils.Now()
 	return &Session{
-		ID:        valueobject.SessionID(utils.GenerateID()),
-		UserID:    valueobject.MustNewUserID(userID),
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:          valueobject.NewGeneratedSessionID(),
+		UserID:      valueobject.MustNewUserID(userID),
+		WorkspaceID: valueobject.DefaultWorkspaceID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 }
 
@@ -32,6 +36,21 @@ func (s *Session) UpdateTimestamp() {
 	s.UpdatedAt = utils.Now()
 }
 
+// Pin protects the session's messages and tasks from data retention.
+func (s *Session) Pin() {
+	s.Pinned = true
+}
+
+// Unpin makes the session's messages and tasks subject to data retention again.
+func (s *Session) Unpin() {
+	s.Pinned = false
+}
+
+// IsPinned returns true if the session is excluded from data retention.
+func (s *Session) IsPinned() bool {
+	return s.Pinned
+}
+
 // IsOwnedBy returns true if the session belongs to the specified user.
 func (s *Session) IsOwnedBy(userID valueobject.UserID) bool {
 	return s.UserID.Equals(userID)
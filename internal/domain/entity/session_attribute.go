@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// SessionAttribute represents a piece of per-session key-value state.
+// Skills and the orchestrator use attributes to keep state between messages,
+// such as the current workflow step. Attributes with an expiry time disappear once it has passed.
+type SessionAttribute struct {
+	SessionID valueobject.SessionID `json:"session_id"`           // ID of the session the attribute belongs to
+	Key       string                `json:"key"`                  // Attribute name, unique within the session
+	Value     string                `json:"value"`                // Attribute value in JSON format
+	ExpiresAt *time.Time            `json:"expires_at,omitempty"` // Time the attribute expires (nil = never)
+	CreatedAt time.Time             `json:"created_at"`           // Timestamp when the attribute was first set
+	UpdatedAt time.Time             `json:"updated_at"`           // Timestamp when the attribute was last set
+}
+
+// NewSessionAttribute creates a new session attribute with a JSON value.
+// A positive ttl makes the attribute expire after that duration; zero keeps it until deleted.
+func NewSessionAttribute(sessionID, key, value string, ttl time.Duration) *SessionAttribute {
+	now := utils.Now()
+	attr := &SessionAttribute{
+		SessionID: valueobject.SessionID(sessionID),
+		Key:       key,
+		Value:     value,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	attr.SetTTL(ttl)
+	return attr
+}
+
+// SetTTL sets the attribute to expire ttl from now. A zero or negative ttl removes the expiry.
+func (a *SessionAttribute) SetTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		a.ExpiresAt = nil
+		return
+	}
+	expiresAt := utils.Now().Add(ttl)
+	a.ExpiresAt = &expiresAt
+}
+
+// IsExpired returns true if the attribute has an expiry time at or before now.
+func (a *SessionAttribute) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !a.ExpiresAt.After(now)
+}
+
+// GetValue parses and returns the value as a map.
+// Returns nil if the value is not a JSON object.
+func (a *SessionAttribute) GetValue() map[string]interface{} {
+	return utils.UnmarshalJSONToMap(a.Value)
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSessionAttribute(t *testing.T) {
+	// Act
+	attr := NewSessionAttribute("session-1", "step", `{"name":"confirm"}`, 0)
+
+	// Assert
+	assert.Equal(t, valueobject.SessionID("session-1"), attr.SessionID)
+	assert.Equal(t, "step", attr.Key)
+	assert.Equal(t, `{"name":"confirm"}`, attr.Value)
+	assert.Nil(t, attr.ExpiresAt)
+	assert.WithinDuration(t, time.Now(), attr.CreatedAt, time.Second)
+	assert.Equal(t, attr.CreatedAt, attr.UpdatedAt)
+	assert.False(t, attr.IsExpired(time.Now().Add(24*time.Hour)))
+	assert.Equal(t, "confirm", attr.GetValue()["name"])
+}
+
+func TestSessionAttribute_Expiry(t *testing.T) {
+	// Arrange
+	attr := NewSessionAttribute("session-1", "otp", `"123456"`, time.Minute)
+
+	// Assert
+	require.NotNil(t, attr.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *attr.ExpiresAt, time.Second)
+	assert.False(t, attr.IsExpired(time.Now()))
+	assert.True(t, attr.IsExpired(attr.ExpiresAt.Add(time.Second)))
+	assert.True(t, attr.IsExpired(*attr.ExpiresAt))
+	assert.Nil(t, attr.GetValue())
+
+	// Act
+	attr.SetTTL(0)
+
+	// Assert
+	assert.Nil(t, attr.ExpiresAt)
+	assert.False(t, attr.IsExpired(time.Now().Add(time.Hour)))
+}
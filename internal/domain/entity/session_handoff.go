@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// SessionHandoffKey is the session attribute key under which an operator takeover is stored
+const SessionHandoffKey = "handoff"
+
+// HandoffMode defines what happens to user messages while an operator has taken over a session
+type HandoffMode string
+
+const (
+	// HandoffModeForward forwards user messages to the operator
+	HandoffModeForward HandoffMode = "forward"
+
+	// HandoffModePause only records user messages until the operator replies or ends the takeover
+	HandoffModePause HandoffMode = "pause"
+)
+
+// IsValid returns true if the handoff mode is known
+func (m HandoffMode) IsValid() bool {
+	return m == HandoffModeForward || m == HandoffModePause
+}
+
+// SessionHandoff represents an operator takeover of a session.
+// While a session is handed off, user messages are not answered by the LLM. Handoffs are
+// stored as the SessionHandoffKey attribute of the session, so they end when it is deleted.
+type SessionHandoff struct {
+	Operator  string      `json:"operator"`   // Name of the operator who took over the session
+	Mode      HandoffMode `json:"mode"`       // What happens to user messages during the handoff
+	StartedAt time.Time   `json:"started_at"` // Timestamp when the operator took over
+}
+
+// NewSessionHandoff creates a new handoff of a session to an operator
+func NewSessionHandoff(operator string, mode HandoffMode) *SessionHandoff {
+	return &SessionHandoff{
+		Operator:  operator,
+		Mode:      mode,
+		StartedAt: utils.Now(),
+	}
+}
+
+// ToAttribute returns the session attribute storing the handoff
+func (h *SessionHandoff) ToAttribute(sessionID string) *SessionAttribute {
+	value, _ := json.Marshal(h)
+	return NewSessionAttribute(sessionID, SessionHandoffKey, string(value), 0)
+}
+
+// SessionHandoffFromAttribute parses the handoff stored in a session attribute
+func SessionHandoffFromAttribute(attr *SessionAttribute) (*SessionHandoff, error) {
+	var handoff SessionHandoff
+	if err := json.Unmarshal([]byte(attr.Value), &handoff); err != nil {
+		return nil, fmt.Errorf("invalid session handoff: %w", err)
+	}
+	if !handoff.Mode.IsValid() {
+		return nil, fmt.Errorf("invalid session handoff mode: %q", handoff.Mode)
+	}
+	return &handoff, nil
+}
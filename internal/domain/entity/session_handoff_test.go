@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionHandoff_Attribute(t *testing.T) {
+	// Arrange
+	handoff := NewSessionHandoff("alice", HandoffModeForward)
+
+	// Act
+	attr := handoff.ToAttribute("session-1")
+	parsed, err := SessionHandoffFromAttribute(attr)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, SessionHandoffKey, attr.Key)
+	assert.Nil(t, attr.ExpiresAt)
+	assert.Equal(t, "alice", parsed.Operator)
+	assert.Equal(t, HandoffModeForward, parsed.Mode)
+	assert.WithinDuration(t, handoff.StartedAt, parsed.StartedAt, time.Millisecond)
+}
+
+func TestSessionHandoffFromAttribute_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not json", `"alice"`},
+		{"unknown mode", `{"operator":"alice","mode":"mute"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SessionHandoffFromAttribute(NewSessionAttribute("session-1", SessionHandoffKey, tt.value, 0))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHandoffMode_IsValid(t *testing.T) {
+	assert.True(t, HandoffModeForward.IsValid())
+	assert.True(t, HandoffModePause.IsValid())
+	assert.False(t, HandoffMode("").IsValid())
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SessionInstructionsKey is the session attribute key under which the custom instructions of a
+// session are stored, as a JSON string
+const SessionInstructionsKey = "instructions"
+
+// SessionInstructionsAttribute returns the session attribute storing custom instructions of a
+// session. The instructions last as long as the session.
+func SessionInstructionsAttribute(sessionID, instructions string) (*SessionAttribute, error) {
+	if err := ValidateInstructions(instructions); err != nil {
+		return nil, err
+	}
+	value, _ := json.Marshal(instructions)
+	return NewSessionAttribute(sessionID, SessionInstructionsKey, string(value), 0), nil
+}
+
+// SessionInstructionsFromAttribute parses the custom instructions stored in a session attribute
+func SessionInstructionsFromAttribute(attr *SessionAttribute) (string, error) {
+	var instructions string
+	if err := json.Unmarshal([]byte(attr.Value), &instructions); err != nil {
+		return "", fmt.Errorf("invalid session instructions: %w", err)
+	}
+	return instructions, nil
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionInstructions_Attribute(t *testing.T) {
+	// Arrange
+	instructions := "Answer \"briefly\".\nUse metric units."
+
+	// Act
+	attr, err := SessionInstructionsAttribute("session-1", instructions)
+	require.NoError(t, err)
+	parsed, err := SessionInstructionsFromAttribute(attr)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, SessionInstructionsKey, attr.Key)
+	assert.Nil(t, attr.ExpiresAt)
+	assert.Equal(t, instructions, parsed)
+}
+
+func TestSessionInstructions_Invalid(t *testing.T) {
+	_, err := SessionInstructionsAttribute("session-1", strings.Repeat("a", MaxInstructionsLength+1))
+	assert.ErrorIs(t, err, ErrInstructionsTooLong)
+
+	_, err = SessionInstructionsFromAttribute(NewSessionAttribute("session-1", SessionInstructionsKey, `{"text":"x"}`, 0))
+	assert.Error(t, err)
+}
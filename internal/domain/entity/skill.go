@@ -10,20 +10,22 @@ import (
 // Skill represents a registered skill that can be executed by the AI.
 // Skills are tools with specific permissions and metadata.
 type Skill struct {
-	ID          valueobject.SkillID    `json:"id"`          // Unique identifier for the skill
-	Name        string                 `json:"name"`        // Unique skill name
-	Version     valueobject.Version    `json:"version"`     // Skill version (e.g., "1.0.0")
-	Location    string                 `json:"location"`    // Path to skill directory
-	Permissions string                 `json:"permissions"` // JSON array of required permissions
-	Metadata    string                 `json:"metadata"`    // JSON metadata (timeout, description, etc.)
-	CreatedAt   time.Time              `json:"created_at"`  // Timestamp when the skill was registered
-	MetadataMap map[string]interface{} `json:"-"`           // Parsed metadata (not persisted)
+	ID          valueobject.SkillID     `json:"id"`                     // Unique identifier for the skill
+	Name        string                  `json:"name"`                   // Unique skill name
+	Version     valueobject.Version     `json:"version"`                // Skill version (e.g., "1.0.0")
+	Location    string                  `json:"location"`               // Path to skill directory
+	Permissions string                  `json:"permissions"`            // JSON array of required permissions
+	Metadata    string                  `json:"metadata"`               // JSON metadata (timeout, description, etc.)
+	CreatedAt   time.Time               `json:"created_at"`             // Timestamp when the skill was registered
+	Disabled    bool                    `json:"disabled"`               // Disabled skills stay registered but cannot be executed
+	WorkspaceID valueobject.WorkspaceID `json:"workspace_id,omitempty"` // Workspace the skill is available in (empty = all workspaces)
+	MetadataMap map[string]interface{}  `json:"-"`                      // Parsed metadata (not persisted)
 }
 
 // NewSkill creates a new skill with the specified name, version, location, permissions, and metadata.
 func NewSkill(name, version, location string, permissions []string, metadata map[string]interface{}) *Skill {
 	return &Skill{
-		ID:          valueobject.SkillID(utils.GenerateID()),
+		ID:          valueobject.NewGeneratedSkillID(),
 		Name:        name,
 		Version:     valueobject.MustNewVersion(version),
 		Location:    location,
@@ -34,6 +36,27 @@ func NewSkill(name, version, location string, permissions []string, metadata map
 	}
 }
 
+// Disable prevents the skill from being executed without unregistering it.
+func (s *Skill) Disable() {
+	s.Disabled = true
+}
+
+// Enable allows the skill to be executed again.
+func (s *Skill) Enable() {
+	s.Disabled = false
+}
+
+// IsEnabled returns true if the skill can be executed.
+func (s *Skill) IsEnabled() bool {
+	return !s.Disabled
+}
+
+// IsAvailableIn returns true if the skill can be used in the workspace.
+// Skills without a workspace are shared by all workspaces.
+func (s *Skill) IsAvailableIn(workspaceID valueobject.WorkspaceID) bool {
+	return s.WorkspaceID == "" || s.WorkspaceID == workspaceID
+}
+
 // GetPermissions parses and returns the list of permissions.
 // Returns nil if parsing fails or permissions is empty.
 func (s *Skill) GetPermissions() []string {
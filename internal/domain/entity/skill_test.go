@@ -105,3 +105,26 @@ func TestSkill_DifferentVersions(t *testing.T) {
 	assert.NotEqual(t, skill1.Version, skill2.Version)
 	assert.NotEqual(t, skill1.ID, skill2.ID)
 }
+
+func TestSkill_DisableEnable(t *testing.T) {
+	// Arrange
+	skill := NewSkill("skill", "1.0.0", "/path", nil, nil)
+	require.True(t, skill.IsEnabled())
+
+	// Act & Assert
+	skill.Disable()
+	assert.False(t, skill.IsEnabled())
+	assert.True(t, skill.Disabled)
+
+	skill.Enable()
+	assert.True(t, skill.IsEnabled())
+}
+
+func TestSkill_IsAvailableIn(t *testing.T) {
+	skill := NewSkill("skill", "1.0.0", "/path", nil, nil)
+	assert.True(t, skill.IsAvailableIn("support"))
+
+	skill.WorkspaceID = "support"
+	assert.True(t, skill.IsAvailableIn("support"))
+	assert.False(t, skill.IsAvailableIn("default"))
+}
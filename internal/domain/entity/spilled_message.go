@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// SpilledMessage represents a connector message that did not fit into the incoming buffer of
+// a connector with the spill overflow policy. Spilled messages are kept until they are fed
+// back to the connector, in sequence order.
+type SpilledMessage struct {
+	ID        string    `json:"id"`         // Unique identifier
+	Connector string    `json:"connector"`  // Name of the connector that received the message
+	UserID    string    `json:"user_id"`    // User ID on the channel
+	ChannelID string    `json:"channel_id"` // Channel ID, e.g. the Telegram chat ID
+	Content   string    `json:"content"`    // Message text
+	Metadata  string    `json:"metadata"`   // Connector metadata in JSON format
+	Sequence  int64     `json:"sequence"`   // Position of the message in the order it was received
+	CreatedAt time.Time `json:"created_at"` // Timestamp when the message was spilled
+}
+
+// NewSpilledMessage creates a spilled message received at the given position of its connector.
+func NewSpilledMessage(connector, userID, channelID, content string, metadata map[string]interface{}, sequence int64) *SpilledMessage {
+	return &SpilledMessage{
+		ID:        valueobject.GenerateID(nil).String(),
+		Connector: connector,
+		UserID:    userID,
+		ChannelID: channelID,
+		Content:   content,
+		Metadata:  utils.MarshalJSON(metadata),
+		Sequence:  sequence,
+		CreatedAt: utils.Now(),
+	}
+}
+
+// GetMetadata parses and returns the connector metadata as a map.
+// Returns nil if parsing fails or metadata is empty.
+func (m *SpilledMessage) GetMetadata() map[string]interface{} {
+	return utils.UnmarshalJSONToMap(m.Metadata)
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpilledMessage(t *testing.T) {
+	// Act
+	message := NewSpilledMessage("telegram", "tg:42", "100", "Hello", map[string]interface{}{"message_id": 7}, 12)
+
+	// Assert
+	require.NotEmpty(t, message.ID)
+	assert.Equal(t, "telegram", message.Connector)
+	assert.Equal(t, "tg:42", message.UserID)
+	assert.Equal(t, "100", message.ChannelID)
+	assert.Equal(t, "Hello", message.Content)
+	assert.Equal(t, map[string]interface{}{"message_id": float64(7)}, message.GetMetadata())
+	assert.Equal(t, int64(12), message.Sequence)
+	assert.WithinDuration(t, time.Now(), message.CreatedAt, time.Second)
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// StoredEvent represents an event bus event kept in the event store.
+// Stored events are never changed; replaying them in sequence order rebuilds what happened.
+type StoredEvent struct {
+	Seq        int64     `json:"seq"`         // Position in the event store, assigned when the event is stored
+	ID         string    `json:"id"`          // Unique identifier for the stored event
+	Type       string    `json:"type"`        // Event type, e.g. "router.message"
+	Payload    string    `json:"payload"`     // Event fields in JSON format
+	OccurredAt time.Time `json:"occurred_at"` // Timestamp when the event was published
+}
+
+// NewStoredEvent creates a new stored event with a JSON payload.
+// The sequence number is assigned by the event store.
+func NewStoredEvent(eventType, payload string, occurredAt time.Time) *StoredEvent {
+	return &StoredEvent{
+		ID:         valueobject.GenerateID(nil).String(),
+		Type:       eventType,
+		Payload:    payload,
+		OccurredAt: occurredAt,
+	}
+}
+
+// IsStored returns true if the event has been assigned a position in the event store.
+func (e *StoredEvent) IsStored() bool {
+	return e.Seq > 0
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStoredEvent(t *testing.T) {
+	// Arrange
+	occurredAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	event := NewStoredEvent("router.message", `{"user_id":"user-1"}`, occurredAt)
+
+	// Assert
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, "router.message", event.Type)
+	assert.Equal(t, `{"user_id":"user-1"}`, event.Payload)
+	assert.Equal(t, occurredAt, event.OccurredAt)
+	assert.False(t, event.IsStored())
+
+	event.Seq = 1
+	assert.True(t, event.IsStored())
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/atumaikin/nexflow/internal/domain/valueobject"
@@ -8,24 +9,38 @@ import (
 )
 
 // Task represents a skill execution task.
-// Tasks track skill execution, status, and results.
+// Tasks track skill execution, status, and results. The status only changes
+// through Start, Complete, Fail and Cancel, which follow the TaskStatus state machine.
 type Task struct {
-	ID        valueobject.TaskID     `json:"id"`         // Unique identifier for the task
-	SessionID valueobject.SessionID  `json:"session_id"` // ID of the session this task belongs to
-	Skill     string                 `json:"skill"`      // Name of the skill to execute
-	Input     string                 `json:"input"`      // Input parameters in JSON format
-	Output    string                 `json:"output"`     // Output result in JSON format
-	Status    valueobject.TaskStatus `json:"status"`     // Task status: "pending", "running", "completed", "failed"
-	Error     string                 `json:"error"`      // Error message if the task failed
-	CreatedAt time.Time              `json:"created_at"` // Timestamp when the task was created
-	UpdatedAt time.Time              `json:"updated_at"` // Timestamp when the task was last updated
+	ID         valueobject.TaskID     `json:"id"`                    // Unique identifier for the task
+	SessionID  valueobject.SessionID  `json:"session_id"`            // ID of the session this task belongs to
+	Skill      string                 `json:"skill"`                 // Name of the skill to execute
+	Input      string                 `json:"input"`                 // Input parameters in JSON format
+	Output     string                 `json:"output"`                // Output result in JSON format
+	Status     valueobject.TaskStatus `json:"status"`                // Task status: "pending", "running", "completed", "failed", "cancelled"
+	Error      string                 `json:"error"`                 // Error message if the task failed or the reason it was cancelled
+	CreatedAt  time.Time              `json:"created_at"`            // Timestamp when the task was created
+	UpdatedAt  time.Time              `json:"updated_at"`            // Timestamp when the task was last updated
+	StartedAt  *time.Time             `json:"started_at,omitempty"`  // Timestamp when the task started running (nil while pending)
+	FinishedAt *time.Time             `json:"finished_at,omitempty"` // Timestamp when the task reached a terminal status (nil until then)
+
+	events []TaskStatusChanged // Transitions not yet pulled with PullEvents
+}
+
+// TaskStatusChanged is the domain event recorded on every task status transition.
+type TaskStatusChanged struct {
+	TaskID  valueobject.TaskID     // ID of the task
+	From    valueobject.TaskStatus // Status before the transition
+	To      valueobject.TaskStatus // Status after the transition
+	At      time.Time              // Timestamp of the transition
+	Elapsed time.Duration          // Time the task spent in the From status
 }
 
 // NewTask creates a new pending task for the specified session and skill with input parameters.
 func NewTask(sessionID, skill, input string) *Task {
 	now := utils.Now()
 	return &Task{
-		ID:        valueobject.TaskID(utils.GenerateID()),
+		ID:        valueobject.NewGeneratedTaskID(),
 		SessionID: valueobject.MustNewSessionID(sessionID),
 		Skill:     skill,
 		Input:     input,
@@ -35,26 +50,90 @@ func NewTask(sessionID, skill, input string) *Task {
 	}
 }
 
-// SetRunning sets the task status to running and updates the timestamp.
-func (t *Task) SetRunning() {
-	t.Status = valueobject.TaskStatusRunning
-	t.UpdatedAt = utils.Now()
+// Start moves a pending task to running and records when it started.
+func (t *Task) Start() error {
+	now, err := t.transition(valueobject.TaskStatusRunning)
+	if err != nil {
+		return err
+	}
+	t.StartedAt = &now
+	return nil
 }
 
-// SetCompleted sets the task status to completed with the output and updates the timestamp.
-func (t *Task) SetCompleted(output string) {
-	t.Status = valueobject.TaskStatusCompleted
+// Complete moves a running task to completed with the output and records when it finished.
+func (t *Task) Complete(output string) error {
+	now, err := t.transition(valueobject.TaskStatusCompleted)
+	if err != nil {
+		return err
+	}
 	t.Output = output
-	t.UpdatedAt = utils.Now()
+	t.FinishedAt = &now
+	return nil
 }
 
-// SetFailed sets the task status to failed with an error message and updates the timestamp.
-func (t *Task) SetFailed(err string) {
-	t.Status = valueobject.TaskStatusFailed
+// Fail moves a pending or running task to failed with an error message and records when it finished.
+func (t *Task) Fail(err string) error {
+	now, transitionErr := t.transition(valueobject.TaskStatusFailed)
+	if transitionErr != nil {
+		return transitionErr
+	}
 	if err != "" {
 		t.Error = err
 	}
-	t.UpdatedAt = utils.Now()
+	t.FinishedAt = &now
+	return nil
+}
+
+// Cancel moves a pending or running task to cancelled with the reason and records when it finished.
+func (t *Task) Cancel(reason string) error {
+	now, err := t.transition(valueobject.TaskStatusCancelled)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		t.Error = reason
+	}
+	t.FinishedAt = &now
+	return nil
+}
+
+// QueueLatency returns how long the task waited between creation and start, or zero if it never started.
+func (t *Task) QueueLatency() time.Duration {
+	if t.StartedAt == nil {
+		return 0
+	}
+	return t.StartedAt.Sub(t.CreatedAt)
+}
+
+// Duration returns how long the task ran, or zero if it has not started and finished.
+func (t *Task) Duration() time.Duration {
+	if t.StartedAt == nil || t.FinishedAt == nil {
+		return 0
+	}
+	return t.FinishedAt.Sub(*t.StartedAt)
+}
+
+// PullEvents returns the status transitions recorded since the last call and clears them.
+func (t *Task) PullEvents() []TaskStatusChanged {
+	events := t.events
+	t.events = nil
+	return events
+}
+
+// transition moves the task to the next status if the state machine allows it,
+// updates the timestamp and records a TaskStatusChanged event. It returns the transition time.
+func (t *Task) transition(next valueobject.TaskStatus) (time.Time, error) {
+	from := t.Status
+	status, err := from.TransitionTo(next)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("task %s: %w", t.ID, err)
+	}
+	now := utils.Now()
+	elapsed := now.Sub(t.UpdatedAt)
+	t.Status = status
+	t.UpdatedAt = now
+	t.events = append(t.events, TaskStatusChanged{TaskID: t.ID, From: from, To: status, At: now, Elapsed: elapsed})
+	return now, nil
 }
 
 // IsPending returns true if the task is pending.
@@ -77,6 +156,11 @@ func (t *Task) IsFailed() bool {
 	return t.Status == valueobject.TaskStatusFailed
 }
 
+// IsCancelled returns true if the task was cancelled.
+func (t *Task) IsCancelled() bool {
+	return t.Status == valueobject.TaskStatusCancelled
+}
+
 // BelongsToSession returns true if the task belongs to the specified session.
 func (t *Task) BelongsToSession(sessionID valueobject.SessionID) bool {
 	return t.SessionID.Equals(sessionID)
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// TaskAttempt represents a single execution of the skill of a task. A task whose skill failed
+// with a retryable error is executed again according to the retry policy of the skill, and
+// every execution is recorded as an attempt of the task.
+type TaskAttempt struct {
+	ID         string                 `json:"id"`          // Unique identifier
+	TaskID     valueobject.TaskID     `json:"task_id"`     // ID of the task the attempt belongs to
+	Attempt    int                    `json:"attempt"`     // Number of the attempt, starting at 1
+	Status     valueobject.TaskStatus `json:"status"`      // Outcome of the attempt: "completed", "failed" or "cancelled"
+	Error      string                 `json:"error"`       // Error message if the attempt failed or was cancelled
+	Retryable  bool                   `json:"retryable"`   // Whether the attempt failed with a retryable error
+	StartedAt  time.Time              `json:"started_at"`  // Timestamp when the attempt started
+	FinishedAt time.Time              `json:"finished_at"` // Timestamp when the attempt finished
+}
+
+// NewTaskAttempt creates an attempt of a task that starts now.
+func NewTaskAttempt(taskID valueobject.TaskID, attempt int) *TaskAttempt {
+	return &TaskAttempt{
+		ID:        valueobject.GenerateID(nil).String(),
+		TaskID:    taskID,
+		Attempt:   attempt,
+		Status:    valueobject.TaskStatusRunning,
+		StartedAt: utils.Now(),
+	}
+}
+
+// Finish records the outcome of the attempt. A retryable error only makes sense for a failed attempt.
+func (a *TaskAttempt) Finish(status valueobject.TaskStatus, err string, retryable bool) {
+	a.Status = status
+	a.Error = err
+	a.Retryable = retryable && status == valueobject.TaskStatusFailed
+	a.FinishedAt = utils.Now()
+}
+
+// Duration returns how long the attempt took, or zero if it has not finished.
+func (a *TaskAttempt) Duration() time.Duration {
+	if a.FinishedAt.IsZero() {
+		return 0
+	}
+	return a.FinishedAt.Sub(a.StartedAt)
+}
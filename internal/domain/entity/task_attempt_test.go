@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaskAttempt(t *testing.T) {
+	// Act
+	attempt := NewTaskAttempt("task-1", 2)
+
+	// Assert
+	require.NotEmpty(t, attempt.ID)
+	assert.Equal(t, valueobject.TaskID("task-1"), attempt.TaskID)
+	assert.Equal(t, 2, attempt.Attempt)
+	assert.Equal(t, valueobject.TaskStatusRunning, attempt.Status)
+	assert.WithinDuration(t, time.Now(), attempt.StartedAt, time.Second)
+	assert.Zero(t, attempt.Duration())
+}
+
+func TestTaskAttempt_Finish(t *testing.T) {
+	failed := NewTaskAttempt("task-1", 1)
+	failed.Finish(valueobject.TaskStatusFailed, "connection reset", true)
+	assert.Equal(t, valueobject.TaskStatusFailed, failed.Status)
+	assert.Equal(t, "connection reset", failed.Error)
+	assert.True(t, failed.Retryable)
+	assert.False(t, failed.FinishedAt.IsZero())
+
+	// Only failed attempts are retryable
+	completed := NewTaskAttempt("task-1", 2)
+	completed.Finish(valueobject.TaskStatusCompleted, "", true)
+	assert.False(t, completed.Retryable)
+}
@@ -26,61 +26,150 @@ func TestNewTask(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), task.UpdatedAt, time.Second)
 }
 
-func TestTask_SetRunning(t *testing.T) {
+func TestTask_Start(t *testing.T) {
 	// Arrange
 	task := NewTask("session-1", "skill", "{}")
 	time.Sleep(10 * time.Millisecond) // Ensure time difference
 
 	// Act
-	task.SetRunning()
+	err := task.Start()
 
 	// Assert
+	require.NoError(t, err)
 	assert.Equal(t, valueobject.TaskStatusRunning, task.Status)
 	assert.True(t, task.UpdatedAt.After(task.CreatedAt))
+	require.NotNil(t, task.StartedAt)
+	assert.Equal(t, task.UpdatedAt, *task.StartedAt)
+	assert.Nil(t, task.FinishedAt)
+	assert.Greater(t, task.QueueLatency(), time.Duration(0))
 }
 
-func TestTask_SetCompleted(t *testing.T) {
+func TestTask_Complete(t *testing.T) {
 	// Arrange
 	task := NewTask("session-1", "skill", "{}")
+	require.NoError(t, task.Start())
+	startedAt := *task.StartedAt
 	time.Sleep(10 * time.Millisecond) // Ensure time difference
 	output := `{"result": "success"}`
 
 	// Act
-	task.SetCompleted(output)
+	err := task.Complete(output)
 
 	// Assert
+	require.NoError(t, err)
 	assert.Equal(t, valueobject.TaskStatusCompleted, task.Status)
 	assert.Equal(t, output, task.Output)
 	assert.Empty(t, task.Error)
-	assert.True(t, task.UpdatedAt.After(task.CreatedAt))
+	assert.Equal(t, startedAt, *task.StartedAt)
+	require.NotNil(t, task.FinishedAt)
+	assert.True(t, task.FinishedAt.After(startedAt))
+	assert.Equal(t, task.FinishedAt.Sub(startedAt), task.Duration())
 }
 
-func TestTask_SetFailed(t *testing.T) {
+func TestTask_Complete_Pending(t *testing.T) {
 	// Arrange
 	task := NewTask("session-1", "skill", "{}")
+
+	// Act
+	err := task.Complete(`{}`)
+
+	// Assert
+	require.ErrorIs(t, err, valueobject.ErrInvalidTaskTransition)
+	assert.Contains(t, err.Error(), "pending -> completed")
+	assert.Equal(t, valueobject.TaskStatusPending, task.Status)
+	assert.Empty(t, task.Output)
+	assert.Nil(t, task.FinishedAt)
+}
+
+func TestTask_Fail(t *testing.T) {
+	// Arrange
+	task := NewTask("session-1", "skill", "{}")
+	require.NoError(t, task.Start())
 	time.Sleep(10 * time.Millisecond) // Ensure time difference
 	err := errors.New("execution failed")
 
 	// Act
-	task.SetFailed(err.Error())
+	failErr := task.Fail(err.Error())
 
 	// Assert
+	require.NoError(t, failErr)
 	assert.Equal(t, valueobject.TaskStatusFailed, task.Status)
 	assert.Empty(t, task.Output)
 	assert.Equal(t, "execution failed", task.Error)
-	assert.True(t, task.UpdatedAt.After(task.CreatedAt))
+	require.NotNil(t, task.FinishedAt)
+	assert.Greater(t, task.Duration(), time.Duration(0))
 }
 
-func TestTask_SetFailed_NilError(t *testing.T) {
+func TestTask_Fail_Pending(t *testing.T) {
 	// Arrange
 	task := NewTask("session-1", "skill", "{}")
 
 	// Act
-	task.SetFailed("")
+	err := task.Fail("")
 
 	// Assert
+	require.NoError(t, err)
 	assert.Equal(t, valueobject.TaskStatusFailed, task.Status)
 	assert.Empty(t, task.Error)
+	assert.Nil(t, task.StartedAt)
+	require.NotNil(t, task.FinishedAt)
+	assert.Zero(t, task.Duration())
+	assert.Zero(t, task.QueueLatency())
+}
+
+func TestTask_Cancel(t *testing.T) {
+	// Arrange
+	task := NewTask("session-1", "skill", "{}")
+	require.NoError(t, task.Start())
+
+	// Act
+	err := task.Cancel("cancelled by user")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, task.IsCancelled())
+	assert.Equal(t, "cancelled by user", task.Error)
+	require.NotNil(t, task.FinishedAt)
+}
+
+func TestTask_TerminalStatusCannotChange(t *testing.T) {
+	// Arrange
+	task := NewTask("session-1", "skill", "{}")
+	require.NoError(t, task.Start())
+	require.NoError(t, task.Complete(`{"result": "ok"}`))
+	finishedAt := *task.FinishedAt
+
+	// Act & Assert
+	assert.ErrorIs(t, task.Start(), valueobject.ErrInvalidTaskTransition)
+	assert.ErrorIs(t, task.Fail("late error"), valueobject.ErrInvalidTaskTransition)
+	assert.ErrorIs(t, task.Cancel(""), valueobject.ErrInvalidTaskTransition)
+	assert.True(t, task.IsCompleted())
+	assert.Equal(t, `{"result": "ok"}`, task.Output)
+	assert.Empty(t, task.Error)
+	assert.Equal(t, finishedAt, *task.FinishedAt)
+}
+
+func TestTask_PullEvents(t *testing.T) {
+	// Arrange
+	task := NewTask("session-1", "skill", "{}")
+	require.NoError(t, task.Start())
+	require.NoError(t, task.Complete(`{}`))
+	_ = task.Fail("ignored")
+
+	// Act
+	events := task.PullEvents()
+
+	// Assert
+	require.Len(t, events, 2)
+	assert.Equal(t, TaskStatusChanged{
+		TaskID: task.ID, From: valueobject.TaskStatusPending, To: valueobject.TaskStatusRunning,
+		At: *task.StartedAt, Elapsed: task.QueueLatency(),
+	}, events[0])
+	assert.Equal(t, TaskStatusChanged{
+		TaskID: task.ID, From: valueobject.TaskStatusRunning, To: valueobject.TaskStatusCompleted,
+		At: *task.FinishedAt, Elapsed: task.Duration(),
+	}, events[1])
+	assert.Empty(t, task.PullEvents())
 }
 
 func TestTask_IsPending(t *testing.T) {
@@ -97,7 +186,7 @@ func TestTask_IsPending(t *testing.T) {
 func TestTask_IsRunning(t *testing.T) {
 	// Arrange
 	task := NewTask("session-1", "skill", "{}")
-	task.SetRunning()
+	require.NoError(t, task.Start())
 
 	// Act & Assert
 	assert.False(t, task.IsPending())
@@ -109,7 +198,8 @@ func TestTask_IsRunning(t *testing.T) {
 func TestTask_IsCompleted(t *testing.T) {
 	// Arrange
 	task := NewTask("session-1", "skill", "{}")
-	task.SetCompleted(`{"result": "ok"}`)
+	require.NoError(t, task.Start())
+	require.NoError(t, task.Complete(`{"result": "ok"}`))
 
 	// Act & Assert
 	assert.False(t, task.IsPending())
@@ -121,7 +211,7 @@ func TestTask_IsCompleted(t *testing.T) {
 func TestTask_IsFailed(t *testing.T) {
 	// Arrange
 	task := NewTask("session-1", "skill", "{}")
-	task.SetFailed("error")
+	require.NoError(t, task.Fail("error"))
 
 	// Act & Assert
 	assert.False(t, task.IsPending())
@@ -145,12 +235,15 @@ func TestTask_StatusTransitions(t *testing.T) {
 
 	// Act & Assert - Pending to Running
 	assert.True(t, task.IsPending())
-	task.SetRunning()
+	require.NoError(t, task.Start())
 	assert.True(t, task.IsRunning())
 
 	// Running to Completed
-	task.SetCompleted(`{}`)
+	require.NoError(t, task.Complete(`{}`))
 	assert.True(t, task.IsCompleted())
+
+	// Completed back to Pending is not a transition
+	assert.False(t, task.Status.CanTransitionTo(valueobject.TaskStatusPending))
 }
 
 func TestTask_UpdatedTimestampChanges(t *testing.T) {
@@ -160,7 +253,7 @@ func TestTask_UpdatedTimestampChanges(t *testing.T) {
 	time.Sleep(10 * time.Millisecond)
 
 	// Act - trigger update
-	task.SetRunning()
+	require.NoError(t, task.Start())
 
 	// Assert
 	assert.True(t, task.UpdatedAt.After(initialUpdatedAt))
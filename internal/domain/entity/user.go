@@ -19,7 +19,7 @@ type User struct {
 // NewUser creates a new user with the specified channel and channel ID.
 func NewUser(channel, channelID string) *User {
 	return &User{
-		ID:        valueobject.UserID(utils.GenerateID()),
+		ID:        valueobject.NewGeneratedUserID(),
 		Channel:   valueobject.MustNewChannel(channel),
 		ChannelID: channelID,
 		CreatedAt: utils.Now(),
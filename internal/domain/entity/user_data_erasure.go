@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// UserDataErasure is the audit record of the erasure of a user's data.
+// It outlives the user and holds only the user's ID and the number of records erased.
+type UserDataErasure struct {
+	ID          string    `json:"id"`           // Unique identifier
+	UserID      string    `json:"user_id"`      // ID of the erased user
+	RequestedBy string    `json:"requested_by"` // SHA-256 of the credential the erasure was requested with (empty = no credential)
+	Reason      string    `json:"reason"`       // Reason given for the erasure, e.g. a ticket number
+	Sessions    int       `json:"sessions"`     // Number of sessions erased
+	Messages    int       `json:"messages"`     // Number of messages erased
+	Tasks       int       `json:"tasks"`        // Number of tasks erased
+	Attributes  int       `json:"attributes"`   // Number of session attributes erased
+	Preferences bool      `json:"preferences"`  // Whether the user had preferences, which were erased
+	Verified    bool      `json:"verified"`     // Whether no data of the user was found after the erasure
+	CreatedAt   time.Time `json:"created_at"`   // Timestamp when the erasure was performed
+}
+
+// NewUserDataErasure creates the audit record of an erasure of the specified user's data.
+func NewUserDataErasure(userID, requestedBy, reason string) *UserDataErasure {
+	return &UserDataErasure{
+		ID:          valueobject.GenerateID(nil).String(),
+		UserID:      userID,
+		RequestedBy: requestedBy,
+		Reason:      reason,
+		CreatedAt:   utils.Now(),
+	}
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserDataErasure(t *testing.T) {
+	// Act
+	erasure := NewUserDataErasure("user-1", "key-hash", "ticket 42")
+
+	// Assert
+	assert.NotEmpty(t, erasure.ID)
+	assert.Equal(t, "user-1", erasure.UserID)
+	assert.Equal(t, "key-hash", erasure.RequestedBy)
+	assert.Equal(t, "ticket 42", erasure.Reason)
+	assert.Zero(t, erasure.Sessions)
+	assert.False(t, erasure.Verified)
+	assert.WithinDuration(t, time.Now(), erasure.CreatedAt, time.Second)
+}
@@ -0,0 +1,154 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// Verbosity defines how detailed the replies to a user are
+type Verbosity string
+
+const (
+	// VerbosityConcise asks for short replies
+	VerbosityConcise Verbosity = "concise"
+
+	// VerbosityNormal leaves the length of replies to the model
+	VerbosityNormal Verbosity = "normal"
+
+	// VerbosityDetailed asks for thorough replies
+	VerbosityDetailed Verbosity = "detailed"
+)
+
+// Verbosities lists the known verbosities, shortest replies first
+var Verbosities = []Verbosity{VerbosityConcise, VerbosityNormal, VerbosityDetailed}
+
+// IsValid returns true if the verbosity is known or empty
+func (v Verbosity) IsValid() bool {
+	return v == "" || v == VerbosityConcise || v == VerbosityNormal || v == VerbosityDetailed
+}
+
+// Names of the user preferences, as used by Set
+const (
+	PreferenceInstructions = "instructions"
+	PreferenceLanguage     = "language"
+	PreferenceModel        = "model"
+	PreferenceTimezone     = "timezone"
+	PreferenceVerbosity    = "verbosity"
+)
+
+// MaxInstructionsLength is the maximum length in characters of the custom instructions of a user
+// or a session
+const MaxInstructionsLength = 4000
+
+// Errors returned for invalid user preferences
+var (
+	ErrUnknownPreference = errors.New("unknown preference")
+	ErrInvalidLanguage   = errors.New("language must be a language code such as en or pt-BR")
+	ErrInvalidTimezone   = errors.New("timezone must be an IANA time zone such as Europe/Berlin")
+	ErrInvalidVerbosity  = errors.New("verbosity must be concise, normal or detailed")
+
+	ErrInstructionsTooLong = fmt.Errorf("instructions must be at most %d characters", MaxInstructionsLength)
+)
+
+// languagePattern matches BCP 47 style language codes such as "en", "ru" or "pt-BR"
+var languagePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// UserPreferences holds the settings a user chose for the replies they get.
+// Empty fields leave the setting to the deployment defaults.
+type UserPreferences struct {
+	UserID    valueobject.UserID `json:"user_id"`
+	Language  string             `json:"language,omitempty"`  // Language code of the replies, e.g. "ru"
+	Model     string             `json:"model,omitempty"`     // LLM model used unless a request names one
+	Timezone  string             `json:"timezone,omitempty"`  // IANA time zone of the user, e.g. "Europe/Berlin"
+	Verbosity Verbosity          `json:"verbosity,omitempty"` // How detailed the replies are
+	UpdatedAt time.Time          `json:"updated_at"`          // Timestamp when the preferences were last changed
+
+	// Instructions are custom instructions of the user passed to the LLM in every session
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// NewUserPreferences creates empty preferences of a user
+func NewUserPreferences(userID string) *UserPreferences {
+	return &UserPreferences{
+		UserID:    valueobject.UserID(userID),
+		UpdatedAt: utils.Now(),
+	}
+}
+
+// Set validates and sets a preference by name; an empty value clears it
+func (p *UserPreferences) Set(name, value string) error {
+	value = strings.TrimSpace(value)
+	switch name {
+	case PreferenceInstructions:
+		if err := ValidateInstructions(value); err != nil {
+			return err
+		}
+		p.Instructions = value
+	case PreferenceLanguage:
+		if value != "" && !languagePattern.MatchString(value) {
+			return fmt.Errorf("%w: %q", ErrInvalidLanguage, value)
+		}
+		p.Language = value
+	case PreferenceModel:
+		p.Model = value
+	case PreferenceTimezone:
+		// time.LoadLocation accepts "Local", which means nothing to the model
+		if value != "" {
+			if _, err := time.LoadLocation(value); err != nil || value == "Local" {
+				return fmt.Errorf("%w: %q", ErrInvalidTimezone, value)
+			}
+		}
+		p.Timezone = value
+	case PreferenceVerbosity:
+		verbosity := Verbosity(strings.ToLower(value))
+		if !verbosity.IsValid() {
+			return fmt.Errorf("%w: %q", ErrInvalidVerbosity, value)
+		}
+		p.Verbosity = verbosity
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownPreference, name)
+	}
+	p.UpdatedAt = utils.Now()
+	return nil
+}
+
+// IsEmpty returns true if no preference is set
+func (p *UserPreferences) IsEmpty() bool {
+	return p.Language == "" && p.Model == "" && p.Timezone == "" && p.Verbosity == "" && p.Instructions == ""
+}
+
+// ValidateInstructions checks that custom instructions are not too long
+func ValidateInstructions(instructions string) error {
+	if utf8.RuneCountInString(instructions) > MaxInstructionsLength {
+		return ErrInstructionsTooLong
+	}
+	return nil
+}
+
+// SystemPrompt returns the instructions passing the preferences to the LLM, or an empty string
+// if no preference affects the replies. now is rendered in the user's time zone.
+func (p *UserPreferences) SystemPrompt(now time.Time) string {
+	var lines []string
+	if p.Language != "" {
+		lines = append(lines, fmt.Sprintf("Reply in the language with the code %q unless the user asks for another one.", p.Language))
+	}
+	if p.Timezone != "" {
+		if location, err := time.LoadLocation(p.Timezone); err == nil {
+			lines = append(lines, fmt.Sprintf("The user's time zone is %s; their local time is %s.", p.Timezone, now.In(location).Format("2006-01-02 15:04 Monday")))
+		}
+	}
+	switch p.Verbosity {
+	case VerbosityConcise:
+		lines = append(lines, "Keep replies short and to the point.")
+	case VerbosityDetailed:
+		lines = append(lines, "Give thorough, detailed replies with explanations and examples.")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserPreferences_Set(t *testing.T) {
+	// Arrange
+	prefs := NewUserPreferences("user-1")
+	assert.True(t, prefs.IsEmpty())
+
+	// Act
+	require.NoError(t, prefs.Set(PreferenceLanguage, "pt-BR"))
+	require.NoError(t, prefs.Set(PreferenceModel, " gpt-4o "))
+	require.NoError(t, prefs.Set(PreferenceTimezone, "Europe/Berlin"))
+	require.NoError(t, prefs.Set(PreferenceVerbosity, "Concise"))
+	require.NoError(t, prefs.Set(PreferenceInstructions, "Call me Sam. "))
+
+	// Assert
+	assert.Equal(t, "pt-BR", prefs.Language)
+	assert.Equal(t, "gpt-4o", prefs.Model)
+	assert.Equal(t, "Europe/Berlin", prefs.Timezone)
+	assert.Equal(t, VerbosityConcise, prefs.Verbosity)
+	assert.Equal(t, "Call me Sam.", prefs.Instructions)
+	assert.False(t, prefs.IsEmpty())
+
+	require.NoError(t, prefs.Set(PreferenceModel, ""))
+	assert.Empty(t, prefs.Model)
+}
+
+func TestUserPreferences_Set_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		pref  string
+		value string
+		err   error
+	}{
+		{name: "language", pref: PreferenceLanguage, value: "english please", err: ErrInvalidLanguage},
+		{name: "timezone", pref: PreferenceTimezone, value: "Mars/Olympus", err: ErrInvalidTimezone},
+		{name: "local timezone", pref: PreferenceTimezone, value: "Local", err: ErrInvalidTimezone},
+		{name: "verbosity", pref: PreferenceVerbosity, value: "chatty", err: ErrInvalidVerbosity},
+		{name: "instructions", pref: PreferenceInstructions, value: strings.Repeat("a", MaxInstructionsLength+1), err: ErrInstructionsTooLong},
+		{name: "unknown preference", pref: "theme", value: "dark", err: ErrUnknownPreference},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := NewUserPreferences("user-1")
+			assert.ErrorIs(t, prefs.Set(tt.pref, tt.value), tt.err)
+			assert.True(t, prefs.IsEmpty())
+		})
+	}
+}
+
+func TestUserPreferences_SystemPrompt(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 30, 0, 0, time.UTC)
+	prefs := NewUserPreferences("user-1")
+	assert.Empty(t, prefs.SystemPrompt(now))
+
+	prefs.Language = "ru"
+	prefs.Timezone = "Europe/Berlin"
+	prefs.Verbosity = VerbosityConcise
+	prompt := prefs.SystemPrompt(now)
+
+	assert.Contains(t, prompt, `"ru"`)
+	assert.Contains(t, prompt, "2026-03-02 13:30 Monday")
+	assert.Contains(t, prompt, "short")
+
+	// A model preference does not change the prompt
+	prefs = NewUserPreferences("user-1")
+	prefs.Model = "gpt-4o"
+	prefs.Verbosity = VerbosityNormal
+	assert.Empty(t, prefs.SystemPrompt(now))
+}
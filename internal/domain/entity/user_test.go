@@ -20,6 +20,15 @@ func TestNewUser(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), user.CreatedAt, time.Second)
 }
 
+func TestNewUser_GeneratedID(t *testing.T) {
+	restore := valueobject.SetIDGenerator(func() string { return "user-fixed" })
+	defer restore()
+
+	user := NewUser("telegram", "user123")
+
+	assert.Equal(t, valueobject.UserID("user-fixed"), user.ID)
+}
+
 func TestUser_CanAccessSession(t *testing.T) {
 	// Arrange
 	user := NewUser("telegram", "user123")
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"   // Not delivered yet; more attempts may follow
+	WebhookDeliveryDelivered = "delivered" // The endpoint accepted the payload
+	WebhookDeliveryFailed    = "failed"    // All attempts failed or the endpoint rejected the payload
+)
+
+// WebhookDelivery represents the delivery of one event to one webhook endpoint.
+// Deliveries form the delivery log and are updated after every attempt.
+type WebhookDelivery struct {
+	ID             string    `json:"id"`              // Unique identifier, also sent to the endpoint
+	Endpoint       string    `json:"endpoint"`        // Name of the configured endpoint
+	URL            string    `json:"url"`             // URL the payload is posted to
+	EventType      string    `json:"event_type"`      // Type of the delivered event, e.g. "task.completed"
+	Payload        string    `json:"payload"`         // JSON body posted to the endpoint
+	Status         string    `json:"status"`          // "pending", "delivered" or "failed"
+	Attempts       int       `json:"attempts"`        // Number of attempts made so far
+	ResponseStatus int       `json:"response_status"` // HTTP status of the last response (0 = no response)
+	Error          string    `json:"error"`           // Error of the last failed attempt
+	CreatedAt      time.Time `json:"created_at"`      // Timestamp when the delivery was created
+	UpdatedAt      time.Time `json:"updated_at"`      // Timestamp of the last attempt
+}
+
+// NewWebhookDelivery creates a new pending delivery of a JSON payload to an endpoint.
+func NewWebhookDelivery(endpoint, url, eventType, payload string) *WebhookDelivery {
+	now := utils.Now()
+	return &WebhookDelivery{
+		ID:        valueobject.GenerateID(nil).String(),
+		Endpoint:  endpoint,
+		URL:       url,
+		EventType: eventType,
+		Payload:   payload,
+		Status:    WebhookDeliveryPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// RecordAttempt records a failed attempt that will be retried.
+func (d *WebhookDelivery) RecordAttempt(responseStatus int, err string) {
+	d.Attempts++
+	d.ResponseStatus = responseStatus
+	d.Error = err
+	d.UpdatedAt = utils.Now()
+}
+
+// SetDelivered records the successful attempt.
+func (d *WebhookDelivery) SetDelivered(responseStatus int) {
+	d.RecordAttempt(responseStatus, "")
+	d.Status = WebhookDeliveryDelivered
+}
+
+// SetFailed records the last failed attempt; the delivery is not retried.
+func (d *WebhookDelivery) SetFailed(responseStatus int, err string) {
+	d.RecordAttempt(responseStatus, err)
+	d.Status = WebhookDeliveryFailed
+}
+
+// IsFinished returns true if the delivery was delivered or has failed.
+func (d *WebhookDelivery) IsFinished() bool {
+	return d.Status != WebhookDeliveryPending
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookDelivery(t *testing.T) {
+	// Act
+	delivery := NewWebhookDelivery("ci", "https://example.com/hook", "task.completed", `{"type":"task.completed"}`)
+
+	// Assert
+	require.NotEmpty(t, delivery.ID)
+	assert.Equal(t, "ci", delivery.Endpoint)
+	assert.Equal(t, "https://example.com/hook", delivery.URL)
+	assert.Equal(t, "task.completed", delivery.EventType)
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.Zero(t, delivery.Attempts)
+	assert.WithinDuration(t, time.Now(), delivery.CreatedAt, time.Second)
+	assert.False(t, delivery.IsFinished())
+}
+
+func TestWebhookDelivery_Attempts(t *testing.T) {
+	// Arrange
+	delivery := NewWebhookDelivery("ci", "https://example.com/hook", "task.completed", "{}")
+
+	// Act
+	delivery.RecordAttempt(503, "service unavailable")
+
+	// Assert
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, 503, delivery.ResponseStatus)
+	assert.Equal(t, "service unavailable", delivery.Error)
+	assert.False(t, delivery.IsFinished())
+
+	// Act
+	delivery.SetDelivered(200)
+
+	// Assert
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 200, delivery.ResponseStatus)
+	assert.Empty(t, delivery.Error)
+	assert.True(t, delivery.IsFinished())
+}
+
+func TestWebhookDelivery_SetFailed(t *testing.T) {
+	// Arrange
+	delivery := NewWebhookDelivery("ci", "https://example.com/hook", "task.completed", "{}")
+
+	// Act
+	delivery.SetFailed(400, "bad request")
+
+	// Assert
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, "bad request", delivery.Error)
+	assert.True(t, delivery.IsFinished())
+}
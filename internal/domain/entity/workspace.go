@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// Workspace represents a tenant of the deployment, such as a team.
+// Connectors are mapped to a workspace in the configuration; the sessions they start, the skills
+// registered for the workspace and workspace-bound API keys belong to it.
+type Workspace struct {
+	ID        valueobject.WorkspaceID `json:"id"`         // Unique identifier chosen by the admin, e.g. "support"
+	Name      string                  `json:"name"`       // Human-readable name
+	MaxTokens int                     `json:"max_tokens"` // LLM token budget of a reply in the workspace (0 = router default)
+	CreatedAt time.Time               `json:"created_at"` // Timestamp when the workspace was created
+}
+
+// NewWorkspace creates a new workspace with the specified ID and name.
+func NewWorkspace(id, name string) *Workspace {
+	return &Workspace{
+		ID:        valueobject.MustNewWorkspaceID(id),
+		Name:      name,
+		CreatedAt: utils.Now(),
+	}
+}
+
+// IsDefault returns true if the workspace is the default workspace, which cannot be deleted.
+func (w *Workspace) IsDefault() bool {
+	return w.ID == valueobject.DefaultWorkspaceID
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkspace(t *testing.T) {
+	// Act
+	workspace := NewWorkspace("support", "Support team")
+
+	// Assert
+	assert.Equal(t, valueobject.WorkspaceID("support"), workspace.ID)
+	assert.Equal(t, "Support team", workspace.Name)
+	assert.Zero(t, workspace.MaxTokens)
+	assert.WithinDuration(t, time.Now(), workspace.CreatedAt, time.Second)
+	assert.False(t, workspace.IsDefault())
+	assert.True(t, NewWorkspace("default", "Default").IsDefault())
+}
+
+func TestNewWorkspace_InvalidID(t *testing.T) {
+	assert.Panics(t, func() { NewWorkspace("support team", "Support team") })
+}
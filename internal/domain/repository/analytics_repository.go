@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// AnalyticsRepository defines the interface for the daily analytics counters
+type AnalyticsRepository interface {
+	// Increment adds delta to the counter of a metric and dimension on a day, creating it if needed
+	Increment(ctx context.Context, day string, metric entity.AnalyticsMetric, dimension string, delta float64) error
+
+	// AddActiveUser records that a user of a connector was active on a day
+	AddActiveUser(ctx context.Context, day, connector, userID string) error
+
+	// ListCounters retrieves the counters of the days from since to until, both inclusive,
+	// ordered by day, metric and dimension
+	ListCounters(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error)
+
+	// CountActiveUsers counts the distinct active users of the days from since to until as
+	// AnalyticsActiveUsers counters: per day and connector, per day (empty dimension), per
+	// connector over the range (empty day) and over the range (both empty)
+	CountActiveUsers(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error)
+}
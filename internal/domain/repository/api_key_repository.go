@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// APIKeyRepository defines the interface for HTTP API key data access.
+// Keys are stored and looked up by the hash of the key, never the key itself.
+type APIKeyRepository interface {
+	// Create saves a new API key
+	Create(ctx context.Context, apiKey *entity.APIKey) error
+
+	// GetByHash retrieves an API key by the hash of the key, including revoked keys
+	GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// List retrieves all API keys, oldest first
+	List(ctx context.Context) ([]*entity.APIKey, error)
+
+	// Revoke marks an active API key as revoked and reports whether it was active
+	Revoke(ctx context.Context, id string, revokedAt time.Time) (bool, error)
+}
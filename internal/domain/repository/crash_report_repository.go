@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// CrashReportFilter selects crash reports.
+// The zero value selects every report.
+type CrashReportFilter struct {
+	// Component keeps reports of panics recovered in this component (empty = all components)
+	Component string
+
+	// Limit is the maximum number of reports to return (0 = no limit)
+	Limit int
+
+	// Offset is the number of reports to skip
+	Offset int
+}
+
+// CrashReportRepository defines the interface for the crash log of recovered panics
+type CrashReportRepository interface {
+	// Create saves the report of a recovered panic
+	Create(ctx context.Context, report *entity.CrashReport) error
+
+	// List retrieves the reports matching a filter, newest first
+	List(ctx context.Context, filter CrashReportFilter) ([]*entity.CrashReport, error)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// DeadLetterFilter selects dead letters.
+// The zero value selects every dead letter.
+type DeadLetterFilter struct {
+	// Subscription keeps dead letters of the subscription with this name (empty = all subscriptions)
+	Subscription string
+
+	// Limit is the maximum number of dead letters to return (0 = no limit)
+	Limit int
+
+	// Offset is the number of dead letters to skip
+	Offset int
+}
+
+// DeadLetterRepository defines the interface for the dead-letter queue of the event bus
+type DeadLetterRepository interface {
+	// Create saves a new dead letter
+	Create(ctx context.Context, letter *entity.DeadLetter) error
+
+	// GetByID retrieves a dead letter by its ID
+	GetByID(ctx context.Context, id string) (*entity.DeadLetter, error)
+
+	// List retrieves the dead letters matching a filter, newest first
+	List(ctx context.Context, filter DeadLetterFilter) ([]*entity.DeadLetter, error)
+
+	// Update saves the outcome of the latest redelivery attempt
+	Update(ctx context.Context, letter *entity.DeadLetter) error
+
+	// Delete removes a dead letter
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// DocumentChunkRepository defines the interface for the chunks of the documents shared in
+// sessions. Chunks are deleted with their session.
+type DocumentChunkRepository interface {
+	// Create saves the chunks of a document
+	Create(ctx context.Context, chunks []*entity.DocumentChunk) error
+
+	// FindBySessionID retrieves the chunks of the documents of a session, in the order the
+	// documents were shared, then by position
+	FindBySessionID(ctx context.Context, sessionID string) ([]*entity.DocumentChunk, error)
+
+	// DeleteBySessionID deletes the chunks of the documents of a session and returns their number
+	DeleteBySessionID(ctx context.Context, sessionID string) (int64, error)
+}
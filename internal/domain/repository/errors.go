@@ -0,0 +1,11 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrNotFound is wrapped by repository errors when the requested record does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is wrapped by repository errors when a write violates a uniqueness constraint
+	ErrConflict = errors.New("already exists")
+)
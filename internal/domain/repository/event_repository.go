@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// EventFilter selects stored events.
+// The zero value selects every event.
+type EventFilter struct {
+	// Since keeps events that occurred at or after this time (zero = no lower bound)
+	Since time.Time
+
+	// Until keeps events that occurred before this time (zero = no upper bound)
+	Until time.Time
+
+	// Types keeps events of these types (empty = all types)
+	Types []string
+
+	// AfterSeq continues a listing after the event with this sequence number (0 = from the start)
+	AfterSeq int64
+
+	// Limit is the maximum number of events to return (0 = no limit)
+	Limit int
+}
+
+// EventSubject selects the stored events about users or sessions: those whose payload names
+// one of the users or sessions. The zero value selects no event.
+type EventSubject struct {
+	// UserIDs are the entity IDs of the users
+	UserIDs []string
+
+	// ChannelUsers are the users by their ID on the channel of a connector. Connectors of
+	// different channels may use the same IDs, so they select the events of their connector only.
+	ChannelUsers []ChannelUser
+
+	// SessionIDs are the IDs of the sessions
+	SessionIDs []string
+}
+
+// ChannelUser names a user by their ID on the channel of a connector
+type ChannelUser struct {
+	// Connector is the name of the connector that received the messages of the user
+	Connector string
+
+	// UserID is the ID of the user on the channel
+	UserID string
+}
+
+// EventRepository defines the interface for the persistent event store
+type EventRepository interface {
+	// Append saves events in order and assigns their sequence numbers
+	Append(ctx context.Context, events ...*entity.StoredEvent) error
+
+	// GetByID retrieves a stored event by ID; it returns ErrNotFound for an unknown ID
+	GetByID(ctx context.Context, id string) (*entity.StoredEvent, error)
+
+	// List retrieves the events matching a filter, in sequence order
+	List(ctx context.Context, filter EventFilter) ([]*entity.StoredEvent, error)
+
+	// ListBySubject retrieves the events about the users or sessions of a subject, in sequence order
+	ListBySubject(ctx context.Context, subject EventSubject) ([]*entity.StoredEvent, error)
+
+	// DeleteBySubject removes the events about the users or sessions of a subject and returns
+	// the number of events removed
+	DeleteBySubject(ctx context.Context, subject EventSubject) (int, error)
+}
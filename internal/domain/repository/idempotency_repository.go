@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// IdempotencyRepository keeps the responses of write API requests sent with an Idempotency-Key
+// header, so that retries of a request get its response instead of repeating it
+type IdempotencyRepository interface {
+	// Find returns the unexpired record of a key, or ErrNotFound
+	Find(ctx context.Context, key string) (*entity.IdempotencyRecord, error)
+
+	// Reserve records a pending request of a key, without a response, so that other requests
+	// with the key, also of other instances, find it while the request is handled. It returns
+	// false if an unexpired record of the key exists, which is kept.
+	Reserve(ctx context.Context, record *entity.IdempotencyRecord) (bool, error)
+
+	// Save records a response, replacing the pending record of the same request. It returns
+	// false if another unexpired record of the key exists, which is kept.
+	Save(ctx context.Context, record *entity.IdempotencyRecord) (bool, error)
+
+	// Release removes the pending record of a key, so that its request can be retried
+	Release(ctx context.Context, key string) error
+
+	// DeleteExpired removes records that expired at or before now and returns how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// JobRepository keeps the jobs of the background job queue
+type JobRepository interface {
+	// Create saves a new job; a job whose ID already exists is left as is and ErrConflict is
+	// returned
+	Create(ctx context.Context, job *entity.Job) error
+
+	// FindByID returns a job, or ErrNotFound
+	FindByID(ctx context.Context, id string) (*entity.Job, error)
+
+	// Claim leases the job that is due the longest to a worker until lockedUntil, counting an
+	// attempt. Pending jobs due at now and running jobs whose lease expired are due; leader-only
+	// jobs are only due for the leader. Returns ErrNotFound if no job is due.
+	Claim(ctx context.Context, owner string, leader bool, now, lockedUntil time.Time) (*entity.Job, error)
+
+	// Update saves the status and outcome of a job leased to owner; ErrNotFound is returned
+	// when the lease was lost, e.g. because it expired and the job was claimed again
+	Update(ctx context.Context, job *entity.Job, owner string) error
+
+	// DeleteFinishedBefore removes jobs that finished at or before a time and returns how many
+	// were removed
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// LeaseRepository keeps the named leases that coordinate the instances sharing the database
+type LeaseRepository interface {
+	// Acquire takes a lease for holder until expiresAt, or renews it if holder already has it.
+	// Returns false if another holder has a lease that has not expired at now.
+	Acquire(ctx context.Context, name, holder string, now, expiresAt time.Time) (bool, error)
+
+	// Release gives up a lease held by holder; a lease of another holder is left as is
+	Release(ctx context.Context, name, holder string) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// LLMUsageRepository defines the interface for the usage of the LLM calls, kept for billing
+type LLMUsageRepository interface {
+	// Create saves the usage of an LLM call. Saving a usage with the ID of a saved one does
+	// nothing, so that a usage event handled twice is counted once.
+	Create(ctx context.Context, usage *entity.LLMUsage) error
+
+	// SumByMonth sums the usage of the months from since to until, both inclusive, per month,
+	// workspace, user, provider and model, ordered by these
+	SumByMonth(ctx context.Context, since, until string) ([]*entity.LLMUsageTotal, error)
+}
@@ -2,10 +2,26 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 )
 
+// LogFilter selects the logs returned by LogRepository.List
+type LogFilter struct {
+	// Level keeps logs with this level (empty = all levels)
+	Level string
+
+	// Source keeps logs from this source (empty = all sources)
+	Source string
+
+	// Limit is the maximum number of logs to return (0 = no limit)
+	Limit int
+
+	// Offset is the number of logs to skip
+	Offset int
+}
+
 // LogRepository defines the interface for log data operations
 type LogRepository interface {
 	// Create saves a new log entry
@@ -14,20 +30,30 @@ type LogRepository interface {
 	// FindByID retrieves a log entry by ID
 	FindByID(ctx context.Context, id string) (*entity.Log, error)
 
-	// FindByLevel retrieves logs by level
-	FindByLevel(ctx context.Context, level string, limit int) ([]*entity.Log, error)
+	// FindByLevel retrieves logs by level, newest first
+	FindByLevel(ctx context.Context, level string, opts QueryOptions) ([]*entity.Log, error)
 
-	// FindBySource retrieves logs by source
-	FindBySource(ctx context.Context, source string, limit int) ([]*entity.Log, error)
+	// FindBySource retrieves logs by source, newest first
+	FindBySource(ctx context.Context, source string, opts QueryOptions) ([]*entity.Log, error)
 
 	// FindByDateRange retrieves logs within a date range
 	FindByDateRange(ctx context.Context, startDate, endDate string, limit int) ([]*entity.Log, error)
 
+	// List retrieves the logs matching a filter, newest first
+	List(ctx context.Context, filter LogFilter) ([]*entity.Log, error)
+
 	// Delete removes a log entry
 	Delete(ctx context.Context, id string) error
 
-	// DeleteOlderThan removes logs older than a specific date
-	DeleteOlderThan(ctx context.Context, date string) error
+	// CountOlderThan counts logs created before a time
+	CountOlderThan(ctx context.Context, before time.Time) (int, error)
+
+	// FindOlderThan retrieves logs created before a time, oldest first.
+	// Only opts.Limit and opts.After are used, to read the logs in batches.
+	FindOlderThan(ctx context.Context, before time.Time, opts QueryOptions) ([]*entity.Log, error)
+
+	// DeleteOlderThan removes logs created before a time and returns how many were removed
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
 
 	// CountByLevel counts logs by level
 	CountByLevel(ctx context.Context, level string) (int, error)
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// MessageDeadLetterFilter selects message dead letters.
+// The zero value selects every message dead letter.
+type MessageDeadLetterFilter struct {
+	// Connector keeps dead letters of messages received by this connector (empty = all connectors)
+	Connector string
+
+	// UserID keeps dead letters of messages from this user on the channel (empty = all users)
+	UserID string
+
+	// Limit is the maximum number of dead letters to return (0 = no limit)
+	Limit int
+
+	// Offset is the number of dead letters to skip
+	Offset int
+}
+
+// MessageDeadLetterRepository defines the interface for the dead-letter queue of the message router
+type MessageDeadLetterRepository interface {
+	// Create saves a new message dead letter
+	Create(ctx context.Context, letter *entity.MessageDeadLetter) error
+
+	// GetByID retrieves a message dead letter by its ID
+	GetByID(ctx context.Context, id string) (*entity.MessageDeadLetter, error)
+
+	// List retrieves the message dead letters matching a filter, newest first
+	List(ctx context.Context, filter MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error)
+
+	// Update saves the outcome of the latest reprocessing attempt
+	Update(ctx context.Context, letter *entity.MessageDeadLetter) error
+
+	// Delete removes a message dead letter
+	Delete(ctx context.Context, id string) error
+
+	// DeleteByUser removes the dead letters of messages received by a connector from a user on
+	// its channel and returns the number of dead letters removed
+	DeleteByUser(ctx context.Context, connector, userID string) (int, error)
+}
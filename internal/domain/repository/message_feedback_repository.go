@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// MessageFeedbackRepository defines the interface for the feedback of users on assistant
+// messages. Feedback is deleted with its message.
+type MessageFeedbackRepository interface {
+	// Save saves the feedback on a message, replacing the rating, comment, day and update time
+	// of an earlier feedback on the same message
+	Save(ctx context.Context, feedback *entity.MessageFeedback) error
+
+	// FindByMessageID retrieves the feedback on a message
+	FindByMessageID(ctx context.Context, messageID string) (*entity.MessageFeedback, error)
+
+	// CountByDay counts the feedback of the days from since to until, both inclusive, as
+	// AnalyticsFeedback counters per day and rating, ordered by day and rating
+	CountByDay(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error)
+}
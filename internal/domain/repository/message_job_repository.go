@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// MessageJobRepository keeps the jobs of the asynchronous message API
+type MessageJobRepository interface {
+	// Create saves a new job
+	Create(ctx context.Context, job *entity.MessageJob) error
+
+	// FindByID returns a job, or ErrNotFound
+	FindByID(ctx context.Context, id string) (*entity.MessageJob, error)
+
+	// FindByBatchID returns the jobs of a batch in their order; a batch without jobs is empty
+	FindByBatchID(ctx context.Context, batchID string) ([]*entity.MessageJob, error)
+
+	// Update saves the status and outcome of a job
+	Update(ctx context.Context, job *entity.MessageJob) error
+
+	// FailUnfinished fails the pending and running jobs with the reason, for jobs left behind
+	// by a stopped process, and returns how many were failed
+	FailUnfinished(ctx context.Context, reason string, now time.Time) (int64, error)
+
+	// DeleteFinishedBefore removes jobs that finished at or before a time and returns how many
+	// were removed
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -2,10 +2,26 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 )
 
+// MessageSearch describes a full-text search over message content
+type MessageSearch struct {
+	// Query is the text to look for; every word has to match
+	Query string
+
+	// UserID limits the search to sessions of a user (empty = all users)
+	UserID string
+
+	// SessionID limits the search to a single session (empty = all sessions)
+	SessionID string
+
+	// Workspace limits the search to sessions of a workspace (empty = all workspaces)
+	Workspace string
+}
+
 // MessageRepository defines the interface for message data operations
 type MessageRepository interface {
 	// Create saves a new message
@@ -14,8 +30,22 @@ type MessageRepository interface {
 	// FindByID retrieves a message by ID
 	FindByID(ctx context.Context, id string) (*entity.Message, error)
 
-	// FindBySessionID retrieves all messages for a session
-	FindBySessionID(ctx context.Context, sessionID string) ([]*entity.Message, error)
+	// FindBySessionID retrieves messages for a session, oldest first
+	FindBySessionID(ctx context.Context, sessionID string, opts QueryOptions) ([]*entity.Message, error)
+
+	// Search retrieves messages whose content matches the search, newest first
+	Search(ctx context.Context, search MessageSearch, opts QueryOptions) ([]*entity.Message, error)
+
+	// CountOlderThan counts messages created before a time, excluding pinned sessions
+	CountOlderThan(ctx context.Context, before time.Time) (int, error)
+
+	// FindOlderThan retrieves messages created before a time, excluding pinned sessions, oldest first.
+	// Only opts.Limit and opts.After are used, to read the messages in batches.
+	FindOlderThan(ctx context.Context, before time.Time, opts QueryOptions) ([]*entity.Message, error)
+
+	// DeleteOlderThan removes messages created before a time, excluding pinned sessions,
+	// and returns how many were removed
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
 
 	// Delete removes a message
 	Delete(ctx context.Context, id string) error
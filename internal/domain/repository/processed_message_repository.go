@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// ProcessedMessageRepository records the incoming connector messages that were already handled,
+// so that messages delivered twice by a connector are handled once.
+// Messages are identified by a key built from the connector and the message ID.
+type ProcessedMessageRepository interface {
+	// Record marks a message as processed for ttl. It returns false if the message was
+	// already recorded and the record has not expired yet.
+	Record(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// Forget removes the record of a message, so that a redelivery of it is handled
+	Forget(ctx context.Context, key string) error
+
+	// DeleteExpired removes records that expired at or before now and returns how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import "time"
+
+// QueryOptions narrows and pages the results of list queries.
+// The zero value returns every row.
+type QueryOptions struct {
+	// Limit is the maximum number of rows to return (0 = no limit)
+	Limit int
+
+	// Offset is the number of rows to skip
+	Offset int
+
+	// Since keeps rows created at or after this time (zero = no lower bound)
+	Since time.Time
+
+	// Until keeps rows created before this time (zero = no upper bound)
+	Until time.Time
+
+	// After continues a listing after the row the cursor points to (nil = from the start)
+	After *Cursor
+
+	// Workspace keeps rows of this workspace (empty = all workspaces); honored by session listings
+	Workspace string
+}
+
+// Cursor identifies the last row of a page so the next page can continue after it.
+// Rows are compared by creation time, then by insertion order for rows created in the same second.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
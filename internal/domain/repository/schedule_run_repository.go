@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// ScheduleRunRepository defines the interface for schedule run history operations
+type ScheduleRunRepository interface {
+	// Create saves a new schedule run
+	Create(ctx context.Context, run *entity.ScheduleRun) error
+
+	// Update updates an existing schedule run
+	Update(ctx context.Context, run *entity.ScheduleRun) error
+
+	// FindByScheduleID retrieves the most recent runs of a schedule, newest first
+	FindByScheduleID(ctx context.Context, scheduleID string, limit int) ([]*entity.ScheduleRun, error)
+
+	// FindLatest retrieves the most recent run of a schedule
+	FindLatest(ctx context.Context, scheduleID string) (*entity.ScheduleRun, error)
+}
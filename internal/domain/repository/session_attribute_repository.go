@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// SessionAttributeRepository defines the interface for per-session key-value state.
+// Expired attributes are never returned.
+type SessionAttributeRepository interface {
+	// Get retrieves an attribute of a session by key
+	Get(ctx context.Context, sessionID, key string) (*entity.SessionAttribute, error)
+
+	// FindBySessionID retrieves all attributes of a session, ordered by key
+	FindBySessionID(ctx context.Context, sessionID string) ([]*entity.SessionAttribute, error)
+
+	// Set creates or replaces an attribute, keeping the original creation time
+	Set(ctx context.Context, attr *entity.SessionAttribute) error
+
+	// Delete removes an attribute and reports whether it existed
+	Delete(ctx context.Context, sessionID, key string) (bool, error)
+
+	// DeleteExpired removes attributes that expired at or before now and returns how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -14,8 +14,11 @@ type SessionRepository interface {
 	// FindByID retrieves a session by ID
 	FindByID(ctx context.Context, id string) (*entity.Session, error)
 
-	// FindByUserID retrieves all sessions for a user
-	FindByUserID(ctx context.Context, userID string) ([]*entity.Session, error)
+	// List retrieves sessions of all users, newest first
+	List(ctx context.Context, opts QueryOptions) ([]*entity.Session, error)
+
+	// FindByUserID retrieves sessions for a user, newest first
+	FindByUserID(ctx context.Context, userID string, opts QueryOptions) ([]*entity.Session, error)
 
 	// Update updates an existing session
 	Update(ctx context.Context, session *entity.Session) error
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// SpilledMessageRepository defines the interface for the messages that did not fit into the
+// incoming buffer of a connector with the spill overflow policy
+type SpilledMessageRepository interface {
+	// Create saves a spilled message
+	Create(ctx context.Context, message *entity.SpilledMessage) error
+
+	// List retrieves up to limit spilled messages of a connector, oldest first
+	List(ctx context.Context, connector string, limit int) ([]*entity.SpilledMessage, error)
+
+	// Count returns the number of spilled messages of a connector
+	Count(ctx context.Context, connector string) (int, error)
+
+	// Delete removes a spilled message once it is fed back to its connector
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// TaskAttemptRepository defines the interface for the attempts of tasks
+type TaskAttemptRepository interface {
+	// Create saves a finished attempt of a task
+	Create(ctx context.Context, attempt *entity.TaskAttempt) error
+
+	// FindByTaskID retrieves the attempts of a task in the order they were made
+	FindByTaskID(ctx context.Context, taskID string) ([]*entity.TaskAttempt, error)
+}
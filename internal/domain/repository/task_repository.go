@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 )
@@ -14,12 +15,23 @@ type TaskRepository interface {
 	// FindByID retrieves a task by ID
 	FindByID(ctx context.Context, id string) (*entity.Task, error)
 
-	// FindBySessionID retrieves all tasks for a session
-	FindBySessionID(ctx context.Context, sessionID string) ([]*entity.Task, error)
+	// FindBySessionID retrieves tasks for a session, newest first
+	FindBySessionID(ctx context.Context, sessionID string, opts QueryOptions) ([]*entity.Task, error)
 
 	// Update updates an existing task
 	Update(ctx context.Context, task *entity.Task) error
 
+	// CountOlderThan counts tasks created before a time, excluding pinned sessions
+	CountOlderThan(ctx context.Context, before time.Time) (int, error)
+
+	// FindOlderThan retrieves tasks created before a time, excluding pinned sessions, oldest first.
+	// Only opts.Limit and opts.After are used, to read the tasks in batches.
+	FindOlderThan(ctx context.Context, before time.Time, opts QueryOptions) ([]*entity.Task, error)
+
+	// DeleteOlderThan removes tasks created before a time, excluding pinned sessions,
+	// and returns how many were removed
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
+
 	// Delete removes a task
 	Delete(ctx context.Context, id string) error
 }
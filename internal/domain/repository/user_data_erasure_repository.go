@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// UserDataErasureFilter selects user data erasures.
+// The zero value selects every erasure.
+type UserDataErasureFilter struct {
+	// UserID keeps erasures of the user with this ID (empty = all users)
+	UserID string
+
+	// Limit is the maximum number of erasures to return (0 = no limit)
+	Limit int
+
+	// Offset is the number of erasures to skip
+	Offset int
+}
+
+// UserDataErasureRepository defines the interface for the audit log of user data erasures
+type UserDataErasureRepository interface {
+	// Create saves the audit record of an erasure
+	Create(ctx context.Context, erasure *entity.UserDataErasure) error
+
+	// List retrieves the erasures matching a filter, newest first
+	List(ctx context.Context, filter UserDataErasureFilter) ([]*entity.UserDataErasure, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// UserPreferencesRepository defines the interface for the preferences of users
+type UserPreferencesRepository interface {
+	// Get retrieves the preferences of a user, or ErrNotFound if the user has set none
+	Get(ctx context.Context, userID string) (*entity.UserPreferences, error)
+
+	// Save creates or replaces the preferences of a user
+	Save(ctx context.Context, prefs *entity.UserPreferences) error
+
+	// Delete removes the preferences of a user and reports whether they existed
+	Delete(ctx context.Context, userID string) (bool, error)
+}
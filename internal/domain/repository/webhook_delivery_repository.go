@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// WebhookDeliveryFilter selects webhook deliveries.
+// The zero value selects every delivery.
+type WebhookDeliveryFilter struct {
+	// Endpoint keeps deliveries to the endpoint with this name (empty = all endpoints)
+	Endpoint string
+
+	// Status keeps deliveries with this status (empty = all statuses)
+	Status string
+
+	// Limit is the maximum number of deliveries to return (0 = no limit)
+	Limit int
+
+	// Offset is the number of deliveries to skip
+	Offset int
+}
+
+// WebhookDeliveryRepository defines the interface for the webhook delivery log
+type WebhookDeliveryRepository interface {
+	// Create saves a new delivery
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// Update saves the outcome of the latest delivery attempt
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// List retrieves the deliveries matching a filter, newest first
+	List(ctx context.Context, filter WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// WorkspaceRepository defines the interface for workspace data access
+type WorkspaceRepository interface {
+	// Create saves a new workspace
+	Create(ctx context.Context, workspace *entity.Workspace) error
+
+	// FindByID retrieves a workspace by its ID
+	FindByID(ctx context.Context, id string) (*entity.Workspace, error)
+
+	// List retrieves all workspaces, oldest first
+	List(ctx context.Context) ([]*entity.Workspace, error)
+
+	// Update saves the name and token budget of a workspace
+	Update(ctx context.Context, workspace *entity.Workspace) error
+
+	// Delete removes a workspace
+	Delete(ctx context.Context, id string) error
+}
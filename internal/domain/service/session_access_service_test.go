@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
 	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,7 +37,11 @@ func (m *mockSessionRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (m *mockSessionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Session, error) {
+func (m *mockSessionRepository) List(ctx context.Context, opts repository.QueryOptions) ([]*entity.Session, error) {
+	return nil, nil
+}
+
+func (m *mockSessionRepository) FindByUserID(ctx context.Context, userID string, opts repository.QueryOptions) ([]*entity.Session, error) {
 	return nil, nil
 }
 
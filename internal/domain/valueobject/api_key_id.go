@@ -0,0 +1,87 @@
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// APIKeyID represents a API key identifier.
+type APIKeyID ID
+
+// String returns the string representation of the APIKeyID.
+func (id APIKeyID) String() string {
+	return string(id)
+}
+
+// IsEmpty returns true if the APIKeyID is empty.
+func (id APIKeyID) IsEmpty() bool {
+	return string(id) == ""
+}
+
+// IsValid checks if the APIKeyID is valid (not empty and matches pattern).
+func (id APIKeyID) IsValid() bool {
+	return ID(id).IsValid()
+}
+
+// Equals checks if the APIKeyID equals another APIKeyID.
+func (id APIKeyID) Equals(other APIKeyID) bool {
+	return id == other
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (id APIKeyID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(id))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (id *APIKeyID) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		return ErrEmptyID
+	}
+	if !APIKeyID(str).IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidID, str)
+	}
+	*id = APIKeyID(str)
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+func (id APIKeyID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty APIKeyID; other values must be valid.
+func (id *APIKeyID) Scan(src any) error {
+	return scanValue(id, src, "API key id", NewAPIKeyID)
+}
+
+// NewAPIKeyID creates a new APIKeyID from a string.
+// Returns an error if the string is not a valid ID.
+func NewAPIKeyID(idStr string) (APIKeyID, error) {
+	id, err := NewID(idStr)
+	if err != nil {
+		return "", err
+	}
+	return APIKeyID(id), nil
+}
+
+// NewGeneratedAPIKeyID generates a new unique APIKeyID (see GenerateID).
+func NewGeneratedAPIKeyID() APIKeyID {
+	return APIKeyID(GenerateID(nil))
+}
+
+// MustNewAPIKeyID creates a new APIKeyID from a string.
+// Panics if the string is not a valid ID.
+func MustNewAPIKeyID(idStr string) APIKeyID {
+	id, err := NewAPIKeyID(idStr)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,71 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidAPIKeyScope is returned when an invalid API key scope is provided.
+	ErrInvalidAPIKeyScope = errors.New("invalid api key scope")
+)
+
+// APIKeyScope represents what an HTTP API credential is allowed to do.
+// It's a value object that ensures type safety for API key scopes.
+type APIKeyScope string
+
+const (
+	// APIKeyScopeRead allows read-only requests outside the admin API.
+	APIKeyScopeRead APIKeyScope = "read"
+	// APIKeyScopeAdmin allows all requests, including writes and the admin API.
+	APIKeyScopeAdmin APIKeyScope = "admin"
+)
+
+// String returns the string representation of the API key scope.
+func (s APIKeyScope) String() string {
+	return string(s)
+}
+
+// IsValid checks if the API key scope is valid.
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case APIKeyScopeRead, APIKeyScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAdmin returns true if the scope allows all requests.
+func (s APIKeyScope) IsAdmin() bool {
+	return s == APIKeyScopeAdmin
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (s APIKeyScope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (s *APIKeyScope) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = APIKeyScope(str)
+	if !s.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidAPIKeyScope, str)
+	}
+	return nil
+}
+
+// NewAPIKeyScope creates a new APIKeyScope from a string.
+// Returns an error if the string is not a valid scope.
+func NewAPIKeyScope(scope string) (APIKeyScope, error) {
+	s := APIKeyScope(scope)
+	if !s.IsValid() {
+		return "", ErrInvalidAPIKeyScope
+	}
+	return s, nil
+}
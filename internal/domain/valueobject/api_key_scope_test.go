@@ -0,0 +1,57 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewAPIKeyScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    APIKeyScope
+		wantErr bool
+	}{
+		{"read", "read", APIKeyScopeRead, false},
+		{"admin", "admin", APIKeyScopeAdmin, false},
+		{"empty", "", "", true},
+		{"invalid", "write", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAPIKeyScope(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewAPIKeyScope() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewAPIKeyScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyScope_IsAdmin(t *testing.T) {
+	if !APIKeyScopeAdmin.IsAdmin() {
+		t.Error("APIKeyScopeAdmin.IsAdmin() = false, want true")
+	}
+	if APIKeyScopeRead.IsAdmin() {
+		t.Error("APIKeyScopeRead.IsAdmin() = true, want false")
+	}
+}
+
+func TestAPIKeyScope_JSON(t *testing.T) {
+	data, err := json.Marshal(APIKeyScopeRead)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `"read"` {
+		t.Errorf("json.Marshal() = %s, want \"read\"", data)
+	}
+
+	var got APIKeyScope
+	if err := json.Unmarshal([]byte(`"owner"`), &got); err == nil {
+		t.Error("json.Unmarshal() of an invalid scope should fail")
+	}
+}
@@ -1,9 +1,13 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"sync"
 )
 
 var (
@@ -24,21 +28,73 @@ const (
 	ChannelDiscord Channel = "discord"
 	// ChannelWeb represents the web interface.
 	ChannelWeb Channel = "web"
+	// ChannelSystem represents internal system actors such as the scheduler.
+	ChannelSystem Channel = "system"
 )
 
+// channelNamePattern is the format of channel names: lowercase letters, digits, "_" and "-"
+var channelNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// channels holds the known channels: the built-in ones and those registered by connectors
+var channels = struct {
+	sync.RWMutex
+	known map[Channel]struct{}
+}{known: map[Channel]struct{}{
+	ChannelTelegram: {},
+	ChannelDiscord:  {},
+	ChannelWeb:      {},
+	ChannelSystem:   {},
+}}
+
+// RegisterChannel adds a channel to the known channels, so that a new connector, such as
+// slack or matrix, is accepted without changing this package. Registering a known channel
+// again has no effect.
+//
+// Parameters:
+//   - name: Name of the channel, e.g. "slack"
+//
+// Returns:
+//   - Channel: The registered channel
+//   - error: ErrEmptyChannel or ErrInvalidChannel if the name is not a valid channel name
+func RegisterChannel(name string) (Channel, error) {
+	if name == "" {
+		return "", ErrEmptyChannel
+	}
+	if !channelNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %q must consist of lowercase letters, digits, '_' and '-'", ErrInvalidChannel, name)
+	}
+
+	c := Channel(name)
+	channels.Lock()
+	channels.known[c] = struct{}{}
+	channels.Unlock()
+	return c, nil
+}
+
+// RegisteredChannels returns the known channels sorted by name.
+func RegisteredChannels() []Channel {
+	channels.RLock()
+	defer channels.RUnlock()
+
+	known := make([]Channel, 0, len(channels.known))
+	for c := range channels.known {
+		known = append(known, c)
+	}
+	sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+	return known
+}
+
 // String returns the string representation of the channel.
 func (c Channel) String() string {
 	return string(c)
 }
 
-// IsValid checks if the channel is valid.
+// IsValid checks if the channel is a built-in or registered channel.
 func (c Channel) IsValid() bool {
-	switch c {
-	case ChannelTelegram, ChannelDiscord, ChannelWeb:
-		return true
-	default:
-		return false
-	}
+	channels.RLock()
+	defer channels.RUnlock()
+	_, ok := channels.known[c]
+	return ok
 }
 
 // IsTelegram returns true if the channel is Telegram.
@@ -56,6 +112,11 @@ func (c Channel) IsWeb() bool {
 	return c == ChannelWeb
 }
 
+// IsSystem returns true if the channel is the internal system channel.
+func (c Channel) IsSystem() bool {
+	return c == ChannelSystem
+}
+
 // Equals checks if the channel equals another channel.
 func (c Channel) Equals(other Channel) bool {
 	return c == other
@@ -84,6 +145,17 @@ func (c *Channel) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (c Channel) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty Channel; other values must be valid.
+func (c *Channel) Scan(src any) error {
+	return scanValue(c, src, "channel", NewChannel)
+}
+
 // NewChannel creates a new Channel from a string.
 // Returns an error if the string is not a valid channel.
 func NewChannel(channel string) (Channel, error) {
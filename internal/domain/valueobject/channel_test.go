@@ -2,6 +2,7 @@ package valueobject
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -145,6 +146,11 @@ func TestChannel_IsValid(t *testing.T) {
 			c:    ChannelWeb,
 			want: true,
 		},
+		{
+			name: "valid system",
+			c:    ChannelSystem,
+			want: true,
+		},
 		{
 			name: "invalid",
 			c:    Channel("invalid"),
@@ -166,6 +172,40 @@ func TestChannel_IsValid(t *testing.T) {
 	}
 }
 
+func TestRegisterChannel(t *testing.T) {
+	if _, err := NewChannel("matrix"); err == nil {
+		t.Fatal("Expected matrix to be unknown before it is registered")
+	}
+
+	c, err := RegisterChannel("matrix")
+	if err != nil || c != Channel("matrix") {
+		t.Fatalf("RegisterChannel() = %v, %v", c, err)
+	}
+	if got, err := NewChannel("matrix"); err != nil || got != c {
+		t.Errorf("Expected the registered channel to be valid, got %v, %v", got, err)
+	}
+	if _, err := RegisterChannel("matrix"); err != nil {
+		t.Errorf("Expected registering a channel again to succeed, got %v", err)
+	}
+
+	var found bool
+	for _, known := range RegisteredChannels() {
+		found = found || known == c
+	}
+	if !found {
+		t.Errorf("Expected matrix among the registered channels, got %v", RegisteredChannels())
+	}
+
+	for _, name := range []string{"", "Slack", "my channel", "-web"} {
+		if _, err := RegisterChannel(name); err == nil {
+			t.Errorf("Expected an error registering %q", name)
+		}
+	}
+	if _, err := NewChannel("Slack"); !errors.Is(err, ErrInvalidChannel) {
+		t.Errorf("Expected an invalid name to stay unknown, got %v", err)
+	}
+}
+
 func TestChannel_IsTelegram(t *testing.T) {
 	if !ChannelTelegram.IsTelegram() {
 		t.Error("ChannelTelegram.IsTelegram() returned false")
@@ -193,6 +233,15 @@ func TestChannel_IsWeb(t *testing.T) {
 	}
 }
 
+func TestChannel_IsSystem(t *testing.T) {
+	if !ChannelSystem.IsSystem() {
+		t.Error("ChannelSystem.IsSystem() returned false")
+	}
+	if ChannelWeb.IsSystem() {
+		t.Error("ChannelWeb.IsSystem() returned true")
+	}
+}
+
 func TestChannel_Equals(t *testing.T) {
 	if !ChannelTelegram.Equals(ChannelTelegram) {
 		t.Error("ChannelTelegram.Equals(ChannelTelegram) returned false")
@@ -1,12 +1,12 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -14,53 +14,16 @@ var (
 	ErrInvalidCronExpression = errors.New("invalid cron expression")
 	// ErrEmptyCronExpression is returned when an empty cron expression is provided.
 	ErrEmptyCronExpression = errors.New("cron expression cannot be empty")
-	// cronRegex is the regex pattern for cron expressions.
-	// Format: MIN HOUR DAY MONTH WEEKDAY (e.g., "0 * * * *", "*/5 * * * *")
-	// Supports: minute (0-59), hour (0-23), day (1-31), month (1-12), weekday (0-6)
-	cronRegex = regexp.MustCompile(`^(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)$`)
 )
 
-// validateCronPart validates a single part of a cron expression.
-func validateCronPart(part string, min, max int) bool {
-	if part == "*" {
-		return true
-	}
-	if strings.HasPrefix(part, "*/") {
-		// Step format: */n
-		n, err := strconv.Atoi(part[2:])
-		return err == nil && n > 0
-	}
-	if strings.Contains(part, "-") {
-		// Range format: a-b
-		rangeParts := strings.Split(part, "-")
-		if len(rangeParts) != 2 {
-			return false
-		}
-		start, err1 := strconv.Atoi(rangeParts[0])
-		end, err2 := strconv.Atoi(rangeParts[1])
-		if err1 != nil || err2 != nil {
-			return false
-		}
-		return start >= min && start <= max && end >= min && end <= max && start <= end
-	}
-	if strings.Contains(part, ",") {
-		// List format: a,b,c
-		listParts := strings.Split(part, ",")
-		for _, p := range listParts {
-			num, err := strconv.Atoi(p)
-			if err != nil || num < min || num > max {
-				return false
-			}
-		}
-		return true
-	}
-	// Single number
-	num, err := strconv.Atoi(part)
-	return err == nil && num >= min && num <= max
-}
+// cronTimezonePrefix is the optional prefix that pins a cron expression
+// to a timezone (e.g., "CRON_TZ=Europe/Moscow 0 9 * * *").
+const cronTimezonePrefix = "CRON_TZ="
 
 // CronExpression represents a cron expression for scheduling.
-// It follows the standard cron format: MINUTE HOUR DAY MONTH WEEKDAY
+// It follows the standard cron format: MINUTE HOUR DAY MONTH WEEKDAY, optionally with a
+// leading SECOND field or as an alias such as "@daily" (see ParseCronSchedule), and
+// optionally prefixed with "CRON_TZ=<IANA timezone>".
 type CronExpression string
 
 // String returns the string representation of the cron expression.
@@ -73,26 +36,86 @@ func (c CronExpression) IsEmpty() bool {
 	return string(c) == ""
 }
 
-// IsValid checks if the cron expression is valid (not empty and matches cron pattern).
+// IsValid checks if the cron expression is valid (not empty and parses).
 func (c CronExpression) IsValid() bool {
+	_, err := c.Parse()
+	return err == nil
+}
+
+// Parse parses the expression into a schedule; the "CRON_TZ=" prefix is not part of the
+// schedule, see Timezone.
+//
+// Returns:
+//   - *CronSchedule: Parsed schedule
+//   - error: ErrEmptyCronExpression, or an error wrapping ErrInvalidCronExpression that
+//     describes the problem
+func (c CronExpression) Parse() (*CronSchedule, error) {
 	if c.IsEmpty() {
-		return false
+		return nil, ErrEmptyCronExpression
+	}
+	if tz, ok := c.timezonePrefix(); ok && !tz.IsValid() {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidCronExpression, tz)
+	}
+	spec, err := ParseCronSchedule(c.Fields())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCronExpression, err)
+	}
+	return spec, nil
+}
+
+// Next returns the first activation time strictly after the given time, evaluated in the
+// timezone of the "CRON_TZ=" prefix, or else in the location of after.
+// Returns the zero time if the expression is invalid or never fires again.
+func (c CronExpression) Next(after time.Time) time.Time {
+	spec, err := c.Parse()
+	if err != nil {
+		return time.Time{}
+	}
+	if tz := c.Timezone(); !tz.IsEmpty() {
+		after = after.In(tz.Location())
+	}
+	return spec.Next(after)
+}
+
+// Describe returns the expression as English text, e.g. "At 09:00 on Monday through Friday",
+// followed by the timezone of the "CRON_TZ=" prefix in parentheses.
+// Returns an empty string if the expression is invalid.
+func (c CronExpression) Describe() string {
+	spec, err := c.Parse()
+	if err != nil {
+		return ""
 	}
-	matches := cronRegex.FindStringSubmatch(string(c))
-	if matches == nil {
-		return false
+	text := spec.Describe()
+	if tz := c.Timezone(); !tz.IsEmpty() {
+		text += " (" + tz.String() + ")"
 	}
-	// Validate each part with its range
-	// matches[1] = minute (0-59)
-	// matches[2] = hour (0-23)
-	// matches[3] = day (1-31)
-	// matches[4] = month (1-12)
-	// matches[5] = weekday (0-6)
-	return validateCronPart(matches[1], 0, 59) &&
-		validateCronPart(matches[2], 0, 23) &&
-		validateCronPart(matches[3], 1, 31) &&
-		validateCronPart(matches[4], 1, 12) &&
-		validateCronPart(matches[5], 0, 6)
+	return text
+}
+
+// Fields returns the cron fields without the optional timezone prefix.
+func (c CronExpression) Fields() string {
+	if _, ok := c.timezonePrefix(); !ok {
+		return string(c)
+	}
+	_, fields, _ := strings.Cut(strings.TrimSpace(string(c)), " ")
+	return strings.TrimSpace(fields)
+}
+
+// Timezone returns the timezone pinned by the "CRON_TZ=" prefix.
+// Returns an empty Timezone if the expression has no prefix.
+func (c CronExpression) Timezone() Timezone {
+	tz, _ := c.timezonePrefix()
+	return tz
+}
+
+// timezonePrefix extracts the "CRON_TZ=" prefix if present.
+func (c CronExpression) timezonePrefix() (Timezone, bool) {
+	s := strings.TrimSpace(string(c))
+	if !strings.HasPrefix(s, cronTimezonePrefix) {
+		return "", false
+	}
+	name, _, _ := strings.Cut(strings.TrimPrefix(s, cronTimezonePrefix), " ")
+	return Timezone(name), true
 }
 
 // MarshalJSON implements json.Marshaler interface.
@@ -110,22 +133,32 @@ func (c *CronExpression) UnmarshalJSON(data []byte) error {
 		return ErrEmptyCronExpression
 	}
 	expr := CronExpression(str)
-	if !expr.IsValid() {
-		return fmt.Errorf("%w: %s", ErrInvalidCronExpression, str)
+	if _, err := expr.Parse(); err != nil {
+		*c = "" // Reset to empty on error
+		return err
 	}
 	*c = expr
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (c CronExpression) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL and the empty string, stored by one-shot schedules, scan as the empty CronExpression.
+func (c *CronExpression) Scan(src any) error {
+	return scanValue(c, src, "cron expression", scanCronExpression)
+}
+
 // NewCronExpression creates a new CronExpression from a string.
-// Returns an error if the string is not a valid cron expression.
+// Returns an error if the string is not a valid cron expression; it wraps
+// ErrInvalidCronExpression and describes the problem, e.g. "minute value 60 out of range".
 func NewCronExpression(expr string) (CronExpression, error) {
-	if expr == "" {
-		return "", ErrEmptyCronExpression
-	}
 	c := CronExpression(expr)
-	if !c.IsValid() {
-		return "", ErrInvalidCronExpression
+	if _, err := c.Parse(); err != nil {
+		return "", err
 	}
 	return c, nil
 }
@@ -139,3 +172,12 @@ func MustNewCronExpression(expr string) CronExpression {
 	}
 	return c
 }
+
+// scanCronExpression parses a stored cron expression, keeping the empty expression of
+// one-shot schedules.
+func scanCronExpression(expr string) (CronExpression, error) {
+	if expr == "" {
+		return "", nil
+	}
+	return NewCronExpression(expr)
+}
@@ -2,7 +2,9 @@ package valueobject
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestNewCronExpression(t *testing.T) {
@@ -54,9 +56,39 @@ func TestNewCronExpression(t *testing.T) {
 			want:    "",
 			wantErr: true,
 		},
+		{
+			name:    "valid with seconds",
+			expr:    "30 0 * * * *",
+			want:    CronExpression("30 0 * * * *"),
+			wantErr: false,
+		},
+		{
+			name:    "valid alias",
+			expr:    "@daily",
+			want:    CronExpression("@daily"),
+			wantErr: false,
+		},
+		{
+			name:    "valid names",
+			expr:    "0 9 * jan-mar MON-FRI",
+			want:    CronExpression("0 9 * jan-mar MON-FRI"),
+			wantErr: false,
+		},
 		{
 			name:    "invalid - too many parts",
-			expr:    "0 * * * * *",
+			expr:    "0 0 * * * * *",
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "invalid - unknown alias",
+			expr:    "@sometimes",
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "invalid - reversed range",
+			expr:    "0 17-9 * * *",
 			want:    "",
 			wantErr: true,
 		},
@@ -174,6 +206,21 @@ func TestCronExpression_IsValid(t *testing.T) {
 			c:    CronExpression("*/5 * * * *"),
 			want: true,
 		},
+		{
+			name: "valid with timezone prefix",
+			c:    CronExpression("CRON_TZ=Europe/Moscow 0 9 * * *"),
+			want: true,
+		},
+		{
+			name: "invalid timezone prefix",
+			c:    CronExpression("CRON_TZ=Mars/Olympus 0 9 * * *"),
+			want: false,
+		},
+		{
+			name: "invalid - timezone prefix only",
+			c:    CronExpression("CRON_TZ=UTC"),
+			want: false,
+		},
 		{
 			name: "invalid - too few parts",
 			c:    CronExpression("0 * * *"),
@@ -195,6 +242,109 @@ func TestCronExpression_IsValid(t *testing.T) {
 	}
 }
 
+func TestCronExpression_Timezone(t *testing.T) {
+	tests := []struct {
+		name       string
+		c          CronExpression
+		wantTZ     Timezone
+		wantFields string
+	}{
+		{
+			name:       "without prefix",
+			c:          CronExpression("0 9 * * *"),
+			wantTZ:     "",
+			wantFields: "0 9 * * *",
+		},
+		{
+			name:       "with prefix",
+			c:          CronExpression("CRON_TZ=Asia/Tokyo 30 8 * * 1-5"),
+			wantTZ:     Timezone("Asia/Tokyo"),
+			wantFields: "30 8 * * 1-5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Timezone(); got != tt.wantTZ {
+				t.Errorf("CronExpression.Timezone() = %v, want %v", got, tt.wantTZ)
+			}
+			if got := tt.c.Fields(); got != tt.wantFields {
+				t.Errorf("CronExpression.Fields() = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestCronExpression_Next(t *testing.T) {
+	after := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name string
+		c    CronExpression
+		want time.Time
+	}{
+		{
+			name: "in the location of after",
+			c:    CronExpression("0 12 * * *"),
+			want: time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "in the timezone of the prefix",
+			c:    CronExpression("CRON_TZ=Asia/Tokyo 0 9 * * *"),
+			want: time.Date(2024, time.January, 16, 0, 0, 0, 0, time.UTC), // 09:00 in Tokyo
+		},
+		{
+			name: "invalid expression",
+			c:    CronExpression("0 25 * * *"),
+			want: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Next(after); !got.Equal(tt.want) {
+				t.Errorf("CronExpression.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronExpression_Describe(t *testing.T) {
+	tests := []struct {
+		c    CronExpression
+		want string
+	}{
+		{CronExpression("*/5 * * * *"), "Every 5 minutes"},
+		{CronExpression("CRON_TZ=Europe/Moscow 0 9 * * 1-5"), "At 09:00 on Monday through Friday (Europe/Moscow)"},
+		{CronExpression("invalid"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.c.String(), func(t *testing.T) {
+			if got := tt.c.Describe(); got != tt.want {
+				t.Errorf("CronExpression.Describe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCronExpression_Error(t *testing.T) {
+	_, err := NewCronExpression("60 * * * *")
+	if !errors.Is(err, ErrInvalidCronExpression) {
+		t.Fatalf("NewCronExpression() error = %v, want ErrInvalidCronExpression", err)
+	}
+	if want := "invalid cron expression: minute value 60 out of range [0-59]"; err.Error() != want {
+		t.Errorf("NewCronExpression() error = %q, want %q", err.Error(), want)
+	}
+
+	if _, err := NewCronExpression("CRON_TZ=Mars/Olympus 0 9 * * *"); !errors.Is(err, ErrInvalidCronExpression) {
+		t.Errorf("NewCronExpression() error = %v, want ErrInvalidCronExpression for an unknown timezone", err)
+	}
+	if _, err := NewCronExpression(""); !errors.Is(err, ErrEmptyCronExpression) {
+		t.Errorf("NewCronExpression() error = %v, want ErrEmptyCronExpression", err)
+	}
+}
+
 func TestCronExpression_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		name string
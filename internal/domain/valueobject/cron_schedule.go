@@ -0,0 +1,492 @@
+package valueobject
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCronSearchYears bounds the search for the next activation time so that
+// impossible expressions (e.g. "0 0 31 2 *") do not loop forever.
+const maxCronSearchYears = 5
+
+// cronField describes the allowed range of a single cron field
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string // Names of the values from min on, e.g. "jan" for month 1
+
+	// last is the end of "*" and "a/n" if below max
+	last int
+}
+
+// end returns the last value of "*" and "a/n"
+func (f cronField) end() int {
+	if f.last > 0 {
+		return f.last
+	}
+	return f.max
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12,
+		names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	// Day of week 7 is accepted as Sunday and stored as 0
+	cronDow = cronField{name: "day of week", min: 0, max: 7, last: 6,
+		names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// cronAliases are the predefined schedules accepted instead of the fields
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronSchedule is a parsed cron expression.
+// Each field is stored as a bit set of allowed values.
+type CronSchedule struct {
+	second uint64
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domAny and dowAny record whether the day fields were "*".
+	// Following cron semantics, when both day fields are restricted
+	// a time matches if either of them matches.
+	domAny bool
+	dowAny bool
+
+	// fields are the fields with names replaced by numbers, used by Describe;
+	// the seconds field is empty for 5-field expressions
+	fields [6]string
+}
+
+// ParseCronSchedule parses a cron expression without the "CRON_TZ=" prefix.
+//
+// The expression has 5 fields (minute hour day-of-month month day-of-week), 6 fields
+// with a leading seconds field, or is one of the aliases @yearly (@annually), @monthly,
+// @weekly, @daily (@midnight) and @hourly.
+// Supported syntax per field: "*", "*/n", "a", "a-b", "a-b/n", "a/n" and
+// comma-separated lists of these forms. Months and days of week also accept
+// three-letter English names ("jan", "mon"), and day of week 7 is Sunday.
+//
+// Parameters:
+//   - expr: Cron expression to parse
+//
+// Returns:
+//   - *CronSchedule: Parsed schedule
+//   - error: Error if the expression is malformed
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		fields, ok := cronAliases[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron alias %q", expr)
+		}
+		expr = fields
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{""}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron expression must have 5 or 6 fields, got %d", len(fields))
+	}
+
+	spec := &CronSchedule{second: 1}
+	targets := []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&spec.second, cronSecond},
+		{&spec.minute, cronMinute},
+		{&spec.hour, cronHour},
+		{&spec.dom, cronDom},
+		{&spec.month, cronMonth},
+		{&spec.dow, cronDow},
+	}
+	for i, target := range targets {
+		if fields[i] == "" {
+			continue
+		}
+		normalized, bits, err := parseCronField(fields[i], target.field)
+		if err != nil {
+			return nil, err
+		}
+		*target.bits = bits
+		spec.fields[i] = normalized
+	}
+
+	// Sunday may be given as 0 or 7
+	if has(spec.dow, 7) {
+		spec.dow = spec.dow&^(1<<7) | 1
+	}
+	spec.domAny = fields[3] == "*"
+	spec.dowAny = fields[5] == "*"
+
+	return spec, nil
+}
+
+// parseCronField parses a single cron field into a bit set and returns it with
+// names replaced by numbers
+func parseCronField(field string, bounds cronField) (string, uint64, error) {
+	var bits uint64
+	parts := strings.Split(field, ",")
+
+	for i, part := range parts {
+		if part == "" {
+			return "", 0, fmt.Errorf("invalid %s field: %q", bounds.name, field)
+		}
+
+		rangePart, step, stepText := part, 1, ""
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart, stepText = part[:idx], part[idx:]
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return "", 0, fmt.Errorf("invalid step in %s field: %q", bounds.name, part)
+			}
+			step = n
+		}
+
+		start, end := bounds.min, bounds.end()
+		switch {
+		case rangePart == "*":
+			// full range
+		case strings.Contains(rangePart, "-"):
+			lo, hi, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(lo, bounds); err != nil {
+				return "", 0, err
+			}
+			if end, err = parseCronValue(hi, bounds); err != nil {
+				return "", 0, err
+			}
+			if start > end {
+				return "", 0, fmt.Errorf("invalid range in %s field: %q", bounds.name, part)
+			}
+			rangePart = strconv.Itoa(start) + "-" + strconv.Itoa(end)
+		default:
+			v, err := parseCronValue(rangePart, bounds)
+			if err != nil {
+				return "", 0, err
+			}
+			start = v
+			// "a/n" means starting at a through the end of the range
+			if step == 1 {
+				end = v
+			}
+			rangePart = strconv.Itoa(v)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+		parts[i] = rangePart + stepText
+	}
+
+	return strings.Join(parts, ","), bits, nil
+}
+
+// parseCronValue parses a single numeric or named value and checks its bounds
+func parseCronValue(s string, bounds cronField) (int, error) {
+	for i, name := range bounds.names {
+		if strings.EqualFold(s, name) {
+			return bounds.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %q", bounds.name, s)
+	}
+	if v < bounds.min || v > bounds.max {
+		return 0, fmt.Errorf("%s value %d out of range [%d-%d]", bounds.name, v, bounds.min, bounds.max)
+	}
+	return v, nil
+}
+
+// HasSeconds reports whether the expression has a seconds field
+func (s *CronSchedule) HasSeconds() bool {
+	return s.fields[0] != ""
+}
+
+// Next returns the first activation time strictly after the given time.
+// The result keeps the location of the input time.
+// Returns the zero time if no activation exists within the search window.
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Second).Add(time.Second)
+	if !s.HasSeconds() {
+		t = after.Truncate(time.Minute).Add(time.Minute)
+	}
+	limit := t.AddDate(maxCronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if !has(s.second, t.Second()) {
+			t = t.Add(time.Second)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches reports whether the day of the given time satisfies the
+// day-of-month and day-of-week fields
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// has reports whether the bit for v is set
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+// maxCronDescribedTimes is the number of times of day listed by Describe before it
+// falls back to describing the fields one by one
+const maxCronDescribedTimes = 6
+
+// Describe returns the schedule as English text, e.g. "At 09:00 on Monday through Friday"
+// for "0 9 * * 1-5" or "Every 15 minutes" for "*/15 * * * *".
+func (s *CronSchedule) Describe() string {
+	second, minute, hour, dom, month, dow := s.fields[0], s.fields[1], s.fields[2], s.fields[3], s.fields[4], s.fields[5]
+
+	text, atTimes := describeCronTimes(second, minute, hour)
+	if !atTimes {
+		text = describeCronClock(second, minute, hour)
+	}
+
+	switch days := describeCronDays(dom, dow); {
+	case days != "":
+		text += " " + days
+	case atTimes:
+		text += " every day"
+	}
+	if month != "*" {
+		text += " in " + joinCronItems(describeCronItems(month, cronMonthName, "month", "months"))
+	}
+
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// describeCronTimes describes a schedule that fires at a few fixed times of day,
+// e.g. "at 09:00 and 17:30". Returns false for other schedules.
+func describeCronTimes(second, minute, hour string) (string, bool) {
+	sec := 0
+	if second != "" {
+		var err error
+		if sec, err = strconv.Atoi(second); err != nil {
+			return "", false
+		}
+	}
+	min, err := strconv.Atoi(minute)
+	if err != nil {
+		return "", false
+	}
+	hours := strings.Split(hour, ",")
+	if len(hours) > maxCronDescribedTimes {
+		return "", false
+	}
+
+	times := make([]string, 0, len(hours))
+	for _, h := range hours {
+		v, err := strconv.Atoi(h)
+		if err != nil {
+			return "", false
+		}
+		t := fmt.Sprintf("%02d:%02d", v, min)
+		if sec != 0 {
+			t += fmt.Sprintf(":%02d", sec)
+		}
+		times = append(times, t)
+	}
+	return "at " + joinCronItems(times), true
+}
+
+// describeCronClock describes the second, minute and hour fields one by one,
+// e.g. "every 15 minutes during hours 9 through 17" or "at minute 0 past every 2 hours"
+func describeCronClock(second, minute, hour string) string {
+	var text string
+	// attach adds the phrase of a larger unit to the text
+	attach := func(phrase string) {
+		switch {
+		case text == "":
+			text = phrase
+		case strings.HasPrefix(text, "every") && strings.HasPrefix(phrase, "every"):
+			text += ", " + phrase
+		case strings.HasPrefix(text, "every"):
+			text += " during " + strings.TrimPrefix(phrase, "at ")
+		default:
+			text += " past " + strings.TrimPrefix(phrase, "at ")
+		}
+	}
+
+	if second != "" && second != "0" {
+		attach(describeCronUnit(second, "second", "seconds"))
+	}
+	switch {
+	case minute != "*":
+		attach(describeCronUnit(minute, "minute", "minutes"))
+	case text == "":
+		text = "every minute"
+	case !strings.HasPrefix(text, "every"):
+		text += " past every minute"
+	}
+	switch {
+	case hour != "*":
+		attach(describeCronUnit(hour, "hour", "hours"))
+	case minute != "*" && !strings.HasPrefix(text, "every"):
+		text += " past every hour"
+	}
+	return text
+}
+
+// describeCronDays describes the day-of-month and day-of-week fields,
+// or returns "" if the schedule fires every day
+func describeCronDays(dom, dow string) string {
+	var days []string
+	if dom != "*" {
+		days = append(days, describeCronUnitOf(dom, strconv.Itoa, "day", "days")+" of the month")
+	}
+	if dow != "*" {
+		items := describeCronItems(dow, cronWeekdayName, "day of the week", "days of the week")
+		if cronPlain(dow) {
+			days = append(days, "on "+joinCronItems(items))
+		} else {
+			days = append(days, joinCronItems(items))
+		}
+	}
+	return strings.Join(days, " or ")
+}
+
+// describeCronUnit describes a numeric field, e.g. "at minute 30", "at hours 9 through 17"
+// or "every 15 minutes"
+func describeCronUnit(field, unit, units string) string {
+	return describeCronUnitOf(field, strconv.Itoa, unit, units)
+}
+
+// describeCronUnitOf describes a field whose values are formatted with format
+func describeCronUnitOf(field string, format func(int) string, unit, units string) string {
+	if field == "*" {
+		return "every " + unit
+	}
+	items := describeCronItems(field, format, unit, units)
+	if !cronPlain(field) {
+		return joinCronItems(items)
+	}
+	prefix := "at "
+	if unit == "day" {
+		prefix = "on "
+	}
+	if len(items) == 1 && !strings.Contains(field, "-") {
+		return prefix + unit + " " + items[0]
+	}
+	return prefix + units + " " + joinCronItems(items)
+}
+
+// describeCronItems describes the comma-separated items of a field
+func describeCronItems(field string, format func(int) string, unit, units string) []string {
+	parts := strings.Split(field, ",")
+	items := make([]string, 0, len(parts))
+	for _, part := range parts {
+		rangePart, step, hasStep := strings.Cut(part, "/")
+
+		var text string
+		if lo, hi, ok := strings.Cut(rangePart, "-"); ok {
+			text = cronFormat(lo, format) + " through " + cronFormat(hi, format)
+		} else if rangePart != "*" {
+			text = cronFormat(rangePart, format)
+		}
+
+		switch {
+		case !hasStep:
+		case step == "1":
+			text = "every " + unit + cronFrom(text, rangePart)
+		default:
+			text = "every " + step + " " + units + cronFrom(text, rangePart)
+		}
+		items = append(items, text)
+	}
+	return items
+}
+
+// cronFrom returns the range a step applies to, e.g. " from 9 through 17"
+func cronFrom(text, rangePart string) string {
+	switch {
+	case rangePart == "*":
+		return ""
+	case strings.Contains(rangePart, "-"):
+		return " from " + text
+	default:
+		return " starting at " + text
+	}
+}
+
+// cronPlain reports whether a field lists values and ranges without steps
+func cronPlain(field string) bool {
+	return !strings.Contains(field, "/")
+}
+
+// cronFormat formats a value of a normalized field
+func cronFormat(value string, format func(int) string) string {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return value
+	}
+	return format(v)
+}
+
+// cronMonthName returns the English name of a month
+func cronMonthName(v int) string {
+	return time.Month(v).String()
+}
+
+// cronWeekdayName returns the English name of a day of week, with 7 as Sunday
+func cronWeekdayName(v int) string {
+	return time.Weekday(v % 7).String()
+}
+
+// joinCronItems joins items as English text, e.g. "a, b and c"
+func joinCronItems(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,224 @@
+package valueobject
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronSchedule_Valid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"every minute", "* * * * *"},
+		{"every 5 minutes", "*/5 * * * *"},
+		{"daily at 9", "0 9 * * *"},
+		{"weekdays", "30 8 * * 1-5"},
+		{"list", "0 9,12,18 * * *"},
+		{"range with step", "0 8-18/2 * * *"},
+		{"value with step", "5/15 * * * *"},
+		{"first of month", "0 0 1 * *"},
+		{"with seconds", "*/10 * * * * *"},
+		{"alias", "@hourly"},
+		{"alias in upper case", "@DAILY"},
+		{"month and day names", "0 9 * Jan,Jul mon-fri"},
+		{"sunday as 7", "0 0 * * 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseCronSchedule(tt.expr)
+			require.NoError(t, err)
+			assert.NotNil(t, spec)
+		})
+	}
+}
+
+func TestParseCronSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * * *"},
+		{"second out of range", "60 * * * * *"},
+		{"unknown alias", "@often"},
+		{"unknown name", "0 0 * * someday"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 0 0 * *"},
+		{"month out of range", "0 0 1 13 *"},
+		{"day of week out of range", "0 0 * * 8"},
+		{"reversed range", "0 10-5 * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"not a number", "a * * * *"},
+		{"empty list item", "1,,2 * * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCronSchedule(tt.expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	base := time.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			from: base,
+			want: time.Date(2024, time.January, 15, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name: "every 15 minutes",
+			expr: "*/15 * * * *",
+			from: base,
+			want: time.Date(2024, time.January, 15, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "daily at 9 rolls to next day",
+			expr: "0 9 * * *",
+			from: base,
+			want: time.Date(2024, time.January, 16, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after exact match",
+			expr: "30 10 * * *",
+			from: time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC),
+			want: time.Date(2024, time.January, 16, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "weekdays skips weekend",
+			expr: "0 9 * * 1-5",
+			from: time.Date(2024, time.January, 19, 10, 0, 0, 0, time.UTC), // Friday
+			want: time.Date(2024, time.January, 22, 9, 0, 0, 0, time.UTC),  // Monday
+		},
+		{
+			name: "first of month rolls over year",
+			expr: "0 0 1 * *",
+			from: time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 20 * 0",
+			from: base,
+			want: time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC), // 20th comes before Sunday 21st
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: base,
+			want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "every 10 seconds",
+			expr: "*/10 * * * * *",
+			from: base,
+			want: time.Date(2024, time.January, 15, 10, 30, 50, 0, time.UTC),
+		},
+		{
+			name: "seconds roll over to next minute",
+			expr: "15 * * * * *",
+			from: base,
+			want: time.Date(2024, time.January, 15, 10, 31, 15, 0, time.UTC),
+		},
+		{
+			name: "weekly alias",
+			expr: "@weekly",
+			from: base,
+			want: time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC), // Sunday
+		},
+		{
+			name: "sunday as 7",
+			expr: "0 0 * * 7",
+			from: base,
+			want: time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day names",
+			expr: "0 9 * * sat",
+			from: base,
+			want: time.Date(2024, time.January, 20, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "month names",
+			expr: "0 0 1 mar *",
+			from: base,
+			want: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseCronSchedule(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, spec.Next(tt.from))
+		})
+	}
+}
+
+func TestCronSchedule_Next_Impossible(t *testing.T) {
+	spec, err := ParseCronSchedule("0 0 31 2 *")
+	require.NoError(t, err)
+
+	assert.True(t, spec.Next(time.Now()).IsZero())
+}
+
+func TestCronSchedule_Next_KeepsLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	spec, err := ParseCronSchedule("0 9 * * *")
+	require.NoError(t, err)
+
+	next := spec.Next(time.Date(2024, time.January, 15, 8, 0, 0, 0, loc))
+
+	assert.Equal(t, loc, next.Location())
+	assert.Equal(t, time.Date(2024, time.January, 15, 9, 0, 0, 0, loc), next)
+}
+
+func TestCronSchedule_Describe(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"* * * * *", "Every minute"},
+		{"*/15 * * * *", "Every 15 minutes"},
+		{"30 * * * *", "At minute 30 past every hour"},
+		{"0 9 * * *", "At 09:00 every day"},
+		{"@daily", "At 00:00 every day"},
+		{"30 8 * * 1-5", "At 08:30 on Monday through Friday"},
+		{"0 9,12,18 * * *", "At 09:00, 12:00 and 18:00 every day"},
+		{"0 9 * * mon,wed,fri", "At 09:00 on Monday, Wednesday and Friday"},
+		{"0 0 1 * *", "At 00:00 on day 1 of the month"},
+		{"0 0 1,15 * *", "At 00:00 on days 1 and 15 of the month"},
+		{"0 0 20 * 0", "At 00:00 on day 20 of the month or on Sunday"},
+		{"0 0 1 1 *", "At 00:00 on day 1 of the month in January"},
+		{"0 12 * jun-aug *", "At 12:00 every day in June through August"},
+		{"*/10 9-17 * * *", "Every 10 minutes during hours 9 through 17"},
+		{"0 8-18/2 * * *", "At minute 0 past every 2 hours from 8 through 18"},
+		{"*/30 * * * * *", "Every 30 seconds"},
+		{"30 0 9 * * *", "At 09:00:30 every day"},
+		{"5/15 * * * *", "Every 15 minutes starting at 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			spec, err := ParseCronSchedule(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, spec.Describe())
+		})
+	}
+}
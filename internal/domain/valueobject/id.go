@@ -1,11 +1,15 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
+
+	"github.com/google/uuid"
 )
 
 var (
@@ -66,6 +70,17 @@ func (id *ID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id ID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty ID; other values must be valid.
+func (id *ID) Scan(src any) error {
+	return scanValue(id, src, "id", NewID)
+}
+
 // NewID creates a new ID from a string.
 // Returns an error if the string is not a valid ID.
 func NewID(idStr string) (ID, error) {
@@ -88,18 +103,53 @@ func MustNewID(idStr string) ID {
 	return id
 }
 
+// IDGenerator returns a new unique identifier
+type IDGenerator func() string
+
+var (
+	idGeneratorMu sync.RWMutex
+	// idGenerator generates the IDs of new entities
+	idGenerator IDGenerator = NewUUIDv7
+)
+
+// NewUUIDv7 returns a new UUID version 7 (RFC 9562). Its first 48 bits are the Unix time in
+// milliseconds, so IDs generated later sort after earlier ones, which keeps index inserts local.
+func NewUUIDv7() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// SetIDGenerator replaces the generator of new IDs, e.g. with a deterministic one in tests,
+// and returns a function restoring the previous generator.
+// A nil generator restores the default UUIDv7 generator.
+func SetIDGenerator(g IDGenerator) (restore func()) {
+	if g == nil {
+		g = NewUUIDv7
+	}
+
+	idGeneratorMu.Lock()
+	previous := idGenerator
+	idGenerator = g
+	idGeneratorMu.Unlock()
+
+	return func() {
+		idGeneratorMu.Lock()
+		idGenerator = previous
+		idGeneratorMu.Unlock()
+	}
+}
+
 // GenerateID generates a unique ID using the provided generator function.
-// If generator is nil, uses a default generator (simple random string).
+// If generator is nil, the generator set with SetIDGenerator is used, by default NewUUIDv7.
 func GenerateID(generator func() string) ID {
 	if generator != nil {
 		return ID(generator())
 	}
-	// Default simple ID generator
-	return ID(fmt.Sprintf("id_%d", len(idStr)))
-}
 
-// idStr is a placeholder for length-based generation.
-var idStr = make([]string, 1000)
+	idGeneratorMu.RLock()
+	g := idGenerator
+	idGeneratorMu.RUnlock()
+	return ID(g())
+}
 
 // StringToIDType converts a string to a specific ID type based on the type name.
 // Useful for dynamic ID creation from string type names.
@@ -117,6 +167,8 @@ func StringToIDType(typeName, idStr string) (interface{}, error) {
 		return NewSkillID(idStr)
 	case "scheduleid", "schedule":
 		return NewScheduleID(idStr)
+	case "schedulerunid", "schedulerun":
+		return NewScheduleRunID(idStr)
 	case "logid", "log":
 		return NewLogID(idStr)
 	default:
@@ -2,8 +2,10 @@ package valueobject
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -132,30 +134,51 @@ func TestMustNewID(t *testing.T) {
 }
 
 func TestGenerateID(t *testing.T) {
-	tests := []struct {
-		name        string
-		generator   func() string
-		expectEmpty bool
-	}{
-		{
-			name:        "with custom generator",
-			generator:   func() string { return "custom-id" },
-			expectEmpty: false,
-		},
-		{
-			name:        "with nil generator",
-			generator:   nil,
-			expectEmpty: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			id := GenerateID(tt.generator)
-			assert.False(t, id.IsEmpty())
-		})
+	assert.Equal(t, ID("custom-id"), GenerateID(func() string { return "custom-id" }))
+
+	// The default generator returns UUIDv7 in creation order
+	previous := GenerateID(nil)
+	for i := 0; i < 100; i++ {
+		id := GenerateID(nil)
+		parsed, err := uuid.Parse(id.String())
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Version(7), parsed.Version())
+		assert.True(t, id.IsValid())
+		assert.Greater(t, id.String(), previous.String())
+		previous = id
 	}
 }
 
+func TestSetIDGenerator(t *testing.T) {
+	n := 0
+	restore := SetIDGenerator(func() string {
+		n++
+		return fmt.Sprintf("id-%d", n)
+	})
+
+	assert.Equal(t, ID("id-1"), GenerateID(nil))
+	assert.Equal(t, UserID("id-2"), NewGeneratedUserID())
+	assert.Equal(t, SessionID("id-3"), NewGeneratedSessionID())
+	assert.Equal(t, MessageID("id-4"), NewGeneratedMessageID())
+	assert.Equal(t, TaskID("id-5"), NewGeneratedTaskID())
+	assert.Equal(t, SkillID("id-6"), NewGeneratedSkillID())
+	assert.Equal(t, ScheduleID("id-7"), NewGeneratedScheduleID())
+	assert.Equal(t, ScheduleRunID("id-8"), NewGeneratedScheduleRunID())
+	assert.Equal(t, LogID("id-9"), NewGeneratedLogID())
+	assert.Equal(t, APIKeyID("id-10"), NewGeneratedAPIKeyID())
+
+	// A nil generator restores the default one
+	restoreDefault := SetIDGenerator(nil)
+	_, err := uuid.Parse(NewGeneratedUserID().String())
+	assert.NoError(t, err)
+	restoreDefault()
+	assert.Equal(t, UserID("id-11"), NewGeneratedUserID())
+
+	restore()
+	_, err = uuid.Parse(GenerateID(nil).String())
+	assert.NoError(t, err)
+}
+
 // Test typed ID types
 
 func TestUserID_String(t *testing.T) {
@@ -235,6 +258,14 @@ func TestNewScheduleID(t *testing.T) {
 	assert.Equal(t, ScheduleID("schedule-222"), id)
 }
 
+func TestNewScheduleRunID(t *testing.T) {
+	id, err := NewScheduleRunID("run-444")
+
+	require.NoError(t, err)
+	assert.Equal(t, ScheduleRunID("run-444"), id)
+	assert.Equal(t, "run-444", id.String())
+}
+
 func TestLogID_String(t *testing.T) {
 	id := LogID("log-333")
 	assert.Equal(t, "log-333", id.String())
@@ -260,6 +291,7 @@ func TestStringToIDType(t *testing.T) {
 		{"MessageID", "messageid", "msg-000", false},
 		{"SkillID", "skillid", "skill-111", false},
 		{"ScheduleID", "scheduleid", "schedule-222", false},
+		{"ScheduleRunID", "schedulerunid", "run-444", false},
 		{"LogID", "logid", "log-333", false},
 		{"unknown type", "unknown", "test", true},
 	}
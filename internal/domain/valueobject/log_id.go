@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -49,6 +50,17 @@ func (id *LogID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id LogID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty LogID; other values must be valid.
+func (id *LogID) Scan(src any) error {
+	return scanValue(id, src, "log id", NewLogID)
+}
+
 // NewLogID creates a new LogID from a string.
 // Returns an error if the string is not a valid ID.
 func NewLogID(idStr string) (LogID, error) {
@@ -59,6 +71,11 @@ func NewLogID(idStr string) (LogID, error) {
 	return LogID(id), nil
 }
 
+// NewGeneratedLogID generates a new unique LogID (see GenerateID).
+func NewGeneratedLogID() LogID {
+	return LogID(GenerateID(nil))
+}
+
 // MustNewLogID creates a new LogID from a string.
 // Panics if the string is not a valid ID.
 func MustNewLogID(idStr string) LogID {
@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -49,6 +50,17 @@ func (id *MessageID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id MessageID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty MessageID; other values must be valid.
+func (id *MessageID) Scan(src any) error {
+	return scanValue(id, src, "message id", NewMessageID)
+}
+
 // NewMessageID creates a new MessageID from a string.
 // Returns an error if the string is not a valid ID.
 func NewMessageID(idStr string) (MessageID, error) {
@@ -59,6 +71,11 @@ func NewMessageID(idStr string) (MessageID, error) {
 	return MessageID(id), nil
 }
 
+// NewGeneratedMessageID generates a new unique MessageID (see GenerateID).
+func NewGeneratedMessageID() MessageID {
+	return MessageID(GenerateID(nil))
+}
+
 // MustNewMessageID creates a new MessageID from a string.
 // Panics if the string is not a valid ID.
 func MustNewMessageID(idStr string) MessageID {
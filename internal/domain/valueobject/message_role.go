@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -72,6 +73,17 @@ func (r *MessageRole) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (r MessageRole) Value() (driver.Value, error) {
+	return string(r), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty MessageRole; other values must be valid.
+func (r *MessageRole) Scan(src any) error {
+	return scanValue(r, src, "message role", NewMessageRole)
+}
+
 // NewMessageRole creates a new MessageRole from a string.
 // Returns an error if the string is not a valid message role.
 func NewMessageRole(role string) (MessageRole, error) {
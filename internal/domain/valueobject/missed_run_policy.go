@@ -0,0 +1,84 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidMissedRunPolicy is returned when an invalid missed run policy is provided.
+	ErrInvalidMissedRunPolicy = errors.New("invalid missed run policy")
+)
+
+// MissedRunPolicy defines what the scheduler does with runs missed while the server was down.
+// It's a value object that ensures type safety for missed run policies.
+type MissedRunPolicy string
+
+const (
+	// MissedRunSkip ignores missed runs and waits for the next activation.
+	MissedRunSkip MissedRunPolicy = "skip"
+	// MissedRunOnce executes a single run for all missed activations.
+	MissedRunOnce MissedRunPolicy = "run_once"
+	// MissedRunAll executes every missed activation in order.
+	MissedRunAll MissedRunPolicy = "run_all"
+)
+
+// String returns the string representation of the missed run policy.
+func (p MissedRunPolicy) String() string {
+	return string(p)
+}
+
+// IsValid checks if the missed run policy is valid.
+func (p MissedRunPolicy) IsValid() bool {
+	switch p {
+	case MissedRunSkip, MissedRunOnce, MissedRunAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSkip returns true if missed runs are ignored.
+// An empty policy is treated as skip.
+func (p MissedRunPolicy) IsSkip() bool {
+	return p == MissedRunSkip || p == ""
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (p MissedRunPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(p))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (p *MissedRunPolicy) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*p = MissedRunPolicy(str)
+	if !p.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidMissedRunPolicy, str)
+	}
+	return nil
+}
+
+// NewMissedRunPolicy creates a new MissedRunPolicy from a string.
+// Returns an error if the string is not a valid missed run policy.
+func NewMissedRunPolicy(policy string) (MissedRunPolicy, error) {
+	p := MissedRunPolicy(policy)
+	if !p.IsValid() {
+		return "", ErrInvalidMissedRunPolicy
+	}
+	return p, nil
+}
+
+// MustNewMissedRunPolicy creates a new MissedRunPolicy from a string.
+// Panics if the string is not a valid missed run policy.
+func MustNewMissedRunPolicy(policy string) MissedRunPolicy {
+	p, err := NewMissedRunPolicy(policy)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
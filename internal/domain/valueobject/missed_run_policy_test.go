@@ -0,0 +1,65 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewMissedRunPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    MissedRunPolicy
+		wantErr bool
+	}{
+		{"skip", "skip", MissedRunSkip, false},
+		{"run once", "run_once", MissedRunOnce, false},
+		{"run all", "run_all", MissedRunAll, false},
+		{"empty", "", "", true},
+		{"invalid", "retry", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMissedRunPolicy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMissedRunPolicy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewMissedRunPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissedRunPolicy_IsSkip(t *testing.T) {
+	if !MissedRunSkip.IsSkip() || !MissedRunPolicy("").IsSkip() {
+		t.Error("skip and empty policies should be treated as skip")
+	}
+	if MissedRunOnce.IsSkip() || MissedRunAll.IsSkip() {
+		t.Error("run_once and run_all should not be treated as skip")
+	}
+}
+
+func TestMissedRunPolicy_JSON(t *testing.T) {
+	data, err := json.Marshal(MissedRunAll)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `"run_all"` {
+		t.Errorf("json.Marshal() = %s, want \"run_all\"", data)
+	}
+
+	var got MissedRunPolicy
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got != MissedRunAll {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, MissedRunAll)
+	}
+
+	if err := json.Unmarshal([]byte(`"sometimes"`), &got); err == nil {
+		t.Error("json.Unmarshal() expected error for invalid policy")
+	}
+}
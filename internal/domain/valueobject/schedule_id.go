@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -49,6 +50,17 @@ func (id *ScheduleID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id ScheduleID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty ScheduleID; other values must be valid.
+func (id *ScheduleID) Scan(src any) error {
+	return scanValue(id, src, "schedule id", NewScheduleID)
+}
+
 // NewScheduleID creates a new ScheduleID from a string.
 // Returns an error if the string is not a valid ID.
 func NewScheduleID(idStr string) (ScheduleID, error) {
@@ -59,6 +71,11 @@ func NewScheduleID(idStr string) (ScheduleID, error) {
 	return ScheduleID(id), nil
 }
 
+// NewGeneratedScheduleID generates a new unique ScheduleID (see GenerateID).
+func NewGeneratedScheduleID() ScheduleID {
+	return ScheduleID(GenerateID(nil))
+}
+
 // MustNewScheduleID creates a new ScheduleID from a string.
 // Panics if the string is not a valid ID.
 func MustNewScheduleID(idStr string) ScheduleID {
@@ -0,0 +1,87 @@
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ScheduleRunID represents a schedule run identifier.
+type ScheduleRunID ID
+
+// String returns the string representation of the ScheduleRunID.
+func (id ScheduleRunID) String() string {
+	return string(id)
+}
+
+// IsEmpty returns true if the ScheduleRunID is empty.
+func (id ScheduleRunID) IsEmpty() bool {
+	return string(id) == ""
+}
+
+// IsValid checks if the ScheduleRunID is valid (not empty and matches pattern).
+func (id ScheduleRunID) IsValid() bool {
+	return ID(id).IsValid()
+}
+
+// Equals checks if the ScheduleRunID equals another ScheduleRunID.
+func (id ScheduleRunID) Equals(other ScheduleRunID) bool {
+	return id == other
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (id ScheduleRunID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(id))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (id *ScheduleRunID) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		return ErrEmptyID
+	}
+	if !ScheduleRunID(str).IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidID, str)
+	}
+	*id = ScheduleRunID(str)
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+func (id ScheduleRunID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty ScheduleRunID; other values must be valid.
+func (id *ScheduleRunID) Scan(src any) error {
+	return scanValue(id, src, "schedule run id", NewScheduleRunID)
+}
+
+// NewScheduleRunID creates a new ScheduleRunID from a string.
+// Returns an error if the string is not a valid ID.
+func NewScheduleRunID(idStr string) (ScheduleRunID, error) {
+	id, err := NewID(idStr)
+	if err != nil {
+		return "", err
+	}
+	return ScheduleRunID(id), nil
+}
+
+// NewGeneratedScheduleRunID generates a new unique ScheduleRunID (see GenerateID).
+func NewGeneratedScheduleRunID() ScheduleRunID {
+	return ScheduleRunID(GenerateID(nil))
+}
+
+// MustNewScheduleRunID creates a new ScheduleRunID from a string.
+// Panics if the string is not a valid ID.
+func MustNewScheduleRunID(idStr string) ScheduleRunID {
+	id, err := NewScheduleRunID(idStr)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,87 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidScheduleType is returned when an invalid schedule type is provided.
+	ErrInvalidScheduleType = errors.New("invalid schedule type")
+)
+
+// ScheduleType represents how a schedule determines its run times.
+// It's a value object that ensures type safety for schedule types.
+type ScheduleType string
+
+const (
+	// ScheduleTypeCron represents a recurring schedule driven by a cron expression.
+	ScheduleTypeCron ScheduleType = "cron"
+	// ScheduleTypeOnce represents a one-shot schedule that runs at a fixed time and is then deleted.
+	ScheduleTypeOnce ScheduleType = "once"
+)
+
+// String returns the string representation of the schedule type.
+func (t ScheduleType) String() string {
+	return string(t)
+}
+
+// IsValid checks if the schedule type is valid.
+func (t ScheduleType) IsValid() bool {
+	switch t {
+	case ScheduleTypeCron, ScheduleTypeOnce:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsCron returns true if the schedule type is cron.
+// An empty type is treated as cron for backward compatibility.
+func (t ScheduleType) IsCron() bool {
+	return t == ScheduleTypeCron || t == ""
+}
+
+// IsOnce returns true if the schedule type is one-shot.
+func (t ScheduleType) IsOnce() bool {
+	return t == ScheduleTypeOnce
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (t ScheduleType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (t *ScheduleType) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*t = ScheduleType(str)
+	if !t.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidScheduleType, str)
+	}
+	return nil
+}
+
+// NewScheduleType creates a new ScheduleType from a string.
+// Returns an error if the string is not a valid schedule type.
+func NewScheduleType(scheduleType string) (ScheduleType, error) {
+	t := ScheduleType(scheduleType)
+	if !t.IsValid() {
+		return "", ErrInvalidScheduleType
+	}
+	return t, nil
+}
+
+// MustNewScheduleType creates a new ScheduleType from a string.
+// Panics if the string is not a valid schedule type.
+func MustNewScheduleType(scheduleType string) ScheduleType {
+	t, err := NewScheduleType(scheduleType)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
@@ -0,0 +1,67 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewScheduleType(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ScheduleType
+		wantErr bool
+	}{
+		{"cron", "cron", ScheduleTypeCron, false},
+		{"once", "once", ScheduleTypeOnce, false},
+		{"empty", "", "", true},
+		{"invalid", "weekly", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewScheduleType(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewScheduleType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewScheduleType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleType_Predicates(t *testing.T) {
+	if !ScheduleTypeCron.IsCron() || ScheduleTypeCron.IsOnce() {
+		t.Error("ScheduleTypeCron predicates are wrong")
+	}
+	if !ScheduleTypeOnce.IsOnce() || ScheduleTypeOnce.IsCron() {
+		t.Error("ScheduleTypeOnce predicates are wrong")
+	}
+	if !ScheduleType("").IsCron() {
+		t.Error("empty ScheduleType should be treated as cron")
+	}
+}
+
+func TestScheduleType_JSON(t *testing.T) {
+	data, err := json.Marshal(ScheduleTypeOnce)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `"once"` {
+		t.Errorf("json.Marshal() = %s, want \"once\"", data)
+	}
+
+	var got ScheduleType
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got != ScheduleTypeOnce {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, ScheduleTypeOnce)
+	}
+
+	if err := json.Unmarshal([]byte(`"hourly"`), &got); err == nil {
+		t.Error("json.Unmarshal() expected error for invalid schedule type")
+	}
+}
@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -49,6 +50,17 @@ func (id *SessionID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id SessionID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty SessionID; other values must be valid.
+func (id *SessionID) Scan(src any) error {
+	return scanValue(id, src, "session id", NewSessionID)
+}
+
 // NewSessionID creates a new SessionID from a string.
 // Returns an error if the string is not a valid ID.
 func NewSessionID(idStr string) (SessionID, error) {
@@ -59,6 +71,11 @@ func NewSessionID(idStr string) (SessionID, error) {
 	return SessionID(id), nil
 }
 
+// NewGeneratedSessionID generates a new unique SessionID (see GenerateID).
+func NewGeneratedSessionID() SessionID {
+	return SessionID(GenerateID(nil))
+}
+
 // MustNewSessionID creates a new SessionID from a string.
 // Panics if the string is not a valid ID.
 func MustNewSessionID(idStr string) SessionID {
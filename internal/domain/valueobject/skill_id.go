@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -49,6 +50,17 @@ func (id *SkillID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id SkillID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty SkillID; other values must be valid.
+func (id *SkillID) Scan(src any) error {
+	return scanValue(id, src, "skill id", NewSkillID)
+}
+
 // NewSkillID creates a new SkillID from a string.
 // Returns an error if the string is not a valid ID.
 func NewSkillID(idStr string) (SkillID, error) {
@@ -59,6 +71,11 @@ func NewSkillID(idStr string) (SkillID, error) {
 	return SkillID(id), nil
 }
 
+// NewGeneratedSkillID generates a new unique SkillID (see GenerateID).
+func NewGeneratedSkillID() SkillID {
+	return SkillID(GenerateID(nil))
+}
+
 // MustNewSkillID creates a new SkillID from a string.
 // Panics if the string is not a valid ID.
 func MustNewSkillID(idStr string) SkillID {
@@ -0,0 +1,86 @@
+package valueobject
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value objects stored in database columns are scanned and written directly
+var (
+	_ sql.Scanner   = (*ID)(nil)
+	_ sql.Scanner   = (*APIKeyID)(nil)
+	_ sql.Scanner   = (*LogID)(nil)
+	_ sql.Scanner   = (*MessageID)(nil)
+	_ sql.Scanner   = (*ScheduleID)(nil)
+	_ sql.Scanner   = (*ScheduleRunID)(nil)
+	_ sql.Scanner   = (*SessionID)(nil)
+	_ sql.Scanner   = (*SkillID)(nil)
+	_ sql.Scanner   = (*TaskID)(nil)
+	_ sql.Scanner   = (*UserID)(nil)
+	_ sql.Scanner   = (*WorkspaceID)(nil)
+	_ sql.Scanner   = (*Channel)(nil)
+	_ sql.Scanner   = (*TaskStatus)(nil)
+	_ sql.Scanner   = (*MessageRole)(nil)
+	_ sql.Scanner   = (*Version)(nil)
+	_ sql.Scanner   = (*CronExpression)(nil)
+	_ driver.Valuer = ID("")
+	_ driver.Valuer = APIKeyID("")
+	_ driver.Valuer = LogID("")
+	_ driver.Valuer = MessageID("")
+	_ driver.Valuer = ScheduleID("")
+	_ driver.Valuer = ScheduleRunID("")
+	_ driver.Valuer = SessionID("")
+	_ driver.Valuer = SkillID("")
+	_ driver.Valuer = TaskID("")
+	_ driver.Valuer = UserID("")
+	_ driver.Valuer = WorkspaceID("")
+	_ driver.Valuer = Channel("")
+	_ driver.Valuer = TaskStatus("")
+	_ driver.Valuer = MessageRole("")
+	_ driver.Valuer = Version("")
+	_ driver.Valuer = CronExpression("")
+)
+
+// scanString converts a value read by database/sql into a string.
+// NULL is read as the empty string.
+func scanString(src any) (string, error) {
+	switch v := src.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("cannot scan %T into a string", src)
+	}
+}
+
+// scanValue implements sql.Scanner for string value objects.
+// NULL scans as the zero value; any other value must be accepted by parse.
+//
+// Parameters:
+//   - dst: Value object scanned into
+//   - src: Value read by database/sql
+//   - name: Name of the value object used in errors, e.g. "channel"
+//   - parse: Constructor of the value object, e.g. NewChannel
+//
+// Returns:
+//   - error: Error if src is not a string or not a valid value
+func scanValue[T ~string](dst *T, src any, name string, parse func(string) (T, error)) error {
+	str, err := scanString(src)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", name, err)
+	}
+	if src == nil {
+		*dst = ""
+		return nil
+	}
+	v, err := parse(str)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s %q: %w", name, str, err)
+	}
+	*dst = v
+	return nil
+}
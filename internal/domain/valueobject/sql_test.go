@@ -0,0 +1,91 @@
+package valueobject
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueObjects_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		dst     sql.Scanner
+		src     any
+		want    string
+		wantErr error
+	}{
+		{"id from string", new(ID), "abc-123", "abc-123", nil},
+		{"id from bytes", new(ID), []byte("abc-123"), "abc-123", nil},
+		{"typed id", new(SessionID), "session-1", "session-1", nil},
+		{"invalid id", new(TaskID), "not valid!", "", ErrInvalidID},
+		{"empty id", new(UserID), "", "", ErrEmptyID},
+		{"channel", new(Channel), "telegram", "telegram", nil},
+		{"invalid channel", new(Channel), "fax", "", ErrInvalidChannel},
+		{"task status", new(TaskStatus), []byte("running"), "running", nil},
+		{"invalid task status", new(TaskStatus), "paused", "", ErrInvalidTaskStatus},
+		{"message role", new(MessageRole), "assistant", "assistant", nil},
+		{"invalid message role", new(MessageRole), "robot", "", ErrInvalidMessageRole},
+		{"version", new(Version), "1.2.3", "1.2.3", nil},
+		{"invalid version", new(Version), "v1", "", ErrInvalidVersion},
+		{"cron expression", new(CronExpression), "*/5 * * * *", "*/5 * * * *", nil},
+		{"empty cron expression of a one-shot schedule", new(CronExpression), "", "", nil},
+		{"invalid cron expression", new(CronExpression), "every minute", "", ErrInvalidCronExpression},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dst.Scan(tt.src)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.dst.(interface{ String() string }).String())
+		})
+	}
+}
+
+func TestValueObjects_ScanNull(t *testing.T) {
+	status := TaskStatusRunning
+	require.NoError(t, status.Scan(nil))
+	assert.Equal(t, TaskStatus(""), status)
+
+	id := SessionID("session-1")
+	require.NoError(t, id.Scan(nil))
+	assert.True(t, id.IsEmpty())
+}
+
+func TestValueObjects_ScanUnsupportedType(t *testing.T) {
+	var channel Channel
+	err := channel.Scan(int64(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to scan channel: cannot scan int64 into a string")
+}
+
+func TestValueObjects_Value(t *testing.T) {
+	tests := []struct {
+		name  string
+		value driver.Valuer
+		want  driver.Value
+	}{
+		{"id", ID("abc-123"), "abc-123"},
+		{"typed id", MessageID("message-1"), "message-1"},
+		{"channel", ChannelWeb, "web"},
+		{"task status", TaskStatusCompleted, "completed"},
+		{"message role", RoleUser, "user"},
+		{"version", Version("2.0.0"), "2.0.0"},
+		{"cron expression", CronExpression("0 9 * * *"), "0 9 * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
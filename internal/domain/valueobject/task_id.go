@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -49,6 +50,17 @@ func (id *TaskID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id TaskID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty TaskID; other values must be valid.
+func (id *TaskID) Scan(src any) error {
+	return scanValue(id, src, "task id", NewTaskID)
+}
+
 // NewTaskID creates a new TaskID from a string.
 // Returns an error if the string is not a valid ID.
 func NewTaskID(idStr string) (TaskID, error) {
@@ -59,6 +71,11 @@ func NewTaskID(idStr string) (TaskID, error) {
 	return TaskID(id), nil
 }
 
+// NewGeneratedTaskID generates a new unique TaskID (see GenerateID).
+func NewGeneratedTaskID() TaskID {
+	return TaskID(GenerateID(nil))
+}
+
 // MustNewTaskID creates a new TaskID from a string.
 // Panics if the string is not a valid ID.
 func MustNewTaskID(idStr string) TaskID {
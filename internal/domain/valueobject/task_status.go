@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 var (
 	// ErrInvalidTaskStatus is returned when an invalid task status is provided.
 	ErrInvalidTaskStatus = errors.New("invalid task status")
+	// ErrInvalidTaskTransition is returned when a task cannot move from its status to the requested one.
+	ErrInvalidTaskTransition = errors.New("invalid task status transition")
 )
 
 // TaskStatus represents the status of a task.
@@ -24,8 +27,17 @@ const (
 	TaskStatusCompleted TaskStatus = "completed"
 	// TaskStatusFailed represents a task failed with an error.
 	TaskStatusFailed TaskStatus = "failed"
+	// TaskStatusCancelled represents a task cancelled before it finished.
+	TaskStatusCancelled TaskStatus = "cancelled"
 )
 
+// taskTransitions lists the statuses each status can move to.
+// Terminal statuses have no outgoing transitions.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending: {TaskStatusRunning, TaskStatusFailed, TaskStatusCancelled},
+	TaskStatusRunning: {TaskStatusCompleted, TaskStatusFailed, TaskStatusCancelled},
+}
+
 // String returns the string representation of the task status.
 func (s TaskStatus) String() string {
 	return string(s)
@@ -34,7 +46,7 @@ func (s TaskStatus) String() string {
 // IsValid checks if the task status is valid.
 func (s TaskStatus) IsValid() bool {
 	switch s {
-	case TaskStatusPending, TaskStatusRunning, TaskStatusCompleted, TaskStatusFailed:
+	case TaskStatusPending, TaskStatusRunning, TaskStatusCompleted, TaskStatusFailed, TaskStatusCancelled:
 		return true
 	default:
 		return false
@@ -61,9 +73,41 @@ func (s TaskStatus) IsFailed() bool {
 	return s == TaskStatusFailed
 }
 
-// IsTerminal returns true if the task status is a terminal state (completed or failed).
+// IsCancelled returns true if the task status is cancelled.
+func (s TaskStatus) IsCancelled() bool {
+	return s == TaskStatusCancelled
+}
+
+// IsTerminal returns true if the task status is a terminal state (completed, failed or cancelled).
 func (s TaskStatus) IsTerminal() bool {
-	return s == TaskStatusCompleted || s == TaskStatusFailed
+	return s == TaskStatusCompleted || s == TaskStatusFailed || s == TaskStatusCancelled
+}
+
+// CanTransitionTo returns true if a task can move from this status to next.
+// Tasks move from pending to running and from running to completed; pending and
+// running tasks can fail or be cancelled. Terminal statuses never change.
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	for _, allowed := range taskTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo validates the transition from this status to next.
+//
+// Parameters:
+//   - next: Status the task moves to
+//
+// Returns:
+//   - TaskStatus: The next status
+//   - error: ErrInvalidTaskTransition if the transition is not allowed
+func (s TaskStatus) TransitionTo(next TaskStatus) (TaskStatus, error) {
+	if !s.CanTransitionTo(next) {
+		return s, fmt.Errorf("%w: %s -> %s", ErrInvalidTaskTransition, s, next)
+	}
+	return next, nil
 }
 
 // MarshalJSON implements json.Marshaler interface.
@@ -84,6 +128,17 @@ func (s *TaskStatus) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (s TaskStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty TaskStatus; other values must be valid.
+func (s *TaskStatus) Scan(src any) error {
+	return scanValue(s, src, "task status", NewTaskStatus)
+}
+
 // NewTaskStatus creates a new TaskStatus from a string.
 // Returns an error if the string is not a valid task status.
 func NewTaskStatus(status string) (TaskStatus, error) {
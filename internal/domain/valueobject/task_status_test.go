@@ -36,6 +36,7 @@ func TestTaskStatus_IsValid(t *testing.T) {
 		{"running", TaskStatusRunning, true},
 		{"completed", TaskStatusCompleted, true},
 		{"failed", TaskStatusFailed, true},
+		{"cancelled", TaskStatusCancelled, true},
 		{"invalid", TaskStatus("invalid"), false},
 		{"empty", TaskStatus(""), false},
 	}
@@ -128,6 +129,7 @@ func TestTaskStatus_IsTerminal(t *testing.T) {
 		{"running", TaskStatusRunning, false},
 		{"completed", TaskStatusCompleted, true},
 		{"failed", TaskStatusFailed, true},
+		{"cancelled", TaskStatusCancelled, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -136,6 +138,46 @@ func TestTaskStatus_IsTerminal(t *testing.T) {
 	}
 }
 
+func TestTaskStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     TaskStatus
+		to       TaskStatus
+		expected bool
+	}{
+		{TaskStatusPending, TaskStatusRunning, true},
+		{TaskStatusPending, TaskStatusFailed, true},
+		{TaskStatusPending, TaskStatusCancelled, true},
+		{TaskStatusPending, TaskStatusCompleted, false},
+		{TaskStatusPending, TaskStatusPending, false},
+		{TaskStatusRunning, TaskStatusCompleted, true},
+		{TaskStatusRunning, TaskStatusFailed, true},
+		{TaskStatusRunning, TaskStatusCancelled, true},
+		{TaskStatusRunning, TaskStatusPending, false},
+		{TaskStatusRunning, TaskStatusRunning, false},
+		{TaskStatusCompleted, TaskStatusPending, false},
+		{TaskStatusCompleted, TaskStatusFailed, false},
+		{TaskStatusFailed, TaskStatusRunning, false},
+		{TaskStatusCancelled, TaskStatusRunning, false},
+		{TaskStatus("invalid"), TaskStatusRunning, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from.String()+" to "+tt.to.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestTaskStatus_TransitionTo(t *testing.T) {
+	next, err := TaskStatusRunning.TransitionTo(TaskStatusCompleted)
+	require.NoError(t, err)
+	assert.Equal(t, TaskStatusCompleted, next)
+
+	next, err = TaskStatusCompleted.TransitionTo(TaskStatusPending)
+	require.ErrorIs(t, err, ErrInvalidTaskTransition)
+	assert.EqualError(t, err, "invalid task status transition: completed -> pending")
+	assert.Equal(t, TaskStatusCompleted, next)
+}
+
 func TestTaskStatus_MarshalJSON(t *testing.T) {
 	status := TaskStatusPending
 	data, err := json.Marshal(status)
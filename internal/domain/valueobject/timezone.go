@@ -0,0 +1,108 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	// Embed the IANA time zone database so that timezones resolve
+	// even in minimal containers without /usr/share/zoneinfo.
+	_ "time/tzdata"
+)
+
+var (
+	// ErrInvalidTimezone is returned when an unknown IANA timezone is provided.
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrEmptyTimezone is returned when an empty timezone is provided.
+	ErrEmptyTimezone = errors.New("timezone cannot be empty")
+)
+
+// TimezoneUTC is the default timezone for schedules.
+const TimezoneUTC Timezone = "UTC"
+
+// Timezone represents an IANA timezone name (e.g., "Europe/Moscow").
+// It's a value object that guarantees the name can be resolved to a location.
+type Timezone string
+
+// String returns the string representation of the timezone.
+func (tz Timezone) String() string {
+	return string(tz)
+}
+
+// IsEmpty returns true if the timezone is empty.
+func (tz Timezone) IsEmpty() bool {
+	return string(tz) == ""
+}
+
+// IsValid checks if the timezone is a known IANA timezone name.
+func (tz Timezone) IsValid() bool {
+	if tz.IsEmpty() {
+		return false
+	}
+	_, err := time.LoadLocation(string(tz))
+	return err == nil
+}
+
+// Location returns the time.Location for the timezone.
+// Empty or unknown timezones resolve to UTC.
+func (tz Timezone) Location() *time.Location {
+	if tz.IsEmpty() {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(string(tz))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Equals checks if the timezone equals another timezone.
+func (tz Timezone) Equals(other Timezone) bool {
+	return tz == other
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (tz Timezone) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(tz))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (tz *Timezone) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		return ErrEmptyTimezone
+	}
+	t := Timezone(str)
+	if !t.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidTimezone, str)
+	}
+	*tz = t
+	return nil
+}
+
+// NewTimezone creates a new Timezone from a string.
+// Returns an error if the string is not a known IANA timezone.
+func NewTimezone(name string) (Timezone, error) {
+	if name == "" {
+		return "", ErrEmptyTimezone
+	}
+	tz := Timezone(name)
+	if !tz.IsValid() {
+		return "", ErrInvalidTimezone
+	}
+	return tz, nil
+}
+
+// MustNewTimezone creates a new Timezone from a string.
+// Panics if the string is not a known IANA timezone.
+func MustNewTimezone(name string) Timezone {
+	tz, err := NewTimezone(name)
+	if err != nil {
+		panic(err)
+	}
+	return tz
+}
@@ -0,0 +1,108 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNewTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		tz      string
+		want    Timezone
+		wantErr bool
+	}{
+		{
+			name:    "UTC",
+			tz:      "UTC",
+			want:    TimezoneUTC,
+			wantErr: false,
+		},
+		{
+			name:    "Europe/Moscow",
+			tz:      "Europe/Moscow",
+			want:    Timezone("Europe/Moscow"),
+			wantErr: false,
+		},
+		{
+			name:    "America/New_York",
+			tz:      "America/New_York",
+			want:    Timezone("America/New_York"),
+			wantErr: false,
+		},
+		{
+			name:    "empty timezone",
+			tz:      "",
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "unknown timezone",
+			tz:      "Mars/Olympus",
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTimezone(tt.tz)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewTimezone() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewTimezone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustNewTimezone_Panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("MustNewTimezone() did not panic for invalid timezone")
+		}
+	}()
+	MustNewTimezone("Invalid/Zone")
+}
+
+func TestTimezone_Location(t *testing.T) {
+	if loc := Timezone("").Location(); loc != time.UTC {
+		t.Errorf("Timezone(\"\").Location() = %v, want UTC", loc)
+	}
+	if loc := Timezone("Invalid/Zone").Location(); loc != time.UTC {
+		t.Errorf("Timezone(\"Invalid/Zone\").Location() = %v, want UTC", loc)
+	}
+	if loc := Timezone("Europe/Moscow").Location(); loc.String() != "Europe/Moscow" {
+		t.Errorf("Timezone(\"Europe/Moscow\").Location() = %v, want Europe/Moscow", loc)
+	}
+}
+
+func TestTimezone_JSON(t *testing.T) {
+	tz := MustNewTimezone("Europe/Berlin")
+
+	data, err := json.Marshal(tz)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `"Europe/Berlin"` {
+		t.Errorf("json.Marshal() = %s, want \"Europe/Berlin\"", data)
+	}
+
+	var got Timezone
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got != tz {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, tz)
+	}
+
+	if err := json.Unmarshal([]byte(`"Nowhere/Land"`), &got); err == nil {
+		t.Error("json.Unmarshal() expected error for invalid timezone")
+	}
+	if err := json.Unmarshal([]byte(`""`), &got); err == nil {
+		t.Error("json.Unmarshal() expected error for empty timezone")
+	}
+}
@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 )
@@ -49,6 +50,17 @@ func (id *UserID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (id UserID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty UserID; other values must be valid.
+func (id *UserID) Scan(src any) error {
+	return scanValue(id, src, "user id", NewUserID)
+}
+
 // NewUserID creates a new UserID from a string.
 // Returns an error if the string is not a valid ID.
 func NewUserID(idStr string) (UserID, error) {
@@ -59,6 +71,11 @@ func NewUserID(idStr string) (UserID, error) {
 	return UserID(id), nil
 }
 
+// NewGeneratedUserID generates a new unique UserID (see GenerateID).
+func NewGeneratedUserID() UserID {
+	return UserID(GenerateID(nil))
+}
+
 // MustNewUserID creates a new UserID from a string.
 // Panics if the string is not a valid ID.
 func MustNewUserID(idStr string) UserID {
@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -61,6 +62,17 @@ func (v *Version) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Value implements driver.Valuer interface.
+func (v Version) Value() (driver.Value, error) {
+	return string(v), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty Version; other values must be valid.
+func (v *Version) Scan(src any) error {
+	return scanValue(v, src, "version", NewVersion)
+}
+
 // NewVersion creates a new Version from a string.
 // Returns an error if the string is not a valid version.
 func NewVersion(version string) (Version, error) {
@@ -0,0 +1,86 @@
+package valueobject
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// WorkspaceID represents a workspace identifier.
+// Workspace IDs are chosen by the admin, e.g. "support", so connectors can be mapped to them in the configuration.
+type WorkspaceID ID
+
+// DefaultWorkspaceID is the workspace of connectors, sessions and API keys not assigned to another one
+const DefaultWorkspaceID WorkspaceID = "default"
+
+// String returns the string representation of the WorkspaceID.
+func (id WorkspaceID) String() string {
+	return string(id)
+}
+
+// IsEmpty returns true if the WorkspaceID is empty.
+func (id WorkspaceID) IsEmpty() bool {
+	return string(id) == ""
+}
+
+// IsValid checks if the WorkspaceID is valid (not empty and matches pattern).
+func (id WorkspaceID) IsValid() bool {
+	return ID(id).IsValid()
+}
+
+// Equals checks if the WorkspaceID equals another WorkspaceID.
+func (id WorkspaceID) Equals(other WorkspaceID) bool {
+	return id == other
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (id WorkspaceID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(id))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (id *WorkspaceID) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		return ErrEmptyID
+	}
+	if !WorkspaceID(str).IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidID, str)
+	}
+	*id = WorkspaceID(str)
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+func (id WorkspaceID) Value() (driver.Value, error) {
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner interface.
+// NULL scans as the empty WorkspaceID; other values must be valid.
+func (id *WorkspaceID) Scan(src any) error {
+	return scanValue(id, src, "workspace id", NewWorkspaceID)
+}
+
+// NewWorkspaceID creates a new WorkspaceID from a string.
+// Returns an error if the string is not a valid ID.
+func NewWorkspaceID(idStr string) (WorkspaceID, error) {
+	id, err := NewID(idStr)
+	if err != nil {
+		return "", err
+	}
+	return WorkspaceID(id), nil
+}
+
+// MustNewWorkspaceID creates a new WorkspaceID from a string.
+// Panics if the string is not a valid ID.
+func MustNewWorkspaceID(idStr string) WorkspaceID {
+	id, err := NewWorkspaceID(idStr)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
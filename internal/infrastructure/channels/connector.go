@@ -2,8 +2,10 @@ package channels
 
 import (
 	"context"
+	"errors"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 )
 
 // Message represents a message from a channel
@@ -11,7 +13,10 @@ type Message struct {
 	UserID    string // Channel-specific user ID
 	ChannelID string // Channel-specific channel or chat ID
 	Content   string // Message content
-	Metadata  map[string]interface{}
+
+	// Metadata carries channel-specific details. A "traceparent" entry in the W3C format
+	// continues the trace of the sender instead of starting a new one.
+	Metadata map[string]interface{}
 }
 
 // Response represents a response to send back to a channel
@@ -92,3 +97,44 @@ type Connector interface {
 	// CreateUser creates a new user in the system
 	CreateUser(ctx context.Context, channelUserID string) (*entity.User, error)
 }
+
+// ChannelConnector is implemented by connectors whose users belong to a channel other than
+// the connector name, such as Telegram bots registered under their own connector names
+type ChannelConnector interface {
+	// Channel returns the channel of the users of the connector
+	Channel() valueobject.Channel
+}
+
+// ChannelOf returns the channel of the users of a connector: the channel of a
+// ChannelConnector, otherwise the connector name
+func ChannelOf(connector Connector) string {
+	if c, ok := connector.(ChannelConnector); ok {
+		return c.Channel().String()
+	}
+	return connector.Name()
+}
+
+// ErrFileTooLarge is returned by a FileDownloader for files over the size limit
+var ErrFileTooLarge = errors.New("file too large")
+
+// FileDownloader is implemented by connectors that can fetch the files sent by users, such as
+// the documents of Telegram messages
+type FileDownloader interface {
+	// DownloadFile returns the content of a file by its channel-specific ID, failing with
+	// ErrFileTooLarge for files over maxSize bytes
+	DownloadFile(ctx context.Context, fileID string, maxSize int64) ([]byte, error)
+}
+
+// ExclusiveConnector is implemented by connectors whose source hands each message to a single
+// consumer, such as Telegram bots pulling their updates. Of the instances sharing the database,
+// only the leader runs them.
+type ExclusiveConnector interface {
+	// Exclusive returns true if the connector must run in a single instance
+	Exclusive() bool
+}
+
+// IsExclusive returns true if a connector must run in a single instance
+func IsExclusive(connector Connector) bool {
+	c, ok := connector.(ExclusiveConnector)
+	return ok && c.Exclusive()
+}
@@ -234,6 +234,21 @@ func (m *MockConnector) Name() string {
 	return m.NameVal
 }
 
+// botConnector is a connector whose users belong to the telegram channel
+type botConnector struct {
+	MockConnector
+}
+
+func (b *botConnector) Channel() valueobject.Channel {
+	return valueobject.ChannelTelegram
+}
+
+// TestChannelOf tests the channel of the users of a connector
+func TestChannelOf(t *testing.T) {
+	assert.Equal(t, "slack", ChannelOf(&MockConnector{NameVal: "slack"}))
+	assert.Equal(t, "telegram", ChannelOf(&botConnector{MockConnector{NameVal: "support_bot"}}))
+}
+
 // TestMockConnector tests MockConnector implementation
 func TestMockConnector(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,323 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+const (
+	// unspillBatchSize is the number of spilled messages loaded at a time to be fed back
+	unspillBatchSize = 50
+
+	// unspillInterval is how often spilled messages are looked for when the buffer did not
+	// signal room, e.g. after the store failed
+	unspillInterval = time.Second
+)
+
+// IncomingMetrics are the metrics of the incoming buffers of connectors, labeled by connector
+type IncomingMetrics struct {
+	registry *metrics.MetricsRegistry
+}
+
+// NewIncomingMetrics creates a new IncomingMetrics instance
+func NewIncomingMetrics() *IncomingMetrics {
+	return &IncomingMetrics{registry: metrics.NewMetricsRegistry()}
+}
+
+// Dropped returns the counter of messages of a connector dropped by an overflow policy
+func (m *IncomingMetrics) Dropped(connector, policy string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("connector_incoming_messages_dropped_total{connector=%q,policy=%q}", connector, policy))
+}
+
+// Spilled returns the counter of messages of a connector stored by the spill policy
+func (m *IncomingMetrics) Spilled(connector string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("connector_incoming_messages_spilled_total{connector=%q}", connector))
+}
+
+// Unspilled returns the counter of spilled messages of a connector fed back to the buffer
+func (m *IncomingMetrics) Unspilled(connector string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("connector_incoming_messages_unspilled_total{connector=%q}", connector))
+}
+
+// SpillBacklog returns the gauge of spilled messages of a connector waiting to be fed back
+func (m *IncomingMetrics) SpillBacklog(connector string) *metrics.Gauge {
+	return m.registry.GetGauge(fmt.Sprintf("connector_incoming_spill_backlog{connector=%q}", connector))
+}
+
+// Registry returns the registry holding the incoming buffer metrics
+func (m *IncomingMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// IncomingQueue buffers the messages a connector received until the message router picks
+// them up, applying the overflow policy of the connector's configuration when the buffer is
+// full. With the spill policy, messages are stored and fed back oldest first once the buffer
+// has room; while spilled messages wait, new messages are spilled too, so that they keep
+// their order. Without a store, the spill policy drops the newest message.
+type IncomingQueue struct {
+	connector string
+	policy    string
+	timeout   time.Duration
+	store     repository.SpilledMessageRepository
+	logger    *slog.Logger
+
+	dropped      *metrics.Counter
+	spilled      *metrics.Counter
+	unspilled    *metrics.Counter
+	spillBacklog *metrics.Gauge
+
+	messages  chan *Message
+	done      chan struct{}
+	wake      chan struct{}
+	closeOnce sync.Once
+
+	// sendMu is held for reading while sending to messages and for writing while closing it
+	sendMu sync.RWMutex
+	closed bool
+
+	// mu serializes pushes and guards the spill state
+	mu       sync.Mutex
+	backlog  int
+	sequence int64
+}
+
+// NewIncomingQueue creates the incoming buffer of a connector. The store and metrics may be
+// nil; spilled messages left by an earlier run are fed back first.
+func NewIncomingQueue(connector string, cfg config.IncomingConfig, store repository.SpilledMessageRepository, m *IncomingMetrics, logger *slog.Logger) *IncomingQueue {
+	if m == nil {
+		m = NewIncomingMetrics()
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	q := &IncomingQueue{
+		connector:    connector,
+		policy:       cfg.Policy(),
+		timeout:      cfg.BlockTimeout(),
+		logger:       logger,
+		dropped:      m.Dropped(connector, cfg.Policy()),
+		spilled:      m.Spilled(connector),
+		unspilled:    m.Unspilled(connector),
+		spillBacklog: m.SpillBacklog(connector),
+		messages:     make(chan *Message, cfg.Size()),
+		done:         make(chan struct{}),
+		wake:         make(chan struct{}, 1),
+	}
+
+	if q.policy == config.OverflowSpill && store != nil {
+		q.store = store
+		backlog, err := store.Count(context.Background(), connector)
+		if err != nil {
+			logger.Error("Failed to count spilled messages", "connector", connector, "error", err)
+		}
+		q.backlog = backlog
+		q.spillBacklog.Set(int64(backlog))
+		go q.unspillLoop()
+	}
+	return q
+}
+
+// Messages returns the channel of buffered messages, closed by Close
+func (q *IncomingQueue) Messages() <-chan *Message {
+	return q.messages
+}
+
+// Push buffers a message, applying the overflow policy when the buffer is full. The block
+// policy waits up to its timeout, unless ctx is done first. Push reports whether the message
+// was buffered or spilled; dropped messages are counted and logged.
+func (q *IncomingQueue) Push(ctx context.Context, msg *Message) bool {
+	q.sendMu.RLock()
+	defer q.sendMu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.store != nil && q.backlog > 0 {
+		return q.spill(ctx, msg)
+	}
+	select {
+	case q.messages <- msg:
+		return true
+	default:
+	}
+
+	switch {
+	case q.policy == config.OverflowBlock:
+		timer := time.NewTimer(q.timeout)
+		defer timer.Stop()
+		select {
+		case q.messages <- msg:
+			return true
+		case <-timer.C:
+		case <-ctx.Done():
+		case <-q.done:
+		}
+
+	case q.policy == config.OverflowDropOldest:
+		for {
+			select {
+			case q.messages <- msg:
+				return true
+			default:
+			}
+			select {
+			case oldest := <-q.messages:
+				q.drop(oldest)
+			default:
+			}
+		}
+
+	case q.store != nil:
+		return q.spill(ctx, msg)
+	}
+
+	q.drop(msg)
+	return false
+}
+
+// Close closes the channel of buffered messages and stops feeding spilled messages back.
+// Messages pushed afterwards are rejected.
+func (q *IncomingQueue) Close() {
+	q.closeOnce.Do(func() {
+		close(q.done)
+		q.sendMu.Lock()
+		q.closed = true
+		close(q.messages)
+		q.sendMu.Unlock()
+	})
+}
+
+// drop counts and logs a message that did not fit into the buffer
+func (q *IncomingQueue) drop(msg *Message) {
+	q.dropped.Inc()
+	q.logger.Warn("Incoming buffer is full, message dropped",
+		"connector", q.connector,
+		"policy", q.policy,
+		"user_id", msg.UserID,
+	)
+}
+
+// spill stores a message to be fed back later, dropping it if the store fails.
+// It must be called with mu held.
+func (q *IncomingQueue) spill(ctx context.Context, msg *Message) bool {
+	// Sequences follow the clock, so that they keep growing across restarts
+	q.sequence = max(q.sequence+1, time.Now().UnixNano())
+	spilled := entity.NewSpilledMessage(q.connector, msg.UserID, msg.ChannelID, msg.Content, msg.Metadata, q.sequence)
+	if err := q.store.Create(context.WithoutCancel(ctx), spilled); err != nil {
+		q.logger.Error("Failed to spill message", "connector", q.connector, "error", err)
+		q.drop(msg)
+		return false
+	}
+
+	q.backlog++
+	q.spilled.Inc()
+	q.spillBacklog.Set(int64(q.backlog))
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// unspillLoop feeds spilled messages back to the buffer until the queue is closed
+func (q *IncomingQueue) unspillLoop() {
+	ticker := time.NewTicker(unspillInterval)
+	defer ticker.Stop()
+
+	for {
+		for q.unspill() {
+		}
+		select {
+		case <-q.done:
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// unspill feeds a batch of the oldest spilled messages back to the buffer, waiting for room,
+// and reports whether spilled messages are left
+func (q *IncomingQueue) unspill() bool {
+	q.mu.Lock()
+	backlog := q.backlog
+	q.mu.Unlock()
+	if backlog == 0 {
+		return false
+	}
+
+	ctx := context.Background()
+	batch, err := q.store.List(ctx, q.connector, unspillBatchSize)
+	if err != nil {
+		q.logger.Error("Failed to load spilled messages", "connector", q.connector, "error", err)
+		return false
+	}
+	if len(batch) == 0 {
+		q.setBacklog(0)
+		return false
+	}
+
+	for _, spilled := range batch {
+		msg := &Message{
+			UserID:    spilled.UserID,
+			ChannelID: spilled.ChannelID,
+			Content:   spilled.Content,
+			Metadata:  spilled.GetMetadata(),
+		}
+		if !q.feed(msg) {
+			return false
+		}
+		// A message that cannot be deleted is fed back again after a restart
+		if err := q.store.Delete(ctx, spilled.ID); err != nil {
+			q.logger.Error("Failed to delete spilled message", "connector", q.connector, "id", spilled.ID, "error", err)
+		}
+		q.unspilled.Inc()
+
+		q.mu.Lock()
+		q.backlog = max(q.backlog-1, 0)
+		backlog = q.backlog
+		q.spillBacklog.Set(int64(backlog))
+		q.mu.Unlock()
+		if backlog == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// feed sends a spilled message to the buffer, waiting for room, and reports whether it was
+// sent before the queue was closed
+func (q *IncomingQueue) feed(msg *Message) bool {
+	q.sendMu.RLock()
+	defer q.sendMu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	select {
+	case q.messages <- msg:
+		return true
+	case <-q.done:
+		return false
+	}
+}
+
+// setBacklog sets the number of spilled messages waiting to be fed back
+func (q *IncomingQueue) setBacklog(backlog int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.backlog = backlog
+	q.spillBacklog.Set(int64(backlog))
+}
@@ -0,0 +1,196 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySpillStore is an in-memory implementation of repository.SpilledMessageRepository
+type memorySpillStore struct {
+	mu       sync.Mutex
+	messages map[string]*entity.SpilledMessage
+	err      error
+}
+
+func newMemorySpillStore() *memorySpillStore {
+	return &memorySpillStore{messages: make(map[string]*entity.SpilledMessage)}
+}
+
+func (s *memorySpillStore) Create(ctx context.Context, message *entity.SpilledMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.messages[message.ID] = message
+	return nil
+}
+
+func (s *memorySpillStore) List(ctx context.Context, connector string, limit int) ([]*entity.SpilledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []*entity.SpilledMessage
+	for _, message := range s.messages {
+		if message.Connector == connector {
+			messages = append(messages, message)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Sequence < messages[j].Sequence })
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+func (s *memorySpillStore) Count(ctx context.Context, connector string) (int, error) {
+	messages, err := s.List(ctx, connector, 1<<20)
+	return len(messages), err
+}
+
+func (s *memorySpillStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.messages[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(s.messages, id)
+	return nil
+}
+
+// receive reads the content of n messages from the queue
+func receive(t *testing.T, q *IncomingQueue, n int) []string {
+	t.Helper()
+	var contents []string
+	for range n {
+		select {
+		case msg := <-q.Messages():
+			contents = append(contents, msg.Content)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out after receiving %v", contents)
+		}
+	}
+	return contents
+}
+
+func TestIncomingQueue_DropNewest(t *testing.T) {
+	m := NewIncomingMetrics()
+	q := NewIncomingQueue("telegram", config.IncomingConfig{BufferSize: 2}, nil, m, nil)
+	defer q.Close()
+
+	for _, content := range []string{"1", "2", "3"} {
+		q.Push(context.Background(), &Message{Content: content})
+	}
+
+	assert.Equal(t, []string{"1", "2"}, receive(t, q, 2))
+	assert.Equal(t, int64(1), m.Dropped("telegram", config.OverflowDropNewest).Get())
+}
+
+func TestIncomingQueue_DropOldest(t *testing.T) {
+	m := NewIncomingMetrics()
+	q := NewIncomingQueue("telegram", config.IncomingConfig{BufferSize: 2, Overflow: config.OverflowDropOldest}, nil, m, nil)
+	defer q.Close()
+
+	for _, content := range []string{"1", "2", "3"} {
+		assert.True(t, q.Push(context.Background(), &Message{Content: content}))
+	}
+
+	assert.Equal(t, []string{"2", "3"}, receive(t, q, 2))
+	assert.Equal(t, int64(1), m.Dropped("telegram", config.OverflowDropOldest).Get())
+}
+
+func TestIncomingQueue_Block(t *testing.T) {
+	m := NewIncomingMetrics()
+	q := NewIncomingQueue("telegram", config.IncomingConfig{BufferSize: 1, Overflow: config.OverflowBlock, BlockTimeoutMs: 50}, nil, m, nil)
+	defer q.Close()
+	ctx := context.Background()
+
+	require.True(t, q.Push(ctx, &Message{Content: "1"}))
+
+	t.Run("waits for room", func(t *testing.T) {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			<-q.Messages()
+		}()
+		assert.True(t, q.Push(ctx, &Message{Content: "2"}))
+	})
+
+	t.Run("drops after the timeout", func(t *testing.T) {
+		start := time.Now()
+		assert.False(t, q.Push(ctx, &Message{Content: "3"}))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		assert.Equal(t, int64(1), m.Dropped("telegram", config.OverflowBlock).Get())
+	})
+
+	t.Run("closing releases a waiting push", func(t *testing.T) {
+		blocked := NewIncomingQueue("telegram", config.IncomingConfig{BufferSize: 1, Overflow: config.OverflowBlock, BlockTimeoutMs: 60000}, nil, m, nil)
+		require.True(t, blocked.Push(ctx, &Message{Content: "1"}))
+
+		done := make(chan bool)
+		go func() { done <- blocked.Push(ctx, &Message{Content: "2"}) }()
+		time.Sleep(10 * time.Millisecond)
+		blocked.Close()
+
+		select {
+		case pushed := <-done:
+			assert.False(t, pushed)
+		case <-time.After(time.Second):
+			t.Fatal("Push did not return after Close")
+		}
+		assert.False(t, blocked.Push(ctx, &Message{Content: "3"}))
+	})
+}
+
+func TestIncomingQueue_Spill(t *testing.T) {
+	m := NewIncomingMetrics()
+	store := newMemorySpillStore()
+	q := NewIncomingQueue("telegram", config.IncomingConfig{BufferSize: 2, Overflow: config.OverflowSpill}, store, m, nil)
+	defer q.Close()
+
+	for _, content := range []string{"1", "2", "3", "4", "5"} {
+		assert.True(t, q.Push(context.Background(), &Message{UserID: "tg:42", Content: content, Metadata: map[string]interface{}{"chat_id": 100}}))
+	}
+	assert.Equal(t, int64(3), m.Spilled("telegram").Get())
+
+	first := <-q.Messages()
+	assert.Equal(t, "1", first.Content)
+	assert.Equal(t, []string{"2", "3", "4", "5"}, receive(t, q, 4), "spilled messages keep their order")
+
+	assert.Eventually(t, func() bool { return m.SpillBacklog("telegram").Get() == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(3), m.Unspilled("telegram").Get())
+	assert.Zero(t, m.Dropped("telegram", config.OverflowSpill).Get())
+	count, _ := store.Count(context.Background(), "telegram")
+	assert.Zero(t, count)
+
+	t.Run("store failure drops the message", func(t *testing.T) {
+		require.True(t, q.Push(context.Background(), &Message{Content: "6"}))
+		require.True(t, q.Push(context.Background(), &Message{Content: "7"}))
+		store.mu.Lock()
+		store.err = errors.New("disk full")
+		store.mu.Unlock()
+
+		assert.False(t, q.Push(context.Background(), &Message{Content: "8"}))
+		assert.Equal(t, int64(1), m.Dropped("telegram", config.OverflowSpill).Get())
+		assert.Equal(t, []string{"6", "7"}, receive(t, q, 2))
+	})
+}
+
+func TestIncomingQueue_SpillBacklogOfEarlierRun(t *testing.T) {
+	store := newMemorySpillStore()
+	require.NoError(t, store.Create(context.Background(), entity.NewSpilledMessage("telegram", "tg:42", "100", "left over", nil, 1)))
+
+	q := NewIncomingQueue("telegram", config.IncomingConfig{Overflow: config.OverflowSpill}, store, nil, nil)
+	defer q.Close()
+	require.True(t, q.Push(context.Background(), &Message{Content: "new"}))
+
+	assert.Equal(t, []string{"left over", "new"}, receive(t, q, 2))
+}
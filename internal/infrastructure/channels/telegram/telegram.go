@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,8 +14,10 @@ import (
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
 	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -26,10 +30,19 @@ type Connector struct {
 	sessionRepo repository.SessionRepository
 	logger      *slog.Logger
 	running     bool
-	incoming    chan *channels.Message
+	stopped     bool
+	incoming    *channels.IncomingQueue
 	cancel      context.CancelFunc
 	updates     <-chan tgbotapi.Update
 	rateLimiter *rateLimiter
+	recoverer   *crash.Recoverer
+
+	// spillStore and incomingMetrics are passed to the incoming queue
+	spillStore      repository.SpilledMessageRepository
+	incomingMetrics *channels.IncomingMetrics
+
+	// fileEndpoint is the URL format of file downloads, with the bot token and the file path
+	fileEndpoint string
 }
 
 // rateLimiter implements token bucket rate limiting for Telegram API
@@ -94,19 +107,61 @@ func NewConnector(
 	sessionRepo repository.SessionRepository,
 	logger *slog.Logger,
 ) *Connector {
-	return &Connector{
+	c := &Connector{
 		config:      cfg,
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
 		logger:      logger,
-		incoming:    make(chan *channels.Message, 100),
 		rateLimiter: newRateLimiter(),
+
+		fileEndpoint: tgbotapi.FileEndpoint,
 	}
+	c.incoming = c.newIncomingQueue()
+	return c
+}
+
+// SetIncoming sets the store of the spill overflow policy and the metrics of the incoming
+// queue. It must be called before Start.
+func (c *Connector) SetIncoming(store repository.SpilledMessageRepository, metrics *channels.IncomingMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spillStore = store
+	c.incomingMetrics = metrics
+	c.incoming.Close()
+	c.incoming = c.newIncomingQueue()
+}
+
+// newIncomingQueue creates the queue of received messages configured for the bot
+func (c *Connector) newIncomingQueue() *channels.IncomingQueue {
+	return channels.NewIncomingQueue(c.Name(), c.config.Incoming, c.spillStore, c.incomingMetrics, c.logger)
 }
 
-// Name returns the name of the channel
+// SetRecoverer sets the recoverer reporting panics while handling updates. An update whose
+// handling panics is dropped; without a recoverer, the panic is only logged with the drop.
+func (c *Connector) SetRecoverer(recoverer *crash.Recoverer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recoverer = recoverer
+}
+
+// Name returns the connector name of the bot, "telegram" unless the configuration names it.
+// Users are shared by all bots, so a Telegram user has the same user in every workspace.
 func (c *Connector) Name() string {
-	return "telegram"
+	return c.config.ConnectorName()
+}
+
+// Channel returns the channel of the users of every bot, telegram
+func (c *Connector) Channel() valueobject.Channel {
+	return valueobject.ChannelTelegram
+}
+
+// Compile-time check that Connector implements channels.ExclusiveConnector
+var _ channels.ExclusiveConnector = (*Connector)(nil)
+
+// Exclusive returns true: Telegram hands each update to a single consumer of the bot, so the
+// connector runs in a single instance
+func (c *Connector) Exclusive() bool {
+	return true
 }
 
 // Start initializes and starts the connector
@@ -118,6 +173,15 @@ func (c *Connector) Start(ctx context.Context) error {
 		return fmt.Errorf("telegram connector is already running")
 	}
 
+	// Stop closes the incoming queue, so a restarted connector needs a new one
+	if c.stopped {
+		c.incoming = c.newIncomingQueue()
+		c.stopped = false
+	}
+	if c.config.Incoming.Policy() == config.OverflowSpill && c.spillStore == nil && c.logger != nil {
+		c.logger.Warn("No store for spilled messages, the newest messages are dropped when the incoming queue is full")
+	}
+
 	// Create bot instance
 	bot, err := tgbotapi.NewBotAPI(c.config.BotToken)
 	if err != nil {
@@ -144,7 +208,7 @@ func (c *Connector) Start(ctx context.Context) error {
 	c.running = true
 
 	// Start processing updates
-	go c.processUpdates(ctx)
+	go c.processUpdates(ctx, c.recoverer)
 
 	if c.logger != nil {
 		c.logger.Info("Telegram connector started", "mode", c.getMode())
@@ -177,7 +241,8 @@ func (c *Connector) Stop(ctx context.Context) error {
 	}
 
 	c.running = false
-	close(c.incoming)
+	c.incoming.Close()
+	c.stopped = true
 
 	if c.logger != nil {
 		c.logger.Info("Telegram connector stopped")
@@ -226,7 +291,7 @@ func (c *Connector) SendResponse(ctx context.Context, userID string, response *c
 func (c *Connector) Incoming() <-chan *channels.Message {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.incoming
+	return c.incoming.Messages()
 }
 
 // IsRunning returns whether the connector is currently running
@@ -238,12 +303,12 @@ func (c *Connector) IsRunning() bool {
 
 // GetUser retrieves a user by channel-specific ID
 func (c *Connector) GetUser(ctx context.Context, channelUserID string) (*entity.User, error) {
-	return c.userRepo.FindByChannel(ctx, "telegram", channelUserID)
+	return c.userRepo.FindByChannel(ctx, valueobject.ChannelTelegram.String(), channelUserID)
 }
 
 // CreateUser creates a new user in the system
 func (c *Connector) CreateUser(ctx context.Context, channelUserID string) (*entity.User, error) {
-	user := entity.NewUser("telegram", channelUserID)
+	user := entity.NewUser(valueobject.ChannelTelegram.String(), channelUserID)
 	if err := c.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -254,6 +319,53 @@ func (c *Connector) CreateUser(ctx context.Context, channelUserID string) (*enti
 	return user, nil
 }
 
+// Compile-time check that Connector implements channels.FileDownloader
+var _ channels.FileDownloader = (*Connector)(nil)
+
+// DownloadFile downloads a file sent to the bot, such as a document, by its file ID. Files
+// over maxSize bytes fail with channels.ErrFileTooLarge; the Bot API serves files of up to
+// 20 MB.
+func (c *Connector) DownloadFile(ctx context.Context, fileID string, maxSize int64) ([]byte, error) {
+	c.mu.RLock()
+	bot, running, endpoint := c.bot, c.running, c.fileEndpoint
+	c.mu.RUnlock()
+
+	if !running || bot == nil {
+		return nil, fmt.Errorf("telegram connector is not running")
+	}
+
+	file, err := bot.GetFile(tgbotapi.FileConfig{FileID: fileID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if int64(file.FileSize) > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes", channels.ErrFileTooLarge, file.FileSize)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(endpoint, bot.Token, file.FilePath), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: over %d bytes", channels.ErrFileTooLarge, maxSize)
+	}
+	return data, nil
+}
+
 // setupWebhook configures the webhook for incoming updates
 func (c *Connector) setupWebhook() error {
 	webhook, err := tgbotapi.NewWebhook(c.config.WebhookURL)
@@ -286,7 +398,7 @@ func (c *Connector) setupPolling() {
 }
 
 // processUpdates processes incoming updates from Telegram
-func (c *Connector) processUpdates(ctx context.Context) {
+func (c *Connector) processUpdates(ctx context.Context, recoverer *crash.Recoverer) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -302,31 +414,41 @@ func (c *Connector) processUpdates(ctx context.Context) {
 				return
 			}
 
-			// Handle callback queries (inline buttons)
-			if update.CallbackQuery != nil {
-				c.handleCallbackQuery(update.CallbackQuery)
-				continue
+			if err := c.handleUpdate(ctx, recoverer, update); err != nil && c.logger != nil {
+				c.logger.Error("Update dropped", "update_id", update.UpdateID, "error", err)
 			}
+		}
+	}
+}
 
-			// Handle regular messages
-			if update.Message == nil {
-				continue
-			}
+// handleUpdate handles a callback query or message, recovering a panic with the recoverer
+func (c *Connector) handleUpdate(ctx context.Context, recoverer *crash.Recoverer, update tgbotapi.Update) (err error) {
+	defer recoverer.Recover(ctx, "telegram", "handle_update", &err, "connector", c.Name(), "update_id", update.UpdateID)
 
-			if !c.isAllowed(update.Message.Chat.ID, update.Message.From.ID) {
-				if c.logger != nil {
-					c.logger.Warn("Message from unauthorized user/chat ignored",
-						"chat_id", update.Message.Chat.ID,
-						"user_id", update.Message.From.ID,
-						"username", update.Message.From.UserName,
-					)
-				}
-				continue
-			}
+	// Handle callback queries (inline buttons)
+	if update.CallbackQuery != nil {
+		c.handleCallbackQuery(ctx, update.CallbackQuery)
+		return nil
+	}
+
+	// Handle regular messages
+	if update.Message == nil {
+		return nil
+	}
 
-			c.handleMessage(ctx, update.Message)
+	if !c.isAllowed(update.Message.Chat.ID, update.Message.From.ID) {
+		if c.logger != nil {
+			c.logger.Warn("Message from unauthorized user/chat ignored",
+				"chat_id", update.Message.Chat.ID,
+				"user_id", update.Message.From.ID,
+				"username", update.Message.From.UserName,
+			)
 		}
+		return nil
 	}
+
+	c.handleMessage(ctx, update.Message)
+	return nil
 }
 
 // handleMessage processes an incoming message from Telegram
@@ -342,25 +464,18 @@ func (c *Connector) handleMessage(ctx context.Context, message *tgbotapi.Message
 		Metadata:  metadata,
 	}
 
-	// Send message to incoming channel
-	select {
-	case c.incoming <- msg:
-		if c.logger != nil {
-			c.logger.Debug("Message received",
-				"user_id", msg.UserID,
-				"type", msg.Metadata["message_type"],
-				"content_length", len(msg.Content),
-			)
-		}
-	default:
-		if c.logger != nil {
-			c.logger.Warn("Incoming channel is full, message dropped")
-		}
+	// Send message to the incoming queue, which counts and logs dropped messages
+	if c.incoming.Push(ctx, msg) && c.logger != nil {
+		c.logger.Debug("Message received",
+			"user_id", msg.UserID,
+			"type", msg.Metadata["message_type"],
+			"content_length", len(msg.Content),
+		)
 	}
 }
 
 // handleCallbackQuery processes callback queries from inline buttons
-func (c *Connector) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
+func (c *Connector) handleCallbackQuery(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	// Create message struct from callback query
 	msg := &channels.Message{
 		UserID:    formatUserID(callback.From.ID, callback.Message.Chat.ID),
@@ -386,19 +501,12 @@ func (c *Connector) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
 		c.bot.Request(callbackCfg)
 	}
 
-	// Send message to incoming channel
-	select {
-	case c.incoming <- msg:
-		if c.logger != nil {
-			c.logger.Debug("Callback query received",
-				"user_id", msg.UserID,
-				"callback_data", callback.Data,
-			)
-		}
-	default:
-		if c.logger != nil {
-			c.logger.Warn("Incoming channel is full, callback dropped")
-		}
+	// Send message to the incoming queue, which counts and logs dropped messages
+	if c.incoming.Push(ctx, msg) && c.logger != nil {
+		c.logger.Debug("Callback query received",
+			"user_id", msg.UserID,
+			"callback_data", callback.Data,
+		)
 	}
 }
 
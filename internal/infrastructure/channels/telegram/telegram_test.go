@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
 	"github.com/atumaikin/nexflow/internal/infrastructure/channels"
 	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -71,7 +76,15 @@ func (m *MockSessionRepository) FindByID(ctx context.Context, id string) (*entit
 	return args.Get(0).(*entity.Session), args.Error(1)
 }
 
-func (m *MockSessionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Session, error) {
+func (m *MockSessionRepository) List(ctx context.Context, opts repository.QueryOptions) ([]*entity.Session, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindByUserID(ctx context.Context, userID string, opts repository.QueryOptions) ([]*entity.Session, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -104,6 +117,20 @@ func TestNewConnector(t *testing.T) {
 	assert.False(t, connector.IsRunning())
 }
 
+func TestConnector_Name_Configured(t *testing.T) {
+	cfg := config.TelegramConfig{
+		Enabled:      true,
+		Name:         "telegram-support",
+		BotToken:     "test_token",
+		AllowedChats: []string{"123456789"},
+		Workspace:    "support",
+	}
+
+	connector := NewConnector(cfg, new(MockUserRepository), nil, nil)
+
+	assert.Equal(t, "telegram-support", connector.Name())
+}
+
 func TestConnector_Start_InvalidToken(t *testing.T) {
 	cfg := config.TelegramConfig{
 		Enabled:      true,
@@ -717,13 +744,13 @@ func TestHandleMessage(t *testing.T) {
 	// Start connector to initialize incoming channel
 	connector.mu.Lock()
 	connector.running = true
-	connector.incoming = make(chan *channels.Message, 100)
+	connector.incoming = connector.newIncomingQueue()
 	connector.mu.Unlock()
 
 	defer func() {
 		connector.mu.Lock()
 		connector.running = false
-		close(connector.incoming)
+		connector.incoming.Close()
 		connector.mu.Unlock()
 	}()
 
@@ -739,7 +766,7 @@ func TestHandleMessage(t *testing.T) {
 
 		// Wait for message to be processed
 		select {
-		case msg := <-connector.incoming:
+		case msg := <-connector.incoming.Messages():
 			assert.NotNil(t, msg)
 			assert.Equal(t, "456:123", msg.UserID)
 			assert.Equal(t, "123", msg.ChannelID)
@@ -749,6 +776,21 @@ func TestHandleMessage(t *testing.T) {
 	})
 }
 
+// TestHandleUpdate_RecoversPanics tests that an update whose handling panics is dropped
+func TestHandleUpdate_RecoversPanics(t *testing.T) {
+	connector := NewConnector(config.TelegramConfig{Enabled: true, BotToken: "test_token"}, new(MockUserRepository), nil, nil)
+	recoverer := crash.NewRecoverer(logging.NewNoopLogger())
+
+	// A message without sender, as posted in channels
+	update := tgbotapi.Update{UpdateID: 7, Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 123}, Text: "Hello"}}
+	err := connector.handleUpdate(context.Background(), recoverer, update)
+
+	var panicErr *crash.PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, int64(1), recoverer.Metrics().Recovered("telegram").Get())
+	assert.NoError(t, connector.handleUpdate(context.Background(), recoverer, tgbotapi.Update{UpdateID: 8}))
+}
+
 // TestHandleCallbackQuery tests processing of callback queries
 func TestHandleCallbackQuery(t *testing.T) {
 	cfg := config.TelegramConfig{
@@ -763,13 +805,13 @@ func TestHandleCallbackQuery(t *testing.T) {
 	// Start the connector to initialize the incoming channel
 	connector.mu.Lock()
 	connector.running = true
-	connector.incoming = make(chan *channels.Message, 100)
+	connector.incoming = connector.newIncomingQueue()
 	connector.mu.Unlock()
 
 	defer func() {
 		connector.mu.Lock()
 		connector.running = false
-		close(connector.incoming)
+		connector.incoming.Close()
 		connector.mu.Unlock()
 	}()
 
@@ -789,10 +831,10 @@ func TestHandleCallbackQuery(t *testing.T) {
 			Data: "button_clicked",
 		}
 
-		go connector.handleCallbackQuery(callback)
+		go connector.handleCallbackQuery(context.Background(), callback)
 
 		select {
-		case msg := <-connector.incoming:
+		case msg := <-connector.incoming.Messages():
 			assert.NotNil(t, msg)
 			assert.Equal(t, "456:789", msg.UserID)
 			assert.Equal(t, "789", msg.ChannelID)
@@ -817,13 +859,13 @@ func TestMessageTypesIntegration(t *testing.T) {
 
 	connector.mu.Lock()
 	connector.running = true
-	connector.incoming = make(chan *channels.Message, 100)
+	connector.incoming = connector.newIncomingQueue()
 	connector.mu.Unlock()
 
 	defer func() {
 		connector.mu.Lock()
 		connector.running = false
-		close(connector.incoming)
+		connector.incoming.Close()
 		connector.mu.Unlock()
 	}()
 
@@ -893,7 +935,7 @@ func TestMessageTypesIntegration(t *testing.T) {
 			go connector.handleMessage(ctx, tt.message)
 
 			select {
-			case msg := <-connector.incoming:
+			case msg := <-connector.incoming.Messages():
 				assert.Equal(t, tt.expected, msg.Metadata[tt.typeKey])
 			}
 		})
@@ -1340,3 +1382,55 @@ func TestNewConnector_WithRateLimiter(t *testing.T) {
 
 	assert.NotNil(t, connector.rateLimiter)
 }
+
+// TestDownloadFile tests downloading a file through a fake Bot API
+func TestDownloadFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest_token/getMe":
+			fmt.Fprint(w, `{"ok":true,"result":{"id":1,"is_bot":true,"username":"test_bot"}}`)
+		case "/bottest_token/getFile":
+			switch r.FormValue("file_id") {
+			case "big":
+				fmt.Fprint(w, `{"ok":true,"result":{"file_id":"big","file_size":5000,"file_path":"documents/big.pdf"}}`)
+			case "unsized":
+				fmt.Fprint(w, `{"ok":true,"result":{"file_id":"unsized","file_path":"documents/file_1.txt"}}`)
+			default:
+				fmt.Fprint(w, `{"ok":true,"result":{"file_id":"doc","file_size":11,"file_path":"documents/file_1.txt"}}`)
+			}
+		case "/file/bottest_token/documents/file_1.txt":
+			fmt.Fprint(w, "hello world")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.TelegramConfig{Enabled: true, BotToken: "test_token"}
+	connector := NewConnector(cfg, new(MockUserRepository), nil, nil)
+
+	_, err := connector.DownloadFile(context.Background(), "doc", 100)
+	assert.Error(t, err, "a stopped connector cannot download files")
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test_token", server.URL+"/bot%s/%s")
+	if !assert.NoError(t, err) {
+		return
+	}
+	connector.mu.Lock()
+	connector.bot = bot
+	connector.running = true
+	connector.fileEndpoint = server.URL + "/file/bot%s/%s"
+	connector.mu.Unlock()
+
+	data, err := connector.DownloadFile(context.Background(), "doc", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+
+	// The size reported by Telegram is over the limit
+	_, err = connector.DownloadFile(context.Background(), "big", 100)
+	assert.ErrorIs(t, err, channels.ErrFileTooLarge)
+
+	// The downloaded content is over the limit
+	_, err = connector.DownloadFile(context.Background(), "unsized", 5)
+	assert.True(t, errors.Is(err, channels.ErrFileTooLarge), "expected ErrFileTooLarge, got %v", err)
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// chatService implements nexflowv1.ChatServiceServer with usecase.ChatUseCase
+type chatService struct {
+	nexflowv1.UnimplementedChatServiceServer
+
+	chat   *usecase.ChatUseCase
+	logger logging.Logger
+}
+
+// SendMessage sends a message and returns the complete reply
+func (s *chatService) SendMessage(ctx context.Context, req *nexflowv1.SendMessageRequest) (*nexflowv1.SendMessageResponse, error) {
+	streamReq := streamRequestFromProto(req)
+	if err := validateRequest(&streamReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.chat.StreamMessage(ctx, streamReq, func(string) error { return nil })
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to send grpc message", "error", err, "session_id", req.GetSessionId())
+		return nil, err
+	}
+	return &nexflowv1.SendMessageResponse{Message: messageToProto(resp.Message)}, nil
+}
+
+// StreamMessage sends a message and streams the reply as it is generated, ending with the saved reply
+func (s *chatService) StreamMessage(req *nexflowv1.SendMessageRequest, stream grpc.ServerStreamingServer[nexflowv1.ChatStreamEvent]) error {
+	streamReq := streamRequestFromProto(req)
+	if err := validateRequest(&streamReq); err != nil {
+		return err
+	}
+
+	resp, err := s.chat.StreamMessage(stream.Context(), streamReq, func(token string) error {
+		return stream.Send(&nexflowv1.ChatStreamEvent{Event: &nexflowv1.ChatStreamEvent_Token{Token: token}})
+	})
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to stream grpc message", "error", err, "session_id", req.GetSessionId())
+		return err
+	}
+	return stream.Send(&nexflowv1.ChatStreamEvent{Event: &nexflowv1.ChatStreamEvent_Done{Done: messageToProto(resp.Message)}})
+}
+
+// GetConversation returns a page of session messages
+func (s *chatService) GetConversation(ctx context.Context, req *nexflowv1.GetConversationRequest) (*nexflowv1.GetConversationResponse, error) {
+	page, err := pageFromProto(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.chat.GetConversation(ctx, req.GetSessionId(), page)
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		return nil, err
+	}
+
+	messages := make([]*nexflowv1.Message, 0, len(resp.Messages))
+	for _, m := range resp.Messages {
+		messages = append(messages, messageToProto(m))
+	}
+	return &nexflowv1.GetConversationResponse{Messages: messages, NextCursor: resp.NextCursor}, nil
+}
+
+func streamRequestFromProto(req *nexflowv1.SendMessageRequest) dto.StreamMessageRequest {
+	return dto.StreamMessageRequest{
+		SessionID: req.GetSessionId(),
+		UserID:    req.GetUserId(),
+		Content:   req.GetContent(),
+		Options:   optionsFromProto(req.GetOptions()),
+	}
+}
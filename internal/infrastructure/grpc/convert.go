@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+)
+
+func messageToProto(m *dto.MessageDTO) *nexflowv1.Message {
+	if m == nil {
+		return nil
+	}
+	return &nexflowv1.Message{
+		Id:        m.ID,
+		SessionId: m.SessionID,
+		Role:      m.Role,
+		Content:   m.Content,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func sessionToProto(s *dto.SessionDTO) *nexflowv1.Session {
+	if s == nil {
+		return nil
+	}
+	return &nexflowv1.Session{
+		Id:        s.ID,
+		UserId:    s.UserID,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+		Pinned:    s.Pinned,
+	}
+}
+
+func skillToProto(s *dto.SkillDTO) *nexflowv1.Skill {
+	if s == nil {
+		return nil
+	}
+	return &nexflowv1.Skill{
+		Id:          s.ID,
+		Name:        s.Name,
+		Version:     s.Version,
+		Location:    s.Location,
+		Permissions: s.Permissions,
+		Metadata:    s.Metadata,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+func scheduleToProto(s *dto.ScheduleDTO) *nexflowv1.Schedule {
+	if s == nil {
+		return nil
+	}
+	return &nexflowv1.Schedule{
+		Id:              s.ID,
+		Skill:           s.Skill,
+		CronExpression:  s.CronExpression,
+		Input:           s.Input,
+		Enabled:         s.Enabled,
+		Timezone:        s.Timezone,
+		JitterSeconds:   int32(s.JitterSeconds),
+		CreatedAt:       s.CreatedAt,
+		Type:            s.Type,
+		RunAt:           s.RunAt,
+		MissedRunPolicy: s.MissedRunPolicy,
+		TargetConnector: s.TargetConnector,
+		TargetUserId:    s.TargetUserID,
+	}
+}
+
+// pageFromProto converts a page request, rejecting a negative limit or offset like the HTTP API
+func pageFromProto(p *nexflowv1.PageRequest) (dto.PageRequest, error) {
+	if p.GetLimit() < 0 || p.GetOffset() < 0 {
+		return dto.PageRequest{}, status.Error(codes.InvalidArgument, "limit and offset must be non-negative")
+	}
+	return dto.PageRequest{
+		Limit:  int(p.GetLimit()),
+		Offset: int(p.GetOffset()),
+		Since:  p.GetSince(),
+		Until:  p.GetUntil(),
+		Cursor: p.GetCursor(),
+	}, nil
+}
+
+func optionsFromProto(o *nexflowv1.MessageOptions) dto.MessageOptions {
+	return dto.MessageOptions{
+		Model:     o.GetModel(),
+		MaxTokens: int(o.GetMaxTokens()),
+	}
+}
+
+// inputFromProto converts a JSON object sent as a google.protobuf.Struct, nil when it is absent
+func inputFromProto(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
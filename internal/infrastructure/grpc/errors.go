@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/validation"
+)
+
+// codeForError maps an error returned by a use case to a gRPC status code,
+// following the HTTP statuses the REST API returns for the same errors
+func codeForError(err error) codes.Code {
+	switch {
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, ports.ErrConnectorNotFound):
+		return codes.NotFound
+	case errors.Is(err, repository.ErrConflict):
+		return codes.AlreadyExists
+	case errors.Is(err, ports.ErrConnectorState), errors.Is(err, ports.ErrSkillDisabled), errors.Is(err, ports.ErrReprocessFailed),
+		errors.Is(err, ports.ErrTaskCancelled), errors.Is(err, valueobject.ErrInvalidTaskTransition):
+		return codes.FailedPrecondition
+	case errors.Is(err, ports.ErrLLMProviderNotFound), errors.Is(err, ports.ErrLLMModelNotAllowed), errors.Is(err, ports.ErrNotReplayable):
+		return codes.InvalidArgument
+	case errors.Is(err, ports.ErrDeadLettersDisabled), errors.Is(err, ports.ErrInstructionsDisabled), errors.Is(err, ports.ErrReplayDisabled),
+		errors.Is(err, ports.ErrSchedulerDisabled), errors.Is(err, usecase.ErrMessageJobQueueFull), errors.Is(err, usecase.ErrMessageJobsStopped):
+		return codes.Unavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	default:
+		return codes.Internal
+	}
+}
+
+// useCaseError converts the result of a use case into a gRPC status error, nil on success.
+// Errors are mapped by codeForError; failures without an error are invalid requests.
+func useCaseError(success bool, message string, err error) error {
+	switch {
+	case err != nil:
+		if message == "" {
+			message = err.Error()
+		}
+		return status.Error(codeForError(err), message)
+	case !success:
+		return status.Error(codes.InvalidArgument, message)
+	default:
+		return nil
+	}
+}
+
+// validateRequest validates a request DTO against its `validate` struct tags
+func validateRequest(req any) error {
+	if err := validation.Struct(req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
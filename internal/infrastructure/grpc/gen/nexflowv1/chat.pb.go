@@ -0,0 +1,565 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: nexflow/v1/chat.proto
+
+package nexflowv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Message mirrors dto.MessageDTO
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"` // "user", "assistant", "system"
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO 8601 format
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_nexflow_v1_chat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_chat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_chat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Message) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Message) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Message) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Message) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type MessageOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	MaxTokens     int32                  `protobuf:"varint,2,opt,name=max_tokens,json=maxTokens,proto3" json:"max_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageOptions) Reset() {
+	*x = MessageOptions{}
+	mi := &file_nexflow_v1_chat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageOptions) ProtoMessage() {}
+
+func (x *MessageOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_chat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageOptions.ProtoReflect.Descriptor instead.
+func (*MessageOptions) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_chat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MessageOptions) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *MessageOptions) GetMaxTokens() int32 {
+	if x != nil {
+		return x.MaxTokens
+	}
+	return 0
+}
+
+// SendMessageRequest mirrors dto.StreamMessageRequest
+type SendMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Existing session to continue; empty starts a new session
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`          // User the new session is created for
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Options       *MessageOptions        `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMessageRequest) Reset() {
+	*x = SendMessageRequest{}
+	mi := &file_nexflow_v1_chat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageRequest) ProtoMessage() {}
+
+func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_chat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendMessageRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_chat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SendMessageRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetOptions() *MessageOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type SendMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *Message               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"` // Saved reply
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMessageResponse) Reset() {
+	*x = SendMessageResponse{}
+	mi := &file_nexflow_v1_chat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageResponse) ProtoMessage() {}
+
+func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_chat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_chat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SendMessageResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// ChatStreamEvent mirrors dto.ChatStreamEvent; errors end the stream with a gRPC status
+type ChatStreamEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*ChatStreamEvent_Token
+	//	*ChatStreamEvent_Done
+	Event         isChatStreamEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatStreamEvent) Reset() {
+	*x = ChatStreamEvent{}
+	mi := &file_nexflow_v1_chat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatStreamEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatStreamEvent) ProtoMessage() {}
+
+func (x *ChatStreamEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_chat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatStreamEvent.ProtoReflect.Descriptor instead.
+func (*ChatStreamEvent) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_chat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChatStreamEvent) GetEvent() isChatStreamEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *ChatStreamEvent) GetToken() string {
+	if x != nil {
+		if x, ok := x.Event.(*ChatStreamEvent_Token); ok {
+			return x.Token
+		}
+	}
+	return ""
+}
+
+func (x *ChatStreamEvent) GetDone() *Message {
+	if x != nil {
+		if x, ok := x.Event.(*ChatStreamEvent_Done); ok {
+			return x.Done
+		}
+	}
+	return nil
+}
+
+type isChatStreamEvent_Event interface {
+	isChatStreamEvent_Event()
+}
+
+type ChatStreamEvent_Token struct {
+	Token string `protobuf:"bytes,1,opt,name=token,proto3,oneof"` // Chunk of the reply
+}
+
+type ChatStreamEvent_Done struct {
+	Done *Message `protobuf:"bytes,2,opt,name=done,proto3,oneof"` // Saved reply, sent last
+}
+
+func (*ChatStreamEvent_Token) isChatStreamEvent_Event() {}
+
+func (*ChatStreamEvent_Done) isChatStreamEvent_Event() {}
+
+type GetConversationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Page          *PageRequest           `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConversationRequest) Reset() {
+	*x = GetConversationRequest{}
+	mi := &file_nexflow_v1_chat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversationRequest) ProtoMessage() {}
+
+func (x *GetConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversationRequest.ProtoReflect.Descriptor instead.
+func (*GetConversationRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetConversationRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *GetConversationRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type GetConversationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConversationResponse) Reset() {
+	*x = GetConversationResponse{}
+	mi := &file_nexflow_v1_chat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversationResponse) ProtoMessage() {}
+
+func (x *GetConversationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_chat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversationResponse.ProtoReflect.Descriptor instead.
+func (*GetConversationResponse) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_chat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetConversationResponse) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetConversationResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_nexflow_v1_chat_proto protoreflect.FileDescriptor
+
+const file_nexflow_v1_chat_proto_rawDesc = "" +
+	"\n" +
+	"\x15nexflow/v1/chat.proto\x12\n" +
+	"nexflow.v1\x1a\x17nexflow/v1/common.proto\"\x85\x01\n" +
+	"\aMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\"E\n" +
+	"\x0eMessageOptions\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12\x1d\n" +
+	"\n" +
+	"max_tokens\x18\x02 \x01(\x05R\tmaxTokens\"\x9c\x01\n" +
+	"\x12SendMessageRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x124\n" +
+	"\aoptions\x18\x04 \x01(\v2\x1a.nexflow.v1.MessageOptionsR\aoptions\"D\n" +
+	"\x13SendMessageResponse\x12-\n" +
+	"\amessage\x18\x01 \x01(\v2\x13.nexflow.v1.MessageR\amessage\"]\n" +
+	"\x0fChatStreamEvent\x12\x16\n" +
+	"\x05token\x18\x01 \x01(\tH\x00R\x05token\x12)\n" +
+	"\x04done\x18\x02 \x01(\v2\x13.nexflow.v1.MessageH\x00R\x04doneB\a\n" +
+	"\x05event\"d\n" +
+	"\x16GetConversationRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12+\n" +
+	"\x04page\x18\x02 \x01(\v2\x17.nexflow.v1.PageRequestR\x04page\"k\n" +
+	"\x17GetConversationResponse\x12/\n" +
+	"\bmessages\x18\x01 \x03(\v2\x13.nexflow.v1.MessageR\bmessages\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor2\x89\x02\n" +
+	"\vChatService\x12N\n" +
+	"\vSendMessage\x12\x1e.nexflow.v1.SendMessageRequest\x1a\x1f.nexflow.v1.SendMessageResponse\x12N\n" +
+	"\rStreamMessage\x12\x1e.nexflow.v1.SendMessageRequest\x1a\x1b.nexflow.v1.ChatStreamEvent0\x01\x12Z\n" +
+	"\x0fGetConversation\x12\".nexflow.v1.GetConversationRequest\x1a#.nexflow.v1.GetConversationResponseBIZGgithub.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1b\x06proto3"
+
+var (
+	file_nexflow_v1_chat_proto_rawDescOnce sync.Once
+	file_nexflow_v1_chat_proto_rawDescData []byte
+)
+
+func file_nexflow_v1_chat_proto_rawDescGZIP() []byte {
+	file_nexflow_v1_chat_proto_rawDescOnce.Do(func() {
+		file_nexflow_v1_chat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nexflow_v1_chat_proto_rawDesc), len(file_nexflow_v1_chat_proto_rawDesc)))
+	})
+	return file_nexflow_v1_chat_proto_rawDescData
+}
+
+var file_nexflow_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_nexflow_v1_chat_proto_goTypes = []any{
+	(*Message)(nil),                 // 0: nexflow.v1.Message
+	(*MessageOptions)(nil),          // 1: nexflow.v1.MessageOptions
+	(*SendMessageRequest)(nil),      // 2: nexflow.v1.SendMessageRequest
+	(*SendMessageResponse)(nil),     // 3: nexflow.v1.SendMessageResponse
+	(*ChatStreamEvent)(nil),         // 4: nexflow.v1.ChatStreamEvent
+	(*GetConversationRequest)(nil),  // 5: nexflow.v1.GetConversationRequest
+	(*GetConversationResponse)(nil), // 6: nexflow.v1.GetConversationResponse
+	(*PageRequest)(nil),             // 7: nexflow.v1.PageRequest
+}
+var file_nexflow_v1_chat_proto_depIdxs = []int32{
+	1, // 0: nexflow.v1.SendMessageRequest.options:type_name -> nexflow.v1.MessageOptions
+	0, // 1: nexflow.v1.SendMessageResponse.message:type_name -> nexflow.v1.Message
+	0, // 2: nexflow.v1.ChatStreamEvent.done:type_name -> nexflow.v1.Message
+	7, // 3: nexflow.v1.GetConversationRequest.page:type_name -> nexflow.v1.PageRequest
+	0, // 4: nexflow.v1.GetConversationResponse.messages:type_name -> nexflow.v1.Message
+	2, // 5: nexflow.v1.ChatService.SendMessage:input_type -> nexflow.v1.SendMessageRequest
+	2, // 6: nexflow.v1.ChatService.StreamMessage:input_type -> nexflow.v1.SendMessageRequest
+	5, // 7: nexflow.v1.ChatService.GetConversation:input_type -> nexflow.v1.GetConversationRequest
+	3, // 8: nexflow.v1.ChatService.SendMessage:output_type -> nexflow.v1.SendMessageResponse
+	4, // 9: nexflow.v1.ChatService.StreamMessage:output_type -> nexflow.v1.ChatStreamEvent
+	6, // 10: nexflow.v1.ChatService.GetConversation:output_type -> nexflow.v1.GetConversationResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_nexflow_v1_chat_proto_init() }
+func file_nexflow_v1_chat_proto_init() {
+	if File_nexflow_v1_chat_proto != nil {
+		return
+	}
+	file_nexflow_v1_common_proto_init()
+	file_nexflow_v1_chat_proto_msgTypes[4].OneofWrappers = []any{
+		(*ChatStreamEvent_Token)(nil),
+		(*ChatStreamEvent_Done)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nexflow_v1_chat_proto_rawDesc), len(file_nexflow_v1_chat_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nexflow_v1_chat_proto_goTypes,
+		DependencyIndexes: file_nexflow_v1_chat_proto_depIdxs,
+		MessageInfos:      file_nexflow_v1_chat_proto_msgTypes,
+	}.Build()
+	File_nexflow_v1_chat_proto = out.File
+	file_nexflow_v1_chat_proto_goTypes = nil
+	file_nexflow_v1_chat_proto_depIdxs = nil
+}
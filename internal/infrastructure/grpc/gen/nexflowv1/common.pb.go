@@ -0,0 +1,160 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: nexflow/v1/common.proto
+
+package nexflowv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PageRequest mirrors dto.PageRequest
+type PageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`   // Maximum number of items to return (0 = server default)
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // Number of items to skip
+	Since         string                 `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`    // ISO 8601, keep items created at or after this time
+	Until         string                 `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"`    // ISO 8601, keep items created before this time
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`  // Opaque cursor returned as next_cursor by the previous page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageRequest) Reset() {
+	*x = PageRequest{}
+	mi := &file_nexflow_v1_common_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageRequest) ProtoMessage() {}
+
+func (x *PageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_common_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageRequest.ProtoReflect.Descriptor instead.
+func (*PageRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_common_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PageRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PageRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PageRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *PageRequest) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *PageRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+var File_nexflow_v1_common_proto protoreflect.FileDescriptor
+
+const file_nexflow_v1_common_proto_rawDesc = "" +
+	"\n" +
+	"\x17nexflow/v1/common.proto\x12\n" +
+	"nexflow.v1\"\x7f\n" +
+	"\vPageRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\tR\x05since\x12\x14\n" +
+	"\x05until\x18\x04 \x01(\tR\x05until\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursorBIZGgithub.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1b\x06proto3"
+
+var (
+	file_nexflow_v1_common_proto_rawDescOnce sync.Once
+	file_nexflow_v1_common_proto_rawDescData []byte
+)
+
+func file_nexflow_v1_common_proto_rawDescGZIP() []byte {
+	file_nexflow_v1_common_proto_rawDescOnce.Do(func() {
+		file_nexflow_v1_common_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nexflow_v1_common_proto_rawDesc), len(file_nexflow_v1_common_proto_rawDesc)))
+	})
+	return file_nexflow_v1_common_proto_rawDescData
+}
+
+var file_nexflow_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_nexflow_v1_common_proto_goTypes = []any{
+	(*PageRequest)(nil), // 0: nexflow.v1.PageRequest
+}
+var file_nexflow_v1_common_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_nexflow_v1_common_proto_init() }
+func file_nexflow_v1_common_proto_init() {
+	if File_nexflow_v1_common_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nexflow_v1_common_proto_rawDesc), len(file_nexflow_v1_common_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nexflow_v1_common_proto_goTypes,
+		DependencyIndexes: file_nexflow_v1_common_proto_depIdxs,
+		MessageInfos:      file_nexflow_v1_common_proto_msgTypes,
+	}.Build()
+	File_nexflow_v1_common_proto = out.File
+	file_nexflow_v1_common_proto_goTypes = nil
+	file_nexflow_v1_common_proto_depIdxs = nil
+}
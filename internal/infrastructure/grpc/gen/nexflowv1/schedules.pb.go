@@ -0,0 +1,524 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: nexflow/v1/schedules.proto
+
+package nexflowv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Schedule mirrors dto.ScheduleDTO
+type Schedule struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Skill           string                 `protobuf:"bytes,2,opt,name=skill,proto3" json:"skill,omitempty"`                                         // Name of the skill to execute
+	CronExpression  string                 `protobuf:"bytes,3,opt,name=cron_expression,json=cronExpression,proto3" json:"cron_expression,omitempty"` // Cron syntax (e.g., "0 * * * *")
+	Input           string                 `protobuf:"bytes,4,opt,name=input,proto3" json:"input,omitempty"`                                         // Input parameters (JSON)
+	Enabled         bool                   `protobuf:"varint,5,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Timezone        string                 `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`                                         // IANA timezone (e.g., "Europe/Moscow")
+	JitterSeconds   int32                  `protobuf:"varint,7,opt,name=jitter_seconds,json=jitterSeconds,proto3" json:"jitter_seconds,omitempty"`         // Maximum random start delay in seconds
+	CreatedAt       string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                      // ISO 8601 format
+	Type            string                 `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`                                                 // "cron" (recurring) or "once" (one-shot)
+	RunAt           string                 `protobuf:"bytes,10,opt,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`                                 // ISO 8601 fire time of a one-shot schedule
+	MissedRunPolicy string                 `protobuf:"bytes,11,opt,name=missed_run_policy,json=missedRunPolicy,proto3" json:"missed_run_policy,omitempty"` // "skip", "run_once" or "run_all"
+	TargetConnector string                 `protobuf:"bytes,12,opt,name=target_connector,json=targetConnector,proto3" json:"target_connector,omitempty"`   // Connector the run output is delivered to
+	TargetUserId    string                 `protobuf:"bytes,13,opt,name=target_user_id,json=targetUserId,proto3" json:"target_user_id,omitempty"`          // Connector-specific user or chat ID
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_schedules_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Schedule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Schedule) GetSkill() string {
+	if x != nil {
+		return x.Skill
+	}
+	return ""
+}
+
+func (x *Schedule) GetCronExpression() string {
+	if x != nil {
+		return x.CronExpression
+	}
+	return ""
+}
+
+func (x *Schedule) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *Schedule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Schedule) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Schedule) GetJitterSeconds() int32 {
+	if x != nil {
+		return x.JitterSeconds
+	}
+	return 0
+}
+
+func (x *Schedule) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Schedule) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Schedule) GetRunAt() string {
+	if x != nil {
+		return x.RunAt
+	}
+	return ""
+}
+
+func (x *Schedule) GetMissedRunPolicy() string {
+	if x != nil {
+		return x.MissedRunPolicy
+	}
+	return ""
+}
+
+func (x *Schedule) GetTargetConnector() string {
+	if x != nil {
+		return x.TargetConnector
+	}
+	return ""
+}
+
+func (x *Schedule) GetTargetUserId() string {
+	if x != nil {
+		return x.TargetUserId
+	}
+	return ""
+}
+
+// CreateScheduleRequest mirrors dto.CreateScheduleRequest
+type CreateScheduleRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Skill           string                 `protobuf:"bytes,1,opt,name=skill,proto3" json:"skill,omitempty"`
+	CronExpression  string                 `protobuf:"bytes,2,opt,name=cron_expression,json=cronExpression,proto3" json:"cron_expression,omitempty"`
+	Input           *structpb.Struct       `protobuf:"bytes,3,opt,name=input,proto3" json:"input,omitempty"`
+	Timezone        string                 `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	JitterSeconds   int32                  `protobuf:"varint,5,opt,name=jitter_seconds,json=jitterSeconds,proto3" json:"jitter_seconds,omitempty"`
+	RunAt           string                 `protobuf:"bytes,6,opt,name=run_at,json=runAt,proto3" json:"run_at,omitempty"`
+	MissedRunPolicy string                 `protobuf:"bytes,7,opt,name=missed_run_policy,json=missedRunPolicy,proto3" json:"missed_run_policy,omitempty"`
+	TargetConnector string                 `protobuf:"bytes,8,opt,name=target_connector,json=targetConnector,proto3" json:"target_connector,omitempty"`
+	TargetUserId    string                 `protobuf:"bytes,9,opt,name=target_user_id,json=targetUserId,proto3" json:"target_user_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateScheduleRequest) Reset() {
+	*x = CreateScheduleRequest{}
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateScheduleRequest) ProtoMessage() {}
+
+func (x *CreateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*CreateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_schedules_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateScheduleRequest) GetSkill() string {
+	if x != nil {
+		return x.Skill
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetCronExpression() string {
+	if x != nil {
+		return x.CronExpression
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *CreateScheduleRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetJitterSeconds() int32 {
+	if x != nil {
+		return x.JitterSeconds
+	}
+	return 0
+}
+
+func (x *CreateScheduleRequest) GetRunAt() string {
+	if x != nil {
+		return x.RunAt
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetMissedRunPolicy() string {
+	if x != nil {
+		return x.MissedRunPolicy
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetTargetConnector() string {
+	if x != nil {
+		return x.TargetConnector
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetTargetUserId() string {
+	if x != nil {
+		return x.TargetUserId
+	}
+	return ""
+}
+
+type GetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_schedules_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetScheduleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skill         string                 `protobuf:"bytes,1,opt,name=skill,proto3" json:"skill,omitempty"` // Filter by skill name
+	EnabledOnly   bool                   `protobuf:"varint,2,opt,name=enabled_only,json=enabledOnly,proto3" json:"enabled_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesRequest) Reset() {
+	*x = ListSchedulesRequest{}
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesRequest) ProtoMessage() {}
+
+func (x *ListSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_schedules_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListSchedulesRequest) GetSkill() string {
+	if x != nil {
+		return x.Skill
+	}
+	return ""
+}
+
+func (x *ListSchedulesRequest) GetEnabledOnly() bool {
+	if x != nil {
+		return x.EnabledOnly
+	}
+	return false
+}
+
+type ListSchedulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedules     []*Schedule            `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesResponse) Reset() {
+	*x = ListSchedulesResponse{}
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesResponse) ProtoMessage() {}
+
+func (x *ListSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_schedules_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_schedules_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSchedulesResponse) GetSchedules() []*Schedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+var File_nexflow_v1_schedules_proto protoreflect.FileDescriptor
+
+const file_nexflow_v1_schedules_proto_rawDesc = "" +
+	"\n" +
+	"\x1anexflow/v1/schedules.proto\x12\n" +
+	"nexflow.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x93\x03\n" +
+	"\bSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05skill\x18\x02 \x01(\tR\x05skill\x12'\n" +
+	"\x0fcron_expression\x18\x03 \x01(\tR\x0ecronExpression\x12\x14\n" +
+	"\x05input\x18\x04 \x01(\tR\x05input\x12\x18\n" +
+	"\aenabled\x18\x05 \x01(\bR\aenabled\x12\x1a\n" +
+	"\btimezone\x18\x06 \x01(\tR\btimezone\x12%\n" +
+	"\x0ejitter_seconds\x18\a \x01(\x05R\rjitterSeconds\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x12\n" +
+	"\x04type\x18\t \x01(\tR\x04type\x12\x15\n" +
+	"\x06run_at\x18\n" +
+	" \x01(\tR\x05runAt\x12*\n" +
+	"\x11missed_run_policy\x18\v \x01(\tR\x0fmissedRunPolicy\x12)\n" +
+	"\x10target_connector\x18\f \x01(\tR\x0ftargetConnector\x12$\n" +
+	"\x0etarget_user_id\x18\r \x01(\tR\ftargetUserId\"\xdc\x02\n" +
+	"\x15CreateScheduleRequest\x12\x14\n" +
+	"\x05skill\x18\x01 \x01(\tR\x05skill\x12'\n" +
+	"\x0fcron_expression\x18\x02 \x01(\tR\x0ecronExpression\x12-\n" +
+	"\x05input\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x05input\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\x12%\n" +
+	"\x0ejitter_seconds\x18\x05 \x01(\x05R\rjitterSeconds\x12\x15\n" +
+	"\x06run_at\x18\x06 \x01(\tR\x05runAt\x12*\n" +
+	"\x11missed_run_policy\x18\a \x01(\tR\x0fmissedRunPolicy\x12)\n" +
+	"\x10target_connector\x18\b \x01(\tR\x0ftargetConnector\x12$\n" +
+	"\x0etarget_user_id\x18\t \x01(\tR\ftargetUserId\"$\n" +
+	"\x12GetScheduleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"O\n" +
+	"\x14ListSchedulesRequest\x12\x14\n" +
+	"\x05skill\x18\x01 \x01(\tR\x05skill\x12!\n" +
+	"\fenabled_only\x18\x02 \x01(\bR\venabledOnly\"K\n" +
+	"\x15ListSchedulesResponse\x122\n" +
+	"\tschedules\x18\x01 \x03(\v2\x14.nexflow.v1.ScheduleR\tschedules2\x87\x03\n" +
+	"\x0fScheduleService\x12I\n" +
+	"\x0eCreateSchedule\x12!.nexflow.v1.CreateScheduleRequest\x1a\x14.nexflow.v1.Schedule\x12C\n" +
+	"\vGetSchedule\x12\x1e.nexflow.v1.GetScheduleRequest\x1a\x14.nexflow.v1.Schedule\x12T\n" +
+	"\rListSchedules\x12 .nexflow.v1.ListSchedulesRequest\x1a!.nexflow.v1.ListSchedulesResponse\x12F\n" +
+	"\x0eDeleteSchedule\x12\x1e.nexflow.v1.GetScheduleRequest\x1a\x14.nexflow.v1.Schedule\x12F\n" +
+	"\x0eRunScheduleNow\x12\x1e.nexflow.v1.GetScheduleRequest\x1a\x14.nexflow.v1.ScheduleBIZGgithub.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1b\x06proto3"
+
+var (
+	file_nexflow_v1_schedules_proto_rawDescOnce sync.Once
+	file_nexflow_v1_schedules_proto_rawDescData []byte
+)
+
+func file_nexflow_v1_schedules_proto_rawDescGZIP() []byte {
+	file_nexflow_v1_schedules_proto_rawDescOnce.Do(func() {
+		file_nexflow_v1_schedules_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nexflow_v1_schedules_proto_rawDesc), len(file_nexflow_v1_schedules_proto_rawDesc)))
+	})
+	return file_nexflow_v1_schedules_proto_rawDescData
+}
+
+var file_nexflow_v1_schedules_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_nexflow_v1_schedules_proto_goTypes = []any{
+	(*Schedule)(nil),              // 0: nexflow.v1.Schedule
+	(*CreateScheduleRequest)(nil), // 1: nexflow.v1.CreateScheduleRequest
+	(*GetScheduleRequest)(nil),    // 2: nexflow.v1.GetScheduleRequest
+	(*ListSchedulesRequest)(nil),  // 3: nexflow.v1.ListSchedulesRequest
+	(*ListSchedulesResponse)(nil), // 4: nexflow.v1.ListSchedulesResponse
+	(*structpb.Struct)(nil),       // 5: google.protobuf.Struct
+}
+var file_nexflow_v1_schedules_proto_depIdxs = []int32{
+	5, // 0: nexflow.v1.CreateScheduleRequest.input:type_name -> google.protobuf.Struct
+	0, // 1: nexflow.v1.ListSchedulesResponse.schedules:type_name -> nexflow.v1.Schedule
+	1, // 2: nexflow.v1.ScheduleService.CreateSchedule:input_type -> nexflow.v1.CreateScheduleRequest
+	2, // 3: nexflow.v1.ScheduleService.GetSchedule:input_type -> nexflow.v1.GetScheduleRequest
+	3, // 4: nexflow.v1.ScheduleService.ListSchedules:input_type -> nexflow.v1.ListSchedulesRequest
+	2, // 5: nexflow.v1.ScheduleService.DeleteSchedule:input_type -> nexflow.v1.GetScheduleRequest
+	2, // 6: nexflow.v1.ScheduleService.RunScheduleNow:input_type -> nexflow.v1.GetScheduleRequest
+	0, // 7: nexflow.v1.ScheduleService.CreateSchedule:output_type -> nexflow.v1.Schedule
+	0, // 8: nexflow.v1.ScheduleService.GetSchedule:output_type -> nexflow.v1.Schedule
+	4, // 9: nexflow.v1.ScheduleService.ListSchedules:output_type -> nexflow.v1.ListSchedulesResponse
+	0, // 10: nexflow.v1.ScheduleService.DeleteSchedule:output_type -> nexflow.v1.Schedule
+	0, // 11: nexflow.v1.ScheduleService.RunScheduleNow:output_type -> nexflow.v1.Schedule
+	7, // [7:12] is the sub-list for method output_type
+	2, // [2:7] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_nexflow_v1_schedules_proto_init() }
+func file_nexflow_v1_schedules_proto_init() {
+	if File_nexflow_v1_schedules_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nexflow_v1_schedules_proto_rawDesc), len(file_nexflow_v1_schedules_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nexflow_v1_schedules_proto_goTypes,
+		DependencyIndexes: file_nexflow_v1_schedules_proto_depIdxs,
+		MessageInfos:      file_nexflow_v1_schedules_proto_msgTypes,
+	}.Build()
+	File_nexflow_v1_schedules_proto = out.File
+	file_nexflow_v1_schedules_proto_goTypes = nil
+	file_nexflow_v1_schedules_proto_depIdxs = nil
+}
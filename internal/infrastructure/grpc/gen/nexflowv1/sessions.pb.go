@@ -0,0 +1,392 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: nexflow/v1/sessions.proto
+
+package nexflowv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Session mirrors dto.SessionDTO
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO 8601 format
+	UpdatedAt     string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // ISO 8601 format
+	Pinned        bool                   `protobuf:"varint,5,opt,name=pinned,proto3" json:"pinned,omitempty"`                       // Whether the session is excluded from data retention
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_sessions_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_sessions_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateSessionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListUserSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          *PageRequest           `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserSessionsRequest) Reset() {
+	*x = ListUserSessionsRequest{}
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserSessionsRequest) ProtoMessage() {}
+
+func (x *ListUserSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_sessions_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListUserSessionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListUserSessionsRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListUserSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserSessionsResponse) Reset() {
+	*x = ListUserSessionsResponse{}
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserSessionsResponse) ProtoMessage() {}
+
+func (x *ListUserSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListUserSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_sessions_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListUserSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *ListUserSessionsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SetSessionPinnedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pinned        bool                   `protobuf:"varint,2,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSessionPinnedRequest) Reset() {
+	*x = SetSessionPinnedRequest{}
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSessionPinnedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSessionPinnedRequest) ProtoMessage() {}
+
+func (x *SetSessionPinnedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_sessions_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSessionPinnedRequest.ProtoReflect.Descriptor instead.
+func (*SetSessionPinnedRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_sessions_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetSessionPinnedRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetSessionPinnedRequest) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+var File_nexflow_v1_sessions_proto protoreflect.FileDescriptor
+
+const file_nexflow_v1_sessions_proto_rawDesc = "" +
+	"\n" +
+	"\x19nexflow/v1/sessions.proto\x12\n" +
+	"nexflow.v1\x1a\x17nexflow/v1/common.proto\"\x88\x01\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\x12\x16\n" +
+	"\x06pinned\x18\x05 \x01(\bR\x06pinned\"/\n" +
+	"\x14CreateSessionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"_\n" +
+	"\x17ListUserSessionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12+\n" +
+	"\x04page\x18\x02 \x01(\v2\x17.nexflow.v1.PageRequestR\x04page\"l\n" +
+	"\x18ListUserSessionsResponse\x12/\n" +
+	"\bsessions\x18\x01 \x03(\v2\x13.nexflow.v1.SessionR\bsessions\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"A\n" +
+	"\x17SetSessionPinnedRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06pinned\x18\x02 \x01(\bR\x06pinned2\x85\x02\n" +
+	"\x0eSessionService\x12F\n" +
+	"\rCreateSession\x12 .nexflow.v1.CreateSessionRequest\x1a\x13.nexflow.v1.Session\x12]\n" +
+	"\x10ListUserSessions\x12#.nexflow.v1.ListUserSessionsRequest\x1a$.nexflow.v1.ListUserSessionsResponse\x12L\n" +
+	"\x10SetSessionPinned\x12#.nexflow.v1.SetSessionPinnedRequest\x1a\x13.nexflow.v1.SessionBIZGgithub.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1b\x06proto3"
+
+var (
+	file_nexflow_v1_sessions_proto_rawDescOnce sync.Once
+	file_nexflow_v1_sessions_proto_rawDescData []byte
+)
+
+func file_nexflow_v1_sessions_proto_rawDescGZIP() []byte {
+	file_nexflow_v1_sessions_proto_rawDescOnce.Do(func() {
+		file_nexflow_v1_sessions_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nexflow_v1_sessions_proto_rawDesc), len(file_nexflow_v1_sessions_proto_rawDesc)))
+	})
+	return file_nexflow_v1_sessions_proto_rawDescData
+}
+
+var file_nexflow_v1_sessions_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_nexflow_v1_sessions_proto_goTypes = []any{
+	(*Session)(nil),                  // 0: nexflow.v1.Session
+	(*CreateSessionRequest)(nil),     // 1: nexflow.v1.CreateSessionRequest
+	(*ListUserSessionsRequest)(nil),  // 2: nexflow.v1.ListUserSessionsRequest
+	(*ListUserSessionsResponse)(nil), // 3: nexflow.v1.ListUserSessionsResponse
+	(*SetSessionPinnedRequest)(nil),  // 4: nexflow.v1.SetSessionPinnedRequest
+	(*PageRequest)(nil),              // 5: nexflow.v1.PageRequest
+}
+var file_nexflow_v1_sessions_proto_depIdxs = []int32{
+	5, // 0: nexflow.v1.ListUserSessionsRequest.page:type_name -> nexflow.v1.PageRequest
+	0, // 1: nexflow.v1.ListUserSessionsResponse.sessions:type_name -> nexflow.v1.Session
+	1, // 2: nexflow.v1.SessionService.CreateSession:input_type -> nexflow.v1.CreateSessionRequest
+	2, // 3: nexflow.v1.SessionService.ListUserSessions:input_type -> nexflow.v1.ListUserSessionsRequest
+	4, // 4: nexflow.v1.SessionService.SetSessionPinned:input_type -> nexflow.v1.SetSessionPinnedRequest
+	0, // 5: nexflow.v1.SessionService.CreateSession:output_type -> nexflow.v1.Session
+	3, // 6: nexflow.v1.SessionService.ListUserSessions:output_type -> nexflow.v1.ListUserSessionsResponse
+	0, // 7: nexflow.v1.SessionService.SetSessionPinned:output_type -> nexflow.v1.Session
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_nexflow_v1_sessions_proto_init() }
+func file_nexflow_v1_sessions_proto_init() {
+	if File_nexflow_v1_sessions_proto != nil {
+		return
+	}
+	file_nexflow_v1_common_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nexflow_v1_sessions_proto_rawDesc), len(file_nexflow_v1_sessions_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nexflow_v1_sessions_proto_goTypes,
+		DependencyIndexes: file_nexflow_v1_sessions_proto_depIdxs,
+		MessageInfos:      file_nexflow_v1_sessions_proto_msgTypes,
+	}.Build()
+	File_nexflow_v1_sessions_proto = out.File
+	file_nexflow_v1_sessions_proto_goTypes = nil
+	file_nexflow_v1_sessions_proto_depIdxs = nil
+}
@@ -0,0 +1,429 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: nexflow/v1/skills.proto
+
+package nexflowv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Skill mirrors dto.SkillDTO
+type Skill struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // Unique skill name
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Location      string                 `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`                    // Path to skill directory
+	Permissions   string                 `protobuf:"bytes,5,opt,name=permissions,proto3" json:"permissions,omitempty"`              // JSON array of required permissions
+	Metadata      string                 `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`                    // JSON metadata (timeout, etc.)
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO 8601 format
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Skill) Reset() {
+	*x = Skill{}
+	mi := &file_nexflow_v1_skills_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Skill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Skill) ProtoMessage() {}
+
+func (x *Skill) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_skills_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Skill.ProtoReflect.Descriptor instead.
+func (*Skill) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_skills_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Skill) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Skill) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Skill) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Skill) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Skill) GetPermissions() string {
+	if x != nil {
+		return x.Permissions
+	}
+	return ""
+}
+
+func (x *Skill) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+func (x *Skill) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListSkillsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSkillsRequest) Reset() {
+	*x = ListSkillsRequest{}
+	mi := &file_nexflow_v1_skills_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSkillsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSkillsRequest) ProtoMessage() {}
+
+func (x *ListSkillsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_skills_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSkillsRequest.ProtoReflect.Descriptor instead.
+func (*ListSkillsRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_skills_proto_rawDescGZIP(), []int{1}
+}
+
+type ListSkillsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skills        []*Skill               `protobuf:"bytes,1,rep,name=skills,proto3" json:"skills,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSkillsResponse) Reset() {
+	*x = ListSkillsResponse{}
+	mi := &file_nexflow_v1_skills_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSkillsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSkillsResponse) ProtoMessage() {}
+
+func (x *ListSkillsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_skills_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSkillsResponse.ProtoReflect.Descriptor instead.
+func (*ListSkillsResponse) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_skills_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSkillsResponse) GetSkills() []*Skill {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+type GetSkillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSkillRequest) Reset() {
+	*x = GetSkillRequest{}
+	mi := &file_nexflow_v1_skills_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSkillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSkillRequest) ProtoMessage() {}
+
+func (x *GetSkillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_skills_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSkillRequest.ProtoReflect.Descriptor instead.
+func (*GetSkillRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_skills_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSkillRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ExecuteSkillRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skill         string                 `protobuf:"bytes,1,opt,name=skill,proto3" json:"skill,omitempty"`
+	Input         *structpb.Struct       `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteSkillRequest) Reset() {
+	*x = ExecuteSkillRequest{}
+	mi := &file_nexflow_v1_skills_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteSkillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteSkillRequest) ProtoMessage() {}
+
+func (x *ExecuteSkillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_skills_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteSkillRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteSkillRequest) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_skills_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExecuteSkillRequest) GetSkill() string {
+	if x != nil {
+		return x.Skill
+	}
+	return ""
+}
+
+func (x *ExecuteSkillRequest) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+type ExecuteSkillResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Output        string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteSkillResponse) Reset() {
+	*x = ExecuteSkillResponse{}
+	mi := &file_nexflow_v1_skills_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteSkillResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteSkillResponse) ProtoMessage() {}
+
+func (x *ExecuteSkillResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexflow_v1_skills_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteSkillResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteSkillResponse) Descriptor() ([]byte, []int) {
+	return file_nexflow_v1_skills_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExecuteSkillResponse) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+var File_nexflow_v1_skills_proto protoreflect.FileDescriptor
+
+const file_nexflow_v1_skills_proto_rawDesc = "" +
+	"\n" +
+	"\x17nexflow/v1/skills.proto\x12\n" +
+	"nexflow.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xbe\x01\n" +
+	"\x05Skill\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x12 \n" +
+	"\vpermissions\x18\x05 \x01(\tR\vpermissions\x12\x1a\n" +
+	"\bmetadata\x18\x06 \x01(\tR\bmetadata\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"\x13\n" +
+	"\x11ListSkillsRequest\"?\n" +
+	"\x12ListSkillsResponse\x12)\n" +
+	"\x06skills\x18\x01 \x03(\v2\x11.nexflow.v1.SkillR\x06skills\"%\n" +
+	"\x0fGetSkillRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"Z\n" +
+	"\x13ExecuteSkillRequest\x12\x14\n" +
+	"\x05skill\x18\x01 \x01(\tR\x05skill\x12-\n" +
+	"\x05input\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x05input\".\n" +
+	"\x14ExecuteSkillResponse\x12\x16\n" +
+	"\x06output\x18\x01 \x01(\tR\x06output2\xea\x01\n" +
+	"\fSkillService\x12K\n" +
+	"\n" +
+	"ListSkills\x12\x1d.nexflow.v1.ListSkillsRequest\x1a\x1e.nexflow.v1.ListSkillsResponse\x12:\n" +
+	"\bGetSkill\x12\x1b.nexflow.v1.GetSkillRequest\x1a\x11.nexflow.v1.Skill\x12Q\n" +
+	"\fExecuteSkill\x12\x1f.nexflow.v1.ExecuteSkillRequest\x1a .nexflow.v1.ExecuteSkillResponseBIZGgithub.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1b\x06proto3"
+
+var (
+	file_nexflow_v1_skills_proto_rawDescOnce sync.Once
+	file_nexflow_v1_skills_proto_rawDescData []byte
+)
+
+func file_nexflow_v1_skills_proto_rawDescGZIP() []byte {
+	file_nexflow_v1_skills_proto_rawDescOnce.Do(func() {
+		file_nexflow_v1_skills_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nexflow_v1_skills_proto_rawDesc), len(file_nexflow_v1_skills_proto_rawDesc)))
+	})
+	return file_nexflow_v1_skills_proto_rawDescData
+}
+
+var file_nexflow_v1_skills_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_nexflow_v1_skills_proto_goTypes = []any{
+	(*Skill)(nil),                // 0: nexflow.v1.Skill
+	(*ListSkillsRequest)(nil),    // 1: nexflow.v1.ListSkillsRequest
+	(*ListSkillsResponse)(nil),   // 2: nexflow.v1.ListSkillsResponse
+	(*GetSkillRequest)(nil),      // 3: nexflow.v1.GetSkillRequest
+	(*ExecuteSkillRequest)(nil),  // 4: nexflow.v1.ExecuteSkillRequest
+	(*ExecuteSkillResponse)(nil), // 5: nexflow.v1.ExecuteSkillResponse
+	(*structpb.Struct)(nil),      // 6: google.protobuf.Struct
+}
+var file_nexflow_v1_skills_proto_depIdxs = []int32{
+	0, // 0: nexflow.v1.ListSkillsResponse.skills:type_name -> nexflow.v1.Skill
+	6, // 1: nexflow.v1.ExecuteSkillRequest.input:type_name -> google.protobuf.Struct
+	1, // 2: nexflow.v1.SkillService.ListSkills:input_type -> nexflow.v1.ListSkillsRequest
+	3, // 3: nexflow.v1.SkillService.GetSkill:input_type -> nexflow.v1.GetSkillRequest
+	4, // 4: nexflow.v1.SkillService.ExecuteSkill:input_type -> nexflow.v1.ExecuteSkillRequest
+	2, // 5: nexflow.v1.SkillService.ListSkills:output_type -> nexflow.v1.ListSkillsResponse
+	0, // 6: nexflow.v1.SkillService.GetSkill:output_type -> nexflow.v1.Skill
+	5, // 7: nexflow.v1.SkillService.ExecuteSkill:output_type -> nexflow.v1.ExecuteSkillResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_nexflow_v1_skills_proto_init() }
+func file_nexflow_v1_skills_proto_init() {
+	if File_nexflow_v1_skills_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nexflow_v1_skills_proto_rawDesc), len(file_nexflow_v1_skills_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nexflow_v1_skills_proto_goTypes,
+		DependencyIndexes: file_nexflow_v1_skills_proto_depIdxs,
+		MessageInfos:      file_nexflow_v1_skills_proto_msgTypes,
+	}.Build()
+	File_nexflow_v1_skills_proto = out.File
+	file_nexflow_v1_skills_proto_goTypes = nil
+	file_nexflow_v1_skills_proto_depIdxs = nil
+}
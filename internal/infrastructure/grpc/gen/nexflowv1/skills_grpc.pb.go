@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: nexflow/v1/skills.proto
+
+package nexflowv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SkillService_ListSkills_FullMethodName   = "/nexflow.v1.SkillService/ListSkills"
+	SkillService_GetSkill_FullMethodName     = "/nexflow.v1.SkillService/GetSkill"
+	SkillService_ExecuteSkill_FullMethodName = "/nexflow.v1.SkillService/ExecuteSkill"
+)
+
+// SkillServiceClient is the client API for SkillService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SkillService lists and executes skills, backed by usecase.SkillUseCase
+type SkillServiceClient interface {
+	ListSkills(ctx context.Context, in *ListSkillsRequest, opts ...grpc.CallOption) (*ListSkillsResponse, error)
+	GetSkill(ctx context.Context, in *GetSkillRequest, opts ...grpc.CallOption) (*Skill, error)
+	ExecuteSkill(ctx context.Context, in *ExecuteSkillRequest, opts ...grpc.CallOption) (*ExecuteSkillResponse, error)
+}
+
+type skillServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSkillServiceClient(cc grpc.ClientConnInterface) SkillServiceClient {
+	return &skillServiceClient{cc}
+}
+
+func (c *skillServiceClient) ListSkills(ctx context.Context, in *ListSkillsRequest, opts ...grpc.CallOption) (*ListSkillsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSkillsResponse)
+	err := c.cc.Invoke(ctx, SkillService_ListSkills_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skillServiceClient) GetSkill(ctx context.Context, in *GetSkillRequest, opts ...grpc.CallOption) (*Skill, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Skill)
+	err := c.cc.Invoke(ctx, SkillService_GetSkill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skillServiceClient) ExecuteSkill(ctx context.Context, in *ExecuteSkillRequest, opts ...grpc.CallOption) (*ExecuteSkillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteSkillResponse)
+	err := c.cc.Invoke(ctx, SkillService_ExecuteSkill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SkillServiceServer is the server API for SkillService service.
+// All implementations must embed UnimplementedSkillServiceServer
+// for forward compatibility.
+//
+// SkillService lists and executes skills, backed by usecase.SkillUseCase
+type SkillServiceServer interface {
+	ListSkills(context.Context, *ListSkillsRequest) (*ListSkillsResponse, error)
+	GetSkill(context.Context, *GetSkillRequest) (*Skill, error)
+	ExecuteSkill(context.Context, *ExecuteSkillRequest) (*ExecuteSkillResponse, error)
+	mustEmbedUnimplementedSkillServiceServer()
+}
+
+// UnimplementedSkillServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSkillServiceServer struct{}
+
+func (UnimplementedSkillServiceServer) ListSkills(context.Context, *ListSkillsRequest) (*ListSkillsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSkills not implemented")
+}
+func (UnimplementedSkillServiceServer) GetSkill(context.Context, *GetSkillRequest) (*Skill, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSkill not implemented")
+}
+func (UnimplementedSkillServiceServer) ExecuteSkill(context.Context, *ExecuteSkillRequest) (*ExecuteSkillResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExecuteSkill not implemented")
+}
+func (UnimplementedSkillServiceServer) mustEmbedUnimplementedSkillServiceServer() {}
+func (UnimplementedSkillServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeSkillServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SkillServiceServer will
+// result in compilation errors.
+type UnsafeSkillServiceServer interface {
+	mustEmbedUnimplementedSkillServiceServer()
+}
+
+func RegisterSkillServiceServer(s grpc.ServiceRegistrar, srv SkillServiceServer) {
+	// If the following call panics, it indicates UnimplementedSkillServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SkillService_ServiceDesc, srv)
+}
+
+func _SkillService_ListSkills_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSkillsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkillServiceServer).ListSkills(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SkillService_ListSkills_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkillServiceServer).ListSkills(ctx, req.(*ListSkillsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkillService_GetSkill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSkillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkillServiceServer).GetSkill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SkillService_GetSkill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkillServiceServer).GetSkill(ctx, req.(*GetSkillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkillService_ExecuteSkill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteSkillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkillServiceServer).ExecuteSkill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SkillService_ExecuteSkill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkillServiceServer).ExecuteSkill(ctx, req.(*ExecuteSkillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SkillService_ServiceDesc is the grpc.ServiceDesc for SkillService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SkillService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nexflow.v1.SkillService",
+	HandlerType: (*SkillServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSkills",
+			Handler:    _SkillService_ListSkills_Handler,
+		},
+		{
+			MethodName: "GetSkill",
+			Handler:    _SkillService_GetSkill_Handler,
+		},
+		{
+			MethodName: "ExecuteSkill",
+			Handler:    _SkillService_ExecuteSkill_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nexflow/v1/skills.proto",
+}
@@ -0,0 +1,189 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// APIKeyMetadata is the metadata key carrying an API key.
+// Keys and JWTs can also be sent as "authorization: Bearer <token>".
+const APIKeyMetadata = "x-api-key"
+
+// Authenticator resolves the API key or JWT sent with a call, implemented by the HTTP authenticator
+type Authenticator interface {
+	AuthenticateToken(ctx context.Context, token string) (usecase.Principal, error)
+}
+
+// readMethods are the calls allowed with read scope, like GET requests of the HTTP API
+var readMethods = map[string]bool{
+	nexflowv1.ChatService_GetConversation_FullMethodName:     true,
+	nexflowv1.SessionService_ListUserSessions_FullMethodName: true,
+	nexflowv1.SkillService_ListSkills_FullMethodName:         true,
+	nexflowv1.SkillService_GetSkill_FullMethodName:           true,
+	nexflowv1.ScheduleService_GetSchedule_FullMethodName:     true,
+	nexflowv1.ScheduleService_ListSchedules_FullMethodName:   true,
+}
+
+// authInterceptor authenticates calls with the API keys and JWTs of the HTTP API and
+// checks that the credential's scope allows the method
+type authInterceptor struct {
+	config        config.AuthConfig
+	authenticator Authenticator
+	logger        logging.Logger
+}
+
+func newAuthInterceptor(cfg config.AuthConfig, authenticator Authenticator, logger logging.Logger) *authInterceptor {
+	return &authInterceptor{config: cfg, authenticator: authenticator, logger: logger}
+}
+
+func (a *authInterceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authInterceptor) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize returns the context of an allowed call carrying its usecase.Principal,
+// or an Unauthenticated or PermissionDenied status
+func (a *authInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
+	if !a.config.Enabled {
+		return ctx, nil
+	}
+
+	var principal usecase.Principal
+	token := tokenFromMetadata(ctx)
+	switch {
+	case token == "" && a.config.AllowLocalhost && isLocalPeer(ctx):
+		principal.Scope = valueobject.APIKeyScopeAdmin
+	case token == "" || a.authenticator == nil:
+		a.logger.Warn("unauthenticated grpc call", "method", method)
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	default:
+		var err error
+		principal, err = a.authenticator.AuthenticateToken(ctx, token)
+		if err != nil {
+			a.logger.Warn("unauthenticated grpc call", "method", method, "error", err)
+			return nil, status.Error(codes.Unauthenticated, "authentication required")
+		}
+	}
+
+	if !principal.Scope.IsAdmin() && !readMethods[method] && !isReflectionMethod(method) {
+		a.logger.Warn("forbidden grpc call", "method", method, "scope", principal.Scope, "workspace", principal.Workspace)
+		return nil, status.Error(codes.PermissionDenied, "insufficient scope")
+	}
+
+	ctx = usecase.WithPrincipal(ctx, principal)
+	if !principal.Workspace.IsEmpty() {
+		ctx = usecase.WithWorkspace(ctx, principal.Workspace)
+	}
+	return ctx, nil
+}
+
+// tokenFromMetadata returns the API key or bearer token sent with a call
+func tokenFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get(APIKeyMetadata); len(keys) > 0 && keys[0] != "" {
+		return keys[0]
+	}
+	for _, value := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// isLocalPeer reports whether a call comes from a loopback address
+func isLocalPeer(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isReflectionMethod reports whether a method belongs to the reflection service, which only describes the API
+func isReflectionMethod(method string) bool {
+	return strings.HasPrefix(method, "/grpc.reflection.")
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// timeoutUnary limits unary calls to timeout, like server.request_timeout_sec limits HTTP requests
+func timeoutUnary(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// recoveryUnary turns a panic in a unary call into an Internal status instead of crashing the server
+func recoveryUnary(logger logging.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// recoveryStream turns a panic in a streaming call into an Internal status instead of crashing the server
+func recoveryStream(logger logging.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(logger logging.Logger, method string, r any) error {
+	logger.Error("panic recovered in grpc call", "method", method, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// scheduleService implements nexflowv1.ScheduleServiceServer with usecase.ScheduleUseCase
+type scheduleService struct {
+	nexflowv1.UnimplementedScheduleServiceServer
+
+	schedules *usecase.ScheduleUseCase
+	logger    logging.Logger
+}
+
+// CreateSchedule creates a schedule
+func (s *scheduleService) CreateSchedule(ctx context.Context, req *nexflowv1.CreateScheduleRequest) (*nexflowv1.Schedule, error) {
+	createReq := dto.CreateScheduleRequest{
+		Skill:           req.GetSkill(),
+		CronExpression:  req.GetCronExpression(),
+		Input:           inputFromProto(req.GetInput()),
+		Timezone:        req.GetTimezone(),
+		JitterSeconds:   int(req.GetJitterSeconds()),
+		RunAt:           req.GetRunAt(),
+		MissedRunPolicy: req.GetMissedRunPolicy(),
+		TargetConnector: req.GetTargetConnector(),
+		TargetUserID:    req.GetTargetUserId(),
+	}
+	if err := validateRequest(&createReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.schedules.CreateSchedule(ctx, createReq)
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to create grpc schedule", "error", err)
+		return nil, err
+	}
+	return scheduleToProto(resp.Schedule), nil
+}
+
+// GetSchedule returns a schedule by ID
+func (s *scheduleService) GetSchedule(ctx context.Context, req *nexflowv1.GetScheduleRequest) (*nexflowv1.Schedule, error) {
+	resp, err := s.schedules.GetScheduleByID(ctx, req.GetId())
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		return nil, err
+	}
+	return scheduleToProto(resp.Schedule), nil
+}
+
+// ListSchedules returns the schedules of a skill, the enabled schedules or all schedules
+func (s *scheduleService) ListSchedules(ctx context.Context, req *nexflowv1.ListSchedulesRequest) (*nexflowv1.ListSchedulesResponse, error) {
+	var resp *dto.SchedulesResponse
+	var err error
+	switch {
+	case req.GetSkill() != "":
+		resp, err = s.schedules.GetSchedulesBySkill(ctx, req.GetSkill())
+	case req.GetEnabledOnly():
+		resp, err = s.schedules.ListEnabledSchedules(ctx)
+	default:
+		resp, err = s.schedules.ListSchedules(ctx)
+	}
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*nexflowv1.Schedule, 0, len(resp.Schedules))
+	for _, schedule := range resp.Schedules {
+		if req.GetEnabledOnly() && !schedule.Enabled {
+			continue
+		}
+		schedules = append(schedules, scheduleToProto(schedule))
+	}
+	return &nexflowv1.ListSchedulesResponse{Schedules: schedules}, nil
+}
+
+// DeleteSchedule deletes a schedule and returns it
+func (s *scheduleService) DeleteSchedule(ctx context.Context, req *nexflowv1.GetScheduleRequest) (*nexflowv1.Schedule, error) {
+	resp, err := s.schedules.DeleteSchedule(ctx, req.GetId())
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to delete grpc schedule", "error", err, "schedule_id", req.GetId())
+		return nil, err
+	}
+	return scheduleToProto(resp.Schedule), nil
+}
+
+// RunScheduleNow starts a run of a schedule, also of a paused one
+func (s *scheduleService) RunScheduleNow(ctx context.Context, req *nexflowv1.GetScheduleRequest) (*nexflowv1.Schedule, error) {
+	resp, err := s.schedules.RunScheduleNow(ctx, req.GetId())
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to run grpc schedule", "error", err, "schedule_id", req.GetId())
+		return nil, err
+	}
+	return scheduleToProto(resp.Schedule), nil
+}
@@ -0,0 +1,156 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Server serves the gRPC API generated from api/proto/nexflow/v1
+type Server struct {
+	grpcServer *grpc.Server
+	addr       string
+	tls        bool
+	logger     logging.Logger
+}
+
+// ServerConfig holds configuration for the gRPC server
+type ServerConfig struct {
+	Addr string // Server address (e.g., ":9090")
+
+	// TLSCertFile and TLSKeyFile enable TLS when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// GetCertificate enables TLS with certificates obtained at run time, such as those of
+	// the autocert manager of the HTTP server; the certificate files take precedence
+	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	// Reflection registers the gRPC reflection service
+	Reflection bool
+
+	// RequestTimeout is the deadline of unary calls (0 = no deadline); streams have none
+	RequestTimeout time.Duration
+
+	// Auth and Authenticator authenticate calls like HTTP requests; Authenticator may be nil
+	// when authentication is disabled
+	Auth          config.AuthConfig
+	Authenticator Authenticator
+}
+
+// Services holds the use cases the gRPC services are backed by, shared with the HTTP API
+type Services struct {
+	Chat     *usecase.ChatUseCase
+	Skill    *usecase.SkillUseCase
+	Schedule *usecase.ScheduleUseCase
+}
+
+// NewServer creates a new gRPC server with the chat, session, skill and schedule services
+func NewServer(cfg *ServerConfig, services Services, logger logging.Logger) (*Server, error) {
+	auth := newAuthInterceptor(cfg.Auth, cfg.Authenticator, logger)
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(recoveryUnary(logger), auth.unary, timeoutUnary(cfg.RequestTimeout)),
+		grpc.ChainStreamInterceptor(recoveryStream(logger), auth.stream),
+	}
+
+	var tlsConfig *tls.Config
+	switch {
+	case cfg.TLSCertFile != "" && cfg.TLSKeyFile != "":
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load gRPC TLS certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	case cfg.GetCertificate != nil:
+		tlsConfig = &tls.Config{GetCertificate: cfg.GetCertificate, MinVersion: tls.VersionTLS12}
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	grpcServer := grpc.NewServer(options...)
+	nexflowv1.RegisterChatServiceServer(grpcServer, &chatService{chat: services.Chat, logger: logger})
+	nexflowv1.RegisterSessionServiceServer(grpcServer, &sessionService{chat: services.Chat, logger: logger})
+	nexflowv1.RegisterSkillServiceServer(grpcServer, &skillService{skills: services.Skill, logger: logger})
+	nexflowv1.RegisterScheduleServiceServer(grpcServer, &scheduleService{schedules: services.Schedule, logger: logger})
+	if cfg.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	return &Server{
+		grpcServer: grpcServer,
+		addr:       cfg.Addr,
+		tls:        tlsConfig != nil,
+		logger:     logger,
+	}, nil
+}
+
+// Start listens on the server address and serves gRPC calls until ctx is done
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.Serve(lis)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		return s.Shutdown(ctx)
+	}
+}
+
+// Serve serves gRPC calls on lis until the server is shut down
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("Starting gRPC server", "addr", lis.Addr().String(), "tls", s.tls)
+	if err := s.grpcServer.Serve(lis); err != nil {
+		return fmt.Errorf("failed to serve gRPC: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops accepting calls and waits for the calls in flight until ctx is done,
+// after which the remaining calls are cancelled
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("Shutting down gRPC server")
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return fmt.Errorf("failed to shutdown gRPC server: %w", ctx.Err())
+	}
+}
+
+// Addr returns the server address
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// TLS reports whether the server serves TLS
+func (s *Server) TLS() bool {
+	return s.tls
+}
@@ -0,0 +1,237 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// stubScheduleRepository keeps schedules in memory
+type stubScheduleRepository struct {
+	schedules map[string]*entity.Schedule
+}
+
+func (s *stubScheduleRepository) Create(ctx context.Context, schedule *entity.Schedule) error {
+	s.schedules[string(schedule.ID)] = schedule
+	return nil
+}
+
+func (s *stubScheduleRepository) FindByID(ctx context.Context, id string) (*entity.Schedule, error) {
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: schedule %s", repository.ErrNotFound, id)
+	}
+	return schedule, nil
+}
+
+func (s *stubScheduleRepository) FindBySkill(ctx context.Context, skill string) ([]*entity.Schedule, error) {
+	var schedules []*entity.Schedule
+	for _, schedule := range s.schedules {
+		if schedule.Skill == skill {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (s *stubScheduleRepository) List(ctx context.Context) ([]*entity.Schedule, error) {
+	schedules := make([]*entity.Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func (s *stubScheduleRepository) Update(ctx context.Context, schedule *entity.Schedule) error {
+	s.schedules[string(schedule.ID)] = schedule
+	return nil
+}
+
+func (s *stubScheduleRepository) Delete(ctx context.Context, id string) error {
+	delete(s.schedules, id)
+	return nil
+}
+
+func (s *stubScheduleRepository) FindEnabled(ctx context.Context) ([]*entity.Schedule, error) {
+	var schedules []*entity.Schedule
+	for _, schedule := range s.schedules {
+		if schedule.Enabled {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+// stubAuthenticator accepts the tokens "read-key" and "admin-key"
+type stubAuthenticator struct{}
+
+func (stubAuthenticator) AuthenticateToken(ctx context.Context, token string) (usecase.Principal, error) {
+	switch token {
+	case "read-key":
+		return usecase.Principal{Scope: valueobject.APIKeyScopeRead}, nil
+	case "admin-key":
+		return usecase.Principal{Scope: valueobject.APIKeyScopeAdmin}, nil
+	default:
+		return usecase.Principal{}, errors.New("unknown api key")
+	}
+}
+
+// startTestServer serves the gRPC API over an in-memory listener and returns a client connection
+func startTestServer(t *testing.T, cfg *ServerConfig, schedules *usecase.ScheduleUseCase) *grpc.ClientConn {
+	t.Helper()
+
+	server, err := NewServer(cfg, Services{Schedule: schedules}, logging.NewNoopLogger())
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// newTestSchedules returns a schedule use case with an enabled and a paused schedule
+func newTestSchedules() (*usecase.ScheduleUseCase, *entity.Schedule, *entity.Schedule) {
+	enabled := entity.NewSchedule("backup", "0 3 * * *", "{}")
+	paused := entity.NewSchedule("digest", "0 8 * * *", "{}")
+	paused.Disable()
+	repo := &stubScheduleRepository{schedules: map[string]*entity.Schedule{
+		string(enabled.ID): enabled,
+		string(paused.ID):  paused,
+	}}
+	return usecase.NewScheduleUseCase(repo, nil, logging.NewNoopLogger()), enabled, paused
+}
+
+func TestScheduleService(t *testing.T) {
+	schedules, enabled, paused := newTestSchedules()
+	client := nexflowv1.NewScheduleServiceClient(startTestServer(t, &ServerConfig{}, schedules))
+	ctx := context.Background()
+
+	schedule, err := client.GetSchedule(ctx, &nexflowv1.GetScheduleRequest{Id: string(paused.ID)})
+	require.NoError(t, err)
+	assert.Equal(t, "digest", schedule.Skill)
+	assert.False(t, schedule.Enabled)
+
+	list, err := client.ListSchedules(ctx, &nexflowv1.ListSchedulesRequest{EnabledOnly: true})
+	require.NoError(t, err)
+	require.Len(t, list.Schedules, 1)
+	assert.Equal(t, string(enabled.ID), list.Schedules[0].Id)
+
+	_, err = client.GetSchedule(ctx, &nexflowv1.GetScheduleRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.CreateSchedule(ctx, &nexflowv1.CreateScheduleRequest{Skill: "backup", CronExpression: "0 25 * * *"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.RunScheduleNow(ctx, &nexflowv1.GetScheduleRequest{Id: string(enabled.ID)})
+	assert.Equal(t, codes.Unavailable, status.Code(err), "running a schedule without a scheduler should be unavailable")
+}
+
+func TestServer_Auth(t *testing.T) {
+	schedules, enabled, _ := newTestSchedules()
+	conn := startTestServer(t, &ServerConfig{
+		Auth:          config.AuthConfig{Enabled: true},
+		Authenticator: stubAuthenticator{},
+	}, schedules)
+	client := nexflowv1.NewScheduleServiceClient(conn)
+	get := &nexflowv1.GetScheduleRequest{Id: string(enabled.ID)}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		call     func(ctx context.Context) error
+		wantCode codes.Code
+	}{
+		{
+			name:     "no credentials",
+			call:     func(ctx context.Context) error { _, err := client.GetSchedule(ctx, get); return err },
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "unknown key",
+			md:       metadata.Pairs(APIKeyMetadata, "wrong"),
+			call:     func(ctx context.Context) error { _, err := client.GetSchedule(ctx, get); return err },
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "read key reads",
+			md:       metadata.Pairs(APIKeyMetadata, "read-key"),
+			call:     func(ctx context.Context) error { _, err := client.GetSchedule(ctx, get); return err },
+			wantCode: codes.OK,
+		},
+		{
+			name:     "read key cannot delete",
+			md:       metadata.Pairs("authorization", "Bearer read-key"),
+			call:     func(ctx context.Context) error { _, err := client.DeleteSchedule(ctx, get); return err },
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "admin bearer token",
+			md:   metadata.Pairs("authorization", "Bearer admin-key"),
+			call: func(ctx context.Context) error {
+				_, err := client.ListSchedules(ctx, &nexflowv1.ListSchedulesRequest{})
+				return err
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			assert.Equal(t, tt.wantCode, status.Code(tt.call(ctx)))
+		})
+	}
+}
+
+func TestServer_Reflection(t *testing.T) {
+	schedules, _, _ := newTestSchedules()
+	conn := startTestServer(t, &ServerConfig{Reflection: true}, schedules)
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	sort.Strings(services)
+	assert.Equal(t, []string{
+		"grpc.reflection.v1.ServerReflection",
+		"grpc.reflection.v1alpha.ServerReflection",
+		"nexflow.v1.ChatService",
+		"nexflow.v1.ScheduleService",
+		"nexflow.v1.SessionService",
+		"nexflow.v1.SkillService",
+	}, services)
+}
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// sessionService implements nexflowv1.SessionServiceServer with usecase.ChatUseCase
+type sessionService struct {
+	nexflowv1.UnimplementedSessionServiceServer
+
+	chat   *usecase.ChatUseCase
+	logger logging.Logger
+}
+
+// CreateSession creates a session for a user
+func (s *sessionService) CreateSession(ctx context.Context, req *nexflowv1.CreateSessionRequest) (*nexflowv1.Session, error) {
+	createReq := dto.CreateSessionRequest{UserID: req.GetUserId()}
+	if err := validateRequest(&createReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.chat.CreateSession(ctx, createReq)
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to create grpc session", "error", err)
+		return nil, err
+	}
+	return sessionToProto(resp.Session), nil
+}
+
+// ListUserSessions returns a page of the sessions of a user
+func (s *sessionService) ListUserSessions(ctx context.Context, req *nexflowv1.ListUserSessionsRequest) (*nexflowv1.ListUserSessionsResponse, error) {
+	page, err := pageFromProto(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.chat.GetUserSessions(ctx, req.GetUserId(), page)
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*nexflowv1.Session, 0, len(resp.Sessions))
+	for _, session := range resp.Sessions {
+		sessions = append(sessions, sessionToProto(session))
+	}
+	return &nexflowv1.ListUserSessionsResponse{Sessions: sessions, NextCursor: resp.NextCursor}, nil
+}
+
+// SetSessionPinned pins or unpins a session
+func (s *sessionService) SetSessionPinned(ctx context.Context, req *nexflowv1.SetSessionPinnedRequest) (*nexflowv1.Session, error) {
+	resp, err := s.chat.SetSessionPinned(ctx, req.GetId(), req.GetPinned())
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to update grpc session pin", "error", err, "session_id", req.GetId())
+		return nil, err
+	}
+	return sessionToProto(resp.Session), nil
+}
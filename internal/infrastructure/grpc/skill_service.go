@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/infrastructure/grpc/gen/nexflowv1"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// skillService implements nexflowv1.SkillServiceServer with usecase.SkillUseCase
+type skillService struct {
+	nexflowv1.UnimplementedSkillServiceServer
+
+	skills *usecase.SkillUseCase
+	logger logging.Logger
+}
+
+// ListSkills returns all skills
+func (s *skillService) ListSkills(ctx context.Context, req *nexflowv1.ListSkillsRequest) (*nexflowv1.ListSkillsResponse, error) {
+	resp, err := s.skills.ListSkills(ctx)
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		return nil, err
+	}
+
+	skills := make([]*nexflowv1.Skill, 0, len(resp.Skills))
+	for _, skill := range resp.Skills {
+		skills = append(skills, skillToProto(skill))
+	}
+	return &nexflowv1.ListSkillsResponse{Skills: skills}, nil
+}
+
+// GetSkill returns a skill by name
+func (s *skillService) GetSkill(ctx context.Context, req *nexflowv1.GetSkillRequest) (*nexflowv1.Skill, error) {
+	resp, err := s.skills.GetSkillByName(ctx, req.GetName())
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		return nil, err
+	}
+	return skillToProto(resp.Skill), nil
+}
+
+// ExecuteSkill executes a skill and returns its output
+func (s *skillService) ExecuteSkill(ctx context.Context, req *nexflowv1.ExecuteSkillRequest) (*nexflowv1.ExecuteSkillResponse, error) {
+	resp, err := s.skills.ExecuteSkill(ctx, dto.SkillExecutionRequest{
+		Skill: req.GetSkill(),
+		Input: inputFromProto(req.GetInput()),
+	})
+	if err := useCaseError(resp.Success, resp.Error, err); err != nil {
+		s.logger.Error("failed to execute grpc skill", "error", err, "skill", req.GetSkill())
+		return nil, err
+	}
+	return &nexflowv1.ExecuteSkillResponse{Output: resp.Output}, nil
+}
@@ -0,0 +1,364 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// AdminHandler handles the runtime admin API: connectors, router statistics, message dead letters,
+// message replay, LLM providers, the global custom instructions and the prompt fragments
+type AdminHandler struct {
+	adminUseCase *usecase.AdminUseCase
+	logger       logging.Logger
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(adminUseCase *usecase.AdminUseCase, logger logging.Logger) *AdminHandler {
+	return &AdminHandler{
+		adminUseCase: adminUseCase,
+		logger:       logger,
+	}
+}
+
+// ListConnectors handles GET /admin/connectors
+func (h *AdminHandler) ListConnectors(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.adminUseCase.ListConnectors(ctx)
+	if err != nil {
+		h.logger.Error("failed to list connectors", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetConnector handles GET /admin/connectors/{name}
+func (h *AdminHandler) GetConnector(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	name := r.PathValue("name")
+
+	resp, err := h.adminUseCase.GetConnector(ctx, name)
+	if err != nil {
+		h.logger.Error("failed to get connector", "error", err, "connector", name)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusNotFound, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// StartConnector handles POST /admin/connectors/{name}/start
+func (h *AdminHandler) StartConnector(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	name := r.PathValue("name")
+
+	resp, err := h.adminUseCase.StartConnector(ctx, name)
+	if err != nil {
+		h.logger.Error("failed to start connector", "error", err, "connector", name)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return writeConnectorResponse(w, resp)
+}
+
+// StopConnector handles POST /admin/connectors/{name}/stop
+func (h *AdminHandler) StopConnector(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	name := r.PathValue("name")
+
+	resp, err := h.adminUseCase.StopConnector(ctx, name)
+	if err != nil {
+		h.logger.Error("failed to stop connector", "error", err, "connector", name)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return writeConnectorResponse(w, resp)
+}
+
+// writeConnectorResponse writes the result of a connector start or stop.
+// A failed response without a connector means the connector is unknown;
+// with a connector, it is in a state that does not allow the operation,
+// and the error details carry its current status.
+func writeConnectorResponse(w http.ResponseWriter, resp *dto.ConnectorResponse) error {
+	if !resp.Success {
+		if resp.Connector == nil {
+			return WriteError(w, http.StatusNotFound, resp.Error)
+		}
+		return WriteErrorCode(w, http.StatusConflict, ErrCodeConflict, resp.Error, resp.Connector)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetRouterStats handles GET /admin/router/stats
+func (h *AdminHandler) GetRouterStats(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.adminUseCase.GetRouterStats(ctx)
+	if err != nil {
+		h.logger.Error("failed to get router stats", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListLLMProviders handles GET /admin/llm/providers
+func (h *AdminHandler) ListLLMProviders(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.adminUseCase.ListLLMProviders(ctx)
+	if err != nil {
+		h.logger.Error("failed to list llm providers", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListDeadLetters handles GET /admin/dead-letters.
+// It lists the messages the router failed to process, newest first.
+func (h *AdminHandler) ListDeadLetters(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	if limit == 0 {
+		limit = defaultDeadLetterLimit
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"), "offset")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.adminUseCase.ListDeadLetters(ctx, repository.MessageDeadLetterFilter{
+		Connector: query.Get("connector"),
+		Limit:     min(limit, maxDeadLetterLimit),
+		Offset:    offset,
+	})
+	if err != nil {
+		h.logger.Error("failed to list dead letters", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// ReprocessDeadLetter handles POST /admin/dead-letters/{id}/reprocess
+func (h *AdminHandler) ReprocessDeadLetter(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+
+	resp, err := h.adminUseCase.ReprocessDeadLetter(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to reprocess dead letter", "error", err, "dead_letter_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// ReplayMessage handles POST /admin/events/{id}/replay.
+// The body is optional; without it, the replies are returned but not sent to the user.
+func (h *AdminHandler) ReplayMessage(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+
+	var req dto.ReplayMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("failed to decode replay request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.adminUseCase.ReplayMessage(ctx, id, req)
+	if err != nil {
+		h.logger.Error("failed to replay message", "error", err, "event_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// DeleteDeadLetter handles DELETE /admin/dead-letters/{id}
+func (h *AdminHandler) DeleteDeadLetter(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+
+	resp, err := h.adminUseCase.DeleteDeadLetter(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to delete dead letter", "error", err, "dead_letter_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetInstructions handles GET /admin/instructions
+func (h *AdminHandler) GetInstructions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.adminUseCase.GetInstructions(ctx)
+	if err != nil {
+		h.logger.Error("failed to get instructions", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// UpdateInstructions handles PUT /admin/instructions
+func (h *AdminHandler) UpdateInstructions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req dto.UpdateInstructionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode instructions request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.adminUseCase.UpdateInstructions(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to update instructions", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListPromptFragments handles GET /admin/prompts
+func (h *AdminHandler) ListPromptFragments(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.adminUseCase.ListPromptFragments(ctx)
+	if err != nil {
+		h.logger.Error("failed to list prompt fragments", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// UpdatePromptFragment handles PUT /admin/prompts/{scope}/{name}
+func (h *AdminHandler) UpdatePromptFragment(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	scope, name := r.PathValue("scope"), r.PathValue("name")
+
+	var req dto.UpdatePromptFragmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode prompt fragment request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.adminUseCase.UpdatePromptFragment(ctx, scope, name, req)
+	if err != nil {
+		h.logger.Error("failed to update prompt fragment", "error", err, "scope", scope, "name", name)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterAdminRoutes registers the runtime admin routes
+func RegisterAdminRoutes(r *Router, handler *AdminHandler) {
+	admin := r.Group("/admin")
+	connectors := admin.Group("/connectors")
+	deadLetters := admin.Group("/dead-letters")
+	connectors.HandleFunc("GET /", handler.ListConnectors).Describe(RouteDoc{
+		Summary:  "List channel connectors",
+		Tag:      "admin",
+		Response: dto.ConnectorsResponse{},
+	})
+	connectors.HandleFunc("GET /{name}", handler.GetConnector).Describe(RouteDoc{
+		Summary:  "Get the status of a channel connector",
+		Tag:      "admin",
+		Response: dto.ConnectorResponse{},
+	})
+	connectors.HandleFunc("POST /{name}/start", handler.StartConnector).Describe(RouteDoc{
+		Summary:     "Start a channel connector",
+		Description: "Responds with 409 and the connector status in the error details if the connector is already running.",
+		Tag:         "admin",
+		Response:    dto.ConnectorResponse{},
+	})
+	connectors.HandleFunc("POST /{name}/stop", handler.StopConnector).Describe(RouteDoc{
+		Summary:     "Stop a channel connector",
+		Description: "Stops the connector without stopping the router. Responds with 409 and the connector status in the error details if the connector is not running.",
+		Tag:         "admin",
+		Response:    dto.ConnectorResponse{},
+	})
+	admin.HandleFunc("GET /router/stats", handler.GetRouterStats).Describe(RouteDoc{
+		Summary:  "Get message router statistics",
+		Tag:      "admin",
+		Response: dto.RouterStatsResponse{},
+	})
+	admin.HandleFunc("GET /llm/providers", handler.ListLLMProviders).Describe(RouteDoc{
+		Summary:     "List LLM providers",
+		Description: "Lists the configured providers with their recent health checks when llm.health_check is enabled; otherwise checks the availability of the active one.",
+		Tag:         "admin",
+		Response:    dto.LLMProvidersResponse{},
+	})
+	admin.HandleFunc("GET /instructions", handler.GetInstructions).Describe(RouteDoc{
+		Summary:     "Get the global custom instructions",
+		Description: "Instructions passed to the LLM in every session, before those of the user and the session. Responds with 503 when they are disabled.",
+		Tag:         "admin",
+		Response:    dto.InstructionsResponse{},
+	})
+	admin.HandleFunc("PUT /instructions", handler.UpdateInstructions).Describe(RouteDoc{
+		Summary:     "Replace the global custom instructions",
+		Description: "An empty value removes them. The change lasts until the server restarts, when llm.instructions applies again.",
+		Tag:         "admin",
+		Request:     dto.UpdateInstructionsRequest{},
+		Response:    dto.InstructionsResponse{},
+	})
+	admin.HandleFunc("GET /prompts", handler.ListPromptFragments).Describe(RouteDoc{
+		Summary:     "List the prompt fragments",
+		Description: "Parts of the system prompt passed to the LLM within their scope: the sessions of a workspace, the messages of a connector, or the plans using a skill. Responds with 503 when they are disabled.",
+		Tag:         "admin",
+		Response:    dto.PromptFragmentsResponse{},
+	})
+	admin.HandleFunc("PUT /prompts/{scope}/{name}", handler.UpdatePromptFragment).Describe(RouteDoc{
+		Summary:     "Replace a prompt fragment",
+		Description: "The scope is workspace, connector or skill, and the name the workspace ID, connector name or skill name. An empty prompt removes the fragment. The change lasts until the server restarts, when llm.prompts applies again.",
+		Tag:         "admin",
+		Request:     dto.UpdatePromptFragmentRequest{},
+		Response:    dto.PromptFragmentResponse{},
+	})
+	deadLetters.HandleFunc("GET /", handler.ListDeadLetters).Describe(RouteDoc{
+		Summary:     "List message dead letters",
+		Description: "Connector messages the router failed to process, newest first. Responds with 503 when the dead-letter queue is disabled.",
+		Tag:         "admin",
+		Query: []QueryParam{
+			{Name: "connector", Description: "Only messages received by this connector, e.g. telegram"},
+			{Name: "limit", Description: "Maximum number of dead letters to return (default 50, max 500)"},
+			{Name: "offset", Description: "Number of dead letters to skip"},
+		},
+		Response: dto.MessageDeadLettersResponse{},
+	})
+	deadLetters.HandleFunc("POST /{id}/reprocess", handler.ReprocessDeadLetter).Describe(RouteDoc{
+		Summary:     "Reprocess a message dead letter",
+		Description: "Routes the message once more, sends the reply to the user and deletes the dead letter on success. Responds with 409 when the connector is not running or processing fails again.",
+		Tag:         "admin",
+		Response:    dto.DeadLetterResponse{},
+	})
+	deadLetters.HandleFunc("DELETE /{id}", handler.DeleteDeadLetter).Describe(RouteDoc{
+		Summary:  "Discard a message dead letter",
+		Tag:      "admin",
+		Response: dto.DeadLetterResponse{},
+	})
+	admin.HandleFunc("POST /events/{id}/replay", handler.ReplayMessage).Describe(RouteDoc{
+		Summary:     "Replay a received message",
+		Description: "Routes the message of a stored connector.message event once more, marked with replay metadata. The replies are returned and only sent to the user with deliver: true. Responds with 400 for other event types, 409 when the connector is not running or processing fails, and 503 unless eventbus.persist is enabled.",
+		Tag:         "admin",
+		Request:     dto.ReplayMessageRequest{},
+		Response:    dto.MessageReplayResponse{},
+	})
+}
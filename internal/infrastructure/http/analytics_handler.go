@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// AnalyticsHandler handles the conversation analytics API
+type AnalyticsHandler struct {
+	analyticsUseCase *usecase.AnalyticsUseCase
+	logger           logging.Logger
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler
+func NewAnalyticsHandler(analyticsUseCase *usecase.AnalyticsUseCase, logger logging.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsUseCase: analyticsUseCase,
+		logger:           logger,
+	}
+}
+
+// GetDaily handles GET /analytics/daily
+func (h *AnalyticsHandler) GetDaily(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.analyticsUseCase.GetDailyAnalytics(ctx, analyticsRequest(r))
+	if err != nil {
+		h.logger.Error("failed to get daily analytics", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetSummary handles GET /analytics/summary
+func (h *AnalyticsHandler) GetSummary(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.analyticsUseCase.GetAnalyticsSummary(ctx, analyticsRequest(r))
+	if err != nil {
+		h.logger.Error("failed to get analytics summary", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// analyticsRequest reads the range of days from the query string
+func analyticsRequest(r *http.Request) dto.AnalyticsRequest {
+	query := r.URL.Query()
+	return dto.AnalyticsRequest{
+		Since: query.Get("since"),
+		Until: query.Get("until"),
+	}
+}
+
+// analyticsQuery are the query parameters read by analyticsRequest
+var analyticsQuery = []QueryParam{
+	{Name: "since", Description: "First UTC day, YYYY-MM-DD (default 29 days before until)"},
+	{Name: "until", Description: "Last UTC day, YYYY-MM-DD, inclusive (default today)"},
+}
+
+// RegisterAnalyticsRoutes registers the conversation analytics routes
+func RegisterAnalyticsRoutes(r *Router, handler *AnalyticsHandler) {
+	analytics := r.Group("/analytics")
+	analytics.HandleFunc("GET /daily", handler.GetDaily).Describe(RouteDoc{
+		Summary:     "Get daily analytics",
+		Description: "Messages per connector, active users, LLM tokens and cost per model and skill executions for every day of the range, oldest first, with the totals of the range. Ranges are limited to 366 days.",
+		Tag:         "analytics",
+		Response:    dto.AnalyticsResponse{},
+		Query:       analyticsQuery,
+	})
+	analytics.HandleFunc("GET /summary", handler.GetSummary).Describe(RouteDoc{
+		Summary:     "Get an analytics summary",
+		Description: "The totals of GET /analytics/daily without the days. Active users are counted once over the range.",
+		Tag:         "analytics",
+		Response:    dto.AnalyticsResponse{},
+		Query:       analyticsQuery,
+	})
+}
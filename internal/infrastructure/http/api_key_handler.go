@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// APIKeyHandler handles the API key admin API
+type APIKeyHandler struct {
+	apiKeyUseCase *usecase.APIKeyUseCase
+	logger        logging.Logger
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(apiKeyUseCase *usecase.APIKeyUseCase, logger logging.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+		logger:        logger,
+	}
+}
+
+// CreateAPIKey handles POST /admin/api-keys
+func (h *APIKeyHandler) CreateAPIKey(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode api key request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.apiKeyUseCase.CreateKey(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to create api key", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusCreated, resp)
+}
+
+// ListAPIKeys handles GET /admin/api-keys
+func (h *APIKeyHandler) ListAPIKeys(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.apiKeyUseCase.ListKeys(ctx)
+	if err != nil {
+		h.logger.Error("failed to list api keys", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RevokeAPIKey handles DELETE /admin/api-keys/{id}
+func (h *APIKeyHandler) RevokeAPIKey(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "api key id is required")
+	}
+
+	resp, err := h.apiKeyUseCase.RevokeKey(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to revoke api key", "error", err, "api_key_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusNotFound, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterAPIKeyRoutes registers the API key admin routes
+func RegisterAPIKeyRoutes(r *Router, handler *APIKeyHandler) {
+	keys := r.Group("/admin/api-keys")
+	keys.HandleFunc("POST /", handler.CreateAPIKey).Describe(RouteDoc{
+		Summary:     "Create an API key",
+		Description: "The plaintext key is only returned in this response.",
+		Tag:         "admin",
+		Request:     dto.CreateAPIKeyRequest{},
+		Response:    dto.APIKeyResponse{},
+		Status:      http.StatusCreated,
+	})
+	keys.HandleFunc("GET /", handler.ListAPIKeys).Describe(RouteDoc{
+		Summary:  "List API keys",
+		Tag:      "admin",
+		Response: dto.APIKeysResponse{},
+	})
+	keys.HandleFunc("DELETE /{id}", handler.RevokeAPIKey).Describe(RouteDoc{
+		Summary:  "Revoke an API key",
+		Tag:      "admin",
+		Response: dto.APIKeyResponse{},
+	})
+}
@@ -0,0 +1,308 @@
+// Nexflow admin dashboard. The HTTP API is reached through api/, i.e. /dashboard/api/,
+// so that requests carry the credentials the browser asked for when opening the dashboard.
+"use strict";
+
+const REFRESH_INTERVAL_MS = 30000;
+const MAX_EVENTS = 100;
+const LIVE_EVENT_TYPES = [
+  "connector.message",
+  "router.message",
+  "router.error",
+  "router.handoff",
+  "feedback.received",
+  "schedule.triggered",
+  "schedule.completed",
+  "schedule.failed",
+];
+
+// request calls the HTTP API, sending body as JSON if given, and returns the decoded response body
+async function request(method, path, body) {
+  const headers = { Accept: "application/json" };
+  if (body !== undefined) {
+    headers["Content-Type"] = "application/json";
+  }
+  const resp = await fetch("api" + path, {
+    method,
+    credentials: "same-origin",
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const result = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    throw new Error((result.error && result.error.message) || resp.statusText);
+  }
+  return result;
+}
+
+// el creates an element with the given class and text
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined) {
+    node.textContent = text;
+  }
+  if (className) {
+    node.className = className;
+  }
+  return node;
+}
+
+// button creates a button that runs an action and refreshes the dashboard
+function button(label, action) {
+  const node = el("button", label);
+  node.type = "button";
+  node.addEventListener("click", async () => {
+    node.disabled = true;
+    try {
+      await action();
+      showStatus("");
+    } catch (err) {
+      showStatus(label + " failed: " + err.message);
+    }
+    refresh();
+  });
+  return node;
+}
+
+// state renders an on/off cell
+function state(on, onText, offText) {
+  return el("td", on ? onText : offText, on ? "ok" : "off");
+}
+
+// fill replaces the rows of a section's table
+function fill(section, items, columns, render) {
+  const tbody = document.querySelector("#" + section + " tbody");
+  tbody.replaceChildren();
+  if (!items || items.length === 0) {
+    const tr = el("tr");
+    const td = el("td", "none", "empty");
+    td.colSpan = columns;
+    tr.append(td);
+    tbody.append(tr);
+    return;
+  }
+  for (const item of items) {
+    const tr = el("tr");
+    tr.append(...render(item));
+    tbody.append(tr);
+  }
+}
+
+// cell wraps a node or text in a table cell
+function cell(content) {
+  const td = el("td");
+  td.append(content === undefined || content === null ? "" : content);
+  return td;
+}
+
+function showStatus(message) {
+  document.getElementById("status").textContent = message;
+}
+
+async function loadConnectors() {
+  const body = await request("GET", "/admin/connectors");
+  fill("connectors", body.connectors, 4, (c) => [
+    cell(c.name),
+    state(c.running, "running", "stopped"),
+    cell(String(c.pending)),
+    cell(String(c.messages_received)),
+  ]);
+}
+
+async function loadProviders() {
+  const body = await request("GET", "/admin/llm/providers");
+  fill("providers", body.providers, 5, (p) => {
+    const last = p.history && p.history[p.history.length - 1];
+    return [
+      cell(p.active ? p.name + " (default)" : p.name),
+      cell(p.model),
+      providerState(p),
+      cell(last ? last.latency_ms + " ms" : ""),
+      cell(checks(p.history)),
+    ];
+  });
+}
+
+// providerState renders the health of a provider, or its availability without health checks
+function providerState(provider) {
+  if (provider.healthy !== undefined) {
+    return state(provider.healthy, "healthy", "down");
+  }
+  if (provider.available !== undefined) {
+    return state(provider.available, "available", "unavailable");
+  }
+  return cell("");
+}
+
+// checks renders the recent health checks of a provider, oldest first, as a row of marks
+function checks(history) {
+  const span = el("span");
+  for (const check of history || []) {
+    const mark = el("span", "●", check.available ? "ok" : "off");
+    mark.title = new Date(check.checked_at).toLocaleString() + ", " + check.latency_ms + " ms";
+    span.append(mark);
+  }
+  return span;
+}
+
+async function loadSessions() {
+  const body = await request("GET", "/sessions?limit=20");
+  fill("sessions", body.sessions, 4, (s) => [
+    cell(el("code", s.id)),
+    cell(s.user_id),
+    cell(new Date(s.updated_at).toLocaleString()),
+    cell(s.pinned ? "yes" : ""),
+  ]);
+}
+
+async function loadSkills() {
+  const body = await request("GET", "/skills");
+  fill("skills", body.skills, 4, (s) => [
+    cell(s.name),
+    cell(s.version),
+    state(!s.disabled, "enabled", "disabled"),
+    cell(s.disabled
+      ? button("Enable", () => request("POST", "/skills/" + encodeURIComponent(s.id) + "/enable"))
+      : button("Disable", () => request("POST", "/skills/" + encodeURIComponent(s.id) + "/disable"))),
+  ]);
+}
+
+async function loadSchedules() {
+  const body = await request("GET", "/schedules");
+  fill("schedules", body.schedules, 4, (s) => [
+    cell(s.skill || "prompt: " + s.prompt),
+    cell(el("code", s.type === "once" ? s.run_at : s.cron_expression)),
+    state(s.enabled, "enabled", "paused"),
+    cell(button("Run now", () => request("POST", "/schedules/" + encodeURIComponent(s.id) + "/run-now"))),
+  ]);
+}
+
+// loadVersion shows the server version and a notice when update_check found a newer release
+async function loadVersion() {
+  const body = await request("GET", "/version");
+  document.getElementById("version").textContent = body.version;
+  const notice = document.getElementById("update");
+  notice.hidden = !(body.update && body.update.available);
+  if (!notice.hidden) {
+    notice.textContent = "Nexflow " + body.update.latest_version + " is available";
+    notice.href = body.update.release_url;
+  }
+}
+
+const ANALYTICS_DAYS = 7;
+
+async function loadAnalytics() {
+  const until = new Date();
+  const since = new Date(until.getTime() - (ANALYTICS_DAYS - 1) * 24 * 60 * 60 * 1000);
+  const day = (d) => d.toISOString().slice(0, 10);
+  const range = "?since=" + day(since) + "&until=" + day(until);
+  const [body, feedback] = await Promise.all([
+    request("GET", "/analytics/daily" + range),
+    request("GET", "/analytics/feedback" + range),
+  ]);
+  const ratings = new Map((feedback.days || []).map((d) => [d.day, d]));
+  const days = (body.days || []).slice().reverse();
+  fill("analytics", days, 7, (d) => [
+    cell(d.day),
+    cell(String(d.messages)),
+    cell(String(d.active_users)),
+    cell(String(d.tokens)),
+    cell("$" + d.cost.toFixed(4)),
+    cell(d.skill_failures > 0 ? d.skill_executions + " (" + d.skill_failures + " failed)" : String(d.skill_executions)),
+    cell(rating(ratings.get(d.day))),
+  ]);
+}
+
+// rating renders the thumbs up and down counts of a day
+function rating(counts) {
+  if (!counts || counts.up + counts.down === 0) {
+    return "";
+  }
+  return "👍 " + counts.up + " 👎 " + counts.down;
+}
+
+// refresh reloads all sections; a failing section does not prevent the others from loading
+async function refresh() {
+  const results = await Promise.allSettled([loadVersion(), loadConnectors(), loadProviders(), loadAnalytics(), loadSessions(), loadSkills(), loadSchedules()]);
+  const failed = results.filter((r) => r.status === "rejected").map((r) => r.reason.message);
+  if (failed.length > 0) {
+    showStatus([...new Set(failed)].join("; "));
+  }
+}
+
+// describe returns the text shown for a live event
+function describe(event) {
+  switch (event.type) {
+    case "connector.message":
+      return event.connector + " ← " + event.user_id + ": " + event.content;
+    case "router.message":
+      return event.connector + " → session " + event.session_id + ": " + event.content;
+    case "router.error":
+      return "routing failed for " + event.user_id + ": " + event.error;
+    case "router.handoff":
+      return event.connector + " ← " + event.user_id + " (session " + event.session_id + ", handed off): " + event.content;
+    case "feedback.received":
+      return event.user_id + " rated " + event.message_id + (event.rating === "up" ? " 👍" : " 👎") + (event.content ? ": " + event.content : "");
+    case "schedule.triggered":
+      return "schedule " + event.skill + " triggered";
+    case "schedule.completed":
+      return "schedule " + event.skill + " completed in " + (event.duration_ms || 0) + " ms";
+    case "schedule.failed":
+      return "schedule " + event.skill + " failed: " + event.error;
+    default:
+      return event.type;
+  }
+}
+
+// feedbackButtons creates the buttons rating a reply; the rating is saved like the buttons under
+// Telegram replies
+function feedbackButtons(messageID) {
+  return ["up", "down"].map((r) => {
+    const label = r === "up" ? "👍" : "👎";
+    const node = el("button", label);
+    node.type = "button";
+    node.addEventListener("click", async () => {
+      try {
+        await request("POST", "/messages/" + encodeURIComponent(messageID) + "/feedback", { rating: r });
+        showStatus("");
+      } catch (err) {
+        showStatus("Rating failed: " + err.message);
+      }
+    });
+    return node;
+  });
+}
+
+// connectEvents streams live events into the list; EventSource reconnects on its own
+function connectEvents() {
+  const list = document.getElementById("events");
+  const status = document.getElementById("live-status");
+  const source = new EventSource("api/events");
+
+  source.onopen = () => {
+    status.textContent = "connected";
+  };
+  source.onerror = () => {
+    status.textContent = "reconnecting…";
+  };
+
+  const onEvent = (message) => {
+    const event = JSON.parse(message.data);
+    const li = el("li");
+    li.append(el("time", new Date(event.timestamp).toLocaleTimeString()), " ", el("span", describe(event), event.error ? "off" : ""));
+    if (event.type === "router.message" && event.message_id) {
+      li.append(" ", ...feedbackButtons(event.message_id));
+    }
+    list.prepend(li);
+    while (list.children.length > MAX_EVENTS) {
+      list.lastChild.remove();
+    }
+  };
+  for (const type of LIVE_EVENT_TYPES) {
+    source.addEventListener(type, onEvent);
+  }
+}
+
+document.getElementById("refresh").addEventListener("click", refresh);
+refresh();
+setInterval(refresh, REFRESH_INTERVAL_MS);
+connectEvents();
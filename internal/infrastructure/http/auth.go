@@ -0,0 +1,267 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// APIKeyHeader is the request header carrying an API key.
+// Keys can also be sent as "Authorization: Bearer <key>".
+const APIKeyHeader = "X-API-Key"
+
+// AccessTokenParam is the query parameter carrying an API key or JWT on WebSocket upgrade
+// requests, since browsers cannot set headers on WebSocket connections
+const AccessTokenParam = "access_token"
+
+// errNoCredentials is returned when a request carries neither an API key, a bearer token nor Basic credentials
+var errNoCredentials = errors.New("no credentials")
+
+// APIKeyAuthenticator resolves API keys created through the admin API
+type APIKeyAuthenticator interface {
+	// Authenticate returns the scope of an active API key and the workspace it is bound to,
+	// empty for keys of all workspaces
+	Authenticate(ctx context.Context, key string) (valueobject.APIKeyScope, valueobject.WorkspaceID, error)
+}
+
+// staticAPIKey is an API key from the configuration
+type staticAPIKey struct {
+	hash  string
+	scope valueobject.APIKeyScope
+}
+
+// Authenticator authenticates HTTP API requests with API keys and JWT bearer tokens,
+// which can also be sent as the password of HTTP Basic credentials,
+// and checks that the credential's scope allows the request.
+// Read scope allows GET, HEAD and OPTIONS requests outside /admin/, except WebSocket upgrades;
+// admin scope allows everything. Workspace-bound API keys never allow /admin/ requests and
+// restrict the use cases to the sessions of their workspace.
+type Authenticator struct {
+	config     config.AuthConfig
+	staticKeys []staticAPIKey
+	keys       APIKeyAuthenticator
+	logger     logging.Logger
+	now        func() time.Time
+}
+
+// NewAuthenticator creates a new Authenticator.
+// keys may be nil, in which case only static keys and JWTs are accepted.
+func NewAuthenticator(cfg config.AuthConfig, keys APIKeyAuthenticator, logger logging.Logger) *Authenticator {
+	staticKeys := make([]staticAPIKey, 0, len(cfg.APIKeys))
+	for _, key := range cfg.APIKeys {
+		staticKeys = append(staticKeys, staticAPIKey{
+			hash:  entity.HashAPIKey(key.Key),
+			scope: valueobject.APIKeyScope(key.Scope),
+		})
+	}
+
+	return &Authenticator{
+		config:     cfg,
+		staticKeys: staticKeys,
+		keys:       keys,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Middleware rejects unauthenticated requests with 401 and requests outside
+// the credential's scope with 403. GET /healthz is always allowed.
+// The context of an authenticated request carries its usecase.Principal.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.config.Enabled || isPublicRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scope, workspace, credential, err := a.authenticate(r)
+		switch {
+		case errors.Is(err, errNoCredentials) && a.config.AllowLocalhost && isLocalRequest(r):
+			scope = valueobject.APIKeyScopeAdmin
+		case err != nil:
+			a.logger.Warn("unauthenticated http request", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr, "error", err)
+			// Browsers prompt for Basic credentials, which lets the dashboard be opened directly
+			if isDashboardPath(r.URL.Path) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="nexflow", charset="UTF-8"`)
+			} else {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="nexflow"`)
+			}
+			_ = WriteError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		if !scopeAllows(scope, r) || !workspaceAllows(workspace, r) {
+			a.logger.Warn("forbidden http request", "method", r.Method, "path", r.URL.Path, "scope", scope, "workspace", workspace)
+			_ = WriteError(w, http.StatusForbidden, "insufficient scope")
+			return
+		}
+
+		r = r.WithContext(usecase.WithPrincipal(r.Context(), usecase.Principal{Scope: scope, Workspace: workspace, Credential: credential}))
+		if !workspace.IsEmpty() {
+			r = r.WithContext(usecase.WithWorkspace(r.Context(), workspace))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns the scope of the credential presented with the request, the workspace
+// the credential is bound to, and the SHA-256 of the credential identifying it
+func (a *Authenticator) authenticate(r *http.Request) (valueobject.APIKeyScope, valueobject.WorkspaceID, string, error) {
+	// Only bearer tokens and access tokens can be JWTs
+	key := r.Header.Get(APIKeyHeader)
+	bearer := false
+	if key == "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			key, bearer = strings.TrimSpace(token), true
+		}
+	}
+	if key == "" && isWebSocketUpgrade(r) {
+		key, bearer = r.URL.Query().Get(AccessTokenParam), true
+	}
+	// HTTP Basic credentials carry the API key or JWT as the password; the username is ignored
+	if _, password, ok := r.BasicAuth(); key == "" && ok {
+		key, bearer = password, true
+	}
+	if key == "" {
+		return "", "", "", errNoCredentials
+	}
+	return a.authenticateKey(r.Context(), key, bearer)
+}
+
+// AuthenticateToken authenticates an API key or JWT presented outside an HTTP request, such as
+// in the metadata of a gRPC call, and returns the principal it identifies
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (usecase.Principal, error) {
+	if token == "" {
+		return usecase.Principal{}, errNoCredentials
+	}
+	scope, workspace, credential, err := a.authenticateKey(ctx, token, true)
+	if err != nil {
+		return usecase.Principal{}, err
+	}
+	return usecase.Principal{Scope: scope, Workspace: workspace, Credential: credential}, nil
+}
+
+// authenticateKey returns the scope, the workspace and the SHA-256 of an API key, or of a JWT
+// when the key was sent as a bearer token
+func (a *Authenticator) authenticateKey(ctx context.Context, key string, bearer bool) (valueobject.APIKeyScope, valueobject.WorkspaceID, string, error) {
+	hash := entity.HashAPIKey(key)
+	if bearer && isJWT(key) {
+		scope, err := a.authenticateJWT(key)
+		return scope, "", hash, err
+	}
+
+	for _, static := range a.staticKeys {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(static.hash)) == 1 {
+			return static.scope, "", hash, nil
+		}
+	}
+
+	if a.keys == nil {
+		return "", "", "", errors.New("unknown api key")
+	}
+	scope, workspace, err := a.keys.Authenticate(ctx, key)
+	return scope, workspace, hash, err
+}
+
+// credentialFromContext returns the SHA-256 of the credential a request was authenticated with,
+// or an empty string for requests without credentials
+func credentialFromContext(ctx context.Context) string {
+	principal, _ := usecase.PrincipalFromContext(ctx)
+	return principal.Credential
+}
+
+// authenticateJWT verifies a JWT bearer token and returns the scope from its "scope" claim
+func (a *Authenticator) authenticateJWT(token string) (valueobject.APIKeyScope, error) {
+	if a.config.JWT.Secret == "" {
+		return "", errors.New("jwt authentication is not configured")
+	}
+
+	claims, err := verifyJWT(token, []byte(a.config.JWT.Secret), a.config.JWT.Issuer, a.config.JWT.Audience, a.now())
+	if err != nil {
+		return "", err
+	}
+
+	for _, scope := range strings.Fields(claims.Scope) {
+		if valueobject.APIKeyScope(scope).IsAdmin() {
+			return valueobject.APIKeyScopeAdmin, nil
+		}
+	}
+	return valueobject.APIKeyScopeRead, nil
+}
+
+// workspaceAllows reports whether a credential bound to a workspace may make the request.
+// The admin API, the analytics and the user data export and erasure, which span all
+// workspaces, are closed to bound credentials.
+func workspaceAllows(workspace valueobject.WorkspaceID, r *http.Request) bool {
+	if workspace.IsEmpty() {
+		return true
+	}
+	path := apiPath(r)
+	return !strings.HasPrefix(path, "/admin/") && !strings.HasPrefix(path, "/analytics/") && !isUserDataPath(path)
+}
+
+// isUserDataPath reports whether a path is the export or erasure of the data of a user
+func isUserDataPath(path string) bool {
+	rest, ok := strings.CutPrefix(path, "/users/")
+	if !ok {
+		return false
+	}
+	_, action, ok := strings.Cut(rest, "/")
+	return ok && (action == "export" || action == "data")
+}
+
+// scopeAllows reports whether a scope allows the request
+func scopeAllows(scope valueobject.APIKeyScope, r *http.Request) bool {
+	if scope.IsAdmin() {
+		return true
+	}
+	// The WebSocket chat API sends messages, so it is not read-only
+	if isWebSocketUpgrade(r) {
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return !strings.HasPrefix(apiPath(r), "/admin/")
+	default:
+		return false
+	}
+}
+
+// isPublicRequest reports whether a request is allowed without credentials
+func isPublicRequest(r *http.Request) bool {
+	return r.Method == http.MethodGet && r.URL.Path == "/healthz"
+}
+
+// isLocalRequest reports whether a request comes directly from a loopback address.
+// Requests forwarded by a proxy and cross-origin browser requests, which a web page
+// open on the developer's machine could send, are never treated as local.
+func isLocalRequest(r *http.Request) bool {
+	if r.Header.Get("X-Forwarded-For") != "" || r.Header.Get("Forwarded") != "" {
+		return false
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return false
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
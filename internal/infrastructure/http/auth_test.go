@@ -0,0 +1,274 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+type stubAPIKeys map[string]valueobject.APIKeyScope
+
+func (s stubAPIKeys) Authenticate(ctx context.Context, key string) (valueobject.APIKeyScope, valueobject.WorkspaceID, error) {
+	if scope, ok := s[key]; ok {
+		// Keys named after a workspace, like "support:key", are bound to it
+		if workspace, _, ok := strings.Cut(key, ":"); ok {
+			return scope, valueobject.WorkspaceID(workspace), nil
+		}
+		return scope, "", nil
+	}
+	return "", "", errors.New("invalid api key")
+}
+
+func signTestJWT(t *testing.T, alg string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(testJWTSecret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func newTestAuthenticator() *Authenticator {
+	cfg := config.AuthConfig{
+		Enabled:        true,
+		AllowLocalhost: true,
+		APIKeys: []config.APIKeyConfig{
+			{Name: "ci", Key: "static-read", Scope: "read"},
+			{Name: "ops", Key: "static-admin", Scope: "admin"},
+		},
+		JWT: config.JWTConfig{Secret: testJWTSecret, Issuer: "nexflow-test", Audience: "api"},
+	}
+	return NewAuthenticator(cfg, stubAPIKeys{"nfx_db": valueobject.APIKeyScopeRead, "support:nfx_db": valueobject.APIKeyScopeAdmin}, logging.NewNoopLogger())
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		remoteAddr string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{name: "health check is public", method: "GET", path: "/healthz", wantStatus: http.StatusOK},
+		{name: "missing credentials", method: "GET", path: "/sessions", wantStatus: http.StatusUnauthorized},
+		{name: "localhost without credentials", method: "POST", path: "/admin/backups", remoteAddr: "127.0.0.1:5000", wantStatus: http.StatusOK},
+		{name: "forwarded localhost request", method: "GET", path: "/sessions", remoteAddr: "127.0.0.1:5000", headers: map[string]string{"X-Forwarded-For": "203.0.113.7"}, wantStatus: http.StatusUnauthorized},
+		{name: "localhost with invalid key", method: "GET", path: "/sessions", remoteAddr: "[::1]:5000", headers: map[string]string{APIKeyHeader: "wrong"}, wantStatus: http.StatusUnauthorized},
+		{name: "static read key", method: "GET", path: "/sessions", headers: map[string]string{APIKeyHeader: "static-read"}, wantStatus: http.StatusOK},
+		{name: "read key cannot write", method: "POST", path: "/messages", headers: map[string]string{APIKeyHeader: "static-read"}, wantStatus: http.StatusForbidden},
+		{name: "read key cannot read admin", method: "GET", path: "/admin/api-keys", headers: map[string]string{APIKeyHeader: "static-read"}, wantStatus: http.StatusForbidden},
+		{name: "static admin key as bearer", method: "POST", path: "/admin/api-keys", headers: map[string]string{"Authorization": "Bearer static-admin"}, wantStatus: http.StatusOK},
+		{name: "database key", method: "GET", path: "/sessions", headers: map[string]string{APIKeyHeader: "nfx_db"}, wantStatus: http.StatusOK},
+		{name: "workspace-bound key", method: "POST", path: "/messages", headers: map[string]string{APIKeyHeader: "support:nfx_db"}, wantStatus: http.StatusOK},
+		{name: "workspace-bound key cannot use admin", method: "GET", path: "/admin/api-keys", headers: map[string]string{APIKeyHeader: "support:nfx_db"}, wantStatus: http.StatusForbidden},
+		{name: "workspace-bound key cannot read analytics", method: "GET", path: "/analytics/daily", headers: map[string]string{APIKeyHeader: "support:nfx_db"}, wantStatus: http.StatusForbidden},
+		{name: "workspace-bound key cannot read feedback analytics", method: "GET", path: "/analytics/feedback", headers: map[string]string{APIKeyHeader: "support:nfx_db"}, wantStatus: http.StatusForbidden},
+		{name: "workspace-bound key cannot export user data", method: "GET", path: "/users/u1/export", headers: map[string]string{APIKeyHeader: "support:nfx_db"}, wantStatus: http.StatusForbidden},
+		{name: "workspace-bound key cannot erase user data", method: "DELETE", path: "/users/u1/data", headers: map[string]string{APIKeyHeader: "support:nfx_db"}, wantStatus: http.StatusForbidden},
+		{name: "read key cannot erase user data", method: "DELETE", path: "/users/u1/data", headers: map[string]string{APIKeyHeader: "static-read"}, wantStatus: http.StatusForbidden},
+		{name: "unknown key", method: "GET", path: "/sessions", headers: map[string]string{APIKeyHeader: "nfx_unknown"}, wantStatus: http.StatusUnauthorized},
+		{name: "websocket requires admin scope", method: "GET", path: "/chat/ws", headers: map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", APIKeyHeader: "static-read"}, wantStatus: http.StatusForbidden},
+		{name: "websocket access token", method: "GET", path: "/chat/ws?access_token=static-admin", headers: map[string]string{"Connection": "Upgrade", "Upgrade": "websocket"}, wantStatus: http.StatusOK},
+		{name: "access token ignored without upgrade", method: "GET", path: "/sessions?access_token=static-admin", wantStatus: http.StatusUnauthorized},
+		{name: "basic auth password is the api key", method: "GET", path: "/sessions", headers: map[string]string{"Authorization": basicAuth("anyone", "static-read")}, wantStatus: http.StatusOK},
+		{name: "basic auth with wrong password", method: "GET", path: "/sessions", headers: map[string]string{"Authorization": basicAuth("anyone", "wrong")}, wantStatus: http.StatusUnauthorized},
+		{name: "dashboard requires credentials", method: "GET", path: "/dashboard/", wantStatus: http.StatusUnauthorized},
+		{name: "dashboard with basic auth", method: "GET", path: "/dashboard/", headers: map[string]string{"Authorization": basicAuth("", "static-read")}, wantStatus: http.StatusOK},
+		{name: "read key cannot read admin through dashboard", method: "GET", path: "/dashboard/api/admin/connectors", headers: map[string]string{"Authorization": basicAuth("", "static-read")}, wantStatus: http.StatusForbidden},
+		{name: "admin key through dashboard", method: "POST", path: "/dashboard/api/skills/1/disable", headers: map[string]string{"Authorization": basicAuth("", "static-admin")}, wantStatus: http.StatusOK},
+		{name: "cross-origin localhost request", method: "GET", path: "/sessions", remoteAddr: "127.0.0.1:5000", headers: map[string]string{"Origin": "https://evil.example"}, wantStatus: http.StatusUnauthorized},
+		{
+			name:   "jwt with admin scope",
+			method: "DELETE",
+			path:   "/users/1",
+			headers: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "HS256", map[string]any{
+				"sub": "svc", "iss": "nexflow-test", "aud": []string{"api"}, "exp": future, "scope": "read admin",
+			})},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "jwt defaults to read scope",
+			method: "POST",
+			path:   "/messages",
+			headers: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "HS256", map[string]any{
+				"iss": "nexflow-test", "aud": "api", "exp": future,
+			})},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "expired jwt",
+			method: "GET",
+			path:   "/sessions",
+			headers: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "HS256", map[string]any{
+				"iss": "nexflow-test", "aud": "api", "exp": past,
+			})},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "jwt without exp",
+			method: "GET",
+			path:   "/sessions",
+			headers: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "HS256", map[string]any{
+				"sub": "svc", "iss": "nexflow-test", "aud": "api", "scope": "read admin",
+			})},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "jwt with wrong issuer",
+			method: "GET",
+			path:   "/sessions",
+			headers: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "HS256", map[string]any{
+				"iss": "other", "aud": "api", "exp": future,
+			})},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "jwt with unsupported algorithm",
+			method: "GET",
+			path:   "/sessions",
+			headers: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "none", map[string]any{
+				"iss": "nexflow-test", "aud": "api", "exp": future,
+			})},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	handler := newTestAuthenticator().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = "203.0.113.1:1234"
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				challenge := "Bearer"
+				if strings.HasPrefix(tt.path, "/dashboard") {
+					challenge = "Basic"
+				}
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), challenge)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_WorkspaceBoundKey(t *testing.T) {
+	var workspace valueobject.WorkspaceID
+	handler := newTestAuthenticator().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspace = usecase.WorkspaceFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/sessions", nil)
+	req.Header.Set(APIKeyHeader, "support:nfx_db")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, valueobject.WorkspaceID("support"), workspace)
+
+	req = httptest.NewRequest("GET", "/sessions", nil)
+	req.Header.Set(APIKeyHeader, "static-admin")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, workspace.IsEmpty(), "static keys are not bound to a workspace")
+}
+
+func TestAuthenticator_Principal(t *testing.T) {
+	var principal usecase.Principal
+	var ok bool
+	handler := newTestAuthenticator().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok = usecase.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/sessions", nil)
+	req.RemoteAddr = "203.0.113.7:4000"
+	req.Header.Set(APIKeyHeader, "support:nfx_db")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.True(t, ok)
+	assert.Equal(t, usecase.Principal{
+		Scope:      valueobject.APIKeyScopeAdmin,
+		Workspace:  "support",
+		Credential: entity.HashAPIKey("support:nfx_db"),
+	}, principal)
+
+	// Local requests without credentials are admins without a credential
+	req = httptest.NewRequest("GET", "/sessions", nil)
+	req.RemoteAddr = "127.0.0.1:4000"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.True(t, ok)
+	assert.Equal(t, usecase.Principal{Scope: valueobject.APIKeyScopeAdmin}, principal)
+
+	// Public requests are not authenticated
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	assert.False(t, ok)
+}
+
+func TestAuthenticator_Disabled(t *testing.T) {
+	auth := NewAuthenticator(config.AuthConfig{Enabled: false}, nil, logging.NewNoopLogger())
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/admin/backups", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestVerifyJWT_TamperedSignature(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	token := signTestJWT(t, "HS256", map[string]any{"sub": "svc", "exp": exp, "scope": "read"})
+	tampered := signTestJWT(t, "HS256", map[string]any{"sub": "svc", "exp": exp, "scope": "admin"})
+	// Combine the payload of one token with the signature of another
+	forged := tampered[:len(tampered)-43] + token[len(token)-43:]
+
+	_, err := verifyJWT(forged, []byte(testJWTSecret), "", "", time.Now())
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	claims, err := verifyJWT(token, []byte(testJWTSecret), "", "", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "svc", claims.Subject)
+}
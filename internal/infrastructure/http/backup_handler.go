@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// BackupHandler handles the database backup admin API
+type BackupHandler struct {
+	backupUseCase *usecase.BackupUseCase
+	logger        logging.Logger
+}
+
+// NewBackupHandler creates a new BackupHandler
+func NewBackupHandler(backupUseCase *usecase.BackupUseCase, logger logging.Logger) *BackupHandler {
+	return &BackupHandler{
+		backupUseCase: backupUseCase,
+		logger:        logger,
+	}
+}
+
+// CreateBackup handles POST /admin/backups
+func (h *BackupHandler) CreateBackup(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	// The request body is optional
+	var req dto.CreateBackupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("failed to decode backup request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	resp, err := h.backupUseCase.CreateBackup(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to create backup", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusCreated, resp)
+}
+
+// ListBackups handles GET /admin/backups
+func (h *BackupHandler) ListBackups(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.backupUseCase.ListBackups(ctx)
+	if err != nil {
+		h.logger.Error("failed to list backups", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RestoreBackup handles POST /admin/backups/restore
+func (h *BackupHandler) RestoreBackup(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req dto.RestoreBackupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode restore request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.backupUseCase.RestoreBackup(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to restore backup", "error", err, "name", req.Name)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterBackupRoutes registers the backup admin routes
+func RegisterBackupRoutes(r *Router, handler *BackupHandler) {
+	backups := r.Group("/admin/backups")
+	backups.HandleFunc("POST /", handler.CreateBackup).Describe(RouteDoc{
+		Summary:  "Create a database backup",
+		Tag:      "admin",
+		Request:  dto.CreateBackupRequest{},
+		Response: dto.BackupResponse{},
+		Status:   http.StatusCreated,
+	})
+	backups.HandleFunc("GET /", handler.ListBackups).Describe(RouteDoc{
+		Summary:  "List database backups",
+		Tag:      "admin",
+		Response: dto.BackupsResponse{},
+	})
+	backups.HandleFunc("POST /restore", handler.RestoreBackup).Describe(RouteDoc{
+		Summary:  "Restore the database from a backup",
+		Tag:      "admin",
+		Request:  dto.RestoreBackupRequest{},
+		Response: dto.BackupResponse{},
+	})
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/validation"
+)
+
+// chatWSMaxPending limits the messages a client can queue while a reply is streaming
+const chatWSMaxPending = 8
+
+// ChatWSHandler handles the WebSocket chat API.
+// Clients send dto.StreamMessageRequest messages and receive dto.ChatStreamEvent messages:
+// a "token" event for every chunk of the reply, then "done" with the saved reply, or "error".
+type ChatWSHandler struct {
+	chatUseCase *usecase.ChatUseCase
+	logger      logging.Logger
+}
+
+// NewChatWSHandler creates a new ChatWSHandler
+func NewChatWSHandler(chatUseCase *usecase.ChatUseCase, logger logging.Logger) *ChatWSHandler {
+	return &ChatWSHandler{
+		chatUseCase: chatUseCase,
+		logger:      logger,
+	}
+}
+
+// Chat handles GET /chat/ws
+func (h *ChatWSHandler) Chat(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		h.logger.Warn("websocket upgrade failed", "error", err, "remote_addr", r.RemoteAddr)
+		return nil
+	}
+	defer conn.Close()
+
+	h.logger.Info("websocket chat client connected", "remote_addr", r.RemoteAddr)
+
+	// Reading continues while a reply streams, so that a disconnect cancels generation
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	requests := make(chan dto.StreamMessageRequest, chatWSMaxPending)
+	go func() {
+		defer cancel()
+		defer close(requests)
+		h.readRequests(ctx, conn, requests)
+	}()
+
+	for req := range requests {
+		h.streamReply(ctx, conn, req)
+	}
+
+	h.logger.Info("websocket chat client disconnected", "remote_addr", r.RemoteAddr)
+	return nil
+}
+
+// readRequests reads chat requests from the client until it disconnects
+func (h *ChatWSHandler) readRequests(ctx context.Context, conn *wsConn, requests chan<- dto.StreamMessageRequest) {
+	for {
+		opcode, data, err := conn.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, errWSClosed) {
+				h.logger.Debug("websocket read failed", "error", err)
+			}
+			return
+		}
+
+		if opcode != wsOpText {
+			_ = conn.WriteClose(wsCloseUnsupported, "only text messages are supported")
+			return
+		}
+
+		var req dto.StreamMessageRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			_ = h.writeEvent(conn, &dto.ChatStreamEvent{Type: dto.ChatEventError, Error: "invalid message"})
+			continue
+		}
+		if err := validation.Struct(&req); err != nil {
+			_ = h.writeEvent(conn, &dto.ChatStreamEvent{Type: dto.ChatEventError, Error: err.Error()})
+			continue
+		}
+
+		select {
+		case requests <- req:
+		case <-ctx.Done():
+			return
+		default:
+			_ = h.writeEvent(conn, &dto.ChatStreamEvent{Type: dto.ChatEventError, Error: "too many pending messages"})
+		}
+	}
+}
+
+// streamReply sends a chat request and streams the reply to the client
+func (h *ChatWSHandler) streamReply(ctx context.Context, conn *wsConn, req dto.StreamMessageRequest) {
+	resp, err := h.chatUseCase.StreamMessage(ctx, req, func(token string) error {
+		return h.writeEvent(conn, &dto.ChatStreamEvent{Type: dto.ChatEventToken, Content: token})
+	})
+	if err != nil {
+		h.logger.Error("failed to stream message", "error", err, "session_id", req.SessionID)
+	}
+
+	if !resp.Success {
+		_ = h.writeEvent(conn, &dto.ChatStreamEvent{Type: dto.ChatEventError, Error: resp.Error})
+		return
+	}
+	_ = h.writeEvent(conn, &dto.ChatStreamEvent{Type: dto.ChatEventDone, Message: resp.Message})
+}
+
+// writeEvent sends an event to the client
+func (h *ChatWSHandler) writeEvent(conn *wsConn, event *dto.ChatStreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return conn.WriteText(data)
+}
+
+// RegisterChatWSRoutes registers the WebSocket chat route
+func RegisterChatWSRoutes(r *Router, handler *ChatWSHandler) {
+	chat := r.Group("/chat")
+	chat.HandleFunc("GET /ws", handler.Chat).Describe(RouteDoc{
+		Summary: "WebSocket chat with streamed replies",
+		Description: "Upgrades to a WebSocket. Send StreamMessageRequest messages as JSON text frames; " +
+			"the server replies with ChatStreamEvent messages: token events with chunks of the reply, " +
+			"then a done event with the saved reply or an error event.",
+		Tag:    "messages",
+		Status: http.StatusSwitchingProtocols,
+	})
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompressTypes are the media types compressed when Compress is given no types
+var DefaultCompressTypes = []string{
+	"application/json",
+	"application/javascript",
+	"image/svg+xml",
+	"text/",
+}
+
+// compressor is a compressing writer that can flush buffered data
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// Compress returns a middleware that compresses responses with gzip or deflate, as accepted by the client.
+// Only responses whose Content-Type starts with one of the given types are compressed
+// (DefaultCompressTypes if none are given); WebSocket upgrades and HEAD requests are passed through.
+// An invalid level falls back to gzip.DefaultCompression.
+func Compress(level int, types ...string) Middleware {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	if len(types) == 0 {
+		types = DefaultCompressTypes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead || isWebSocketUpgrade(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       negotiateEncoding(r.Header.Get("Accept-Encoding")),
+				level:          level,
+				types:          types,
+			}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the content coding to use for an Accept-Encoding header:
+// "gzip", "deflate", or "" if the client accepts neither
+func negotiateEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[coding] = q > 0
+	}
+
+	for _, coding := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[coding]; ok || (!listed && accepted["*"]) {
+			return coding
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter compresses the response body once the status and
+// Content-Type show that the response should be compressed
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	level    int
+	types    []string
+
+	wroteHeader bool
+	writer      compressor // nil if the response is not compressed
+}
+
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.wroteHeader = true
+
+	h := cw.Header()
+	if cw.compressible(code) {
+		h.Add("Vary", "Accept-Encoding")
+		if cw.encoding != "" {
+			h.Set("Content-Encoding", cw.encoding)
+			h.Del("Content-Length")
+			cw.writer = cw.newCompressor()
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.writer != nil {
+		return cw.writer.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered compressed data to the client, so that streamed responses are not held back
+func (cw *compressResponseWriter) Flush() {
+	if cw.writer != nil {
+		_ = cw.writer.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, so that http.ResponseController
+// can reach optional interfaces such as http.Hijacker
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether a response with the given status and the current headers can be compressed
+func (cw *compressResponseWriter) compressible(code int) bool {
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		return false
+	}
+	if cw.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := cw.Header().Get("Content-Type")
+	for _, t := range cw.types {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCompressor creates the writer for the negotiated encoding
+func (cw *compressResponseWriter) newCompressor() compressor {
+	// The level is validated by Compress, so the constructors cannot fail
+	if cw.encoding == "deflate" {
+		// The deflate content coding is the zlib format, not raw deflate
+		zw, _ := zlib.NewWriterLevel(cw.ResponseWriter, cw.level)
+		return zw
+	}
+	gw, _ := gzip.NewWriterLevel(cw.ResponseWriter, cw.level)
+	return gw
+}
+
+// close flushes the remaining compressed data
+func (cw *compressResponseWriter) close() {
+	if cw.writer != nil {
+		_ = cw.writer.Close()
+	}
+}
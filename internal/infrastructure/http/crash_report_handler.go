@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// CrashReportHandler handles requests for the crash log of recovered panics
+type CrashReportHandler struct {
+	crashReportUseCase *usecase.CrashReportUseCase
+	logger             logging.Logger
+}
+
+// NewCrashReportHandler creates a new CrashReportHandler
+func NewCrashReportHandler(crashReportUseCase *usecase.CrashReportUseCase, logger logging.Logger) *CrashReportHandler {
+	return &CrashReportHandler{
+		crashReportUseCase: crashReportUseCase,
+		logger:             logger,
+	}
+}
+
+// ListCrashReports handles GET /admin/crashes
+func (h *CrashReportHandler) ListCrashReports(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"), "offset")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.crashReportUseCase.ListCrashReports(ctx, dto.ListCrashReportsRequest{
+		Component: query.Get("component"),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		h.logger.Error("failed to list crash reports", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterCrashReportRoutes registers crash log routes
+func RegisterCrashReportRoutes(r *Router, handler *CrashReportHandler) {
+	crashes := r.Group("/admin/crashes")
+	crashes.HandleFunc("GET /", handler.ListCrashReports).Describe(RouteDoc{
+		Summary:     "List recovered panics",
+		Description: "Panics recovered in the router, connectors, skills and HTTP handlers, with their stack traces, newest first. Requires observability.crashes.store.",
+		Tag:         "admin",
+		Query: []QueryParam{
+			{Name: "component", Description: "Only panics of this component: router, telegram, skills or http"},
+			{Name: "limit", Description: "Maximum number of reports to return (default 50, max 500)"},
+			{Name: "offset", Description: "Number of reports to skip"},
+		},
+		Response: dto.CrashReportsResponse{},
+	})
+}
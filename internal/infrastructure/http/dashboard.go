@@ -0,0 +1,59 @@
+package http
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// DashboardPath is the path prefix of the admin dashboard
+const DashboardPath = "/dashboard/"
+
+// dashboardAPIPrefix prefixes the API requests of the dashboard. Browsers only send the
+// Basic credentials entered for the dashboard to paths below it, so the dashboard reaches
+// the HTTP API through /dashboard/api/<path> instead of /<path>.
+const dashboardAPIPrefix = "/dashboard/api"
+
+//go:embed assets/dashboard
+var dashboardAssets embed.FS
+
+// RegisterDashboardRoutes registers the admin dashboard: its static assets under /dashboard/
+// and the HTTP API of the router under /dashboard/api/.
+// The dashboard is not part of the OpenAPI specification.
+func RegisterDashboardRoutes(r *Router) {
+	assets, err := fs.Sub(dashboardAssets, "assets/dashboard")
+	if err != nil {
+		panic(err)
+	}
+	r.Handle("GET "+DashboardPath, http.StripPrefix(DashboardPath, http.FileServerFS(assets)))
+
+	api := dashboardAPI(r)
+	r.Handle("GET "+dashboardAPIPrefix+"/", api)
+	r.Handle("POST "+dashboardAPIPrefix+"/", api)
+}
+
+// dashboardAPI serves /dashboard/api/<path> as /<path> of the router.
+// The request has already passed the middleware, including authentication, under its dashboard path.
+func dashboardAPI(router *Router) http.Handler {
+	return http.StripPrefix(dashboardAPIPrefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isDashboardPath(r.URL.Path) {
+			_ = WriteError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+}
+
+// isDashboardPath reports whether a path belongs to the dashboard
+func isDashboardPath(path string) bool {
+	return path == strings.TrimSuffix(DashboardPath, "/") || strings.HasPrefix(path, DashboardPath)
+}
+
+// apiPath returns the API path a request is served under, with the dashboard prefix removed
+func apiPath(r *http.Request) string {
+	if path, ok := strings.CutPrefix(r.URL.Path, dashboardAPIPrefix); ok && strings.HasPrefix(path, "/") {
+		return path
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	"github.com/atumaikin/nexflow/internal/shared/validation"
+)
+
+// ErrorCode identifies the kind of an API error.
+// Clients should branch on the code rather than on the message, which may change.
+type ErrorCode string
+
+// Error codes of the HTTP API
+const (
+	ErrCodeBadRequest           ErrorCode = "bad_request"       // Malformed request or invalid parameters
+	ErrCodeValidation           ErrorCode = "validation_failed" // Request body failed validation; details lists the fields
+	ErrCodeUnauthorized         ErrorCode = "unauthorized"      // Missing or invalid credentials
+	ErrCodeForbidden            ErrorCode = "forbidden"         // Credentials lack the required scope
+	ErrCodeNotFound             ErrorCode = "not_found"         // Resource or route does not exist
+	ErrCodeMethodNotAllowed     ErrorCode = "method_not_allowed"
+	ErrCodeConflict             ErrorCode = "conflict"               // Resource already exists or is in a conflicting state
+	ErrCodeRateLimited          ErrorCode = "rate_limited"           // Rate limit exceeded; see Retry-After
+	ErrCodeIdempotencyKeyReused ErrorCode = "idempotency_key_reused" // Idempotency-Key was used with another request
+	ErrCodeUpgradeRequired      ErrorCode = "upgrade_required"       // Unsupported protocol version
+	ErrCodeInternal             ErrorCode = "internal_error"
+	ErrCodeUnavailable          ErrorCode = "unavailable"
+	ErrCodeTimeout              ErrorCode = "timeout"
+)
+
+// APIError describes an error returned by the HTTP API
+type APIError struct {
+	Code      ErrorCode `json:"code"`
+	Message   string    `json:"message"`
+	Details   any       `json:"details,omitempty"`    // Code-specific details, such as field errors
+	RequestID string    `json:"request_id,omitempty"` // Value of the X-Request-ID response header
+}
+
+// ErrorResponse is the body of every error response of the HTTP API
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// errorCodeForStatus returns the default error code of an HTTP status
+func errorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrCodeForbidden
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrCodeMethodNotAllowed
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case http.StatusUpgradeRequired:
+		return ErrCodeUpgradeRequired
+	case http.StatusServiceUnavailable:
+		return ErrCodeUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrCodeTimeout
+	}
+	if status >= 400 && status < 500 {
+		return ErrCodeBadRequest
+	}
+	return ErrCodeInternal
+}
+
+// statusForError maps an error returned by a use case to an HTTP status
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, ports.ErrConnectorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrConflict), errors.Is(err, ports.ErrConnectorState), errors.Is(err, ports.ErrSkillDisabled),
+		errors.Is(err, ports.ErrReprocessFailed), errors.Is(err, ports.ErrTaskCancelled), errors.Is(err, valueobject.ErrInvalidTaskTransition):
+		return http.StatusConflict
+	case errors.Is(err, ports.ErrLLMProviderNotFound), errors.Is(err, ports.ErrLLMModelNotAllowed), errors.Is(err, ports.ErrNotReplayable):
+		return http.StatusBadRequest
+	case errors.Is(err, ports.ErrDeadLettersDisabled), errors.Is(err, ports.ErrInstructionsDisabled), errors.Is(err, ports.ErrReplayDisabled),
+		errors.Is(err, ports.ErrSchedulerDisabled), errors.Is(err, usecase.ErrMessageJobQueueFull), errors.Is(err, usecase.ErrMessageJobsStopped):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeUseCaseError writes the error response for an error returned by a use case
+func writeUseCaseError(w http.ResponseWriter, err error, message string) error {
+	return WriteError(w, statusForError(err), message)
+}
+
+// validateRequest validates a decoded request body against its `validate` struct tags.
+// If the body is invalid, it writes a validation_failed error listing the invalid fields and returns false.
+func validateRequest(w http.ResponseWriter, req any) bool {
+	err := validation.Struct(req)
+	if err == nil {
+		return true
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		_ = WriteErrorCode(w, http.StatusBadRequest, ErrCodeValidation, fieldErrs.Error(), fieldErrs)
+	} else {
+		_ = WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+	return false
+}
+
+// envelopeWriter replaces the plain text error responses of http.ServeMux,
+// such as for unknown routes, with the JSON error envelope
+type envelopeWriter struct {
+	http.ResponseWriter
+	replaced bool
+}
+
+func (ew *envelopeWriter) WriteHeader(code int) {
+	if code < http.StatusBadRequest {
+		ew.ResponseWriter.WriteHeader(code)
+		return
+	}
+	_ = WriteError(ew.ResponseWriter, code, http.StatusText(code))
+	ew.replaced = true
+}
+
+func (ew *envelopeWriter) Write(b []byte) (int, error) {
+	if ew.replaced {
+		return len(b), nil
+	}
+	return ew.ResponseWriter.Write(b)
+}
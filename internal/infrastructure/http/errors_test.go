@@ -0,0 +1,153 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func decodeErrorResponse(t *testing.T, w *httptest.ResponseRecorder) APIError {
+	t.Helper()
+
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var body ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.Error
+}
+
+func TestWriteError_Envelope(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Request-ID", "req-1")
+
+	require.NoError(t, WriteErrorCode(w, http.StatusConflict, ErrCodeConflict, "connector is running", map[string]bool{"running": true}))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	apiErr := decodeErrorResponse(t, w)
+	assert.Equal(t, ErrCodeConflict, apiErr.Code)
+	assert.Equal(t, "connector is running", apiErr.Message)
+	assert.Equal(t, "req-1", apiErr.RequestID)
+	assert.Equal(t, map[string]any{"running": true}, apiErr.Details)
+}
+
+func TestStatusForError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("failed to get skill: skill %w: 1", repository.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("failed to create user: %w", repository.ErrConflict), http.StatusConflict},
+		{fmt.Errorf("%w: telegram", ports.ErrConnectorNotFound), http.StatusNotFound},
+		{fmt.Errorf("%w: telegram is running", ports.ErrConnectorState), http.StatusConflict},
+		{fmt.Errorf("failed to reprocess dead letter: %w: llm unavailable", ports.ErrReprocessFailed), http.StatusConflict},
+		{fmt.Errorf("failed to list dead letters: %w", ports.ErrDeadLettersDisabled), http.StatusServiceUnavailable},
+		{fmt.Errorf("failed to replay message: %w", ports.ErrReplayDisabled), http.StatusServiceUnavailable},
+		{fmt.Errorf("failed to replay message: %w: event e-1 is a router.message event", ports.ErrNotReplayable), http.StatusBadRequest},
+		{usecase.ErrMessageJobQueueFull, http.StatusServiceUnavailable},
+		{fmt.Errorf("llm call: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{errors.New("disk full"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			require.NoError(t, writeUseCaseError(w, tt.err, "operation failed"))
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, errorCodeForStatus(tt.want), decodeErrorResponse(t, w).Code)
+		})
+	}
+}
+
+func TestRouter_UnmatchedRequests(t *testing.T) {
+	router := NewRouter()
+	router.HandleFunc("GET /users", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return WriteJSON(w, http.StatusOK, map[string]string{})
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, ErrCodeNotFound, decodeErrorResponse(t, w).Code)
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("DELETE", "/users", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+		assert.Equal(t, ErrCodeMethodNotAllowed, decodeErrorResponse(t, w).Code)
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		router.HandleFunc("GET /skills/{id}", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			return fmt.Errorf("skill %w: %s", repository.ErrNotFound, r.PathValue("id"))
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/skills/42", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "skill not found: 42", decodeErrorResponse(t, w).Message)
+	})
+}
+
+func TestValidateRequest_FieldErrors(t *testing.T) {
+	// Validation fails before the use case is called, so the handler needs none
+	handler := NewScheduleHandler(nil, logging.NewNoopLogger())
+	body := `{"run_at":"tomorrow","missed_run_policy":"always","jitter_seconds":-5}`
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/schedules", strings.NewReader(body))
+	require.NoError(t, handler.CreateSchedule(r.Context(), w, r))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	apiErr := decodeErrorResponse(t, w)
+	assert.Equal(t, ErrCodeValidation, apiErr.Code)
+
+	details, ok := apiErr.Details.([]any)
+	require.True(t, ok, "details should list the invalid fields")
+	fields := make([]string, len(details))
+	for i, d := range details {
+		fields[i] = d.(map[string]any)["field"].(string)
+	}
+	assert.Equal(t, []string{"skill", "jitter_seconds", "run_at", "missed_run_policy"}, fields)
+}
+
+func TestValidateRequest_InvalidCronExpression(t *testing.T) {
+	handler := NewScheduleHandler(nil, logging.NewNoopLogger())
+	body := `{"skill":"echo","cron_expression":"0 25 * * *"}`
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/schedules", strings.NewReader(body))
+	require.NoError(t, handler.CreateSchedule(r.Context(), w, r))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	apiErr := decodeErrorResponse(t, w)
+	assert.Equal(t, ErrCodeValidation, apiErr.Code)
+	assert.Equal(t, "cron_expression must be a cron expression: hour value 25 out of range [0-23]", apiErr.Message)
+}
+
+func TestValidateRequest_Valid(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := dto.CreateUserRequest{Channel: "telegram", ChannelID: "42"}
+
+	assert.True(t, validateRequest(w, &req))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, w.Body.Len())
+}
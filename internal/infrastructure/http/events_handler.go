@@ -0,0 +1,378 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// liveEventTypes are the event bus events streamed by GET /events
+var liveEventTypes = []string{
+	eventbus.EventConnectorMessage,
+	eventbus.EventRouterMessage,
+	eventbus.EventRouterError,
+	eventbus.EventRouterHandoff,
+	eventbus.EventScheduleTriggered,
+	eventbus.EventScheduleCompleted,
+	eventbus.EventScheduleFailed,
+	eventbus.EventFeedbackReceived,
+}
+
+const (
+	// liveEventBuffer is the number of events buffered per client; events are dropped while it is full
+	liveEventBuffer = 64
+
+	// defaultHeartbeatInterval is how often an idle stream sends a comment to keep the connection open
+	defaultHeartbeatInterval = 15 * time.Second
+
+	// defaultEventHistoryLimit and maxEventHistoryLimit bound the events returned by GET /admin/events
+	defaultEventHistoryLimit = 100
+	maxEventHistoryLimit     = 1000
+
+	// defaultDeadLetterLimit and maxDeadLetterLimit bound the dead letters returned by GET /admin/events/dead-letters
+	// and GET /admin/dead-letters
+	defaultDeadLetterLimit = 50
+	maxDeadLetterLimit     = 500
+)
+
+// errHistoryFull stops a replay once a page of stored events has been read
+var errHistoryFull = errors.New("event history page is full")
+
+// EventsHandler streams runtime events, such as routed messages and schedule runs,
+// to HTTP clients as server-sent events
+type EventsHandler struct {
+	bus       *eventbus.EventBus
+	logger    logging.Logger
+	heartbeat time.Duration
+}
+
+// NewEventsHandler creates a new EventsHandler.
+// bus may be nil when the event bus is disabled, in which case streams are refused with 503.
+func NewEventsHandler(bus *eventbus.EventBus, logger logging.Logger) *EventsHandler {
+	return &EventsHandler{
+		bus:       bus,
+		logger:    logger,
+		heartbeat: defaultHeartbeatInterval,
+	}
+}
+
+// Stream handles GET /events.
+// Each event is sent as a server-sent event named after the event type, with a LiveEventDTO as data.
+// Slow clients miss events rather than holding up the event bus.
+func (h *EventsHandler) Stream(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.bus == nil {
+		return WriteError(w, http.StatusServiceUnavailable, "event bus is disabled")
+	}
+
+	events := make(chan eventbus.Event, liveEventBuffer)
+	// All streams share one name, so that they share their queue metrics
+	sub := h.bus.SubscribeWithOptions(liveEventTypes, eventbus.SubscribeOptions{Name: "live-events"}, func(_ context.Context, event eventbus.Event) error {
+		select {
+		case events <- event:
+		default:
+			h.logger.Debug("event stream full, dropping event", "type", event.Type())
+		}
+		return nil
+	})
+	defer h.bus.Unsubscribe(sub)
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		h.logger.Error("event stream cannot be flushed", "error", err)
+		return nil
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-events:
+			data, err := json.Marshal(liveEvent(event))
+			if err != nil {
+				h.logger.Error("failed to marshal live event", "type", event.Type(), "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type(), data); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
+
+// History handles GET /admin/events.
+// It lists the stored events in publication order, optionally narrowed by time range and type.
+func (h *EventsHandler) History(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.bus == nil || !h.bus.HasStore() {
+		return WriteError(w, http.StatusServiceUnavailable, "event store is disabled")
+	}
+
+	query := r.URL.Query()
+	since, err := parseOptionalTime(query.Get("since"), "since")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	until, err := parseOptionalTime(query.Get("until"), "until")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	if limit == 0 {
+		limit = defaultEventHistoryLimit
+	}
+	limit = min(limit, maxEventHistoryLimit)
+
+	var types []string
+	for _, value := range query["type"] {
+		for _, eventType := range strings.Split(value, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				types = append(types, eventType)
+			}
+		}
+	}
+
+	events := make([]*dto.LiveEventDTO, 0, limit)
+	err = h.bus.Replay(ctx, since, until, types, func(_ context.Context, event eventbus.Event) error {
+		events = append(events, liveEvent(event))
+		if len(events) == limit {
+			return errHistoryFull
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errHistoryFull) {
+		h.logger.Error("failed to read stored events", "error", err)
+		return WriteError(w, http.StatusInternalServerError, "failed to read stored events")
+	}
+
+	return WriteJSON(w, http.StatusOK, &dto.LiveEventsResponse{Success: true, Events: events})
+}
+
+// DeadLetters handles GET /admin/events/dead-letters.
+// It lists the events whose handlers kept failing, newest first.
+func (h *EventsHandler) DeadLetters(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.bus == nil {
+		return WriteError(w, http.StatusServiceUnavailable, "event bus is disabled")
+	}
+
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	if limit == 0 {
+		limit = defaultDeadLetterLimit
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"), "offset")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	letters, err := h.bus.ListDeadLetters(ctx, repository.DeadLetterFilter{
+		Subscription: query.Get("subscription"),
+		Limit:        min(limit, maxDeadLetterLimit),
+		Offset:       offset,
+	})
+	if err != nil {
+		h.logger.Error("failed to list dead letters", "error", err)
+		return WriteError(w, http.StatusInternalServerError, "failed to list dead letters")
+	}
+
+	dtos := make([]*dto.DeadLetterDTO, 0, len(letters))
+	for _, letter := range letters {
+		dtos = append(dtos, dto.DeadLetterDTOFromEntity(letter))
+	}
+	return WriteJSON(w, http.StatusOK, &dto.DeadLettersResponse{Success: true, DeadLetters: dtos})
+}
+
+// RedeliverDeadLetter handles POST /admin/events/dead-letters/{id}/redeliver.
+// The dead letter is deleted when its subscription handles the event.
+func (h *EventsHandler) RedeliverDeadLetter(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.bus == nil {
+		return WriteError(w, http.StatusServiceUnavailable, "event bus is disabled")
+	}
+
+	id := r.PathValue("id")
+	err := h.bus.RedeliverDeadLetter(ctx, id)
+	switch {
+	case err == nil:
+		return WriteJSON(w, http.StatusOK, &dto.DeadLetterResponse{Success: true})
+	case errors.Is(err, eventbus.ErrSubscriptionNotFound), errors.Is(err, eventbus.ErrRedeliveryFailed):
+		return WriteErrorCode(w, http.StatusConflict, ErrCodeConflict, err.Error(), nil)
+	case errors.Is(err, repository.ErrNotFound):
+		return WriteError(w, http.StatusNotFound, "dead letter not found")
+	default:
+		h.logger.Error("failed to redeliver dead letter", "error", err, "dead_letter_id", id)
+		return WriteError(w, http.StatusInternalServerError, "failed to redeliver dead letter")
+	}
+}
+
+// DeleteDeadLetter handles DELETE /admin/events/dead-letters/{id}
+func (h *EventsHandler) DeleteDeadLetter(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.bus == nil {
+		return WriteError(w, http.StatusServiceUnavailable, "event bus is disabled")
+	}
+
+	id := r.PathValue("id")
+	if err := h.bus.DeleteDeadLetter(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return WriteError(w, http.StatusNotFound, "dead letter not found")
+		}
+		h.logger.Error("failed to delete dead letter", "error", err, "dead_letter_id", id)
+		return WriteError(w, http.StatusInternalServerError, "failed to delete dead letter")
+	}
+	return WriteJSON(w, http.StatusOK, &dto.DeadLetterResponse{Success: true})
+}
+
+// parseOptionalTime parses an optional RFC3339 query parameter
+func parseOptionalTime(raw, name string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC3339 time", name)
+	}
+	return t, nil
+}
+
+// liveEvent converts an event bus event to a DTO
+func liveEvent(event eventbus.Event) *dto.LiveEventDTO {
+	live := &dto.LiveEventDTO{
+		Type:      event.Type(),
+		Timestamp: event.Timestamp().Format(time.RFC3339),
+	}
+	if stored, ok := event.(interface{ StoredID() string }); ok {
+		live.ID = stored.StoredID()
+	}
+
+	switch e := event.(type) {
+	case *eventbus.ConnectorEvent:
+		live.Connector = e.ConnectorName
+		live.UserID = e.UserID
+		live.Content = e.Message
+		live.Error = errorString(e.Error)
+	case *eventbus.RouterEvent:
+		live.Connector = e.Source
+		live.UserID = e.UserID
+		live.SessionID = e.SessionID
+		live.MessageID = e.MessageID
+		live.Content = e.Content
+		live.Error = errorString(e.Error)
+	case *eventbus.ScheduleEvent:
+		live.ScheduleID = e.ScheduleID
+		live.Skill = e.SkillName
+		live.SessionID = e.SessionID
+		live.Content = e.Output
+		live.Error = errorString(e.Error)
+		live.DurationMs = e.Duration.Milliseconds()
+	case *eventbus.TaskEvent:
+		live.SessionID = e.SessionID
+		live.Skill = e.SkillName
+		live.Content = e.Output
+		live.Error = e.Error
+		live.DurationMs = e.Duration.Milliseconds()
+	case *eventbus.SkillEvent:
+		live.Skill = e.SkillName
+		live.Content = e.Output
+		live.Error = errorString(e.Error)
+		live.DurationMs = e.Duration.Milliseconds()
+	case *eventbus.SessionEvent:
+		live.SessionID = e.SessionID
+		live.UserID = e.UserID
+	case *eventbus.UserEvent:
+		live.UserID = e.UserID
+	case *eventbus.FeedbackEvent:
+		live.UserID = e.UserID
+		live.SessionID = e.SessionID
+		live.MessageID = e.MessageID
+		live.Rating = e.Rating
+		live.Content = e.Comment
+	case *eventbus.LLMPublishedEvent:
+		live.Error = errorString(e.Error)
+		live.DurationMs = e.Duration.Milliseconds()
+	}
+	return live
+}
+
+// errorString returns the message of an error, or "" for nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// RegisterEventsRoutes registers the live events, event history and dead-letter routes
+func RegisterEventsRoutes(r *Router, handler *EventsHandler) {
+	events := r.Group("/events")
+	admin := r.Group("/admin/events")
+	deadLetters := admin.Group("/dead-letters")
+	events.HandleFunc("GET /", handler.Stream).Describe(RouteDoc{
+		Summary:     "Stream live events",
+		Description: "Server-sent events for received and routed messages, schedule runs and reply feedback. Each event is named after its type and carries a LiveEventDTO as JSON data. Responds with 503 when the event bus is disabled.",
+		Tag:         "events",
+		Produces:    []string{"text/event-stream"},
+	})
+	admin.HandleFunc("GET /", handler.History).Describe(RouteDoc{
+		Summary:     "List stored events",
+		Description: "Events from the event store in publication order. Responds with 503 unless eventbus.persist is enabled.",
+		Tag:         "admin",
+		Query: []QueryParam{
+			{Name: "since", Description: "Only events that occurred at or after this time (RFC3339)"},
+			{Name: "until", Description: "Only events that occurred before this time (RFC3339)"},
+			{Name: "type", Description: "Comma-separated event types, e.g. router.message,schedule.failed"},
+			{Name: "limit", Description: "Maximum number of events to return (default 100, max 1000)"},
+		},
+		Response: dto.LiveEventsResponse{},
+	})
+	deadLetters.HandleFunc("GET /", handler.DeadLetters).Describe(RouteDoc{
+		Summary:     "List dead letters",
+		Description: "Events whose handlers still failed after all retries, newest first. Responds with 503 when the event bus is disabled.",
+		Tag:         "admin",
+		Query: []QueryParam{
+			{Name: "subscription", Description: "Only dead letters of the subscription with this name, e.g. webhooks"},
+			{Name: "limit", Description: "Maximum number of dead letters to return (default 50, max 500)"},
+			{Name: "offset", Description: "Number of dead letters to skip"},
+		},
+		Response: dto.DeadLettersResponse{},
+	})
+	deadLetters.HandleFunc("POST /{id}/redeliver", handler.RedeliverDeadLetter).Describe(RouteDoc{
+		Summary:     "Redeliver a dead letter",
+		Description: "Passes the event once more to the handler of its subscription and deletes the dead letter on success. Responds with 409 when no subscription with the recorded name is active or the handler fails again.",
+		Tag:         "admin",
+		Response:    dto.DeadLetterResponse{},
+	})
+	deadLetters.HandleFunc("DELETE /{id}", handler.DeleteDeadLetter).Describe(RouteDoc{
+		Summary:  "Delete a dead letter",
+		Tag:      "admin",
+		Response: dto.DeadLetterResponse{},
+	})
+}
@@ -0,0 +1,259 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/eventbus"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func TestEventsHandler_Stream(t *testing.T) {
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{BatchSize: 1, FlushInterval: 10 * time.Millisecond, Logger: logging.NewNoopLogger()})
+	require.NoError(t, bus.Start())
+	defer func() { _ = bus.Stop() }()
+
+	router := NewRouter()
+	RegisterEventsRoutes(router, NewEventsHandler(bus, logging.NewNoopLogger()))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool {
+		return bus.GetSubscriptionCount(eventbus.EventRouterMessage) == 1
+	}, time.Second, 5*time.Millisecond)
+	bus.Publish(eventbus.NewRouterEvent(eventbus.EventRouterMessage, "msg-1", "session-1", "user-1", "hello", "telegram", nil))
+
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: router.message\n", event)
+
+	data, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	var live dto.LiveEventDTO
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &live))
+	assert.Equal(t, "router.message", live.Type)
+	assert.Equal(t, "telegram", live.Connector)
+	assert.Equal(t, "session-1", live.SessionID)
+	assert.Equal(t, "hello", live.Content)
+}
+
+func TestEventsHandler_EventBusDisabled(t *testing.T) {
+	router := NewRouter()
+	RegisterEventsRoutes(router, NewEventsHandler(nil, logging.NewNoopLogger()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestLiveEvent_ScheduleFailed(t *testing.T) {
+	event := eventbus.NewScheduleEvent(eventbus.EventScheduleFailed, "schedule-1", "backup", "session-1", "", errors.New("timeout"), 1500*time.Millisecond)
+
+	live := liveEvent(event)
+
+	assert.Equal(t, "schedule.failed", live.Type)
+	assert.Equal(t, "schedule-1", live.ScheduleID)
+	assert.Equal(t, "backup", live.Skill)
+	assert.Equal(t, "timeout", live.Error)
+	assert.Equal(t, int64(1500), live.DurationMs)
+}
+
+func TestLiveEvent_Feedback(t *testing.T) {
+	event := eventbus.NewFeedbackEvent(eventbus.EventFeedbackReceived, "msg-1", "session-1", "user-1", "down", "Outdated", "", "")
+
+	live := liveEvent(event)
+
+	assert.Equal(t, "feedback.received", live.Type)
+	assert.Equal(t, "msg-1", live.MessageID)
+	assert.Equal(t, "user-1", live.UserID)
+	assert.Equal(t, "down", live.Rating)
+	assert.Equal(t, "Outdated", live.Content)
+}
+
+// memoryEventStore is an in-memory eventbus.EventStore
+type memoryEventStore struct {
+	events []eventbus.Event
+}
+
+func (s *memoryEventStore) Append(ctx context.Context, events []eventbus.Event) error {
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *memoryEventStore) Replay(ctx context.Context, from, to time.Time, types []string, handler eventbus.EventHandler) error {
+	for _, event := range s.events {
+		if !from.IsZero() && event.Timestamp().Before(from) || !to.IsZero() && !event.Timestamp().Before(to) {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, event.Type()) {
+			continue
+		}
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryEventStore) Get(ctx context.Context, id string) (eventbus.Event, error) {
+	for _, event := range s.events {
+		if stored, ok := event.(interface{ StoredID() string }); ok && stored.StoredID() == id {
+			return event, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func TestEventsHandler_History(t *testing.T) {
+	store := &memoryEventStore{}
+	require.NoError(t, store.Append(context.Background(), []eventbus.Event{
+		eventbus.NewRouterEvent(eventbus.EventRouterMessage, "msg-1", "session-1", "user-1", "hello", "telegram", nil),
+		eventbus.NewTaskEvent(eventbus.EventTaskFailed, "task-1", "session-1", "echo", "failed", "", "", "boom"),
+		eventbus.NewRouterEvent(eventbus.EventRouterMessage, "msg-2", "session-1", "user-1", "again", "telegram", nil),
+	}))
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{BatchSize: 1, FlushInterval: time.Second, Logger: logging.NewNoopLogger(), Store: store})
+
+	router := NewRouter()
+	RegisterEventsRoutes(router, NewEventsHandler(bus, logging.NewNoopLogger()))
+
+	get := func(target string) (*httptest.ResponseRecorder, dto.LiveEventsResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		var resp dto.LiveEventsResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	w, resp := get("/admin/events")
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, resp.Events, 3)
+	assert.Equal(t, "msg-1", resp.Events[0].MessageID)
+	assert.Equal(t, "boom", resp.Events[1].Error)
+	assert.Equal(t, "echo", resp.Events[1].Skill)
+
+	_, resp = get("/admin/events?type=task.failed,schedule.failed")
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, "task.failed", resp.Events[0].Type)
+
+	_, resp = get("/admin/events?limit=2")
+	require.Len(t, resp.Events, 2)
+	assert.Equal(t, "msg-1", resp.Events[0].MessageID)
+
+	_, resp = get("/admin/events?since=" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	assert.Empty(t, resp.Events)
+
+	w, _ = get("/admin/events?since=yesterday")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEventsHandler_HistoryWithoutStore(t *testing.T) {
+	bus := eventbus.NewEventBus(nil)
+	router := NewRouter()
+	RegisterEventsRoutes(router, NewEventsHandler(bus, logging.NewNoopLogger()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/events", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestEventsHandler_DeadLetters(t *testing.T) {
+	bus := eventbus.NewEventBus(&eventbus.EventBusConfig{
+		BatchSize:     1,
+		FlushInterval: 10 * time.Millisecond,
+		Logger:        logging.NewNoopLogger(),
+		Retry:         &eventbus.RetryPolicy{MaxAttempts: 1},
+	})
+	require.NoError(t, bus.Start())
+	defer func() { _ = bus.Stop() }()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	bus.SubscribeWithOptions([]string{eventbus.EventTaskFailed}, eventbus.SubscribeOptions{Name: "audit"}, func(context.Context, eventbus.Event) error {
+		if failing.Load() {
+			return errors.New("audit log unavailable")
+		}
+		return nil
+	})
+	bus.Publish(eventbus.NewTaskEvent(eventbus.EventTaskFailed, "task-1", "session-1", "echo", "failed", "", "", "boom"))
+	bus.Publish(eventbus.NewTaskEvent(eventbus.EventTaskFailed, "task-2", "session-1", "echo", "failed", "", "", "boom"))
+
+	router := NewRouter()
+	RegisterEventsRoutes(router, NewEventsHandler(bus, logging.NewNoopLogger()))
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+	list := func(target string) dto.DeadLettersResponse {
+		var resp dto.DeadLettersResponse
+		_ = json.Unmarshal(serve("GET", target).Body.Bytes(), &resp)
+		return resp
+	}
+
+	require.Eventually(t, func() bool {
+		return len(list("/admin/events/dead-letters").DeadLetters) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	resp := list("/admin/events/dead-letters?subscription=audit&limit=1")
+	require.Len(t, resp.DeadLetters, 1)
+	letter := resp.DeadLetters[0]
+	assert.Equal(t, "audit", letter.Subscription)
+	assert.Equal(t, "task.failed", letter.EventType)
+	assert.Equal(t, 1, letter.Attempts)
+	assert.Equal(t, "audit log unavailable", letter.Error)
+	assert.Empty(t, list("/admin/events/dead-letters?subscription=webhooks").DeadLetters)
+	assert.Equal(t, http.StatusBadRequest, serve("GET", "/admin/events/dead-letters?limit=-1").Code)
+
+	w := serve("POST", "/admin/events/dead-letters/"+letter.ID+"/redeliver")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "audit log unavailable")
+
+	failing.Store(false)
+	w = serve("POST", "/admin/events/dead-letters/"+letter.ID+"/redeliver")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNotFound, serve("POST", "/admin/events/dead-letters/"+letter.ID+"/redeliver").Code)
+
+	remaining := list("/admin/events/dead-letters").DeadLetters
+	require.Len(t, remaining, 1)
+	assert.Equal(t, http.StatusOK, serve("DELETE", "/admin/events/dead-letters/"+remaining[0].ID).Code)
+	assert.Equal(t, http.StatusNotFound, serve("DELETE", "/admin/events/dead-letters/"+remaining[0].ID).Code)
+}
+
+func TestEventsHandler_DeadLettersWithoutEventBus(t *testing.T) {
+	router := NewRouter()
+	RegisterEventsRoutes(router, NewEventsHandler(nil, logging.NewNoopLogger()))
+
+	for _, route := range []struct{ method, target string }{
+		{"GET", "/admin/events/dead-letters"},
+		{"POST", "/admin/events/dead-letters/letter-1/redeliver"},
+		{"DELETE", "/admin/events/dead-letters/letter-1"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(route.method, route.target, nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, route.target)
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// FeedbackHandler handles the ratings of assistant replies
+type FeedbackHandler struct {
+	feedbackUseCase *usecase.FeedbackUseCase
+	logger          logging.Logger
+}
+
+// NewFeedbackHandler creates a new FeedbackHandler
+func NewFeedbackHandler(feedbackUseCase *usecase.FeedbackUseCase, logger logging.Logger) *FeedbackHandler {
+	return &FeedbackHandler{
+		feedbackUseCase: feedbackUseCase,
+		logger:          logger,
+	}
+}
+
+// RecordFeedback handles POST /messages/{id}/feedback
+func (h *FeedbackHandler) RecordFeedback(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	messageID := r.PathValue("id")
+	if messageID == "" {
+		return WriteError(w, http.StatusBadRequest, "message id is required")
+	}
+
+	var req dto.RecordFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode feedback request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.feedbackUseCase.RecordFeedback(ctx, messageID, "", req)
+	if err != nil {
+		h.logger.Error("failed to record feedback", "error", err, "message_id", messageID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetAnalytics handles GET /analytics/feedback
+func (h *FeedbackHandler) GetAnalytics(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.feedbackUseCase.GetFeedbackAnalytics(ctx, analyticsRequest(r))
+	if err != nil {
+		h.logger.Error("failed to get feedback analytics", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterFeedbackRoutes registers the reply feedback routes
+func RegisterFeedbackRoutes(r *Router, handler *FeedbackHandler) {
+	messages := r.Group("/messages")
+	analytics := r.Group("/analytics")
+	messages.HandleFunc("POST /{id}/feedback", handler.RecordFeedback).Describe(RouteDoc{
+		Summary:     "Rate an assistant reply",
+		Description: "Saves a thumbs up or down rating with an optional comment, replacing an earlier rating of the message, and publishes a feedback.received event with the rated reply and its prompt.",
+		Tag:         "messages",
+		Request:     dto.RecordFeedbackRequest{},
+		Response:    dto.FeedbackResponse{},
+	})
+	analytics.HandleFunc("GET /feedback", handler.GetAnalytics).Describe(RouteDoc{
+		Summary:     "Get daily reply feedback",
+		Description: "Thumbs up and down ratings for every day of the range, oldest first, with the totals of the range. A rating counts on the day it was last changed. Ranges are limited to 366 days.",
+		Tag:         "analytics",
+		Response:    dto.FeedbackAnalyticsResponse{},
+		Query:       analyticsQuery,
+	})
+}
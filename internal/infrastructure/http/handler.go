@@ -18,7 +18,7 @@ type DefaultErrorHandler struct{}
 
 // HandleError implements ErrorHandler interface
 func (h *DefaultErrorHandler) HandleError(w http.ResponseWriter, r *http.Request, err error) {
-	WriteError(w, http.StatusInternalServerError, err.Error())
+	_ = writeUseCaseError(w, err, err.Error())
 }
 
 // HandlerAdapter adapts a Handler to an http.Handler
@@ -41,9 +41,15 @@ func NewHandlerAdapter(handler Handler, ctx context.Context, errHandler ErrorHan
 	}
 }
 
-// ServeHTTP implements http.Handler interface
+// ServeHTTP implements http.Handler interface.
+// Handlers get the request context unless the adapter was created with a context.
 func (ha *HandlerAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	err := ha.handler(ha.ctx, w, r)
+	ctx := ha.ctx
+	if ctx == nil {
+		ctx = r.Context()
+	}
+
+	err := ha.handler(ctx, w, r)
 	if err != nil {
 		ha.errHandler.HandleError(w, r, err)
 	}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// HandoffHandler handles operator takeover HTTP requests
+type HandoffHandler struct {
+	handoffUseCase *usecase.HandoffUseCase
+	logger         logging.Logger
+}
+
+// NewHandoffHandler creates a new HandoffHandler
+func NewHandoffHandler(handoffUseCase *usecase.HandoffUseCase, logger logging.Logger) *HandoffHandler {
+	return &HandoffHandler{
+		handoffUseCase: handoffUseCase,
+		logger:         logger,
+	}
+}
+
+// GetHandoff handles GET /admin/sessions/{id}/handoff
+func (h *HandoffHandler) GetHandoff(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+
+	resp, err := h.handoffUseCase.GetHandoff(ctx, sessionID)
+	if err != nil {
+		h.logger.Error("failed to get session handoff", "error", err, "session_id", sessionID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// StartHandoff handles PUT /admin/sessions/{id}/handoff
+func (h *HandoffHandler) StartHandoff(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+
+	var req dto.StartHandoffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode handoff request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.handoffUseCase.StartHandoff(ctx, sessionID, req)
+	if err != nil {
+		h.logger.Error("failed to start session handoff", "error", err, "session_id", sessionID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// EndHandoff handles DELETE /admin/sessions/{id}/handoff
+func (h *HandoffHandler) EndHandoff(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+
+	resp, err := h.handoffUseCase.EndHandoff(ctx, sessionID)
+	if err != nil {
+		h.logger.Error("failed to end session handoff", "error", err, "session_id", sessionID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusNotFound, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// Reply handles POST /admin/sessions/{id}/handoff/messages
+func (h *HandoffHandler) Reply(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+
+	var req dto.HandoffReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode handoff reply", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.handoffUseCase.Reply(ctx, sessionID, req)
+	if err != nil {
+		h.logger.Error("failed to send operator reply", "error", err, "session_id", sessionID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusCreated, resp)
+}
+
+// RegisterHandoffRoutes registers the operator takeover routes
+func RegisterHandoffRoutes(r *Router, handler *HandoffHandler) {
+	handoff := r.Group("/admin/sessions/{id}/handoff")
+	handoff.HandleFunc("GET /", handler.GetHandoff).Describe(RouteDoc{
+		Summary:  "Get the operator handoff of a session",
+		Tag:      "admin",
+		Response: dto.HandoffResponse{},
+	})
+	handoff.HandleFunc("PUT /", handler.StartHandoff).Describe(RouteDoc{
+		Summary:     "Hand a session over to an operator",
+		Description: "User messages of the session are no longer answered by the LLM. In forward mode they are published as router.handoff events, streamed by GET /events; in pause mode they are only saved.",
+		Tag:         "admin",
+		Request:     dto.StartHandoffRequest{},
+		Response:    dto.HandoffResponse{},
+	})
+	handoff.HandleFunc("DELETE /", handler.EndHandoff).Describe(RouteDoc{
+		Summary:  "Return a session to the LLM",
+		Tag:      "admin",
+		Response: dto.HandoffResponse{},
+	})
+	handoff.HandleFunc("POST /messages", handler.Reply).Describe(RouteDoc{
+		Summary:     "Send an operator reply",
+		Description: "Sends the reply to the user through the connector of the session and saves it as an assistant message. Responds with 409 when the session is not handed off.",
+		Tag:         "admin",
+		Status:      http.StatusCreated,
+		Request:     dto.HandoffReplyRequest{},
+		Response:    dto.HandoffResponse{},
+	})
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// healthCheckTimeout bounds how long a single health check may take
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck probes one dependency of the server
+type HealthCheck func(ctx context.Context) *dto.HealthCheckDTO
+
+// HealthHandler handles the health endpoint
+type HealthHandler struct {
+	checks map[string]HealthCheck
+	logger logging.Logger
+}
+
+// NewHealthHandler creates a new HealthHandler without checks
+func NewHealthHandler(logger logging.Logger) *HealthHandler {
+	return &HealthHandler{
+		checks: make(map[string]HealthCheck),
+		logger: logger,
+	}
+}
+
+// AddCheck registers a named health check
+func (h *HealthHandler) AddCheck(name string, check HealthCheck) {
+	h.checks[name] = check
+}
+
+// Healthz handles GET /healthz.
+// It responds with 200 when all checks pass and 503 otherwise.
+func (h *HealthHandler) Healthz(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := &dto.HealthResponse{
+		Status: dto.HealthStatusOK,
+		Checks: make(map[string]*dto.HealthCheckDTO, len(names)),
+	}
+	for _, name := range names {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		result := h.checks[name](checkCtx)
+		cancel()
+
+		if result.Status != dto.HealthStatusOK {
+			resp.Status = dto.HealthStatusUnavailable
+			h.logger.Warn("health check failed", "check", name, "error", result.Error)
+		}
+		resp.Checks[name] = result
+	}
+
+	status := http.StatusOK
+	if resp.Status != dto.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	return WriteJSON(w, status, resp)
+}
+
+// RegisterHealthRoutes registers the health routes
+func RegisterHealthRoutes(r *Router, handler *HealthHandler) {
+	r.HandleFunc("GET /healthz", handler.Healthz).Describe(RouteDoc{
+		Summary:     "Check server health",
+		Description: "Responds with 503 when a health check fails.",
+		Tag:         "health",
+		Response:    dto.HealthResponse{},
+		Public:      true,
+	})
+}
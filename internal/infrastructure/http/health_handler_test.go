@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler_Healthz(t *testing.T) {
+	ok := func(ctx context.Context) *dto.HealthCheckDTO {
+		return &dto.HealthCheckDTO{Status: dto.HealthStatusOK}
+	}
+	down := func(ctx context.Context) *dto.HealthCheckDTO {
+		return &dto.HealthCheckDTO{Status: dto.HealthStatusUnavailable, Error: "connection refused"}
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]HealthCheck
+		wantCode   int
+		wantStatus string
+	}{
+		{
+			name:       "no checks",
+			wantCode:   http.StatusOK,
+			wantStatus: dto.HealthStatusOK,
+		},
+		{
+			name:       "all checks pass",
+			checks:     map[string]HealthCheck{"database": ok},
+			wantCode:   http.StatusOK,
+			wantStatus: dto.HealthStatusOK,
+		},
+		{
+			name:       "failing check",
+			checks:     map[string]HealthCheck{"database": down, "cache": ok},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: dto.HealthStatusUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(logging.NewNoopLogger())
+			for name, check := range tt.checks {
+				handler.AddCheck(name, check)
+			}
+
+			router := NewRouter()
+			RegisterHealthRoutes(router, handler)
+
+			req := httptest.NewRequest("GET", "/healthz", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			var resp dto.HealthResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			assert.Len(t, resp.Checks, len(tt.checks))
+		})
+	}
+}
+
+func TestHealthHandler_CheckTimeout(t *testing.T) {
+	handler := NewHealthHandler(logging.NewNoopLogger())
+	handler.AddCheck("database", func(ctx context.Context) *dto.HealthCheckDTO {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok, "check context should have a deadline")
+		assert.False(t, deadline.IsZero())
+		return &dto.HealthCheckDTO{Status: dto.HealthStatusOK}
+	})
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	require.NoError(t, handler.Healthz(context.Background(), w, req))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,299 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header with the client's idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set to "true" on responses replayed for a retried request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// MaxIdempotencyKeyLength limits the length of an idempotency key
+	MaxIdempotencyKeyLength = 255
+
+	// idempotencyPendingTTL is how long the key of a request being handled is reserved, so
+	// that the key of an instance that stopped while handling it can be used again
+	idempotencyPendingTTL = 10 * time.Minute
+)
+
+// IdempotencyMetrics holds all metrics for the Idempotency middleware
+type IdempotencyMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	Stored      *metrics.Counter
+	Replayed    *metrics.Counter
+	Conflicts   *metrics.Counter
+	Mismatches  *metrics.Counter
+	StoreErrors *metrics.Counter
+}
+
+// NewIdempotencyMetrics creates a new IdempotencyMetrics instance
+func NewIdempotencyMetrics() *IdempotencyMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &IdempotencyMetrics{
+		registry: registry,
+
+		Stored:      registry.GetCounter("http_idempotency_stored_total"),
+		Replayed:    registry.GetCounter("http_idempotency_replayed_total"),
+		Conflicts:   registry.GetCounter("http_idempotency_conflicts_total"),
+		Mismatches:  registry.GetCounter("http_idempotency_mismatches_total"),
+		StoreErrors: registry.GetCounter("http_idempotency_store_errors_total"),
+	}
+}
+
+// Registry returns the registry holding the Idempotency metrics
+func (m *IdempotencyMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Idempotency replays the response of a request retried with the same Idempotency-Key header
+// instead of handling it again, so that the retries of a client do not send a message or
+// execute a skill twice. It applies to the routes documented as Idempotent. Keys are scoped
+// to the credential of the request, or the client IP without one, so it must run after the
+// Authenticator. The key of a request is reserved in the repository before it is handled, so
+// that a retry arriving meanwhile is rejected also when another instance receives it.
+type Idempotency struct {
+	enabled bool
+	ttl     time.Duration
+	repo    repository.IdempotencyRepository
+	metrics *IdempotencyMetrics
+	logger  logging.Logger
+	now     func() time.Time
+}
+
+// NewIdempotency creates a new Idempotency middleware storing responses in repo
+func NewIdempotency(cfg config.IdempotencyConfig, repo repository.IdempotencyRepository, logger logging.Logger) *Idempotency {
+	return &Idempotency{
+		enabled: cfg.Enabled && repo != nil,
+		ttl:     time.Duration(cfg.TTLHours) * time.Hour,
+		repo:    repo,
+		metrics: NewIdempotencyMetrics(),
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Metrics returns the idempotency metrics
+func (i *Idempotency) Metrics() *IdempotencyMetrics {
+	return i.metrics
+}
+
+// PurgeExpired deletes the stored responses whose keys have expired
+func (i *Idempotency) PurgeExpired(ctx context.Context) error {
+	if i.repo == nil {
+		return nil
+	}
+	n, err := i.repo.DeleteExpired(ctx, i.now())
+	if err != nil {
+		return fmt.Errorf("failed to purge expired idempotency keys: %w", err)
+	}
+	if n > 0 {
+		i.logger.WithContext(ctx).Debug("purged expired idempotency keys", "count", n)
+	}
+	return nil
+}
+
+// Middleware returns the middleware handling the Idempotency-Key header of the idempotent routes.
+// A retry with the same key and request gets the stored response with the Idempotent-Replayed
+// header; the same key with another request is rejected with 422, and a retry while the first
+// request is still being handled, by this or another instance, with 409. Server errors are not
+// stored, so that they can be retried. Requests without the header are not affected.
+func (i *Idempotency) Middleware(routes []*Route) Middleware {
+	idempotent := http.NewServeMux()
+	for _, route := range routes {
+		if route.Doc.Idempotent {
+			idempotent.Handle(route.Method+" "+route.Path, http.NotFoundHandler())
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if !i.enabled || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, pattern := idempotent.Handler(r); pattern == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > MaxIdempotencyKeyLength {
+				_ = WriteError(w, http.StatusBadRequest, fmt.Sprintf("%s header must be at most %d characters", IdempotencyKeyHeader, MaxIdempotencyKeyLength))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				_ = WriteError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			i.serve(w, r, next, i.scopedKey(r, key), requestHash(r, body))
+		})
+	}
+}
+
+// serve replays the stored response for the key, or reserves the key, handles the request and
+// stores its response
+func (i *Idempotency) serve(w http.ResponseWriter, r *http.Request, next http.Handler, key, hash string) {
+	ctx := r.Context()
+	logger := i.logger.WithContext(ctx)
+
+	record, err := i.repo.Find(ctx, key)
+	switch {
+	case err == nil:
+		i.replay(w, record, hash)
+		return
+	case !errors.Is(err, repository.ErrNotFound):
+		// The request is handled without idempotency rather than failed
+		i.metrics.StoreErrors.Inc()
+		logger.Error("failed to find idempotency key", "method", r.Method, "path", r.URL.Path, "error", err)
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	now := i.now()
+	reserved, err := i.repo.Reserve(ctx, &entity.IdempotencyRecord{
+		Key:         key,
+		RequestHash: hash,
+		ExpiresAt:   now.Add(min(idempotencyPendingTTL, i.ttl)),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		i.metrics.StoreErrors.Inc()
+		logger.Error("failed to reserve idempotency key", "method", r.Method, "path", r.URL.Path, "error", err)
+		next.ServeHTTP(w, r)
+		return
+	}
+	if !reserved {
+		// Another request with the key was received since it was looked up
+		i.metrics.Conflicts.Inc()
+		_ = WriteError(w, http.StatusConflict, "a request with this idempotency key is in progress")
+		return
+	}
+
+	saved := false
+	defer func() {
+		if !saved {
+			i.release(ctx, r, key)
+		}
+	}()
+
+	rec := &idempotencyResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	next.ServeHTTP(rec, r)
+	if rec.statusCode >= http.StatusInternalServerError {
+		return
+	}
+
+	now = i.now()
+	saved, err = i.repo.Save(context.WithoutCancel(ctx), &entity.IdempotencyRecord{
+		Key:         key,
+		RequestHash: hash,
+		StatusCode:  rec.statusCode,
+		ContentType: rec.Header().Get("Content-Type"),
+		Body:        rec.body.Bytes(),
+		ExpiresAt:   now.Add(i.ttl),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		i.metrics.StoreErrors.Inc()
+		logger.Error("failed to save idempotency key", "method", r.Method, "path", r.URL.Path, "error", err)
+		return
+	}
+	if saved {
+		i.metrics.Stored.Inc()
+	}
+}
+
+// replay writes the stored response of a record, or rejects the request if the record belongs
+// to another request or is still pending
+func (i *Idempotency) replay(w http.ResponseWriter, record *entity.IdempotencyRecord, hash string) {
+	if record.RequestHash != hash {
+		i.metrics.Mismatches.Inc()
+		_ = WriteErrorCode(w, http.StatusUnprocessableEntity, ErrCodeIdempotencyKeyReused, "idempotency key was used with another request", nil)
+		return
+	}
+	if record.IsPending() {
+		i.metrics.Conflicts.Inc()
+		_ = WriteError(w, http.StatusConflict, "a request with this idempotency key is in progress")
+		return
+	}
+
+	i.metrics.Replayed.Inc()
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// release removes the reservation of a key whose response was not stored, so that the request
+// can be retried
+func (i *Idempotency) release(ctx context.Context, r *http.Request, key string) {
+	if err := i.repo.Release(context.WithoutCancel(ctx), key); err != nil {
+		i.metrics.StoreErrors.Inc()
+		i.logger.WithContext(ctx).Error("failed to release idempotency key", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+}
+
+// scopedKey returns the stored key of an idempotency key, hashed with the credential of the
+// request so that clients cannot replay each other's responses
+func (i *Idempotency) scopedKey(r *http.Request, key string) string {
+	scope := credentialFromContext(r.Context())
+	if scope == "" {
+		scope = "ip:" + clientIP(r)
+	}
+	sum := sha256.Sum256([]byte(scope + "\n" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// requestHash returns the hash of the method, URL and body of a request
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyResponseWriter wraps http.ResponseWriter to capture the response to store
+type idempotencyResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (iw *idempotencyResponseWriter) WriteHeader(code int) {
+	iw.statusCode = code
+	iw.ResponseWriter.WriteHeader(code)
+}
+
+func (iw *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	iw.body.Write(b)
+	return iw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, so that http.ResponseController
+// can reach optional interfaces such as http.Flusher
+func (iw *idempotencyResponseWriter) Unwrap() http.ResponseWriter {
+	return iw.ResponseWriter
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// memoryIdempotencyRepository is an in-memory repository.IdempotencyRepository
+type memoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*entity.IdempotencyRecord
+	now     func() time.Time
+	err     error
+}
+
+func (m *memoryIdempotencyRepository) Find(ctx context.Context, key string) (*entity.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	record, ok := m.records[key]
+	if !ok || record.IsExpired(m.now()) {
+		return nil, fmt.Errorf("idempotency key %w", repository.ErrNotFound)
+	}
+	return record, nil
+}
+
+func (m *memoryIdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return false, m.err
+	}
+	if existing, ok := m.records[record.Key]; ok && !existing.IsExpired(record.CreatedAt) {
+		return false, nil
+	}
+	m.records[record.Key] = record
+	return true, nil
+}
+
+func (m *memoryIdempotencyRepository) Save(ctx context.Context, record *entity.IdempotencyRecord) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.records[record.Key]
+	if ok && !existing.IsExpired(record.CreatedAt) && !(existing.IsPending() && existing.RequestHash == record.RequestHash) {
+		return false, nil
+	}
+	m.records[record.Key] = record
+	return true, nil
+}
+
+func (m *memoryIdempotencyRepository) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[key]; ok && record.IsPending() {
+		delete(m.records, key)
+	}
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for key, record := range m.records {
+		if record.IsExpired(now) {
+			delete(m.records, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestIdempotency_Middleware(t *testing.T) {
+	now := time.Now()
+	repo := &memoryIdempotencyRepository{records: make(map[string]*entity.IdempotencyRecord), now: func() time.Time { return now }}
+	idempotency := NewIdempotency(config.IdempotencyConfig{Enabled: true, TTLHours: 1}, repo, logging.NewNoopLogger())
+	idempotency.now = func() time.Time { return now }
+
+	router := NewRouter()
+	var calls int
+	status := http.StatusCreated
+	router.HandleFunc("POST /sessions", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		return WriteJSON(w, status, map[string]any{"call": calls, "body": string(body)})
+	}).Describe(RouteDoc{Idempotent: true})
+	router.HandleFunc("POST /users", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+		return nil
+	})
+	handler := newTestAuthenticator().Middleware(idempotency.Middleware(router.Routes())(router))
+
+	send := func(path, key, apiKey, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		req.Header.Set(APIKeyHeader, apiKey)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("/sessions", "key-1", "static-admin", `{"user_id":"u1"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+	// A retry replays the first response without calling the handler
+	retry := send("/sessions", "key-1", "static-admin", `{"user_id":"u1"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// The same key with another body is rejected
+	mismatch := send("/sessions", "key-1", "static-admin", `{"user_id":"u2"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, mismatch.Code)
+	assert.Contains(t, mismatch.Body.String(), string(ErrCodeIdempotencyKeyReused))
+
+	// Keys are scoped to the credential
+	assert.Equal(t, http.StatusCreated, send("/sessions", "key-1", "support:nfx_db", `{"user_id":"u1"}`).Code)
+	assert.Equal(t, 2, calls)
+
+	// Requests without a key and routes that are not idempotent are handled every time
+	send("/sessions", "", "static-admin", `{"user_id":"u1"}`)
+	send("/users", "key-1", "static-admin", "")
+	send("/users", "key-1", "static-admin", "")
+	assert.Equal(t, 5, calls)
+
+	assert.Equal(t, http.StatusBadRequest, send("/sessions", strings.Repeat("k", MaxIdempotencyKeyLength+1), "static-admin", "").Code)
+
+	// Server errors are not stored, so the retry is handled
+	status = http.StatusInternalServerError
+	send("/sessions", "key-2", "static-admin", "")
+	status = http.StatusCreated
+	assert.Equal(t, http.StatusCreated, send("/sessions", "key-2", "static-admin", "").Code)
+	assert.Equal(t, 7, calls)
+
+	// Expired keys handle the request again and are purged
+	now = now.Add(2 * time.Hour)
+	assert.Empty(t, send("/sessions", "key-2", "static-admin", "").Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 8, calls)
+	assert.NoError(t, idempotency.PurgeExpired(context.Background()))
+	assert.Len(t, repo.records, 1)
+
+	// A request is handled when the store fails
+	repo.err = errors.New("database is locked")
+	assert.Equal(t, http.StatusCreated, send("/sessions", "key-2", "static-admin", "").Code)
+	assert.Equal(t, 9, calls)
+	assert.Equal(t, int64(1), idempotency.Metrics().Replayed.Get())
+}
+
+func TestIdempotency_InFlight(t *testing.T) {
+	repo := &memoryIdempotencyRepository{records: make(map[string]*entity.IdempotencyRecord), now: time.Now}
+	idempotency := NewIdempotency(config.DefaultIdempotencyConfig(), repo, logging.NewNoopLogger())
+	// Another instance sharing the repository
+	other := NewIdempotency(config.DefaultIdempotencyConfig(), repo, logging.NewNoopLogger())
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	router := NewRouter()
+	router.HandleFunc("POST /chat/send", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		close(started)
+		<-finish
+		return WriteJSON(w, http.StatusOK, map[string]any{"success": true})
+	}).Describe(RouteDoc{Idempotent: true})
+	handler := idempotency.Middleware(router.Routes())(router)
+	otherHandler := other.Middleware(router.Routes())(router)
+
+	send := func(handler http.Handler, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/chat/send", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send(handler, `{}`) }()
+	<-started
+
+	// A retry while the first request is handled is rejected, also by another instance
+	assert.Equal(t, http.StatusConflict, send(handler, `{}`).Code)
+	assert.Equal(t, http.StatusConflict, send(otherHandler, `{}`).Code)
+	assert.Equal(t, int64(1), idempotency.Metrics().Conflicts.Get())
+	assert.Equal(t, int64(1), other.Metrics().Conflicts.Get())
+	assert.Equal(t, http.StatusUnprocessableEntity, send(otherHandler, `{"other":true}`).Code)
+
+	close(finish)
+	assert.Equal(t, http.StatusOK, (<-done).Code)
+	assert.Equal(t, "true", send(otherHandler, `{}`).Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_ReleasesFailedRequest(t *testing.T) {
+	repo := &memoryIdempotencyRepository{records: make(map[string]*entity.IdempotencyRecord), now: time.Now}
+	idempotency := NewIdempotency(config.DefaultIdempotencyConfig(), repo, logging.NewNoopLogger())
+
+	router := NewRouter()
+	router.HandleFunc("POST /chat/send", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		panic("handler failed")
+	}).Describe(RouteDoc{Idempotent: true})
+	handler := idempotency.Middleware(router.Routes())(router)
+
+	req := httptest.NewRequest("POST", "/chat/send", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	assert.Panics(t, func() { handler.ServeHTTP(httptest.NewRecorder(), req) })
+
+	// The key of a request that did not finish is not left reserved
+	assert.Empty(t, repo.records)
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// Headers of the signature schemes of inbound webhooks
+const (
+	TelegramSecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	SlackSignatureHeader      = "X-Slack-Signature"
+	SlackTimestampHeader      = "X-Slack-Request-Timestamp"
+	HubSignatureHeader        = "X-Hub-Signature-256"
+)
+
+// MaxInboundWebhookBody limits the size of a verified webhook request body
+const MaxInboundWebhookBody = 1 << 20
+
+var (
+	// ErrWebhookSignature is returned for a webhook request without a valid signature
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+
+	// ErrWebhookReplay is returned for a signed webhook request outside the replay window
+	ErrWebhookReplay = errors.New("webhook request timestamp is outside the replay window")
+)
+
+// WebhookVerifier verifies that an inbound webhook request was sent by the platform of a connector
+type WebhookVerifier interface {
+	// Verify returns ErrWebhookSignature or ErrWebhookReplay if the request with
+	// the body, received at now, must be rejected
+	Verify(r *http.Request, body []byte, now time.Time) error
+}
+
+// NewWebhookVerifier creates the verifier of the signature scheme of a connector's webhook
+// configuration. It returns nil if the configuration has no scheme and an error if the
+// configuration is invalid, e.g. has no secret, which would accept requests without a signature.
+func NewWebhookVerifier(cfg config.InboundWebhookConfig) (WebhookVerifier, error) {
+	if err := cfg.Validate("webhook"); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Scheme {
+	case config.InboundWebhookSecretToken:
+		return &secretTokenVerifier{header: headerOr(cfg.Header, TelegramSecretTokenHeader), secret: []byte(cfg.Secret)}, nil
+	case config.InboundWebhookSlack:
+		return &slackVerifier{header: headerOr(cfg.Header, SlackSignatureHeader), secret: []byte(cfg.Secret), tolerance: cfg.Tolerance()}, nil
+	case config.InboundWebhookHMACSHA256:
+		return &hmacVerifier{header: headerOr(cfg.Header, HubSignatureHeader), secret: []byte(cfg.Secret)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// VerifyWebhook returns the middleware rejecting the webhook requests of a connector that the
+// verifier rejects with 401. The body is read, up to MaxInboundWebhookBody bytes, and restored
+// for the handler. A nil verifier lets all requests through.
+func VerifyWebhook(connector string, verifier WebhookVerifier, logger logging.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		if verifier == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxInboundWebhookBody))
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				_ = WriteError(w, http.StatusRequestEntityTooLarge, "webhook request body is too large")
+				return
+			case err != nil:
+				_ = WriteError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err := verifier.Verify(r, body, time.Now()); err != nil {
+				logger.WithContext(r.Context()).Warn("rejected webhook request", "connector", connector, "remote_addr", r.RemoteAddr, "error", err)
+				_ = WriteError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// secretTokenVerifier compares a header with the secret token registered with the platform
+type secretTokenVerifier struct {
+	header string
+	secret []byte
+}
+
+func (v *secretTokenVerifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(v.header)), v.secret) != 1 {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+// slackVerifier verifies the "v0=" + hex HMAC-SHA256 of "v0:<timestamp>:<body>" and
+// rejects timestamps outside the replay window
+type slackVerifier struct {
+	header    string
+	secret    []byte
+	tolerance time.Duration
+}
+
+func (v *slackVerifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	header := r.Header.Get(SlackTimestampHeader)
+	timestamp, err := strconv.ParseInt(header, 10, 64)
+	if err != nil {
+		return ErrWebhookSignature
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > v.tolerance || age < -v.tolerance {
+		return ErrWebhookReplay
+	}
+
+	mac := hmac.New(sha256.New, v.secret)
+	_, _ = fmt.Fprintf(mac, "v0:%s:", header)
+	mac.Write(body)
+	return verifyHexSignature(r.Header.Get(v.header), "v0=", mac.Sum(nil))
+}
+
+// hmacVerifier verifies the "sha256=" + hex HMAC-SHA256 of the body
+type hmacVerifier struct {
+	header string
+	secret []byte
+}
+
+func (v *hmacVerifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write(body)
+	return verifyHexSignature(r.Header.Get(v.header), "sha256=", mac.Sum(nil))
+}
+
+// verifyHexSignature compares a prefixed hex signature with the expected MAC in constant time
+func verifyHexSignature(signature, prefix string, expected []byte) error {
+	encoded, ok := strings.CutPrefix(signature, prefix)
+	if !ok {
+		return ErrWebhookSignature
+	}
+	actual, err := hex.DecodeString(encoded)
+	if err != nil || !hmac.Equal(actual, expected) {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+// headerOr returns header, or fallback if it is empty
+func headerOr(header, fallback string) string {
+	if header != "" {
+		return header
+	}
+	return fallback
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func testHMAC(secret, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookVerifier_SecretToken(t *testing.T) {
+	verifier, err := NewWebhookVerifier(config.InboundWebhookConfig{Scheme: config.InboundWebhookSecretToken, Secret: "token-1"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/webhooks/telegram", nil)
+	assert.ErrorIs(t, verifier.Verify(req, nil, time.Now()), ErrWebhookSignature)
+
+	req.Header.Set(TelegramSecretTokenHeader, "token-2")
+	assert.ErrorIs(t, verifier.Verify(req, nil, time.Now()), ErrWebhookSignature)
+
+	req.Header.Set(TelegramSecretTokenHeader, "token-1")
+	assert.NoError(t, verifier.Verify(req, nil, time.Now()))
+}
+
+func TestWebhookVerifier_Slack(t *testing.T) {
+	verifier, err := NewWebhookVerifier(config.InboundWebhookConfig{Scheme: config.InboundWebhookSlack, Secret: "signing-secret", ToleranceSec: 60})
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"event_callback"}`)
+	signed := func(timestamp time.Time, signature string) *http.Request {
+		req := httptest.NewRequest("POST", "/webhooks/slack", nil)
+		ts := strconv.FormatInt(timestamp.Unix(), 10)
+		req.Header.Set(SlackTimestampHeader, ts)
+		if signature == "" {
+			signature = "v0=" + testHMAC("signing-secret", "v0:"+ts+":"+string(body))
+		}
+		req.Header.Set(SlackSignatureHeader, signature)
+		return req
+	}
+
+	assert.NoError(t, verifier.Verify(signed(now, ""), body, now))
+	assert.NoError(t, verifier.Verify(signed(now.Add(-time.Minute), ""), body, now))
+	assert.ErrorIs(t, verifier.Verify(signed(now, ""), []byte(`{"type":"tampered"}`), now), ErrWebhookSignature)
+	assert.ErrorIs(t, verifier.Verify(signed(now, "v0=deadbeef"), body, now), ErrWebhookSignature)
+	assert.ErrorIs(t, verifier.Verify(signed(now, "v1="+testHMAC("signing-secret", "v0:1700000000:"+string(body))), body, now), ErrWebhookSignature)
+
+	// Recorded requests cannot be replayed after the window
+	assert.ErrorIs(t, verifier.Verify(signed(now.Add(-2*time.Minute), ""), body, now), ErrWebhookReplay)
+	assert.ErrorIs(t, verifier.Verify(signed(now.Add(2*time.Minute), ""), body, now), ErrWebhookReplay)
+
+	req := signed(now, "")
+	req.Header.Del(SlackTimestampHeader)
+	assert.ErrorIs(t, verifier.Verify(req, body, now), ErrWebhookSignature)
+}
+
+func TestWebhookVerifier_HMACSHA256(t *testing.T) {
+	verifier, err := NewWebhookVerifier(config.InboundWebhookConfig{Scheme: config.InboundWebhookHMACSHA256, Secret: "app-secret"})
+	require.NoError(t, err)
+
+	body := []byte(`{"object":"whatsapp_business_account"}`)
+	req := httptest.NewRequest("POST", "/webhooks/whatsapp", nil)
+	req.Header.Set(HubSignatureHeader, "sha256="+testHMAC("app-secret", string(body)))
+	assert.NoError(t, verifier.Verify(req, body, time.Now()))
+	assert.ErrorIs(t, verifier.Verify(req, []byte(`{}`), time.Now()), ErrWebhookSignature)
+
+	req.Header.Set(HubSignatureHeader, testHMAC("app-secret", string(body)))
+	assert.ErrorIs(t, verifier.Verify(req, body, time.Now()), ErrWebhookSignature)
+
+	// The header can be overridden
+	verifier, err = NewWebhookVerifier(config.InboundWebhookConfig{Scheme: config.InboundWebhookHMACSHA256, Secret: "app-secret", Header: "X-Signature"})
+	require.NoError(t, err)
+	req.Header.Set("X-Signature", "sha256="+testHMAC("app-secret", string(body)))
+	assert.NoError(t, verifier.Verify(req, body, time.Now()))
+}
+
+func TestNewWebhookVerifier_NoScheme(t *testing.T) {
+	verifier, err := NewWebhookVerifier(config.InboundWebhookConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, verifier)
+
+	_, err = NewWebhookVerifier(config.InboundWebhookConfig{Scheme: "md5", Secret: "s"})
+	assert.Error(t, err)
+}
+
+func TestNewWebhookVerifier_InvalidConfig(t *testing.T) {
+	// An empty secret token would match requests without the header
+	verifier, err := NewWebhookVerifier(config.InboundWebhookConfig{Scheme: config.InboundWebhookSecretToken})
+	assert.ErrorContains(t, err, "webhook.secret is required")
+	assert.Nil(t, verifier)
+
+	_, err = NewWebhookVerifier(config.InboundWebhookConfig{Scheme: config.InboundWebhookSlack, Secret: "signing-secret", ToleranceSec: -1})
+	assert.ErrorContains(t, err, "tolerance_sec")
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	verifier, err := NewWebhookVerifier(config.InboundWebhookConfig{Scheme: config.InboundWebhookHMACSHA256, Secret: "app-secret"})
+	require.NoError(t, err)
+
+	var received string
+	handler := VerifyWebhook("whatsapp", verifier, logging.NewNoopLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(body, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/webhooks/whatsapp", strings.NewReader(body))
+		req.Header.Set(HubSignatureHeader, signature)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// The handler reads the verified body
+	w := send(`{"entry":[]}`, "sha256="+testHMAC("app-secret", `{"entry":[]}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"entry":[]}`, received)
+
+	received = ""
+	w = send(`{"entry":[]}`, "sha256=00")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, ErrCodeUnauthorized, decodeErrorResponse(t, w).Code)
+	assert.Empty(t, received)
+
+	big := strings.Repeat("a", MaxInboundWebhookBody+1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(big, "sha256="+testHMAC("app-secret", big)).Code)
+
+	// Without a verifier requests are passed through
+	handler = VerifyWebhook("telegram", nil, logging.NewNoopLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	assert.Equal(t, http.StatusNoContent, send("{}", "").Code)
+}
@@ -6,18 +6,21 @@ import (
 	"net/http"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
 )
 
 // LogHandler handles log-related HTTP requests
 type LogHandler struct {
-	logger logging.Logger
+	logUseCase *usecase.LogUseCase
+	logger     logging.Logger
 }
 
 // NewLogHandler creates a new LogHandler
-func NewLogHandler(logger logging.Logger) *LogHandler {
+func NewLogHandler(logUseCase *usecase.LogUseCase, logger logging.Logger) *LogHandler {
 	return &LogHandler{
-		logger: logger,
+		logUseCase: logUseCase,
+		logger:     logger,
 	}
 }
 
@@ -30,22 +33,18 @@ func (h *LogHandler) CreateLog(ctx context.Context, w http.ResponseWriter, r *ht
 	}
 
 	// Validate request
-	if req.Level == "" || req.Source == "" || req.Message == "" {
-		return WriteError(w, http.StatusBadRequest, "level, source, and message are required")
+	if !validateRequest(w, &req) {
+		return nil
 	}
 
-	// TODO: Implement log storage when LogUseCase is available
-	// See issue Nexflow-6n4 for implementation details.
-	// For now, just acknowledge the request
-	h.logger.Info("log entry received", "level", req.Level, "source", req.Source, "message", req.Message)
+	resp, err := h.logUseCase.CreateLog(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to create log", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
 
-	resp := &dto.LogResponse{
-		Success: true,
-		Log: &dto.LogDTO{
-			Level:   req.Level,
-			Source:  req.Source,
-			Message: req.Message,
-		},
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusCreated, resp)
@@ -53,11 +52,29 @@ func (h *LogHandler) CreateLog(ctx context.Context, w http.ResponseWriter, r *ht
 
 // ListLogs handles GET /logs
 func (h *LogHandler) ListLogs(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	// TODO: Implement log listing when LogUseCase is available
-	// See issue Nexflow-6n4 for implementation details.
-	resp := &dto.LogsResponse{
-		Success: true,
-		Logs:    []*dto.LogDTO{},
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"), "offset")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.logUseCase.ListLogs(ctx, dto.ListLogsRequest{
+		Level:  query.Get("level"),
+		Source: query.Get("source"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		h.logger.Error("failed to list logs", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusOK, resp)
@@ -65,6 +82,22 @@ func (h *LogHandler) ListLogs(ctx context.Context, w http.ResponseWriter, r *htt
 
 // RegisterLogRoutes registers log routes
 func RegisterLogRoutes(r *Router, handler *LogHandler) {
-	r.HandleFunc("POST /logs", handler.CreateLog)
-	r.HandleFunc("GET /logs", handler.ListLogs)
+	logs := r.Group("/logs")
+	logs.HandleFunc("POST /", handler.CreateLog).Describe(RouteDoc{
+		Summary:  "Write a log entry",
+		Request:  dto.CreateLogRequest{},
+		Response: dto.LogResponse{},
+		Status:   http.StatusCreated,
+	})
+	logs.HandleFunc("GET /", handler.ListLogs).Describe(RouteDoc{
+		Summary:     "List log entries",
+		Description: "Entries written through the API and the application logs persisted by the server, newest first.",
+		Query: []QueryParam{
+			{Name: "level", Description: "Only entries with this level: debug, info, warn or error"},
+			{Name: "source", Description: "Only entries from this source, e.g. router or telegram"},
+			{Name: "limit", Description: "Maximum number of entries to return (default 100, max 1000)"},
+			{Name: "offset", Description: "Number of entries to skip"},
+		},
+		Response: dto.LogsResponse{},
+	})
 }
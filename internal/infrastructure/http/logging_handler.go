@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// LoggingHandler handles the admin API changing log levels at runtime
+type LoggingHandler struct {
+	levels *logging.Levels
+	logger logging.Logger
+}
+
+// NewLoggingHandler creates a new LoggingHandler
+func NewLoggingHandler(levels *logging.Levels, logger logging.Logger) *LoggingHandler {
+	return &LoggingHandler{
+		levels: levels,
+		logger: logger,
+	}
+}
+
+// GetLevels handles GET /admin/logging
+func (h *LoggingHandler) GetLevels(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return WriteJSON(w, http.StatusOK, h.response())
+}
+
+// UpdateLevels handles PUT /admin/logging.
+// All levels are checked before any is changed, so an invalid request changes nothing.
+func (h *LoggingHandler) UpdateLevels(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req dto.UpdateLogLevelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode log levels request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	names := make([]string, 0, len(req.Modules))
+	for name := range req.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !h.levels.Has(name) {
+			return WriteError(w, http.StatusBadRequest, fmt.Sprintf("unknown logging module %q", name))
+		}
+		if level := req.Modules[name]; level != "" && !validLogLevel(level) {
+			return WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid level %q for logging module %q", level, name))
+		}
+	}
+
+	if req.Level != "" {
+		level, _ := logging.ParseLevel(req.Level)
+		h.levels.SetDefault(level)
+	}
+	for _, name := range names {
+		if req.Modules[name] == "" {
+			h.levels.Reset(name)
+			continue
+		}
+		level, _ := logging.ParseLevel(req.Modules[name])
+		h.levels.Set(name, level)
+	}
+	h.logger.Info("log levels changed", "level", req.Level, "modules", req.Modules)
+
+	return WriteJSON(w, http.StatusOK, h.response())
+}
+
+// response returns the current log levels
+func (h *LoggingHandler) response() *dto.LogLevelsResponse {
+	modules := h.levels.Modules()
+	resp := &dto.LogLevelsResponse{
+		Success: true,
+		Level:   levelString(h.levels.Default()),
+		Modules: make([]dto.ModuleLogLevelDTO, len(modules)),
+	}
+	for i, m := range modules {
+		resp.Modules[i] = dto.ModuleLogLevelDTO{Name: m.Name, Level: levelString(m.Level), Inherited: m.Inherited}
+	}
+	return resp
+}
+
+// validLogLevel reports whether level is a level accepted by the API
+func validLogLevel(level string) bool {
+	switch level {
+	case "debug", "info", "warn", "error":
+		return true
+	}
+	return false
+}
+
+// levelString returns the API name of a level, e.g. "debug"
+func levelString(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+// RegisterLoggingRoutes registers the log level admin routes
+func RegisterLoggingRoutes(r *Router, handler *LoggingHandler) {
+	logging := r.Group("/admin/logging")
+	logging.HandleFunc("GET /", handler.GetLevels).Describe(RouteDoc{
+		Summary:     "Get log levels",
+		Description: "Returns the default level and the levels of the named loggers of subsystems, e.g. router, telegram, llm or db.",
+		Tag:         "admin",
+		Response:    dto.LogLevelsResponse{},
+	})
+	logging.HandleFunc("PUT /", handler.UpdateLevels).Describe(RouteDoc{
+		Summary:     "Change log levels",
+		Description: "Changes the default level and the levels of modules until the next restart. An empty module level makes the module follow the default level again.",
+		Tag:         "admin",
+		Request:     dto.UpdateLogLevelsRequest{},
+		Response:    dto.LogLevelsResponse{},
+	})
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingHandler(t *testing.T) {
+	levels := logging.NewLevels(slog.LevelInfo)
+	logger := logging.NewWithLevels(levels, "json")
+	logging.Named(logger, "router")
+	logging.Named(logger, "telegram")
+
+	router := NewRouter()
+	RegisterLoggingRoutes(router, NewLoggingHandler(levels, logging.NewNoopLogger()))
+
+	do := func(method, body string) (*httptest.ResponseRecorder, dto.LogLevelsResponse) {
+		req := httptest.NewRequest(method, "/admin/logging", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp dto.LogLevelsResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	w, resp := do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "info", resp.Level)
+	assert.Equal(t, []dto.ModuleLogLevelDTO{
+		{Name: "router", Level: "info", Inherited: true},
+		{Name: "telegram", Level: "info", Inherited: true},
+	}, resp.Modules)
+
+	w, resp = do(http.MethodPut, `{"level":"warn","modules":{"telegram":"debug"}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "warn", resp.Level)
+	assert.Equal(t, dto.ModuleLogLevelDTO{Name: "router", Level: "warn", Inherited: true}, resp.Modules[0])
+	assert.Equal(t, dto.ModuleLogLevelDTO{Name: "telegram", Level: "debug"}, resp.Modules[1])
+
+	// An empty level makes the module follow the default level again
+	_, resp = do(http.MethodPut, `{"modules":{"telegram":""}}`)
+	assert.Equal(t, dto.ModuleLogLevelDTO{Name: "telegram", Level: "warn", Inherited: true}, resp.Modules[1])
+
+	// Invalid requests change nothing
+	for _, body := range []string{
+		`{"level":"verbose"}`,
+		`{"modules":{"router":"debug","discord":"debug"}}`,
+		`{"modules":{"router":"loud"}}`,
+		`not json`,
+	} {
+		w, _ := do(http.MethodPut, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Equal(t, slog.LevelWarn, levels.Default())
+	assert.True(t, levels.Modules()[0].Inherited)
+}
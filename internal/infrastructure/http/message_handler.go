@@ -31,14 +31,49 @@ func (h *MessageHandler) GetConversation(ctx context.Context, w http.ResponseWri
 		return WriteError(w, http.StatusBadRequest, "session id is required")
 	}
 
-	resp, err := h.chatUseCase.GetConversation(ctx, sessionID)
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.chatUseCase.GetConversation(ctx, sessionID, page)
 	if err != nil {
 		h.logger.Error("failed to get conversation", "error", err, "session_id", sessionID)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSONWithETag(w, r, http.StatusOK, resp)
+}
+
+// SearchMessages handles GET /messages/search
+func (h *MessageHandler) SearchMessages(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	req := dto.SearchMessagesRequest{
+		Query:     query.Get("q"),
+		UserID:    query.Get("user_id"),
+		SessionID: query.Get("session_id"),
+	}
+	if req.Query == "" {
+		return WriteError(w, http.StatusBadRequest, "q is required")
+	}
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.chatUseCase.SearchMessages(ctx, req, page)
+	if err != nil {
+		h.logger.Error("failed to search messages", "error", err, "user_id", req.UserID)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return WriteError(w, http.StatusBadRequest, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusOK, resp)
@@ -53,17 +88,14 @@ func (h *MessageHandler) SendMessage(ctx context.Context, w http.ResponseWriter,
 	}
 
 	// Validate request
-	if req.UserID == "" {
-		return WriteError(w, http.StatusBadRequest, "user_id is required")
-	}
-	if req.Message.Content == "" {
-		return WriteError(w, http.StatusBadRequest, "message content is required")
+	if !validateRequest(w, &req) {
+		return nil
 	}
 
 	resp, err := h.chatUseCase.SendMessage(ctx, req)
 	if err != nil {
 		h.logger.Error("failed to send message", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -75,6 +107,31 @@ func (h *MessageHandler) SendMessage(ctx context.Context, w http.ResponseWriter,
 
 // RegisterMessageRoutes registers message routes
 func RegisterMessageRoutes(r *Router, handler *MessageHandler) {
-	r.HandleFunc("GET /sessions/{id}/messages", handler.GetConversation)
-	r.HandleFunc("POST /chat/send", handler.SendMessage)
+	session := r.Group("/sessions/{id}")
+	messages := r.Group("/messages")
+	chat := r.Group("/chat")
+	session.HandleFunc("GET /messages", handler.GetConversation).Describe(RouteDoc{
+		Summary:   "List the messages of a session",
+		Tag:       "messages",
+		Response:  dto.MessagesResponse{},
+		Paginated: true,
+		ETag:      true,
+	})
+	messages.HandleFunc("GET /search", handler.SearchMessages).Describe(RouteDoc{
+		Summary:  "Search message history",
+		Response: dto.MessagesResponse{},
+		Query: []QueryParam{
+			{Name: "q", Description: "Full-text search query", Required: true},
+			{Name: "user_id", Description: "Only messages of this user"},
+			{Name: "session_id", Description: "Only messages of this session"},
+		},
+		Paginated: true,
+	})
+	chat.HandleFunc("POST /send", handler.SendMessage).Describe(RouteDoc{
+		Summary:    "Send a message to a session and get the reply",
+		Tag:        "messages",
+		Request:    dto.SendMessageRequest{},
+		Response:   dto.SendMessageResponse{},
+		Idempotent: true,
+	})
 }
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// MessageJobHandler handles the asynchronous message API
+type MessageJobHandler struct {
+	messageJobUseCase *usecase.MessageJobUseCase
+	logger            logging.Logger
+}
+
+// NewMessageJobHandler creates a new MessageJobHandler
+func NewMessageJobHandler(messageJobUseCase *usecase.MessageJobUseCase, logger logging.Logger) *MessageJobHandler {
+	return &MessageJobHandler{
+		messageJobUseCase: messageJobUseCase,
+		logger:            logger,
+	}
+}
+
+// SendMessageAsync handles POST /messages/async
+func (h *MessageJobHandler) SendMessageAsync(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req dto.SendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode async message request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.messageJobUseCase.SendMessageAsync(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to send async message", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	w.Header().Set("Location", "/jobs/"+resp.Job.ID)
+	return WriteJSON(w, http.StatusAccepted, resp)
+}
+
+// GetJob handles GET /jobs/{id}
+func (h *MessageJobHandler) GetJob(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "job id is required")
+	}
+
+	resp, err := h.messageJobUseCase.GetMessageJob(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to get message job", "error", err, "job_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// SendMessageBatch handles POST /messages/batch
+func (h *MessageJobHandler) SendMessageBatch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req dto.SendMessageBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode message batch request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.messageJobUseCase.SendMessageBatch(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to send message batch", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	w.Header().Set("Location", "/batches/"+resp.Batch.ID)
+	return WriteJSON(w, http.StatusAccepted, resp)
+}
+
+// GetBatch handles GET /batches/{id}
+func (h *MessageJobHandler) GetBatch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "batch id is required")
+	}
+
+	resp, err := h.messageJobUseCase.GetMessageBatch(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to get message batch", "error", err, "batch_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterMessageJobRoutes registers the asynchronous message routes
+func RegisterMessageJobRoutes(r *Router, handler *MessageJobHandler) {
+	messages := r.Group("/messages")
+	jobs := r.Group("/jobs")
+	batches := r.Group("/batches")
+	messages.HandleFunc("POST /async", handler.SendMessageAsync).Describe(RouteDoc{
+		Summary:     "Send a message to a session and answer it in the background",
+		Description: "Accepts the message like POST /chat/send and returns a pending job immediately, with its URL in the Location header; poll GET /jobs/{id} for the reply. Returns 503 when too many messages are waiting to be answered.",
+		Tag:         "messages",
+		Request:     dto.SendMessageRequest{},
+		Response:    dto.MessageJobResponse{},
+		Status:      http.StatusAccepted,
+		Idempotent:  true,
+	})
+	jobs.HandleFunc("GET /{id}", handler.GetJob).Describe(RouteDoc{
+		Summary:     "Get an asynchronous message job",
+		Description: "Status of a job created by POST /messages/async: pending, running, completed with the reply in message_id and reply, or failed with the reason in error. Finished jobs are kept for message_jobs.ttl_hours.",
+		Tag:         "messages",
+		Response:    dto.MessageJobResponse{},
+	})
+	messages.HandleFunc("POST /batch", handler.SendMessageBatch).Describe(RouteDoc{
+		Summary:     "Answer a batch of prompts in the background",
+		Description: "Creates a job for every prompt, answered through the orchestrator like POST /messages/async, and returns the pending batch immediately with its URL in the Location header; poll GET /batches/{id} for the results. Prompts without session_id are answered in a new session each. A batch holds up to message_jobs.max_batch_size prompts and is rejected with 503 as a whole when the queue cannot hold it.",
+		Tag:         "messages",
+		Request:     dto.SendMessageBatchRequest{},
+		Response:    dto.MessageBatchResponse{},
+		Status:      http.StatusAccepted,
+		Idempotent:  true,
+	})
+	batches.HandleFunc("GET /{id}", handler.GetBatch).Describe(RouteDoc{
+		Summary:     "Get a batch of prompts",
+		Description: "Jobs of a batch created by POST /messages/batch in the order of its prompts, each with its status, reply or error, and the number of jobs per status. The batch is completed once every job is completed or failed.",
+		Tag:         "messages",
+		Response:    dto.MessageBatchResponse{},
+	})
+}
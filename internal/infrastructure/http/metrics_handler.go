@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// MetricsHandler handles the Prometheus metrics endpoint
+type MetricsHandler struct {
+	registries []*metrics.MetricsRegistry
+	logger     logging.Logger
+}
+
+// NewMetricsHandler creates a new MetricsHandler exposing the given registries
+func NewMetricsHandler(logger logging.Logger, registries ...*metrics.MetricsRegistry) *MetricsHandler {
+	return &MetricsHandler{
+		registries: registries,
+		logger:     logger,
+	}
+}
+
+// Metrics handles GET /metrics
+func (h *MetricsHandler) Metrics(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", metrics.PrometheusContentType)
+	w.WriteHeader(http.StatusOK)
+	if err := metrics.WritePrometheus(w, h.registries...); err != nil {
+		h.logger.Debug("failed to write metrics", "error", err)
+	}
+	return nil
+}
+
+// RegisterMetricsRoutes registers the metrics route
+func RegisterMetricsRoutes(r *Router, handler *MetricsHandler) {
+	r.HandleFunc("GET /metrics", handler.Metrics).Describe(RouteDoc{
+		Summary:     "Prometheus metrics",
+		Description: "Metrics of the router, connectors, LLM providers, database, scheduler and HTTP API in the Prometheus text format.",
+		Tag:         "health",
+		Produces:    []string{"text/plain"},
+	})
+}
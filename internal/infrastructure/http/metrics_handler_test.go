@@ -0,0 +1,32 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+func TestMetricsHandler_Metrics(t *testing.T) {
+	registry := metrics.NewMetricsRegistry()
+	registry.GetCounter(`router_connector_messages_received_total{connector="telegram"}`).Add(3)
+	handler := NewMetricsHandler(logging.NewNoopLogger(), registry, NewRateLimitMetrics().Registry())
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+
+	err := handler.Metrics(context.Background(), w, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, metrics.PrometheusContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "# TYPE router_connector_messages_received_total counter\n")
+	assert.Contains(t, w.Body.String(), `router_connector_messages_received_total{connector="telegram"} 3`)
+	assert.Contains(t, w.Body.String(), "http_rate_limit_allowed_total 0\n")
+}
@@ -1,11 +1,22 @@
 package http
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 // Middleware is a function that wraps an http.Handler
@@ -37,7 +48,7 @@ func Recovery(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				log.Printf("Panic recovered: %v", err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				_ = WriteError(w, http.StatusInternalServerError, "internal server error")
 			}
 		}()
 
@@ -45,7 +56,27 @@ func Recovery(next http.Handler) http.Handler {
 	})
 }
 
-// RequestID adds a unique request ID to each request
+// RecoveryWithRecoverer recovers from panics like Recovery, logging, counting and reporting
+// them with the recoverer
+func RecoveryWithRecoverer(recoverer *crash.Recoverer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			defer func() {
+				if err != nil {
+					_ = WriteError(w, http.StatusInternalServerError, "internal server error")
+				}
+			}()
+			defer recoverer.Recover(r.Context(), "http", "handle_request", &err, "method", r.Method, "path", r.URL.Path)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestID adds a unique request ID to each request.
+// The ID is set as the X-Request-ID response header and carried by the request context,
+// so that the records logged with the context get the request_id attribute.
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestID := r.Header.Get("X-Request-ID")
@@ -54,10 +85,46 @@ func RequestID(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("X-Request-ID", requestID)
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
 	})
 }
 
+// RequestTimeout sets a deadline of timeout on the context of each request, so that the use
+// cases and the LLM calls of a request that takes too long are cancelled and the request fails
+// with 504. The context of a request is also cancelled when the client disconnects. Streaming
+// routes, documented as producing text/event-stream, also under the dashboard API, and
+// WebSocket connections have no deadline. A timeout of 0 disables the deadline.
+func RequestTimeout(timeout time.Duration, routes []*Route) Middleware {
+	streaming := http.NewServeMux()
+	for _, route := range routes {
+		if slices.Contains(route.Doc.Produces, "text/event-stream") {
+			streaming.Handle(route.Method+" "+route.Path, http.NotFoundHandler())
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if timeout <= 0 || isWebSocketUpgrade(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			match := r
+			if path := apiPath(r); path != r.URL.Path {
+				match = r.Clone(r.Context())
+				match.URL.Path = path
+			}
+			if _, pattern := streaming.Handler(match); pattern != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // Timeout adds a timeout to the request context
 func Timeout(timeout time.Duration) Middleware {
 	return func(next http.Handler) http.Handler {
@@ -65,47 +132,133 @@ func Timeout(timeout time.Duration) Middleware {
 	}
 }
 
-// Compress compresses responses (simplified version - gzip would require more implementation)
-func Compress(level int, types ...string) Middleware {
+// ContentType sets the content type
+func ContentType(contentType string) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Check if client accepts gzip
-			if r.Header.Get("Accept-Encoding") == "" {
-				next.ServeHTTP(w, r)
+			w.Header().Set("Content-Type", contentType)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORS returns a middleware that allows cross-origin requests from the configured origins.
+// Requests from other origins get no CORS headers, so browsers block them; preflight requests
+// from allowed origins are answered directly. CORS is disabled if no origins are configured.
+func CORS(cfg config.ServerCORSConfig) Middleware {
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = config.DefaultCORSMethods
+	}
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = config.DefaultCORSHeaders
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	exposeHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	anyOrigin := cfg.AllowsAnyOrigin()
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowOrigin := "*"
+			if !anyOrigin {
+				w.Header().Add("Vary", "Origin")
+				allowOrigin = r.Header.Get("Origin")
+				if !slices.Contains(cfg.AllowedOrigins, allowOrigin) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			if exposeHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			if cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method == "OPTIONS" {
+				if cfg.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+				}
+				w.WriteHeader(http.StatusOK)
 				return
 			}
 
-			// For simplicity, we're not implementing full compression here
-			// In production, use gzip middleware or a library
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-// ContentType sets the content type
-func ContentType(contentType string) Middleware {
+// SecurityHeaders returns a middleware that adds security headers to every response:
+// X-Content-Type-Options, X-Frame-Options, Referrer-Policy, the configured Content-Security-Policy,
+// and Strict-Transport-Security on HTTPS requests.
+func SecurityHeaders(cfg config.ServerSecurityHeadersConfig) Middleware {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge) + "; includeSubDomains"
+	}
+
 	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", contentType)
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", "DENY")
+			h.Set("Referrer-Policy", "no-referrer")
+			if cfg.ContentSecurityPolicy != "" {
+				h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+			}
+			if hsts != "" && r.TLS != nil {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-// CORS enables CORS support
-func CORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// Tracing returns a middleware that records a span for every request, continuing the trace of
+// the traceparent request header. The traceparent of the span is returned in the response
+// header, so that clients can look up the trace of a request. A nil tracer disables tracing.
+func Tracing(tracer *tracing.Tracer) Middleware {
+	return func(next http.Handler) http.Handler {
+		if tracer == nil {
+			return next
 		}
 
-		next.ServeHTTP(w, r)
-	})
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := tracing.Extract(r.Context(), r.Header.Get(tracing.TraceparentHeader))
+			ctx, span := tracer.Start(ctx, r.Method, tracing.SpanKindServer,
+				"http.method", r.Method,
+				"http.target", r.URL.Path,
+			)
+			defer span.End()
+			w.Header().Set(tracing.TraceparentHeader, span.SpanContext().Traceparent())
+
+			lrw := &loggingResponseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+			next.ServeHTTP(lrw, r.WithContext(ctx))
+
+			span.SetAttributes("http.status_code", lrw.statusCode)
+			if lrw.statusCode >= http.StatusInternalServerError {
+				span.RecordError(fmt.Errorf("%d %s", lrw.statusCode, http.StatusText(lrw.statusCode)))
+			}
+		})
+	}
 }
 
 // loggingResponseWriter wraps http.ResponseWriter to capture status code
@@ -119,6 +272,12 @@ func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap returns the wrapped http.ResponseWriter, so that http.ResponseController
+// can reach optional interfaces such as http.Hijacker
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
+
 // generateRequestID generates a simple request ID
 func generateRequestID() string {
 	return fmt.Sprintf("%d", time.Now().UnixNano())
@@ -133,9 +292,58 @@ func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) error {
 	return json.NewEncoder(w).Encode(data)
 }
 
-// WriteError writes an error response
+// WriteJSONWithETag writes a JSON response with a weak ETag computed from the body.
+// If the request's If-None-Match matches the ETag, it writes 304 Not Modified without a body,
+// so that clients polling a list only download it when it has changed.
+func WriteJSONWithETag(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	body = append(body, '\n')
+
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err = w.Write(body)
+	return err
+}
+
+// etagMatches reports whether an If-None-Match header matches an ETag, using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteError writes an error response with the error code matching the status
 func WriteError(w http.ResponseWriter, statusCode int, message string) error {
-	return WriteJSON(w, statusCode, map[string]interface{}{
-		"error": message,
-	})
+	return WriteErrorCode(w, statusCode, errorCodeForStatus(statusCode), message, nil)
+}
+
+// WriteErrorCode writes an error response with an explicit error code and optional details.
+// The request ID is taken from the X-Request-ID response header set by the RequestID middleware.
+func WriteErrorCode(w http.ResponseWriter, statusCode int, code ErrorCode, message string, details any) error {
+	return WriteJSON(w, statusCode, ErrorResponse{Error: APIError{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: w.Header().Get("X-Request-ID"),
+	}})
 }
@@ -1,12 +1,25 @@
 package http
 
 import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 func TestLogging(t *testing.T) {
@@ -53,6 +66,25 @@ func TestRecovery_WithPanic(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestRecoveryWithRecoverer(t *testing.T) {
+	recoverer := crash.NewRecoverer(logging.NewNoopLogger())
+	middleware := RecoveryWithRecoverer(recoverer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("test panic")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	middleware.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	middleware.ServeHTTP(w, httptest.NewRequest("GET", "/ok", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, int64(1), recoverer.Metrics().Recovered("http").Get())
+}
+
 func TestRequestID_NoHeader(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -69,7 +101,9 @@ func TestRequestID_NoHeader(t *testing.T) {
 }
 
 func TestRequestID_WithHeader(t *testing.T) {
+	var requestID string
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = logging.RequestIDFromContext(r.Context())
 		w.WriteHeader(http.StatusOK)
 	})
 
@@ -82,6 +116,34 @@ func TestRequestID_WithHeader(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "test-request-id", w.Header().Get("X-Request-ID"))
+	assert.Equal(t, "test-request-id", requestID)
+}
+
+func TestTracing(t *testing.T) {
+	tracer := tracing.NewTracer(tracing.Config{SampleRatio: 1})
+	defer tracer.Shutdown(context.Background())
+
+	var sc tracing.SpanContext
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc = tracing.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest("POST", "/api/v1/messages", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	Tracing(tracer)(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.NotEqual(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.Equal(t, sc.Traceparent(), w.Header().Get("traceparent"))
+
+	// Without a tracer requests are passed through
+	w = httptest.NewRecorder()
+	Tracing(nil)(handler).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users", nil))
+	assert.Empty(t, w.Header().Get("traceparent"))
+	assert.False(t, sc.IsValid())
 }
 
 func TestTimeout(t *testing.T) {
@@ -99,6 +161,60 @@ func TestTimeout(t *testing.T) {
 	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
 }
 
+func TestRequestTimeout(t *testing.T) {
+	router := NewRouter()
+	slow := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to generate response: %w", ctx.Err())
+		case <-time.After(time.Second):
+			return WriteJSON(w, http.StatusOK, map[string]any{})
+		}
+	}
+	var deadline bool
+	stream := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, deadline = ctx.Deadline()
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	router.HandleFunc("POST /chat/send", slow)
+	router.HandleFunc("GET /events", stream).Describe(RouteDoc{Produces: []string{"text/event-stream"}})
+	router.HandleFunc("GET /chat/ws", stream)
+
+	handler := RequestTimeout(20*time.Millisecond, router.Routes())(router)
+
+	// The use case is cancelled at the deadline and the request fails with 504
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/chat/send", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, ErrCodeTimeout, decodeErrorResponse(t, w).Code)
+
+	// Streams and WebSocket connections have no deadline
+	deadline = true
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/events", nil))
+	assert.False(t, deadline)
+
+	dashboard := RequestTimeout(20*time.Millisecond, router.Routes())(dashboardAPI(router))
+	deadline = true
+	dashboard.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", dashboardAPIPrefix+"/events", nil))
+	assert.False(t, deadline)
+
+	req := httptest.NewRequest("GET", "/chat/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	deadline = true
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, deadline)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/chat/ws", nil))
+	assert.True(t, deadline)
+
+	// Without a timeout requests have no deadline
+	deadline = true
+	RequestTimeout(0, router.Routes())(router).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/chat/ws", nil))
+	assert.False(t, deadline)
+}
+
 func TestCompress(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -113,6 +229,65 @@ func TestCompress(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestCompress_Encodings(t *testing.T) {
+	body := strings.Repeat(`{"message":"hello"}`, 100)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write([]byte(body))
+	})
+	middleware := Compress(gzip.BestSpeed)(handler)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		wantEncoding   string
+		decode         func(io.Reader) (io.Reader, error)
+	}{
+		{"gzip", "deflate, gzip;q=0.9", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"deflate", "gzip;q=0, deflate", "deflate", func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }},
+		{"identity", "br", "", func(r io.Reader) (io.Reader, error) { return r, nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+
+			middleware.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			if tt.wantEncoding != "" {
+				assert.Empty(t, w.Header().Get("Content-Length"))
+				assert.Less(t, w.Body.Len(), len(body))
+			}
+
+			reader, err := tt.decode(w.Body)
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(decoded))
+		})
+	}
+}
+
+func TestCompress_SkipsOtherTypes(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = w.Write([]byte("already compressed"))
+	})
+
+	req := httptest.NewRequest("GET", "/backup.db.gz", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	Compress(gzip.DefaultCompression)(handler).ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "already compressed", w.Body.String())
+}
+
 func TestContentType(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -133,7 +308,7 @@ func TestCORS_Get(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	middleware := CORS(handler)
+	middleware := CORS(config.ServerCORSConfig{AllowedOrigins: []string{"*"}})(handler)
 	req := httptest.NewRequest("GET", "/test", nil)
 	w := httptest.NewRecorder()
 
@@ -150,7 +325,7 @@ func TestCORS_Options(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	middleware := CORS(handler)
+	middleware := CORS(config.ServerCORSConfig{AllowedOrigins: []string{"*"}})(handler)
 	req := httptest.NewRequest("OPTIONS", "/test", nil)
 	w := httptest.NewRecorder()
 
@@ -159,6 +334,118 @@ func TestCORS_Options(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestCORS_AllowedOrigins(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	middleware := CORS(config.ServerCORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	})(handler)
+
+	t.Run("allowed origin", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		middleware.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		req := httptest.NewRequest("OPTIONS", "/test", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		middleware.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("other origin", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		w := httptest.NewRecorder()
+		middleware.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestCORS_Disabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/test", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	CORS(config.ServerCORSConfig{})(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middleware := SecurityHeaders(config.DefaultServerSecurityHeadersConfig())(handler)
+
+	req := httptest.NewRequest("GET", "/api/docs", nil)
+	w := httptest.NewRecorder()
+	middleware.ServeHTTP(w, req)
+
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", w.Header().Get("Referrer-Policy"))
+	assert.Equal(t, config.DefaultContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"), "HSTS must only be sent over HTTPS")
+
+	req = httptest.NewRequest("GET", "https://example.com/api/docs", nil)
+	w = httptest.NewRecorder()
+	middleware.ServeHTTP(w, req)
+
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestWriteJSONWithETag(t *testing.T) {
+	data := map[string]string{"key": "value"}
+
+	w := httptest.NewRecorder()
+	require.NoError(t, WriteJSONWithETag(w, httptest.NewRequest("GET", "/skills", nil), http.StatusOK, data))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+	assert.JSONEq(t, `{"key":"value"}`, w.Body.String())
+
+	req := httptest.NewRequest("GET", "/skills", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	w = httptest.NewRecorder()
+	require.NoError(t, WriteJSONWithETag(w, req, http.StatusOK, data))
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Zero(t, w.Body.Len())
+
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	require.NoError(t, WriteJSONWithETag(w, req, http.StatusOK, map[string]string{"key": "changed"}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
 func TestWriteJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 
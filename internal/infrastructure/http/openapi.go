@@ -0,0 +1,327 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIVersion is the version of the HTTP API reported in the OpenAPI specification
+const APIVersion = "0.1.0"
+
+// RouteDoc describes a route in the OpenAPI specification
+type RouteDoc struct {
+	Summary     string
+	Description string
+	Tag         string       // Defaults to the first path segment
+	Request     any          // JSON request body, e.g. dto.CreateUserRequest{}
+	Response    any          // JSON response body on success
+	Status      int          // Success status code (0 = 200)
+	Query       []QueryParam // Query string parameters
+	Paginated   bool         // Adds the limit, offset, since, until and cursor parameters
+	ETag        bool         // The response has an ETag and honours If-None-Match (see WriteJSONWithETag)
+	Produces    []string     // Content types of a file response, used instead of Response
+	Public      bool         // The route does not require authentication
+	Idempotent  bool         // The route honours the Idempotency-Key header (see Idempotency)
+}
+
+// QueryParam describes a query string parameter
+type QueryParam struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// paginationParams are the query parameters read by parsePageRequest
+var paginationParams = []QueryParam{
+	{Name: "limit", Description: "Maximum number of items to return"},
+	{Name: "offset", Description: "Number of items to skip"},
+	{Name: "since", Description: "Only items created at or after this time (RFC3339)"},
+	{Name: "until", Description: "Only items created before this time (RFC3339)"},
+	{Name: "cursor", Description: "Cursor from next_cursor of the previous page"},
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// NewOpenAPISpec builds an OpenAPI 3 specification of the routes
+func NewOpenAPISpec(routes []*Route) map[string]any {
+	b := &openAPIBuilder{schemas: make(map[string]any)}
+	b.schema(reflect.TypeOf(ErrorResponse{}))
+
+	paths := make(map[string]any)
+	for _, route := range routes {
+		if route.Method == "" {
+			continue
+		}
+		item, ok := paths[route.Path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = b.operation(route)
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Nexflow API",
+			"version": APIVersion,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]any{
+				"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": APIKeyHeader},
+				"bearer": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"security": []any{
+			map[string]any{"apiKey": []string{}},
+			map[string]any{"bearer": []string{}},
+		},
+	}
+}
+
+// MarshalOpenAPISpec renders the OpenAPI specification of the routes as indented JSON
+func MarshalOpenAPISpec(routes []*Route) ([]byte, error) {
+	data, err := json.MarshalIndent(NewOpenAPISpec(routes), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// openAPIBuilder collects the component schemas referenced by operations
+type openAPIBuilder struct {
+	schemas map[string]any
+}
+
+// operation builds the OpenAPI operation object of a route
+func (b *openAPIBuilder) operation(route *Route) map[string]any {
+	doc := route.Doc
+
+	tag := doc.Tag
+	if tag == "" {
+		tag = strings.Split(strings.TrimPrefix(route.Path, "/"), "/")[0]
+	}
+	op := map[string]any{
+		"tags":    []string{tag},
+		"summary": doc.Summary,
+	}
+	if route.Handler != "" {
+		op["operationId"] = strings.ToLower(route.Handler[:1]) + route.Handler[1:]
+	}
+	if doc.Description != "" {
+		op["description"] = doc.Description
+	}
+
+	params := make([]any, 0)
+	for _, name := range pathParams(route.Path) {
+		params = append(params, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+	query := doc.Query
+	if doc.Paginated {
+		query = append(append([]QueryParam{}, query...), paginationParams...)
+	}
+	for _, q := range query {
+		param := map[string]any{
+			"name":   q.Name,
+			"in":     "query",
+			"schema": map[string]any{"type": "string"},
+		}
+		if q.Description != "" {
+			param["description"] = q.Description
+		}
+		if q.Required {
+			param["required"] = true
+		}
+		params = append(params, param)
+	}
+	if doc.ETag {
+		params = append(params, map[string]any{
+			"name":        "If-None-Match",
+			"in":          "header",
+			"description": "ETag of a previous response; 304 is returned if the response has not changed",
+			"schema":      map[string]any{"type": "string"},
+		})
+	}
+	if doc.Idempotent {
+		params = append(params, map[string]any{
+			"name":        IdempotencyKeyHeader,
+			"in":          "header",
+			"description": "Key of the request; a retry with the same key gets the response of the first request",
+			"schema":      map[string]any{"type": "string", "maxLength": MaxIdempotencyKeyLength},
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if doc.Request != nil {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": b.schema(reflect.TypeOf(doc.Request))},
+			},
+		}
+	}
+
+	status := doc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]any{"description": http.StatusText(status)}
+	switch {
+	case len(doc.Produces) > 0:
+		content := make(map[string]any)
+		for _, contentType := range doc.Produces {
+			content[contentType] = map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}}
+		}
+		success["content"] = content
+	case doc.Response != nil:
+		success["content"] = map[string]any{
+			"application/json": map[string]any{"schema": b.schema(reflect.TypeOf(doc.Response))},
+		}
+	}
+	responses := map[string]any{
+		strconv.Itoa(status): success,
+		"default": map[string]any{
+			"description": "Error",
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/ErrorResponse"}},
+			},
+		},
+	}
+	if doc.ETag {
+		success["headers"] = map[string]any{"ETag": map[string]any{"schema": map[string]any{"type": "string"}}}
+		responses[strconv.Itoa(http.StatusNotModified)] = map[string]any{"description": http.StatusText(http.StatusNotModified)}
+	}
+	op["responses"] = responses
+
+	if doc.Public {
+		op["security"] = []any{}
+	}
+
+	return op
+}
+
+// schema returns the JSON schema of a Go type.
+// Named structs are added to the component schemas and referenced by name.
+func (b *openAPIBuilder) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if _, ok := b.schemas[t.Name()]; !ok {
+			// Reserve the name first so that recursive types terminate
+			b.schemas[t.Name()] = map[string]any{}
+			b.schemas[t.Name()] = b.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the object schema of a struct's JSON fields.
+// Fields without omitempty are required; a oneof validation rule becomes an enum.
+func (b *openAPIBuilder) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.schema(field.Type)
+		if enum := oneofValues(field.Tag.Get("validate")); enum != nil && property["type"] == "string" {
+			property["enum"] = enum
+		}
+		properties[name] = property
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// oneofValues returns the values allowed by the oneof rule of a validate tag, or nil if it has none
+func oneofValues(tag string) []string {
+	for _, rule := range strings.Split(tag, ",") {
+		if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+			return strings.Fields(values)
+		}
+	}
+	return nil
+}
+
+// pathParams returns the names of the wildcards in a route path, e.g. "id" in "/users/{id}"
+func pathParams(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, segment[1:len(segment)-1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"sync"
+)
+
+//go:embed assets/swagger-ui.html
+var swaggerUIPage []byte
+
+// OpenAPIHandler serves the OpenAPI specification of a router and the Swagger UI
+type OpenAPIHandler struct {
+	router *Router
+
+	once sync.Once
+	spec []byte
+	err  error
+}
+
+// NewOpenAPIHandler creates a new OpenAPIHandler.
+// The specification is generated on first request, after all routes are registered.
+func NewOpenAPIHandler(router *Router) *OpenAPIHandler {
+	return &OpenAPIHandler{router: router}
+}
+
+// Spec handles GET /api/openapi.json
+func (h *OpenAPIHandler) Spec(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h.once.Do(func() {
+		h.spec, h.err = MarshalOpenAPISpec(h.router.Routes())
+	})
+	if h.err != nil {
+		return h.err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(h.spec)
+	return err
+}
+
+// SwaggerUI handles GET /api/docs
+func (h *OpenAPIHandler) SwaggerUI(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(swaggerUIPage)
+	return err
+}
+
+// RegisterOpenAPIRoutes registers the OpenAPI specification and Swagger UI routes
+func RegisterOpenAPIRoutes(r *Router) {
+	handler := NewOpenAPIHandler(r)
+	api := r.Group("/api")
+	api.HandleFunc("GET /openapi.json", handler.Spec).Describe(RouteDoc{
+		Summary:  "OpenAPI specification of the HTTP API",
+		Tag:      "docs",
+		Produces: []string{"application/json"},
+	})
+	api.HandleFunc("GET /docs", handler.SwaggerUI).Describe(RouteDoc{
+		Summary:  "Swagger UI for the HTTP API",
+		Tag:      "docs",
+		Produces: []string{"text/html"},
+	})
+}
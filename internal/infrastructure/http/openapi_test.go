@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	ID       string      `json:"id"`
+	Note     string      `json:"note,omitempty"`
+	Kind     string      `json:"kind,omitempty" validate:"omitempty,oneof=a b"`
+	Children []*testItem `json:"children"`
+	Secret   string      `json:"-"`
+}
+
+func TestRegisterRoutes_AllDescribed(t *testing.T) {
+	router := NewRouter()
+	RegisterRoutes(router, Handlers{})
+
+	require.NotEmpty(t, router.Routes())
+	for _, route := range router.Routes() {
+		assert.NotEmpty(t, route.Method, route.Path)
+		assert.NotEmpty(t, route.Doc.Summary, "%s %s has no summary", route.Method, route.Path)
+		assert.NotEmpty(t, route.Handler, route.Path)
+	}
+}
+
+func TestNewOpenAPISpec(t *testing.T) {
+	router := NewRouter()
+	noop := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error { return nil }
+	router.HandleFunc("PUT /items/{id}", noop).Describe(RouteDoc{
+		Summary:  "Update an item",
+		Request:  testItem{},
+		Response: testItem{},
+		Query:    []QueryParam{{Name: "force", Required: true}},
+	})
+	router.HandleFunc("GET /status", noop).Describe(RouteDoc{Summary: "Status", Public: true})
+
+	spec := NewOpenAPISpec(router.Routes())
+
+	paths := spec["paths"].(map[string]any)
+	op := paths["/items/{id}"].(map[string]any)["put"].(map[string]any)
+	assert.Equal(t, []string{"items"}, op["tags"])
+	params := op["parameters"].([]any)
+	require.Len(t, params, 2)
+	assert.Equal(t, "id", params[0].(map[string]any)["name"])
+	assert.Equal(t, "path", params[0].(map[string]any)["in"])
+	assert.Equal(t, true, params[1].(map[string]any)["required"])
+	assert.NotContains(t, op, "security")
+
+	status := paths["/status"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, []any{}, status["security"])
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	item := schemas["testItem"].(map[string]any)
+	properties := item["properties"].(map[string]any)
+	assert.Contains(t, properties, "id")
+	assert.NotContains(t, properties, "Secret")
+	assert.Equal(t, []string{"a", "b"}, properties["kind"].(map[string]any)["enum"])
+	assert.Equal(t, []string{"id", "children"}, item["required"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/testItem"}, properties["children"].(map[string]any)["items"])
+}
+
+func TestOpenAPIHandler_Spec(t *testing.T) {
+	router := NewRouter()
+	RegisterRoutes(router, Handlers{})
+
+	req := httptest.NewRequest("GET", "/api/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+	assert.Contains(t, spec["paths"], "/users/{id}")
+}
+
+// TestOpenAPISpec_UpToDate fails when docs/openapi.json was not regenerated after a route change
+func TestOpenAPISpec_UpToDate(t *testing.T) {
+	router := NewRouter()
+	RegisterRoutes(router, Handlers{})
+
+	want, err := MarshalOpenAPISpec(router.Routes())
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../../docs/openapi.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "docs/openapi.json is out of date, run: go run ./cmd/openapi -o docs/openapi.json")
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+)
+
+// parsePageRequest reads the limit, offset, since, until and cursor query parameters.
+func parsePageRequest(r *http.Request) (dto.PageRequest, error) {
+	query := r.URL.Query()
+
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return dto.PageRequest{}, err
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"), "offset")
+	if err != nil {
+		return dto.PageRequest{}, err
+	}
+
+	return dto.PageRequest{
+		Limit:  limit,
+		Offset: offset,
+		Since:  query.Get("since"),
+		Until:  query.Get("until"),
+		Cursor: query.Get("cursor"),
+	}, nil
+}
+
+// parseNonNegativeInt parses an optional non-negative integer query parameter.
+func parseNonNegativeInt(raw, name string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
@@ -0,0 +1,237 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// rateLimitSweepInterval is how often buckets of idle clients are dropped
+const rateLimitSweepInterval = time.Minute
+
+// RateLimitMetrics holds all metrics for the RateLimiter
+type RateLimitMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	Allowed    *metrics.Counter
+	LimitedIP  *metrics.Counter
+	LimitedKey *metrics.Counter
+	Buckets    *metrics.Gauge
+}
+
+// NewRateLimitMetrics creates a new RateLimitMetrics instance
+func NewRateLimitMetrics() *RateLimitMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &RateLimitMetrics{
+		registry: registry,
+
+		Allowed:    registry.GetCounter("http_rate_limit_allowed_total"),
+		LimitedIP:  registry.GetCounter("http_rate_limit_limited_ip_total"),
+		LimitedKey: registry.GetCounter("http_rate_limit_limited_api_key_total"),
+		Buckets:    registry.GetGauge("http_rate_limit_buckets"),
+	}
+}
+
+// Registry returns the registry holding the RateLimiter metrics
+func (m *RateLimitMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// RateLimiter limits the request rate of HTTP API clients with token buckets.
+// IPMiddleware limits requests per client IP before the Authenticator, so that requests with
+// wrong credentials are limited too; Middleware limits the requests the Authenticator
+// authenticated per credential instead, and returns their token to the bucket of the IP.
+// GET /healthz is not limited.
+type RateLimiter struct {
+	enabled bool
+	perIP   *tokenBuckets
+	perKey  *tokenBuckets
+	metrics *RateLimitMetrics
+	logger  logging.Logger
+	now     func() time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter
+func NewRateLimiter(cfg config.RateLimitConfig, logger logging.Logger) *RateLimiter {
+	return &RateLimiter{
+		enabled: cfg.Enabled,
+		perIP:   newTokenBuckets(cfg.PerIP),
+		perKey:  newTokenBuckets(cfg.PerAPIKey),
+		metrics: NewRateLimitMetrics(),
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Metrics returns the rate limiter metrics
+func (l *RateLimiter) Metrics() *RateLimitMetrics {
+	return l.metrics
+}
+
+// ipTokenKey marks the context of requests that took a token from the bucket of their IP
+type ipTokenKey struct{}
+
+// IPMiddleware rejects requests over the limit of their client IP with 429 and a Retry-After
+// header. It must run before the Authenticator.
+func (l *RateLimiter) IPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.enabled || isPublicRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, retryAfter := l.perIP.take(clientIP(r), l.now())
+		l.metrics.Buckets.Set(int64(l.perIP.len() + l.perKey.len()))
+		if !allowed {
+			l.metrics.LimitedIP.Inc()
+			l.reject(w, r, retryAfter)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ipTokenKey{}, true)))
+	})
+}
+
+// Middleware rejects authenticated requests over the limit of their credential with 429 and
+// a Retry-After header. It must run after the Authenticator. Requests without credentials are
+// limited by IPMiddleware only, or here by their IP if IPMiddleware did not run.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.enabled || isPublicRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		now := l.now()
+		ipToken, _ := r.Context().Value(ipTokenKey{}).(bool)
+		allowed, retryAfter := true, time.Duration(0)
+		if credential := credentialFromContext(r.Context()); credential != "" {
+			// Authenticated requests count against their credential only
+			if ipToken {
+				l.perIP.refund(clientIP(r), now)
+			}
+			allowed, retryAfter = l.perKey.take(credential, now)
+			if !allowed {
+				l.metrics.LimitedKey.Inc()
+			}
+		} else if !ipToken {
+			allowed, retryAfter = l.perIP.take(clientIP(r), now)
+			if !allowed {
+				l.metrics.LimitedIP.Inc()
+			}
+		}
+		l.metrics.Buckets.Set(int64(l.perIP.len() + l.perKey.len()))
+
+		if !allowed {
+			l.reject(w, r, retryAfter)
+			return
+		}
+
+		l.metrics.Allowed.Inc()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reject answers a request over the limit with 429 and the seconds until a token is available
+func (l *RateLimiter) reject(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	l.logger.Warn("http request rate limited", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	_ = WriteError(w, http.StatusTooManyRequests, "rate limit exceeded")
+}
+
+// tokenBuckets is a set of token buckets with the same rate and burst, keyed by client
+type tokenBuckets struct {
+	mu        sync.Mutex
+	rate      float64 // Tokens added per second
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket holds the tokens of a client as of the last update
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newTokenBuckets(cfg config.RateLimitBucketConfig) *tokenBuckets {
+	return &tokenBuckets{
+		rate:    float64(cfg.RequestsPerMinute) / 60,
+		burst:   float64(cfg.Burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// take takes a token from the client's bucket. When the bucket is empty it returns false
+// and how long until a token is available.
+func (b *tokenBuckets) take(key string, now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Sub(b.lastSweep) >= rateLimitSweepInterval {
+		b.sweep(now)
+	}
+
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: b.burst, updated: now}
+		b.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(b.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*b.rate)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / b.rate * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// refund returns a token taken from the client's bucket
+func (b *tokenBuckets) refund(key string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if bucket, ok := b.buckets[key]; ok {
+		bucket.tokens = math.Min(b.burst, bucket.tokens+1+now.Sub(bucket.updated).Seconds()*b.rate)
+		bucket.updated = now
+	}
+}
+
+// sweep drops buckets that have refilled completely, which are the same as new buckets
+func (b *tokenBuckets) sweep(now time.Time) {
+	for key, bucket := range b.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*b.rate >= b.burst {
+			delete(b.buckets, key)
+		}
+	}
+	b.lastSweep = now
+}
+
+// len returns the number of tracked clients
+func (b *tokenBuckets) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.buckets)
+}
+
+// clientIP returns the IP address a request comes from.
+// Forwarding headers are ignored since they can be set by any client.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func newTestRateLimiter(now *time.Time) *RateLimiter {
+	limiter := NewRateLimiter(config.RateLimitConfig{
+		Enabled:   true,
+		PerIP:     config.RateLimitBucketConfig{RequestsPerMinute: 60, Burst: 2},
+		PerAPIKey: config.RateLimitBucketConfig{RequestsPerMinute: 60, Burst: 3},
+	}, logging.NewNoopLogger())
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	now := time.Now()
+	limiter := newTestRateLimiter(&now)
+	// The Authenticator in front of the limiter identifies clients with credentials
+	handler := newTestAuthenticator().Middleware(limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	send := func(remoteAddr, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/sessions", nil)
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Per-IP bucket for requests without credentials
+	assert.Equal(t, http.StatusOK, send("127.0.0.1:1000", "").Code)
+	assert.Equal(t, http.StatusOK, send("127.0.0.1:1001", "").Code)
+	w := send("127.0.0.1:1002", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// Another IP has its own bucket
+	assert.Equal(t, http.StatusOK, send("[::1]:1000", "").Code)
+
+	// Requests with an API key use the per-key bucket, whatever the IP
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, send("127.0.0.1:2000", "static-read").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("203.0.113.9:2000", "static-read").Code)
+
+	// Health checks are not limited
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	req.RemoteAddr = "127.0.0.1:1003"
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// A token is added every second
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, send("127.0.0.1:1004", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send("127.0.0.1:1005", "").Code)
+
+	assert.Equal(t, int64(7), limiter.Metrics().Allowed.Get())
+	assert.Equal(t, int64(2), limiter.Metrics().LimitedIP.Get())
+	assert.Equal(t, int64(1), limiter.Metrics().LimitedKey.Get())
+}
+
+func TestRateLimiter_IPMiddleware(t *testing.T) {
+	now := time.Now()
+	limiter := newTestRateLimiter(&now)
+	// The per-IP bucket is taken before the Authenticator, the per-key bucket after it
+	handler := limiter.IPMiddleware(newTestAuthenticator().Middleware(limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))))
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/sessions", nil)
+		req.RemoteAddr = "203.0.113.7:1000"
+		req.Header.Set(APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Requests with valid credentials count against their key, not the IP
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, send("static-read").Code)
+	}
+
+	// Guessing credentials is limited by the IP
+	assert.Equal(t, http.StatusUnauthorized, send("guess-1").Code)
+	assert.Equal(t, http.StatusUnauthorized, send("guess-2").Code)
+	w := send("guess-3")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, send("static-admin").Code, "the IP is limited whatever the credentials")
+
+	assert.Equal(t, int64(3), limiter.Metrics().Allowed.Get())
+	assert.Equal(t, int64(2), limiter.Metrics().LimitedIP.Get())
+	assert.Zero(t, limiter.Metrics().LimitedKey.Get())
+}
+
+func TestTokenBuckets_Sweep(t *testing.T) {
+	buckets := newTokenBuckets(config.RateLimitBucketConfig{RequestsPerMinute: 60, Burst: 5})
+	now := time.Now()
+
+	buckets.take("a", now)
+	buckets.take("b", now)
+	assert.Equal(t, 2, buckets.len())
+
+	// Both buckets have refilled by the next sweep
+	buckets.take("c", now.Add(rateLimitSweepInterval))
+	assert.Equal(t, 1, buckets.len())
+}
@@ -2,11 +2,30 @@ package http
 
 import (
 	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 )
 
+// Route is a registered route and its API documentation
+type Route struct {
+	Method  string
+	Path    string
+	Handler string // Handler method name, used as the OpenAPI operation ID
+	Doc     RouteDoc
+}
+
+// Describe attaches API documentation to the route
+func (rt *Route) Describe(doc RouteDoc) *Route {
+	rt.Doc = doc
+	return rt
+}
+
 // Router wraps http.ServeMux with handler registration
 type Router struct {
-	mux *http.ServeMux
+	mux    *http.ServeMux
+	routes []*Route
 }
 
 // NewRouter creates a new Router
@@ -16,13 +35,58 @@ func NewRouter() *Router {
 	}
 }
 
-// HandleFunc registers a handler for the given pattern
-func (r *Router) HandleFunc(pattern string, handler Handler) {
-	r.mux.Handle(pattern, NewHandlerAdapter(handler, nil, nil))
+// HandleFunc registers a handler for the given pattern.
+// The returned route can be described for the OpenAPI specification.
+func (r *Router) HandleFunc(pattern string, handler Handler) *Route {
+	method, path := splitPattern(pattern)
+	return r.handle(method, path, NewHandlerAdapter(handler, nil, nil), handlerName(handler))
+}
+
+// Handle registers a plain http.Handler for the given pattern.
+// Such routes serve non-API content, such as static files, and are not part of the OpenAPI specification.
+func (r *Router) Handle(pattern string, handler http.Handler) {
+	r.mux.Handle(pattern, handler)
+}
+
+// Group creates a route group whose patterns are relative to prefix
+// and whose handlers are wrapped with the middleware
+func (r *Router) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		router:     r,
+		prefix:     strings.TrimSuffix(prefix, "/"),
+		middleware: middleware,
+	}
+}
+
+// handle registers an API route served by handler
+func (r *Router) handle(method, path string, handler http.Handler, name string) *Route {
+	pattern := path
+	if method != "" {
+		pattern = method + " " + path
+	}
+	r.mux.Handle(pattern, handler)
+
+	route := &Route{
+		Method:  method,
+		Path:    path,
+		Handler: name,
+	}
+	r.routes = append(r.routes, route)
+	return route
+}
+
+// Routes returns the registered routes in registration order
+func (r *Router) Routes() []*Route {
+	return r.routes
 }
 
-// ServeHTTP implements http.Handler interface
+// ServeHTTP implements http.Handler interface.
+// Requests that match no route get the JSON error envelope instead of a plain text error.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if _, pattern := r.mux.Handler(req); pattern == "" {
+		r.mux.ServeHTTP(&envelopeWriter{ResponseWriter: w}, req)
+		return
+	}
 	r.mux.ServeHTTP(w, req)
 }
 
@@ -30,3 +94,77 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 func (r *Router) Handler() http.Handler {
 	return r.mux
 }
+
+// Group registers routes under a common path prefix with shared middleware.
+// Patterns are relative to the prefix, e.g. "GET /{id}" in the "/sessions" group
+// registers "GET /sessions/{id}"; the path "/" registers the prefix itself.
+// Path parameters of the prefix, such as {id} in "/sessions/{id}", are read
+// with r.PathValue like those of the pattern.
+type Group struct {
+	router     *Router
+	prefix     string
+	middleware []Middleware
+}
+
+// Group creates a nested group under the prefix of g.
+// Its handlers are wrapped with the middleware of g, then with its own.
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     g.prefix + strings.TrimSuffix(prefix, "/"),
+		middleware: append(slices.Clip(g.middleware), middleware...),
+	}
+}
+
+// HandleFunc registers a handler for the given pattern relative to the prefix of the group.
+// The returned route can be described for the OpenAPI specification.
+func (g *Group) HandleFunc(pattern string, handler Handler) *Route {
+	method, path := splitPattern(pattern)
+	return g.router.handle(method, g.path(path), g.wrap(NewHandlerAdapter(handler, nil, nil)), handlerName(handler))
+}
+
+// Handle registers a plain http.Handler for the given pattern relative to the prefix of the group.
+// Like Router.Handle, such routes are not part of the OpenAPI specification.
+func (g *Group) Handle(pattern string, handler http.Handler) {
+	method, path := splitPattern(pattern)
+	if method != "" {
+		method += " "
+	}
+	g.router.Handle(method+g.path(path), g.wrap(handler))
+}
+
+// path returns the full path of a path relative to the group
+func (g *Group) path(path string) string {
+	if path == "/" && g.prefix != "" {
+		return g.prefix
+	}
+	return g.prefix + path
+}
+
+// wrap wraps a handler with the middleware of the group, the first middleware being the outermost
+func (g *Group) wrap(handler http.Handler) http.Handler {
+	for i := len(g.middleware) - 1; i >= 0; i-- {
+		handler = g.middleware[i](handler)
+	}
+	return handler
+}
+
+// splitPattern splits a pattern such as "GET /users/{id}" into its method and path
+func splitPattern(pattern string) (method, path string) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return "", pattern
+	}
+	return method, path
+}
+
+// handlerName returns the method name of a handler method value, e.g. "CreateUser"
+func handlerName(handler Handler) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.TrimSuffix(name, "-fm")
+}
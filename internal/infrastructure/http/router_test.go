@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Group(t *testing.T) {
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	echo := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return WriteJSON(w, http.StatusOK, map[string]string{"id": r.PathValue("id"), "key": r.PathValue("key")})
+	}
+
+	router := NewRouter()
+	sessions := router.Group("/api/sessions/", tag("api"))
+	session := sessions.Group("/{id}", tag("session"))
+	sessions.HandleFunc("GET /", echo)
+	session.HandleFunc("GET /messages", echo)
+	session.HandleFunc("PUT /attributes/{key}", echo)
+	router.HandleFunc("GET /healthz", echo)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		calls = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("registers full paths", func(t *testing.T) {
+		var patterns []string
+		for _, route := range router.Routes() {
+			patterns = append(patterns, route.Method+" "+route.Path)
+		}
+		assert.Equal(t, []string{
+			"GET /api/sessions",
+			"GET /api/sessions/{id}/messages",
+			"PUT /api/sessions/{id}/attributes/{key}",
+			"GET /healthz",
+		}, patterns)
+	})
+
+	t.Run("path parameters of the prefix and the pattern", func(t *testing.T) {
+		w := serve("PUT", "/api/sessions/s-1/attributes/lang")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":"s-1","key":"lang"}`, w.Body.String())
+	})
+
+	t.Run("middleware of nested groups", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("GET", "/api/sessions/s-1/messages").Code)
+		assert.Equal(t, []string{"api", "session"}, calls)
+
+		assert.Equal(t, http.StatusOK, serve("GET", "/api/sessions").Code)
+		assert.Equal(t, []string{"api"}, calls)
+
+		assert.Equal(t, http.StatusOK, serve("GET", "/healthz").Code)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		w := serve("POST", "/api/sessions/s-1/messages")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+		assert.Equal(t, ErrCodeMethodNotAllowed, decodeErrorResponse(t, w).Code)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("prefix is not a subtree", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve("GET", "/api/sessions/s-1/unknown").Code)
+	})
+}
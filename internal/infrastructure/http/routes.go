@@ -0,0 +1,66 @@
+package http
+
+// Handlers groups the handlers of the HTTP API.
+// Handlers may be nil when routes are only registered to generate the OpenAPI specification.
+type Handlers struct {
+	User         *UserHandler
+	UserPrefs    *UserPreferencesHandler
+	UserData     *UserDataHandler
+	Session      *SessionHandler
+	SessionState *SessionStateHandler
+	Handoff      *HandoffHandler
+	Message      *MessageHandler
+	MessageJob   *MessageJobHandler
+	ChatWS       *ChatWSHandler
+	Task         *TaskHandler
+	Skill        *SkillHandler
+	Schedule     *ScheduleHandler
+	Log          *LogHandler
+	Backup       *BackupHandler
+	APIKey       *APIKeyHandler
+	Workspace    *WorkspaceHandler
+	Admin        *AdminHandler
+	Events       *EventsHandler
+	Logging      *LoggingHandler
+	Webhook      *WebhookHandler
+	Analytics    *AnalyticsHandler
+	Feedback     *FeedbackHandler
+	Usage        *UsageHandler
+	Crash        *CrashReportHandler
+	Health       *HealthHandler
+	Metrics      *MetricsHandler
+	Version      *VersionHandler
+}
+
+// RegisterRoutes registers all HTTP API routes, the OpenAPI specification, the Swagger UI and the admin dashboard
+func RegisterRoutes(r *Router, h Handlers) {
+	RegisterUserRoutes(r, h.User)
+	RegisterUserPreferencesRoutes(r, h.UserPrefs)
+	RegisterUserDataRoutes(r, h.UserData)
+	RegisterSessionRoutes(r, h.Session)
+	RegisterSessionStateRoutes(r, h.SessionState)
+	RegisterHandoffRoutes(r, h.Handoff)
+	RegisterMessageRoutes(r, h.Message)
+	RegisterMessageJobRoutes(r, h.MessageJob)
+	RegisterChatWSRoutes(r, h.ChatWS)
+	RegisterTaskRoutes(r, h.Task)
+	RegisterSkillRoutes(r, h.Skill)
+	RegisterScheduleRoutes(r, h.Schedule)
+	RegisterLogRoutes(r, h.Log)
+	RegisterBackupRoutes(r, h.Backup)
+	RegisterAPIKeyRoutes(r, h.APIKey)
+	RegisterWorkspaceRoutes(r, h.Workspace)
+	RegisterAdminRoutes(r, h.Admin)
+	RegisterEventsRoutes(r, h.Events)
+	RegisterLoggingRoutes(r, h.Logging)
+	RegisterWebhookRoutes(r, h.Webhook)
+	RegisterAnalyticsRoutes(r, h.Analytics)
+	RegisterFeedbackRoutes(r, h.Feedback)
+	RegisterUsageRoutes(r, h.Usage)
+	RegisterCrashReportRoutes(r, h.Crash)
+	RegisterHealthRoutes(r, h.Health)
+	RegisterMetricsRoutes(r, h.Metrics)
+	RegisterVersionRoutes(r, h.Version)
+	RegisterOpenAPIRoutes(r)
+	RegisterDashboardRoutes(r)
+}
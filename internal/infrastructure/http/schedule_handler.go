@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
 	"github.com/atumaikin/nexflow/internal/application/usecase"
@@ -33,14 +34,14 @@ func (h *ScheduleHandler) CreateSchedule(ctx context.Context, w http.ResponseWri
 	}
 
 	// Validate request
-	if req.Skill == "" || req.CronExpression == "" {
-		return WriteError(w, http.StatusBadRequest, "skill and cron_expression are required")
+	if !validateRequest(w, &req) {
+		return nil
 	}
 
 	resp, err := h.scheduleUseCase.CreateSchedule(ctx, req)
 	if err != nil {
 		h.logger.Error("failed to create schedule", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -60,7 +61,7 @@ func (h *ScheduleHandler) GetScheduleByID(ctx context.Context, w http.ResponseWr
 	resp, err := h.scheduleUseCase.GetScheduleByID(ctx, id)
 	if err != nil {
 		h.logger.Error("failed to get schedule", "error", err, "schedule_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -70,45 +71,29 @@ func (h *ScheduleHandler) GetScheduleByID(ctx context.Context, w http.ResponseWr
 	return WriteJSON(w, http.StatusOK, resp)
 }
 
-// ListSchedules handles GET /schedules
+// ListSchedules handles GET /schedules?enabled=true&skill=<name>
 func (h *ScheduleHandler) ListSchedules(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	enabled := r.URL.Query().Get("enabled")
+	skill := r.URL.Query().Get("skill")
 	var resp *dto.SchedulesResponse
 	var err error
 
-	if enabled == "true" {
+	switch {
+	case skill != "":
+		resp, err = h.scheduleUseCase.GetSchedulesBySkill(ctx, skill)
+	case enabled == "true":
 		resp, err = h.scheduleUseCase.ListEnabledSchedules(ctx)
-	} else {
+	default:
 		resp, err = h.scheduleUseCase.ListSchedules(ctx)
 	}
 
 	if err != nil {
 		h.logger.Error("failed to list schedules", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
-	}
-
-	return WriteJSON(w, http.StatusOK, resp)
-}
-
-// GetSchedulesBySkill handles GET /skills/{skill}/schedules
-func (h *ScheduleHandler) GetSchedulesBySkill(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	skill := r.PathValue("skill")
-	if skill == "" {
-		return WriteError(w, http.StatusBadRequest, "skill name is required")
-	}
-
-	resp, err := h.scheduleUseCase.GetSchedulesBySkill(ctx, skill)
-	if err != nil {
-		h.logger.Error("failed to get schedules by skill", "error", err, "skill", skill)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
-	}
-
-	if !resp.Success {
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusOK, resp)
@@ -127,10 +112,15 @@ func (h *ScheduleHandler) UpdateSchedule(ctx context.Context, w http.ResponseWri
 		return WriteError(w, http.StatusBadRequest, "invalid request body")
 	}
 
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
 	resp, err := h.scheduleUseCase.UpdateSchedule(ctx, id, req)
 	if err != nil {
 		h.logger.Error("failed to update schedule", "error", err, "schedule_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -156,7 +146,7 @@ func (h *ScheduleHandler) ToggleSchedule(ctx context.Context, w http.ResponseWri
 	resp, err := h.scheduleUseCase.ToggleSchedule(ctx, id, req)
 	if err != nil {
 		h.logger.Error("failed to toggle schedule", "error", err, "schedule_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -176,7 +166,7 @@ func (h *ScheduleHandler) EnableSchedule(ctx context.Context, w http.ResponseWri
 	resp, err := h.scheduleUseCase.EnableSchedule(ctx, id)
 	if err != nil {
 		h.logger.Error("failed to enable schedule", "error", err, "schedule_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -196,7 +186,27 @@ func (h *ScheduleHandler) DisableSchedule(ctx context.Context, w http.ResponseWr
 	resp, err := h.scheduleUseCase.DisableSchedule(ctx, id)
 	if err != nil {
 		h.logger.Error("failed to disable schedule", "error", err, "schedule_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// PauseSchedule handles POST /schedules/{id}/pause
+func (h *ScheduleHandler) PauseSchedule(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "schedule id is required")
+	}
+
+	resp, err := h.scheduleUseCase.PauseSchedule(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to pause schedule", "error", err, "schedule_id", id)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -206,14 +216,130 @@ func (h *ScheduleHandler) DisableSchedule(ctx context.Context, w http.ResponseWr
 	return WriteJSON(w, http.StatusOK, resp)
 }
 
+// ResumeSchedule handles POST /schedules/{id}/resume
+func (h *ScheduleHandler) ResumeSchedule(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "schedule id is required")
+	}
+
+	resp, err := h.scheduleUseCase.ResumeSchedule(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to resume schedule", "error", err, "schedule_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RunScheduleNow handles POST /schedules/{id}/run-now
+func (h *ScheduleHandler) RunScheduleNow(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "schedule id is required")
+	}
+
+	resp, err := h.scheduleUseCase.RunScheduleNow(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to run schedule", "error", err, "schedule_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetScheduleRuns handles GET /schedules/{id}/runs
+func (h *ScheduleHandler) GetScheduleRuns(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "schedule id is required")
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return WriteError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+		}
+		limit = n
+	}
+
+	resp, err := h.scheduleUseCase.GetScheduleRuns(ctx, id, limit)
+	if err != nil {
+		h.logger.Error("failed to get schedule runs", "error", err, "schedule_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
 // RegisterScheduleRoutes registers schedule routes
 func RegisterScheduleRoutes(r *Router, handler *ScheduleHandler) {
-	r.HandleFunc("POST /schedules", handler.CreateSchedule)
-	r.HandleFunc("GET /schedules", handler.ListSchedules)
-	r.HandleFunc("GET /schedules/{id}", handler.GetScheduleByID)
-	r.HandleFunc("GET /skills/{skill}/schedules", handler.GetSchedulesBySkill)
-	r.HandleFunc("PUT /schedules/{id}", handler.UpdateSchedule)
-	r.HandleFunc("POST /schedules/{id}/toggle", handler.ToggleSchedule)
-	r.HandleFunc("POST /schedules/{id}/enable", handler.EnableSchedule)
-	r.HandleFunc("POST /schedules/{id}/disable", handler.DisableSchedule)
+	schedules := r.Group("/schedules")
+	schedule := schedules.Group("/{id}")
+	schedules.HandleFunc("POST /", handler.CreateSchedule).Describe(RouteDoc{
+		Summary:  "Create a schedule",
+		Request:  dto.CreateScheduleRequest{},
+		Response: dto.ScheduleResponse{},
+		Status:   http.StatusCreated,
+	})
+	schedules.HandleFunc("GET /", handler.ListSchedules).Describe(RouteDoc{
+		Summary:  "List schedules",
+		Response: dto.SchedulesResponse{},
+		Query: []QueryParam{
+			{Name: "enabled", Description: "true to list only enabled schedules"},
+			{Name: "skill", Description: "Only schedules of this skill; takes precedence over enabled"},
+		},
+	})
+	schedule.HandleFunc("GET /", handler.GetScheduleByID).Describe(RouteDoc{
+		Summary:  "Get a schedule by ID",
+		Response: dto.ScheduleResponse{},
+	})
+	schedule.HandleFunc("GET /runs", handler.GetScheduleRuns).Describe(RouteDoc{
+		Summary:  "List the run history of a schedule",
+		Response: dto.ScheduleRunsResponse{},
+		Query:    []QueryParam{{Name: "limit", Description: "Maximum number of runs to return"}},
+	})
+	schedule.HandleFunc("PUT /", handler.UpdateSchedule).Describe(RouteDoc{
+		Summary:  "Update a schedule",
+		Request:  dto.UpdateScheduleRequest{},
+		Response: dto.ScheduleResponse{},
+	})
+	schedule.HandleFunc("POST /toggle", handler.ToggleSchedule).Describe(RouteDoc{
+		Summary:  "Enable or disable a schedule",
+		Request:  dto.ToggleScheduleRequest{},
+		Response: dto.ScheduleResponse{},
+	})
+	schedule.HandleFunc("POST /enable", handler.EnableSchedule).Describe(RouteDoc{
+		Summary:  "Enable a schedule",
+		Response: dto.ScheduleResponse{},
+	})
+	schedule.HandleFunc("POST /disable", handler.DisableSchedule).Describe(RouteDoc{
+		Summary:  "Disable a schedule",
+		Response: dto.ScheduleResponse{},
+	})
+	schedule.HandleFunc("POST /pause", handler.PauseSchedule).Describe(RouteDoc{
+		Summary:  "Pause a schedule",
+		Response: dto.ScheduleResponse{},
+	})
+	schedule.HandleFunc("POST /resume", handler.ResumeSchedule).Describe(RouteDoc{
+		Summary:  "Resume a paused schedule",
+		Response: dto.ScheduleResponse{},
+	})
+	schedule.HandleFunc("POST /run-now", handler.RunScheduleNow).Describe(RouteDoc{
+		Summary:  "Run a schedule immediately",
+		Response: dto.ScheduleResponse{},
+	})
 }
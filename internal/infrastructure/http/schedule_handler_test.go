@@ -0,0 +1,151 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// stubScheduleRepository keeps schedules in memory
+type stubScheduleRepository struct {
+	schedules map[string]*entity.Schedule
+}
+
+func (s *stubScheduleRepository) Create(ctx context.Context, schedule *entity.Schedule) error {
+	s.schedules[string(schedule.ID)] = schedule
+	return nil
+}
+
+func (s *stubScheduleRepository) FindByID(ctx context.Context, id string) (*entity.Schedule, error) {
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: schedule %s", repository.ErrNotFound, id)
+	}
+	return schedule, nil
+}
+
+func (s *stubScheduleRepository) FindBySkill(ctx context.Context, skill string) ([]*entity.Schedule, error) {
+	return nil, nil
+}
+
+func (s *stubScheduleRepository) List(ctx context.Context) ([]*entity.Schedule, error) {
+	return nil, nil
+}
+
+func (s *stubScheduleRepository) Update(ctx context.Context, schedule *entity.Schedule) error {
+	s.schedules[string(schedule.ID)] = schedule
+	return nil
+}
+
+func (s *stubScheduleRepository) Delete(ctx context.Context, id string) error {
+	delete(s.schedules, id)
+	return nil
+}
+
+func (s *stubScheduleRepository) FindEnabled(ctx context.Context) ([]*entity.Schedule, error) {
+	return nil, nil
+}
+
+// stubRunScheduler is a ports.Scheduler whose on-demand runs fail with err
+type stubRunScheduler struct {
+	err error
+}
+
+func (s *stubRunScheduler) Start() error { return nil }
+
+func (s *stubRunScheduler) Stop() error { return nil }
+
+func (s *stubRunScheduler) Reload() {}
+
+func (s *stubRunScheduler) RunNow(ctx context.Context, scheduleID string) error { return s.err }
+
+// newScheduleTestRouter returns a router with the schedule routes and a paused schedule
+func newScheduleTestRouter(scheduler *stubRunScheduler) (*Router, *entity.Schedule) {
+	schedule := entity.NewSchedule("backup", "0 3 * * *", "{}")
+	schedule.Disable()
+	repo := &stubScheduleRepository{schedules: map[string]*entity.Schedule{string(schedule.ID): schedule}}
+
+	uc := usecase.NewScheduleUseCase(repo, nil, logging.NewNoopLogger())
+	if scheduler != nil {
+		uc.SetScheduler(scheduler)
+	}
+
+	router := NewRouter()
+	RegisterScheduleRoutes(router, NewScheduleHandler(uc, logging.NewNoopLogger()))
+	return router, schedule
+}
+
+func TestScheduleHandler_PauseResume(t *testing.T) {
+	router, schedule := newScheduleTestRouter(&stubRunScheduler{})
+
+	tests := []struct {
+		path        string
+		wantStatus  int
+		wantEnabled bool
+	}{
+		{"/schedules/" + string(schedule.ID) + "/pause", http.StatusOK, false},
+		{"/schedules/" + string(schedule.ID) + "/resume", http.StatusOK, true},
+		{"/schedules/" + string(schedule.ID) + "/resume", http.StatusOK, true},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", tt.path, nil))
+
+		require.Equal(t, tt.wantStatus, w.Code, tt.path)
+		var resp dto.ScheduleResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, tt.wantEnabled, resp.Schedule.Enabled, tt.path)
+	}
+
+	for _, action := range []string{"pause", "resume", "run-now"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/schedules/missing/"+action, nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code, action)
+		assert.Equal(t, ErrCodeNotFound, decodeErrorResponse(t, w).Code, action)
+	}
+}
+
+func TestScheduleHandler_RunScheduleNow(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheduler  *stubRunScheduler
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{name: "paused schedule", scheduler: &stubRunScheduler{}, wantStatus: http.StatusOK},
+		{name: "scheduler disabled", wantStatus: http.StatusServiceUnavailable, wantCode: ErrCodeUnavailable},
+		{name: "scheduler error", scheduler: &stubRunScheduler{err: errors.New("schedule is already running")}, wantStatus: http.StatusInternalServerError, wantCode: ErrCodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, schedule := newScheduleTestRouter(tt.scheduler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/schedules/"+string(schedule.ID)+"/run-now", nil))
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantCode != "" {
+				assert.Equal(t, tt.wantCode, decodeErrorResponse(t, w).Code)
+				return
+			}
+			var resp dto.ScheduleResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.True(t, resp.Success)
+			assert.False(t, resp.Schedule.Enabled, "running a paused schedule should not resume it")
+		})
+	}
+}
@@ -2,16 +2,24 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"time"
+
+	"golang.org/x/crypto/acme/autocert"
 )
 
 // Server represents HTTP server
 type Server struct {
-	httpServer  *http.Server
-	middlewares []Middleware
+	httpServer     *http.Server
+	redirectServer *http.Server
+	certFile       string
+	keyFile        string
+	autocert       *autocert.Manager
+	middlewares    []Middleware
 }
 
 // ServerConfig holds configuration for HTTP server
@@ -21,11 +29,25 @@ type ServerConfig struct {
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
 	Handler      http.Handler
+
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// RedirectAddr is the address of a plain HTTP server redirecting to HTTPS (empty = disabled).
+	// With autocert it also answers the ACME HTTP-01 challenge.
+	RedirectAddr string
+
+	// AutocertDomains enables HTTPS with certificates obtained from Let's Encrypt for these host
+	// names, kept in AutocertCacheDir, instead of TLSCertFile and TLSKeyFile
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
 }
 
 // NewServer creates a new HTTP server
 func NewServer(cfg *ServerConfig) *Server {
-	return &Server{
+	s := &Server{
 		httpServer: &http.Server{
 			Addr:         cfg.Addr,
 			Handler:      cfg.Handler,
@@ -33,22 +55,77 @@ func NewServer(cfg *ServerConfig) *Server {
 			WriteTimeout: cfg.WriteTimeout,
 			IdleTimeout:  cfg.IdleTimeout,
 		},
+		certFile:    cfg.TLSCertFile,
+		keyFile:     cfg.TLSKeyFile,
 		middlewares: make([]Middleware, 0),
 	}
+
+	if len(cfg.AutocertDomains) > 0 {
+		s.autocert = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+			Email:      cfg.AutocertEmail,
+		}
+	}
+
+	if s.TLS() {
+		s.httpServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		redirect := httpsRedirect(cfg.Addr)
+		if s.autocert != nil {
+			// The autocert TLS config also answers the TLS-ALPN-01 challenge on the HTTPS listener,
+			// and the redirect listener the HTTP-01 challenge
+			s.httpServer.TLSConfig = s.autocert.TLSConfig()
+			s.httpServer.TLSConfig.MinVersion = tls.VersionTLS12
+			redirect = s.autocert.HTTPHandler(redirect)
+		}
+
+		if cfg.RedirectAddr != "" {
+			s.redirectServer = &http.Server{
+				Addr:         cfg.RedirectAddr,
+				Handler:      redirect,
+				ReadTimeout:  cfg.ReadTimeout,
+				WriteTimeout: cfg.WriteTimeout,
+				IdleTimeout:  cfg.IdleTimeout,
+			}
+		}
+	}
+
+	return s
 }
 
 // Start starts HTTP server
 func (s *Server) Start(ctx context.Context) error {
-	log.Printf("Starting HTTP server on %s", s.httpServer.Addr)
-
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 2)
 
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		switch {
+		case s.autocert != nil:
+			log.Printf("Starting HTTPS server on %s with automatic certificates", s.httpServer.Addr)
+			// The certificates come from the TLS config
+			err = s.httpServer.ListenAndServeTLS("", "")
+		case s.TLS():
+			log.Printf("Starting HTTPS server on %s", s.httpServer.Addr)
+			err = s.httpServer.ListenAndServeTLS(s.certFile, s.keyFile)
+		default:
+			log.Printf("Starting HTTP server on %s", s.httpServer.Addr)
+			err = s.httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			errChan <- fmt.Errorf("failed to start HTTP server: %w", err)
 		}
 	}()
 
+	if s.redirectServer != nil {
+		go func() {
+			log.Printf("Starting HTTP to HTTPS redirect on %s", s.redirectServer.Addr)
+			if err := s.redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("failed to start HTTPS redirect server: %w", err)
+			}
+		}()
+	}
+
 	select {
 	case err := <-errChan:
 		return err
@@ -61,6 +138,12 @@ func (s *Server) Start(ctx context.Context) error {
 func (s *Server) Shutdown(ctx context.Context) error {
 	log.Println("Shutting down HTTP server")
 
+	if s.redirectServer != nil {
+		if err := s.redirectServer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shutdown HTTPS redirect server: %w", err)
+		}
+	}
+
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown HTTP server: %w", err)
 	}
@@ -78,3 +161,35 @@ func (s *Server) Use(middleware Middleware) {
 func (s *Server) Addr() string {
 	return s.httpServer.Addr
 }
+
+// TLS reports whether the server serves HTTPS
+func (s *Server) TLS() bool {
+	return (s.certFile != "" && s.keyFile != "") || s.autocert != nil
+}
+
+// GetCertificate returns the certificates obtained by autocert, e.g. for another TLS listener,
+// or nil when autocert is not used
+func (s *Server) GetCertificate() func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if s.autocert == nil {
+		return nil
+	}
+	return s.autocert.GetCertificate
+}
+
+// httpsRedirect redirects requests to the same host and path over HTTPS on the port of httpsAddr
+func httpsRedirect(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -98,3 +99,67 @@ func TestServer_Shutdown(t *testing.T) {
 	// Cancel start context
 	cancel()
 }
+
+func TestNewServer_TLS(t *testing.T) {
+	server := NewServer(&ServerConfig{Addr: ":8443", TLSCertFile: "cert.pem", TLSKeyFile: "key.pem", RedirectAddr: ":8080"})
+
+	assert.True(t, server.TLS())
+	require.NotNil(t, server.redirectServer)
+	assert.Equal(t, ":8080", server.redirectServer.Addr)
+
+	// Without TLS there is nothing to redirect to
+	server = NewServer(&ServerConfig{Addr: ":8080", RedirectAddr: ":8081"})
+
+	assert.False(t, server.TLS())
+	assert.Nil(t, server.redirectServer)
+}
+
+func TestNewServer_Autocert(t *testing.T) {
+	server := NewServer(&ServerConfig{
+		Addr:             ":443",
+		RedirectAddr:     ":80",
+		AutocertDomains:  []string{"example.com"},
+		AutocertCacheDir: t.TempDir(),
+	})
+
+	assert.True(t, server.TLS())
+	assert.NotNil(t, server.GetCertificate())
+	assert.Contains(t, server.httpServer.TLSConfig.NextProtos, "acme-tls/1", "the HTTPS listener should answer TLS-ALPN-01 challenges")
+	require.NotNil(t, server.redirectServer)
+
+	// The redirect listener answers HTTP-01 challenges and redirects everything else
+	w := httptest.NewRecorder()
+	server.redirectServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example.com/.well-known/acme-challenge/token", nil))
+	assert.NotEqual(t, http.StatusPermanentRedirect, w.Code)
+
+	w = httptest.NewRecorder()
+	server.redirectServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example.com/healthz", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "https://example.com/healthz", w.Header().Get("Location"))
+
+	assert.Nil(t, NewServer(&ServerConfig{Addr: ":8080"}).GetCertificate())
+}
+
+func TestHTTPSRedirect(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsAddr string
+		target    string
+		want      string
+	}{
+		{name: "default port", httpsAddr: ":443", target: "http://example.com/sessions?limit=5", want: "https://example.com/sessions?limit=5"},
+		{name: "custom port", httpsAddr: "0.0.0.0:8443", target: "http://example.com:8080/healthz", want: "https://example.com:8443/healthz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			httpsRedirect(tt.httpsAddr).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Location"))
+		})
+	}
+}
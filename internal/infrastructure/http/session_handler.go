@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"mime"
 	"net/http"
 
 	"github.com/atumaikin/nexflow/internal/application/dto"
@@ -33,14 +34,14 @@ func (h *SessionHandler) CreateSession(ctx context.Context, w http.ResponseWrite
 	}
 
 	// Validate request
-	if req.UserID == "" {
-		return WriteError(w, http.StatusBadRequest, "user_id is required")
+	if !validateRequest(w, &req) {
+		return nil
 	}
 
 	resp, err := h.chatUseCase.CreateSession(ctx, req)
 	if err != nil {
 		h.logger.Error("failed to create session", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -50,6 +51,26 @@ func (h *SessionHandler) CreateSession(ctx context.Context, w http.ResponseWrite
 	return WriteJSON(w, http.StatusCreated, resp)
 }
 
+// ListSessions handles GET /sessions
+func (h *SessionHandler) ListSessions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.chatUseCase.ListSessions(ctx, page)
+	if err != nil {
+		h.logger.Error("failed to list sessions", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSONWithETag(w, r, http.StatusOK, resp)
+}
+
 // GetUserSessions handles GET /users/{id}/sessions
 func (h *SessionHandler) GetUserSessions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	userID := r.PathValue("id")
@@ -57,21 +78,141 @@ func (h *SessionHandler) GetUserSessions(ctx context.Context, w http.ResponseWri
 		return WriteError(w, http.StatusBadRequest, "user id is required")
 	}
 
-	resp, err := h.chatUseCase.GetUserSessions(ctx, userID)
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.chatUseCase.GetUserSessions(ctx, userID, page)
 	if err != nil {
 		h.logger.Error("failed to get user sessions", "error", err, "user_id", userID)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSONWithETag(w, r, http.StatusOK, resp)
+}
+
+// PinSession handles POST /sessions/{id}/pin
+func (h *SessionHandler) PinSession(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setPinned(ctx, w, r, true)
+}
+
+// UnpinSession handles POST /sessions/{id}/unpin
+func (h *SessionHandler) UnpinSession(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setPinned(ctx, w, r, false)
+}
+
+func (h *SessionHandler) setPinned(ctx context.Context, w http.ResponseWriter, r *http.Request, pinned bool) error {
+	sessionID := r.PathValue("id")
+	if sessionID == "" {
+		return WriteError(w, http.StatusBadRequest, "session id is required")
+	}
+
+	resp, err := h.chatUseCase.SetSessionPinned(ctx, sessionID, pinned)
+	if err != nil {
+		h.logger.Error("failed to update session pin", "error", err, "session_id", sessionID, "pinned", pinned)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return WriteError(w, http.StatusBadRequest, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusOK, resp)
 }
 
+// ForkSession handles POST /sessions/{id}/fork?from_message=<message id>.
+// The new session holds the history up to the message, or all of it without from_message.
+func (h *SessionHandler) ForkSession(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+	if sessionID == "" {
+		return WriteError(w, http.StatusBadRequest, "session id is required")
+	}
+
+	resp, err := h.chatUseCase.ForkSession(ctx, sessionID, r.URL.Query().Get("from_message"))
+	if err != nil {
+		h.logger.Error("failed to fork session", "error", err, "session_id", sessionID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusCreated, resp)
+}
+
+// ExportSession handles GET /sessions/{id}/export?format=json|markdown.
+// The transcript is returned as a file download.
+func (h *SessionHandler) ExportSession(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+	if sessionID == "" {
+		return WriteError(w, http.StatusBadRequest, "session id is required")
+	}
+
+	resp, err := h.chatUseCase.ExportSession(ctx, sessionID, r.URL.Query().Get("format"))
+	if err != nil {
+		h.logger.Error("failed to export session", "error", err, "session_id", sessionID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	w.Header().Set("Content-Type", resp.Export.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": resp.Export.FileName}))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resp.Export.Content)
+	return err
+}
+
 // RegisterSessionRoutes registers session routes
 func RegisterSessionRoutes(r *Router, handler *SessionHandler) {
-	r.HandleFunc("POST /sessions", handler.CreateSession)
-	r.HandleFunc("GET /users/{id}/sessions", handler.GetUserSessions)
+	sessions := r.Group("/sessions")
+	session := sessions.Group("/{id}")
+	user := r.Group("/users/{id}")
+	sessions.HandleFunc("POST /", handler.CreateSession).Describe(RouteDoc{
+		Summary:    "Create a session",
+		Request:    dto.CreateSessionRequest{},
+		Response:   dto.SessionResponse{},
+		Status:     http.StatusCreated,
+		Idempotent: true,
+	})
+	sessions.HandleFunc("GET /", handler.ListSessions).Describe(RouteDoc{
+		Summary:   "List the sessions of all users",
+		Response:  dto.SessionsResponse{},
+		Paginated: true,
+		ETag:      true,
+	})
+	session.HandleFunc("POST /pin", handler.PinSession).Describe(RouteDoc{
+		Summary:  "Pin a session to protect it from data retention",
+		Response: dto.SessionResponse{},
+	})
+	session.HandleFunc("POST /unpin", handler.UnpinSession).Describe(RouteDoc{
+		Summary:  "Unpin a session",
+		Response: dto.SessionResponse{},
+	})
+	session.HandleFunc("POST /fork", handler.ForkSession).Describe(RouteDoc{
+		Summary:  "Fork a session with its history up to a message",
+		Query:    []QueryParam{{Name: "from_message", Description: "ID of the last message to copy; the whole history without it"}},
+		Response: dto.SessionResponse{},
+		Status:   http.StatusCreated,
+	})
+	session.HandleFunc("GET /export", handler.ExportSession).Describe(RouteDoc{
+		Summary:  "Export a session transcript",
+		Query:    []QueryParam{{Name: "format", Description: "json (default) or markdown"}},
+		Produces: []string{"application/json", "text/markdown"},
+	})
+	user.HandleFunc("GET /sessions", handler.GetUserSessions).Describe(RouteDoc{
+		Summary:   "List the sessions of a user",
+		Tag:       "sessions",
+		Response:  dto.SessionsResponse{},
+		Paginated: true,
+		ETag:      true,
+	})
 }
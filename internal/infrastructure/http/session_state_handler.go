@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// SessionStateHandler handles session attribute HTTP requests
+type SessionStateHandler struct {
+	sessionStateUseCase *usecase.SessionStateUseCase
+	logger              logging.Logger
+}
+
+// NewSessionStateHandler creates a new SessionStateHandler
+func NewSessionStateHandler(sessionStateUseCase *usecase.SessionStateUseCase, logger logging.Logger) *SessionStateHandler {
+	return &SessionStateHandler{
+		sessionStateUseCase: sessionStateUseCase,
+		logger:              logger,
+	}
+}
+
+// ListAttributes handles GET /sessions/{id}/attributes
+func (h *SessionStateHandler) ListAttributes(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+	if sessionID == "" {
+		return WriteError(w, http.StatusBadRequest, "session id is required")
+	}
+
+	resp, err := h.sessionStateUseCase.ListAttributes(ctx, sessionID)
+	if err != nil {
+		h.logger.Error("failed to list session attributes", "error", err, "session_id", sessionID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetAttribute handles GET /sessions/{id}/attributes/{key}
+func (h *SessionStateHandler) GetAttribute(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+	key := r.PathValue("key")
+	if sessionID == "" || key == "" {
+		return WriteError(w, http.StatusBadRequest, "session id and key are required")
+	}
+
+	resp, err := h.sessionStateUseCase.GetAttribute(ctx, sessionID, key)
+	if err != nil {
+		h.logger.Error("failed to get session attribute", "error", err, "session_id", sessionID, "key", key)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusNotFound, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// SetAttribute handles PUT /sessions/{id}/attributes/{key}
+func (h *SessionStateHandler) SetAttribute(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+	key := r.PathValue("key")
+	if sessionID == "" || key == "" {
+		return WriteError(w, http.StatusBadRequest, "session id and key are required")
+	}
+
+	var req dto.SetSessionAttributeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode session attribute request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.sessionStateUseCase.SetAttribute(ctx, sessionID, key, req)
+	if err != nil {
+		h.logger.Error("failed to set session attribute", "error", err, "session_id", sessionID, "key", key)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// DeleteAttribute handles DELETE /sessions/{id}/attributes/{key}
+func (h *SessionStateHandler) DeleteAttribute(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	sessionID := r.PathValue("id")
+	key := r.PathValue("key")
+	if sessionID == "" || key == "" {
+		return WriteError(w, http.StatusBadRequest, "session id and key are required")
+	}
+
+	resp, err := h.sessionStateUseCase.DeleteAttribute(ctx, sessionID, key)
+	if err != nil {
+		h.logger.Error("failed to delete session attribute", "error", err, "session_id", sessionID, "key", key)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusNotFound, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterSessionStateRoutes registers session attribute routes
+func RegisterSessionStateRoutes(r *Router, handler *SessionStateHandler) {
+	attributes := r.Group("/sessions/{id}/attributes")
+	attributes.HandleFunc("GET /", handler.ListAttributes).Describe(RouteDoc{
+		Summary:  "List session attributes",
+		Response: dto.SessionAttributesResponse{},
+	})
+	attributes.HandleFunc("GET /{key}", handler.GetAttribute).Describe(RouteDoc{
+		Summary:  "Get a session attribute",
+		Response: dto.SessionAttributeResponse{},
+	})
+	attributes.HandleFunc("PUT /{key}", handler.SetAttribute).Describe(RouteDoc{
+		Summary:  "Set a session attribute",
+		Request:  dto.SetSessionAttributeRequest{},
+		Response: dto.SessionAttributeResponse{},
+	})
+	attributes.HandleFunc("DELETE /{key}", handler.DeleteAttribute).Describe(RouteDoc{
+		Summary:  "Delete a session attribute",
+		Response: dto.SessionAttributeResponse{},
+	})
+}
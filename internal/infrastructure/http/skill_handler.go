@@ -33,14 +33,14 @@ func (h *SkillHandler) CreateSkill(ctx context.Context, w http.ResponseWriter, r
 	}
 
 	// Validate request
-	if req.Name == "" || req.Version == "" || req.Location == "" {
-		return WriteError(w, http.StatusBadRequest, "name, version, and location are required")
+	if !validateRequest(w, &req) {
+		return nil
 	}
 
 	resp, err := h.skillUseCase.CreateSkill(ctx, req)
 	if err != nil {
 		h.logger.Error("failed to create skill", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -60,7 +60,7 @@ func (h *SkillHandler) GetSkillByID(ctx context.Context, w http.ResponseWriter,
 	resp, err := h.skillUseCase.GetSkillByID(ctx, id)
 	if err != nil {
 		h.logger.Error("failed to get skill", "error", err, "skill_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -80,7 +80,7 @@ func (h *SkillHandler) GetSkillByName(ctx context.Context, w http.ResponseWriter
 	resp, err := h.skillUseCase.GetSkillByName(ctx, name)
 	if err != nil {
 		h.logger.Error("failed to get skill by name", "error", err, "skill_name", name)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -95,11 +95,45 @@ func (h *SkillHandler) ListSkills(ctx context.Context, w http.ResponseWriter, r
 	resp, err := h.skillUseCase.ListSkills(ctx)
 	if err != nil {
 		h.logger.Error("failed to list skills", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSONWithETag(w, r, http.StatusOK, resp)
+}
+
+// DisableSkill handles POST /skills/{id}/disable
+func (h *SkillHandler) DisableSkill(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.toggle(ctx, w, r, false)
+}
+
+// EnableSkill handles POST /skills/{id}/enable
+func (h *SkillHandler) EnableSkill(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.toggle(ctx, w, r, true)
+}
+
+func (h *SkillHandler) toggle(ctx context.Context, w http.ResponseWriter, r *http.Request, enabled bool) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return WriteError(w, http.StatusBadRequest, "skill id is required")
+	}
+
+	toggle := h.skillUseCase.DisableSkill
+	if enabled {
+		toggle = h.skillUseCase.EnableSkill
+	}
+
+	resp, err := toggle(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to toggle skill", "error", err, "skill_id", id, "enabled", enabled)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusOK, resp)
@@ -107,8 +141,34 @@ func (h *SkillHandler) ListSkills(ctx context.Context, w http.ResponseWriter, r
 
 // RegisterSkillRoutes registers skill routes
 func RegisterSkillRoutes(r *Router, handler *SkillHandler) {
-	r.HandleFunc("POST /skills", handler.CreateSkill)
-	r.HandleFunc("GET /skills", handler.ListSkills)
-	r.HandleFunc("GET /skills/{id}", handler.GetSkillByID)
-	r.HandleFunc("GET /skills/name/{name}", handler.GetSkillByName)
+	skills := r.Group("/skills")
+	skill := skills.Group("/{id}")
+	skills.HandleFunc("POST /", handler.CreateSkill).Describe(RouteDoc{
+		Summary:  "Register a skill",
+		Request:  dto.CreateSkillRequest{},
+		Response: dto.SkillResponse{},
+		Status:   http.StatusCreated,
+	})
+	skills.HandleFunc("GET /", handler.ListSkills).Describe(RouteDoc{
+		Summary:  "List skills",
+		Response: dto.SkillsResponse{},
+		ETag:     true,
+	})
+	skill.HandleFunc("GET /", handler.GetSkillByID).Describe(RouteDoc{
+		Summary:  "Get a skill by ID",
+		Response: dto.SkillResponse{},
+	})
+	skills.HandleFunc("GET /name/{name}", handler.GetSkillByName).Describe(RouteDoc{
+		Summary:  "Get a skill by name",
+		Response: dto.SkillResponse{},
+	})
+	skill.HandleFunc("POST /disable", handler.DisableSkill).Describe(RouteDoc{
+		Summary:     "Disable a skill",
+		Description: "A disabled skill stays registered, but executing it fails with 409 until it is enabled again.",
+		Response:    dto.SkillResponse{},
+	})
+	skill.HandleFunc("POST /enable", handler.EnableSkill).Describe(RouteDoc{
+		Summary:  "Enable a disabled skill",
+		Response: dto.SkillResponse{},
+	})
 }
@@ -31,14 +31,19 @@ func (h *TaskHandler) GetSessionTasks(ctx context.Context, w http.ResponseWriter
 		return WriteError(w, http.StatusBadRequest, "session id is required")
 	}
 
-	resp, err := h.chatUseCase.GetSessionTasks(ctx, sessionID)
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.chatUseCase.GetSessionTasks(ctx, sessionID, page)
 	if err != nil {
 		h.logger.Error("failed to get session tasks", "error", err, "session_id", sessionID)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return WriteError(w, http.StatusBadRequest, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusOK, resp)
@@ -53,8 +58,8 @@ func (h *TaskHandler) ExecuteSkill(ctx context.Context, w http.ResponseWriter, r
 	}
 
 	// Validate request
-	if req.Skill == "" {
-		return WriteError(w, http.StatusBadRequest, "skill name is required")
+	if !validateRequest(w, &req) {
+		return nil
 	}
 
 	// Get session ID from query parameter
@@ -66,7 +71,7 @@ func (h *TaskHandler) ExecuteSkill(ctx context.Context, w http.ResponseWriter, r
 	resp, err := h.chatUseCase.ExecuteSkill(ctx, sessionID, req.Skill, req.Input)
 	if err != nil {
 		h.logger.Error("failed to execute skill", "error", err, "skill", req.Skill)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -76,8 +81,43 @@ func (h *TaskHandler) ExecuteSkill(ctx context.Context, w http.ResponseWriter, r
 	return WriteJSON(w, http.StatusOK, resp)
 }
 
+// CancelTask handles POST /tasks/{id}/cancel
+func (h *TaskHandler) CancelTask(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	taskID := r.PathValue("id")
+	if taskID == "" {
+		return WriteError(w, http.StatusBadRequest, "task id is required")
+	}
+
+	resp, err := h.chatUseCase.CancelTask(ctx, taskID)
+	if err != nil {
+		h.logger.Error("failed to cancel task", "error", err, "task_id", taskID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
 // RegisterTaskRoutes registers task routes
 func RegisterTaskRoutes(r *Router, handler *TaskHandler) {
-	r.HandleFunc("GET /sessions/{id}/tasks", handler.GetSessionTasks)
-	r.HandleFunc("POST /skills/execute", handler.ExecuteSkill)
+	session := r.Group("/sessions/{id}")
+	skills := r.Group("/skills")
+	tasks := r.Group("/tasks")
+	session.HandleFunc("GET /tasks", handler.GetSessionTasks).Describe(RouteDoc{
+		Summary:   "List the tasks of a session",
+		Tag:       "tasks",
+		Response:  dto.TasksResponse{},
+		Paginated: true,
+	})
+	skills.HandleFunc("POST /execute", handler.ExecuteSkill).Describe(RouteDoc{
+		Summary:    "Execute a skill in a session",
+		Tag:        "tasks",
+		Request:    dto.SkillExecutionRequest{},
+		Response:   dto.SkillExecutionResponse{},
+		Query:      []QueryParam{{Name: "session_id", Description: "Session to execute the skill in", Required: true}},
+		Idempotent: true,
+	})
+	tasks.HandleFunc("POST /{id}/cancel", handler.CancelTask).Describe(RouteDoc{
+		Summary:  "Cancel a pending or running task",
+		Response: dto.TaskResponse{},
+	})
 }
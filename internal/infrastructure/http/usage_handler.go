@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// UsageHandler handles the LLM usage API used for billing
+type UsageHandler struct {
+	usageUseCase *usecase.UsageUseCase
+	logger       logging.Logger
+}
+
+// NewUsageHandler creates a new UsageHandler
+func NewUsageHandler(usageUseCase *usecase.UsageUseCase, logger logging.Logger) *UsageHandler {
+	return &UsageHandler{
+		usageUseCase: usageUseCase,
+		logger:       logger,
+	}
+}
+
+// GetMonthly handles GET /analytics/usage/monthly
+func (h *UsageHandler) GetMonthly(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	resp, err := h.usageUseCase.GetMonthlyUsage(ctx, dto.UsageRequest{
+		Since:     query.Get("since"),
+		Until:     query.Get("until"),
+		GroupBy:   query.Get("group_by"),
+		Workspace: query.Get("workspace"),
+	})
+	if err != nil {
+		h.logger.Error("failed to get monthly usage", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterUsageRoutes registers the LLM usage routes
+func RegisterUsageRoutes(r *Router, handler *UsageHandler) {
+	analytics := r.Group("/analytics")
+	analytics.HandleFunc("GET /usage/monthly", handler.GetMonthly).Describe(RouteDoc{
+		Summary:     "Get monthly LLM usage",
+		Description: "LLM requests, input and output tokens and estimated cost of every UTC month of the range, oldest first, in total and per group, with the totals of the range. Usage is recorded from llm.usage events and kept when sessions and users are deleted, so it can be billed or charged back. Ranges are limited to 36 months.",
+		Tag:         "analytics",
+		Response:    dto.UsageResponse{},
+		Query: []QueryParam{
+			{Name: "since", Description: "First UTC month, YYYY-MM (default 11 months before until)"},
+			{Name: "until", Description: "Last UTC month, YYYY-MM, inclusive (default the current month)"},
+			{Name: "group_by", Description: "workspace (default), user, provider or model"},
+			{Name: "workspace", Description: "Only usage of this workspace; sessions without a workspace belong to default"},
+		},
+	})
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"mime"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// UserDataHandler handles HTTP requests to export and erase the data of users
+type UserDataHandler struct {
+	userDataUseCase *usecase.UserDataUseCase
+	logger          logging.Logger
+}
+
+// NewUserDataHandler creates a new UserDataHandler
+func NewUserDataHandler(userDataUseCase *usecase.UserDataUseCase, logger logging.Logger) *UserDataHandler {
+	return &UserDataHandler{
+		userDataUseCase: userDataUseCase,
+		logger:          logger,
+	}
+}
+
+// ExportUserData handles GET /users/{id}/export
+func (h *UserDataHandler) ExportUserData(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+
+	resp, err := h.userDataUseCase.ExportUserData(ctx, userID)
+	if err != nil {
+		h.logger.Error("failed to export user data", "error", err, "user_id", userID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	w.Header().Set("Content-Type", resp.Export.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": resp.Export.FileName}))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resp.Export.Content)
+	return err
+}
+
+// EraseUserData handles DELETE /users/{id}/data
+func (h *UserDataHandler) EraseUserData(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+
+	resp, err := h.userDataUseCase.EraseUserData(ctx, userID, dto.EraseUserDataRequest{
+		Reason:      r.URL.Query().Get("reason"),
+		RequestedBy: credentialFromContext(ctx),
+	})
+	if err != nil {
+		h.logger.Error("failed to erase user data", "error", err, "user_id", userID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListErasures handles GET /admin/data-erasures
+func (h *UserDataHandler) ListErasures(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"), "offset")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.userDataUseCase.ListErasures(ctx, dto.ListUserDataErasuresRequest{
+		UserID: query.Get("user_id"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		h.logger.Error("failed to list user data erasures", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterUserDataRoutes registers the user data export and erasure routes
+func RegisterUserDataRoutes(r *Router, handler *UserDataHandler) {
+	user := r.Group("/users/{id}")
+	admin := r.Group("/admin")
+	user.HandleFunc("GET /export", handler.ExportUserData).Describe(RouteDoc{
+		Summary:     "Export all data of a user",
+		Description: "A ZIP archive with user.json (the user, their preferences and the IDs of their sessions) and a sessions/<id>.json transcript with the attributes of every session.",
+		Produces:    []string{"application/zip"},
+	})
+	user.HandleFunc("DELETE /data", handler.EraseUserData).Describe(RouteDoc{
+		Summary:     "Erase all data of a user",
+		Description: "Deletes the user, their preferences and their sessions with the messages, tasks and session attributes, checks that nothing is left and saves an audit record of the erasure. Answers 500 if data is left; the audit record is then saved as unverified.",
+		Query:       []QueryParam{{Name: "reason", Description: "Reason recorded in the audit record, e.g. a ticket number (max 500 characters)"}},
+		Response:    dto.UserDataErasureResponse{},
+	})
+	admin.HandleFunc("GET /data-erasures", handler.ListErasures).Describe(RouteDoc{
+		Summary:     "List user data erasures",
+		Description: "The audit log of user data erasures, newest first.",
+		Tag:         "admin",
+		Query: []QueryParam{
+			{Name: "user_id", Description: "Only erasures of the user with this ID"},
+			{Name: "limit", Description: "Maximum number of erasures to return (default 50, max 500)"},
+			{Name: "offset", Description: "Number of erasures to skip"},
+		},
+		Response: dto.UserDataErasuresResponse{},
+	})
+}
@@ -33,14 +33,14 @@ func (h *UserHandler) CreateUser(ctx context.Context, w http.ResponseWriter, r *
 	}
 
 	// Validate request
-	if req.Channel == "" || req.ChannelID == "" {
-		return WriteError(w, http.StatusBadRequest, "channel and channel_id are required")
+	if !validateRequest(w, &req) {
+		return nil
 	}
 
 	resp, err := h.userUseCase.CreateUser(ctx, req)
 	if err != nil {
 		h.logger.Error("failed to create user", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -60,7 +60,7 @@ func (h *UserHandler) GetUserByID(ctx context.Context, w http.ResponseWriter, r
 	resp, err := h.userUseCase.GetUserByID(ctx, id)
 	if err != nil {
 		h.logger.Error("failed to get user", "error", err, "user_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -82,7 +82,7 @@ func (h *UserHandler) GetUserByChannel(ctx context.Context, w http.ResponseWrite
 	resp, err := h.userUseCase.GetUserByChannel(ctx, channel, channelID)
 	if err != nil {
 		h.logger.Error("failed to get user by channel", "error", err, "channel", channel, "channel_id", channelID)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -97,11 +97,11 @@ func (h *UserHandler) ListUsers(ctx context.Context, w http.ResponseWriter, r *h
 	resp, err := h.userUseCase.ListUsers(ctx)
 	if err != nil {
 		h.logger.Error("failed to list users", "error", err)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	return WriteJSON(w, http.StatusOK, resp)
@@ -117,7 +117,7 @@ func (h *UserHandler) DeleteUser(ctx context.Context, w http.ResponseWriter, r *
 	resp, err := h.userUseCase.DeleteUser(ctx, id)
 	if err != nil {
 		h.logger.Error("failed to delete user", "error", err, "user_id", id)
-		return WriteError(w, http.StatusInternalServerError, resp.Error)
+		return writeUseCaseError(w, err, resp.Error)
 	}
 
 	if !resp.Success {
@@ -129,9 +129,27 @@ func (h *UserHandler) DeleteUser(ctx context.Context, w http.ResponseWriter, r *
 
 // RegisterUserRoutes registers user routes
 func RegisterUserRoutes(r *Router, handler *UserHandler) {
-	r.HandleFunc("POST /users", handler.CreateUser)
-	r.HandleFunc("GET /users", handler.ListUsers)
-	r.HandleFunc("GET /users/{id}", handler.GetUserByID)
-	r.HandleFunc("GET /users/channel/{channel}/{channelID}", handler.GetUserByChannel)
-	r.HandleFunc("DELETE /users/{id}", handler.DeleteUser)
+	users := r.Group("/users")
+	users.HandleFunc("POST /", handler.CreateUser).Describe(RouteDoc{
+		Summary:  "Create a user",
+		Request:  dto.CreateUserRequest{},
+		Response: dto.UserResponse{},
+		Status:   http.StatusCreated,
+	})
+	users.HandleFunc("GET /", handler.ListUsers).Describe(RouteDoc{
+		Summary:  "List users",
+		Response: dto.UsersResponse{},
+	})
+	users.HandleFunc("GET /{id}", handler.GetUserByID).Describe(RouteDoc{
+		Summary:  "Get a user by ID",
+		Response: dto.UserResponse{},
+	})
+	users.HandleFunc("GET /channel/{channel}/{channelID}", handler.GetUserByChannel).Describe(RouteDoc{
+		Summary:  "Get a user by channel and channel user ID",
+		Response: dto.UserResponse{},
+	})
+	users.HandleFunc("DELETE /{id}", handler.DeleteUser).Describe(RouteDoc{
+		Summary:  "Delete a user",
+		Response: dto.UserResponse{},
+	})
 }
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// UserPreferencesHandler handles user preferences HTTP requests
+type UserPreferencesHandler struct {
+	prefsUseCase *usecase.UserPreferencesUseCase
+	logger       logging.Logger
+}
+
+// NewUserPreferencesHandler creates a new UserPreferencesHandler
+func NewUserPreferencesHandler(prefsUseCase *usecase.UserPreferencesUseCase, logger logging.Logger) *UserPreferencesHandler {
+	return &UserPreferencesHandler{
+		prefsUseCase: prefsUseCase,
+		logger:       logger,
+	}
+}
+
+// GetPreferences handles GET /users/{id}/preferences
+func (h *UserPreferencesHandler) GetPreferences(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+
+	resp, err := h.prefsUseCase.GetPreferences(ctx, userID)
+	if err != nil {
+		h.logger.Error("failed to get user preferences", "error", err, "user_id", userID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// UpdatePreferences handles PUT /users/{id}/preferences
+func (h *UserPreferencesHandler) UpdatePreferences(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+
+	var req dto.UpdateUserPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode user preferences request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.prefsUseCase.UpdatePreferences(ctx, userID, req)
+	if err != nil {
+		h.logger.Error("failed to update user preferences", "error", err, "user_id", userID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// DeletePreferences handles DELETE /users/{id}/preferences
+func (h *UserPreferencesHandler) DeletePreferences(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+
+	resp, err := h.prefsUseCase.DeletePreferences(ctx, userID)
+	if err != nil {
+		h.logger.Error("failed to delete user preferences", "error", err, "user_id", userID)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterUserPreferencesRoutes registers the user preferences routes
+func RegisterUserPreferencesRoutes(r *Router, handler *UserPreferencesHandler) {
+	preferences := r.Group("/users/{id}/preferences")
+	preferences.HandleFunc("GET /", handler.GetPreferences).Describe(RouteDoc{
+		Summary:     "Get the preferences of a user",
+		Description: "Users who never set a preference get empty preferences.",
+		Response:    dto.UserPreferencesResponse{},
+	})
+	preferences.HandleFunc("PUT /", handler.UpdatePreferences).Describe(RouteDoc{
+		Summary:     "Replace the preferences of a user",
+		Description: "Omitted fields are cleared. The preferences are passed to the LLM in the system prompt; model is used unless a request names one.",
+		Request:     dto.UpdateUserPreferencesRequest{},
+		Response:    dto.UserPreferencesResponse{},
+	})
+	preferences.HandleFunc("DELETE /", handler.DeletePreferences).Describe(RouteDoc{
+		Summary:  "Reset the preferences of a user",
+		Response: dto.UserPreferencesResponse{},
+	})
+}
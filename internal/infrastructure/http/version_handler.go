@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/shared/version"
+)
+
+// VersionHandler handles the version endpoint
+type VersionHandler struct {
+	info    version.Info
+	updates *version.Checker
+}
+
+// NewVersionHandler creates a new VersionHandler reporting info and, if updates is not nil,
+// the result of its last update check
+func NewVersionHandler(info version.Info, updates *version.Checker) *VersionHandler {
+	return &VersionHandler{
+		info:    info,
+		updates: updates,
+	}
+}
+
+// GetVersion handles GET /version
+func (h *VersionHandler) GetVersion(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp := &dto.VersionResponse{
+		Version:   h.info.Version,
+		Commit:    h.info.Commit,
+		BuildDate: h.info.BuildDate,
+		GoVersion: h.info.GoVersion,
+	}
+
+	if h.updates != nil {
+		status := h.updates.Status()
+		resp.Update = &dto.UpdateStatusDTO{
+			Available: status.Available,
+			Error:     status.Error,
+		}
+		if status.Latest != nil {
+			resp.Update.LatestVersion = status.Latest.Version
+			resp.Update.ReleaseURL = status.Latest.URL
+		}
+		if !status.CheckedAt.IsZero() {
+			resp.Update.CheckedAt = status.CheckedAt.Format(time.RFC3339)
+		}
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterVersionRoutes registers the version route
+func RegisterVersionRoutes(r *Router, handler *VersionHandler) {
+	r.HandleFunc("GET /version", handler.GetVersion).Describe(RouteDoc{
+		Summary:     "Get the server version",
+		Description: "Version, commit and build date of the server and, with update_check enabled, whether a newer release is available.",
+		Tag:         "health",
+		Response:    dto.VersionResponse{},
+	})
+}
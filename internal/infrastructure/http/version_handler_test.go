@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionHandler_GetVersion(t *testing.T) {
+	info := version.Info{Version: "1.2.0", Commit: "3f2a1b9c", BuildDate: "2026-05-01T10:00:00Z", GoVersion: "go1.25.5"}
+
+	get := func(handler *VersionHandler) *dto.VersionResponse {
+		router := NewRouter()
+		RegisterVersionRoutes(router, handler)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/version", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp dto.VersionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return &resp
+	}
+
+	// Without the update check
+	resp := get(NewVersionHandler(info, nil))
+	assert.Equal(t, &dto.VersionResponse{Version: "1.2.0", Commit: "3f2a1b9c", BuildDate: "2026-05-01T10:00:00Z", GoVersion: "go1.25.5"}, resp)
+
+	releases := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v1.3.0","html_url":"https://github.com/aatumaykin/nexflow/releases/tag/v1.3.0"}`))
+	}))
+	defer releases.Close()
+	checker := version.NewChecker(info.Version, version.CheckerConfig{Repository: "aatumaykin/nexflow", APIURL: releases.URL}, releases.Client(), logging.NewNoopLogger())
+
+	// Before the first check
+	resp = get(NewVersionHandler(info, checker))
+	assert.Equal(t, &dto.UpdateStatusDTO{}, resp.Update)
+
+	_, err := checker.Check(context.Background())
+	require.NoError(t, err)
+	resp = get(NewVersionHandler(info, checker))
+	require.NotNil(t, resp.Update)
+	assert.True(t, resp.Update.Available)
+	assert.Equal(t, "1.3.0", resp.Update.LatestVersion)
+	assert.Equal(t, "https://github.com/aatumaykin/nexflow/releases/tag/v1.3.0", resp.Update.ReleaseURL)
+	assert.NotEmpty(t, resp.Update.CheckedAt)
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// WebhookHandler handles the outbound webhook admin API
+type WebhookHandler struct {
+	webhookUseCase *usecase.WebhookUseCase
+	logger         logging.Logger
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(webhookUseCase *usecase.WebhookUseCase, logger logging.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: webhookUseCase,
+		logger:         logger,
+	}
+}
+
+// ListEndpoints handles GET /admin/webhooks
+func (h *WebhookHandler) ListEndpoints(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.webhookUseCase.ListEndpoints(ctx)
+	if err != nil {
+		h.logger.Error("failed to list webhook endpoints", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListDeliveries handles GET /admin/webhooks/deliveries
+func (h *WebhookHandler) ListDeliveries(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"), "limit")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"), "offset")
+	if err != nil {
+		return WriteError(w, http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := h.webhookUseCase.ListDeliveries(ctx, dto.ListWebhookDeliveriesRequest{
+		Endpoint: query.Get("endpoint"),
+		Status:   query.Get("status"),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		h.logger.Error("failed to list webhook deliveries", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterWebhookRoutes registers the outbound webhook admin routes
+func RegisterWebhookRoutes(r *Router, handler *WebhookHandler) {
+	webhooks := r.Group("/admin/webhooks")
+	webhooks.HandleFunc("GET /", handler.ListEndpoints).Describe(RouteDoc{
+		Summary:     "List webhook endpoints",
+		Description: "The configured webhook endpoints and the event types posted to them. Secrets are not returned.",
+		Tag:         "admin",
+		Response:    dto.WebhookEndpointsResponse{},
+	})
+	webhooks.HandleFunc("GET /deliveries", handler.ListDeliveries).Describe(RouteDoc{
+		Summary:     "List webhook deliveries",
+		Description: "The delivery log of outbound webhooks, newest first.",
+		Tag:         "admin",
+		Query: []QueryParam{
+			{Name: "endpoint", Description: "Only deliveries to the endpoint with this name"},
+			{Name: "status", Description: "Only deliveries with this status: pending, delivered or failed"},
+			{Name: "limit", Description: "Maximum number of deliveries to return (default 50, max 500)"},
+			{Name: "offset", Description: "Number of deliveries to skip"},
+		},
+		Response: dto.WebhookDeliveriesResponse{},
+	})
+}
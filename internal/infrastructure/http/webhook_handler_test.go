@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// stubDeliveryRepository returns fixed deliveries and records the last filter
+type stubDeliveryRepository struct {
+	deliveries []*entity.WebhookDelivery
+	filter     repository.WebhookDeliveryFilter
+}
+
+func (s *stubDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return nil
+}
+
+func (s *stubDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return nil
+}
+
+func (s *stubDeliveryRepository) List(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	s.filter = filter
+	return s.deliveries, nil
+}
+
+func newWebhookTestRouter(repo repository.WebhookDeliveryRepository) *Router {
+	uc := usecase.NewWebhookUseCase(repo, usecase.WebhookConfig{
+		Enabled:   true,
+		Endpoints: []*dto.WebhookEndpointDTO{{Name: "ci", URL: "https://ci.example.com/hook", Events: []string{"task.completed"}, Signed: true}},
+	}, logging.NewNoopLogger())
+
+	router := NewRouter()
+	RegisterWebhookRoutes(router, NewWebhookHandler(uc, logging.NewNoopLogger()))
+	return router
+}
+
+func TestWebhookHandler_ListEndpoints(t *testing.T) {
+	router := newWebhookTestRouter(&stubDeliveryRepository{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/webhooks", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+	var resp dto.WebhookEndpointsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Enabled)
+	require.Len(t, resp.Endpoints, 1)
+	assert.Equal(t, "ci", resp.Endpoints[0].Name)
+	assert.True(t, resp.Endpoints[0].Signed)
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	delivery := entity.NewWebhookDelivery("ci", "https://ci.example.com/hook", "task.completed", `{}`)
+	delivery.SetFailed(http.StatusBadRequest, "unexpected status 400")
+	repo := &stubDeliveryRepository{deliveries: []*entity.WebhookDelivery{delivery}}
+	router := newWebhookTestRouter(repo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/webhooks/deliveries?endpoint=ci&status=failed&limit=5&offset=5", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, repository.WebhookDeliveryFilter{Endpoint: "ci", Status: "failed", Limit: 5, Offset: 5}, repo.filter)
+	var resp dto.WebhookDeliveriesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Deliveries, 1)
+	assert.Equal(t, delivery.ID, resp.Deliveries[0].ID)
+	assert.Equal(t, "unexpected status 400", resp.Deliveries[0].Error)
+}
+
+func TestWebhookHandler_ListDeliveries_BadRequest(t *testing.T) {
+	router := newWebhookTestRouter(&stubDeliveryRepository{})
+
+	for _, query := range []string{"?limit=-1", "?offset=x", "?status=lost"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/webhooks/deliveries"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
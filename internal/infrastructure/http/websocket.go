@@ -0,0 +1,277 @@
+package http
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes (RFC 6455, section 5.2)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// WebSocket close status codes (RFC 6455, section 7.4.1)
+const (
+	wsCloseNormal      = 1000
+	wsCloseProtocol    = 1002
+	wsCloseUnsupported = 1003
+	wsCloseTooBig      = 1009
+)
+
+// wsMaxMessageSize limits the size of a message read from a client
+const wsMaxMessageSize = 1 << 20
+
+// wsAcceptGUID is appended to the client key to compute Sec-WebSocket-Accept
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// errWSClosed is returned by wsConn.ReadMessage when the client closes the connection
+var errWSClosed = errors.New("websocket closed")
+
+// wsConn is a server-side WebSocket connection.
+// Reads must come from a single goroutine; writes are safe for concurrent use.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+
+	writeMu sync.Mutex
+}
+
+// isWebSocketUpgrade reports whether a request asks to upgrade to the WebSocket protocol
+func isWebSocketUpgrade(r *http.Request) bool {
+	return headerContainsToken(r.Header, "Connection", "upgrade") &&
+		headerContainsToken(r.Header, "Upgrade", "websocket")
+}
+
+// upgradeWebSocket performs the WebSocket opening handshake and takes over the connection.
+// On failure an error response has already been written.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if r.Method != http.MethodGet || !isWebSocketUpgrade(r) {
+		_ = WriteError(w, http.StatusBadRequest, "websocket upgrade required")
+		return nil, errors.New("not a websocket upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		_ = WriteError(w, http.StatusUpgradeRequired, "unsupported websocket version")
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		_ = WriteError(w, http.StatusBadRequest, "missing Sec-WebSocket-Key")
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		_ = WriteError(w, http.StatusInternalServerError, "websocket not supported")
+		return nil, fmt.Errorf("failed to hijack connection: %w", err)
+	}
+	// The server's read and write timeouts are meant for requests, not long-lived connections
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// ReadMessage returns the next text or binary message, reassembling fragments.
+// Pings are answered automatically. A close frame from the client is echoed and
+// errWSClosed is returned.
+func (c *wsConn) ReadMessage() (opcode byte, payload []byte, err error) {
+	var message []byte
+	messageOpcode := byte(0)
+
+	for {
+		fin, op, data, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, data); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			code := uint16(wsCloseNormal)
+			if len(data) >= 2 {
+				code = binary.BigEndian.Uint16(data)
+			}
+			_ = c.WriteClose(code, "")
+			return 0, nil, errWSClosed
+		case wsOpText, wsOpBinary:
+			if messageOpcode != 0 {
+				_ = c.WriteClose(wsCloseProtocol, "expected continuation frame")
+				return 0, nil, errors.New("websocket: unexpected data frame")
+			}
+			messageOpcode = op
+		case wsOpContinuation:
+			if messageOpcode == 0 {
+				_ = c.WriteClose(wsCloseProtocol, "unexpected continuation frame")
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			_ = c.WriteClose(wsCloseProtocol, "unknown opcode")
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %d", op)
+		}
+
+		if len(message)+len(data) > wsMaxMessageSize {
+			_ = c.WriteClose(wsCloseTooBig, "message too big")
+			return 0, nil, errors.New("websocket: message too big")
+		}
+		message = append(message, data...)
+
+		if fin {
+			return messageOpcode, message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame and unmasks its payload.
+// Client frames must be masked (RFC 6455, section 5.1).
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.rw, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	if header[0]&0x70 != 0 {
+		_ = c.WriteClose(wsCloseProtocol, "reserved bits set")
+		return false, 0, nil, errors.New("websocket: reserved bits set")
+	}
+	if !masked {
+		_ = c.WriteClose(wsCloseProtocol, "client frames must be masked")
+		return false, 0, nil, errors.New("websocket: unmasked client frame")
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= wsOpClose && (length > 125 || !fin) {
+		_ = c.WriteClose(wsCloseProtocol, "invalid control frame")
+		return false, 0, nil, errors.New("websocket: invalid control frame")
+	}
+	if length > wsMaxMessageSize {
+		_ = c.WriteClose(wsCloseTooBig, "message too big")
+		return false, 0, nil, errors.New("websocket: frame too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// WriteText sends a text message
+func (c *wsConn) WriteText(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// WriteClose sends a close frame with a status code and reason
+func (c *wsConn) WriteClose(code uint16, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, code)
+	payload = append(payload, reason...)
+	return c.writeFrame(wsOpClose, payload)
+}
+
+// writeFrame writes a single unmasked frame; server frames are never masked
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := make([]byte, 0, 10)
+	header = append(header, 0x80|opcode)
+	switch n := len(payload); {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// Close closes the underlying connection
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
+
+// headerContainsToken reports whether a comma-separated header contains a token, ignoring case
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialTestWebSocket opens a WebSocket connection to a test server
+func dialTestWebSocket(t *testing.T, server *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = io.WriteString(conn, "GET /ws HTTP/1.1\r\n"+
+		"Host: example.com\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	// Accept value for the sample key from RFC 6455, section 1.3
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return conn, reader
+}
+
+// writeClientFrame writes a masked client frame
+func writeClientFrame(t *testing.T, conn net.Conn, fin bool, opcode byte, payload []byte) {
+	t.Helper()
+
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, 0x80|byte(len(payload)))
+	default:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := conn.Write(frame)
+	require.NoError(t, err)
+}
+
+// readServerFrame reads an unmasked server frame
+func readServerFrame(t *testing.T, reader *bufio.Reader) (byte, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	_, err := io.ReadFull(reader, header[:])
+	require.NoError(t, err)
+	require.Zero(t, header[1]&0x80, "server frames must not be masked")
+
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		_, err := io.ReadFull(reader, ext[:])
+		require.NoError(t, err)
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	require.NoError(t, err)
+
+	return header[0] & 0x0F, payload
+}
+
+func newEchoWebSocketServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgradeWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteText(data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebSocket_Echo(t *testing.T) {
+	conn, reader := dialTestWebSocket(t, newEchoWebSocketServer(t))
+
+	writeClientFrame(t, conn, true, wsOpText, []byte("hello"))
+	opcode, payload := readServerFrame(t, reader)
+	assert.Equal(t, byte(wsOpText), opcode)
+	assert.Equal(t, "hello", string(payload))
+
+	// Fragmented message with a ping between the fragments
+	long := strings.Repeat("x", 300)
+	writeClientFrame(t, conn, false, wsOpText, []byte(long[:100]))
+	writeClientFrame(t, conn, true, wsOpPing, []byte("ping"))
+	writeClientFrame(t, conn, true, wsOpContinuation, []byte(long[100:]))
+
+	opcode, payload = readServerFrame(t, reader)
+	assert.Equal(t, byte(wsOpPong), opcode)
+	assert.Equal(t, "ping", string(payload))
+
+	opcode, payload = readServerFrame(t, reader)
+	assert.Equal(t, byte(wsOpText), opcode)
+	assert.Equal(t, long, string(payload))
+}
+
+func TestWebSocket_Close(t *testing.T) {
+	conn, reader := dialTestWebSocket(t, newEchoWebSocketServer(t))
+
+	writeClientFrame(t, conn, true, wsOpClose, binary.BigEndian.AppendUint16(nil, wsCloseNormal))
+
+	opcode, payload := readServerFrame(t, reader)
+	assert.Equal(t, byte(wsOpClose), opcode)
+	assert.Equal(t, uint16(wsCloseNormal), binary.BigEndian.Uint16(payload))
+}
+
+func TestWebSocket_RejectsPlainRequest(t *testing.T) {
+	server := newEchoWebSocketServer(t)
+
+	resp, err := http.Get(server.URL + "/ws")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// WorkspaceHandler handles the workspace admin API
+type WorkspaceHandler struct {
+	workspaceUseCase *usecase.WorkspaceUseCase
+	logger           logging.Logger
+}
+
+// NewWorkspaceHandler creates a new WorkspaceHandler
+func NewWorkspaceHandler(workspaceUseCase *usecase.WorkspaceUseCase, logger logging.Logger) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceUseCase: workspaceUseCase,
+		logger:           logger,
+	}
+}
+
+// CreateWorkspace handles POST /admin/workspaces
+func (h *WorkspaceHandler) CreateWorkspace(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req dto.CreateWorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode workspace request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.workspaceUseCase.CreateWorkspace(ctx, req)
+	if err != nil {
+		h.logger.Error("failed to create workspace", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusCreated, resp)
+}
+
+// ListWorkspaces handles GET /admin/workspaces
+func (h *WorkspaceHandler) ListWorkspaces(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	resp, err := h.workspaceUseCase.ListWorkspaces(ctx)
+	if err != nil {
+		h.logger.Error("failed to list workspaces", "error", err)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetWorkspace handles GET /admin/workspaces/{id}
+func (h *WorkspaceHandler) GetWorkspace(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+
+	resp, err := h.workspaceUseCase.GetWorkspace(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to get workspace", "error", err, "workspace_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// UpdateWorkspace handles PUT /admin/workspaces/{id}
+func (h *WorkspaceHandler) UpdateWorkspace(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+
+	var req dto.UpdateWorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode workspace request", "error", err)
+		return WriteError(w, http.StatusBadRequest, "invalid request body")
+	}
+
+	// Validate request
+	if !validateRequest(w, &req) {
+		return nil
+	}
+
+	resp, err := h.workspaceUseCase.UpdateWorkspace(ctx, id, req)
+	if err != nil {
+		h.logger.Error("failed to update workspace", "error", err, "workspace_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	if !resp.Success {
+		return WriteError(w, http.StatusBadRequest, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// DeleteWorkspace handles DELETE /admin/workspaces/{id}
+func (h *WorkspaceHandler) DeleteWorkspace(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+
+	resp, err := h.workspaceUseCase.DeleteWorkspace(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to delete workspace", "error", err, "workspace_id", id)
+		return writeUseCaseError(w, err, resp.Error)
+	}
+
+	return WriteJSON(w, http.StatusOK, resp)
+}
+
+// RegisterWorkspaceRoutes registers the workspace admin routes
+func RegisterWorkspaceRoutes(r *Router, handler *WorkspaceHandler) {
+	workspaces := r.Group("/admin/workspaces")
+	workspace := workspaces.Group("/{id}")
+	workspaces.HandleFunc("POST /", handler.CreateWorkspace).Describe(RouteDoc{
+		Summary:     "Create a workspace",
+		Description: "Connectors are mapped to the workspace by its id in the channels configuration.",
+		Tag:         "admin",
+		Request:     dto.CreateWorkspaceRequest{},
+		Response:    dto.WorkspaceResponse{},
+		Status:      http.StatusCreated,
+	})
+	workspaces.HandleFunc("GET /", handler.ListWorkspaces).Describe(RouteDoc{
+		Summary:  "List workspaces",
+		Tag:      "admin",
+		Response: dto.WorkspacesResponse{},
+	})
+	workspace.HandleFunc("GET /", handler.GetWorkspace).Describe(RouteDoc{
+		Summary:  "Get a workspace",
+		Tag:      "admin",
+		Response: dto.WorkspaceResponse{},
+	})
+	workspace.HandleFunc("PUT /", handler.UpdateWorkspace).Describe(RouteDoc{
+		Summary:  "Update a workspace",
+		Tag:      "admin",
+		Request:  dto.UpdateWorkspaceRequest{},
+		Response: dto.WorkspaceResponse{},
+	})
+	workspace.HandleFunc("DELETE /", handler.DeleteWorkspace).Describe(RouteDoc{
+		Summary:     "Delete a workspace",
+		Description: "Sessions of the workspace are kept. Responds with 409 for the default workspace.",
+		Tag:         "admin",
+		Response:    dto.WorkspaceResponse{},
+	})
+}
@@ -3,59 +3,176 @@ package llm
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/infrastructure/llm/tokenizer"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
+// LLMMetrics holds request metrics of LLM providers, labelled by provider name
+type LLMMetrics struct {
+	registry *metrics.MetricsRegistry
+}
+
+// NewLLMMetrics creates a new LLMMetrics instance
+func NewLLMMetrics() *LLMMetrics {
+	return &LLMMetrics{registry: metrics.NewMetricsRegistry()}
+}
+
+// Requests returns the request counter of a provider
+func (m *LLMMetrics) Requests(provider string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("llm_requests_total{provider=%q}", provider))
+}
+
+// Errors returns the failed request counter of a provider
+func (m *LLMMetrics) Errors(provider string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("llm_request_errors_total{provider=%q}", provider))
+}
+
+// Tokens returns the counter of tokens used by a provider
+func (m *LLMMetrics) Tokens(provider string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("llm_tokens_total{provider=%q}", provider))
+}
+
+// Duration returns the request duration histogram of a provider
+func (m *LLMMetrics) Duration(provider string) *metrics.Histogram {
+	return m.registry.GetHistogram(fmt.Sprintf("llm_request_duration_seconds{provider=%q}", provider), metrics.DefaultBuckets())
+}
+
+// Registry returns the registry holding the LLM metrics
+func (m *LLMMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// defaultTemperature is the temperature of requests that set none
+const defaultTemperature = 0.7
+
 // ProviderAdapter adapts infrastructure.Provider to ports.LLMProvider
 type ProviderAdapter struct {
-	provider Provider
+	provider  Provider
+	metrics   *LLMMetrics
+	tracer    *tracing.Tracer
+	tokenizer ports.Tokenizer
 }
 
 // NewProviderAdapter creates a new adapter that implements ports.LLMProvider
 func NewProviderAdapter(provider Provider) ports.LLMProvider {
+	return NewProviderAdapterWithMetrics(provider, nil)
+}
+
+// NewProviderAdapterWithMetrics creates a new adapter that records request metrics.
+// If llmMetrics is nil, new metrics are created.
+func NewProviderAdapterWithMetrics(provider Provider, llmMetrics *LLMMetrics) ports.LLMProvider {
+	return NewProviderAdapterWithTracer(provider, llmMetrics, nil)
+}
+
+// NewProviderAdapterWithTracer creates a new adapter that records request metrics and a span
+// for every request. If llmMetrics is nil, new metrics are created; a nil tracer disables tracing.
+func NewProviderAdapterWithTracer(provider Provider, llmMetrics *LLMMetrics, tracer *tracing.Tracer) ports.LLMProvider {
+	return NewProviderAdapterWithTokenizer(provider, llmMetrics, tracer, nil)
+}
+
+// NewProviderAdapterWithTokenizer creates a new adapter that counts the tokens of requests and
+// replies with tok, besides recording metrics and spans. If tok is nil, tokens are estimated
+// with tokenizer.Heuristic.
+func NewProviderAdapterWithTokenizer(provider Provider, llmMetrics *LLMMetrics, tracer *tracing.Tracer, tok ports.Tokenizer) ports.LLMProvider {
+	if llmMetrics == nil {
+		llmMetrics = NewLLMMetrics()
+	}
+	if tok == nil {
+		tok = tokenizer.Heuristic{}
+	}
+
 	return &ProviderAdapter{
-		provider: provider,
+		provider:  provider,
+		metrics:   llmMetrics,
+		tracer:    tracer,
+		tokenizer: tok,
 	}
 }
 
+// Name returns the name of the wrapped provider
+func (a *ProviderAdapter) Name() string {
+	return a.provider.Name()
+}
+
+// IsAvailable checks if the wrapped provider is available
+func (a *ProviderAdapter) IsAvailable(ctx context.Context) bool {
+	return a.provider.IsAvailable(ctx)
+}
+
 // Generate implements ports.LLMProvider.Generate
 func (a *ProviderAdapter) Generate(ctx context.Context, req ports.CompletionRequest) (*ports.CompletionResponse, error) {
+	return a.generate(ctx, req, nil)
+}
+
+// generate sends req with the tools the LLM may call to the wrapped provider
+func (a *ProviderAdapter) generate(ctx context.Context, req ports.CompletionRequest, tools []*ToolDefinition) (*ports.CompletionResponse, error) {
+	temperature := defaultTemperature
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+
 	// Convert ports.CompletionRequest to llm.CompletionRequest
 	infraReq := &CompletionRequest{
 		Messages:    convertMessages(req.Messages),
 		Model:       req.Model,
 		MaxTokens:   req.MaxTokens,
-		Temperature: 0.7, // Default temperature
+		Temperature: temperature,
+		Tools:       tools,
 		Metadata:    make(map[string]interface{}),
 	}
 
 	// Use Chat method for better chat support
+	name := a.provider.Name()
+	ctx, span := a.tracer.Start(ctx, "llm.generate", tracing.SpanKindClient, "llm.provider", name, "llm.model", req.Model)
+	defer span.End()
+
+	start := time.Now()
 	resp, err := a.provider.Chat(ctx, infraReq)
+	a.metrics.Requests(name).Inc()
+	a.metrics.Duration(name).Observe(time.Since(start).Seconds())
 	if err != nil {
+		a.metrics.Errors(name).Inc()
+		span.RecordError(err)
 		return nil, fmt.Errorf("LLMProviderAdapter.Generate: %w", err)
 	}
+	tokens := a.tokens(req, resp)
+	a.metrics.Tokens(name).Add(int64(tokens.TotalTokens))
+	span.SetAttributes("llm.tokens", tokens.TotalTokens)
 
 	// Convert llm.CompletionResponse to ports.CompletionResponse
+	toolCalls := convertToolCalls(resp.ToolCalls)
 	return &ports.CompletionResponse{
 		Message: ports.Message{
-			Role:    "assistant",
-			Content: resp.Content,
-		},
-		Tokens: ports.Tokens{
-			InputTokens:  resp.TokensUsed / 2, // Approximation
-			OutputTokens: resp.TokensUsed - (resp.TokensUsed / 2),
-			TotalTokens:  resp.TokensUsed,
+			Role:      "assistant",
+			Content:   resp.Content,
+			ToolCalls: toolCalls,
 		},
+		ToolCalls: toolCalls,
+		Tokens:    tokens,
 	}, nil
 }
 
-// GenerateWithTools implements ports.LLMProvider.GenerateWithTools
-// Note: Current infrastructure.Provider doesn't support tools, so we'll call Generate
+// tokens splits the tokens the provider reports used between the request and the reply by
+// counting those of the request. Tokens of providers reporting none are counted in full.
+func (a *ProviderAdapter) tokens(req ports.CompletionRequest, resp *CompletionResponse) ports.Tokens {
+	input := a.tokenizer.CountMessages(req.Model, req.Messages)
+	if resp.TokensUsed <= 0 {
+		output := a.tokenizer.Count(req.Model, resp.Content)
+		return ports.Tokens{InputTokens: input, OutputTokens: output, TotalTokens: input + output}
+	}
+
+	input = min(input, resp.TokensUsed)
+	return ports.Tokens{InputTokens: input, OutputTokens: resp.TokensUsed - input, TotalTokens: resp.TokensUsed}
+}
+
+// GenerateWithTools implements ports.LLMProvider.GenerateWithTools.
+// Providers without tool support ignore the tools and answer with text.
 func (a *ProviderAdapter) GenerateWithTools(ctx context.Context, req ports.CompletionRequest, tools []ports.ToolDefinition) (*ports.CompletionResponse, error) {
-	// For now, just call Generate without tool support
-	// This can be extended when infrastructure.Provider supports tools
-	return a.Generate(ctx, req)
+	return a.generate(ctx, req, convertTools(tools))
 }
 
 // Stream implements ports.LLMProvider.Stream
@@ -80,11 +197,14 @@ func (a *ProviderAdapter) Stream(ctx context.Context, req ports.CompletionReques
 // EstimateCost implements ports.LLMProvider.EstimateCost
 // Note: Current infrastructure.Provider doesn't provide cost estimation
 func (a *ProviderAdapter) EstimateCost(req ports.CompletionRequest) (float64, error) {
-	// Simple estimation: $0.00002 per token (approximate)
+	// Simple estimation: $0.00002 per token (approximate) of the messages and the reply
 	totalTokens := req.MaxTokens
 	if totalTokens == 0 {
 		totalTokens = 100 // Default estimation
 	}
+	if len(req.Messages) > 0 {
+		totalTokens += a.tokenizer.CountMessages(req.Model, req.Messages)
+	}
 	return float64(totalTokens) * 0.00002, nil
 }
 
@@ -93,9 +213,51 @@ func convertMessages(messages []ports.Message) []*Message {
 	infraMessages := make([]*Message, len(messages))
 	for i, msg := range messages {
 		infraMessages[i] = &Message{
-			Role:    msg.Role,
-			Content: msg.Content,
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		}
+		for _, call := range msg.ToolCalls {
+			infraMessages[i].ToolCalls = append(infraMessages[i].ToolCalls, &ToolCall{
+				ID:        call.ID,
+				Name:      call.Name,
+				Arguments: call.Arguments,
+			})
 		}
 	}
 	return infraMessages
 }
+
+// convertTools converts ports.ToolDefinition slice to llm.ToolDefinition slice. Tools without
+// a JSON Schema object take any object as arguments.
+func convertTools(tools []ports.ToolDefinition) []*ToolDefinition {
+	infraTools := make([]*ToolDefinition, len(tools))
+	for i, tool := range tools {
+		parameters, ok := tool.Parameters.(map[string]interface{})
+		if !ok {
+			parameters = map[string]interface{}{"type": "object"}
+		}
+		infraTools[i] = &ToolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  parameters,
+		}
+	}
+	return infraTools
+}
+
+// convertToolCalls converts llm.ToolCall slice to ports.ToolCall slice
+func convertToolCalls(calls []*ToolCall) []ports.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	portCalls := make([]ports.ToolCall, len(calls))
+	for i, call := range calls {
+		portCalls[i] = ports.ToolCall{
+			ID:        call.ID,
+			Name:      call.Name,
+			Arguments: call.Arguments,
+		}
+	}
+	return portCalls
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/infrastructure/llm/tokenizer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,6 +16,7 @@ type mockProvider struct {
 	available bool
 	responses []*CompletionResponse
 	err       error
+	requests  []*CompletionRequest // Requests received, in order
 }
 
 func (m *mockProvider) Name() string {
@@ -22,6 +24,7 @@ func (m *mockProvider) Name() string {
 }
 
 func (m *mockProvider) Completion(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	m.requests = append(m.requests, req)
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -82,11 +85,44 @@ func TestProviderAdapter_Generate(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.Equal(t, "assistant", resp.Message.Role)
 	assert.Equal(t, "test response", resp.Message.Content)
-	assert.Equal(t, 50, resp.Tokens.InputTokens)  // 100 / 2
-	assert.Equal(t, 50, resp.Tokens.OutputTokens) // 100 - (100/2)
+	assert.Equal(t, 10, resp.Tokens.InputTokens)  // 3 + 3 + "user" (1) + "test message" (3), estimated
+	assert.Equal(t, 90, resp.Tokens.OutputTokens) // 100 - 10
 	assert.Equal(t, 100, resp.Tokens.TotalTokens)
 }
 
+func TestProviderAdapter_Generate_TokensCounted(t *testing.T) {
+	provider := &mockProvider{
+		name:      "test",
+		responses: []*CompletionResponse{{Content: "hello world"}},
+	}
+	adapter := NewProviderAdapterWithTokenizer(provider, nil, nil, tokenizer.New())
+
+	resp, err := adapter.Generate(context.Background(), ports.CompletionRequest{
+		Messages: []ports.Message{{Role: "user", Content: "tiktoken is great!"}},
+		Model:    "gpt-4",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3+3+1+6, resp.Tokens.InputTokens, "the request should be counted with the encoding of the model")
+	assert.Equal(t, 2, resp.Tokens.OutputTokens, "a reply without reported usage should be counted")
+	assert.Equal(t, 15, resp.Tokens.TotalTokens)
+}
+
+func TestProviderAdapter_Generate_Temperature(t *testing.T) {
+	provider := &mockProvider{name: "test"}
+	adapter := NewProviderAdapter(provider)
+	temperature := 0.2
+
+	_, err := adapter.Generate(context.Background(), ports.CompletionRequest{})
+	require.NoError(t, err)
+	_, err = adapter.Generate(context.Background(), ports.CompletionRequest{Temperature: &temperature})
+	require.NoError(t, err)
+
+	require.Len(t, provider.requests, 2)
+	assert.Equal(t, defaultTemperature, provider.requests[0].Temperature)
+	assert.Equal(t, 0.2, provider.requests[1].Temperature)
+}
+
 func TestProviderAdapter_Generate_Error(t *testing.T) {
 	provider := &mockProvider{
 		name: "test",
@@ -133,12 +169,56 @@ func TestProviderAdapter_GenerateWithTools(t *testing.T) {
 		{Name: "test_tool", Description: "A test tool"},
 	}
 
-	// Note: Current implementation doesn't use tools, but should still work
 	resp, err := adapter.GenerateWithTools(ctx, req, tools)
 
 	require.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, "test response with tools", resp.Message.Content)
+	assert.Empty(t, resp.ToolCalls)
+
+	// Tools without a schema take any object
+	require.Len(t, provider.requests[0].Tools, 1)
+	assert.Equal(t, "test_tool", provider.requests[0].Tools[0].Name)
+	assert.Equal(t, map[string]interface{}{"type": "object"}, provider.requests[0].Tools[0].Parameters)
+}
+
+func TestProviderAdapter_GenerateWithTools_ToolCalls(t *testing.T) {
+	provider := &mockProvider{
+		name: "test",
+		responses: []*CompletionResponse{
+			{
+				ToolCalls: []*ToolCall{
+					{ID: "call_1", Name: "weather", Arguments: map[string]interface{}{"city": "Rome"}},
+					{ID: "call_2", Name: "weather", Arguments: map[string]interface{}{"city": "Paris"}},
+				},
+				TokensUsed: 20,
+			},
+		},
+	}
+	adapter := NewProviderAdapter(provider)
+
+	req := ports.CompletionRequest{
+		Messages: []ports.Message{
+			{Role: "user", Content: "Weather in Berlin, Rome and Paris?"},
+			{Role: "assistant", ToolCalls: []ports.ToolCall{{ID: "call_0", Name: "weather", Arguments: map[string]interface{}{"city": "Berlin"}}}},
+			{Role: "tool", ToolCallID: "call_0", Content: "12°C"},
+		},
+	}
+	schema := map[string]interface{}{"type": "object", "required": []string{"city"}}
+
+	resp, err := adapter.GenerateWithTools(context.Background(), req, []ports.ToolDefinition{{Name: "weather", Parameters: schema}})
+	require.NoError(t, err)
+
+	require.Len(t, resp.ToolCalls, 2)
+	assert.Equal(t, "call_1", resp.ToolCalls[0].ID)
+	assert.Equal(t, "Paris", resp.ToolCalls[1].Arguments["city"])
+	assert.Equal(t, resp.ToolCalls, resp.Message.ToolCalls)
+
+	sent := provider.requests[0]
+	assert.Equal(t, schema, sent.Tools[0].Parameters)
+	require.Len(t, sent.Messages[1].ToolCalls, 1)
+	assert.Equal(t, "call_0", sent.Messages[1].ToolCalls[0].ID)
+	assert.Equal(t, "call_0", sent.Messages[2].ToolCallID)
 }
 
 func TestProviderAdapter_Stream(t *testing.T) {
@@ -227,6 +307,19 @@ func TestProviderAdapter_EstimateCost_DefaultTokens(t *testing.T) {
 	assert.Equal(t, 0.002, cost) // 100 (default) * 0.00002
 }
 
+func TestProviderAdapter_EstimateCost_Messages(t *testing.T) {
+	adapter := NewProviderAdapterWithTokenizer(&mockProvider{name: "test"}, nil, nil, tokenizer.New())
+
+	cost, err := adapter.EstimateCost(ports.CompletionRequest{
+		Messages:  []ports.Message{{Role: "user", Content: "hello world"}},
+		Model:     "gpt-4o",
+		MaxTokens: 100,
+	})
+
+	require.NoError(t, err)
+	assert.InDelta(t, 0.00218, cost, 1e-9) // (100 + 3 + 3 + 1 + 2) * 0.00002
+}
+
 func TestConvertMessages(t *testing.T) {
 	messages := []ports.Message{
 		{Role: "system", Content: "system message"},
@@ -244,3 +337,21 @@ func TestConvertMessages(t *testing.T) {
 	assert.Equal(t, "assistant", infraMessages[2].Role)
 	assert.Equal(t, "assistant message", infraMessages[2].Content)
 }
+
+func TestProviderAdapter_Generate_Metrics(t *testing.T) {
+	llmMetrics := NewLLMMetrics()
+	adapter := NewProviderAdapterWithMetrics(&mockProvider{name: "openai"}, llmMetrics)
+	failing := NewProviderAdapterWithMetrics(&mockProvider{name: "ollama", err: assert.AnError}, llmMetrics)
+	req := ports.CompletionRequest{Messages: []ports.Message{{Role: "user", Content: "test message"}}}
+
+	_, err := adapter.Generate(context.Background(), req)
+	require.NoError(t, err)
+	_, err = failing.Generate(context.Background(), req)
+	require.Error(t, err)
+
+	assert.Equal(t, int64(1), llmMetrics.Requests("openai").Get())
+	assert.Equal(t, int64(10), llmMetrics.Tokens("openai").Get())
+	assert.Equal(t, int64(0), llmMetrics.Errors("openai").Get())
+	assert.Equal(t, int64(1), llmMetrics.Duration("openai").Count())
+	assert.Equal(t, int64(1), llmMetrics.Errors("ollama").Get())
+}
@@ -110,9 +110,9 @@ func TestZaiProviderAdapterIntegration(t *testing.T) {
 	assert.Equal(t, "assistant", portResp.Message.Role)
 	assert.Equal(t, "Hello! How can I help you?", portResp.Message.Content)
 
-	// Verify token calculation (adapter approximates: input = total/2, output = total - input)
-	assert.Equal(t, 15, portResp.Tokens.InputTokens)  // 30 / 2
-	assert.Equal(t, 15, portResp.Tokens.OutputTokens) // 30 - 15
+	// Verify token calculation (adapter counts the input, the output is the rest of the total)
+	assert.Equal(t, 9, portResp.Tokens.InputTokens)   // 3 + 3 + "user" (1) + "Hello" (2), estimated
+	assert.Equal(t, 21, portResp.Tokens.OutputTokens) // 30 - 9
 	assert.Equal(t, 30, portResp.Tokens.TotalTokens)
 
 	// Step 7: Test streaming through adapter
@@ -99,3 +99,13 @@ func (m *MockLLMProvider) EstimateCost(req ports.CompletionRequest) (float64, er
 	}
 	return 0, fmt.Errorf("EstimateCostFunc not set")
 }
+
+// Name implements ports.LLMProviderStatus
+func (m *MockLLMProvider) Name() string {
+	return "mock"
+}
+
+// IsAvailable implements ports.LLMProviderStatus; the mock provider is always available
+func (m *MockLLMProvider) IsAvailable(ctx context.Context) bool {
+	return true
+}
@@ -79,9 +79,13 @@ func (p *Provider) Chat(ctx context.Context, req *llm.CompletionRequest) (*llm.C
 	}
 
 	// Convert to OpenAI chat completion request
+	messages, err := convertMessages(req.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("openai: %w", err)
+	}
 	openaiReq := chatCompletionRequest{
 		Model:    model,
-		Messages: convertMessages(req.Messages),
+		Messages: messages,
 	}
 
 	// Add optional parameters
@@ -91,6 +95,10 @@ func (p *Provider) Chat(ctx context.Context, req *llm.CompletionRequest) (*llm.C
 	if req.MaxTokens > 0 {
 		openaiReq.MaxTokens = req.MaxTokens
 	}
+	if len(req.Tools) > 0 {
+		openaiReq.Tools = convertTools(req.Tools)
+		openaiReq.ToolChoice = "auto"
+	}
 
 	// Marshal request
 	reqBody, err := json.Marshal(openaiReq)
@@ -143,10 +151,19 @@ func (p *Provider) Chat(ctx context.Context, req *llm.CompletionRequest) (*llm.C
 		"model", chatResp.Model,
 		"total_tokens", chatResp.Usage.TotalTokens)
 
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("openai: no choices in response")
+	}
+	toolCalls, err := convertToolCalls(chatResp.Choices[0].Message.ToolCalls)
+	if err != nil {
+		return nil, fmt.Errorf("openai: %w", err)
+	}
+
 	return &llm.CompletionResponse{
 		Content:    chatResp.Choices[0].Message.Content,
 		Model:      chatResp.Model,
 		TokensUsed: chatResp.Usage.TotalTokens,
+		ToolCalls:  toolCalls,
 		Metadata: map[string]interface{}{
 			"prompt_tokens":     chatResp.Usage.PromptTokens,
 			"completion_tokens": chatResp.Usage.CompletionTokens,
@@ -180,11 +197,37 @@ type chatCompletionRequest struct {
 	Messages    []chatMessage `json:"messages"`
 	Temperature float64       `json:"temperature,omitempty"`
 	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Tools       []chatTool    `json:"tools,omitempty"`
+	ToolChoice  string        `json:"tool_choice,omitempty"`
 }
 
 type chatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type chatTool struct {
+	Type     string       `json:"type"`
+	Function chatFunction `json:"function"`
+}
+
+type chatFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type chatToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function chatFunctionCall `json:"function"`
+}
+
+type chatFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 type chatCompletionResponse struct {
@@ -217,13 +260,54 @@ type errorResponse struct {
 }
 
 // convertMessages converts llm.Messages to chat messages
-func convertMessages(messages []*llm.Message) []chatMessage {
+func convertMessages(messages []*llm.Message) ([]chatMessage, error) {
 	chatMessages := make([]chatMessage, len(messages))
 	for i, msg := range messages {
 		chatMessages[i] = chatMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		}
+		for _, call := range msg.ToolCalls {
+			arguments, err := llm.EncodeToolArguments(call.Arguments)
+			if err != nil {
+				return nil, err
+			}
+			chatMessages[i].ToolCalls = append(chatMessages[i].ToolCalls, chatToolCall{
+				ID:       call.ID,
+				Type:     "function",
+				Function: chatFunctionCall{Name: call.Name, Arguments: arguments},
+			})
+		}
+	}
+	return chatMessages, nil
+}
+
+// convertTools converts llm.ToolDefinitions to function tools
+func convertTools(tools []*llm.ToolDefinition) []chatTool {
+	chatTools := make([]chatTool, len(tools))
+	for i, tool := range tools {
+		chatTools[i] = chatTool{
+			Type: "function",
+			Function: chatFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		}
+	}
+	return chatTools
+}
+
+// convertToolCalls converts the tool calls of a reply to llm.ToolCalls
+func convertToolCalls(calls []chatToolCall) ([]*llm.ToolCall, error) {
+	var toolCalls []*llm.ToolCall
+	for _, call := range calls {
+		arguments, err := llm.DecodeToolArguments(call.Function.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("tool call %s: %w", call.ID, err)
 		}
+		toolCalls = append(toolCalls, &llm.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments})
 	}
-	return chatMessages
+	return toolCalls, nil
 }
@@ -6,8 +6,10 @@ import (
 
 // Message represents a message in a conversation with LLM
 type Message struct {
-	Role    string // "system", "user", "assistant"
-	Content string
+	Role       string // "system", "user", "assistant", "tool"
+	Content    string
+	ToolCalls  []*ToolCall // Tool calls requested by an assistant message
+	ToolCallID string      // ID of the tool call a "tool" message answers
 }
 
 // CompletionRequest represents a request to generate completion
@@ -16,6 +18,7 @@ type CompletionRequest struct {
 	Model       string
 	Temperature float64
 	MaxTokens   int
+	Tools       []*ToolDefinition // Tools the model may call; providers without tool support ignore them
 	Metadata    map[string]interface{}
 }
 
@@ -24,9 +27,24 @@ type CompletionResponse struct {
 	Content    string
 	Model      string
 	TokensUsed int
+	ToolCalls  []*ToolCall // Tools the model asks to call, in order
 	Metadata   map[string]interface{}
 }
 
+// ToolDefinition describes a tool the model may call
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{} // JSON Schema of the arguments
+}
+
+// ToolCall is a call of a tool requested by the model
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments map[string]interface{}
+}
+
 // Provider defines the interface for LLM providers
 type Provider interface {
 	// Name returns the name of the provider
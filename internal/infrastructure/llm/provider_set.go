@@ -0,0 +1,209 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/infrastructure/llm/tokenizer"
+)
+
+// ProviderLimits are the configured limits of the requests a ProviderSet sends to a provider
+type ProviderLimits struct {
+	Model       string   // Model of requests that name none
+	Models      []string // Models requests may select besides Model; empty allows any model
+	Temperature float64  // Temperature of requests that set none; 0 keeps the default of the adapter
+	MaxTokens   int      // Upper bound and default of the max tokens of a request; 0 leaves it unbounded
+
+	// ContextTokens is the context window of the model: the oldest messages of a conversation
+	// exceeding it with the reply are left out, and the reply is capped to the room left; 0
+	// leaves conversations whole
+	ContextTokens int
+}
+
+// ProviderSet is a ports.LLMProvider that sends every request to the provider it selects,
+// or to the default provider, within the limits configured for that provider
+type ProviderSet struct {
+	defaultName string
+	providers   map[string]ports.LLMProvider
+	limits      map[string]ProviderLimits
+	failover    []string
+	health      ports.LLMProviderHealth
+	tokenizer   ports.Tokenizer
+}
+
+// NewProviderSet creates an empty provider set whose default provider is defaultName
+func NewProviderSet(defaultName string) *ProviderSet {
+	return &ProviderSet{
+		defaultName: defaultName,
+		providers:   make(map[string]ports.LLMProvider),
+		limits:      make(map[string]ProviderLimits),
+		tokenizer:   tokenizer.Heuristic{},
+	}
+}
+
+// SetTokenizer sets the tokenizer counting conversations against the context window of their
+// provider. Without it, tokens are estimated with tokenizer.Heuristic.
+func (s *ProviderSet) SetTokenizer(tok ports.Tokenizer) {
+	s.tokenizer = tok
+}
+
+// Add adds a provider under name, replacing any provider added under it before
+func (s *ProviderSet) Add(name string, provider ports.LLMProvider, limits ProviderLimits) {
+	s.providers[name] = provider
+	s.limits[name] = limits
+}
+
+// Provider returns the provider added under name
+func (s *ProviderSet) Provider(name string) (ports.LLMProvider, bool) {
+	provider, ok := s.providers[name]
+	return provider, ok
+}
+
+// SetFailover sends the requests selecting neither a provider nor a model to the first healthy
+// provider of chain while the default provider is unhealthy. When the providers of the chain
+// are unhealthy too, they still go to the default provider. It must be called before the set
+// serves requests.
+func (s *ProviderSet) SetFailover(chain []string, health ports.LLMProviderHealth) {
+	s.failover = chain
+	s.health = health
+}
+
+// Names returns the names of the providers of the set, sorted
+func (s *ProviderSet) Names() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Name returns the name of the default provider
+func (s *ProviderSet) Name() string {
+	return s.defaultName
+}
+
+// IsAvailable checks if the default provider is available
+func (s *ProviderSet) IsAvailable(ctx context.Context) bool {
+	status, ok := s.providers[s.defaultName].(ports.LLMProviderStatus)
+	return ok && status.IsAvailable(ctx)
+}
+
+// Generate implements ports.LLMProvider.Generate
+func (s *ProviderSet) Generate(ctx context.Context, req ports.CompletionRequest) (*ports.CompletionResponse, error) {
+	provider, req, err := s.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Generate(ctx, req)
+}
+
+// GenerateWithTools implements ports.LLMProvider.GenerateWithTools
+func (s *ProviderSet) GenerateWithTools(ctx context.Context, req ports.CompletionRequest, tools []ports.ToolDefinition) (*ports.CompletionResponse, error) {
+	provider, req, err := s.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+	return provider.GenerateWithTools(ctx, req, tools)
+}
+
+// Stream implements ports.LLMProvider.Stream
+func (s *ProviderSet) Stream(ctx context.Context, req ports.CompletionRequest) (<-chan string, error) {
+	provider, req, err := s.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Stream(ctx, req)
+}
+
+// EstimateCost implements ports.LLMProvider.EstimateCost
+func (s *ProviderSet) EstimateCost(req ports.CompletionRequest) (float64, error) {
+	provider, req, err := s.resolve(req)
+	if err != nil {
+		return 0, err
+	}
+	return provider.EstimateCost(req)
+}
+
+// resolve returns the provider selected by a request and the request within its limits:
+// the model and temperature default to the configured ones, and max tokens are capped
+func (s *ProviderSet) resolve(req ports.CompletionRequest) (ports.LLMProvider, ports.CompletionRequest, error) {
+	name := req.Provider
+	if name == "" {
+		name = s.defaultName
+		if req.Model == "" {
+			name = s.healthyName()
+		}
+	}
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, req, fmt.Errorf("%w: %q", ports.ErrLLMProviderNotFound, name)
+	}
+
+	limits := s.limits[name]
+	switch {
+	case req.Model == "":
+		req.Model = limits.Model
+	case req.Model != limits.Model && len(limits.Models) > 0 && !slices.Contains(limits.Models, req.Model):
+		return nil, req, fmt.Errorf("%w: %q for provider %q", ports.ErrLLMModelNotAllowed, req.Model, name)
+	}
+	if limits.MaxTokens > 0 && (req.MaxTokens <= 0 || req.MaxTokens > limits.MaxTokens) {
+		req.MaxTokens = limits.MaxTokens
+	}
+	if req.Temperature == nil && limits.Temperature > 0 {
+		temperature := limits.Temperature
+		req.Temperature = &temperature
+	}
+	req.Provider = name
+	return provider, s.fitContext(req, limits.ContextTokens), nil
+}
+
+// fitContext leaves out the oldest messages of a conversation until it fits in the context
+// window with the reply, keeping the system messages and the last message, and caps the reply
+// to the room left. A reply budget taking the whole window is not reserved.
+func (s *ProviderSet) fitContext(req ports.CompletionRequest, contextTokens int) ports.CompletionRequest {
+	if contextTokens <= 0 {
+		return req
+	}
+
+	budget := contextTokens
+	if req.MaxTokens > 0 && req.MaxTokens < contextTokens {
+		budget -= req.MaxTokens
+	}
+
+	tokens := s.tokenizer.CountMessages(req.Model, req.Messages)
+	if tokens > budget {
+		framing := s.tokenizer.CountMessages(req.Model, nil)
+		messages := make([]ports.Message, 0, len(req.Messages))
+		last := len(req.Messages) - 1
+		for i, message := range req.Messages {
+			if tokens > budget && i < last && message.Role != "system" {
+				tokens -= s.tokenizer.CountMessages(req.Model, []ports.Message{message}) - framing
+				continue
+			}
+			messages = append(messages, message)
+		}
+		req.Messages = messages
+	}
+
+	if room := contextTokens - tokens; room > 0 && (req.MaxTokens <= 0 || req.MaxTokens > room) {
+		req.MaxTokens = room
+	}
+	return req
+}
+
+// healthyName returns the name of the default provider, or while it is unhealthy the first
+// healthy provider of the failover chain
+func (s *ProviderSet) healthyName() string {
+	if s.health == nil || s.health.Healthy(s.defaultName) {
+		return s.defaultName
+	}
+	for _, name := range s.failover {
+		if _, ok := s.providers[name]; ok && s.health.Healthy(name) {
+			return name
+		}
+	}
+	return s.defaultName
+}
@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+)
+
+func newTestProviderSet() (*ProviderSet, *mockProvider, *mockProvider) {
+	openai := &mockProvider{name: "openai", available: true}
+	ollama := &mockProvider{name: "ollama"}
+	set := NewProviderSet("openai")
+	set.Add("openai", NewProviderAdapter(openai), ProviderLimits{Model: "gpt-4o", Models: []string{"gpt-4o-mini"}, Temperature: 0.5, MaxTokens: 1000})
+	set.Add("ollama", NewProviderAdapter(ollama), ProviderLimits{Model: "llama3"})
+	return set, openai, ollama
+}
+
+func TestProviderSet_Generate(t *testing.T) {
+	set, openai, ollama := newTestProviderSet()
+	temperature := 1.2
+
+	tests := []struct {
+		name     string
+		req      ports.CompletionRequest
+		provider *mockProvider
+		want     CompletionRequest
+	}{
+		{"default provider and limits", ports.CompletionRequest{}, openai,
+			CompletionRequest{Model: "gpt-4o", MaxTokens: 1000, Temperature: 0.5}},
+		{"allowed model and tokens within the cap", ports.CompletionRequest{Model: "gpt-4o-mini", MaxTokens: 200, Temperature: &temperature}, openai,
+			CompletionRequest{Model: "gpt-4o-mini", MaxTokens: 200, Temperature: 1.2}},
+		{"max tokens capped", ports.CompletionRequest{MaxTokens: 5000}, openai,
+			CompletionRequest{Model: "gpt-4o", MaxTokens: 1000, Temperature: 0.5}},
+		{"selected provider without limits", ports.CompletionRequest{Provider: "ollama", Model: "mistral", MaxTokens: 5000}, ollama,
+			CompletionRequest{Model: "mistral", MaxTokens: 5000, Temperature: defaultTemperature}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(tt.provider.requests)
+			_, err := set.Generate(context.Background(), tt.req)
+			require.NoError(t, err)
+
+			require.Len(t, tt.provider.requests, before+1)
+			got := tt.provider.requests[before]
+			assert.Equal(t, tt.want.Model, got.Model)
+			assert.Equal(t, tt.want.MaxTokens, got.MaxTokens)
+			assert.Equal(t, tt.want.Temperature, got.Temperature)
+		})
+	}
+}
+
+func TestProviderSet_Generate_Rejected(t *testing.T) {
+	set, openai, ollama := newTestProviderSet()
+
+	_, err := set.Generate(context.Background(), ports.CompletionRequest{Provider: "anthropic"})
+	assert.ErrorIs(t, err, ports.ErrLLMProviderNotFound)
+
+	_, err = set.Generate(context.Background(), ports.CompletionRequest{Model: "gpt-5"})
+	assert.ErrorIs(t, err, ports.ErrLLMModelNotAllowed)
+
+	assert.Empty(t, openai.requests)
+	assert.Empty(t, ollama.requests)
+}
+
+func TestProviderSet_Status(t *testing.T) {
+	set, _, _ := newTestProviderSet()
+
+	assert.Equal(t, "openai", set.Name())
+	assert.True(t, set.IsAvailable(context.Background()))
+	assert.Equal(t, []string{"ollama", "openai"}, set.Names())
+}
+
+// mockHealth is a ports.LLMProviderHealth reporting the providers it lists as unhealthy
+type mockHealth map[string]bool
+
+func (h mockHealth) Healthy(name string) bool {
+	return !h[name]
+}
+
+func (h mockHealth) History(name string) []ports.LLMProviderCheck {
+	return nil
+}
+
+func TestProviderSet_Failover(t *testing.T) {
+	set, openai, ollama := newTestProviderSet()
+	health := mockHealth{"openai": true}
+	set.SetFailover([]string{"anthropic", "ollama"}, health)
+
+	_, err := set.Generate(context.Background(), ports.CompletionRequest{})
+	require.NoError(t, err)
+	require.Len(t, ollama.requests, 1, "the first healthy provider of the chain should answer while the default is down")
+	assert.Equal(t, "llama3", ollama.requests[0].Model)
+
+	_, err = set.Generate(context.Background(), ports.CompletionRequest{Model: "gpt-4o-mini"})
+	require.NoError(t, err)
+	assert.Len(t, openai.requests, 1, "a request selecting a model should stay on the default provider")
+
+	health["ollama"] = true
+	_, err = set.Generate(context.Background(), ports.CompletionRequest{})
+	require.NoError(t, err)
+	assert.Len(t, openai.requests, 2, "the default provider should answer when the whole chain is down")
+
+	delete(health, "openai")
+	_, err = set.Generate(context.Background(), ports.CompletionRequest{})
+	require.NoError(t, err)
+	assert.Len(t, openai.requests, 3)
+	assert.Len(t, ollama.requests, 1)
+}
+
+func TestProviderSet_ContextWindow(t *testing.T) {
+	provider := &mockProvider{name: "ollama"}
+	set := NewProviderSet("ollama")
+	set.Add("ollama", NewProviderAdapter(provider), ProviderLimits{Model: "llama3", ContextTokens: 60})
+
+	// With the heuristic, each message is 3 framing tokens, its role and 10 content tokens, and
+	// the conversation 3 more: 62 tokens in all
+	messages := []ports.Message{
+		{Role: "system", Content: strings.Repeat("s", 40)},
+		{Role: "user", Content: strings.Repeat("a", 40)},
+		{Role: "assistant", Content: strings.Repeat("b", 40)},
+		{Role: "user", Content: strings.Repeat("c", 40)},
+	}
+
+	_, err := set.Generate(context.Background(), ports.CompletionRequest{Messages: messages})
+	require.NoError(t, err)
+	require.Len(t, provider.requests, 1)
+	got := provider.requests[0]
+	require.Len(t, got.Messages, 3, "the oldest message should be left out")
+	assert.Equal(t, "system", got.Messages[0].Role)
+	assert.Equal(t, messages[2].Content, got.Messages[1].Content)
+	assert.Equal(t, 12, got.MaxTokens, "the reply should be capped to the room left")
+
+	_, err = set.Generate(context.Background(), ports.CompletionRequest{Messages: messages, MaxTokens: 20})
+	require.NoError(t, err)
+	got = provider.requests[1]
+	require.Len(t, got.Messages, 2, "the reply budget should be reserved")
+	assert.Equal(t, "system", got.Messages[0].Role)
+	assert.Equal(t, messages[3].Content, got.Messages[1].Content)
+	assert.Equal(t, 20, got.MaxTokens)
+	assert.Len(t, messages, 4, "the messages of the request should be left as is")
+}
@@ -0,0 +1,158 @@
+// Package tokenizer counts the tokens of LLM requests: exactly with the BPE encodings of
+// OpenAI-compatible models (tiktoken), and with a heuristic for the other models.
+package tokenizer
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+)
+
+func init() {
+	// The encodings are embedded, so that counting never downloads them
+	tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+}
+
+// Tokens framing a chat conversation, as counted by OpenAI: each message is wrapped in
+// special tokens, and the reply is primed with further ones
+const (
+	tokensPerMessage = 3
+	tokensPerReply   = 3
+)
+
+// modelPrefixEncodings completes the models known to tiktoken with newer OpenAI models
+var modelPrefixEncodings = []struct {
+	prefix   string
+	encoding string
+}{
+	{"gpt-5", tiktoken.MODEL_O200K_BASE},
+	{"gpt-4.1", tiktoken.MODEL_O200K_BASE},
+	{"gpt-4o", tiktoken.MODEL_O200K_BASE},
+	{"chatgpt-4o", tiktoken.MODEL_O200K_BASE},
+	{"o1", tiktoken.MODEL_O200K_BASE},
+	{"o3", tiktoken.MODEL_O200K_BASE},
+	{"o4", tiktoken.MODEL_O200K_BASE},
+	{"gpt-4", tiktoken.MODEL_CL100K_BASE},
+	{"gpt-3.5", tiktoken.MODEL_CL100K_BASE},
+}
+
+// Tokenizer is a ports.Tokenizer counting the tokens of OpenAI-compatible models with their
+// encoding, and estimating those of other models, such as Claude or local models, with
+// Estimate. Encodings are loaded on first use.
+type Tokenizer struct {
+	mu        sync.Mutex
+	models    map[string]*tiktoken.Tiktoken // Encoding by model; nil for models estimated
+	encodings map[string]*tiktoken.Tiktoken // Loaded encodings by name
+}
+
+// Compile-time check that Tokenizer implements ports.Tokenizer
+var _ ports.Tokenizer = (*Tokenizer)(nil)
+
+// New creates a new Tokenizer
+func New() *Tokenizer {
+	return &Tokenizer{
+		models:    make(map[string]*tiktoken.Tiktoken),
+		encodings: make(map[string]*tiktoken.Tiktoken),
+	}
+}
+
+// Count implements ports.Tokenizer.Count
+func (t *Tokenizer) Count(model, text string) int {
+	if text == "" {
+		return 0
+	}
+	if encoding := t.encoding(model); encoding != nil {
+		return len(encoding.EncodeOrdinary(text))
+	}
+	return Estimate(text)
+}
+
+// CountMessages implements ports.Tokenizer.CountMessages
+func (t *Tokenizer) CountMessages(model string, messages []ports.Message) int {
+	return countMessages(t, model, messages)
+}
+
+// encoding returns the encoding of a model, or nil if the model is not OpenAI-compatible.
+// Models may be prefixed with their vendor, e.g. "openai/gpt-4o".
+func (t *Tokenizer) encoding(model string) *tiktoken.Tiktoken {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if encoding, ok := t.models[model]; ok {
+		return encoding
+	}
+
+	var encoding *tiktoken.Tiktoken
+	if name := encodingName(model); name != "" {
+		encoding = t.encodings[name]
+		if encoding == nil {
+			// A missing encoding leaves the model estimated
+			encoding, _ = tiktoken.GetEncoding(name)
+			t.encodings[name] = encoding
+		}
+	}
+	t.models[model] = encoding
+	return encoding
+}
+
+// encodingName returns the name of the encoding of a model, or an empty string if it is unknown
+func encodingName(model string) string {
+	model = strings.ToLower(model[strings.LastIndex(model, "/")+1:])
+	if name, ok := tiktoken.MODEL_TO_ENCODING[model]; ok {
+		return name
+	}
+	for _, p := range modelPrefixEncodings {
+		if strings.HasPrefix(model, p.prefix) {
+			return p.encoding
+		}
+	}
+	return ""
+}
+
+// Heuristic is a ports.Tokenizer estimating the tokens of every model with Estimate
+type Heuristic struct{}
+
+// Compile-time check that Heuristic implements ports.Tokenizer
+var _ ports.Tokenizer = Heuristic{}
+
+// Count implements ports.Tokenizer.Count
+func (Heuristic) Count(model, text string) int {
+	return Estimate(text)
+}
+
+// CountMessages implements ports.Tokenizer.CountMessages
+func (h Heuristic) CountMessages(model string, messages []ports.Message) int {
+	return countMessages(h, model, messages)
+}
+
+// countMessages counts the tokens of a conversation with the tokens framing it
+func countMessages(tokenizer ports.Tokenizer, model string, messages []ports.Message) int {
+	tokens := tokensPerReply
+	for _, message := range messages {
+		tokens += tokensPerMessage + tokenizer.Count(model, message.Role) + tokenizer.Count(model, message.Content)
+	}
+	return tokens
+}
+
+// Estimate estimates the tokens of a text from its characters, as BPE encodings tend to split
+// them: four ASCII characters per token, a token per CJK character and two other characters,
+// such as Cyrillic letters, per token
+func Estimate(text string) int {
+	var ascii, cjk, other int
+	for _, r := range text {
+		switch {
+		case r <= unicode.MaxASCII:
+			ascii++
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+		default:
+			other++
+		}
+	}
+	return (ascii+3)/4 + cjk + (other+1)/2
+}
@@ -0,0 +1,53 @@
+package tokenizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
+)
+
+func TestTokenizer_Count(t *testing.T) {
+	tok := New()
+
+	tests := []struct {
+		name  string
+		model string
+		text  string
+		want  int
+	}{
+		{"cl100k model", "gpt-4", "Привет, как дела?", 8},
+		{"o200k model", "gpt-4o-mini", "Привет, как дела?", 6},
+		{"model with vendor prefix", "openai/GPT-4o", "Привет, как дела?", 6},
+		{"newer model by prefix", "o3-mini", "hello world", 2},
+		{"empty text", "gpt-4o", "", 0},
+		{"unknown model is estimated", "claude-sonnet-4", "tiktoken is great!", Estimate("tiktoken is great!")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tok.Count(tt.model, tt.text))
+		})
+	}
+}
+
+func TestTokenizer_CountMessages(t *testing.T) {
+	tok := New()
+	messages := []ports.Message{
+		{Role: "system", Content: "You are a helpful assistant."},
+		{Role: "user", Content: "hello world"},
+	}
+
+	// Every message is framed by three tokens and the reply primed by three more
+	want := 3 + (3 + 1 + 6) + (3 + 1 + 2)
+	assert.Equal(t, want, tok.CountMessages("gpt-4", messages))
+	assert.Equal(t, tokensPerReply, tok.CountMessages("gpt-4", nil))
+	assert.Equal(t, Heuristic{}.CountMessages("llama3", messages), tok.CountMessages("llama3", messages))
+}
+
+func TestEstimate(t *testing.T) {
+	assert.Equal(t, 0, Estimate(""))
+	assert.Equal(t, 3, Estimate("hello world"), "ASCII text is four characters per token")
+	assert.Equal(t, 3, Estimate("привет"), "Cyrillic text is two characters per token")
+	assert.Equal(t, 2, Estimate("你好"), "CJK text is a token per character")
+}
@@ -0,0 +1,32 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EncodeToolArguments returns the arguments of a tool call as the JSON string of the
+// OpenAI-compatible chat APIs; no arguments are encoded as "{}"
+func EncodeToolArguments(arguments map[string]interface{}) (string, error) {
+	if arguments == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tool arguments: %w", err)
+	}
+	return string(data), nil
+}
+
+// DecodeToolArguments reads the arguments of a tool call from the JSON string of the
+// OpenAI-compatible chat APIs; an empty string has no arguments
+func DecodeToolArguments(arguments string) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{})
+	if arguments == "" {
+		return decoded, nil
+	}
+	if err := json.Unmarshal([]byte(arguments), &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode tool arguments: %w", err)
+	}
+	return decoded, nil
+}
@@ -81,9 +81,13 @@ func (p *Provider) Chat(ctx context.Context, req *llm.CompletionRequest) (*llm.C
 	}
 
 	// Convert to z.ai chat completion request
+	messages, err := convertMessages(req.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("zai: %w", err)
+	}
 	zaiReq := &zaiChatRequest{
 		Model:    model,
-		Messages: convertMessages(req.Messages),
+		Messages: messages,
 	}
 
 	// Add optional parameters
@@ -93,6 +97,10 @@ func (p *Provider) Chat(ctx context.Context, req *llm.CompletionRequest) (*llm.C
 	if req.MaxTokens > 0 {
 		zaiReq.MaxTokens = req.MaxTokens
 	}
+	if len(req.Tools) > 0 {
+		zaiReq.Tools = convertTools(req.Tools)
+		zaiReq.ToolChoice = "auto"
+	}
 
 	// Marshal request
 	reqBody, err := json.Marshal(zaiReq)
@@ -154,8 +162,12 @@ func (p *Provider) Chat(ctx context.Context, req *llm.CompletionRequest) (*llm.C
 		"total_tokens", zaiResp.Usage.TotalTokens,
 		"finish_reason", zaiResp.Choices[0].FinishReason)
 
-	// Extract content from response
+	// Extract content and tool calls from response
 	content := zaiResp.Choices[0].Message.Content
+	toolCalls, err := convertToolCalls(zaiResp.Choices[0].Message.ToolCalls)
+	if err != nil {
+		return nil, fmt.Errorf("zai: %w", err)
+	}
 
 	// Build metadata
 	metadata := map[string]interface{}{
@@ -175,6 +187,7 @@ func (p *Provider) Chat(ctx context.Context, req *llm.CompletionRequest) (*llm.C
 		Content:    content,
 		Model:      zaiResp.Model,
 		TokensUsed: zaiResp.Usage.TotalTokens,
+		ToolCalls:  toolCalls,
 		Metadata:   metadata,
 	}, nil
 }
@@ -188,9 +201,13 @@ func (p *Provider) Stream(ctx context.Context, req *llm.CompletionRequest) (<-ch
 	}
 
 	// Convert to z.ai chat completion request with streaming enabled
+	messages, err := convertMessages(req.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("zai: %w", err)
+	}
 	zaiReq := &zaiChatRequest{
 		Model:    model,
-		Messages: convertMessages(req.Messages),
+		Messages: messages,
 		Stream:   true,
 	}
 
@@ -323,16 +340,60 @@ func (p *Provider) IsAvailable(ctx context.Context) bool {
 }
 
 // convertMessages converts llm.Messages to z.ai messages
-func convertMessages(messages []*llm.Message) []zaiMessage {
+func convertMessages(messages []*llm.Message) ([]zaiMessage, error) {
 	zaiMessages := make([]zaiMessage, 0, len(messages))
 
 	for _, msg := range messages {
 		zaiMsg := zaiMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		}
+		for _, call := range msg.ToolCalls {
+			arguments, err := llm.EncodeToolArguments(call.Arguments)
+			if err != nil {
+				return nil, err
+			}
+			zaiMsg.ToolCalls = append(zaiMsg.ToolCalls, zaiToolCall{
+				ID:       call.ID,
+				Type:     "function",
+				Function: &zaiFunctionCall{Name: call.Name, Arguments: arguments},
+			})
 		}
 		zaiMessages = append(zaiMessages, zaiMsg)
 	}
 
-	return zaiMessages
+	return zaiMessages, nil
+}
+
+// convertTools converts llm.ToolDefinitions to z.ai function tools
+func convertTools(tools []*llm.ToolDefinition) []zaiTool {
+	zaiTools := make([]zaiTool, 0, len(tools))
+	for _, tool := range tools {
+		zaiTools = append(zaiTools, zaiTool{
+			Type: "function",
+			Function: &zaiFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	return zaiTools
+}
+
+// convertToolCalls converts the tool calls of a z.ai reply to llm.ToolCalls
+func convertToolCalls(calls []zaiToolCall) ([]*llm.ToolCall, error) {
+	var toolCalls []*llm.ToolCall
+	for _, call := range calls {
+		if call.Function == nil {
+			continue
+		}
+		arguments, err := llm.DecodeToolArguments(call.Function.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("tool call %s: %w", call.ID, err)
+		}
+		toolCalls = append(toolCalls, &llm.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments})
+	}
+	return toolCalls, nil
 }
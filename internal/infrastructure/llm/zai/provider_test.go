@@ -175,6 +175,82 @@ func TestChat_BasicRequest(t *testing.T) {
 	}
 }
 
+func TestChat_ToolCalls(t *testing.T) {
+	// Create mock server
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req zaiChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+
+		if len(req.Tools) != 1 || req.Tools[0].Function.Name != "weather" || req.ToolChoice != "auto" {
+			t.Errorf("Expected the weather tool in request, got %+v", req.Tools)
+		}
+
+		// The previous tool call and its result are sent back
+		if len(req.Messages) != 3 {
+			t.Fatalf("Expected 3 messages, got %d", len(req.Messages))
+		}
+		call := req.Messages[1].ToolCalls
+		if len(call) != 1 || call[0].ID != "call_0" || call[0].Function.Arguments != `{"city":"Berlin"}` {
+			t.Errorf("Expected the previous tool call, got %+v", call)
+		}
+		if req.Messages[2].Role != "tool" || req.Messages[2].ToolCallID != "call_0" {
+			t.Errorf("Expected the result of the previous tool call, got %+v", req.Messages[2])
+		}
+
+		resp := zaiChatResponse{
+			Model: "glm-4.7",
+			Choices: []zaiChoice{
+				{
+					Message: zaiResponseMessage{
+						Role: "assistant",
+						ToolCalls: []zaiToolCall{
+							{ID: "call_1", Type: "function", Function: &zaiFunctionCall{Name: "weather", Arguments: `{"city":"Rome"}`}},
+							{ID: "call_2", Type: "function", Function: &zaiFunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+						},
+					},
+					FinishReason: "tool_calls",
+				},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	provider, err := NewProvider(&Config{
+		APIKey:  "test-api-key",
+		BaseURL: ts.URL,
+		Model:   "glm-4.7",
+	}, slog.Default())
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	resp, err := provider.Chat(context.Background(), &llm.CompletionRequest{
+		Messages: []*llm.Message{
+			{Role: "user", Content: "Weather in Berlin, Rome and Paris?"},
+			{Role: "assistant", ToolCalls: []*llm.ToolCall{{ID: "call_0", Name: "weather", Arguments: map[string]interface{}{"city": "Berlin"}}}},
+			{Role: "tool", ToolCallID: "call_0", Content: "12°C"},
+		},
+		Tools: []*llm.ToolDefinition{{Name: "weather", Description: "Current weather", Parameters: map[string]interface{}{"type": "object"}}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(resp.ToolCalls) != 2 {
+		t.Fatalf("Expected 2 tool calls, got %d", len(resp.ToolCalls))
+	}
+	for i, city := range []string{"Rome", "Paris"} {
+		if resp.ToolCalls[i].Name != "weather" || resp.ToolCalls[i].Arguments["city"] != city {
+			t.Errorf("Expected tool call %d for %s, got %+v", i, city, resp.ToolCalls[i])
+		}
+	}
+}
+
 func TestChat_WithTemperature(t *testing.T) {
 	// Create mock server
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -489,7 +565,10 @@ func TestConvertMessages(t *testing.T) {
 		{Role: "assistant", Content: "Hi there"},
 	}
 
-	zaiMessages := convertMessages(messages)
+	zaiMessages, err := convertMessages(messages)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
 	if len(zaiMessages) != 3 {
 		t.Errorf("Expected 3 messages, got %d", len(zaiMessages))
@@ -52,9 +52,15 @@ type zaiFunction struct {
 
 // zaiToolCall represents a tool call made by the model
 type zaiToolCall struct {
-	ID       string       `json:"id"`       // Tool call ID
-	Type     string       `json:"type"`     // "function"
-	Function *zaiFunction `json:"function"` // Function name and arguments
+	ID       string           `json:"id"`       // Tool call ID
+	Type     string           `json:"type"`     // "function"
+	Function *zaiFunctionCall `json:"function"` // Function name and arguments
+}
+
+// zaiFunctionCall represents the function called by a tool call
+type zaiFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON object of the arguments
 }
 
 // zaiThinking represents thinking mode configuration
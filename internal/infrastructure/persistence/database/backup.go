@@ -0,0 +1,355 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Commands used to back up and restore PostgreSQL databases
+var (
+	pgDumpCommand = "pg_dump"
+	psqlCommand   = "psql"
+)
+
+// gzipMagic are the first bytes of a gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Compile-time check that DB implements Backup
+var _ Backup = (*DB)(nil)
+
+// Backup writes a consistent copy of the database to path while the database stays online.
+// SQLite databases are copied with VACUUM INTO, PostgreSQL databases are dumped with pg_dump
+// as plain SQL. The file is written next to path first and renamed when complete.
+func (d *DB) Backup(ctx context.Context, path string, compress bool) error {
+	d.logger.Info("Creating database backup", "type", d.config.Type, "path", path, "compress", compress)
+
+	var err error
+	switch d.config.Type {
+	case "sqlite":
+		err = d.backupSQLite(ctx, path, compress)
+	case "postgres":
+		err = d.backupPostgres(ctx, path, compress)
+	default:
+		err = fmt.Errorf("unsupported database type for backup: %s", d.config.Type)
+	}
+	if err != nil {
+		d.logger.Error("Failed to create database backup", "path", path, "error", err)
+		return err
+	}
+
+	d.logger.Info("Database backup created", "path", path)
+	return nil
+}
+
+// Restore replaces the contents of the database with a backup created by Backup
+// and applies pending migrations, so older backups are brought up to the current schema.
+// Gzip-compressed backups are detected automatically.
+func (d *DB) Restore(ctx context.Context, path string) error {
+	d.logger.Info("Restoring database backup", "type", d.config.Type, "path", path)
+
+	var err error
+	switch d.config.Type {
+	case "sqlite":
+		err = d.restoreSQLite(ctx, path)
+	case "postgres":
+		err = d.restorePostgres(ctx, path)
+	default:
+		err = fmt.Errorf("unsupported database type for restore: %s", d.config.Type)
+	}
+	if err != nil {
+		d.logger.Error("Failed to restore database backup", "path", path, "error", err)
+		return err
+	}
+
+	if err := d.Migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate restored database: %w", err)
+	}
+
+	d.logger.Info("Database backup restored", "path", path)
+	return nil
+}
+
+// backupSQLite copies the database with VACUUM INTO, which reads a single
+// transaction snapshot and does not block writers for long
+func (d *DB) backupSQLite(ctx context.Context, path string, compress bool) error {
+	snapshot := path + ".snapshot"
+	if compress {
+		tmp, err := os.CreateTemp(filepath.Dir(path), ".nexflow-backup-*.db")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		snapshot = tmp.Name()
+		tmp.Close()
+	}
+	// VACUUM INTO refuses to overwrite an existing file
+	if err := os.Remove(snapshot); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	defer os.Remove(snapshot)
+
+	if _, err := d.db.ExecContext(ctx, "VACUUM INTO ?", snapshot); err != nil {
+		return fmt.Errorf("failed to copy sqlite database: %w", err)
+	}
+
+	if !compress {
+		return os.Rename(snapshot, path)
+	}
+
+	src, err := os.Open(snapshot)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return writeBackupFile(path, true, func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	})
+}
+
+// restoreSQLite copies the backup into the open database page by page with the
+// SQLite online backup API, so open connections see the restored data
+func (d *DB) restoreSQLite(ctx context.Context, path string) error {
+	srcPath, cleanup, err := decompressedBackup(path)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	srcDB, err := sql.Open("sqlite3", "file:"+srcPath+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer srcDB.Close()
+
+	var check string
+	if err := srcDB.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&check); err != nil {
+		return fmt.Errorf("failed to check backup: %w", err)
+	}
+	if check != "ok" {
+		return fmt.Errorf("backup is corrupt: %s", check)
+	}
+
+	srcConn, err := srcDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	dstConn, err := d.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+
+	return dstConn.Raw(func(dst any) error {
+		return srcConn.Raw(func(src any) error {
+			dstSQLite, ok := dst.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected sqlite driver connection %T", dst)
+			}
+			srcSQLite, ok := src.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected sqlite driver connection %T", src)
+			}
+			return copySQLite(ctx, dstSQLite, srcSQLite)
+		})
+	})
+}
+
+// copySQLite copies the main database of src into dst
+func copySQLite(ctx context.Context, dst, src *sqlite3.SQLiteConn) error {
+	backup, err := dst.Backup("main", src, "main")
+	if err != nil {
+		return fmt.Errorf("failed to start restore: %w", err)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			backup.Close()
+			return err
+		}
+
+		done, err := backup.Step(256)
+		if err != nil {
+			backup.Close()
+			return fmt.Errorf("failed to restore pages: %w", err)
+		}
+		if done {
+			break
+		}
+	}
+
+	if err := backup.Finish(); err != nil {
+		return fmt.Errorf("failed to finish restore: %w", err)
+	}
+	return nil
+}
+
+// backupPostgres dumps the database as plain SQL that drops and recreates all objects
+func (d *DB) backupPostgres(ctx context.Context, path string, compress bool) error {
+	return writeBackupFile(path, compress, func(w io.Writer) error {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, pgDumpCommand,
+			"--dbname="+d.config.Path,
+			"--format=plain",
+			"--clean",
+			"--if-exists",
+			"--no-owner",
+			"--no-privileges",
+		)
+		cmd.Stdout = w
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pg_dump failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return nil
+	})
+}
+
+// restorePostgres replays a pg_dump backup with psql in a single transaction
+func (d *DB) restorePostgres(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+
+	r, err := backupReader(f)
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, psqlCommand,
+		"--dbname="+d.config.Path,
+		"--quiet",
+		"--no-psqlrc",
+		"--single-transaction",
+		"--set=ON_ERROR_STOP=1",
+		"--file=-",
+	)
+	cmd.Stdin = r
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("psql failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+// writeBackupFile writes a backup to a temporary file next to path, optionally
+// gzip-compressed, and renames it to path once it is complete
+func writeBackupFile(path string, compress bool, write func(w io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".nexflow-backup-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	buf := bufio.NewWriter(tmp)
+	var w io.Writer = buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(buf)
+		w = gz
+	}
+
+	if err = write(w); err != nil {
+		return err
+	}
+	if gz != nil {
+		if err = gz.Close(); err != nil {
+			return err
+		}
+	}
+	if err = buf.Flush(); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// backupReader returns a reader of the uncompressed backup contents
+func backupReader(f *os.File) (io.Reader, error) {
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if !bytes.Equal(magic, gzipMagic) {
+		return br, nil
+	}
+
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed backup: %w", err)
+	}
+	return gz, nil
+}
+
+// decompressedBackup returns the path of an uncompressed copy of the backup.
+// Uncompressed backups are used in place; the cleanup function removes temporary copies.
+func decompressedBackup(path string) (string, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(gzipMagic))
+	if _, err := io.ReadFull(f, magic); err != nil || !bytes.Equal(magic, gzipMagic) {
+		return path, func() {}, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", nil, err
+	}
+
+	r, err := backupReader(f)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "nexflow-restore-*.db")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to decompress backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return tmp.Name(), cleanup, nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func countUsers(t *testing.T, d *DB) int {
+	t.Helper()
+	users, err := d.ListUsers(context.Background())
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	return len(users)
+}
+
+func TestBackupRestore_SQLite(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		name := "plain"
+		if compress {
+			name = "gzip"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			testDB, _ := newMigrationTestDB(t, "")
+			ctx := context.Background()
+
+			if err := testDB.Migrate(ctx); err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			if _, err := testDB.CreateUser(ctx, CreateUserParams{ID: "user-1", Channel: "telegram", ChannelUserID: "1", CreatedAt: "2024-01-01T00:00:00Z"}); err != nil {
+				t.Fatalf("CreateUser() error = %v", err)
+			}
+
+			backupPath := filepath.Join(t.TempDir(), "backup.db")
+			if err := testDB.Backup(ctx, backupPath, compress); err != nil {
+				t.Fatalf("Backup() error = %v", err)
+			}
+
+			data, err := os.ReadFile(backupPath)
+			if err != nil {
+				t.Fatalf("failed to read backup: %v", err)
+			}
+			if isGzip := len(data) > 1 && data[0] == gzipMagic[0] && data[1] == gzipMagic[1]; isGzip != compress {
+				t.Errorf("backup compressed = %t, want %t", isGzip, compress)
+			}
+
+			if _, err := testDB.CreateUser(ctx, CreateUserParams{ID: "user-2", Channel: "telegram", ChannelUserID: "2", CreatedAt: "2024-01-02T00:00:00Z"}); err != nil {
+				t.Fatalf("CreateUser() error = %v", err)
+			}
+			if got := countUsers(t, testDB); got != 2 {
+				t.Fatalf("users before restore = %d, want 2", got)
+			}
+
+			if err := testDB.Restore(ctx, backupPath); err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+			if got := countUsers(t, testDB); got != 1 {
+				t.Errorf("users after restore = %d, want 1", got)
+			}
+
+			status, err := testDB.MigrationStatus(ctx)
+			if err != nil {
+				t.Fatalf("MigrationStatus() error = %v", err)
+			}
+			if status.Version != 34 || status.Dirty {
+				t.Errorf("MigrationStatus() after restore = %+v, want version 34, not dirty", status)
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(backupPath))
+			if err != nil {
+				t.Fatalf("failed to list backup directory: %v", err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("backup directory has %d entries, want only the backup", len(entries))
+			}
+		})
+	}
+}
+
+func TestRestore_RejectsInvalidBackup(t *testing.T) {
+	testDB, _ := newMigrationTestDB(t, "")
+	ctx := context.Background()
+
+	if err := testDB.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := os.WriteFile(backupPath, []byte("not a database"), 0o600); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+
+	if err := testDB.Restore(ctx, backupPath); err == nil {
+		t.Error("Restore() expected error for invalid backup")
+	}
+	if err := testDB.Restore(ctx, filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("Restore() expected error for missing backup")
+	}
+}
@@ -9,10 +9,15 @@ import (
 type DBConfig struct {
 	Type            string        // "sqlite" or "postgres"
 	Path            string        // connection string or file path
-	MigrationsPath  string        // path to migrations directory
+	MigrationsPath  string        // path to migrations directory (empty = embedded migrations)
 	MaxOpenConns    int           // maximum open connections
 	MaxIdleConns    int           // maximum idle connections
 	ConnMaxLifetime time.Duration // maximum connection lifetime
+	ConnMaxIdleTime time.Duration // maximum time a connection may stay idle (0 = no limit)
+	BusyTimeout     time.Duration // SQLite: how long to wait for a lock before failing with SQLITE_BUSY
+	JournalMode     string        // SQLite: journal mode, e.g. "wal"
+
+	SlowQueryThreshold time.Duration // queries taking at least this long are logged (0 = disabled)
 }
 
 // Validate checks if configuration is valid.
@@ -26,5 +31,14 @@ func (c *DBConfig) Validate() error {
 	if c.Type != "sqlite" && c.Type != "postgres" {
 		return fmt.Errorf("unsupported database type: %s, must be 'sqlite' or 'postgres'", c.Type)
 	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		return fmt.Errorf("connection pool sizes must be non-negative")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		return fmt.Errorf("max idle connections (%d) must not exceed max open connections (%d)", c.MaxIdleConns, c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 || c.BusyTimeout < 0 || c.SlowQueryThreshold < 0 {
+		return fmt.Errorf("connection timeouts must be non-negative")
+	}
 	return nil
 }
@@ -7,14 +7,17 @@ import (
 
 	"github.com/atumaikin/nexflow/internal/shared/config"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 // DB is main database implementation
 type DB struct {
 	*Queries
-	db     *sql.DB
-	config *DBConfig
-	logger logging.Logger
+	db      *sql.DB
+	config  *DBConfig
+	logger  logging.Logger
+	metrics *DBMetrics
+	tracer  *tracing.Tracer
 }
 
 // NewDatabase creates a new database instance.
@@ -27,6 +30,11 @@ func NewDatabase(cfg *config.DatabaseConfig, opts ...Option) (Database, error) {
 		MaxOpenConns:    cfg.MaxOpenConns,
 		MaxIdleConns:    cfg.MaxIdleConns,
 		ConnMaxLifetime: cfg.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.ConnMaxIdleTime,
+		BusyTimeout:     cfg.BusyTimeout,
+		JournalMode:     cfg.JournalMode,
+
+		SlowQueryThreshold: cfg.SlowQueryThreshold,
 	}
 
 	// Validate configuration
@@ -44,13 +52,21 @@ func NewDatabase(cfg *config.DatabaseConfig, opts ...Option) (Database, error) {
 	if dbConfig.ConnMaxLifetime == 0 {
 		dbConfig.ConnMaxLifetime = 5 * time.Minute
 	}
+	if dbConfig.Type == "sqlite" {
+		if dbConfig.BusyTimeout == 0 {
+			dbConfig.BusyTimeout = 5 * time.Second
+		}
+		if dbConfig.JournalMode == "" {
+			dbConfig.JournalMode = "wal"
+		}
+	}
 
 	var db *sql.DB
 	var err error
 
 	switch dbConfig.Type {
 	case "sqlite":
-		db, err = openSQLite(dbConfig.Path)
+		db, err = openSQLite(dbConfig.Path, dbConfig.BusyTimeout, dbConfig.JournalMode)
 	case "postgres":
 		db, err = openPostgres(dbConfig.Path)
 	}
@@ -63,15 +79,14 @@ func NewDatabase(cfg *config.DatabaseConfig, opts ...Option) (Database, error) {
 	db.SetMaxOpenConns(dbConfig.MaxOpenConns)
 	db.SetMaxIdleConns(dbConfig.MaxIdleConns)
 	db.SetConnMaxLifetime(dbConfig.ConnMaxLifetime)
-
-	queries := New(db)
+	db.SetConnMaxIdleTime(dbConfig.ConnMaxIdleTime)
 
 	// Create database instance with default NoopLogger
 	dbInstance := &DB{
-		Queries: queries,
 		db:      db,
 		config:  dbConfig,
 		logger:  logging.NewNoopLogger(), // Default to NoopLogger
+		metrics: NewDBMetrics(),
 	}
 
 	// Apply options
@@ -79,6 +94,9 @@ func NewDatabase(cfg *config.DatabaseConfig, opts ...Option) (Database, error) {
 		opt(dbInstance)
 	}
 
+	// Queries are instrumented after options are applied so slow queries use the configured logger and tracer
+	dbInstance.Queries = New(newInstrumentedDB(db, dbInstance.metrics.Queries, dbConfig.SlowQueryThreshold, dbInstance.logger, dbInstance.tracer))
+
 	return dbInstance, nil
 }
 
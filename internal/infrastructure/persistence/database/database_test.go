@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
 	"github.com/google/uuid"
 )
@@ -30,9 +31,23 @@ CREATE TABLE sessions (
     user_id TEXT NOT NULL,
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
     updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    pinned INTEGER NOT NULL DEFAULT 0,
+    workspace_id TEXT NOT NULL DEFAULT 'default',
+    title TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+CREATE TABLE session_attributes (
+    session_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    expires_at TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (session_id, key),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
 CREATE TABLE messages (
     id TEXT PRIMARY KEY,
     session_id TEXT NOT NULL,
@@ -42,6 +57,16 @@ CREATE TABLE messages (
     FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
 );
 
+CREATE VIRTUAL TABLE messages_fts USING fts4(content="messages", content, tokenize=unicode61);
+
+CREATE TRIGGER messages_fts_after_insert AFTER INSERT ON messages BEGIN
+    INSERT INTO messages_fts(docid, content) VALUES (new.rowid, new.content);
+END;
+
+CREATE TRIGGER messages_fts_before_delete BEFORE DELETE ON messages BEGIN
+    DELETE FROM messages_fts WHERE docid = old.rowid;
+END;
+
 CREATE TABLE tasks (
     id TEXT PRIMARY KEY,
     session_id TEXT NOT NULL,
@@ -52,6 +77,8 @@ CREATE TABLE tasks (
     error TEXT,
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
     updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    started_at TEXT,
+    finished_at TEXT,
     FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
 );
 
@@ -62,19 +89,42 @@ CREATE TABLE skills (
     location TEXT NOT NULL,
     permissions TEXT NOT NULL,
     metadata TEXT NOT NULL,
-    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    disabled INTEGER NOT NULL DEFAULT 0,
+    workspace_id TEXT NOT NULL DEFAULT ''
 );
 
 CREATE TABLE schedules (
     id TEXT PRIMARY KEY,
-    skill TEXT NOT NULL,
+    skill TEXT,
     cron_expression TEXT NOT NULL,
     input TEXT NOT NULL,
     enabled INTEGER NOT NULL DEFAULT 1,
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    jitter_seconds INTEGER NOT NULL DEFAULT 0,
+    schedule_type TEXT NOT NULL DEFAULT 'cron',
+    run_at TEXT,
+    missed_run_policy TEXT NOT NULL DEFAULT 'skip',
+    target_connector TEXT NOT NULL DEFAULT '',
+    target_user_id TEXT NOT NULL DEFAULT '',
+    prompt TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (skill) REFERENCES skills(name) ON DELETE CASCADE
 );
 
+CREATE TABLE schedule_runs (
+    id TEXT PRIMARY KEY,
+    schedule_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    scheduled_at TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    finished_at TEXT,
+    output TEXT,
+    error TEXT,
+    FOREIGN KEY (schedule_id) REFERENCES schedules(id) ON DELETE CASCADE
+);
+
 CREATE TABLE logs (
     id TEXT PRIMARY KEY,
     level TEXT NOT NULL,
@@ -83,6 +133,239 @@ CREATE TABLE logs (
     metadata TEXT,
     created_at TEXT NOT NULL DEFAULT (datetime('now'))
 );
+
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    revoked_at TEXT,
+    workspace_id TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE events (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL
+);
+
+CREATE TABLE webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    endpoint TEXT NOT NULL,
+    url TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE dead_letters (
+    id TEXT PRIMARY KEY,
+    subscription TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE processed_messages (
+    key TEXT PRIMARY KEY,
+    expires_at TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE message_dead_letters (
+    id TEXT PRIMARY KEY,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE workspaces (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    max_tokens INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE user_preferences (
+    user_id TEXT PRIMARY KEY,
+    language TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    timezone TEXT NOT NULL DEFAULT '',
+    verbosity TEXT NOT NULL DEFAULT '',
+    updated_at TEXT NOT NULL,
+    instructions TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE analytics_counters (
+    day TEXT NOT NULL,
+    metric TEXT NOT NULL,
+    dimension TEXT NOT NULL DEFAULT '',
+    value REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, metric, dimension)
+);
+
+CREATE TABLE analytics_active_users (
+    day TEXT NOT NULL,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (day, connector, user_id)
+);
+
+CREATE TABLE user_data_erasures (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    requested_by TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    sessions INTEGER NOT NULL DEFAULT 0,
+    messages INTEGER NOT NULL DEFAULT 0,
+    tasks INTEGER NOT NULL DEFAULT 0,
+    attributes INTEGER NOT NULL DEFAULT 0,
+    preferences INTEGER NOT NULL DEFAULT 0,
+    verified INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE crash_reports (
+    id TEXT PRIMARY KEY,
+    component TEXT NOT NULL,
+    operation TEXT NOT NULL DEFAULT '',
+    panic TEXT NOT NULL,
+    stack TEXT NOT NULL DEFAULT '',
+    attributes TEXT NOT NULL DEFAULT '{}',
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE document_chunks (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    document_id TEXT NOT NULL,
+    document_name TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    embedding TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE message_feedback (
+    id TEXT PRIMARY KEY,
+    message_id TEXT NOT NULL UNIQUE,
+    session_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    rating TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    day TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE llm_usage (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    workspace_id TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    input_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    total_tokens INTEGER NOT NULL DEFAULT 0,
+    cost REAL NOT NULL DEFAULT 0,
+    month TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE idempotency_keys (
+    key TEXT PRIMARY KEY,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    content_type TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    expires_at TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE message_jobs (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    content TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message_id TEXT NOT NULL DEFAULT '',
+    reply TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    finished_at TEXT,
+    batch_id TEXT NOT NULL DEFAULT '',
+    batch_index INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE spilled_messages (
+    id TEXT PRIMARY KEY,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    sequence INTEGER NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE task_attempts (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    retryable INTEGER NOT NULL DEFAULT 0,
+    started_at TEXT NOT NULL,
+    finished_at TEXT NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE TABLE jobs (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT NOT NULL DEFAULT '',
+    run_at TEXT NOT NULL,
+    locked_by TEXT NOT NULL DEFAULT '',
+    locked_until TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    finished_at TEXT,
+    leader_only INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    expires_at TEXT NOT NULL
+);
 `
 
 func setupTestDB(t *testing.T) *sql.DB {
@@ -166,6 +449,24 @@ func TestDBConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "unsupported database type",
 		},
+		{
+			name:    "Negative pool size",
+			config:  &DBConfig{Type: "sqlite", Path: "./test.db", MaxOpenConns: -1},
+			wantErr: true,
+			errMsg:  "must be non-negative",
+		},
+		{
+			name:    "Idle connections exceed open connections",
+			config:  &DBConfig{Type: "sqlite", Path: "./test.db", MaxOpenConns: 5, MaxIdleConns: 10},
+			wantErr: true,
+			errMsg:  "must not exceed max open connections",
+		},
+		{
+			name:    "Negative busy timeout",
+			config:  &DBConfig{Type: "sqlite", Path: "./test.db", BusyTimeout: -time.Second},
+			wantErr: true,
+			errMsg:  "timeouts must be non-negative",
+		},
 		{
 			name:    "Empty config",
 			config:  &DBConfig{},
@@ -200,7 +501,7 @@ func TestCreateAndGetUser(t *testing.T) {
 	db := setupTestDBInstance(t)
 
 	userID := uuid.New().String()
-	channel := "telegram"
+	channel := valueobject.ChannelTelegram
 	channelUserID := "123456"
 
 	// Create user
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -229,3 +230,55 @@ func (l *TestLogger) WarnContext(ctx context.Context, msg string, args ...any) {
 func (l *TestLogger) WithContext(ctx context.Context) logging.Logger {
 	return l
 }
+
+func TestNewDatabase_SQLitePragmas(t *testing.T) {
+	db, err := NewDatabase(&config.DatabaseConfig{
+		Type: "sqlite",
+		Path: filepath.Join(t.TempDir(), "pragmas.db"),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlDB := db.(*DB).GetDB()
+	sqlDB.SetMaxIdleConns(0)
+
+	// Every pooled connection gets the pragmas, so check over several fresh connections
+	for i := 0; i < 3; i++ {
+		var journalMode string
+		require.NoError(t, sqlDB.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+		assert.Equal(t, "wal", journalMode)
+
+		var busyTimeout int
+		require.NoError(t, sqlDB.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout))
+		assert.Equal(t, 5000, busyTimeout)
+
+		var foreignKeys int
+		require.NoError(t, sqlDB.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+		assert.Equal(t, 1, foreignKeys)
+	}
+}
+
+func TestDB_Health(t *testing.T) {
+	db, err := NewDatabase(&config.DatabaseConfig{
+		Type: "sqlite",
+		Path: ":memory:",
+	})
+	require.NoError(t, err)
+	dbImpl := db.(*DB)
+
+	status := db.Health(context.Background())
+	assert.True(t, status.Healthy)
+	assert.Empty(t, status.Error)
+	assert.Equal(t, 25, status.Stats.MaxOpenConnections)
+	assert.Equal(t, int64(1), dbImpl.Metrics().Up.Get())
+	assert.Equal(t, int64(25), dbImpl.Metrics().MaxOpenConnections.Get())
+	assert.Equal(t, int64(1), dbImpl.Metrics().PingDuration.Count())
+
+	require.NoError(t, db.Close())
+
+	status = db.Health(context.Background())
+	assert.False(t, status.Healthy)
+	assert.NotEmpty(t, status.Error)
+	assert.Equal(t, int64(0), dbImpl.Metrics().Up.Get())
+	assert.Equal(t, int64(1), dbImpl.Metrics().PingsFailed.Get())
+}
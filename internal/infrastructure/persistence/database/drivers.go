@@ -4,13 +4,34 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// openSQLite opens a SQLite database connection with context timeout
-func openSQLite(path string) (*sql.DB, error) {
+// sqliteDSN adds the connection pragmas to a SQLite path. The pragmas are passed
+// as DSN parameters so that the driver applies them to every pooled connection.
+func sqliteDSN(path string, busyTimeout time.Duration, journalMode string) string {
+	params := url.Values{}
+	params.Set("_foreign_keys", "1")
+	params.Set("_busy_timeout", strconv.FormatInt(busyTimeout.Milliseconds(), 10))
+	if journalMode != "" {
+		params.Set("_journal_mode", strings.ToUpper(journalMode))
+	}
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + params.Encode()
+}
+
+// openSQLite opens a SQLite database connection with context timeout.
+// Foreign keys are enabled and the busy timeout and journal mode are set on every connection.
+func openSQLite(path string, busyTimeout time.Duration, journalMode string) (*sql.DB, error) {
 	// Create connector with context timeout
-	db, err := sql.Open("sqlite3", path)
+	db, err := sql.Open("sqlite3", sqliteDSN(path, busyTimeout, journalMode))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
 	}
@@ -24,12 +45,6 @@ func openSQLite(path string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Enable foreign keys
-	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
-
 	return db, nil
 }
 
@@ -4,7 +4,124 @@
 
 package database
 
-import "database/sql"
+import (
+	"database/sql"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+type AnalyticsActiveUser struct {
+	Day       string `json:"day"`
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+}
+
+type AnalyticsCounter struct {
+	Day       string  `json:"day"`
+	Metric    string  `json:"metric"`
+	Dimension string  `json:"dimension"`
+	Value     float64 `json:"value"`
+}
+
+type ApiKey struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	KeyHash     string         `json:"key_hash"`
+	Prefix      string         `json:"prefix"`
+	Scope       string         `json:"scope"`
+	CreatedAt   string         `json:"created_at"`
+	RevokedAt   sql.NullString `json:"revoked_at"`
+	WorkspaceID string         `json:"workspace_id"`
+}
+
+type CrashReport struct {
+	ID         string `json:"id"`
+	Component  string `json:"component"`
+	Operation  string `json:"operation"`
+	Panic      string `json:"panic"`
+	Stack      string `json:"stack"`
+	Attributes string `json:"attributes"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type DeadLetter struct {
+	ID           string `json:"id"`
+	Subscription string `json:"subscription"`
+	EventType    string `json:"event_type"`
+	Payload      string `json:"payload"`
+	OccurredAt   string `json:"occurred_at"`
+	Attempts     int64  `json:"attempts"`
+	Error        string `json:"error"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type DocumentChunk struct {
+	ID           string `json:"id"`
+	SessionID    string `json:"session_id"`
+	DocumentID   string `json:"document_id"`
+	DocumentName string `json:"document_name"`
+	Position     int64  `json:"position"`
+	Content      string `json:"content"`
+	Embedding    string `json:"embedding"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type Event struct {
+	Seq        int64  `json:"seq"`
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Payload    string `json:"payload"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+type IdempotencyKey struct {
+	Key         string `json:"key"`
+	RequestHash string `json:"request_hash"`
+	StatusCode  int64  `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+	ExpiresAt   string `json:"expires_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type Job struct {
+	ID          string         `json:"id"`
+	Kind        string         `json:"kind"`
+	Payload     string         `json:"payload"`
+	Status      string         `json:"status"`
+	Attempts    int64          `json:"attempts"`
+	MaxAttempts int64          `json:"max_attempts"`
+	Error       string         `json:"error"`
+	RunAt       string         `json:"run_at"`
+	LockedBy    string         `json:"locked_by"`
+	LockedUntil sql.NullString `json:"locked_until"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+	FinishedAt  sql.NullString `json:"finished_at"`
+	LeaderOnly  int64          `json:"leader_only"`
+}
+
+type Lease struct {
+	Name      string `json:"name"`
+	Holder    string `json:"holder"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type LlmUsage struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	WorkspaceID  string  `json:"workspace_id"`
+	SessionID    string  `json:"session_id"`
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	Cost         float64 `json:"cost"`
+	Month        string  `json:"month"`
+	CreatedAt    string  `json:"created_at"`
+}
 
 type Log struct {
 	ID        string         `json:"id"`
@@ -16,54 +133,203 @@ type Log struct {
 }
 
 type Message struct {
+	ID        string                  `json:"id"`
+	SessionID string                  `json:"session_id"`
+	Role      valueobject.MessageRole `json:"role"`
+	Content   string                  `json:"content"`
+	CreatedAt string                  `json:"created_at"`
+}
+
+type MessageDeadLetter struct {
 	ID        string `json:"id"`
-	SessionID string `json:"session_id"`
-	Role      string `json:"role"`
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
 	Content   string `json:"content"`
+	Metadata  string `json:"metadata"`
+	Attempts  int64  `json:"attempts"`
+	Error     string `json:"error"`
 	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
 }
 
-type Schedule struct {
-	ID             string `json:"id"`
-	Skill          string `json:"skill"`
-	CronExpression string `json:"cron_expression"`
-	Input          string `json:"input"`
-	Enabled        int64  `json:"enabled"`
-	CreatedAt      string `json:"created_at"`
-}
-
-type Session struct {
+type MessageFeedback struct {
 	ID        string `json:"id"`
+	MessageID string `json:"message_id"`
+	SessionID string `json:"session_id"`
 	UserID    string `json:"user_id"`
+	Rating    string `json:"rating"`
+	Comment   string `json:"comment"`
+	Day       string `json:"day"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
 
-type Skill struct {
+type MessageJob struct {
+	ID         string         `json:"id"`
+	SessionID  string         `json:"session_id"`
+	Content    string         `json:"content"`
+	Status     string         `json:"status"`
+	MessageID  string         `json:"message_id"`
+	Reply      string         `json:"reply"`
+	Error      string         `json:"error"`
+	CreatedAt  string         `json:"created_at"`
+	UpdatedAt  string         `json:"updated_at"`
+	FinishedAt sql.NullString `json:"finished_at"`
+	BatchID    string         `json:"batch_id"`
+	BatchIndex int64          `json:"batch_index"`
+}
+
+type ProcessedMessage struct {
+	Key       string `json:"key"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type Schedule struct {
+	ID              string                     `json:"id"`
+	Skill           sql.NullString             `json:"skill"`
+	CronExpression  valueobject.CronExpression `json:"cron_expression"`
+	Input           string                     `json:"input"`
+	Enabled         int64                      `json:"enabled"`
+	CreatedAt       string                     `json:"created_at"`
+	Timezone        string                     `json:"timezone"`
+	JitterSeconds   int64                      `json:"jitter_seconds"`
+	ScheduleType    string                     `json:"schedule_type"`
+	RunAt           sql.NullString             `json:"run_at"`
+	MissedRunPolicy string                     `json:"missed_run_policy"`
+	TargetConnector string                     `json:"target_connector"`
+	TargetUserID    string                     `json:"target_user_id"`
+	Prompt          string                     `json:"prompt"`
+	SessionID       string                     `json:"session_id"`
+}
+
+type ScheduleRun struct {
+	ID          string                 `json:"id"`
+	ScheduleID  string                 `json:"schedule_id"`
+	Status      valueobject.TaskStatus `json:"status"`
+	ScheduledAt string                 `json:"scheduled_at"`
+	StartedAt   string                 `json:"started_at"`
+	FinishedAt  sql.NullString         `json:"finished_at"`
+	Output      sql.NullString         `json:"output"`
+	Error       sql.NullString         `json:"error"`
+}
+
+type Session struct {
 	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	Location    string `json:"location"`
-	Permissions string `json:"permissions"`
-	Metadata    string `json:"metadata"`
+	UserID      string `json:"user_id"`
 	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+	Pinned      int64  `json:"pinned"`
+	WorkspaceID string `json:"workspace_id"`
+	Title       string `json:"title"`
 }
 
-type Task struct {
-	ID        string         `json:"id"`
+type SessionAttribute struct {
 	SessionID string         `json:"session_id"`
-	Skill     string         `json:"skill"`
-	Input     string         `json:"input"`
-	Output    sql.NullString `json:"output"`
-	Status    string         `json:"status"`
-	Error     sql.NullString `json:"error"`
+	Key       string         `json:"key"`
+	Value     string         `json:"value"`
+	ExpiresAt sql.NullString `json:"expires_at"`
 	CreatedAt string         `json:"created_at"`
 	UpdatedAt string         `json:"updated_at"`
 }
 
+type Skill struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Version     valueobject.Version `json:"version"`
+	Location    string              `json:"location"`
+	Permissions string              `json:"permissions"`
+	Metadata    string              `json:"metadata"`
+	CreatedAt   string              `json:"created_at"`
+	Disabled    int64               `json:"disabled"`
+	WorkspaceID string              `json:"workspace_id"`
+}
+
+type SpilledMessage struct {
+	ID        string `json:"id"`
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	Content   string `json:"content"`
+	Metadata  string `json:"metadata"`
+	Sequence  int64  `json:"sequence"`
+	CreatedAt string `json:"created_at"`
+}
+
+type Task struct {
+	ID         string                 `json:"id"`
+	SessionID  string                 `json:"session_id"`
+	Skill      string                 `json:"skill"`
+	Input      string                 `json:"input"`
+	Output     sql.NullString         `json:"output"`
+	Status     valueobject.TaskStatus `json:"status"`
+	Error      sql.NullString         `json:"error"`
+	CreatedAt  string                 `json:"created_at"`
+	UpdatedAt  string                 `json:"updated_at"`
+	StartedAt  sql.NullString         `json:"started_at"`
+	FinishedAt sql.NullString         `json:"finished_at"`
+}
+
+type TaskAttempt struct {
+	ID         string                 `json:"id"`
+	TaskID     string                 `json:"task_id"`
+	Attempt    int64                  `json:"attempt"`
+	Status     valueobject.TaskStatus `json:"status"`
+	Error      string                 `json:"error"`
+	Retryable  int64                  `json:"retryable"`
+	StartedAt  string                 `json:"started_at"`
+	FinishedAt string                 `json:"finished_at"`
+}
+
 type User struct {
-	ID            string `json:"id"`
-	Channel       string `json:"channel"`
-	ChannelUserID string `json:"channel_user_id"`
-	CreatedAt     string `json:"created_at"`
+	ID            string              `json:"id"`
+	Channel       valueobject.Channel `json:"channel"`
+	ChannelUserID string              `json:"channel_user_id"`
+	CreatedAt     string              `json:"created_at"`
+}
+
+type UserDataErasure struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	RequestedBy string `json:"requested_by"`
+	Reason      string `json:"reason"`
+	Sessions    int64  `json:"sessions"`
+	Messages    int64  `json:"messages"`
+	Tasks       int64  `json:"tasks"`
+	Attributes  int64  `json:"attributes"`
+	Preferences int64  `json:"preferences"`
+	Verified    int64  `json:"verified"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type UserPreference struct {
+	UserID       string `json:"user_id"`
+	Language     string `json:"language"`
+	Model        string `json:"model"`
+	Timezone     string `json:"timezone"`
+	Verbosity    string `json:"verbosity"`
+	UpdatedAt    string `json:"updated_at"`
+	Instructions string `json:"instructions"`
+}
+
+type WebhookDelivery struct {
+	ID             string `json:"id"`
+	Endpoint       string `json:"endpoint"`
+	URL            string `json:"url"`
+	EventType      string `json:"event_type"`
+	Payload        string `json:"payload"`
+	Status         string `json:"status"`
+	Attempts       int64  `json:"attempts"`
+	ResponseStatus int64  `json:"response_status"`
+	Error          string `json:"error"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type Workspace struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MaxTokens int64  `json:"max_tokens"`
+	CreatedAt string `json:"created_at"`
 }
@@ -6,32 +6,93 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	// A lease is acquired when it is free, expired or already held by the holder
+	AcquireLease(ctx context.Context, arg AcquireLeaseParams) (int64, error)
+	AddAnalyticsActiveUser(ctx context.Context, arg AddAnalyticsActiveUserParams) error
+	// Jobs are claimed for a lease; the job due the longest comes first. Leader-only jobs are
+	// claimed by the leader only. The due condition is repeated for the row being updated, so
+	// that a job claimed concurrently by another instance is not claimed twice.
+	ClaimJob(ctx context.Context, arg ClaimJobParams) (Job, error)
+	CountAnalyticsActiveUsers(ctx context.Context, arg CountAnalyticsActiveUsersParams) ([]CountAnalyticsActiveUsersRow, error)
+	CountLogsOlderThan(ctx context.Context, createdAt string) (int64, error)
+	CountMessageFeedback(ctx context.Context, arg CountMessageFeedbackParams) ([]CountMessageFeedbackRow, error)
+	CountMessagesOlderThan(ctx context.Context, createdAt string) (int64, error)
+	CountSpilledMessages(ctx context.Context, connector string) (int64, error)
+	CountTasksOlderThan(ctx context.Context, createdAt string) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateCrashReport(ctx context.Context, arg CreateCrashReportParams) (CrashReport, error)
+	CreateDeadLetter(ctx context.Context, arg CreateDeadLetterParams) (DeadLetter, error)
+	CreateDocumentChunk(ctx context.Context, arg CreateDocumentChunkParams) error
+	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	CreateJob(ctx context.Context, arg CreateJobParams) (int64, error)
+	CreateLLMUsage(ctx context.Context, arg CreateLLMUsageParams) error
 	CreateLog(ctx context.Context, arg CreateLogParams) (Log, error)
 	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
+	CreateMessageDeadLetter(ctx context.Context, arg CreateMessageDeadLetterParams) (MessageDeadLetter, error)
+	CreateMessageJob(ctx context.Context, arg CreateMessageJobParams) error
 	CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error)
+	CreateScheduleRun(ctx context.Context, arg CreateScheduleRunParams) (ScheduleRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateSkill(ctx context.Context, arg CreateSkillParams) (Skill, error)
+	CreateSpilledMessage(ctx context.Context, arg CreateSpilledMessageParams) error
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
+	CreateTaskAttempt(ctx context.Context, arg CreateTaskAttemptParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserDataErasure(ctx context.Context, arg CreateUserDataErasureParams) (UserDataErasure, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) (Workspace, error)
+	DeleteDeadLetter(ctx context.Context, id string) (int64, error)
+	DeleteDocumentChunks(ctx context.Context, sessionID string) (int64, error)
+	DeleteEventsBySubject(ctx context.Context, arg DeleteEventsBySubjectParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now string) (int64, error)
+	DeleteExpiredProcessedMessages(ctx context.Context, now string) (int64, error)
+	DeleteExpiredSessionAttributes(ctx context.Context, now string) (int64, error)
+	DeleteFinishedJobs(ctx context.Context, finishedBefore string) (int64, error)
+	DeleteFinishedMessageJobs(ctx context.Context, finishedBefore string) (int64, error)
 	DeleteLog(ctx context.Context, id string) error
-	DeleteLogsOlderThan(ctx context.Context, createdAt string) error
+	DeleteLogsOlderThan(ctx context.Context, createdAt string) (int64, error)
 	DeleteMessage(ctx context.Context, id string) error
+	DeleteMessageDeadLetter(ctx context.Context, id string) (int64, error)
+	DeleteMessageDeadLettersByUser(ctx context.Context, arg DeleteMessageDeadLettersByUserParams) (int64, error)
+	DeleteMessagesOlderThan(ctx context.Context, createdAt string) (int64, error)
+	DeletePendingIdempotencyKey(ctx context.Context, key string) (int64, error)
+	DeleteProcessedMessage(ctx context.Context, key string) error
 	DeleteSchedule(ctx context.Context, id string) error
 	DeleteSession(ctx context.Context, id string) error
+	DeleteSessionAttribute(ctx context.Context, arg DeleteSessionAttributeParams) (int64, error)
 	DeleteSkill(ctx context.Context, id string) error
+	DeleteSpilledMessage(ctx context.Context, id string) (int64, error)
 	DeleteTask(ctx context.Context, id string) error
+	DeleteTasksOlderThan(ctx context.Context, createdAt string) (int64, error)
 	DeleteUser(ctx context.Context, id string) error
+	DeleteUserPreferences(ctx context.Context, userID string) (int64, error)
+	DeleteWorkspace(ctx context.Context, id string) (int64, error)
+	// Jobs left pending or running by a stopped process are failed at startup
+	FailUnfinishedMessageJobs(ctx context.Context, arg FailUnfinishedMessageJobsParams) (int64, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetDeadLetter(ctx context.Context, id string) (DeadLetter, error)
+	GetEvent(ctx context.Context, id string) (Event, error)
+	// Idempotency keys are found until they expire; saving keeps an unexpired key
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetJob(ctx context.Context, id string) (Job, error)
+	GetLatestScheduleRun(ctx context.Context, scheduleID string) (ScheduleRun, error)
 	GetLogByID(ctx context.Context, id string) (Log, error)
 	GetLogsByDateRange(ctx context.Context, arg GetLogsByDateRangeParams) ([]Log, error)
 	GetLogsByLevel(ctx context.Context, arg GetLogsByLevelParams) ([]Log, error)
 	GetLogsBySource(ctx context.Context, arg GetLogsBySourceParams) ([]Log, error)
 	GetMessageByID(ctx context.Context, id string) (Message, error)
+	GetMessageDeadLetter(ctx context.Context, id string) (MessageDeadLetter, error)
+	GetMessageFeedback(ctx context.Context, messageID string) (MessageFeedback, error)
+	GetMessageJob(ctx context.Context, id string) (MessageJob, error)
 	GetMessagesBySessionID(ctx context.Context, sessionID string) ([]Message, error)
 	GetScheduleByID(ctx context.Context, id string) (Schedule, error)
-	GetSchedulesBySkill(ctx context.Context, skill string) ([]Schedule, error)
+	GetScheduleRunsByScheduleID(ctx context.Context, arg GetScheduleRunsByScheduleIDParams) ([]ScheduleRun, error)
+	GetSchedulesBySkill(ctx context.Context, skill sql.NullString) ([]Schedule, error)
+	GetSessionAttribute(ctx context.Context, arg GetSessionAttributeParams) (SessionAttribute, error)
 	GetSessionByID(ctx context.Context, id string) (Session, error)
 	GetSessionsByUserID(ctx context.Context, userID string) ([]Session, error)
 	GetSkillByID(ctx context.Context, id string) (Skill, error)
@@ -40,13 +101,58 @@ type Querier interface {
 	GetTasksBySessionID(ctx context.Context, sessionID string) ([]Task, error)
 	GetUserByChannel(ctx context.Context, arg GetUserByChannelParams) (User, error)
 	GetUserByID(ctx context.Context, id string) (User, error)
+	GetUserPreferences(ctx context.Context, userID string) (UserPreference, error)
+	GetWorkspaceByID(ctx context.Context, id string) (Workspace, error)
+	IncrementAnalyticsCounter(ctx context.Context, arg IncrementAnalyticsCounterParams) error
+	ListAPIKeys(ctx context.Context) ([]ApiKey, error)
+	ListAnalyticsCounters(ctx context.Context, arg ListAnalyticsCountersParams) ([]AnalyticsCounter, error)
+	ListCrashReports(ctx context.Context, arg ListCrashReportsParams) ([]CrashReport, error)
+	ListDeadLetters(ctx context.Context, arg ListDeadLettersParams) ([]DeadLetter, error)
+	ListDocumentChunks(ctx context.Context, sessionID string) ([]DocumentChunk, error)
+	ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error)
+	ListEventsBySubject(ctx context.Context, arg ListEventsBySubjectParams) ([]Event, error)
+	ListLogs(ctx context.Context, arg ListLogsParams) ([]Log, error)
+	ListLogsOlderThan(ctx context.Context, arg ListLogsOlderThanParams) ([]Log, error)
+	ListMessageDeadLetters(ctx context.Context, arg ListMessageDeadLettersParams) ([]MessageDeadLetter, error)
+	ListMessageJobsByBatch(ctx context.Context, batchID string) ([]MessageJob, error)
+	ListMessagesBySessionID(ctx context.Context, arg ListMessagesBySessionIDParams) ([]Message, error)
+	ListMessagesOlderThan(ctx context.Context, arg ListMessagesOlderThanParams) ([]Message, error)
 	ListSchedules(ctx context.Context) ([]Schedule, error)
+	ListSessionAttributes(ctx context.Context, arg ListSessionAttributesParams) ([]SessionAttribute, error)
+	ListSessions(ctx context.Context, arg ListSessionsParams) ([]Session, error)
+	ListSessionsByUserID(ctx context.Context, arg ListSessionsByUserIDParams) ([]Session, error)
 	ListSkills(ctx context.Context) ([]Skill, error)
+	ListSpilledMessages(ctx context.Context, arg ListSpilledMessagesParams) ([]SpilledMessage, error)
+	ListTaskAttempts(ctx context.Context, taskID string) ([]TaskAttempt, error)
+	ListTasksBySessionID(ctx context.Context, arg ListTasksBySessionIDParams) ([]Task, error)
+	ListTasksOlderThan(ctx context.Context, arg ListTasksOlderThanParams) ([]Task, error)
 	ListUsers(ctx context.Context) ([]User, error)
+	ListUserDataErasures(ctx context.Context, arg ListUserDataErasuresParams) ([]UserDataErasure, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWorkspaces(ctx context.Context) ([]Workspace, error)
+	RecordProcessedMessage(ctx context.Context, arg RecordProcessedMessageParams) (int64, error)
+	ReleaseLease(ctx context.Context, arg ReleaseLeaseParams) error
+	// A pending key (status code 0) is reserved before its request is handled, unless an
+	// unexpired key exists, and replaced by the response of the request
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	SaveIdempotencyKey(ctx context.Context, arg SaveIdempotencyKeyParams) (int64, error)
+	SearchMessages(ctx context.Context, arg SearchMessagesParams) ([]Message, error)
+	SumLLMUsage(ctx context.Context, arg SumLLMUsageParams) ([]SumLLMUsageRow, error)
+	UpdateDeadLetter(ctx context.Context, arg UpdateDeadLetterParams) (DeadLetter, error)
+	UpdateJob(ctx context.Context, arg UpdateJobParams) (int64, error)
+	UpdateMessageDeadLetter(ctx context.Context, arg UpdateMessageDeadLetterParams) (MessageDeadLetter, error)
+	UpdateMessageJob(ctx context.Context, arg UpdateMessageJobParams) error
 	UpdateSchedule(ctx context.Context, arg UpdateScheduleParams) (Schedule, error)
+	UpdateScheduleRun(ctx context.Context, arg UpdateScheduleRunParams) (ScheduleRun, error)
 	UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error)
 	UpdateSkill(ctx context.Context, arg UpdateSkillParams) (Skill, error)
 	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error)
+	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
+	UpsertMessageFeedback(ctx context.Context, arg UpsertMessageFeedbackParams) (MessageFeedback, error)
+	UpsertSessionAttribute(ctx context.Context, arg UpsertSessionAttributeParams) (SessionAttribute, error)
+	UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error)
 }
 
 var _ Querier = (*Queries)(nil)
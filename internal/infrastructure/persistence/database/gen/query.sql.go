@@ -8,373 +8,3292 @@ package database
 import (
 	"context"
 	"database/sql"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 )
 
-const createLog = `-- name: CreateLog :one
-INSERT INTO logs (id, level, source, message, metadata, created_at)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, level, source, message, metadata, created_at
+const acquireLease = `-- name: AcquireLease :execrows
+INSERT INTO leases (name, holder, expires_at)
+VALUES (?1, ?2, ?3)
+ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+WHERE leases.holder = excluded.holder OR leases.expires_at <= CAST(?4 AS TEXT)
 `
 
-type CreateLogParams struct {
-	ID        string         `json:"id"`
-	Level     string         `json:"level"`
-	Source    string         `json:"source"`
-	Message   string         `json:"message"`
-	Metadata  sql.NullString `json:"metadata"`
-	CreatedAt string         `json:"created_at"`
+type AcquireLeaseParams struct {
+	Name      string `json:"name"`
+	Holder    string `json:"holder"`
+	ExpiresAt string `json:"expires_at"`
+	Now       string `json:"now"`
 }
 
-func (q *Queries) CreateLog(ctx context.Context, arg CreateLogParams) (Log, error) {
-	row := q.db.QueryRowContext(ctx, createLog,
-		arg.ID,
-		arg.Level,
-		arg.Source,
-		arg.Message,
-		arg.Metadata,
-		arg.CreatedAt,
-	)
-	var i Log
-	err := row.Scan(
-		&i.ID,
-		&i.Level,
-		&i.Source,
-		&i.Message,
-		&i.Metadata,
-		&i.CreatedAt,
+// A lease is acquired when it is free, expired or already held by the holder
+func (q *Queries) AcquireLease(ctx context.Context, arg AcquireLeaseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, acquireLease,
+		arg.Name,
+		arg.Holder,
+		arg.ExpiresAt,
+		arg.Now,
 	)
-	return i, err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const createMessage = `-- name: CreateMessage :one
-INSERT INTO messages (id, session_id, role, content, created_at)
-VALUES (?, ?, ?, ?, ?)
-RETURNING id, session_id, role, content, created_at
+const addAnalyticsActiveUser = `-- name: AddAnalyticsActiveUser :exec
+INSERT INTO analytics_active_users (day, connector, user_id)
+VALUES (?, ?, ?)
+ON CONFLICT (day, connector, user_id) DO NOTHING
 `
 
-type CreateMessageParams struct {
-	ID        string `json:"id"`
-	SessionID string `json:"session_id"`
-	Role      string `json:"role"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"created_at"`
+type AddAnalyticsActiveUserParams struct {
+	Day       string `json:"day"`
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
 }
 
-func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error) {
-	row := q.db.QueryRowContext(ctx, createMessage,
-		arg.ID,
-		arg.SessionID,
-		arg.Role,
-		arg.Content,
-		arg.CreatedAt,
-	)
-	var i Message
-	err := row.Scan(
-		&i.ID,
-		&i.SessionID,
-		&i.Role,
-		&i.Content,
-		&i.CreatedAt,
-	)
-	return i, err
+func (q *Queries) AddAnalyticsActiveUser(ctx context.Context, arg AddAnalyticsActiveUserParams) error {
+	_, err := q.db.ExecContext(ctx, addAnalyticsActiveUser, arg.Day, arg.Connector, arg.UserID)
+	return err
 }
 
-const createSchedule = `-- name: CreateSchedule :one
-INSERT INTO schedules (id, skill, cron_expression, input, enabled, created_at)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, skill, cron_expression, input, enabled, created_at
+const claimJob = `-- name: ClaimJob :one
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, locked_by = ?1, locked_until = ?2, updated_at = ?3
+WHERE id = (
+    SELECT j.id FROM jobs j
+    WHERE ((j.status = 'pending' AND j.run_at <= CAST(?4 AS TEXT))
+       OR (j.status = 'running' AND j.locked_until <= CAST(?4 AS TEXT)))
+      AND (NOT j.leader_only OR CAST(?5 AS INTEGER) = 1)
+    ORDER BY j.run_at ASC
+    LIMIT 1
+)
+AND ((status = 'pending' AND run_at <= CAST(?4 AS TEXT))
+  OR (status = 'running' AND locked_until <= CAST(?4 AS TEXT)))
+RETURNING id, kind, payload, status, attempts, max_attempts, error, run_at, locked_by, locked_until, created_at, updated_at, finished_at, leader_only
 `
 
-type CreateScheduleParams struct {
-	ID             string `json:"id"`
-	Skill          string `json:"skill"`
-	CronExpression string `json:"cron_expression"`
-	Input          string `json:"input"`
-	Enabled        int64  `json:"enabled"`
-	CreatedAt      string `json:"created_at"`
+type ClaimJobParams struct {
+	LockedBy    string         `json:"locked_by"`
+	LockedUntil sql.NullString `json:"locked_until"`
+	UpdatedAt   string         `json:"updated_at"`
+	Now         string         `json:"now"`
+	Leader      int64          `json:"leader"`
 }
 
-func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error) {
-	row := q.db.QueryRowContext(ctx, createSchedule,
-		arg.ID,
-		arg.Skill,
-		arg.CronExpression,
-		arg.Input,
-		arg.Enabled,
-		arg.CreatedAt,
+// Jobs are claimed for a lease; the job due the longest comes first. Leader-only jobs are
+// claimed by the leader only. The due condition is repeated for the row being updated, so
+// that a job claimed concurrently by another instance is not claimed twice.
+func (q *Queries) ClaimJob(ctx context.Context, arg ClaimJobParams) (Job, error) {
+	row := q.db.QueryRowContext(ctx, claimJob,
+		arg.LockedBy,
+		arg.LockedUntil,
+		arg.UpdatedAt,
+		arg.Now,
+		arg.Leader,
 	)
-	var i Schedule
+	var i Job
 	err := row.Scan(
 		&i.ID,
-		&i.Skill,
-		&i.CronExpression,
-		&i.Input,
-		&i.Enabled,
+		&i.Kind,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.Error,
+		&i.RunAt,
+		&i.LockedBy,
+		&i.LockedUntil,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinishedAt,
+		&i.LeaderOnly,
 	)
 	return i, err
 }
 
-const createSession = `-- name: CreateSession :one
-INSERT INTO sessions (id, user_id, created_at, updated_at)
-VALUES (?, ?, ?, ?)
-RETURNING id, user_id, created_at, updated_at
+const countAnalyticsActiveUsers = `-- name: CountAnalyticsActiveUsers :many
+SELECT day, connector, COUNT(*) AS users FROM analytics_active_users
+WHERE day >= ?1 AND day <= ?2
+GROUP BY day, connector
+UNION ALL
+SELECT day, CAST('' AS TEXT) AS connector, COUNT(DISTINCT user_id) AS users FROM analytics_active_users
+WHERE day >= ?1 AND day <= ?2
+GROUP BY day
+UNION ALL
+SELECT CAST('' AS TEXT) AS day, connector, COUNT(DISTINCT user_id) AS users FROM analytics_active_users
+WHERE day >= ?1 AND day <= ?2
+GROUP BY connector
+UNION ALL
+SELECT CAST('' AS TEXT) AS day, CAST('' AS TEXT) AS connector, COUNT(DISTINCT user_id) AS users FROM analytics_active_users
+WHERE day >= ?1 AND day <= ?2
+ORDER BY day, connector
 `
 
-type CreateSessionParams struct {
-	ID        string `json:"id"`
-	UserID    string `json:"user_id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+type CountAnalyticsActiveUsersParams struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
 }
 
-func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
-	row := q.db.QueryRowContext(ctx, createSession,
-		arg.ID,
-		arg.UserID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-	)
-	var i Session
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type CountAnalyticsActiveUsersRow struct {
+	Day       string `json:"day"`
+	Connector string `json:"connector"`
+	Users     int64  `json:"users"`
 }
 
-const createSkill = `-- name: CreateSkill :one
-INSERT INTO skills (id, name, version, location, permissions, metadata, created_at)
+func (q *Queries) CountAnalyticsActiveUsers(ctx context.Context, arg CountAnalyticsActiveUsersParams) ([]CountAnalyticsActiveUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, countAnalyticsActiveUsers, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountAnalyticsActiveUsersRow
+	for rows.Next() {
+		var i CountAnalyticsActiveUsersRow
+		if err := rows.Scan(&i.Day, &i.Connector, &i.Users); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countLogsOlderThan = `-- name: CountLogsOlderThan :one
+SELECT COUNT(*) FROM logs
+WHERE created_at < ?
+`
+
+func (q *Queries) CountLogsOlderThan(ctx context.Context, createdAt string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLogsOlderThan, createdAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countMessageFeedback = `-- name: CountMessageFeedback :many
+SELECT day, rating, COUNT(*) AS count FROM message_feedback
+WHERE day >= ?1 AND day <= ?2
+GROUP BY day, rating
+ORDER BY day, rating
+`
+
+type CountMessageFeedbackParams struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+type CountMessageFeedbackRow struct {
+	Day    string `json:"day"`
+	Rating string `json:"rating"`
+	Count  int64  `json:"count"`
+}
+
+func (q *Queries) CountMessageFeedback(ctx context.Context, arg CountMessageFeedbackParams) ([]CountMessageFeedbackRow, error) {
+	rows, err := q.db.QueryContext(ctx, countMessageFeedback, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMessageFeedbackRow
+	for rows.Next() {
+		var i CountMessageFeedbackRow
+		if err := rows.Scan(&i.Day, &i.Rating, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countMessagesOlderThan = `-- name: CountMessagesOlderThan :one
+SELECT COUNT(*) FROM messages
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+`
+
+func (q *Queries) CountMessagesOlderThan(ctx context.Context, createdAt string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMessagesOlderThan, createdAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSpilledMessages = `-- name: CountSpilledMessages :one
+SELECT COUNT(*) FROM spilled_messages
+WHERE connector = ?
+`
+
+func (q *Queries) CountSpilledMessages(ctx context.Context, connector string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSpilledMessages, connector)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTasksOlderThan = `-- name: CountTasksOlderThan :one
+SELECT COUNT(*) FROM tasks
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+`
+
+func (q *Queries) CountTasksOlderThan(ctx context.Context, createdAt string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTasksOlderThan, createdAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, name, key_hash, prefix, scope, created_at, workspace_id)
 VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, name, version, location, permissions, metadata, created_at
+RETURNING id, name, key_hash, prefix, scope, created_at, revoked_at, workspace_id
 `
 
-type CreateSkillParams struct {
+type CreateAPIKeyParams struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
-	Version     string `json:"version"`
-	Location    string `json:"location"`
-	Permissions string `json:"permissions"`
-	Metadata    string `json:"metadata"`
+	KeyHash     string `json:"key_hash"`
+	Prefix      string `json:"prefix"`
+	Scope       string `json:"scope"`
 	CreatedAt   string `json:"created_at"`
+	WorkspaceID string `json:"workspace_id"`
 }
 
-func (q *Queries) CreateSkill(ctx context.Context, arg CreateSkillParams) (Skill, error) {
-	row := q.db.QueryRowContext(ctx, createSkill,
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, createAPIKey,
 		arg.ID,
 		arg.Name,
-		arg.Version,
-		arg.Location,
-		arg.Permissions,
-		arg.Metadata,
+		arg.KeyHash,
+		arg.Prefix,
+		arg.Scope,
 		arg.CreatedAt,
+		arg.WorkspaceID,
 	)
-	var i Skill
+	var i ApiKey
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.Version,
-		&i.Location,
-		&i.Permissions,
-		&i.Metadata,
+		&i.KeyHash,
+		&i.Prefix,
+		&i.Scope,
 		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
 
-const createTask = `-- name: CreateTask :one
-INSERT INTO tasks (id, session_id, skill, input, status, created_at, updated_at)
+const createCrashReport = `-- name: CreateCrashReport :one
+INSERT INTO crash_reports (id, component, operation, panic, stack, attributes, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, session_id, skill, input, output, status, error, created_at, updated_at
+RETURNING id, component, operation, panic, stack, attributes, created_at
 `
 
-type CreateTaskParams struct {
-	ID        string `json:"id"`
-	SessionID string `json:"session_id"`
-	Skill     string `json:"skill"`
-	Input     string `json:"input"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+type CreateCrashReportParams struct {
+	ID         string `json:"id"`
+	Component  string `json:"component"`
+	Operation  string `json:"operation"`
+	Panic      string `json:"panic"`
+	Stack      string `json:"stack"`
+	Attributes string `json:"attributes"`
+	CreatedAt  string `json:"created_at"`
 }
 
-func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
-	row := q.db.QueryRowContext(ctx, createTask,
+func (q *Queries) CreateCrashReport(ctx context.Context, arg CreateCrashReportParams) (CrashReport, error) {
+	row := q.db.QueryRowContext(ctx, createCrashReport,
 		arg.ID,
-		arg.SessionID,
-		arg.Skill,
-		arg.Input,
-		arg.Status,
+		arg.Component,
+		arg.Operation,
+		arg.Panic,
+		arg.Stack,
+		arg.Attributes,
 		arg.CreatedAt,
-		arg.UpdatedAt,
 	)
-	var i Task
+	var i CrashReport
 	err := row.Scan(
 		&i.ID,
-		&i.SessionID,
-		&i.Skill,
-		&i.Input,
-		&i.Output,
-		&i.Status,
-		&i.Error,
+		&i.Component,
+		&i.Operation,
+		&i.Panic,
+		&i.Stack,
+		&i.Attributes,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, channel, channel_user_id, created_at)
-VALUES (?, ?, ?, ?)
-RETURNING id, channel, channel_user_id, created_at
+const createDeadLetter = `-- name: CreateDeadLetter :one
+INSERT INTO dead_letters (id, subscription, event_type, payload, occurred_at, attempts, error, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, subscription, event_type, payload, occurred_at, attempts, error, created_at, updated_at
 `
 
-type CreateUserParams struct {
-	ID            string `json:"id"`
-	Channel       string `json:"channel"`
-	ChannelUserID string `json:"channel_user_id"`
-	CreatedAt     string `json:"created_at"`
+type CreateDeadLetterParams struct {
+	ID           string `json:"id"`
+	Subscription string `json:"subscription"`
+	EventType    string `json:"event_type"`
+	Payload      string `json:"payload"`
+	OccurredAt   string `json:"occurred_at"`
+	Attempts     int64  `json:"attempts"`
+	Error        string `json:"error"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
 }
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser,
+func (q *Queries) CreateDeadLetter(ctx context.Context, arg CreateDeadLetterParams) (DeadLetter, error) {
+	row := q.db.QueryRowContext(ctx, createDeadLetter,
 		arg.ID,
-		arg.Channel,
-		arg.ChannelUserID,
+		arg.Subscription,
+		arg.EventType,
+		arg.Payload,
+		arg.OccurredAt,
+		arg.Attempts,
+		arg.Error,
 		arg.CreatedAt,
+		arg.UpdatedAt,
 	)
-	var i User
+	var i DeadLetter
 	err := row.Scan(
 		&i.ID,
-		&i.Channel,
-		&i.ChannelUserID,
+		&i.Subscription,
+		&i.EventType,
+		&i.Payload,
+		&i.OccurredAt,
+		&i.Attempts,
+		&i.Error,
 		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const deleteLog = `-- name: DeleteLog :exec
-DELETE FROM logs WHERE id = ?
+const createDocumentChunk = `-- name: CreateDocumentChunk :exec
+INSERT INTO document_chunks (id, session_id, document_id, document_name, position, content, embedding, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
-func (q *Queries) DeleteLog(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteLog, id)
-	return err
+type CreateDocumentChunkParams struct {
+	ID           string `json:"id"`
+	SessionID    string `json:"session_id"`
+	DocumentID   string `json:"document_id"`
+	DocumentName string `json:"document_name"`
+	Position     int64  `json:"position"`
+	Content      string `json:"content"`
+	Embedding    string `json:"embedding"`
+	CreatedAt    string `json:"created_at"`
 }
 
-const deleteLogsOlderThan = `-- name: DeleteLogsOlderThan :exec
-DELETE FROM logs WHERE created_at < ?
-`
-
-func (q *Queries) DeleteLogsOlderThan(ctx context.Context, createdAt string) error {
-	_, err := q.db.ExecContext(ctx, deleteLogsOlderThan, createdAt)
+func (q *Queries) CreateDocumentChunk(ctx context.Context, arg CreateDocumentChunkParams) error {
+	_, err := q.db.ExecContext(ctx, createDocumentChunk,
+		arg.ID,
+		arg.SessionID,
+		arg.DocumentID,
+		arg.DocumentName,
+		arg.Position,
+		arg.Content,
+		arg.Embedding,
+		arg.CreatedAt,
+	)
 	return err
 }
 
-const deleteMessage = `-- name: DeleteMessage :exec
-DELETE FROM messages WHERE id = ?
+const createEvent = `-- name: CreateEvent :one
+INSERT INTO events (id, type, payload, occurred_at)
+VALUES (?, ?, ?, ?)
+RETURNING seq, id, type, payload, occurred_at
 `
 
-func (q *Queries) DeleteMessage(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteMessage, id)
-	return err
+type CreateEventParams struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Payload    string `json:"payload"`
+	OccurredAt string `json:"occurred_at"`
 }
 
-const deleteSchedule = `-- name: DeleteSchedule :exec
-DELETE FROM schedules WHERE id = ?
-`
-
-func (q *Queries) DeleteSchedule(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteSchedule, id)
-	return err
+func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error) {
+	row := q.db.QueryRowContext(ctx, createEvent,
+		arg.ID,
+		arg.Type,
+		arg.Payload,
+		arg.OccurredAt,
+	)
+	var i Event
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.Type,
+		&i.Payload,
+		&i.OccurredAt,
+	)
+	return i, err
 }
 
-const deleteSession = `-- name: DeleteSession :exec
-DELETE FROM sessions WHERE id = ?
+const createJob = `-- name: CreateJob :execrows
+INSERT INTO jobs (id, kind, payload, status, attempts, max_attempts, error, run_at, locked_by, locked_until, created_at, updated_at, finished_at, leader_only)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO NOTHING
 `
 
-func (q *Queries) DeleteSession(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteSession, id)
-	return err
+type CreateJobParams struct {
+	ID          string         `json:"id"`
+	Kind        string         `json:"kind"`
+	Payload     string         `json:"payload"`
+	Status      string         `json:"status"`
+	Attempts    int64          `json:"attempts"`
+	MaxAttempts int64          `json:"max_attempts"`
+	Error       string         `json:"error"`
+	RunAt       string         `json:"run_at"`
+	LockedBy    string         `json:"locked_by"`
+	LockedUntil sql.NullString `json:"locked_until"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+	FinishedAt  sql.NullString `json:"finished_at"`
+	LeaderOnly  int64          `json:"leader_only"`
 }
 
-const deleteSkill = `-- name: DeleteSkill :exec
-DELETE FROM skills WHERE id = ?
-`
-
-func (q *Queries) DeleteSkill(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteSkill, id)
+func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createJob,
+		arg.ID,
+		arg.Kind,
+		arg.Payload,
+		arg.Status,
+		arg.Attempts,
+		arg.MaxAttempts,
+		arg.Error,
+		arg.RunAt,
+		arg.LockedBy,
+		arg.LockedUntil,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.LeaderOnly,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createLLMUsage = `-- name: CreateLLMUsage :exec
+INSERT INTO llm_usage (id, user_id, workspace_id, session_id, provider, model, input_tokens, output_tokens, total_tokens, cost, month, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO NOTHING
+`
+
+type CreateLLMUsageParams struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	WorkspaceID  string  `json:"workspace_id"`
+	SessionID    string  `json:"session_id"`
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	Cost         float64 `json:"cost"`
+	Month        string  `json:"month"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+func (q *Queries) CreateLLMUsage(ctx context.Context, arg CreateLLMUsageParams) error {
+	_, err := q.db.ExecContext(ctx, createLLMUsage,
+		arg.ID,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.SessionID,
+		arg.Provider,
+		arg.Model,
+		arg.InputTokens,
+		arg.OutputTokens,
+		arg.TotalTokens,
+		arg.Cost,
+		arg.Month,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const createLog = `-- name: CreateLog :one
+INSERT INTO logs (id, level, source, message, metadata, created_at)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, level, source, message, metadata, created_at
+`
+
+type CreateLogParams struct {
+	ID        string         `json:"id"`
+	Level     string         `json:"level"`
+	Source    string         `json:"source"`
+	Message   string         `json:"message"`
+	Metadata  sql.NullString `json:"metadata"`
+	CreatedAt string         `json:"created_at"`
+}
+
+func (q *Queries) CreateLog(ctx context.Context, arg CreateLogParams) (Log, error) {
+	row := q.db.QueryRowContext(ctx, createLog,
+		arg.ID,
+		arg.Level,
+		arg.Source,
+		arg.Message,
+		arg.Metadata,
+		arg.CreatedAt,
+	)
+	var i Log
+	err := row.Scan(
+		&i.ID,
+		&i.Level,
+		&i.Source,
+		&i.Message,
+		&i.Metadata,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createMessage = `-- name: CreateMessage :one
+INSERT INTO messages (id, session_id, role, content, created_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, session_id, role, content, created_at
+`
+
+type CreateMessageParams struct {
+	ID        string                  `json:"id"`
+	SessionID string                  `json:"session_id"`
+	Role      valueobject.MessageRole `json:"role"`
+	Content   string                  `json:"content"`
+	CreatedAt string                  `json:"created_at"`
+}
+
+func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error) {
+	row := q.db.QueryRowContext(ctx, createMessage,
+		arg.ID,
+		arg.SessionID,
+		arg.Role,
+		arg.Content,
+		arg.CreatedAt,
+	)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.Role,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createMessageDeadLetter = `-- name: CreateMessageDeadLetter :one
+INSERT INTO message_dead_letters (id, connector, user_id, channel_id, content, metadata, attempts, error, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, connector, user_id, channel_id, content, metadata, attempts, error, created_at, updated_at
+`
+
+type CreateMessageDeadLetterParams struct {
+	ID        string `json:"id"`
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	Content   string `json:"content"`
+	Metadata  string `json:"metadata"`
+	Attempts  int64  `json:"attempts"`
+	Error     string `json:"error"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func (q *Queries) CreateMessageDeadLetter(ctx context.Context, arg CreateMessageDeadLetterParams) (MessageDeadLetter, error) {
+	row := q.db.QueryRowContext(ctx, createMessageDeadLetter,
+		arg.ID,
+		arg.Connector,
+		arg.UserID,
+		arg.ChannelID,
+		arg.Content,
+		arg.Metadata,
+		arg.Attempts,
+		arg.Error,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i MessageDeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Connector,
+		&i.UserID,
+		&i.ChannelID,
+		&i.Content,
+		&i.Metadata,
+		&i.Attempts,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createMessageJob = `-- name: CreateMessageJob :exec
+INSERT INTO message_jobs (id, session_id, content, status, message_id, reply, error, created_at, updated_at, finished_at, batch_id, batch_index)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateMessageJobParams struct {
+	ID         string         `json:"id"`
+	SessionID  string         `json:"session_id"`
+	Content    string         `json:"content"`
+	Status     string         `json:"status"`
+	MessageID  string         `json:"message_id"`
+	Reply      string         `json:"reply"`
+	Error      string         `json:"error"`
+	CreatedAt  string         `json:"created_at"`
+	UpdatedAt  string         `json:"updated_at"`
+	FinishedAt sql.NullString `json:"finished_at"`
+	BatchID    string         `json:"batch_id"`
+	BatchIndex int64          `json:"batch_index"`
+}
+
+func (q *Queries) CreateMessageJob(ctx context.Context, arg CreateMessageJobParams) error {
+	_, err := q.db.ExecContext(ctx, createMessageJob,
+		arg.ID,
+		arg.SessionID,
+		arg.Content,
+		arg.Status,
+		arg.MessageID,
+		arg.Reply,
+		arg.Error,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.BatchID,
+		arg.BatchIndex,
+	)
 	return err
 }
 
-const deleteTask = `-- name: DeleteTask :exec
-DELETE FROM tasks WHERE id = ?
+const createSchedule = `-- name: CreateSchedule :one
+INSERT INTO schedules (id, skill, cron_expression, input, enabled, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id, prompt, session_id, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id, prompt, session_id
+`
+
+type CreateScheduleParams struct {
+	ID              string                     `json:"id"`
+	Skill           sql.NullString             `json:"skill"`
+	CronExpression  valueobject.CronExpression `json:"cron_expression"`
+	Input           string                     `json:"input"`
+	Enabled         int64                      `json:"enabled"`
+	Timezone        string                     `json:"timezone"`
+	JitterSeconds   int64                      `json:"jitter_seconds"`
+	ScheduleType    string                     `json:"schedule_type"`
+	RunAt           sql.NullString             `json:"run_at"`
+	MissedRunPolicy string                     `json:"missed_run_policy"`
+	TargetConnector string                     `json:"target_connector"`
+	TargetUserID    string                     `json:"target_user_id"`
+	Prompt          string                     `json:"prompt"`
+	SessionID       string                     `json:"session_id"`
+	CreatedAt       string                     `json:"created_at"`
+}
+
+func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, createSchedule,
+		arg.ID,
+		arg.Skill,
+		arg.CronExpression,
+		arg.Input,
+		arg.Enabled,
+		arg.Timezone,
+		arg.JitterSeconds,
+		arg.ScheduleType,
+		arg.RunAt,
+		arg.MissedRunPolicy,
+		arg.TargetConnector,
+		arg.TargetUserID,
+		arg.Prompt,
+		arg.SessionID,
+		arg.CreatedAt,
+	)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.Skill,
+		&i.CronExpression,
+		&i.Input,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.Timezone,
+		&i.JitterSeconds,
+		&i.ScheduleType,
+		&i.RunAt,
+		&i.MissedRunPolicy,
+		&i.TargetConnector,
+		&i.TargetUserID,
+		&i.Prompt,
+		&i.SessionID,
+	)
+	return i, err
+}
+
+const createScheduleRun = `-- name: CreateScheduleRun :one
+INSERT INTO schedule_runs (id, schedule_id, status, scheduled_at, started_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, schedule_id, status, scheduled_at, started_at, finished_at, output, error
+`
+
+type CreateScheduleRunParams struct {
+	ID          string                 `json:"id"`
+	ScheduleID  string                 `json:"schedule_id"`
+	Status      valueobject.TaskStatus `json:"status"`
+	ScheduledAt string                 `json:"scheduled_at"`
+	StartedAt   string                 `json:"started_at"`
+}
+
+func (q *Queries) CreateScheduleRun(ctx context.Context, arg CreateScheduleRunParams) (ScheduleRun, error) {
+	row := q.db.QueryRowContext(ctx, createScheduleRun,
+		arg.ID,
+		arg.ScheduleID,
+		arg.Status,
+		arg.ScheduledAt,
+		arg.StartedAt,
+	)
+	var i ScheduleRun
+	err := row.Scan(
+		&i.ID,
+		&i.ScheduleID,
+		&i.Status,
+		&i.ScheduledAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Output,
+		&i.Error,
+	)
+	return i, err
+}
+
+const createSession = `-- name: CreateSession :one
+INSERT INTO sessions (id, user_id, created_at, updated_at, pinned, workspace_id, title)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, user_id, created_at, updated_at, pinned, workspace_id, title
+`
+
+type CreateSessionParams struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+	Pinned      int64  `json:"pinned"`
+	WorkspaceID string `json:"workspace_id"`
+	Title       string `json:"title"`
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Pinned,
+		arg.WorkspaceID,
+		arg.Title,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Pinned,
+		&i.WorkspaceID,
+		&i.Title,
+	)
+	return i, err
+}
+
+const createSkill = `-- name: CreateSkill :one
+INSERT INTO skills (id, name, version, location, permissions, metadata, created_at, disabled, workspace_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, version, location, permissions, metadata, created_at, disabled, workspace_id
+`
+
+type CreateSkillParams struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Version     valueobject.Version `json:"version"`
+	Location    string              `json:"location"`
+	Permissions string              `json:"permissions"`
+	Metadata    string              `json:"metadata"`
+	CreatedAt   string              `json:"created_at"`
+	Disabled    int64               `json:"disabled"`
+	WorkspaceID string              `json:"workspace_id"`
+}
+
+func (q *Queries) CreateSkill(ctx context.Context, arg CreateSkillParams) (Skill, error) {
+	row := q.db.QueryRowContext(ctx, createSkill,
+		arg.ID,
+		arg.Name,
+		arg.Version,
+		arg.Location,
+		arg.Permissions,
+		arg.Metadata,
+		arg.CreatedAt,
+		arg.Disabled,
+		arg.WorkspaceID,
+	)
+	var i Skill
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.Location,
+		&i.Permissions,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Disabled,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const createSpilledMessage = `-- name: CreateSpilledMessage :exec
+INSERT INTO spilled_messages (id, connector, user_id, channel_id, content, metadata, sequence, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateSpilledMessageParams struct {
+	ID        string `json:"id"`
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	Content   string `json:"content"`
+	Metadata  string `json:"metadata"`
+	Sequence  int64  `json:"sequence"`
+	CreatedAt string `json:"created_at"`
+}
+
+func (q *Queries) CreateSpilledMessage(ctx context.Context, arg CreateSpilledMessageParams) error {
+	_, err := q.db.ExecContext(ctx, createSpilledMessage,
+		arg.ID,
+		arg.Connector,
+		arg.UserID,
+		arg.ChannelID,
+		arg.Content,
+		arg.Metadata,
+		arg.Sequence,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const createTaskAttempt = `-- name: CreateTaskAttempt :exec
+INSERT INTO task_attempts (id, task_id, attempt, status, error, retryable, started_at, finished_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateTaskAttemptParams struct {
+	ID         string                 `json:"id"`
+	TaskID     string                 `json:"task_id"`
+	Attempt    int64                  `json:"attempt"`
+	Status     valueobject.TaskStatus `json:"status"`
+	Error      string                 `json:"error"`
+	Retryable  int64                  `json:"retryable"`
+	StartedAt  string                 `json:"started_at"`
+	FinishedAt string                 `json:"finished_at"`
+}
+
+func (q *Queries) CreateTaskAttempt(ctx context.Context, arg CreateTaskAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, createTaskAttempt,
+		arg.ID,
+		arg.TaskID,
+		arg.Attempt,
+		arg.Status,
+		arg.Error,
+		arg.Retryable,
+		arg.StartedAt,
+		arg.FinishedAt,
+	)
+	return err
+}
+
+const createTask = `-- name: CreateTask :one
+INSERT INTO tasks (id, session_id, skill, input, status, created_at, updated_at, started_at, finished_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, session_id, skill, input, output, status, error, created_at, updated_at, started_at, finished_at
+`
+
+type CreateTaskParams struct {
+	ID         string                 `json:"id"`
+	SessionID  string                 `json:"session_id"`
+	Skill      string                 `json:"skill"`
+	Input      string                 `json:"input"`
+	Status     valueobject.TaskStatus `json:"status"`
+	CreatedAt  string                 `json:"created_at"`
+	UpdatedAt  string                 `json:"updated_at"`
+	StartedAt  sql.NullString         `json:"started_at"`
+	FinishedAt sql.NullString         `json:"finished_at"`
+}
+
+func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
+	row := q.db.QueryRowContext(ctx, createTask,
+		arg.ID,
+		arg.SessionID,
+		arg.Skill,
+		arg.Input,
+		arg.Status,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.StartedAt,
+		arg.FinishedAt,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.Skill,
+		&i.Input,
+		&i.Output,
+		&i.Status,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (id, channel, channel_user_id, created_at)
+VALUES (?, ?, ?, ?)
+RETURNING id, channel, channel_user_id, created_at
+`
+
+type CreateUserParams struct {
+	ID            string              `json:"id"`
+	Channel       valueobject.Channel `json:"channel"`
+	ChannelUserID string              `json:"channel_user_id"`
+	CreatedAt     string              `json:"created_at"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.ID,
+		arg.Channel,
+		arg.ChannelUserID,
+		arg.CreatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Channel,
+		&i.ChannelUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createUserDataErasure = `-- name: CreateUserDataErasure :one
+INSERT INTO user_data_erasures (id, user_id, requested_by, reason, sessions, messages, tasks, attributes, preferences, verified, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, user_id, requested_by, reason, sessions, messages, tasks, attributes, preferences, verified, created_at
+`
+
+type CreateUserDataErasureParams struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	RequestedBy string `json:"requested_by"`
+	Reason      string `json:"reason"`
+	Sessions    int64  `json:"sessions"`
+	Messages    int64  `json:"messages"`
+	Tasks       int64  `json:"tasks"`
+	Attributes  int64  `json:"attributes"`
+	Preferences int64  `json:"preferences"`
+	Verified    int64  `json:"verified"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func (q *Queries) CreateUserDataErasure(ctx context.Context, arg CreateUserDataErasureParams) (UserDataErasure, error) {
+	row := q.db.QueryRowContext(ctx, createUserDataErasure,
+		arg.ID,
+		arg.UserID,
+		arg.RequestedBy,
+		arg.Reason,
+		arg.Sessions,
+		arg.Messages,
+		arg.Tasks,
+		arg.Attributes,
+		arg.Preferences,
+		arg.Verified,
+		arg.CreatedAt,
+	)
+	var i UserDataErasure
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RequestedBy,
+		&i.Reason,
+		&i.Sessions,
+		&i.Messages,
+		&i.Tasks,
+		&i.Attributes,
+		&i.Preferences,
+		&i.Verified,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (id, endpoint, url, event_type, payload, status, attempts, response_status, error, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, endpoint, url, event_type, payload, status, attempts, response_status, error, created_at, updated_at
+`
+
+type CreateWebhookDeliveryParams struct {
+	ID             string `json:"id"`
+	Endpoint       string `json:"endpoint"`
+	URL            string `json:"url"`
+	EventType      string `json:"event_type"`
+	Payload        string `json:"payload"`
+	Status         string `json:"status"`
+	Attempts       int64  `json:"attempts"`
+	ResponseStatus int64  `json:"response_status"`
+	Error          string `json:"error"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookDelivery,
+		arg.ID,
+		arg.Endpoint,
+		arg.URL,
+		arg.EventType,
+		arg.Payload,
+		arg.Status,
+		arg.Attempts,
+		arg.ResponseStatus,
+		arg.Error,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.Endpoint,
+		&i.URL,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createWorkspace = `-- name: CreateWorkspace :one
+INSERT INTO workspaces (id, name, max_tokens, created_at)
+VALUES (?, ?, ?, ?)
+RETURNING id, name, max_tokens, created_at
+`
+
+type CreateWorkspaceParams struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MaxTokens int64  `json:"max_tokens"`
+	CreatedAt string `json:"created_at"`
+}
+
+func (q *Queries) CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, createWorkspace,
+		arg.ID,
+		arg.Name,
+		arg.MaxTokens,
+		arg.CreatedAt,
+	)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.MaxTokens,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteDeadLetter = `-- name: DeleteDeadLetter :execrows
+DELETE FROM dead_letters WHERE id = ?
+`
+
+func (q *Queries) DeleteDeadLetter(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDeadLetter, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteDocumentChunks = `-- name: DeleteDocumentChunks :execrows
+DELETE FROM document_chunks WHERE session_id = ?
+`
+
+func (q *Queries) DeleteDocumentChunks(ctx context.Context, sessionID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDocumentChunks, sessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteEventsBySubject = `-- name: DeleteEventsBySubject :execrows
+DELETE FROM events
+WHERE json_valid(payload)
+  AND (json_extract(payload, '$.user_id') IN (SELECT value FROM json_each(CAST(?1 AS TEXT)))
+    OR json_extract(payload, '$.session_id') IN (SELECT value FROM json_each(CAST(?2 AS TEXT)))
+    OR EXISTS (SELECT 1 FROM json_each(CAST(?3 AS TEXT)) AS channel_user
+      WHERE json_extract(channel_user.value, '$.user_id') = json_extract(payload, '$.user_id')
+        AND json_extract(channel_user.value, '$.connector') = COALESCE(json_extract(payload, '$.connector'), json_extract(payload, '$.source'))))
+`
+
+type DeleteEventsBySubjectParams struct {
+	UserIds      string `json:"user_ids"`
+	SessionIds   string `json:"session_ids"`
+	ChannelUsers string `json:"channel_users"`
+}
+
+func (q *Queries) DeleteEventsBySubject(ctx context.Context, arg DeleteEventsBySubjectParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteEventsBySubject, arg.UserIds, arg.SessionIds, arg.ChannelUsers)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= CAST(? AS TEXT)
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context, now string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredIdempotencyKeys, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredProcessedMessages = `-- name: DeleteExpiredProcessedMessages :execrows
+DELETE FROM processed_messages
+WHERE expires_at <= CAST(? AS TEXT)
+`
+
+func (q *Queries) DeleteExpiredProcessedMessages(ctx context.Context, now string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredProcessedMessages, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredSessionAttributes = `-- name: DeleteExpiredSessionAttributes :execrows
+DELETE FROM session_attributes
+WHERE expires_at IS NOT NULL AND expires_at <= CAST(? AS TEXT)
+`
+
+func (q *Queries) DeleteExpiredSessionAttributes(ctx context.Context, now string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredSessionAttributes, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFinishedJobs = `-- name: DeleteFinishedJobs :execrows
+DELETE FROM jobs
+WHERE finished_at IS NOT NULL AND finished_at <= CAST(? AS TEXT)
+`
+
+func (q *Queries) DeleteFinishedJobs(ctx context.Context, finishedBefore string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFinishedJobs, finishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFinishedMessageJobs = `-- name: DeleteFinishedMessageJobs :execrows
+DELETE FROM message_jobs
+WHERE finished_at IS NOT NULL AND finished_at <= CAST(? AS TEXT)
+`
+
+func (q *Queries) DeleteFinishedMessageJobs(ctx context.Context, finishedBefore string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFinishedMessageJobs, finishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLog = `-- name: DeleteLog :exec
+DELETE FROM logs WHERE id = ?
+`
+
+func (q *Queries) DeleteLog(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteLog, id)
+	return err
+}
+
+const deleteLogsOlderThan = `-- name: DeleteLogsOlderThan :execrows
+DELETE FROM logs WHERE created_at < ?
+`
+
+func (q *Queries) DeleteLogsOlderThan(ctx context.Context, createdAt string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteLogsOlderThan, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteMessage = `-- name: DeleteMessage :exec
+DELETE FROM messages WHERE id = ?
+`
+
+func (q *Queries) DeleteMessage(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteMessage, id)
+	return err
+}
+
+const deleteMessageDeadLetter = `-- name: DeleteMessageDeadLetter :execrows
+DELETE FROM message_dead_letters WHERE id = ?
+`
+
+func (q *Queries) DeleteMessageDeadLetter(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMessageDeadLetter, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteMessageDeadLettersByUser = `-- name: DeleteMessageDeadLettersByUser :execrows
+DELETE FROM message_dead_letters WHERE connector = ? AND user_id = ?
+`
+
+type DeleteMessageDeadLettersByUserParams struct {
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+}
+
+func (q *Queries) DeleteMessageDeadLettersByUser(ctx context.Context, arg DeleteMessageDeadLettersByUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMessageDeadLettersByUser, arg.Connector, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteMessagesOlderThan = `-- name: DeleteMessagesOlderThan :execrows
+DELETE FROM messages
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+`
+
+func (q *Queries) DeleteMessagesOlderThan(ctx context.Context, createdAt string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMessagesOlderThan, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePendingIdempotencyKey = `-- name: DeletePendingIdempotencyKey :execrows
+DELETE FROM idempotency_keys
+WHERE key = ? AND status_code = 0
+`
+
+func (q *Queries) DeletePendingIdempotencyKey(ctx context.Context, key string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePendingIdempotencyKey, key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteProcessedMessage = `-- name: DeleteProcessedMessage :exec
+DELETE FROM processed_messages WHERE key = ?
+`
+
+func (q *Queries) DeleteProcessedMessage(ctx context.Context, key string) error {
+	_, err := q.db.ExecContext(ctx, deleteProcessedMessage, key)
+	return err
+}
+
+const deleteSchedule = `-- name: DeleteSchedule :exec
+DELETE FROM schedules WHERE id = ?
+`
+
+func (q *Queries) DeleteSchedule(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteSchedule, id)
+	return err
+}
+
+const deleteSession = `-- name: DeleteSession :exec
+DELETE FROM sessions WHERE id = ?
+`
+
+func (q *Queries) DeleteSession(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteSession, id)
+	return err
+}
+
+const deleteSessionAttribute = `-- name: DeleteSessionAttribute :execrows
+DELETE FROM session_attributes WHERE session_id = ? AND key = ?
+`
+
+type DeleteSessionAttributeParams struct {
+	SessionID string `json:"session_id"`
+	Key       string `json:"key"`
+}
+
+func (q *Queries) DeleteSessionAttribute(ctx context.Context, arg DeleteSessionAttributeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSessionAttribute, arg.SessionID, arg.Key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSkill = `-- name: DeleteSkill :exec
+DELETE FROM skills WHERE id = ?
+`
+
+func (q *Queries) DeleteSkill(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteSkill, id)
+	return err
+}
+
+const deleteSpilledMessage = `-- name: DeleteSpilledMessage :execrows
+DELETE FROM spilled_messages WHERE id = ?
+`
+
+func (q *Queries) DeleteSpilledMessage(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSpilledMessage, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTask = `-- name: DeleteTask :exec
+DELETE FROM tasks WHERE id = ?
+`
+
+func (q *Queries) DeleteTask(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteTask, id)
+	return err
+}
+
+const deleteTasksOlderThan = `-- name: DeleteTasksOlderThan :execrows
+DELETE FROM tasks
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+`
+
+func (q *Queries) DeleteTasksOlderThan(ctx context.Context, createdAt string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTasksOlderThan, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const deleteUserPreferences = `-- name: DeleteUserPreferences :execrows
+DELETE FROM user_preferences WHERE user_id = ?
+`
+
+func (q *Queries) DeleteUserPreferences(ctx context.Context, userID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserPreferences, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWorkspace = `-- name: DeleteWorkspace :execrows
+DELETE FROM workspaces WHERE id = ?
+`
+
+func (q *Queries) DeleteWorkspace(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWorkspace, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const failUnfinishedMessageJobs = `-- name: FailUnfinishedMessageJobs :execrows
+UPDATE message_jobs
+SET status = 'failed', error = ?, updated_at = ?, finished_at = ?
+WHERE status IN ('pending', 'running')
+`
+
+type FailUnfinishedMessageJobsParams struct {
+	Error      string         `json:"error"`
+	UpdatedAt  string         `json:"updated_at"`
+	FinishedAt sql.NullString `json:"finished_at"`
+}
+
+// Jobs left pending or running by a stopped process are failed at startup
+func (q *Queries) FailUnfinishedMessageJobs(ctx context.Context, arg FailUnfinishedMessageJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, failUnfinishedMessageJobs, arg.Error, arg.UpdatedAt, arg.FinishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAPIKeyByHash = `-- name: GetAPIKeyByHash :one
+SELECT id, name, key_hash, prefix, scope, created_at, revoked_at, workspace_id FROM api_keys
+WHERE key_hash = ?
+`
+
+func (q *Queries) GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyByHash, keyHash)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.KeyHash,
+		&i.Prefix,
+		&i.Scope,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const getDeadLetter = `-- name: GetDeadLetter :one
+SELECT id, subscription, event_type, payload, occurred_at, attempts, error, created_at, updated_at FROM dead_letters WHERE id = ?
+`
+
+func (q *Queries) GetDeadLetter(ctx context.Context, id string) (DeadLetter, error) {
+	row := q.db.QueryRowContext(ctx, getDeadLetter, id)
+	var i DeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Subscription,
+		&i.EventType,
+		&i.Payload,
+		&i.OccurredAt,
+		&i.Attempts,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getEvent = `-- name: GetEvent :one
+SELECT seq, id, type, payload, occurred_at FROM events WHERE id = ?
+`
+
+func (q *Queries) GetEvent(ctx context.Context, id string) (Event, error) {
+	row := q.db.QueryRowContext(ctx, getEvent, id)
+	var i Event
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.Type,
+		&i.Payload,
+		&i.OccurredAt,
+	)
+	return i, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT key, request_hash, status_code, content_type, body, expires_at, created_at FROM idempotency_keys
+WHERE key = ?1 AND expires_at > ?2
+`
+
+type GetIdempotencyKeyParams struct {
+	Key string `json:"key"`
+	Now string `json:"now"`
+}
+
+// Idempotency keys are found until they expire; saving keeps an unexpired key
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, arg.Key, arg.Now)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Key,
+		&i.RequestHash,
+		&i.StatusCode,
+		&i.ContentType,
+		&i.Body,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getJob = `-- name: GetJob :one
+SELECT id, kind, payload, status, attempts, max_attempts, error, run_at, locked_by, locked_until, created_at, updated_at, finished_at, leader_only FROM jobs WHERE id = ?
+`
+
+func (q *Queries) GetJob(ctx context.Context, id string) (Job, error) {
+	row := q.db.QueryRowContext(ctx, getJob, id)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.MaxAttempts,
+		&i.Error,
+		&i.RunAt,
+		&i.LockedBy,
+		&i.LockedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinishedAt,
+		&i.LeaderOnly,
+	)
+	return i, err
+}
+
+const getLatestScheduleRun = `-- name: GetLatestScheduleRun :one
+SELECT id, schedule_id, status, scheduled_at, started_at, finished_at, output, error FROM schedule_runs
+WHERE schedule_id = ?
+ORDER BY scheduled_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestScheduleRun(ctx context.Context, scheduleID string) (ScheduleRun, error) {
+	row := q.db.QueryRowContext(ctx, getLatestScheduleRun, scheduleID)
+	var i ScheduleRun
+	err := row.Scan(
+		&i.ID,
+		&i.ScheduleID,
+		&i.Status,
+		&i.ScheduledAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Output,
+		&i.Error,
+	)
+	return i, err
+}
+
+const getLogByID = `-- name: GetLogByID :one
+SELECT id, level, source, message, metadata, created_at FROM logs
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetLogByID(ctx context.Context, id string) (Log, error) {
+	row := q.db.QueryRowContext(ctx, getLogByID, id)
+	var i Log
+	err := row.Scan(
+		&i.ID,
+		&i.Level,
+		&i.Source,
+		&i.Message,
+		&i.Metadata,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLogsByDateRange = `-- name: GetLogsByDateRange :many
+SELECT id, level, source, message, metadata, created_at FROM logs
+WHERE created_at >= ? AND created_at <= ?
+ORDER BY created_at DESC
+LIMIT ?
+`
+
+type GetLogsByDateRangeParams struct {
+	CreatedAt   string `json:"created_at"`
+	CreatedAt_2 string `json:"created_at_2"`
+	Limit       int64  `json:"limit"`
+}
+
+func (q *Queries) GetLogsByDateRange(ctx context.Context, arg GetLogsByDateRangeParams) ([]Log, error) {
+	rows, err := q.db.QueryContext(ctx, getLogsByDateRange, arg.CreatedAt, arg.CreatedAt_2, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Log
+	for rows.Next() {
+		var i Log
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Source,
+			&i.Message,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLogsByLevel = `-- name: GetLogsByLevel :many
+SELECT id, level, source, message, metadata, created_at FROM logs
+WHERE level = ?1
+  AND (?2 = '' OR created_at >= ?2)
+  AND (?3 = '' OR created_at < ?3)
+  AND (?4 = '' OR created_at < ?4 OR (created_at = ?4 AND rowid < (SELECT l.rowid FROM logs l WHERE l.id = ?5)))
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?6 OFFSET ?7
+`
+
+type GetLogsByLevelParams struct {
+	Level          string `json:"level"`
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
+	Offset         int64  `json:"offset"`
+}
+
+func (q *Queries) GetLogsByLevel(ctx context.Context, arg GetLogsByLevelParams) ([]Log, error) {
+	rows, err := q.db.QueryContext(ctx, getLogsByLevel,
+		arg.Level,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Log
+	for rows.Next() {
+		var i Log
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Source,
+			&i.Message,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLogsBySource = `-- name: GetLogsBySource :many
+SELECT id, level, source, message, metadata, created_at FROM logs
+WHERE source = ?1
+  AND (?2 = '' OR created_at >= ?2)
+  AND (?3 = '' OR created_at < ?3)
+  AND (?4 = '' OR created_at < ?4 OR (created_at = ?4 AND rowid < (SELECT l.rowid FROM logs l WHERE l.id = ?5)))
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?6 OFFSET ?7
+`
+
+type GetLogsBySourceParams struct {
+	Source         string `json:"source"`
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
+	Offset         int64  `json:"offset"`
+}
+
+func (q *Queries) GetLogsBySource(ctx context.Context, arg GetLogsBySourceParams) ([]Log, error) {
+	rows, err := q.db.QueryContext(ctx, getLogsBySource,
+		arg.Source,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Log
+	for rows.Next() {
+		var i Log
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Source,
+			&i.Message,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMessageByID = `-- name: GetMessageByID :one
+SELECT id, session_id, role, content, created_at FROM messages
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetMessageByID(ctx context.Context, id string) (Message, error) {
+	row := q.db.QueryRowContext(ctx, getMessageByID, id)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.Role,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMessageDeadLetter = `-- name: GetMessageDeadLetter :one
+SELECT id, connector, user_id, channel_id, content, metadata, attempts, error, created_at, updated_at FROM message_dead_letters WHERE id = ?
+`
+
+func (q *Queries) GetMessageDeadLetter(ctx context.Context, id string) (MessageDeadLetter, error) {
+	row := q.db.QueryRowContext(ctx, getMessageDeadLetter, id)
+	var i MessageDeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Connector,
+		&i.UserID,
+		&i.ChannelID,
+		&i.Content,
+		&i.Metadata,
+		&i.Attempts,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getMessageFeedback = `-- name: GetMessageFeedback :one
+SELECT id, message_id, session_id, user_id, rating, comment, day, created_at, updated_at FROM message_feedback WHERE message_id = ?
+`
+
+func (q *Queries) GetMessageFeedback(ctx context.Context, messageID string) (MessageFeedback, error) {
+	row := q.db.QueryRowContext(ctx, getMessageFeedback, messageID)
+	var i MessageFeedback
+	err := row.Scan(
+		&i.ID,
+		&i.MessageID,
+		&i.SessionID,
+		&i.UserID,
+		&i.Rating,
+		&i.Comment,
+		&i.Day,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getMessageJob = `-- name: GetMessageJob :one
+SELECT id, session_id, content, status, message_id, reply, error, created_at, updated_at, finished_at, batch_id, batch_index FROM message_jobs WHERE id = ?
+`
+
+func (q *Queries) GetMessageJob(ctx context.Context, id string) (MessageJob, error) {
+	row := q.db.QueryRowContext(ctx, getMessageJob, id)
+	var i MessageJob
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.Content,
+		&i.Status,
+		&i.MessageID,
+		&i.Reply,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinishedAt,
+		&i.BatchID,
+		&i.BatchIndex,
+	)
+	return i, err
+}
+
+const getMessagesBySessionID = `-- name: GetMessagesBySessionID :many
+SELECT id, session_id, role, content, created_at FROM messages
+WHERE session_id = ?
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetMessagesBySessionID(ctx context.Context, sessionID string) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, getMessagesBySessionID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Role,
+			&i.Content,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getScheduleByID = `-- name: GetScheduleByID :one
+SELECT id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id, prompt, session_id FROM schedules
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetScheduleByID(ctx context.Context, id string) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, getScheduleByID, id)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.Skill,
+		&i.CronExpression,
+		&i.Input,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.Timezone,
+		&i.JitterSeconds,
+		&i.ScheduleType,
+		&i.RunAt,
+		&i.MissedRunPolicy,
+		&i.TargetConnector,
+		&i.TargetUserID,
+		&i.Prompt,
+		&i.SessionID,
+	)
+	return i, err
+}
+
+const getScheduleRunsByScheduleID = `-- name: GetScheduleRunsByScheduleID :many
+SELECT id, schedule_id, status, scheduled_at, started_at, finished_at, output, error FROM schedule_runs
+WHERE schedule_id = ?
+ORDER BY scheduled_at DESC
+LIMIT ?
+`
+
+type GetScheduleRunsByScheduleIDParams struct {
+	ScheduleID string `json:"schedule_id"`
+	Limit      int64  `json:"limit"`
+}
+
+func (q *Queries) GetScheduleRunsByScheduleID(ctx context.Context, arg GetScheduleRunsByScheduleIDParams) ([]ScheduleRun, error) {
+	rows, err := q.db.QueryContext(ctx, getScheduleRunsByScheduleID, arg.ScheduleID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduleRun
+	for rows.Next() {
+		var i ScheduleRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.ScheduleID,
+			&i.Status,
+			&i.ScheduledAt,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Output,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSchedulesBySkill = `-- name: GetSchedulesBySkill :many
+SELECT id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id, prompt, session_id FROM schedules
+WHERE skill = ?
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetSchedulesBySkill(ctx context.Context, skill sql.NullString) ([]Schedule, error) {
+	rows, err := q.db.QueryContext(ctx, getSchedulesBySkill, skill)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Schedule
+	for rows.Next() {
+		var i Schedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Skill,
+			&i.CronExpression,
+			&i.Input,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.Timezone,
+			&i.JitterSeconds,
+			&i.ScheduleType,
+			&i.RunAt,
+			&i.MissedRunPolicy,
+			&i.TargetConnector,
+			&i.TargetUserID,
+			&i.Prompt,
+			&i.SessionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionAttribute = `-- name: GetSessionAttribute :one
+SELECT session_id, key, value, expires_at, created_at, updated_at FROM session_attributes
+WHERE session_id = ? AND key = ?
+  AND (expires_at IS NULL OR expires_at > CAST(? AS TEXT))
+`
+
+type GetSessionAttributeParams struct {
+	SessionID string `json:"session_id"`
+	Key       string `json:"key"`
+	Now       string `json:"now"`
+}
+
+func (q *Queries) GetSessionAttribute(ctx context.Context, arg GetSessionAttributeParams) (SessionAttribute, error) {
+	row := q.db.QueryRowContext(ctx, getSessionAttribute, arg.SessionID, arg.Key, arg.Now)
+	var i SessionAttribute
+	err := row.Scan(
+		&i.SessionID,
+		&i.Key,
+		&i.Value,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSessionByID = `-- name: GetSessionByID :one
+SELECT id, user_id, created_at, updated_at, pinned, workspace_id, title FROM sessions
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error) {
+	row := q.db.QueryRowContext(ctx, getSessionByID, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Pinned,
+		&i.WorkspaceID,
+		&i.Title,
+	)
+	return i, err
+}
+
+const getSessionsByUserID = `-- name: GetSessionsByUserID :many
+SELECT id, user_id, created_at, updated_at, pinned, workspace_id, title FROM sessions
+WHERE user_id = ?
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetSessionsByUserID(ctx context.Context, userID string) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Pinned,
+			&i.WorkspaceID,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSkillByID = `-- name: GetSkillByID :one
+SELECT id, name, version, location, permissions, metadata, created_at, disabled, workspace_id FROM skills
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetSkillByID(ctx context.Context, id string) (Skill, error) {
+	row := q.db.QueryRowContext(ctx, getSkillByID, id)
+	var i Skill
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.Location,
+		&i.Permissions,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Disabled,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const getSkillByName = `-- name: GetSkillByName :one
+SELECT id, name, version, location, permissions, metadata, created_at, disabled, workspace_id FROM skills
+WHERE name = ? LIMIT 1
+`
+
+func (q *Queries) GetSkillByName(ctx context.Context, name string) (Skill, error) {
+	row := q.db.QueryRowContext(ctx, getSkillByName, name)
+	var i Skill
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.Location,
+		&i.Permissions,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Disabled,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const getTaskByID = `-- name: GetTaskByID :one
+SELECT id, session_id, skill, input, output, status, error, created_at, updated_at, started_at, finished_at FROM tasks
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetTaskByID(ctx context.Context, id string) (Task, error) {
+	row := q.db.QueryRowContext(ctx, getTaskByID, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.Skill,
+		&i.Input,
+		&i.Output,
+		&i.Status,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getTasksBySessionID = `-- name: GetTasksBySessionID :many
+SELECT id, session_id, skill, input, output, status, error, created_at, updated_at, started_at, finished_at FROM tasks
+WHERE session_id = ?
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetTasksBySessionID(ctx context.Context, sessionID string) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, getTasksBySessionID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Skill,
+			&i.Input,
+			&i.Output,
+			&i.Status,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByChannel = `-- name: GetUserByChannel :one
+SELECT id, channel, channel_user_id, created_at FROM users
+WHERE channel = ? AND channel_user_id = ? LIMIT 1
+`
+
+type GetUserByChannelParams struct {
+	Channel       valueobject.Channel `json:"channel"`
+	ChannelUserID string              `json:"channel_user_id"`
+}
+
+func (q *Queries) GetUserByChannel(ctx context.Context, arg GetUserByChannelParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByChannel, arg.Channel, arg.ChannelUserID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Channel,
+		&i.ChannelUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, channel, channel_user_id, created_at FROM users
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Channel,
+		&i.ChannelUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserPreferences = `-- name: GetUserPreferences :one
+SELECT user_id, language, model, timezone, verbosity, updated_at, instructions FROM user_preferences WHERE user_id = ?
+`
+
+func (q *Queries) GetUserPreferences(ctx context.Context, userID string) (UserPreference, error) {
+	row := q.db.QueryRowContext(ctx, getUserPreferences, userID)
+	var i UserPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Language,
+		&i.Model,
+		&i.Timezone,
+		&i.Verbosity,
+		&i.UpdatedAt,
+		&i.Instructions,
+	)
+	return i, err
+}
+
+const getWorkspaceByID = `-- name: GetWorkspaceByID :one
+SELECT id, name, max_tokens, created_at FROM workspaces WHERE id = ?
+`
+
+func (q *Queries) GetWorkspaceByID(ctx context.Context, id string) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceByID, id)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.MaxTokens,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const incrementAnalyticsCounter = `-- name: IncrementAnalyticsCounter :exec
+INSERT INTO analytics_counters (day, metric, dimension, value)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (day, metric, dimension) DO UPDATE
+SET value = analytics_counters.value + excluded.value
+`
+
+type IncrementAnalyticsCounterParams struct {
+	Day       string  `json:"day"`
+	Metric    string  `json:"metric"`
+	Dimension string  `json:"dimension"`
+	Value     float64 `json:"value"`
+}
+
+func (q *Queries) IncrementAnalyticsCounter(ctx context.Context, arg IncrementAnalyticsCounterParams) error {
+	_, err := q.db.ExecContext(ctx, incrementAnalyticsCounter,
+		arg.Day,
+		arg.Metric,
+		arg.Dimension,
+		arg.Value,
+	)
+	return err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, name, key_hash, prefix, scope, created_at, revoked_at, workspace_id FROM api_keys
+ORDER BY created_at ASC, rowid ASC
+`
+
+func (q *Queries) ListAPIKeys(ctx context.Context) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiKey
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.KeyHash,
+			&i.Prefix,
+			&i.Scope,
+			&i.CreatedAt,
+			&i.RevokedAt,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAnalyticsCounters = `-- name: ListAnalyticsCounters :many
+SELECT day, metric, dimension, value FROM analytics_counters
+WHERE day >= ?1 AND day <= ?2
+ORDER BY day, metric, dimension
+`
+
+type ListAnalyticsCountersParams struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+func (q *Queries) ListAnalyticsCounters(ctx context.Context, arg ListAnalyticsCountersParams) ([]AnalyticsCounter, error) {
+	rows, err := q.db.QueryContext(ctx, listAnalyticsCounters, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AnalyticsCounter
+	for rows.Next() {
+		var i AnalyticsCounter
+		if err := rows.Scan(
+			&i.Day,
+			&i.Metric,
+			&i.Dimension,
+			&i.Value,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCrashReports = `-- name: ListCrashReports :many
+SELECT id, component, operation, panic, stack, attributes, created_at FROM crash_reports
+WHERE (?1 = '' OR component = ?1)
+ORDER BY created_at DESC, id DESC
+LIMIT ?2 OFFSET ?3
+`
+
+type ListCrashReportsParams struct {
+	Component string `json:"component"`
+	Limit     int64  `json:"limit"`
+	Offset    int64  `json:"offset"`
+}
+
+func (q *Queries) ListCrashReports(ctx context.Context, arg ListCrashReportsParams) ([]CrashReport, error) {
+	rows, err := q.db.QueryContext(ctx, listCrashReports, arg.Component, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CrashReport
+	for rows.Next() {
+		var i CrashReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.Component,
+			&i.Operation,
+			&i.Panic,
+			&i.Stack,
+			&i.Attributes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeadLetters = `-- name: ListDeadLetters :many
+SELECT id, subscription, event_type, payload, occurred_at, attempts, error, created_at, updated_at FROM dead_letters
+WHERE (?1 = '' OR subscription = ?1)
+ORDER BY created_at DESC, id DESC
+LIMIT ?2 OFFSET ?3
+`
+
+type ListDeadLettersParams struct {
+	Subscription string `json:"subscription"`
+	Limit        int64  `json:"limit"`
+	Offset       int64  `json:"offset"`
+}
+
+func (q *Queries) ListDeadLetters(ctx context.Context, arg ListDeadLettersParams) ([]DeadLetter, error) {
+	rows, err := q.db.QueryContext(ctx, listDeadLetters, arg.Subscription, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeadLetter
+	for rows.Next() {
+		var i DeadLetter
+		if err := rows.Scan(
+			&i.ID,
+			&i.Subscription,
+			&i.EventType,
+			&i.Payload,
+			&i.OccurredAt,
+			&i.Attempts,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDocumentChunks = `-- name: ListDocumentChunks :many
+SELECT id, session_id, document_id, document_name, position, content, embedding, created_at FROM document_chunks
+WHERE session_id = ?
+ORDER BY created_at, document_id, position
+`
+
+func (q *Queries) ListDocumentChunks(ctx context.Context, sessionID string) ([]DocumentChunk, error) {
+	rows, err := q.db.QueryContext(ctx, listDocumentChunks, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DocumentChunk
+	for rows.Next() {
+		var i DocumentChunk
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.DocumentID,
+			&i.DocumentName,
+			&i.Position,
+			&i.Content,
+			&i.Embedding,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEvents = `-- name: ListEvents :many
+SELECT seq, id, type, payload, occurred_at FROM events
+WHERE seq > ?1
+  AND (?2 = '' OR occurred_at >= ?2)
+  AND (?3 = '' OR occurred_at < ?3)
+  AND (?4 = '' OR type IN (SELECT value FROM json_each(?4)))
+ORDER BY seq ASC
+LIMIT ?5
+`
+
+type ListEventsParams struct {
+	AfterSeq int64  `json:"after_seq"`
+	Since    string `json:"since"`
+	Until    string `json:"until"`
+	Types    string `json:"types"`
+	Limit    int64  `json:"limit"`
+}
+
+func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error) {
+	rows, err := q.db.QueryContext(ctx, listEvents,
+		arg.AfterSeq,
+		arg.Since,
+		arg.Until,
+		arg.Types,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.Type,
+			&i.Payload,
+			&i.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsBySubject = `-- name: ListEventsBySubject :many
+SELECT seq, id, type, payload, occurred_at FROM events
+WHERE json_valid(payload)
+  AND (json_extract(payload, '$.user_id') IN (SELECT value FROM json_each(CAST(?1 AS TEXT)))
+    OR json_extract(payload, '$.session_id') IN (SELECT value FROM json_each(CAST(?2 AS TEXT)))
+    OR EXISTS (SELECT 1 FROM json_each(CAST(?3 AS TEXT)) AS channel_user
+      WHERE json_extract(channel_user.value, '$.user_id') = json_extract(payload, '$.user_id')
+        AND json_extract(channel_user.value, '$.connector') = COALESCE(json_extract(payload, '$.connector'), json_extract(payload, '$.source'))))
+ORDER BY seq ASC
+`
+
+type ListEventsBySubjectParams struct {
+	UserIds      string `json:"user_ids"`
+	SessionIds   string `json:"session_ids"`
+	ChannelUsers string `json:"channel_users"`
+}
+
+func (q *Queries) ListEventsBySubject(ctx context.Context, arg ListEventsBySubjectParams) ([]Event, error) {
+	rows, err := q.db.QueryContext(ctx, listEventsBySubject, arg.UserIds, arg.SessionIds, arg.ChannelUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.Type,
+			&i.Payload,
+			&i.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLogs = `-- name: ListLogs :many
+SELECT id, level, source, message, metadata, created_at FROM logs
+WHERE (?1 = '' OR level = ?1)
+  AND (?2 = '' OR source = ?2)
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?3 OFFSET ?4
+`
+
+type ListLogsParams struct {
+	Level  string `json:"level"`
+	Source string `json:"source"`
+	Limit  int64  `json:"limit"`
+	Offset int64  `json:"offset"`
+}
+
+func (q *Queries) ListLogs(ctx context.Context, arg ListLogsParams) ([]Log, error) {
+	rows, err := q.db.QueryContext(ctx, listLogs,
+		arg.Level,
+		arg.Source,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Log
+	for rows.Next() {
+		var i Log
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Source,
+			&i.Message,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLogsOlderThan = `-- name: ListLogsOlderThan :many
+SELECT id, level, source, message, metadata, created_at FROM logs
+WHERE created_at < ?1
+  AND (?2 = '' OR created_at > ?2 OR (created_at = ?2 AND rowid > (SELECT l.rowid FROM logs l WHERE l.id = ?3)))
+ORDER BY created_at ASC, rowid ASC
+LIMIT ?4
+`
+
+type ListLogsOlderThanParams struct {
+	Before         string `json:"before"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
+}
+
+func (q *Queries) ListLogsOlderThan(ctx context.Context, arg ListLogsOlderThanParams) ([]Log, error) {
+	rows, err := q.db.QueryContext(ctx, listLogsOlderThan,
+		arg.Before,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Log
+	for rows.Next() {
+		var i Log
+		if err := rows.Scan(
+			&i.ID,
+			&i.Level,
+			&i.Source,
+			&i.Message,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessageDeadLetters = `-- name: ListMessageDeadLetters :many
+SELECT id, connector, user_id, channel_id, content, metadata, attempts, error, created_at, updated_at FROM message_dead_letters
+WHERE (?1 = '' OR connector = ?1)
+  AND (?2 = '' OR user_id = ?2)
+ORDER BY created_at DESC, id DESC
+LIMIT ?3 OFFSET ?4
+`
+
+type ListMessageDeadLettersParams struct {
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+	Limit     int64  `json:"limit"`
+	Offset    int64  `json:"offset"`
+}
+
+func (q *Queries) ListMessageDeadLetters(ctx context.Context, arg ListMessageDeadLettersParams) ([]MessageDeadLetter, error) {
+	rows, err := q.db.QueryContext(ctx, listMessageDeadLetters,
+		arg.Connector,
+		arg.UserID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageDeadLetter
+	for rows.Next() {
+		var i MessageDeadLetter
+		if err := rows.Scan(
+			&i.ID,
+			&i.Connector,
+			&i.UserID,
+			&i.ChannelID,
+			&i.Content,
+			&i.Metadata,
+			&i.Attempts,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessageJobsByBatch = `-- name: ListMessageJobsByBatch :many
+SELECT id, session_id, content, status, message_id, reply, error, created_at, updated_at, finished_at, batch_id, batch_index FROM message_jobs
+WHERE batch_id = ?
+ORDER BY batch_index ASC
+`
+
+func (q *Queries) ListMessageJobsByBatch(ctx context.Context, batchID string) ([]MessageJob, error) {
+	rows, err := q.db.QueryContext(ctx, listMessageJobsByBatch, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageJob
+	for rows.Next() {
+		var i MessageJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Content,
+			&i.Status,
+			&i.MessageID,
+			&i.Reply,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FinishedAt,
+			&i.BatchID,
+			&i.BatchIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessagesBySessionID = `-- name: ListMessagesBySessionID :many
+SELECT id, session_id, role, content, created_at FROM messages
+WHERE session_id = ?1
+  AND (?2 = '' OR created_at >= ?2)
+  AND (?3 = '' OR created_at < ?3)
+  AND (?4 = '' OR created_at > ?4 OR (created_at = ?4 AND rowid > (SELECT m.rowid FROM messages m WHERE m.id = ?5)))
+ORDER BY created_at ASC, rowid ASC
+LIMIT ?6 OFFSET ?7
+`
+
+type ListMessagesBySessionIDParams struct {
+	SessionID      string `json:"session_id"`
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
+	Offset         int64  `json:"offset"`
+}
+
+func (q *Queries) ListMessagesBySessionID(ctx context.Context, arg ListMessagesBySessionIDParams) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, listMessagesBySessionID,
+		arg.SessionID,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Role,
+			&i.Content,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessagesOlderThan = `-- name: ListMessagesOlderThan :many
+SELECT id, session_id, role, content, created_at FROM messages
+WHERE created_at < ?1
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+  AND (?2 = '' OR created_at > ?2 OR (created_at = ?2 AND rowid > (SELECT m.rowid FROM messages m WHERE m.id = ?3)))
+ORDER BY created_at ASC, rowid ASC
+LIMIT ?4
+`
+
+type ListMessagesOlderThanParams struct {
+	Before         string `json:"before"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
+}
+
+func (q *Queries) ListMessagesOlderThan(ctx context.Context, arg ListMessagesOlderThanParams) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, listMessagesOlderThan,
+		arg.Before,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Role,
+			&i.Content,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSchedules = `-- name: ListSchedules :many
+SELECT id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id, prompt, session_id FROM schedules
+ORDER BY created_at DESC
 `
 
-func (q *Queries) DeleteTask(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteTask, id)
-	return err
+func (q *Queries) ListSchedules(ctx context.Context) ([]Schedule, error) {
+	rows, err := q.db.QueryContext(ctx, listSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Schedule
+	for rows.Next() {
+		var i Schedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Skill,
+			&i.CronExpression,
+			&i.Input,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.Timezone,
+			&i.JitterSeconds,
+			&i.ScheduleType,
+			&i.RunAt,
+			&i.MissedRunPolicy,
+			&i.TargetConnector,
+			&i.TargetUserID,
+			&i.Prompt,
+			&i.SessionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users WHERE id = ?
+const listSessionAttributes = `-- name: ListSessionAttributes :many
+SELECT session_id, key, value, expires_at, created_at, updated_at FROM session_attributes
+WHERE session_id = ?
+  AND (expires_at IS NULL OR expires_at > CAST(? AS TEXT))
+ORDER BY key
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
-	return err
+type ListSessionAttributesParams struct {
+	SessionID string `json:"session_id"`
+	Now       string `json:"now"`
 }
 
-const getLogByID = `-- name: GetLogByID :one
-SELECT id, level, source, message, metadata, created_at FROM logs
-WHERE id = ? LIMIT 1
+func (q *Queries) ListSessionAttributes(ctx context.Context, arg ListSessionAttributesParams) ([]SessionAttribute, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionAttributes, arg.SessionID, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionAttribute
+	for rows.Next() {
+		var i SessionAttribute
+		if err := rows.Scan(
+			&i.SessionID,
+			&i.Key,
+			&i.Value,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSessions = `-- name: ListSessions :many
+SELECT id, user_id, created_at, updated_at, pinned, workspace_id, title FROM sessions
+WHERE (?1 = '' OR created_at >= ?1)
+  AND (?2 = '' OR created_at < ?2)
+  AND (?3 = '' OR created_at < ?3 OR (created_at = ?3 AND rowid < (SELECT s.rowid FROM sessions s WHERE s.id = ?4)))
+  AND (?5 = '' OR workspace_id = ?5)
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?6 OFFSET ?7
 `
 
-func (q *Queries) GetLogByID(ctx context.Context, id string) (Log, error) {
-	row := q.db.QueryRowContext(ctx, getLogByID, id)
-	var i Log
-	err := row.Scan(
-		&i.ID,
-		&i.Level,
-		&i.Source,
-		&i.Message,
-		&i.Metadata,
-		&i.CreatedAt,
+type ListSessionsParams struct {
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	WorkspaceID    string `json:"workspace_id"`
+	Limit          int64  `json:"limit"`
+	Offset         int64  `json:"offset"`
+}
+
+func (q *Queries) ListSessions(ctx context.Context, arg ListSessionsParams) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listSessions,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.WorkspaceID,
+		arg.Limit,
+		arg.Offset,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Pinned,
+			&i.WorkspaceID,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getLogsByDateRange = `-- name: GetLogsByDateRange :many
-SELECT id, level, source, message, metadata, created_at FROM logs
-WHERE created_at >= ? AND created_at <= ?
+const listSessionsByUserID = `-- name: ListSessionsByUserID :many
+SELECT id, user_id, created_at, updated_at, pinned, workspace_id, title FROM sessions
+WHERE user_id = ?1
+  AND (?2 = '' OR created_at >= ?2)
+  AND (?3 = '' OR created_at < ?3)
+  AND (?4 = '' OR created_at < ?4 OR (created_at = ?4 AND rowid < (SELECT s.rowid FROM sessions s WHERE s.id = ?5)))
+  AND (?6 = '' OR workspace_id = ?6)
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?7 OFFSET ?8
+`
+
+type ListSessionsByUserIDParams struct {
+	UserID         string `json:"user_id"`
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	WorkspaceID    string `json:"workspace_id"`
+	Limit          int64  `json:"limit"`
+	Offset         int64  `json:"offset"`
+}
+
+func (q *Queries) ListSessionsByUserID(ctx context.Context, arg ListSessionsByUserIDParams) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionsByUserID,
+		arg.UserID,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.WorkspaceID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Pinned,
+			&i.WorkspaceID,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSkills = `-- name: ListSkills :many
+SELECT id, name, version, location, permissions, metadata, created_at, disabled, workspace_id FROM skills
 ORDER BY created_at DESC
+`
+
+func (q *Queries) ListSkills(ctx context.Context) ([]Skill, error) {
+	rows, err := q.db.QueryContext(ctx, listSkills)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Skill
+	for rows.Next() {
+		var i Skill
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Version,
+			&i.Location,
+			&i.Permissions,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.Disabled,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSpilledMessages = `-- name: ListSpilledMessages :many
+SELECT id, connector, user_id, channel_id, content, metadata, sequence, created_at FROM spilled_messages
+WHERE connector = ?
+ORDER BY sequence ASC
 LIMIT ?
 `
 
-type GetLogsByDateRangeParams struct {
-	CreatedAt   string `json:"created_at"`
-	CreatedAt_2 string `json:"created_at_2"`
-	Limit       int64  `json:"limit"`
+type ListSpilledMessagesParams struct {
+	Connector string `json:"connector"`
+	Limit     int64  `json:"limit"`
 }
 
-func (q *Queries) GetLogsByDateRange(ctx context.Context, arg GetLogsByDateRangeParams) ([]Log, error) {
-	rows, err := q.db.QueryContext(ctx, getLogsByDateRange, arg.CreatedAt, arg.CreatedAt_2, arg.Limit)
+func (q *Queries) ListSpilledMessages(ctx context.Context, arg ListSpilledMessagesParams) ([]SpilledMessage, error) {
+	rows, err := q.db.QueryContext(ctx, listSpilledMessages, arg.Connector, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Log
+	var items []SpilledMessage
 	for rows.Next() {
-		var i Log
+		var i SpilledMessage
 		if err := rows.Scan(
 			&i.ID,
-			&i.Level,
-			&i.Source,
-			&i.Message,
+			&i.Connector,
+			&i.UserID,
+			&i.ChannelID,
+			&i.Content,
 			&i.Metadata,
+			&i.Sequence,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskAttempts = `-- name: ListTaskAttempts :many
+SELECT id, task_id, attempt, status, error, retryable, started_at, finished_at FROM task_attempts
+WHERE task_id = ?
+ORDER BY attempt ASC
+`
+
+func (q *Queries) ListTaskAttempts(ctx context.Context, taskID string) ([]TaskAttempt, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskAttempts, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaskAttempt
+	for rows.Next() {
+		var i TaskAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.Attempt,
+			&i.Status,
+			&i.Error,
+			&i.Retryable,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksBySessionID = `-- name: ListTasksBySessionID :many
+SELECT id, session_id, skill, input, output, status, error, created_at, updated_at, started_at, finished_at FROM tasks
+WHERE session_id = ?1
+  AND (?2 = '' OR created_at >= ?2)
+  AND (?3 = '' OR created_at < ?3)
+  AND (?4 = '' OR created_at < ?4 OR (created_at = ?4 AND rowid < (SELECT t.rowid FROM tasks t WHERE t.id = ?5)))
+ORDER BY created_at DESC, rowid DESC
+LIMIT ?6 OFFSET ?7
+`
+
+type ListTasksBySessionIDParams struct {
+	SessionID      string `json:"session_id"`
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
+	Offset         int64  `json:"offset"`
+}
+
+func (q *Queries) ListTasksBySessionID(ctx context.Context, arg ListTasksBySessionIDParams) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, listTasksBySessionID,
+		arg.SessionID,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.Skill,
+			&i.Input,
+			&i.Output,
+			&i.Status,
+			&i.Error,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -389,34 +3308,48 @@ func (q *Queries) GetLogsByDateRange(ctx context.Context, arg GetLogsByDateRange
 	return items, nil
 }
 
-const getLogsByLevel = `-- name: GetLogsByLevel :many
-SELECT id, level, source, message, metadata, created_at FROM logs
-WHERE level = ?
-ORDER BY created_at DESC
-LIMIT ?
+const listTasksOlderThan = `-- name: ListTasksOlderThan :many
+SELECT id, session_id, skill, input, output, status, error, created_at, updated_at, started_at, finished_at FROM tasks
+WHERE created_at < ?1
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+  AND (?2 = '' OR created_at > ?2 OR (created_at = ?2 AND rowid > (SELECT t.rowid FROM tasks t WHERE t.id = ?3)))
+ORDER BY created_at ASC, rowid ASC
+LIMIT ?4
 `
 
-type GetLogsByLevelParams struct {
-	Level string `json:"level"`
-	Limit int64  `json:"limit"`
+type ListTasksOlderThanParams struct {
+	Before         string `json:"before"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
 }
 
-func (q *Queries) GetLogsByLevel(ctx context.Context, arg GetLogsByLevelParams) ([]Log, error) {
-	rows, err := q.db.QueryContext(ctx, getLogsByLevel, arg.Level, arg.Limit)
+func (q *Queries) ListTasksOlderThan(ctx context.Context, arg ListTasksOlderThanParams) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, listTasksOlderThan,
+		arg.Before,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Log
+	var items []Task
 	for rows.Next() {
-		var i Log
+		var i Task
 		if err := rows.Scan(
 			&i.ID,
-			&i.Level,
-			&i.Source,
-			&i.Message,
-			&i.Metadata,
+			&i.SessionID,
+			&i.Skill,
+			&i.Input,
+			&i.Output,
+			&i.Status,
+			&i.Error,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -431,33 +3364,24 @@ func (q *Queries) GetLogsByLevel(ctx context.Context, arg GetLogsByLevelParams)
 	return items, nil
 }
 
-const getLogsBySource = `-- name: GetLogsBySource :many
-SELECT id, level, source, message, metadata, created_at FROM logs
-WHERE source = ?
+const listUsers = `-- name: ListUsers :many
+SELECT id, channel, channel_user_id, created_at FROM users
 ORDER BY created_at DESC
-LIMIT ?
 `
 
-type GetLogsBySourceParams struct {
-	Source string `json:"source"`
-	Limit  int64  `json:"limit"`
-}
-
-func (q *Queries) GetLogsBySource(ctx context.Context, arg GetLogsBySourceParams) ([]Log, error) {
-	rows, err := q.db.QueryContext(ctx, getLogsBySource, arg.Source, arg.Limit)
+func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Log
+	var items []User
 	for rows.Next() {
-		var i Log
+		var i User
 		if err := rows.Scan(
 			&i.ID,
-			&i.Level,
-			&i.Source,
-			&i.Message,
-			&i.Metadata,
+			&i.Channel,
+			&i.ChannelUserID,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -473,44 +3397,39 @@ func (q *Queries) GetLogsBySource(ctx context.Context, arg GetLogsBySourceParams
 	return items, nil
 }
 
-const getMessageByID = `-- name: GetMessageByID :one
-SELECT id, session_id, role, content, created_at FROM messages
-WHERE id = ? LIMIT 1
+const listUserDataErasures = `-- name: ListUserDataErasures :many
+SELECT id, user_id, requested_by, reason, sessions, messages, tasks, attributes, preferences, verified, created_at FROM user_data_erasures
+WHERE (?1 = '' OR user_id = ?1)
+ORDER BY created_at DESC, id DESC
+LIMIT ?2 OFFSET ?3
 `
 
-func (q *Queries) GetMessageByID(ctx context.Context, id string) (Message, error) {
-	row := q.db.QueryRowContext(ctx, getMessageByID, id)
-	var i Message
-	err := row.Scan(
-		&i.ID,
-		&i.SessionID,
-		&i.Role,
-		&i.Content,
-		&i.CreatedAt,
-	)
-	return i, err
+type ListUserDataErasuresParams struct {
+	UserID string `json:"user_id"`
+	Limit  int64  `json:"limit"`
+	Offset int64  `json:"offset"`
 }
 
-const getMessagesBySessionID = `-- name: GetMessagesBySessionID :many
-SELECT id, session_id, role, content, created_at FROM messages
-WHERE session_id = ?
-ORDER BY created_at ASC
-`
-
-func (q *Queries) GetMessagesBySessionID(ctx context.Context, sessionID string) ([]Message, error) {
-	rows, err := q.db.QueryContext(ctx, getMessagesBySessionID, sessionID)
+func (q *Queries) ListUserDataErasures(ctx context.Context, arg ListUserDataErasuresParams) ([]UserDataErasure, error) {
+	rows, err := q.db.QueryContext(ctx, listUserDataErasures, arg.UserID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Message
+	var items []UserDataErasure
 	for rows.Next() {
-		var i Message
+		var i UserDataErasure
 		if err := rows.Scan(
 			&i.ID,
-			&i.SessionID,
-			&i.Role,
-			&i.Content,
+			&i.UserID,
+			&i.RequestedBy,
+			&i.Reason,
+			&i.Sessions,
+			&i.Messages,
+			&i.Tasks,
+			&i.Attributes,
+			&i.Preferences,
+			&i.Verified,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -526,47 +3445,47 @@ func (q *Queries) GetMessagesBySessionID(ctx context.Context, sessionID string)
 	return items, nil
 }
 
-const getScheduleByID = `-- name: GetScheduleByID :one
-SELECT id, skill, cron_expression, input, enabled, created_at FROM schedules
-WHERE id = ? LIMIT 1
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, endpoint, url, event_type, payload, status, attempts, response_status, error, created_at, updated_at FROM webhook_deliveries
+WHERE (?1 = '' OR endpoint = ?1)
+  AND (?2 = '' OR status = ?2)
+ORDER BY created_at DESC, id DESC
+LIMIT ?3 OFFSET ?4
 `
 
-func (q *Queries) GetScheduleByID(ctx context.Context, id string) (Schedule, error) {
-	row := q.db.QueryRowContext(ctx, getScheduleByID, id)
-	var i Schedule
-	err := row.Scan(
-		&i.ID,
-		&i.Skill,
-		&i.CronExpression,
-		&i.Input,
-		&i.Enabled,
-		&i.CreatedAt,
-	)
-	return i, err
+type ListWebhookDeliveriesParams struct {
+	Endpoint string `json:"endpoint"`
+	Status   string `json:"status"`
+	Limit    int64  `json:"limit"`
+	Offset   int64  `json:"offset"`
 }
 
-const getSchedulesBySkill = `-- name: GetSchedulesBySkill :many
-SELECT id, skill, cron_expression, input, enabled, created_at FROM schedules
-WHERE skill = ?
-ORDER BY created_at DESC
-`
-
-func (q *Queries) GetSchedulesBySkill(ctx context.Context, skill string) ([]Schedule, error) {
-	rows, err := q.db.QueryContext(ctx, getSchedulesBySkill, skill)
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveries,
+		arg.Endpoint,
+		arg.Status,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Schedule
+	var items []WebhookDelivery
 	for rows.Next() {
-		var i Schedule
+		var i WebhookDelivery
 		if err := rows.Scan(
 			&i.ID,
-			&i.Skill,
-			&i.CronExpression,
-			&i.Input,
-			&i.Enabled,
+			&i.Endpoint,
+			&i.URL,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.Error,
 			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -581,43 +3500,25 @@ func (q *Queries) GetSchedulesBySkill(ctx context.Context, skill string) ([]Sche
 	return items, nil
 }
 
-const getSessionByID = `-- name: GetSessionByID :one
-SELECT id, user_id, created_at, updated_at FROM sessions
-WHERE id = ? LIMIT 1
-`
-
-func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error) {
-	row := q.db.QueryRowContext(ctx, getSessionByID, id)
-	var i Session
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
-}
-
-const getSessionsByUserID = `-- name: GetSessionsByUserID :many
-SELECT id, user_id, created_at, updated_at FROM sessions
-WHERE user_id = ?
-ORDER BY created_at DESC
+const listWorkspaces = `-- name: ListWorkspaces :many
+SELECT id, name, max_tokens, created_at FROM workspaces
+ORDER BY created_at ASC, id ASC
 `
 
-func (q *Queries) GetSessionsByUserID(ctx context.Context, userID string) ([]Session, error) {
-	rows, err := q.db.QueryContext(ctx, getSessionsByUserID, userID)
+func (q *Queries) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkspaces)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Session
+	var items []Workspace
 	for rows.Next() {
-		var i Session
+		var i Workspace
 		if err := rows.Scan(
 			&i.ID,
-			&i.UserID,
+			&i.Name,
+			&i.MaxTokens,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -632,166 +3533,177 @@ func (q *Queries) GetSessionsByUserID(ctx context.Context, userID string) ([]Ses
 	return items, nil
 }
 
-const getSkillByID = `-- name: GetSkillByID :one
-SELECT id, name, version, location, permissions, metadata, created_at FROM skills
-WHERE id = ? LIMIT 1
+const recordProcessedMessage = `-- name: RecordProcessedMessage :execrows
+INSERT INTO processed_messages (key, expires_at, created_at)
+VALUES (?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET expires_at = excluded.expires_at, created_at = excluded.created_at
+WHERE processed_messages.expires_at <= excluded.created_at
 `
 
-func (q *Queries) GetSkillByID(ctx context.Context, id string) (Skill, error) {
-	row := q.db.QueryRowContext(ctx, getSkillByID, id)
-	var i Skill
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Version,
-		&i.Location,
-		&i.Permissions,
-		&i.Metadata,
-		&i.CreatedAt,
-	)
-	return i, err
+type RecordProcessedMessageParams struct {
+	Key       string `json:"key"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
 }
 
-const getSkillByName = `-- name: GetSkillByName :one
-SELECT id, name, version, location, permissions, metadata, created_at FROM skills
-WHERE name = ? LIMIT 1
-`
-
-func (q *Queries) GetSkillByName(ctx context.Context, name string) (Skill, error) {
-	row := q.db.QueryRowContext(ctx, getSkillByName, name)
-	var i Skill
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Version,
-		&i.Location,
-		&i.Permissions,
-		&i.Metadata,
-		&i.CreatedAt,
-	)
-	return i, err
+func (q *Queries) RecordProcessedMessage(ctx context.Context, arg RecordProcessedMessageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordProcessedMessage, arg.Key, arg.ExpiresAt, arg.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getTaskByID = `-- name: GetTaskByID :one
-SELECT id, session_id, skill, input, output, status, error, created_at, updated_at FROM tasks
-WHERE id = ? LIMIT 1
+const releaseLease = `-- name: ReleaseLease :exec
+DELETE FROM leases WHERE name = ? AND holder = ?
 `
 
-func (q *Queries) GetTaskByID(ctx context.Context, id string) (Task, error) {
-	row := q.db.QueryRowContext(ctx, getTaskByID, id)
-	var i Task
-	err := row.Scan(
-		&i.ID,
-		&i.SessionID,
-		&i.Skill,
-		&i.Input,
-		&i.Output,
-		&i.Status,
-		&i.Error,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type ReleaseLeaseParams struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder"`
 }
 
-const getTasksBySessionID = `-- name: GetTasksBySessionID :many
-SELECT id, session_id, skill, input, output, status, error, created_at, updated_at FROM tasks
-WHERE session_id = ?
-ORDER BY created_at DESC
+func (q *Queries) ReleaseLease(ctx context.Context, arg ReleaseLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, releaseLease, arg.Name, arg.Holder)
+	return err
+}
+
+const reserveIdempotencyKey = `-- name: ReserveIdempotencyKey :execrows
+INSERT INTO idempotency_keys (key, request_hash, status_code, content_type, body, expires_at, created_at)
+VALUES (?, ?, 0, '', '', ?, ?)
+ON CONFLICT (key) DO UPDATE SET request_hash = excluded.request_hash, status_code = 0,
+    content_type = '', body = '', expires_at = excluded.expires_at, created_at = excluded.created_at
+WHERE idempotency_keys.expires_at <= excluded.created_at
 `
 
-func (q *Queries) GetTasksBySessionID(ctx context.Context, sessionID string) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, getTasksBySessionID, sessionID)
+type ReserveIdempotencyKeyParams struct {
+	Key         string `json:"key"`
+	RequestHash string `json:"request_hash"`
+	ExpiresAt   string `json:"expires_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// A pending key (status code 0) is reserved before its request is handled, unless an
+// unexpired key exists, and replaced by the response of the request
+func (q *Queries) ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reserveIdempotencyKey,
+		arg.Key,
+		arg.RequestHash,
+		arg.ExpiresAt,
+		arg.CreatedAt,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Task
-	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.SessionID,
-			&i.Skill,
-			&i.Input,
-			&i.Output,
-			&i.Status,
-			&i.Error,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const getUserByChannel = `-- name: GetUserByChannel :one
-SELECT id, channel, channel_user_id, created_at FROM users
-WHERE channel = ? AND channel_user_id = ? LIMIT 1
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = ?
+WHERE id = ? AND revoked_at IS NULL
 `
 
-type GetUserByChannelParams struct {
-	Channel       string `json:"channel"`
-	ChannelUserID string `json:"channel_user_id"`
+type RevokeAPIKeyParams struct {
+	RevokedAt sql.NullString `json:"revoked_at"`
+	ID        string         `json:"id"`
 }
 
-func (q *Queries) GetUserByChannel(ctx context.Context, arg GetUserByChannelParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByChannel, arg.Channel, arg.ChannelUserID)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Channel,
-		&i.ChannelUserID,
-		&i.CreatedAt,
-	)
-	return i, err
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeAPIKey, arg.RevokedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT id, channel, channel_user_id, created_at FROM users
-WHERE id = ? LIMIT 1
+const saveIdempotencyKey = `-- name: SaveIdempotencyKey :execrows
+INSERT INTO idempotency_keys (key, request_hash, status_code, content_type, body, expires_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET request_hash = excluded.request_hash, status_code = excluded.status_code,
+    content_type = excluded.content_type, body = excluded.body, expires_at = excluded.expires_at, created_at = excluded.created_at
+WHERE idempotency_keys.expires_at <= excluded.created_at
+    OR (idempotency_keys.status_code = 0 AND idempotency_keys.request_hash = excluded.request_hash)
 `
 
-func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Channel,
-		&i.ChannelUserID,
-		&i.CreatedAt,
+type SaveIdempotencyKeyParams struct {
+	Key         string `json:"key"`
+	RequestHash string `json:"request_hash"`
+	StatusCode  int64  `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+	ExpiresAt   string `json:"expires_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func (q *Queries) SaveIdempotencyKey(ctx context.Context, arg SaveIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, saveIdempotencyKey,
+		arg.Key,
+		arg.RequestHash,
+		arg.StatusCode,
+		arg.ContentType,
+		arg.Body,
+		arg.ExpiresAt,
+		arg.CreatedAt,
 	)
-	return i, err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const listSchedules = `-- name: ListSchedules :many
-SELECT id, skill, cron_expression, input, enabled, created_at FROM schedules
-ORDER BY created_at DESC
+const searchMessages = `-- name: SearchMessages :many
+SELECT m.id, m.session_id, m.role, m.content, m.created_at FROM messages m
+JOIN messages_fts ON messages_fts.docid = m.rowid
+WHERE messages_fts MATCH ?1
+  AND (?2 = '' OR m.session_id IN (SELECT s.id FROM sessions s WHERE s.user_id = ?2))
+  AND (?3 = '' OR m.session_id = ?3)
+  AND (?4 = '' OR m.session_id IN (SELECT s.id FROM sessions s WHERE s.workspace_id = ?4))
+  AND (?5 = '' OR m.created_at >= ?5)
+  AND (?6 = '' OR m.created_at < ?6)
+  AND (?7 = '' OR m.created_at < ?7 OR (m.created_at = ?7 AND m.rowid < (SELECT c.rowid FROM messages c WHERE c.id = ?8)))
+ORDER BY m.created_at DESC, m.rowid DESC
+LIMIT ?9 OFFSET ?10
 `
 
-func (q *Queries) ListSchedules(ctx context.Context) ([]Schedule, error) {
-	rows, err := q.db.QueryContext(ctx, listSchedules)
+type SearchMessagesParams struct {
+	Query          string `json:"query"`
+	UserID         string `json:"user_id"`
+	SessionID      string `json:"session_id"`
+	WorkspaceID    string `json:"workspace_id"`
+	Since          string `json:"since"`
+	Until          string `json:"until"`
+	AfterCreatedAt string `json:"after_created_at"`
+	AfterID        string `json:"after_id"`
+	Limit          int64  `json:"limit"`
+	Offset         int64  `json:"offset"`
+}
+
+func (q *Queries) SearchMessages(ctx context.Context, arg SearchMessagesParams) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, searchMessages,
+		arg.Query,
+		arg.UserID,
+		arg.SessionID,
+		arg.WorkspaceID,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Schedule
+	var items []Message
 	for rows.Next() {
-		var i Schedule
+		var i Message
 		if err := rows.Scan(
 			&i.ID,
-			&i.Skill,
-			&i.CronExpression,
-			&i.Input,
-			&i.Enabled,
+			&i.SessionID,
+			&i.Role,
+			&i.Content,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -807,28 +3719,54 @@ func (q *Queries) ListSchedules(ctx context.Context) ([]Schedule, error) {
 	return items, nil
 }
 
-const listSkills = `-- name: ListSkills :many
-SELECT id, name, version, location, permissions, metadata, created_at FROM skills
-ORDER BY created_at DESC
+const sumLLMUsage = `-- name: SumLLMUsage :many
+SELECT month, workspace_id, user_id, provider, model, COUNT(*) AS requests,
+    CAST(SUM(input_tokens) AS INTEGER) AS input_tokens, CAST(SUM(output_tokens) AS INTEGER) AS output_tokens,
+    CAST(SUM(total_tokens) AS INTEGER) AS total_tokens, CAST(SUM(cost) AS REAL) AS cost
+FROM llm_usage
+WHERE month >= ?1 AND month <= ?2
+GROUP BY month, workspace_id, user_id, provider, model
+ORDER BY month, workspace_id, user_id, provider, model
 `
 
-func (q *Queries) ListSkills(ctx context.Context) ([]Skill, error) {
-	rows, err := q.db.QueryContext(ctx, listSkills)
+type SumLLMUsageParams struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+type SumLLMUsageRow struct {
+	Month        string  `json:"month"`
+	WorkspaceID  string  `json:"workspace_id"`
+	UserID       string  `json:"user_id"`
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	Requests     int64   `json:"requests"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	Cost         float64 `json:"cost"`
+}
+
+func (q *Queries) SumLLMUsage(ctx context.Context, arg SumLLMUsageParams) ([]SumLLMUsageRow, error) {
+	rows, err := q.db.QueryContext(ctx, sumLLMUsage, arg.Since, arg.Until)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Skill
+	var items []SumLLMUsageRow
 	for rows.Next() {
-		var i Skill
+		var i SumLLMUsageRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Version,
-			&i.Location,
-			&i.Permissions,
-			&i.Metadata,
-			&i.CreatedAt,
+			&i.Month,
+			&i.WorkspaceID,
+			&i.UserID,
+			&i.Provider,
+			&i.Model,
+			&i.Requests,
+			&i.InputTokens,
+			&i.OutputTokens,
+			&i.TotalTokens,
+			&i.Cost,
 		); err != nil {
 			return nil, err
 		}
@@ -843,51 +3781,165 @@ func (q *Queries) ListSkills(ctx context.Context) ([]Skill, error) {
 	return items, nil
 }
 
-const listUsers = `-- name: ListUsers :many
-SELECT id, channel, channel_user_id, created_at FROM users
-ORDER BY created_at DESC
+const updateDeadLetter = `-- name: UpdateDeadLetter :one
+UPDATE dead_letters
+SET attempts = ?, error = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, subscription, event_type, payload, occurred_at, attempts, error, created_at, updated_at
 `
 
-func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listUsers)
+type UpdateDeadLetterParams struct {
+	Attempts  int64  `json:"attempts"`
+	Error     string `json:"error"`
+	UpdatedAt string `json:"updated_at"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) UpdateDeadLetter(ctx context.Context, arg UpdateDeadLetterParams) (DeadLetter, error) {
+	row := q.db.QueryRowContext(ctx, updateDeadLetter,
+		arg.Attempts,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i DeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Subscription,
+		&i.EventType,
+		&i.Payload,
+		&i.OccurredAt,
+		&i.Attempts,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateJob = `-- name: UpdateJob :execrows
+UPDATE jobs
+SET status = ?1, error = ?2, run_at = ?3, locked_by = ?4, locked_until = ?5, updated_at = ?6, finished_at = ?7
+WHERE id = ?8 AND status = 'running' AND locked_by = ?9
+`
+
+type UpdateJobParams struct {
+	Status      string         `json:"status"`
+	Error       string         `json:"error"`
+	RunAt       string         `json:"run_at"`
+	LockedBy    string         `json:"locked_by"`
+	LockedUntil sql.NullString `json:"locked_until"`
+	UpdatedAt   string         `json:"updated_at"`
+	FinishedAt  sql.NullString `json:"finished_at"`
+	ID          string         `json:"id"`
+	Owner       string         `json:"owner"`
+}
+
+func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateJob,
+		arg.Status,
+		arg.Error,
+		arg.RunAt,
+		arg.LockedBy,
+		arg.LockedUntil,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.ID,
+		arg.Owner,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []User
-	for rows.Next() {
-		var i User
-		if err := rows.Scan(
-			&i.ID,
-			&i.Channel,
-			&i.ChannelUserID,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected()
+}
+
+const updateMessageDeadLetter = `-- name: UpdateMessageDeadLetter :one
+UPDATE message_dead_letters
+SET attempts = ?, error = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, connector, user_id, channel_id, content, metadata, attempts, error, created_at, updated_at
+`
+
+type UpdateMessageDeadLetterParams struct {
+	Attempts  int64  `json:"attempts"`
+	Error     string `json:"error"`
+	UpdatedAt string `json:"updated_at"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) UpdateMessageDeadLetter(ctx context.Context, arg UpdateMessageDeadLetterParams) (MessageDeadLetter, error) {
+	row := q.db.QueryRowContext(ctx, updateMessageDeadLetter,
+		arg.Attempts,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i MessageDeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Connector,
+		&i.UserID,
+		&i.ChannelID,
+		&i.Content,
+		&i.Metadata,
+		&i.Attempts,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateMessageJob = `-- name: UpdateMessageJob :exec
+UPDATE message_jobs
+SET status = ?, message_id = ?, reply = ?, error = ?, updated_at = ?, finished_at = ?
+WHERE id = ?
+`
+
+type UpdateMessageJobParams struct {
+	Status     string         `json:"status"`
+	MessageID  string         `json:"message_id"`
+	Reply      string         `json:"reply"`
+	Error      string         `json:"error"`
+	UpdatedAt  string         `json:"updated_at"`
+	FinishedAt sql.NullString `json:"finished_at"`
+	ID         string         `json:"id"`
+}
+
+func (q *Queries) UpdateMessageJob(ctx context.Context, arg UpdateMessageJobParams) error {
+	_, err := q.db.ExecContext(ctx, updateMessageJob,
+		arg.Status,
+		arg.MessageID,
+		arg.Reply,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.ID,
+	)
+	return err
 }
 
 const updateSchedule = `-- name: UpdateSchedule :one
 UPDATE schedules
-SET cron_expression = ?, input = ?, enabled = ?
+SET cron_expression = ?, input = ?, enabled = ?, timezone = ?, jitter_seconds = ?, schedule_type = ?, run_at = ?, missed_run_policy = ?, target_connector = ?, target_user_id = ?, prompt = ?, session_id = ?
 WHERE id = ?
-RETURNING id, skill, cron_expression, input, enabled, created_at
+RETURNING id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id, prompt, session_id
 `
 
 type UpdateScheduleParams struct {
-	CronExpression string `json:"cron_expression"`
-	Input          string `json:"input"`
-	Enabled        int64  `json:"enabled"`
-	ID             string `json:"id"`
+	CronExpression  valueobject.CronExpression `json:"cron_expression"`
+	Input           string                     `json:"input"`
+	Enabled         int64                      `json:"enabled"`
+	Timezone        string                     `json:"timezone"`
+	JitterSeconds   int64                      `json:"jitter_seconds"`
+	ScheduleType    string                     `json:"schedule_type"`
+	RunAt           sql.NullString             `json:"run_at"`
+	MissedRunPolicy string                     `json:"missed_run_policy"`
+	TargetConnector string                     `json:"target_connector"`
+	TargetUserID    string                     `json:"target_user_id"`
+	Prompt          string                     `json:"prompt"`
+	SessionID       string                     `json:"session_id"`
+	ID              string                     `json:"id"`
 }
 
 func (q *Queries) UpdateSchedule(ctx context.Context, arg UpdateScheduleParams) (Schedule, error) {
@@ -895,6 +3947,15 @@ func (q *Queries) UpdateSchedule(ctx context.Context, arg UpdateScheduleParams)
 		arg.CronExpression,
 		arg.Input,
 		arg.Enabled,
+		arg.Timezone,
+		arg.JitterSeconds,
+		arg.ScheduleType,
+		arg.RunAt,
+		arg.MissedRunPolicy,
+		arg.TargetConnector,
+		arg.TargetUserID,
+		arg.Prompt,
+		arg.SessionID,
 		arg.ID,
 	)
 	var i Schedule
@@ -905,47 +3966,105 @@ func (q *Queries) UpdateSchedule(ctx context.Context, arg UpdateScheduleParams)
 		&i.Input,
 		&i.Enabled,
 		&i.CreatedAt,
+		&i.Timezone,
+		&i.JitterSeconds,
+		&i.ScheduleType,
+		&i.RunAt,
+		&i.MissedRunPolicy,
+		&i.TargetConnector,
+		&i.TargetUserID,
+		&i.Prompt,
+		&i.SessionID,
+	)
+	return i, err
+}
+
+const updateScheduleRun = `-- name: UpdateScheduleRun :one
+UPDATE schedule_runs
+SET status = ?, finished_at = ?, output = ?, error = ?
+WHERE id = ?
+RETURNING id, schedule_id, status, scheduled_at, started_at, finished_at, output, error
+`
+
+type UpdateScheduleRunParams struct {
+	Status     valueobject.TaskStatus `json:"status"`
+	FinishedAt sql.NullString         `json:"finished_at"`
+	Output     sql.NullString         `json:"output"`
+	Error      sql.NullString         `json:"error"`
+	ID         string                 `json:"id"`
+}
+
+func (q *Queries) UpdateScheduleRun(ctx context.Context, arg UpdateScheduleRunParams) (ScheduleRun, error) {
+	row := q.db.QueryRowContext(ctx, updateScheduleRun,
+		arg.Status,
+		arg.FinishedAt,
+		arg.Output,
+		arg.Error,
+		arg.ID,
+	)
+	var i ScheduleRun
+	err := row.Scan(
+		&i.ID,
+		&i.ScheduleID,
+		&i.Status,
+		&i.ScheduledAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Output,
+		&i.Error,
 	)
 	return i, err
 }
 
 const updateSession = `-- name: UpdateSession :one
 UPDATE sessions
-SET updated_at = ?
+SET updated_at = ?, pinned = ?, title = ?
 WHERE id = ?
-RETURNING id, user_id, created_at, updated_at
+RETURNING id, user_id, created_at, updated_at, pinned, workspace_id, title
 `
 
 type UpdateSessionParams struct {
 	UpdatedAt string `json:"updated_at"`
+	Pinned    int64  `json:"pinned"`
+	Title     string `json:"title"`
 	ID        string `json:"id"`
 }
 
 func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error) {
-	row := q.db.QueryRowContext(ctx, updateSession, arg.UpdatedAt, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateSession,
+		arg.UpdatedAt,
+		arg.Pinned,
+		arg.Title,
+		arg.ID,
+	)
 	var i Session
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Pinned,
+		&i.WorkspaceID,
+		&i.Title,
 	)
 	return i, err
 }
 
 const updateSkill = `-- name: UpdateSkill :one
 UPDATE skills
-SET version = ?, location = ?, permissions = ?, metadata = ?
+SET version = ?, location = ?, permissions = ?, metadata = ?, disabled = ?, workspace_id = ?
 WHERE id = ?
-RETURNING id, name, version, location, permissions, metadata, created_at
+RETURNING id, name, version, location, permissions, metadata, created_at, disabled, workspace_id
 `
 
 type UpdateSkillParams struct {
-	Version     string `json:"version"`
-	Location    string `json:"location"`
-	Permissions string `json:"permissions"`
-	Metadata    string `json:"metadata"`
-	ID          string `json:"id"`
+	Version     valueobject.Version `json:"version"`
+	Location    string              `json:"location"`
+	Permissions string              `json:"permissions"`
+	Metadata    string              `json:"metadata"`
+	Disabled    int64               `json:"disabled"`
+	WorkspaceID string              `json:"workspace_id"`
+	ID          string              `json:"id"`
 }
 
 func (q *Queries) UpdateSkill(ctx context.Context, arg UpdateSkillParams) (Skill, error) {
@@ -954,6 +4073,8 @@ func (q *Queries) UpdateSkill(ctx context.Context, arg UpdateSkillParams) (Skill
 		arg.Location,
 		arg.Permissions,
 		arg.Metadata,
+		arg.Disabled,
+		arg.WorkspaceID,
 		arg.ID,
 	)
 	var i Skill
@@ -965,23 +4086,27 @@ func (q *Queries) UpdateSkill(ctx context.Context, arg UpdateSkillParams) (Skill
 		&i.Permissions,
 		&i.Metadata,
 		&i.CreatedAt,
+		&i.Disabled,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
 
 const updateTask = `-- name: UpdateTask :one
 UPDATE tasks
-SET output = ?, status = ?, error = ?, updated_at = ?
+SET output = ?, status = ?, error = ?, updated_at = ?, started_at = ?, finished_at = ?
 WHERE id = ?
-RETURNING id, session_id, skill, input, output, status, error, created_at, updated_at
+RETURNING id, session_id, skill, input, output, status, error, created_at, updated_at, started_at, finished_at
 `
 
 type UpdateTaskParams struct {
-	Output    sql.NullString `json:"output"`
-	Status    string         `json:"status"`
-	Error     sql.NullString `json:"error"`
-	UpdatedAt string         `json:"updated_at"`
-	ID        string         `json:"id"`
+	Output     sql.NullString         `json:"output"`
+	Status     valueobject.TaskStatus `json:"status"`
+	Error      sql.NullString         `json:"error"`
+	UpdatedAt  string                 `json:"updated_at"`
+	StartedAt  sql.NullString         `json:"started_at"`
+	FinishedAt sql.NullString         `json:"finished_at"`
+	ID         string                 `json:"id"`
 }
 
 func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
@@ -990,6 +4115,8 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		arg.Status,
 		arg.Error,
 		arg.UpdatedAt,
+		arg.StartedAt,
+		arg.FinishedAt,
 		arg.ID,
 	)
 	var i Task
@@ -1003,6 +4130,202 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const updateWebhookDelivery = `-- name: UpdateWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = ?, attempts = ?, response_status = ?, error = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, endpoint, url, event_type, payload, status, attempts, response_status, error, created_at, updated_at
+`
+
+type UpdateWebhookDeliveryParams struct {
+	Status         string `json:"status"`
+	Attempts       int64  `json:"attempts"`
+	ResponseStatus int64  `json:"response_status"`
+	Error          string `json:"error"`
+	UpdatedAt      string `json:"updated_at"`
+	ID             string `json:"id"`
+}
+
+func (q *Queries) UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhookDelivery,
+		arg.Status,
+		arg.Attempts,
+		arg.ResponseStatus,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.Endpoint,
+		&i.URL,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateWorkspace = `-- name: UpdateWorkspace :one
+UPDATE workspaces
+SET name = ?, max_tokens = ?
+WHERE id = ?
+RETURNING id, name, max_tokens, created_at
+`
+
+type UpdateWorkspaceParams struct {
+	Name      string `json:"name"`
+	MaxTokens int64  `json:"max_tokens"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspace, arg.Name, arg.MaxTokens, arg.ID)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.MaxTokens,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertMessageFeedback = `-- name: UpsertMessageFeedback :one
+INSERT INTO message_feedback (id, message_id, session_id, user_id, rating, comment, day, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (message_id) DO UPDATE
+SET rating = excluded.rating, comment = excluded.comment, day = excluded.day, updated_at = excluded.updated_at
+RETURNING id, message_id, session_id, user_id, rating, comment, day, created_at, updated_at
+`
+
+type UpsertMessageFeedbackParams struct {
+	ID        string `json:"id"`
+	MessageID string `json:"message_id"`
+	SessionID string `json:"session_id"`
+	UserID    string `json:"user_id"`
+	Rating    string `json:"rating"`
+	Comment   string `json:"comment"`
+	Day       string `json:"day"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func (q *Queries) UpsertMessageFeedback(ctx context.Context, arg UpsertMessageFeedbackParams) (MessageFeedback, error) {
+	row := q.db.QueryRowContext(ctx, upsertMessageFeedback,
+		arg.ID,
+		arg.MessageID,
+		arg.SessionID,
+		arg.UserID,
+		arg.Rating,
+		arg.Comment,
+		arg.Day,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i MessageFeedback
+	err := row.Scan(
+		&i.ID,
+		&i.MessageID,
+		&i.SessionID,
+		&i.UserID,
+		&i.Rating,
+		&i.Comment,
+		&i.Day,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertSessionAttribute = `-- name: UpsertSessionAttribute :one
+INSERT INTO session_attributes (session_id, key, value, expires_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (session_id, key) DO UPDATE
+SET value = excluded.value, expires_at = excluded.expires_at, updated_at = excluded.updated_at
+RETURNING session_id, key, value, expires_at, created_at, updated_at
+`
+
+type UpsertSessionAttributeParams struct {
+	SessionID string         `json:"session_id"`
+	Key       string         `json:"key"`
+	Value     string         `json:"value"`
+	ExpiresAt sql.NullString `json:"expires_at"`
+	CreatedAt string         `json:"created_at"`
+	UpdatedAt string         `json:"updated_at"`
+}
+
+func (q *Queries) UpsertSessionAttribute(ctx context.Context, arg UpsertSessionAttributeParams) (SessionAttribute, error) {
+	row := q.db.QueryRowContext(ctx, upsertSessionAttribute,
+		arg.SessionID,
+		arg.Key,
+		arg.Value,
+		arg.ExpiresAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i SessionAttribute
+	err := row.Scan(
+		&i.SessionID,
+		&i.Key,
+		&i.Value,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserPreferences = `-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (user_id, language, model, timezone, verbosity, updated_at, instructions)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET language = excluded.language, model = excluded.model, timezone = excluded.timezone,
+    verbosity = excluded.verbosity, updated_at = excluded.updated_at, instructions = excluded.instructions
+RETURNING user_id, language, model, timezone, verbosity, updated_at, instructions
+`
+
+type UpsertUserPreferencesParams struct {
+	UserID       string `json:"user_id"`
+	Language     string `json:"language"`
+	Model        string `json:"model"`
+	Timezone     string `json:"timezone"`
+	Verbosity    string `json:"verbosity"`
+	UpdatedAt    string `json:"updated_at"`
+	Instructions string `json:"instructions"`
+}
+
+func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserPreferences,
+		arg.UserID,
+		arg.Language,
+		arg.Model,
+		arg.Timezone,
+		arg.Verbosity,
+		arg.UpdatedAt,
+		arg.Instructions,
+	)
+	var i UserPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Language,
+		&i.Model,
+		&i.Timezone,
+		&i.Verbosity,
+		&i.UpdatedAt,
+		&i.Instructions,
 	)
 	return i, err
 }
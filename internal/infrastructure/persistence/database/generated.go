@@ -6,29 +6,118 @@ import (
 
 // Re-export generated types
 type (
-	Log      = gendb.Log
-	Message  = gendb.Message
-	Schedule = gendb.Schedule
-	Session  = gendb.Session
-	Skill    = gendb.Skill
-	Task     = gendb.Task
-	User     = gendb.User
+	AnalyticsActiveUser = gendb.AnalyticsActiveUser
+	AnalyticsCounter    = gendb.AnalyticsCounter
+	ApiKey              = gendb.ApiKey
+	CrashReport         = gendb.CrashReport
+	DeadLetter          = gendb.DeadLetter
+	DocumentChunk       = gendb.DocumentChunk
+	Event               = gendb.Event
+	IdempotencyKey      = gendb.IdempotencyKey
+	Job                 = gendb.Job
+	Lease               = gendb.Lease
+	LlmUsage            = gendb.LlmUsage
+	Log                 = gendb.Log
+	Message             = gendb.Message
+	MessageDeadLetter   = gendb.MessageDeadLetter
+	MessageFeedback     = gendb.MessageFeedback
+	MessageJob          = gendb.MessageJob
+	ProcessedMessage    = gendb.ProcessedMessage
+	Schedule            = gendb.Schedule
+	ScheduleRun         = gendb.ScheduleRun
+	Session             = gendb.Session
+	SessionAttribute    = gendb.SessionAttribute
+	Skill               = gendb.Skill
+	SpilledMessage      = gendb.SpilledMessage
+	Task                = gendb.Task
+	TaskAttempt         = gendb.TaskAttempt
+	User                = gendb.User
+	UserDataErasure     = gendb.UserDataErasure
+	UserPreference      = gendb.UserPreference
+	WebhookDelivery     = gendb.WebhookDelivery
+	Workspace           = gendb.Workspace
 
-	CreateLogParams          = gendb.CreateLogParams
-	CreateMessageParams      = gendb.CreateMessageParams
-	CreateScheduleParams     = gendb.CreateScheduleParams
-	CreateSessionParams      = gendb.CreateSessionParams
-	CreateSkillParams        = gendb.CreateSkillParams
-	CreateTaskParams         = gendb.CreateTaskParams
-	CreateUserParams         = gendb.CreateUserParams
-	GetLogsByDateRangeParams = gendb.GetLogsByDateRangeParams
-	GetLogsByLevelParams     = gendb.GetLogsByLevelParams
-	GetLogsBySourceParams    = gendb.GetLogsBySourceParams
-	GetUserByChannelParams   = gendb.GetUserByChannelParams
-	UpdateScheduleParams     = gendb.UpdateScheduleParams
-	UpdateSessionParams      = gendb.UpdateSessionParams
-	UpdateSkillParams        = gendb.UpdateSkillParams
-	UpdateTaskParams         = gendb.UpdateTaskParams
+	AcquireLeaseParams                   = gendb.AcquireLeaseParams
+	AddAnalyticsActiveUserParams         = gendb.AddAnalyticsActiveUserParams
+	ClaimJobParams                       = gendb.ClaimJobParams
+	CountAnalyticsActiveUsersParams      = gendb.CountAnalyticsActiveUsersParams
+	CountAnalyticsActiveUsersRow         = gendb.CountAnalyticsActiveUsersRow
+	CountMessageFeedbackParams           = gendb.CountMessageFeedbackParams
+	CountMessageFeedbackRow              = gendb.CountMessageFeedbackRow
+	CreateAPIKeyParams                   = gendb.CreateAPIKeyParams
+	CreateCrashReportParams              = gendb.CreateCrashReportParams
+	CreateDeadLetterParams               = gendb.CreateDeadLetterParams
+	CreateDocumentChunkParams            = gendb.CreateDocumentChunkParams
+	CreateEventParams                    = gendb.CreateEventParams
+	CreateJobParams                      = gendb.CreateJobParams
+	CreateLLMUsageParams                 = gendb.CreateLLMUsageParams
+	CreateLogParams                      = gendb.CreateLogParams
+	CreateMessageDeadLetterParams        = gendb.CreateMessageDeadLetterParams
+	CreateMessageJobParams               = gendb.CreateMessageJobParams
+	CreateMessageParams                  = gendb.CreateMessageParams
+	CreateScheduleParams                 = gendb.CreateScheduleParams
+	CreateScheduleRunParams              = gendb.CreateScheduleRunParams
+	CreateSessionParams                  = gendb.CreateSessionParams
+	CreateSkillParams                    = gendb.CreateSkillParams
+	CreateSpilledMessageParams           = gendb.CreateSpilledMessageParams
+	CreateTaskParams                     = gendb.CreateTaskParams
+	CreateTaskAttemptParams              = gendb.CreateTaskAttemptParams
+	CreateUserDataErasureParams          = gendb.CreateUserDataErasureParams
+	CreateUserParams                     = gendb.CreateUserParams
+	CreateWebhookDeliveryParams          = gendb.CreateWebhookDeliveryParams
+	CreateWorkspaceParams                = gendb.CreateWorkspaceParams
+	DeleteEventsBySubjectParams          = gendb.DeleteEventsBySubjectParams
+	DeleteMessageDeadLettersByUserParams = gendb.DeleteMessageDeadLettersByUserParams
+	DeleteSessionAttributeParams         = gendb.DeleteSessionAttributeParams
+	FailUnfinishedMessageJobsParams      = gendb.FailUnfinishedMessageJobsParams
+	GetIdempotencyKeyParams              = gendb.GetIdempotencyKeyParams
+	GetLogsByDateRangeParams             = gendb.GetLogsByDateRangeParams
+	GetLogsByLevelParams                 = gendb.GetLogsByLevelParams
+	GetLogsBySourceParams                = gendb.GetLogsBySourceParams
+	GetScheduleRunsByScheduleIDParams    = gendb.GetScheduleRunsByScheduleIDParams
+	GetSessionAttributeParams            = gendb.GetSessionAttributeParams
+	GetUserByChannelParams               = gendb.GetUserByChannelParams
+	IncrementAnalyticsCounterParams      = gendb.IncrementAnalyticsCounterParams
+	ListAnalyticsCountersParams          = gendb.ListAnalyticsCountersParams
+	ListCrashReportsParams               = gendb.ListCrashReportsParams
+	ListDeadLettersParams                = gendb.ListDeadLettersParams
+	ListEventsParams                     = gendb.ListEventsParams
+	ListEventsBySubjectParams            = gendb.ListEventsBySubjectParams
+	ListLogsParams                       = gendb.ListLogsParams
+	ListLogsOlderThanParams              = gendb.ListLogsOlderThanParams
+	ListMessageDeadLettersParams         = gendb.ListMessageDeadLettersParams
+	ListMessagesBySessionIDParams        = gendb.ListMessagesBySessionIDParams
+	ListMessagesOlderThanParams          = gendb.ListMessagesOlderThanParams
+	ListSessionAttributesParams          = gendb.ListSessionAttributesParams
+	ListSessionsParams                   = gendb.ListSessionsParams
+	ListSessionsByUserIDParams           = gendb.ListSessionsByUserIDParams
+	ListSpilledMessagesParams            = gendb.ListSpilledMessagesParams
+	ListTasksBySessionIDParams           = gendb.ListTasksBySessionIDParams
+	ListTasksOlderThanParams             = gendb.ListTasksOlderThanParams
+	ListUserDataErasuresParams           = gendb.ListUserDataErasuresParams
+	ListWebhookDeliveriesParams          = gendb.ListWebhookDeliveriesParams
+	RecordProcessedMessageParams         = gendb.RecordProcessedMessageParams
+	ReleaseLeaseParams                   = gendb.ReleaseLeaseParams
+	ReserveIdempotencyKeyParams          = gendb.ReserveIdempotencyKeyParams
+	RevokeAPIKeyParams                   = gendb.RevokeAPIKeyParams
+	SaveIdempotencyKeyParams             = gendb.SaveIdempotencyKeyParams
+	SearchMessagesParams                 = gendb.SearchMessagesParams
+	SumLLMUsageParams                    = gendb.SumLLMUsageParams
+	SumLLMUsageRow                       = gendb.SumLLMUsageRow
+	UpdateDeadLetterParams               = gendb.UpdateDeadLetterParams
+	UpdateMessageDeadLetterParams        = gendb.UpdateMessageDeadLetterParams
+	UpdateJobParams                      = gendb.UpdateJobParams
+	UpdateMessageJobParams               = gendb.UpdateMessageJobParams
+	UpdateScheduleParams                 = gendb.UpdateScheduleParams
+	UpdateScheduleRunParams              = gendb.UpdateScheduleRunParams
+	UpdateSessionParams                  = gendb.UpdateSessionParams
+	UpdateSkillParams                    = gendb.UpdateSkillParams
+	UpdateTaskParams                     = gendb.UpdateTaskParams
+	UpdateWebhookDeliveryParams          = gendb.UpdateWebhookDeliveryParams
+	UpdateWorkspaceParams                = gendb.UpdateWorkspaceParams
+	UpsertMessageFeedbackParams          = gendb.UpsertMessageFeedbackParams
+	UpsertSessionAttributeParams         = gendb.UpsertSessionAttributeParams
+	UpsertUserPreferencesParams          = gendb.UpsertUserPreferencesParams
 
 	DBTX    = gendb.DBTX
 	Querier = gendb.Querier
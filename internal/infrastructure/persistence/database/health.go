@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// Compile-time check that DB implements HealthChecker
+var _ HealthChecker = (*DB)(nil)
+
+// DBMetrics holds metrics for database connection health and pool usage
+type DBMetrics struct {
+	registry *metrics.MetricsRegistry
+
+	Up                 *metrics.Gauge
+	OpenConnections    *metrics.Gauge
+	InUseConnections   *metrics.Gauge
+	IdleConnections    *metrics.Gauge
+	MaxOpenConnections *metrics.Gauge
+	WaitCount          *metrics.Gauge
+	PingsFailed        *metrics.Counter
+	PingDuration       *metrics.Histogram
+
+	// Queries holds per-query metrics of the Queries layer
+	Queries *QueryMetrics
+}
+
+// NewDBMetrics creates a new DBMetrics instance
+func NewDBMetrics() *DBMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &DBMetrics{
+		registry: registry,
+
+		Up:                 registry.GetGauge("database_up"),
+		OpenConnections:    registry.GetGauge("database_open_connections"),
+		InUseConnections:   registry.GetGauge("database_in_use_connections"),
+		IdleConnections:    registry.GetGauge("database_idle_connections"),
+		MaxOpenConnections: registry.GetGauge("database_max_open_connections"),
+		WaitCount:          registry.GetGauge("database_wait_count"),
+		PingsFailed:        registry.GetCounter("database_pings_failed_total"),
+		PingDuration:       registry.GetHistogram("database_ping_duration_seconds", metrics.DefaultBuckets()),
+		Queries:            NewQueryMetrics(),
+	}
+}
+
+// Registry returns the registry holding the database metrics
+func (m *DBMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// HealthStatus is the result of a database health probe
+type HealthStatus struct {
+	Healthy bool
+	Latency time.Duration
+	Error   string
+	Stats   sql.DBStats
+}
+
+// Health pings the database and reports the pool statistics.
+// The result is also recorded in the database metrics.
+func (d *DB) Health(ctx context.Context) HealthStatus {
+	start := time.Now()
+	err := d.db.PingContext(ctx)
+	status := HealthStatus{
+		Healthy: err == nil,
+		Latency: time.Since(start),
+		Stats:   d.db.Stats(),
+	}
+	if err != nil {
+		status.Error = err.Error()
+		d.logger.Warn("Database health check failed", "type", d.config.Type, "error", err)
+	}
+
+	d.recordHealth(status)
+	return status
+}
+
+// Metrics returns the database metrics
+func (d *DB) Metrics() *DBMetrics {
+	return d.metrics
+}
+
+// recordHealth updates the database metrics with the result of a health probe
+func (d *DB) recordHealth(status HealthStatus) {
+	if d.metrics == nil {
+		return
+	}
+
+	if status.Healthy {
+		d.metrics.Up.Set(1)
+	} else {
+		d.metrics.Up.Set(0)
+		d.metrics.PingsFailed.Inc()
+	}
+	d.metrics.PingDuration.Observe(status.Latency.Seconds())
+	d.metrics.OpenConnections.Set(int64(status.Stats.OpenConnections))
+	d.metrics.InUseConnections.Set(int64(status.Stats.InUse))
+	d.metrics.IdleConnections.Set(int64(status.Stats.Idle))
+	d.metrics.MaxOpenConnections.Set(int64(status.Stats.MaxOpenConnections))
+	d.metrics.WaitCount.Set(status.Stats.WaitCount)
+}
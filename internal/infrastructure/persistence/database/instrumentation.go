@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
+)
+
+// unnamedQuery is the metric label for statements without a sqlc name comment
+const unnamedQuery = "unnamed"
+
+// QueryMetrics holds per-query latency and error metrics for the Queries layer
+type QueryMetrics struct {
+	registry    *metrics.MetricsRegistry
+	SlowQueries *metrics.Counter
+}
+
+// NewQueryMetrics creates a new QueryMetrics instance
+func NewQueryMetrics() *QueryMetrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &QueryMetrics{
+		registry:    registry,
+		SlowQueries: registry.GetCounter("database_slow_queries_total"),
+	}
+}
+
+// Registry returns the registry holding the query metrics
+func (m *QueryMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Duration returns the duration histogram of a query
+func (m *QueryMetrics) Duration(query string) *metrics.Histogram {
+	return m.registry.GetHistogram(fmt.Sprintf("database_query_duration_seconds{query=%q}", query), metrics.DefaultBuckets())
+}
+
+// Errors returns the error counter of a query
+func (m *QueryMetrics) Errors(query string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("database_query_errors_total{query=%q}", query))
+}
+
+// Snapshot returns a snapshot of all query metrics
+func (m *QueryMetrics) Snapshot() map[string]any {
+	return m.registry.Snapshot()
+}
+
+// instrumentedDB wraps a DBTX and records the duration of every statement by its sqlc query name.
+// Statements slower than the threshold are logged; a threshold of 0 disables slow query logging.
+// For QueryContext and QueryRowContext the time until the first result is measured, not row iteration.
+// Statements run within a trace get a span named after the query.
+type instrumentedDB struct {
+	db        DBTX
+	metrics   *QueryMetrics
+	threshold time.Duration
+	logger    logging.Logger
+	tracer    *tracing.Tracer
+}
+
+// Compile-time check that instrumentedDB implements DBTX
+var _ DBTX = (*instrumentedDB)(nil)
+
+// newInstrumentedDB wraps db with query metrics, slow query logging and tracing; a nil tracer
+// disables tracing
+func newInstrumentedDB(db DBTX, metrics *QueryMetrics, threshold time.Duration, logger logging.Logger, tracer *tracing.Tracer) *instrumentedDB {
+	return &instrumentedDB{
+		db:        db,
+		metrics:   metrics,
+		threshold: threshold,
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// ExecContext implements DBTX
+func (i *instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	ctx, span := i.startSpan(ctx, query)
+	result, err := i.db.ExecContext(ctx, query, args...)
+	i.observe(ctx, span, query, start, err)
+	return result, err
+}
+
+// PrepareContext implements DBTX
+func (i *instrumentedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	start := time.Now()
+	ctx, span := i.startSpan(ctx, query)
+	stmt, err := i.db.PrepareContext(ctx, query)
+	i.observe(ctx, span, query, start, err)
+	return stmt, err
+}
+
+// QueryContext implements DBTX
+func (i *instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	ctx, span := i.startSpan(ctx, query)
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	i.observe(ctx, span, query, start, err)
+	return rows, err
+}
+
+// QueryRowContext implements DBTX
+func (i *instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	ctx, span := i.startSpan(ctx, query)
+	row := i.db.QueryRowContext(ctx, query, args...)
+	i.observe(ctx, span, query, start, row.Err())
+	return row
+}
+
+// startSpan starts the span of a statement if ctx carries a trace
+func (i *instrumentedDB) startSpan(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	name := queryName(query)
+	return i.tracer.StartChild(ctx, "db."+name, tracing.SpanKindClient, "db.operation", name)
+}
+
+// observe records the duration of a statement, ends its span and logs it when it exceeds the
+// threshold
+func (i *instrumentedDB) observe(ctx context.Context, span *tracing.Span, query string, start time.Time, err error) {
+	duration := time.Since(start)
+	name := queryName(query)
+
+	i.metrics.Duration(name).Observe(duration.Seconds())
+	if err != nil && err != sql.ErrNoRows {
+		i.metrics.Errors(name).Inc()
+		span.RecordError(err)
+	}
+	span.End()
+
+	if i.threshold > 0 && duration >= i.threshold {
+		i.metrics.SlowQueries.Inc()
+		i.logger.WarnContext(ctx, "Slow database query", "query", name, "duration", duration, "threshold", i.threshold)
+	}
+}
+
+// queryName extracts the query name from the "-- name: <Name> :<kind>" comment sqlc puts
+// in front of every generated statement
+func queryName(query string) string {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(query), "-- name:")
+	if !ok {
+		return unnamedQuery
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return unnamedQuery
+	}
+	return fields[0]
+}
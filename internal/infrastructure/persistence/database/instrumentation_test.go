@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/config"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"-- name: GetUserByID :one\nSELECT * FROM users WHERE id = ?", "GetUserByID"},
+		{"\n-- name: DeleteLogsOlderThan :execrows\nDELETE FROM logs", "DeleteLogsOlderThan"},
+		{"SELECT 1", unnamedQuery},
+		{"-- name:", unnamedQuery},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, queryName(tt.query), tt.query)
+	}
+}
+
+func TestInstrumentedQueries(t *testing.T) {
+	logger := &TestLogger{}
+	db, err := NewDatabase(&config.DatabaseConfig{
+		Type:               "sqlite",
+		Path:               filepath.Join(t.TempDir(), "queries.db"),
+		SlowQueryThreshold: time.Nanosecond,
+	}, WithLogger(logger))
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	queryMetrics := db.(*DB).Metrics().Queries
+
+	// Without migrations the users table does not exist
+	_, err = db.ListUsers(ctx)
+	require.Error(t, err)
+	assert.Equal(t, int64(1), queryMetrics.Errors("ListUsers").Get())
+
+	require.NoError(t, db.Migrate(ctx))
+
+	_, err = db.ListUsers(ctx)
+	require.NoError(t, err)
+	_, err = db.CountLogsOlderThan(ctx, time.Now().UTC().Format(time.RFC3339))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), queryMetrics.Duration("ListUsers").Count())
+	assert.Equal(t, int64(1), queryMetrics.Errors("ListUsers").Get())
+	assert.Equal(t, int64(1), queryMetrics.Duration("CountLogsOlderThan").Count())
+	assert.Equal(t, int64(0), queryMetrics.Errors("CountLogsOlderThan").Get())
+	assert.Equal(t, int64(3), queryMetrics.SlowQueries.Get())
+	assert.Contains(t, logger.messages, "WARN: Slow database query")
+}
+
+func TestInstrumentedQueries_SlowQueryLoggingDisabled(t *testing.T) {
+	logger := &TestLogger{}
+	db, err := NewDatabase(&config.DatabaseConfig{
+		Type: "sqlite",
+		Path: filepath.Join(t.TempDir(), "queries.db"),
+	}, WithLogger(logger))
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	require.NoError(t, db.Migrate(ctx))
+	_, err = db.ListUsers(ctx)
+	require.NoError(t, err)
+
+	queryMetrics := db.(*DB).Metrics().Queries
+	assert.Equal(t, int64(1), queryMetrics.Duration("ListUsers").Count())
+	assert.Equal(t, int64(0), queryMetrics.SlowQueries.Get())
+	assert.NotContains(t, logger.messages, "WARN: Slow database query")
+}
+
+// spanRecorder is a tracing.Exporter keeping the exported spans
+type spanRecorder struct {
+	spans []tracing.SpanData
+}
+
+func (e *spanRecorder) ExportSpans(ctx context.Context, spans []tracing.SpanData) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestInstrumentedQueries_Tracing(t *testing.T) {
+	recorder := &spanRecorder{}
+	tracer := tracing.NewTracer(tracing.Config{Exporter: recorder, SampleRatio: 1})
+	db, err := NewDatabase(&config.DatabaseConfig{
+		Type: "sqlite",
+		Path: filepath.Join(t.TempDir(), "queries.db"),
+	}, WithTracer(tracer))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Queries outside a trace get no span
+	ctx := context.Background()
+	require.NoError(t, db.Migrate(ctx))
+	_, err = db.ListUsers(ctx)
+	require.NoError(t, err)
+
+	ctx, span := tracer.Start(ctx, "router.message", tracing.SpanKindConsumer)
+	_, err = db.ListUsers(ctx)
+	require.NoError(t, err)
+	span.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	require.Len(t, recorder.spans, 2)
+	assert.Equal(t, "db.ListUsers", recorder.spans[0].Name)
+	assert.Equal(t, tracing.SpanKindClient, recorder.spans[0].Kind)
+	assert.Equal(t, span.SpanContext().SpanID, recorder.spans[0].Parent)
+}
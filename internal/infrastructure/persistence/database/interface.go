@@ -1,6 +1,9 @@
 package database
 
-import "context"
+import (
+	"context"
+	"database/sql"
+)
 
 // Database is a legacy composite interface for backward compatibility.
 // It combines all repository methods into a single interface.
@@ -11,12 +14,16 @@ import "context"
 // New code should use individual repository interfaces:
 // - UserRepository for user operations
 // - SessionRepository for session operations
+// - SessionAttributeRepository for per-session key-value state
 // - MessageRepository for message operations
 // - TaskRepository for task operations
 // - SkillRepository for skill operations
 // - ScheduleRepository for schedule operations
+// - ScheduleRunRepository for schedule run history operations
 // - LogRepository for log operations
 // - Migration for database migrations
+// - Backup for online backups
+// - HealthChecker for connection health probes
 type Database interface {
 	// Users
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
@@ -29,21 +36,39 @@ type Database interface {
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	GetSessionByID(ctx context.Context, id string) (Session, error)
 	GetSessionsByUserID(ctx context.Context, userID string) ([]Session, error)
+	ListSessions(ctx context.Context, arg ListSessionsParams) ([]Session, error)
+	ListSessionsByUserID(ctx context.Context, arg ListSessionsByUserIDParams) ([]Session, error)
 	UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error)
 	DeleteSession(ctx context.Context, id string) error
 
+	// Session attributes
+	GetSessionAttribute(ctx context.Context, arg GetSessionAttributeParams) (SessionAttribute, error)
+	ListSessionAttributes(ctx context.Context, arg ListSessionAttributesParams) ([]SessionAttribute, error)
+	UpsertSessionAttribute(ctx context.Context, arg UpsertSessionAttributeParams) (SessionAttribute, error)
+	DeleteSessionAttribute(ctx context.Context, arg DeleteSessionAttributeParams) (int64, error)
+	DeleteExpiredSessionAttributes(ctx context.Context, now string) (int64, error)
+
 	// Messages
 	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
 	GetMessageByID(ctx context.Context, id string) (Message, error)
 	GetMessagesBySessionID(ctx context.Context, sessionID string) ([]Message, error)
+	ListMessagesBySessionID(ctx context.Context, arg ListMessagesBySessionIDParams) ([]Message, error)
+	SearchMessages(ctx context.Context, arg SearchMessagesParams) ([]Message, error)
+	CountMessagesOlderThan(ctx context.Context, createdAt string) (int64, error)
+	ListMessagesOlderThan(ctx context.Context, arg ListMessagesOlderThanParams) ([]Message, error)
 	DeleteMessage(ctx context.Context, id string) error
+	DeleteMessagesOlderThan(ctx context.Context, createdAt string) (int64, error)
 
 	// Tasks
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
 	GetTaskByID(ctx context.Context, id string) (Task, error)
 	GetTasksBySessionID(ctx context.Context, sessionID string) ([]Task, error)
+	ListTasksBySessionID(ctx context.Context, arg ListTasksBySessionIDParams) ([]Task, error)
+	CountTasksOlderThan(ctx context.Context, createdAt string) (int64, error)
+	ListTasksOlderThan(ctx context.Context, arg ListTasksOlderThanParams) ([]Task, error)
 	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
 	DeleteTask(ctx context.Context, id string) error
+	DeleteTasksOlderThan(ctx context.Context, createdAt string) (int64, error)
 
 	// Skills
 	CreateSkill(ctx context.Context, arg CreateSkillParams) (Skill, error)
@@ -56,21 +81,155 @@ type Database interface {
 	// Schedules
 	CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error)
 	GetScheduleByID(ctx context.Context, id string) (Schedule, error)
-	GetSchedulesBySkill(ctx context.Context, skill string) ([]Schedule, error)
+	GetSchedulesBySkill(ctx context.Context, skill sql.NullString) ([]Schedule, error)
 	ListSchedules(ctx context.Context) ([]Schedule, error)
 	UpdateSchedule(ctx context.Context, arg UpdateScheduleParams) (Schedule, error)
 	DeleteSchedule(ctx context.Context, id string) error
 
+	// Schedule runs
+	CreateScheduleRun(ctx context.Context, arg CreateScheduleRunParams) (ScheduleRun, error)
+	UpdateScheduleRun(ctx context.Context, arg UpdateScheduleRunParams) (ScheduleRun, error)
+	GetScheduleRunsByScheduleID(ctx context.Context, arg GetScheduleRunsByScheduleIDParams) ([]ScheduleRun, error)
+	GetLatestScheduleRun(ctx context.Context, scheduleID string) (ScheduleRun, error)
+
 	// Logs
 	CreateLog(ctx context.Context, arg CreateLogParams) (Log, error)
 	GetLogByID(ctx context.Context, id string) (Log, error)
 	GetLogsByLevel(ctx context.Context, arg GetLogsByLevelParams) ([]Log, error)
 	GetLogsBySource(ctx context.Context, arg GetLogsBySourceParams) ([]Log, error)
 	GetLogsByDateRange(ctx context.Context, arg GetLogsByDateRangeParams) ([]Log, error)
+	ListLogs(ctx context.Context, arg ListLogsParams) ([]Log, error)
+	CountLogsOlderThan(ctx context.Context, createdAt string) (int64, error)
+	ListLogsOlderThan(ctx context.Context, arg ListLogsOlderThanParams) ([]Log, error)
 	DeleteLog(ctx context.Context, id string) error
-	DeleteLogsOlderThan(ctx context.Context, date string) error
+	DeleteLogsOlderThan(ctx context.Context, date string) (int64, error)
+
+	// API keys
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	ListAPIKeys(ctx context.Context) ([]ApiKey, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+
+	// Events
+	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	GetEvent(ctx context.Context, id string) (Event, error)
+	ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error)
+	ListEventsBySubject(ctx context.Context, arg ListEventsBySubjectParams) ([]Event, error)
+	DeleteEventsBySubject(ctx context.Context, arg DeleteEventsBySubjectParams) (int64, error)
+
+	// Webhook deliveries
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+
+	// Dead letters
+	CreateDeadLetter(ctx context.Context, arg CreateDeadLetterParams) (DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id string) (DeadLetter, error)
+	ListDeadLetters(ctx context.Context, arg ListDeadLettersParams) ([]DeadLetter, error)
+	UpdateDeadLetter(ctx context.Context, arg UpdateDeadLetterParams) (DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, id string) (int64, error)
+
+	// Message dead letters
+	CreateMessageDeadLetter(ctx context.Context, arg CreateMessageDeadLetterParams) (MessageDeadLetter, error)
+	GetMessageDeadLetter(ctx context.Context, id string) (MessageDeadLetter, error)
+	ListMessageDeadLetters(ctx context.Context, arg ListMessageDeadLettersParams) ([]MessageDeadLetter, error)
+	UpdateMessageDeadLetter(ctx context.Context, arg UpdateMessageDeadLetterParams) (MessageDeadLetter, error)
+	DeleteMessageDeadLetter(ctx context.Context, id string) (int64, error)
+	DeleteMessageDeadLettersByUser(ctx context.Context, arg DeleteMessageDeadLettersByUserParams) (int64, error)
+
+	// Processed messages
+	RecordProcessedMessage(ctx context.Context, arg RecordProcessedMessageParams) (int64, error)
+	DeleteProcessedMessage(ctx context.Context, key string) error
+	DeleteExpiredProcessedMessages(ctx context.Context, now string) (int64, error)
+
+	// Workspaces
+	CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) (Workspace, error)
+	GetWorkspaceByID(ctx context.Context, id string) (Workspace, error)
+	ListWorkspaces(ctx context.Context) ([]Workspace, error)
+	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
+	DeleteWorkspace(ctx context.Context, id string) (int64, error)
+
+	// User preferences
+	GetUserPreferences(ctx context.Context, userID string) (UserPreference, error)
+	UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error)
+	DeleteUserPreferences(ctx context.Context, userID string) (int64, error)
+
+	// Analytics
+	IncrementAnalyticsCounter(ctx context.Context, arg IncrementAnalyticsCounterParams) error
+	AddAnalyticsActiveUser(ctx context.Context, arg AddAnalyticsActiveUserParams) error
+	ListAnalyticsCounters(ctx context.Context, arg ListAnalyticsCountersParams) ([]AnalyticsCounter, error)
+	CountAnalyticsActiveUsers(ctx context.Context, arg CountAnalyticsActiveUsersParams) ([]CountAnalyticsActiveUsersRow, error)
+
+	// User data erasures
+	CreateUserDataErasure(ctx context.Context, arg CreateUserDataErasureParams) (UserDataErasure, error)
+	ListUserDataErasures(ctx context.Context, arg ListUserDataErasuresParams) ([]UserDataErasure, error)
+
+	// Crash reports
+	CreateCrashReport(ctx context.Context, arg CreateCrashReportParams) (CrashReport, error)
+	ListCrashReports(ctx context.Context, arg ListCrashReportsParams) ([]CrashReport, error)
+
+	// Document chunks
+	CreateDocumentChunk(ctx context.Context, arg CreateDocumentChunkParams) error
+	ListDocumentChunks(ctx context.Context, sessionID string) ([]DocumentChunk, error)
+	DeleteDocumentChunks(ctx context.Context, sessionID string) (int64, error)
+
+	// Message feedback
+	UpsertMessageFeedback(ctx context.Context, arg UpsertMessageFeedbackParams) (MessageFeedback, error)
+	GetMessageFeedback(ctx context.Context, messageID string) (MessageFeedback, error)
+	CountMessageFeedback(ctx context.Context, arg CountMessageFeedbackParams) ([]CountMessageFeedbackRow, error)
+
+	// LLM usage
+	CreateLLMUsage(ctx context.Context, arg CreateLLMUsageParams) error
+	SumLLMUsage(ctx context.Context, arg SumLLMUsageParams) ([]SumLLMUsageRow, error)
+
+	// Idempotency keys
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error)
+	SaveIdempotencyKey(ctx context.Context, arg SaveIdempotencyKeyParams) (int64, error)
+	DeletePendingIdempotencyKey(ctx context.Context, key string) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now string) (int64, error)
+
+	// Message jobs
+	CreateMessageJob(ctx context.Context, arg CreateMessageJobParams) error
+	GetMessageJob(ctx context.Context, id string) (MessageJob, error)
+	ListMessageJobsByBatch(ctx context.Context, batchID string) ([]MessageJob, error)
+	UpdateMessageJob(ctx context.Context, arg UpdateMessageJobParams) error
+	FailUnfinishedMessageJobs(ctx context.Context, arg FailUnfinishedMessageJobsParams) (int64, error)
+	DeleteFinishedMessageJobs(ctx context.Context, finishedBefore string) (int64, error)
+
+	// Spilled messages
+	CreateSpilledMessage(ctx context.Context, arg CreateSpilledMessageParams) error
+	ListSpilledMessages(ctx context.Context, arg ListSpilledMessagesParams) ([]SpilledMessage, error)
+	CountSpilledMessages(ctx context.Context, connector string) (int64, error)
+	DeleteSpilledMessage(ctx context.Context, id string) (int64, error)
+
+	// Task attempts
+	CreateTaskAttempt(ctx context.Context, arg CreateTaskAttemptParams) error
+	ListTaskAttempts(ctx context.Context, taskID string) ([]TaskAttempt, error)
+
+	// Jobs
+	CreateJob(ctx context.Context, arg CreateJobParams) (int64, error)
+	GetJob(ctx context.Context, id string) (Job, error)
+	ClaimJob(ctx context.Context, arg ClaimJobParams) (Job, error)
+	UpdateJob(ctx context.Context, arg UpdateJobParams) (int64, error)
+	DeleteFinishedJobs(ctx context.Context, finishedBefore string) (int64, error)
+
+	// Leases
+	AcquireLease(ctx context.Context, arg AcquireLeaseParams) (int64, error)
+	ReleaseLease(ctx context.Context, arg ReleaseLeaseParams) error
 
 	// Migration
 	Migrate(ctx context.Context) error
+	MigrateTo(ctx context.Context, version uint) error
+	Rollback(ctx context.Context) error
+	MigrationStatus(ctx context.Context) (MigrationStatus, error)
+	ForceMigrationVersion(ctx context.Context, version int) error
+
+	// Backup
+	Backup(ctx context.Context, path string, compress bool) error
+	Restore(ctx context.Context, path string) error
+
+	// Health
+	Health(ctx context.Context) HealthStatus
 	Close() error
 }
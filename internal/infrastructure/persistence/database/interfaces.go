@@ -24,12 +24,30 @@ type SessionRepository interface {
 	GetByID(ctx context.Context, id string) (Session, error)
 	// GetByUserID retrieves all sessions for a user
 	GetByUserID(ctx context.Context, userID string) ([]Session, error)
+	// List retrieves a page of sessions of all users, newest first
+	List(ctx context.Context, arg ListSessionsParams) ([]Session, error)
+	// ListByUserID retrieves a page of sessions for a user, newest first
+	ListByUserID(ctx context.Context, arg ListSessionsByUserIDParams) ([]Session, error)
 	// Update updates a session
 	Update(ctx context.Context, arg UpdateSessionParams) (Session, error)
 	// Delete removes a session
 	Delete(ctx context.Context, id string) error
 }
 
+// SessionAttributeRepository defines operations for SessionAttribute entity
+type SessionAttributeRepository interface {
+	// Get retrieves an unexpired attribute of a session by key
+	Get(ctx context.Context, arg GetSessionAttributeParams) (SessionAttribute, error)
+	// List retrieves the unexpired attributes of a session, ordered by key
+	List(ctx context.Context, arg ListSessionAttributesParams) ([]SessionAttribute, error)
+	// Upsert creates or replaces an attribute
+	Upsert(ctx context.Context, arg UpsertSessionAttributeParams) (SessionAttribute, error)
+	// Delete removes an attribute and returns the number of removed rows
+	Delete(ctx context.Context, arg DeleteSessionAttributeParams) (int64, error)
+	// DeleteExpired removes attributes that expired at or before a specific date
+	DeleteExpired(ctx context.Context, now string) (int64, error)
+}
+
 // MessageRepository defines operations for Message entity
 type MessageRepository interface {
 	// Create creates a new message
@@ -38,6 +56,16 @@ type MessageRepository interface {
 	GetByID(ctx context.Context, id string) (Message, error)
 	// GetBySessionID retrieves all messages for a session
 	GetBySessionID(ctx context.Context, sessionID string) ([]Message, error)
+	// ListBySessionID retrieves a page of messages for a session, oldest first
+	ListBySessionID(ctx context.Context, arg ListMessagesBySessionIDParams) ([]Message, error)
+	// Search retrieves messages whose content matches a full-text query, newest first
+	Search(ctx context.Context, arg SearchMessagesParams) ([]Message, error)
+	// CountOlderThan counts messages older than a date outside pinned sessions
+	CountOlderThan(ctx context.Context, createdAt string) (int64, error)
+	// ListOlderThan retrieves a batch of messages older than a date outside pinned sessions, oldest first
+	ListOlderThan(ctx context.Context, arg ListMessagesOlderThanParams) ([]Message, error)
+	// DeleteOlderThan removes messages older than a date outside pinned sessions
+	DeleteOlderThan(ctx context.Context, createdAt string) (int64, error)
 	// Delete removes a message
 	Delete(ctx context.Context, id string) error
 }
@@ -50,6 +78,14 @@ type TaskRepository interface {
 	GetByID(ctx context.Context, id string) (Task, error)
 	// GetBySessionID retrieves all tasks for a session
 	GetBySessionID(ctx context.Context, sessionID string) ([]Task, error)
+	// ListBySessionID retrieves a page of tasks for a session, newest first
+	ListBySessionID(ctx context.Context, arg ListTasksBySessionIDParams) ([]Task, error)
+	// CountOlderThan counts tasks older than a date outside pinned sessions
+	CountOlderThan(ctx context.Context, createdAt string) (int64, error)
+	// ListOlderThan retrieves a batch of tasks older than a date outside pinned sessions, oldest first
+	ListOlderThan(ctx context.Context, arg ListTasksOlderThanParams) ([]Task, error)
+	// DeleteOlderThan removes tasks older than a date outside pinned sessions
+	DeleteOlderThan(ctx context.Context, createdAt string) (int64, error)
 	// Update updates a task
 	Update(ctx context.Context, arg UpdateTaskParams) (Task, error)
 	// Delete removes a task
@@ -88,6 +124,18 @@ type ScheduleRepository interface {
 	Delete(ctx context.Context, id string) error
 }
 
+// ScheduleRunRepository defines operations for ScheduleRun entity
+type ScheduleRunRepository interface {
+	// Create creates a new schedule run
+	Create(ctx context.Context, arg CreateScheduleRunParams) (ScheduleRun, error)
+	// Update updates a schedule run
+	Update(ctx context.Context, arg UpdateScheduleRunParams) (ScheduleRun, error)
+	// GetByScheduleID retrieves the most recent runs of a schedule
+	GetByScheduleID(ctx context.Context, arg GetScheduleRunsByScheduleIDParams) ([]ScheduleRun, error)
+	// GetLatest retrieves the most recent run of a schedule
+	GetLatest(ctx context.Context, scheduleID string) (ScheduleRun, error)
+}
+
 // LogRepository defines operations for Log entity
 type LogRepository interface {
 	// Create creates a new log entry
@@ -100,16 +148,272 @@ type LogRepository interface {
 	GetBySource(ctx context.Context, arg GetLogsBySourceParams) ([]Log, error)
 	// GetByDateRange retrieves all logs within a date range
 	GetByDateRange(ctx context.Context, arg GetLogsByDateRangeParams) ([]Log, error)
+	// List retrieves logs, newest first
+	List(ctx context.Context, arg ListLogsParams) ([]Log, error)
 	// Delete removes a log entry
 	Delete(ctx context.Context, id string) error
 	// DeleteOlderThan removes logs older than a specific date
-	DeleteOlderThan(ctx context.Context, date string) error
+	DeleteOlderThan(ctx context.Context, date string) (int64, error)
+	// CountOlderThan counts logs older than a specific date
+	CountOlderThan(ctx context.Context, createdAt string) (int64, error)
+	// ListOlderThan retrieves a batch of logs older than a specific date, oldest first
+	ListOlderThan(ctx context.Context, arg ListLogsOlderThanParams) ([]Log, error)
+}
+
+// APIKeyRepository defines operations for ApiKey entity
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	// GetByHash retrieves an API key by the SHA-256 hash of the key
+	GetByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	// List retrieves all API keys, oldest first
+	List(ctx context.Context) ([]ApiKey, error)
+	// Revoke marks an API key as revoked and returns the number of updated rows
+	Revoke(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+}
+
+// EventRepository defines operations for Event entity
+type EventRepository interface {
+	// Create stores an event after the previously stored ones
+	Create(ctx context.Context, arg CreateEventParams) (Event, error)
+	// Get retrieves a stored event by ID
+	Get(ctx context.Context, id string) (Event, error)
+	// List retrieves a batch of stored events in the order they were stored
+	List(ctx context.Context, arg ListEventsParams) ([]Event, error)
+	// ListBySubject retrieves the stored events about users or sessions in the order they were stored
+	ListBySubject(ctx context.Context, arg ListEventsBySubjectParams) ([]Event, error)
+	// DeleteBySubject removes the stored events about users or sessions and returns the number of deleted rows
+	DeleteBySubject(ctx context.Context, arg DeleteEventsBySubjectParams) (int64, error)
+}
+
+// WebhookDeliveryRepository defines operations for WebhookDelivery entity
+type WebhookDeliveryRepository interface {
+	// Create stores a new delivery
+	Create(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	// Update records the outcome of a delivery attempt
+	Update(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error)
+	// List retrieves deliveries, newest first
+	List(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+}
+
+// DeadLetterRepository defines operations for DeadLetter entity
+type DeadLetterRepository interface {
+	// Create stores an event whose handler failed
+	Create(ctx context.Context, arg CreateDeadLetterParams) (DeadLetter, error)
+	// GetByID retrieves a dead letter by ID
+	GetByID(ctx context.Context, id string) (DeadLetter, error)
+	// List retrieves dead letters, newest first
+	List(ctx context.Context, arg ListDeadLettersParams) ([]DeadLetter, error)
+	// Update records the outcome of a redelivery
+	Update(ctx context.Context, arg UpdateDeadLetterParams) (DeadLetter, error)
+	// Delete removes a dead letter and returns the number of deleted rows
+	Delete(ctx context.Context, id string) (int64, error)
+}
+
+// ProcessedMessageRepository defines operations for ProcessedMessage entity
+type ProcessedMessageRepository interface {
+	// Record stores a processed message, replacing an expired record, and returns the number of written rows
+	Record(ctx context.Context, arg RecordProcessedMessageParams) (int64, error)
+	// Delete removes the record of a processed message
+	Delete(ctx context.Context, key string) error
+	// DeleteExpired removes records that expired at or before a specific date
+	DeleteExpired(ctx context.Context, now string) (int64, error)
+}
+
+// MessageDeadLetterRepository defines operations for MessageDeadLetter entity
+type MessageDeadLetterRepository interface {
+	// Create stores a message the router failed to process
+	Create(ctx context.Context, arg CreateMessageDeadLetterParams) (MessageDeadLetter, error)
+	// GetByID retrieves a message dead letter by ID
+	GetByID(ctx context.Context, id string) (MessageDeadLetter, error)
+	// List retrieves message dead letters, newest first
+	List(ctx context.Context, arg ListMessageDeadLettersParams) ([]MessageDeadLetter, error)
+	// Update records the outcome of a reprocessing attempt
+	Update(ctx context.Context, arg UpdateMessageDeadLetterParams) (MessageDeadLetter, error)
+	// Delete removes a message dead letter and returns the number of deleted rows
+	Delete(ctx context.Context, id string) (int64, error)
+	// DeleteByUser removes the message dead letters of a user of a connector and returns the number of deleted rows
+	DeleteByUser(ctx context.Context, arg DeleteMessageDeadLettersByUserParams) (int64, error)
+}
+
+// WorkspaceRepository defines operations for Workspace entity
+type WorkspaceRepository interface {
+	// Create creates a new workspace
+	Create(ctx context.Context, arg CreateWorkspaceParams) (Workspace, error)
+	// GetByID retrieves a workspace by ID
+	GetByID(ctx context.Context, id string) (Workspace, error)
+	// List retrieves all workspaces, oldest first
+	List(ctx context.Context) ([]Workspace, error)
+	// Update updates the name and token budget of a workspace
+	Update(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
+	// Delete removes a workspace and returns the number of deleted rows
+	Delete(ctx context.Context, id string) (int64, error)
+}
+
+// UserPreferencesRepository defines operations for UserPreference entity
+type UserPreferencesRepository interface {
+	// Get retrieves the preferences of a user
+	Get(ctx context.Context, userID string) (UserPreference, error)
+	// Upsert creates or replaces the preferences of a user
+	Upsert(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error)
+	// Delete removes the preferences of a user and returns the number of deleted rows
+	Delete(ctx context.Context, userID string) (int64, error)
+}
+
+// AnalyticsRepository defines operations for the AnalyticsCounter and AnalyticsActiveUser entities
+type AnalyticsRepository interface {
+	// Increment adds a value to a daily counter, creating it if needed
+	Increment(ctx context.Context, arg IncrementAnalyticsCounterParams) error
+	// AddActiveUser records an active user of a connector on a day
+	AddActiveUser(ctx context.Context, arg AddAnalyticsActiveUserParams) error
+	// ListCounters retrieves the counters of a range of days
+	ListCounters(ctx context.Context, arg ListAnalyticsCountersParams) ([]AnalyticsCounter, error)
+	// CountActiveUsers counts the distinct active users of a range of days
+	CountActiveUsers(ctx context.Context, arg CountAnalyticsActiveUsersParams) ([]CountAnalyticsActiveUsersRow, error)
+}
+
+// UserDataErasureRepository defines operations for the UserDataErasure entity
+type UserDataErasureRepository interface {
+	// Create saves the audit record of an erasure
+	Create(ctx context.Context, arg CreateUserDataErasureParams) (UserDataErasure, error)
+	// List retrieves the audit records of erasures, newest first
+	List(ctx context.Context, arg ListUserDataErasuresParams) ([]UserDataErasure, error)
+}
+
+// CrashReportRepository defines operations for the CrashReport entity
+type CrashReportRepository interface {
+	// Create saves the report of a recovered panic
+	Create(ctx context.Context, arg CreateCrashReportParams) (CrashReport, error)
+	// List retrieves the reports of recovered panics, newest first
+	List(ctx context.Context, arg ListCrashReportsParams) ([]CrashReport, error)
+}
+
+// DocumentChunkRepository defines operations for the DocumentChunk entity
+type DocumentChunkRepository interface {
+	// Create saves a chunk of a document shared in a session
+	Create(ctx context.Context, arg CreateDocumentChunkParams) error
+	// ListBySessionID retrieves the chunks of the documents of a session
+	ListBySessionID(ctx context.Context, sessionID string) ([]DocumentChunk, error)
+	// DeleteBySessionID deletes the chunks of the documents of a session
+	DeleteBySessionID(ctx context.Context, sessionID string) (int64, error)
+}
+
+// MessageFeedbackRepository defines operations for the MessageFeedback entity
+type MessageFeedbackRepository interface {
+	// Upsert saves the feedback on a message, replacing an earlier one
+	Upsert(ctx context.Context, arg UpsertMessageFeedbackParams) (MessageFeedback, error)
+	// GetByMessageID retrieves the feedback on a message
+	GetByMessageID(ctx context.Context, messageID string) (MessageFeedback, error)
+	// CountByDay counts the feedback per day and rating
+	CountByDay(ctx context.Context, arg CountMessageFeedbackParams) ([]CountMessageFeedbackRow, error)
+}
+
+// LLMUsageRepository defines operations for the LlmUsage entity
+type LLMUsageRepository interface {
+	// Create saves the usage of an LLM call, ignoring a usage with the ID of a saved one
+	Create(ctx context.Context, arg CreateLLMUsageParams) error
+	// SumByMonth sums the usage per month, workspace, user, provider and model
+	SumByMonth(ctx context.Context, arg SumLLMUsageParams) ([]SumLLMUsageRow, error)
+}
+
+// IdempotencyRepository defines operations for the IdempotencyKey entity
+type IdempotencyRepository interface {
+	// Get retrieves an idempotency key that has not expired
+	Get(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	// Reserve saves a pending idempotency key unless an unexpired one is saved
+	Reserve(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error)
+	// Save saves the response of an idempotency key, replacing its pending key, unless an unexpired one is saved
+	Save(ctx context.Context, arg SaveIdempotencyKeyParams) (int64, error)
+	// DeletePending removes a pending idempotency key
+	DeletePending(ctx context.Context, key string) (int64, error)
+	// DeleteExpired removes keys that expired at or before a specific date
+	DeleteExpired(ctx context.Context, now string) (int64, error)
+}
+
+// MessageJobRepository defines operations for the MessageJob entity
+type MessageJobRepository interface {
+	// Create creates a new message job
+	Create(ctx context.Context, arg CreateMessageJobParams) error
+	// GetByID retrieves a message job by its ID
+	GetByID(ctx context.Context, id string) (MessageJob, error)
+	// ListByBatch retrieves the message jobs of a batch in their order
+	ListByBatch(ctx context.Context, batchID string) ([]MessageJob, error)
+	// Update updates the status and outcome of a message job
+	Update(ctx context.Context, arg UpdateMessageJobParams) error
+	// FailUnfinished fails the jobs that are pending or running
+	FailUnfinished(ctx context.Context, arg FailUnfinishedMessageJobsParams) (int64, error)
+	// DeleteFinished removes jobs that finished at or before a specific date
+	DeleteFinished(ctx context.Context, finishedBefore string) (int64, error)
+}
+
+// SpilledMessageRepository defines operations for the SpilledMessage entity
+type SpilledMessageRepository interface {
+	// Create saves a message that did not fit into the incoming buffer of a connector
+	Create(ctx context.Context, arg CreateSpilledMessageParams) error
+	// List retrieves the oldest spilled messages of a connector in sequence order
+	List(ctx context.Context, arg ListSpilledMessagesParams) ([]SpilledMessage, error)
+	// Count returns the number of spilled messages of a connector
+	Count(ctx context.Context, connector string) (int64, error)
+	// Delete removes a spilled message fed back to its connector
+	Delete(ctx context.Context, id string) (int64, error)
+}
+
+// TaskAttemptRepository defines operations for the TaskAttempt entity
+type TaskAttemptRepository interface {
+	// Create saves a finished attempt of a task
+	Create(ctx context.Context, arg CreateTaskAttemptParams) error
+	// ListByTaskID retrieves the attempts of a task in attempt order
+	ListByTaskID(ctx context.Context, taskID string) ([]TaskAttempt, error)
+}
+
+// JobRepository defines operations for the Job entity
+type JobRepository interface {
+	// Create saves a new job unless a job with its ID exists, and returns the rows inserted
+	Create(ctx context.Context, arg CreateJobParams) (int64, error)
+	// Get retrieves a job by its ID
+	Get(ctx context.Context, id string) (Job, error)
+	// Claim leases the job due the longest to a worker
+	Claim(ctx context.Context, arg ClaimJobParams) (Job, error)
+	// Update updates the status of a job still leased to its worker
+	Update(ctx context.Context, arg UpdateJobParams) (int64, error)
+	// DeleteFinished removes jobs that finished at or before a specific date
+	DeleteFinished(ctx context.Context, finishedBefore string) (int64, error)
+}
+
+// LeaseRepository defines operations for the Lease entity
+type LeaseRepository interface {
+	// Acquire takes or renews a lease unless another holder has it, and returns the rows written
+	Acquire(ctx context.Context, arg AcquireLeaseParams) (int64, error)
+	// Release gives up a lease held by a holder
+	Release(ctx context.Context, arg ReleaseLeaseParams) error
 }
 
 // Migration defines operations for database migrations
 type Migration interface {
 	// Migrate runs all pending migrations
 	Migrate(ctx context.Context) error
+	// MigrateTo migrates up or down to a specific version (0 = roll back everything)
+	MigrateTo(ctx context.Context, version uint) error
+	// Rollback rolls back the last migration
+	Rollback(ctx context.Context) error
+	// MigrationStatus returns the current migration version and dirty state
+	MigrationStatus(ctx context.Context) (MigrationStatus, error)
+	// ForceMigrationVersion sets the version and clears the dirty state without running migrations
+	ForceMigrationVersion(ctx context.Context, version int) error
+}
+
+// Backup defines operations for online database backups
+type Backup interface {
+	// Backup writes a consistent copy of the database to path, optionally gzip-compressed
+	Backup(ctx context.Context, path string, compress bool) error
+	// Restore replaces the database contents with a backup and applies pending migrations
+	Restore(ctx context.Context, path string) error
+}
+
+// HealthChecker defines the database health probe
+type HealthChecker interface {
+	// Health pings the database and reports connection pool statistics
+	Health(ctx context.Context) HealthStatus
 }
 
 // Closer defines the operation to close database connection
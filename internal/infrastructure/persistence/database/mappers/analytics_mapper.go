@@ -0,0 +1,35 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+)
+
+// AnalyticsCounterToDomain converts SQLC AnalyticsCounter model to domain AnalyticsCounter entity.
+func AnalyticsCounterToDomain(dbCounter *dbmodel.AnalyticsCounter) *entity.AnalyticsCounter {
+	if dbCounter == nil {
+		return nil
+	}
+
+	return &entity.AnalyticsCounter{
+		Day:       dbCounter.Day,
+		Metric:    entity.AnalyticsMetric(dbCounter.Metric),
+		Dimension: dbCounter.Dimension,
+		Value:     dbCounter.Value,
+	}
+}
+
+// AnalyticsActiveUsersToDomain converts a SQLC CountAnalyticsActiveUsersRow to an AnalyticsActiveUsers
+// counter of the connector.
+func AnalyticsActiveUsersToDomain(row *dbmodel.CountAnalyticsActiveUsersRow) *entity.AnalyticsCounter {
+	if row == nil {
+		return nil
+	}
+
+	return &entity.AnalyticsCounter{
+		Day:       row.Day,
+		Metric:    entity.AnalyticsActiveUsers,
+		Dimension: row.Connector,
+		Value:     float64(row.Users),
+	}
+}
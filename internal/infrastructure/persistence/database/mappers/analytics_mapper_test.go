@@ -0,0 +1,38 @@
+package mappers
+
+import (
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsCounterToDomain(t *testing.T) {
+	dbCounter := &dbmodel.AnalyticsCounter{
+		Day:       "2024-01-15",
+		Metric:    "tokens",
+		Dimension: "gpt-4o",
+		Value:     1250,
+	}
+
+	assert.Equal(t, &entity.AnalyticsCounter{
+		Day:       "2024-01-15",
+		Metric:    entity.AnalyticsTokens,
+		Dimension: "gpt-4o",
+		Value:     1250,
+	}, AnalyticsCounterToDomain(dbCounter))
+	assert.Nil(t, AnalyticsCounterToDomain(nil))
+}
+
+func TestAnalyticsActiveUsersToDomain(t *testing.T) {
+	row := &dbmodel.CountAnalyticsActiveUsersRow{Day: "2024-01-15", Connector: "telegram", Users: 3}
+
+	assert.Equal(t, &entity.AnalyticsCounter{
+		Day:       "2024-01-15",
+		Metric:    entity.AnalyticsActiveUsers,
+		Dimension: "telegram",
+		Value:     3,
+	}, AnalyticsActiveUsersToDomain(row))
+	assert.Nil(t, AnalyticsActiveUsersToDomain(nil))
+}
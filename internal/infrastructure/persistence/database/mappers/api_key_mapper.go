@@ -0,0 +1,68 @@
+package mappers
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// APIKeyToDomain converts SQLC ApiKey model to domain APIKey entity.
+func APIKeyToDomain(dbKey *dbmodel.ApiKey) *entity.APIKey {
+	if dbKey == nil {
+		return nil
+	}
+
+	var revokedAt *time.Time
+	if dbKey.RevokedAt.Valid && dbKey.RevokedAt.String != "" {
+		t := utils.ParseTimeRFC3339(dbKey.RevokedAt.String)
+		revokedAt = &t
+	}
+
+	return &entity.APIKey{
+		ID:          valueobject.APIKeyID(dbKey.ID),
+		Name:        dbKey.Name,
+		KeyHash:     dbKey.KeyHash,
+		Prefix:      dbKey.Prefix,
+		Scope:       valueobject.APIKeyScope(dbKey.Scope),
+		CreatedAt:   utils.ParseTimeRFC3339(dbKey.CreatedAt),
+		RevokedAt:   revokedAt,
+		WorkspaceID: valueobject.WorkspaceID(dbKey.WorkspaceID),
+	}
+}
+
+// APIKeyToDB converts domain APIKey entity to SQLC ApiKey model.
+func APIKeyToDB(apiKey *entity.APIKey) *dbmodel.ApiKey {
+	if apiKey == nil {
+		return nil
+	}
+
+	var revokedAt sql.NullString
+	if apiKey.RevokedAt != nil {
+		revokedAt.Valid = true
+		revokedAt.String = utils.FormatTimeRFC3339(*apiKey.RevokedAt)
+	}
+
+	return &dbmodel.ApiKey{
+		ID:          string(apiKey.ID),
+		Name:        apiKey.Name,
+		KeyHash:     apiKey.KeyHash,
+		Prefix:      apiKey.Prefix,
+		Scope:       string(apiKey.Scope),
+		CreatedAt:   utils.FormatTimeRFC3339(apiKey.CreatedAt),
+		RevokedAt:   revokedAt,
+		WorkspaceID: string(apiKey.WorkspaceID),
+	}
+}
+
+// APIKeysToDomain converts slice of SQLC ApiKey models to domain APIKey entities.
+func APIKeysToDomain(dbKeys []dbmodel.ApiKey) []*entity.APIKey {
+	keys := make([]*entity.APIKey, 0, len(dbKeys))
+	for i := range dbKeys {
+		keys = append(keys, APIKeyToDomain(&dbKeys[i]))
+	}
+	return keys
+}
@@ -0,0 +1,70 @@
+package mappers
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyMapper_RoundTrip(t *testing.T) {
+	revokedAt := time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		dbKey *dbmodel.ApiKey
+	}{
+		{
+			name: "Active key",
+			dbKey: &dbmodel.ApiKey{
+				ID:        "key-1",
+				Name:      "ci",
+				KeyHash:   "abc123",
+				Prefix:    "nfx_ABCDEF",
+				Scope:     "read",
+				CreatedAt: "2024-01-15T09:00:00Z",
+			},
+		},
+		{
+			name: "Revoked key",
+			dbKey: &dbmodel.ApiKey{
+				ID:        "key-2",
+				Name:      "admin",
+				KeyHash:   "def456",
+				Prefix:    "nfx_GHIJKL",
+				Scope:     "admin",
+				CreatedAt: "2024-01-15T09:00:00Z",
+				RevokedAt: sql.NullString{String: "2024-02-01T12:00:00Z", Valid: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKey := APIKeyToDomain(tt.dbKey)
+			require.NotNil(t, apiKey)
+			assert.Equal(t, valueobject.APIKeyID(tt.dbKey.ID), apiKey.ID)
+			assert.Equal(t, valueobject.APIKeyScope(tt.dbKey.Scope), apiKey.Scope)
+			assert.Equal(t, time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC), apiKey.CreatedAt)
+			if tt.dbKey.RevokedAt.Valid {
+				require.NotNil(t, apiKey.RevokedAt)
+				assert.Equal(t, revokedAt, *apiKey.RevokedAt)
+			} else {
+				assert.Nil(t, apiKey.RevokedAt)
+			}
+
+			assert.Equal(t, tt.dbKey, APIKeyToDB(apiKey))
+		})
+	}
+}
+
+func TestAPIKeyMapper_Nil(t *testing.T) {
+	assert.Nil(t, APIKeyToDomain(nil))
+	assert.Nil(t, APIKeyToDB((*entity.APIKey)(nil)))
+	assert.Empty(t, APIKeysToDomain(nil))
+}
@@ -0,0 +1,62 @@
+package mappers
+
+import (
+	"encoding/json"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// CrashReportToDomain converts SQLC CrashReport model to domain CrashReport entity.
+func CrashReportToDomain(dbReport *dbmodel.CrashReport) *entity.CrashReport {
+	if dbReport == nil {
+		return nil
+	}
+
+	var attributes map[string]string
+	if err := json.Unmarshal([]byte(dbReport.Attributes), &attributes); err != nil || len(attributes) == 0 {
+		attributes = nil
+	}
+
+	return &entity.CrashReport{
+		ID:         dbReport.ID,
+		Component:  dbReport.Component,
+		Operation:  dbReport.Operation,
+		Panic:      dbReport.Panic,
+		Stack:      dbReport.Stack,
+		Attributes: attributes,
+		CreatedAt:  utils.ParseTimeRFC3339(dbReport.CreatedAt),
+	}
+}
+
+// CrashReportToDB converts domain CrashReport entity to SQLC CrashReport model.
+func CrashReportToDB(report *entity.CrashReport) *dbmodel.CrashReport {
+	if report == nil {
+		return nil
+	}
+
+	attributes := "{}"
+	if len(report.Attributes) > 0 {
+		attributes = utils.MarshalJSON(report.Attributes)
+	}
+
+	return &dbmodel.CrashReport{
+		ID:         report.ID,
+		Component:  report.Component,
+		Operation:  report.Operation,
+		Panic:      report.Panic,
+		Stack:      report.Stack,
+		Attributes: attributes,
+		CreatedAt:  utils.FormatTimeRFC3339(report.CreatedAt),
+	}
+}
+
+// CrashReportsToDomain converts slice of SQLC CrashReport models to domain CrashReport entities.
+func CrashReportsToDomain(dbReports []dbmodel.CrashReport) []*entity.CrashReport {
+	reports := make([]*entity.CrashReport, 0, len(dbReports))
+	for i := range dbReports {
+		reports = append(reports, CrashReportToDomain(&dbReports[i]))
+	}
+	return reports
+}
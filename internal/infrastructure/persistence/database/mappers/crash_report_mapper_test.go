@@ -0,0 +1,51 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrashReportToDomain(t *testing.T) {
+	dbReport := &dbmodel.CrashReport{
+		ID:         "crash-1",
+		Component:  "router",
+		Operation:  "handle_message",
+		Panic:      "boom",
+		Stack:      "goroutine 1 [running]:",
+		Attributes: `{"connector":"telegram"}`,
+		CreatedAt:  "2024-01-15T09:00:00Z",
+	}
+
+	result := CrashReportToDomain(dbReport)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.CrashReport{
+		ID:         "crash-1",
+		Component:  "router",
+		Operation:  "handle_message",
+		Panic:      "boom",
+		Stack:      "goroutine 1 [running]:",
+		Attributes: map[string]string{"connector": "telegram"},
+		CreatedAt:  time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, CrashReportToDomain(nil))
+}
+
+func TestCrashReportToDB_RoundTrip(t *testing.T) {
+	report := entity.NewCrashReport("skills", "execute", "nil map", "stack", nil)
+
+	dbReport := CrashReportToDB(report)
+	require.NotNil(t, dbReport)
+	assert.Equal(t, "{}", dbReport.Attributes)
+
+	result := CrashReportToDomain(dbReport)
+	assert.Equal(t, report.ID, result.ID)
+	assert.Nil(t, result.Attributes)
+	assert.WithinDuration(t, report.CreatedAt, result.CreatedAt, time.Second)
+	assert.Nil(t, CrashReportToDB(nil))
+}
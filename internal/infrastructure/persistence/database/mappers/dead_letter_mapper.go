@@ -0,0 +1,54 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// DeadLetterToDomain converts SQLC DeadLetter model to domain DeadLetter entity.
+func DeadLetterToDomain(dbLetter *dbmodel.DeadLetter) *entity.DeadLetter {
+	if dbLetter == nil {
+		return nil
+	}
+
+	return &entity.DeadLetter{
+		ID:           dbLetter.ID,
+		Subscription: dbLetter.Subscription,
+		EventType:    dbLetter.EventType,
+		Payload:      dbLetter.Payload,
+		OccurredAt:   utils.ParseTimeRFC3339(dbLetter.OccurredAt),
+		Attempts:     int(dbLetter.Attempts),
+		Error:        dbLetter.Error,
+		CreatedAt:    utils.ParseTimeRFC3339(dbLetter.CreatedAt),
+		UpdatedAt:    utils.ParseTimeRFC3339(dbLetter.UpdatedAt),
+	}
+}
+
+// DeadLetterToDB converts domain DeadLetter entity to SQLC DeadLetter model.
+func DeadLetterToDB(letter *entity.DeadLetter) *dbmodel.DeadLetter {
+	if letter == nil {
+		return nil
+	}
+
+	return &dbmodel.DeadLetter{
+		ID:           letter.ID,
+		Subscription: letter.Subscription,
+		EventType:    letter.EventType,
+		Payload:      letter.Payload,
+		OccurredAt:   utils.FormatTimeRFC3339(letter.OccurredAt.UTC()),
+		Attempts:     int64(letter.Attempts),
+		Error:        letter.Error,
+		CreatedAt:    utils.FormatTimeRFC3339(letter.CreatedAt),
+		UpdatedAt:    utils.FormatTimeRFC3339(letter.UpdatedAt),
+	}
+}
+
+// DeadLettersToDomain converts slice of SQLC DeadLetter models to domain DeadLetter entities.
+func DeadLettersToDomain(dbLetters []dbmodel.DeadLetter) []*entity.DeadLetter {
+	letters := make([]*entity.DeadLetter, 0, len(dbLetters))
+	for i := range dbLetters {
+		letters = append(letters, DeadLetterToDomain(&dbLetters[i]))
+	}
+	return letters
+}
@@ -0,0 +1,57 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterToDomain(t *testing.T) {
+	dbLetter := &dbmodel.DeadLetter{
+		ID:           "letter-1",
+		Subscription: "webhooks",
+		EventType:    "task.completed",
+		Payload:      `{"task_id":"task-1"}`,
+		OccurredAt:   "2024-01-15T08:59:59Z",
+		Attempts:     3,
+		Error:        "boom",
+		CreatedAt:    "2024-01-15T09:00:00Z",
+		UpdatedAt:    "2024-01-15T09:00:05Z",
+	}
+
+	result := DeadLetterToDomain(dbLetter)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.DeadLetter{
+		ID:           "letter-1",
+		Subscription: "webhooks",
+		EventType:    "task.completed",
+		Payload:      `{"task_id":"task-1"}`,
+		OccurredAt:   time.Date(2024, time.January, 15, 8, 59, 59, 0, time.UTC),
+		Attempts:     3,
+		Error:        "boom",
+		CreatedAt:    time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2024, time.January, 15, 9, 0, 5, 0, time.UTC),
+	}, result)
+	assert.Nil(t, DeadLetterToDomain(nil))
+}
+
+func TestDeadLetterToDB_RoundTrip(t *testing.T) {
+	letter := entity.NewDeadLetter("webhooks", "router.message", `{"message_id":"msg-1"}`, time.Now(), 2, "timeout")
+
+	dbLetter := DeadLetterToDB(letter)
+	require.NotNil(t, dbLetter)
+	assert.Equal(t, int64(2), dbLetter.Attempts)
+
+	result := DeadLetterToDomain(dbLetter)
+	assert.Equal(t, letter.ID, result.ID)
+	assert.Equal(t, letter.Payload, result.Payload)
+	assert.WithinDuration(t, letter.OccurredAt, result.OccurredAt, time.Second)
+	assert.WithinDuration(t, letter.CreatedAt, result.CreatedAt, time.Second)
+
+	assert.Nil(t, DeadLetterToDB(nil))
+}
@@ -0,0 +1,65 @@
+package mappers
+
+import (
+	"encoding/json"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// DocumentChunkToDomain converts SQLC DocumentChunk model to domain DocumentChunk entity.
+func DocumentChunkToDomain(dbChunk *dbmodel.DocumentChunk) *entity.DocumentChunk {
+	if dbChunk == nil {
+		return nil
+	}
+
+	var embedding []float32
+	if err := json.Unmarshal([]byte(dbChunk.Embedding), &embedding); err != nil || len(embedding) == 0 {
+		embedding = nil
+	}
+
+	return &entity.DocumentChunk{
+		ID:           dbChunk.ID,
+		SessionID:    valueobject.SessionID(dbChunk.SessionID),
+		DocumentID:   dbChunk.DocumentID,
+		DocumentName: dbChunk.DocumentName,
+		Position:     int(dbChunk.Position),
+		Content:      dbChunk.Content,
+		Embedding:    embedding,
+		CreatedAt:    utils.ParseTimeRFC3339(dbChunk.CreatedAt),
+	}
+}
+
+// DocumentChunkToDB converts domain DocumentChunk entity to SQLC DocumentChunk model.
+func DocumentChunkToDB(chunk *entity.DocumentChunk) *dbmodel.DocumentChunk {
+	if chunk == nil {
+		return nil
+	}
+
+	embedding := "[]"
+	if len(chunk.Embedding) > 0 {
+		embedding = utils.MarshalJSON(chunk.Embedding)
+	}
+
+	return &dbmodel.DocumentChunk{
+		ID:           chunk.ID,
+		SessionID:    chunk.SessionID.String(),
+		DocumentID:   chunk.DocumentID,
+		DocumentName: chunk.DocumentName,
+		Position:     int64(chunk.Position),
+		Content:      chunk.Content,
+		Embedding:    embedding,
+		CreatedAt:    utils.FormatTimeRFC3339(chunk.CreatedAt),
+	}
+}
+
+// DocumentChunksToDomain converts slice of SQLC DocumentChunk models to domain DocumentChunk entities.
+func DocumentChunksToDomain(dbChunks []dbmodel.DocumentChunk) []*entity.DocumentChunk {
+	chunks := make([]*entity.DocumentChunk, 0, len(dbChunks))
+	for i := range dbChunks {
+		chunks = append(chunks, DocumentChunkToDomain(&dbChunks[i]))
+	}
+	return chunks
+}
@@ -0,0 +1,53 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentChunkToDomain(t *testing.T) {
+	dbChunk := &dbmodel.DocumentChunk{
+		ID:           "chunk-1",
+		SessionID:    "session-1",
+		DocumentID:   "doc-1",
+		DocumentName: "report.pdf",
+		Position:     3,
+		Content:      "Revenue grew by 12%.",
+		Embedding:    "[0.6,0.8]",
+		CreatedAt:    "2024-01-15T09:00:00Z",
+	}
+
+	result := DocumentChunkToDomain(dbChunk)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.DocumentChunk{
+		ID:           "chunk-1",
+		SessionID:    "session-1",
+		DocumentID:   "doc-1",
+		DocumentName: "report.pdf",
+		Position:     3,
+		Content:      "Revenue grew by 12%.",
+		Embedding:    []float32{0.6, 0.8},
+		CreatedAt:    time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, DocumentChunkToDomain(nil))
+}
+
+func TestDocumentChunkToDB_RoundTrip(t *testing.T) {
+	chunk := entity.NewDocumentChunk("session-1", "doc-1", "notes.txt", 0, "Meeting notes", nil)
+
+	dbChunk := DocumentChunkToDB(chunk)
+	require.NotNil(t, dbChunk)
+	assert.Equal(t, "[]", dbChunk.Embedding)
+
+	result := DocumentChunkToDomain(dbChunk)
+	assert.Equal(t, chunk.ID, result.ID)
+	assert.Nil(t, result.Embedding)
+	assert.WithinDuration(t, chunk.CreatedAt, result.CreatedAt, time.Second)
+	assert.Nil(t, DocumentChunkToDB(nil))
+}
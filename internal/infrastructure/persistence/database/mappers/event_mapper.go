@@ -0,0 +1,46 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// StoredEventToDomain converts SQLC Event model to domain StoredEvent entity.
+func StoredEventToDomain(dbEvent *dbmodel.Event) *entity.StoredEvent {
+	if dbEvent == nil {
+		return nil
+	}
+
+	return &entity.StoredEvent{
+		Seq:        dbEvent.Seq,
+		ID:         dbEvent.ID,
+		Type:       dbEvent.Type,
+		Payload:    dbEvent.Payload,
+		OccurredAt: utils.ParseTimeRFC3339(dbEvent.OccurredAt),
+	}
+}
+
+// StoredEventToDB converts domain StoredEvent entity to SQLC Event model.
+func StoredEventToDB(event *entity.StoredEvent) *dbmodel.Event {
+	if event == nil {
+		return nil
+	}
+
+	return &dbmodel.Event{
+		Seq:        event.Seq,
+		ID:         event.ID,
+		Type:       event.Type,
+		Payload:    event.Payload,
+		OccurredAt: utils.FormatTimeRFC3339(event.OccurredAt.UTC()),
+	}
+}
+
+// StoredEventsToDomain converts slice of SQLC Event models to domain StoredEvent entities.
+func StoredEventsToDomain(dbEvents []dbmodel.Event) []*entity.StoredEvent {
+	events := make([]*entity.StoredEvent, 0, len(dbEvents))
+	for i := range dbEvents {
+		events = append(events, StoredEventToDomain(&dbEvents[i]))
+	}
+	return events
+}
@@ -0,0 +1,58 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredEventToDomain(t *testing.T) {
+	dbEvent := &dbmodel.Event{
+		Seq:        7,
+		ID:         "event-1",
+		Type:       "router.message",
+		Payload:    `{"user_id":"user-1"}`,
+		OccurredAt: "2024-01-15T09:00:00Z",
+	}
+
+	result := StoredEventToDomain(dbEvent)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.StoredEvent{
+		Seq:        7,
+		ID:         "event-1",
+		Type:       "router.message",
+		Payload:    `{"user_id":"user-1"}`,
+		OccurredAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, StoredEventToDomain(nil))
+}
+
+func TestStoredEventToDB_RoundTrip(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	event := entity.NewStoredEvent("schedule.completed", `{"schedule_id":"schedule-1"}`, time.Date(2024, time.January, 15, 12, 0, 0, 0, moscow))
+
+	dbEvent := StoredEventToDB(event)
+	require.NotNil(t, dbEvent)
+	assert.Equal(t, "2024-01-15T09:00:00Z", dbEvent.OccurredAt)
+
+	result := StoredEventToDomain(dbEvent)
+	assert.Equal(t, event.ID, result.ID)
+	assert.Equal(t, event.Type, result.Type)
+	assert.Equal(t, event.Payload, result.Payload)
+	assert.True(t, event.OccurredAt.Equal(result.OccurredAt))
+
+	assert.Nil(t, StoredEventToDB(nil))
+}
+
+func TestStoredEventsToDomain(t *testing.T) {
+	result := StoredEventsToDomain([]dbmodel.Event{{Seq: 1, ID: "event-1"}, {Seq: 2, ID: "event-2"}})
+
+	require.Len(t, result, 2)
+	assert.Equal(t, int64(1), result[0].Seq)
+	assert.Equal(t, int64(2), result[1].Seq)
+}
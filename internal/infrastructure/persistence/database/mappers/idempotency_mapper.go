@@ -0,0 +1,41 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// IdempotencyRecordToDomain converts SQLC IdempotencyKey model to domain IdempotencyRecord entity.
+func IdempotencyRecordToDomain(dbKey *dbmodel.IdempotencyKey) *entity.IdempotencyRecord {
+	if dbKey == nil {
+		return nil
+	}
+
+	return &entity.IdempotencyRecord{
+		Key:         dbKey.Key,
+		RequestHash: dbKey.RequestHash,
+		StatusCode:  int(dbKey.StatusCode),
+		ContentType: dbKey.ContentType,
+		Body:        []byte(dbKey.Body),
+		ExpiresAt:   utils.ParseTimeRFC3339(dbKey.ExpiresAt),
+		CreatedAt:   utils.ParseTimeRFC3339(dbKey.CreatedAt),
+	}
+}
+
+// IdempotencyRecordToDB converts domain IdempotencyRecord entity to SQLC IdempotencyKey model.
+func IdempotencyRecordToDB(record *entity.IdempotencyRecord) *dbmodel.IdempotencyKey {
+	if record == nil {
+		return nil
+	}
+
+	return &dbmodel.IdempotencyKey{
+		Key:         record.Key,
+		RequestHash: record.RequestHash,
+		StatusCode:  int64(record.StatusCode),
+		ContentType: record.ContentType,
+		Body:        string(record.Body),
+		ExpiresAt:   utils.FormatTimeRFC3339(record.ExpiresAt.UTC()),
+		CreatedAt:   utils.FormatTimeRFC3339(record.CreatedAt.UTC()),
+	}
+}
@@ -0,0 +1,32 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRecord_RoundTrip(t *testing.T) {
+	record := &entity.IdempotencyRecord{
+		Key:         "key-hash",
+		RequestHash: "request-hash",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"success":true}`),
+		ExpiresAt:   time.Date(2024, time.January, 16, 9, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}
+
+	dbKey := IdempotencyRecordToDB(record)
+	require.NotNil(t, dbKey)
+	assert.Equal(t, int64(201), dbKey.StatusCode)
+	assert.Equal(t, `{"success":true}`, dbKey.Body)
+	assert.Equal(t, "2024-01-16T09:00:00Z", dbKey.ExpiresAt)
+
+	assert.Equal(t, record, IdempotencyRecordToDomain(dbKey))
+	assert.Nil(t, IdempotencyRecordToDB(nil))
+	assert.Nil(t, IdempotencyRecordToDomain(nil))
+}
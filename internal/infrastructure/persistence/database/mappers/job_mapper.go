@@ -0,0 +1,72 @@
+package mappers
+
+import (
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// JobToDomain converts SQLC Job model to domain Job entity.
+func JobToDomain(dbJob *dbmodel.Job) *entity.Job {
+	if dbJob == nil {
+		return nil
+	}
+
+	return &entity.Job{
+		ID:          dbJob.ID,
+		Kind:        dbJob.Kind,
+		Payload:     dbJob.Payload,
+		Status:      entity.JobStatus(dbJob.Status),
+		Attempts:    int(dbJob.Attempts),
+		MaxAttempts: int(dbJob.MaxAttempts),
+		Error:       dbJob.Error,
+		RunAt:       utils.ParseTimeRFC3339(dbJob.RunAt),
+		LockedBy:    dbJob.LockedBy,
+		LockedUntil: nullTimeToDomain(dbJob.LockedUntil),
+		CreatedAt:   utils.ParseTimeRFC3339(dbJob.CreatedAt),
+		UpdatedAt:   utils.ParseTimeRFC3339(dbJob.UpdatedAt),
+		FinishedAt:  nullTimeToDomain(dbJob.FinishedAt),
+		LeaderOnly:  dbJob.LeaderOnly != 0,
+	}
+}
+
+// JobToDB converts domain Job entity to SQLC Job model. Times are stored in UTC, so that the
+// due jobs can be compared as text.
+func JobToDB(job *entity.Job) *dbmodel.Job {
+	if job == nil {
+		return nil
+	}
+
+	leaderOnly := int64(0)
+	if job.LeaderOnly {
+		leaderOnly = 1
+	}
+
+	return &dbmodel.Job{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		Payload:     job.Payload,
+		Status:      string(job.Status),
+		Attempts:    int64(job.Attempts),
+		MaxAttempts: int64(job.MaxAttempts),
+		Error:       job.Error,
+		RunAt:       utils.FormatTimeRFC3339(job.RunAt.UTC()),
+		LockedBy:    job.LockedBy,
+		LockedUntil: nullTimeToDB(utcTime(job.LockedUntil)),
+		CreatedAt:   utils.FormatTimeRFC3339(job.CreatedAt.UTC()),
+		UpdatedAt:   utils.FormatTimeRFC3339(job.UpdatedAt.UTC()),
+		FinishedAt:  nullTimeToDB(utcTime(job.FinishedAt)),
+		LeaderOnly:  leaderOnly,
+	}
+}
+
+// utcTime returns an optional time in UTC
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,47 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJob_RoundTrip(t *testing.T) {
+	created := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	lockedUntil := created.Add(5 * time.Minute)
+	job := &entity.Job{
+		ID:          "job-1",
+		Kind:        "webhook_delivery",
+		Payload:     `{"delivery_id": "1"}`,
+		Status:      entity.JobRunning,
+		Attempts:    1,
+		MaxAttempts: 3,
+		Error:       "connection reset",
+		RunAt:       created,
+		LockedBy:    "worker-1",
+		LockedUntil: &lockedUntil,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+
+	dbJob := JobToDB(job)
+	require.NotNil(t, dbJob)
+	assert.Equal(t, "2024-01-15T09:05:00Z", dbJob.LockedUntil.String)
+	assert.False(t, dbJob.FinishedAt.Valid)
+
+	assert.Equal(t, job, JobToDomain(dbJob))
+	assert.Nil(t, JobToDomain(nil))
+	assert.Nil(t, JobToDB(nil))
+}
+
+func TestJobToDB_UTC(t *testing.T) {
+	// Due jobs are compared as text, so times in other zones are stored in UTC
+	zone := time.FixedZone("UTC+3", 3*60*60)
+	job := entity.NewJob("schedule_run", "", "{}", 1)
+	job.RunAt = time.Date(2024, time.January, 15, 12, 0, 0, 0, zone)
+
+	assert.Equal(t, "2024-01-15T09:00:00Z", JobToDB(job).RunAt)
+}
@@ -0,0 +1,72 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// LLMUsageToDomain converts SQLC LlmUsage model to domain LLMUsage entity.
+func LLMUsageToDomain(dbUsage *dbmodel.LlmUsage) *entity.LLMUsage {
+	if dbUsage == nil {
+		return nil
+	}
+
+	return &entity.LLMUsage{
+		ID:           dbUsage.ID,
+		UserID:       valueobject.UserID(dbUsage.UserID),
+		WorkspaceID:  valueobject.WorkspaceID(dbUsage.WorkspaceID),
+		SessionID:    valueobject.SessionID(dbUsage.SessionID),
+		Provider:     dbUsage.Provider,
+		Model:        dbUsage.Model,
+		InputTokens:  int(dbUsage.InputTokens),
+		OutputTokens: int(dbUsage.OutputTokens),
+		TotalTokens:  int(dbUsage.TotalTokens),
+		Cost:         dbUsage.Cost,
+		Month:        dbUsage.Month,
+		CreatedAt:    utils.ParseTimeRFC3339(dbUsage.CreatedAt),
+	}
+}
+
+// LLMUsageToDB converts domain LLMUsage entity to SQLC LlmUsage model.
+func LLMUsageToDB(usage *entity.LLMUsage) *dbmodel.LlmUsage {
+	if usage == nil {
+		return nil
+	}
+
+	return &dbmodel.LlmUsage{
+		ID:           usage.ID,
+		UserID:       usage.UserID.String(),
+		WorkspaceID:  usage.WorkspaceID.String(),
+		SessionID:    usage.SessionID.String(),
+		Provider:     usage.Provider,
+		Model:        usage.Model,
+		InputTokens:  int64(usage.InputTokens),
+		OutputTokens: int64(usage.OutputTokens),
+		TotalTokens:  int64(usage.TotalTokens),
+		Cost:         usage.Cost,
+		Month:        usage.Month,
+		CreatedAt:    utils.FormatTimeRFC3339(usage.CreatedAt),
+	}
+}
+
+// LLMUsageTotalToDomain converts a SQLC usage sum row to a domain LLMUsageTotal.
+func LLMUsageTotalToDomain(row *dbmodel.SumLLMUsageRow) *entity.LLMUsageTotal {
+	if row == nil {
+		return nil
+	}
+
+	return &entity.LLMUsageTotal{
+		Month:        row.Month,
+		WorkspaceID:  valueobject.WorkspaceID(row.WorkspaceID),
+		UserID:       valueobject.UserID(row.UserID),
+		Provider:     row.Provider,
+		Model:        row.Model,
+		Requests:     row.Requests,
+		InputTokens:  row.InputTokens,
+		OutputTokens: row.OutputTokens,
+		TotalTokens:  row.TotalTokens,
+		Cost:         row.Cost,
+	}
+}
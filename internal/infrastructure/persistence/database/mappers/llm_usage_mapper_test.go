@@ -0,0 +1,72 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLLMUsageToDomain(t *testing.T) {
+	dbUsage := &dbmodel.LlmUsage{
+		ID:           "event-1",
+		UserID:       "user-1",
+		WorkspaceID:  "support",
+		SessionID:    "session-1",
+		Provider:     "openai",
+		Model:        "gpt-4o",
+		InputTokens:  120,
+		OutputTokens: 30,
+		TotalTokens:  150,
+		Cost:         0.002,
+		Month:        "2024-01",
+		CreatedAt:    "2024-01-15T09:00:00Z",
+	}
+
+	result := LLMUsageToDomain(dbUsage)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.LLMUsage{
+		ID:           "event-1",
+		UserID:       "user-1",
+		WorkspaceID:  "support",
+		SessionID:    "session-1",
+		Provider:     "openai",
+		Model:        "gpt-4o",
+		InputTokens:  120,
+		OutputTokens: 30,
+		TotalTokens:  150,
+		Cost:         0.002,
+		Month:        "2024-01",
+		CreatedAt:    time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, LLMUsageToDomain(nil))
+}
+
+func TestLLMUsageToDB_RoundTrip(t *testing.T) {
+	usage := &entity.LLMUsage{
+		ID:          "event-1",
+		UserID:      "user-1",
+		Model:       "gpt-4o",
+		TotalTokens: 150,
+		Month:       "2024-01",
+		CreatedAt:   time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}
+
+	dbUsage := LLMUsageToDB(usage)
+	require.NotNil(t, dbUsage)
+	assert.Equal(t, int64(150), dbUsage.TotalTokens)
+
+	assert.Equal(t, usage, LLMUsageToDomain(dbUsage))
+	assert.Nil(t, LLMUsageToDB(nil))
+}
+
+func TestLLMUsageTotalToDomain(t *testing.T) {
+	result := LLMUsageTotalToDomain(&dbmodel.SumLLMUsageRow{Month: "2024-01", WorkspaceID: "support", UserID: "user-1", Model: "gpt-4o", Requests: 2, TotalTokens: 300, Cost: 0.004})
+
+	assert.Equal(t, &entity.LLMUsageTotal{Month: "2024-01", WorkspaceID: "support", UserID: "user-1", Model: "gpt-4o", Requests: 2, TotalTokens: 300, Cost: 0.004}, result)
+	assert.Nil(t, LLMUsageTotalToDomain(nil))
+}
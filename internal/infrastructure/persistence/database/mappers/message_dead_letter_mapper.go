@@ -0,0 +1,61 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// MessageDeadLetterToDomain converts SQLC MessageDeadLetter model to domain MessageDeadLetter entity.
+func MessageDeadLetterToDomain(dbLetter *dbmodel.MessageDeadLetter) *entity.MessageDeadLetter {
+	if dbLetter == nil {
+		return nil
+	}
+
+	return &entity.MessageDeadLetter{
+		ID:        dbLetter.ID,
+		Connector: dbLetter.Connector,
+		UserID:    dbLetter.UserID,
+		ChannelID: dbLetter.ChannelID,
+		Content:   dbLetter.Content,
+		Metadata:  dbLetter.Metadata,
+		Attempts:  int(dbLetter.Attempts),
+		Error:     dbLetter.Error,
+		CreatedAt: utils.ParseTimeRFC3339(dbLetter.CreatedAt),
+		UpdatedAt: utils.ParseTimeRFC3339(dbLetter.UpdatedAt),
+	}
+}
+
+// MessageDeadLetterToDB converts domain MessageDeadLetter entity to SQLC MessageDeadLetter model.
+func MessageDeadLetterToDB(letter *entity.MessageDeadLetter) *dbmodel.MessageDeadLetter {
+	if letter == nil {
+		return nil
+	}
+
+	metadata := letter.Metadata
+	if metadata == "" {
+		metadata = "{}"
+	}
+
+	return &dbmodel.MessageDeadLetter{
+		ID:        letter.ID,
+		Connector: letter.Connector,
+		UserID:    letter.UserID,
+		ChannelID: letter.ChannelID,
+		Content:   letter.Content,
+		Metadata:  metadata,
+		Attempts:  int64(letter.Attempts),
+		Error:     letter.Error,
+		CreatedAt: utils.FormatTimeRFC3339(letter.CreatedAt),
+		UpdatedAt: utils.FormatTimeRFC3339(letter.UpdatedAt),
+	}
+}
+
+// MessageDeadLettersToDomain converts slice of SQLC MessageDeadLetter models to domain MessageDeadLetter entities.
+func MessageDeadLettersToDomain(dbLetters []dbmodel.MessageDeadLetter) []*entity.MessageDeadLetter {
+	letters := make([]*entity.MessageDeadLetter, 0, len(dbLetters))
+	for i := range dbLetters {
+		letters = append(letters, MessageDeadLetterToDomain(&dbLetters[i]))
+	}
+	return letters
+}
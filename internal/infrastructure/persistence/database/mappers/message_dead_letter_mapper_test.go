@@ -0,0 +1,60 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageDeadLetterToDomain(t *testing.T) {
+	dbLetter := &dbmodel.MessageDeadLetter{
+		ID:        "letter-1",
+		Connector: "telegram",
+		UserID:    "tg:42",
+		ChannelID: "100",
+		Content:   "Hello",
+		Metadata:  `{"message_id":7}`,
+		Attempts:  2,
+		Error:     "boom",
+		CreatedAt: "2024-01-15T09:00:00Z",
+		UpdatedAt: "2024-01-15T09:00:05Z",
+	}
+
+	result := MessageDeadLetterToDomain(dbLetter)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.MessageDeadLetter{
+		ID:        "letter-1",
+		Connector: "telegram",
+		UserID:    "tg:42",
+		ChannelID: "100",
+		Content:   "Hello",
+		Metadata:  `{"message_id":7}`,
+		Attempts:  2,
+		Error:     "boom",
+		CreatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, time.January, 15, 9, 0, 5, 0, time.UTC),
+	}, result)
+	assert.Nil(t, MessageDeadLetterToDomain(nil))
+}
+
+func TestMessageDeadLetterToDB_RoundTrip(t *testing.T) {
+	letter := entity.NewMessageDeadLetter("telegram", "tg:42", "100", "Hello", map[string]interface{}{"chat_id": 100}, "timeout")
+
+	dbLetter := MessageDeadLetterToDB(letter)
+	require.NotNil(t, dbLetter)
+	assert.Equal(t, int64(1), dbLetter.Attempts)
+
+	result := MessageDeadLetterToDomain(dbLetter)
+	assert.Equal(t, letter.ID, result.ID)
+	assert.Equal(t, letter.Metadata, result.Metadata)
+	assert.WithinDuration(t, letter.CreatedAt, result.CreatedAt, time.Second)
+
+	letter.Metadata = ""
+	assert.Equal(t, "{}", MessageDeadLetterToDB(letter).Metadata)
+	assert.Nil(t, MessageDeadLetterToDB(nil))
+}
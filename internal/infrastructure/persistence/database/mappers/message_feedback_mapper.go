@@ -0,0 +1,60 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// MessageFeedbackToDomain converts SQLC MessageFeedback model to domain MessageFeedback entity.
+func MessageFeedbackToDomain(dbFeedback *dbmodel.MessageFeedback) *entity.MessageFeedback {
+	if dbFeedback == nil {
+		return nil
+	}
+
+	return &entity.MessageFeedback{
+		ID:        dbFeedback.ID,
+		MessageID: valueobject.MessageID(dbFeedback.MessageID),
+		SessionID: valueobject.SessionID(dbFeedback.SessionID),
+		UserID:    valueobject.UserID(dbFeedback.UserID),
+		Rating:    entity.FeedbackRating(dbFeedback.Rating),
+		Comment:   dbFeedback.Comment,
+		Day:       dbFeedback.Day,
+		CreatedAt: utils.ParseTimeRFC3339(dbFeedback.CreatedAt),
+		UpdatedAt: utils.ParseTimeRFC3339(dbFeedback.UpdatedAt),
+	}
+}
+
+// MessageFeedbackToDB converts domain MessageFeedback entity to SQLC MessageFeedback model.
+func MessageFeedbackToDB(feedback *entity.MessageFeedback) *dbmodel.MessageFeedback {
+	if feedback == nil {
+		return nil
+	}
+
+	return &dbmodel.MessageFeedback{
+		ID:        feedback.ID,
+		MessageID: feedback.MessageID.String(),
+		SessionID: feedback.SessionID.String(),
+		UserID:    feedback.UserID.String(),
+		Rating:    string(feedback.Rating),
+		Comment:   feedback.Comment,
+		Day:       feedback.Day,
+		CreatedAt: utils.FormatTimeRFC3339(feedback.CreatedAt),
+		UpdatedAt: utils.FormatTimeRFC3339(feedback.UpdatedAt),
+	}
+}
+
+// MessageFeedbackCountToDomain converts a SQLC feedback count row to an AnalyticsFeedback counter.
+func MessageFeedbackCountToDomain(row *dbmodel.CountMessageFeedbackRow) *entity.AnalyticsCounter {
+	if row == nil {
+		return nil
+	}
+
+	return &entity.AnalyticsCounter{
+		Day:       row.Day,
+		Metric:    entity.AnalyticsFeedback,
+		Dimension: row.Rating,
+		Value:     float64(row.Count),
+	}
+}
@@ -0,0 +1,62 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageFeedbackToDomain(t *testing.T) {
+	dbFeedback := &dbmodel.MessageFeedback{
+		ID:        "feedback-1",
+		MessageID: "msg-1",
+		SessionID: "session-1",
+		UserID:    "user-1",
+		Rating:    "down",
+		Comment:   "Wrong date",
+		Day:       "2024-01-15",
+		CreatedAt: "2024-01-15T09:00:00Z",
+		UpdatedAt: "2024-01-15T09:05:00Z",
+	}
+
+	result := MessageFeedbackToDomain(dbFeedback)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.MessageFeedback{
+		ID:        "feedback-1",
+		MessageID: "msg-1",
+		SessionID: "session-1",
+		UserID:    "user-1",
+		Rating:    entity.FeedbackDown,
+		Comment:   "Wrong date",
+		Day:       "2024-01-15",
+		CreatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, time.January, 15, 9, 5, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, MessageFeedbackToDomain(nil))
+}
+
+func TestMessageFeedbackToDB_RoundTrip(t *testing.T) {
+	feedback := entity.NewMessageFeedback("msg-1", "session-1", "user-1", entity.FeedbackUp, "")
+
+	dbFeedback := MessageFeedbackToDB(feedback)
+	require.NotNil(t, dbFeedback)
+	assert.Equal(t, "up", dbFeedback.Rating)
+
+	result := MessageFeedbackToDomain(dbFeedback)
+	assert.Equal(t, feedback.ID, result.ID)
+	assert.Equal(t, feedback.Day, result.Day)
+	assert.WithinDuration(t, feedback.UpdatedAt, result.UpdatedAt, time.Second)
+	assert.Nil(t, MessageFeedbackToDB(nil))
+}
+
+func TestMessageFeedbackCountToDomain(t *testing.T) {
+	result := MessageFeedbackCountToDomain(&dbmodel.CountMessageFeedbackRow{Day: "2024-01-15", Rating: "up", Count: 3})
+
+	assert.Equal(t, &entity.AnalyticsCounter{Day: "2024-01-15", Metric: entity.AnalyticsFeedback, Dimension: "up", Value: 3}, result)
+	assert.Nil(t, MessageFeedbackCountToDomain(nil))
+}
@@ -0,0 +1,52 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// MessageJobToDomain converts SQLC MessageJob model to domain MessageJob entity.
+func MessageJobToDomain(dbJob *dbmodel.MessageJob) *entity.MessageJob {
+	if dbJob == nil {
+		return nil
+	}
+
+	return &entity.MessageJob{
+		ID:         dbJob.ID,
+		SessionID:  valueobject.SessionID(dbJob.SessionID),
+		Content:    dbJob.Content,
+		Status:     entity.MessageJobStatus(dbJob.Status),
+		MessageID:  valueobject.MessageID(dbJob.MessageID),
+		Reply:      dbJob.Reply,
+		Error:      dbJob.Error,
+		CreatedAt:  utils.ParseTimeRFC3339(dbJob.CreatedAt),
+		UpdatedAt:  utils.ParseTimeRFC3339(dbJob.UpdatedAt),
+		FinishedAt: nullTimeToDomain(dbJob.FinishedAt),
+		BatchID:    dbJob.BatchID,
+		BatchIndex: int(dbJob.BatchIndex),
+	}
+}
+
+// MessageJobToDB converts domain MessageJob entity to SQLC MessageJob model.
+func MessageJobToDB(job *entity.MessageJob) *dbmodel.MessageJob {
+	if job == nil {
+		return nil
+	}
+
+	return &dbmodel.MessageJob{
+		ID:         job.ID,
+		SessionID:  job.SessionID.String(),
+		Content:    job.Content,
+		Status:     string(job.Status),
+		MessageID:  job.MessageID.String(),
+		Reply:      job.Reply,
+		Error:      job.Error,
+		CreatedAt:  utils.FormatTimeRFC3339(job.CreatedAt),
+		UpdatedAt:  utils.FormatTimeRFC3339(job.UpdatedAt),
+		FinishedAt: nullTimeToDB(job.FinishedAt),
+		BatchID:    job.BatchID,
+		BatchIndex: int64(job.BatchIndex),
+	}
+}
@@ -0,0 +1,47 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageJob_RoundTrip(t *testing.T) {
+	created := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	finished := created.Add(30 * time.Second)
+	job := &entity.MessageJob{
+		ID:         "job-1",
+		SessionID:  "session-1",
+		Content:    "Hello",
+		Status:     entity.MessageJobCompleted,
+		MessageID:  "message-1",
+		Reply:      "Hi there",
+		CreatedAt:  created,
+		UpdatedAt:  finished,
+		FinishedAt: &finished,
+		BatchID:    "batch-1",
+		BatchIndex: 2,
+	}
+
+	dbJob := MessageJobToDB(job)
+	require.NotNil(t, dbJob)
+	assert.Equal(t, "completed", dbJob.Status)
+	assert.True(t, dbJob.FinishedAt.Valid)
+	assert.Equal(t, "2024-01-15T09:00:30Z", dbJob.FinishedAt.String)
+	assert.Equal(t, int64(2), dbJob.BatchIndex)
+
+	assert.Equal(t, job, MessageJobToDomain(dbJob))
+
+	// Unfinished jobs have no finish time
+	job.Status = entity.MessageJobPending
+	job.FinishedAt = nil
+	dbJob = MessageJobToDB(job)
+	assert.False(t, dbJob.FinishedAt.Valid)
+	assert.Nil(t, MessageJobToDomain(dbJob).FinishedAt)
+
+	assert.Nil(t, MessageJobToDB(nil))
+	assert.Nil(t, MessageJobToDomain(nil))
+}
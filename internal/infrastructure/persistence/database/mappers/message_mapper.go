@@ -16,7 +16,7 @@ func MessageToDomain(dbMessage *dbmodel.Message) *entity.Message {
 	return &entity.Message{
 		ID:        valueobject.MessageID(dbMessage.ID),
 		SessionID: valueobject.MustNewSessionID(dbMessage.SessionID),
-		Role:      valueobject.MustNewMessageRole(dbMessage.Role),
+		Role:      dbMessage.Role,
 		Content:   dbMessage.Content,
 		CreatedAt: utils.ParseTimeRFC3339(dbMessage.CreatedAt),
 	}
@@ -31,7 +31,7 @@ func MessageToDB(message *entity.Message) *dbmodel.Message {
 	return &dbmodel.Message{
 		ID:        string(message.ID),
 		SessionID: string(message.SessionID),
-		Role:      string(message.Role),
+		Role:      message.Role,
 		Content:   message.Content,
 		CreatedAt: utils.FormatTimeRFC3339(message.CreatedAt),
 	}
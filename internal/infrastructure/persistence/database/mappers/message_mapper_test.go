@@ -208,7 +208,7 @@ func TestMessagesToDomain(t *testing.T) {
 			assert.Len(t, result, tt.expectedLen)
 			for i, msg := range result {
 				assert.Equal(t, tt.dbMessages[i].ID, string(msg.ID))
-				assert.Equal(t, tt.dbMessages[i].Role, string(msg.Role))
+				assert.Equal(t, tt.dbMessages[i].Role, msg.Role)
 				assert.Equal(t, tt.dbMessages[i].Content, msg.Content)
 			}
 		})
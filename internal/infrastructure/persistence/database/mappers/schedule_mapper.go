@@ -1,6 +1,9 @@
 package mappers
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
@@ -14,12 +17,21 @@ func ScheduleToDomain(dbSchedule *dbmodel.Schedule) *entity.Schedule {
 	}
 
 	return &entity.Schedule{
-		ID:             valueobject.ScheduleID(dbSchedule.ID),
-		Skill:          dbSchedule.Skill,
-		CronExpression: valueobject.MustNewCronExpression(dbSchedule.CronExpression),
-		Input:          dbSchedule.Input,
-		Enabled:        dbSchedule.Enabled == 1,
-		CreatedAt:      utils.ParseTimeRFC3339(dbSchedule.CreatedAt),
+		ID:              valueobject.ScheduleID(dbSchedule.ID),
+		Skill:           dbSchedule.Skill.String,
+		CronExpression:  dbSchedule.CronExpression,
+		Input:           dbSchedule.Input,
+		Enabled:         dbSchedule.Enabled == 1,
+		Timezone:        timezoneToDomain(dbSchedule.Timezone),
+		JitterSeconds:   int(dbSchedule.JitterSeconds),
+		CreatedAt:       utils.ParseTimeRFC3339(dbSchedule.CreatedAt),
+		Type:            scheduleTypeToDomain(dbSchedule.ScheduleType),
+		RunAt:           nullTimeToDomain(dbSchedule.RunAt),
+		MissedRunPolicy: missedRunPolicyToDomain(dbSchedule.MissedRunPolicy),
+		TargetConnector: dbSchedule.TargetConnector,
+		TargetUserID:    dbSchedule.TargetUserID,
+		Prompt:          dbSchedule.Prompt,
+		SessionID:       dbSchedule.SessionID,
 	}
 }
 
@@ -35,12 +47,21 @@ func ScheduleToDB(schedule *entity.Schedule) *dbmodel.Schedule {
 	}
 
 	return &dbmodel.Schedule{
-		ID:             string(schedule.ID),
-		Skill:          schedule.Skill,
-		CronExpression: string(schedule.CronExpression),
-		Input:          schedule.Input,
-		Enabled:        enabled,
-		CreatedAt:      utils.FormatTimeRFC3339(schedule.CreatedAt),
+		ID:              string(schedule.ID),
+		Skill:           skillToDB(schedule.Skill),
+		CronExpression:  schedule.CronExpression,
+		Input:           schedule.Input,
+		Enabled:         enabled,
+		Timezone:        timezoneToDB(schedule.Timezone),
+		JitterSeconds:   int64(schedule.JitterSeconds),
+		CreatedAt:       utils.FormatTimeRFC3339(schedule.CreatedAt),
+		ScheduleType:    scheduleTypeToDB(schedule.Type),
+		RunAt:           nullTimeToDB(schedule.RunAt),
+		MissedRunPolicy: missedRunPolicyToDB(schedule.MissedRunPolicy),
+		TargetConnector: schedule.TargetConnector,
+		TargetUserID:    schedule.TargetUserID,
+		Prompt:          schedule.Prompt,
+		SessionID:       schedule.SessionID,
 	}
 }
 
@@ -52,3 +73,74 @@ func SchedulesToDomain(dbSchedules []dbmodel.Schedule) []*entity.Schedule {
 	}
 	return schedules
 }
+
+// skillToDB converts the skill of a schedule to its stored value; prompt schedules have none,
+// which is stored as NULL so that it does not reference a skill.
+func skillToDB(skill string) sql.NullString {
+	return sql.NullString{String: skill, Valid: skill != ""}
+}
+
+// timezoneToDomain converts a stored timezone name, falling back to UTC for empty values.
+func timezoneToDomain(name string) valueobject.Timezone {
+	if name == "" {
+		return valueobject.TimezoneUTC
+	}
+	return valueobject.Timezone(name)
+}
+
+// timezoneToDB converts a domain timezone to its stored name, defaulting to UTC.
+func timezoneToDB(tz valueobject.Timezone) string {
+	if tz.IsEmpty() {
+		return string(valueobject.TimezoneUTC)
+	}
+	return string(tz)
+}
+
+// scheduleTypeToDomain converts a stored schedule type, falling back to cron for empty values.
+func scheduleTypeToDomain(scheduleType string) valueobject.ScheduleType {
+	if scheduleType == "" {
+		return valueobject.ScheduleTypeCron
+	}
+	return valueobject.ScheduleType(scheduleType)
+}
+
+// scheduleTypeToDB converts a domain schedule type to its stored value, defaulting to cron.
+func scheduleTypeToDB(scheduleType valueobject.ScheduleType) string {
+	if scheduleType == "" {
+		return string(valueobject.ScheduleTypeCron)
+	}
+	return string(scheduleType)
+}
+
+// missedRunPolicyToDomain converts a stored missed run policy, falling back to skip for empty values.
+func missedRunPolicyToDomain(policy string) valueobject.MissedRunPolicy {
+	if policy == "" {
+		return valueobject.MissedRunSkip
+	}
+	return valueobject.MissedRunPolicy(policy)
+}
+
+// missedRunPolicyToDB converts a domain missed run policy to its stored value, defaulting to skip.
+func missedRunPolicyToDB(policy valueobject.MissedRunPolicy) string {
+	if policy == "" {
+		return string(valueobject.MissedRunSkip)
+	}
+	return string(policy)
+}
+
+// nullTimeToDomain converts a nullable stored time to a time pointer.
+func nullTimeToDomain(t sql.NullString) *time.Time {
+	if !t.Valid || t.String == "" {
+		return nil
+	}
+	parsed := utils.ParseTimeRFC3339(t.String)
+	return &parsed
+}
+
+// nullTimeToDB converts an optional time to a nullable stored value.
+func nullTimeToDB(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: utils.FormatTimeRFC3339(*t), Valid: true}
+}
@@ -1,6 +1,7 @@
 package mappers
 
 import (
+	"database/sql"
 	"testing"
 	"time"
 
@@ -22,7 +23,7 @@ func TestScheduleToDomain(t *testing.T) {
 			name: "Valid schedule with enabled=true",
 			dbSchedule: &dbmodel.Schedule{
 				ID:             "test-id",
-				Skill:          "test-skill",
+				Skill:          sql.NullString{String: "test-skill", Valid: true},
 				CronExpression: "0 0 * * *",
 				Input:          "test input",
 				Enabled:        1,
@@ -34,6 +35,7 @@ func TestScheduleToDomain(t *testing.T) {
 				CronExpression: valueobject.MustNewCronExpression("0 0 * * *"),
 				Input:          "test input",
 				Enabled:        true,
+				Timezone:       valueobject.TimezoneUTC,
 				CreatedAt:      time.Now(),
 			},
 			expectedNil: false,
@@ -42,10 +44,12 @@ func TestScheduleToDomain(t *testing.T) {
 			name: "Valid schedule with enabled=false",
 			dbSchedule: &dbmodel.Schedule{
 				ID:             "test-id-2",
-				Skill:          "test-skill-2",
+				Skill:          sql.NullString{String: "test-skill-2", Valid: true},
 				CronExpression: "0 1 * * *",
 				Input:          "test input 2",
 				Enabled:        0,
+				Timezone:       "Europe/Moscow",
+				JitterSeconds:  30,
 				CreatedAt:      time.Now().Format(time.RFC3339),
 			},
 			expected: &entity.Schedule{
@@ -54,6 +58,8 @@ func TestScheduleToDomain(t *testing.T) {
 				CronExpression: valueobject.MustNewCronExpression("0 1 * * *"),
 				Input:          "test input 2",
 				Enabled:        false,
+				Timezone:       valueobject.Timezone("Europe/Moscow"),
+				JitterSeconds:  30,
 				CreatedAt:      time.Now(),
 			},
 			expectedNil: false,
@@ -81,6 +87,8 @@ func TestScheduleToDomain(t *testing.T) {
 			assert.Equal(t, tt.expected.CronExpression, result.CronExpression)
 			assert.Equal(t, tt.expected.Input, result.Input)
 			assert.Equal(t, tt.expected.Enabled, result.Enabled)
+			assert.Equal(t, tt.expected.Timezone, result.Timezone)
+			assert.Equal(t, tt.expected.JitterSeconds, result.JitterSeconds)
 			assert.WithinDuration(t, tt.expected.CreatedAt, result.CreatedAt, time.Second)
 		})
 	}
@@ -105,10 +113,11 @@ func TestScheduleToDB(t *testing.T) {
 			},
 			expected: &dbmodel.Schedule{
 				ID:             "test-id",
-				Skill:          "test-skill",
+				Skill:          sql.NullString{String: "test-skill", Valid: true},
 				CronExpression: "0 0 * * *",
 				Input:          "test input",
 				Enabled:        1,
+				Timezone:       "UTC",
 				CreatedAt:      time.Now().Format(time.RFC3339),
 			},
 			expectedNil: false,
@@ -121,14 +130,18 @@ func TestScheduleToDB(t *testing.T) {
 				CronExpression: valueobject.MustNewCronExpression("0 1 * * *"),
 				Input:          "test input 2",
 				Enabled:        false,
+				Timezone:       valueobject.Timezone("America/New_York"),
+				JitterSeconds:  120,
 				CreatedAt:      time.Now(),
 			},
 			expected: &dbmodel.Schedule{
 				ID:             "test-id-2",
-				Skill:          "test-skill-2",
+				Skill:          sql.NullString{String: "test-skill-2", Valid: true},
 				CronExpression: "0 1 * * *",
 				Input:          "test input 2",
 				Enabled:        0,
+				Timezone:       "America/New_York",
+				JitterSeconds:  120,
 				CreatedAt:      time.Now().Format(time.RFC3339),
 			},
 			expectedNil: false,
@@ -156,11 +169,81 @@ func TestScheduleToDB(t *testing.T) {
 			assert.Equal(t, tt.expected.CronExpression, result.CronExpression)
 			assert.Equal(t, tt.expected.Input, result.Input)
 			assert.Equal(t, tt.expected.Enabled, result.Enabled)
+			assert.Equal(t, tt.expected.Timezone, result.Timezone)
+			assert.Equal(t, tt.expected.JitterSeconds, result.JitterSeconds)
 			assert.Equal(t, tt.expected.CreatedAt, result.CreatedAt)
 		})
 	}
 }
 
+func TestScheduleMapper_OneShot(t *testing.T) {
+	runAt := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	schedule := entity.NewOneShotSchedule("reminder", runAt, "{}")
+
+	dbSchedule := ScheduleToDB(schedule)
+	require.NotNil(t, dbSchedule)
+	assert.True(t, dbSchedule.CronExpression.IsEmpty())
+	assert.Equal(t, "once", dbSchedule.ScheduleType)
+	assert.Equal(t, sql.NullString{String: runAt.Format(time.RFC3339), Valid: true}, dbSchedule.RunAt)
+
+	result := ScheduleToDomain(dbSchedule)
+	require.NotNil(t, result)
+	assert.True(t, result.CronExpression.IsEmpty())
+	assert.Equal(t, valueobject.ScheduleTypeOnce, result.Type)
+	require.NotNil(t, result.RunAt)
+	assert.True(t, runAt.Equal(*result.RunAt))
+}
+
+func TestScheduleMapper_LegacyRowDefaultsToCron(t *testing.T) {
+	result := ScheduleToDomain(&dbmodel.Schedule{
+		ID:             "legacy",
+		Skill:          sql.NullString{String: "skill", Valid: true},
+		CronExpression: "0 * * * *",
+		CreatedAt:      time.Now().Format(time.RFC3339),
+	})
+
+	require.NotNil(t, result)
+	assert.Equal(t, valueobject.ScheduleTypeCron, result.Type)
+	assert.Nil(t, result.RunAt)
+
+	dbSchedule := ScheduleToDB(&entity.Schedule{ID: "legacy", CronExpression: "0 * * * *"})
+	assert.Equal(t, "cron", dbSchedule.ScheduleType)
+	assert.False(t, dbSchedule.RunAt.Valid)
+}
+
+func TestScheduleMapper_Target(t *testing.T) {
+	schedule := entity.NewSchedule("digest", "0 8 * * *", "{}")
+	schedule.SetTarget("telegram", "123456")
+
+	dbSchedule := ScheduleToDB(schedule)
+	require.NotNil(t, dbSchedule)
+	assert.Equal(t, "telegram", dbSchedule.TargetConnector)
+	assert.Equal(t, "123456", dbSchedule.TargetUserID)
+
+	result := ScheduleToDomain(dbSchedule)
+	require.NotNil(t, result)
+	assert.True(t, result.HasTarget())
+	assert.Equal(t, "telegram", result.TargetConnector)
+	assert.Equal(t, "123456", result.TargetUserID)
+}
+
+func TestScheduleMapper_Prompt(t *testing.T) {
+	schedule := entity.NewPromptSchedule("Good morning, it is {{.Weekday}}", "0 8 * * *", "{}")
+	schedule.SessionID = "session-1"
+
+	dbSchedule := ScheduleToDB(schedule)
+	require.NotNil(t, dbSchedule)
+	assert.False(t, dbSchedule.Skill.Valid)
+	assert.Equal(t, "Good morning, it is {{.Weekday}}", dbSchedule.Prompt)
+	assert.Equal(t, "session-1", dbSchedule.SessionID)
+
+	result := ScheduleToDomain(dbSchedule)
+	require.NotNil(t, result)
+	assert.True(t, result.IsPrompt())
+	assert.Empty(t, result.Skill)
+	assert.Equal(t, "session-1", result.SessionID)
+}
+
 func TestSchedulesToDomain(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -172,7 +255,7 @@ func TestSchedulesToDomain(t *testing.T) {
 			dbSchedules: []dbmodel.Schedule{
 				{
 					ID:             "sched-1",
-					Skill:          "skill-1",
+					Skill:          sql.NullString{String: "skill-1", Valid: true},
 					CronExpression: "0 0 * * *",
 					Input:          "input-1",
 					Enabled:        1,
@@ -180,7 +263,7 @@ func TestSchedulesToDomain(t *testing.T) {
 				},
 				{
 					ID:             "sched-2",
-					Skill:          "skill-2",
+					Skill:          sql.NullString{String: "skill-2", Valid: true},
 					CronExpression: "0 1 * * *",
 					Input:          "input-2",
 					Enabled:        0,
@@ -188,7 +271,7 @@ func TestSchedulesToDomain(t *testing.T) {
 				},
 				{
 					ID:             "sched-3",
-					Skill:          "skill-3",
+					Skill:          sql.NullString{String: "skill-3", Valid: true},
 					CronExpression: "0 2 * * *",
 					Input:          "input-3",
 					Enabled:        1,
@@ -221,7 +304,7 @@ func TestSchedulesToDomain(t *testing.T) {
 			assert.Len(t, result, tt.expectedLen)
 			for i, schedule := range result {
 				assert.Equal(t, tt.dbSchedules[i].ID, string(schedule.ID))
-				assert.Equal(t, tt.dbSchedules[i].Skill, schedule.Skill)
+				assert.Equal(t, tt.dbSchedules[i].Skill.String, schedule.Skill)
 				assert.Equal(t, tt.dbSchedules[i].Enabled == 1, schedule.Enabled)
 			}
 		})
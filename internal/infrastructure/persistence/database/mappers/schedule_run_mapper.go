@@ -0,0 +1,90 @@
+package mappers
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// ScheduleRunToDomain converts SQLC ScheduleRun model to domain ScheduleRun entity.
+func ScheduleRunToDomain(dbRun *dbmodel.ScheduleRun) *entity.ScheduleRun {
+	if dbRun == nil {
+		return nil
+	}
+
+	var finishedAt *time.Time
+	if dbRun.FinishedAt.Valid && dbRun.FinishedAt.String != "" {
+		t := utils.ParseTimeRFC3339(dbRun.FinishedAt.String)
+		finishedAt = &t
+	}
+
+	output := ""
+	if dbRun.Output.Valid {
+		output = dbRun.Output.String
+	}
+
+	runErr := ""
+	if dbRun.Error.Valid {
+		runErr = dbRun.Error.String
+	}
+
+	return &entity.ScheduleRun{
+		ID:          valueobject.ScheduleRunID(dbRun.ID),
+		ScheduleID:  valueobject.ScheduleID(dbRun.ScheduleID),
+		Status:      dbRun.Status,
+		ScheduledAt: utils.ParseTimeRFC3339(dbRun.ScheduledAt),
+		StartedAt:   utils.ParseTimeRFC3339(dbRun.StartedAt),
+		FinishedAt:  finishedAt,
+		Output:      output,
+		Error:       runErr,
+	}
+}
+
+// ScheduleRunToDB converts domain ScheduleRun entity to SQLC ScheduleRun model.
+func ScheduleRunToDB(run *entity.ScheduleRun) *dbmodel.ScheduleRun {
+	if run == nil {
+		return nil
+	}
+
+	var finishedAt sql.NullString
+	if run.FinishedAt != nil {
+		finishedAt.Valid = true
+		finishedAt.String = utils.FormatTimeRFC3339(*run.FinishedAt)
+	}
+
+	var output sql.NullString
+	if run.Output != "" {
+		output.Valid = true
+		output.String = run.Output
+	}
+
+	var runErr sql.NullString
+	if run.Error != "" {
+		runErr.Valid = true
+		runErr.String = run.Error
+	}
+
+	return &dbmodel.ScheduleRun{
+		ID:          string(run.ID),
+		ScheduleID:  string(run.ScheduleID),
+		Status:      run.Status,
+		ScheduledAt: utils.FormatTimeRFC3339(run.ScheduledAt),
+		StartedAt:   utils.FormatTimeRFC3339(run.StartedAt),
+		FinishedAt:  finishedAt,
+		Output:      output,
+		Error:       runErr,
+	}
+}
+
+// ScheduleRunsToDomain converts slice of SQLC ScheduleRun models to domain ScheduleRun entities.
+func ScheduleRunsToDomain(dbRuns []dbmodel.ScheduleRun) []*entity.ScheduleRun {
+	runs := make([]*entity.ScheduleRun, 0, len(dbRuns))
+	for i := range dbRuns {
+		runs = append(runs, ScheduleRunToDomain(&dbRuns[i]))
+	}
+	return runs
+}
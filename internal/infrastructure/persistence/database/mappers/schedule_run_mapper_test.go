@@ -0,0 +1,105 @@
+package mappers
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleRunToDomain(t *testing.T) {
+	tests := []struct {
+		name        string
+		dbRun       *dbmodel.ScheduleRun
+		expected    *entity.ScheduleRun
+		expectedNil bool
+	}{
+		{
+			name: "Running run",
+			dbRun: &dbmodel.ScheduleRun{
+				ID:          "run-1",
+				ScheduleID:  "schedule-1",
+				Status:      "running",
+				ScheduledAt: "2024-01-15T09:00:00Z",
+				StartedAt:   "2024-01-15T09:00:01Z",
+			},
+			expected: &entity.ScheduleRun{
+				ID:          valueobject.ScheduleRunID("run-1"),
+				ScheduleID:  valueobject.ScheduleID("schedule-1"),
+				Status:      valueobject.TaskStatusRunning,
+				ScheduledAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+				StartedAt:   time.Date(2024, time.January, 15, 9, 0, 1, 0, time.UTC),
+			},
+		},
+		{
+			name: "Failed run",
+			dbRun: &dbmodel.ScheduleRun{
+				ID:          "run-2",
+				ScheduleID:  "schedule-1",
+				Status:      "failed",
+				ScheduledAt: "2024-01-15T10:00:00Z",
+				StartedAt:   "2024-01-15T10:00:00Z",
+				FinishedAt:  sql.NullString{String: "2024-01-15T10:00:05Z", Valid: true},
+				Error:       sql.NullString{String: "boom", Valid: true},
+			},
+			expected: &entity.ScheduleRun{
+				ID:          valueobject.ScheduleRunID("run-2"),
+				ScheduleID:  valueobject.ScheduleID("schedule-1"),
+				Status:      valueobject.TaskStatusFailed,
+				ScheduledAt: time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC),
+				StartedAt:   time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC),
+				Error:       "boom",
+			},
+		},
+		{
+			name:        "Nil input",
+			dbRun:       nil,
+			expectedNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ScheduleRunToDomain(tt.dbRun)
+
+			if tt.expectedNil {
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected.ID, result.ID)
+			assert.Equal(t, tt.expected.ScheduleID, result.ScheduleID)
+			assert.Equal(t, tt.expected.Status, result.Status)
+			assert.True(t, tt.expected.ScheduledAt.Equal(result.ScheduledAt))
+			assert.True(t, tt.expected.StartedAt.Equal(result.StartedAt))
+			assert.Equal(t, tt.expected.Error, result.Error)
+			assert.Equal(t, tt.dbRun.FinishedAt.Valid, result.IsFinished())
+		})
+	}
+}
+
+func TestScheduleRunToDB(t *testing.T) {
+	run := entity.NewScheduleRun("schedule-1", time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC))
+
+	dbRun := ScheduleRunToDB(run)
+	require.NotNil(t, dbRun)
+	assert.Equal(t, valueobject.TaskStatusRunning, dbRun.Status)
+	assert.Equal(t, "2024-01-15T09:00:00Z", dbRun.ScheduledAt)
+	assert.False(t, dbRun.FinishedAt.Valid)
+	assert.False(t, dbRun.Output.Valid)
+
+	run.SetCompleted("done")
+	dbRun = ScheduleRunToDB(run)
+	assert.Equal(t, valueobject.TaskStatusCompleted, dbRun.Status)
+	assert.True(t, dbRun.FinishedAt.Valid)
+	assert.Equal(t, sql.NullString{String: "done", Valid: true}, dbRun.Output)
+	assert.False(t, dbRun.Error.Valid)
+
+	assert.Nil(t, ScheduleRunToDB(nil))
+}
@@ -0,0 +1,64 @@
+package mappers
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// SessionAttributeToDomain converts SQLC SessionAttribute model to domain SessionAttribute entity.
+func SessionAttributeToDomain(dbAttr *dbmodel.SessionAttribute) *entity.SessionAttribute {
+	if dbAttr == nil {
+		return nil
+	}
+
+	var expiresAt *time.Time
+	if dbAttr.ExpiresAt.Valid && dbAttr.ExpiresAt.String != "" {
+		t := utils.ParseTimeRFC3339(dbAttr.ExpiresAt.String)
+		expiresAt = &t
+	}
+
+	return &entity.SessionAttribute{
+		SessionID: valueobject.SessionID(dbAttr.SessionID),
+		Key:       dbAttr.Key,
+		Value:     dbAttr.Value,
+		ExpiresAt: expiresAt,
+		CreatedAt: utils.ParseTimeRFC3339(dbAttr.CreatedAt),
+		UpdatedAt: utils.ParseTimeRFC3339(dbAttr.UpdatedAt),
+	}
+}
+
+// SessionAttributeToDB converts domain SessionAttribute entity to SQLC SessionAttribute model.
+func SessionAttributeToDB(attr *entity.SessionAttribute) *dbmodel.SessionAttribute {
+	if attr == nil {
+		return nil
+	}
+
+	var expiresAt sql.NullString
+	if attr.ExpiresAt != nil {
+		expiresAt.Valid = true
+		expiresAt.String = utils.FormatTimeRFC3339(*attr.ExpiresAt)
+	}
+
+	return &dbmodel.SessionAttribute{
+		SessionID: string(attr.SessionID),
+		Key:       attr.Key,
+		Value:     attr.Value,
+		ExpiresAt: expiresAt,
+		CreatedAt: utils.FormatTimeRFC3339(attr.CreatedAt),
+		UpdatedAt: utils.FormatTimeRFC3339(attr.UpdatedAt),
+	}
+}
+
+// SessionAttributesToDomain converts slice of SQLC SessionAttribute models to domain SessionAttribute entities.
+func SessionAttributesToDomain(dbAttrs []dbmodel.SessionAttribute) []*entity.SessionAttribute {
+	attrs := make([]*entity.SessionAttribute, 0, len(dbAttrs))
+	for i := range dbAttrs {
+		attrs = append(attrs, SessionAttributeToDomain(&dbAttrs[i]))
+	}
+	return attrs
+}
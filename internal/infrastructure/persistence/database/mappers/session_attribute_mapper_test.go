@@ -0,0 +1,89 @@
+package mappers
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAttributeToDomain(t *testing.T) {
+	expiresAt := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		dbAttr   *dbmodel.SessionAttribute
+		expected *entity.SessionAttribute
+	}{
+		{
+			name: "Attribute without expiry",
+			dbAttr: &dbmodel.SessionAttribute{
+				SessionID: "session-1",
+				Key:       "step",
+				Value:     `"confirm"`,
+				CreatedAt: "2024-01-15T09:00:00Z",
+				UpdatedAt: "2024-01-15T09:30:00Z",
+			},
+			expected: &entity.SessionAttribute{
+				SessionID: valueobject.SessionID("session-1"),
+				Key:       "step",
+				Value:     `"confirm"`,
+				CreatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2024, time.January, 15, 9, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Attribute with expiry",
+			dbAttr: &dbmodel.SessionAttribute{
+				SessionID: "session-1",
+				Key:       "otp",
+				Value:     `"123456"`,
+				ExpiresAt: sql.NullString{String: "2024-01-15T10:00:00Z", Valid: true},
+				CreatedAt: "2024-01-15T09:00:00Z",
+				UpdatedAt: "2024-01-15T09:00:00Z",
+			},
+			expected: &entity.SessionAttribute{
+				SessionID: valueobject.SessionID("session-1"),
+				Key:       "otp",
+				Value:     `"123456"`,
+				ExpiresAt: &expiresAt,
+				CreatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SessionAttributeToDomain(tt.dbAttr)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	assert.Nil(t, SessionAttributeToDomain(nil))
+}
+
+func TestSessionAttributeToDB_RoundTrip(t *testing.T) {
+	attr := entity.NewSessionAttribute("session-1", "otp", `"123456"`, time.Hour)
+
+	dbAttr := SessionAttributeToDB(attr)
+	require.NotNil(t, dbAttr)
+	assert.True(t, dbAttr.ExpiresAt.Valid)
+
+	result := SessionAttributeToDomain(dbAttr)
+	assert.Equal(t, attr.SessionID, result.SessionID)
+	assert.Equal(t, attr.Key, result.Key)
+	assert.Equal(t, attr.Value, result.Value)
+	require.NotNil(t, result.ExpiresAt)
+	assert.WithinDuration(t, *attr.ExpiresAt, *result.ExpiresAt, time.Second)
+
+	attr.SetTTL(0)
+	assert.False(t, SessionAttributeToDB(attr).ExpiresAt.Valid)
+	assert.Nil(t, SessionAttributeToDB(nil))
+}
@@ -14,10 +14,13 @@ func SessionToDomain(dbSession *dbmodel.Session) *entity.Session {
 	}
 
 	return &entity.Session{
-		ID:        valueobject.SessionID(dbSession.ID),
-		UserID:    valueobject.MustNewUserID(dbSession.UserID),
-		CreatedAt: utils.ParseTimeRFC3339(dbSession.CreatedAt),
-		UpdatedAt: utils.ParseTimeRFC3339(dbSession.UpdatedAt),
+		ID:          valueobject.SessionID(dbSession.ID),
+		UserID:      valueobject.MustNewUserID(dbSession.UserID),
+		CreatedAt:   utils.ParseTimeRFC3339(dbSession.CreatedAt),
+		UpdatedAt:   utils.ParseTimeRFC3339(dbSession.UpdatedAt),
+		Pinned:      dbSession.Pinned == 1,
+		WorkspaceID: valueobject.WorkspaceID(dbSession.WorkspaceID),
+		Title:       dbSession.Title,
 	}
 }
 
@@ -27,11 +30,19 @@ func SessionToDB(session *entity.Session) *dbmodel.Session {
 		return nil
 	}
 
+	var pinned int64
+	if session.Pinned {
+		pinned = 1
+	}
+
 	return &dbmodel.Session{
-		ID:        string(session.ID),
-		UserID:    string(session.UserID),
-		CreatedAt: utils.FormatTimeRFC3339(session.CreatedAt),
-		UpdatedAt: utils.FormatTimeRFC3339(session.UpdatedAt),
+		ID:          string(session.ID),
+		UserID:      string(session.UserID),
+		CreatedAt:   utils.FormatTimeRFC3339(session.CreatedAt),
+		UpdatedAt:   utils.FormatTimeRFC3339(session.UpdatedAt),
+		Pinned:      pinned,
+		WorkspaceID: string(session.WorkspaceID),
+		Title:       session.Title,
 	}
 }
 
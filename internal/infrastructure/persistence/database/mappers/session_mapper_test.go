@@ -21,16 +21,20 @@ func TestSessionToDomain(t *testing.T) {
 		{
 			name: "Valid session",
 			dbSession: &dbmodel.Session{
-				ID:        "session-id",
-				UserID:    "user-id",
-				CreatedAt: time.Now().Format(time.RFC3339),
-				UpdatedAt: time.Now().Format(time.RFC3339),
+				ID:          "session-id",
+				UserID:      "user-id",
+				CreatedAt:   time.Now().Format(time.RFC3339),
+				UpdatedAt:   time.Now().Format(time.RFC3339),
+				WorkspaceID: "acme",
+				Title:       "Trip to Lisbon",
 			},
 			expected: &entity.Session{
-				ID:        valueobject.SessionID("session-id"),
-				UserID:    valueobject.MustNewUserID("user-id"),
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				ID:          valueobject.SessionID("session-id"),
+				UserID:      valueobject.MustNewUserID("user-id"),
+				CreatedAt:   time.Now(),
+				UpdatedAt:   time.Now(),
+				WorkspaceID: valueobject.WorkspaceID("acme"),
+				Title:       "Trip to Lisbon",
 			},
 			expectedNil: false,
 		},
@@ -70,6 +74,8 @@ func TestSessionToDomain(t *testing.T) {
 			require.NotNil(t, result)
 			assert.Equal(t, tt.expected.ID, result.ID)
 			assert.Equal(t, tt.expected.UserID, result.UserID)
+			assert.Equal(t, tt.expected.WorkspaceID, result.WorkspaceID)
+			assert.Equal(t, tt.expected.Title, result.Title)
 			assert.WithinDuration(t, tt.expected.CreatedAt, result.CreatedAt, time.Second)
 			assert.WithinDuration(t, tt.expected.UpdatedAt, result.UpdatedAt, time.Second)
 		})
@@ -16,11 +16,13 @@ func SkillToDomain(dbSkill *dbmodel.Skill) *entity.Skill {
 	return &entity.Skill{
 		ID:          valueobject.SkillID(dbSkill.ID),
 		Name:        dbSkill.Name,
-		Version:     valueobject.MustNewVersion(dbSkill.Version),
+		Version:     dbSkill.Version,
 		Location:    dbSkill.Location,
 		Permissions: dbSkill.Permissions,
 		Metadata:    dbSkill.Metadata,
 		CreatedAt:   utils.ParseTimeRFC3339(dbSkill.CreatedAt),
+		Disabled:    dbSkill.Disabled == 1,
+		WorkspaceID: valueobject.WorkspaceID(dbSkill.WorkspaceID),
 	}
 }
 
@@ -30,14 +32,21 @@ func SkillToDB(skill *entity.Skill) *dbmodel.Skill {
 		return nil
 	}
 
+	var disabled int64
+	if skill.Disabled {
+		disabled = 1
+	}
+
 	return &dbmodel.Skill{
 		ID:          string(skill.ID),
 		Name:        skill.Name,
-		Version:     string(skill.Version),
+		Version:     skill.Version,
 		Location:    skill.Location,
 		Permissions: skill.Permissions,
 		Metadata:    skill.Metadata,
 		CreatedAt:   utils.FormatTimeRFC3339(skill.CreatedAt),
+		Disabled:    disabled,
+		WorkspaceID: string(skill.WorkspaceID),
 	}
 }
 
@@ -0,0 +1,57 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// SpilledMessageToDomain converts SQLC SpilledMessage model to domain SpilledMessage entity.
+func SpilledMessageToDomain(dbMessage *dbmodel.SpilledMessage) *entity.SpilledMessage {
+	if dbMessage == nil {
+		return nil
+	}
+
+	return &entity.SpilledMessage{
+		ID:        dbMessage.ID,
+		Connector: dbMessage.Connector,
+		UserID:    dbMessage.UserID,
+		ChannelID: dbMessage.ChannelID,
+		Content:   dbMessage.Content,
+		Metadata:  dbMessage.Metadata,
+		Sequence:  dbMessage.Sequence,
+		CreatedAt: utils.ParseTimeRFC3339(dbMessage.CreatedAt),
+	}
+}
+
+// SpilledMessageToDB converts domain SpilledMessage entity to SQLC SpilledMessage model.
+func SpilledMessageToDB(message *entity.SpilledMessage) *dbmodel.SpilledMessage {
+	if message == nil {
+		return nil
+	}
+
+	metadata := message.Metadata
+	if metadata == "" {
+		metadata = "{}"
+	}
+
+	return &dbmodel.SpilledMessage{
+		ID:        message.ID,
+		Connector: message.Connector,
+		UserID:    message.UserID,
+		ChannelID: message.ChannelID,
+		Content:   message.Content,
+		Metadata:  metadata,
+		Sequence:  message.Sequence,
+		CreatedAt: utils.FormatTimeRFC3339(message.CreatedAt),
+	}
+}
+
+// SpilledMessagesToDomain converts slice of SQLC SpilledMessage models to domain SpilledMessage entities.
+func SpilledMessagesToDomain(dbMessages []dbmodel.SpilledMessage) []*entity.SpilledMessage {
+	messages := make([]*entity.SpilledMessage, 0, len(dbMessages))
+	for i := range dbMessages {
+		messages = append(messages, SpilledMessageToDomain(&dbMessages[i]))
+	}
+	return messages
+}
@@ -0,0 +1,56 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpilledMessageToDomain(t *testing.T) {
+	dbMessage := &dbmodel.SpilledMessage{
+		ID:        "spilled-1",
+		Connector: "telegram",
+		UserID:    "tg:42",
+		ChannelID: "100",
+		Content:   "Hello",
+		Metadata:  `{"message_id":7}`,
+		Sequence:  3,
+		CreatedAt: "2024-01-15T09:00:00Z",
+	}
+
+	result := SpilledMessageToDomain(dbMessage)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.SpilledMessage{
+		ID:        "spilled-1",
+		Connector: "telegram",
+		UserID:    "tg:42",
+		ChannelID: "100",
+		Content:   "Hello",
+		Metadata:  `{"message_id":7}`,
+		Sequence:  3,
+		CreatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, SpilledMessageToDomain(nil))
+}
+
+func TestSpilledMessageToDB_RoundTrip(t *testing.T) {
+	message := entity.NewSpilledMessage("telegram", "tg:42", "100", "Hello", map[string]interface{}{"chat_id": 100}, 5)
+
+	dbMessage := SpilledMessageToDB(message)
+	require.NotNil(t, dbMessage)
+	assert.Equal(t, int64(5), dbMessage.Sequence)
+
+	result := SpilledMessageToDomain(dbMessage)
+	assert.Equal(t, message.ID, result.ID)
+	assert.Equal(t, message.Metadata, result.Metadata)
+	assert.WithinDuration(t, message.CreatedAt, result.CreatedAt, time.Second)
+
+	message.Metadata = ""
+	assert.Equal(t, "{}", SpilledMessageToDB(message).Metadata)
+	assert.Nil(t, SpilledMessageToDB(nil))
+}
@@ -0,0 +1,58 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// TaskAttemptToDomain converts SQLC TaskAttempt model to domain TaskAttempt entity.
+func TaskAttemptToDomain(dbAttempt *dbmodel.TaskAttempt) *entity.TaskAttempt {
+	if dbAttempt == nil {
+		return nil
+	}
+
+	return &entity.TaskAttempt{
+		ID:         dbAttempt.ID,
+		TaskID:     valueobject.TaskID(dbAttempt.TaskID),
+		Attempt:    int(dbAttempt.Attempt),
+		Status:     dbAttempt.Status,
+		Error:      dbAttempt.Error,
+		Retryable:  dbAttempt.Retryable == 1,
+		StartedAt:  utils.ParseTimeRFC3339(dbAttempt.StartedAt),
+		FinishedAt: utils.ParseTimeRFC3339(dbAttempt.FinishedAt),
+	}
+}
+
+// TaskAttemptToDB converts domain TaskAttempt entity to SQLC TaskAttempt model.
+func TaskAttemptToDB(attempt *entity.TaskAttempt) *dbmodel.TaskAttempt {
+	if attempt == nil {
+		return nil
+	}
+
+	retryable := int64(0)
+	if attempt.Retryable {
+		retryable = 1
+	}
+
+	return &dbmodel.TaskAttempt{
+		ID:         attempt.ID,
+		TaskID:     string(attempt.TaskID),
+		Attempt:    int64(attempt.Attempt),
+		Status:     attempt.Status,
+		Error:      attempt.Error,
+		Retryable:  retryable,
+		StartedAt:  utils.FormatTimeRFC3339(attempt.StartedAt),
+		FinishedAt: utils.FormatTimeRFC3339(attempt.FinishedAt),
+	}
+}
+
+// TaskAttemptsToDomain converts slice of SQLC TaskAttempt models to domain TaskAttempt entities.
+func TaskAttemptsToDomain(dbAttempts []dbmodel.TaskAttempt) []*entity.TaskAttempt {
+	attempts := make([]*entity.TaskAttempt, 0, len(dbAttempts))
+	for i := range dbAttempts {
+		attempts = append(attempts, TaskAttemptToDomain(&dbAttempts[i]))
+	}
+	return attempts
+}
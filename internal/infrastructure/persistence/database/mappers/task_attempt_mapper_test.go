@@ -0,0 +1,57 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskAttemptToDomain(t *testing.T) {
+	dbAttempt := &dbmodel.TaskAttempt{
+		ID:         "attempt-1",
+		TaskID:     "task-1",
+		Attempt:    2,
+		Status:     valueobject.TaskStatusFailed,
+		Error:      "skill timed out",
+		Retryable:  1,
+		StartedAt:  "2024-01-15T09:00:00Z",
+		FinishedAt: "2024-01-15T09:00:30Z",
+	}
+
+	result := TaskAttemptToDomain(dbAttempt)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.TaskAttempt{
+		ID:         "attempt-1",
+		TaskID:     "task-1",
+		Attempt:    2,
+		Status:     valueobject.TaskStatusFailed,
+		Error:      "skill timed out",
+		Retryable:  true,
+		StartedAt:  time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		FinishedAt: time.Date(2024, time.January, 15, 9, 0, 30, 0, time.UTC),
+	}, result)
+	assert.Nil(t, TaskAttemptToDomain(nil))
+}
+
+func TestTaskAttemptToDB_RoundTrip(t *testing.T) {
+	attempt := entity.NewTaskAttempt("task-1", 1)
+	attempt.Finish(valueobject.TaskStatusCompleted, "", false)
+
+	dbAttempt := TaskAttemptToDB(attempt)
+	require.NotNil(t, dbAttempt)
+	assert.Equal(t, int64(0), dbAttempt.Retryable)
+	assert.Equal(t, int64(1), dbAttempt.Attempt)
+
+	result := TaskAttemptToDomain(dbAttempt)
+	assert.Equal(t, attempt.ID, result.ID)
+	assert.Equal(t, attempt.Status, result.Status)
+	assert.False(t, result.Retryable)
+	assert.WithinDuration(t, attempt.FinishedAt, result.FinishedAt, time.Second)
+	assert.Nil(t, TaskAttemptToDB(nil))
+}
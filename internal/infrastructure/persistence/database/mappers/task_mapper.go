@@ -26,15 +26,17 @@ func TaskToDomain(dbTask *dbmodel.Task) *entity.Task {
 	}
 
 	return &entity.Task{
-		ID:        valueobject.TaskID(dbTask.ID),
-		SessionID: valueobject.MustNewSessionID(dbTask.SessionID),
-		Skill:     dbTask.Skill,
-		Input:     dbTask.Input,
-		Output:    output,
-		Status:    valueobject.MustNewTaskStatus(dbTask.Status),
-		Error:     taskErr,
-		CreatedAt: utils.ParseTimeRFC3339(dbTask.CreatedAt),
-		UpdatedAt: utils.ParseTimeRFC3339(dbTask.UpdatedAt),
+		ID:         valueobject.TaskID(dbTask.ID),
+		SessionID:  valueobject.MustNewSessionID(dbTask.SessionID),
+		Skill:      dbTask.Skill,
+		Input:      dbTask.Input,
+		Output:     output,
+		Status:     dbTask.Status,
+		Error:      taskErr,
+		CreatedAt:  utils.ParseTimeRFC3339(dbTask.CreatedAt),
+		UpdatedAt:  utils.ParseTimeRFC3339(dbTask.UpdatedAt),
+		StartedAt:  nullTimeToDomain(dbTask.StartedAt),
+		FinishedAt: nullTimeToDomain(dbTask.FinishedAt),
 	}
 }
 
@@ -57,15 +59,17 @@ func TaskToDB(task *entity.Task) *dbmodel.Task {
 	}
 
 	return &dbmodel.Task{
-		ID:        string(task.ID),
-		SessionID: string(task.SessionID),
-		Skill:     task.Skill,
-		Input:     task.Input,
-		Output:    output,
-		Status:    string(task.Status),
-		Error:     taskErr,
-		CreatedAt: utils.FormatTimeRFC3339(task.CreatedAt),
-		UpdatedAt: utils.FormatTimeRFC3339(task.UpdatedAt),
+		ID:         string(task.ID),
+		SessionID:  string(task.SessionID),
+		Skill:      task.Skill,
+		Input:      task.Input,
+		Output:     output,
+		Status:     task.Status,
+		Error:      taskErr,
+		CreatedAt:  utils.FormatTimeRFC3339(task.CreatedAt),
+		UpdatedAt:  utils.FormatTimeRFC3339(task.UpdatedAt),
+		StartedAt:  nullTimeToDB(task.StartedAt),
+		FinishedAt: nullTimeToDB(task.FinishedAt),
 	}
 }
 
@@ -321,8 +321,28 @@ func TestTasksToDomain(t *testing.T) {
 				assert.Equal(t, tt.dbTasks[i].ID, string(task.ID))
 				assert.Equal(t, tt.dbTasks[i].SessionID, string(task.SessionID))
 				assert.Equal(t, tt.dbTasks[i].Skill, task.Skill)
-				assert.Equal(t, tt.dbTasks[i].Status, string(task.Status))
+				assert.Equal(t, tt.dbTasks[i].Status, task.Status)
 			}
 		})
 	}
 }
+
+func TestTaskMapper_TransitionTimestamps(t *testing.T) {
+	task := entity.NewTask("session-1", "skill", "{}")
+	dbTask := TaskToDB(task)
+	assert.False(t, dbTask.StartedAt.Valid)
+	assert.False(t, dbTask.FinishedAt.Valid)
+	assert.Nil(t, TaskToDomain(dbTask).StartedAt)
+
+	require.NoError(t, task.Start())
+	require.NoError(t, task.Complete("{}"))
+	dbTask = TaskToDB(task)
+	require.True(t, dbTask.StartedAt.Valid)
+	require.True(t, dbTask.FinishedAt.Valid)
+
+	result := TaskToDomain(dbTask)
+	require.NotNil(t, result.StartedAt)
+	require.NotNil(t, result.FinishedAt)
+	assert.WithinDuration(t, *task.StartedAt, *result.StartedAt, time.Second)
+	assert.WithinDuration(t, *task.FinishedAt, *result.FinishedAt, time.Second)
+}
@@ -0,0 +1,66 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// UserDataErasureToDomain converts SQLC UserDataErasure model to domain UserDataErasure entity.
+func UserDataErasureToDomain(dbErasure *dbmodel.UserDataErasure) *entity.UserDataErasure {
+	if dbErasure == nil {
+		return nil
+	}
+
+	return &entity.UserDataErasure{
+		ID:          dbErasure.ID,
+		UserID:      dbErasure.UserID,
+		RequestedBy: dbErasure.RequestedBy,
+		Reason:      dbErasure.Reason,
+		Sessions:    int(dbErasure.Sessions),
+		Messages:    int(dbErasure.Messages),
+		Tasks:       int(dbErasure.Tasks),
+		Attributes:  int(dbErasure.Attributes),
+		Preferences: dbErasure.Preferences == 1,
+		Verified:    dbErasure.Verified == 1,
+		CreatedAt:   utils.ParseTimeRFC3339(dbErasure.CreatedAt),
+	}
+}
+
+// UserDataErasureToDB converts domain UserDataErasure entity to SQLC UserDataErasure model.
+func UserDataErasureToDB(erasure *entity.UserDataErasure) *dbmodel.UserDataErasure {
+	if erasure == nil {
+		return nil
+	}
+
+	var preferences, verified int64
+	if erasure.Preferences {
+		preferences = 1
+	}
+	if erasure.Verified {
+		verified = 1
+	}
+
+	return &dbmodel.UserDataErasure{
+		ID:          erasure.ID,
+		UserID:      erasure.UserID,
+		RequestedBy: erasure.RequestedBy,
+		Reason:      erasure.Reason,
+		Sessions:    int64(erasure.Sessions),
+		Messages:    int64(erasure.Messages),
+		Tasks:       int64(erasure.Tasks),
+		Attributes:  int64(erasure.Attributes),
+		Preferences: preferences,
+		Verified:    verified,
+		CreatedAt:   utils.FormatTimeRFC3339(erasure.CreatedAt),
+	}
+}
+
+// UserDataErasuresToDomain converts slice of SQLC UserDataErasure models to domain UserDataErasure entities.
+func UserDataErasuresToDomain(dbErasures []dbmodel.UserDataErasure) []*entity.UserDataErasure {
+	erasures := make([]*entity.UserDataErasure, 0, len(dbErasures))
+	for i := range dbErasures {
+		erasures = append(erasures, UserDataErasureToDomain(&dbErasures[i]))
+	}
+	return erasures
+}
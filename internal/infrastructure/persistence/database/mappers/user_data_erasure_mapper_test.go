@@ -0,0 +1,63 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserDataErasureToDomain(t *testing.T) {
+	dbErasure := &dbmodel.UserDataErasure{
+		ID:          "erasure-1",
+		UserID:      "user-1",
+		RequestedBy: "key-hash",
+		Reason:      "ticket 42",
+		Sessions:    2,
+		Messages:    10,
+		Tasks:       1,
+		Attributes:  3,
+		Preferences: 1,
+		Verified:    1,
+		CreatedAt:   "2024-01-15T09:00:00Z",
+	}
+
+	result := UserDataErasureToDomain(dbErasure)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.UserDataErasure{
+		ID:          "erasure-1",
+		UserID:      "user-1",
+		RequestedBy: "key-hash",
+		Reason:      "ticket 42",
+		Sessions:    2,
+		Messages:    10,
+		Tasks:       1,
+		Attributes:  3,
+		Preferences: true,
+		Verified:    true,
+		CreatedAt:   time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, UserDataErasureToDomain(nil))
+}
+
+func TestUserDataErasureToDB_RoundTrip(t *testing.T) {
+	erasure := entity.NewUserDataErasure("user-1", "", "")
+	erasure.Messages = 4
+	erasure.Verified = true
+
+	dbErasure := UserDataErasureToDB(erasure)
+	require.NotNil(t, dbErasure)
+	assert.Equal(t, int64(4), dbErasure.Messages)
+	assert.Equal(t, int64(0), dbErasure.Preferences)
+	assert.Equal(t, int64(1), dbErasure.Verified)
+
+	result := UserDataErasureToDomain(dbErasure)
+	assert.Equal(t, erasure.ID, result.ID)
+	assert.True(t, result.Verified)
+	assert.WithinDuration(t, erasure.CreatedAt, result.CreatedAt, time.Second)
+	assert.Nil(t, UserDataErasureToDB(nil))
+}
@@ -15,7 +15,7 @@ func UserToDomain(dbUser *dbmodel.User) *entity.User {
 
 	return &entity.User{
 		ID:        valueobject.UserID(dbUser.ID),
-		Channel:   valueobject.MustNewChannel(dbUser.Channel),
+		Channel:   dbUser.Channel,
 		ChannelID: dbUser.ChannelUserID,
 		CreatedAt: utils.ParseTimeRFC3339(dbUser.CreatedAt),
 	}
@@ -29,7 +29,7 @@ func UserToDB(user *entity.User) *dbmodel.User {
 
 	return &dbmodel.User{
 		ID:            string(user.ID),
-		Channel:       string(user.Channel),
+		Channel:       user.Channel,
 		ChannelUserID: user.ChannelID,
 		CreatedAt:     utils.FormatTimeRFC3339(user.CreatedAt),
 	}
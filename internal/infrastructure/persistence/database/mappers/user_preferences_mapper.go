@@ -0,0 +1,42 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// UserPreferencesToDomain converts SQLC UserPreference model to domain UserPreferences entity.
+func UserPreferencesToDomain(dbPrefs *dbmodel.UserPreference) *entity.UserPreferences {
+	if dbPrefs == nil {
+		return nil
+	}
+
+	return &entity.UserPreferences{
+		UserID:       valueobject.UserID(dbPrefs.UserID),
+		Language:     dbPrefs.Language,
+		Model:        dbPrefs.Model,
+		Timezone:     dbPrefs.Timezone,
+		Verbosity:    entity.Verbosity(dbPrefs.Verbosity),
+		UpdatedAt:    utils.ParseTimeRFC3339(dbPrefs.UpdatedAt),
+		Instructions: dbPrefs.Instructions,
+	}
+}
+
+// UserPreferencesToDB converts domain UserPreferences entity to SQLC UserPreference model.
+func UserPreferencesToDB(prefs *entity.UserPreferences) *dbmodel.UserPreference {
+	if prefs == nil {
+		return nil
+	}
+
+	return &dbmodel.UserPreference{
+		UserID:       string(prefs.UserID),
+		Language:     prefs.Language,
+		Model:        prefs.Model,
+		Timezone:     prefs.Timezone,
+		Verbosity:    string(prefs.Verbosity),
+		UpdatedAt:    utils.FormatTimeRFC3339(prefs.UpdatedAt),
+		Instructions: prefs.Instructions,
+	}
+}
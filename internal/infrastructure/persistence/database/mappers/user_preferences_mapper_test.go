@@ -0,0 +1,49 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserPreferencesToDomain(t *testing.T) {
+	dbPrefs := &dbmodel.UserPreference{
+		UserID:    "user-1",
+		Language:  "ru",
+		Model:     "gpt-4o",
+		Timezone:  "Europe/Moscow",
+		Verbosity: "concise",
+		UpdatedAt: "2024-01-15T09:00:00Z",
+	}
+
+	result := UserPreferencesToDomain(dbPrefs)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.UserPreferences{
+		UserID:    valueobject.UserID("user-1"),
+		Language:  "ru",
+		Model:     "gpt-4o",
+		Timezone:  "Europe/Moscow",
+		Verbosity: entity.VerbosityConcise,
+		UpdatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+}
+
+func TestUserPreferencesToDB_RoundTrip(t *testing.T) {
+	prefs := &entity.UserPreferences{
+		UserID:       valueobject.UserID("user-1"),
+		Language:     "de",
+		Verbosity:    entity.VerbosityDetailed,
+		UpdatedAt:    time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		Instructions: "Use metric units.",
+	}
+
+	assert.Equal(t, prefs, UserPreferencesToDomain(UserPreferencesToDB(prefs)))
+	assert.Nil(t, UserPreferencesToDB(nil))
+	assert.Nil(t, UserPreferencesToDomain(nil))
+}
@@ -0,0 +1,58 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// WebhookDeliveryToDomain converts SQLC WebhookDelivery model to domain WebhookDelivery entity.
+func WebhookDeliveryToDomain(dbDelivery *dbmodel.WebhookDelivery) *entity.WebhookDelivery {
+	if dbDelivery == nil {
+		return nil
+	}
+
+	return &entity.WebhookDelivery{
+		ID:             dbDelivery.ID,
+		Endpoint:       dbDelivery.Endpoint,
+		URL:            dbDelivery.URL,
+		EventType:      dbDelivery.EventType,
+		Payload:        dbDelivery.Payload,
+		Status:         dbDelivery.Status,
+		Attempts:       int(dbDelivery.Attempts),
+		ResponseStatus: int(dbDelivery.ResponseStatus),
+		Error:          dbDelivery.Error,
+		CreatedAt:      utils.ParseTimeRFC3339(dbDelivery.CreatedAt),
+		UpdatedAt:      utils.ParseTimeRFC3339(dbDelivery.UpdatedAt),
+	}
+}
+
+// WebhookDeliveryToDB converts domain WebhookDelivery entity to SQLC WebhookDelivery model.
+func WebhookDeliveryToDB(delivery *entity.WebhookDelivery) *dbmodel.WebhookDelivery {
+	if delivery == nil {
+		return nil
+	}
+
+	return &dbmodel.WebhookDelivery{
+		ID:             delivery.ID,
+		Endpoint:       delivery.Endpoint,
+		URL:            delivery.URL,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       int64(delivery.Attempts),
+		ResponseStatus: int64(delivery.ResponseStatus),
+		Error:          delivery.Error,
+		CreatedAt:      utils.FormatTimeRFC3339(delivery.CreatedAt),
+		UpdatedAt:      utils.FormatTimeRFC3339(delivery.UpdatedAt),
+	}
+}
+
+// WebhookDeliveriesToDomain converts slice of SQLC WebhookDelivery models to domain WebhookDelivery entities.
+func WebhookDeliveriesToDomain(dbDeliveries []dbmodel.WebhookDelivery) []*entity.WebhookDelivery {
+	deliveries := make([]*entity.WebhookDelivery, 0, len(dbDeliveries))
+	for i := range dbDeliveries {
+		deliveries = append(deliveries, WebhookDeliveryToDomain(&dbDeliveries[i]))
+	}
+	return deliveries
+}
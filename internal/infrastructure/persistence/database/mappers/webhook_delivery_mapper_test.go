@@ -0,0 +1,63 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryToDomain(t *testing.T) {
+	dbDelivery := &dbmodel.WebhookDelivery{
+		ID:             "delivery-1",
+		Endpoint:       "ci",
+		URL:            "https://example.com/hook",
+		EventType:      "task.completed",
+		Payload:        `{"type":"task.completed"}`,
+		Status:         entity.WebhookDeliveryFailed,
+		Attempts:       3,
+		ResponseStatus: 503,
+		Error:          "unexpected status 503",
+		CreatedAt:      "2024-01-15T09:00:00Z",
+		UpdatedAt:      "2024-01-15T09:00:05Z",
+	}
+
+	result := WebhookDeliveryToDomain(dbDelivery)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.WebhookDelivery{
+		ID:             "delivery-1",
+		Endpoint:       "ci",
+		URL:            "https://example.com/hook",
+		EventType:      "task.completed",
+		Payload:        `{"type":"task.completed"}`,
+		Status:         entity.WebhookDeliveryFailed,
+		Attempts:       3,
+		ResponseStatus: 503,
+		Error:          "unexpected status 503",
+		CreatedAt:      time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2024, time.January, 15, 9, 0, 5, 0, time.UTC),
+	}, result)
+	assert.Nil(t, WebhookDeliveryToDomain(nil))
+}
+
+func TestWebhookDeliveryToDB_RoundTrip(t *testing.T) {
+	delivery := entity.NewWebhookDelivery("ci", "https://example.com/hook", "router.message", `{"type":"router.message"}`)
+	delivery.SetDelivered(204)
+
+	dbDelivery := WebhookDeliveryToDB(delivery)
+	require.NotNil(t, dbDelivery)
+	assert.Equal(t, int64(1), dbDelivery.Attempts)
+	assert.Equal(t, int64(204), dbDelivery.ResponseStatus)
+
+	result := WebhookDeliveryToDomain(dbDelivery)
+	assert.Equal(t, delivery.ID, result.ID)
+	assert.Equal(t, delivery.Status, result.Status)
+	assert.Equal(t, delivery.Payload, result.Payload)
+	assert.WithinDuration(t, delivery.CreatedAt, result.CreatedAt, time.Second)
+
+	assert.Nil(t, WebhookDeliveryToDB(nil))
+}
@@ -0,0 +1,45 @@
+package mappers
+
+import (
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// WorkspaceToDomain converts SQLC Workspace model to domain Workspace entity.
+func WorkspaceToDomain(dbWorkspace *dbmodel.Workspace) *entity.Workspace {
+	if dbWorkspace == nil {
+		return nil
+	}
+
+	return &entity.Workspace{
+		ID:        valueobject.WorkspaceID(dbWorkspace.ID),
+		Name:      dbWorkspace.Name,
+		MaxTokens: int(dbWorkspace.MaxTokens),
+		CreatedAt: utils.ParseTimeRFC3339(dbWorkspace.CreatedAt),
+	}
+}
+
+// WorkspaceToDB converts domain Workspace entity to SQLC Workspace model.
+func WorkspaceToDB(workspace *entity.Workspace) *dbmodel.Workspace {
+	if workspace == nil {
+		return nil
+	}
+
+	return &dbmodel.Workspace{
+		ID:        string(workspace.ID),
+		Name:      workspace.Name,
+		MaxTokens: int64(workspace.MaxTokens),
+		CreatedAt: utils.FormatTimeRFC3339(workspace.CreatedAt),
+	}
+}
+
+// WorkspacesToDomain converts slice of SQLC Workspace models to domain Workspace entities.
+func WorkspacesToDomain(dbWorkspaces []dbmodel.Workspace) []*entity.Workspace {
+	workspaces := make([]*entity.Workspace, 0, len(dbWorkspaces))
+	for i := range dbWorkspaces {
+		workspaces = append(workspaces, WorkspaceToDomain(&dbWorkspaces[i]))
+	}
+	return workspaces
+}
@@ -0,0 +1,47 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+	dbmodel "github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceToDomain(t *testing.T) {
+	dbWorkspace := &dbmodel.Workspace{
+		ID:        "acme",
+		Name:      "Acme Corp",
+		MaxTokens: 2000,
+		CreatedAt: "2024-01-15T09:00:00Z",
+	}
+
+	result := WorkspaceToDomain(dbWorkspace)
+
+	require.NotNil(t, result)
+	assert.Equal(t, &entity.Workspace{
+		ID:        valueobject.WorkspaceID("acme"),
+		Name:      "Acme Corp",
+		MaxTokens: 2000,
+		CreatedAt: time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+	}, result)
+	assert.Nil(t, WorkspaceToDomain(nil))
+}
+
+func TestWorkspaceToDB_RoundTrip(t *testing.T) {
+	workspace := entity.NewWorkspace("acme", "Acme Corp")
+	workspace.MaxTokens = 500
+
+	dbWorkspace := WorkspaceToDB(workspace)
+	require.NotNil(t, dbWorkspace)
+	assert.Equal(t, int64(500), dbWorkspace.MaxTokens)
+
+	result := WorkspaceToDomain(dbWorkspace)
+	assert.Equal(t, workspace.ID, result.ID)
+	assert.Equal(t, workspace.Name, result.Name)
+	assert.WithinDuration(t, workspace.CreatedAt, result.CreatedAt, time.Second)
+	assert.Nil(t, WorkspaceToDB(nil))
+}
@@ -2,162 +2,211 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"github.com/atumaikin/nexflow/migrations"
 )
 
-// Migrate runs database migrations
-func (d *DB) Migrate(ctx context.Context) error {
-	switch d.config.Type {
-	case "sqlite":
-		return d.migrateSQLite(ctx)
-	case "postgres":
-		return d.migratePostgres(ctx)
-	default:
-		return fmt.Errorf("unsupported database type for migration: %s", d.config.Type)
-	}
-}
+// ErrDirtyDatabase is returned when a previous migration failed halfway.
+// The schema has to be fixed manually and the version set with ForceMigrationVersion.
+var ErrDirtyDatabase = errors.New("database is in a dirty migration state")
 
-// migrateSQLite runs migrations for SQLite
-func (d *DB) migrateSQLite(ctx context.Context) error {
-	d.logger.Info("Running SQLite migrations", "path", d.config.MigrationsPath)
+// Compile-time check that DB implements Migration
+var _ Migration = (*DB)(nil)
+
+// MigrationStatus describes the migration state of the database
+type MigrationStatus struct {
+	Version uint // Version of the last applied migration (0 = none)
+	Dirty   bool // Whether the last migration failed halfway
+}
 
-	driver, err := sqlite3.WithInstance(d.db, &sqlite3.Config{})
+// Migrate runs all pending migrations
+func (d *DB) Migrate(ctx context.Context) error {
+	m, err := d.newMigrate()
 	if err != nil {
-		d.logger.Error("Failed to create sqlite migration driver", "error", err)
-		return fmt.Errorf("failed to create sqlite migration driver: %w", err)
+		return err
 	}
 
-	// Create migration instance from file system
-	migrationPath := d.config.MigrationsPath + "/sqlite"
-	d.logger.Debug("Migration path", "path", migrationPath)
-
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrationPath,
-		"sqlite3", driver,
-	)
-	if err != nil {
-		d.logger.Error("Failed to create migration instance", "error", err)
-		return fmt.Errorf("failed to create migration instance: %w", err)
+	if err := d.checkDirty(m); err != nil {
+		return err
 	}
 
-	// Run migrations
-	err = m.Up()
-	if err != nil && err != migrate.ErrNoChange {
+	d.logger.Info("Running migrations", "type", d.config.Type, "source", d.migrationSource())
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		d.logger.Error("Failed to run migrations", "error", err)
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	d.logger.Info("SQLite migrations completed successfully")
+	d.logger.Info("Migrations completed successfully", "type", d.config.Type)
 	return nil
 }
 
-// migratePostgres runs migrations for PostgreSQL
-func (d *DB) migratePostgres(ctx context.Context) error {
-	d.logger.Info("Running PostgreSQL migrations", "path", d.config.MigrationsPath)
-
-	driver, err := postgres.WithInstance(d.db, &postgres.Config{})
+// MigrateTo migrates the database up or down to the given version.
+// Version 0 rolls back all migrations.
+func (d *DB) MigrateTo(ctx context.Context, version uint) error {
+	m, err := d.newMigrate()
 	if err != nil {
-		d.logger.Error("Failed to create postgres migration driver", "error", err)
-		return fmt.Errorf("failed to create postgres migration driver: %w", err)
+		return err
 	}
 
-	// Create migration instance from file system
-	migrationPath := d.config.MigrationsPath + "/postgres"
-	d.logger.Debug("Migration path", "path", migrationPath)
-
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrationPath,
-		"postgres", driver,
-	)
-	if err != nil {
-		d.logger.Error("Failed to create migration instance", "error", err)
-		return fmt.Errorf("failed to create migration instance: %w", err)
+	if err := d.checkDirty(m); err != nil {
+		return err
 	}
 
-	// Run migrations
-	err = m.Up()
-	if err != nil && err != migrate.ErrNoChange {
-		d.logger.Error("Failed to run migrations", "error", err)
-		return fmt.Errorf("failed to run migrations: %w", err)
+	d.logger.Info("Migrating database to version", "type", d.config.Type, "version", version)
+
+	if version == 0 {
+		err = m.Down()
+	} else {
+		err = m.Migrate(version)
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		d.logger.Error("Failed to migrate to version", "version", version, "error", err)
+		return fmt.Errorf("failed to migrate to version %d: %w", version, err)
 	}
 
-	d.logger.Info("PostgreSQL migrations completed successfully")
+	d.logger.Info("Migration to version completed successfully", "version", version)
 	return nil
 }
 
 // Rollback rolls back the last migration
 func (d *DB) Rollback(ctx context.Context) error {
-	switch d.config.Type {
-	case "sqlite":
-		return d.rollbackSQLite(ctx)
-	case "postgres":
-		return d.rollbackPostgres(ctx)
-	default:
-		return fmt.Errorf("unsupported database type for rollback: %s", d.config.Type)
+	m, err := d.newMigrate()
+	if err != nil {
+		return err
+	}
+
+	if err := d.checkDirty(m); err != nil {
+		return err
+	}
+
+	d.logger.Info("Rolling back last migration", "type", d.config.Type)
+
+	if err := m.Steps(-1); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		d.logger.Error("Failed to rollback migration", "error", err)
+		return fmt.Errorf("failed to rollback migration: %w", err)
 	}
+
+	d.logger.Info("Migration rollback completed successfully")
+	return nil
 }
 
-// rollbackSQLite rolls back the last migration for SQLite
-func (d *DB) rollbackSQLite(ctx context.Context) error {
-	d.logger.Info("Rolling back SQLite migrations", "path", d.config.MigrationsPath)
+// MigrationStatus returns the current migration version and dirty state
+func (d *DB) MigrationStatus(ctx context.Context) (MigrationStatus, error) {
+	m, err := d.newMigrate()
+	if err != nil {
+		return MigrationStatus{}, err
+	}
 
-	driver, err := sqlite3.WithInstance(d.db, &sqlite3.Config{})
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return MigrationStatus{}, nil
+	}
 	if err != nil {
-		d.logger.Error("Failed to create sqlite migration driver", "error", err)
-		return fmt.Errorf("failed to create sqlite migration driver: %w", err)
+		return MigrationStatus{}, fmt.Errorf("failed to read migration version: %w", err)
 	}
 
-	migrationPath := d.config.MigrationsPath + "/sqlite"
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrationPath,
-		"sqlite3", driver,
-	)
+	return MigrationStatus{Version: version, Dirty: dirty}, nil
+}
+
+// ForceMigrationVersion sets the migration version without running migrations
+// and clears the dirty state. Use it after fixing a failed migration manually.
+// Version -1 marks the database as having no migrations applied.
+func (d *DB) ForceMigrationVersion(ctx context.Context, version int) error {
+	m, err := d.newMigrate()
 	if err != nil {
-		d.logger.Error("Failed to create migration instance", "error", err)
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return err
 	}
 
-	err = m.Steps(-1)
-	if err != nil && err != migrate.ErrNoChange {
-		d.logger.Error("Failed to rollback migration", "error", err)
-		return fmt.Errorf("failed to rollback migration: %w", err)
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
 	}
 
-	d.logger.Info("SQLite migration rollback completed successfully")
+	d.logger.Info("Migration version forced", "version", version)
 	return nil
 }
 
-// rollbackPostgres rolls back the last migration for PostgreSQL
-func (d *DB) rollbackPostgres(ctx context.Context) error {
-	d.logger.Info("Rolling back PostgreSQL migrations", "path", d.config.MigrationsPath)
+// checkDirty returns ErrDirtyDatabase if the last migration failed halfway
+func (d *DB) checkDirty(m *migrate.Migrate) error {
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if dirty {
+		d.logger.Error("Database is in a dirty migration state", "version", version)
+		return fmt.Errorf("%w: version %d", ErrDirtyDatabase, version)
+	}
+	return nil
+}
+
+// newMigrate creates a migration instance for the configured database type.
+// Migrations are read from MigrationsPath if set, otherwise the embedded files are used.
+// The migration instance is not closed because closing it would close the shared connection.
+func (d *DB) newMigrate() (*migrate.Migrate, error) {
+	var (
+		driver database.Driver
+		name   string
+		err    error
+	)
 
-	driver, err := postgres.WithInstance(d.db, &postgres.Config{})
+	switch d.config.Type {
+	case "sqlite":
+		name = "sqlite3"
+		driver, err = sqlite3.WithInstance(d.db, &sqlite3.Config{})
+	case "postgres":
+		name = "postgres"
+		driver, err = postgres.WithInstance(d.db, &postgres.Config{})
+	default:
+		return nil, fmt.Errorf("unsupported database type for migration: %s", d.config.Type)
+	}
 	if err != nil {
-		d.logger.Error("Failed to create postgres migration driver", "error", err)
-		return fmt.Errorf("failed to create postgres migration driver: %w", err)
+		d.logger.Error("Failed to create migration driver", "type", d.config.Type, "error", err)
+		return nil, fmt.Errorf("failed to create %s migration driver: %w", d.config.Type, err)
 	}
 
-	migrationPath := d.config.MigrationsPath + "/postgres"
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrationPath,
-		"postgres", driver,
-	)
+	var m *migrate.Migrate
+	if d.config.MigrationsPath != "" {
+		m, err = migrate.NewWithDatabaseInstance("file://"+d.migrationSource(), name, driver)
+	} else {
+		m, err = d.newEmbeddedMigrate(name, driver)
+	}
 	if err != nil {
 		d.logger.Error("Failed to create migration instance", "error", err)
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
 	}
 
-	err = m.Steps(-1)
-	if err != nil && err != migrate.ErrNoChange {
-		d.logger.Error("Failed to rollback migration", "error", err)
-		return fmt.Errorf("failed to rollback migration: %w", err)
+	return m, nil
+}
+
+// newEmbeddedMigrate creates a migration instance reading the embedded migration files
+func (d *DB) newEmbeddedMigrate(name string, driver database.Driver) (*migrate.Migrate, error) {
+	dir, err := fs.Sub(migrations.FS, d.config.Type)
+	if err != nil {
+		return nil, err
 	}
 
-	d.logger.Info("PostgreSQL migration rollback completed successfully")
-	return nil
+	source, err := iofs.New(dir, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", source, name, driver)
+}
+
+// migrationSource returns a description of where migrations are read from
+func (d *DB) migrationSource() string {
+	if d.config.MigrationsPath == "" {
+		return "embedded"
+	}
+	return d.config.MigrationsPath + "/" + d.config.Type
 }
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -54,7 +55,7 @@ func TestMigrations(t *testing.T) {
 	}
 
 	// Verify tables exist before DB is closed by migrations
-	tables := []string{"users", "sessions", "messages", "tasks", "skills", "schedules", "logs"}
+	tables := []string{"users", "sessions", "messages", "tasks", "skills", "schedules", "schedule_runs", "logs", "messages_fts"}
 	for _, table := range tables {
 		var exists int
 		err := db.QueryRow(`
@@ -69,3 +70,118 @@ func TestMigrations(t *testing.T) {
 		}
 	}
 }
+
+// newMigrationTestDB creates an empty SQLite database for migration tests
+func newMigrationTestDB(t *testing.T, migrationsPath string) (*DB, *sql.DB) {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &DB{
+		Queries: New(db),
+		db:      db,
+		config: &DBConfig{
+			Type:           "sqlite",
+			Path:           dbPath,
+			MigrationsPath: migrationsPath,
+		},
+		logger: logging.NewNoopLogger(),
+	}, db
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+
+	var exists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?`, table).Scan(&exists); err != nil {
+		t.Fatalf("failed to check table %s: %v", table, err)
+	}
+	return exists == 1
+}
+
+func TestMigrations_EmbeddedVersioning(t *testing.T) {
+	testDB, db := newMigrationTestDB(t, "")
+	ctx := context.Background()
+
+	status, err := testDB.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus() error = %v", err)
+	}
+	if status.Version != 0 || status.Dirty {
+		t.Errorf("MigrationStatus() = %+v, want version 0, not dirty", status)
+	}
+
+	if err := testDB.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	status, _ = testDB.MigrationStatus(ctx)
+	if status.Version != 34 {
+		t.Errorf("version after Migrate() = %d, want 34", status.Version)
+	}
+
+	if err := testDB.MigrateTo(ctx, 3); err != nil {
+		t.Fatalf("MigrateTo(3) error = %v", err)
+	}
+	if tableExists(t, db, "schedule_runs") {
+		t.Error("schedule_runs still exists after migrating down to version 3")
+	}
+
+	if err := testDB.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	status, _ = testDB.MigrationStatus(ctx)
+	if status.Version != 2 {
+		t.Errorf("version after Rollback() = %d, want 2", status.Version)
+	}
+
+	if err := testDB.MigrateTo(ctx, 0); err != nil {
+		t.Fatalf("MigrateTo(0) error = %v", err)
+	}
+	if tableExists(t, db, "users") {
+		t.Error("users still exists after rolling back all migrations")
+	}
+
+	if err := testDB.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() after full rollback error = %v", err)
+	}
+	if !tableExists(t, db, "schedule_runs") {
+		t.Error("schedule_runs does not exist after migrating up again")
+	}
+}
+
+func TestMigrations_DirtyState(t *testing.T) {
+	testDB, db := newMigrationTestDB(t, "")
+	ctx := context.Background()
+
+	if err := testDB.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	// Simulate a migration that failed halfway
+	if _, err := db.Exec(`UPDATE schema_migrations SET dirty = 1`); err != nil {
+		t.Fatalf("failed to mark database dirty: %v", err)
+	}
+
+	if err := testDB.Migrate(ctx); !errors.Is(err, ErrDirtyDatabase) {
+		t.Fatalf("Migrate() error = %v, want ErrDirtyDatabase", err)
+	}
+	if err := testDB.MigrateTo(ctx, 1); !errors.Is(err, ErrDirtyDatabase) {
+		t.Errorf("MigrateTo() error = %v, want ErrDirtyDatabase", err)
+	}
+
+	if err := testDB.ForceMigrationVersion(ctx, 34); err != nil {
+		t.Fatalf("ForceMigrationVersion() error = %v", err)
+	}
+	status, _ := testDB.MigrationStatus(ctx)
+	if status.Dirty {
+		t.Error("database still dirty after ForceMigrationVersion()")
+	}
+	if err := testDB.Migrate(ctx); err != nil {
+		t.Errorf("Migrate() after force error = %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 // Option is a function that configures database.
@@ -13,3 +14,10 @@ func WithLogger(logger logging.Logger) Option {
 		db.logger = logger
 	}
 }
+
+// WithTracer sets the tracer recording a span for every query run within a trace.
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(db *DB) {
+		db.tracer = tracer
+	}
+}
@@ -19,8 +19,8 @@ ORDER BY created_at DESC;
 DELETE FROM users WHERE id = ?;
 
 -- name: CreateSession :one
-INSERT INTO sessions (id, user_id, created_at, updated_at)
-VALUES (?, ?, ?, ?)
+INSERT INTO sessions (id, user_id, created_at, updated_at, pinned, workspace_id, title)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetSessionByID :one
@@ -32,15 +32,64 @@ SELECT * FROM sessions
 WHERE user_id = ?
 ORDER BY created_at DESC;
 
+-- Paginated list queries (List*, GetLogsByLevel, GetLogsBySource): empty since, until
+-- and after_created_at disable the filter, limit -1 returns all rows, and rowid breaks
+-- ties between rows created within the same second in insertion order. An empty
+-- workspace_id lists the sessions of all workspaces.
+-- name: ListSessions :many
+SELECT * FROM sessions
+WHERE (sqlc.arg(since) = '' OR created_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR created_at < sqlc.arg(until))
+  AND (sqlc.arg(after_created_at) = '' OR created_at < sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid < (SELECT s.rowid FROM sessions s WHERE s.id = sqlc.arg(after_id))))
+  AND (sqlc.arg(workspace_id) = '' OR workspace_id = sqlc.arg(workspace_id))
+ORDER BY created_at DESC, rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListSessionsByUserID :many
+SELECT * FROM sessions
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(since) = '' OR created_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR created_at < sqlc.arg(until))
+  AND (sqlc.arg(after_created_at) = '' OR created_at < sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid < (SELECT s.rowid FROM sessions s WHERE s.id = sqlc.arg(after_id))))
+  AND (sqlc.arg(workspace_id) = '' OR workspace_id = sqlc.arg(workspace_id))
+ORDER BY created_at DESC, rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
 -- name: UpdateSession :one
 UPDATE sessions
-SET updated_at = ?
+SET updated_at = ?, pinned = ?, title = ?
 WHERE id = ?
 RETURNING *;
 
 -- name: DeleteSession :exec
 DELETE FROM sessions WHERE id = ?;
 
+-- Session attribute reads skip rows whose expires_at is at or before now (RFC3339 UTC)
+-- name: GetSessionAttribute :one
+SELECT * FROM session_attributes
+WHERE session_id = sqlc.arg(session_id) AND key = sqlc.arg(key)
+  AND (expires_at IS NULL OR expires_at > CAST(sqlc.arg(now) AS TEXT));
+
+-- name: ListSessionAttributes :many
+SELECT * FROM session_attributes
+WHERE session_id = sqlc.arg(session_id)
+  AND (expires_at IS NULL OR expires_at > CAST(sqlc.arg(now) AS TEXT))
+ORDER BY key;
+
+-- name: UpsertSessionAttribute :one
+INSERT INTO session_attributes (session_id, key, value, expires_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (session_id, key) DO UPDATE
+SET value = excluded.value, expires_at = excluded.expires_at, updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: DeleteSessionAttribute :execrows
+DELETE FROM session_attributes WHERE session_id = ? AND key = ?;
+
+-- name: DeleteExpiredSessionAttributes :execrows
+DELETE FROM session_attributes
+WHERE expires_at IS NOT NULL AND expires_at <= CAST(sqlc.arg(now) AS TEXT);
+
 -- name: CreateMessage :one
 INSERT INTO messages (id, session_id, role, content, created_at)
 VALUES (?, ?, ?, ?, ?)
@@ -55,12 +104,56 @@ SELECT * FROM messages
 WHERE session_id = ?
 ORDER BY created_at ASC;
 
+-- name: ListMessagesBySessionID :many
+SELECT * FROM messages
+WHERE session_id = sqlc.arg(session_id)
+  AND (sqlc.arg(since) = '' OR created_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR created_at < sqlc.arg(until))
+  AND (sqlc.arg(after_created_at) = '' OR created_at > sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid > (SELECT m.rowid FROM messages m WHERE m.id = sqlc.arg(after_id))))
+ORDER BY created_at ASC, rowid ASC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: SearchMessages :many
+-- Matches content through the messages_fts index; user_id and session_id narrow
+-- the search when set; workspace_id keeps sessions of one workspace.
+SELECT m.* FROM messages m
+JOIN messages_fts ON messages_fts.docid = m.rowid
+WHERE messages_fts MATCH sqlc.arg(query)
+  AND (sqlc.arg(user_id) = '' OR m.session_id IN (SELECT s.id FROM sessions s WHERE s.user_id = sqlc.arg(user_id)))
+  AND (sqlc.arg(session_id) = '' OR m.session_id = sqlc.arg(session_id))
+  AND (sqlc.arg(workspace_id) = '' OR m.session_id IN (SELECT s.id FROM sessions s WHERE s.workspace_id = sqlc.arg(workspace_id)))
+  AND (sqlc.arg(since) = '' OR m.created_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR m.created_at < sqlc.arg(until))
+  AND (sqlc.arg(after_created_at) = '' OR m.created_at < sqlc.arg(after_created_at) OR (m.created_at = sqlc.arg(after_created_at) AND m.rowid < (SELECT c.rowid FROM messages c WHERE c.id = sqlc.arg(after_id))))
+ORDER BY m.created_at DESC, m.rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
 -- name: DeleteMessage :exec
 DELETE FROM messages WHERE id = ?;
 
+-- Retention queries (*OlderThan): rows of pinned sessions are never listed or deleted,
+-- and batches are read oldest first after the (after_created_at, after_id) cursor.
+-- name: CountMessagesOlderThan :one
+SELECT COUNT(*) FROM messages
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1);
+
+-- name: ListMessagesOlderThan :many
+SELECT * FROM messages
+WHERE created_at < sqlc.arg(before)
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+  AND (sqlc.arg(after_created_at) = '' OR created_at > sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid > (SELECT m.rowid FROM messages m WHERE m.id = sqlc.arg(after_id))))
+ORDER BY created_at ASC, rowid ASC
+LIMIT sqlc.arg(limit);
+
+-- name: DeleteMessagesOlderThan :execrows
+DELETE FROM messages
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1);
+
 -- name: CreateTask :one
-INSERT INTO tasks (id, session_id, skill, input, status, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO tasks (id, session_id, skill, input, status, created_at, updated_at, started_at, finished_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetTaskByID :one
@@ -72,18 +165,45 @@ SELECT * FROM tasks
 WHERE session_id = ?
 ORDER BY created_at DESC;
 
+-- name: ListTasksBySessionID :many
+SELECT * FROM tasks
+WHERE session_id = sqlc.arg(session_id)
+  AND (sqlc.arg(since) = '' OR created_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR created_at < sqlc.arg(until))
+  AND (sqlc.arg(after_created_at) = '' OR created_at < sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid < (SELECT t.rowid FROM tasks t WHERE t.id = sqlc.arg(after_id))))
+ORDER BY created_at DESC, rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
 -- name: UpdateTask :one
 UPDATE tasks
-SET output = ?, status = ?, error = ?, updated_at = ?
+SET output = ?, status = ?, error = ?, updated_at = ?, started_at = ?, finished_at = ?
 WHERE id = ?
 RETURNING *;
 
 -- name: DeleteTask :exec
 DELETE FROM tasks WHERE id = ?;
 
+-- name: CountTasksOlderThan :one
+SELECT COUNT(*) FROM tasks
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1);
+
+-- name: ListTasksOlderThan :many
+SELECT * FROM tasks
+WHERE created_at < sqlc.arg(before)
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1)
+  AND (sqlc.arg(after_created_at) = '' OR created_at > sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid > (SELECT t.rowid FROM tasks t WHERE t.id = sqlc.arg(after_id))))
+ORDER BY created_at ASC, rowid ASC
+LIMIT sqlc.arg(limit);
+
+-- name: DeleteTasksOlderThan :execrows
+DELETE FROM tasks
+WHERE created_at < ?
+  AND session_id NOT IN (SELECT s.id FROM sessions s WHERE s.pinned = 1);
+
 -- name: CreateSkill :one
-INSERT INTO skills (id, name, version, location, permissions, metadata, created_at)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO skills (id, name, version, location, permissions, metadata, created_at, disabled, workspace_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetSkillByID :one
@@ -100,7 +220,7 @@ ORDER BY created_at DESC;
 
 -- name: UpdateSkill :one
 UPDATE skills
-SET version = ?, location = ?, permissions = ?, metadata = ?
+SET version = ?, location = ?, permissions = ?, metadata = ?, disabled = ?, workspace_id = ?
 WHERE id = ?
 RETURNING *;
 
@@ -108,8 +228,8 @@ RETURNING *;
 DELETE FROM skills WHERE id = ?;
 
 -- name: CreateSchedule :one
-INSERT INTO schedules (id, skill, cron_expression, input, enabled, created_at)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO schedules (id, skill, cron_expression, input, enabled, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id, prompt, session_id, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetScheduleByID :one
@@ -127,13 +247,36 @@ ORDER BY created_at DESC;
 
 -- name: UpdateSchedule :one
 UPDATE schedules
-SET cron_expression = ?, input = ?, enabled = ?
+SET cron_expression = ?, input = ?, enabled = ?, timezone = ?, jitter_seconds = ?, schedule_type = ?, run_at = ?, missed_run_policy = ?, target_connector = ?, target_user_id = ?, prompt = ?, session_id = ?
 WHERE id = ?
 RETURNING *;
 
 -- name: DeleteSchedule :exec
 DELETE FROM schedules WHERE id = ?;
 
+-- name: CreateScheduleRun :one
+INSERT INTO schedule_runs (id, schedule_id, status, scheduled_at, started_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateScheduleRun :one
+UPDATE schedule_runs
+SET status = ?, finished_at = ?, output = ?, error = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: GetScheduleRunsByScheduleID :many
+SELECT * FROM schedule_runs
+WHERE schedule_id = ?
+ORDER BY scheduled_at DESC
+LIMIT ?;
+
+-- name: GetLatestScheduleRun :one
+SELECT * FROM schedule_runs
+WHERE schedule_id = ?
+ORDER BY scheduled_at DESC
+LIMIT 1;
+
 -- name: CreateLog :one
 INSERT INTO logs (id, level, source, message, metadata, created_at)
 VALUES (?, ?, ?, ?, ?, ?)
@@ -145,15 +288,29 @@ WHERE id = ? LIMIT 1;
 
 -- name: GetLogsByLevel :many
 SELECT * FROM logs
-WHERE level = ?
-ORDER BY created_at DESC
-LIMIT ?;
+WHERE level = sqlc.arg(level)
+  AND (sqlc.arg(since) = '' OR created_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR created_at < sqlc.arg(until))
+  AND (sqlc.arg(after_created_at) = '' OR created_at < sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid < (SELECT l.rowid FROM logs l WHERE l.id = sqlc.arg(after_id))))
+ORDER BY created_at DESC, rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: GetLogsBySource :many
 SELECT * FROM logs
-WHERE source = ?
-ORDER BY created_at DESC
-LIMIT ?;
+WHERE source = sqlc.arg(source)
+  AND (sqlc.arg(since) = '' OR created_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR created_at < sqlc.arg(until))
+  AND (sqlc.arg(after_created_at) = '' OR created_at < sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid < (SELECT l.rowid FROM logs l WHERE l.id = sqlc.arg(after_id))))
+ORDER BY created_at DESC, rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- Logs are listed newest first; an empty level or source selects logs of all levels or sources
+-- name: ListLogs :many
+SELECT * FROM logs
+WHERE (sqlc.arg(level) = '' OR level = sqlc.arg(level))
+  AND (sqlc.arg(source) = '' OR source = sqlc.arg(source))
+ORDER BY created_at DESC, rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: GetLogsByDateRange :many
 SELECT * FROM logs
@@ -164,5 +321,429 @@ LIMIT ?;
 -- name: DeleteLog :exec
 DELETE FROM logs WHERE id = ?;
 
--- name: DeleteLogsOlderThan :exec
+-- name: DeleteLogsOlderThan :execrows
 DELETE FROM logs WHERE created_at < ?;
+
+-- name: CountLogsOlderThan :one
+SELECT COUNT(*) FROM logs
+WHERE created_at < ?;
+
+-- name: ListLogsOlderThan :many
+SELECT * FROM logs
+WHERE created_at < sqlc.arg(before)
+  AND (sqlc.arg(after_created_at) = '' OR created_at > sqlc.arg(after_created_at) OR (created_at = sqlc.arg(after_created_at) AND rowid > (SELECT l.rowid FROM logs l WHERE l.id = sqlc.arg(after_id))))
+ORDER BY created_at ASC, rowid ASC
+LIMIT sqlc.arg(limit);
+
+-- API keys are looked up by the SHA-256 hash of the presented key
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, name, key_hash, prefix, scope, created_at, workspace_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = ?;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+ORDER BY created_at ASC, rowid ASC;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = ?
+WHERE id = ? AND revoked_at IS NULL;
+
+-- Events are replayed in the order they were stored; types is a JSON array of event types, '' for all types
+-- name: CreateEvent :one
+INSERT INTO events (id, type, payload, occurred_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetEvent :one
+SELECT * FROM events WHERE id = ?;
+
+-- name: ListEvents :many
+SELECT * FROM events
+WHERE seq > sqlc.arg(after_seq)
+  AND (sqlc.arg(since) = '' OR occurred_at >= sqlc.arg(since))
+  AND (sqlc.arg(until) = '' OR occurred_at < sqlc.arg(until))
+  AND (sqlc.arg(types) = '' OR type IN (SELECT value FROM json_each(sqlc.arg(types))))
+ORDER BY seq ASC
+LIMIT sqlc.arg(limit);
+
+-- Events about a subject name one of the users or sessions in their payload; user_ids and session_ids are JSON arrays
+-- Users on a channel match the events of the connectors named in channel_users only; router
+-- events name their connector as the source
+-- name: ListEventsBySubject :many
+SELECT * FROM events
+WHERE json_valid(payload)
+  AND (json_extract(payload, '$.user_id') IN (SELECT value FROM json_each(CAST(sqlc.arg(user_ids) AS TEXT)))
+    OR json_extract(payload, '$.session_id') IN (SELECT value FROM json_each(CAST(sqlc.arg(session_ids) AS TEXT)))
+    OR EXISTS (SELECT 1 FROM json_each(CAST(sqlc.arg(channel_users) AS TEXT)) AS channel_user
+      WHERE json_extract(channel_user.value, '$.user_id') = json_extract(payload, '$.user_id')
+        AND json_extract(channel_user.value, '$.connector') = COALESCE(json_extract(payload, '$.connector'), json_extract(payload, '$.source'))))
+ORDER BY seq ASC;
+
+-- name: DeleteEventsBySubject :execrows
+DELETE FROM events
+WHERE json_valid(payload)
+  AND (json_extract(payload, '$.user_id') IN (SELECT value FROM json_each(CAST(sqlc.arg(user_ids) AS TEXT)))
+    OR json_extract(payload, '$.session_id') IN (SELECT value FROM json_each(CAST(sqlc.arg(session_ids) AS TEXT)))
+    OR EXISTS (SELECT 1 FROM json_each(CAST(sqlc.arg(channel_users) AS TEXT)) AS channel_user
+      WHERE json_extract(channel_user.value, '$.user_id') = json_extract(payload, '$.user_id')
+        AND json_extract(channel_user.value, '$.connector') = COALESCE(json_extract(payload, '$.connector'), json_extract(payload, '$.source'))));
+
+-- Webhook deliveries are listed newest first; empty endpoint and status select all deliveries
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (id, endpoint, url, event_type, payload, status, attempts, response_status, error, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = ?, attempts = ?, response_status = ?, error = ?, updated_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE (sqlc.arg(endpoint) = '' OR endpoint = sqlc.arg(endpoint))
+  AND (sqlc.arg(status) = '' OR status = sqlc.arg(status))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- Dead letters are listed newest first; an empty subscription selects all dead letters
+-- name: CreateDeadLetter :one
+INSERT INTO dead_letters (id, subscription, event_type, payload, occurred_at, attempts, error, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetDeadLetter :one
+SELECT * FROM dead_letters WHERE id = ?;
+
+-- name: UpdateDeadLetter :one
+UPDATE dead_letters
+SET attempts = ?, error = ?, updated_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteDeadLetter :execrows
+DELETE FROM dead_letters WHERE id = ?;
+
+-- name: ListDeadLetters :many
+SELECT * FROM dead_letters
+WHERE (sqlc.arg(subscription) = '' OR subscription = sqlc.arg(subscription))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- A processed message is recorded again only once its previous record has expired
+-- name: RecordProcessedMessage :execrows
+INSERT INTO processed_messages (key, expires_at, created_at)
+VALUES (?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET expires_at = excluded.expires_at, created_at = excluded.created_at
+WHERE processed_messages.expires_at <= excluded.created_at;
+
+-- name: DeleteProcessedMessage :exec
+DELETE FROM processed_messages WHERE key = ?;
+
+-- name: DeleteExpiredProcessedMessages :execrows
+DELETE FROM processed_messages
+WHERE expires_at <= CAST(? AS TEXT);
+
+-- Message dead letters are listed newest first; an empty connector or user ID selects all dead letters
+-- name: CreateMessageDeadLetter :one
+INSERT INTO message_dead_letters (id, connector, user_id, channel_id, content, metadata, attempts, error, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetMessageDeadLetter :one
+SELECT * FROM message_dead_letters WHERE id = ?;
+
+-- name: UpdateMessageDeadLetter :one
+UPDATE message_dead_letters
+SET attempts = ?, error = ?, updated_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteMessageDeadLetter :execrows
+DELETE FROM message_dead_letters WHERE id = ?;
+
+-- name: DeleteMessageDeadLettersByUser :execrows
+DELETE FROM message_dead_letters WHERE connector = ? AND user_id = ?;
+
+-- name: ListMessageDeadLetters :many
+SELECT * FROM message_dead_letters
+WHERE (sqlc.arg(connector) = '' OR connector = sqlc.arg(connector))
+  AND (sqlc.arg(user_id) = '' OR user_id = sqlc.arg(user_id))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CreateWorkspace :one
+INSERT INTO workspaces (id, name, max_tokens, created_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetWorkspaceByID :one
+SELECT * FROM workspaces WHERE id = ?;
+
+-- name: ListWorkspaces :many
+SELECT * FROM workspaces
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateWorkspace :one
+UPDATE workspaces
+SET name = ?, max_tokens = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteWorkspace :execrows
+DELETE FROM workspaces WHERE id = ?;
+
+-- name: GetUserPreferences :one
+SELECT * FROM user_preferences WHERE user_id = ?;
+
+-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (user_id, language, model, timezone, verbosity, updated_at, instructions)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET language = excluded.language, model = excluded.model, timezone = excluded.timezone,
+    verbosity = excluded.verbosity, updated_at = excluded.updated_at, instructions = excluded.instructions
+RETURNING *;
+
+-- name: DeleteUserPreferences :execrows
+DELETE FROM user_preferences WHERE user_id = ?;
+
+-- name: IncrementAnalyticsCounter :exec
+INSERT INTO analytics_counters (day, metric, dimension, value)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (day, metric, dimension) DO UPDATE
+SET value = analytics_counters.value + excluded.value;
+
+-- name: AddAnalyticsActiveUser :exec
+INSERT INTO analytics_active_users (day, connector, user_id)
+VALUES (?, ?, ?)
+ON CONFLICT (day, connector, user_id) DO NOTHING;
+
+-- name: ListAnalyticsCounters :many
+SELECT * FROM analytics_counters
+WHERE day >= sqlc.arg(since) AND day <= sqlc.arg(until)
+ORDER BY day, metric, dimension;
+
+-- Distinct active users per day and connector, per day, per connector and over the whole
+-- range; an empty day or connector marks a total
+-- name: CountAnalyticsActiveUsers :many
+SELECT day, connector, COUNT(*) AS users FROM analytics_active_users
+WHERE day >= sqlc.arg(since) AND day <= sqlc.arg(until)
+GROUP BY day, connector
+UNION ALL
+SELECT day, CAST('' AS TEXT) AS connector, COUNT(DISTINCT user_id) AS users FROM analytics_active_users
+WHERE day >= sqlc.arg(since) AND day <= sqlc.arg(until)
+GROUP BY day
+UNION ALL
+SELECT CAST('' AS TEXT) AS day, connector, COUNT(DISTINCT user_id) AS users FROM analytics_active_users
+WHERE day >= sqlc.arg(since) AND day <= sqlc.arg(until)
+GROUP BY connector
+UNION ALL
+SELECT CAST('' AS TEXT) AS day, CAST('' AS TEXT) AS connector, COUNT(DISTINCT user_id) AS users FROM analytics_active_users
+WHERE day >= sqlc.arg(since) AND day <= sqlc.arg(until)
+ORDER BY day, connector;
+
+-- User data erasures are listed newest first; an empty user ID selects all erasures
+-- name: CreateUserDataErasure :one
+INSERT INTO user_data_erasures (id, user_id, requested_by, reason, sessions, messages, tasks, attributes, preferences, verified, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListUserDataErasures :many
+SELECT * FROM user_data_erasures
+WHERE (sqlc.arg(user_id) = '' OR user_id = sqlc.arg(user_id))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- Crash reports are listed newest first; an empty component selects all reports
+-- name: CreateCrashReport :one
+INSERT INTO crash_reports (id, component, operation, panic, stack, attributes, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListCrashReports :many
+SELECT * FROM crash_reports
+WHERE (sqlc.arg(component) = '' OR component = sqlc.arg(component))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- Document chunks are listed in the order the documents were shared, then by position
+-- name: CreateDocumentChunk :exec
+INSERT INTO document_chunks (id, session_id, document_id, document_name, position, content, embedding, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListDocumentChunks :many
+SELECT * FROM document_chunks
+WHERE session_id = ?
+ORDER BY created_at, document_id, position;
+
+-- name: DeleteDocumentChunks :execrows
+DELETE FROM document_chunks WHERE session_id = ?;
+
+-- Rating a message again replaces its earlier feedback
+-- name: UpsertMessageFeedback :one
+INSERT INTO message_feedback (id, message_id, session_id, user_id, rating, comment, day, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (message_id) DO UPDATE
+SET rating = excluded.rating, comment = excluded.comment, day = excluded.day, updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: GetMessageFeedback :one
+SELECT * FROM message_feedback WHERE message_id = ?;
+
+-- name: CountMessageFeedback :many
+SELECT day, rating, COUNT(*) AS count FROM message_feedback
+WHERE day >= sqlc.arg(since) AND day <= sqlc.arg(until)
+GROUP BY day, rating
+ORDER BY day, rating;
+
+-- The ID is the ID of the usage event, so a usage event handled twice is saved once
+-- name: CreateLLMUsage :exec
+INSERT INTO llm_usage (id, user_id, workspace_id, session_id, provider, model, input_tokens, output_tokens, total_tokens, cost, month, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: SumLLMUsage :many
+SELECT month, workspace_id, user_id, provider, model, COUNT(*) AS requests,
+    CAST(SUM(input_tokens) AS INTEGER) AS input_tokens, CAST(SUM(output_tokens) AS INTEGER) AS output_tokens,
+    CAST(SUM(total_tokens) AS INTEGER) AS total_tokens, CAST(SUM(cost) AS REAL) AS cost
+FROM llm_usage
+WHERE month >= sqlc.arg(since) AND month <= sqlc.arg(until)
+GROUP BY month, workspace_id, user_id, provider, model
+ORDER BY month, workspace_id, user_id, provider, model;
+
+-- Idempotency keys are found until they expire; saving keeps an unexpired key
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE key = sqlc.arg(key) AND expires_at > sqlc.arg(now);
+
+-- A pending key (status code 0) is reserved before its request is handled, unless an
+-- unexpired key exists, and replaced by the response of the request
+-- name: ReserveIdempotencyKey :execrows
+INSERT INTO idempotency_keys (key, request_hash, status_code, content_type, body, expires_at, created_at)
+VALUES (?, ?, 0, '', '', ?, ?)
+ON CONFLICT (key) DO UPDATE SET request_hash = excluded.request_hash, status_code = 0,
+    content_type = '', body = '', expires_at = excluded.expires_at, created_at = excluded.created_at
+WHERE idempotency_keys.expires_at <= excluded.created_at;
+
+-- name: SaveIdempotencyKey :execrows
+INSERT INTO idempotency_keys (key, request_hash, status_code, content_type, body, expires_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET request_hash = excluded.request_hash, status_code = excluded.status_code,
+    content_type = excluded.content_type, body = excluded.body, expires_at = excluded.expires_at, created_at = excluded.created_at
+WHERE idempotency_keys.expires_at <= excluded.created_at
+    OR (idempotency_keys.status_code = 0 AND idempotency_keys.request_hash = excluded.request_hash);
+
+-- name: DeletePendingIdempotencyKey :execrows
+DELETE FROM idempotency_keys
+WHERE key = ? AND status_code = 0;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= CAST(? AS TEXT);
+
+-- name: CreateMessageJob :exec
+INSERT INTO message_jobs (id, session_id, content, status, message_id, reply, error, created_at, updated_at, finished_at, batch_id, batch_index)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetMessageJob :one
+SELECT * FROM message_jobs WHERE id = ?;
+
+-- name: ListMessageJobsByBatch :many
+SELECT * FROM message_jobs
+WHERE batch_id = ?
+ORDER BY batch_index ASC;
+
+-- name: UpdateMessageJob :exec
+UPDATE message_jobs
+SET status = ?, message_id = ?, reply = ?, error = ?, updated_at = ?, finished_at = ?
+WHERE id = ?;
+
+-- Jobs left pending or running by a stopped process are failed at startup
+-- name: FailUnfinishedMessageJobs :execrows
+UPDATE message_jobs
+SET status = 'failed', error = ?, updated_at = ?, finished_at = ?
+WHERE status IN ('pending', 'running');
+
+-- name: DeleteFinishedMessageJobs :execrows
+DELETE FROM message_jobs
+WHERE finished_at IS NOT NULL AND finished_at <= CAST(? AS TEXT);
+
+-- Spilled messages are fed back to their connector in sequence order
+-- name: CreateSpilledMessage :exec
+INSERT INTO spilled_messages (id, connector, user_id, channel_id, content, metadata, sequence, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListSpilledMessages :many
+SELECT * FROM spilled_messages
+WHERE connector = ?
+ORDER BY sequence ASC
+LIMIT ?;
+
+-- name: CountSpilledMessages :one
+SELECT COUNT(*) FROM spilled_messages
+WHERE connector = ?;
+
+-- name: DeleteSpilledMessage :execrows
+DELETE FROM spilled_messages WHERE id = ?;
+
+-- Task attempts are recorded once they finish
+-- name: CreateTaskAttempt :exec
+INSERT INTO task_attempts (id, task_id, attempt, status, error, retryable, started_at, finished_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListTaskAttempts :many
+SELECT * FROM task_attempts
+WHERE task_id = ?
+ORDER BY attempt ASC;
+
+-- name: CreateJob :execrows
+INSERT INTO jobs (id, kind, payload, status, attempts, max_attempts, error, run_at, locked_by, locked_until, created_at, updated_at, finished_at, leader_only)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: GetJob :one
+SELECT * FROM jobs WHERE id = ?;
+
+-- Jobs are claimed for a lease; the job due the longest comes first. Leader-only jobs are
+-- claimed by the leader only. The due condition is repeated for the row being updated, so
+-- that a job claimed concurrently by another instance is not claimed twice.
+-- name: ClaimJob :one
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, locked_by = ?, locked_until = ?, updated_at = ?
+WHERE id = (
+    SELECT j.id FROM jobs j
+    WHERE ((j.status = 'pending' AND j.run_at <= CAST(sqlc.arg(now) AS TEXT))
+       OR (j.status = 'running' AND j.locked_until <= CAST(sqlc.arg(now) AS TEXT)))
+      AND (NOT j.leader_only OR CAST(sqlc.arg(leader) AS INTEGER) = 1)
+    ORDER BY j.run_at ASC
+    LIMIT 1
+)
+AND ((status = 'pending' AND run_at <= CAST(sqlc.arg(now) AS TEXT))
+  OR (status = 'running' AND locked_until <= CAST(sqlc.arg(now) AS TEXT)))
+RETURNING *;
+
+-- name: UpdateJob :execrows
+UPDATE jobs
+SET status = ?, error = ?, run_at = ?, locked_by = ?, locked_until = ?, updated_at = ?, finished_at = ?
+WHERE id = ? AND status = 'running' AND locked_by = sqlc.arg(owner);
+
+-- name: DeleteFinishedJobs :execrows
+DELETE FROM jobs
+WHERE finished_at IS NOT NULL AND finished_at <= CAST(? AS TEXT);
+
+-- A lease is acquired when it is free, expired or already held by the holder
+-- name: AcquireLease :execrows
+INSERT INTO leases (name, holder, expires_at)
+VALUES (?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+WHERE leases.holder = excluded.holder OR leases.expires_at <= CAST(sqlc.arg(now) AS TEXT);
+
+-- name: ReleaseLease :exec
+DELETE FROM leases WHERE name = ? AND holder = ?;
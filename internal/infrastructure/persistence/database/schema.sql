@@ -13,9 +13,24 @@ CREATE TABLE sessions (
     user_id TEXT NOT NULL,
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
     updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    pinned INTEGER NOT NULL DEFAULT 0,
+    workspace_id TEXT NOT NULL DEFAULT 'default',
+    title TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+-- Session attributes table (per-session key-value state with optional expiry)
+CREATE TABLE session_attributes (
+    session_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    expires_at TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (session_id, key),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
 -- Messages table
 CREATE TABLE messages (
     id TEXT PRIMARY KEY,
@@ -26,6 +41,25 @@ CREATE TABLE messages (
     FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
 );
 
+-- Full-text index over message content (kept in sync by triggers)
+CREATE VIRTUAL TABLE messages_fts USING fts4(content="messages", content, tokenize=unicode61);
+
+CREATE TRIGGER messages_fts_after_insert AFTER INSERT ON messages BEGIN
+    INSERT INTO messages_fts(docid, content) VALUES (new.rowid, new.content);
+END;
+
+CREATE TRIGGER messages_fts_before_update BEFORE UPDATE ON messages BEGIN
+    DELETE FROM messages_fts WHERE docid = old.rowid;
+END;
+
+CREATE TRIGGER messages_fts_after_update AFTER UPDATE ON messages BEGIN
+    INSERT INTO messages_fts(docid, content) VALUES (new.rowid, new.content);
+END;
+
+CREATE TRIGGER messages_fts_before_delete BEFORE DELETE ON messages BEGIN
+    DELETE FROM messages_fts WHERE docid = old.rowid;
+END;
+
 -- Tasks table
 CREATE TABLE tasks (
     id TEXT PRIMARY KEY,
@@ -37,6 +71,8 @@ CREATE TABLE tasks (
     error TEXT,
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
     updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    started_at TEXT,
+    finished_at TEXT,
     FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
 );
 
@@ -48,20 +84,44 @@ CREATE TABLE skills (
     location TEXT NOT NULL,
     permissions TEXT NOT NULL,
     metadata TEXT NOT NULL,
-    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    disabled INTEGER NOT NULL DEFAULT 0,
+    workspace_id TEXT NOT NULL DEFAULT ''
 );
 
 -- Schedules table
 CREATE TABLE schedules (
     id TEXT PRIMARY KEY,
-    skill TEXT NOT NULL,
+    skill TEXT,
     cron_expression TEXT NOT NULL,
     input TEXT NOT NULL,
     enabled INTEGER NOT NULL DEFAULT 1,
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    jitter_seconds INTEGER NOT NULL DEFAULT 0,
+    schedule_type TEXT NOT NULL DEFAULT 'cron',
+    run_at TEXT,
+    missed_run_policy TEXT NOT NULL DEFAULT 'skip',
+    target_connector TEXT NOT NULL DEFAULT '',
+    target_user_id TEXT NOT NULL DEFAULT '',
+    prompt TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (skill) REFERENCES skills(name) ON DELETE CASCADE
 );
 
+-- Schedule runs table
+CREATE TABLE schedule_runs (
+    id TEXT PRIMARY KEY,
+    schedule_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    scheduled_at TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    finished_at TEXT,
+    output TEXT,
+    error TEXT,
+    FOREIGN KEY (schedule_id) REFERENCES schedules(id) ON DELETE CASCADE
+);
+
 -- Logs table
 CREATE TABLE logs (
     id TEXT PRIMARY KEY,
@@ -72,11 +132,288 @@ CREATE TABLE logs (
     created_at TEXT NOT NULL DEFAULT (datetime('now'))
 );
 
+-- API keys table (SHA-256 hashes of HTTP API keys)
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    revoked_at TEXT,
+    workspace_id TEXT NOT NULL DEFAULT ''
+);
+
+-- Events table (persistent event store of the event bus, in publication order)
+CREATE TABLE events (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL
+);
+
+-- Webhook deliveries table (delivery log of outbound webhooks)
+CREATE TABLE webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    endpoint TEXT NOT NULL,
+    url TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- Dead letters table (events whose subscriber handler failed after all retries)
+CREATE TABLE dead_letters (
+    id TEXT PRIMARY KEY,
+    subscription TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- Processed messages table (incoming connector messages already handled, for deduplication)
+CREATE TABLE processed_messages (
+    key TEXT PRIMARY KEY,
+    expires_at TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- Message dead letters table (connector messages the router failed to process)
+CREATE TABLE message_dead_letters (
+    id TEXT PRIMARY KEY,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- Workspaces table (tenants; connectors are mapped to them in the configuration)
+CREATE TABLE workspaces (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    max_tokens INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL
+);
+
+-- User preferences table (settings users chose for their replies)
+CREATE TABLE user_preferences (
+    user_id TEXT PRIMARY KEY,
+    language TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    timezone TEXT NOT NULL DEFAULT '',
+    verbosity TEXT NOT NULL DEFAULT '',
+    updated_at TEXT NOT NULL,
+    instructions TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Analytics counters table (daily aggregates such as the messages per connector)
+CREATE TABLE analytics_counters (
+    day TEXT NOT NULL,
+    metric TEXT NOT NULL,
+    dimension TEXT NOT NULL DEFAULT '',
+    value REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, metric, dimension)
+);
+
+-- Analytics active users table (users who sent a message on a day)
+CREATE TABLE analytics_active_users (
+    day TEXT NOT NULL,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (day, connector, user_id)
+);
+
+-- User data erasures table (audit records of erased users, without personal data)
+CREATE TABLE user_data_erasures (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    requested_by TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    sessions INTEGER NOT NULL DEFAULT 0,
+    messages INTEGER NOT NULL DEFAULT 0,
+    tasks INTEGER NOT NULL DEFAULT 0,
+    attributes INTEGER NOT NULL DEFAULT 0,
+    preferences INTEGER NOT NULL DEFAULT 0,
+    verified INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL
+);
+
+-- Crash reports table (panics recovered by the application, with their stack traces)
+CREATE TABLE crash_reports (
+    id TEXT PRIMARY KEY,
+    component TEXT NOT NULL,
+    operation TEXT NOT NULL DEFAULT '',
+    panic TEXT NOT NULL,
+    stack TEXT NOT NULL DEFAULT '',
+    attributes TEXT NOT NULL DEFAULT '{}',
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE document_chunks (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    document_id TEXT NOT NULL,
+    document_name TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    embedding TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE message_feedback (
+    id TEXT PRIMARY KEY,
+    message_id TEXT NOT NULL UNIQUE,
+    session_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    rating TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    day TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE llm_usage (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    workspace_id TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    input_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    total_tokens INTEGER NOT NULL DEFAULT 0,
+    cost REAL NOT NULL DEFAULT 0,
+    month TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- Idempotency keys table (responses of write API requests replayed to retries)
+CREATE TABLE idempotency_keys (
+    key TEXT PRIMARY KEY,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    content_type TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    expires_at TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- Message jobs table (messages sent through the asynchronous message API)
+CREATE TABLE message_jobs (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    content TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message_id TEXT NOT NULL DEFAULT '',
+    reply TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    finished_at TEXT,
+    batch_id TEXT NOT NULL DEFAULT '',
+    batch_index INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+-- Spilled messages table (messages that did not fit into the incoming buffer of a connector)
+CREATE TABLE spilled_messages (
+    id TEXT PRIMARY KEY,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    sequence INTEGER NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- Task attempts table (executions of the skill of a task, retried according to its retry policy)
+CREATE TABLE task_attempts (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    retryable INTEGER NOT NULL DEFAULT 0,
+    started_at TEXT NOT NULL,
+    finished_at TEXT NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- Jobs table (background work of the job queue, claimed by workers for a lease)
+CREATE TABLE jobs (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT NOT NULL DEFAULT '',
+    run_at TEXT NOT NULL,
+    locked_by TEXT NOT NULL DEFAULT '',
+    locked_until TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    finished_at TEXT,
+    leader_only INTEGER NOT NULL DEFAULT 0
+);
+
+-- Leases table (named leases held by one instance of a cluster until they expire)
+CREATE TABLE leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    expires_at TEXT NOT NULL
+);
+
 -- Indexes for better performance
 CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_workspace_id ON sessions(workspace_id, created_at);
+CREATE INDEX idx_session_attributes_expires_at ON session_attributes(expires_at);
 CREATE INDEX idx_messages_session_id ON messages(session_id);
 CREATE INDEX idx_tasks_session_id ON tasks(session_id);
 CREATE INDEX idx_schedules_skill ON schedules(skill);
+CREATE INDEX idx_schedule_runs_schedule_id ON schedule_runs(schedule_id, scheduled_at);
 CREATE INDEX idx_logs_level ON logs(level);
 CREATE INDEX idx_logs_source ON logs(source);
 CREATE INDEX idx_logs_created_at ON logs(created_at);
+CREATE INDEX idx_events_occurred_at ON events(occurred_at);
+CREATE INDEX idx_events_type ON events(type);
+CREATE INDEX idx_webhook_deliveries_created_at ON webhook_deliveries(created_at);
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint, created_at);
+CREATE INDEX idx_dead_letters_created_at ON dead_letters(created_at);
+CREATE INDEX idx_dead_letters_subscription ON dead_letters(subscription, created_at);
+CREATE INDEX idx_processed_messages_expires_at ON processed_messages(expires_at);
+CREATE INDEX idx_message_dead_letters_created_at ON message_dead_letters(created_at);
+CREATE INDEX idx_message_dead_letters_connector ON message_dead_letters(connector, created_at);
+CREATE INDEX idx_user_data_erasures_user_id ON user_data_erasures(user_id);
+CREATE INDEX idx_crash_reports_created_at ON crash_reports(created_at);
+CREATE INDEX idx_crash_reports_component ON crash_reports(component, created_at);
+CREATE INDEX idx_document_chunks_session_id ON document_chunks(session_id, created_at, position);
+CREATE INDEX idx_message_feedback_day ON message_feedback(day, rating);
+CREATE INDEX idx_llm_usage_month ON llm_usage(month, workspace_id);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+CREATE INDEX idx_message_jobs_status ON message_jobs(status);
+CREATE INDEX idx_message_jobs_finished_at ON message_jobs(finished_at);
+CREATE INDEX idx_message_jobs_batch_id ON message_jobs(batch_id, batch_index);
+CREATE INDEX idx_spilled_messages_connector ON spilled_messages(connector, sequence);
+CREATE INDEX idx_task_attempts_task_id ON task_attempts(task_id, attempt);
+CREATE INDEX idx_jobs_status_run_at ON jobs(status, run_at);
+CREATE INDEX idx_jobs_finished_at ON jobs(finished_at);
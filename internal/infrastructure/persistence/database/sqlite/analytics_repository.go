@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.AnalyticsRepository = (*AnalyticsRepository)(nil)
+
+type AnalyticsRepository struct {
+	queries *database.Queries
+}
+
+func NewAnalyticsRepository(queries *database.Queries) *AnalyticsRepository {
+	return &AnalyticsRepository{queries: queries}
+}
+
+func (r *AnalyticsRepository) Increment(ctx context.Context, day string, metric entity.AnalyticsMetric, dimension string, delta float64) error {
+	err := r.queries.IncrementAnalyticsCounter(ctx, database.IncrementAnalyticsCounterParams{
+		Day:       day,
+		Metric:    string(metric),
+		Dimension: dimension,
+		Value:     delta,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to increment analytics counter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AnalyticsRepository) AddActiveUser(ctx context.Context, day, connector, userID string) error {
+	err := r.queries.AddAnalyticsActiveUser(ctx, database.AddAnalyticsActiveUserParams{
+		Day:       day,
+		Connector: connector,
+		UserID:    userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add analytics active user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AnalyticsRepository) ListCounters(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	dbCounters, err := r.queries.ListAnalyticsCounters(ctx, database.ListAnalyticsCountersParams{Since: since, Until: until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics counters: %w", err)
+	}
+
+	counters := make([]*entity.AnalyticsCounter, 0, len(dbCounters))
+	for i := range dbCounters {
+		counters = append(counters, mappers.AnalyticsCounterToDomain(&dbCounters[i]))
+	}
+	return counters, nil
+}
+
+func (r *AnalyticsRepository) CountActiveUsers(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	rows, err := r.queries.CountAnalyticsActiveUsers(ctx, database.CountAnalyticsActiveUsersParams{Since: since, Until: until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count analytics active users: %w", err)
+	}
+
+	counters := make([]*entity.AnalyticsCounter, 0, len(rows))
+	for i := range rows {
+		counters = append(counters, mappers.AnalyticsActiveUsersToDomain(&rows[i]))
+	}
+	return counters, nil
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+var _ repository.APIKeyRepository = (*APIKeyRepository)(nil)
+
+type APIKeyRepository struct {
+	queries *database.Queries
+}
+
+func NewAPIKeyRepository(queries *database.Queries) *APIKeyRepository {
+	return &APIKeyRepository{queries: queries}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, apiKey *entity.APIKey) error {
+	dbKey := mappers.APIKeyToDB(apiKey)
+	if dbKey == nil {
+		return fmt.Errorf("failed to convert api key to db model")
+	}
+
+	_, err := r.queries.CreateAPIKey(ctx, database.CreateAPIKeyParams{
+		ID:          dbKey.ID,
+		Name:        dbKey.Name,
+		KeyHash:     dbKey.KeyHash,
+		Prefix:      dbKey.Prefix,
+		Scope:       dbKey.Scope,
+		CreatedAt:   dbKey.CreatedAt,
+		WorkspaceID: dbKey.WorkspaceID,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create api key")
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	dbKey, err := r.queries.GetAPIKeyByHash(ctx, keyHash)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("api key %w", repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api key: %w", err)
+	}
+
+	return mappers.APIKeyToDomain(&dbKey), nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	dbKeys, err := r.queries.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	return mappers.APIKeysToDomain(dbKeys), nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) (bool, error) {
+	n, err := r.queries.RevokeAPIKey(ctx, database.RevokeAPIKeyParams{
+		RevokedAt: sql.NullString{String: utils.FormatTimeRFC3339(revokedAt.UTC()), Valid: true},
+		ID:        id,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.CrashReportRepository = (*CrashReportRepository)(nil)
+
+type CrashReportRepository struct {
+	queries *database.Queries
+}
+
+func NewCrashReportRepository(queries *database.Queries) *CrashReportRepository {
+	return &CrashReportRepository{queries: queries}
+}
+
+func (r *CrashReportRepository) Create(ctx context.Context, report *entity.CrashReport) error {
+	dbReport := mappers.CrashReportToDB(report)
+	if dbReport == nil {
+		return fmt.Errorf("failed to convert crash report to db model")
+	}
+
+	_, err := r.queries.CreateCrashReport(ctx, database.CreateCrashReportParams{
+		ID:         dbReport.ID,
+		Component:  dbReport.Component,
+		Operation:  dbReport.Operation,
+		Panic:      dbReport.Panic,
+		Stack:      dbReport.Stack,
+		Attributes: dbReport.Attributes,
+		CreatedAt:  dbReport.CreatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create crash report")
+	}
+
+	return nil
+}
+
+func (r *CrashReportRepository) List(ctx context.Context, filter repository.CrashReportFilter) ([]*entity.CrashReport, error) {
+	limit := int64(-1)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dbReports, err := r.queries.ListCrashReports(ctx, database.ListCrashReportsParams{
+		Component: filter.Component,
+		Limit:     limit,
+		Offset:    int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crash reports: %w", err)
+	}
+
+	return mappers.CrashReportsToDomain(dbReports), nil
+}
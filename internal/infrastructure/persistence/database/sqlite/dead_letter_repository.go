@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.DeadLetterRepository = (*DeadLetterRepository)(nil)
+
+type DeadLetterRepository struct {
+	queries *database.Queries
+}
+
+func NewDeadLetterRepository(queries *database.Queries) *DeadLetterRepository {
+	return &DeadLetterRepository{queries: queries}
+}
+
+func (r *DeadLetterRepository) Create(ctx context.Context, letter *entity.DeadLetter) error {
+	dbLetter := mappers.DeadLetterToDB(letter)
+	if dbLetter == nil {
+		return fmt.Errorf("failed to convert dead letter to db model")
+	}
+
+	_, err := r.queries.CreateDeadLetter(ctx, database.CreateDeadLetterParams{
+		ID:           dbLetter.ID,
+		Subscription: dbLetter.Subscription,
+		EventType:    dbLetter.EventType,
+		Payload:      dbLetter.Payload,
+		OccurredAt:   dbLetter.OccurredAt,
+		Attempts:     dbLetter.Attempts,
+		Error:        dbLetter.Error,
+		CreatedAt:    dbLetter.CreatedAt,
+		UpdatedAt:    dbLetter.UpdatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create dead letter")
+	}
+
+	return nil
+}
+
+func (r *DeadLetterRepository) GetByID(ctx context.Context, id string) (*entity.DeadLetter, error) {
+	dbLetter, err := r.queries.GetDeadLetter(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("dead letter %w: %s", repository.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+
+	return mappers.DeadLetterToDomain(&dbLetter), nil
+}
+
+func (r *DeadLetterRepository) List(ctx context.Context, filter repository.DeadLetterFilter) ([]*entity.DeadLetter, error) {
+	limit := int64(-1)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dbLetters, err := r.queries.ListDeadLetters(ctx, database.ListDeadLettersParams{
+		Subscription: filter.Subscription,
+		Limit:        limit,
+		Offset:       int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return mappers.DeadLettersToDomain(dbLetters), nil
+}
+
+func (r *DeadLetterRepository) Update(ctx context.Context, letter *entity.DeadLetter) error {
+	dbLetter := mappers.DeadLetterToDB(letter)
+	if dbLetter == nil {
+		return fmt.Errorf("failed to convert dead letter to db model")
+	}
+
+	_, err := r.queries.UpdateDeadLetter(ctx, database.UpdateDeadLetterParams{
+		Attempts:  dbLetter.Attempts,
+		Error:     dbLetter.Error,
+		UpdatedAt: dbLetter.UpdatedAt,
+		ID:        dbLetter.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("dead letter %w: %s", repository.ErrNotFound, letter.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DeadLetterRepository) Delete(ctx context.Context, id string) error {
+	deleted, err := r.queries.DeleteDeadLetter(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("dead letter %w: %s", repository.ErrNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.DocumentChunkRepository = (*DocumentChunkRepository)(nil)
+
+type DocumentChunkRepository struct {
+	queries *database.Queries
+}
+
+func NewDocumentChunkRepository(queries *database.Queries) *DocumentChunkRepository {
+	return &DocumentChunkRepository{queries: queries}
+}
+
+func (r *DocumentChunkRepository) Create(ctx context.Context, chunks []*entity.DocumentChunk) error {
+	for _, chunk := range chunks {
+		dbChunk := mappers.DocumentChunkToDB(chunk)
+		if dbChunk == nil {
+			return fmt.Errorf("failed to convert document chunk to db model")
+		}
+
+		err := r.queries.CreateDocumentChunk(ctx, database.CreateDocumentChunkParams{
+			ID:           dbChunk.ID,
+			SessionID:    dbChunk.SessionID,
+			DocumentID:   dbChunk.DocumentID,
+			DocumentName: dbChunk.DocumentName,
+			Position:     dbChunk.Position,
+			Content:      dbChunk.Content,
+			Embedding:    dbChunk.Embedding,
+			CreatedAt:    dbChunk.CreatedAt,
+		})
+		if err != nil {
+			return wrapWriteError(err, "failed to create document chunk")
+		}
+	}
+
+	return nil
+}
+
+func (r *DocumentChunkRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.DocumentChunk, error) {
+	dbChunks, err := r.queries.ListDocumentChunks(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find document chunks: %w", err)
+	}
+
+	return mappers.DocumentChunksToDomain(dbChunks), nil
+}
+
+func (r *DocumentChunkRepository) DeleteBySessionID(ctx context.Context, sessionID string) (int64, error) {
+	n, err := r.queries.DeleteDocumentChunks(ctx, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete document chunks: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,17 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+)
+
+// wrapWriteError wraps an insert or update error, marking uniqueness violations
+// with repository.ErrConflict so that callers can tell them from other failures
+func wrapWriteError(err error, message string) error {
+	if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return fmt.Errorf("%s: %w: %v", message, repository.ErrConflict, err)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
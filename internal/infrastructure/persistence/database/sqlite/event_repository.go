@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.EventRepository = (*EventRepository)(nil)
+
+type EventRepository struct {
+	queries *database.Queries
+}
+
+func NewEventRepository(queries *database.Queries) *EventRepository {
+	return &EventRepository{queries: queries}
+}
+
+func (r *EventRepository) Append(ctx context.Context, events ...*entity.StoredEvent) error {
+	for _, event := range events {
+		dbEvent := mappers.StoredEventToDB(event)
+		if dbEvent == nil {
+			return fmt.Errorf("failed to convert event to db model")
+		}
+
+		saved, err := r.queries.CreateEvent(ctx, database.CreateEventParams{
+			ID:         dbEvent.ID,
+			Type:       dbEvent.Type,
+			Payload:    dbEvent.Payload,
+			OccurredAt: dbEvent.OccurredAt,
+		})
+		if err != nil {
+			return wrapWriteError(err, "failed to store event")
+		}
+
+		event.Seq = saved.Seq
+	}
+
+	return nil
+}
+
+func (r *EventRepository) GetByID(ctx context.Context, id string) (*entity.StoredEvent, error) {
+	dbEvent, err := r.queries.GetEvent(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("event %w: %s", repository.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return mappers.StoredEventToDomain(&dbEvent), nil
+}
+
+func (r *EventRepository) List(ctx context.Context, filter repository.EventFilter) ([]*entity.StoredEvent, error) {
+	types := ""
+	if len(filter.Types) > 0 {
+		data, err := json.Marshal(filter.Types)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event types: %w", err)
+		}
+		types = string(data)
+	}
+
+	limit := int64(-1)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dbEvents, err := r.queries.ListEvents(ctx, database.ListEventsParams{
+		AfterSeq: filter.AfterSeq,
+		Since:    formatOptionalTime(filter.Since),
+		Until:    formatOptionalTime(filter.Until),
+		Types:    types,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	return mappers.StoredEventsToDomain(dbEvents), nil
+}
+
+func (r *EventRepository) ListBySubject(ctx context.Context, subject repository.EventSubject) ([]*entity.StoredEvent, error) {
+	params, err := encodeEventSubject(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	dbEvents, err := r.queries.ListEventsBySubject(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	return mappers.StoredEventsToDomain(dbEvents), nil
+}
+
+func (r *EventRepository) DeleteBySubject(ctx context.Context, subject repository.EventSubject) (int, error) {
+	params, err := encodeEventSubject(subject)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := r.queries.DeleteEventsBySubject(ctx, database.DeleteEventsBySubjectParams(params))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete events: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+// channelUserJSON is the form of a repository.ChannelUser matched by the subject queries
+type channelUserJSON struct {
+	Connector string `json:"connector"`
+	UserID    string `json:"user_id"`
+}
+
+// encodeEventSubject returns the user IDs, session IDs and channel users of a subject as JSON arrays
+func encodeEventSubject(subject repository.EventSubject) (database.ListEventsBySubjectParams, error) {
+	var params database.ListEventsBySubjectParams
+	userIDs, err := json.Marshal(append([]string{}, subject.UserIDs...))
+	if err != nil {
+		return params, fmt.Errorf("failed to encode user IDs: %w", err)
+	}
+	sessionIDs, err := json.Marshal(append([]string{}, subject.SessionIDs...))
+	if err != nil {
+		return params, fmt.Errorf("failed to encode session IDs: %w", err)
+	}
+	channelUsers := make([]channelUserJSON, 0, len(subject.ChannelUsers))
+	for _, user := range subject.ChannelUsers {
+		channelUsers = append(channelUsers, channelUserJSON{Connector: user.Connector, UserID: user.UserID})
+	}
+	encodedUsers, err := json.Marshal(channelUsers)
+	if err != nil {
+		return params, fmt.Errorf("failed to encode channel users: %w", err)
+	}
+	params.UserIds = string(userIDs)
+	params.SessionIds = string(sessionIDs)
+	params.ChannelUsers = string(encodedUsers)
+	return params, nil
+}
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+var _ repository.IdempotencyRepository = (*IdempotencyRepository)(nil)
+
+type IdempotencyRepository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func NewIdempotencyRepository(queries *database.Queries) *IdempotencyRepository {
+	return &IdempotencyRepository{queries: queries, now: utils.Now}
+}
+
+func (r *IdempotencyRepository) Find(ctx context.Context, key string) (*entity.IdempotencyRecord, error) {
+	dbKey, err := r.queries.GetIdempotencyKey(ctx, database.GetIdempotencyKeyParams{
+		Key: key,
+		Now: utils.FormatTimeRFC3339(r.now().UTC()),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("idempotency key %w", repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find idempotency key: %w", err)
+	}
+
+	return mappers.IdempotencyRecordToDomain(&dbKey), nil
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (bool, error) {
+	dbKey := mappers.IdempotencyRecordToDB(record)
+	if dbKey == nil {
+		return false, fmt.Errorf("failed to convert idempotency record to db model")
+	}
+
+	written, err := r.queries.ReserveIdempotencyKey(ctx, database.ReserveIdempotencyKeyParams{
+		Key:         dbKey.Key,
+		RequestHash: dbKey.RequestHash,
+		ExpiresAt:   dbKey.ExpiresAt,
+		CreatedAt:   dbKey.CreatedAt,
+	})
+	if err != nil {
+		return false, wrapWriteError(err, "failed to reserve idempotency key")
+	}
+
+	return written > 0, nil
+}
+
+func (r *IdempotencyRepository) Save(ctx context.Context, record *entity.IdempotencyRecord) (bool, error) {
+	dbKey := mappers.IdempotencyRecordToDB(record)
+	if dbKey == nil {
+		return false, fmt.Errorf("failed to convert idempotency record to db model")
+	}
+
+	written, err := r.queries.SaveIdempotencyKey(ctx, database.SaveIdempotencyKeyParams{
+		Key:         dbKey.Key,
+		RequestHash: dbKey.RequestHash,
+		StatusCode:  dbKey.StatusCode,
+		ContentType: dbKey.ContentType,
+		Body:        dbKey.Body,
+		ExpiresAt:   dbKey.ExpiresAt,
+		CreatedAt:   dbKey.CreatedAt,
+	})
+	if err != nil {
+		return false, wrapWriteError(err, "failed to save idempotency key")
+	}
+
+	return written > 0, nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	if _, err := r.queries.DeletePendingIdempotencyKey(ctx, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	n, err := r.queries.DeleteExpiredIdempotencyKeys(ctx, utils.FormatTimeRFC3339(now.UTC()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+var _ repository.JobRepository = (*JobRepository)(nil)
+
+type JobRepository struct {
+	queries *database.Queries
+}
+
+func NewJobRepository(queries *database.Queries) *JobRepository {
+	return &JobRepository{queries: queries}
+}
+
+func (r *JobRepository) Create(ctx context.Context, job *entity.Job) error {
+	dbJob := mappers.JobToDB(job)
+	if dbJob == nil {
+		return fmt.Errorf("failed to convert job to db model")
+	}
+
+	n, err := r.queries.CreateJob(ctx, database.CreateJobParams{
+		ID:          dbJob.ID,
+		Kind:        dbJob.Kind,
+		Payload:     dbJob.Payload,
+		Status:      dbJob.Status,
+		Attempts:    dbJob.Attempts,
+		MaxAttempts: dbJob.MaxAttempts,
+		Error:       dbJob.Error,
+		RunAt:       dbJob.RunAt,
+		LockedBy:    dbJob.LockedBy,
+		LockedUntil: dbJob.LockedUntil,
+		CreatedAt:   dbJob.CreatedAt,
+		UpdatedAt:   dbJob.UpdatedAt,
+		FinishedAt:  dbJob.FinishedAt,
+		LeaderOnly:  dbJob.LeaderOnly,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create job")
+	}
+	if n == 0 {
+		return fmt.Errorf("job %w: %s", repository.ErrConflict, job.ID)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) FindByID(ctx context.Context, id string) (*entity.Job, error) {
+	dbJob, err := r.queries.GetJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("job %w: %s", repository.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job: %w", err)
+	}
+
+	return mappers.JobToDomain(&dbJob), nil
+}
+
+func (r *JobRepository) Claim(ctx context.Context, owner string, leader bool, now, lockedUntil time.Time) (*entity.Job, error) {
+	leaderFlag := int64(0)
+	if leader {
+		leaderFlag = 1
+	}
+
+	dbJob, err := r.queries.ClaimJob(ctx, database.ClaimJobParams{
+		LockedBy:    owner,
+		LockedUntil: sql.NullString{String: utils.FormatTimeRFC3339(lockedUntil.UTC()), Valid: true},
+		UpdatedAt:   utils.FormatTimeRFC3339(now.UTC()),
+		Now:         utils.FormatTimeRFC3339(now.UTC()),
+		Leader:      leaderFlag,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("due job %w", repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, wrapWriteError(err, "failed to claim job")
+	}
+
+	return mappers.JobToDomain(&dbJob), nil
+}
+
+func (r *JobRepository) Update(ctx context.Context, job *entity.Job, owner string) error {
+	dbJob := mappers.JobToDB(job)
+	if dbJob == nil {
+		return fmt.Errorf("failed to convert job to db model")
+	}
+
+	n, err := r.queries.UpdateJob(ctx, database.UpdateJobParams{
+		Status:      dbJob.Status,
+		Error:       dbJob.Error,
+		RunAt:       dbJob.RunAt,
+		LockedBy:    dbJob.LockedBy,
+		LockedUntil: dbJob.LockedUntil,
+		UpdatedAt:   dbJob.UpdatedAt,
+		FinishedAt:  dbJob.FinishedAt,
+		ID:          dbJob.ID,
+		Owner:       owner,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to update job")
+	}
+	if n == 0 {
+		return fmt.Errorf("lease of job %w: %s", repository.ErrNotFound, job.ID)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.queries.DeleteFinishedJobs(ctx, utils.FormatTimeRFC3339(before.UTC()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+
+	return n, nil
+}
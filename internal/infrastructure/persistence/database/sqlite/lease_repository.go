@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+var _ repository.LeaseRepository = (*LeaseRepository)(nil)
+
+type LeaseRepository struct {
+	queries *database.Queries
+}
+
+func NewLeaseRepository(queries *database.Queries) *LeaseRepository {
+	return &LeaseRepository{queries: queries}
+}
+
+func (r *LeaseRepository) Acquire(ctx context.Context, name, holder string, now, expiresAt time.Time) (bool, error) {
+	n, err := r.queries.AcquireLease(ctx, database.AcquireLeaseParams{
+		Name:      name,
+		Holder:    holder,
+		ExpiresAt: utils.FormatTimeRFC3339(expiresAt.UTC()),
+		Now:       utils.FormatTimeRFC3339(now.UTC()),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func (r *LeaseRepository) Release(ctx context.Context, name, holder string) error {
+	if err := r.queries.ReleaseLease(ctx, database.ReleaseLeaseParams{Name: name, Holder: holder}); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+
+	return nil
+}
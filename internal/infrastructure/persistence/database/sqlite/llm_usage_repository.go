@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.LLMUsageRepository = (*LLMUsageRepository)(nil)
+
+type LLMUsageRepository struct {
+	queries *database.Queries
+}
+
+func NewLLMUsageRepository(queries *database.Queries) *LLMUsageRepository {
+	return &LLMUsageRepository{queries: queries}
+}
+
+func (r *LLMUsageRepository) Create(ctx context.Context, usage *entity.LLMUsage) error {
+	dbUsage := mappers.LLMUsageToDB(usage)
+	if dbUsage == nil {
+		return fmt.Errorf("failed to convert llm usage to db model")
+	}
+
+	err := r.queries.CreateLLMUsage(ctx, database.CreateLLMUsageParams{
+		ID:           dbUsage.ID,
+		UserID:       dbUsage.UserID,
+		WorkspaceID:  dbUsage.WorkspaceID,
+		SessionID:    dbUsage.SessionID,
+		Provider:     dbUsage.Provider,
+		Model:        dbUsage.Model,
+		InputTokens:  dbUsage.InputTokens,
+		OutputTokens: dbUsage.OutputTokens,
+		TotalTokens:  dbUsage.TotalTokens,
+		Cost:         dbUsage.Cost,
+		Month:        dbUsage.Month,
+		CreatedAt:    dbUsage.CreatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create llm usage")
+	}
+	return nil
+}
+
+func (r *LLMUsageRepository) SumByMonth(ctx context.Context, since, until string) ([]*entity.LLMUsageTotal, error) {
+	rows, err := r.queries.SumLLMUsage(ctx, database.SumLLMUsageParams{Since: since, Until: until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum llm usage: %w", err)
+	}
+
+	totals := make([]*entity.LLMUsageTotal, 0, len(rows))
+	for i := range rows {
+		totals = append(totals, mappers.LLMUsageTotalToDomain(&rows[i]))
+	}
+	return totals, nil
+}
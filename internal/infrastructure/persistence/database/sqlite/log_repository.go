@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 	"github.com/atumaikin/nexflow/internal/domain/repository"
@@ -52,7 +53,7 @@ func (r *LogRepository) Create(ctx context.Context, log *entity.Log) error {
 func (r *LogRepository) FindByID(ctx context.Context, id string) (*entity.Log, error) {
 	dbLog, err := r.queries.GetLogByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("log not found: %s", id)
+		return nil, fmt.Errorf("log %w: %s", repository.ErrNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find log by id: %w", err)
@@ -61,10 +62,16 @@ func (r *LogRepository) FindByID(ctx context.Context, id string) (*entity.Log, e
 	return mappers.LogToDomain(&dbLog), nil
 }
 
-func (r *LogRepository) FindByLevel(ctx context.Context, level string, limit int) ([]*entity.Log, error) {
+func (r *LogRepository) FindByLevel(ctx context.Context, level string, opts repository.QueryOptions) ([]*entity.Log, error) {
+	p := newPageParams(opts)
 	dbLogs, err := r.queries.GetLogsByLevel(ctx, database.GetLogsByLevelParams{
-		Level: level,
-		Limit: int64(limit),
+		Level:          level,
+		Since:          p.since,
+		Until:          p.until,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+		Offset:         p.offset,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find logs by level: %w", err)
@@ -73,10 +80,16 @@ func (r *LogRepository) FindByLevel(ctx context.Context, level string, limit int
 	return mappers.LogsToDomain(dbLogs), nil
 }
 
-func (r *LogRepository) FindBySource(ctx context.Context, source string, limit int) ([]*entity.Log, error) {
+func (r *LogRepository) FindBySource(ctx context.Context, source string, opts repository.QueryOptions) ([]*entity.Log, error) {
+	p := newPageParams(opts)
 	dbLogs, err := r.queries.GetLogsBySource(ctx, database.GetLogsBySourceParams{
-		Source: source,
-		Limit:  int64(limit),
+		Source:         source,
+		Since:          p.since,
+		Until:          p.until,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+		Offset:         p.offset,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find logs by source: %w", err)
@@ -98,11 +111,30 @@ func (r *LogRepository) FindByDateRange(ctx context.Context, startDate, endDate
 	return mappers.LogsToDomain(dbLogs), nil
 }
 
+func (r *LogRepository) List(ctx context.Context, filter repository.LogFilter) ([]*entity.Log, error) {
+	limit := int64(-1)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dbLogs, err := r.queries.ListLogs(ctx, database.ListLogsParams{
+		Level:  filter.Level,
+		Source: filter.Source,
+		Limit:  limit,
+		Offset: int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logs: %w", err)
+	}
+
+	return mappers.LogsToDomain(dbLogs), nil
+}
+
 func (r *LogRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.queries.GetLogByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("log not found: %s", id)
+			return fmt.Errorf("log %w: %s", repository.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to check log existence: %w", err)
 	}
@@ -115,19 +147,43 @@ func (r *LogRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *LogRepository) DeleteOlderThan(ctx context.Context, date string) error {
-	err := r.queries.DeleteLogsOlderThan(ctx, date)
+func (r *LogRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	count, err := r.queries.CountLogsOlderThan(ctx, formatOptionalTime(before))
 	if err != nil {
-		return fmt.Errorf("failed to delete logs older than: %w", err)
+		return 0, fmt.Errorf("failed to count logs older than: %w", err)
 	}
 
-	return nil
+	return int(count), nil
+}
+
+func (r *LogRepository) FindOlderThan(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]*entity.Log, error) {
+	p := newBatchParams(before, opts)
+	dbLogs, err := r.queries.ListLogsOlderThan(ctx, database.ListLogsOlderThanParams{
+		Before:         p.before,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find logs older than: %w", err)
+	}
+
+	return mappers.LogsToDomain(dbLogs), nil
+}
+
+func (r *LogRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeleteLogsOlderThan(ctx, formatOptionalTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete logs older than: %w", err)
+	}
+
+	return int(deleted), nil
 }
 
 func (r *LogRepository) CountByLevel(ctx context.Context, level string) (int, error) {
 	dbLogs, err := r.queries.GetLogsByLevel(ctx, database.GetLogsByLevelParams{
 		Level: level,
-		Limit: 1000000,
+		Limit: -1,
 	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to count logs by level: %w", err)
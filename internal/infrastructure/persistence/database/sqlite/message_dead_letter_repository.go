@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.MessageDeadLetterRepository = (*MessageDeadLetterRepository)(nil)
+
+type MessageDeadLetterRepository struct {
+	queries *database.Queries
+}
+
+func NewMessageDeadLetterRepository(queries *database.Queries) *MessageDeadLetterRepository {
+	return &MessageDeadLetterRepository{queries: queries}
+}
+
+func (r *MessageDeadLetterRepository) Create(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	dbLetter := mappers.MessageDeadLetterToDB(letter)
+	if dbLetter == nil {
+		return fmt.Errorf("failed to convert message dead letter to db model")
+	}
+
+	_, err := r.queries.CreateMessageDeadLetter(ctx, database.CreateMessageDeadLetterParams{
+		ID:        dbLetter.ID,
+		Connector: dbLetter.Connector,
+		UserID:    dbLetter.UserID,
+		ChannelID: dbLetter.ChannelID,
+		Content:   dbLetter.Content,
+		Metadata:  dbLetter.Metadata,
+		Attempts:  dbLetter.Attempts,
+		Error:     dbLetter.Error,
+		CreatedAt: dbLetter.CreatedAt,
+		UpdatedAt: dbLetter.UpdatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create message dead letter")
+	}
+
+	return nil
+}
+
+func (r *MessageDeadLetterRepository) GetByID(ctx context.Context, id string) (*entity.MessageDeadLetter, error) {
+	dbLetter, err := r.queries.GetMessageDeadLetter(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message dead letter %w: %s", repository.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message dead letter: %w", err)
+	}
+
+	return mappers.MessageDeadLetterToDomain(&dbLetter), nil
+}
+
+func (r *MessageDeadLetterRepository) List(ctx context.Context, filter repository.MessageDeadLetterFilter) ([]*entity.MessageDeadLetter, error) {
+	limit := int64(-1)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dbLetters, err := r.queries.ListMessageDeadLetters(ctx, database.ListMessageDeadLettersParams{
+		Connector: filter.Connector,
+		UserID:    filter.UserID,
+		Limit:     limit,
+		Offset:    int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message dead letters: %w", err)
+	}
+
+	return mappers.MessageDeadLettersToDomain(dbLetters), nil
+}
+
+func (r *MessageDeadLetterRepository) Update(ctx context.Context, letter *entity.MessageDeadLetter) error {
+	dbLetter := mappers.MessageDeadLetterToDB(letter)
+	if dbLetter == nil {
+		return fmt.Errorf("failed to convert message dead letter to db model")
+	}
+
+	_, err := r.queries.UpdateMessageDeadLetter(ctx, database.UpdateMessageDeadLetterParams{
+		Attempts:  dbLetter.Attempts,
+		Error:     dbLetter.Error,
+		UpdatedAt: dbLetter.UpdatedAt,
+		ID:        dbLetter.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("message dead letter %w: %s", repository.ErrNotFound, letter.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update message dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MessageDeadLetterRepository) Delete(ctx context.Context, id string) error {
+	deleted, err := r.queries.DeleteMessageDeadLetter(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete message dead letter: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("message dead letter %w: %s", repository.ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (r *MessageDeadLetterRepository) DeleteByUser(ctx context.Context, connector, userID string) (int, error) {
+	deleted, err := r.queries.DeleteMessageDeadLettersByUser(ctx, database.DeleteMessageDeadLettersByUserParams{
+		Connector: connector,
+		UserID:    userID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete message dead letters: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.MessageFeedbackRepository = (*MessageFeedbackRepository)(nil)
+
+type MessageFeedbackRepository struct {
+	queries *database.Queries
+}
+
+func NewMessageFeedbackRepository(queries *database.Queries) *MessageFeedbackRepository {
+	return &MessageFeedbackRepository{queries: queries}
+}
+
+func (r *MessageFeedbackRepository) Save(ctx context.Context, feedback *entity.MessageFeedback) error {
+	dbFeedback := mappers.MessageFeedbackToDB(feedback)
+	if dbFeedback == nil {
+		return fmt.Errorf("failed to convert message feedback to db model")
+	}
+
+	saved, err := r.queries.UpsertMessageFeedback(ctx, database.UpsertMessageFeedbackParams{
+		ID:        dbFeedback.ID,
+		MessageID: dbFeedback.MessageID,
+		SessionID: dbFeedback.SessionID,
+		UserID:    dbFeedback.UserID,
+		Rating:    dbFeedback.Rating,
+		Comment:   dbFeedback.Comment,
+		Day:       dbFeedback.Day,
+		CreatedAt: dbFeedback.CreatedAt,
+		UpdatedAt: dbFeedback.UpdatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to save message feedback")
+	}
+
+	// A replaced feedback keeps the ID and creation time it was first saved with
+	*feedback = *mappers.MessageFeedbackToDomain(&saved)
+	return nil
+}
+
+func (r *MessageFeedbackRepository) FindByMessageID(ctx context.Context, messageID string) (*entity.MessageFeedback, error) {
+	dbFeedback, err := r.queries.GetMessageFeedback(ctx, messageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message feedback %w: %s", repository.ErrNotFound, messageID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message feedback: %w", err)
+	}
+
+	return mappers.MessageFeedbackToDomain(&dbFeedback), nil
+}
+
+func (r *MessageFeedbackRepository) CountByDay(ctx context.Context, since, until string) ([]*entity.AnalyticsCounter, error) {
+	rows, err := r.queries.CountMessageFeedback(ctx, database.CountMessageFeedbackParams{Since: since, Until: until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count message feedback: %w", err)
+	}
+
+	counters := make([]*entity.AnalyticsCounter, 0, len(rows))
+	for i := range rows {
+		counters = append(counters, mappers.MessageFeedbackCountToDomain(&rows[i]))
+	}
+	return counters, nil
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+var _ repository.MessageJobRepository = (*MessageJobRepository)(nil)
+
+type MessageJobRepository struct {
+	queries *database.Queries
+}
+
+func NewMessageJobRepository(queries *database.Queries) *MessageJobRepository {
+	return &MessageJobRepository{queries: queries}
+}
+
+func (r *MessageJobRepository) Create(ctx context.Context, job *entity.MessageJob) error {
+	dbJob := mappers.MessageJobToDB(job)
+	if dbJob == nil {
+		return fmt.Errorf("failed to convert message job to db model")
+	}
+
+	err := r.queries.CreateMessageJob(ctx, database.CreateMessageJobParams{
+		ID:         dbJob.ID,
+		SessionID:  dbJob.SessionID,
+		Content:    dbJob.Content,
+		Status:     dbJob.Status,
+		MessageID:  dbJob.MessageID,
+		Reply:      dbJob.Reply,
+		Error:      dbJob.Error,
+		CreatedAt:  dbJob.CreatedAt,
+		UpdatedAt:  dbJob.UpdatedAt,
+		FinishedAt: dbJob.FinishedAt,
+		BatchID:    dbJob.BatchID,
+		BatchIndex: dbJob.BatchIndex,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create message job")
+	}
+
+	return nil
+}
+
+func (r *MessageJobRepository) FindByID(ctx context.Context, id string) (*entity.MessageJob, error) {
+	dbJob, err := r.queries.GetMessageJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message job %w: %s", repository.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message job: %w", err)
+	}
+
+	return mappers.MessageJobToDomain(&dbJob), nil
+}
+
+func (r *MessageJobRepository) FindByBatchID(ctx context.Context, batchID string) ([]*entity.MessageJob, error) {
+	dbJobs, err := r.queries.ListMessageJobsByBatch(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message jobs of batch: %w", err)
+	}
+
+	jobs := make([]*entity.MessageJob, 0, len(dbJobs))
+	for i := range dbJobs {
+		jobs = append(jobs, mappers.MessageJobToDomain(&dbJobs[i]))
+	}
+	return jobs, nil
+}
+
+func (r *MessageJobRepository) Update(ctx context.Context, job *entity.MessageJob) error {
+	dbJob := mappers.MessageJobToDB(job)
+	if dbJob == nil {
+		return fmt.Errorf("failed to convert message job to db model")
+	}
+
+	err := r.queries.UpdateMessageJob(ctx, database.UpdateMessageJobParams{
+		Status:     dbJob.Status,
+		MessageID:  dbJob.MessageID,
+		Reply:      dbJob.Reply,
+		Error:      dbJob.Error,
+		UpdatedAt:  dbJob.UpdatedAt,
+		FinishedAt: dbJob.FinishedAt,
+		ID:         dbJob.ID,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to update message job")
+	}
+
+	return nil
+}
+
+func (r *MessageJobRepository) FailUnfinished(ctx context.Context, reason string, now time.Time) (int64, error) {
+	formatted := utils.FormatTimeRFC3339(now.UTC())
+	n, err := r.queries.FailUnfinishedMessageJobs(ctx, database.FailUnfinishedMessageJobsParams{
+		Error:      reason,
+		UpdatedAt:  formatted,
+		FinishedAt: sql.NullString{String: formatted, Valid: true},
+	})
+	if err != nil {
+		return 0, wrapWriteError(err, "failed to fail unfinished message jobs")
+	}
+
+	return n, nil
+}
+
+func (r *MessageJobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.queries.DeleteFinishedMessageJobs(ctx, utils.FormatTimeRFC3339(before.UTC()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished message jobs: %w", err)
+	}
+
+	return n, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 	"github.com/atumaikin/nexflow/internal/domain/repository"
@@ -45,7 +46,7 @@ func (r *MessageRepository) Create(ctx context.Context, message *entity.Message)
 func (r *MessageRepository) FindByID(ctx context.Context, id string) (*entity.Message, error) {
 	dbMessage, err := r.queries.GetMessageByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("message not found: %s", id)
+		return nil, fmt.Errorf("message %w: %s", repository.ErrNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find message by id: %w", err)
@@ -54,8 +55,17 @@ func (r *MessageRepository) FindByID(ctx context.Context, id string) (*entity.Me
 	return mappers.MessageToDomain(&dbMessage), nil
 }
 
-func (r *MessageRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.Message, error) {
-	dbMessages, err := r.queries.GetMessagesBySessionID(ctx, sessionID)
+func (r *MessageRepository) FindBySessionID(ctx context.Context, sessionID string, opts repository.QueryOptions) ([]*entity.Message, error) {
+	p := newPageParams(opts)
+	dbMessages, err := r.queries.ListMessagesBySessionID(ctx, database.ListMessagesBySessionIDParams{
+		SessionID:      sessionID,
+		Since:          p.since,
+		Until:          p.until,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+		Offset:         p.offset,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find messages by session id: %w", err)
 	}
@@ -63,11 +73,70 @@ func (r *MessageRepository) FindBySessionID(ctx context.Context, sessionID strin
 	return mappers.MessagesToDomain(dbMessages), nil
 }
 
+func (r *MessageRepository) Search(ctx context.Context, search repository.MessageSearch, opts repository.QueryOptions) ([]*entity.Message, error) {
+	query := ftsQuery(search.Query)
+	if query == "" {
+		return []*entity.Message{}, nil
+	}
+
+	p := newPageParams(opts)
+	dbMessages, err := r.queries.SearchMessages(ctx, database.SearchMessagesParams{
+		Query:          query,
+		UserID:         search.UserID,
+		SessionID:      search.SessionID,
+		WorkspaceID:    search.Workspace,
+		Since:          p.since,
+		Until:          p.until,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+		Offset:         p.offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	return mappers.MessagesToDomain(dbMessages), nil
+}
+
+func (r *MessageRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	count, err := r.queries.CountMessagesOlderThan(ctx, formatOptionalTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count messages older than: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (r *MessageRepository) FindOlderThan(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]*entity.Message, error) {
+	p := newBatchParams(before, opts)
+	dbMessages, err := r.queries.ListMessagesOlderThan(ctx, database.ListMessagesOlderThanParams{
+		Before:         p.before,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find messages older than: %w", err)
+	}
+
+	return mappers.MessagesToDomain(dbMessages), nil
+}
+
+func (r *MessageRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeleteMessagesOlderThan(ctx, formatOptionalTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete messages older than: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
 func (r *MessageRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.queries.GetMessageByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("message not found: %s", id)
+			return fmt.Errorf("message %w: %s", repository.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to check message existence: %w", err)
 	}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+var _ repository.ProcessedMessageRepository = (*ProcessedMessageRepository)(nil)
+
+type ProcessedMessageRepository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func NewProcessedMessageRepository(queries *database.Queries) *ProcessedMessageRepository {
+	return &ProcessedMessageRepository{queries: queries, now: utils.Now}
+}
+
+func (r *ProcessedMessageRepository) Record(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := r.now().UTC()
+	written, err := r.queries.RecordProcessedMessage(ctx, database.RecordProcessedMessageParams{
+		Key:       key,
+		ExpiresAt: utils.FormatTimeRFC3339(now.Add(ttl)),
+		CreatedAt: utils.FormatTimeRFC3339(now),
+	})
+	if err != nil {
+		return false, wrapWriteError(err, "failed to record processed message")
+	}
+
+	return written > 0, nil
+}
+
+func (r *ProcessedMessageRepository) Forget(ctx context.Context, key string) error {
+	if err := r.queries.DeleteProcessedMessage(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete processed message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProcessedMessageRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	n, err := r.queries.DeleteExpiredProcessedMessages(ctx, utils.FormatTimeRFC3339(now.UTC()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired processed messages: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+// pageParams holds repository.QueryOptions in the form the list queries expect:
+// empty strings disable a filter and a limit of -1 returns all rows.
+type pageParams struct {
+	since          string
+	until          string
+	afterCreatedAt string
+	afterID        string
+	workspace      string
+	limit          int64
+	offset         int64
+}
+
+func newPageParams(opts repository.QueryOptions) pageParams {
+	p := pageParams{
+		since:     formatOptionalTime(opts.Since),
+		until:     formatOptionalTime(opts.Until),
+		workspace: opts.Workspace,
+		limit:     -1,
+		offset:    int64(opts.Offset),
+	}
+	if opts.Limit > 0 {
+		p.limit = int64(opts.Limit)
+	}
+	if opts.After != nil {
+		p.afterCreatedAt = formatOptionalTime(opts.After.CreatedAt)
+		p.afterID = opts.After.ID
+	}
+	return p
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return utils.FormatTimeRFC3339(t.UTC())
+}
+
+// ftsQuery turns user input into an FTS MATCH expression that matches every word.
+// Each word is quoted so operators and punctuation in the input are searched for
+// literally instead of being parsed as query syntax.
+func ftsQuery(input string) string {
+	words := strings.Fields(input)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// batchParams holds the cursor of a retention batch query in string form.
+type batchParams struct {
+	before         string
+	afterCreatedAt string
+	afterID        string
+	limit          int64
+}
+
+func newBatchParams(before time.Time, opts repository.QueryOptions) batchParams {
+	p := newPageParams(opts)
+	return batchParams{
+		before:         formatOptionalTime(before),
+		afterCreatedAt: p.afterCreatedAt,
+		afterID:        p.afterID,
+		limit:          p.limit,
+	}
+}
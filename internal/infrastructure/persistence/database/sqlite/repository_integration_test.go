@@ -1,11 +1,21 @@
 package sqlite
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/atumaikin/nexflow/internal/application/dto"
+	"github.com/atumaikin/nexflow/internal/application/usecase"
 	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -67,7 +77,7 @@ func TestSessionRepository_FindByUserID(t *testing.T) {
 	require.NoError(t, sessionRepo.Create(ctx, session2))
 	require.NoError(t, sessionRepo.Create(ctx, session3))
 
-	sessions, err := sessionRepo.FindByUserID(ctx, string(user.ID))
+	sessions, err := sessionRepo.FindByUserID(ctx, string(user.ID), repository.QueryOptions{})
 	require.NoError(t, err)
 	assert.Len(t, sessions, 3)
 }
@@ -87,6 +97,7 @@ func TestSessionRepository_Update(t *testing.T) {
 	require.NoError(t, sessionRepo.Create(ctx, session))
 
 	session.UpdateTimestamp()
+	session.Title = "Trip to Lisbon"
 	err := sessionRepo.Update(ctx, session)
 	require.NoError(t, err)
 
@@ -94,6 +105,7 @@ func TestSessionRepository_Update(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, session.ID, foundSession.ID)
 	assert.Equal(t, session.UserID, foundSession.UserID)
+	assert.Equal(t, "Trip to Lisbon", foundSession.Title)
 }
 
 func TestSessionRepository_Delete(t *testing.T) {
@@ -161,11 +173,348 @@ func TestMessageRepository_FindBySessionID(t *testing.T) {
 	require.NoError(t, messageRepo.Create(ctx, msg1))
 	require.NoError(t, messageRepo.Create(ctx, msg2))
 
-	messages, err := messageRepo.FindBySessionID(ctx, string(session.ID))
+	messages, err := messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{})
 	require.NoError(t, err)
 	assert.Len(t, messages, 2)
 }
 
+func TestMessageRepository_FindBySessionID_Pagination(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	userRepo := NewUserRepository(queries)
+	user := entity.NewUser("telegram", "user123")
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	sessionRepo := NewSessionRepository(queries)
+	session := entity.NewSession(string(user.ID))
+	require.NoError(t, sessionRepo.Create(ctx, session))
+
+	// Two messages share a timestamp so the cursor has to fall back to insertion order.
+	base := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{0, time.Minute, time.Minute, 2 * time.Minute, 3 * time.Minute}
+	messageRepo := NewMessageRepository(queries)
+	created := make([]*entity.Message, 0, len(offsets))
+	for i, offset := range offsets {
+		msg := entity.NewUserMessage(string(session.ID), fmt.Sprintf("message %d", i))
+		msg.CreatedAt = base.Add(offset)
+		require.NoError(t, messageRepo.Create(ctx, msg))
+		created = append(created, msg)
+	}
+
+	page, err := messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, created[0].ID, page[0].ID)
+	assert.Equal(t, created[1].ID, page[1].ID)
+
+	last := page[1]
+	page, err = messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{
+		Limit: 2,
+		After: &repository.Cursor{CreatedAt: last.CreatedAt, ID: string(last.ID)},
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, created[2].ID, page[0].ID)
+	assert.Equal(t, created[3].ID, page[1].ID)
+
+	page, err = messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{Offset: 3})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, created[3].ID, page[0].ID)
+
+	page, err = messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{
+		Since: base.Add(time.Minute),
+		Until: base.Add(3 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	assert.Equal(t, created[1].ID, page[0].ID)
+	assert.Equal(t, created[3].ID, page[2].ID)
+}
+
+func TestSessionRepository_FindByUserID_NewestFirst(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	userRepo := NewUserRepository(queries)
+	user := entity.NewUser("telegram", "user123")
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	sessionRepo := NewSessionRepository(queries)
+	older := entity.NewSession(string(user.ID))
+	older.CreatedAt = time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	newer := entity.NewSession(string(user.ID))
+	newer.CreatedAt = older.CreatedAt.Add(time.Hour)
+	require.NoError(t, sessionRepo.Create(ctx, older))
+	require.NoError(t, sessionRepo.Create(ctx, newer))
+
+	sessions, err := sessionRepo.FindByUserID(ctx, string(user.ID), repository.QueryOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, newer.ID, sessions[0].ID)
+
+	sessions, err = sessionRepo.FindByUserID(ctx, string(user.ID), repository.QueryOptions{
+		After: &repository.Cursor{CreatedAt: newer.CreatedAt, ID: string(newer.ID)},
+	})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, older.ID, sessions[0].ID)
+}
+
+func TestSessionRepository_List(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	userRepo := NewUserRepository(queries)
+	alice := entity.NewUser("telegram", "alice")
+	bob := entity.NewUser("discord", "bob")
+	require.NoError(t, userRepo.Create(ctx, alice))
+	require.NoError(t, userRepo.Create(ctx, bob))
+
+	sessionRepo := NewSessionRepository(queries)
+	older := entity.NewSession(string(alice.ID))
+	older.CreatedAt = time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	newer := entity.NewSession(string(bob.ID))
+	newer.CreatedAt = older.CreatedAt.Add(time.Hour)
+	require.NoError(t, sessionRepo.Create(ctx, older))
+	require.NoError(t, sessionRepo.Create(ctx, newer))
+
+	sessions, err := sessionRepo.List(ctx, repository.QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, newer.ID, sessions[0].ID)
+	assert.Equal(t, older.ID, sessions[1].ID)
+
+	sessions, err = sessionRepo.List(ctx, repository.QueryOptions{
+		Limit: 1,
+		After: &repository.Cursor{CreatedAt: newer.CreatedAt, ID: string(newer.ID)},
+	})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, older.ID, sessions[0].ID)
+}
+
+func TestSkillRepository_Disabled(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	skillRepo := NewSkillRepository(database.New(db))
+	skill := entity.NewSkill("weather", "1.0.0", "/skills/weather", nil, nil)
+	require.NoError(t, skillRepo.Create(ctx, skill))
+
+	found, err := skillRepo.FindByName(ctx, "weather")
+	require.NoError(t, err)
+	assert.True(t, found.IsEnabled())
+
+	found.Disable()
+	require.NoError(t, skillRepo.Update(ctx, found))
+
+	found, err = skillRepo.FindByID(ctx, string(skill.ID))
+	require.NoError(t, err)
+	assert.True(t, found.Disabled)
+}
+
+func TestMessageRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	userRepo := NewUserRepository(queries)
+	alice := entity.NewUser("telegram", "alice")
+	bob := entity.NewUser("telegram", "bob")
+	require.NoError(t, userRepo.Create(ctx, alice))
+	require.NoError(t, userRepo.Create(ctx, bob))
+
+	sessionRepo := NewSessionRepository(queries)
+	aliceSession := entity.NewSession(string(alice.ID))
+	bobSession := entity.NewSession(string(bob.ID))
+	require.NoError(t, sessionRepo.Create(ctx, aliceSession))
+	require.NoError(t, sessionRepo.Create(ctx, bobSession))
+
+	base := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	messageRepo := NewMessageRepository(queries)
+	create := func(sessionID, content string, offset time.Duration) *entity.Message {
+		msg := entity.NewUserMessage(sessionID, content)
+		msg.CreatedAt = base.Add(offset)
+		require.NoError(t, messageRepo.Create(ctx, msg))
+		return msg
+	}
+	older := create(string(aliceSession.ID), "Weather forecast for Moscow", 0)
+	newer := create(string(aliceSession.ID), "Is the weather in Berlin sunny?", time.Minute)
+	create(string(aliceSession.ID), "Remind me about the meeting", 2*time.Minute)
+	create(string(bobSession.ID), "Weather in Paris", 3*time.Minute)
+
+	found, err := messageRepo.Search(ctx, repository.MessageSearch{Query: "weather", UserID: string(alice.ID)}, repository.QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, newer.ID, found[0].ID)
+	assert.Equal(t, older.ID, found[1].ID)
+
+	found, err = messageRepo.Search(ctx, repository.MessageSearch{Query: "WEATHER moscow"}, repository.QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, older.ID, found[0].ID)
+
+	found, err = messageRepo.Search(ctx, repository.MessageSearch{Query: "weather"}, repository.QueryOptions{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, newer.ID, found[0].ID)
+
+	// Query syntax in user input is searched for literally instead of failing
+	found, err = messageRepo.Search(ctx, repository.MessageSearch{Query: `sunny? "OR NEAR(`}, repository.QueryOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	require.NoError(t, messageRepo.Delete(ctx, string(older.ID)))
+	found, err = messageRepo.Search(ctx, repository.MessageSearch{Query: "moscow"}, repository.QueryOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestRepositories_OlderThanSkipsPinnedSessions(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	userRepo := NewUserRepository(queries)
+	user := entity.NewUser("telegram", "retention")
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	sessionRepo := NewSessionRepository(queries)
+	regular := entity.NewSession(string(user.ID))
+	pinned := entity.NewSession(string(user.ID))
+	pinned.Pin()
+	require.NoError(t, sessionRepo.Create(ctx, regular))
+	require.NoError(t, sessionRepo.Create(ctx, pinned))
+
+	found, err := sessionRepo.FindByID(ctx, string(pinned.ID))
+	require.NoError(t, err)
+	assert.True(t, found.IsPinned())
+
+	cutoff := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	messageRepo := NewMessageRepository(queries)
+	taskRepo := NewTaskRepository(queries)
+	var oldMessages []*entity.Message
+	for i, sessionID := range []string{string(regular.ID), string(regular.ID), string(pinned.ID)} {
+		msg := entity.NewUserMessage(sessionID, "old")
+		msg.CreatedAt = cutoff.Add(-time.Duration(i+1) * time.Hour)
+		require.NoError(t, messageRepo.Create(ctx, msg))
+		oldMessages = append(oldMessages, msg)
+
+		task := entity.NewTask(sessionID, "echo", "{}")
+		task.CreatedAt = msg.CreatedAt
+		require.NoError(t, taskRepo.Create(ctx, task))
+	}
+	recent := entity.NewUserMessage(string(regular.ID), "recent")
+	recent.CreatedAt = cutoff.Add(time.Hour)
+	require.NoError(t, messageRepo.Create(ctx, recent))
+
+	count, err := messageRepo.CountOlderThan(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	page, err := messageRepo.FindOlderThan(ctx, cutoff, repository.QueryOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, oldMessages[1].ID, page[0].ID)
+
+	page, err = messageRepo.FindOlderThan(ctx, cutoff, repository.QueryOptions{
+		Limit: 1,
+		After: &repository.Cursor{CreatedAt: page[0].CreatedAt, ID: string(page[0].ID)},
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, oldMessages[0].ID, page[0].ID)
+
+	deleted, err := messageRepo.DeleteOlderThan(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	remaining, err := messageRepo.FindBySessionID(ctx, string(pinned.ID), repository.QueryOptions{})
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+	remaining, err = messageRepo.FindBySessionID(ctx, string(regular.ID), repository.QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, recent.ID, remaining[0].ID)
+
+	deleted, err = taskRepo.DeleteOlderThan(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	tasks, err := taskRepo.FindBySessionID(ctx, string(pinned.ID), repository.QueryOptions{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func TestLogRepository_DeleteOlderThan(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	logRepo := NewLogRepository(database.New(db))
+	cutoff := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	for _, offset := range []time.Duration{-2 * time.Hour, -time.Hour, time.Hour} {
+		log := entity.NewLog(valueobject.LogLevelInfo, "test", "entry", nil)
+		log.CreatedAt = cutoff.Add(offset)
+		require.NoError(t, logRepo.Create(ctx, log))
+	}
+
+	count, err := logRepo.CountOlderThan(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	deleted, err := logRepo.DeleteOlderThan(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	count, err = logRepo.CountOlderThan(ctx, cutoff.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestLogRepository_List(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	logRepo := NewLogRepository(database.New(db))
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	logs := []*entity.Log{
+		entity.NewLog(valueobject.LogLevelInfo, "router", "message routed", nil),
+		entity.NewLog(valueobject.LogLevelError, "telegram", "connector failed", nil),
+		entity.NewLog(valueobject.LogLevelInfo, "telegram", "connector started", nil),
+	}
+	for i, log := range logs {
+		log.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, logRepo.Create(ctx, log))
+	}
+
+	all, err := logRepo.List(ctx, repository.LogFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, logs[2].ID, all[0].ID, "newest log first")
+
+	filtered, err := logRepo.List(ctx, repository.LogFilter{Level: "info", Source: "telegram"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, logs[2].ID, filtered[0].ID)
+
+	page, err := logRepo.List(ctx, repository.LogFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, logs[1].ID, page[0].ID)
+}
+
 func TestMessageRepository_Roles(t *testing.T) {
 	ctx := context.Background()
 	db := setupTestDB(t)
@@ -187,10 +536,1335 @@ func TestMessageRepository_Roles(t *testing.T) {
 	require.NoError(t, messageRepo.Create(ctx, userMsg))
 	require.NoError(t, messageRepo.Create(ctx, assistantMsg))
 
-	messages, err := messageRepo.FindBySessionID(ctx, string(session.ID))
+	messages, err := messageRepo.FindBySessionID(ctx, string(session.ID), repository.QueryOptions{})
 	require.NoError(t, err)
 
 	assert.Len(t, messages, 2)
 	assert.True(t, messages[0].IsFromUser())
 	assert.True(t, messages[1].IsFromAssistant())
 }
+
+func TestScheduleRunRepository_History(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	require.NoError(t, NewSkillRepository(queries).Create(ctx, entity.NewSkill("weather", "1.0.0", "skills/weather", nil, nil)))
+
+	scheduleRepo := NewScheduleRepository(queries)
+	schedule := entity.NewSchedule("weather", "0 * * * *", "{}")
+	schedule.SetMissedRunPolicy(valueobject.MissedRunAll)
+	require.NoError(t, scheduleRepo.Create(ctx, schedule))
+
+	runRepo := NewScheduleRunRepository(queries)
+
+	_, err := runRepo.FindLatest(ctx, string(schedule.ID))
+	assert.Error(t, err)
+
+	base := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	first := entity.NewScheduleRun(string(schedule.ID), base)
+	second := entity.NewScheduleRun(string(schedule.ID), base.Add(time.Hour))
+	require.NoError(t, runRepo.Create(ctx, first))
+	require.NoError(t, runRepo.Create(ctx, second))
+
+	second.SetFailed("boom")
+	require.NoError(t, runRepo.Update(ctx, second))
+
+	latest, err := runRepo.FindLatest(ctx, string(schedule.ID))
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, latest.ID)
+	assert.Equal(t, valueobject.TaskStatusFailed, latest.Status)
+	assert.Equal(t, "boom", latest.Error)
+	assert.True(t, latest.IsFinished())
+	assert.True(t, base.Add(time.Hour).Equal(latest.ScheduledAt))
+
+	runs, err := runRepo.FindByScheduleID(ctx, string(schedule.ID), 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, second.ID, runs[0].ID)
+	assert.Equal(t, first.ID, runs[1].ID)
+	assert.False(t, runs[1].IsFinished())
+
+	found, err := scheduleRepo.FindByID(ctx, string(schedule.ID))
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.MissedRunAll, found.MissedRunPolicy)
+
+	// Runs are removed together with their schedule
+	require.NoError(t, scheduleRepo.Delete(ctx, string(schedule.ID)))
+	runs, err = runRepo.FindByScheduleID(ctx, string(schedule.ID), 10)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestScheduleRepository_PromptSchedule(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	scheduleRepo := NewScheduleRepository(database.New(db))
+	schedule := entity.NewPromptSchedule("Summarize the news of {{.Date}}", "0 8 * * *", "{}")
+	schedule.SetTarget("telegram", "123456")
+	require.NoError(t, scheduleRepo.Create(ctx, schedule))
+
+	found, err := scheduleRepo.FindByID(ctx, string(schedule.ID))
+	require.NoError(t, err)
+	assert.True(t, found.IsPrompt())
+	assert.Empty(t, found.Skill)
+	assert.Equal(t, "Summarize the news of {{.Date}}", found.Prompt)
+	assert.Empty(t, found.SessionID)
+
+	found.SessionID = "session-1"
+	require.NoError(t, scheduleRepo.Update(ctx, found))
+	found, err = scheduleRepo.FindByID(ctx, string(schedule.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", found.SessionID)
+
+	// Prompt schedules belong to no skill
+	bySkill, err := scheduleRepo.FindBySkill(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, bySkill)
+}
+
+func TestSessionAttributeRepository_GetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	user := entity.NewUser("telegram", "user123")
+	require.NoError(t, NewUserRepository(queries).Create(ctx, user))
+	session := entity.NewSession(string(user.ID))
+	require.NoError(t, NewSessionRepository(queries).Create(ctx, session))
+
+	attrRepo := NewSessionAttributeRepository(queries)
+	sessionID := string(session.ID)
+
+	attr := entity.NewSessionAttribute(sessionID, "step", `"collect_email"`, 0)
+	require.NoError(t, attrRepo.Set(ctx, attr))
+	createdAt := attr.CreatedAt
+
+	found, err := attrRepo.Get(ctx, sessionID, "step")
+	require.NoError(t, err)
+	assert.Equal(t, `"collect_email"`, found.Value)
+	assert.Nil(t, found.ExpiresAt)
+
+	// Setting an existing key replaces the value and keeps the creation time
+	updated := entity.NewSessionAttribute(sessionID, "step", `"confirm"`, 0)
+	updated.CreatedAt = createdAt.Add(time.Hour)
+	require.NoError(t, attrRepo.Set(ctx, updated))
+	assert.True(t, createdAt.Equal(updated.CreatedAt))
+
+	require.NoError(t, attrRepo.Set(ctx, entity.NewSessionAttribute(sessionID, "lang", `"ru"`, 0)))
+
+	attrs, err := attrRepo.FindBySessionID(ctx, sessionID)
+	require.NoError(t, err)
+	require.Len(t, attrs, 2)
+	assert.Equal(t, "lang", attrs[0].Key)
+	assert.Equal(t, "step", attrs[1].Key)
+	assert.Equal(t, `"confirm"`, attrs[1].Value)
+
+	deleted, err := attrRepo.Delete(ctx, sessionID, "step")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = attrRepo.Delete(ctx, sessionID, "step")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	_, err = attrRepo.Get(ctx, sessionID, "step")
+	assert.Error(t, err)
+}
+
+func TestSessionAttributeRepository_Expiry(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	user := entity.NewUser("telegram", "user123")
+	require.NoError(t, NewUserRepository(queries).Create(ctx, user))
+	session := entity.NewSession(string(user.ID))
+	require.NoError(t, NewSessionRepository(queries).Create(ctx, session))
+
+	attrRepo := NewSessionAttributeRepository(queries)
+	sessionID := string(session.ID)
+
+	require.NoError(t, attrRepo.Set(ctx, entity.NewSessionAttribute(sessionID, "otp", `"123456"`, time.Hour)))
+	require.NoError(t, attrRepo.Set(ctx, entity.NewSessionAttribute(sessionID, "lang", `"ru"`, 0)))
+
+	_, err := attrRepo.Get(ctx, sessionID, "otp")
+	require.NoError(t, err)
+
+	// Two hours later the attribute is expired and no longer returned
+	attrRepo.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	_, err = attrRepo.Get(ctx, sessionID, "otp")
+	assert.Error(t, err)
+
+	attrs, err := attrRepo.FindBySessionID(ctx, sessionID)
+	require.NoError(t, err)
+	require.Len(t, attrs, 1)
+	assert.Equal(t, "lang", attrs[0].Key)
+
+	purged, err := attrRepo.DeleteExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	purged, err = attrRepo.DeleteExpired(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestAPIKeyRepository_CreateGetRevoke(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(database.New(db))
+
+	apiKey, key := entity.NewAPIKey("ci", valueobject.APIKeyScopeRead)
+	require.NoError(t, repo.Create(ctx, apiKey))
+
+	found, err := repo.GetByHash(ctx, entity.HashAPIKey(key))
+	require.NoError(t, err)
+	assert.Equal(t, apiKey.ID, found.ID)
+	assert.Equal(t, valueobject.APIKeyScopeRead, found.Scope)
+	assert.False(t, found.IsRevoked())
+
+	_, err = repo.GetByHash(ctx, entity.HashAPIKey("nfx_unknown"))
+	assert.Error(t, err)
+
+	other, _ := entity.NewAPIKey("admin", valueobject.APIKeyScopeAdmin)
+	require.NoError(t, repo.Create(ctx, other))
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, apiKey.ID, keys[0].ID)
+
+	revoked, err := repo.Revoke(ctx, string(apiKey.ID), time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// Revoking again reports that the key was no longer active
+	revoked, err = repo.Revoke(ctx, string(apiKey.ID), time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	found, err = repo.GetByHash(ctx, entity.HashAPIKey(key))
+	require.NoError(t, err)
+	assert.True(t, found.IsRevoked())
+}
+
+func TestEventRepository_AppendList(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewEventRepository(database.New(db))
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []*entity.StoredEvent{
+		entity.NewStoredEvent("router.message", `{"user_id":"user-1"}`, base),
+		entity.NewStoredEvent("schedule.completed", `{"schedule_id":"schedule-1"}`, base.Add(time.Minute)),
+		entity.NewStoredEvent("router.message", `{"user_id":"user-2"}`, base.Add(2*time.Minute)),
+		entity.NewStoredEvent("router.error", `{"error":"boom"}`, base.Add(3*time.Minute)),
+	}
+	require.NoError(t, repo.Append(ctx, events...))
+	for i, event := range events {
+		assert.Equal(t, int64(i+1), event.Seq)
+	}
+
+	all, err := repo.List(ctx, repository.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, events[0].ID, all[0].ID)
+	assert.Equal(t, `{"user_id":"user-1"}`, all[0].Payload)
+	assert.Equal(t, base, all[0].OccurredAt)
+
+	byType, err := repo.List(ctx, repository.EventFilter{Types: []string{"router.message", "router.error"}})
+	require.NoError(t, err)
+	require.Len(t, byType, 3)
+	assert.Equal(t, []int64{1, 3, 4}, []int64{byType[0].Seq, byType[1].Seq, byType[2].Seq})
+
+	byTime, err := repo.List(ctx, repository.EventFilter{Since: base.Add(time.Minute), Until: base.Add(3 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, byTime, 2)
+	assert.Equal(t, events[1].ID, byTime[0].ID)
+	assert.Equal(t, events[2].ID, byTime[1].ID)
+
+	page, err := repo.List(ctx, repository.EventFilter{AfterSeq: 2, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, events[2].ID, page[0].ID)
+
+	found, err := repo.GetByID(ctx, events[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), found.Seq)
+	assert.Equal(t, "schedule.completed", found.Type)
+
+	_, err = repo.GetByID(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// Storing the same event twice is a conflict
+	err = repo.Append(ctx, events[0])
+	assert.ErrorIs(t, err, repository.ErrConflict)
+}
+
+func TestEventRepository_Subject(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewEventRepository(database.New(db))
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []*entity.StoredEvent{
+		entity.NewStoredEvent("router.message", `{"user_id":"user-1","content":"Hello"}`, base),
+		entity.NewStoredEvent("session.created", `{"session_id":"session-1"}`, base.Add(time.Minute)),
+		entity.NewStoredEvent("router.message", `{"user_id":"user-2","content":"Hi"}`, base.Add(2*time.Minute)),
+		entity.NewStoredEvent("legacy", `not json`, base.Add(3*time.Minute)),
+		entity.NewStoredEvent("router.message", `{"user_id":"42","source":"telegram"}`, base.Add(4*time.Minute)),
+		entity.NewStoredEvent("connector.message", `{"user_id":"42","connector":"discord"}`, base.Add(5*time.Minute)),
+	}
+	require.NoError(t, repo.Append(ctx, events...))
+
+	subject := repository.EventSubject{
+		UserIDs:      []string{"user-1"},
+		ChannelUsers: []repository.ChannelUser{{Connector: "telegram", UserID: "42"}},
+		SessionIDs:   []string{"session-1"},
+	}
+	about, err := repo.ListBySubject(ctx, subject)
+	require.NoError(t, err)
+	require.Len(t, about, 3)
+	assert.Equal(t, events[0].ID, about[0].ID)
+	assert.Equal(t, events[1].ID, about[1].ID)
+	assert.Equal(t, events[4].ID, about[2].ID, "the same user ID of another connector is not matched")
+
+	none, err := repo.ListBySubject(ctx, repository.EventSubject{})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	deleted, err := repo.DeleteBySubject(ctx, subject)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+
+	left, err := repo.List(ctx, repository.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, left, 3)
+	assert.Equal(t, events[2].ID, left[0].ID)
+	assert.Equal(t, events[3].ID, left[1].ID)
+	assert.Equal(t, events[5].ID, left[2].ID)
+}
+
+func TestWebhookDeliveryRepository_CreateUpdateList(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepository(database.New(db))
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deliveries := []*entity.WebhookDelivery{
+		entity.NewWebhookDelivery("ci", "https://ci.example.com/hook", "task.completed", `{"type":"task.completed"}`),
+		entity.NewWebhookDelivery("alerts", "https://alerts.example.com/hook", "connector.error", `{"type":"connector.error"}`),
+		entity.NewWebhookDelivery("ci", "https://ci.example.com/hook", "router.message", `{"type":"router.message"}`),
+	}
+	for i, delivery := range deliveries {
+		delivery.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		delivery.UpdatedAt = delivery.CreatedAt
+		require.NoError(t, repo.Create(ctx, delivery))
+	}
+
+	deliveries[0].SetDelivered(200)
+	require.NoError(t, repo.Update(ctx, deliveries[0]))
+	deliveries[1].SetFailed(400, "unexpected status 400")
+	require.NoError(t, repo.Update(ctx, deliveries[1]))
+
+	all, err := repo.List(ctx, repository.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, deliveries[2].ID, all[0].ID, "newest delivery first")
+	assert.Equal(t, deliveries[0].ID, all[2].ID)
+	assert.Equal(t, entity.WebhookDeliveryDelivered, all[2].Status)
+	assert.Equal(t, 1, all[2].Attempts)
+	assert.Equal(t, 200, all[2].ResponseStatus)
+
+	byEndpoint, err := repo.List(ctx, repository.WebhookDeliveryFilter{Endpoint: "ci"})
+	require.NoError(t, err)
+	require.Len(t, byEndpoint, 2)
+
+	failed, err := repo.List(ctx, repository.WebhookDeliveryFilter{Status: entity.WebhookDeliveryFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "unexpected status 400", failed[0].Error)
+
+	page, err := repo.List(ctx, repository.WebhookDeliveryFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, deliveries[1].ID, page[0].ID)
+
+	missing := entity.NewWebhookDelivery("ci", "https://ci.example.com/hook", "task.completed", "{}")
+	assert.ErrorIs(t, repo.Update(ctx, missing), repository.ErrNotFound)
+}
+
+func TestDeadLetterRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewDeadLetterRepository(database.New(db))
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	letters := []*entity.DeadLetter{
+		entity.NewDeadLetter("webhooks", "task.completed", `{"task_id":"task-1"}`, base, 3, "boom"),
+		entity.NewDeadLetter("audit", "user.created", `{"user_id":"user-1"}`, base, 1, "disk full"),
+		entity.NewDeadLetter("webhooks", "connector.error", `{"connector_name":"telegram"}`, base, 3, "timeout"),
+	}
+	for i, letter := range letters {
+		letter.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		letter.UpdatedAt = letter.CreatedAt
+		require.NoError(t, repo.Create(ctx, letter))
+	}
+
+	got, err := repo.GetByID(ctx, letters[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "webhooks", got.Subscription)
+	assert.Equal(t, `{"task_id":"task-1"}`, got.Payload)
+	assert.True(t, base.Equal(got.OccurredAt))
+	assert.Equal(t, 3, got.Attempts)
+
+	letters[0].RecordRedelivery("still failing")
+	require.NoError(t, repo.Update(ctx, letters[0]))
+	got, err = repo.GetByID(ctx, letters[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, got.Attempts)
+	assert.Equal(t, "still failing", got.Error)
+
+	all, err := repo.List(ctx, repository.DeadLetterFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, letters[2].ID, all[0].ID, "newest dead letter first")
+
+	bySubscription, err := repo.List(ctx, repository.DeadLetterFilter{Subscription: "webhooks"})
+	require.NoError(t, err)
+	require.Len(t, bySubscription, 2)
+
+	page, err := repo.List(ctx, repository.DeadLetterFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, letters[1].ID, page[0].ID)
+
+	require.NoError(t, repo.Delete(ctx, letters[1].ID))
+	_, err = repo.GetByID(ctx, letters[1].ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, letters[1].ID), repository.ErrNotFound)
+	assert.ErrorIs(t, repo.Update(ctx, letters[1]), repository.ErrNotFound)
+}
+
+func TestProcessedMessageRepository_Record(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProcessedMessageRepository(database.New(db))
+
+	first, err := repo.Record(ctx, "telegram:100:1", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	first, err = repo.Record(ctx, "telegram:100:1", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, first, "a message recorded within the TTL is a duplicate")
+
+	first, err = repo.Record(ctx, "telegram:100:2", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	// A forgotten message is recorded again
+	require.NoError(t, repo.Forget(ctx, "telegram:100:2"))
+	first, err = repo.Record(ctx, "telegram:100:2", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	// Two hours later the records are expired and can be recorded again
+	repo.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	first, err = repo.Record(ctx, "telegram:100:1", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	purged, err := repo.DeleteExpired(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestIdempotencyRepository_SaveFind(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewIdempotencyRepository(database.New(db))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	record := &entity.IdempotencyRecord{
+		Key:         "key-1",
+		RequestHash: "hash-1",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"success":true}`),
+		ExpiresAt:   now.Add(time.Hour),
+		CreatedAt:   now,
+	}
+
+	_, err := repo.Find(ctx, "key-1")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	saved, err := repo.Save(ctx, record)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	found, err := repo.Find(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, record, found)
+
+	// An unexpired record is not replaced
+	other := *record
+	other.RequestHash = "hash-2"
+	saved, err = repo.Save(ctx, &other)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	// Two hours later the record is expired, not found and can be saved again
+	repo.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = repo.Find(ctx, "key-1")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	other.CreatedAt = now.Add(2 * time.Hour)
+	other.ExpiresAt = now.Add(3 * time.Hour)
+	saved, err = repo.Save(ctx, &other)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	purged, err := repo.DeleteExpired(ctx, now.Add(4*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestIdempotencyRepository_ReserveRelease(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewIdempotencyRepository(database.New(db))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	pending := &entity.IdempotencyRecord{Key: "key-1", RequestHash: "hash-1", ExpiresAt: now.Add(time.Minute), CreatedAt: now}
+
+	reserved, err := repo.Reserve(ctx, pending)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	found, err := repo.Find(ctx, "key-1")
+	require.NoError(t, err)
+	assert.True(t, found.IsPending())
+
+	// A pending key is reserved once
+	reserved, err = repo.Reserve(ctx, pending)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+
+	// A released key can be reserved again
+	require.NoError(t, repo.Release(ctx, "key-1"))
+	_, err = repo.Find(ctx, "key-1")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	reserved, err = repo.Reserve(ctx, pending)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	// The response of another request does not replace the pending key
+	response := &entity.IdempotencyRecord{
+		Key:         "key-1",
+		RequestHash: "hash-2",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"success":true}`),
+		ExpiresAt:   now.Add(time.Hour),
+		CreatedAt:   now,
+	}
+	saved, err := repo.Save(ctx, response)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	// The response of the request does, and is not released
+	response.RequestHash = "hash-1"
+	saved, err = repo.Save(ctx, response)
+	require.NoError(t, err)
+	assert.True(t, saved)
+	require.NoError(t, repo.Release(ctx, "key-1"))
+	found, err = repo.Find(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, response, found)
+}
+
+func TestJobRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewJobRepository(database.New(db))
+	job := entity.NewJob("webhook_delivery", "delivery:1", `{"delivery_id": "1"}`, 2)
+	require.NoError(t, repo.Create(ctx, job))
+	assert.ErrorIs(t, repo.Create(ctx, entity.NewJob("webhook_delivery", "delivery:1", "{}", 2)), repository.ErrConflict, "a key is queued once")
+	later := entity.NewJob("webhook_delivery", "", "{}", 1)
+	later.RunAt = time.Now().Add(time.Hour)
+	require.NoError(t, repo.Create(ctx, later))
+
+	now := time.Now()
+	claimed, err := repo.Claim(ctx, "worker-1", false, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, claimed.ID)
+	assert.Equal(t, entity.JobRunning, claimed.Status)
+	assert.Equal(t, 1, claimed.Attempts)
+	assert.Equal(t, "worker-1", claimed.LockedBy)
+	assert.Equal(t, `{"delivery_id": "1"}`, claimed.Payload)
+
+	_, err = repo.Claim(ctx, "worker-2", false, now, now.Add(time.Minute))
+	assert.ErrorIs(t, err, repository.ErrNotFound, "leased and future jobs are not due")
+
+	// The lease expires, e.g. because the process of worker-1 stopped
+	expired := now.Add(2 * time.Minute)
+	reclaimed, err := repo.Claim(ctx, "worker-2", false, expired, expired.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, reclaimed.ID)
+	assert.Equal(t, 2, reclaimed.Attempts)
+
+	claimed.Complete()
+	assert.ErrorIs(t, repo.Update(ctx, claimed, "worker-1"), repository.ErrNotFound, "the lease was lost")
+	reclaimed.Complete()
+	require.NoError(t, repo.Update(ctx, reclaimed, "worker-2"))
+
+	found, err := repo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.JobCompleted, found.Status)
+	assert.Empty(t, found.LockedBy)
+	require.NotNil(t, found.FinishedAt)
+
+	n, err := repo.DeleteFinishedBefore(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	_, err = repo.FindByID(ctx, job.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repo.FindByID(ctx, later.ID)
+	assert.NoError(t, err, "unfinished jobs are kept")
+}
+
+func TestJobRepository_ClaimLeaderOnly(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewJobRepository(database.New(db))
+	job := entity.NewJob("connector_send", "", "{}", 1)
+	job.LeaderOnly = true
+	require.NoError(t, repo.Create(ctx, job))
+
+	now := time.Now()
+	_, err := repo.Claim(ctx, "follower", false, now, now.Add(time.Minute))
+	assert.ErrorIs(t, err, repository.ErrNotFound, "leader-only jobs are not due for followers")
+
+	claimed, err := repo.Claim(ctx, "leader", true, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, claimed.ID)
+	assert.True(t, claimed.LeaderOnly)
+}
+
+func TestLeaseRepository_AcquireRelease(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewLeaseRepository(database.New(db))
+	now := time.Now()
+
+	acquired, err := repo.Acquire(ctx, "leader", "instance-1", now, now.Add(15*time.Second))
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = repo.Acquire(ctx, "leader", "instance-2", now, now.Add(15*time.Second))
+	require.NoError(t, err)
+	assert.False(t, acquired, "the lease is held by instance-1")
+
+	acquired, err = repo.Acquire(ctx, "leader", "instance-1", now.Add(5*time.Second), now.Add(20*time.Second))
+	require.NoError(t, err)
+	assert.True(t, acquired, "the holder renews its lease")
+
+	// The lease expires, e.g. because instance-1 stopped
+	later := now.Add(30 * time.Second)
+	acquired, err = repo.Acquire(ctx, "leader", "instance-2", later, later.Add(15*time.Second))
+	require.NoError(t, err)
+	assert.True(t, acquired, "an expired lease is taken over")
+
+	require.NoError(t, repo.Release(ctx, "leader", "instance-1"), "releasing a lost lease is a no-op")
+	acquired, err = repo.Acquire(ctx, "leader", "instance-1", later, later.Add(15*time.Second))
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	require.NoError(t, repo.Release(ctx, "leader", "instance-2"))
+	acquired, err = repo.Acquire(ctx, "leader", "instance-1", later, later.Add(15*time.Second))
+	require.NoError(t, err)
+	assert.True(t, acquired, "a released lease is free")
+}
+
+func TestMessageJobRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	user := entity.NewUser("web", "user123")
+	require.NoError(t, NewUserRepository(queries).Create(ctx, user))
+	session := entity.NewSession(string(user.ID))
+	require.NoError(t, NewSessionRepository(queries).Create(ctx, session))
+
+	repo := NewMessageJobRepository(queries)
+
+	_, err := repo.FindByID(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	completed := entity.NewMessageJob(string(session.ID), "Hello")
+	require.NoError(t, repo.Create(ctx, completed))
+	completed.Start()
+	completed.Complete("message-1", "Hi there")
+	require.NoError(t, repo.Update(ctx, completed))
+
+	found, err := repo.FindByID(ctx, completed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageJobCompleted, found.Status)
+	assert.Equal(t, "message-1", found.MessageID.String())
+	assert.Equal(t, "Hi there", found.Reply)
+	require.NotNil(t, found.FinishedAt)
+
+	running := entity.NewMessageJob(string(session.ID), "Are you there?")
+	require.NoError(t, repo.Create(ctx, running))
+	running.Start()
+	require.NoError(t, repo.Update(ctx, running))
+	require.NoError(t, repo.Create(ctx, entity.NewMessageJob(string(session.ID), "Still there?")))
+
+	// Jobs left behind by a stopped process are failed
+	failed, err := repo.FailUnfinished(ctx, "interrupted", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), failed)
+
+	found, err = repo.FindByID(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MessageJobFailed, found.Status)
+	assert.Equal(t, "interrupted", found.Error)
+	assert.NotNil(t, found.FinishedAt)
+
+	// Jobs of a batch are listed in their order
+	for i, content := range []string{"First", "Second"} {
+		require.NoError(t, repo.Create(ctx, entity.NewBatchMessageJob("batch-1", 1-i, string(session.ID), content)))
+	}
+	batch, err := repo.FindByBatchID(ctx, "batch-1")
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	assert.Equal(t, "Second", batch[0].Content)
+	assert.Equal(t, 1, batch[1].BatchIndex)
+	batch, err = repo.FindByBatchID(ctx, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, batch)
+
+	deleted, err := repo.DeleteFinishedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	deleted, err = repo.DeleteFinishedBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
+
+func TestMessageDeadLetterRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMessageDeadLetterRepository(database.New(db))
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	letters := []*entity.MessageDeadLetter{
+		entity.NewMessageDeadLetter("telegram", "tg:42", "100", "Hello", map[string]interface{}{"message_id": 1}, "llm unavailable"),
+		entity.NewMessageDeadLetter("web", "web-user", "", "Hi", nil, "session not found"),
+		entity.NewMessageDeadLetter("telegram", "tg:43", "101", "Ping", nil, "timeout"),
+	}
+	for i, letter := range letters {
+		letter.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		letter.UpdatedAt = letter.CreatedAt
+		require.NoError(t, repo.Create(ctx, letter))
+	}
+
+	got, err := repo.GetByID(ctx, letters[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "telegram", got.Connector)
+	assert.Equal(t, "tg:42", got.UserID)
+	assert.Equal(t, "100", got.ChannelID)
+	assert.Equal(t, "Hello", got.Content)
+	assert.Equal(t, `{"message_id":1}`, got.Metadata)
+	assert.Equal(t, 1, got.Attempts)
+
+	letters[0].RecordReprocess("still failing")
+	require.NoError(t, repo.Update(ctx, letters[0]))
+	got, err = repo.GetByID(ctx, letters[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Attempts)
+	assert.Equal(t, "still failing", got.Error)
+
+	all, err := repo.List(ctx, repository.MessageDeadLetterFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, letters[2].ID, all[0].ID, "newest dead letter first")
+
+	byConnector, err := repo.List(ctx, repository.MessageDeadLetterFilter{Connector: "telegram"})
+	require.NoError(t, err)
+	require.Len(t, byConnector, 2)
+
+	byUser, err := repo.List(ctx, repository.MessageDeadLetterFilter{UserID: "tg:43"})
+	require.NoError(t, err)
+	require.Len(t, byUser, 1)
+	assert.Equal(t, letters[2].ID, byUser[0].ID)
+
+	page, err := repo.List(ctx, repository.MessageDeadLetterFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, letters[1].ID, page[0].ID)
+
+	require.NoError(t, repo.Delete(ctx, letters[1].ID))
+	_, err = repo.GetByID(ctx, letters[1].ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, letters[1].ID), repository.ErrNotFound)
+	assert.ErrorIs(t, repo.Update(ctx, letters[1]), repository.ErrNotFound)
+
+	deleted, err := repo.DeleteByUser(ctx, "web", "tg:43")
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "dead letters of other connectors are kept")
+	deleted, err = repo.DeleteByUser(ctx, "telegram", "tg:43")
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	left, err := repo.List(ctx, repository.MessageDeadLetterFilter{})
+	require.NoError(t, err)
+	require.Len(t, left, 1)
+	assert.Equal(t, letters[0].ID, left[0].ID)
+}
+
+func TestSpilledMessageRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewSpilledMessageRepository(database.New(db))
+
+	messages := []*entity.SpilledMessage{
+		entity.NewSpilledMessage("telegram", "tg:42", "100", "Third", nil, 3),
+		entity.NewSpilledMessage("telegram", "tg:42", "100", "First", map[string]interface{}{"message_id": 1}, 1),
+		entity.NewSpilledMessage("telegram-support", "tg:43", "101", "Other bot", nil, 2),
+		entity.NewSpilledMessage("telegram", "tg:44", "102", "Second", nil, 2),
+	}
+	for _, message := range messages {
+		require.NoError(t, repo.Create(ctx, message))
+	}
+
+	count, err := repo.Count(ctx, "telegram")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	oldest, err := repo.List(ctx, "telegram", 2)
+	require.NoError(t, err)
+	require.Len(t, oldest, 2)
+	assert.Equal(t, "First", oldest[0].Content, "lowest sequence first")
+	assert.Equal(t, `{"message_id":1}`, oldest[0].Metadata)
+	assert.Equal(t, "tg:42", oldest[0].UserID)
+	assert.Equal(t, "100", oldest[0].ChannelID)
+	assert.Equal(t, "Second", oldest[1].Content)
+
+	require.NoError(t, repo.Delete(ctx, oldest[0].ID))
+	assert.ErrorIs(t, repo.Delete(ctx, oldest[0].ID), repository.ErrNotFound)
+	count, err = repo.Count(ctx, "telegram")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestTaskAttemptRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	user := entity.NewUser("telegram", "attempts")
+	require.NoError(t, NewUserRepository(queries).Create(ctx, user))
+	session := entity.NewSession(string(user.ID))
+	require.NoError(t, NewSessionRepository(queries).Create(ctx, session))
+	taskRepo := NewTaskRepository(queries)
+	task := entity.NewTask(string(session.ID), "fetch", "{}")
+	require.NoError(t, taskRepo.Create(ctx, task))
+
+	repo := NewTaskAttemptRepository(queries)
+	second := entity.NewTaskAttempt(task.ID, 2)
+	second.Finish(valueobject.TaskStatusCompleted, "", false)
+	first := entity.NewTaskAttempt(task.ID, 1)
+	first.Finish(valueobject.TaskStatusFailed, "connection reset", true)
+	require.NoError(t, repo.Create(ctx, second))
+	require.NoError(t, repo.Create(ctx, first))
+	assert.Error(t, repo.Create(ctx, entity.NewTaskAttempt("missing-task", 1)), "attempts belong to a task")
+
+	attempts, err := repo.FindByTaskID(ctx, string(task.ID))
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, 1, attempts[0].Attempt, "attempts in order")
+	assert.Equal(t, valueobject.TaskStatusFailed, attempts[0].Status)
+	assert.Equal(t, "connection reset", attempts[0].Error)
+	assert.True(t, attempts[0].Retryable)
+	assert.Equal(t, valueobject.TaskStatusCompleted, attempts[1].Status)
+	assert.False(t, attempts[1].Retryable)
+
+	require.NoError(t, taskRepo.Delete(ctx, string(task.ID)))
+	attempts, err = repo.FindByTaskID(ctx, string(task.ID))
+	require.NoError(t, err)
+	assert.Empty(t, attempts, "attempts are deleted with their task")
+}
+
+func TestWorkspaceRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewWorkspaceRepository(database.New(db))
+
+	support := entity.NewWorkspace("support", "Support")
+	support.CreatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sales := entity.NewWorkspace("sales", "Sales")
+	sales.CreatedAt = support.CreatedAt.Add(time.Minute)
+	require.NoError(t, repo.Create(ctx, support))
+	require.NoError(t, repo.Create(ctx, sales))
+	assert.ErrorIs(t, repo.Create(ctx, entity.NewWorkspace("sales", "Duplicate")), repository.ErrConflict)
+
+	support.Name = "Customer Support"
+	support.MaxTokens = 2000
+	require.NoError(t, repo.Update(ctx, support))
+	got, err := repo.FindByID(ctx, "support")
+	require.NoError(t, err)
+	assert.Equal(t, "Customer Support", got.Name)
+	assert.Equal(t, 2000, got.MaxTokens)
+
+	all, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, support.ID, all[0].ID, "oldest workspace first")
+
+	require.NoError(t, repo.Delete(ctx, "sales"))
+	_, err = repo.FindByID(ctx, "sales")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, "sales"), repository.ErrNotFound)
+	assert.ErrorIs(t, repo.Update(ctx, sales), repository.ErrNotFound)
+}
+
+func TestRepositories_WorkspaceScope(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	userRepo := NewUserRepository(queries)
+	user := entity.NewUser("telegram", "alice")
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	sessionRepo := NewSessionRepository(queries)
+	inDefault := entity.NewSession(string(user.ID))
+	inSupport := entity.NewSession(string(user.ID))
+	inSupport.WorkspaceID = valueobject.WorkspaceID("support")
+	require.NoError(t, sessionRepo.Create(ctx, inDefault))
+	require.NoError(t, sessionRepo.Create(ctx, inSupport))
+
+	sessions, err := sessionRepo.FindByUserID(ctx, string(user.ID), repository.QueryOptions{Workspace: "support"})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, inSupport.ID, sessions[0].ID)
+	assert.Equal(t, valueobject.WorkspaceID("support"), sessions[0].WorkspaceID)
+
+	sessions, err = sessionRepo.List(ctx, repository.QueryOptions{Workspace: string(valueobject.DefaultWorkspaceID)})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, inDefault.ID, sessions[0].ID)
+
+	sessions, err = sessionRepo.List(ctx, repository.QueryOptions{})
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	skillRepo := NewSkillRepository(queries)
+	skill := entity.NewSkill("tickets", "1.0.0", "/skills/tickets", nil, nil)
+	skill.WorkspaceID = valueobject.WorkspaceID("support")
+	require.NoError(t, skillRepo.Create(ctx, skill))
+	found, err := skillRepo.FindByName(ctx, "tickets")
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.WorkspaceID("support"), found.WorkspaceID)
+}
+
+func TestUserPreferencesRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	user := entity.NewUser("telegram", "alice")
+	require.NoError(t, NewUserRepository(queries).Create(ctx, user))
+	repo := NewUserPreferencesRepository(queries)
+
+	_, err := repo.Get(ctx, string(user.ID))
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	prefs := entity.NewUserPreferences(string(user.ID))
+	require.NoError(t, prefs.Set(entity.PreferenceLanguage, "ru"))
+	require.NoError(t, repo.Save(ctx, prefs))
+
+	require.NoError(t, prefs.Set(entity.PreferenceVerbosity, "detailed"))
+	require.NoError(t, prefs.Set(entity.PreferenceInstructions, "Call me Alice."))
+	require.NoError(t, repo.Save(ctx, prefs))
+	got, err := repo.Get(ctx, string(user.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "ru", got.Language)
+	assert.Equal(t, entity.VerbosityDetailed, got.Verbosity)
+	assert.Equal(t, "Call me Alice.", got.Instructions)
+
+	deleted, err := repo.Delete(ctx, string(user.ID))
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = repo.Delete(ctx, string(user.ID))
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestAnalyticsRepository_Counters(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewAnalyticsRepository(database.New(db))
+	require.NoError(t, repo.Increment(ctx, "2024-01-15", entity.AnalyticsMessages, "telegram", 1))
+	require.NoError(t, repo.Increment(ctx, "2024-01-15", entity.AnalyticsMessages, "telegram", 1))
+	require.NoError(t, repo.Increment(ctx, "2024-01-15", entity.AnalyticsCost, "gpt-4o", 0.25))
+	require.NoError(t, repo.Increment(ctx, "2024-01-16", entity.AnalyticsMessages, "discord", 1))
+	require.NoError(t, repo.Increment(ctx, "2024-01-17", entity.AnalyticsMessages, "discord", 1))
+
+	counters, err := repo.ListCounters(ctx, "2024-01-15", "2024-01-16")
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.AnalyticsCounter{
+		{Day: "2024-01-15", Metric: entity.AnalyticsCost, Dimension: "gpt-4o", Value: 0.25},
+		{Day: "2024-01-15", Metric: entity.AnalyticsMessages, Dimension: "telegram", Value: 2},
+		{Day: "2024-01-16", Metric: entity.AnalyticsMessages, Dimension: "discord", Value: 1},
+	}, counters)
+}
+
+func TestAnalyticsRepository_ActiveUsers(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewAnalyticsRepository(database.New(db))
+	require.NoError(t, repo.AddActiveUser(ctx, "2024-01-15", "telegram", "alice"))
+	require.NoError(t, repo.AddActiveUser(ctx, "2024-01-15", "telegram", "alice"))
+	require.NoError(t, repo.AddActiveUser(ctx, "2024-01-15", "telegram", "bob"))
+	require.NoError(t, repo.AddActiveUser(ctx, "2024-01-15", "discord", "alice"))
+	require.NoError(t, repo.AddActiveUser(ctx, "2024-01-16", "telegram", "alice"))
+
+	counters, err := repo.CountActiveUsers(ctx, "2024-01-15", "2024-01-16")
+	require.NoError(t, err)
+	values := make(map[string]float64, len(counters))
+	for _, counter := range counters {
+		assert.Equal(t, entity.AnalyticsActiveUsers, counter.Metric)
+		values[counter.Day+"/"+counter.Dimension] = counter.Value
+	}
+	assert.Equal(t, map[string]float64{
+		"2024-01-15/discord":  1,
+		"2024-01-15/telegram": 2,
+		"2024-01-15/":         2,
+		"2024-01-16/telegram": 1,
+		"2024-01-16/":         1,
+		"/discord":            1,
+		"/telegram":           2,
+		"/":                   2,
+	}, values)
+}
+
+func TestUserDataErasureRepository_CreateList(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserDataErasureRepository(database.New(db))
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	erasures := []*entity.UserDataErasure{
+		entity.NewUserDataErasure("user-1", "key-hash", "ticket 42"),
+		entity.NewUserDataErasure("user-2", "", ""),
+	}
+	erasures[0].Sessions, erasures[0].Messages, erasures[0].Preferences, erasures[0].Verified = 2, 7, true, true
+	for i, erasure := range erasures {
+		erasure.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(ctx, erasure))
+	}
+
+	all, err := repo.List(ctx, repository.UserDataErasureFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, erasures[1].ID, all[0].ID, "newest erasure first")
+
+	byUser, err := repo.List(ctx, repository.UserDataErasureFilter{UserID: "user-1"})
+	require.NoError(t, err)
+	require.Len(t, byUser, 1)
+	assert.Equal(t, erasures[0], byUser[0])
+
+	paged, err := repo.List(ctx, repository.UserDataErasureFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, paged, 1)
+	assert.Equal(t, erasures[0].ID, paged[0].ID)
+}
+
+func TestUserDataUseCase_EraseEventsAndDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	userRepo := NewUserRepository(queries)
+	sessionRepo := NewSessionRepository(queries)
+	eventRepo := NewEventRepository(queries)
+	deadLetterRepo := NewMessageDeadLetterRepository(queries)
+	uc := usecase.NewUserDataUseCase(
+		userRepo,
+		NewUserPreferencesRepository(queries),
+		sessionRepo,
+		NewMessageRepository(queries),
+		NewTaskRepository(queries),
+		NewSessionAttributeRepository(queries),
+		eventRepo,
+		deadLetterRepo,
+		NewUserDataErasureRepository(queries),
+		logging.NewNoopLogger(),
+	)
+
+	// Two bots of the Telegram channel and a Discord bot, whose user IDs may be the same
+	uc.SetConnectorChannels(map[string]string{"telegram": "telegram", "support-bot": "telegram", "discord": "discord"})
+
+	user := entity.NewUser("telegram", "42")
+	require.NoError(t, userRepo.Create(ctx, user))
+	session := entity.NewSession(user.ID.String())
+	require.NoError(t, sessionRepo.Create(ctx, session))
+	other := entity.NewUser("discord", "42")
+	require.NoError(t, userRepo.Create(ctx, other))
+
+	// Router events name the user by their channel ID and connector, the others by their user or session ID
+	now := time.Now().UTC()
+	require.NoError(t, eventRepo.Append(ctx,
+		entity.NewStoredEvent("router.message", `{"user_id":"42","source":"telegram","content":"Hello"}`, now),
+		entity.NewStoredEvent("connector.message", `{"user_id":"42","connector":"support-bot","content":"Help"}`, now),
+		entity.NewStoredEvent("user.created", fmt.Sprintf(`{"user_id":%q}`, user.ID), now),
+		entity.NewStoredEvent("session.created", fmt.Sprintf(`{"session_id":%q}`, session.ID), now),
+		entity.NewStoredEvent("router.message", `{"user_id":"42","source":"discord","content":"Hi"}`, now),
+	))
+	require.NoError(t, deadLetterRepo.Create(ctx, entity.NewMessageDeadLetter("telegram", "42", "100", "Hello", nil, "llm unavailable")))
+	require.NoError(t, deadLetterRepo.Create(ctx, entity.NewMessageDeadLetter("support-bot", "42", "100", "Help", nil, "timeout")))
+	require.NoError(t, deadLetterRepo.Create(ctx, entity.NewMessageDeadLetter("discord", "42", "101", "Hi", nil, "timeout")))
+
+	export, err := uc.ExportUserData(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.True(t, export.Success)
+	archive, err := zip.NewReader(bytes.NewReader(export.Export.Content), int64(len(export.Export.Content)))
+	require.NoError(t, err)
+	f, err := archive.Open("user.json")
+	require.NoError(t, err)
+	var doc dto.UserDataExportDTO
+	require.NoError(t, json.NewDecoder(f).Decode(&doc))
+	assert.Len(t, doc.Events, 4)
+	require.Len(t, doc.DeadLetters, 2)
+	for _, letter := range doc.DeadLetters {
+		assert.NotEqual(t, "discord", letter.Connector, "the dead letters of the other user are not exported")
+	}
+
+	resp, err := uc.EraseUserData(ctx, user.ID.String(), dto.EraseUserDataRequest{})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	assert.True(t, resp.Erasure.Verified)
+
+	// The data of the user of the other channel with the same ID is kept
+	all, err := eventRepo.List(ctx, repository.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.JSONEq(t, `{"user_id":"42","source":"discord","content":"Hi"}`, all[0].Payload)
+	letters, err := deadLetterRepo.List(ctx, repository.MessageDeadLetterFilter{})
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, "discord", letters[0].Connector)
+}
+
+func TestCrashReportRepository_CreateList(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewCrashReportRepository(database.New(db))
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reports := []*entity.CrashReport{
+		entity.NewCrashReport("router", "handle_message", "boom", "goroutine 1 [running]:", map[string]string{"connector": "telegram"}),
+		entity.NewCrashReport("skills", "execute", "nil map", "goroutine 2 [running]:", nil),
+	}
+	for i, report := range reports {
+		report.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(ctx, report))
+	}
+
+	all, err := repo.List(ctx, repository.CrashReportFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, reports[1].ID, all[0].ID, "newest report first")
+
+	byComponent, err := repo.List(ctx, repository.CrashReportFilter{Component: "router"})
+	require.NoError(t, err)
+	require.Len(t, byComponent, 1)
+	assert.Equal(t, reports[0], byComponent[0])
+
+	paged, err := repo.List(ctx, repository.CrashReportFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, paged, 1)
+	assert.Equal(t, reports[0].ID, paged[0].ID)
+}
+
+func TestDocumentChunkRepository_CreateFindDelete(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	user := entity.NewUser("telegram", "user123")
+	require.NoError(t, NewUserRepository(queries).Create(ctx, user))
+	sessions := NewSessionRepository(queries)
+	session := entity.NewSession(string(user.ID))
+	require.NoError(t, sessions.Create(ctx, session))
+
+	repo := NewDocumentChunkRepository(queries)
+	sessionID := string(session.ID)
+
+	chunks := []*entity.DocumentChunk{
+		entity.NewDocumentChunk(sessionID, "doc-1", "report.pdf", 1, "Costs fell.", []float32{0, 1}),
+		entity.NewDocumentChunk(sessionID, "doc-1", "report.pdf", 0, "Revenue grew.", []float32{1, 0}),
+	}
+	require.NoError(t, repo.Create(ctx, chunks))
+
+	found, err := repo.FindBySessionID(ctx, sessionID)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "Revenue grew.", found[0].Content, "chunks ordered by position")
+	assert.Equal(t, []float32{1, 0}, found[0].Embedding)
+
+	n, err := repo.DeleteBySessionID(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// Chunks are deleted with their session
+	require.NoError(t, repo.Create(ctx, chunks[:1]))
+	require.NoError(t, sessions.Delete(ctx, sessionID))
+	found, err = repo.FindBySessionID(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestMessageFeedbackRepository_SaveFindCount(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := database.New(db)
+	user := entity.NewUser("telegram", "user123")
+	require.NoError(t, NewUserRepository(queries).Create(ctx, user))
+	session := entity.NewSession(string(user.ID))
+	require.NoError(t, NewSessionRepository(queries).Create(ctx, session))
+	messages := NewMessageRepository(queries)
+	reply := entity.NewAssistantMessage(string(session.ID), "It is sunny.")
+	other := entity.NewAssistantMessage(string(session.ID), "It is rainy.")
+	require.NoError(t, messages.Create(ctx, reply))
+	require.NoError(t, messages.Create(ctx, other))
+
+	repo := NewMessageFeedbackRepository(queries)
+
+	_, err := repo.FindByMessageID(ctx, string(reply.ID))
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	first := entity.NewMessageFeedback(string(reply.ID), string(session.ID), string(user.ID), entity.FeedbackUp, "")
+	first.Day = "2024-01-15"
+	require.NoError(t, repo.Save(ctx, first))
+
+	// Rating the message again replaces the rating but keeps the feedback ID
+	again := entity.NewMessageFeedback(string(reply.ID), string(session.ID), string(user.ID), entity.FeedbackDown, "Wrong")
+	again.Day = "2024-01-16"
+	require.NoError(t, repo.Save(ctx, again))
+	assert.Equal(t, first.ID, again.ID)
+
+	found, err := repo.FindByMessageID(ctx, string(reply.ID))
+	require.NoError(t, err)
+	assert.Equal(t, entity.FeedbackDown, found.Rating)
+	assert.Equal(t, "Wrong", found.Comment)
+	assert.Equal(t, "2024-01-16", found.Day)
+
+	otherFeedback := entity.NewMessageFeedback(string(other.ID), string(session.ID), string(user.ID), entity.FeedbackDown, "")
+	otherFeedback.Day = "2024-01-16"
+	require.NoError(t, repo.Save(ctx, otherFeedback))
+
+	counters, err := repo.CountByDay(ctx, "2024-01-15", "2024-01-16")
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.AnalyticsCounter{
+		{Day: "2024-01-16", Metric: entity.AnalyticsFeedback, Dimension: "down", Value: 2},
+	}, counters)
+
+	// Feedback is deleted with its message
+	require.NoError(t, messages.Delete(ctx, string(reply.ID)))
+	_, err = repo.FindByMessageID(ctx, string(reply.ID))
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestLLMUsageRepository_CreateSumByMonth(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewLLMUsageRepository(database.New(db))
+	record := func(id, month, workspace, user, model string, tokens int, cost float64) *entity.LLMUsage {
+		return &entity.LLMUsage{
+			ID:           id,
+			UserID:       valueobject.UserID(user),
+			WorkspaceID:  valueobject.WorkspaceID(workspace),
+			SessionID:    "session-1",
+			Provider:     "openai",
+			Model:        model,
+			InputTokens:  tokens - 10,
+			OutputTokens: 10,
+			TotalTokens:  tokens,
+			Cost:         cost,
+			Month:        month,
+			CreatedAt:    time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		}
+	}
+
+	require.NoError(t, repo.Create(ctx, record("event-1", "2024-01", "support", "user-1", "gpt-4o", 100, 0.25)))
+	require.NoError(t, repo.Create(ctx, record("event-2", "2024-01", "support", "user-1", "gpt-4o", 50, 0.5)))
+	require.NoError(t, repo.Create(ctx, record("event-3", "2024-02", "", "user-2", "gpt-4o-mini", 30, 0.125)))
+	require.NoError(t, repo.Create(ctx, record("event-4", "2024-03", "support", "user-1", "gpt-4o", 10, 1)))
+
+	// A redelivered event is recorded once
+	require.NoError(t, repo.Create(ctx, record("event-1", "2024-01", "support", "user-1", "gpt-4o", 100, 0.25)))
+
+	totals, err := repo.SumByMonth(ctx, "2024-01", "2024-02")
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.LLMUsageTotal{
+		{Month: "2024-01", WorkspaceID: "support", UserID: "user-1", Provider: "openai", Model: "gpt-4o", Requests: 2, InputTokens: 130, OutputTokens: 20, TotalTokens: 150, Cost: 0.75},
+		{Month: "2024-02", WorkspaceID: "", UserID: "user-2", Provider: "openai", Model: "gpt-4o-mini", Requests: 1, InputTokens: 20, OutputTokens: 10, TotalTokens: 30, Cost: 0.125},
+	}, totals)
+}
@@ -28,12 +28,21 @@ func (r *ScheduleRepository) Create(ctx context.Context, schedule *entity.Schedu
 	}
 
 	_, err := r.queries.CreateSchedule(ctx, database.CreateScheduleParams{
-		ID:             dbSchedule.ID,
-		Skill:          dbSchedule.Skill,
-		CronExpression: dbSchedule.CronExpression,
-		Input:          dbSchedule.Input,
-		Enabled:        dbSchedule.Enabled,
-		CreatedAt:      dbSchedule.CreatedAt,
+		ID:              dbSchedule.ID,
+		Skill:           dbSchedule.Skill,
+		CronExpression:  dbSchedule.CronExpression,
+		Input:           dbSchedule.Input,
+		Enabled:         dbSchedule.Enabled,
+		Timezone:        dbSchedule.Timezone,
+		JitterSeconds:   dbSchedule.JitterSeconds,
+		ScheduleType:    dbSchedule.ScheduleType,
+		RunAt:           dbSchedule.RunAt,
+		MissedRunPolicy: dbSchedule.MissedRunPolicy,
+		TargetConnector: dbSchedule.TargetConnector,
+		TargetUserID:    dbSchedule.TargetUserID,
+		Prompt:          dbSchedule.Prompt,
+		SessionID:       dbSchedule.SessionID,
+		CreatedAt:       dbSchedule.CreatedAt,
 	})
 
 	if err != nil {
@@ -46,7 +55,7 @@ func (r *ScheduleRepository) Create(ctx context.Context, schedule *entity.Schedu
 func (r *ScheduleRepository) FindByID(ctx context.Context, id string) (*entity.Schedule, error) {
 	dbSchedule, err := r.queries.GetScheduleByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("schedule not found: %s", id)
+		return nil, fmt.Errorf("schedule %w: %s", repository.ErrNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find schedule by id: %w", err)
@@ -56,7 +65,7 @@ func (r *ScheduleRepository) FindByID(ctx context.Context, id string) (*entity.S
 }
 
 func (r *ScheduleRepository) FindBySkill(ctx context.Context, skill string) ([]*entity.Schedule, error) {
-	dbSchedules, err := r.queries.GetSchedulesBySkill(ctx, skill)
+	dbSchedules, err := r.queries.GetSchedulesBySkill(ctx, sql.NullString{String: skill, Valid: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find schedules by skill: %w", err)
 	}
@@ -80,10 +89,19 @@ func (r *ScheduleRepository) Update(ctx context.Context, schedule *entity.Schedu
 	}
 
 	_, err := r.queries.UpdateSchedule(ctx, database.UpdateScheduleParams{
-		CronExpression: dbSchedule.CronExpression,
-		Input:          dbSchedule.Input,
-		Enabled:        dbSchedule.Enabled,
-		ID:             dbSchedule.ID,
+		CronExpression:  dbSchedule.CronExpression,
+		Input:           dbSchedule.Input,
+		Enabled:         dbSchedule.Enabled,
+		Timezone:        dbSchedule.Timezone,
+		JitterSeconds:   dbSchedule.JitterSeconds,
+		ScheduleType:    dbSchedule.ScheduleType,
+		RunAt:           dbSchedule.RunAt,
+		MissedRunPolicy: dbSchedule.MissedRunPolicy,
+		TargetConnector: dbSchedule.TargetConnector,
+		TargetUserID:    dbSchedule.TargetUserID,
+		Prompt:          dbSchedule.Prompt,
+		SessionID:       dbSchedule.SessionID,
+		ID:              dbSchedule.ID,
 	})
 
 	if err != nil {
@@ -97,7 +115,7 @@ func (r *ScheduleRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.queries.GetScheduleByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("schedule not found: %s", id)
+			return fmt.Errorf("schedule %w: %s", repository.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to check schedule existence: %w", err)
 	}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.ScheduleRunRepository = (*ScheduleRunRepository)(nil)
+
+type ScheduleRunRepository struct {
+	queries *database.Queries
+}
+
+func NewScheduleRunRepository(queries *database.Queries) *ScheduleRunRepository {
+	return &ScheduleRunRepository{queries: queries}
+}
+
+func (r *ScheduleRunRepository) Create(ctx context.Context, run *entity.ScheduleRun) error {
+	dbRun := mappers.ScheduleRunToDB(run)
+	if dbRun == nil {
+		return fmt.Errorf("failed to convert schedule run to db model")
+	}
+
+	_, err := r.queries.CreateScheduleRun(ctx, database.CreateScheduleRunParams{
+		ID:          dbRun.ID,
+		ScheduleID:  dbRun.ScheduleID,
+		Status:      dbRun.Status,
+		ScheduledAt: dbRun.ScheduledAt,
+		StartedAt:   dbRun.StartedAt,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create schedule run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduleRunRepository) Update(ctx context.Context, run *entity.ScheduleRun) error {
+	dbRun := mappers.ScheduleRunToDB(run)
+	if dbRun == nil {
+		return fmt.Errorf("failed to convert schedule run to db model")
+	}
+
+	_, err := r.queries.UpdateScheduleRun(ctx, database.UpdateScheduleRunParams{
+		Status:     dbRun.Status,
+		FinishedAt: dbRun.FinishedAt,
+		Output:     dbRun.Output,
+		Error:      dbRun.Error,
+		ID:         dbRun.ID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update schedule run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduleRunRepository) FindByScheduleID(ctx context.Context, scheduleID string, limit int) ([]*entity.ScheduleRun, error) {
+	dbRuns, err := r.queries.GetScheduleRunsByScheduleID(ctx, database.GetScheduleRunsByScheduleIDParams{
+		ScheduleID: scheduleID,
+		Limit:      int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schedule runs: %w", err)
+	}
+
+	return mappers.ScheduleRunsToDomain(dbRuns), nil
+}
+
+func (r *ScheduleRunRepository) FindLatest(ctx context.Context, scheduleID string) (*entity.ScheduleRun, error) {
+	dbRun, err := r.queries.GetLatestScheduleRun(ctx, scheduleID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("schedule run %w for schedule: %s", repository.ErrNotFound, scheduleID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find latest schedule run: %w", err)
+	}
+
+	return mappers.ScheduleRunToDomain(&dbRun), nil
+}
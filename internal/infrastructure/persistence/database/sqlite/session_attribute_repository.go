@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+	"github.com/atumaikin/nexflow/internal/shared/utils"
+)
+
+var _ repository.SessionAttributeRepository = (*SessionAttributeRepository)(nil)
+
+type SessionAttributeRepository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func NewSessionAttributeRepository(queries *database.Queries) *SessionAttributeRepository {
+	return &SessionAttributeRepository{queries: queries, now: utils.Now}
+}
+
+func (r *SessionAttributeRepository) Get(ctx context.Context, sessionID, key string) (*entity.SessionAttribute, error) {
+	dbAttr, err := r.queries.GetSessionAttribute(ctx, database.GetSessionAttributeParams{
+		SessionID: sessionID,
+		Key:       key,
+		Now:       utils.FormatTimeRFC3339(r.now().UTC()),
+	})
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session attribute %w: %s", repository.ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session attribute: %w", err)
+	}
+
+	return mappers.SessionAttributeToDomain(&dbAttr), nil
+}
+
+func (r *SessionAttributeRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.SessionAttribute, error) {
+	dbAttrs, err := r.queries.ListSessionAttributes(ctx, database.ListSessionAttributesParams{
+		SessionID: sessionID,
+		Now:       utils.FormatTimeRFC3339(r.now().UTC()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session attributes: %w", err)
+	}
+
+	return mappers.SessionAttributesToDomain(dbAttrs), nil
+}
+
+func (r *SessionAttributeRepository) Set(ctx context.Context, attr *entity.SessionAttribute) error {
+	dbAttr := mappers.SessionAttributeToDB(attr)
+	if dbAttr == nil {
+		return fmt.Errorf("failed to convert session attribute to db model")
+	}
+
+	saved, err := r.queries.UpsertSessionAttribute(ctx, database.UpsertSessionAttributeParams{
+		SessionID: dbAttr.SessionID,
+		Key:       dbAttr.Key,
+		Value:     dbAttr.Value,
+		ExpiresAt: dbAttr.ExpiresAt,
+		CreatedAt: dbAttr.CreatedAt,
+		UpdatedAt: dbAttr.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set session attribute: %w", err)
+	}
+
+	// An existing attribute keeps its original creation time
+	attr.CreatedAt = utils.ParseTimeRFC3339(saved.CreatedAt)
+	return nil
+}
+
+func (r *SessionAttributeRepository) Delete(ctx context.Context, sessionID, key string) (bool, error) {
+	n, err := r.queries.DeleteSessionAttribute(ctx, database.DeleteSessionAttributeParams{
+		SessionID: sessionID,
+		Key:       key,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete session attribute: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func (r *SessionAttributeRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	n, err := r.queries.DeleteExpiredSessionAttributes(ctx, utils.FormatTimeRFC3339(now.UTC()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired session attributes: %w", err)
+	}
+
+	return n, nil
+}
@@ -29,10 +29,13 @@ func (r *SessionRepository) Create(ctx context.Context, session *entity.Session)
 	}
 
 	_, err := r.queries.CreateSession(ctx, database.CreateSessionParams{
-		ID:        dbSession.ID,
-		UserID:    dbSession.UserID,
-		CreatedAt: dbSession.CreatedAt,
-		UpdatedAt: dbSession.UpdatedAt,
+		ID:          dbSession.ID,
+		UserID:      dbSession.UserID,
+		CreatedAt:   dbSession.CreatedAt,
+		UpdatedAt:   dbSession.UpdatedAt,
+		Pinned:      dbSession.Pinned,
+		WorkspaceID: dbSession.WorkspaceID,
+		Title:       dbSession.Title,
 	})
 
 	if err != nil {
@@ -45,7 +48,7 @@ func (r *SessionRepository) Create(ctx context.Context, session *entity.Session)
 func (r *SessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
 	dbSession, err := r.queries.GetSessionByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("session not found: %s", id)
+		return nil, fmt.Errorf("session %w: %s", repository.ErrNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find session by id: %w", err)
@@ -54,8 +57,36 @@ func (r *SessionRepository) FindByID(ctx context.Context, id string) (*entity.Se
 	return mappers.SessionToDomain(&dbSession), nil
 }
 
-func (r *SessionRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Session, error) {
-	dbSessions, err := r.queries.GetSessionsByUserID(ctx, userID)
+func (r *SessionRepository) List(ctx context.Context, opts repository.QueryOptions) ([]*entity.Session, error) {
+	p := newPageParams(opts)
+	dbSessions, err := r.queries.ListSessions(ctx, database.ListSessionsParams{
+		Since:          p.since,
+		Until:          p.until,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		WorkspaceID:    p.workspace,
+		Limit:          p.limit,
+		Offset:         p.offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return mappers.SessionsToDomain(dbSessions), nil
+}
+
+func (r *SessionRepository) FindByUserID(ctx context.Context, userID string, opts repository.QueryOptions) ([]*entity.Session, error) {
+	p := newPageParams(opts)
+	dbSessions, err := r.queries.ListSessionsByUserID(ctx, database.ListSessionsByUserIDParams{
+		UserID:         userID,
+		Since:          p.since,
+		Until:          p.until,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		WorkspaceID:    p.workspace,
+		Limit:          p.limit,
+		Offset:         p.offset,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find sessions by user id: %w", err)
 	}
@@ -73,6 +104,8 @@ func (r *SessionRepository) Update(ctx context.Context, session *entity.Session)
 
 	_, err := r.queries.UpdateSession(ctx, database.UpdateSessionParams{
 		UpdatedAt: time.Now().Format(time.RFC3339),
+		Pinned:    dbSession.Pinned,
+		Title:     dbSession.Title,
 		ID:        dbSession.ID,
 	})
 
@@ -87,7 +120,7 @@ func (r *SessionRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.queries.GetSessionByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("session not found: %s", id)
+			return fmt.Errorf("session %w: %s", repository.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to check session existence: %w", err)
 	}
@@ -35,10 +35,12 @@ func (r *SkillRepository) Create(ctx context.Context, skill *entity.Skill) error
 		Permissions: dbSkill.Permissions,
 		Metadata:    dbSkill.Metadata,
 		CreatedAt:   dbSkill.CreatedAt,
+		Disabled:    dbSkill.Disabled,
+		WorkspaceID: dbSkill.WorkspaceID,
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to create skill: %w", err)
+		return wrapWriteError(err, "failed to create skill")
 	}
 
 	return nil
@@ -47,7 +49,7 @@ func (r *SkillRepository) Create(ctx context.Context, skill *entity.Skill) error
 func (r *SkillRepository) FindByID(ctx context.Context, id string) (*entity.Skill, error) {
 	dbSkill, err := r.queries.GetSkillByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("skill not found: %s", id)
+		return nil, fmt.Errorf("skill %w: %s", repository.ErrNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find skill by id: %w", err)
@@ -59,7 +61,7 @@ func (r *SkillRepository) FindByID(ctx context.Context, id string) (*entity.Skil
 func (r *SkillRepository) FindByName(ctx context.Context, name string) (*entity.Skill, error) {
 	dbSkill, err := r.queries.GetSkillByName(ctx, name)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("skill not found: %s", name)
+		return nil, fmt.Errorf("skill %w: %s", repository.ErrNotFound, name)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find skill by name: %w", err)
@@ -88,11 +90,13 @@ func (r *SkillRepository) Update(ctx context.Context, skill *entity.Skill) error
 		Location:    dbSkill.Location,
 		Permissions: dbSkill.Permissions,
 		Metadata:    dbSkill.Metadata,
+		Disabled:    dbSkill.Disabled,
+		WorkspaceID: dbSkill.WorkspaceID,
 		ID:          dbSkill.ID,
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to update skill: %w", err)
+		return wrapWriteError(err, "failed to update skill")
 	}
 
 	return nil
@@ -102,7 +106,7 @@ func (r *SkillRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.queries.GetSkillByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("skill not found: %s", id)
+			return fmt.Errorf("skill %w: %s", repository.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to check skill existence: %w", err)
 	}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.SpilledMessageRepository = (*SpilledMessageRepository)(nil)
+
+type SpilledMessageRepository struct {
+	queries *database.Queries
+}
+
+func NewSpilledMessageRepository(queries *database.Queries) *SpilledMessageRepository {
+	return &SpilledMessageRepository{queries: queries}
+}
+
+func (r *SpilledMessageRepository) Create(ctx context.Context, message *entity.SpilledMessage) error {
+	dbMessage := mappers.SpilledMessageToDB(message)
+	if dbMessage == nil {
+		return fmt.Errorf("failed to convert spilled message to db model")
+	}
+
+	err := r.queries.CreateSpilledMessage(ctx, database.CreateSpilledMessageParams{
+		ID:        dbMessage.ID,
+		Connector: dbMessage.Connector,
+		UserID:    dbMessage.UserID,
+		ChannelID: dbMessage.ChannelID,
+		Content:   dbMessage.Content,
+		Metadata:  dbMessage.Metadata,
+		Sequence:  dbMessage.Sequence,
+		CreatedAt: dbMessage.CreatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create spilled message")
+	}
+
+	return nil
+}
+
+func (r *SpilledMessageRepository) List(ctx context.Context, connector string, limit int) ([]*entity.SpilledMessage, error) {
+	dbMessages, err := r.queries.ListSpilledMessages(ctx, database.ListSpilledMessagesParams{
+		Connector: connector,
+		Limit:     int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spilled messages: %w", err)
+	}
+
+	return mappers.SpilledMessagesToDomain(dbMessages), nil
+}
+
+func (r *SpilledMessageRepository) Count(ctx context.Context, connector string) (int, error) {
+	count, err := r.queries.CountSpilledMessages(ctx, connector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count spilled messages: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (r *SpilledMessageRepository) Delete(ctx context.Context, id string) error {
+	deleted, err := r.queries.DeleteSpilledMessage(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete spilled message: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("spilled message %w: %s", repository.ErrNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.TaskAttemptRepository = (*TaskAttemptRepository)(nil)
+
+type TaskAttemptRepository struct {
+	queries *database.Queries
+}
+
+func NewTaskAttemptRepository(queries *database.Queries) *TaskAttemptRepository {
+	return &TaskAttemptRepository{queries: queries}
+}
+
+func (r *TaskAttemptRepository) Create(ctx context.Context, attempt *entity.TaskAttempt) error {
+	dbAttempt := mappers.TaskAttemptToDB(attempt)
+	if dbAttempt == nil {
+		return fmt.Errorf("failed to convert task attempt to db model")
+	}
+
+	err := r.queries.CreateTaskAttempt(ctx, database.CreateTaskAttemptParams{
+		ID:         dbAttempt.ID,
+		TaskID:     dbAttempt.TaskID,
+		Attempt:    dbAttempt.Attempt,
+		Status:     dbAttempt.Status,
+		Error:      dbAttempt.Error,
+		Retryable:  dbAttempt.Retryable,
+		StartedAt:  dbAttempt.StartedAt,
+		FinishedAt: dbAttempt.FinishedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create task attempt")
+	}
+
+	return nil
+}
+
+func (r *TaskAttemptRepository) FindByTaskID(ctx context.Context, taskID string) ([]*entity.TaskAttempt, error) {
+	dbAttempts, err := r.queries.ListTaskAttempts(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task attempts: %w", err)
+	}
+
+	return mappers.TaskAttemptsToDomain(dbAttempts), nil
+}
@@ -29,13 +29,15 @@ func (r *TaskRepository) Create(ctx context.Context, task *entity.Task) error {
 	}
 
 	_, err := r.queries.CreateTask(ctx, database.CreateTaskParams{
-		ID:        dbTask.ID,
-		SessionID: dbTask.SessionID,
-		Skill:     dbTask.Skill,
-		Input:     dbTask.Input,
-		Status:    dbTask.Status,
-		CreatedAt: dbTask.CreatedAt,
-		UpdatedAt: dbTask.UpdatedAt,
+		ID:         dbTask.ID,
+		SessionID:  dbTask.SessionID,
+		Skill:      dbTask.Skill,
+		Input:      dbTask.Input,
+		Status:     dbTask.Status,
+		CreatedAt:  dbTask.CreatedAt,
+		UpdatedAt:  dbTask.UpdatedAt,
+		StartedAt:  dbTask.StartedAt,
+		FinishedAt: dbTask.FinishedAt,
 	})
 
 	if err != nil {
@@ -48,7 +50,7 @@ func (r *TaskRepository) Create(ctx context.Context, task *entity.Task) error {
 func (r *TaskRepository) FindByID(ctx context.Context, id string) (*entity.Task, error) {
 	dbTask, err := r.queries.GetTaskByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("task not found: %s", id)
+		return nil, fmt.Errorf("task %w: %s", repository.ErrNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find task by id: %w", err)
@@ -57,8 +59,17 @@ func (r *TaskRepository) FindByID(ctx context.Context, id string) (*entity.Task,
 	return mappers.TaskToDomain(&dbTask), nil
 }
 
-func (r *TaskRepository) FindBySessionID(ctx context.Context, sessionID string) ([]*entity.Task, error) {
-	dbTasks, err := r.queries.GetTasksBySessionID(ctx, sessionID)
+func (r *TaskRepository) FindBySessionID(ctx context.Context, sessionID string, opts repository.QueryOptions) ([]*entity.Task, error) {
+	p := newPageParams(opts)
+	dbTasks, err := r.queries.ListTasksBySessionID(ctx, database.ListTasksBySessionIDParams{
+		SessionID:      sessionID,
+		Since:          p.since,
+		Until:          p.until,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+		Offset:         p.offset,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find tasks by session id: %w", err)
 	}
@@ -85,11 +96,13 @@ func (r *TaskRepository) Update(ctx context.Context, task *entity.Task) error {
 	}
 
 	_, err := r.queries.UpdateTask(ctx, database.UpdateTaskParams{
-		Output:    output,
-		Status:    dbTask.Status,
-		Error:     taskErr,
-		UpdatedAt: time.Now().Format(time.RFC3339),
-		ID:        dbTask.ID,
+		Output:     output,
+		Status:     dbTask.Status,
+		Error:      taskErr,
+		UpdatedAt:  time.Now().Format(time.RFC3339),
+		StartedAt:  dbTask.StartedAt,
+		FinishedAt: dbTask.FinishedAt,
+		ID:         dbTask.ID,
 	})
 
 	if err != nil {
@@ -99,11 +112,44 @@ func (r *TaskRepository) Update(ctx context.Context, task *entity.Task) error {
 	return nil
 }
 
+func (r *TaskRepository) CountOlderThan(ctx context.Context, before time.Time) (int, error) {
+	count, err := r.queries.CountTasksOlderThan(ctx, formatOptionalTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tasks older than: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (r *TaskRepository) FindOlderThan(ctx context.Context, before time.Time, opts repository.QueryOptions) ([]*entity.Task, error) {
+	p := newBatchParams(before, opts)
+	dbTasks, err := r.queries.ListTasksOlderThan(ctx, database.ListTasksOlderThanParams{
+		Before:         p.before,
+		AfterCreatedAt: p.afterCreatedAt,
+		AfterID:        p.afterID,
+		Limit:          p.limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks older than: %w", err)
+	}
+
+	return mappers.TasksToDomain(dbTasks), nil
+}
+
+func (r *TaskRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeleteTasksOlderThan(ctx, formatOptionalTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tasks older than: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
 func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.queries.GetTaskByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("task not found: %s", id)
+			return fmt.Errorf("task %w: %s", repository.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to check task existence: %w", err)
 	}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.UserDataErasureRepository = (*UserDataErasureRepository)(nil)
+
+type UserDataErasureRepository struct {
+	queries *database.Queries
+}
+
+func NewUserDataErasureRepository(queries *database.Queries) *UserDataErasureRepository {
+	return &UserDataErasureRepository{queries: queries}
+}
+
+func (r *UserDataErasureRepository) Create(ctx context.Context, erasure *entity.UserDataErasure) error {
+	dbErasure := mappers.UserDataErasureToDB(erasure)
+	if dbErasure == nil {
+		return fmt.Errorf("failed to convert user data erasure to db model")
+	}
+
+	_, err := r.queries.CreateUserDataErasure(ctx, database.CreateUserDataErasureParams{
+		ID:          dbErasure.ID,
+		UserID:      dbErasure.UserID,
+		RequestedBy: dbErasure.RequestedBy,
+		Reason:      dbErasure.Reason,
+		Sessions:    dbErasure.Sessions,
+		Messages:    dbErasure.Messages,
+		Tasks:       dbErasure.Tasks,
+		Attributes:  dbErasure.Attributes,
+		Preferences: dbErasure.Preferences,
+		Verified:    dbErasure.Verified,
+		CreatedAt:   dbErasure.CreatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create user data erasure")
+	}
+
+	return nil
+}
+
+func (r *UserDataErasureRepository) List(ctx context.Context, filter repository.UserDataErasureFilter) ([]*entity.UserDataErasure, error) {
+	limit := int64(-1)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dbErasures, err := r.queries.ListUserDataErasures(ctx, database.ListUserDataErasuresParams{
+		UserID: filter.UserID,
+		Limit:  limit,
+		Offset: int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user data erasures: %w", err)
+	}
+
+	return mappers.UserDataErasuresToDomain(dbErasures), nil
+}
@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.UserPreferencesRepository = (*UserPreferencesRepository)(nil)
+
+type UserPreferencesRepository struct {
+	queries *database.Queries
+}
+
+func NewUserPreferencesRepository(queries *database.Queries) *UserPreferencesRepository {
+	return &UserPreferencesRepository{queries: queries}
+}
+
+func (r *UserPreferencesRepository) Get(ctx context.Context, userID string) (*entity.UserPreferences, error) {
+	dbPrefs, err := r.queries.GetUserPreferences(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("user preferences %w: %s", repository.ErrNotFound, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+
+	return mappers.UserPreferencesToDomain(&dbPrefs), nil
+}
+
+func (r *UserPreferencesRepository) Save(ctx context.Context, prefs *entity.UserPreferences) error {
+	dbPrefs := mappers.UserPreferencesToDB(prefs)
+	if dbPrefs == nil {
+		return fmt.Errorf("failed to convert user preferences to db model")
+	}
+
+	_, err := r.queries.UpsertUserPreferences(ctx, database.UpsertUserPreferencesParams{
+		UserID:       dbPrefs.UserID,
+		Language:     dbPrefs.Language,
+		Model:        dbPrefs.Model,
+		Timezone:     dbPrefs.Timezone,
+		Verbosity:    dbPrefs.Verbosity,
+		UpdatedAt:    dbPrefs.UpdatedAt,
+		Instructions: dbPrefs.Instructions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save user preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserPreferencesRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	n, err := r.queries.DeleteUserPreferences(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user preferences: %w", err)
+	}
+
+	return n > 0, nil
+}
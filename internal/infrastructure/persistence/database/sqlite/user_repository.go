@@ -7,6 +7,7 @@ import (
 
 	"github.com/atumaikin/nexflow/internal/domain/entity"
 	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
 	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
 )
@@ -35,7 +36,7 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return wrapWriteError(err, "failed to create user")
 	}
 
 	return nil
@@ -44,7 +45,7 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 func (r *UserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
 	sqlcUser, err := r.queries.GetUserByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found: %s", id)
+		return nil, fmt.Errorf("user %w: %s", repository.ErrNotFound, id)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user by id: %w", err)
@@ -55,12 +56,12 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*entity.User,
 
 func (r *UserRepository) FindByChannel(ctx context.Context, channel, channelID string) (*entity.User, error) {
 	sqlcUser, err := r.queries.GetUserByChannel(ctx, database.GetUserByChannelParams{
-		Channel:       channel,
+		Channel:       valueobject.Channel(channel),
 		ChannelUserID: channelID,
 	})
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found: channel=%s, channelID=%s", channel, channelID)
+		return nil, fmt.Errorf("user %w: channel=%s, channelID=%s", repository.ErrNotFound, channel, channelID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user by channel: %w", err)
@@ -82,7 +83,7 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.queries.GetUserByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("user not found: %s", id)
+			return fmt.Errorf("user %w: %s", repository.ErrNotFound, id)
 		}
 		return fmt.Errorf("failed to check user existence: %w", err)
 	}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.WebhookDeliveryRepository = (*WebhookDeliveryRepository)(nil)
+
+type WebhookDeliveryRepository struct {
+	queries *database.Queries
+}
+
+func NewWebhookDeliveryRepository(queries *database.Queries) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{queries: queries}
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	dbDelivery := mappers.WebhookDeliveryToDB(delivery)
+	if dbDelivery == nil {
+		return fmt.Errorf("failed to convert webhook delivery to db model")
+	}
+
+	_, err := r.queries.CreateWebhookDelivery(ctx, database.CreateWebhookDeliveryParams{
+		ID:             dbDelivery.ID,
+		Endpoint:       dbDelivery.Endpoint,
+		URL:            dbDelivery.URL,
+		EventType:      dbDelivery.EventType,
+		Payload:        dbDelivery.Payload,
+		Status:         dbDelivery.Status,
+		Attempts:       dbDelivery.Attempts,
+		ResponseStatus: dbDelivery.ResponseStatus,
+		Error:          dbDelivery.Error,
+		CreatedAt:      dbDelivery.CreatedAt,
+		UpdatedAt:      dbDelivery.UpdatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create webhook delivery")
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	dbDelivery := mappers.WebhookDeliveryToDB(delivery)
+	if dbDelivery == nil {
+		return fmt.Errorf("failed to convert webhook delivery to db model")
+	}
+
+	_, err := r.queries.UpdateWebhookDelivery(ctx, database.UpdateWebhookDeliveryParams{
+		Status:         dbDelivery.Status,
+		Attempts:       dbDelivery.Attempts,
+		ResponseStatus: dbDelivery.ResponseStatus,
+		Error:          dbDelivery.Error,
+		UpdatedAt:      dbDelivery.UpdatedAt,
+		ID:             dbDelivery.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("webhook delivery %w: %s", repository.ErrNotFound, delivery.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) List(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]*entity.WebhookDelivery, error) {
+	limit := int64(-1)
+	if filter.Limit > 0 {
+		limit = int64(filter.Limit)
+	}
+
+	dbDeliveries, err := r.queries.ListWebhookDeliveries(ctx, database.ListWebhookDeliveriesParams{
+		Endpoint: filter.Endpoint,
+		Status:   filter.Status,
+		Limit:    limit,
+		Offset:   int64(filter.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return mappers.WebhookDeliveriesToDomain(dbDeliveries), nil
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database"
+	"github.com/atumaikin/nexflow/internal/infrastructure/persistence/database/mappers"
+)
+
+var _ repository.WorkspaceRepository = (*WorkspaceRepository)(nil)
+
+type WorkspaceRepository struct {
+	queries *database.Queries
+}
+
+func NewWorkspaceRepository(queries *database.Queries) *WorkspaceRepository {
+	return &WorkspaceRepository{queries: queries}
+}
+
+func (r *WorkspaceRepository) Create(ctx context.Context, workspace *entity.Workspace) error {
+	dbWorkspace := mappers.WorkspaceToDB(workspace)
+	if dbWorkspace == nil {
+		return fmt.Errorf("failed to convert workspace to db model")
+	}
+
+	_, err := r.queries.CreateWorkspace(ctx, database.CreateWorkspaceParams{
+		ID:        dbWorkspace.ID,
+		Name:      dbWorkspace.Name,
+		MaxTokens: dbWorkspace.MaxTokens,
+		CreatedAt: dbWorkspace.CreatedAt,
+	})
+	if err != nil {
+		return wrapWriteError(err, "failed to create workspace")
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) FindByID(ctx context.Context, id string) (*entity.Workspace, error) {
+	dbWorkspace, err := r.queries.GetWorkspaceByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("workspace %w: %s", repository.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return mappers.WorkspaceToDomain(&dbWorkspace), nil
+}
+
+func (r *WorkspaceRepository) List(ctx context.Context) ([]*entity.Workspace, error) {
+	dbWorkspaces, err := r.queries.ListWorkspaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	return mappers.WorkspacesToDomain(dbWorkspaces), nil
+}
+
+func (r *WorkspaceRepository) Update(ctx context.Context, workspace *entity.Workspace) error {
+	dbWorkspace := mappers.WorkspaceToDB(workspace)
+	if dbWorkspace == nil {
+		return fmt.Errorf("failed to convert workspace to db model")
+	}
+
+	_, err := r.queries.UpdateWorkspace(ctx, database.UpdateWorkspaceParams{
+		Name:      dbWorkspace.Name,
+		MaxTokens: dbWorkspace.MaxTokens,
+		ID:        dbWorkspace.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("workspace %w: %s", repository.ErrNotFound, workspace.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) Delete(ctx context.Context, id string) error {
+	deleted, err := r.queries.DeleteWorkspace(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("workspace %w: %s", repository.ErrNotFound, id)
+	}
+
+	return nil
+}
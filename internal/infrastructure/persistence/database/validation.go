@@ -170,10 +170,15 @@ func ValidateSchedule(s *Schedule) error {
 	if _, err := uuid.Parse(s.ID); err != nil {
 		return fmt.Errorf("%w: %s", ErrInvalidID, s.ID)
 	}
-	if s.Skill == "" {
+	// Prompt schedules send their prompt instead of executing a skill
+	if (!s.Skill.Valid || s.Skill.String == "") && s.Prompt == "" {
 		return fmt.Errorf("%w: skill", ErrRequiredField)
 	}
-	if s.CronExpression == "" {
+	if s.ScheduleType == "once" {
+		if !s.RunAt.Valid || s.RunAt.String == "" {
+			return fmt.Errorf("%w: run_at", ErrRequiredField)
+		}
+	} else if s.CronExpression == "" {
 		return fmt.Errorf("%w: cron_expression", ErrRequiredField)
 	}
 	if s.CreatedAt == "" {
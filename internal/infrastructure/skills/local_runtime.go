@@ -3,6 +3,7 @@ package skills
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -10,8 +11,14 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/atumaikin/nexflow/internal/application/ports"
 )
 
+// exitTempFail is the exit code of a skill reporting a temporary failure (EX_TEMPFAIL), after
+// which it may be executed again
+const exitTempFail = 75
+
 // Config represents local skill runtime configuration
 type Config struct {
 	Directory      string
@@ -120,10 +127,14 @@ func (r *LocalRuntime) Execute(ctx context.Context, req *ExecutionRequest) (*Exe
 			"error", err,
 			"output", string(output))
 
+		execErr := fmt.Errorf("execution failed: %w: %s", err, string(output))
+		if retryableFailure(ctx, execCtx, err) {
+			execErr = fmt.Errorf("%w: %w", ports.ErrSkillRetryable, execErr)
+		}
 		return &ExecutionResult{
 			Success: false,
 			Output:  nil,
-			Error:   fmt.Errorf("execution failed: %w: %s", err, string(output)),
+			Error:   execErr,
 		}, nil
 	}
 
@@ -157,6 +168,16 @@ func (r *LocalRuntime) Execute(ctx context.Context, req *ExecutionRequest) (*Exe
 	}, nil
 }
 
+// retryableFailure reports whether a skill failed for a transient reason: it ran into its own
+// timeout, rather than the caller giving up, or exited with exitTempFail
+func retryableFailure(ctx, execCtx context.Context, err error) bool {
+	if ctx.Err() == nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == exitTempFail
+}
+
 // List returns a list of available skills
 func (r *LocalRuntime) List(ctx context.Context) ([]string, error) {
 	r.logger.Debug("Listing available skills")
@@ -10,9 +10,9 @@ import (
 // MockSkillRuntime is a mock implementation of SkillRuntime for testing
 type MockSkillRuntime struct {
 	ExecuteFunc  func(context.Context, string, map[string]interface{}) (*ports.SkillExecution, error)
-	ValidateFunc func(string) error
-	ListFunc     func() ([]string, error)
-	GetSkillFunc func(string) (map[string]interface{}, error)
+	ValidateFunc func(context.Context, string) error
+	ListFunc     func(context.Context) ([]string, error)
+	GetSkillFunc func(context.Context, string) (map[string]interface{}, error)
 }
 
 // NewMockSkillRuntime creates a new mock skill runtime
@@ -26,15 +26,15 @@ func NewMockSkillRuntime() *MockSkillRuntime {
 				Output:  output,
 			}, nil
 		},
-		ValidateFunc: func(skillName string) error {
+		ValidateFunc: func(ctx context.Context, skillName string) error {
 			// Mock validation - all skills are valid
 			return nil
 		},
-		ListFunc: func() ([]string, error) {
+		ListFunc: func(ctx context.Context) ([]string, error) {
 			// Mock skill list
 			return []string{"mock-skill-1", "mock-skill-2", "mock-skill-3"}, nil
 		},
-		GetSkillFunc: func(skillName string) (map[string]interface{}, error) {
+		GetSkillFunc: func(ctx context.Context, skillName string) (map[string]interface{}, error) {
 			// Mock skill details
 			return map[string]interface{}{
 				"name":        skillName,
@@ -54,25 +54,25 @@ func (m *MockSkillRuntime) Execute(ctx context.Context, skillName string, input
 }
 
 // Validate implements SkillRuntime interface
-func (m *MockSkillRuntime) Validate(skillName string) error {
+func (m *MockSkillRuntime) Validate(ctx context.Context, skillName string) error {
 	if m.ValidateFunc != nil {
-		return m.ValidateFunc(skillName)
+		return m.ValidateFunc(ctx, skillName)
 	}
 	return fmt.Errorf("ValidateFunc not set")
 }
 
 // List implements SkillRuntime interface
-func (m *MockSkillRuntime) List() ([]string, error) {
+func (m *MockSkillRuntime) List(ctx context.Context) ([]string, error) {
 	if m.ListFunc != nil {
-		return m.ListFunc()
+		return m.ListFunc(ctx)
 	}
 	return nil, fmt.Errorf("ListFunc not set")
 }
 
 // GetSkill implements SkillRuntime interface
-func (m *MockSkillRuntime) GetSkill(skillName string) (map[string]interface{}, error) {
+func (m *MockSkillRuntime) GetSkill(ctx context.Context, skillName string) (map[string]interface{}, error) {
 	if m.GetSkillFunc != nil {
-		return m.GetSkillFunc(skillName)
+		return m.GetSkillFunc(ctx, skillName)
 	}
 	return nil, fmt.Errorf("GetSkillFunc not set")
 }
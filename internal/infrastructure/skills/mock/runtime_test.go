@@ -38,7 +38,7 @@ func TestMockSkillRuntime_Execute(t *testing.T) {
 func TestMockSkillRuntime_Validate(t *testing.T) {
 	skillRuntime := NewMockSkillRuntime()
 
-	err := skillRuntime.Validate("test-skill")
+	err := skillRuntime.Validate(context.Background(), "test-skill")
 
 	assert.NoError(t, err)
 }
@@ -46,7 +46,7 @@ func TestMockSkillRuntime_Validate(t *testing.T) {
 func TestMockSkillRuntime_List(t *testing.T) {
 	skillRuntime := NewMockSkillRuntime()
 
-	skills, err := skillRuntime.List()
+	skills, err := skillRuntime.List(context.Background())
 
 	require.NoError(t, err)
 	assert.NotNil(t, skills)
@@ -59,7 +59,7 @@ func TestMockSkillRuntime_List(t *testing.T) {
 func TestMockSkillRuntime_GetSkill(t *testing.T) {
 	skillRuntime := NewMockSkillRuntime()
 
-	skill, err := skillRuntime.GetSkill("test-skill")
+	skill, err := skillRuntime.GetSkill(context.Background(), "test-skill")
 
 	require.NoError(t, err)
 	assert.NotNil(t, skill)
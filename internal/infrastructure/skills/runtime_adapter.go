@@ -3,20 +3,40 @@ package skills
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 // RuntimeAdapter adapts infrastructure.Executor to ports.SkillRuntime
 type RuntimeAdapter struct {
-	executor Executor
+	executor  Executor
+	tracer    *tracing.Tracer
+	recoverer *crash.Recoverer
 }
 
 // NewRuntimeAdapter creates a new adapter that implements ports.SkillRuntime
 func NewRuntimeAdapter(executor Executor) ports.SkillRuntime {
+	return NewRuntimeAdapterWithTracer(executor, nil)
+}
+
+// NewRuntimeAdapterWithTracer creates a new adapter that records a span for every skill
+// execution. A nil tracer disables tracing.
+func NewRuntimeAdapterWithTracer(executor Executor, tracer *tracing.Tracer) ports.SkillRuntime {
+	return NewRuntimeAdapterWithRecoverer(executor, tracer, nil)
+}
+
+// NewRuntimeAdapterWithRecoverer creates a new adapter that fails a skill execution that panics
+// rather than the caller, reporting the panic with the recoverer. A nil recoverer only turns the
+// panic into an error.
+func NewRuntimeAdapterWithRecoverer(executor Executor, tracer *tracing.Tracer, recoverer *crash.Recoverer) ports.SkillRuntime {
 	return &RuntimeAdapter{
-		executor: executor,
+		executor:  executor,
+		tracer:    tracer,
+		recoverer: recoverer,
 	}
 }
 
@@ -30,10 +50,20 @@ func (a *RuntimeAdapter) Execute(ctx context.Context, skillName string, input ma
 	}
 
 	// Execute the skill
-	result, err := a.executor.Execute(ctx, req)
+	ctx, span := a.tracer.Start(ctx, "skill.execute", tracing.SpanKindInternal, "skill.name", skillName)
+	defer span.End()
+	result, err := a.execute(ctx, req)
 	if err != nil {
+		span.RecordError(err)
 		return nil, fmt.Errorf("SkillRuntimeAdapter.Execute: %w", err)
 	}
+	if result.Error != nil {
+		span.RecordError(result.Error)
+	}
+	// A transient failure is an error, so that the caller may retry the skill
+	if !result.Success && errors.Is(result.Error, ports.ErrSkillRetryable) {
+		return nil, fmt.Errorf("SkillRuntimeAdapter.Execute: %w", result.Error)
+	}
 
 	// Convert output to JSON string for compatibility with ports.SkillExecution
 	outputJSON, err := json.Marshal(result.Output)
@@ -55,10 +85,15 @@ func (a *RuntimeAdapter) Execute(ctx context.Context, skillName string, input ma
 	return execution, nil
 }
 
-// Validate implements ports.SkillRuntime.Validate
-func (a *RuntimeAdapter) Validate(skillName string) error {
-	ctx := context.Background()
+// execute runs a skill with the executor, recovering a panic
+func (a *RuntimeAdapter) execute(ctx context.Context, req *ExecutionRequest) (result *ExecutionResult, err error) {
+	defer a.recoverer.Recover(ctx, "skills", "execute", &err, "skill", req.SkillName)
+
+	return a.executor.Execute(ctx, req)
+}
 
+// Validate implements ports.SkillRuntime.Validate
+func (a *RuntimeAdapter) Validate(ctx context.Context, skillName string) error {
 	// First check if skill exists by getting metadata
 	_, err := a.executor.GetMetadata(ctx, skillName)
 	if err != nil {
@@ -74,9 +109,7 @@ func (a *RuntimeAdapter) Validate(skillName string) error {
 }
 
 // List implements ports.SkillRuntime.List
-func (a *RuntimeAdapter) List() ([]string, error) {
-	ctx := context.Background()
-
+func (a *RuntimeAdapter) List(ctx context.Context) ([]string, error) {
 	// Get list of skills from executor
 	skills, err := a.executor.List(ctx)
 	if err != nil {
@@ -87,9 +120,7 @@ func (a *RuntimeAdapter) List() ([]string, error) {
 }
 
 // GetSkill implements ports.SkillRuntime.GetSkill
-func (a *RuntimeAdapter) GetSkill(skillName string) (map[string]interface{}, error) {
-	ctx := context.Background()
-
+func (a *RuntimeAdapter) GetSkill(ctx context.Context, skillName string) (map[string]interface{}, error) {
 	// Get metadata from executor
 	metadata, err := a.executor.GetMetadata(ctx, skillName)
 	if err != nil {
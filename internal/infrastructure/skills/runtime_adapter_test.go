@@ -3,8 +3,12 @@ package skills
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/atumaikin/nexflow/internal/application/ports"
+	"github.com/atumaikin/nexflow/internal/shared/crash"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -161,6 +165,24 @@ func TestRuntimeAdapter_Execute_WithError(t *testing.T) {
 	assert.Equal(t, "skill execution failed", exec.Error)
 }
 
+func TestRuntimeAdapter_Execute_Retryable(t *testing.T) {
+	executor := &mockExecutor{
+		name: "test",
+	}
+	executor.setResult("test-skill", &ExecutionResult{
+		Success: false,
+		Error:   fmt.Errorf("%w: skill timed out", ports.ErrSkillRetryable),
+	})
+
+	adapter := NewRuntimeAdapter(executor)
+
+	exec, err := adapter.Execute(context.Background(), "test-skill", map[string]interface{}{})
+
+	assert.Nil(t, exec)
+	assert.ErrorIs(t, err, ports.ErrSkillRetryable)
+	assert.Contains(t, err.Error(), "skill timed out")
+}
+
 func TestRuntimeAdapter_Execute_Error(t *testing.T) {
 	executor := &mockExecutor{
 		name: "test",
@@ -192,7 +214,7 @@ func TestRuntimeAdapter_Validate(t *testing.T) {
 
 	adapter := NewRuntimeAdapter(executor)
 
-	err := adapter.Validate("test-skill")
+	err := adapter.Validate(context.Background(), "test-skill")
 
 	assert.NoError(t, err)
 }
@@ -207,7 +229,7 @@ func TestRuntimeAdapter_Validate_NotAvailable(t *testing.T) {
 
 	adapter := NewRuntimeAdapter(executor)
 
-	err := adapter.Validate("test-skill")
+	err := adapter.Validate(context.Background(), "test-skill")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "is not available")
@@ -223,7 +245,7 @@ func TestRuntimeAdapter_Validate_NotFound(t *testing.T) {
 
 	adapter := NewRuntimeAdapter(executor)
 
-	err := adapter.Validate("test-skill")
+	err := adapter.Validate(context.Background(), "test-skill")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "validation failed")
@@ -239,7 +261,7 @@ func TestRuntimeAdapter_List(t *testing.T) {
 
 	adapter := NewRuntimeAdapter(executor)
 
-	skills, err := adapter.List()
+	skills, err := adapter.List(context.Background())
 
 	require.NoError(t, err)
 	assert.NotNil(t, skills)
@@ -256,7 +278,7 @@ func TestRuntimeAdapter_List_Error(t *testing.T) {
 	}
 	adapter := NewRuntimeAdapter(executor)
 
-	skills, err := adapter.List()
+	skills, err := adapter.List(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, skills)
@@ -277,7 +299,7 @@ func TestRuntimeAdapter_GetSkill(t *testing.T) {
 
 	adapter := NewRuntimeAdapter(executor)
 
-	skill, err := adapter.GetSkill("test-skill")
+	skill, err := adapter.GetSkill(context.Background(), "test-skill")
 
 	require.NoError(t, err)
 	assert.NotNil(t, skill)
@@ -295,7 +317,7 @@ func TestRuntimeAdapter_GetSkill_NotFound(t *testing.T) {
 
 	adapter := NewRuntimeAdapter(executor)
 
-	skill, err := adapter.GetSkill("test-skill")
+	skill, err := adapter.GetSkill(context.Background(), "test-skill")
 
 	assert.Error(t, err)
 	assert.Nil(t, skill)
@@ -332,3 +354,24 @@ func TestRuntimeAdapter_Execute_OutputFormat(t *testing.T) {
 	assert.Contains(t, exec.Output, "{")
 	assert.Contains(t, exec.Output, "}")
 }
+
+// panickingExecutor panics on every execution
+type panickingExecutor struct {
+	mockExecutor
+}
+
+func (e *panickingExecutor) Execute(ctx context.Context, req *ExecutionRequest) (*ExecutionResult, error) {
+	panic("skill bug")
+}
+
+func TestRuntimeAdapter_Execute_RecoversPanics(t *testing.T) {
+	recoverer := crash.NewRecoverer(logging.NewNoopLogger())
+	adapter := NewRuntimeAdapterWithRecoverer(&panickingExecutor{}, nil, recoverer)
+
+	execution, err := adapter.Execute(context.Background(), "weather", nil)
+
+	require.Error(t, err)
+	assert.Nil(t, execution)
+	assert.Contains(t, err.Error(), "panic: skill bug")
+	assert.Equal(t, int64(1), recoverer.Metrics().Recovered("skills").Get())
+}
@@ -0,0 +1,20 @@
+package config
+
+// AnalyticsConfig represents configuration for the conversation analytics.
+// Analytics take their events from the event bus, which must be enabled.
+type AnalyticsConfig struct {
+	// Enabled enables or disables the daily counts of messages, active users, tokens, cost and skill executions
+	Enabled bool `yaml:"enabled"`
+
+	// Usage enables or disables recording the tokens and cost of every LLM call per user and workspace,
+	// for monthly billing; it does not depend on Enabled
+	Usage bool `yaml:"usage"`
+}
+
+// DefaultAnalyticsConfig returns the default analytics configuration
+func DefaultAnalyticsConfig() AnalyticsConfig {
+	return AnalyticsConfig{
+		Enabled: true,
+		Usage:   true,
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+)
+
+// MinJWTSecretLength is the minimum length of the HS256 secret used to verify JWTs
+const MinJWTSecretLength = 32
+
+// AuthConfig represents HTTP API authentication configuration
+type AuthConfig struct {
+	// Enabled requires a valid API key or JWT on every request except GET /healthz
+	Enabled bool `yaml:"enabled"`
+
+	// AllowLocalhost lets requests from loopback addresses through without credentials,
+	// with admin scope, for local development
+	AllowLocalhost bool `yaml:"allow_localhost"`
+
+	// APIKeys are static keys, in addition to the keys created through the admin API
+	APIKeys []APIKeyConfig `yaml:"api_keys"`
+
+	// JWT configures bearer token validation (empty secret = JWTs are rejected)
+	JWT JWTConfig `yaml:"jwt"`
+}
+
+// APIKeyConfig represents a static HTTP API key
+type APIKeyConfig struct {
+	// Name identifies the key in logs
+	Name string `yaml:"name"`
+
+	// Key is the secret sent in the X-API-Key header or as a bearer token
+	Key string `yaml:"key" secret:"true"`
+
+	// Scope is "read" (read-only requests) or "admin" (all requests)
+	Scope string `yaml:"scope"`
+}
+
+// JWTConfig represents validation settings for HS256-signed JWT bearer tokens.
+// The "scope" claim selects the scope and defaults to "read".
+type JWTConfig struct {
+	// Secret is the HMAC secret tokens are signed with
+	Secret string `yaml:"secret" secret:"true"`
+
+	// Issuer is the required "iss" claim (empty = not checked)
+	Issuer string `yaml:"issuer"`
+
+	// Audience is the required "aud" claim (empty = not checked)
+	Audience string `yaml:"audience"`
+}
+
+// Validate validates the auth configuration
+func (c *AuthConfig) Validate() error {
+	for i, key := range c.APIKeys {
+		if key.Key == "" {
+			return fmt.Errorf("auth.api_keys[%d].key is required", i)
+		}
+		if key.Scope != "read" && key.Scope != "admin" {
+			return fmt.Errorf("auth.api_keys[%d].scope must be read or admin, got %q", i, key.Scope)
+		}
+	}
+
+	if c.JWT.Secret != "" && len(c.JWT.Secret) < MinJWTSecretLength {
+		return fmt.Errorf("auth.jwt.secret must be at least %d bytes", MinJWTSecretLength)
+	}
+
+	// Keys created through the admin API need an admin credential to bootstrap
+	if c.Enabled && len(c.APIKeys) == 0 && c.JWT.Secret == "" && !c.AllowLocalhost {
+		return fmt.Errorf("auth is enabled but no api_keys, jwt.secret or allow_localhost is configured")
+	}
+
+	return nil
+}
+
+// DefaultAuthConfig returns default auth configuration.
+// Authentication is disabled; once enabled, loopback requests are allowed without credentials.
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		Enabled:        false,
+		AllowLocalhost: true,
+	}
+}
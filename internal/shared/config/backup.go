@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// BackupConfig represents configuration for database backups
+type BackupConfig struct {
+	// Dir is the directory backups are written to
+	Dir string `yaml:"dir"`
+
+	// Compress gzip-compresses backups
+	Compress bool `yaml:"compress"`
+
+	// Keep is the number of newest backups kept (0 = keep all)
+	Keep int `yaml:"keep"`
+
+	// Schedule is a cron expression for automatic backups, evaluated in UTC (empty = disabled),
+	// e.g. "0 3 * * *" or "@daily".
+	// Automatic backups run through the scheduler and require it to be enabled.
+	Schedule string `yaml:"schedule"`
+}
+
+// Validate validates the backup configuration
+func (c *BackupConfig) Validate() error {
+	if c.Dir == "" {
+		return fmt.Errorf("backup dir is required")
+	}
+
+	if c.Keep < 0 {
+		return fmt.Errorf("backup keep must be non-negative, got %d", c.Keep)
+	}
+
+	if c.Schedule != "" {
+		if _, err := valueobject.ParseCronSchedule(c.Schedule); err != nil {
+			return fmt.Errorf("backup schedule %q is not a valid cron expression: %w", c.Schedule, err)
+		}
+	}
+
+	return nil
+}
+
+// DefaultBackupConfig returns default backup configuration.
+// Automatic backups are disabled.
+func DefaultBackupConfig() BackupConfig {
+	return BackupConfig{
+		Dir:      "./data/backups",
+		Compress: true,
+		Keep:     7,
+	}
+}
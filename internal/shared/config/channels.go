@@ -1,47 +1,130 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// workspacePattern matches the workspace IDs connectors can be mapped to
+var workspacePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// DefaultTelegramConnector is the connector name of the Telegram bot configured under telegram
+const DefaultTelegramConnector = "telegram"
 
 // ChannelsConfig represents channels configuration
 type ChannelsConfig struct {
-	Telegram TelegramConfig `json:"telegram" yaml:"telegram"`
-	Discord  DiscordConfig  `json:"discord" yaml:"discord"`
-	Web      WebConfig      `json:"web" yaml:"web"`
+	Telegram     TelegramConfig   `json:"telegram" yaml:"telegram"`
+	TelegramBots []TelegramConfig `json:"telegram_bots" yaml:"telegram_bots"` // Further Telegram bots, e.g. one per workspace
+	Discord      DiscordConfig    `json:"discord" yaml:"discord"`
+	Web          WebConfig        `json:"web" yaml:"web"`
 }
 
 // TelegramConfig represents Telegram bot configuration
 type TelegramConfig struct {
 	Enabled      bool     `json:"enabled" yaml:"enabled"`
-	BotToken     string   `json:"bot_token" yaml:"bot_token"`
+	Name         string   `json:"name" yaml:"name"` // Connector name; required for telegram_bots, "telegram" otherwise
+	BotToken     string   `json:"bot_token" yaml:"bot_token" secret:"true"`
 	AllowedUsers []string `json:"allowed_users" yaml:"allowed_users"`
 	AllowedChats []string `json:"allowed_chats" yaml:"allowed_chats"`
 	WebhookURL   string   `json:"webhook_url" yaml:"webhook_url"` // Optional: use webhook instead of long polling
+	Workspace    string   `json:"workspace" yaml:"workspace"`     // Workspace of the sessions; empty for the default workspace
+
+	// Incoming configures the buffer of received messages and what happens when it is full
+	Incoming IncomingConfig `json:"incoming" yaml:"incoming"`
 }
 
 // DiscordConfig represents Discord bot configuration
 type DiscordConfig struct {
-	Enabled  bool   `json:"enabled" yaml:"enabled"`
-	BotToken string `json:"bot_token" yaml:"bot_token"`
+	Enabled   bool   `json:"enabled" yaml:"enabled"`
+	BotToken  string `json:"bot_token" yaml:"bot_token" secret:"true"`
+	Workspace string `json:"workspace" yaml:"workspace"`
 }
 
 // WebConfig represents web interface configuration
 type WebConfig struct {
-	Enabled bool `json:"enabled" yaml:"enabled"`
+	Enabled   bool   `json:"enabled" yaml:"enabled"`
+	Workspace string `json:"workspace" yaml:"workspace"`
+}
+
+// ConnectorName returns the name the bot is registered under with the message router
+func (c *TelegramConfig) ConnectorName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return DefaultTelegramConnector
+}
+
+// Workspaces maps the names of the enabled connectors to the workspaces they are assigned to.
+// Connectors without a workspace are not listed.
+func (c *ChannelsConfig) Workspaces() map[string]string {
+	workspaces := make(map[string]string)
+	add := func(enabled bool, connector, workspace string) {
+		if enabled && workspace != "" {
+			workspaces[connector] = workspace
+		}
+	}
+	add(c.Telegram.Enabled, c.Telegram.ConnectorName(), c.Telegram.Workspace)
+	for _, bot := range c.TelegramBots {
+		add(bot.Enabled, bot.Name, bot.Workspace)
+	}
+	add(c.Discord.Enabled, "discord", c.Discord.Workspace)
+	add(c.Web.Enabled, "web", c.Web.Workspace)
+	return workspaces
 }
 
 // Validate validates the channels configuration
 func (c *ChannelsConfig) Validate() error {
+	var errs ValidationErrors
 	if c.Telegram.Enabled {
-		if c.Telegram.BotToken == "" {
-			return fmt.Errorf("telegram bot_token is required when telegram is enabled")
+		errs.add(c.Telegram.validate("telegram"))
+	}
+
+	names := map[string]bool{c.Telegram.ConnectorName(): true, "discord": true, "web": true}
+	for i, bot := range c.TelegramBots {
+		field := fmt.Sprintf("telegram_bots[%d]", i)
+		if bot.Name == "" {
+			errs.addf("%s: name is required", field)
+		} else if names[bot.Name] {
+			errs.addf("%s: connector name %q is already used", field, bot.Name)
 		}
-		// At least one of allowed_users or allowed_chats must be specified for security
-		if len(c.Telegram.AllowedUsers) == 0 && len(c.Telegram.AllowedChats) == 0 {
-			return fmt.Errorf("telegram: at least one of allowed_users or allowed_chats must be specified for security when telegram is enabled")
+		names[bot.Name] = true
+		if bot.Enabled {
+			errs.add(bot.validate(field))
 		}
 	}
+
 	if c.Discord.Enabled && c.Discord.BotToken == "" {
-		return fmt.Errorf("discord bot_token is required when discord is enabled")
+		errs.addf("discord bot_token is required when discord is enabled")
+	}
+	if c.Discord.Workspace != "" && !workspacePattern.MatchString(c.Discord.Workspace) {
+		errs.addf("discord: invalid workspace %q", c.Discord.Workspace)
+	}
+	if c.Web.Workspace != "" && !workspacePattern.MatchString(c.Web.Workspace) {
+		errs.addf("web: invalid workspace %q", c.Web.Workspace)
+	}
+	return errs.err()
+}
+
+// validate validates an enabled Telegram bot, reporting errors under field
+func (c *TelegramConfig) validate(field string) error {
+	var errs ValidationErrors
+	if c.BotToken == "" {
+		errs.addf("%s bot_token is required when %s is enabled", field, field)
+	}
+	// At least one of allowed_users or allowed_chats must be specified for security
+	if len(c.AllowedUsers) == 0 && len(c.AllowedChats) == 0 {
+		errs.addf("%s: at least one of allowed_users or allowed_chats must be specified for security when %s is enabled", field, field)
+	}
+	// Telegram only delivers updates to HTTPS webhooks
+	if c.WebhookURL != "" {
+		if u, err := url.Parse(c.WebhookURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			errs.addf("%s: webhook_url must be an https URL, got %q", field, c.WebhookURL)
+		}
+	}
+	if c.Workspace != "" && !workspacePattern.MatchString(c.Workspace) {
+		errs.addf("%s: invalid workspace %q", field, c.Workspace)
 	}
-	return nil
+	errs.add(c.Incoming.Validate(field + ".incoming"))
+	return errs.err()
 }
@@ -92,6 +92,29 @@ func TestTelegramConfig_Validation(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "telegram with plain http webhook_url",
+			config: ChannelsConfig{
+				Telegram: TelegramConfig{
+					Enabled:      true,
+					BotToken:     "test-token",
+					AllowedUsers: []string{"123456789"},
+					WebhookURL:   "http://example.com/webhook/telegram",
+				},
+			},
+			expectError: true,
+			errorMsg:    "webhook_url must be an https URL",
+		},
+		{
+			name: "telegram without bot_token and allowed users reports both",
+			config: ChannelsConfig{
+				Telegram: TelegramConfig{
+					Enabled: true,
+				},
+			},
+			expectError: true,
+			errorMsg:    "bot_token is required when telegram is enabled; telegram: at least one of allowed_users",
+		},
 	}
 
 	for _, tt := range tests {
@@ -194,3 +217,88 @@ func TestWebConfig_NoValidation(t *testing.T) {
 	err := config.Validate()
 	assert.NoError(t, err)
 }
+
+// TestTelegramBots_Validation tests validation of the further Telegram bots
+func TestTelegramBots_Validation(t *testing.T) {
+	bot := func(name, workspace string) TelegramConfig {
+		return TelegramConfig{
+			Enabled:      true,
+			Name:         name,
+			BotToken:     "test-token",
+			AllowedUsers: []string{"123456789"},
+			Workspace:    workspace,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		config   ChannelsConfig
+		errorMsg string
+	}{
+		{
+			name:   "valid bots",
+			config: ChannelsConfig{TelegramBots: []TelegramConfig{bot("telegram-support", "support"), bot("telegram-sales", "sales")}},
+		},
+		{
+			name:     "bot without a name",
+			config:   ChannelsConfig{TelegramBots: []TelegramConfig{bot("", "support")}},
+			errorMsg: "telegram_bots[0]: name is required",
+		},
+		{
+			name:     "bot named like the default bot",
+			config:   ChannelsConfig{TelegramBots: []TelegramConfig{bot("telegram", "support")}},
+			errorMsg: "connector name \"telegram\" is already used",
+		},
+		{
+			name:     "duplicate bot names",
+			config:   ChannelsConfig{TelegramBots: []TelegramConfig{bot("support", ""), bot("support", "")}},
+			errorMsg: "telegram_bots[1]: connector name \"support\" is already used",
+		},
+		{
+			name:     "bot without a token",
+			config:   ChannelsConfig{TelegramBots: []TelegramConfig{{Enabled: true, Name: "telegram-support", AllowedUsers: []string{"1"}}}},
+			errorMsg: "telegram_bots[0] bot_token is required",
+		},
+		{
+			name:     "invalid workspace",
+			config:   ChannelsConfig{TelegramBots: []TelegramConfig{bot("telegram-support", "customer support")}},
+			errorMsg: "invalid workspace",
+		},
+		{
+			name:     "invalid web workspace",
+			config:   ChannelsConfig{Web: WebConfig{Enabled: true, Workspace: "a/b"}},
+			errorMsg: "web: invalid workspace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestChannelsConfig_Workspaces tests the mapping of connectors to workspaces
+func TestChannelsConfig_Workspaces(t *testing.T) {
+	config := ChannelsConfig{
+		Telegram: TelegramConfig{Enabled: true, Workspace: "engineering"},
+		TelegramBots: []TelegramConfig{
+			{Enabled: true, Name: "telegram-support", Workspace: "support"},
+			{Enabled: false, Name: "telegram-sales", Workspace: "sales"},
+		},
+		Web: WebConfig{Enabled: true},
+	}
+
+	assert.Equal(t, "telegram", config.Telegram.ConnectorName())
+	assert.Equal(t, map[string]string{
+		"telegram":         "engineering",
+		"telegram-support": "support",
+	}, config.Workspaces())
+}
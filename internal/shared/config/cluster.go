@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+)
+
+// ClusterConfig represents the configuration of running several instances against one
+// database. The instances elect a leader, which runs the scheduler, the retention and the
+// connectors that allow a single consumer, such as Telegram bots; jobs of the job queue are run
+// by all instances.
+type ClusterConfig struct {
+	// Enabled elects a leader among the instances sharing the database
+	Enabled bool `yaml:"enabled"`
+
+	// InstanceID names this instance; empty uses the host name and process ID
+	InstanceID string `yaml:"instance_id"`
+
+	// LeaseTTLSec is how long the leadership lasts without being renewed in seconds, and so how
+	// long the instances wait for a new leader when the leader stops without handing over
+	LeaseTTLSec int `yaml:"lease_ttl_sec"`
+
+	// RenewIntervalSec is how often the leader renews its lease and the other instances try to
+	// take it over in seconds
+	RenewIntervalSec int `yaml:"renew_interval_sec"`
+}
+
+// Validate validates the cluster configuration
+func (c *ClusterConfig) Validate() error {
+	if c.RenewIntervalSec <= 0 {
+		return fmt.Errorf("cluster.renew_interval_sec must be positive, got %d", c.RenewIntervalSec)
+	}
+	if c.LeaseTTLSec < 2*c.RenewIntervalSec {
+		return fmt.Errorf("cluster.lease_ttl_sec must be at least twice renew_interval_sec, got %d", c.LeaseTTLSec)
+	}
+	return nil
+}
+
+// DefaultClusterConfig returns default cluster configuration
+func DefaultClusterConfig() ClusterConfig {
+	return ClusterConfig{
+		Enabled:          false,
+		LeaseTTLSec:      15,
+		RenewIntervalSec: 5,
+	}
+}
@@ -1,49 +1,107 @@
 package config
 
 import (
-	"gopkg.in/yaml.v3"
+	"fmt"
+	"os"
+
+	"github.com/atumaikin/nexflow/internal/shared/secrets"
 )
 
+// newSecretResolver creates the resolver of the secret references of a configuration
+var newSecretResolver = secrets.NewDefaultResolver
+
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	LLM      LLMConfig      `yaml:"llm"`
-	Channels ChannelsConfig `yaml:"channels"`
-	Skills   SkillsConfig   `yaml:"skills"`
-	Logging  LoggingConfig  `yaml:"logging"`
-	EventBus EventBusConfig `yaml:"eventbus"`
-	Router   RouterConfig   `yaml:"router"`
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	LLM         LLMConfig         `yaml:"llm"`
+	Channels    ChannelsConfig    `yaml:"channels"`
+	Skills      SkillsConfig      `yaml:"skills"`
+	Logging     LoggingConfig     `yaml:"logging"`
+	EventBus    EventBusConfig    `yaml:"eventbus"`
+	Router      RouterConfig      `yaml:"router"`
+	Scheduler   SchedulerConfig   `yaml:"scheduler"`
+	Retention   RetentionConfig   `yaml:"retention"`
+	Backup      BackupConfig      `yaml:"backup"`
+	Auth        AuthConfig        `yaml:"auth"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	MessageJobs MessageJobsConfig `yaml:"message_jobs"`
+	Jobs        JobsConfig        `yaml:"jobs"`
+	Cluster     ClusterConfig     `yaml:"cluster"`
+	Webhooks    WebhooksConfig    `yaml:"webhooks"`
+	Analytics   AnalyticsConfig   `yaml:"analytics"`
+	PII         PIIConfig         `yaml:"pii"`
+	Documents   DocumentsConfig   `yaml:"documents"`
+
+	Observability ObservabilityConfig `yaml:"observability"`
+	UpdateCheck   UpdateCheckConfig   `yaml:"update_check"`
+	Orchestrator  OrchestratorConfig  `yaml:"orchestrator"`
 }
 
 // Load loads configuration from a YAML file.
-// It expands environment variables in the format ${VAR_NAME} and validates the configuration.
+// It applies NEXFLOW_* environment variables, expands environment variables in the format
+// ${VAR_NAME} and secret references such as ${file:/run/secrets/x}, and validates the configuration.
 // Returns an error if the file cannot be read, parsed, or if the configuration is invalid.
 func Load(path string) (*Config, error) {
-	// Read file content
-	data, err := readConfigFile(path)
-	if err != nil {
-		return nil, err
+	return LoadWithOverrides(path, nil)
+}
+
+// LoadWithOverrides loads configuration like LoadProfile, with the profile selected by the
+// NEXFLOW_PROFILE environment variable.
+func LoadWithOverrides(path string, overrides []Override) (*Config, error) {
+	return LoadProfile(path, os.Getenv(ProfileEnv), overrides)
+}
+
+// LoadProfile loads configuration in layers: the defaults, the YAML file at path, the
+// NEXFLOW_* environment variables (see ApplyEnv) and the overrides, e.g. from command-line
+// flags, each layer taking precedence over the ones before. If path is empty, no file is
+// read, so the configuration can come from the environment alone.
+//
+// The file may build on other files listed under include:, relative to it, and define
+// settings for named profiles under profiles:, e.g. profiles.production. The included files,
+// the file and the section of the profile are merged in this order; a profile that no file
+// defines is an error.
+//
+// Then ${VAR_NAME} references are replaced with environment variables and ${file:path},
+// ${vault:path#key} and ${aws-sm:name#key} references with the secrets they point to (see
+// secrets.NewDefaultResolver); a secret that cannot be resolved fails with a
+// *secrets.ResolveError. The result is validated.
+func LoadProfile(path, profile string, overrides []Override) (*Config, error) {
+	config := defaultConfig()
+	if path != "" {
+		// Read the file with its includes and profile
+		node, err := loadConfigFile(path, profile)
+		if err != nil {
+			return nil, err
+		}
+		if err := node.Decode(config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	} else if profile != "" {
+		return nil, fmt.Errorf("config profile %q requires a configuration file", profile)
 	}
 
-	// Parse configuration
-	config, err := parseConfig(data, path)
-	if err != nil {
+	// Apply NEXFLOW_* environment variables and the overrides
+	if err := config.ApplyEnv(os.Environ()); err != nil {
 		return nil, err
 	}
+	for _, override := range overrides {
+		if err := config.Set(override.Key, override.Value); err != nil {
+			return nil, err
+		}
+	}
 
-	// Expand environment variables
-	if err := expandEnvVars(config); err != nil {
+	// Expand environment variables and resolve secret references
+	if err := expandEnvVars(config, newSecretResolver()); err != nil {
 		return nil, err
 	}
 
-	// Apply default values for router config if not set
+	// Apply the default message length limit if it is set to zero
 	if config.Router.MaxMessageLength == 0 {
-		defaultRouter := DefaultRouterConfig()
-		config.Router = defaultRouter
+		config.Router.MaxMessageLength = DefaultRouterConfig().MaxMessageLength
 	}
 
-
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		return nil, err
@@ -52,48 +110,77 @@ func Load(path string) (*Config, error) {
 	return config, nil
 }
 
-// Validate validates the configuration by validating all sub-configurations
+// Validate validates the configuration by validating all sub-configurations.
+// All problems are reported at once as ValidationErrors.
 func (c *Config) Validate() error {
-	if err := c.Server.Validate(); err != nil {
-		return err
-	}
-	if err := c.Database.Validate(); err != nil {
-		return err
-	}
-	if err := c.LLM.Validate(); err != nil {
-		return err
-	}
-	if err := c.Skills.Validate(); err != nil {
-		return err
-	}
-	if err := c.Router.Validate(); err != nil {
-		return err
+	var errs ValidationErrors
+	for _, v := range []interface{ Validate() error }{
+		&c.Server,
+		&c.Database,
+		&c.LLM,
+		&c.Skills,
+		&c.Router,
+		&c.Orchestrator,
+		&c.Scheduler,
+		&c.Retention,
+		&c.Backup,
+		&c.Auth,
+		&c.RateLimit,
+		&c.Idempotency,
+		&c.MessageJobs,
+		&c.Jobs,
+		&c.Cluster,
+		&c.Webhooks,
+		&c.Logging,
+		&c.EventBus,
+		&c.Channels,
+		&c.PII,
+		&c.Documents,
+		&c.Observability,
+		&c.UpdateCheck,
+	} {
+		errs.add(v.Validate())
 	}
-	if err := c.Logging.Validate(); err != nil {
-		return err
+	// Leases and jobs of a cluster are claimed with SQLite SQL, which PostgreSQL does not run
+	if c.Cluster.Enabled && c.Database.Type == "postgres" {
+		errs.addf("cluster.enabled requires database.type \"sqlite\", leader election is not supported on PostgreSQL")
 	}
-	if err := c.EventBus.Validate(); err != nil {
-		return err
-	}
-	if err := c.Channels.Validate(); err != nil {
-		return err
-	}
-	return nil
+	return errs.err()
 }
 
-// parseConfig parses configuration data from YAML file
-func parseConfig(data []byte, path string) (*Config, error) {
-	var config Config
-	ext := getFileExtension(path)
+// defaultConfig returns the configuration a YAML file is parsed into.
+// Every section is pre-filled with its defaults, so that a minimal configuration naming only an
+// LLM provider is valid, omitted keys keep their default values and an explicit
+// "enabled: false" is respected.
+func defaultConfig() *Config {
+	// The event bus stays disabled unless enabled explicitly
+	eventBus := DefaultEventBusConfig()
+	eventBus.Enabled = false
 
-	switch ext {
-	case ".yaml", ".yml":
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			return nil, err
-		}
-	default:
-		return nil, errUnsupportedFormat(ext)
-	}
+	return &Config{
+		LLM:         LLMConfig{SessionTitles: true, HealthCheck: DefaultLLMHealthCheckConfig()},
+		Server:      DefaultServerConfig(),
+		Database:    DefaultDatabaseConfig(),
+		Skills:      DefaultSkillsConfig(),
+		Logging:     DefaultLoggingConfig(),
+		Router:      DefaultRouterConfig(),
+		EventBus:    eventBus,
+		Scheduler:   DefaultSchedulerConfig(),
+		Retention:   DefaultRetentionConfig(),
+		Backup:      DefaultBackupConfig(),
+		Auth:        DefaultAuthConfig(),
+		RateLimit:   DefaultRateLimitConfig(),
+		Idempotency: DefaultIdempotencyConfig(),
+		MessageJobs: DefaultMessageJobsConfig(),
+		Jobs:        DefaultJobsConfig(),
+		Cluster:     DefaultClusterConfig(),
+		Webhooks:    DefaultWebhooksConfig(),
+		Analytics:   DefaultAnalyticsConfig(),
+		PII:         DefaultPIIConfig(),
+		Documents:   DefaultDocumentsConfig(),
 
-	return &config, nil
+		Observability: DefaultObservabilityConfig(),
+		UpdateCheck:   DefaultUpdateCheckConfig(),
+		Orchestrator:  DefaultOrchestratorConfig(),
+	}
 }
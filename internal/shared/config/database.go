@@ -1,30 +1,75 @@
 package config
 
 import (
-	"fmt"
+	"strings"
 	"time"
 )
 
+// sqliteJournalModes lists the journal modes accepted by SQLite
+var sqliteJournalModes = []string{"delete", "truncate", "persist", "memory", "wal", "off"}
+
 // DatabaseConfig represents database configuration
 type DatabaseConfig struct {
 	Type            string        `json:"type" yaml:"type"`
 	Path            string        `json:"path" yaml:"path"`
-	MigrationsPath  string        `json:"migrations_path" yaml:"migrations_path"`
+	MigrationsPath  string        `json:"migrations_path" yaml:"migrations_path"` // Optional, embedded migrations are used if empty
 	MaxOpenConns    int           `json:"max_open_conns" yaml:"max_open_conns"`
 	MaxIdleConns    int           `json:"max_idle_conns" yaml:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+	BusyTimeout     time.Duration `json:"busy_timeout" yaml:"busy_timeout"` // SQLite only: how long to wait for a lock
+	JournalMode     string        `json:"journal_mode" yaml:"journal_mode"` // SQLite only: "wal" by default
+
+	SlowQueryThreshold time.Duration `json:"slow_query_threshold" yaml:"slow_query_threshold"` // Queries taking at least this long are logged, 0 disables
+}
+
+// Default SQLite database
+const (
+	DefaultDatabaseType = "sqlite"
+	DefaultDatabasePath = "./data/nexflow.db"
+)
+
+// DefaultDatabaseConfig returns default database configuration: SQLite at ./data/nexflow.db
+func DefaultDatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{
+		Type: DefaultDatabaseType,
+		Path: DefaultDatabasePath,
+	}
 }
 
 // Validate validates the database configuration
 func (d *DatabaseConfig) Validate() error {
-	if d.Type == "" {
-		return fmt.Errorf("database.type is required")
+	var errs ValidationErrors
+	switch d.Type {
+	case "":
+		errs.addf("database.type is required")
+	case "sqlite", "postgres":
+	default:
+		errs.addf("database.type must be \"sqlite\" or \"postgres\", got %q", d.Type)
 	}
 	if d.Path == "" {
-		return fmt.Errorf("database.path is required")
+		errs.addf("database.path is required")
+	}
+	if d.MaxOpenConns < 0 || d.MaxIdleConns < 0 {
+		errs.addf("database max_open_conns and max_idle_conns must be non-negative")
+	} else if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		errs.addf("database max_idle_conns (%d) must not exceed max_open_conns (%d)", d.MaxIdleConns, d.MaxOpenConns)
+	}
+	if d.ConnMaxLifetime < 0 || d.ConnMaxIdleTime < 0 || d.BusyTimeout < 0 || d.SlowQueryThreshold < 0 {
+		errs.addf("database conn_max_lifetime, conn_max_idle_time, busy_timeout and slow_query_threshold must be non-negative")
 	}
-	if d.MigrationsPath == "" {
-		return fmt.Errorf("database.migrations_path is required")
+	if d.JournalMode != "" && !isSQLiteJournalMode(d.JournalMode) {
+		errs.addf("database journal_mode must be one of %s, got %q", strings.Join(sqliteJournalModes, ", "), d.JournalMode)
+	}
+	return errs.err()
+}
+
+// isSQLiteJournalMode reports whether mode is a known SQLite journal mode
+func isSQLiteJournalMode(mode string) bool {
+	for _, m := range sqliteJournalModes {
+		if strings.EqualFold(mode, m) {
+			return true
+		}
 	}
-	return nil
+	return false
 }
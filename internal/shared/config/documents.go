@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+)
+
+// DocumentsConfig represents configuration for the documents sent to the bot, such as PDFs.
+// Their text is split into chunks kept with the session, and the chunks closest to a question
+// are passed to the LLM.
+type DocumentsConfig struct {
+	// Enabled enables or disables reading documents; disabled, they are passed to the LLM as
+	// a description of the file
+	Enabled bool `yaml:"enabled"`
+
+	// MaxFileSizeMB is the size limit of a document in megabytes. The Telegram Bot API serves
+	// files of up to 20 MB.
+	MaxFileSizeMB int `yaml:"max_file_size_mb"`
+
+	// ChunkSize is the length of the chunks in characters
+	ChunkSize int `yaml:"chunk_size"`
+
+	// ChunkOverlap is the number of characters a chunk repeats from the previous one
+	ChunkOverlap int `yaml:"chunk_overlap"`
+
+	// MaxChunks is the maximum number of chunks of a document; longer documents are rejected
+	MaxChunks int `yaml:"max_chunks"`
+
+	// TopK is the number of chunks passed to the LLM with a message
+	TopK int `yaml:"top_k"`
+}
+
+// Validate validates the documents configuration
+func (c *DocumentsConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxFileSizeMB <= 0 {
+		return fmt.Errorf("documents max_file_size_mb must be positive, got %d", c.MaxFileSizeMB)
+	}
+	if c.ChunkSize <= 0 {
+		return fmt.Errorf("documents chunk_size must be positive, got %d", c.ChunkSize)
+	}
+	if c.ChunkOverlap < 0 || c.ChunkOverlap >= c.ChunkSize {
+		return fmt.Errorf("documents chunk_overlap must be non-negative and less than chunk_size, got %d", c.ChunkOverlap)
+	}
+	if c.MaxChunks <= 0 {
+		return fmt.Errorf("documents max_chunks must be positive, got %d", c.MaxChunks)
+	}
+	if c.TopK <= 0 {
+		return fmt.Errorf("documents top_k must be positive, got %d", c.TopK)
+	}
+	return nil
+}
+
+// DefaultDocumentsConfig returns the default documents configuration
+func DefaultDocumentsConfig() DocumentsConfig {
+	return DocumentsConfig{
+		Enabled:       true,
+		MaxFileSizeMB: 10,
+		ChunkSize:     1000,
+		ChunkOverlap:  150,
+		MaxChunks:     500,
+		TopK:          4,
+	}
+}
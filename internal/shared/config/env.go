@@ -1,12 +1,15 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
+
+	"github.com/atumaikin/nexflow/internal/shared/secrets"
 )
 
 const (
@@ -32,14 +35,17 @@ func errUnsupportedFormat(ext string) error {
 	return fmt.Errorf("unsupported config file format: %s", ext)
 }
 
-// expandEnvVars expands environment variable references in the config
+// expandEnvVars expands environment variable references and secret references in the config
 // This is a universal function that processes all string fields recursively
-func expandEnvVars(config *Config) error {
-	return expandValue(reflect.ValueOf(config).Elem())
+func expandEnvVars(config *Config, resolver *secrets.Resolver) error {
+	pattern := secretRefPattern(resolver)
+	return expandValue(reflect.ValueOf(config).Elem(), func(s string) (string, error) {
+		return expandSecretRefs(expandAllEnvVars(s), pattern, resolver)
+	})
 }
 
 // expandValue recursively processes all string fields in a struct or map
-func expandValue(v reflect.Value) error {
+func expandValue(v reflect.Value, expand func(string) (string, error)) error {
 	// Skip invalid or unexported values
 	if !v.IsValid() {
 		return nil
@@ -48,7 +54,11 @@ func expandValue(v reflect.Value) error {
 	switch v.Kind() {
 	case reflect.String:
 		if v.CanSet() {
-			v.SetString(expandAllEnvVars(v.String()))
+			expanded, err := expand(v.String())
+			if err != nil {
+				return err
+			}
+			v.SetString(expanded)
 		}
 	case reflect.Struct:
 		// Skip unexported fields
@@ -56,7 +66,7 @@ func expandValue(v reflect.Value) error {
 			field := v.Field(i)
 			// Check if field is exported and can be set
 			if field.CanInterface() && field.CanAddr() {
-				if err := expandValue(field); err != nil {
+				if err := expandValue(field, expand); err != nil {
 					return err
 				}
 			}
@@ -82,7 +92,7 @@ func expandValue(v reflect.Value) error {
 			newValue.Set(value)
 
 			// Expand the copy
-			if err := expandValue(newValue); err != nil {
+			if err := expandValue(newValue, expand); err != nil {
 				return err
 			}
 
@@ -101,7 +111,7 @@ func expandValue(v reflect.Value) error {
 		for i := 0; i < v.Len(); i++ {
 			element := v.Index(i)
 			if element.CanAddr() && element.CanSet() {
-				if err := expandValue(element); err != nil {
+				if err := expandValue(element, expand); err != nil {
 					return err
 				}
 			}
@@ -113,7 +123,7 @@ func expandValue(v reflect.Value) error {
 		}
 		// Dereference and process
 		if v.Elem().CanAddr() {
-			return expandValue(v.Elem())
+			return expandValue(v.Elem(), expand)
 		}
 	}
 
@@ -142,3 +152,31 @@ func expandAllEnvVars(s string) string {
 
 	return result
 }
+
+// secretRefPattern returns the pattern of the ${scheme:reference} secret references of the
+// schemes of a resolver
+func secretRefPattern(resolver *secrets.Resolver) *regexp.Regexp {
+	schemes := resolver.Schemes()
+	for i, scheme := range schemes {
+		schemes[i] = regexp.QuoteMeta(scheme)
+	}
+	return regexp.MustCompile(`\$\{(` + strings.Join(schemes, "|") + `):([^}]*)\}`)
+}
+
+// expandSecretRefs replaces the secret references in a string with the secrets they point to.
+// Unlike environment variables, a reference that cannot be resolved is an error.
+func expandSecretRefs(s string, pattern *regexp.Regexp, resolver *secrets.Resolver) (string, error) {
+	var resolveErr error
+	result := pattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := pattern.FindStringSubmatch(match)
+		value, err := resolver.Resolve(context.Background(), groups[1], groups[2])
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return value
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationErrors lists every problem found by validating a configuration, so that all of
+// them can be fixed at once
+type ValidationErrors []error
+
+// Error returns the error message: the only error, or all of them separated by semicolons
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d configuration errors: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
+// add appends an error, flattening nested ValidationErrors; nil is ignored
+func (e *ValidationErrors) add(err error) {
+	if err == nil {
+		return
+	}
+	if nested, ok := err.(ValidationErrors); ok {
+		*e = append(*e, nested...)
+		return
+	}
+	*e = append(*e, err)
+}
+
+// addf appends an error formatted as by fmt.Errorf
+func (e *ValidationErrors) addf(format string, args ...any) {
+	e.add(fmt.Errorf(format, args...))
+}
+
+// err returns the errors as an error, nil if there are none
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
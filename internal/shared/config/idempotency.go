@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+)
+
+// MaxIdempotencyTTLHours limits how long the responses of idempotent requests are kept
+const MaxIdempotencyTTLHours = 24 * 30
+
+// IdempotencyConfig represents the configuration of the Idempotency-Key header of write API
+// endpoints. A request retried with the same key gets the response of the first request
+// instead of being handled again.
+type IdempotencyConfig struct {
+	// Enabled enables or disables idempotency keys
+	Enabled bool `yaml:"enabled"`
+
+	// TTLHours is how long the response of a request is replayed for its key
+	TTLHours int `yaml:"ttl_hours"`
+}
+
+// Validate validates the idempotency configuration
+func (c *IdempotencyConfig) Validate() error {
+	if c.TTLHours <= 0 {
+		return fmt.Errorf("idempotency.ttl_hours must be positive, got %d", c.TTLHours)
+	}
+	if c.TTLHours > MaxIdempotencyTTLHours {
+		return fmt.Errorf("idempotency.ttl_hours too large, got %d (max %d)", c.TTLHours, MaxIdempotencyTTLHours)
+	}
+	return nil
+}
+
+// DefaultIdempotencyConfig returns default idempotency configuration.
+// Responses are replayed for a day, which covers the retries of clients.
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		Enabled:  true,
+		TTLHours: 24,
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Signature schemes of inbound webhooks
+const (
+	// InboundWebhookSecretToken compares a header with the secret, like the
+	// X-Telegram-Bot-Api-Secret-Token header of Telegram
+	InboundWebhookSecretToken = "secret_token"
+
+	// InboundWebhookSlack verifies the v0 HMAC-SHA256 signature of the timestamp and body
+	// in the X-Slack-Signature and X-Slack-Request-Timestamp headers of Slack
+	InboundWebhookSlack = "slack"
+
+	// InboundWebhookHMACSHA256 verifies the "sha256=" HMAC-SHA256 signature of the body,
+	// like the X-Hub-Signature-256 header of WhatsApp
+	InboundWebhookHMACSHA256 = "hmac_sha256"
+)
+
+// DefaultInboundWebhookToleranceSec is the default replay window of timestamped signatures
+const DefaultInboundWebhookToleranceSec = 300
+
+// InboundWebhookConfig represents the signature verification of the webhook requests a
+// connector receives. Webhook-mode connectors embed it in their configuration, so that
+// every connector has its own scheme and secret.
+type InboundWebhookConfig struct {
+	// Scheme is secret_token, slack or hmac_sha256 (empty = requests are not verified)
+	Scheme string `json:"scheme" yaml:"scheme"`
+
+	// Secret is the secret token, signing secret or app secret shared with the platform
+	Secret string `json:"secret" yaml:"secret" secret:"true"`
+
+	// Header overrides the header carrying the token or signature of the scheme
+	Header string `json:"header" yaml:"header"`
+
+	// ToleranceSec is how old the timestamp of a signed request may be, in seconds, so that
+	// recorded requests cannot be replayed later (0 = 300 seconds); untimestamped schemes ignore it
+	ToleranceSec int `json:"tolerance_sec" yaml:"tolerance_sec"`
+}
+
+// Enabled reports whether webhook requests are verified
+func (c InboundWebhookConfig) Enabled() bool {
+	return c.Scheme != ""
+}
+
+// Tolerance returns the replay window of timestamped signatures
+func (c InboundWebhookConfig) Tolerance() time.Duration {
+	if c.ToleranceSec == 0 {
+		return DefaultInboundWebhookToleranceSec * time.Second
+	}
+	return time.Duration(c.ToleranceSec) * time.Second
+}
+
+// Validate validates the webhook verification of the connector configured at field
+func (c *InboundWebhookConfig) Validate(field string) error {
+	switch c.Scheme {
+	case "":
+		return nil
+	case InboundWebhookSecretToken, InboundWebhookSlack, InboundWebhookHMACSHA256:
+	default:
+		return fmt.Errorf("%s.scheme must be %s, %s or %s, got %q", field,
+			InboundWebhookSecretToken, InboundWebhookSlack, InboundWebhookHMACSHA256, c.Scheme)
+	}
+	if c.Secret == "" {
+		return fmt.Errorf("%s.secret is required for scheme %s", field, c.Scheme)
+	}
+	if c.ToleranceSec < 0 {
+		return fmt.Errorf("%s.tolerance_sec must be non-negative, got %d", field, c.ToleranceSec)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import "time"
+
+// OverflowSpill stores messages that do not fit into the incoming buffer of a connector in the
+// database and feeds them back, oldest first, once the buffer has room
+const OverflowSpill = "spill"
+
+// Defaults of the incoming buffers of connectors
+const (
+	DefaultIncomingBufferSize     = 100
+	DefaultIncomingBlockTimeoutMs = 5000
+)
+
+// MaxIncomingBufferSize limits the incoming buffer of a connector
+const MaxIncomingBufferSize = 100000
+
+// IncomingConfig represents the buffer of the messages a connector received and the message
+// router has not picked up yet. Connectors embed it in their configuration, so that every
+// connector has its own buffer size and overflow policy.
+type IncomingConfig struct {
+	// BufferSize is the number of messages buffered (0 = 100)
+	BufferSize int `json:"buffer_size" yaml:"buffer_size"`
+
+	// Overflow decides what happens to a message that does not fit into the buffer:
+	// "drop_newest" (default), "drop_oldest", "block" or "spill"
+	Overflow string `json:"overflow" yaml:"overflow"`
+
+	// BlockTimeoutMs is how long the "block" policy waits for room before the message is
+	// dropped, in milliseconds (0 = 5000)
+	BlockTimeoutMs int `json:"block_timeout_ms" yaml:"block_timeout_ms"`
+}
+
+// Size returns the number of messages buffered
+func (c IncomingConfig) Size() int {
+	if c.BufferSize == 0 {
+		return DefaultIncomingBufferSize
+	}
+	return c.BufferSize
+}
+
+// Policy returns the overflow policy
+func (c IncomingConfig) Policy() string {
+	if c.Overflow == "" {
+		return OverflowDropNewest
+	}
+	return c.Overflow
+}
+
+// BlockTimeout returns how long the "block" policy waits for room
+func (c IncomingConfig) BlockTimeout() time.Duration {
+	if c.BlockTimeoutMs == 0 {
+		return DefaultIncomingBlockTimeoutMs * time.Millisecond
+	}
+	return time.Duration(c.BlockTimeoutMs) * time.Millisecond
+}
+
+// Validate validates the incoming buffer of the connector configured at field
+func (c *IncomingConfig) Validate(field string) error {
+	var errs ValidationErrors
+	if c.BufferSize < 0 || c.BufferSize > MaxIncomingBufferSize {
+		errs.addf("%s.buffer_size must be between 0 and %d, got %d", field, MaxIncomingBufferSize, c.BufferSize)
+	}
+	switch c.Overflow {
+	case "", OverflowDropNewest, OverflowDropOldest, OverflowBlock, OverflowSpill:
+	default:
+		errs.addf("%s.overflow must be %q, %q, %q or %q, got %q", field,
+			OverflowDropNewest, OverflowDropOldest, OverflowBlock, OverflowSpill, c.Overflow)
+	}
+	if c.BlockTimeoutMs < 0 {
+		errs.addf("%s.block_timeout_ms must be non-negative, got %d", field, c.BlockTimeoutMs)
+	}
+	return errs.err()
+}
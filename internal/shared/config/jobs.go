@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+)
+
+// JobsConfig represents the configuration of the persistent job queue. Schedule runs,
+// asynchronous messages, document ingestion and webhook deliveries are stored in the database
+// and run from it, so that they survive restarts.
+type JobsConfig struct {
+	// Enabled runs background work from the job queue; disabled keeps it in memory
+	Enabled bool `yaml:"enabled"`
+
+	// Workers is the number of jobs run at the same time
+	Workers int `yaml:"workers"`
+
+	// PollIntervalMs is how often idle workers look for due jobs in milliseconds
+	PollIntervalMs int `yaml:"poll_interval_ms"`
+
+	// VisibilityTimeoutSec is the lease of a running job in seconds; the job is run again
+	// when its process stops renewing it
+	VisibilityTimeoutSec int `yaml:"visibility_timeout_sec"`
+
+	// MaxAttempts is how many times a job is attempted before it fails
+	MaxAttempts int `yaml:"max_attempts"`
+
+	// RetryBackoffMs is the delay before the first retry in milliseconds; it doubles with every retry
+	RetryBackoffMs int `yaml:"retry_backoff_ms"`
+
+	// MaxRetryBackoffSec caps the delay between retries in seconds
+	MaxRetryBackoffSec int `yaml:"max_retry_backoff_sec"`
+
+	// TTLHours is how long finished jobs are kept
+	TTLHours int `yaml:"ttl_hours"`
+}
+
+// Validate validates the jobs configuration
+func (c *JobsConfig) Validate() error {
+	if c.Workers <= 0 {
+		return fmt.Errorf("jobs.workers must be positive, got %d", c.Workers)
+	}
+	if c.PollIntervalMs <= 0 {
+		return fmt.Errorf("jobs.poll_interval_ms must be positive, got %d", c.PollIntervalMs)
+	}
+	if c.VisibilityTimeoutSec < 3 {
+		return fmt.Errorf("jobs.visibility_timeout_sec must be at least 3, got %d", c.VisibilityTimeoutSec)
+	}
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("jobs.max_attempts must be positive, got %d", c.MaxAttempts)
+	}
+	if c.RetryBackoffMs < 0 {
+		return fmt.Errorf("jobs.retry_backoff_ms must be non-negative, got %d", c.RetryBackoffMs)
+	}
+	if c.MaxRetryBackoffSec*1000 < c.RetryBackoffMs {
+		return fmt.Errorf("jobs.max_retry_backoff_sec must not be less than retry_backoff_ms, got %d", c.MaxRetryBackoffSec)
+	}
+	if c.TTLHours <= 0 {
+		return fmt.Errorf("jobs.ttl_hours must be positive, got %d", c.TTLHours)
+	}
+	return nil
+}
+
+// DefaultJobsConfig returns default jobs configuration
+func DefaultJobsConfig() JobsConfig {
+	return JobsConfig{
+		Enabled:              true,
+		Workers:              4,
+		PollIntervalMs:       1000,
+		VisibilityTimeoutSec: 300,
+		MaxAttempts:          3,
+		RetryBackoffMs:       5000,
+		MaxRetryBackoffSec:   600,
+		TTLHours:             24,
+	}
+}
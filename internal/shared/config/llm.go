@@ -1,34 +1,226 @@
 package config
 
 import (
-	"fmt"
+	"net/url"
+	"sort"
+	"unicode/utf8"
 )
 
 // LLMConfig represents LLM provider configuration
 type LLMConfig struct {
 	DefaultProvider string                 `json:"default_provider" yaml:"default_provider"`
 	Providers       map[string]LLMProvider `json:"providers" yaml:"providers"`
+
+	// Instructions are passed to the LLM in every session, before the custom instructions of the
+	// user and the session; PUT /admin/instructions replaces them until the server restarts
+	Instructions string `json:"instructions,omitempty" yaml:"instructions,omitempty"`
+
+	// Prompts are fragments of the system prompt passed to the LLM within their scope;
+	// PUT /admin/prompts/{scope}/{name} replaces them until the server restarts
+	Prompts LLMPromptsConfig `json:"prompts,omitempty" yaml:"prompts,omitempty"`
+
+	// SessionTitles generates a short title for each session with the default provider after
+	// its first exchange, shown in session listings (default true)
+	SessionTitles bool `json:"session_titles" yaml:"session_titles"`
+
+	// HealthCheck checks the availability of every configured provider periodically
+	HealthCheck LLMHealthCheckConfig `json:"health_check" yaml:"health_check"`
+
+	// Failover lists, in order, the providers answering the messages that select no provider
+	// while the default provider fails its health checks
+	Failover []string `json:"failover,omitempty" yaml:"failover,omitempty"`
+}
+
+// LLMPromptsConfig maps the workspaces, connectors and skills to their prompt fragments. The
+// fragments of the workspace and the connector of a message follow llm.instructions; those of
+// skills are passed when planning with the skills and answering from their output.
+type LLMPromptsConfig struct {
+	Workspaces map[string]string `json:"workspaces,omitempty" yaml:"workspaces,omitempty"` // By workspace ID
+	Connectors map[string]string `json:"connectors,omitempty" yaml:"connectors,omitempty"` // By connector name, e.g. telegram
+	Skills     map[string]string `json:"skills,omitempty" yaml:"skills,omitempty"`         // By skill name
+}
+
+// validate validates the prompt fragments
+func (c *LLMPromptsConfig) validate() error {
+	var errs ValidationErrors
+	for _, scope := range []struct {
+		field   string
+		prompts map[string]string
+	}{
+		{"workspaces", c.Workspaces},
+		{"connectors", c.Connectors},
+		{"skills", c.Skills},
+	} {
+		names := make([]string, 0, len(scope.prompts))
+		for name := range scope.prompts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if scope.field == "workspaces" && !workspacePattern.MatchString(name) {
+				errs.addf("llm.prompts.workspaces: invalid workspace %q", name)
+			}
+			if n := utf8.RuneCountInString(scope.prompts[name]); n > MaxLLMInstructionsLength {
+				errs.addf("llm.prompts.%s.%s must be at most %d characters, got %d", scope.field, name, MaxLLMInstructionsLength, n)
+			}
+		}
+	}
+	return errs.err()
+}
+
+// LLMHealthCheckConfig represents the periodic availability checks of the LLM providers
+type LLMHealthCheckConfig struct {
+	Enabled     bool `json:"enabled" yaml:"enabled"`
+	IntervalSec int  `json:"interval_sec" yaml:"interval_sec"`
+	TimeoutSec  int  `json:"timeout_sec" yaml:"timeout_sec"`
+
+	// History is the number of checks kept per provider for the admin API
+	History int `json:"history" yaml:"history"`
+
+	// FailureThreshold is the number of consecutive failed checks after which a provider is
+	// considered down
+	FailureThreshold int `json:"failure_threshold" yaml:"failure_threshold"`
+}
+
+// DefaultLLMHealthCheckConfig returns the default health checks: every provider each minute,
+// down after two failed checks
+func DefaultLLMHealthCheckConfig() LLMHealthCheckConfig {
+	return LLMHealthCheckConfig{
+		Enabled:          true,
+		IntervalSec:      60,
+		TimeoutSec:       10,
+		History:          20,
+		FailureThreshold: 2,
+	}
 }
 
+// MaxLLMHealthCheckHistory is the maximum value of llm.health_check.history
+const MaxLLMHealthCheckHistory = 1000
+
+// MaxLLMInstructionsLength is the maximum length in characters of llm.instructions and of each
+// prompt fragment of llm.prompts
+const MaxLLMInstructionsLength = 4000
+
 // LLMProvider represents a single LLM provider configuration
 type LLMProvider struct {
-	APIKey      string  `json:"api_key" yaml:"api_key"`
+	APIKey      string  `json:"api_key" yaml:"api_key" secret:"true"`
 	BaseURL     string  `json:"base_url" yaml:"base_url"`
 	Model       string  `json:"model" yaml:"model"`
 	Temperature float64 `json:"temperature" yaml:"temperature"`
 	MaxTokens   int     `json:"max_tokens" yaml:"max_tokens"`
+
+	// Models lists the models a message may select besides Model; empty allows any model
+	Models []string `json:"models,omitempty" yaml:"models,omitempty"`
+
+	// ContextTokens is the context window of the models: the oldest messages of longer
+	// conversations are left out (0 = conversations are sent whole)
+	ContextTokens int `json:"context_tokens,omitempty" yaml:"context_tokens,omitempty"`
 }
 
+// Temperature range accepted by the LLM providers
+const (
+	MinLLMTemperature = 0.0
+	MaxLLMTemperature = 2.0
+)
+
+// llmProvidersWithAPIKey lists the built-in providers that require an api_key
+var llmProvidersWithAPIKey = map[string]bool{"openai": true, "anthropic": true, "zai": true}
+
 // Validate validates the LLM configuration
 func (l *LLMConfig) Validate() error {
+	var errs ValidationErrors
 	if l.DefaultProvider == "" {
-		return fmt.Errorf("llm.default_provider is required")
+		errs.addf("llm.default_provider is required")
 	}
 	if len(l.Providers) == 0 {
-		return fmt.Errorf("at least one llm provider is required")
+		errs.addf("at least one llm provider is required")
+	} else if _, ok := l.Providers[l.DefaultProvider]; l.DefaultProvider != "" && !ok {
+		errs.addf("llm.default_provider '%s' not found in providers", l.DefaultProvider)
+	}
+
+	names := make([]string, 0, len(l.Providers))
+	for name := range l.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		provider := l.Providers[name]
+		errs.add(provider.validate(name))
+	}
+	if n := utf8.RuneCountInString(l.Instructions); n > MaxLLMInstructionsLength {
+		errs.addf("llm.instructions must be at most %d characters, got %d", MaxLLMInstructionsLength, n)
+	}
+	errs.add(l.Prompts.validate())
+	errs.add(l.HealthCheck.Validate())
+
+	seen := make(map[string]bool, len(l.Failover))
+	for _, name := range l.Failover {
+		switch _, ok := l.Providers[name]; {
+		case !ok:
+			errs.addf("llm.failover provider '%s' not found in providers", name)
+		case name == l.DefaultProvider:
+			errs.addf("llm.failover must not contain the default provider '%s'", name)
+		case seen[name]:
+			errs.addf("llm.failover contains '%s' twice", name)
+		}
+		seen[name] = true
+	}
+	if len(l.Failover) > 0 && !l.HealthCheck.Enabled {
+		errs.addf("llm.failover requires llm.health_check.enabled")
+	}
+	return errs.err()
+}
+
+// Validate validates the health check configuration
+func (c *LLMHealthCheckConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errs ValidationErrors
+	if c.IntervalSec <= 0 {
+		errs.addf("llm.health_check.interval_sec must be positive, got %d", c.IntervalSec)
+	}
+	if c.TimeoutSec <= 0 {
+		errs.addf("llm.health_check.timeout_sec must be positive, got %d", c.TimeoutSec)
+	}
+	if c.History <= 0 || c.History > MaxLLMHealthCheckHistory {
+		errs.addf("llm.health_check.history must be between 1 and %d, got %d", MaxLLMHealthCheckHistory, c.History)
+	}
+	if c.FailureThreshold <= 0 {
+		errs.addf("llm.health_check.failure_threshold must be positive, got %d", c.FailureThreshold)
+	}
+	return errs.err()
+}
+
+// validate validates a provider configured under llm.providers.<name>
+func (p *LLMProvider) validate(name string) error {
+	var errs ValidationErrors
+	field := "llm.providers." + name
+	if p.Model == "" {
+		errs.addf("%s.model is required", field)
+	}
+	if p.APIKey == "" && llmProvidersWithAPIKey[name] {
+		errs.addf("%s.api_key is required", field)
+	}
+	if p.BaseURL == "" && name == "ollama" {
+		errs.addf("%s.base_url is required", field)
+	}
+	if p.BaseURL != "" {
+		if u, err := url.Parse(p.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.addf("%s.base_url must be an http or https URL, got %q", field, p.BaseURL)
+		}
+	}
+	if p.Temperature < MinLLMTemperature || p.Temperature > MaxLLMTemperature {
+		errs.addf("%s.temperature must be between %g and %g, got %g", field, MinLLMTemperature, MaxLLMTemperature, p.Temperature)
+	}
+	if p.MaxTokens < 0 {
+		errs.addf("%s.max_tokens must be non-negative, got %d", field, p.MaxTokens)
 	}
-	if _, ok := l.Providers[l.DefaultProvider]; !ok {
-		return fmt.Errorf("llm.default_provider '%s' not found in providers", l.DefaultProvider)
+	if p.ContextTokens < 0 {
+		errs.addf("%s.context_tokens must be non-negative, got %d", field, p.ContextTokens)
+	} else if p.ContextTokens > 0 && p.ContextTokens <= p.MaxTokens {
+		errs.addf("%s.context_tokens must be greater than max_tokens, got %d", field, p.ContextTokens)
 	}
-	return nil
+	return errs.err()
 }
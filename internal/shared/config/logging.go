@@ -1,7 +1,9 @@
 package config
 
 import (
-	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 const (
@@ -13,21 +15,199 @@ const (
 type LoggingConfig struct {
 	Level  string `json:"level" yaml:"level"`
 	Format string `json:"format" yaml:"format"`
+
+	// Modules sets the levels of the loggers of subsystems, e.g. "router", "telegram", "llm"
+	// or "db", independently of Level; they can be changed at runtime with PUT /admin/logging
+	Modules map[string]string `json:"modules" yaml:"modules"`
+
+	// Output configures where logs are written
+	Output LogOutputConfig `json:"output" yaml:"output"`
+
+	// Database configures persisting logs to the logs table, as returned by GET /logs
+	Database DatabaseLoggingConfig `json:"database" yaml:"database"`
+
+	// Masking adds secrets masked in all log outputs and in the audit log
+	Masking LogMaskingConfig `json:"masking" yaml:"masking"`
+}
+
+// LogMaskingConfig represents secrets masked in addition to the built-in ones: values of
+// attributes named like api_key, token or password, and API keys, bot tokens and JWTs
+// anywhere in messages and values
+type LogMaskingConfig struct {
+	// Keys are further attribute names whose values are masked, matched case-insensitively
+	// and ignoring "_" and "-"
+	Keys []string `json:"keys" yaml:"keys"`
+
+	// Patterns are regular expressions of further secrets masked in messages and string
+	// values; when a pattern has a capture group, only the group is masked
+	Patterns []string `json:"patterns" yaml:"patterns"`
+}
+
+// LogOutputConfig represents configuration of the log outputs. Console and file output can be
+// combined; both use logging.format.
+type LogOutputConfig struct {
+	// Console writes logs to stdout
+	Console bool `json:"console" yaml:"console"`
+
+	// File writes logs to a file with rotation
+	File LogFileConfig `json:"file" yaml:"file"`
+}
+
+// LogFileConfig represents configuration of a rotated log file.
+// Rotated files are renamed with the time of the rotation, e.g. nexflow-2026-01-02T15-04-05.000.log.
+type LogFileConfig struct {
+	// Enabled enables or disables the log file
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Path is the path of the log file; its directory is created if needed
+	Path string `json:"path" yaml:"path"`
+
+	// MaxSizeMB is the size in megabytes at which the file is rotated; 0 disables size-based rotation
+	MaxSizeMB int `json:"max_size_mb" yaml:"max_size_mb"`
+
+	// RotateIntervalHours rotates the file every number of hours, at multiples of the interval
+	// in UTC (24 rotates it at midnight UTC); 0 disables time-based rotation
+	RotateIntervalHours int `json:"rotate_interval_hours" yaml:"rotate_interval_hours"`
+
+	// MaxBackups is the number of rotated files kept; 0 keeps all
+	MaxBackups int `json:"max_backups" yaml:"max_backups"`
+
+	// MaxAgeDays is the number of days rotated files are kept; 0 keeps them regardless of age
+	MaxAgeDays int `json:"max_age_days" yaml:"max_age_days"`
+
+	// Compress compresses rotated files with gzip
+	Compress bool `json:"compress" yaml:"compress"`
+}
+
+// DatabaseLoggingConfig represents configuration for persisting logs to the database.
+// Records are queued and written in batches in the background; when the queue is full they
+// are dropped instead of slowing down the application.
+type DatabaseLoggingConfig struct {
+	// Enabled enables or disables persisting logs
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Level is the lowest level of persisted logs; logs below the level of their logger are
+	// not persisted either
+	Level string `json:"level" yaml:"level"`
+
+	// SampleRatio is the share of debug and info logs that are persisted, from 0 to 1;
+	// warnings and errors are always persisted
+	SampleRatio float64 `json:"sample_ratio" yaml:"sample_ratio"`
+
+	// BatchSize is the number of logs written together
+	BatchSize int `json:"batch_size" yaml:"batch_size"`
+
+	// QueueSize is the number of logs waiting to be written; further logs are dropped
+	QueueSize int `json:"queue_size" yaml:"queue_size"`
+
+	// FlushIntervalMs is the longest time in milliseconds a log waits to be written
+	FlushIntervalMs int `json:"flush_interval_ms" yaml:"flush_interval_ms"`
+}
+
+// DefaultLoggingConfig returns default logging configuration: info level in JSON to stdout,
+// with info logs and above persisted to the database
+func DefaultLoggingConfig() LoggingConfig {
+	return LoggingConfig{
+		Level:  "info",
+		Format: "json",
+		Output: LogOutputConfig{
+			Console: true,
+			File: LogFileConfig{
+				Path:       "./logs/nexflow.log",
+				MaxSizeMB:  100,
+				MaxBackups: 10,
+				MaxAgeDays: 30,
+				Compress:   true,
+			},
+		},
+		Database: DatabaseLoggingConfig{
+			Enabled:         true,
+			Level:           "info",
+			SampleRatio:     1,
+			BatchSize:       100,
+			QueueSize:       10000,
+			FlushIntervalMs: 1000,
+		},
+	}
 }
 
 // Validate validates the logging configuration
 func (l *LoggingConfig) Validate() error {
+	var errs ValidationErrors
 	validLevels := map[string]bool{
 		"debug": true, "info": true, "warn": true, "error": true, "fatal": true,
 	}
 	if !validLevels[l.Level] {
-		return fmt.Errorf("logging.level must be one of: %s", ValidLevels)
+		errs.addf("logging.level must be one of: %s", ValidLevels)
 	}
 	validFormats := map[string]bool{
 		"json": true, "text": true,
 	}
 	if !validFormats[l.Format] {
-		return fmt.Errorf("logging.format must be one of: %s", ValidFormats)
+		errs.addf("logging.format must be one of: %s", ValidFormats)
+	}
+
+	modules := make([]string, 0, len(l.Modules))
+	for module := range l.Modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		if !validLevels[l.Modules[module]] {
+			errs.addf("logging.modules.%s must be one of: %s", module, ValidLevels)
+		}
+	}
+
+	if file := l.Output.File; file.Enabled {
+		if file.Path == "" {
+			errs.addf("logging.output.file path is required when the log file is enabled")
+		}
+		for _, field := range []struct {
+			name  string
+			value int
+		}{
+			{"max_size_mb", file.MaxSizeMB},
+			{"rotate_interval_hours", file.RotateIntervalHours},
+			{"max_backups", file.MaxBackups},
+			{"max_age_days", file.MaxAgeDays},
+		} {
+			if field.value < 0 {
+				errs.addf("logging.output.file %s must not be negative, got %d", field.name, field.value)
+			}
+		}
+	}
+
+	if db := l.Database; db.Enabled {
+		if !validLevels[db.Level] {
+			errs.addf("logging.database level must be one of: %s", ValidLevels)
+		}
+		if db.SampleRatio < 0 || db.SampleRatio > 1 {
+			errs.addf("logging.database sample_ratio must be between 0 and 1, got %g", db.SampleRatio)
+		}
+		if db.BatchSize <= 0 {
+			errs.addf("logging.database batch_size must be positive, got %d", db.BatchSize)
+		}
+		if db.QueueSize <= 0 {
+			errs.addf("logging.database queue_size must be positive, got %d", db.QueueSize)
+		} else if db.BatchSize > db.QueueSize {
+			errs.addf("logging.database batch_size (%d) must not exceed queue_size (%d)", db.BatchSize, db.QueueSize)
+		}
+		if db.FlushIntervalMs <= 0 {
+			errs.addf("logging.database flush_interval_ms must be positive, got %d", db.FlushIntervalMs)
+		}
+	}
+
+	for i, key := range l.Masking.Keys {
+		if strings.TrimSpace(key) == "" {
+			errs.addf("logging.masking.keys[%d] must not be empty", i)
+		}
+	}
+	for i, pattern := range l.Masking.Patterns {
+		if pattern == "" {
+			errs.addf("logging.masking.patterns[%d] must not be empty", i)
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			errs.addf("logging.masking.patterns[%d] is not a valid regular expression: %v", i, err)
+		}
 	}
-	return nil
+	return errs.err()
 }
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+)
+
+// MaxMessageJobWorkers limits the number of asynchronous messages answered at the same time
+const MaxMessageJobWorkers = 64
+
+// MessageJobsConfig represents the configuration of the asynchronous message API, whose
+// messages are answered in the background while clients poll their job
+type MessageJobsConfig struct {
+	// Workers is the number of messages answered at the same time
+	Workers int `yaml:"workers"`
+
+	// QueueSize is the number of messages waiting for a worker; further messages are rejected
+	QueueSize int `yaml:"queue_size"`
+
+	// MaxBatchSize is the maximum number of prompts of a batch; a batch is queued as a whole
+	MaxBatchSize int `yaml:"max_batch_size"`
+
+	// TTLHours is how long finished jobs can be polled before they are deleted
+	TTLHours int `yaml:"ttl_hours"`
+}
+
+// Validate validates the message jobs configuration
+func (c *MessageJobsConfig) Validate() error {
+	if c.Workers <= 0 {
+		return fmt.Errorf("message_jobs.workers must be positive, got %d", c.Workers)
+	}
+	if c.Workers > MaxMessageJobWorkers {
+		return fmt.Errorf("message_jobs.workers too large, got %d (max %d)", c.Workers, MaxMessageJobWorkers)
+	}
+	if c.QueueSize <= 0 {
+		return fmt.Errorf("message_jobs.queue_size must be positive, got %d", c.QueueSize)
+	}
+	if c.MaxBatchSize <= 0 || c.MaxBatchSize > c.QueueSize {
+		return fmt.Errorf("message_jobs.max_batch_size must be between 1 and queue_size (%d), got %d", c.QueueSize, c.MaxBatchSize)
+	}
+	if c.TTLHours <= 0 {
+		return fmt.Errorf("message_jobs.ttl_hours must be positive, got %d", c.TTLHours)
+	}
+	return nil
+}
+
+// DefaultMessageJobsConfig returns default message jobs configuration
+func DefaultMessageJobsConfig() MessageJobsConfig {
+	return MessageJobsConfig{
+		Workers:      4,
+		QueueSize:    100,
+		MaxBatchSize: 100,
+		TTLHours:     24,
+	}
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Metrics exporters
+const (
+	// MetricsExporterOTLP pushes metrics over OTLP/HTTP, e.g. to an OpenTelemetry collector
+	MetricsExporterOTLP = "otlp"
+	// MetricsExporterStatsD sends metrics to a StatsD or DogStatsD server over UDP
+	MetricsExporterStatsD = "statsd"
+)
+
+// ObservabilityConfig represents configuration for exporting telemetry
+type ObservabilityConfig struct {
+	// ServiceName identifies the service in the tracing and metrics backends
+	ServiceName string `yaml:"service_name"`
+
+	// Tracing configures distributed tracing
+	Tracing TracingConfig `yaml:"tracing"`
+
+	// Metrics configures pushing metrics to a monitoring backend
+	Metrics MetricsExportConfig `yaml:"metrics"`
+
+	// Crashes configures reporting of panics recovered in the router, connectors, skills and
+	// HTTP handlers
+	Crashes CrashReportingConfig `yaml:"crashes"`
+}
+
+// TracingConfig represents configuration for OpenTelemetry tracing.
+// A span is recorded for every incoming message and follows it through the router, the
+// orchestrator, LLM providers, skills and database calls.
+type TracingConfig struct {
+	// Enabled enables or disables tracing
+	Enabled bool `yaml:"enabled"`
+
+	// Endpoint is the base URL of the OTLP/HTTP receiver, e.g. an OpenTelemetry collector
+	Endpoint string `yaml:"endpoint"`
+
+	// Headers are added to every export request, e.g. the API key of a hosted backend
+	Headers map[string]string `yaml:"headers" secret:"true"`
+
+	// SampleRatio is the share of new traces that are recorded, from 0 to 1
+	SampleRatio float64 `yaml:"sample_ratio"`
+
+	// BatchSize is the number of spans exported together
+	BatchSize int `yaml:"batch_size"`
+
+	// QueueSize is the number of finished spans waiting for export; further spans are dropped
+	QueueSize int `yaml:"queue_size"`
+
+	// FlushIntervalMs is the longest time in milliseconds a finished span waits for export
+	FlushIntervalMs int `yaml:"flush_interval_ms"`
+
+	// TimeoutSec limits a single export request in seconds
+	TimeoutSec int `yaml:"timeout_sec"`
+}
+
+// MetricsExportConfig represents configuration for pushing metrics.
+// The metrics served on /metrics are exported in an interval, e.g. for Grafana Cloud or Datadog,
+// in addition to being available for scraping.
+type MetricsExportConfig struct {
+	// Enabled enables or disables pushing metrics
+	Enabled bool `yaml:"enabled"`
+
+	// Exporter is the protocol metrics are pushed with: "otlp" (default) or "statsd"
+	Exporter string `yaml:"exporter"`
+
+	// IntervalSec is the time between two exports in seconds
+	IntervalSec int `yaml:"interval_sec"`
+
+	// OTLP configures the "otlp" exporter
+	OTLP OTLPMetricsConfig `yaml:"otlp"`
+
+	// StatsD configures the "statsd" exporter
+	StatsD StatsDConfig `yaml:"statsd"`
+}
+
+// OTLPMetricsConfig represents configuration for exporting metrics over OTLP/HTTP
+type OTLPMetricsConfig struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver
+	Endpoint string `yaml:"endpoint"`
+
+	// Headers are added to every export request, e.g. the credentials of Grafana Cloud
+	Headers map[string]string `yaml:"headers" secret:"true"`
+
+	// TimeoutSec limits a single export request in seconds
+	TimeoutSec int `yaml:"timeout_sec"`
+}
+
+// StatsDConfig represents configuration for exporting metrics to StatsD
+type StatsDConfig struct {
+	// Address is the host:port of the StatsD server
+	Address string `yaml:"address"`
+
+	// Prefix is prepended to the metric names
+	Prefix string `yaml:"prefix"`
+
+	// Tags sends the labels of metrics as DogStatsD tags; without, label values are appended
+	// to the metric names
+	Tags bool `yaml:"tags"`
+}
+
+// CrashReportingConfig represents configuration for reporting recovered panics.
+// Recovered panics are always logged with their stack trace and counted in the
+// crash_panics_recovered_total metric.
+type CrashReportingConfig struct {
+	// Store saves recovered panics in the crash_reports table, served on /admin/crashes
+	Store bool `yaml:"store"`
+
+	// Sentry configures sending recovered panics to Sentry
+	Sentry SentryConfig `yaml:"sentry"`
+}
+
+// SentryConfig represents configuration for sending recovered panics to Sentry, or a service
+// accepting Sentry events such as GlitchTip
+type SentryConfig struct {
+	// Enabled enables or disables sending events
+	Enabled bool `yaml:"enabled"`
+
+	// DSN is the client key URL of the Sentry project, e.g. https://<key>@o0.ingest.sentry.io/<project>
+	DSN string `yaml:"dsn" secret:"true"`
+
+	// Environment is the environment of the events, e.g. "production"
+	Environment string `yaml:"environment"`
+
+	// TimeoutSec limits sending a single event in seconds
+	TimeoutSec int `yaml:"timeout_sec"`
+}
+
+// Validate validates the observability configuration
+func (c *ObservabilityConfig) Validate() error {
+	var errs ValidationErrors
+
+	if c.ServiceName == "" && (c.Tracing.Enabled || c.Metrics.Enabled) {
+		errs.addf("observability service_name is required when tracing or metrics are enabled")
+	}
+	if c.Tracing.Enabled {
+		errs.add(c.Tracing.validate())
+	}
+	if c.Metrics.Enabled {
+		errs.add(c.Metrics.validate())
+	}
+	if c.Crashes.Sentry.Enabled {
+		errs.add(c.Crashes.Sentry.validate())
+	}
+
+	return errs.err()
+}
+
+// validate validates the tracing configuration
+func (t *TracingConfig) validate() error {
+	var errs ValidationErrors
+
+	u, err := url.Parse(t.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.addf("observability.tracing endpoint must be an http or https URL, got %q", t.Endpoint)
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		errs.addf("observability.tracing sample_ratio must be between 0 and 1, got %g", t.SampleRatio)
+	}
+	if t.BatchSize <= 0 {
+		errs.addf("observability.tracing batch_size must be positive, got %d", t.BatchSize)
+	}
+	if t.QueueSize <= 0 {
+		errs.addf("observability.tracing queue_size must be positive, got %d", t.QueueSize)
+	} else if t.BatchSize > t.QueueSize {
+		errs.addf("observability.tracing batch_size (%d) must not exceed queue_size (%d)", t.BatchSize, t.QueueSize)
+	}
+	if t.FlushIntervalMs <= 0 {
+		errs.addf("observability.tracing flush_interval_ms must be positive, got %d", t.FlushIntervalMs)
+	}
+	if t.TimeoutSec <= 0 {
+		errs.addf("observability.tracing timeout_sec must be positive, got %d", t.TimeoutSec)
+	}
+
+	return errs.err()
+}
+
+// validate validates the metrics export configuration
+func (m *MetricsExportConfig) validate() error {
+	var errs ValidationErrors
+
+	if m.IntervalSec <= 0 {
+		errs.addf("observability.metrics interval_sec must be positive, got %d", m.IntervalSec)
+	}
+
+	switch m.Exporter {
+	case MetricsExporterOTLP:
+		u, err := url.Parse(m.OTLP.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.addf("observability.metrics.otlp endpoint must be an http or https URL, got %q", m.OTLP.Endpoint)
+		}
+		if m.OTLP.TimeoutSec <= 0 {
+			errs.addf("observability.metrics.otlp timeout_sec must be positive, got %d", m.OTLP.TimeoutSec)
+		}
+	case MetricsExporterStatsD:
+		if _, port, err := net.SplitHostPort(m.StatsD.Address); err != nil || port == "" {
+			errs.addf("observability.metrics.statsd address must be host:port, got %q", m.StatsD.Address)
+		}
+	default:
+		errs.addf("observability.metrics exporter must be %s or %s, got %q", MetricsExporterOTLP, MetricsExporterStatsD, m.Exporter)
+	}
+
+	return errs.err()
+}
+
+// validate validates the Sentry configuration
+func (s *SentryConfig) validate() error {
+	var errs ValidationErrors
+
+	u, err := url.Parse(s.DSN)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User == nil || strings.Trim(u.Path, "/") == "" {
+		errs.addf("observability.crashes.sentry dsn must be an http or https URL with a key and a project ID")
+	}
+	if s.TimeoutSec <= 0 {
+		errs.addf("observability.crashes.sentry timeout_sec must be positive, got %d", s.TimeoutSec)
+	}
+
+	return errs.err()
+}
+
+// DefaultObservabilityConfig returns default observability configuration.
+// Tracing, metrics export and Sentry are disabled; when enabled, all traces and metrics are sent
+// to a local OpenTelemetry collector. Recovered panics are stored in the crash_reports table.
+func DefaultObservabilityConfig() ObservabilityConfig {
+	return ObservabilityConfig{
+		ServiceName: "nexflow",
+		Tracing: TracingConfig{
+			Endpoint:        "http://localhost:4318",
+			SampleRatio:     1,
+			BatchSize:       512,
+			QueueSize:       2048,
+			FlushIntervalMs: 5000,
+			TimeoutSec:      10,
+		},
+		Metrics: MetricsExportConfig{
+			Exporter:    MetricsExporterOTLP,
+			IntervalSec: 30,
+			OTLP: OTLPMetricsConfig{
+				Endpoint:   "http://localhost:4318",
+				TimeoutSec: 10,
+			},
+			StatsD: StatsDConfig{
+				Address: "127.0.0.1:8125",
+				Prefix:  "nexflow.",
+				Tags:    true,
+			},
+		},
+		Crashes: CrashReportingConfig{
+			Store: true,
+			Sentry: SentryConfig{
+				TimeoutSec: 5,
+			},
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import "fmt"
+
+// OrchestratorConfig represents configuration of the orchestrator answering chat messages
+type OrchestratorConfig struct {
+	// Planning breaks complex messages down into steps
+	Planning PlanningConfig `yaml:"planning"`
+
+	// Tools lets the LLM call skills while it answers messages that are not planned
+	Tools ToolsConfig `yaml:"tools"`
+}
+
+// PlanningConfig represents configuration of multi-step planning. A planned message is broken
+// down by the LLM into steps, each executed as a task with a progress message to the user,
+// and answered from the results of the steps.
+type PlanningConfig struct {
+	// Enabled enables or disables planning
+	Enabled bool `yaml:"enabled"`
+
+	// MaxSteps is the maximum number of steps of a plan
+	MaxSteps int `yaml:"max_steps"`
+
+	// MinLength is the length in characters from which messages are planned; shorter
+	// messages are answered with a single LLM call. 0 plans every message.
+	MinLength int `yaml:"min_length"`
+
+	// MaxParallelSkills is the number of consecutive skill steps of a plan run at the same time
+	MaxParallelSkills int `yaml:"max_parallel_skills"`
+
+	// SkillTimeoutSec is the combined timeout in seconds of the skill steps run together; 0 for none
+	SkillTimeoutSec int `yaml:"skill_timeout_sec"`
+}
+
+// ToolsConfig represents configuration of the agent loop. The enabled skills are offered to
+// the LLM as tools, the tool calls of a reply run together as skill executions and their
+// results are sent back to the LLM, until it answers.
+type ToolsConfig struct {
+	// Enabled enables or disables tool calls
+	Enabled bool `yaml:"enabled"`
+
+	// MaxRounds is the number of LLM replies whose tool calls are run; the next reply must answer
+	MaxRounds int `yaml:"max_rounds"`
+
+	// MaxParallel is the number of tool calls of a reply run at the same time
+	MaxParallel int `yaml:"max_parallel"`
+
+	// TimeoutSec is the combined timeout in seconds of the tool calls of a reply; 0 for none
+	TimeoutSec int `yaml:"timeout_sec"`
+}
+
+// MaxPlanSteps is the upper bound of orchestrator.planning.max_steps
+const MaxPlanSteps = 20
+
+// MaxToolRounds is the upper bound of orchestrator.tools.max_rounds and max_parallel
+const MaxToolRounds = 20
+
+// Validate validates the orchestrator configuration
+func (c *OrchestratorConfig) Validate() error {
+	if err := c.validatePlanning(); err != nil {
+		return err
+	}
+
+	tools := c.Tools
+	if !tools.Enabled {
+		return nil
+	}
+
+	if tools.MaxRounds < 1 || tools.MaxRounds > MaxToolRounds {
+		return fmt.Errorf("orchestrator.tools.max_rounds must be between 1 and %d, got %d", MaxToolRounds, tools.MaxRounds)
+	}
+
+	if tools.MaxParallel < 1 || tools.MaxParallel > MaxToolRounds {
+		return fmt.Errorf("orchestrator.tools.max_parallel must be between 1 and %d, got %d", MaxToolRounds, tools.MaxParallel)
+	}
+
+	if tools.TimeoutSec < 0 {
+		return fmt.Errorf("orchestrator.tools.timeout_sec must be non-negative, got %d", tools.TimeoutSec)
+	}
+
+	return nil
+}
+
+// validatePlanning validates the planning configuration
+func (c *OrchestratorConfig) validatePlanning() error {
+	planning := c.Planning
+	if !planning.Enabled {
+		return nil
+	}
+
+	if planning.MaxSteps < 2 || planning.MaxSteps > MaxPlanSteps {
+		return fmt.Errorf("orchestrator.planning.max_steps must be between 2 and %d, got %d", MaxPlanSteps, planning.MaxSteps)
+	}
+
+	if planning.MinLength < 0 {
+		return fmt.Errorf("orchestrator.planning.min_length must be non-negative, got %d", planning.MinLength)
+	}
+
+	if planning.MaxParallelSkills < 1 || planning.MaxParallelSkills > MaxPlanSteps {
+		return fmt.Errorf("orchestrator.planning.max_parallel_skills must be between 1 and %d, got %d", MaxPlanSteps, planning.MaxParallelSkills)
+	}
+
+	if planning.SkillTimeoutSec < 0 {
+		return fmt.Errorf("orchestrator.planning.skill_timeout_sec must be non-negative, got %d", planning.SkillTimeoutSec)
+	}
+
+	return nil
+}
+
+// DefaultOrchestratorConfig returns default orchestrator configuration.
+// Planning and tools are disabled.
+func DefaultOrchestratorConfig() OrchestratorConfig {
+	return OrchestratorConfig{
+		Planning: PlanningConfig{
+			MaxSteps:          5,
+			MinLength:         100,
+			MaxParallelSkills: 4,
+			SkillTimeoutSec:   120,
+		},
+		Tools: ToolsConfig{
+			MaxRounds:   5,
+			MaxParallel: 4,
+			TimeoutSec:  120,
+		},
+	}
+}
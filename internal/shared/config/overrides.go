@@ -0,0 +1,346 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvPrefix is the prefix of the environment variables that override configuration keys,
+	// e.g. NEXFLOW_SERVER_PORT for server.port
+	EnvPrefix = "NEXFLOW_"
+
+	// maskedSecret replaces the values of secret keys in the output of Masked
+	maskedSecret = "******"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Override sets a configuration key, e.g. from a command-line flag
+type Override struct {
+	// Key is the dotted path of the key, e.g. "server.port" or "llm.providers.openai.api_key"
+	Key string
+
+	// Value is the value in its text form. Lists are comma-separated.
+	Value string
+}
+
+// Set sets the configuration key with the dotted path key to value.
+// Map entries are created as needed; list items are addressed by index, and an index equal
+// to the length of the list appends an item. Lists of scalars take comma-separated values.
+func (c *Config) Set(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("config key is required")
+	}
+	if err := setPath(reflect.ValueOf(c).Elem(), strings.Split(key, "."), value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// ApplyEnv sets the configuration keys named by NEXFLOW_* variables of environ, given in
+// the "NAME=value" form of os.Environ. The name is the upper-case key with "_" between the
+// parts, e.g. NEXFLOW_SERVER_PORT or NEXFLOW_LLM_PROVIDERS_OPENAI_API_KEY; map keys are
+// lower-cased. Variables that name no key are ignored. Variables are applied sorted by name,
+// so NEXFLOW_AUTH_API_KEYS_0_KEY creates a list item before NEXFLOW_AUTH_API_KEYS_1_KEY.
+func (c *Config) ApplyEnv(environ []string) error {
+	environ = append([]string(nil), environ...)
+	sort.Strings(environ)
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		rest, ok := strings.CutPrefix(name, EnvPrefix)
+		if !ok || rest == "" {
+			continue
+		}
+		path, ok := resolveEnvPath(reflect.TypeOf(*c), strings.Split(rest, "_"))
+		if !ok {
+			continue
+		}
+		if err := c.Set(strings.Join(path, "."), value); err != nil {
+			return fmt.Errorf("environment variable %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Keys returns the dotted paths of the keys that can be set without a map key or list
+// index, sorted
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+// Masked returns a copy of the configuration with the values of secret keys (tokens, API
+// keys, JWT and webhook secrets, the PII encryption key) replaced by "******"
+func (c *Config) Masked() *Config {
+	masked := reflect.New(reflect.TypeOf(*c)).Elem()
+	copyMasked(masked, reflect.ValueOf(*c), false)
+	return masked.Addr().Interface().(*Config)
+}
+
+// setPath sets the value at path below v, which must be settable
+func setPath(v reflect.Value, path []string, value string) error {
+	if len(path) == 0 {
+		return setScalar(v, value)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		field, ok := fieldByKey(v.Type(), path[0])
+		if !ok {
+			return fmt.Errorf("unknown key %q", path[0])
+		}
+		return setPath(v.FieldByIndex(field.Index), path[1:], value)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setPath(elem, path[1:], value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	case reflect.Slice:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 || index > v.Len() {
+			return fmt.Errorf("list index must be between 0 and %d, got %q", v.Len(), path[0])
+		}
+		if index == v.Len() {
+			v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+		}
+		return setPath(v.Index(index), path[1:], value)
+	default:
+		return fmt.Errorf("%q is not a section", path[0])
+	}
+}
+
+// setScalar parses value into v
+func setScalar(v reflect.Value, value string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", value)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if !isScalar(v.Type().Elem()) {
+			return fmt.Errorf("a list of sections needs an index")
+		}
+		items := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setScalar(elem, item); err != nil {
+				return err
+			}
+			items = reflect.Append(items, elem)
+		}
+		v.Set(items)
+	default:
+		return fmt.Errorf("a section cannot be set to a value")
+	}
+	return nil
+}
+
+// resolveEnvPath maps the upper-case parts of an environment variable name to the path of
+// a key below t. The parts of map keys are joined with "_" and lower-cased.
+func resolveEnvPath(t reflect.Type, parts []string) ([]string, bool) {
+	if isLeaf(t) {
+		return nil, len(parts) == 0
+	}
+	if len(parts) == 0 {
+		return nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			name := yamlName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			nameParts := strings.Split(strings.ToUpper(name), "_")
+			if len(nameParts) > len(parts) || !equalParts(nameParts, parts[:len(nameParts)]) {
+				continue
+			}
+			if rest, ok := resolveEnvPath(t.Field(i).Type, parts[len(nameParts):]); ok {
+				return append([]string{name}, rest...), true
+			}
+		}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, false
+		}
+		for n := 1; n <= len(parts); n++ {
+			if rest, ok := resolveEnvPath(t.Elem(), parts[n:]); ok {
+				key := strings.ToLower(strings.Join(parts[:n], "_"))
+				return append([]string{key}, rest...), true
+			}
+		}
+	case reflect.Slice:
+		if rest, ok := resolveEnvPath(t.Elem(), parts[1:]); ok {
+			if _, err := strconv.Atoi(parts[0]); err == nil {
+				return append([]string{parts[0]}, rest...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// collectKeys appends the paths of the leaf keys below t that are not inside a map or a
+// list of sections
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+		key := prefix + name
+		switch {
+		case isLeaf(field.Type):
+			*keys = append(*keys, key)
+		case field.Type.Kind() == reflect.Struct:
+			collectKeys(field.Type, key+".", keys)
+		}
+	}
+}
+
+// copyMasked deep-copies src into dst, which must be settable, replacing non-empty secret
+// strings if mask is set or the field is tagged secret:"true"
+func copyMasked(dst, src reflect.Value, mask bool) {
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if !dst.Field(i).CanSet() {
+				continue
+			}
+			copyMasked(dst.Field(i), src.Field(i), src.Type().Field(i).Tag.Get("secret") == "true")
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		for _, key := range src.MapKeys() {
+			elem := reflect.New(src.Type().Elem()).Elem()
+			copyMasked(elem, src.MapIndex(key), mask)
+			dst.SetMapIndex(key, elem)
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			copyMasked(dst.Index(i), src.Index(i), mask)
+		}
+	case reflect.String:
+		if mask && src.String() != "" {
+			dst.SetString(maskedSecret)
+		} else {
+			dst.SetString(src.String())
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// fieldByKey returns the struct field with the YAML name key
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlName(t.Field(i)) == key {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// yamlName returns the YAML name of an exported struct field, or "" if it has none
+func yamlName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// isLeaf reports whether values of t are set from a single text value
+func isLeaf(t reflect.Type) bool {
+	return isScalar(t) || (t.Kind() == reflect.Slice && isScalar(t.Elem()))
+}
+
+// isScalar reports whether t is a string, boolean, number or duration
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// equalParts reports whether two lists of name parts are equal
+func equalParts(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
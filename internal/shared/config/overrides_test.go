@@ -0,0 +1,316 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/secrets"
+)
+
+func TestConfig_Set(t *testing.T) {
+	config := defaultConfig()
+
+	sets := []Override{
+		{Key: "server.port", Value: "9090"},
+		{Key: "database.busy_timeout", Value: "3s"},
+		{Key: "server.cors.allowed_origins", Value: "https://a.example.com, https://b.example.com"},
+		{Key: "llm.providers.openai.api_key", Value: "sk-test"},
+		{Key: "llm.providers.openai.temperature", Value: "0.5"},
+		{Key: "auth.api_keys.0.name", Value: "ci"},
+		{Key: "auth.api_keys.0.key", Value: "secret"},
+		{Key: "router.commands.admins.telegram", Value: "1,2"},
+		{Key: "eventbus.enabled", Value: "true"},
+	}
+	for _, s := range sets {
+		if err := config.Set(s.Key, s.Value); err != nil {
+			t.Fatalf("Set(%q) failed: %v", s.Key, err)
+		}
+	}
+
+	if config.Server.Port != 9090 {
+		t.Errorf("Expected port 9090, got %d", config.Server.Port)
+	}
+	if config.Database.BusyTimeout != 3*time.Second {
+		t.Errorf("Expected busy_timeout 3s, got %s", config.Database.BusyTimeout)
+	}
+	if want := []string{"https://a.example.com", "https://b.example.com"}; !reflect.DeepEqual(config.Server.CORS.AllowedOrigins, want) {
+		t.Errorf("Expected allowed_origins %v, got %v", want, config.Server.CORS.AllowedOrigins)
+	}
+	if provider := config.LLM.Providers["openai"]; provider.APIKey != "sk-test" || provider.Temperature != 0.5 {
+		t.Errorf("Expected the openai provider to be set, got %+v", provider)
+	}
+	if want := []APIKeyConfig{{Name: "ci", Key: "secret"}}; !reflect.DeepEqual(config.Auth.APIKeys, want) {
+		t.Errorf("Expected api_keys %v, got %v", want, config.Auth.APIKeys)
+	}
+	if want := []string{"1", "2"}; !reflect.DeepEqual(config.Router.Commands.Admins["telegram"], want) {
+		t.Errorf("Expected telegram admins %v, got %v", want, config.Router.Commands.Admins["telegram"])
+	}
+	if !config.EventBus.Enabled {
+		t.Error("Expected eventbus to be enabled")
+	}
+
+	invalid := []Override{
+		{Key: "server.port", Value: "http"},
+		{Key: "server.unknown", Value: "1"},
+		{Key: "server", Value: "1"},
+		{Key: "server.port.x", Value: "1"},
+		{Key: "auth.api_keys.5.name", Value: "ci"},
+		{Key: "auth.api_keys", Value: "ci"},
+		{Key: "database.busy_timeout", Value: "soon"},
+		{Key: "", Value: "1"},
+	}
+	for _, s := range invalid {
+		if err := config.Set(s.Key, s.Value); err == nil {
+			t.Errorf("Expected Set(%q, %q) to fail", s.Key, s.Value)
+		}
+	}
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	config := defaultConfig()
+
+	err := config.ApplyEnv([]string{
+		"NEXFLOW_SERVER_PORT=9090",
+		"NEXFLOW_DATABASE_SLOW_QUERY_THRESHOLD=250ms",
+		"NEXFLOW_LLM_DEFAULT_PROVIDER=zai",
+		"NEXFLOW_LLM_PROVIDERS_ZAI_API_KEY=key",
+		"NEXFLOW_LLM_PROVIDERS_ZAI_MAX_TOKENS=2048",
+		"NEXFLOW_CHANNELS_TELEGRAM_BOT_TOKEN=token",
+		"NEXFLOW_CHANNELS_TELEGRAM_BOTS_0_NAME=support",
+		"NEXFLOW_PII_WORKSPACES_SUPPORT_EU=mask",
+		"NEXFLOW_API_KEY=client-only",
+		"HOME=/root",
+	})
+	if err != nil {
+		t.Fatalf("ApplyEnv failed: %v", err)
+	}
+
+	if config.Server.Port != 9090 {
+		t.Errorf("Expected port 9090, got %d", config.Server.Port)
+	}
+	if config.Database.SlowQueryThreshold != 250*time.Millisecond {
+		t.Errorf("Expected slow_query_threshold 250ms, got %s", config.Database.SlowQueryThreshold)
+	}
+	if config.LLM.DefaultProvider != "zai" {
+		t.Errorf("Expected default_provider zai, got %s", config.LLM.DefaultProvider)
+	}
+	if provider := config.LLM.Providers["zai"]; provider.APIKey != "key" || provider.MaxTokens != 2048 {
+		t.Errorf("Expected the zai provider to be set, got %+v", provider)
+	}
+	if config.Channels.Telegram.BotToken != "token" {
+		t.Errorf("Expected telegram bot_token to be set, got %q", config.Channels.Telegram.BotToken)
+	}
+	if len(config.Channels.TelegramBots) != 1 || config.Channels.TelegramBots[0].Name != "support" {
+		t.Errorf("Expected one telegram bot named support, got %+v", config.Channels.TelegramBots)
+	}
+	if config.PII.Workspaces["support_eu"] != "mask" {
+		t.Errorf("Expected pii workspace support_eu to be set, got %v", config.PII.Workspaces)
+	}
+
+	if err := config.ApplyEnv([]string{"NEXFLOW_SERVER_PORT=http"}); err == nil {
+		t.Error("Expected an invalid port to fail")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	for _, key := range []string{"server.port", "database.busy_timeout", "server.cors.allowed_origins", "auth.jwt.secret"} {
+		if !slices.Contains(keys, key) {
+			t.Errorf("Expected keys to contain %s", key)
+		}
+	}
+	for _, key := range []string{"server", "llm.providers", "auth.api_keys"} {
+		if slices.Contains(keys, key) {
+			t.Errorf("Expected keys not to contain %s", key)
+		}
+	}
+	if !slices.IsSorted(keys) {
+		t.Error("Expected keys to be sorted")
+	}
+}
+
+func TestConfig_Masked(t *testing.T) {
+	config := defaultConfig()
+	config.Server.Port = 8080
+	config.LLM.Providers = map[string]LLMProvider{"openai": {APIKey: "sk-test", Model: "gpt-4"}}
+	config.Auth.APIKeys = []APIKeyConfig{{Name: "ci", Key: "secret"}}
+	config.Auth.JWT.Secret = "jwt-secret"
+	config.Channels.Telegram.BotToken = "token"
+	config.PII.EncryptionKey = ""
+
+	masked := config.Masked()
+
+	if masked.Server.Port != 8080 || masked.LLM.Providers["openai"].Model != "gpt-4" || masked.Auth.APIKeys[0].Name != "ci" {
+		t.Errorf("Expected other keys to be kept, got %+v", masked)
+	}
+	for name, value := range map[string]string{
+		"llm api_key":        masked.LLM.Providers["openai"].APIKey,
+		"auth api key":       masked.Auth.APIKeys[0].Key,
+		"jwt secret":         masked.Auth.JWT.Secret,
+		"telegram bot_token": masked.Channels.Telegram.BotToken,
+	} {
+		if value != maskedSecret {
+			t.Errorf("Expected %s to be masked, got %q", name, value)
+		}
+	}
+	if masked.PII.EncryptionKey != "" {
+		t.Errorf("Expected an empty secret to stay empty, got %q", masked.PII.EncryptionKey)
+	}
+
+	// The configuration itself is not modified
+	if config.LLM.Providers["openai"].APIKey != "sk-test" || config.Auth.APIKeys[0].Key != "secret" {
+		t.Error("Expected Masked not to modify the configuration")
+	}
+}
+
+func TestLoadWithOverrides(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	yamlContent := `server:
+  host: "127.0.0.1"
+  port: 8080
+database:
+  type: "sqlite"
+  path: "./data/nexflow.db"
+llm:
+  default_provider: "openai"
+  providers:
+    openai:
+      api_key: "sk-file"
+      model: "gpt-4"
+skills:
+  directory: "./skills"
+  timeout_sec: 30
+logging:
+  level: "info"
+  format: "json"
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	t.Setenv("NEXFLOW_SERVER_PORT", "9090")
+	t.Setenv("NEXFLOW_SERVER_HOST", "0.0.0.0")
+	t.Setenv("NEXFLOW_LLM_PROVIDERS_OPENAI_API_KEY", "sk-env")
+
+	// The file is overridden by the environment, which is overridden by the overrides
+	config, err := LoadWithOverrides(configPath, []Override{{Key: "server.port", Value: "7070"}})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Server.Port != 7070 {
+		t.Errorf("Expected port 7070 from the overrides, got %d", config.Server.Port)
+	}
+	if config.Server.Host != "0.0.0.0" {
+		t.Errorf("Expected host 0.0.0.0 from the environment, got %s", config.Server.Host)
+	}
+	if config.LLM.Providers["openai"].APIKey != "sk-env" {
+		t.Errorf("Expected the api_key from the environment, got %s", config.LLM.Providers["openai"].APIKey)
+	}
+
+	// Without a file the configuration comes from the environment alone
+	t.Setenv("NEXFLOW_DATABASE_TYPE", "sqlite")
+	t.Setenv("NEXFLOW_DATABASE_PATH", "/data/nexflow.db")
+	t.Setenv("NEXFLOW_LLM_DEFAULT_PROVIDER", "openai")
+	t.Setenv("NEXFLOW_LLM_PROVIDERS_OPENAI_MODEL", "gpt-4")
+	t.Setenv("NEXFLOW_SKILLS_DIRECTORY", "/skills")
+	t.Setenv("NEXFLOW_SKILLS_TIMEOUT_SEC", "30")
+	t.Setenv("NEXFLOW_LOGGING_LEVEL", "info")
+	t.Setenv("NEXFLOW_LOGGING_FORMAT", "json")
+	config, err = LoadWithOverrides("", nil)
+	if err != nil {
+		t.Fatalf("Failed to load config from the environment: %v", err)
+	}
+	if config.Database.Path != "/data/nexflow.db" || config.Server.Port != 9090 {
+		t.Errorf("Expected the configuration from the environment, got %+v", config)
+	}
+	if !reflect.DeepEqual(config.Router, DefaultRouterConfig()) {
+		t.Errorf("Expected default router config, got %+v", config.Router)
+	}
+
+	if _, err := LoadWithOverrides("", []Override{{Key: "server.port", Value: "-1"}}); err == nil {
+		t.Error("Expected an invalid override to fail validation")
+	}
+}
+
+// stubSecretProvider returns the secrets by reference
+type stubSecretProvider map[string]string
+
+func (p stubSecretProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	if value, ok := p[ref]; ok {
+		return value, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestLoad_SecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "telegram_token")
+	if err := os.WriteFile(tokenPath, []byte("123:abc\n"), 0600); err != nil {
+		t.Fatalf("Failed to write secret: %v", err)
+	}
+	vault := stubSecretProvider{"secret/data/nexflow#openai_key": "sk-vault"}
+	restore := newSecretResolver
+	newSecretResolver = func() *secrets.Resolver {
+		return secrets.NewResolver(map[string]secrets.Provider{
+			secrets.SchemeFile:  secrets.FileProvider{},
+			secrets.SchemeVault: vault,
+		})
+	}
+	t.Cleanup(func() { newSecretResolver = restore })
+
+	configPath := filepath.Join(dir, "config.yml")
+	yamlContent := `server:
+  host: "127.0.0.1"
+  port: 8080
+database:
+  type: "sqlite"
+  path: "./data/nexflow.db"
+llm:
+  default_provider: "openai"
+  providers:
+    openai:
+      api_key: "${vault:secret/data/nexflow#openai_key}"
+      model: "gpt-4"
+channels:
+  telegram:
+    bot_token: "${file:` + tokenPath + `}"
+skills:
+  directory: "./skills"
+  timeout_sec: 30
+logging:
+  level: "info"
+  format: "json"
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	// References are resolved in environment variables too
+	t.Setenv("NEXFLOW_CHANNELS_DISCORD_BOT_TOKEN", "${vault:secret/data/nexflow#openai_key}")
+
+	config, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.LLM.Providers["openai"].APIKey != "sk-vault" {
+		t.Errorf("Expected the api_key from vault, got %q", config.LLM.Providers["openai"].APIKey)
+	}
+	if config.Channels.Telegram.BotToken != "123:abc" {
+		t.Errorf("Expected the bot_token from the file, got %q", config.Channels.Telegram.BotToken)
+	}
+	if config.Channels.Discord.BotToken != "sk-vault" {
+		t.Errorf("Expected the discord bot_token from vault, got %q", config.Channels.Discord.BotToken)
+	}
+
+	// A reference that cannot be resolved fails the load
+	t.Setenv("NEXFLOW_CHANNELS_DISCORD_BOT_TOKEN", "${vault:secret/data/nexflow#missing}")
+	var resolveErr *secrets.ResolveError
+	if _, err := Load(configPath); !errors.As(err, &resolveErr) {
+		t.Errorf("Expected a ResolveError, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// PIIKeySize is the size of the decoded pii.encryption_key
+const PIIKeySize = 32
+
+// PIIConfig represents configuration for the redaction of personal data (e-mail addresses,
+// phone numbers and card numbers) in stored messages
+type PIIConfig struct {
+	// Mode is "off" (store as is), "mask" (replace with placeholders) or "encrypt"
+	// (store encrypted, decrypt when read) for workspaces without an override
+	Mode string `yaml:"mode"`
+
+	// EncryptionKey is the base64-encoded 32-byte AES key required by the encrypt mode
+	EncryptionKey string `yaml:"encryption_key" secret:"true"`
+
+	// Workspaces overrides the mode per workspace ID
+	Workspaces map[string]string `yaml:"workspaces"`
+}
+
+// Validate validates the PII configuration
+func (c *PIIConfig) Validate() error {
+	encrypts := c.Mode == "encrypt"
+	if !validPIIMode(c.Mode) {
+		return fmt.Errorf("pii.mode must be off, mask or encrypt, got %q", c.Mode)
+	}
+	for workspace, mode := range c.Workspaces {
+		if !validPIIMode(mode) {
+			return fmt.Errorf("pii.workspaces.%s must be off, mask or encrypt, got %q", workspace, mode)
+		}
+		encrypts = encrypts || mode == "encrypt"
+	}
+
+	key, err := c.Key()
+	if err != nil {
+		return err
+	}
+	if encrypts && key == nil {
+		return fmt.Errorf("pii.encryption_key is required by the encrypt mode")
+	}
+
+	return nil
+}
+
+// Key returns the decoded encryption key, or nil if none is configured
+func (c *PIIConfig) Key() ([]byte, error) {
+	if c.EncryptionKey == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(c.EncryptionKey)
+	if err != nil || len(key) != PIIKeySize {
+		return nil, fmt.Errorf("pii.encryption_key must be %d bytes encoded in base64", PIIKeySize)
+	}
+	return key, nil
+}
+
+// validPIIMode returns true if a mode is a known redaction mode
+func validPIIMode(mode string) bool {
+	return mode == "off" || mode == "mask" || mode == "encrypt"
+}
+
+// DefaultPIIConfig returns default PII configuration.
+// Messages are stored as they are.
+func DefaultPIIConfig() PIIConfig {
+	return PIIConfig{
+		Mode: "off",
+	}
+}
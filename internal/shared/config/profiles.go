@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnv selects the profile of the configuration, e.g. NEXFLOW_PROFILE=production
+const ProfileEnv = EnvPrefix + "PROFILE"
+
+// Directives of configuration files, which are resolved before the configuration is decoded
+const (
+	// includeKey lists files, relative to the including file, whose settings the file builds on
+	includeKey = "include"
+
+	// profilesKey maps profile names to settings layered on top of the file
+	profilesKey = "profiles"
+)
+
+// configLoader reads a configuration file with its includes and the sections of a profile
+type configLoader struct {
+	profile string
+
+	// stack holds the files being read, to detect include cycles
+	stack []string
+
+	// profileFound reports whether any of the files defines the profile
+	profileFound bool
+}
+
+// loadConfigFile reads the configuration file at path into a YAML mapping. The included
+// files come first, then the file itself and then its section of the profile, each layer
+// taking precedence over the ones before: mappings are merged key by key, while scalars and
+// lists are replaced.
+func loadConfigFile(path, profile string) (*yaml.Node, error) {
+	loader := &configLoader{profile: profile}
+	node, err := loader.load(path)
+	if err != nil {
+		return nil, err
+	}
+	if profile != "" && !loader.profileFound {
+		return nil, fmt.Errorf("config profile %q is not defined in %s", profile, path)
+	}
+	return node, nil
+}
+
+// load reads a file and resolves its directives
+func (l *configLoader) load(path string) (*yaml.Node, error) {
+	path = filepath.Clean(path)
+	for i, p := range l.stack {
+		if p == path {
+			return nil, fmt.Errorf("config include cycle: %s", strings.Join(append(l.stack[i:], path), " -> "))
+		}
+	}
+	l.stack = append(l.stack, path)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	if ext := getFileExtension(path); ext != ".yaml" && ext != ".yml" {
+		return nil, errUnsupportedFormat(ext)
+	}
+	data, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(doc.Content) > 0 && !isNullNode(doc.Content[0]) {
+		root = doc.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file %s must contain a mapping", path)
+	}
+
+	profiles := takeKey(root, profilesKey)
+	merged, err := l.resolveIncludes(root, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if profiles == nil || l.profile == "" {
+		return merged, nil
+	}
+	if profiles.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: %s must map profile names to settings", path, profilesKey)
+	}
+	section := lookupKey(profiles, l.profile)
+	if section == nil {
+		return merged, nil
+	}
+	l.profileFound = true
+	if isNullNode(section) {
+		return merged, nil
+	}
+	if section.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: profile %q must contain a mapping", path, l.profile)
+	}
+	if lookupKey(section, profilesKey) != nil {
+		return nil, fmt.Errorf("%s: profile %q must not define %s", path, l.profile, profilesKey)
+	}
+	section, err = l.resolveIncludes(section, path)
+	if err != nil {
+		return nil, err
+	}
+	mergeNodes(merged, section)
+	return merged, nil
+}
+
+// resolveIncludes returns the settings of node layered on top of the files it includes
+func (l *configLoader) resolveIncludes(node *yaml.Node, path string) (*yaml.Node, error) {
+	includes, err := includePaths(takeKey(node, includeKey), path)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, include := range includes {
+		included, err := l.load(include)
+		if err != nil {
+			return nil, err
+		}
+		mergeNodes(merged, included)
+	}
+	mergeNodes(merged, node)
+	return merged, nil
+}
+
+// includePaths returns the files an include directive of the file at path names: a single
+// path or a list, relative to the directory of the file
+func includePaths(node *yaml.Node, path string) ([]string, error) {
+	if node == nil || isNullNode(node) {
+		return nil, nil
+	}
+
+	var names []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		names = []string{node.Value}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("%s: %s must list file paths", path, includeKey)
+			}
+			names = append(names, item.Value)
+		}
+	default:
+		return nil, fmt.Errorf("%s: %s must be a file path or a list of file paths", path, includeKey)
+	}
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("%s: %s must not contain empty paths", path, includeKey)
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(path), name)
+		}
+		paths = append(paths, name)
+	}
+	return paths, nil
+}
+
+// mergeNodes merges the mapping src into the mapping dst: nested mappings are merged, other
+// values of src replace those of dst
+func mergeNodes(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := lookupKey(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeNodes(existing, value)
+		default:
+			*existing = *value
+		}
+	}
+}
+
+// lookupKey returns the value of a key of a mapping, nil if the key is not set
+func lookupKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// takeKey removes a key from a mapping and returns its value, nil if the key is not set
+func takeKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
+
+// isNullNode reports whether a node is an empty or null value
+func isNullNode(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
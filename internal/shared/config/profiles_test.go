@@ -0,0 +1,241 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfigFiles writes configuration files by name into a temporary directory and returns it
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"shared/base.yml": `server:
+  port: 9000
+llm:
+  default_provider: "openai"
+  providers:
+    openai:
+      api_key: "sk-base"
+      model: "gpt-4"
+logging:
+  level: "info"
+  format: "text"
+`,
+		"shared/dev.yml": `database:
+  path: "./data/dev.db"
+`,
+		"config.yml": `include: shared/base.yml
+logging:
+  level: "debug"
+profiles:
+  development:
+    include:
+      - shared/dev.yml
+  production:
+    server:
+      host: "0.0.0.0"
+    llm:
+      providers:
+        openai:
+          model: "gpt-4o"
+    logging:
+      level: "warn"
+`,
+	})
+	path := filepath.Join(dir, "config.yml")
+
+	// Without a profile the file is layered on top of its includes
+	config, err := LoadProfile(path, "", nil)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Server.Port != 9000 || config.Server.Host != DefaultServerHost {
+		t.Errorf("Expected the server from the included file, got %+v", config.Server)
+	}
+	if config.Logging.Level != "debug" || config.Logging.Format != "text" {
+		t.Errorf("Expected the file to override the included logging level only, got %+v", config.Logging)
+	}
+
+	// The profile is layered on top of the file; nested mappings are merged
+	config, err = LoadProfile(path, "production", nil)
+	if err != nil {
+		t.Fatalf("Failed to load production config: %v", err)
+	}
+	if config.Server.Host != "0.0.0.0" || config.Server.Port != 9000 {
+		t.Errorf("Expected the production host with the included port, got %+v", config.Server)
+	}
+	if provider := config.LLM.Providers["openai"]; provider.Model != "gpt-4o" || provider.APIKey != "sk-base" {
+		t.Errorf("Expected the production model with the included api_key, got %+v", provider)
+	}
+	if config.Logging.Level != "warn" {
+		t.Errorf("Expected the production logging level, got %s", config.Logging.Level)
+	}
+
+	// Profiles may include files too
+	config, err = LoadProfile(path, "development", nil)
+	if err != nil {
+		t.Fatalf("Failed to load development config: %v", err)
+	}
+	if config.Database.Path != "./data/dev.db" || config.Logging.Level != "debug" {
+		t.Errorf("Expected the development database, got %+v", config.Database)
+	}
+
+	// NEXFLOW_PROFILE selects the profile of LoadWithOverrides
+	t.Setenv(ProfileEnv, "production")
+	config, err = LoadWithOverrides(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Logging.Level != "warn" {
+		t.Errorf("Expected the profile from %s, got logging level %s", ProfileEnv, config.Logging.Level)
+	}
+}
+
+func TestLoadProfile_Errors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"a.yml":       "include: b.yml\n",
+		"b.yml":       "include: [a.yml]\n",
+		"missing.yml": "include: nowhere.yml\n",
+		"nested.yml":  "profiles:\n  production:\n    profiles: {}\n",
+		"include.yml": "include:\n  server: {}\n",
+		"list.yml":    "- server\n",
+	})
+
+	tests := []struct {
+		name     string
+		file     string
+		profile  string
+		errorMsg string
+	}{
+		{name: "include cycle", file: "a.yml", errorMsg: "config include cycle"},
+		{name: "missing include", file: "missing.yml", errorMsg: "failed to read config file"},
+		{name: "nested profiles", file: "nested.yml", profile: "production", errorMsg: "must not define profiles"},
+		{name: "invalid include", file: "include.yml", errorMsg: "include must be a file path or a list"},
+		{name: "not a mapping", file: "list.yml", errorMsg: "must contain a mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadProfile(filepath.Join(dir, tt.file), tt.profile, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+
+	if _, err := LoadProfile(filepath.Join(dir, "b.yml"), "staging", nil); err == nil {
+		t.Error("Expected a cycle to fail before the profile is checked")
+	}
+	if _, err := LoadProfile("", "production", nil); err == nil {
+		t.Error("Expected a profile without a configuration file to fail")
+	}
+}
+
+func TestLoadProfile_UndefinedProfile(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.yml": `llm:
+  default_provider: "openai"
+  providers:
+    openai:
+      api_key: "sk-test"
+      model: "gpt-4"
+profiles:
+  production:
+`,
+	})
+	path := filepath.Join(dir, "config.yml")
+
+	if _, err := LoadProfile(path, "production", nil); err != nil {
+		t.Errorf("Expected an empty profile to be valid, got %v", err)
+	}
+	_, err := LoadProfile(path, "staging", nil)
+	if err == nil || !strings.Contains(err.Error(), `config profile "staging" is not defined`) {
+		t.Errorf("Expected an undefined profile to fail, got %v", err)
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"base.yml": `logging:
+  levle: "debug"
+`,
+		"config.yml": `include: base.yml
+server:
+  prot: 9000
+  port: 8080
+llm:
+  providers:
+    openai:
+      model: "gpt-4"
+      temprature: 0.5
+auth:
+  api_keys:
+    - key: "secret"
+      nmae: "ci"
+profiles:
+  production:
+    database:
+      pth: "/var/lib/nexflow.db"
+`,
+	})
+	path := filepath.Join(dir, "config.yml")
+
+	unknown, err := UnknownKeys(path, "")
+	if err != nil {
+		t.Fatalf("UnknownKeys failed: %v", err)
+	}
+	want := "auth.api_keys.0.nmae,llm.providers.openai.temprature,logging.levle,server.prot"
+	if got := strings.Join(unknown, ","); got != want {
+		t.Errorf("Expected unknown keys %s, got %s", want, got)
+	}
+
+	// The section of the profile is checked when it is selected
+	unknown, err = UnknownKeys(path, "production")
+	if err != nil {
+		t.Fatalf("UnknownKeys failed: %v", err)
+	}
+	if !strings.Contains(strings.Join(unknown, ","), "database.pth") {
+		t.Errorf("Expected the keys of the profile to be checked, got %v", unknown)
+	}
+
+	if _, err := UnknownKeys(filepath.Join(dir, "missing.yml"), ""); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestStarterTemplate(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{"config.yml": string(StarterTemplate)})
+	path := filepath.Join(dir, "config.yml")
+
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-test")
+	config, err := LoadProfile(path, "", nil)
+	if err != nil {
+		t.Fatalf("Expected the starter template to be valid, got %v", err)
+	}
+	if config.LLM.Providers["anthropic"].APIKey != "sk-ant-test" {
+		t.Errorf("Expected the API key from ANTHROPIC_API_KEY, got %q", config.LLM.Providers["anthropic"].APIKey)
+	}
+	if config.Database.MigrationsPath != "" || config.Database.Path != DefaultDatabasePath {
+		t.Errorf("Expected the embedded migrations and the default database, got %+v", config.Database)
+	}
+
+	unknown, err := UnknownKeys(path, "")
+	if err != nil || len(unknown) > 0 {
+		t.Errorf("Expected no unknown keys, got %v, %v", unknown, err)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+)
+
+// RateLimitConfig represents HTTP API rate limiting configuration.
+// Requests authenticated with an API key or JWT are limited per credential,
+// other requests per client IP.
+type RateLimitConfig struct {
+	// Enabled enables or disables rate limiting
+	Enabled bool `yaml:"enabled"`
+
+	// PerIP limits requests without valid credentials, by client IP
+	PerIP RateLimitBucketConfig `yaml:"per_ip"`
+
+	// PerAPIKey limits requests with an API key or JWT, by credential
+	PerAPIKey RateLimitBucketConfig `yaml:"per_api_key"`
+}
+
+// RateLimitBucketConfig represents a token bucket limit
+type RateLimitBucketConfig struct {
+	// RequestsPerMinute is the sustained request rate
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+
+	// Burst is how many requests can be made at once before the rate applies
+	Burst int `yaml:"burst"`
+}
+
+// Validate validates the rate limit configuration
+func (c *RateLimitConfig) Validate() error {
+	buckets := []struct {
+		name   string
+		bucket RateLimitBucketConfig
+	}{
+		{"per_ip", c.PerIP},
+		{"per_api_key", c.PerAPIKey},
+	}
+
+	for _, b := range buckets {
+		if b.bucket.RequestsPerMinute <= 0 {
+			return fmt.Errorf("rate_limit.%s.requests_per_minute must be positive, got %d", b.name, b.bucket.RequestsPerMinute)
+		}
+		if b.bucket.Burst <= 0 {
+			return fmt.Errorf("rate_limit.%s.burst must be positive, got %d", b.name, b.bucket.Burst)
+		}
+	}
+
+	return nil
+}
+
+// DefaultRateLimitConfig returns default rate limit configuration.
+// Rate limiting is disabled; once enabled, clients with credentials get a higher limit.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Enabled:   false,
+		PerIP:     RateLimitBucketConfig{RequestsPerMinute: 60, Burst: 20},
+		PerAPIKey: RateLimitBucketConfig{RequestsPerMinute: 600, Burst: 100},
+	}
+}
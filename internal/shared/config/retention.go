@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+)
+
+// RetentionPolicyConfig represents the retention policy of one kind of records
+type RetentionPolicyConfig struct {
+	// MaxAgeDays is how many days records are kept (0 = forever)
+	MaxAgeDays int `yaml:"max_age_days"`
+
+	// Archive exports records to the archive directory before they are deleted
+	Archive bool `yaml:"archive"`
+}
+
+// RetentionConfig represents configuration for periodic deletion of old records.
+// Messages and tasks of pinned sessions are never deleted.
+type RetentionConfig struct {
+	// Enabled enables or disables data retention
+	Enabled bool `yaml:"enabled"`
+
+	// IntervalSec is how often a retention pass runs in seconds
+	IntervalSec int `yaml:"interval_sec"`
+
+	// DryRun only logs how many records would be deleted
+	DryRun bool `yaml:"dry_run"`
+
+	// ArchiveDir is the directory archived records are written to as JSON lines
+	ArchiveDir string `yaml:"archive_dir"`
+
+	// Messages is the retention policy for messages
+	Messages RetentionPolicyConfig `yaml:"messages"`
+
+	// Tasks is the retention policy for tasks
+	Tasks RetentionPolicyConfig `yaml:"tasks"`
+
+	// Logs is the retention policy for logs
+	Logs RetentionPolicyConfig `yaml:"logs"`
+}
+
+// Validate validates the retention configuration
+func (c *RetentionConfig) Validate() error {
+	if c.IntervalSec < 0 {
+		return fmt.Errorf("retention interval_sec must be non-negative, got %d", c.IntervalSec)
+	}
+
+	policies := []struct {
+		name   string
+		policy RetentionPolicyConfig
+	}{
+		{"messages", c.Messages},
+		{"tasks", c.Tasks},
+		{"logs", c.Logs},
+	}
+	for _, p := range policies {
+		if p.policy.MaxAgeDays < 0 {
+			return fmt.Errorf("retention %s max_age_days must be non-negative, got %d", p.name, p.policy.MaxAgeDays)
+		}
+		if p.policy.Archive && c.ArchiveDir == "" {
+			return fmt.Errorf("retention archive_dir is required to archive %s", p.name)
+		}
+	}
+
+	return nil
+}
+
+// DefaultRetentionConfig returns default retention configuration.
+// Retention is disabled and all records are kept forever.
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		IntervalSec: 86400,
+	}
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 )
 
 // RouterConfig represents configuration for message router
@@ -23,6 +25,224 @@ type RouterConfig struct {
 
 	// RetryBackoffMultiplier is the multiplier for exponential backoff
 	RetryBackoffMultiplier float64 `yaml:"retry_backoff_multiplier"`
+
+	// Workers is the number of messages handled at the same time
+	Workers int `yaml:"workers"`
+
+	// QueueSize is the number of messages waiting for a worker
+	QueueSize int `yaml:"queue_size"`
+
+	// DrainTimeoutSec is how long stopping the router waits for queued and in-flight messages
+	DrainTimeoutSec int `yaml:"drain_timeout_sec"`
+
+	// Dedup configures dropping of messages delivered twice by a connector
+	Dedup DedupConfig `yaml:"dedup"`
+
+	// Session configures when a user's session expires and the next message starts a new one
+	Session SessionPolicyConfig `yaml:"session"`
+
+	// Commands configures the syntax and permissions of the chat commands answered by the router
+	Commands CommandsConfig `yaml:"commands"`
+
+	// I18n configures the language of the system messages sent to users
+	I18n I18nConfig `yaml:"i18n"`
+
+	// PostProcessing configures by connector how the replies of the LLM are transformed before they are sent
+	PostProcessing PostProcessingConfig `yaml:"post_processing"`
+
+	// FeedbackButtons appends thumbs up and down buttons rating the replies of the LLM, on connectors showing buttons
+	FeedbackButtons bool `yaml:"feedback_buttons"`
+}
+
+// languagePattern matches language codes such as "en", "ru" or "pt-BR"
+var languagePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})*$`)
+
+// I18nConfig represents the language selection and translations of the router system messages
+type I18nConfig struct {
+	// DefaultLanguage is the language of connectors without one; users may prefer another one with /settings
+	DefaultLanguage string `yaml:"default_language"`
+
+	// Languages sets the language by connector name
+	Languages map[string]string `yaml:"languages"`
+
+	// Messages adds or replaces translations by language code and message key
+	Messages map[string]map[string]string `yaml:"messages"`
+}
+
+// Validate validates the i18n configuration
+func (c *I18nConfig) Validate() error {
+	if c.DefaultLanguage != "" && !languagePattern.MatchString(c.DefaultLanguage) {
+		return fmt.Errorf("router i18n default_language must be a language code such as en, got %q", c.DefaultLanguage)
+	}
+
+	for connector, language := range c.Languages {
+		if !languagePattern.MatchString(language) {
+			return fmt.Errorf("router i18n language for %s must be a language code such as en, got %q", connector, language)
+		}
+	}
+
+	for language := range c.Messages {
+		if !languagePattern.MatchString(language) {
+			return fmt.Errorf("router i18n messages must be keyed by language codes such as en, got %q", language)
+		}
+	}
+
+	return nil
+}
+
+// CommandsConfig represents configuration for the chat commands of the router
+type CommandsConfig struct {
+	// Prefixes sets the command prefix by connector name; connectors without one use "/"
+	Prefixes map[string]string `yaml:"prefixes"`
+
+	// Admins lists by connector name the connector user IDs allowed to run admin-only commands
+	Admins map[string][]string `yaml:"admins"`
+
+	// AdminOnly lists the names of further commands restricted to admins, without the prefix
+	AdminOnly []string `yaml:"admin_only"`
+}
+
+// Validate validates the commands configuration
+func (c *CommandsConfig) Validate() error {
+	for connector, prefix := range c.Prefixes {
+		if prefix == "" || strings.ContainsAny(prefix, " \t\n") {
+			return fmt.Errorf("router commands prefix for %s must be non-empty and without spaces, got %q", connector, prefix)
+		}
+	}
+
+	for _, name := range c.AdminOnly {
+		if name == "" {
+			return fmt.Errorf("router commands admin_only must not contain empty names")
+		}
+	}
+
+	return nil
+}
+
+// SessionPolicyConfig represents the session expiry policy of the router; zero disables a condition
+type SessionPolicyConfig struct {
+	// IdleTimeoutSec expires a session that has not been updated for this many seconds
+	IdleTimeoutSec int `yaml:"idle_timeout_sec"`
+
+	// MaxAgeSec expires a session this many seconds after it was created
+	MaxAgeSec int `yaml:"max_age_sec"`
+
+	// MaxMessages expires a session holding this many messages, counting the replies
+	MaxMessages int `yaml:"max_messages"`
+}
+
+// Validate validates the session policy configuration
+func (c *SessionPolicyConfig) Validate() error {
+	if c.IdleTimeoutSec < 0 {
+		return fmt.Errorf("router session idle_timeout_sec must be non-negative, got %d", c.IdleTimeoutSec)
+	}
+
+	if c.MaxAgeSec < 0 {
+		return fmt.Errorf("router session max_age_sec must be non-negative, got %d", c.MaxAgeSec)
+	}
+
+	if c.MaxMessages < 0 {
+		return fmt.Errorf("router session max_messages must be non-negative, got %d", c.MaxMessages)
+	}
+
+	return nil
+}
+
+// Formats of the replies the router post-processing converts the Markdown of the LLM to
+var responseFormats = map[string]bool{"": true, "markdown": true, "plain": true, "html": true}
+
+// PostProcessingConfig represents the post-processing of the replies of the LLM by connector
+type PostProcessingConfig struct {
+	// Default applies to the connectors not listed in Connectors
+	Default ResponsePostProcessing `yaml:"default"`
+
+	// Connectors sets the post-processing by connector name, replacing Default
+	Connectors map[string]ResponsePostProcessing `yaml:"connectors"`
+}
+
+// ResponsePostProcessing represents the post-processing of the replies sent through a connector
+type ResponsePostProcessing struct {
+	// StripReasoning removes reasoning blocks such as <think>...</think> from the replies
+	StripReasoning bool `yaml:"strip_reasoning"`
+
+	// MaxLength cuts replies longer than this many characters; 0 leaves them whole
+	MaxLength int `yaml:"max_length"`
+
+	// Citations replaces the links of the replies with numbered citations listed under them
+	Citations bool `yaml:"citations"`
+
+	// Format is the formatting of the replies: markdown (default), plain or html
+	Format string `yaml:"format"`
+}
+
+// Validate validates the post-processing configuration
+func (c *PostProcessingConfig) Validate() error {
+	if err := c.Default.validate("default"); err != nil {
+		return err
+	}
+
+	for connector, pp := range c.Connectors {
+		if err := pp.validate("for " + connector); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate validates the post-processing of a connector, described in errors as which
+func (p *ResponsePostProcessing) validate(which string) error {
+	if p.MaxLength < 0 {
+		return fmt.Errorf("router post_processing %s max_length must be non-negative, got %d", which, p.MaxLength)
+	}
+
+	if !responseFormats[p.Format] {
+		return fmt.Errorf("router post_processing %s format must be markdown, plain or html, got %q", which, p.Format)
+	}
+
+	return nil
+}
+
+// DedupConfig represents configuration for message deduplication in the router
+type DedupConfig struct {
+	// Enabled enables dropping of duplicate messages
+	Enabled bool `yaml:"enabled"`
+
+	// TTLSeconds is how long a handled message is remembered
+	TTLSeconds int `yaml:"ttl_seconds"`
+
+	// CacheSize is the maximum number of messages remembered in memory
+	CacheSize int `yaml:"cache_size"`
+
+	// Persist also records handled messages in the database, so duplicates are detected
+	// after a restart and across instances sharing the database
+	Persist bool `yaml:"persist"`
+}
+
+// Validate validates the deduplication configuration
+func (c *DedupConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.TTLSeconds <= 0 {
+		return fmt.Errorf("router dedup ttl_seconds must be positive, got %d", c.TTLSeconds)
+	}
+
+	if c.CacheSize <= 0 {
+		return fmt.Errorf("router dedup cache_size must be positive, got %d", c.CacheSize)
+	}
+
+	return nil
+}
+
+// DefaultDedupConfig returns default deduplication configuration
+func DefaultDedupConfig() DedupConfig {
+	return DedupConfig{
+		Enabled:    true,
+		TTLSeconds: 600,
+		CacheSize:  10000,
+	}
 }
 
 // Validate validates the router configuration
@@ -35,6 +255,38 @@ func (c *RouterConfig) Validate() error {
 		return fmt.Errorf("router max_message_length too large, got %d (max 100000)", c.MaxMessageLength)
 	}
 
+	if c.Workers <= 0 {
+		return fmt.Errorf("router workers must be positive, got %d", c.Workers)
+	}
+
+	if c.QueueSize <= 0 {
+		return fmt.Errorf("router queue_size must be positive, got %d", c.QueueSize)
+	}
+
+	if c.DrainTimeoutSec <= 0 {
+		return fmt.Errorf("router drain_timeout_sec must be positive, got %d", c.DrainTimeoutSec)
+	}
+
+	if err := c.Dedup.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Session.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Commands.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.I18n.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.PostProcessing.Validate(); err != nil {
+		return err
+	}
+
 	if c.RetryMaxAttempts < 0 {
 		return fmt.Errorf("router retry_max_attempts must be non-negative, got %d", c.RetryMaxAttempts)
 	}
@@ -71,6 +323,13 @@ func DefaultRouterConfig() RouterConfig {
 		RetryInitialDelayMs:    100,
 		RetryMaxDelayMs:        5000,
 		RetryBackoffMultiplier: 2.0,
+		Workers:                16,
+		QueueSize:              256,
+		DrainTimeoutSec:        30,
+		Dedup:                  DefaultDedupConfig(),
+		PostProcessing: PostProcessingConfig{
+			Default: ResponsePostProcessing{StripReasoning: true},
+		},
 	}
 }
 
@@ -84,5 +343,8 @@ func (c *RouterConfig) ToRouterConfig() map[string]interface{} {
 		"retry_initial_delay_ms":   c.RetryInitialDelayMs,
 		"retry_max_delay_ms":       c.RetryMaxDelayMs,
 		"retry_backoff_multiplier": c.RetryBackoffMultiplier,
+		"workers":                  c.Workers,
+		"queue_size":               c.QueueSize,
+		"drain_timeout_sec":        c.DrainTimeoutSec,
 	}
 }
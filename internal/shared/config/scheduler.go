@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+)
+
+// SchedulerConfig represents configuration for the schedule execution engine
+type SchedulerConfig struct {
+	// Enabled enables or disables firing of schedules
+	Enabled bool `yaml:"enabled"`
+
+	// MaxConcurrent is the maximum number of schedules executed at the same time
+	MaxConcurrent int `yaml:"max_concurrent"`
+
+	// ExecutionTimeoutSec limits a single scheduled execution in seconds
+	ExecutionTimeoutSec int `yaml:"execution_timeout_sec"`
+}
+
+// Validate validates the scheduler configuration
+func (c *SchedulerConfig) Validate() error {
+	if c.MaxConcurrent < 0 {
+		return fmt.Errorf("scheduler max_concurrent must be non-negative, got %d", c.MaxConcurrent)
+	}
+
+	if c.ExecutionTimeoutSec < 0 {
+		return fmt.Errorf("scheduler execution_timeout_sec must be non-negative, got %d", c.ExecutionTimeoutSec)
+	}
+
+	return nil
+}
+
+// DefaultSchedulerConfig returns default scheduler configuration
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Enabled:             true,
+		MaxConcurrent:       4,
+		ExecutionTimeoutSec: 300,
+	}
+}
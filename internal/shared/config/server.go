@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 // Constants for validation
@@ -13,17 +15,286 @@ const (
 
 // ServerConfig represents server configuration
 type ServerConfig struct {
-	Host string `json:"host" yaml:"host"`
-	Port int    `json:"port" yaml:"port"`
+	Host            string                      `json:"host" yaml:"host"`
+	Port            int                         `json:"port" yaml:"port"`
+	TLS             ServerTLSConfig             `json:"tls" yaml:"tls"`
+	CORS            ServerCORSConfig            `json:"cors" yaml:"cors"`
+	SecurityHeaders ServerSecurityHeadersConfig `json:"security_headers" yaml:"security_headers"`
+	Shutdown        ServerShutdownConfig        `json:"shutdown" yaml:"shutdown"`
+	GRPC            ServerGRPCConfig            `json:"grpc" yaml:"grpc"`
+
+	// RequestTimeoutSec is the deadline of handling an API request, after which its use cases and
+	// LLM calls are cancelled and it fails with 504; streams and WebSockets have none (0 = no deadline)
+	RequestTimeoutSec int `json:"request_timeout_sec" yaml:"request_timeout_sec"`
+}
+
+// DefaultRequestTimeoutSec is the default deadline of an API request, shorter than the
+// 30 second write timeout of the server so that the 504 response can still be written
+const DefaultRequestTimeoutSec = 25
+
+// RequestTimeout returns the deadline of handling an API request, 0 meaning none
+func (s ServerConfig) RequestTimeout() time.Duration {
+	return time.Duration(s.RequestTimeoutSec) * time.Second
+}
+
+// ServerTLSConfig represents HTTPS configuration of the server.
+// HTTPS is enabled when both cert_file and key_file are set, or when autocert has domains.
+type ServerTLSConfig struct {
+	CertFile string `json:"cert_file" yaml:"cert_file"` // PEM certificate chain
+	KeyFile  string `json:"key_file" yaml:"key_file"`   // PEM private key
+
+	// RedirectPort is the port of a plain HTTP listener redirecting to HTTPS (0 = disabled)
+	RedirectPort int `json:"redirect_port" yaml:"redirect_port"`
+
+	// Autocert obtains and renews certificates from Let's Encrypt instead of cert_file and key_file
+	Autocert ServerAutocertConfig `json:"autocert" yaml:"autocert"`
+}
+
+// ServerAutocertConfig represents automatic certificates obtained over ACME.
+// The TLS-ALPN-01 challenge is answered on the HTTPS port and the HTTP-01 challenge
+// on the redirect listener, so one of them has to be reachable as port 443 or 80.
+type ServerAutocertConfig struct {
+	// Domains lists the host names certificates are requested for (empty = disabled)
+	Domains []string `json:"domains" yaml:"domains"`
+
+	// CacheDir keeps the account key and the certificates across restarts
+	CacheDir string `json:"cache_dir" yaml:"cache_dir"`
+
+	// Email is the contact address of the ACME account, used for expiry notices (optional)
+	Email string `json:"email" yaml:"email"`
+}
+
+// DefaultAutocertCacheDir is the default directory of the autocert cache
+const DefaultAutocertCacheDir = "./data/autocert"
+
+// Enabled reports whether certificates are obtained automatically
+func (a *ServerAutocertConfig) Enabled() bool {
+	return len(a.Domains) > 0
+}
+
+// ServerGRPCConfig represents the gRPC API served alongside HTTP on its own port,
+// backed by the same use cases and authenticated with the same API keys and JWTs
+type ServerGRPCConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	Port    int  `json:"port" yaml:"port"`
+
+	// Reflection registers the gRPC reflection service, which lets tools like grpcurl list the services
+	Reflection bool `json:"reflection" yaml:"reflection"`
+
+	// TLS enables TLS with its own certificate; empty uses the certificate of server.tls,
+	// including one obtained by autocert, if any
+	TLS ServerGRPCTLSConfig `json:"tls" yaml:"tls"`
+}
+
+// ServerGRPCTLSConfig represents the certificate of the gRPC server
+type ServerGRPCTLSConfig struct {
+	CertFile string `json:"cert_file" yaml:"cert_file"` // PEM certificate chain
+	KeyFile  string `json:"key_file" yaml:"key_file"`   // PEM private key
+}
+
+// DefaultGRPCPort is the default port of the gRPC server
+const DefaultGRPCPort = 9090
+
+// ServerCORSConfig represents cross-origin resource sharing configuration of the server.
+// CORS is disabled when allowed_origins is empty, so browsers only allow same-origin requests.
+type ServerCORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the API, e.g. "https://app.example.com"; "*" allows any origin
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins"`
+
+	// AllowedMethods lists the allowed request methods (defaults to DefaultCORSMethods)
+	AllowedMethods []string `json:"allowed_methods" yaml:"allowed_methods"`
+
+	// AllowedHeaders lists the allowed request headers (defaults to DefaultCORSHeaders)
+	AllowedHeaders []string `json:"allowed_headers" yaml:"allowed_headers"`
+
+	// ExposedHeaders lists the response headers readable by the browser
+	ExposedHeaders []string `json:"exposed_headers" yaml:"exposed_headers"`
+
+	// AllowCredentials allows cookies and the Authorization header on cross-origin requests
+	AllowCredentials bool `json:"allow_credentials" yaml:"allow_credentials"`
+
+	// MaxAge is how long browsers may cache preflight responses, in seconds (0 = browser default)
+	MaxAge int `json:"max_age" yaml:"max_age"`
+}
+
+// ServerShutdownConfig represents the timeouts of the stages of a graceful shutdown.
+// Draining the messages in flight is limited by router.drain_timeout_sec.
+type ServerShutdownConfig struct {
+	// HTTPTimeoutSec is how long in-flight HTTP requests and gRPC calls are waited for (0 = 10 seconds)
+	HTTPTimeoutSec int `json:"http_timeout_sec" yaml:"http_timeout_sec"`
+
+	// StageTimeoutSec limits each further stage, e.g. stopping the scheduler or flushing the
+	// event bus and the database logs (0 = 10 seconds)
+	StageTimeoutSec int `json:"stage_timeout_sec" yaml:"stage_timeout_sec"`
+}
+
+// HTTPTimeout returns the timeout of shutting down the HTTP server, 0 meaning the default
+func (s ServerShutdownConfig) HTTPTimeout() time.Duration {
+	return time.Duration(s.HTTPTimeoutSec) * time.Second
+}
+
+// StageTimeout returns the timeout of the further shutdown stages, 0 meaning the default
+func (s ServerShutdownConfig) StageTimeout() time.Duration {
+	return time.Duration(s.StageTimeoutSec) * time.Second
+}
+
+// Default CORS methods and headers, used when allowed_methods or allowed_headers is empty
+var (
+	DefaultCORSMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	DefaultCORSHeaders = []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID"}
+)
+
+// ServerSecurityHeadersConfig represents the security headers added to every response.
+// X-Content-Type-Options, X-Frame-Options and Referrer-Policy are fixed when enabled.
+type ServerSecurityHeadersConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// HSTSMaxAge is the max-age of the Strict-Transport-Security header, in seconds.
+	// The header is only sent over HTTPS (0 = disabled).
+	HSTSMaxAge int `json:"hsts_max_age" yaml:"hsts_max_age"`
+
+	// ContentSecurityPolicy is the Content-Security-Policy header, which protects the
+	// dashboard and Swagger UI pages (empty = not sent)
+	ContentSecurityPolicy string `json:"content_security_policy" yaml:"content_security_policy"`
+}
+
+// DefaultContentSecurityPolicy allows the server's own pages and the Swagger UI assets served by unpkg.com
+const DefaultContentSecurityPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline' https://unpkg.com; " +
+	"style-src 'self' 'unsafe-inline' https://unpkg.com; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'"
+
+// DefaultServerSecurityHeadersConfig returns default security headers configuration.
+// Security headers are enabled, with HSTS for one year.
+func DefaultServerSecurityHeadersConfig() ServerSecurityHeadersConfig {
+	return ServerSecurityHeadersConfig{
+		Enabled:               true,
+		HSTSMaxAge:            31536000,
+		ContentSecurityPolicy: DefaultContentSecurityPolicy,
+	}
+}
+
+// Default address the server listens on
+const (
+	DefaultServerHost = "127.0.0.1"
+	DefaultServerPort = 8080
+)
+
+// DefaultServerConfig returns default server configuration: plain HTTP on 127.0.0.1:8080
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Host:            DefaultServerHost,
+		Port:            DefaultServerPort,
+		SecurityHeaders: DefaultServerSecurityHeadersConfig(),
+		Shutdown:        ServerShutdownConfig{HTTPTimeoutSec: 10, StageTimeoutSec: 10},
+		TLS:             ServerTLSConfig{Autocert: ServerAutocertConfig{CacheDir: DefaultAutocertCacheDir}},
+		GRPC:            ServerGRPCConfig{Port: DefaultGRPCPort, Reflection: true},
+
+		RequestTimeoutSec: DefaultRequestTimeoutSec,
+	}
+}
+
+// Enabled reports whether HTTPS is configured
+func (t *ServerTLSConfig) Enabled() bool {
+	return (t.CertFile != "" && t.KeyFile != "") || t.Autocert.Enabled()
+}
+
+// GRPCTLSFiles returns the certificate and key of the gRPC server, those of server.tls
+// unless server.grpc.tls sets its own; both are empty when gRPC is served without TLS
+func (s *ServerConfig) GRPCTLSFiles() (certFile, keyFile string) {
+	if s.GRPC.TLS.CertFile != "" {
+		return s.GRPC.TLS.CertFile, s.GRPC.TLS.KeyFile
+	}
+	return s.TLS.CertFile, s.TLS.KeyFile
 }
 
 // Validate validates the server configuration
 func (s *ServerConfig) Validate() error {
+	var errs ValidationErrors
 	if s.Host == "" {
-		return fmt.Errorf("server.host is required")
+		errs.addf("server.host is required")
 	}
 	if s.Port < MinPort || s.Port > MaxPort {
-		return fmt.Errorf("server.port must be between %d and %d", MinPort, MaxPort)
+		errs.addf("server.port must be between %d and %d", MinPort, MaxPort)
+	}
+	if (s.TLS.CertFile == "") != (s.TLS.KeyFile == "") {
+		errs.addf("server.tls.cert_file and server.tls.key_file must be set together")
+	}
+	if s.TLS.Autocert.Enabled() {
+		if s.TLS.CertFile != "" {
+			errs.addf("server.tls.autocert cannot be combined with server.tls.cert_file and server.tls.key_file")
+		}
+		if s.TLS.Autocert.CacheDir == "" {
+			errs.addf("server.tls.autocert.cache_dir is required")
+		}
+		for i, domain := range s.TLS.Autocert.Domains {
+			if domain == "" || strings.ContainsAny(domain, "/: ") {
+				errs.addf("server.tls.autocert.domains[%d] must be a host name, got %q", i, domain)
+			}
+		}
+	}
+	if s.TLS.RedirectPort != 0 {
+		switch {
+		case !s.TLS.Enabled():
+			errs.addf("server.tls.redirect_port requires server.tls.cert_file and server.tls.key_file or server.tls.autocert")
+		case s.TLS.RedirectPort < MinPort || s.TLS.RedirectPort > MaxPort:
+			errs.addf("server.tls.redirect_port must be between %d and %d", MinPort, MaxPort)
+		case s.TLS.RedirectPort == s.Port:
+			errs.addf("server.tls.redirect_port must differ from server.port")
+		}
+	}
+	if s.GRPC.Enabled {
+		switch {
+		case s.GRPC.Port < MinPort || s.GRPC.Port > MaxPort:
+			errs.addf("server.grpc.port must be between %d and %d", MinPort, MaxPort)
+		case s.GRPC.Port == s.Port || s.GRPC.Port == s.TLS.RedirectPort:
+			errs.addf("server.grpc.port must differ from server.port and server.tls.redirect_port")
+		}
+	}
+	if (s.GRPC.TLS.CertFile == "") != (s.GRPC.TLS.KeyFile == "") {
+		errs.addf("server.grpc.tls.cert_file and server.grpc.tls.key_file must be set together")
+	}
+	errs.add(s.CORS.Validate())
+	if s.SecurityHeaders.HSTSMaxAge < 0 {
+		errs.addf("server.security_headers.hsts_max_age must be non-negative")
+	}
+	if s.RequestTimeoutSec < 0 {
+		errs.addf("server.request_timeout_sec must be non-negative")
+	}
+	if s.Shutdown.HTTPTimeoutSec < 0 {
+		errs.addf("server.shutdown.http_timeout_sec must be non-negative")
+	}
+	if s.Shutdown.StageTimeoutSec < 0 {
+		errs.addf("server.shutdown.stage_timeout_sec must be non-negative")
+	}
+	return errs.err()
+}
+
+// Enabled reports whether CORS is configured
+func (c *ServerCORSConfig) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+// AllowsAnyOrigin reports whether allowed_origins contains "*"
+func (c *ServerCORSConfig) AllowsAnyOrigin() bool {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the CORS configuration
+func (c *ServerCORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "" {
+			return fmt.Errorf("server.cors.allowed_origins must not contain empty origins")
+		}
+	}
+	if c.AllowCredentials && c.AllowsAnyOrigin() {
+		return fmt.Errorf("server.cors.allow_credentials cannot be used with allowed_origins \"*\"")
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("server.cors.max_age must be non-negative")
 	}
 	return nil
 }
@@ -1,7 +1,11 @@
 package config
 
-import (
-	"fmt"
+import "sort"
+
+// Limits of the retry policies of skills
+const (
+	MaxSkillRetryAttempts  = 10
+	MaxSkillRetryBackoffMs = 600000
 )
 
 // SkillsConfig represents skills configuration
@@ -9,15 +13,103 @@ type SkillsConfig struct {
 	Directory      string `json:"directory" yaml:"directory"`
 	TimeoutSec     int    `json:"timeout_sec" yaml:"timeout_sec"`
 	SandboxEnabled bool   `json:"sandbox_enabled" yaml:"sandbox_enabled"`
+
+	// Retry is the retry policy of skill executions failing with a retryable error, e.g. a timeout
+	Retry SkillRetryConfig `json:"retry" yaml:"retry"`
+
+	// Retries overrides the retry policy by skill name; zero fields fall back to Retry
+	Retries map[string]SkillRetryConfig `json:"retries" yaml:"retries"`
+}
+
+// SkillRetryConfig represents the retry policy of a skill
+type SkillRetryConfig struct {
+	// MaxAttempts is the number of times a skill is executed before its task fails (0 = 1)
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+
+	// BackoffMs is the delay in milliseconds before the first retry; it doubles with every retry
+	BackoffMs int `json:"backoff_ms" yaml:"backoff_ms"`
+
+	// MaxBackoffMs caps the delay in milliseconds between retries (0 = no cap)
+	MaxBackoffMs int `json:"max_backoff_ms" yaml:"max_backoff_ms"`
+}
+
+// DefaultSkillsConfig returns default skills configuration: skills in ./skills with a 30 second
+// timeout, executed once
+func DefaultSkillsConfig() SkillsConfig {
+	return SkillsConfig{
+		Directory:  "./skills",
+		TimeoutSec: DefaultTimeout,
+		Retry: SkillRetryConfig{
+			MaxAttempts:  1,
+			BackoffMs:    1000,
+			MaxBackoffMs: 30000,
+		},
+	}
+}
+
+// RetryFor returns the retry policy of a skill: its override in Retries, completed by Retry
+func (s *SkillsConfig) RetryFor(skill string) SkillRetryConfig {
+	retry := s.Retry
+	override, ok := s.Retries[skill]
+	if !ok {
+		return retry
+	}
+	if override.MaxAttempts != 0 {
+		retry.MaxAttempts = override.MaxAttempts
+	}
+	if override.BackoffMs != 0 {
+		retry.BackoffMs = override.BackoffMs
+	}
+	if override.MaxBackoffMs != 0 {
+		retry.MaxBackoffMs = override.MaxBackoffMs
+	}
+	return retry
 }
 
 // Validate validates the skills configuration
 func (s *SkillsConfig) Validate() error {
+	var errs ValidationErrors
 	if s.Directory == "" {
-		return fmt.Errorf("skills.directory is required")
+		errs.addf("skills.directory is required")
 	}
 	if s.TimeoutSec <= 0 {
-		return fmt.Errorf("skills.timeout_sec must be positive")
+		errs.addf("skills.timeout_sec must be positive")
+	}
+
+	errs.add(s.Retry.Validate("skills.retry"))
+	if s.Retry.MaxBackoffMs > 0 && s.Retry.MaxBackoffMs < s.Retry.BackoffMs {
+		errs.addf("skills.retry.max_backoff_ms (%d) must not be less than backoff_ms (%d)", s.Retry.MaxBackoffMs, s.Retry.BackoffMs)
+	}
+	skills := make([]string, 0, len(s.Retries))
+	for skill := range s.Retries {
+		skills = append(skills, skill)
+	}
+	sort.Strings(skills)
+	for _, skill := range skills {
+		if skill == "" {
+			errs.addf("skills.retries must not have an empty skill name")
+			continue
+		}
+		retry := s.Retries[skill]
+		errs.add(retry.Validate("skills.retries." + skill))
+		if merged := s.RetryFor(skill); merged.MaxBackoffMs > 0 && merged.MaxBackoffMs < merged.BackoffMs {
+			errs.addf("skills.retries.%s max_backoff_ms (%d) must not be less than backoff_ms (%d)", skill, merged.MaxBackoffMs, merged.BackoffMs)
+		}
+	}
+	return errs.err()
+}
+
+// Validate validates the retry policy of skills configured at field
+func (c *SkillRetryConfig) Validate(field string) error {
+	var errs ValidationErrors
+	if c.MaxAttempts < 0 || c.MaxAttempts > MaxSkillRetryAttempts {
+		errs.addf("%s.max_attempts must be between 0 and %d, got %d", field, MaxSkillRetryAttempts, c.MaxAttempts)
+	}
+	if c.BackoffMs < 0 || c.BackoffMs > MaxSkillRetryBackoffMs {
+		errs.addf("%s.backoff_ms must be between 0 and %d, got %d", field, MaxSkillRetryBackoffMs, c.BackoffMs)
+	}
+	if c.MaxBackoffMs < 0 || c.MaxBackoffMs > MaxSkillRetryBackoffMs {
+		errs.addf("%s.max_backoff_ms must be between 0 and %d, got %d", field, MaxSkillRetryBackoffMs, c.MaxBackoffMs)
 	}
-	return nil
+	return errs.err()
 }
@@ -0,0 +1,22 @@
+package config
+
+import (
+	_ "embed"
+	"path/filepath"
+)
+
+// StarterTemplate is the configuration file written by nexflow init: a minimal, commented
+// config.yml reading the API key of the LLM provider from ANTHROPIC_API_KEY
+//
+//go:embed starter.yml
+var StarterTemplate []byte
+
+// StarterDirectories returns the directories the starter configuration keeps its data and
+// skills in, relative to the working directory of the server like all paths of the configuration
+func StarterDirectories() []string {
+	return []string{
+		filepath.Dir(DefaultDatabasePath),
+		filepath.Clean(DefaultBackupConfig().Dir),
+		filepath.Clean(DefaultSkillsConfig().Directory),
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownKeys returns the dotted paths of the keys set in the configuration file at path,
+// its includes and the section of profile that name no configuration key, e.g. a misspelled
+// "server.prot", sorted. Such keys are ignored by LoadProfile.
+func UnknownKeys(path, profile string) ([]string, error) {
+	node, err := loadConfigFile(path, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	collectUnknownKeys(node, reflect.TypeOf(Config{}), "", &unknown)
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// collectUnknownKeys appends the paths of the keys of node that name no key below t
+func collectUnknownKeys(node *yaml.Node, t reflect.Type, prefix string, unknown *[]string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode || t == durationType {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			field, ok := fieldByKey(t, key)
+			if !ok {
+				*unknown = append(*unknown, prefix+key)
+				continue
+			}
+			collectUnknownKeys(node.Content[i+1], field.Type, prefix+key+".", unknown)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			collectUnknownKeys(node.Content[i+1], t.Elem(), prefix+node.Content[i].Value+".", unknown)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			collectUnknownKeys(item, t.Elem(), prefix+strconv.Itoa(i)+".", unknown)
+		}
+	case reflect.Pointer:
+		collectUnknownKeys(node, t.Elem(), prefix, unknown)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UpdateCheckConfig represents configuration for checking GitHub releases for newer versions.
+// A newer version is logged as a warning and reported by GET /version.
+type UpdateCheckConfig struct {
+	// Enabled enables or disables the update check
+	Enabled bool `yaml:"enabled"`
+
+	// Repository is the GitHub repository releases are read from, as "owner/name"
+	Repository string `yaml:"repository"`
+
+	// IntervalHours is how often releases are checked in hours
+	IntervalHours int `yaml:"interval_hours"`
+}
+
+// Validate validates the update check configuration
+func (c *UpdateCheckConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if owner, name, ok := strings.Cut(c.Repository, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("update_check repository must be \"owner/name\", got %q", c.Repository)
+	}
+
+	if c.IntervalHours < 1 {
+		return fmt.Errorf("update_check interval_hours must be at least 1, got %d", c.IntervalHours)
+	}
+
+	return nil
+}
+
+// DefaultUpdateCheckConfig returns default update check configuration.
+// The check is disabled.
+func DefaultUpdateCheckConfig() UpdateCheckConfig {
+	return UpdateCheckConfig{
+		Repository:    "aatumaykin/nexflow",
+		IntervalHours: 24,
+	}
+}
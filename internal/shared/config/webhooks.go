@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// WebhookEndpointConfig represents an external URL that receives events
+type WebhookEndpointConfig struct {
+	// Name identifies the endpoint in the delivery log
+	Name string `yaml:"name"`
+
+	// URL is the http or https URL events are posted to
+	URL string `yaml:"url"`
+
+	// Secret signs the payloads with HMAC-SHA256 (empty = unsigned)
+	Secret string `yaml:"secret" secret:"true"`
+
+	// Events are the event types posted to the endpoint, e.g. task.completed
+	Events []string `yaml:"events"`
+}
+
+// WebhooksConfig represents configuration for outbound webhooks.
+// Webhooks take their events from the event bus, which must be enabled.
+type WebhooksConfig struct {
+	// Enabled enables or disables outbound webhooks
+	Enabled bool `yaml:"enabled"`
+
+	// TimeoutSec limits a single delivery attempt in seconds
+	TimeoutSec int `yaml:"timeout_sec"`
+
+	// MaxAttempts is how many times a delivery is attempted before it fails
+	MaxAttempts int `yaml:"max_attempts"`
+
+	// RetryBackoffMs is the delay before the first retry in milliseconds; it doubles with every retry
+	RetryBackoffMs int `yaml:"retry_backoff_ms"`
+
+	// Workers is the number of deliveries sent concurrently
+	Workers int `yaml:"workers"`
+
+	// QueueSize is the number of deliveries waiting to be sent
+	QueueSize int `yaml:"queue_size"`
+
+	// Endpoints are the endpoints events are posted to
+	Endpoints []WebhookEndpointConfig `yaml:"endpoints"`
+}
+
+// Validate validates the webhooks configuration
+func (c *WebhooksConfig) Validate() error {
+	if c.TimeoutSec < 0 {
+		return fmt.Errorf("webhooks timeout_sec must be non-negative, got %d", c.TimeoutSec)
+	}
+
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("webhooks max_attempts must be non-negative, got %d", c.MaxAttempts)
+	}
+
+	if c.RetryBackoffMs < 0 {
+		return fmt.Errorf("webhooks retry_backoff_ms must be non-negative, got %d", c.RetryBackoffMs)
+	}
+
+	if c.Workers < 0 {
+		return fmt.Errorf("webhooks workers must be non-negative, got %d", c.Workers)
+	}
+
+	if c.QueueSize < 0 {
+		return fmt.Errorf("webhooks queue_size must be non-negative, got %d", c.QueueSize)
+	}
+
+	names := make(map[string]bool, len(c.Endpoints))
+	for i, endpoint := range c.Endpoints {
+		if endpoint.Name == "" {
+			return fmt.Errorf("webhooks endpoint %d: name is required", i)
+		}
+		if names[endpoint.Name] {
+			return fmt.Errorf("webhooks endpoint %q is defined more than once", endpoint.Name)
+		}
+		names[endpoint.Name] = true
+
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks endpoint %q: url must be an http or https URL, got %q", endpoint.Name, endpoint.URL)
+		}
+
+		if len(endpoint.Events) == 0 {
+			return fmt.Errorf("webhooks endpoint %q: events must list at least one event type", endpoint.Name)
+		}
+	}
+
+	return nil
+}
+
+// DefaultWebhooksConfig returns default webhooks configuration.
+// Webhooks are disabled and no endpoints are configured.
+func DefaultWebhooksConfig() WebhooksConfig {
+	return WebhooksConfig{
+		TimeoutSec:     10,
+		MaxAttempts:    5,
+		RetryBackoffMs: 1000,
+		Workers:        4,
+		QueueSize:      1000,
+	}
+}
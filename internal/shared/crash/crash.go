@@ -0,0 +1,233 @@
+// Package crash recovers panics in the goroutines of the application, e.g. the router workers,
+// connector update loops and skill executions, so that a single failing message or update does
+// not bring down the process. Recovered panics are logged with their stack trace, counted and
+// passed to reporters, e.g. the crash log table or Sentry.
+package crash
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// reportTimeout limits passing a recovered panic to a single reporter
+const reportTimeout = 10 * time.Second
+
+// Report describes a recovered panic
+type Report struct {
+	Time      time.Time
+	Component string // Subsystem the panic was recovered in, e.g. "router", "telegram" or "skills"
+	Operation string // Operation that panicked, e.g. "handle_message"
+	Panic     string // Value passed to panic
+	Stack     string // Stack trace of the panicking goroutine
+	Frames    []Frame
+	Attrs     map[string]string // Context of the operation, e.g. the connector or skill name
+}
+
+// Frame is a function call of the stack of a panic
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Reporter receives the reports of recovered panics, e.g. to store them or send them to an
+// error tracker
+type Reporter interface {
+	ReportCrash(ctx context.Context, report Report) error
+}
+
+// PanicError is the error of an operation that panicked
+type PanicError struct {
+	Value any
+	Stack string
+}
+
+// Error implements error
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Metrics holds the metrics of recovered panics
+type Metrics struct {
+	registry *metrics.MetricsRegistry
+
+	ReportErrors *metrics.Counter
+}
+
+// NewMetrics creates a new Metrics instance
+func NewMetrics() *Metrics {
+	registry := metrics.NewMetricsRegistry()
+
+	return &Metrics{
+		registry:     registry,
+		ReportErrors: registry.GetCounter("crash_report_errors_total"),
+	}
+}
+
+// Recovered returns the counter of panics recovered in a component
+func (m *Metrics) Recovered(component string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("crash_panics_recovered_total{component=%q}", component))
+}
+
+// Registry returns the registry holding the crash metrics
+func (m *Metrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
+// Recoverer recovers panics, logging them with their stack trace, counting them and passing
+// them to its reporters in the background
+type Recoverer struct {
+	logger  logging.Logger
+	metrics *Metrics
+	masker  *logging.Masker
+
+	mu        sync.RWMutex
+	reporters []Reporter
+	pending   sync.WaitGroup
+}
+
+// NewRecoverer creates a Recoverer logging recovered panics and passing them to the reporters
+func NewRecoverer(logger logging.Logger, reporters ...Reporter) *Recoverer {
+	return &Recoverer{
+		logger:    logger,
+		metrics:   NewMetrics(),
+		masker:    logging.DefaultMasker(),
+		reporters: reporters,
+	}
+}
+
+// AddReporter adds a reporter receiving the panics recovered from now on
+func (r *Recoverer) AddReporter(reporter Reporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reporters = append(r.reporters, reporter)
+}
+
+// SetMasker sets the masker applied to panic values and attributes before they are logged or
+// reported. Without a masker, the DefaultMasker is used.
+func (r *Recoverer) SetMasker(masker *logging.Masker) {
+	if masker == nil {
+		masker = logging.DefaultMasker()
+	}
+	r.masker = masker
+}
+
+// Metrics returns the crash metrics
+func (r *Recoverer) Metrics() *Metrics {
+	return r.metrics
+}
+
+// Recover recovers a panic of the calling goroutine. It must be deferred directly:
+//
+//	defer recoverer.Recover(ctx, "router", "handle_message", &err, "connector", name)
+//
+// A recovered panic is logged, counted and reported with the stack trace and the attributes,
+// given as key-value pairs, and stored in *errp as a *PanicError unless errp is nil.
+// A nil Recoverer only stores the error.
+func (r *Recoverer) Recover(ctx context.Context, component, operation string, errp *error, args ...any) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	stack := string(debug.Stack())
+	if errp != nil {
+		*errp = &PanicError{Value: value, Stack: stack}
+	}
+	if r == nil {
+		return
+	}
+
+	report := Report{
+		Time:      time.Now().UTC(),
+		Component: component,
+		Operation: operation,
+		Panic:     r.masker.MaskString(fmt.Sprint(value)),
+		Stack:     stack,
+		Frames:    panicFrames(),
+		Attrs:     r.attrs(args),
+	}
+	r.handle(ctx, report)
+}
+
+// Wait waits for the recovered panics to be reported, e.g. before the application exits
+func (r *Recoverer) Wait() {
+	r.pending.Wait()
+}
+
+// handle logs, counts and reports a recovered panic
+func (r *Recoverer) handle(ctx context.Context, report Report) {
+	r.metrics.Recovered(report.Component).Inc()
+
+	logArgs := []any{"component", report.Component, "operation", report.Operation, "panic", report.Panic}
+	for key, value := range report.Attrs {
+		logArgs = append(logArgs, key, value)
+	}
+	r.logger.ErrorContext(ctx, "recovered panic", append(logArgs, "stack", report.Stack)...)
+
+	r.mu.RLock()
+	reporters := r.reporters
+	r.mu.RUnlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, reporter := range reporters {
+		r.pending.Add(1)
+		go func() {
+			defer r.pending.Done()
+			ctx, cancel := context.WithTimeout(ctx, reportTimeout)
+			defer cancel()
+			if err := reporter.ReportCrash(ctx, report); err != nil {
+				r.metrics.ReportErrors.Inc()
+				r.logger.WarnContext(logging.WithoutDatabase(ctx), "failed to report panic", "component", report.Component, "error", err)
+			}
+		}()
+	}
+}
+
+// attrs converts key-value pairs to the attributes of a report, masking secrets
+func (r *Recoverer) attrs(args []any) map[string]string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	attrs := make(map[string]string, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		key := fmt.Sprint(args[i])
+		value := fmt.Sprint(args[i+1])
+		if r.masker.MaskKey(key) {
+			value = "***"
+		} else {
+			value = r.masker.MaskString(value)
+		}
+		attrs[key] = value
+	}
+	return attrs
+}
+
+// panicFrames returns the stack of the panicking goroutine, innermost call first, starting at
+// the function that panicked
+func panicFrames() []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs) // Skip runtime.Callers, panicFrames and Recover
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var out []Frame
+	for {
+		frame, more := frames.Next()
+		// Skip the frames of the runtime raising the panic
+		if out != nil || !strings.HasPrefix(frame.Function, "runtime.") {
+			out = append(out, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return out
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package crash
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// recordingReporter keeps the reports it receives
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports []Report
+	err     error
+}
+
+func (r *recordingReporter) ReportCrash(ctx context.Context, report Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+	return r.err
+}
+
+// explode panics with a nil map write
+func explode() {
+	var m map[string]int
+	m["key"]++
+}
+
+func TestRecoverer_Recover(t *testing.T) {
+	reporter := &recordingReporter{}
+	failing := &recordingReporter{err: errors.New("unavailable")}
+	recoverer := NewRecoverer(logging.NewNoopLogger(), reporter)
+	recoverer.AddReporter(failing)
+
+	run := func() (err error) {
+		defer recoverer.Recover(context.Background(), "router", "handle_message", &err, "connector", "telegram", "bot_token", "123456:secret")
+		explode()
+		return nil
+	}
+	err := run()
+	recoverer.Wait()
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || !strings.Contains(err.Error(), "assignment to entry in nil map") {
+		t.Fatalf("Expected a PanicError, got %v", err)
+	}
+	if !strings.Contains(panicErr.Stack, "explode") {
+		t.Errorf("Expected the stack of the panic, got %s", panicErr.Stack)
+	}
+
+	if len(reporter.reports) != 1 || len(failing.reports) != 1 {
+		t.Fatalf("Expected the panic to be reported once to each reporter, got %d and %d", len(reporter.reports), len(failing.reports))
+	}
+	report := reporter.reports[0]
+	if report.Component != "router" || report.Operation != "handle_message" || report.Attrs["connector"] != "telegram" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if report.Attrs["bot_token"] != "***" {
+		t.Errorf("Expected secret attributes to be masked, got %q", report.Attrs["bot_token"])
+	}
+	if len(report.Frames) == 0 || !strings.HasSuffix(report.Frames[0].Function, "crash.explode") {
+		t.Errorf("Expected the frames to start at the panicking function, got %+v", report.Frames)
+	}
+
+	if got := recoverer.Metrics().Recovered("router").Get(); got != 1 {
+		t.Errorf("Expected 1 recovered panic, got %d", got)
+	}
+	if got := recoverer.Metrics().ReportErrors.Get(); got != 1 {
+		t.Errorf("Expected 1 report error, got %d", got)
+	}
+}
+
+func TestRecoverer_NoPanic(t *testing.T) {
+	reporter := &recordingReporter{}
+	recoverer := NewRecoverer(logging.NewNoopLogger(), reporter)
+
+	run := func() (err error) {
+		defer recoverer.Recover(context.Background(), "skills", "execute", &err)
+		return errors.New("failed")
+	}
+	if err := run(); err == nil || err.Error() != "failed" {
+		t.Errorf("Expected the error of the operation, got %v", err)
+	}
+	recoverer.Wait()
+	if len(reporter.reports) != 0 || recoverer.Metrics().Recovered("skills").Get() != 0 {
+		t.Error("Expected nothing to be reported without a panic")
+	}
+}
+
+func TestRecoverer_Nil(t *testing.T) {
+	var recoverer *Recoverer
+
+	run := func() (err error) {
+		defer recoverer.Recover(context.Background(), "telegram", "handle_update", &err)
+		panic("boom")
+	}
+	if err := run(); err == nil || err.Error() != "panic: boom" {
+		t.Errorf("Expected a nil Recoverer to recover the panic, got %v", err)
+	}
+
+	// Without an error to store, the panic is dropped
+	func() {
+		defer recoverer.Recover(context.Background(), "telegram", "handle_update", nil)
+		panic("boom")
+	}()
+}
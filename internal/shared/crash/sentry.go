@@ -0,0 +1,232 @@
+package crash
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// sentryClient identifies the reporter in the authentication header of Sentry requests
+const sentryClient = "nexflow-crash/1.0"
+
+// inAppPrefix marks the frames of the application itself in the stack traces sent to Sentry
+const inAppPrefix = "github.com/atumaikin/nexflow/"
+
+// SentryReporter sends recovered panics to Sentry, or a service accepting Sentry events such as
+// GlitchTip, as events posted to the envelope endpoint of the project of a DSN
+type SentryReporter struct {
+	dsn         string
+	endpoint    string
+	key         string
+	environment string
+	serverName  string
+	client      *http.Client
+}
+
+// NewSentryReporter creates a reporter sending events to the project of dsn, e.g.
+// "https://<key>@o0.ingest.sentry.io/<project>". The events carry the environment and the
+// server name, e.g. the service name, if they are not empty.
+func NewSentryReporter(dsn, environment, serverName string, client *http.Client) (*SentryReporter, error) {
+	endpoint, key, err := parseSentryDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &SentryReporter{
+		dsn:         dsn,
+		endpoint:    endpoint,
+		key:         key,
+		environment: environment,
+		serverName:  serverName,
+		client:      client,
+	}, nil
+}
+
+// parseSentryDSN returns the envelope endpoint and the public key of a DSN
+func parseSentryDSN(dsn string) (endpoint, key string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("invalid Sentry DSN: must be an http or https URL")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("invalid Sentry DSN: missing public key")
+	}
+	prefix, project := path.Split(strings.TrimRight(u.Path, "/"))
+	if project == "" {
+		return "", "", fmt.Errorf("invalid Sentry DSN: missing project ID")
+	}
+
+	endpoint = fmt.Sprintf("%s://%s%sapi/%s/envelope/", u.Scheme, u.Host, prefix, project)
+	return endpoint, u.User.Username(), nil
+}
+
+// ReportCrash implements Reporter
+func (s *SentryReporter) ReportCrash(ctx context.Context, report Report) error {
+	body, err := s.envelope(report, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClient, s.key))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event to Sentry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sentry returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Sentry envelope and event messages
+type (
+	sentryEnvelopeHeader struct {
+		EventID string `json:"event_id"`
+		SentAt  string `json:"sent_at"`
+		DSN     string `json:"dsn"`
+	}
+
+	sentryItemHeader struct {
+		Type        string `json:"type"`
+		Length      int    `json:"length"`
+		ContentType string `json:"content_type"`
+	}
+
+	sentryEvent struct {
+		EventID     string            `json:"event_id"`
+		Timestamp   string            `json:"timestamp"`
+		Platform    string            `json:"platform"`
+		Level       string            `json:"level"`
+		Logger      string            `json:"logger"`
+		ServerName  string            `json:"server_name,omitempty"`
+		Environment string            `json:"environment,omitempty"`
+		Transaction string            `json:"transaction,omitempty"`
+		Tags        map[string]string `json:"tags"`
+		Exception   sentryExceptions  `json:"exception"`
+	}
+
+	sentryExceptions struct {
+		Values []sentryException `json:"values"`
+	}
+
+	sentryException struct {
+		Type       string           `json:"type"`
+		Value      string           `json:"value"`
+		Mechanism  sentryMechanism  `json:"mechanism"`
+		Stacktrace sentryStacktrace `json:"stacktrace"`
+	}
+
+	sentryMechanism struct {
+		Type    string `json:"type"`
+		Handled bool   `json:"handled"`
+	}
+
+	sentryStacktrace struct {
+		Frames []sentryFrame `json:"frames"`
+	}
+
+	sentryFrame struct {
+		Function string `json:"function"`
+		Module   string `json:"module,omitempty"`
+		AbsPath  string `json:"abs_path"`
+		Lineno   int    `json:"lineno"`
+		InApp    bool   `json:"in_app"`
+	}
+)
+
+// envelope encodes a report as an envelope with a single event
+func (s *SentryReporter) envelope(report Report, now time.Time) ([]byte, error) {
+	eventID, err := newEventID()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{"component": report.Component, "operation": report.Operation}
+	for key, value := range report.Attrs {
+		tags[key] = value
+	}
+
+	// Sentry expects the outermost call first
+	frames := make([]sentryFrame, 0, len(report.Frames))
+	for i := len(report.Frames) - 1; i >= 0; i-- {
+		frame := report.Frames[i]
+		module, function := splitFunction(frame.Function)
+		frames = append(frames, sentryFrame{
+			Function: function,
+			Module:   module,
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    strings.HasPrefix(frame.Function, inAppPrefix),
+		})
+	}
+
+	event, err := json.Marshal(sentryEvent{
+		EventID:     eventID,
+		Timestamp:   report.Time.UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       "error",
+		Logger:      report.Component,
+		ServerName:  s.serverName,
+		Environment: s.environment,
+		Transaction: report.Operation,
+		Tags:        tags,
+		Exception: sentryExceptions{Values: []sentryException{{
+			Type:       "panic",
+			Value:      report.Panic,
+			Mechanism:  sentryMechanism{Type: "recover", Handled: true},
+			Stacktrace: sentryStacktrace{Frames: frames},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(sentryEnvelopeHeader{EventID: eventID, SentAt: now.Format(time.RFC3339Nano), DSN: s.dsn}); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(sentryItemHeader{Type: "event", Length: len(event), ContentType: "application/json"}); err != nil {
+		return nil, err
+	}
+	buf.Write(event)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// splitFunction splits a function name such as "github.com/a/b/pkg.(*T).Method" into the
+// package path and the function
+func splitFunction(name string) (module, function string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	dot += slash + 1
+	return name[:dot], name[dot+1:]
+}
+
+// newEventID returns a random event ID of 32 hexadecimal characters
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,105 @@
+package crash
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSentryDSN(t *testing.T) {
+	endpoint, key, err := parseSentryDSN("https://abc123@o1.ingest.sentry.io/42")
+	if err != nil || endpoint != "https://o1.ingest.sentry.io/api/42/envelope/" || key != "abc123" {
+		t.Errorf("Unexpected endpoint %q, key %q, error %v", endpoint, key, err)
+	}
+	endpoint, _, err = parseSentryDSN("http://key@glitchtip.local:8000/sentry/7/")
+	if err != nil || endpoint != "http://glitchtip.local:8000/sentry/api/7/envelope/" {
+		t.Errorf("Expected the path prefix to be kept, got %q, %v", endpoint, err)
+	}
+
+	for _, dsn := range []string{"", "ftp://key@host/1", "https://host/1", "https://key@host/"} {
+		if _, _, err := parseSentryDSN(dsn); err == nil {
+			t.Errorf("Expected DSN %q to be invalid", dsn)
+		}
+	}
+}
+
+func TestSentryReporter_ReportCrash(t *testing.T) {
+	var auth string
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/envelope/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		auth = r.Header.Get("X-Sentry-Auth")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/42"
+	reporter, err := NewSentryReporter(dsn, "production", "nexflow", server.Client())
+	if err != nil {
+		t.Fatalf("NewSentryReporter failed: %v", err)
+	}
+
+	report := Report{
+		Time:      time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		Component: "router",
+		Operation: "handle_message",
+		Panic:     "boom",
+		Frames: []Frame{
+			{Function: "github.com/atumaikin/nexflow/internal/application/router.(*MessageRouter).routeMessage", File: "/src/message_router.go", Line: 10},
+			{Function: "net/http.HandlerFunc.ServeHTTP", File: "/go/src/net/http/server.go", Line: 20},
+		},
+		Attrs: map[string]string{"connector": "telegram"},
+	}
+	if err := reporter.ReportCrash(context.Background(), report); err != nil {
+		t.Fatalf("ReportCrash failed: %v", err)
+	}
+
+	if !strings.Contains(auth, "sentry_key=public") || !strings.Contains(auth, "sentry_version=7") {
+		t.Errorf("Unexpected auth header %q", auth)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("Expected an envelope of 3 lines, got %q", lines)
+	}
+
+	var header sentryEnvelopeHeader
+	var item sentryItemHeader
+	var event sentryEvent
+	_ = json.Unmarshal([]byte(lines[0]), &header)
+	_ = json.Unmarshal([]byte(lines[1]), &item)
+	if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+		t.Fatalf("Invalid event: %v", err)
+	}
+	if len(header.EventID) != 32 || header.EventID != event.EventID || item.Type != "event" || item.Length != len(lines[2]) {
+		t.Errorf("Unexpected envelope headers %+v, %+v", header, item)
+	}
+	if event.Environment != "production" || event.ServerName != "nexflow" || event.Tags["connector"] != "telegram" || event.Timestamp != "2026-01-02T15:04:05Z" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+
+	exception := event.Exception.Values[0]
+	if exception.Value != "boom" || !exception.Mechanism.Handled {
+		t.Errorf("Unexpected exception %+v", exception)
+	}
+	frames := exception.Stacktrace.Frames
+	if len(frames) != 2 || frames[1].Function != "(*MessageRouter).routeMessage" || frames[1].Module != "github.com/atumaikin/nexflow/internal/application/router" || !frames[1].InApp || frames[0].InApp {
+		t.Errorf("Expected the outermost frame first and the application frames in app, got %+v", frames)
+	}
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	})
+	if err := reporter.ReportCrash(context.Background(), report); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("Expected the status of a rejected event, got %v", err)
+	}
+}
@@ -118,6 +118,31 @@ func (del *DatabaseEventLogger) Handle(ctx context.Context, event Event) error {
 		if e.Error != "" {
 			metadata["error"] = e.Error
 		}
+		if e.Duration > 0 {
+			metadata["duration_ms"] = e.Duration.Milliseconds()
+		}
+
+	case *FeedbackEvent:
+		metadata["message_id"] = e.MessageID
+		metadata["session_id"] = e.SessionID
+		metadata["user_id"] = e.UserID
+		metadata["rating"] = e.Rating
+		if e.Comment != "" {
+			metadata["comment"] = e.Comment
+		}
+
+	case *UsageEvent:
+		metadata["user_id"] = e.UserID
+		if e.WorkspaceID != "" {
+			metadata["workspace_id"] = e.WorkspaceID
+		}
+		metadata["session_id"] = e.SessionID
+		metadata["provider"] = e.ProviderName
+		metadata["model"] = e.Model
+		metadata["input_tokens"] = e.InputTokens
+		metadata["output_tokens"] = e.OutputTokens
+		metadata["total_tokens"] = e.TotalTokens
+		metadata["cost"] = e.Cost
 	}
 
 	// Determine log level
@@ -0,0 +1,373 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+var (
+	// ErrSubscriptionNotFound is returned when a dead letter is redelivered and no
+	// subscription with its name is active
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	// ErrRedeliveryFailed is returned when the handler fails again on a redelivered dead letter
+	ErrRedeliveryFailed = errors.New("redelivery failed")
+)
+
+// DefaultDeadLetterCapacity is the number of dead letters kept by the default in-memory store
+const DefaultDeadLetterCapacity = 1000
+
+// RetryPolicy controls how often a failing handler is called again with the same event
+type RetryPolicy struct {
+	// MaxAttempts is the number of times the handler is called before the event is
+	// dead-lettered (values below 1 mean a single attempt)
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry; it doubles with every retry
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between retries (30s if zero)
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by subscriptions without their own
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// attempts returns the number of times the handler is called
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the delay before a retry, counting retries from 1
+func (p RetryPolicy) delay(retry int) time.Duration {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	delay := p.Backoff
+	for i := 1; i < retry && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// SubscribeOptions configures a subscription created with SubscribeWithOptions
+type SubscribeOptions struct {
+	// Name identifies the subscription in dead letters; redelivered events go to the
+	// active subscription with this name. Defaults to the subscription ID, which is not
+	// stable across restarts, so subscriptions whose dead letters are persisted should be named.
+	Name string
+
+	// Filter holds the attributes the events must have (the zero value matches every event)
+	Filter EventFilter
+
+	// Retry overrides the retry policy of the event bus for this subscription (optional)
+	Retry *RetryPolicy
+
+	// BufferSize overrides the number of events queued for the handler (optional)
+	BufferSize int
+
+	// Overflow overrides the policy for events that do not fit into the queue (optional)
+	Overflow OverflowPolicy
+}
+
+// MemoryDeadLetterStore keeps dead letters in memory.
+// When it is full, the oldest dead letter is dropped to make room for a new one.
+type MemoryDeadLetterStore struct {
+	mu       sync.Mutex
+	letters  []*entity.DeadLetter // Oldest first
+	capacity int
+}
+
+var _ repository.DeadLetterRepository = (*MemoryDeadLetterStore)(nil)
+
+// NewMemoryDeadLetterStore creates a new in-memory dead-letter store
+//
+// Parameters:
+//   - capacity: Maximum number of dead letters kept (DefaultDeadLetterCapacity if not positive)
+//
+// Returns:
+//   - *MemoryDeadLetterStore: Initialized dead-letter store
+func NewMemoryDeadLetterStore(capacity int) *MemoryDeadLetterStore {
+	if capacity <= 0 {
+		capacity = DefaultDeadLetterCapacity
+	}
+	return &MemoryDeadLetterStore{capacity: capacity}
+}
+
+// Create saves a new dead letter, dropping the oldest one if the store is full
+func (s *MemoryDeadLetterStore) Create(ctx context.Context, letter *entity.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.letters) >= s.capacity {
+		s.letters = slices.Delete(s.letters, 0, len(s.letters)-s.capacity+1)
+	}
+	copied := *letter
+	s.letters = append(s.letters, &copied)
+	return nil
+}
+
+// GetByID retrieves a dead letter by its ID
+func (s *MemoryDeadLetterStore) GetByID(ctx context.Context, id string) (*entity.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.index(id)
+	if i < 0 {
+		return nil, fmt.Errorf("dead letter %w: %s", repository.ErrNotFound, id)
+	}
+	copied := *s.letters[i]
+	return &copied, nil
+}
+
+// List retrieves the dead letters matching a filter, newest first
+func (s *MemoryDeadLetterStore) List(ctx context.Context, filter repository.DeadLetterFilter) ([]*entity.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letters := make([]*entity.DeadLetter, 0)
+	skipped := 0
+	for i := len(s.letters) - 1; i >= 0; i-- {
+		letter := s.letters[i]
+		if filter.Subscription != "" && letter.Subscription != filter.Subscription {
+			continue
+		}
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+		if filter.Limit > 0 && len(letters) >= filter.Limit {
+			break
+		}
+		copied := *letter
+		letters = append(letters, &copied)
+	}
+	return letters, nil
+}
+
+// Update saves the outcome of the latest redelivery attempt
+func (s *MemoryDeadLetterStore) Update(ctx context.Context, letter *entity.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.index(letter.ID)
+	if i < 0 {
+		return fmt.Errorf("dead letter %w: %s", repository.ErrNotFound, letter.ID)
+	}
+	copied := *letter
+	s.letters[i] = &copied
+	return nil
+}
+
+// Delete removes a dead letter
+func (s *MemoryDeadLetterStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.index(id)
+	if i < 0 {
+		return fmt.Errorf("dead letter %w: %s", repository.ErrNotFound, id)
+	}
+	s.letters = slices.Delete(s.letters, i, i+1)
+	return nil
+}
+
+// index returns the position of the dead letter with an ID, or -1
+func (s *MemoryDeadLetterStore) index(id string) int {
+	return slices.IndexFunc(s.letters, func(letter *entity.DeadLetter) bool {
+		return letter.ID == id
+	})
+}
+
+// deliver calls the handler of a subscription with an event, retrying failed calls
+// according to the retry policy of the subscription. The event is dead-lettered when
+// the last attempt fails, or when the event bus stops while a retry is pending.
+func (eb *EventBus) deliver(sub *EventSubscription, event Event) {
+	attempts := sub.Retry.attempts()
+
+	var err error
+	made := 0
+	for made < attempts {
+		if made > 0 {
+			select {
+			case <-eb.ctx.Done():
+				eb.deadLetter(sub, event, made, err)
+				return
+			case <-time.After(sub.Retry.delay(made)):
+			}
+			eb.metrics.HandlerRetries.Inc()
+		}
+
+		err = eb.callHandler(eb.ctx, sub, event)
+		made++
+		if err == nil {
+			return
+		}
+
+		if made < attempts {
+			eb.logger.Warn("event handler failed, retrying",
+				"type", event.Type(),
+				"subscription", sub.Name,
+				"attempt", made,
+				"error", err,
+			)
+		}
+	}
+
+	eb.deadLetter(sub, event, made, err)
+}
+
+// callHandler calls the handler of a subscription once, recording its duration
+func (eb *EventBus) callHandler(parent context.Context, sub *EventSubscription, event Event) error {
+	ctx, cancel := context.WithTimeout(parent, 30*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := metrics.RecordDurationWithError(eb.metrics.ProcessingDuration, func() error {
+		return sub.Handler(ctx, event)
+	})
+	sub.metrics.handlerDuration.Observe(time.Since(start).Seconds())
+	return err
+}
+
+// deadLetter stores an event whose handler kept failing
+func (eb *EventBus) deadLetter(sub *EventSubscription, event Event, attempts int, handlerErr error) {
+	eb.metrics.EventsFailed.Inc()
+	eb.logger.Error("event handler failed",
+		"type", event.Type(),
+		"subscription", sub.Name,
+		"attempts", attempts,
+		"error", handlerErr,
+	)
+
+	payload, err := MarshalPayload(event)
+	if err != nil {
+		eb.logger.Error("failed to encode event for the dead-letter queue",
+			"type", event.Type(),
+			"error", err,
+		)
+		return
+	}
+
+	letter := entity.NewDeadLetter(sub.Name, event.Type(), string(payload), event.Timestamp(), attempts, errorMessage(handlerErr))
+
+	// The bus context may already be cancelled when a handler fails during Stop
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := eb.deadLetters.Create(ctx, letter); err != nil {
+		eb.logger.Error("failed to store dead letter",
+			"type", event.Type(),
+			"subscription", sub.Name,
+			"error", err,
+		)
+		return
+	}
+	eb.metrics.EventsDeadLettered.Inc()
+}
+
+// ListDeadLetters retrieves the events whose handlers kept failing, newest first
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - filter: Subscription and page of the dead letters
+//
+// Returns:
+//   - []*entity.DeadLetter: Matching dead letters
+//   - error: Error if reading the dead letters failed
+func (eb *EventBus) ListDeadLetters(ctx context.Context, filter repository.DeadLetterFilter) ([]*entity.DeadLetter, error) {
+	return eb.deadLetters.List(ctx, filter)
+}
+
+// DeleteDeadLetter discards a dead letter without redelivering it
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - id: ID of the dead letter
+//
+// Returns:
+//   - error: repository.ErrNotFound for an unknown ID, or the error of the store
+func (eb *EventBus) DeleteDeadLetter(ctx context.Context, id string) error {
+	return eb.deadLetters.Delete(ctx, id)
+}
+
+// RedeliverDeadLetter passes a dead-lettered event once more to the handler of the active
+// subscription with the name recorded in the dead letter; if several subscriptions share
+// the name, the oldest one is used. The dead letter is deleted when the handler succeeds
+// and updated with the new error when it fails.
+//
+// Parameters:
+//   - ctx: Context for the handler call
+//   - id: ID of the dead letter
+//
+// Returns:
+//   - error: repository.ErrNotFound for an unknown ID, ErrSubscriptionNotFound without an
+//     active subscription, or ErrRedeliveryFailed wrapping the handler error
+func (eb *EventBus) RedeliverDeadLetter(ctx context.Context, id string) error {
+	letter, err := eb.deadLetters.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	sub := eb.findSubscription(letter.Subscription)
+	if sub == nil {
+		return fmt.Errorf("%w: %s", ErrSubscriptionNotFound, letter.Subscription)
+	}
+
+	event, err := decodeEvent(&entity.StoredEvent{
+		Type:       letter.EventType,
+		Payload:    letter.Payload,
+		OccurredAt: letter.OccurredAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to decode dead letter %s: %w", id, err)
+	}
+
+	if err := eb.callHandler(ctx, sub, event); err != nil {
+		letter.RecordRedelivery(err.Error())
+		if updateErr := eb.deadLetters.Update(ctx, letter); updateErr != nil {
+			eb.logger.Error("failed to update dead letter", "id", id, "error", updateErr)
+		}
+		return fmt.Errorf("%w: %v", ErrRedeliveryFailed, err)
+	}
+
+	eb.metrics.DeadLettersRedelivered.Inc()
+	eb.logger.Info("dead letter redelivered", "id", id, "subscription", sub.Name)
+	return eb.deadLetters.Delete(ctx, id)
+}
+
+// findSubscription returns the oldest active subscription with a name, or nil
+func (eb *EventBus) findSubscription(name string) *EventSubscription {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	var found *EventSubscription
+	for _, index := range []map[string][]*EventSubscription{eb.subscriptions, eb.patterns} {
+		for _, subs := range index {
+			for _, sub := range subs {
+				if sub.Name == name && (found == nil || sub.seq < found.seq) {
+					found = sub
+				}
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,243 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func newRetryTestBus(t *testing.T, store repository.DeadLetterRepository) *EventBus {
+	t.Helper()
+	eb := NewEventBus(&EventBusConfig{
+		BatchSize:     10,
+		FlushInterval: 10 * time.Millisecond,
+		Logger:        logging.NewNoopLogger(),
+		Retry:         &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+		DeadLetters:   store,
+	})
+	if err := eb.Start(); err != nil {
+		t.Fatalf("Failed to start event bus: %v", err)
+	}
+	t.Cleanup(func() { _ = eb.Stop() })
+	return eb
+}
+
+// flakyHandler fails until it has been called a number of times
+type flakyHandler struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	events   []Event
+}
+
+func (h *flakyHandler) handle(ctx context.Context, event Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	h.events = append(h.events, event)
+	if h.calls <= h.failures {
+		return errors.New("handler unavailable")
+	}
+	return nil
+}
+
+func (h *flakyHandler) callCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls
+}
+
+func (h *flakyHandler) setFailures(failures int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = failures
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := policy.delay(i + 1); got != expected {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, expected)
+		}
+	}
+
+	if attempts := (RetryPolicy{}).attempts(); attempts != 1 {
+		t.Errorf("Expected a single attempt for the zero policy, got %d", attempts)
+	}
+}
+
+func TestEventBus_RetriesFailingHandler(t *testing.T) {
+	store := NewMemoryDeadLetterStore(10)
+	eb := newRetryTestBus(t, store)
+
+	handler := &flakyHandler{failures: 2}
+	eb.Subscribe([]string{EventUserCreated}, handler.handle)
+
+	eb.Publish(NewUserEvent(EventUserCreated, "user-1", "user@example.com", "web"))
+
+	waitUntil(t, "handler success", func() bool { return handler.callCount() == 3 })
+	time.Sleep(50 * time.Millisecond)
+
+	if calls := handler.callCount(); calls != 3 {
+		t.Errorf("Expected 3 handler calls, got %d", calls)
+	}
+	if retries := eb.Metrics().HandlerRetries.Get(); retries != 2 {
+		t.Errorf("Expected 2 retries, got %d", retries)
+	}
+	if failed := eb.Metrics().EventsFailed.Get(); failed != 0 {
+		t.Errorf("Expected no failed events, got %d", failed)
+	}
+	letters, _ := store.List(context.Background(), repository.DeadLetterFilter{})
+	if len(letters) != 0 {
+		t.Errorf("Expected no dead letters, got %d", len(letters))
+	}
+}
+
+func TestEventBus_DeadLettersAndRedelivers(t *testing.T) {
+	ctx := context.Background()
+	eb := newRetryTestBus(t, nil)
+
+	handler := &flakyHandler{failures: 100}
+	eb.SubscribeWithOptions([]string{EventTaskFailed}, SubscribeOptions{
+		Name:  "audit",
+		Retry: &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+	}, handler.handle)
+
+	eb.Publish(NewTaskEvent(EventTaskFailed, "task-1", "session-1", "echo", "failed", "{}", "", "boom"))
+
+	var letters []*entity.DeadLetter
+	waitUntil(t, "dead letter", func() bool {
+		letters, _ = eb.ListDeadLetters(ctx, repository.DeadLetterFilter{Subscription: "audit"})
+		return len(letters) == 1
+	})
+
+	letter := letters[0]
+	if letter.EventType != EventTaskFailed || letter.Attempts != 2 || letter.Error != "handler unavailable" {
+		t.Errorf("Unexpected dead letter: %+v", letter)
+	}
+	if handler.callCount() != 2 {
+		t.Errorf("Expected 2 handler calls, got %d", handler.callCount())
+	}
+	if count := eb.Metrics().EventsDeadLettered.Get(); count != 1 {
+		t.Errorf("Expected 1 dead-lettered event, got %d", count)
+	}
+
+	// The handler still fails: the dead letter records the attempt
+	err := eb.RedeliverDeadLetter(ctx, letter.ID)
+	if !errors.Is(err, ErrRedeliveryFailed) {
+		t.Fatalf("Expected ErrRedeliveryFailed, got %v", err)
+	}
+	letters, _ = eb.ListDeadLetters(ctx, repository.DeadLetterFilter{})
+	if len(letters) != 1 || letters[0].Attempts != 3 {
+		t.Fatalf("Expected the dead letter to record 3 attempts, got %+v", letters)
+	}
+
+	// The handler recovers: the event is delivered as the struct it was published as
+	handler.setFailures(0)
+	if err := eb.RedeliverDeadLetter(ctx, letter.ID); err != nil {
+		t.Fatalf("RedeliverDeadLetter() error = %v", err)
+	}
+	letters, _ = eb.ListDeadLetters(ctx, repository.DeadLetterFilter{})
+	if len(letters) != 0 {
+		t.Errorf("Expected the redelivered dead letter to be deleted, got %d", len(letters))
+	}
+	handler.mu.Lock()
+	task, ok := handler.events[len(handler.events)-1].(*TaskEvent)
+	handler.mu.Unlock()
+	if !ok || task.TaskID != "task-1" || task.Error != "boom" {
+		t.Errorf("Unexpected redelivered event: %+v", task)
+	}
+	if count := eb.Metrics().DeadLettersRedelivered.Get(); count != 1 {
+		t.Errorf("Expected 1 redelivered dead letter, got %d", count)
+	}
+
+	if err := eb.RedeliverDeadLetter(ctx, letter.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a redelivered dead letter, got %v", err)
+	}
+}
+
+func TestEventBus_RedeliverWithoutSubscription(t *testing.T) {
+	ctx := context.Background()
+	eb := newRetryTestBus(t, nil)
+
+	handler := &flakyHandler{failures: 100}
+	sub := eb.SubscribeWithOptions([]string{EventUserCreated}, SubscribeOptions{
+		Retry: &RetryPolicy{MaxAttempts: 1},
+	}, handler.handle)
+	if sub.Name != sub.ID {
+		t.Errorf("Expected the name to default to the ID %q, got %q", sub.ID, sub.Name)
+	}
+
+	eb.Publish(NewUserEvent(EventUserCreated, "user-1", "user@example.com", "web"))
+
+	var letters []*entity.DeadLetter
+	waitUntil(t, "dead letter", func() bool {
+		letters, _ = eb.ListDeadLetters(ctx, repository.DeadLetterFilter{})
+		return len(letters) == 1
+	})
+	if letters[0].Subscription != sub.ID || letters[0].Attempts != 1 {
+		t.Errorf("Unexpected dead letter: %+v", letters[0])
+	}
+
+	eb.Unsubscribe(sub)
+	if err := eb.RedeliverDeadLetter(ctx, letters[0].ID); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Expected ErrSubscriptionNotFound, got %v", err)
+	}
+
+	if err := eb.DeleteDeadLetter(ctx, letters[0].ID); err != nil {
+		t.Fatalf("DeleteDeadLetter() error = %v", err)
+	}
+	if err := eb.DeleteDeadLetter(ctx, letters[0].ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a deleted dead letter, got %v", err)
+	}
+}
+
+func TestMemoryDeadLetterStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDeadLetterStore(3)
+
+	var letters []*entity.DeadLetter
+	for i, subscription := range []string{"audit", "webhooks", "audit", "audit"} {
+		letter := entity.NewDeadLetter(subscription, EventUserCreated, "{}", time.Now(), i+1, "boom")
+		letters = append(letters, letter)
+		if err := store.Create(ctx, letter); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	// The oldest dead letter was dropped
+	if _, err := store.GetByID(ctx, letters[0].ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected the oldest dead letter to be dropped, got %v", err)
+	}
+
+	all, _ := store.List(ctx, repository.DeadLetterFilter{})
+	if len(all) != 3 || all[0].ID != letters[3].ID {
+		t.Fatalf("Expected 3 dead letters, newest first, got %+v", all)
+	}
+
+	page, _ := store.List(ctx, repository.DeadLetterFilter{Subscription: "audit", Limit: 1, Offset: 1})
+	if len(page) != 1 || page[0].ID != letters[2].ID {
+		t.Errorf("Unexpected page of audit dead letters: %+v", page)
+	}
+
+	letters[1].RecordRedelivery("still failing")
+	if err := store.Update(ctx, letters[1]); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, _ := store.GetByID(ctx, letters[1].ID)
+	if got.Attempts != 3 || got.Error != "still failing" {
+		t.Errorf("Unexpected updated dead letter: %+v", got)
+	}
+
+	if err := store.Update(ctx, letters[0]); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when updating a dropped dead letter, got %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// ErrNoEventStore is returned by Replay when the event bus has no event store
+var ErrNoEventStore = errors.New("event store is not configured")
+
+// replayBatchSize is the number of stored events read at a time during a replay
+const replayBatchSize = 500
+
+// EventStore persists published events so that they can be replayed later
+type EventStore interface {
+	// Append persists events in the order they were published
+	Append(ctx context.Context, events []Event) error
+
+	// Replay calls handler, in publication order, for every stored event that occurred
+	// at or after from and before to and has one of the given types.
+	// A zero from or to leaves that end of the range open; empty types select all events.
+	// Replay stops at the first handler error and returns it.
+	Replay(ctx context.Context, from, to time.Time, types []string, handler EventHandler) error
+
+	// Get returns the stored event with an ID, or an error wrapping repository.ErrNotFound
+	// for an unknown ID
+	Get(ctx context.Context, id string) (Event, error)
+}
+
+// DatabaseEventStore is an EventStore backed by the events table
+type DatabaseEventStore struct {
+	eventRepo repository.EventRepository
+	logger    logging.Logger
+}
+
+// NewDatabaseEventStore creates a new database event store
+//
+// Parameters:
+//   - eventRepo: Repository for stored events
+//   - logger: Structured logger for logging
+//
+// Returns:
+//   - *DatabaseEventStore: Initialized database event store
+func NewDatabaseEventStore(eventRepo repository.EventRepository, logger logging.Logger) *DatabaseEventStore {
+	return &DatabaseEventStore{
+		eventRepo: eventRepo,
+		logger:    logger,
+	}
+}
+
+// Append persists events in the order they were published
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - events: Events to persist
+//
+// Returns:
+//   - error: Error if persistence failed
+func (s *DatabaseEventStore) Append(ctx context.Context, events []Event) error {
+	records := make([]*entity.StoredEvent, 0, len(events))
+	for _, event := range events {
+		payload, err := MarshalPayload(event)
+		if err != nil {
+			// One event with data that has no JSON form must not cost the rest of the batch
+			s.logger.Error("failed to encode event for the event store",
+				"type", event.Type(),
+				"error", err,
+			)
+			continue
+		}
+		records = append(records, entity.NewStoredEvent(event.Type(), string(payload), event.Timestamp()))
+	}
+
+	return s.eventRepo.Append(ctx, records...)
+}
+
+// Replay calls handler for stored events in publication order
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - from: Earliest time of the replayed events (zero = from the first event)
+//   - to: Time before which the replayed events occurred (zero = up to the last event)
+//   - types: Types of the replayed events (empty = all types)
+//   - handler: Function called with each event
+//
+// Returns:
+//   - error: Error if reading the events failed or the handler returned an error
+func (s *DatabaseEventStore) Replay(ctx context.Context, from, to time.Time, types []string, handler EventHandler) error {
+	filter := repository.EventFilter{
+		Since: from,
+		Until: to,
+		Types: types,
+		Limit: replayBatchSize,
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		records, err := s.eventRepo.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to read stored events: %w", err)
+		}
+
+		for _, record := range records {
+			event, err := decodeEvent(record)
+			if err != nil {
+				s.logger.Warn("skipping undecodable stored event",
+					"seq", record.Seq,
+					"type", record.Type,
+					"error", err,
+				)
+				continue
+			}
+			if err := handler(ctx, event); err != nil {
+				return err
+			}
+		}
+
+		if len(records) < replayBatchSize {
+			return nil
+		}
+		filter.AfterSeq = records[len(records)-1].Seq
+	}
+}
+
+// Get returns a stored event by ID
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - id: ID of the stored event
+//
+// Returns:
+//   - Event: Event rebuilt as the struct it was published as
+//   - error: repository.ErrNotFound for an unknown ID, or an error if reading or decoding the event failed
+func (s *DatabaseEventStore) Get(ctx context.Context, id string) (Event, error) {
+	record, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvent(record)
+}
+
+// Event kinds recorded in stored payloads, one per event struct
+const (
+	eventKindBase      = ""
+	eventKindConnector = "connector"
+	eventKindRouter    = "router"
+	eventKindLLM       = "llm"
+	eventKindUser      = "user"
+	eventKindSession   = "session"
+	eventKindSkill     = "skill"
+	eventKindTask      = "task"
+	eventKindSchedule  = "schedule"
+	eventKindFeedback  = "feedback"
+	eventKindUsage     = "usage"
+)
+
+// eventPayload is the stored form of the fields of all event structs
+type eventPayload struct {
+	Kind         string                 `json:"kind,omitempty"`
+	Connector    string                 `json:"connector,omitempty"`
+	Provider     string                 `json:"provider,omitempty"`
+	Model        string                 `json:"model,omitempty"`
+	Source       string                 `json:"source,omitempty"`
+	UsageID      string                 `json:"usage_id,omitempty"`
+	UserID       string                 `json:"user_id,omitempty"`
+	WorkspaceID  string                 `json:"workspace_id,omitempty"`
+	Email        string                 `json:"email,omitempty"`
+	Channel      string                 `json:"channel,omitempty"`
+	ChannelID    string                 `json:"channel_id,omitempty"`
+	SessionID    string                 `json:"session_id,omitempty"`
+	MessageID    string                 `json:"message_id,omitempty"`
+	TaskID       string                 `json:"task_id,omitempty"`
+	ScheduleID   string                 `json:"schedule_id,omitempty"`
+	Skill        string                 `json:"skill,omitempty"`
+	Rating       string                 `json:"rating,omitempty"`
+	Comment      string                 `json:"comment,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+	Content      string                 `json:"content,omitempty"`
+	Input        string                 `json:"input,omitempty"`
+	Output       string                 `json:"output,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	Tokens       int                    `json:"tokens,omitempty"`
+	InputTokens  int                    `json:"input_tokens,omitempty"`
+	OutputTokens int                    `json:"output_tokens,omitempty"`
+	Cost         float64                `json:"cost,omitempty"`
+	MessageCount int                    `json:"message_count,omitempty"`
+	DurationMs   int64                  `json:"duration_ms,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Data         interface{}            `json:"data,omitempty"`
+}
+
+// MarshalPayload returns the JSON form of the fields of an event, as kept in the event store,
+// sent through a transport and posted to webhooks. The "kind" field names the event struct.
+//
+// Parameters:
+//   - event: Event to encode
+//
+// Returns:
+//   - []byte: JSON object with the event fields
+//   - error: Error if the event data has no JSON form
+func MarshalPayload(event Event) ([]byte, error) {
+	return json.Marshal(encodeEvent(event))
+}
+
+// encodeEvent converts an event to its stored form
+func encodeEvent(event Event) *eventPayload {
+	p := &eventPayload{}
+	if base, ok := event.(interface {
+		Metadata() map[string]interface{}
+		Data() interface{}
+	}); ok {
+		if metadata := base.Metadata(); len(metadata) > 0 {
+			p.Metadata = metadata
+		}
+		p.Data = base.Data()
+	}
+
+	switch e := event.(type) {
+	case *ConnectorEvent:
+		p.Kind = eventKindConnector
+		p.Connector = e.ConnectorName
+		p.UserID = e.UserID
+		p.ChannelID = e.ChannelID
+		p.Content = e.Message
+		p.Error = errorMessage(e.Error)
+	case *RouterEvent:
+		p.Kind = eventKindRouter
+		p.MessageID = e.MessageID
+		p.SessionID = e.SessionID
+		p.UserID = e.UserID
+		p.Content = e.Content
+		p.Source = e.Source
+		p.Error = errorMessage(e.Error)
+	case *LLMPublishedEvent:
+		p.Kind = eventKindLLM
+		p.Provider = e.ProviderName
+		p.Model = e.Model
+		p.Tokens = e.Tokens
+		p.Cost = e.Cost
+		p.DurationMs = e.Duration.Milliseconds()
+		p.Error = errorMessage(e.Error)
+	case *UserEvent:
+		p.Kind = eventKindUser
+		p.UserID = e.UserID
+		p.Email = e.Email
+		p.Channel = e.Channel
+	case *SessionEvent:
+		p.Kind = eventKindSession
+		p.SessionID = e.SessionID
+		p.UserID = e.UserID
+		p.MessageCount = e.MessageCount
+	case *SkillEvent:
+		p.Kind = eventKindSkill
+		p.Skill = e.SkillName
+		p.Input = e.Input
+		p.Output = e.Output
+		p.Error = errorMessage(e.Error)
+		p.DurationMs = e.Duration.Milliseconds()
+	case *TaskEvent:
+		p.Kind = eventKindTask
+		p.TaskID = e.TaskID
+		p.SessionID = e.SessionID
+		p.Skill = e.SkillName
+		p.Status = e.Status
+		p.Input = e.Input
+		p.Output = e.Output
+		p.Error = e.Error
+		p.DurationMs = e.Duration.Milliseconds()
+	case *ScheduleEvent:
+		p.Kind = eventKindSchedule
+		p.ScheduleID = e.ScheduleID
+		p.Skill = e.SkillName
+		p.SessionID = e.SessionID
+		p.Output = e.Output
+		p.Error = errorMessage(e.Error)
+		p.DurationMs = e.Duration.Milliseconds()
+	case *FeedbackEvent:
+		p.Kind = eventKindFeedback
+		p.MessageID = e.MessageID
+		p.SessionID = e.SessionID
+		p.UserID = e.UserID
+		p.Rating = e.Rating
+		p.Comment = e.Comment
+		p.Input = e.Prompt
+		p.Output = e.Response
+	case *UsageEvent:
+		p.Kind = eventKindUsage
+		p.UsageID = e.UsageID
+		p.UserID = e.UserID
+		p.WorkspaceID = e.WorkspaceID
+		p.SessionID = e.SessionID
+		p.Provider = e.ProviderName
+		p.Model = e.Model
+		p.InputTokens = e.InputTokens
+		p.OutputTokens = e.OutputTokens
+		p.Tokens = e.TotalTokens
+		p.Cost = e.Cost
+	}
+	return p
+}
+
+// decodeEvent rebuilds an event from its stored form.
+// Events are rebuilt as the struct they were published as, so handlers can treat
+// replayed and live events alike; errors come back as plain errors with the original message.
+// Event data comes back in its JSON form, e.g. as a map for a struct.
+func decodeEvent(record *entity.StoredEvent) (Event, error) {
+	var p eventPayload
+	if err := json.Unmarshal([]byte(record.Payload), &p); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	base := NewBaseEvent(record.Type, p.Data)
+	base.storedID = record.ID
+	base.timestamp = record.OccurredAt
+	if p.Metadata != nil {
+		base.metadata = p.Metadata
+	}
+	duration := time.Duration(p.DurationMs) * time.Millisecond
+
+	switch p.Kind {
+	case eventKindBase:
+		return base, nil
+	case eventKindConnector:
+		return &ConnectorEvent{BaseEvent: base, ConnectorName: p.Connector, UserID: p.UserID, ChannelID: p.ChannelID, Message: p.Content, Error: messageError(p.Error)}, nil
+	case eventKindRouter:
+		return &RouterEvent{BaseEvent: base, MessageID: p.MessageID, SessionID: p.SessionID, UserID: p.UserID, Content: p.Content, Source: p.Source, Error: messageError(p.Error)}, nil
+	case eventKindLLM:
+		return &LLMPublishedEvent{BaseEvent: base, ProviderName: p.Provider, Model: p.Model, Tokens: p.Tokens, Cost: p.Cost, Duration: duration, Error: messageError(p.Error)}, nil
+	case eventKindUser:
+		return &UserEvent{BaseEvent: base, UserID: p.UserID, Email: p.Email, Channel: p.Channel}, nil
+	case eventKindSession:
+		return &SessionEvent{BaseEvent: base, SessionID: p.SessionID, UserID: p.UserID, MessageCount: p.MessageCount}, nil
+	case eventKindSkill:
+		return &SkillEvent{BaseEvent: base, SkillName: p.Skill, Input: p.Input, Output: p.Output, Error: messageError(p.Error), Duration: duration}, nil
+	case eventKindTask:
+		return &TaskEvent{BaseEvent: base, TaskID: p.TaskID, SessionID: p.SessionID, SkillName: p.Skill, Status: p.Status, Input: p.Input, Output: p.Output, Error: p.Error, Duration: duration}, nil
+	case eventKindSchedule:
+		return &ScheduleEvent{BaseEvent: base, ScheduleID: p.ScheduleID, SkillName: p.Skill, SessionID: p.SessionID, Output: p.Output, Error: messageError(p.Error), Duration: duration}, nil
+	case eventKindFeedback:
+		return &FeedbackEvent{BaseEvent: base, MessageID: p.MessageID, SessionID: p.SessionID, UserID: p.UserID, Rating: p.Rating, Comment: p.Comment, Prompt: p.Input, Response: p.Output}, nil
+	case eventKindUsage:
+		return &UsageEvent{BaseEvent: base, UsageID: p.UsageID, UserID: p.UserID, WorkspaceID: p.WorkspaceID, SessionID: p.SessionID, ProviderName: p.Provider, Model: p.Model, InputTokens: p.InputTokens, OutputTokens: p.OutputTokens, TotalTokens: p.Tokens, Cost: p.Cost}, nil
+	}
+	return nil, fmt.Errorf("unknown event kind %q", p.Kind)
+}
+
+// errorMessage returns the message of an error, or "" for nil
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// messageError returns an error with a message, or nil for ""
+func messageError(message string) error {
+	if message == "" {
+		return nil
+	}
+	return errors.New(message)
+}
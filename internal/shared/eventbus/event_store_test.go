@@ -0,0 +1,366 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+	"github.com/atumaikin/nexflow/internal/domain/repository"
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// memoryEventRepository is an in-memory repository.EventRepository
+type memoryEventRepository struct {
+	mu     sync.Mutex
+	events []*entity.StoredEvent
+}
+
+func (r *memoryEventRepository) Append(ctx context.Context, events ...*entity.StoredEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range events {
+		event.Seq = int64(len(r.events) + 1)
+		r.events = append(r.events, event)
+	}
+	return nil
+}
+
+func (r *memoryEventRepository) GetByID(ctx context.Context, id string) (*entity.StoredEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range r.events {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memoryEventRepository) List(ctx context.Context, filter repository.EventFilter) ([]*entity.StoredEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*entity.StoredEvent
+	for _, event := range r.events {
+		switch {
+		case event.Seq <= filter.AfterSeq,
+			!filter.Since.IsZero() && event.OccurredAt.Before(filter.Since),
+			!filter.Until.IsZero() && !event.OccurredAt.Before(filter.Until),
+			len(filter.Types) > 0 && !slices.Contains(filter.Types, event.Type):
+			continue
+		}
+		result = append(result, event)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryEventRepository) ListBySubject(ctx context.Context, subject repository.EventSubject) ([]*entity.StoredEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*entity.StoredEvent
+	for _, event := range r.events {
+		if eventAbout(event, subject) {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryEventRepository) DeleteBySubject(ctx context.Context, subject repository.EventSubject) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	before := len(r.events)
+	r.events = slices.DeleteFunc(r.events, func(event *entity.StoredEvent) bool {
+		return eventAbout(event, subject)
+	})
+	return before - len(r.events), nil
+}
+
+// eventAbout reports whether the payload of a stored event names a user or session of a subject
+func eventAbout(event *entity.StoredEvent, subject repository.EventSubject) bool {
+	var payload eventPayload
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return false
+	}
+	return (payload.UserID != "" && slices.Contains(subject.UserIDs, payload.UserID)) ||
+		(payload.SessionID != "" && slices.Contains(subject.SessionIDs, payload.SessionID))
+}
+
+func (r *memoryEventRepository) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func collectReplay(t *testing.T, store EventStore, from, to time.Time, types []string) []Event {
+	t.Helper()
+	var events []Event
+	err := store.Replay(context.Background(), from, to, types, func(ctx context.Context, event Event) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	return events
+}
+
+func TestDatabaseEventStore_RoundTrip(t *testing.T) {
+	store := NewDatabaseEventStore(&memoryEventRepository{}, logging.NewNoopLogger())
+
+	routed := NewRouterEvent(EventRouterError, "msg-1", "session-1", "user-1", "hello", "telegram", errors.New("no route"))
+	completed := NewScheduleEvent(EventScheduleCompleted, "schedule-1", "digest", "session-2", "done", nil, 1500*time.Millisecond)
+	custom := NewBaseEvent("custom.event", map[string]interface{}{"answer": 42})
+	custom.SetMetadataValue("origin", "test")
+
+	if err := store.Append(context.Background(), []Event{routed, completed, custom}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	events := collectReplay(t, store, time.Time{}, time.Time{}, nil)
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 replayed events, got %d", len(events))
+	}
+
+	router, ok := events[0].(*RouterEvent)
+	if !ok {
+		t.Fatalf("Expected *RouterEvent, got %T", events[0])
+	}
+	if router.Type() != EventRouterError || router.MessageID != "msg-1" || router.Source != "telegram" || router.Content != "hello" {
+		t.Errorf("Unexpected router event: %+v", router)
+	}
+	if router.Error == nil || router.Error.Error() != "no route" {
+		t.Errorf("Expected error 'no route', got %v", router.Error)
+	}
+	if !router.Timestamp().Equal(routed.Timestamp()) {
+		t.Errorf("Expected timestamp %v, got %v", routed.Timestamp(), router.Timestamp())
+	}
+
+	schedule, ok := events[1].(*ScheduleEvent)
+	if !ok {
+		t.Fatalf("Expected *ScheduleEvent, got %T", events[1])
+	}
+	if schedule.ScheduleID != "schedule-1" || schedule.Duration != 1500*time.Millisecond || schedule.Error != nil {
+		t.Errorf("Unexpected schedule event: %+v", schedule)
+	}
+
+	base, ok := events[2].(*BaseEvent)
+	if !ok {
+		t.Fatalf("Expected *BaseEvent, got %T", events[2])
+	}
+	if data, _ := base.Data().(map[string]interface{}); data["answer"] != float64(42) {
+		t.Errorf("Expected data answer 42, got %v", base.Data())
+	}
+	if origin, _ := base.GetMetadataValue("origin"); origin != "test" {
+		t.Errorf("Expected metadata origin 'test', got %v", origin)
+	}
+}
+
+func TestDatabaseEventStore_RoundTripFeedback(t *testing.T) {
+	store := NewDatabaseEventStore(&memoryEventRepository{}, logging.NewNoopLogger())
+
+	rated := NewFeedbackEvent(EventFeedbackReceived, "msg-1", "session-1", "user-1", "down", "Wrong date", "When is Easter?", "On Monday.")
+	if err := store.Append(context.Background(), []Event{rated}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	events := collectReplay(t, store, time.Time{}, time.Time{}, nil)
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 replayed event, got %d", len(events))
+	}
+	feedback, ok := events[0].(*FeedbackEvent)
+	if !ok {
+		t.Fatalf("Expected *FeedbackEvent, got %T", events[0])
+	}
+	if feedback.MessageID != "msg-1" || feedback.SessionID != "session-1" || feedback.UserID != "user-1" ||
+		feedback.Rating != "down" || feedback.Comment != "Wrong date" || feedback.Prompt != "When is Easter?" || feedback.Response != "On Monday." {
+		t.Errorf("Unexpected feedback event: %+v", feedback)
+	}
+}
+
+func TestDatabaseEventStore_RoundTripUsage(t *testing.T) {
+	store := NewDatabaseEventStore(&memoryEventRepository{}, logging.NewNoopLogger())
+
+	used := NewUsageEvent(EventLLMUsage, "user-1", "support", "session-1", "openai", "gpt-4o", 120, 30, 150, 0.002)
+	if err := store.Append(context.Background(), []Event{used}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	events := collectReplay(t, store, time.Time{}, time.Time{}, nil)
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 replayed event, got %d", len(events))
+	}
+	usage, ok := events[0].(*UsageEvent)
+	if !ok {
+		t.Fatalf("Expected *UsageEvent, got %T", events[0])
+	}
+	if usage.UsageID == "" || usage.UsageID != used.UsageID {
+		t.Errorf("Expected usage ID %q, got %q", used.UsageID, usage.UsageID)
+	}
+	if usage.UserID != "user-1" || usage.WorkspaceID != "support" || usage.SessionID != "session-1" || usage.ProviderName != "openai" ||
+		usage.Model != "gpt-4o" || usage.InputTokens != 120 || usage.OutputTokens != 30 || usage.TotalTokens != 150 || usage.Cost != 0.002 {
+		t.Errorf("Unexpected usage event: %+v", usage)
+	}
+}
+
+func TestDatabaseEventStore_Get(t *testing.T) {
+	store := NewDatabaseEventStore(&memoryEventRepository{}, logging.NewNoopLogger())
+	received := NewConnectorEvent(EventConnectorMessage, "telegram", "user-1", "chat-1", "hi", nil)
+	if err := store.Append(context.Background(), []Event{received}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	replayed := collectReplay(t, store, time.Time{}, time.Time{}, nil)
+	id := replayed[0].(*ConnectorEvent).StoredID()
+	if id == "" || received.StoredID() != "" {
+		t.Fatalf("Expected only the stored event to have a stored ID, got %q and %q", id, received.StoredID())
+	}
+
+	event, err := store.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	connector, ok := event.(*ConnectorEvent)
+	if !ok {
+		t.Fatalf("Expected *ConnectorEvent, got %T", event)
+	}
+	if connector.StoredID() != id || connector.ConnectorName != "telegram" || connector.ChannelID != "chat-1" || connector.Message != "hi" {
+		t.Errorf("Unexpected connector event: %+v", connector)
+	}
+
+	if _, err := store.Get(context.Background(), "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseEventStore_ReplayFilters(t *testing.T) {
+	repo := &memoryEventRepository{}
+	store := NewDatabaseEventStore(repo, logging.NewNoopLogger())
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var events []Event
+	for i := 0; i < replayBatchSize+10; i++ {
+		event := NewSessionEvent(EventSessionCreated, "session", "user", i)
+		event.timestamp = start.Add(time.Duration(i) * time.Second)
+		events = append(events, event)
+	}
+	failed := NewTaskEvent(EventTaskFailed, "task-1", "session-1", "echo", "failed", "", "", "boom")
+	failed.timestamp = start
+	events = append(events, failed)
+
+	if err := store.Append(context.Background(), events); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	// Replays span several batches
+	all := collectReplay(t, store, time.Time{}, time.Time{}, []string{EventSessionCreated})
+	if len(all) != replayBatchSize+10 {
+		t.Fatalf("Expected %d session events, got %d", replayBatchSize+10, len(all))
+	}
+	for i, event := range all {
+		if count := event.(*SessionEvent).MessageCount; count != i {
+			t.Fatalf("Expected event %d in order, got message count %d", i, count)
+		}
+	}
+
+	window := collectReplay(t, store, start.Add(10*time.Second), start.Add(20*time.Second), nil)
+	if len(window) != 10 {
+		t.Errorf("Expected 10 events in the time window, got %d", len(window))
+	}
+
+	tasks := collectReplay(t, store, time.Time{}, time.Time{}, []string{EventTaskFailed})
+	if len(tasks) != 1 || tasks[0].(*TaskEvent).Error != "boom" {
+		t.Errorf("Expected the failed task event, got %v", tasks)
+	}
+}
+
+func TestDatabaseEventStore_ReplayStopsOnHandlerError(t *testing.T) {
+	store := NewDatabaseEventStore(&memoryEventRepository{}, logging.NewNoopLogger())
+	events := []Event{
+		NewUserEvent(EventUserCreated, "user-1", "a@example.com", "web"),
+		NewUserEvent(EventUserCreated, "user-2", "b@example.com", "web"),
+	}
+	if err := store.Append(context.Background(), events); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := store.Replay(context.Background(), time.Time{}, time.Time{}, nil, func(ctx context.Context, event Event) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected handler error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected replay to stop after 1 event, got %d", calls)
+	}
+}
+
+func TestEventBus_PersistsPublishedEvents(t *testing.T) {
+	repo := &memoryEventRepository{}
+	eb := NewEventBus(&EventBusConfig{
+		BatchSize:     10,
+		FlushInterval: 10 * time.Millisecond,
+		Logger:        logging.NewNoopLogger(),
+		Store:         NewDatabaseEventStore(repo, logging.NewNoopLogger()),
+	})
+	if !eb.HasStore() {
+		t.Fatal("Expected event bus with a store")
+	}
+	if err := eb.Start(); err != nil {
+		t.Fatalf("Failed to start event bus: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		eb.Publish(NewSessionEvent(EventSessionUpdated, "session-1", "user-1", i))
+	}
+	eb.Publish(NewConnectorEvent(EventConnectorMessage, "telegram", "user-1", "chat-1", "hi", nil))
+
+	if err := eb.Stop(); err != nil {
+		t.Fatalf("Failed to stop event bus: %v", err)
+	}
+	if repo.count() != 6 {
+		t.Fatalf("Expected 6 stored events, got %d", repo.count())
+	}
+	if persisted := eb.Metrics().EventsPersisted.Get(); persisted != 6 {
+		t.Errorf("Expected 6 persisted events in metrics, got %d", persisted)
+	}
+
+	var counts []int
+	err := eb.Replay(context.Background(), time.Time{}, time.Time{}, []string{EventSessionUpdated}, func(ctx context.Context, event Event) error {
+		counts = append(counts, event.(*SessionEvent).MessageCount)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if !slices.Equal(counts, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("Expected events replayed in publication order, got %v", counts)
+	}
+}
+
+func TestEventBus_ReplayWithoutStore(t *testing.T) {
+	eb := NewEventBus(DefaultConfig())
+
+	err := eb.Replay(context.Background(), time.Time{}, time.Time{}, nil, func(ctx context.Context, event Event) error {
+		return nil
+	})
+	if !errors.Is(err, ErrNoEventStore) {
+		t.Errorf("Expected ErrNoEventStore, got %v", err)
+	}
+	if _, err := eb.StoredEvent(context.Background(), "event-1"); !errors.Is(err, ErrNoEventStore) {
+		t.Errorf("Expected ErrNoEventStore from StoredEvent, got %v", err)
+	}
+	if eb.HasStore() {
+		t.Error("Expected event bus without a store")
+	}
+}
@@ -2,9 +2,11 @@ package eventbus
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/atumaikin/nexflow/internal/domain/repository"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
 	"github.com/atumaikin/nexflow/internal/shared/metrics"
 )
@@ -23,19 +25,44 @@ type EventHandler func(ctx context.Context, event Event) error
 // EventSubscription represents a subscription to event types
 type EventSubscription struct {
 	ID      string
-	Types   []string
+	Name    string   // Name recorded in dead letters, see SubscribeOptions
+	Types   []string // Event types and patterns, see MatchEventType
+	Filter  EventFilter
+	Retry   RetryPolicy
 	Handler EventHandler
-	cancel  context.CancelFunc
+
+	// BufferSize is the number of events queued for the handler
+	BufferSize int
+	// Overflow decides what happens to events that do not fit into the queue
+	Overflow OverflowPolicy
+
+	seq      uint64
+	queue    chan Event
+	done     chan struct{}
+	stopOnce sync.Once
+	metrics  subscriptionMetrics
+	cancel   context.CancelFunc
 }
 
 // EventBusMetrics holds all metrics for EventBus
 type EventBusMetrics struct {
-	EventsPublished     *metrics.Counter
-	EventsProcessed     *metrics.Counter
-	EventsDropped       *metrics.Counter
-	EventsFailed        *metrics.Counter
-	SubscriptionsActive *metrics.Counter
-	ProcessingDuration  *metrics.Histogram
+	registry *metrics.MetricsRegistry
+
+	EventsPublished        *metrics.Counter
+	EventsProcessed        *metrics.Counter
+	EventsDropped          *metrics.Counter
+	SubscriberDrops        *metrics.Counter
+	EventsFailed           *metrics.Counter
+	HandlerRetries         *metrics.Counter
+	EventsDeadLettered     *metrics.Counter
+	DeadLettersRedelivered *metrics.Counter
+	EventsPersisted        *metrics.Counter
+	PersistFailures        *metrics.Counter
+	EventsSent             *metrics.Counter
+	EventsReceived         *metrics.Counter
+	TransportFailures      *metrics.Counter
+	SubscriptionsActive    *metrics.Counter
+	ProcessingDuration     *metrics.Histogram
 }
 
 // NewEventBusMetrics creates a new EventBusMetrics instance
@@ -44,19 +71,36 @@ func NewEventBusMetrics() *EventBusMetrics {
 	buckets := metrics.DefaultBuckets()
 
 	return &EventBusMetrics{
-		EventsPublished:     registry.GetCounter("eventbus_events_published_total"),
-		EventsProcessed:     registry.GetCounter("eventbus_events_processed_total"),
-		EventsDropped:       registry.GetCounter("eventbus_events_dropped_total"),
-		EventsFailed:        registry.GetCounter("eventbus_events_failed_total"),
-		SubscriptionsActive: registry.GetCounter("eventbus_subscriptions_active"),
-		ProcessingDuration:  registry.GetHistogram("eventbus_processing_duration_seconds", buckets),
+		registry: registry,
+
+		EventsPublished:        registry.GetCounter("eventbus_events_published_total"),
+		EventsProcessed:        registry.GetCounter("eventbus_events_processed_total"),
+		EventsDropped:          registry.GetCounter("eventbus_events_dropped_total"),
+		SubscriberDrops:        registry.GetCounter("eventbus_subscriber_events_dropped_total"),
+		EventsFailed:           registry.GetCounter("eventbus_events_failed_total"),
+		HandlerRetries:         registry.GetCounter("eventbus_handler_retries_total"),
+		EventsDeadLettered:     registry.GetCounter("eventbus_events_dead_lettered_total"),
+		DeadLettersRedelivered: registry.GetCounter("eventbus_dead_letters_redelivered_total"),
+		EventsPersisted:        registry.GetCounter("eventbus_events_persisted_total"),
+		PersistFailures:        registry.GetCounter("eventbus_persist_failures_total"),
+		EventsSent:             registry.GetCounter("eventbus_events_sent_total"),
+		EventsReceived:         registry.GetCounter("eventbus_events_received_total"),
+		TransportFailures:      registry.GetCounter("eventbus_transport_failures_total"),
+		SubscriptionsActive:    registry.GetCounter("eventbus_subscriptions_active"),
+		ProcessingDuration:     registry.GetHistogram("eventbus_processing_duration_seconds", buckets),
 	}
 }
 
+// Registry returns the registry holding the EventBus metrics
+func (m *EventBusMetrics) Registry() *metrics.MetricsRegistry {
+	return m.registry
+}
+
 // EventBus implements a publish-subscribe pattern for internal events
 type EventBus struct {
 	mu            sync.RWMutex
 	subscriptions map[string][]*EventSubscription
+	patterns      map[string][]*EventSubscription // Subscriptions to event type patterns such as "connector.*"
 	handlers      map[string][]EventHandler
 	logger        logging.Logger
 	eventChannel  chan Event
@@ -68,8 +112,15 @@ type EventBus struct {
 	flushInterval time.Duration
 	eventBuffer   []Event
 	bufferMu      sync.Mutex
+	flushMu       sync.Mutex
 	started       bool
 	metrics       *EventBusMetrics
+	store         EventStore
+	transport     Transport
+	retry         RetryPolicy
+	deadLetters   repository.DeadLetterRepository
+	subBuffer     int
+	overflow      OverflowPolicy
 }
 
 // EventBusConfig contains configuration options for the EventBus
@@ -78,10 +129,24 @@ type EventBusConfig struct {
 	BatchSize int
 	// FlushInterval is the maximum time to wait before flushing events
 	FlushInterval time.Duration
+	// BufferSize is the number of published events waiting to be batched; further events are dropped (1000 if not positive)
+	BufferSize int
+	// SubscriberBufferSize is the default number of events queued per subscription (DefaultSubscriberBufferSize if not positive)
+	SubscriberBufferSize int
+	// Overflow is the default policy for events that do not fit into the queue of a subscription (OverflowDropNewest if empty)
+	Overflow OverflowPolicy
 	// Logger is the logger to use
 	Logger logging.Logger
 	// Metrics is the metrics to use (optional)
 	Metrics *EventBusMetrics
+	// Store persists every published event so it can be replayed (optional)
+	Store EventStore
+	// Transport carries events to and from other instances (optional; in-process delivery only if nil)
+	Transport Transport
+	// Retry is the retry policy of failing handlers (DefaultRetryPolicy if nil)
+	Retry *RetryPolicy
+	// DeadLetters stores the events whose handlers kept failing (in memory if nil)
+	DeadLetters repository.DeadLetterRepository
 }
 
 // DefaultConfig returns the default configuration for EventBus
@@ -113,17 +178,49 @@ func NewEventBus(config *EventBusConfig) *EventBus {
 		metrics = NewEventBusMetrics()
 	}
 
+	retry := DefaultRetryPolicy()
+	if config.Retry != nil {
+		retry = *config.Retry
+	}
+
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 1000
+	}
+
+	subBuffer := config.SubscriberBufferSize
+	if subBuffer <= 0 {
+		subBuffer = DefaultSubscriberBufferSize
+	}
+
+	overflow := config.Overflow
+	if overflow == "" {
+		overflow = OverflowDropNewest
+	}
+
+	deadLetters := config.DeadLetters
+	if deadLetters == nil {
+		deadLetters = NewMemoryDeadLetterStore(DefaultDeadLetterCapacity)
+	}
+
 	return &EventBus{
 		subscriptions: make(map[string][]*EventSubscription),
+		patterns:      make(map[string][]*EventSubscription),
 		handlers:      make(map[string][]EventHandler),
 		logger:        config.Logger,
-		eventChannel:  make(chan Event, 1000),
+		eventChannel:  make(chan Event, bufferSize),
 		ctx:           ctx,
 		cancel:        cancel,
 		batchSize:     config.BatchSize,
 		flushInterval: config.FlushInterval,
 		eventBuffer:   make([]Event, 0, config.BatchSize),
 		metrics:       metrics,
+		store:         config.Store,
+		transport:     config.Transport,
+		retry:         retry,
+		deadLetters:   deadLetters,
+		subBuffer:     subBuffer,
+		overflow:      overflow,
 	}
 }
 
@@ -139,6 +236,13 @@ func (eb *EventBus) Start() error {
 		return nil
 	}
 
+	// Receive the events of other instances
+	if eb.transport != nil {
+		if err := eb.transport.Receive(eb.deliverRemote); err != nil {
+			return fmt.Errorf("failed to receive events from transport: %w", err)
+		}
+	}
+
 	// Start event processor
 	eb.wg.Add(1)
 	go eb.processEvents()
@@ -155,9 +259,9 @@ func (eb *EventBus) Start() error {
 // Stop stops the event bus gracefully
 func (eb *EventBus) Stop() error {
 	eb.mu.Lock()
-	defer eb.mu.Unlock()
 
 	if !eb.started {
+		eb.mu.Unlock()
 		return nil
 	}
 
@@ -167,56 +271,154 @@ func (eb *EventBus) Stop() error {
 	// Close event channel
 	close(eb.eventChannel)
 
+	eb.started = false
+
+	// Dispatching takes the read lock, so the processing goroutines must be able to finish a flush
+	eb.mu.Unlock()
+
 	// Wait for all goroutines to finish
 	eb.wg.Wait()
 
+	// Keep the events still queued so that they are persisted and dispatched too
+	eb.bufferMu.Lock()
+	for event := range eb.eventChannel {
+		eb.eventBuffer = append(eb.eventBuffer, event)
+	}
+	eb.bufferMu.Unlock()
+
 	// Flush remaining events
 	eb.flushBuffer()
 
-	eb.started = false
+	// Let the subscription workers finish the queued events and exit
+	eb.mu.RLock()
+	for _, index := range []map[string][]*EventSubscription{eb.subscriptions, eb.patterns} {
+		for _, subs := range index {
+			for _, sub := range subs {
+				sub.stop()
+			}
+		}
+	}
+	eb.mu.RUnlock()
+
+	if eb.transport != nil {
+		if err := eb.transport.Close(); err != nil {
+			eb.logger.Error("failed to close event transport", "error", err)
+		}
+	}
+
 	eb.logger.Info("event bus stopped")
 	return nil
 }
 
-// Subscribe subscribes to events of specific types
+// Subscribe subscribes to events of specific types.
+// Event types may be patterns such as "connector.*" (see MatchEventType).
 //
 // Parameters:
-//   - eventTypes: List of event types to subscribe to
+//   - eventTypes: List of event types and patterns to subscribe to
 //   - handler: Function to handle events
 //
 // Returns:
 //   - *EventSubscription: Subscription that can be used to unsubscribe
 func (eb *EventBus) Subscribe(eventTypes []string, handler EventHandler) *EventSubscription {
+	return eb.SubscribeFiltered(eventTypes, EventFilter{}, handler)
+}
+
+// SubscribeFiltered subscribes to events of specific types that match a filter.
+// The filter is evaluated before the handler is started, so handlers only see events
+// of e.g. one user or connector. An event matching several of the event types and
+// patterns is passed to the handler once.
+//
+// Parameters:
+//   - eventTypes: List of event types and patterns to subscribe to
+//   - filter: Attributes the events must have (the zero value matches every event)
+//   - handler: Function to handle events
+//
+// Returns:
+//   - *EventSubscription: Subscription that can be used to unsubscribe
+func (eb *EventBus) SubscribeFiltered(eventTypes []string, filter EventFilter, handler EventHandler) *EventSubscription {
+	return eb.SubscribeWithOptions(eventTypes, SubscribeOptions{Filter: filter}, handler)
+}
+
+// SubscribeWithOptions subscribes to events of specific types with a name, filter,
+// retry policy and queue. Events are queued for the handler, which handles them one at a
+// time in dispatch order; events that do not fit into the queue are handled according to
+// the overflow policy. A handler that returns an error is called again after a backoff;
+// when the last attempt fails, the event is stored as a dead letter under the subscription name.
+//
+// Parameters:
+//   - eventTypes: List of event types and patterns to subscribe to
+//   - opts: Name, filter, retry policy and queue of the subscription
+//   - handler: Function to handle events
+//
+// Returns:
+//   - *EventSubscription: Subscription that can be used to unsubscribe
+func (eb *EventBus) SubscribeWithOptions(eventTypes []string, opts SubscribeOptions, handler EventHandler) *EventSubscription {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
 	subID := eb.generateSubID()
 	sub := &EventSubscription{
 		ID:      subID,
+		Name:    opts.Name,
 		Types:   eventTypes,
+		Filter:  opts.Filter,
+		Retry:   eb.retry,
 		Handler: handler,
+		seq:     eb.subIDCounter,
+	}
+	if sub.Name == "" {
+		sub.Name = subID
+	}
+	if opts.Retry != nil {
+		sub.Retry = *opts.Retry
+	}
+	sub.BufferSize = opts.BufferSize
+	if sub.BufferSize <= 0 {
+		sub.BufferSize = eb.subBuffer
+	}
+	sub.Overflow = opts.Overflow
+	if sub.Overflow == "" {
+		sub.Overflow = eb.overflow
 	}
+	sub.queue = make(chan Event, sub.BufferSize)
+	sub.done = make(chan struct{})
+	sub.metrics = eb.metrics.subscriptionMetrics(sub.Name)
+	go eb.runSubscription(sub)
 
-	// Add subscription for each event type
+	// Add subscription for each event type or pattern
 	for _, eventType := range eventTypes {
-		eb.subscriptions[eventType] = append(eb.subscriptions[eventType], sub)
+		index := eb.subscriptionIndex(eventType)
+		index[eventType] = append(index[eventType], sub)
 	}
 
 	eb.metrics.SubscriptionsActive.Inc()
 	eb.logger.Info("subscription created",
 		"id", subID,
+		"name", sub.Name,
 		"types", eventTypes,
+		"filtered", !opts.Filter.IsZero(),
 		"total_subscriptions", eb.countTotalSubscriptions(),
 	)
 	return sub
 }
 
+// subscriptionIndex returns the map holding the subscriptions to an event type or pattern
+func (eb *EventBus) subscriptionIndex(eventType string) map[string][]*EventSubscription {
+	if IsEventTypePattern(eventType) {
+		return eb.patterns
+	}
+	return eb.subscriptions
+}
+
 // countTotalSubscriptions returns the total number of active subscriptions
 func (eb *EventBus) countTotalSubscriptions() int {
 	total := 0
 	for _, subs := range eb.subscriptions {
 		total += len(subs)
 	}
+	for _, subs := range eb.patterns {
+		total += len(subs)
+	}
 	return total
 }
 
@@ -232,17 +434,22 @@ func (eb *EventBus) Unsubscribe(sub *EventSubscription) {
 		return
 	}
 
-	// Remove subscription from all event types
+	// Remove subscription from all event types and patterns
 	for _, eventType := range sub.Types {
-		subs := eb.subscriptions[eventType]
+		index := eb.subscriptionIndex(eventType)
+		subs := index[eventType]
 		for i, s := range subs {
 			if s.ID == sub.ID {
-				eb.subscriptions[eventType] = append(subs[:i], subs[i+1:]...)
+				index[eventType] = append(subs[:i], subs[i+1:]...)
 				break
 			}
 		}
+		if len(index[eventType]) == 0 && IsEventTypePattern(eventType) {
+			delete(index, eventType)
+		}
 	}
 
+	sub.stop()
 	eb.metrics.SubscriptionsActive.Add(-1) // Decrement counter
 	eb.logger.Info("subscription removed",
 		"id", sub.ID,
@@ -312,11 +519,13 @@ func (eb *EventBus) processEvents() {
 			eb.bufferMu.Lock()
 			eb.eventBuffer = append(eb.eventBuffer, event)
 
+			full := len(eb.eventBuffer) >= eb.batchSize
+			eb.bufferMu.Unlock()
+
 			// Flush if buffer is full
-			if len(eb.eventBuffer) >= eb.batchSize {
+			if full {
 				eb.flushBuffer()
 			}
-			eb.bufferMu.Unlock()
 		}
 	}
 }
@@ -338,8 +547,12 @@ func (eb *EventBus) flushBatches() {
 	}
 }
 
-// flushBuffer processes all buffered events
+// flushBuffer persists and processes all buffered events.
+// Flushes are serialized so that batches reach the event store in publication order.
 func (eb *EventBus) flushBuffer() {
+	eb.flushMu.Lock()
+	defer eb.flushMu.Unlock()
+
 	eb.bufferMu.Lock()
 	events := eb.eventBuffer
 	eb.eventBuffer = make([]Event, 0, eb.batchSize)
@@ -351,6 +564,9 @@ func (eb *EventBus) flushBuffer() {
 
 	eb.logger.Debug("flushing events", "count", len(events))
 
+	eb.persist(events)
+	eb.send(events)
+
 	// Process each event with metrics
 	for _, event := range events {
 		eb.dispatch(event)
@@ -358,50 +574,147 @@ func (eb *EventBus) flushBuffer() {
 	}
 }
 
-// dispatch dispatches an event to all subscribed handlers
-func (eb *EventBus) dispatch(event Event) {
-	eb.mu.RLock()
-	defer eb.mu.RUnlock()
+// persist appends a batch of events to the event store, if one is configured.
+// A failed write is logged and counted; the events are still dispatched.
+func (eb *EventBus) persist(events []Event) {
+	if eb.store == nil {
+		return
+	}
 
-	// Get all subscriptions for this event type
-	subs := eb.subscriptions[event.Type()]
+	// The bus context is already cancelled while Stop flushes the last batch
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := eb.store.Append(ctx, events); err != nil {
+		eb.metrics.PersistFailures.Inc()
+		eb.logger.Error("failed to persist events",
+			"count", len(events),
+			"error", err,
+		)
+		return
+	}
+	eb.metrics.EventsPersisted.Add(int64(len(events)))
+}
 
-	// Create a copy of handlers to avoid holding the lock during execution
-	handlers := make([]EventHandler, len(subs))
-	for i, sub := range subs {
-		handlers[i] = sub.Handler
+// send passes a batch of events to the transport, if one is configured.
+// A failed send is logged and counted; the events are still dispatched locally.
+func (eb *EventBus) send(events []Event) {
+	if eb.transport == nil {
+		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := eb.transport.Send(ctx, events); err != nil {
+		eb.metrics.TransportFailures.Inc()
+		eb.logger.Error("failed to send events to transport",
+			"count", len(events),
+			"error", err,
+		)
+		return
+	}
+	eb.metrics.EventsSent.Add(int64(len(events)))
+}
+
+// deliverRemote dispatches an event received from another instance to the local subscribers
+func (eb *EventBus) deliverRemote(event Event) {
+	if event == nil {
+		return
+	}
+	eb.metrics.EventsReceived.Inc()
+	eb.dispatch(event)
+	eb.metrics.EventsProcessed.Inc()
+}
+
+// Replay calls handler, in publication order, for the stored events that occurred
+// at or after from and before to and have one of the given types.
+// A zero from or to leaves that end of the range open; empty types select all events.
+// Replayed events are passed to handler only, not to the subscribers of the event bus.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - from: Earliest time of the replayed events
+//   - to: Time before which the replayed events occurred
+//   - types: Types of the replayed events
+//   - handler: Function called with each event; an error stops the replay
+//
+// Returns:
+//   - error: ErrNoEventStore without an event store, or the first read or handler error
+func (eb *EventBus) Replay(ctx context.Context, from, to time.Time, types []string, handler EventHandler) error {
+	if eb.store == nil {
+		return ErrNoEventStore
+	}
+	return eb.store.Replay(ctx, from, to, types, handler)
+}
+
+// StoredEvent returns an event from the event store by the ID of its stored form
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - id: ID of the stored event
+//
+// Returns:
+//   - Event: Event rebuilt as the struct it was published as
+//   - error: ErrNoEventStore without an event store, repository.ErrNotFound for an unknown ID,
+//     or a read error
+func (eb *EventBus) StoredEvent(ctx context.Context, id string) (Event, error) {
+	if eb.store == nil {
+		return nil, ErrNoEventStore
+	}
+	return eb.store.Get(ctx, id)
+}
+
+// HasStore reports whether published events are persisted to an event store
+func (eb *EventBus) HasStore() bool {
+	return eb.store != nil
+}
+
+// dispatch queues an event for all subscribed handlers
+func (eb *EventBus) dispatch(event Event) {
+	selected := eb.selectSubscriptions(event)
+
 	eb.logger.Debug("dispatching event",
 		"type", event.Type(),
-		"handlers_count", len(handlers),
+		"handlers_count", len(selected),
 	)
 
-	// Execute each handler with metrics
-	for _, handler := range handlers {
-		go func(h EventHandler) {
-			ctx, cancel := context.WithTimeout(eb.ctx, 30*time.Second)
-			defer cancel()
-
-			err := metrics.RecordDurationWithError(eb.metrics.ProcessingDuration, func() error {
-				return h(ctx, event)
-			})
-
-			if err != nil {
-				eb.metrics.EventsFailed.Inc()
-				eb.logger.Error("event handler failed",
-					"type", event.Type(),
-					"error", err,
-				)
-			}
-		}(handler)
+	// Queue the event for each handler; the lock is released, as a blocking queue may wait for a while
+	for _, sub := range selected {
+		eb.enqueue(sub, event)
+	}
+}
+
+// selectSubscriptions returns the subscriptions an event is dispatched to
+func (eb *EventBus) selectSubscriptions(event Event) []*EventSubscription {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	// Get all subscriptions for this event type and the patterns it matches
+	subs := eb.subscriptions[event.Type()]
+	for pattern, patternSubs := range eb.patterns {
+		if MatchEventType(pattern, event.Type()) {
+			subs = append(subs[:len(subs):len(subs)], patternSubs...)
+		}
 	}
+
+	// A subscription is called once per event, however many of its types match.
+	selected := make([]*EventSubscription, 0, len(subs))
+	seen := make(map[*EventSubscription]bool, len(subs))
+	for _, sub := range subs {
+		if seen[sub] || !sub.Filter.Matches(event) {
+			continue
+		}
+		seen[sub] = true
+		selected = append(selected, sub)
+	}
+	return selected
 }
 
 // generateSubID generates a unique subscription ID
 func (eb *EventBus) generateSubID() string {
 	eb.subIDCounter++
-	return string(rune(eb.subIDCounter))
+	return fmt.Sprintf("sub-%d", eb.subIDCounter)
 }
 
 // AddHandler adds a handler for a specific event type (convenience method)
@@ -437,10 +750,11 @@ func (eb *EventBus) RemoveHandler(eventType string, handler EventHandler) {
 	}
 }
 
-// GetSubscriptionCount returns the number of subscriptions for a specific event type
+// GetSubscriptionCount returns the number of subscriptions for a specific event type or pattern.
+// Subscriptions to patterns are only counted under the pattern itself.
 //
 // Parameters:
-//   - eventType: Type of event
+//   - eventType: Type of event or pattern
 //
 // Returns:
 //   - int: Number of subscriptions
@@ -448,10 +762,10 @@ func (eb *EventBus) GetSubscriptionCount(eventType string) int {
 	eb.mu.RLock()
 	defer eb.mu.RUnlock()
 
-	return len(eb.subscriptions[eventType])
+	return len(eb.subscriptionIndex(eventType)[eventType])
 }
 
-// GetAllSubscriptionCounts returns a map of event types to subscription counts
+// GetAllSubscriptionCounts returns a map of event types and patterns to subscription counts
 //
 // Returns:
 //   - map[string]int: Map of event type to subscription count
@@ -463,5 +777,16 @@ func (eb *EventBus) GetAllSubscriptionCounts() map[string]int {
 	for eventType, subs := range eb.subscriptions {
 		counts[eventType] = len(subs)
 	}
+	for pattern, subs := range eb.patterns {
+		counts[pattern] = len(subs)
+	}
 	return counts
 }
+
+// Metrics returns the event bus metrics
+//
+// Returns:
+//   - *EventBusMetrics: Event bus metrics
+func (eb *EventBus) Metrics() *EventBusMetrics {
+	return eb.metrics
+}
@@ -444,4 +444,10 @@ func TestSpecificEventTypes(t *testing.T) {
 	if taskEvent.Type() != EventTaskCreated {
 		t.Errorf("Expected type %s, got %s", EventTaskCreated, taskEvent.Type())
 	}
+
+	// Test schedule event
+	scheduleEvent := NewScheduleEvent(EventScheduleTriggered, "sched123", "weather", "sess456", "", nil, 0)
+	if scheduleEvent.Type() != EventScheduleTriggered {
+		t.Errorf("Expected type %s, got %s", EventScheduleTriggered, scheduleEvent.Type())
+	}
 }
@@ -5,11 +5,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
 	"github.com/atumaikin/nexflow/internal/shared/logging"
 )
 
 // BaseEvent provides a base implementation for events
 type BaseEvent struct {
+	storedID  string
 	eventType string
 	timestamp time.Time
 	metadata  map[string]interface{}
@@ -39,6 +41,12 @@ func (e *BaseEvent) Type() string {
 	return e.eventType
 }
 
+// StoredID returns the ID of the event in the event store, or "" for an event that was not
+// read from the store
+func (e *BaseEvent) StoredID() string {
+	return e.storedID
+}
+
 // Timestamp returns the event timestamp
 func (e *BaseEvent) Timestamp() time.Time {
 	return e.timestamp
@@ -100,6 +108,7 @@ const (
 	EventRouterStopped = "router.stopped"
 	EventRouterError   = "router.error"
 	EventRouterMessage = "router.message"
+	EventRouterHandoff = "router.handoff" // User message forwarded to the operator of a handed-off session
 
 	// Orchestrator events
 	EventOrchestratorStarted = "orchestrator.started"
@@ -111,6 +120,7 @@ const (
 	EventLLMRequest  = "llm.request"
 	EventLLMResponse = "llm.response"
 	EventLLMError    = "llm.error"
+	EventLLMUsage    = "llm.usage" // Tokens and cost of a completed LLM call, for billing
 
 	// User events
 	EventUserCreated = "user.created"
@@ -132,6 +142,15 @@ const (
 	EventTaskStarted   = "task.started"
 	EventTaskCompleted = "task.completed"
 	EventTaskFailed    = "task.failed"
+	EventTaskCancelled = "task.cancelled"
+
+	// Schedule events
+	EventScheduleTriggered = "schedule.triggered"
+	EventScheduleCompleted = "schedule.completed"
+	EventScheduleFailed    = "schedule.failed"
+
+	// Feedback events
+	EventFeedbackReceived = "feedback.received" // User rated an assistant reply
 )
 
 // ConnectorEvent represents an event from a connector
@@ -204,6 +223,40 @@ func NewLLMEvent(eventType, providerName, model string, tokens int, cost float64
 	}
 }
 
+// UsageEvent records the tokens and cost of a completed LLM call for the user and workspace
+// of its session. UsageID identifies the call, so that consumers persisting usage for billing
+// can recognize a redelivered event.
+type UsageEvent struct {
+	*BaseEvent
+	UsageID      string
+	UserID       string
+	WorkspaceID  string
+	SessionID    string
+	ProviderName string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	TotalTokens  int
+	Cost         float64
+}
+
+// NewUsageEvent creates a new usage event with a new usage ID
+func NewUsageEvent(eventType, userID, workspaceID, sessionID, providerName, model string, inputTokens, outputTokens, totalTokens int, cost float64) *UsageEvent {
+	return &UsageEvent{
+		BaseEvent:    NewBaseEvent(eventType, nil),
+		UsageID:      valueobject.GenerateID(nil).String(),
+		UserID:       userID,
+		WorkspaceID:  workspaceID,
+		SessionID:    sessionID,
+		ProviderName: providerName,
+		Model:        model,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		TotalTokens:  totalTokens,
+		Cost:         cost,
+	}
+}
+
 // UserEvent represents a user-related event
 type UserEvent struct {
 	*BaseEvent
@@ -272,6 +325,7 @@ type TaskEvent struct {
 	Input     string
 	Output    string
 	Error     string
+	Duration  time.Duration // Time the task spent in its previous status
 }
 
 // NewTaskEvent creates a new task event
@@ -288,6 +342,58 @@ func NewTaskEvent(eventType, taskID, sessionID, skillName, status, input, output
 	}
 }
 
+// ScheduleEvent represents a schedule execution event
+type ScheduleEvent struct {
+	*BaseEvent
+	ScheduleID string
+	SkillName  string
+	SessionID  string
+	Output     string
+	Error      error
+	Duration   time.Duration
+}
+
+// NewScheduleEvent creates a new schedule event
+func NewScheduleEvent(eventType, scheduleID, skillName, sessionID, output string, err error, duration time.Duration) *ScheduleEvent {
+	return &ScheduleEvent{
+		BaseEvent:  NewBaseEvent(eventType, nil),
+		ScheduleID: scheduleID,
+		SkillName:  skillName,
+		SessionID:  sessionID,
+		Output:     output,
+		Error:      err,
+		Duration:   duration,
+	}
+}
+
+// FeedbackEvent represents the rating of an assistant reply by a user.
+// It carries the reply and the user message it answered, so that downstream pipelines,
+// such as fine-tuning dataset builders, do not have to read them back.
+type FeedbackEvent struct {
+	*BaseEvent
+	MessageID string
+	SessionID string
+	UserID    string
+	Rating    string // "up" or "down"
+	Comment   string
+	Prompt    string // User message the reply answered, if any
+	Response  string // Rated reply
+}
+
+// NewFeedbackEvent creates a new feedback event
+func NewFeedbackEvent(eventType, messageID, sessionID, userID, rating, comment, prompt, response string) *FeedbackEvent {
+	return &FeedbackEvent{
+		BaseEvent: NewBaseEvent(eventType, nil),
+		MessageID: messageID,
+		SessionID: sessionID,
+		UserID:    userID,
+		Rating:    rating,
+		Comment:   comment,
+		Prompt:    prompt,
+		Response:  response,
+	}
+}
+
 // EventLogger is a built-in event handler that logs events
 type EventLogger struct {
 	logger logging.Logger
@@ -0,0 +1,89 @@
+package eventbus
+
+import "strings"
+
+// wildcard matches any sequence of characters, including dots, in an event type pattern
+const wildcard = "*"
+
+// EventFilter selects events by their attributes, in addition to their type.
+// Empty fields match every event; a set field only matches events that carry the
+// attribute with that value, so e.g. a UserID filter never matches schedule events.
+type EventFilter struct {
+	// UserID keeps events of this user (ConnectorEvent, RouterEvent, UserEvent, SessionEvent, FeedbackEvent, UsageEvent)
+	UserID string
+
+	// Connector keeps events of this connector (ConnectorEvent name, RouterEvent source, UserEvent channel)
+	Connector string
+
+	// SessionID keeps events of this session (RouterEvent, SessionEvent, TaskEvent, ScheduleEvent, FeedbackEvent, UsageEvent)
+	SessionID string
+}
+
+// IsZero reports whether the filter matches every event
+func (f EventFilter) IsZero() bool {
+	return f == EventFilter{}
+}
+
+// Matches reports whether an event has the attributes required by the filter
+func (f EventFilter) Matches(event Event) bool {
+	if f.IsZero() {
+		return true
+	}
+	attrs := eventAttributes(event)
+	return (f.UserID == "" || f.UserID == attrs.UserID) &&
+		(f.Connector == "" || f.Connector == attrs.Connector) &&
+		(f.SessionID == "" || f.SessionID == attrs.SessionID)
+}
+
+// eventAttributes returns the filterable attributes of an event
+func eventAttributes(event Event) EventFilter {
+	switch e := event.(type) {
+	case *ConnectorEvent:
+		return EventFilter{UserID: e.UserID, Connector: e.ConnectorName}
+	case *RouterEvent:
+		return EventFilter{UserID: e.UserID, Connector: e.Source, SessionID: e.SessionID}
+	case *UserEvent:
+		return EventFilter{UserID: e.UserID, Connector: e.Channel}
+	case *SessionEvent:
+		return EventFilter{UserID: e.UserID, SessionID: e.SessionID}
+	case *TaskEvent:
+		return EventFilter{SessionID: e.SessionID}
+	case *ScheduleEvent:
+		return EventFilter{SessionID: e.SessionID}
+	case *FeedbackEvent:
+		return EventFilter{UserID: e.UserID, SessionID: e.SessionID}
+	case *UsageEvent:
+		return EventFilter{UserID: e.UserID, SessionID: e.SessionID}
+	}
+	return EventFilter{}
+}
+
+// IsEventTypePattern reports whether an event type contains a wildcard
+func IsEventTypePattern(eventType string) bool {
+	return strings.Contains(eventType, wildcard)
+}
+
+// MatchEventType reports whether an event type matches a pattern.
+// In patterns "*" matches any sequence of characters: "connector.*" matches all connector
+// events, "*.failed" all failures and "*" every event. Patterns without "*" match exactly.
+func MatchEventType(pattern, eventType string) bool {
+	parts := strings.Split(pattern, wildcard)
+	if len(parts) == 1 {
+		return pattern == eventType
+	}
+
+	// The first part is a prefix and the last a suffix; the parts between appear in order
+	rest, ok := strings.CutPrefix(eventType, parts[0])
+	if !ok {
+		return false
+	}
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	return len(rest) >= len(last) && strings.HasSuffix(rest, last)
+}
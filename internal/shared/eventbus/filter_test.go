@@ -0,0 +1,147 @@
+package eventbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func newFilterTestBus(t *testing.T) *EventBus {
+	t.Helper()
+	eb := NewEventBus(&EventBusConfig{
+		BatchSize:     10,
+		FlushInterval: 10 * time.Millisecond,
+		Logger:        logging.NewNoopLogger(),
+	})
+	if err := eb.Start(); err != nil {
+		t.Fatalf("Failed to start event bus: %v", err)
+	}
+	t.Cleanup(func() { _ = eb.Stop() })
+	return eb
+}
+
+func TestMatchEventType(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		eventType string
+		want      bool
+	}{
+		{"connector.error", "connector.error", true},
+		{"connector.error", "connector.message", false},
+		{"connector.*", "connector.error", true},
+		{"connector.*", "connector.", true},
+		{"connector.*", "router.error", false},
+		{"connector.*", "connector", false},
+		{"*.failed", "task.failed", true},
+		{"*.failed", "task.completed", false},
+		{"*", "schedule.triggered", true},
+		{"s*.*ed", "schedule.triggered", true},
+		{"s*.*ed", "session.ended", true},
+		{"s*.*ed", "skill.started", true},
+		{"s*.*ed", "skill.failure", false},
+		{"*error*", "llm.error", true},
+		{"a*b*b", "abb", true},
+		{"a*b*b", "ab", false},
+	}
+	for _, tt := range tests {
+		if got := MatchEventType(tt.pattern, tt.eventType); got != tt.want {
+			t.Errorf("MatchEventType(%q, %q) = %v, want %v", tt.pattern, tt.eventType, got, tt.want)
+		}
+	}
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	message := NewRouterEvent(EventRouterMessage, "message-1", "session-1", "user-1", "hi", "telegram", nil)
+	schedule := NewScheduleEvent(EventScheduleFailed, "schedule-1", "echo", "session-1", "", errors.New("timeout"), time.Second)
+
+	tests := []struct {
+		name   string
+		filter EventFilter
+		event  Event
+		want   bool
+	}{
+		{"zero filter", EventFilter{}, NewBaseEvent(EventRouterStarted, nil), true},
+		{"user", EventFilter{UserID: "user-1"}, message, true},
+		{"other user", EventFilter{UserID: "user-2"}, message, false},
+		{"connector", EventFilter{Connector: "telegram"}, message, true},
+		{"user and other connector", EventFilter{UserID: "user-1", Connector: "discord"}, message, false},
+		{"session", EventFilter{SessionID: "session-1"}, schedule, true},
+		{"user on event without user", EventFilter{UserID: "user-1"}, schedule, false},
+		{"connector event", EventFilter{Connector: "web"}, NewConnectorEvent(EventConnectorError, "web", "", "", "", errors.New("closed")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventBusSubscribePattern(t *testing.T) {
+	eb := newFilterTestBus(t)
+
+	var connector, all eventRecorder
+	eb.Subscribe([]string{"connector.*"}, connector.handle)
+	eb.Subscribe([]string{"*"}, all.handle)
+
+	eb.Publish(NewConnectorEvent(EventConnectorStarted, "telegram", "", "", "", nil))
+	eb.Publish(NewConnectorEvent(EventConnectorError, "telegram", "", "", "", errors.New("closed")))
+	eb.Publish(NewRouterEvent(EventRouterMessage, "message-1", "session-1", "user-1", "hi", "telegram", nil))
+
+	waitUntil(t, "all events", func() bool { return all.count() == 3 })
+	waitUntil(t, "connector events", func() bool { return connector.count() == 2 })
+
+	time.Sleep(50 * time.Millisecond)
+	if connector.count() != 2 {
+		t.Errorf("Expected 2 connector events, got %d", connector.count())
+	}
+	if count := eb.GetSubscriptionCount("connector.*"); count != 1 {
+		t.Errorf("Expected 1 subscription to connector.*, got %d", count)
+	}
+	if count := eb.GetSubscriptionCount(EventConnectorError); count != 0 {
+		t.Errorf("Expected pattern subscriptions not to be counted under event types, got %d", count)
+	}
+}
+
+func TestEventBusSubscribeFiltered(t *testing.T) {
+	eb := newFilterTestBus(t)
+
+	var recorder eventRecorder
+	eb.SubscribeFiltered([]string{"router.*", "connector.*"}, EventFilter{UserID: "user-1"}, recorder.handle)
+
+	eb.Publish(NewRouterEvent(EventRouterMessage, "message-1", "session-1", "user-2", "hi", "telegram", nil))
+	eb.Publish(NewConnectorEvent(EventConnectorMessage, "web", "user-2", "", "hello", nil))
+	eb.Publish(NewRouterEvent(EventRouterMessage, "message-2", "session-2", "user-1", "hi", "telegram", nil))
+
+	waitUntil(t, "event of user-1", func() bool { return recorder.count() == 1 })
+	time.Sleep(50 * time.Millisecond)
+	if recorder.count() != 1 {
+		t.Fatalf("Expected 1 event, got %d", recorder.count())
+	}
+	if event := recorder.get(0).(*RouterEvent); event.MessageID != "message-2" {
+		t.Errorf("Expected message-2, got %s", event.MessageID)
+	}
+}
+
+func TestEventBusSubscribeOverlappingTypes(t *testing.T) {
+	eb := newFilterTestBus(t)
+
+	var recorder eventRecorder
+	sub := eb.Subscribe([]string{EventTaskFailed, "task.*", "*.failed"}, recorder.handle)
+
+	eb.Publish(NewTaskEvent(EventTaskFailed, "task-1", "session-1", "echo", "failed", "{}", "", "boom"))
+
+	waitUntil(t, "event", func() bool { return recorder.count() == 1 })
+	time.Sleep(50 * time.Millisecond)
+	if recorder.count() != 1 {
+		t.Errorf("Expected the event once, got %d", recorder.count())
+	}
+
+	eb.Unsubscribe(sub)
+	if counts := eb.GetAllSubscriptionCounts(); counts["task.*"] != 0 || counts[EventTaskFailed] != 0 {
+		t.Errorf("Expected no subscriptions after unsubscribe, got %v", counts)
+	}
+}
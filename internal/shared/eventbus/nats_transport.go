@@ -0,0 +1,294 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nuid"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+const (
+	// natsInstanceHeader names the instance that published an event, so that it is not
+	// delivered back to it
+	natsInstanceHeader = "Nexflow-Instance"
+
+	// natsStreamMaxAge is how long a stream created by the transport keeps events
+	natsStreamMaxAge = 24 * time.Hour
+)
+
+// NATSTransportConfig contains configuration options for the NATS transport
+type NATSTransportConfig struct {
+	// URL is the server address: nats://host:port or tls://host:port, optionally with user:password
+	URL string
+	// Token authenticates with a NATS token (optional)
+	Token string
+	// SubjectPrefix prefixes the subjects events are published to: <prefix>.<event type>
+	SubjectPrefix string
+	// Stream is the JetStream stream storing the events; it is created over <prefix>.> if missing
+	Stream string
+	// ConnectTimeout limits connecting and setting up the stream
+	ConnectTimeout time.Duration
+	// Name identifies the connection in the server monitoring (optional)
+	Name string
+	// Logger is the logger to use
+	Logger logging.Logger
+}
+
+// NATSTransport is a Transport over NATS JetStream.
+//
+// Events are published as JSON to <prefix>.<event type>, and Send waits until the stream has
+// stored them. The events of other instances are read from the stream by an ordered consumer,
+// which resumes after the last received event when a lost connection is re-established, so
+// events published meanwhile are not missed. Publications made while the client reconnects
+// are buffered and sent once the connection is back.
+type NATSTransport struct {
+	config   NATSTransportConfig
+	logger   logging.Logger
+	instance string
+
+	conn *nats.Conn
+	js   jetstream.JetStream
+
+	mu      sync.Mutex
+	consume jetstream.ConsumeContext
+}
+
+// NewNATSTransport creates a new NATS transport. Call Connect before using it.
+//
+// Parameters:
+//   - config: Configuration options for the transport
+//
+// Returns:
+//   - *NATSTransport: Initialized NATS transport
+func NewNATSTransport(config NATSTransportConfig) *NATSTransport {
+	if config.Logger == nil {
+		config.Logger = logging.NewNoopLogger()
+	}
+	if config.ConnectTimeout <= 0 {
+		config.ConnectTimeout = 5 * time.Second
+	}
+	config.SubjectPrefix = strings.TrimSuffix(config.SubjectPrefix, ".")
+
+	return &NATSTransport{
+		config:   config,
+		logger:   config.Logger,
+		instance: nuid.Next(),
+	}
+}
+
+// Connect connects to the NATS server and creates the event stream if it does not exist
+//
+// Parameters:
+//   - ctx: Context for the operation
+//
+// Returns:
+//   - error: Error if the server could not be reached, rejected the connection or has no JetStream
+func (t *NATSTransport) Connect(ctx context.Context) error {
+	options := []nats.Option{
+		nats.Name(t.config.Name),
+		nats.Timeout(t.config.ConnectTimeout),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				t.logger.Warn("NATS connection lost", "error", err)
+			}
+		}),
+		nats.ReconnectHandler(func(*nats.Conn) {
+			t.logger.Info("reconnected to NATS", "url", redactURL(t.config.URL))
+		}),
+	}
+	if t.config.Token != "" {
+		options = append(options, nats.Token(t.config.Token))
+	}
+
+	conn, err := nats.Connect(t.config.URL, options...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to use NATS JetStream: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.config.ConnectTimeout)
+	defer cancel()
+	if err := t.ensureStream(ctx, js); err != nil {
+		conn.Close()
+		return err
+	}
+
+	t.conn = conn
+	t.js = js
+	t.logger.Info("connected to NATS", "url", redactURL(t.config.URL), "stream", t.config.Stream)
+	return nil
+}
+
+// Send publishes events to <prefix>.<event type> and waits until the stream has stored them
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - events: Events to publish
+//
+// Returns:
+//   - error: Error if an event was not stored before ctx is done
+func (t *NATSTransport) Send(ctx context.Context, events []Event) error {
+	acks := make([]jetstream.PubAckFuture, 0, len(events))
+	for _, event := range events {
+		data, err := marshalWireEvent(event)
+		if err != nil {
+			t.logger.Error("failed to encode event for NATS", "type", event.Type(), "error", err)
+			continue
+		}
+		if maxPayload := t.conn.MaxPayload(); maxPayload > 0 && int64(len(data)) > maxPayload {
+			t.logger.Warn("event exceeds the NATS payload limit, not sent",
+				"type", event.Type(),
+				"size", len(data),
+				"max_payload", maxPayload,
+			)
+			continue
+		}
+
+		msg := nats.NewMsg(t.subject(event.Type()))
+		msg.Header.Set(natsInstanceHeader, t.instance)
+		msg.Data = data
+		ack, err := t.js.PublishMsgAsync(msg)
+		if err != nil {
+			return fmt.Errorf("failed to publish event to NATS: %w", err)
+		}
+		acks = append(acks, ack)
+	}
+
+	for _, ack := range acks {
+		select {
+		case <-ack.Ok():
+		case err := <-ack.Err():
+			return fmt.Errorf("failed to publish event to NATS: %w", err)
+		case <-ctx.Done():
+			return fmt.Errorf("failed to publish event to NATS: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// Receive starts reading the events of other instances published after this call from the
+// stream and passes them to deliver
+//
+// Parameters:
+//   - deliver: Function called with each received event, from the consuming goroutine
+//
+// Returns:
+//   - error: Error if the consumer could not be created
+func (t *NATSTransport) Receive(deliver func(Event)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.config.ConnectTimeout)
+	defer cancel()
+
+	// The consumer starts after the last stored event, also when it is recreated after a
+	// reconnect before it received any event
+	stream, err := t.js.Stream(ctx, t.config.Stream)
+	if err != nil {
+		return fmt.Errorf("failed to look up NATS stream %q: %w", t.config.Stream, err)
+	}
+	consumer, err := stream.OrderedConsumer(ctx, jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{t.config.SubjectPrefix + ".>"},
+		DeliverPolicy:  jetstream.DeliverByStartSequencePolicy,
+		OptStartSeq:    stream.CachedInfo().State.LastSeq + 1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create NATS consumer: %w", err)
+	}
+
+	consume, err := consumer.Consume(func(msg jetstream.Msg) {
+		t.handleMessage(msg, deliver)
+	}, jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+		t.logger.Warn("NATS consumer error", "error", err)
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to consume NATS events: %w", err)
+	}
+
+	t.mu.Lock()
+	t.consume = consume
+	t.mu.Unlock()
+	return nil
+}
+
+// Close stops receiving events, sends the buffered publications and closes the connection
+//
+// Returns:
+//   - error: Always nil
+func (t *NATSTransport) Close() error {
+	t.mu.Lock()
+	if t.consume != nil {
+		t.consume.Stop()
+		t.consume = nil
+	}
+	t.mu.Unlock()
+
+	if t.conn != nil {
+		if err := t.conn.Drain(); err != nil {
+			t.conn.Close()
+		}
+	}
+	return nil
+}
+
+// subject returns the subject of an event type
+func (t *NATSTransport) subject(eventType string) string {
+	return t.config.SubjectPrefix + "." + eventType
+}
+
+// ensureStream creates the event stream unless it already exists
+func (t *NATSTransport) ensureStream(ctx context.Context, js jetstream.JetStream) error {
+	_, err := js.Stream(ctx, t.config.Stream)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, jetstream.ErrStreamNotFound) {
+		return fmt.Errorf("failed to look up NATS stream %q: %w", t.config.Stream, err)
+	}
+
+	_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     t.config.Stream,
+		Subjects: []string{t.config.SubjectPrefix + ".>"},
+		MaxAge:   natsStreamMaxAge,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create NATS stream %q: %w", t.config.Stream, err)
+	}
+	t.logger.Info("created NATS stream", "stream", t.config.Stream)
+	return nil
+}
+
+// handleMessage decodes an event received from the stream and passes it on, unless this
+// instance published it
+func (t *NATSTransport) handleMessage(msg jetstream.Msg, deliver func(Event)) {
+	if msg.Headers().Get(natsInstanceHeader) == t.instance {
+		return
+	}
+
+	event, err := unmarshalWireEvent(msg.Data())
+	if err != nil {
+		t.logger.Warn("skipping undecodable NATS event", "error", err)
+		return
+	}
+	deliver(event)
+}
+
+// redactURL removes the password from a URL for logging
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
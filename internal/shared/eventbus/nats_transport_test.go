@@ -0,0 +1,231 @@
+package eventbus
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// runNATSServer starts an in-process NATS server with JetStream storing into dir.
+// A zero port picks a free one.
+func runNATSServer(t *testing.T, dir string, port int, token string) *server.Server {
+	t.Helper()
+	if port == 0 {
+		port = server.RANDOM_PORT
+	}
+	s, err := server.NewServer(&server.Options{
+		Host:          "127.0.0.1",
+		Port:          port,
+		JetStream:     true,
+		StoreDir:      dir,
+		Authorization: token,
+		NoLog:         true,
+		NoSigs:        true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+// storedEvents returns the number of events in the stream of a server
+func storedEvents(t *testing.T, s *server.Server) uint64 {
+	t.Helper()
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatalf("Failed to use JetStream: %v", err)
+	}
+	stream, err := js.Stream(context.Background(), "NEXFLOW_EVENTS")
+	if err != nil {
+		t.Fatalf("Failed to find stream: %v", err)
+	}
+	return stream.CachedInfo().State.Msgs
+}
+
+func waitUntil(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(15 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newNATSEventBus(t *testing.T, serverURL string) *EventBus {
+	t.Helper()
+	transport := NewNATSTransport(NATSTransportConfig{
+		URL:           serverURL,
+		SubjectPrefix: "nexflow.events",
+		Stream:        "NEXFLOW_EVENTS",
+		Name:          "test",
+		Logger:        logging.NewNoopLogger(),
+	})
+	if err := transport.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	eb := NewEventBus(&EventBusConfig{
+		BatchSize:     10,
+		FlushInterval: 10 * time.Millisecond,
+		Logger:        logging.NewNoopLogger(),
+		Transport:     transport,
+	})
+	if err := eb.Start(); err != nil {
+		t.Fatalf("Failed to start event bus: %v", err)
+	}
+	t.Cleanup(func() { _ = eb.Stop() })
+	return eb
+}
+
+// eventRecorder collects the events passed to a handler
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) handle(ctx context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *eventRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func (r *eventRecorder) get(i int) Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[i]
+}
+
+func TestNATSTransport_SharesEventsBetweenBuses(t *testing.T) {
+	s := runNATSServer(t, t.TempDir(), 0, "")
+	busA := newNATSEventBus(t, s.ClientURL())
+	busB := newNATSEventBus(t, s.ClientURL())
+
+	var onA, onB eventRecorder
+	busA.Subscribe([]string{EventSessionUpdated}, onA.handle)
+	busB.Subscribe([]string{EventSessionUpdated}, onB.handle)
+
+	busA.Publish(NewSessionEvent(EventSessionUpdated, "session-1", "user-1", 3))
+
+	waitUntil(t, "event on bus B", func() bool { return onB.count() == 1 })
+	waitUntil(t, "event on bus A", func() bool { return onA.count() == 1 })
+
+	received, ok := onB.get(0).(*SessionEvent)
+	if !ok {
+		t.Fatalf("Expected *SessionEvent on bus B, got %T", onB.get(0))
+	}
+	if received.SessionID != "session-1" || received.UserID != "user-1" || received.MessageCount != 3 {
+		t.Errorf("Unexpected event on bus B: %+v", received)
+	}
+
+	// Bus A skips its own event in the stream, so it dispatches it once
+	time.Sleep(100 * time.Millisecond)
+	if onA.count() != 1 {
+		t.Errorf("Expected the event once on bus A, got %d", onA.count())
+	}
+	if sent := busA.Metrics().EventsSent.Get(); sent != 1 {
+		t.Errorf("Expected 1 sent event on bus A, got %d", sent)
+	}
+	if received := busB.Metrics().EventsReceived.Get(); received != 1 {
+		t.Errorf("Expected 1 received event on bus B, got %d", received)
+	}
+	if stored := storedEvents(t, s); stored != 1 {
+		t.Errorf("Expected 1 event in the stream, got %d", stored)
+	}
+}
+
+func TestNATSTransport_DeliversEventsPublishedWhileDisconnected(t *testing.T) {
+	dir := t.TempDir()
+	s := runNATSServer(t, dir, 0, "")
+	port := s.Addr().(*net.TCPAddr).Port
+	busA := newNATSEventBus(t, s.ClientURL())
+	busB := newNATSEventBus(t, s.ClientURL())
+
+	var onB eventRecorder
+	busB.Subscribe([]string{EventUserCreated}, onB.handle)
+
+	s.Shutdown()
+	s.WaitForShutdown()
+
+	// The publication waits in the client until the server is back
+	busA.Publish(NewUserEvent(EventUserCreated, "user-1", "user@example.com", "web"))
+	time.Sleep(100 * time.Millisecond)
+	runNATSServer(t, dir, port, "")
+
+	waitUntil(t, "event after reconnect", func() bool { return onB.count() == 1 })
+	if failures := busA.Metrics().TransportFailures.Get(); failures != 0 {
+		t.Errorf("Expected no transport failures on bus A, got %d", failures)
+	}
+}
+
+func TestNATSTransport_ConnectRejected(t *testing.T) {
+	s := runNATSServer(t, t.TempDir(), 0, "secret")
+
+	transport := NewNATSTransport(NATSTransportConfig{URL: s.ClientURL(), SubjectPrefix: "nexflow.events", Stream: "NEXFLOW_EVENTS"})
+	err := transport.Connect(context.Background())
+	if err == nil || !strings.Contains(strings.ToLower(err.Error()), "authorization violation") {
+		t.Errorf("Expected authorization error, got %v", err)
+	}
+
+	transport = NewNATSTransport(NATSTransportConfig{URL: s.ClientURL(), Token: "secret", SubjectPrefix: "nexflow.events", Stream: "NEXFLOW_EVENTS"})
+	if err := transport.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() with token error = %v", err)
+	}
+	if err := transport.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestWireEvent_RoundTrip(t *testing.T) {
+	event := NewTaskEvent(EventTaskFailed, "task-1", "session-1", "echo", "failed", "{}", "", "boom")
+
+	data, err := marshalWireEvent(event)
+	if err != nil {
+		t.Fatalf("marshalWireEvent() error = %v", err)
+	}
+	decoded, err := unmarshalWireEvent(data)
+	if err != nil {
+		t.Fatalf("unmarshalWireEvent() error = %v", err)
+	}
+
+	task, ok := decoded.(*TaskEvent)
+	if !ok {
+		t.Fatalf("Expected *TaskEvent, got %T", decoded)
+	}
+	if task.TaskID != "task-1" || task.SkillName != "echo" || task.Error != "boom" {
+		t.Errorf("Unexpected decoded event: %+v", task)
+	}
+	if !task.Timestamp().Equal(event.Timestamp()) {
+		t.Errorf("Expected timestamp %v, got %v", event.Timestamp(), task.Timestamp())
+	}
+
+	if _, err := unmarshalWireEvent([]byte(`{"payload":{}}`)); err == nil {
+		t.Error("Expected error for event without type")
+	}
+}
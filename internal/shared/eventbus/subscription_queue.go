@@ -0,0 +1,159 @@
+package eventbus
+
+import (
+	"fmt"
+
+	"github.com/atumaikin/nexflow/internal/shared/metrics"
+)
+
+// OverflowPolicy decides what happens to an event when the queue of a subscription is full
+type OverflowPolicy string
+
+// Overflow policies
+const (
+	// OverflowDropNewest drops the event that does not fit into the queue
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowDropOldest drops the oldest queued event to make room for the new one
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowBlock holds up dispatching until the subscription has room, so a slow
+	// subscriber slows down every subscriber and, once the event channel is full, publishers
+	OverflowBlock OverflowPolicy = "block"
+)
+
+// DefaultSubscriberBufferSize is the number of events queued per subscription by default
+const DefaultSubscriberBufferSize = 256
+
+// IsValid reports whether the policy is one of the overflow policies
+func (p OverflowPolicy) IsValid() bool {
+	switch p {
+	case OverflowDropNewest, OverflowDropOldest, OverflowBlock:
+		return true
+	}
+	return false
+}
+
+// subscriptionMetrics are the metrics of the subscriptions sharing a name
+type subscriptionMetrics struct {
+	queueDepth      *metrics.Gauge
+	dropped         *metrics.Counter
+	handlerDuration *metrics.Histogram
+}
+
+// QueueDepth returns the gauge of events queued for the subscriptions with a name
+func (m *EventBusMetrics) QueueDepth(subscription string) *metrics.Gauge {
+	return m.registry.GetGauge(fmt.Sprintf("eventbus_subscription_queue_depth{subscription=%q}", subscription))
+}
+
+// SubscriptionDropped returns the counter of events dropped for the subscriptions with a name
+func (m *EventBusMetrics) SubscriptionDropped(subscription string) *metrics.Counter {
+	return m.registry.GetCounter(fmt.Sprintf("eventbus_subscription_events_dropped_total{subscription=%q}", subscription))
+}
+
+// HandlerDuration returns the handler duration histogram of the subscriptions with a name
+func (m *EventBusMetrics) HandlerDuration(subscription string) *metrics.Histogram {
+	return m.registry.GetHistogram(fmt.Sprintf("eventbus_handler_duration_seconds{subscription=%q}", subscription), metrics.DefaultBuckets())
+}
+
+// subscriptionMetrics returns the metrics of the subscriptions with a name
+func (m *EventBusMetrics) subscriptionMetrics(subscription string) subscriptionMetrics {
+	return subscriptionMetrics{
+		queueDepth:      m.QueueDepth(subscription),
+		dropped:         m.SubscriptionDropped(subscription),
+		handlerDuration: m.HandlerDuration(subscription),
+	}
+}
+
+// QueueDepth returns the number of events waiting for the handler of the subscription
+func (sub *EventSubscription) QueueDepth() int {
+	return len(sub.queue)
+}
+
+// stop makes the worker of the subscription exit once it has handled the queued events
+func (sub *EventSubscription) stop() {
+	sub.stopOnce.Do(func() { close(sub.done) })
+}
+
+// enqueue queues an event for a subscription according to its overflow policy
+func (eb *EventBus) enqueue(sub *EventSubscription, event Event) {
+	select {
+	case <-sub.done:
+		return
+	default:
+	}
+
+	switch sub.Overflow {
+	case OverflowBlock:
+		select {
+		case sub.queue <- event:
+			sub.metrics.queueDepth.Add(1)
+			return
+		default:
+		}
+		// Wait for room, unless the subscription is removed or the event bus stops
+		select {
+		case sub.queue <- event:
+			sub.metrics.queueDepth.Add(1)
+		case <-sub.done:
+		case <-eb.ctx.Done():
+			eb.dropEvent(sub, event)
+		}
+
+	case OverflowDropOldest:
+		for {
+			select {
+			case sub.queue <- event:
+				sub.metrics.queueDepth.Add(1)
+				return
+			default:
+			}
+			select {
+			case oldest := <-sub.queue:
+				sub.metrics.queueDepth.Add(-1)
+				eb.dropEvent(sub, oldest)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case sub.queue <- event:
+			sub.metrics.queueDepth.Add(1)
+		default:
+			eb.dropEvent(sub, event)
+		}
+	}
+}
+
+// dropEvent counts an event that did not fit into the queue of a subscription
+func (eb *EventBus) dropEvent(sub *EventSubscription, event Event) {
+	eb.metrics.SubscriberDrops.Inc()
+	sub.metrics.dropped.Inc()
+	eb.logger.Warn("subscription queue full, dropping event",
+		"type", event.Type(),
+		"subscription", sub.Name,
+		"policy", string(sub.Overflow),
+	)
+}
+
+// runSubscription passes the queued events to the handler of a subscription, one at a time
+// and in dispatch order. Once the subscription is stopped it handles the events still
+// queued and exits.
+func (eb *EventBus) runSubscription(sub *EventSubscription) {
+	for {
+		select {
+		case event := <-sub.queue:
+			sub.metrics.queueDepth.Add(-1)
+			eb.deliver(sub, event)
+		case <-sub.done:
+			for {
+				select {
+				case event := <-sub.queue:
+					sub.metrics.queueDepth.Add(-1)
+					eb.deliver(sub, event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package eventbus
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// blockingHandler holds up every call until it is released and records the user IDs it handled
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+
+	mu      sync.Mutex
+	handled []string
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (h *blockingHandler) handle(ctx context.Context, event Event) error {
+	h.started <- struct{}{}
+	<-h.release
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled = append(h.handled, event.(*UserEvent).UserID)
+	return nil
+}
+
+func (h *blockingHandler) get() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.handled)
+}
+
+// publishAfterFirst publishes user-1 and waits for the handler to start on it, then publishes the other users
+func publishAfterFirst(t *testing.T, eb *EventBus, handler *blockingHandler, users ...string) {
+	t.Helper()
+	eb.Publish(NewUserEvent(EventUserCreated, "user-1", "", "web"))
+	select {
+	case <-handler.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the handler to start")
+	}
+	for _, user := range users {
+		eb.Publish(NewUserEvent(EventUserCreated, user, "", "web"))
+	}
+}
+
+func TestSubscriptionQueue_DropNewest(t *testing.T) {
+	eb := newFilterTestBus(t)
+	handler := newBlockingHandler()
+	sub := eb.SubscribeWithOptions([]string{EventUserCreated}, SubscribeOptions{Name: "slow", BufferSize: 2}, handler.handle)
+	if sub.Overflow != OverflowDropNewest {
+		t.Errorf("Expected the default policy %q, got %q", OverflowDropNewest, sub.Overflow)
+	}
+
+	publishAfterFirst(t, eb, handler, "user-2", "user-3", "user-4", "user-5")
+	dropped := eb.Metrics().SubscriptionDropped("slow")
+	waitUntil(t, "dropped events", func() bool { return dropped.Get() == 2 })
+
+	if depth := sub.QueueDepth(); depth != 2 {
+		t.Errorf("Expected 2 queued events, got %d", depth)
+	}
+	if depth := eb.Metrics().QueueDepth("slow").Get(); depth != 2 {
+		t.Errorf("Expected queue depth gauge 2, got %d", depth)
+	}
+	if total := eb.Metrics().SubscriberDrops.Get(); total != 2 {
+		t.Errorf("Expected 2 subscriber drops, got %d", total)
+	}
+
+	close(handler.release)
+	waitUntil(t, "queued events", func() bool { return len(handler.get()) == 3 })
+	if got := handler.get(); !slices.Equal(got, []string{"user-1", "user-2", "user-3"}) {
+		t.Errorf("Expected the newest events to be dropped, handled %v", got)
+	}
+	if depth := eb.Metrics().QueueDepth("slow").Get(); depth != 0 {
+		t.Errorf("Expected queue depth gauge 0, got %d", depth)
+	}
+	if count := eb.Metrics().HandlerDuration("slow").Count(); count != 3 {
+		t.Errorf("Expected 3 handler durations, got %d", count)
+	}
+}
+
+func TestSubscriptionQueue_DropOldest(t *testing.T) {
+	eb := newFilterTestBus(t)
+	handler := newBlockingHandler()
+	eb.SubscribeWithOptions([]string{EventUserCreated}, SubscribeOptions{
+		Name:       "slow",
+		BufferSize: 2,
+		Overflow:   OverflowDropOldest,
+	}, handler.handle)
+
+	publishAfterFirst(t, eb, handler, "user-2", "user-3", "user-4", "user-5")
+	dropped := eb.Metrics().SubscriptionDropped("slow")
+	waitUntil(t, "dropped events", func() bool { return dropped.Get() == 2 })
+
+	close(handler.release)
+	waitUntil(t, "queued events", func() bool { return len(handler.get()) == 3 })
+	if got := handler.get(); !slices.Equal(got, []string{"user-1", "user-4", "user-5"}) {
+		t.Errorf("Expected the oldest queued events to be dropped, handled %v", got)
+	}
+}
+
+func TestSubscriptionQueue_Block(t *testing.T) {
+	eb := newFilterTestBus(t)
+	handler := newBlockingHandler()
+	sub := eb.SubscribeWithOptions([]string{EventUserCreated}, SubscribeOptions{
+		BufferSize: 1,
+		Overflow:   OverflowBlock,
+	}, handler.handle)
+
+	var fast eventRecorder
+	eb.Subscribe([]string{EventUserCreated}, fast.handle)
+
+	publishAfterFirst(t, eb, handler, "user-2", "user-3")
+	waitUntil(t, "full queue", func() bool { return sub.QueueDepth() == 1 })
+
+	// Dispatching waits for the slow subscriber, so the others fall behind as well
+	time.Sleep(50 * time.Millisecond)
+	if count := fast.count(); count != 2 {
+		t.Errorf("Expected the fast subscriber to be held up after 2 events, got %d", count)
+	}
+
+	close(handler.release)
+	waitUntil(t, "all events", func() bool { return len(handler.get()) == 3 && fast.count() == 3 })
+	if got := handler.get(); !slices.Equal(got, []string{"user-1", "user-2", "user-3"}) {
+		t.Errorf("Expected every event in order, handled %v", got)
+	}
+	if drops := eb.Metrics().SubscriberDrops.Get(); drops != 0 {
+		t.Errorf("Expected no drops, got %d", drops)
+	}
+}
+
+func TestSubscriptionQueue_UnsubscribeStopsWorker(t *testing.T) {
+	eb := newFilterTestBus(t)
+
+	var recorder eventRecorder
+	sub := eb.Subscribe([]string{EventUserCreated}, recorder.handle)
+	eb.Unsubscribe(sub)
+
+	// Events dispatched to a removed subscription are neither queued nor counted as dropped
+	eb.enqueue(sub, NewUserEvent(EventUserCreated, "user-1", "", "web"))
+	if depth := sub.QueueDepth(); depth != 0 {
+		t.Errorf("Expected no queued events, got %d", depth)
+	}
+	if drops := eb.Metrics().SubscriberDrops.Get(); drops != 0 {
+		t.Errorf("Expected no drops, got %d", drops)
+	}
+}
+
+func TestEventBus_BufferSize(t *testing.T) {
+	eb := NewEventBus(&EventBusConfig{BatchSize: 10, FlushInterval: time.Second, BufferSize: 2, Logger: logging.NewNoopLogger()})
+
+	// Not started: the event channel fills up
+	for i := 0; i < 3; i++ {
+		eb.Publish(NewBaseEvent(EventRouterStarted, nil))
+	}
+	if dropped := eb.Metrics().EventsDropped.Get(); dropped != 1 {
+		t.Errorf("Expected 1 dropped event, got %d", dropped)
+	}
+}
+
+func TestOverflowPolicy_IsValid(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDropNewest, OverflowDropOldest, OverflowBlock} {
+		if !policy.IsValid() {
+			t.Errorf("Expected %q to be valid", policy)
+		}
+	}
+	if OverflowPolicy("drop_all").IsValid() {
+		t.Error("Expected unknown policy to be invalid")
+	}
+}
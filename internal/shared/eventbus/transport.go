@@ -0,0 +1,64 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/entity"
+)
+
+// Transport carries events between the event buses of several instances.
+//
+// The event bus sends every event published on this instance through the transport after
+// persisting it, and dispatches the events the transport receives from other instances to
+// its local subscribers. Received events are neither persisted nor sent again.
+type Transport interface {
+	// Send publishes a batch of events published on this instance, in publication order
+	Send(ctx context.Context, events []Event) error
+
+	// Receive starts passing the events published by other instances to deliver.
+	// Events sent through this transport must not be delivered back to it.
+	Receive(deliver func(Event)) error
+
+	// Close stops receiving events and releases the connection
+	Close() error
+}
+
+// wireEvent is the form in which events travel between instances
+type wireEvent struct {
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// marshalWireEvent encodes an event for a transport
+func marshalWireEvent(event Event) ([]byte, error) {
+	payload, err := MarshalPayload(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(wireEvent{
+		Type:       event.Type(),
+		OccurredAt: event.Timestamp(),
+		Payload:    payload,
+	})
+}
+
+// unmarshalWireEvent decodes an event received from a transport.
+// Like replayed events, it is rebuilt as the struct it was published as.
+func unmarshalWireEvent(data []byte) (Event, error) {
+	var w wireEvent
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	if w.Type == "" {
+		return nil, fmt.Errorf("invalid event: missing type")
+	}
+	return decodeEvent(&entity.StoredEvent{
+		Type:       w.Type,
+		Payload:    string(w.Payload),
+		OccurredAt: w.OccurredAt,
+	})
+}
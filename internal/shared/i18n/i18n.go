@@ -0,0 +1,130 @@
+// Package i18n translates the system messages sent to users, such as error replies and the
+// replies of chat commands.
+//
+// A Catalog holds message maps by language code. Messages are looked up by key and formatted
+// with fmt.Sprintf; a language without the key falls back to its base language ("pt" for
+// "pt-BR"), then to the fallback language of the catalog and at last to English.
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is the language every catalog falls back to
+const DefaultLanguage = "en"
+
+// Messages maps message keys to message formats, e.g. "command.reset.done" to "Conversation cleared."
+type Messages map[string]string
+
+// Catalog holds the translations of the system messages
+type Catalog struct {
+	mu        sync.RWMutex
+	languages map[string]Messages // Messages by normalized language code
+	fallback  string
+}
+
+// NewCatalog creates an empty catalog falling back to the given language; an empty fallback
+// means DefaultLanguage
+func NewCatalog(fallback string) *Catalog {
+	if fallback == "" {
+		fallback = DefaultLanguage
+	}
+	return &Catalog{
+		languages: make(map[string]Messages),
+		fallback:  Normalize(fallback),
+	}
+}
+
+// Add adds the messages of a language, replacing messages of the same key
+func (c *Catalog) Add(language string, messages Messages) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	language = Normalize(language)
+	existing, ok := c.languages[language]
+	if !ok {
+		existing = make(Messages, len(messages))
+		c.languages[language] = existing
+	}
+	for key, message := range messages {
+		existing[key] = message
+	}
+}
+
+// Lookup returns the unformatted message of a key in a language, following the fallbacks
+func (c *Catalog) Lookup(language, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, candidate := range c.candidates(language) {
+		if message, ok := c.languages[candidate][key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// Translate returns the message of a key in a language formatted with args, or the key if no
+// language of the fallbacks has the message
+func (c *Catalog) Translate(language, key string, args ...interface{}) string {
+	message, ok := c.Lookup(language, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Has returns true if the catalog has messages of a language or of its base language
+func (c *Catalog) Has(language string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	language = Normalize(language)
+	if _, ok := c.languages[language]; ok {
+		return true
+	}
+	_, ok := c.languages[Base(language)]
+	return ok
+}
+
+// Languages returns the languages of the catalog, sorted
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	languages := make([]string, 0, len(c.languages))
+	for language := range c.languages {
+		languages = append(languages, language)
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// candidates returns the languages a lookup tries, in order
+func (c *Catalog) candidates(language string) []string {
+	language = Normalize(language)
+	candidates := make([]string, 0, 4)
+	for _, candidate := range []string{language, Base(language), c.fallback, DefaultLanguage} {
+		if candidate != "" && !slices.Contains(candidates, candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// Normalize returns a language code in lower case with "-" separators, e.g. "pt-br" for "pt_BR"
+func Normalize(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
+
+// Base returns the language of a code without its region or script, e.g. "pt" for "pt-BR"
+func Base(language string) string {
+	base, _, _ := strings.Cut(Normalize(language), "-")
+	return base
+}
@@ -0,0 +1,59 @@
+package i18n
+
+import "testing"
+
+func TestCatalog_Translate(t *testing.T) {
+	catalog := NewCatalog("ru")
+	catalog.Add("en", Messages{"greeting": "Hello, %s!", "bye": "Bye."})
+	catalog.Add("ru", Messages{"greeting": "Привет, %s!"})
+	catalog.Add("pt", Messages{"greeting": "Olá, %s!", "bye": "Tchau."})
+
+	tests := []struct {
+		language string
+		key      string
+		want     string
+	}{
+		{"ru", "greeting", "Привет, Alice!"},
+		{"pt-BR", "greeting", "Olá, Alice!"},
+		{"PT_br", "bye", "Tchau."},
+		{"de", "greeting", "Привет, Alice!"}, // Fallback language of the catalog
+		{"ru", "bye", "Bye."},                // English as the last resort
+		{"", "greeting", "Привет, Alice!"},
+		{"en", "missing", "missing"},
+	}
+	for _, tt := range tests {
+		var got string
+		if tt.key == "bye" {
+			got = catalog.Translate(tt.language, tt.key)
+		} else {
+			got = catalog.Translate(tt.language, tt.key, "Alice")
+		}
+		if got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.language, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCatalog_AddReplaces(t *testing.T) {
+	catalog := NewCatalog("")
+	catalog.Add("en", Messages{"a": "A", "b": "B"})
+	catalog.Add("EN", Messages{"b": "Bee"})
+
+	if got := catalog.Translate("en", "a"); got != "A" {
+		t.Errorf("Expected the earlier message to be kept, got %q", got)
+	}
+	if got := catalog.Translate("en", "b"); got != "Bee" {
+		t.Errorf("Expected the message to be replaced, got %q", got)
+	}
+	if !catalog.Has("en-GB") || catalog.Has("fr") {
+		t.Errorf("Unexpected languages %v", catalog.Languages())
+	}
+}
+
+func TestBase(t *testing.T) {
+	for code, want := range map[string]string{"pt-BR": "pt", "zh_Hant_TW": "zh", "ru": "ru", "": ""} {
+		if got := Base(code); got != want {
+			t.Errorf("Base(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// databaseWriteTimeout limits writing a single batch of log records
+const databaseWriteTimeout = 10 * time.Second
+
+// LogRecord is a log record persisted by a DatabaseHandler
+type LogRecord struct {
+	Time    time.Time
+	Level   string // "debug", "info", "warn" or "error"
+	Source  string // The "component" attribute, or the "source" attribute of the logger
+	Message string
+	Attrs   map[string]any // The other attributes; keys of groups are joined with "."
+}
+
+// LogSink stores batches of log records, e.g. in the logs table.
+// The records slice must not be retained after WriteLogs returns.
+type LogSink interface {
+	WriteLogs(ctx context.Context, records []LogRecord) error
+}
+
+// DatabaseHandlerConfig represents configuration of a DatabaseHandler
+type DatabaseHandlerConfig struct {
+	// Level is the lowest level of persisted records
+	Level slog.Level
+	// SampleRatio is the share of debug and info records that are persisted, from 0 to 1;
+	// warnings and errors are always persisted
+	SampleRatio float64
+	// BatchSize is the number of records written together
+	BatchSize int
+	// QueueSize is the number of records waiting to be written; further records are dropped
+	QueueSize int
+	// FlushInterval is the longest time a record waits to be written
+	FlushInterval time.Duration
+	// Masker masks secrets in the messages and attributes; nil uses the DefaultMasker
+	Masker *Masker
+}
+
+// DatabaseHandler is a slog.Handler persisting log records asynchronously.
+// Records are queued and written in batches by a background goroutine, so logging never waits
+// for the database; when the queue is full, records are dropped. Records logged before Start
+// are kept in the queue and written once the sink is available.
+//
+// Records logged while a batch is written, e.g. slow query warnings of the database, are not
+// persisted, so that writing logs cannot produce more logs to write.
+type DatabaseHandler struct {
+	core   *databaseCore
+	attrs  []slog.Attr // Attributes added by WithAttrs, keys prefixed with their groups
+	prefix string      // Groups added by WithGroup, joined with "." and ending with "."
+}
+
+// databaseCore is shared by a DatabaseHandler and the handlers derived from it
+type databaseCore struct {
+	config  DatabaseHandlerConfig
+	queue   chan LogRecord
+	dropped atomic.Int64
+
+	sink    LogSink
+	onError func(error)
+	started atomic.Bool
+	closed  atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// withoutDatabaseKey marks contexts whose log records are not persisted
+type withoutDatabaseKey struct{}
+
+// WithoutDatabase returns a context whose log records are not persisted by a DatabaseHandler,
+// e.g. to log errors of the sink itself
+func WithoutDatabase(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutDatabaseKey{}, true)
+}
+
+// NewDatabaseHandler creates a DatabaseHandler. Records are queued until Start is called.
+func NewDatabaseHandler(config DatabaseHandlerConfig) *DatabaseHandler {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10 * config.BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.Masker == nil {
+		config.Masker = DefaultMasker()
+	}
+	return &DatabaseHandler{core: &databaseCore{
+		config: config,
+		queue:  make(chan LogRecord, config.QueueSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}}
+}
+
+// Start starts writing the queued records to sink in the background. onError is called with
+// write errors, e.g. to log them with a WithoutDatabase context; nil ignores them.
+func (h *DatabaseHandler) Start(sink LogSink, onError func(error)) {
+	if h.core.started.CompareAndSwap(false, true) {
+		h.core.sink = sink
+		h.core.onError = onError
+		go h.core.run()
+	}
+}
+
+// Close stops accepting records, writes the queued records and waits until they are written
+// or ctx is done
+func (h *DatabaseHandler) Close(ctx context.Context) error {
+	h.core.closed.Store(true)
+	if !h.core.started.Load() {
+		return nil
+	}
+	h.core.once.Do(func() { close(h.core.stop) })
+	select {
+	case <-h.core.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dropped returns the number of records dropped because the queue was full
+func (h *DatabaseHandler) Dropped() int64 {
+	return h.core.dropped.Load()
+}
+
+// Enabled reports whether records of the level are persisted
+func (h *DatabaseHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.core.config.Level && !h.core.closed.Load() && ctx.Value(withoutDatabaseKey{}) == nil
+}
+
+// Handle queues the record for writing
+func (h *DatabaseHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.Enabled(ctx, r.Level) {
+		return nil
+	}
+	if r.Level < slog.LevelWarn && h.core.config.SampleRatio < 1 && rand.Float64() >= h.core.config.SampleRatio {
+		return nil
+	}
+
+	masker := h.core.config.Masker
+	attrs := make(map[string]any, len(h.attrs)+r.NumAttrs())
+	for _, a := range h.attrs {
+		addAttr(attrs, masker, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(attrs, masker, h.prefix, a)
+		return true
+	})
+
+	record := LogRecord{
+		Time:    r.Time,
+		Level:   levelName(r.Level),
+		Source:  "nexflow",
+		Message: masker.MaskString(r.Message),
+		Attrs:   attrs,
+	}
+	for _, key := range []string{"source", "component"} {
+		if source, ok := attrs[key].(string); ok && source != "" {
+			record.Source = source
+		}
+	}
+	delete(attrs, "source")
+
+	select {
+	case h.core.queue <- record:
+	default:
+		h.core.dropped.Add(1)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler adding the attributes to its records
+func (h *DatabaseHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.attrs = make([]slog.Attr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(derived.attrs, h.attrs)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		derived.attrs = append(derived.attrs, a)
+	}
+	return &derived
+}
+
+// WithGroup returns a handler prefixing the keys of the attributes of its records with the group
+func (h *DatabaseHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.prefix = h.prefix + name + "."
+	return &derived
+}
+
+// addAttr adds an attribute to attrs, flattening groups and masking secrets
+func addAttr(attrs map[string]any, masker *Masker, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, attr := range value.Group() {
+			addAttr(attrs, masker, groupPrefix, attr)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+
+	key := prefix + a.Key
+	name := key[strings.LastIndex(key, ".")+1:]
+	switch {
+	case masker.MaskKey(name):
+		attrs[key] = maskValue(value.String())
+	case value.Kind() == slog.KindString:
+		attrs[key] = masker.MaskString(value.String())
+	case value.Kind() == slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			attrs[key] = masker.MaskString(err.Error())
+		} else {
+			attrs[key] = value.Any()
+		}
+	default:
+		attrs[key] = value.Any()
+	}
+}
+
+// levelName returns the name of a level as stored in the logs table
+func levelName(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// run writes the queued records in batches until the handler is closed
+func (c *databaseCore) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]LogRecord, 0, c.config.BatchSize)
+	for {
+		select {
+		case record := <-c.queue:
+			batch = append(batch, record)
+			if len(batch) >= c.config.BatchSize {
+				batch = c.write(batch)
+			}
+		case <-ticker.C:
+			batch = c.write(batch)
+		case <-c.stop:
+			for {
+				select {
+				case record := <-c.queue:
+					batch = append(batch, record)
+					if len(batch) >= c.config.BatchSize {
+						batch = c.write(batch)
+					}
+				default:
+					c.write(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// write writes a batch and returns the emptied batch
+func (c *databaseCore) write(batch []LogRecord) []LogRecord {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(WithoutDatabase(context.Background()), databaseWriteTimeout)
+	defer cancel()
+	if err := c.sink.WriteLogs(ctx, batch); err != nil && c.onError != nil {
+		c.onError(err)
+	}
+	return batch[:0]
+}
+
+// teeHandler passes records to several handlers
+type teeHandler []slog.Handler
+
+// Enabled reports whether any of the handlers handles records of the level
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes the record to the handlers handling its level
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range t {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WithAttrs returns a teeHandler adding the attributes in all handlers
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+// WithGroup returns a teeHandler starting the group in all handlers
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
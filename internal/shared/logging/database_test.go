@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink keeps the written log records
+type recordingSink struct {
+	mu      sync.Mutex
+	records []LogRecord
+	batches int
+	logger  Logger // Logs while writing, like the instrumented database
+}
+
+func (s *recordingSink) WriteLogs(ctx context.Context, records []LogRecord) error {
+	if s.logger != nil {
+		s.logger.WarnContext(ctx, "Slow query", "query", "CreateLog")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, records...)
+	s.batches++
+	return nil
+}
+
+func (s *recordingSink) written() []LogRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LogRecord(nil), s.records...)
+}
+
+func TestDatabaseHandler(t *testing.T) {
+	handler := NewDatabaseHandler(DatabaseHandlerConfig{Level: slog.LevelInfo, SampleRatio: 1, BatchSize: 2, FlushInterval: time.Hour})
+	logger, err := New("info", "json", handler)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// Records logged before the sink is available are kept
+	logger.Info("Starting", "version", "1.0.0")
+	logger.Debug("Not persisted")
+
+	sink := &recordingSink{logger: logger}
+	handler.Start(sink, nil)
+	logger.With("component", "router").Warn("Message failed", "api_key", "sk-1234567890abcdef", "error", errors.New("timeout"))
+	logger.WarnContext(WithoutDatabase(context.Background()), "Not persisted either")
+	slog.New(handler).WithGroup("request").Error("Request failed", "status", 500)
+
+	if err := handler.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	slog.New(handler).Error("After close")
+
+	records := sink.written()
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 persisted records, got %+v", records)
+	}
+	if records[0].Level != "info" || records[0].Source != "nexflow" || records[0].Message != "Starting" || records[0].Attrs["version"] != "1.0.0" {
+		t.Errorf("Unexpected first record %+v", records[0])
+	}
+	if _, ok := records[0].Attrs["source"]; ok {
+		t.Error("Expected the source not to be repeated in the attributes")
+	}
+	if records[1].Source != "router" || records[1].Attrs["error"] != "timeout" || records[1].Attrs["api_key"] == "sk-1234567890abcdef" {
+		t.Errorf("Expected the component as source, the error message and a masked secret, got %+v", records[1])
+	}
+	if records[2].Level != "error" || records[2].Attrs["request.status"] != int64(500) {
+		t.Errorf("Expected grouped attributes to be flattened, got %+v", records[2])
+	}
+	if sink.batches != 2 {
+		t.Errorf("Expected 2 batches, got %d", sink.batches)
+	}
+}
+
+func TestDatabaseHandler_Sampling(t *testing.T) {
+	handler := NewDatabaseHandler(DatabaseHandlerConfig{Level: slog.LevelDebug, SampleRatio: 0, QueueSize: 10})
+	logger := slog.New(handler)
+
+	logger.Info("Sampled out")
+	logger.Warn("Kept")
+	for i := 0; i < 20; i++ {
+		logger.Error("Queued")
+	}
+	if handler.Dropped() != 11 {
+		t.Errorf("Expected the records beyond the queue size to be dropped, got %d", handler.Dropped())
+	}
+
+	sink := &recordingSink{}
+	handler.Start(sink, nil)
+	if err := handler.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if records := sink.written(); len(records) != 10 || records[0].Message != "Kept" {
+		t.Errorf("Expected only warnings and errors up to the queue size, got %d records", len(records))
+	}
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Levels holds the log level of the application and of the named loggers of its subsystems,
+// e.g. "router", "telegram", "llm" or "db". A module without a level of its own follows the
+// default level. Levels can be changed at runtime, e.g. to enable debug logs of one connector.
+type Levels struct {
+	defaultLevel slog.LevelVar
+
+	mu      sync.Mutex
+	modules map[string]*moduleLevel
+}
+
+// moduleLevel is the level of a module
+type moduleLevel struct {
+	set   atomic.Bool // Whether the module has a level of its own
+	level slog.LevelVar
+}
+
+// ModuleLevel is the current level of a module
+type ModuleLevel struct {
+	Name  string
+	Level slog.Level
+	// Inherited is true when the module follows the default level
+	Inherited bool
+}
+
+// NewLevels creates Levels with the default level
+func NewLevels(defaultLevel slog.Level) *Levels {
+	l := &Levels{modules: make(map[string]*moduleLevel)}
+	l.defaultLevel.Set(defaultLevel)
+	return l
+}
+
+// Default returns the default level
+func (l *Levels) Default() slog.Level {
+	return l.defaultLevel.Level()
+}
+
+// SetDefault sets the default level, which applies to all modules without a level of their own
+func (l *Levels) SetDefault(level slog.Level) {
+	l.defaultLevel.Set(level)
+}
+
+// Set sets the level of a module, registering it if it has no logger yet
+func (l *Levels) Set(module string, level slog.Level) {
+	m := l.module(module)
+	m.level.Set(level)
+	m.set.Store(true)
+}
+
+// Reset makes a module follow the default level again
+func (l *Levels) Reset(module string) {
+	l.module(module).set.Store(false)
+}
+
+// Has reports whether a module is registered, by a named logger or a level of its own
+func (l *Levels) Has(module string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.modules[module]
+	return ok
+}
+
+// Modules returns the levels of the registered modules in name order
+func (l *Levels) Modules() []ModuleLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	modules := make([]ModuleLevel, 0, len(l.modules))
+	for name, m := range l.modules {
+		modules = append(modules, ModuleLevel{Name: name, Level: l.level(m), Inherited: !m.set.Load()})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	return modules
+}
+
+// module returns the level of a module, registering it
+func (l *Levels) module(name string) *moduleLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	m, ok := l.modules[name]
+	if !ok {
+		m = &moduleLevel{}
+		l.modules[name] = m
+	}
+	return m
+}
+
+// level returns the effective level of a module; nil is the default level
+func (l *Levels) level(m *moduleLevel) slog.Level {
+	if m != nil && m.set.Load() {
+		return m.level.Level()
+	}
+	return l.defaultLevel.Level()
+}
+
+// levelHandler drops the records below the current level of its module
+type levelHandler struct {
+	slog.Handler
+	levels *Levels
+	module *moduleLevel // nil for the loggers of no module
+}
+
+// Enabled reports whether the module logs records of the level
+func (h levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.levels.level(h.module) && h.Handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a levelHandler of the same module wrapping the handler with the attributes
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), levels: h.levels, module: h.module}
+}
+
+// WithGroup returns a levelHandler of the same module wrapping the handler with the group
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), levels: h.levels, module: h.module}
+}
+
+// Named returns the logger of a subsystem: its records carry the module as "component"
+// attribute, and its level can be changed independently with Levels.Set. Loggers not created
+// by New only get the attribute.
+func Named(logger Logger, module string) Logger {
+	if l, ok := logger.(*SlogLogger); ok {
+		return l.Named(module)
+	}
+	return logger.With("component", module)
+}
+
+// LevelsOf returns the levels of a logger created by New or NewWithLevels. Other loggers have
+// no levels; for them, Levels affecting no logger are returned.
+func LevelsOf(logger Logger) *Levels {
+	if l, ok := logger.(*SlogLogger); ok && l.levels != nil {
+		return l.levels
+	}
+	return NewLevels(slog.LevelInfo)
+}
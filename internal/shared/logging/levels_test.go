@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// recordingHandler keeps the messages of the handled records
+type recordingHandler struct {
+	mu       *sync.Mutex
+	messages *[]string
+	attrs    []slog.Attr
+}
+
+func newRecordingHandler() recordingHandler {
+	return recordingHandler{mu: &sync.Mutex{}, messages: &[]string{}}
+}
+
+func (h recordingHandler) Enabled(ctx context.Context, level slog.Level) bool { return true }
+
+func (h recordingHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	msg := r.Message
+	for _, a := range h.attrs {
+		if a.Key == "component" {
+			msg = a.Value.String() + ": " + msg
+		}
+	}
+	*h.messages = append(*h.messages, msg)
+	return nil
+}
+
+func (h recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return h
+}
+
+func (h recordingHandler) WithGroup(name string) slog.Handler { return h }
+
+func (h recordingHandler) handled() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), *h.messages...)
+}
+
+func TestNamedLoggerLevels(t *testing.T) {
+	levels := NewLevels(slog.LevelInfo)
+	recorder := newRecordingHandler()
+	logger := NewWithLevels(levels, "json", recorder)
+	router := Named(logger, "router")
+	telegram := Named(logger, "telegram").With("chat_id", 42)
+
+	levels.Set("telegram", slog.LevelDebug)
+	logger.Debug("Hidden")
+	router.Debug("Hidden")
+	telegram.Debug("Update received")
+	router.Info("Message routed")
+
+	levels.Reset("telegram")
+	levels.SetDefault(slog.LevelWarn)
+	telegram.Debug("Hidden")
+	router.Info("Hidden")
+	logger.Warn("Rate limited")
+
+	got := recorder.handled()
+	want := []string{"telegram: Update received", "router: Message routed", "Rate limited"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], got[i])
+		}
+	}
+
+	levels.Set("db", slog.LevelError)
+	modules := levels.Modules()
+	if len(modules) != 3 || modules[0].Name != "db" || modules[0].Inherited || modules[1].Name != "router" || !modules[1].Inherited || modules[1].Level != slog.LevelWarn {
+		t.Errorf("Unexpected module levels %+v", modules)
+	}
+	if !levels.Has("telegram") || levels.Has("discord") {
+		t.Error("Expected only modules with a logger or a level to be registered")
+	}
+
+	// Loggers not created by New only get the component attribute
+	if Named(NewNoopLogger(), "router") == nil {
+		t.Error("Expected a logger")
+	}
+}
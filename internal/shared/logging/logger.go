@@ -3,6 +3,7 @@ package logging
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
@@ -36,49 +37,87 @@ type Logger interface {
 type SlogLogger struct {
 	logger *slog.Logger
 	ctx    context.Context
+	levels *Levels
 }
 
-// New creates a new logger with the specified level and format
-func New(level string, format string) (Logger, error) {
+// New creates a new logger with the specified level and format. Records are also passed to
+// handlers, e.g. a DatabaseHandler, which may filter them further by their own level.
+func New(level string, format string, handlers ...slog.Handler) (Logger, error) {
 	// Parse log level
-	logLevel, err := parseLevel(level)
+	logLevel, err := ParseLevel(level)
 	if err != nil {
 		return nil, fmt.Errorf("invalid log level: %w", err)
 	}
 
-	// Create handler options
+	return NewWithLevels(NewLevels(logLevel), format, handlers...), nil
+}
+
+// NewWithLevels creates a new logger with the specified format whose level and the levels of
+// its named loggers are taken from levels, so that they can be changed at runtime
+func NewWithLevels(levels *Levels, format string, handlers ...slog.Handler) Logger {
+	return NewWithHandlers(levels, append([]slog.Handler{NewHandler(os.Stdout, format)}, handlers...)...)
+}
+
+// NewHandler creates a handler writing records to w in the specified format, "json" or
+// "text", with secrets masked by the DefaultMasker
+func NewHandler(w io.Writer, format string) slog.Handler {
+	return NewHandlerWithMasker(w, format, nil)
+}
+
+// NewHandlerWithMasker creates a handler writing records to w in the specified format, "json"
+// or "text", with secrets in messages and attributes masked by masker; nil uses the DefaultMasker
+func NewHandlerWithMasker(w io.Writer, format string, masker *Masker) slog.Handler {
+	if masker == nil {
+		masker = DefaultMasker()
+	}
+
+	// Create handler options; records are filtered by the levels before reaching the handler
 	opts := &slog.HandlerOptions{
-		Level: logLevel,
+		Level: slog.LevelDebug,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-			// Mask secret fields
-			if shouldMask(a.Key) {
-				return slog.String(a.Key, maskValue(a.Value.String()))
+			// The time and level are never secrets; the message and other attributes may be
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return a
 			}
-			return a
+			return masker.MaskAttr(a)
 		},
 	}
 
 	// Create handler based on format
-	var handler slog.Handler
 	if strings.ToLower(format) == "json" {
-		handler = slog.NewJSONHandler(os.Stdout, opts)
-	} else {
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		return slog.NewJSONHandler(w, opts)
 	}
+	return slog.NewTextHandler(w, opts)
+}
 
-	// Add default attributes
-	handler = handler.WithAttrs([]slog.Attr{
+// NewWithHandlers creates a new logger passing records to handlers, e.g. a console handler
+// created with NewHandler, a log file and a DatabaseHandler. Without handlers records are
+// discarded.
+func NewWithHandlers(levels *Levels, handlers ...slog.Handler) Logger {
+	var handler slog.Handler
+	switch len(handlers) {
+	case 0:
+		handler = slog.DiscardHandler
+	case 1:
+		handler = handlers[0]
+	default:
+		handler = teeHandler(handlers)
+	}
+
+	// Add default attributes and the trace of the context
+	handler = traceHandler{Handler: handler.WithAttrs([]slog.Attr{
 		slog.String("source", "nexflow"),
-	})
+	})}
 
 	return &SlogLogger{
-		logger: slog.New(handler),
+		logger: slog.New(levelHandler{Handler: handler, levels: levels}),
 		ctx:    context.Background(),
-	}, nil
+		levels: levels,
+	}
 }
 
-// parseLevel converts a string level to slog.Level
-func parseLevel(level string) (slog.Level, error) {
+// ParseLevel converts a string level to slog.Level
+func ParseLevel(level string) (slog.Level, error) {
 	switch strings.ToLower(level) {
 	case "debug":
 		return slog.LevelDebug, nil
@@ -120,6 +159,21 @@ func (l *SlogLogger) With(args ...any) Logger {
 	return &SlogLogger{
 		logger: l.logger.With(args...),
 		ctx:    l.ctx,
+		levels: l.levels,
+	}
+}
+
+// Named returns the logger of a subsystem, see Named
+func (l *SlogLogger) Named(module string) Logger {
+	handler := l.logger.Handler()
+	if h, ok := handler.(levelHandler); ok {
+		handler = h.Handler
+	}
+	handler = handler.WithAttrs([]slog.Attr{slog.String("component", module)})
+	return &SlogLogger{
+		logger: slog.New(levelHandler{Handler: handler, levels: l.levels, module: l.levels.module(module)}),
+		ctx:    l.ctx,
+		levels: l.levels,
 	}
 }
 
@@ -128,6 +182,7 @@ func (l *SlogLogger) WithContext(ctx context.Context) Logger {
 	return &SlogLogger{
 		logger: l.logger,
 		ctx:    ctx,
+		levels: l.levels,
 	}
 }
 
@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
 )
 
 func TestNew(t *testing.T) {
@@ -199,6 +201,43 @@ func TestLoggerOutput(t *testing.T) {
 	}
 }
 
+func TestLoggerTraceIDs(t *testing.T) {
+	// Capture stdout
+	var buf bytes.Buffer
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	logger, err := New("info", "json")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// Log within a trace
+	sc, _ := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.ContextWithRemoteSpanContext(WithRequestID(context.Background(), "req-1"), sc)
+	logger.With("component", "router").WithContext(ctx).Info("Traced message")
+
+	// Restore stdout and capture output
+	w.Close()
+	os.Stdout = old
+	_, _ = buf.ReadFrom(r)
+
+	var logEntry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to parse log output as JSON: %v", err)
+	}
+	if logEntry["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || logEntry["span_id"] != "00f067aa0ba902b7" {
+		t.Errorf("Expected the trace IDs in the log output, got %v", logEntry)
+	}
+	if logEntry["request_id"] != "req-1" {
+		t.Errorf("Expected request_id 'req-1', got %v", logEntry["request_id"])
+	}
+	if logEntry["component"] != "router" {
+		t.Errorf("Expected component 'router', got %v", logEntry["component"])
+	}
+}
+
 func TestSecretMaskingInLogs(t *testing.T) {
 	// Capture stdout
 	var buf bytes.Buffer
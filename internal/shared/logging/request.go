@@ -0,0 +1,19 @@
+package logging
+
+import "context"
+
+// requestIDContextKey is the context key of the ID of the request being handled
+type requestIDContextKey struct{}
+
+// WithRequestID returns a context carrying the ID of the request it is used for.
+// Records logged with the context get the request_id attribute.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the ID of the request a context is used for,
+// or an empty string outside of a request
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,266 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp in the names of rotated log files, e.g.
+// "nexflow-2026-01-02T15-04-05.000.log"
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotationConfig represents the rotation and retention policy of a RotatingFile
+type RotationConfig struct {
+	// MaxSize is the size in bytes at which the file is rotated; 0 disables size-based rotation
+	MaxSize int64
+	// Interval rotates the file at multiples of the interval in UTC, e.g. 24h rotates it at
+	// midnight; 0 disables time-based rotation
+	Interval time.Duration
+	// MaxBackups is the number of rotated files kept; 0 keeps all
+	MaxBackups int
+	// MaxAge is the age after which rotated files are removed; 0 keeps them regardless of age
+	MaxAge time.Duration
+	// Compress compresses the rotated files with gzip
+	Compress bool
+}
+
+// RotatingFile is an io.Writer appending to a log file that is rotated by size and time.
+// A rotated file is renamed with the time of the rotation, e.g. "nexflow-2026-01-02T15-04-05.000.log",
+// and a new file is started. Compression and removal of old files run in the background.
+type RotatingFile struct {
+	path   string
+	config RotationConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	rotateAt time.Time // Zero without time-based rotation
+
+	millMu sync.Mutex // Serializes compression and removal of rotated files
+	mills  sync.WaitGroup
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating it and its directory
+// if needed
+func OpenRotatingFile(path string, config RotationConfig) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, config: config, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if p would exceed the maximum size or the
+// rotation interval has passed
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	due := !f.rotateAt.IsZero() && !f.now().Before(f.rotateAt)
+	tooLarge := f.config.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.config.MaxSize
+	if due || tooLarge {
+		// Keep writing to the current file if it cannot be rotated, rather than losing records
+		if err := f.rotate(); err != nil && f.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file immediately
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Close closes the file and waits for the compression and removal of rotated files
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.mills.Wait()
+	return err
+}
+
+// open opens the file, taking its size and the time of its last change into account
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.rotateAt = time.Time{}
+	if f.config.Interval > 0 {
+		started := f.now()
+		if f.size > 0 {
+			started = info.ModTime()
+		}
+		f.rotateAt = started.UTC().Truncate(f.config.Interval).Add(f.config.Interval)
+	}
+	return nil
+}
+
+// rotate renames the file, opens a new one and cleans up the rotated files in the background
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+
+	backup := f.backupName(f.now())
+	renameErr := os.Rename(f.path, backup)
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil && !os.IsNotExist(renameErr) {
+		return fmt.Errorf("failed to rotate log file: %w", renameErr)
+	}
+
+	f.mills.Add(1)
+	go func() {
+		defer f.mills.Done()
+		f.mill()
+	}()
+	return nil
+}
+
+// backupName returns the name of the file rotated at t
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	return filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+// nameParts splits the path into the directory, the prefix of rotated files and the extension
+func (f *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.path)
+	base := filepath.Base(f.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backup is a rotated log file
+type backup struct {
+	path    string
+	rotated time.Time
+}
+
+// backups returns the rotated files, newest first
+func (f *RotatingFile) backups() ([]backup, error) {
+	dir, prefix, ext := f.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		rotated, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), rotated: rotated})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.After(backups[j].rotated) })
+	return backups, nil
+}
+
+// mill compresses the rotated files and removes those beyond the retention policy.
+// Errors are ignored; the files are retried at the next rotation.
+func (f *RotatingFile) mill() {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	backups, err := f.backups()
+	if err != nil {
+		return
+	}
+
+	cutoff := time.Time{}
+	if f.config.MaxAge > 0 {
+		cutoff = f.now().Add(-f.config.MaxAge)
+	}
+	for i, b := range backups {
+		expired := !cutoff.IsZero() && b.rotated.Before(cutoff)
+		if expired || (f.config.MaxBackups > 0 && i >= f.config.MaxBackups) {
+			_ = os.Remove(b.path)
+			continue
+		}
+		if f.config.Compress && !strings.HasSuffix(b.path, ".gz") {
+			_ = compressFile(b.path)
+		}
+	}
+}
+
+// compressFile compresses a file with gzip and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logFiles returns the names of the files in dir
+func logFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingFile_Size(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "nexflow.log")
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	f, err := OpenRotatingFile(path, RotationConfig{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	f.now = func() time.Time { return now }
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Each line exceeds the size together with the previous one; only 2 rotated files are kept
+	want := []string{"nexflow-2026-01-02T15-04-07.000.log", "nexflow-2026-01-02T15-04-08.000.log", "nexflow.log"}
+	got := logFiles(t, filepath.Dir(path))
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected files %v, got %v", want, got)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "fourth\n" {
+		t.Errorf("Expected the last line in the current file, got %q", data)
+	}
+	if _, err := f.Write([]byte("closed\n")); err == nil {
+		t.Error("Expected Write after Close to fail")
+	}
+}
+
+func TestRotatingFile_IntervalAndCompression(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nexflow.log")
+	now := time.Date(2026, 1, 2, 23, 0, 0, 0, time.UTC)
+
+	// An expired rotated file from an earlier run
+	old := filepath.Join(dir, "nexflow-2025-12-01T00-00-00.000.log.gz")
+	if err := os.WriteFile(old, nil, 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", old, err)
+	}
+
+	f := &RotatingFile{path: path, config: RotationConfig{Interval: 24 * time.Hour, MaxAge: 7 * 24 * time.Hour, Compress: true}, now: func() time.Time { return now }}
+	if err := f.open(); err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+
+	_, _ = f.Write([]byte("before midnight\n"))
+	now = now.Add(2 * time.Hour)
+	_, _ = f.Write([]byte("after midnight\n"))
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	want := []string{"nexflow-2026-01-03T01-00-00.000.log.gz", "nexflow.log"}
+	got := logFiles(t, dir)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected files %v, got %v", want, got)
+	}
+
+	file, err := os.Open(filepath.Join(dir, want[0]))
+	if err != nil {
+		t.Fatalf("Failed to open the rotated file: %v", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Expected a gzip file: %v", err)
+	}
+	data, _ := io.ReadAll(gz)
+	if string(data) != "before midnight\n" {
+		t.Errorf("Unexpected rotated content %q", data)
+	}
+}
@@ -1,55 +1,166 @@
 package logging
 
-import "strings"
-
-// secretFields contains patterns that identify secret fields
-var secretFields = map[string]bool{
-	"api_key":       true,
-	"apikey":        true,
-	"apiKey":        true,
-	"token":         true,
-	"access_token":  true,
-	"accesstoken":   true,
-	"accessToken":   true,
-	"refresh_token": true,
-	"refreshtoken":  true,
-	"refreshToken":  true,
-	"password":      true,
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// secretKeys are the names of attributes whose values are masked. Names are normalized by
+// normalizeKey, so "api_key", "apiKey" and "API-Key" are the same name.
+var secretKeys = map[string]bool{
 	"pass":          true,
-	"secret":        true,
-	"private_key":   true,
-	"privatekey":    true,
-	"privateKey":    true,
-	"auth_token":    true,
-	"authtoken":     true,
-	"authToken":     true,
-	"bot_token":     true,
-	"bottoken":      true,
-	"botToken":      true,
-	"bearer_token":  true,
-	"bearertoken":   true,
-	"bearerToken":   true,
-	"client_secret": true,
-	"clientsecret":  true,
-	"clientSecret":  true,
-	"api_secret":    true,
-	"apisecret":     true,
-	"apiSecret":     true,
-	"session_token": true,
-	"sessiontoken":  true,
-	"sessionToken":  true,
-	"csrf_token":    true,
-	"csrftoken":     true,
-	"csrfToken":     true,
+	"passwd":        true,
 	"authorization": true,
 	"credentials":   true,
 	"credential":    true,
 }
 
+// secretKeySuffixes mask the values of attributes whose normalized name ends with one of them,
+// e.g. "bot_token", "client_secret" or "openai_api_key"
+var secretKeySuffixes = []string{
+	"token",
+	"secret",
+	"password",
+	"apikey",
+	"privatekey",
+}
+
+// secretPatterns match secrets by their format wherever they appear in messages and string
+// values. When a pattern has a capture group, only the group is masked.
+var secretPatterns = []string{
+	`\bsk-[A-Za-z0-9_-]{16,}`,                                // OpenAI and Anthropic API keys
+	`\d{6,12}:[A-Za-z0-9_-]{30,}`,                            // Telegram bot tokens, also in API URLs
+	`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`, // JWTs
+	`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{8,}=*)`,               // Bearer credentials
+	`\bgh[pousr]_[A-Za-z0-9]{36,}`,                           // GitHub tokens
+	`\bxox[abposr]-[A-Za-z0-9-]{10,}`,                        // Slack tokens
+	`\bAKIA[0-9A-Z]{16}\b`,                                   // AWS access key IDs
+}
+
+// defaultMasker masks the built-in key names and patterns
+var defaultMasker = mustMasker(NewMasker(nil, nil))
+
+// Masker masks secrets in log records: the values of attributes whose name denotes a secret,
+// e.g. "api_key", "bot_token" or "password", and strings in the format of a known token, e.g. an
+// OpenAI key, a Telegram bot token or a JWT, wherever they appear in messages and values.
+// Further key names and patterns can be added, e.g. from the configuration.
+type Masker struct {
+	keys     map[string]bool // Further normalized key names
+	patterns []*regexp.Regexp
+}
+
+// NewMasker creates a Masker masking the built-in key names and patterns and the specified ones.
+// Key names are matched case-insensitively, ignoring "_" and "-". When a pattern has a capture
+// group, only the group is masked.
+func NewMasker(keys, patterns []string) (*Masker, error) {
+	m := &Masker{keys: make(map[string]bool, len(keys))}
+	for _, key := range keys {
+		m.keys[normalizeKey(key)] = true
+	}
+	for _, pattern := range append(append([]string(nil), secretPatterns...), patterns...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid masking pattern %q: %w", pattern, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// DefaultMasker returns the Masker of the built-in key names and patterns
+func DefaultMasker() *Masker {
+	return defaultMasker
+}
+
+// MaskKey reports whether the values of attributes with the key are masked
+func (m *Masker) MaskKey(key string) bool {
+	name := normalizeKey(key)
+	if secretKeys[name] || m.keys[name] {
+		return true
+	}
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskString masks the parts of s matching a pattern
+func (m *Masker) MaskString(s string) string {
+	for _, re := range m.patterns {
+		s = maskMatches(re, s)
+	}
+	return s
+}
+
+// MaskAttr returns the attribute with its value masked if its key denotes a secret, or with the
+// secrets in a string or error value masked
+func (m *Masker) MaskAttr(a slog.Attr) slog.Attr {
+	if m.MaskKey(a.Key) {
+		return slog.String(a.Key, maskValue(a.Value.String()))
+	}
+
+	var s string
+	switch a.Value.Kind() {
+	case slog.KindString:
+		s = a.Value.String()
+	case slog.KindAny:
+		err, ok := a.Value.Any().(error)
+		if !ok {
+			return a
+		}
+		s = err.Error()
+	default:
+		return a
+	}
+	if masked := m.MaskString(s); masked != s {
+		return slog.String(a.Key, masked)
+	}
+	return a
+}
+
+// maskMatches masks the matches of re in s, or their first capture group
+func maskMatches(re *regexp.Regexp, s string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if len(match) >= 4 && match[2] >= 0 {
+			start, end = match[2], match[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(maskValue(s[start:end]))
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// normalizeKey returns the key in lower case without "_" and "-"
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+// mustMasker panics if the built-in patterns are invalid
+func mustMasker(m *Masker, err error) *Masker {
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
 // shouldMask checks if a field key should have its value masked
 func shouldMask(key string) bool {
-	lowerKey := strings.ToLower(key)
-	return secretFields[lowerKey]
+	return defaultMasker.MaskKey(key)
 }
 
 // maskValue masks a secret value by showing only first and last characters
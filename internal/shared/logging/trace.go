@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/atumaikin/nexflow/internal/shared/tracing"
+)
+
+// traceHandler adds the request_id and the trace_id and span_id of the span carried by the
+// context of a record, so that log lines can be correlated with requests and traces
+type traceHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request and trace attributes and passes the record on
+func (h traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String("request_id", requestID))
+	}
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID.String()),
+			slog.String("span_id", sc.SpanID.String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a traceHandler wrapping the handler with the attributes
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a traceHandler wrapping the handler with the group
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Exporter pushes the metrics of registries to a monitoring backend, as an alternative to
+// scraping WritePrometheus output
+type Exporter interface {
+	// Export sends the current values of the metrics of the registries
+	Export(ctx context.Context, registries []*MetricsRegistry) error
+}
+
+// Label is a label of a metric, parsed from its name
+type Label struct {
+	Name  string
+	Value string
+}
+
+// sample is a metric of a registry with its name split into the metric family name and the
+// parsed labels
+type sample struct {
+	family string
+	labels []Label
+	metric any
+}
+
+// collect returns the metrics of the registries in name order
+func collect(registries []*MetricsRegistry) []sample {
+	var samples []sample
+	for _, registry := range registries {
+		if registry == nil {
+			continue
+		}
+		registry.Each(func(name string, metric any) {
+			family, labels := splitMetricName(name)
+			samples = append(samples, sample{family: family, labels: parseLabels(labels), metric: metric})
+		})
+	}
+	return samples
+}
+
+// parseLabels parses label pairs in Prometheus syntax, e.g. `query="GetUser",le="0.1"`.
+// Pairs that cannot be parsed are skipped.
+func parseLabels(labels string) []Label {
+	var out []Label
+	for labels != "" {
+		name, rest, ok := strings.Cut(labels, "=")
+		if !ok || !strings.HasPrefix(rest, `"`) {
+			return out
+		}
+
+		// Find the closing quote, skipping escaped characters
+		end := 1
+		for end < len(rest) && rest[end] != '"' {
+			if rest[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(rest) {
+			return out
+		}
+		value, err := strconv.Unquote(rest[:end+1])
+		if err != nil {
+			return out
+		}
+		out = append(out, Label{Name: strings.TrimSpace(name), Value: value})
+		labels = strings.TrimPrefix(rest[end+1:], ",")
+	}
+	return out
+}
+
+// histogramSnapshot returns the bucket bounds, the non-cumulative counts of the buckets
+// including the +Inf bucket, the sum and the count of a histogram
+func histogramSnapshot(h *Histogram) (bounds []float64, counts []int64, sum float64, count int64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var previous int64
+	for i := range h.buckets {
+		cumulative := h.buckets[i].count.Load()
+		if h.buckets[i].value != -1 {
+			bounds = append(bounds, h.buckets[i].value)
+		}
+		counts = append(counts, cumulative-previous)
+		previous = cumulative
+	}
+	return bounds, counts, h.sum, h.count.Load()
+}
+
+// Pusher exports the metrics of registries in an interval
+type Pusher struct {
+	exporter   Exporter
+	registries []*MetricsRegistry
+	interval   time.Duration
+	onError    func(error)
+
+	started atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewPusher creates a Pusher exporting the registries every interval. onError is called with
+// export errors, e.g. to log them; nil ignores them.
+func NewPusher(exporter Exporter, interval time.Duration, onError func(error), registries ...*MetricsRegistry) *Pusher {
+	return &Pusher{
+		exporter:   exporter,
+		registries: registries,
+		interval:   interval,
+		onError:    onError,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start starts exporting in the background
+func (p *Pusher) Start() {
+	if p.started.CompareAndSwap(false, true) {
+		go p.run()
+	}
+}
+
+// Stop stops exporting after a final export, so that the last values are not lost, and waits
+// until it is done or ctx is done
+func (p *Pusher) Stop(ctx context.Context) error {
+	if !p.started.Load() {
+		return nil
+	}
+	p.once.Do(func() { close(p.stop) })
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run exports the metrics every interval until the pusher is stopped
+func (p *Pusher) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.export()
+		case <-p.stop:
+			p.export()
+			return
+		}
+	}
+}
+
+// export exports the metrics once; a single export is limited to the interval
+func (p *Pusher) export() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+	if err := p.exporter.Export(ctx, p.registries); err != nil && p.onError != nil {
+		p.onError(err)
+	}
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingExporter counts the exports
+type countingExporter struct {
+	exports atomic.Int64
+}
+
+func (e *countingExporter) Export(ctx context.Context, registries []*MetricsRegistry) error {
+	e.exports.Add(1)
+	return nil
+}
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		labels string
+		want   []Label
+	}{
+		{``, nil},
+		{`query="GetUser"`, []Label{{Name: "query", Value: "GetUser"}}},
+		{`provider="openai",le="0.1"`, []Label{{Name: "provider", Value: "openai"}, {Name: "le", Value: "0.1"}}},
+		{`path="/a\"b,c"`, []Label{{Name: "path", Value: `/a"b,c`}}},
+		{`query=GetUser`, nil},
+		{`query="GetUser`, nil},
+	}
+	for _, tt := range tests {
+		if got := parseLabels(tt.labels); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseLabels(%q) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestPusher(t *testing.T) {
+	exporter := &countingExporter{}
+	pusher := NewPusher(exporter, 10*time.Millisecond, nil, NewMetricsRegistry())
+
+	// Stopping a pusher that was not started does nothing
+	if err := pusher.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	pusher = NewPusher(exporter, 10*time.Millisecond, nil, NewMetricsRegistry())
+	pusher.Start()
+	time.Sleep(35 * time.Millisecond)
+	if err := pusher.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	exports := exporter.exports.Load()
+	if exports < 2 {
+		t.Errorf("Expected exports every interval and on Stop, got %d", exports)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if exporter.exports.Load() != exports {
+		t.Error("Expected no exports after Stop")
+	}
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,6 +38,31 @@ func (c *Counter) Reset() {
 	c.value.Store(0)
 }
 
+// Gauge is a value that can go up and down, such as a number of open connections
+type Gauge struct {
+	value atomic.Int64
+}
+
+// NewGauge creates a new gauge
+func NewGauge() *Gauge {
+	return &Gauge{}
+}
+
+// Set sets the gauge to the given value
+func (g *Gauge) Set(v int64) {
+	g.value.Store(v)
+}
+
+// Add adds the given value to the gauge (negative values decrease it)
+func (g *Gauge) Add(v int64) {
+	g.value.Add(v)
+}
+
+// Get returns the current value of the gauge
+func (g *Gauge) Get() int64 {
+	return g.value.Load()
+}
+
 // Histogram tracks a distribution of values (typically for timing)
 type Histogram struct {
 	count   atomic.Int64
@@ -113,7 +139,8 @@ func (h *Histogram) GetBuckets() map[string]int64 {
 	defer h.mu.RUnlock()
 
 	result := make(map[string]int64)
-	for _, b := range h.buckets {
+	for i := range h.buckets {
+		b := &h.buckets[i]
 		key := "+Inf"
 		if b.value != -1 {
 			key = formatFloat64(b.value)
@@ -169,6 +196,22 @@ func (r *MetricsRegistry) GetCounter(name string) *Counter {
 	return c
 }
 
+// GetGauge retrieves or creates a gauge
+func (r *MetricsRegistry) GetGauge(name string) *Gauge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m, exists := r.metrics[name]; exists {
+		if g, ok := m.(*Gauge); ok {
+			return g
+		}
+	}
+
+	g := NewGauge()
+	r.metrics[name] = g
+	return g
+}
+
 // GetHistogram retrieves or creates a histogram
 func (r *MetricsRegistry) GetHistogram(name string, buckets []float64) *Histogram {
 	r.mu.Lock()
@@ -185,6 +228,25 @@ func (r *MetricsRegistry) GetHistogram(name string, buckets []float64) *Histogra
 	return h
 }
 
+// Each calls fn for every metric in name order
+func (r *MetricsRegistry) Each(fn func(name string, metric any)) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	metrics := make(map[string]any, len(r.metrics))
+	for name, metric := range r.metrics {
+		metrics[name] = metric
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		fn(name, metrics[name])
+	}
+}
+
 // Snapshot returns a snapshot of all metrics
 func (r *MetricsRegistry) Snapshot() map[string]any {
 	r.mu.RLock()
@@ -195,6 +257,8 @@ func (r *MetricsRegistry) Snapshot() map[string]any {
 		switch m := v.(type) {
 		case *Counter:
 			snapshot[k] = m.Get()
+		case *Gauge:
+			snapshot[k] = m.Get()
 		case *Histogram:
 			snapshot[k] = map[string]any{
 				"count":   m.Count(),
@@ -120,6 +120,26 @@ func TestMetricsRegistry_GetCounter(t *testing.T) {
 	}
 }
 
+func TestGauge(t *testing.T) {
+	g := NewGauge()
+
+	g.Set(5)
+	g.Add(-2)
+	if g.Get() != 3 {
+		t.Errorf("Expected gauge to be 3, got %d", g.Get())
+	}
+
+	r := NewMetricsRegistry()
+	if r.GetGauge("test_gauge") != r.GetGauge("test_gauge") {
+		t.Error("Expected same gauge instance")
+	}
+
+	r.GetGauge("test_gauge").Set(7)
+	if r.Snapshot()["test_gauge"].(int64) != 7 {
+		t.Errorf("Expected gauge snapshot to be 7, got %v", r.Snapshot()["test_gauge"])
+	}
+}
+
 func TestMetricsRegistry_GetHistogram(t *testing.T) {
 	r := NewMetricsRegistry()
 
@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// otlpMetricsPath is the path of the OTLP/HTTP metrics endpoint
+const otlpMetricsPath = "/v1/metrics"
+
+// OTLP aggregation temporality of sums and histograms: the metrics of a registry only grow,
+// so they are exported as totals since the exporter was created
+const otlpCumulative = 2
+
+// OTLPExporter exports metrics to an OpenTelemetry collector, or a backend accepting OTLP such as
+// Grafana Cloud or Datadog, over OTLP/HTTP with the JSON encoding. Counters are exported as
+// monotonic sums, gauges as gauges and histograms as explicit-bucket histograms; the labels of
+// a metric name become attributes.
+type OTLPExporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+	start       time.Time
+}
+
+// NewOTLPExporter creates an exporter sending metrics to endpoint, the base URL of an OTLP/HTTP
+// receiver, e.g. "http://localhost:4318"; "/v1/metrics" is appended unless the URL already
+// ends with it. headers are added to every request, e.g. for API keys of hosted backends.
+func NewOTLPExporter(endpoint, serviceName string, headers map[string]string, client *http.Client) *OTLPExporter {
+	endpoint = strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(endpoint, otlpMetricsPath) {
+		endpoint += otlpMetricsPath
+	}
+	return &OTLPExporter{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: serviceName,
+		client:      client,
+		start:       time.Now(),
+	}
+}
+
+// Export implements Exporter
+func (e *OTLPExporter) Export(ctx context.Context, registries []*MetricsRegistry) error {
+	body, err := json.Marshal(e.request(collect(registries), time.Now()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("OTLP receiver returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// OTLP/JSON messages of ExportMetricsServiceRequest. 64-bit integers are strings, as the
+// OTLP/JSON encoding requires.
+type (
+	otlpMetricsRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpMetric struct {
+		Name      string         `json:"name"`
+		Sum       *otlpSum       `json:"sum,omitempty"`
+		Gauge     *otlpGauge     `json:"gauge,omitempty"`
+		Histogram *otlpHistogram `json:"histogram,omitempty"`
+	}
+	otlpSum struct {
+		DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+		AggregationTemporality int                   `json:"aggregationTemporality"`
+		IsMonotonic            bool                  `json:"isMonotonic"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+	}
+	otlpHistogram struct {
+		DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+		AggregationTemporality int                      `json:"aggregationTemporality"`
+	}
+	otlpNumberDataPoint struct {
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string         `json:"timeUnixNano"`
+		AsInt             string         `json:"asInt"`
+	}
+	otlpHistogramDataPoint struct {
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		TimeUnixNano      string         `json:"timeUnixNano"`
+		Count             string         `json:"count"`
+		Sum               float64        `json:"sum"`
+		BucketCounts      []string       `json:"bucketCounts"`
+		ExplicitBounds    []float64      `json:"explicitBounds"`
+	}
+	otlpKeyValue struct {
+		Key   string          `json:"key"`
+		Value otlpStringValue `json:"value"`
+	}
+	otlpStringValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// request builds the export request of the samples; samples of the same family become the
+// data points of one metric
+func (e *OTLPExporter) request(samples []sample, now time.Time) otlpMetricsRequest {
+	start := strconv.FormatInt(e.start.UnixNano(), 10)
+	timestamp := strconv.FormatInt(now.UnixNano(), 10)
+
+	var metrics []otlpMetric
+	index := make(map[string]int)
+	for _, s := range samples {
+		i, ok := index[s.family]
+		if !ok {
+			i = len(metrics)
+			index[s.family] = i
+			metrics = append(metrics, otlpMetric{Name: s.family})
+		}
+		metric := &metrics[i]
+		attrs := otlpLabels(s.labels)
+
+		switch m := s.metric.(type) {
+		case *Counter:
+			if metric.Sum == nil {
+				metric.Sum = &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+			}
+			metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpNumberDataPoint{
+				Attributes:        attrs,
+				StartTimeUnixNano: start,
+				TimeUnixNano:      timestamp,
+				AsInt:             strconv.FormatInt(m.Get(), 10),
+			})
+		case *Gauge:
+			if metric.Gauge == nil {
+				metric.Gauge = &otlpGauge{}
+			}
+			metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpNumberDataPoint{
+				Attributes:   attrs,
+				TimeUnixNano: timestamp,
+				AsInt:        strconv.FormatInt(m.Get(), 10),
+			})
+		case *Histogram:
+			if metric.Histogram == nil {
+				metric.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulative}
+			}
+			bounds, counts, sum, count := histogramSnapshot(m)
+			bucketCounts := make([]string, len(counts))
+			for j, c := range counts {
+				bucketCounts[j] = strconv.FormatInt(c, 10)
+			}
+			metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, otlpHistogramDataPoint{
+				Attributes:        attrs,
+				StartTimeUnixNano: start,
+				TimeUnixNano:      timestamp,
+				Count:             strconv.FormatInt(count, 10),
+				Sum:               sum,
+				BucketCounts:      bucketCounts,
+				ExplicitBounds:    bounds,
+			})
+		}
+	}
+
+	return otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     otlpResource{Attributes: []otlpKeyValue{{Key: "service.name", Value: otlpStringValue{StringValue: e.serviceName}}}},
+		ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: "github.com/atumaikin/nexflow"}, Metrics: metrics}},
+	}}}
+}
+
+// otlpLabels converts the labels of a metric to OTLP attributes
+func otlpLabels(labels []Label) []otlpKeyValue {
+	if len(labels) == 0 {
+		return nil
+	}
+	attrs := make([]otlpKeyValue, len(labels))
+	for i, label := range labels {
+		attrs[i] = otlpKeyValue{Key: label.Name, Value: otlpStringValue{StringValue: label.Value}}
+	}
+	return attrs
+}
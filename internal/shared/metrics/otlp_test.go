@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var body otlpMetricsRequest
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		header = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	llm := NewMetricsRegistry()
+	llm.GetCounter(`llm_requests_total{provider="openai"}`).Add(3)
+	llm.GetCounter(`llm_requests_total{provider="ollama"}`).Inc()
+	llm.GetHistogram(`llm_request_duration_seconds{provider="openai"}`, []float64{0.1, 1}).Observe(0.5)
+	router := NewMetricsRegistry()
+	router.GetGauge("router_messages_in_flight").Set(2)
+
+	exporter := NewOTLPExporter(server.URL, "nexflow", map[string]string{"Authorization": "Basic token"}, server.Client())
+	if err := exporter.Export(context.Background(), []*MetricsRegistry{llm, nil, router}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if header.Get("Authorization") != "Basic token" || header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected request headers %v", header)
+	}
+
+	resource := body.ResourceMetrics[0]
+	if resource.Resource.Attributes[0].Value.StringValue != "nexflow" {
+		t.Errorf("Expected the service name as resource attribute, got %+v", resource.Resource)
+	}
+	metrics := resource.ScopeMetrics[0].Metrics
+	if len(metrics) != 3 {
+		t.Fatalf("Expected 3 metrics, got %+v", metrics)
+	}
+
+	histogram := metrics[0].Histogram
+	if metrics[0].Name != "llm_request_duration_seconds" || histogram == nil {
+		t.Fatalf("Expected the duration histogram first, got %+v", metrics[0])
+	}
+	point := histogram.DataPoints[0]
+	if point.Count != "1" || point.Sum != 0.5 || !reflect.DeepEqual(point.BucketCounts, []string{"0", "1", "0"}) ||
+		!reflect.DeepEqual(point.ExplicitBounds, []float64{0.1, 1}) || point.Attributes[0].Value.StringValue != "openai" {
+		t.Errorf("Unexpected histogram data point %+v", point)
+	}
+
+	sum := metrics[1].Sum
+	if metrics[1].Name != "llm_requests_total" || sum == nil || !sum.IsMonotonic || sum.AggregationTemporality != otlpCumulative || len(sum.DataPoints) != 2 {
+		t.Fatalf("Expected the request counter as a cumulative sum with two data points, got %+v", metrics[1])
+	}
+	if sum.DataPoints[0].Attributes[0].Value.StringValue != "ollama" || sum.DataPoints[0].AsInt != "1" || sum.DataPoints[1].AsInt != "3" {
+		t.Errorf("Unexpected sum data points %+v", sum.DataPoints)
+	}
+
+	if metrics[2].Gauge == nil || metrics[2].Gauge.DataPoints[0].AsInt != "2" {
+		t.Errorf("Expected the in-flight gauge, got %+v", metrics[2])
+	}
+
+	failing := NewOTLPExporter(server.URL+"/other", "nexflow", nil, server.Client())
+	if err := failing.Export(context.Background(), []*MetricsRegistry{llm}); err == nil {
+		t.Error("Expected an error response to fail the export")
+	}
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// prometheusSample is a metric of a registry with its name split into the metric
+// family name and the labels, e.g. `database_query_errors_total{query="GetUser"}`
+type prometheusSample struct {
+	family string
+	labels string // Label pairs without braces, empty if none
+	metric any
+}
+
+// WritePrometheus writes the metrics of the registries in the Prometheus text exposition format.
+// Metric names may carry labels in Prometheus syntax; metrics with the same name and different
+// labels are written as one metric family.
+func WritePrometheus(w io.Writer, registries ...*MetricsRegistry) error {
+	families := make(map[string][]prometheusSample)
+	for _, registry := range registries {
+		if registry == nil {
+			continue
+		}
+		registry.Each(func(name string, metric any) {
+			family, labels := splitMetricName(name)
+			families[family] = append(families[family], prometheusSample{family: family, labels: labels, metric: metric})
+		})
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		samples := families[name]
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].labels < samples[j].labels })
+
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, prometheusType(samples[0].metric))
+		for _, sample := range samples {
+			writePrometheusSample(bw, sample)
+		}
+	}
+	return bw.Flush()
+}
+
+// splitMetricName splits `name{labels}` into the name and the labels
+func splitMetricName(name string) (string, string) {
+	family, labels, ok := strings.Cut(name, "{")
+	if !ok {
+		return name, ""
+	}
+	return family, strings.TrimSuffix(labels, "}")
+}
+
+func prometheusType(metric any) string {
+	switch metric.(type) {
+	case *Counter:
+		return "counter"
+	case *Gauge:
+		return "gauge"
+	case *Histogram:
+		return "histogram"
+	default:
+		return "untyped"
+	}
+}
+
+func writePrometheusSample(w io.Writer, sample prometheusSample) {
+	switch m := sample.metric.(type) {
+	case *Counter:
+		fmt.Fprintf(w, "%s%s %d\n", sample.family, formatLabels(sample.labels, ""), m.Get())
+	case *Gauge:
+		fmt.Fprintf(w, "%s%s %d\n", sample.family, formatLabels(sample.labels, ""), m.Get())
+	case *Histogram:
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		// Bucket counts are cumulative; the +Inf bucket is last
+		for i := range m.buckets {
+			le := "+Inf"
+			if m.buckets[i].value != -1 {
+				le = strconv.FormatFloat(m.buckets[i].value, 'g', -1, 64)
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", sample.family, formatLabels(sample.labels, `le="`+le+`"`), m.buckets[i].count.Load())
+		}
+		fmt.Fprintf(w, "%s_sum%s %s\n", sample.family, formatLabels(sample.labels, ""), formatPrometheusFloat(m.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", sample.family, formatLabels(sample.labels, ""), m.Count())
+	}
+}
+
+// formatLabels joins label pairs into a label set, e.g. {query="GetUser",le="0.1"}
+func formatLabels(labels, extra string) string {
+	switch {
+	case labels == "" && extra == "":
+		return ""
+	case labels == "":
+		return "{" + extra + "}"
+	case extra == "":
+		return "{" + labels + "}"
+	default:
+		return "{" + labels + "," + extra + "}"
+	}
+}
+
+func formatPrometheusFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
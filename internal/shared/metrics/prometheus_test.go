@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	db := NewMetricsRegistry()
+	db.GetCounter(`database_query_errors_total{query="GetUser"}`).Add(2)
+	db.GetCounter(`database_query_errors_total{query="CreateUser"}`).Inc()
+	db.GetHistogram("database_ping_duration_seconds", []float64{0.1, 1}).Observe(0.5)
+
+	router := NewMetricsRegistry()
+	router.GetGauge("router_connectors_active").Set(3)
+
+	var out strings.Builder
+	if err := WritePrometheus(&out, db, nil, router); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+
+	expected := `# TYPE database_ping_duration_seconds histogram
+database_ping_duration_seconds_bucket{le="0.1"} 0
+database_ping_duration_seconds_bucket{le="1"} 1
+database_ping_duration_seconds_bucket{le="+Inf"} 1
+database_ping_duration_seconds_sum 0.5
+database_ping_duration_seconds_count 1
+# TYPE database_query_errors_total counter
+database_query_errors_total{query="CreateUser"} 1
+database_query_errors_total{query="GetUser"} 2
+# TYPE router_connectors_active gauge
+router_connectors_active 3
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestWritePrometheus_HistogramLabels(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.GetHistogram(`llm_request_duration_seconds{provider="openai"}`, []float64{1}).Observe(2)
+
+	var out strings.Builder
+	if err := WritePrometheus(&out, registry); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+
+	for _, line := range []string{
+		`llm_request_duration_seconds_bucket{provider="openai",le="1"} 0`,
+		`llm_request_duration_seconds_bucket{provider="openai",le="+Inf"} 1`,
+		`llm_request_duration_seconds_sum{provider="openai"} 2`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("Expected line %q in output:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// statsdMaxPacketSize keeps datagrams below the usual MTU so that they are not fragmented
+const statsdMaxPacketSize = 1432
+
+// StatsDExporter exports metrics to a StatsD server over UDP. Counters and the count and sum
+// of histograms are sent as counters with the increase since the previous export, gauges as
+// gauges. With tags, the labels of a metric name are sent as DogStatsD tags (Datadog, Telegraf);
+// without, their values are appended to the metric name, e.g. "llm_requests_total.openai".
+type StatsDExporter struct {
+	address string
+	prefix  string
+	tags    bool
+
+	mu      sync.Mutex
+	last    map[string]int64   // Counter values at the previous export
+	lastSum map[string]float64 // Histogram sums at the previous export
+}
+
+// NewStatsDExporter creates an exporter sending metrics to the StatsD server at address,
+// e.g. "127.0.0.1:8125". prefix is prepended to metric names, e.g. "nexflow.".
+func NewStatsDExporter(address, prefix string, tags bool) *StatsDExporter {
+	return &StatsDExporter{
+		address: address,
+		prefix:  prefix,
+		tags:    tags,
+		last:    make(map[string]int64),
+		lastSum: make(map[string]float64),
+	}
+}
+
+// Export implements Exporter
+func (e *StatsDExporter) Export(ctx context.Context, registries []*MetricsRegistry) error {
+	lines := e.lines(collect(registries))
+	if len(lines) == 0 {
+		return nil
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", e.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to StatsD at %s: %w", e.address, err)
+	}
+	defer conn.Close()
+
+	var packet bytes.Buffer
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := conn.Write(packet.Bytes())
+		packet.Reset()
+		return err
+	}
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsdMaxPacketSize {
+			if err := flush(); err != nil {
+				return fmt.Errorf("failed to send metrics to StatsD: %w", err)
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	if err := flush(); err != nil {
+		return fmt.Errorf("failed to send metrics to StatsD: %w", err)
+	}
+	return nil
+}
+
+// lines returns the StatsD lines of the samples and remembers the exported counter values
+func (e *StatsDExporter) lines(samples []sample) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var lines []string
+	for _, s := range samples {
+		key := s.family + formatStatsDKey(s.labels)
+		name, tags := e.name(s.family, s.labels)
+
+		switch m := s.metric.(type) {
+		case *Counter:
+			if delta := e.delta(key, m.Get()); delta != 0 {
+				lines = append(lines, name+":"+strconv.FormatInt(delta, 10)+"|c"+tags)
+			}
+		case *Gauge:
+			lines = append(lines, name+":"+strconv.FormatInt(m.Get(), 10)+"|g"+tags)
+		case *Histogram:
+			_, _, sum, count := histogramSnapshot(m)
+			if delta := e.delta(key, count); delta != 0 {
+				lines = append(lines, name+".count:"+strconv.FormatInt(delta, 10)+"|c"+tags)
+				lines = append(lines, name+".sum:"+strconv.FormatFloat(sum-e.lastSum[key], 'g', -1, 64)+"|c"+tags)
+			}
+			e.lastSum[key] = sum
+		}
+	}
+	return lines
+}
+
+// delta returns the increase of a counter since the previous export. A counter that went
+// down was reset, so its whole value is the increase.
+func (e *StatsDExporter) delta(key string, value int64) int64 {
+	delta := value - e.last[key]
+	if delta < 0 {
+		delta = value
+	}
+	e.last[key] = value
+	return delta
+}
+
+// name returns the metric name and the tag suffix of a metric
+func (e *StatsDExporter) name(family string, labels []Label) (string, string) {
+	name := e.prefix + family
+	if len(labels) == 0 {
+		return name, ""
+	}
+	if !e.tags {
+		for _, label := range labels {
+			name += "." + sanitizeStatsD(label.Value)
+		}
+		return name, ""
+	}
+	tags := make([]string, len(labels))
+	for i, label := range labels {
+		tags[i] = sanitizeStatsD(label.Name) + ":" + sanitizeStatsD(label.Value)
+	}
+	return name, "|#" + strings.Join(tags, ",")
+}
+
+// formatStatsDKey returns a key identifying the labels of a metric
+func formatStatsDKey(labels []Label) string {
+	var b strings.Builder
+	for _, label := range labels {
+		b.WriteString("," + label.Name + "=" + label.Value)
+	}
+	return b.String()
+}
+
+// sanitizeStatsD replaces the characters with a meaning in the StatsD line format
+func sanitizeStatsD(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', ',', '#', '@', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readStatsD reads a datagram sent to conn
+func readStatsD(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+	buf := make([]byte, statsdMaxPacketSize)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read StatsD packet: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestStatsDExporter_Export(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	registry := NewMetricsRegistry()
+	requests := registry.GetCounter(`llm_requests_total{provider="openai"}`)
+	requests.Add(3)
+	registry.GetGauge("router_messages_in_flight").Set(2)
+	duration := registry.GetHistogram("database_ping_duration_seconds", []float64{0.1})
+	duration.Observe(0.5)
+
+	exporter := NewStatsDExporter(conn.LocalAddr().String(), "nexflow.", true)
+	if err := exporter.Export(context.Background(), []*MetricsRegistry{registry}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	expected := "nexflow.database_ping_duration_seconds.count:1|c\n" +
+		"nexflow.database_ping_duration_seconds.sum:0.5|c\n" +
+		"nexflow.llm_requests_total:3|c|#provider:openai\n" +
+		"nexflow.router_messages_in_flight:2|g"
+	if got := readStatsD(t, conn); got != expected {
+		t.Errorf("Unexpected packet:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	// Counters are sent as the increase since the previous export; unchanged ones are skipped
+	requests.Inc()
+	if err := exporter.Export(context.Background(), []*MetricsRegistry{registry}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	expected = "nexflow.llm_requests_total:1|c|#provider:openai\nnexflow.router_messages_in_flight:2|g"
+	if got := readStatsD(t, conn); got != expected {
+		t.Errorf("Unexpected packet:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	// Without tags the label values become part of the name
+	plain := NewStatsDExporter(conn.LocalAddr().String(), "", false)
+	if err := plain.Export(context.Background(), []*MetricsRegistry{registry}); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if got := readStatsD(t, conn); !strings.Contains(got, "llm_requests_total.openai:4|c") {
+		t.Errorf("Expected the label value in the metric name, got %s", got)
+	}
+}
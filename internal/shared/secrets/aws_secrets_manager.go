@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager. The reference is the name
+// or ARN of the secret, optionally followed by the key of a field of a JSON secret, e.g.
+// "nexflow/prod#jwt_secret".
+//
+// The client is configured like the AWS SDKs and CLI: the region, credentials and endpoint
+// come from the environment (AWS_REGION, AWS_ACCESS_KEY_ID, AWS_PROFILE,
+// AWS_ENDPOINT_URL_SECRETS_MANAGER, ...), the shared configuration files, web identity tokens
+// or the instance and container metadata. It is created when the first secret is resolved.
+type AWSSecretsManagerProvider struct {
+	loadOptions []func(*awsconfig.LoadOptions) error
+
+	mu     sync.Mutex
+	client *secretsmanager.Client
+}
+
+// NewAWSSecretsManagerProvider creates a new AWSSecretsManagerProvider
+//
+// Parameters:
+//   - loadOptions: Options overriding the default AWS configuration, e.g. the region or endpoint
+//
+// Returns:
+//   - *AWSSecretsManagerProvider: Initialized provider
+func NewAWSSecretsManagerProvider(loadOptions ...func(*awsconfig.LoadOptions) error) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{loadOptions: loadOptions}
+}
+
+// Resolve returns the secret, or the field of the JSON secret, a reference points to
+func (p *AWSSecretsManagerProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitKey(ref)
+	if secretID == "" {
+		return "", fmt.Errorf("secret name is required")
+	}
+
+	secret, err := p.getSecretValue(ctx, secretID)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return secret, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getSecretValue calls the GetSecretValue action of Secrets Manager in the region of the
+// secret's ARN, or else of the configuration
+func (p *AWSSecretsManagerProvider) getSecretValue(ctx context.Context, secretID string) (string, error) {
+	client, err := p.getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var optFns []func(*secretsmanager.Options)
+	if arn := strings.Split(secretID, ":"); len(arn) > 3 && arn[0] == "arn" && arn[3] != "" {
+		optFns = append(optFns, func(o *secretsmanager.Options) { o.Region = arn[3] })
+	} else if client.Options().Region == "" {
+		return "", fmt.Errorf("AWS region is not configured, set AWS_REGION")
+	}
+
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)}, optFns...)
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil && out.SecretBinary != nil {
+		return string(out.SecretBinary), nil
+	}
+	return aws.ToString(out.SecretString), nil
+}
+
+// getClient returns the Secrets Manager client, loading the AWS configuration on first use
+func (p *AWSSecretsManagerProvider) getClient(ctx context.Context) (*secretsmanager.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, p.loadOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+		}
+		p.client = secretsmanager.NewFromConfig(cfg)
+	}
+	return p.client, nil
+}
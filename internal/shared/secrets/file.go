@@ -0,0 +1,24 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileProvider reads secrets from files, e.g. Docker and Kubernetes secrets mounted under
+// /run/secrets. The reference is the path of the file; a trailing line break is removed.
+type FileProvider struct{}
+
+// Resolve returns the contents of the file at ref
+func (FileProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("file path is required")
+	}
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
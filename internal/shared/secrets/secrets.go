@@ -0,0 +1,125 @@
+// Package secrets resolves references to secrets kept outside the configuration file, such as
+// ${file:/run/secrets/telegram_token}, ${vault:secret/data/nexflow#openai_key} and
+// ${aws-sm:nexflow/prod#jwt_secret}.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schemes of the built-in providers
+const (
+	SchemeFile  = "file"
+	SchemeVault = "vault"
+	SchemeAWSSM = "aws-sm"
+)
+
+// requestTimeout limits the requests of the providers to secret stores
+const requestTimeout = 10 * time.Second
+
+// Provider reads secrets from a secret store
+type Provider interface {
+	// Resolve returns the secret a reference points to. The reference is the part of
+	// ${scheme:reference} after the scheme.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// ResolveError is returned when a secret reference cannot be resolved
+type ResolveError struct {
+	Scheme string
+	Ref    string
+	Err    error
+}
+
+// Error returns the error message
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("failed to resolve secret ${%s:%s}: %v", e.Scheme, e.Ref, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// Resolver resolves secret references with the provider registered for their scheme.
+// Resolved secrets are cached, so every reference is read from its store once.
+type Resolver struct {
+	providers map[string]Provider
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewResolver creates a new Resolver with the providers by scheme
+func NewResolver(providers map[string]Provider) *Resolver {
+	return &Resolver{
+		providers: providers,
+		cache:     make(map[string]string),
+	}
+}
+
+// NewDefaultResolver creates a Resolver with the file, vault and aws-sm providers, configured
+// by the environment variables of the Vault CLI (VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE) and
+// by the default configuration of the AWS SDK
+func NewDefaultResolver() *Resolver {
+	client := &http.Client{Timeout: requestTimeout}
+	return NewResolver(map[string]Provider{
+		SchemeFile:  FileProvider{},
+		SchemeVault: NewVaultProviderFromEnv(os.Getenv, client),
+		SchemeAWSSM: NewAWSSecretsManagerProvider(),
+	})
+}
+
+// Schemes returns the schemes of the registered providers, sorted
+func (r *Resolver) Schemes() []string {
+	schemes := make([]string, 0, len(r.providers))
+	for scheme := range r.providers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Resolve returns the secret a reference of a scheme points to
+func (r *Resolver) Resolve(ctx context.Context, scheme, ref string) (string, error) {
+	provider, ok := r.providers[scheme]
+	if !ok {
+		return "", &ResolveError{Scheme: scheme, Ref: ref, Err: fmt.Errorf("unknown secret provider")}
+	}
+
+	cacheKey := scheme + ":" + ref
+	r.mu.Lock()
+	value, ok := r.cache[cacheKey]
+	r.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	value, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", &ResolveError{Scheme: scheme, Ref: ref, Err: err}
+	}
+
+	r.mu.Lock()
+	r.cache[cacheKey] = value
+	r.mu.Unlock()
+	return value, nil
+}
+
+// splitKey splits a reference into the secret and the key of a field in it, e.g.
+// "secret/data/nexflow#openai_key" into "secret/data/nexflow" and "openai_key"
+func splitKey(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
@@ -0,0 +1,209 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// countingProvider returns the reference and counts the calls
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	p.calls++
+	if ref == "missing" {
+		return "", errors.New("not found")
+	}
+	return "value-of-" + ref, nil
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	provider := &countingProvider{}
+	resolver := NewResolver(map[string]Provider{"test": provider})
+
+	for i := 0; i < 2; i++ {
+		value, err := resolver.Resolve(context.Background(), "test", "a")
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if value != "value-of-a" {
+			t.Errorf("Expected value-of-a, got %q", value)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("Expected the secret to be read once, got %d calls", provider.calls)
+	}
+
+	var resolveErr *ResolveError
+	_, err := resolver.Resolve(context.Background(), "test", "missing")
+	if !errors.As(err, &resolveErr) || resolveErr.Ref != "missing" {
+		t.Errorf("Expected a ResolveError, got %v", err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "other", "a"); !errors.As(err, &resolveErr) {
+		t.Errorf("Expected a ResolveError for an unknown scheme, got %v", err)
+	}
+
+	if schemes := NewDefaultResolver().Schemes(); strings.Join(schemes, ",") != "aws-sm,file,vault" {
+		t.Errorf("Expected the built-in schemes, got %v", schemes)
+	}
+}
+
+func TestFileProvider_Resolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telegram_token")
+	if err := os.WriteFile(path, []byte("123:abc\n"), 0600); err != nil {
+		t.Fatalf("Failed to write secret: %v", err)
+	}
+
+	value, err := FileProvider{}.Resolve(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if value != "123:abc" {
+		t.Errorf("Expected the trailing line break to be removed, got %q", value)
+	}
+
+	if _, err := (FileProvider{}).Resolve(context.Background(), path+".missing"); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+}
+
+func TestVaultProvider_Resolve(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Vault-Token") != "s.token" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/nexflow":
+			_, _ = io.WriteString(w, `{"data":{"data":{"openai_key":"sk-v2","port":8080},"metadata":{"version":3}}}`)
+		case "/v1/kv/nexflow":
+			_, _ = io.WriteString(w, `{"data":{"openai_key":"sk-v1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"errors":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	env := map[string]string{"VAULT_ADDR": server.URL, "VAULT_TOKEN": "s.token", "VAULT_NAMESPACE": "team"}
+	provider := NewVaultProviderFromEnv(func(name string) string { return env[name] }, server.Client())
+	ctx := context.Background()
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "secret/data/nexflow#openai_key", want: "sk-v2"},
+		{ref: "secret/data/nexflow#port", want: "8080"},
+		{ref: "/kv/nexflow#openai_key", want: "sk-v1"},
+	}
+	for _, tt := range tests {
+		value, err := provider.Resolve(ctx, tt.ref)
+		if err != nil {
+			t.Fatalf("Resolve(%q) failed: %v", tt.ref, err)
+		}
+		if value != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.ref, value, tt.want)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected every secret to be read once, got %d requests", requests)
+	}
+
+	for _, ref := range []string{"secret/data/nexflow", "secret/data/nexflow#missing", "secret/data/other#key"} {
+		if _, err := provider.Resolve(ctx, ref); err == nil {
+			t.Errorf("Expected Resolve(%q) to fail", ref)
+		}
+	}
+
+	unconfigured := NewVaultProviderFromEnv(func(string) string { return "" }, server.Client())
+	if _, err := unconfigured.Resolve(ctx, "secret/data/nexflow#openai_key"); err == nil {
+		t.Error("Expected a provider without VAULT_ADDR to fail")
+	}
+}
+
+func TestAWSSecretsManagerProvider_Resolve(t *testing.T) {
+	var regions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		auth := r.Header.Get("Authorization")
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"InvalidSignatureException","message":"bad signature"}`)
+			return
+		}
+		if scope := strings.Split(auth, "/"); len(scope) > 2 {
+			regions = append(regions, scope[2])
+		}
+		var in struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		switch in.SecretId {
+		case "nexflow/prod":
+			_, _ = io.WriteString(w, `{"SecretString":"{\"jwt_secret\":\"jwt\",\"bot_token\":\"123:abc\"}"}`)
+		case "plain":
+			_, _ = io.WriteString(w, `{"SecretString":"sk-plain"}`)
+		case "arn:aws:secretsmanager:us-east-1:123456789012:secret:binary":
+			_, _ = io.WriteString(w, `{"SecretBinary":"c2stYmluYXJ5"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"ResourceNotFoundException","message":"not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	// The provider is configured from the environment like the AWS SDK, without shared
+	// configuration files or instance metadata
+	for name, value := range map[string]string{
+		"AWS_REGION":                       "eu-west-1",
+		"AWS_ACCESS_KEY_ID":                "AKID",
+		"AWS_SECRET_ACCESS_KEY":            "secret",
+		"AWS_SESSION_TOKEN":                "session",
+		"AWS_ENDPOINT_URL_SECRETS_MANAGER": server.URL,
+		"AWS_CONFIG_FILE":                  filepath.Join(t.TempDir(), "config"),
+		"AWS_SHARED_CREDENTIALS_FILE":      filepath.Join(t.TempDir(), "credentials"),
+		"AWS_EC2_METADATA_DISABLED":        "true",
+		"AWS_MAX_ATTEMPTS":                 "1",
+	} {
+		t.Setenv(name, value)
+	}
+	provider := NewAWSSecretsManagerProvider()
+	ctx := context.Background()
+
+	for ref, want := range map[string]string{
+		"nexflow/prod#jwt_secret": "jwt",
+		"plain":                   "sk-plain",
+		"arn:aws:secretsmanager:us-east-1:123456789012:secret:binary": "sk-binary",
+	} {
+		value, err := provider.Resolve(ctx, ref)
+		if err != nil {
+			t.Fatalf("Resolve(%q) failed: %v", ref, err)
+		}
+		if value != want {
+			t.Errorf("Resolve(%q) = %q, want %q", ref, value, want)
+		}
+	}
+	if !slices.Contains(regions, "us-east-1") || !slices.Contains(regions, "eu-west-1") {
+		t.Errorf("Signed regions = %v, want the configured region and the region of the ARN", regions)
+	}
+
+	for _, ref := range []string{"missing", "nexflow/prod#missing", "plain#key", ""} {
+		if _, err := provider.Resolve(ctx, ref); err == nil {
+			t.Errorf("Expected Resolve(%q) to fail", ref)
+		}
+	}
+}
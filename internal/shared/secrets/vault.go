@@ -0,0 +1,115 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// VaultProvider reads secrets from HashiCorp Vault over its HTTP API. The reference is the
+// API path of the secret and the key of the field, e.g. "secret/data/nexflow#openai_key" for
+// the KV version 2 engine mounted at secret/, or "kv/nexflow#openai_key" for version 1.
+type VaultProvider struct {
+	// Addr is the address of the Vault server, e.g. "https://vault.example.com:8200"
+	Addr string
+
+	// Token is the Vault token requests are made with
+	Token string
+
+	// Namespace is the Vault Enterprise namespace, empty for none
+	Namespace string
+
+	client *http.Client
+
+	mu      sync.Mutex
+	secrets map[string]map[string]any
+}
+
+// NewVaultProviderFromEnv creates a VaultProvider from the VAULT_ADDR, VAULT_TOKEN and
+// VAULT_NAMESPACE environment variables read with getenv
+func NewVaultProviderFromEnv(getenv func(string) string, client *http.Client) *VaultProvider {
+	return &VaultProvider{
+		Addr:      getenv("VAULT_ADDR"),
+		Token:     getenv("VAULT_TOKEN"),
+		Namespace: getenv("VAULT_NAMESPACE"),
+		client:    client,
+		secrets:   make(map[string]map[string]any),
+	}
+}
+
+// Resolve returns the field of the secret a reference points to.
+// Every secret is read once, however many of its fields are referenced.
+func (p *VaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key := splitKey(ref)
+	if path == "" || key == "" {
+		return "", fmt.Errorf("vault reference must be <path>#<key>")
+	}
+	if p.Addr == "" || p.Token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required")
+	}
+
+	fields, err := p.read(ctx, strings.Trim(path, "/"))
+	if err != nil {
+		return "", err
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// read returns the fields of the secret at path
+func (p *VaultProvider) read(ctx context.Context, path string) (map[string]any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if fields, ok := p.secrets[path]; ok {
+		return fields, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.Addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Data   map[string]any `json:"data"`
+		Errors []string       `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("invalid vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.Join(body.Errors, "; "))
+	}
+
+	// The KV version 2 engine nests the fields under data.data, next to data.metadata
+	fields := body.Data
+	if nested, ok := fields["data"].(map[string]any); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = nested
+		}
+	}
+	p.secrets[path] = fields
+	return fields, nil
+}
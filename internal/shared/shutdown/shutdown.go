@@ -0,0 +1,153 @@
+// Package shutdown stops the components of the application in order, e.g. first the HTTP
+// server and the connectors so that no new work is accepted, then the router draining the
+// messages in flight, and last the event bus and the log sinks flushing what is left.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// DefaultStageTimeout limits a stage added without a timeout
+const DefaultStageTimeout = 10 * time.Second
+
+// ErrStageTimeout is the error of a stage that did not finish within its timeout
+var ErrStageTimeout = errors.New("shutdown stage timed out")
+
+// StopFunc stops a component. The context is cancelled when the timeout of the stage expires.
+type StopFunc func(ctx context.Context) error
+
+// stage is a step of the shutdown
+type stage struct {
+	name    string
+	timeout time.Duration
+	stop    StopFunc
+}
+
+// StageResult is the outcome of a stage
+type StageResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error // ErrStageTimeout if the stage timed out
+}
+
+// Report is the outcome of a shutdown
+type Report struct {
+	Stages   []StageResult
+	Duration time.Duration
+}
+
+// Failed returns the stages that failed or timed out
+func (r *Report) Failed() []StageResult {
+	var failed []StageResult
+	for _, result := range r.Stages {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err returns the errors of the failed stages, or nil if all stages completed
+func (r *Report) Err() error {
+	var errs []error
+	for _, result := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// Manager runs the stages of a shutdown in the order they were added, each limited by its
+// own timeout. A stage that fails or times out is reported and the next stage runs anyway,
+// so that a stuck component does not keep the others from flushing their data.
+type Manager struct {
+	logger logging.Logger
+
+	mu     sync.Mutex
+	stages []stage
+	done   bool
+}
+
+// NewManager creates a Manager logging the progress of the shutdown
+func NewManager(logger logging.Logger) *Manager {
+	return &Manager{logger: logger}
+}
+
+// Add adds a stage stopping a component. A non-positive timeout uses DefaultStageTimeout.
+func (m *Manager) Add(name string, timeout time.Duration, stop StopFunc) {
+	if timeout <= 0 {
+		timeout = DefaultStageTimeout
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stages = append(m.stages, stage{name: name, timeout: timeout, stop: stop})
+}
+
+// Run runs the stages and logs the final report. Cancelling ctx cancels the remaining stages.
+// Only the first call runs the stages; later calls return an empty report.
+func (m *Manager) Run(ctx context.Context) *Report {
+	m.mu.Lock()
+	stages := m.stages
+	done := m.done
+	m.done = true
+	m.mu.Unlock()
+
+	report := &Report{}
+	if done {
+		return report
+	}
+
+	started := time.Now()
+	for _, s := range stages {
+		result := m.runStage(ctx, s)
+		report.Stages = append(report.Stages, result)
+		if result.Err != nil {
+			m.logger.Error("shutdown stage failed", "stage", s.name, "duration_ms", result.Duration.Milliseconds(), "error", result.Err)
+		} else {
+			m.logger.Info("shutdown stage completed", "stage", s.name, "duration_ms", result.Duration.Milliseconds())
+		}
+	}
+	report.Duration = time.Since(started)
+
+	args := []any{"stages", len(report.Stages), "duration_ms", report.Duration.Milliseconds()}
+	if failed := report.Failed(); len(failed) > 0 {
+		names := make([]string, 0, len(failed))
+		for _, result := range failed {
+			names = append(names, result.Name)
+		}
+		m.logger.Warn("shutdown completed with failures", append(args, "failed", names)...)
+	} else {
+		m.logger.Info("shutdown completed", args...)
+	}
+	return report
+}
+
+// runStage runs a stage, giving up on it when its timeout expires. A stage that times out
+// keeps running in the background; the process is about to exit anyway.
+func (m *Manager) runStage(ctx context.Context, s stage) StageResult {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- s.stop(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrStageTimeout
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = ctx.Err()
+		}
+	}
+	return StageResult{Name: s.name, Duration: time.Since(started), Err: err}
+}
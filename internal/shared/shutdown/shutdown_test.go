@@ -0,0 +1,79 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func TestManager_Run(t *testing.T) {
+	m := NewManager(logging.NewNoopLogger())
+
+	var order []string
+	record := func(name string, err error) StopFunc {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	m.Add("http", time.Second, record("http", nil))
+	m.Add("router", time.Second, record("router", errors.New("3 messages abandoned")))
+	m.Add("stuck", 20*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(time.Second) // Ignores the context
+		return nil
+	})
+	m.Add("logs", 0, record("logs", nil))
+
+	report := m.Run(context.Background())
+
+	if strings.Join(order, ",") != "http,router,logs" {
+		t.Errorf("Expected the stages to run in order despite failures, got %v", order)
+	}
+	if len(report.Stages) != 4 {
+		t.Fatalf("Expected 4 stage results, got %d", len(report.Stages))
+	}
+	if report.Stages[0].Err != nil || report.Stages[3].Err != nil {
+		t.Errorf("Expected http and logs to complete, got %+v", report.Stages)
+	}
+	if !errors.Is(report.Stages[2].Err, ErrStageTimeout) {
+		t.Errorf("Expected stuck to time out, got %v", report.Stages[2].Err)
+	}
+	if report.Stages[2].Duration >= time.Second {
+		t.Errorf("Expected the timeout to end the stage early, took %v", report.Stages[2].Duration)
+	}
+
+	failed := report.Failed()
+	if len(failed) != 2 || failed[0].Name != "router" || failed[1].Name != "stuck" {
+		t.Errorf("Expected router and stuck to fail, got %+v", failed)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "router: 3 messages abandoned") {
+		t.Errorf("Expected the joined stage errors, got %v", err)
+	}
+
+	// The stages only run once
+	if again := m.Run(context.Background()); len(again.Stages) != 0 {
+		t.Errorf("Expected a second run to be empty, got %+v", again.Stages)
+	}
+}
+
+func TestManager_RunCancelled(t *testing.T) {
+	m := NewManager(logging.NewNoopLogger())
+	m.Add("http", time.Second, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := m.Run(ctx)
+	if !errors.Is(report.Err(), context.Canceled) {
+		t.Errorf("Expected the stage to be cancelled, got %v", report.Err())
+	}
+	if (&Report{}).Err() != nil {
+		t.Error("Expected no error without failed stages")
+	}
+}
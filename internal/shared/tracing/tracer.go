@@ -0,0 +1,415 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpanKind describes the relationship of a span to its parent and children (OTLP values)
+type SpanKind int
+
+const (
+	// SpanKindInternal is an operation within the process
+	SpanKindInternal SpanKind = 1
+	// SpanKindServer handles a request of a remote client, e.g. an HTTP request
+	SpanKindServer SpanKind = 2
+	// SpanKindClient is a request to a remote service, e.g. an LLM provider or the database
+	SpanKindClient SpanKind = 3
+	// SpanKindProducer hands a message to a queue
+	SpanKindProducer SpanKind = 4
+	// SpanKindConsumer processes a message from a queue, e.g. one received by a connector
+	SpanKindConsumer SpanKind = 5
+)
+
+// StatusCode is the status of a span (OTLP values)
+type StatusCode int
+
+const (
+	// StatusUnset is the status of spans that did not fail
+	StatusUnset StatusCode = 0
+	// StatusError is the status of spans that failed
+	StatusError StatusCode = 2
+)
+
+// Attribute is a key-value pair describing a span or an event
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Event is a timestamped annotation of a span, such as a recorded error
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes []Attribute
+}
+
+// SpanData is a finished span as handed to the exporter
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	SpanContext   SpanContext
+	Parent        SpanID // Zero for root spans
+	Start         time.Time
+	End           time.Time
+	Attributes    []Attribute
+	Events        []Event
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Exporter sends finished spans to a tracing backend
+type Exporter interface {
+	// ExportSpans sends a batch of spans
+	ExportSpans(ctx context.Context, spans []SpanData) error
+}
+
+// Config configures a Tracer
+type Config struct {
+	// Exporter receives the finished sampled spans; nil keeps spans for log correlation only
+	Exporter Exporter
+
+	// SampleRatio is the share of new traces that are recorded, from 0 to 1. Traces started
+	// elsewhere follow the sampling decision of their parent.
+	SampleRatio float64
+
+	// BatchSize is the number of spans exported together (default 512)
+	BatchSize int
+
+	// QueueSize is the number of finished spans waiting for export; further spans are dropped
+	// (default 2048)
+	QueueSize int
+
+	// FlushInterval is the longest time a finished span waits for export (default 5s)
+	FlushInterval time.Duration
+
+	// ExportTimeout limits the export of a batch (default 10s)
+	ExportTimeout time.Duration
+
+	// OnError is called with export errors, e.g. to log them; nil ignores them
+	OnError func(error)
+}
+
+// Default values of Config
+const (
+	DefaultBatchSize     = 512
+	DefaultQueueSize     = 2048
+	DefaultFlushInterval = 5 * time.Second
+	DefaultExportTimeout = 10 * time.Second
+)
+
+// Tracer starts spans and exports them in batches in the background.
+// A nil *Tracer is valid and starts no spans, so tracing can be disabled by not creating one.
+type Tracer struct {
+	config  Config
+	queue   chan SpanData
+	flush   chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+// NewTracer creates a Tracer and starts exporting the spans it records
+func NewTracer(config Config) *Tracer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+	if config.ExportTimeout <= 0 {
+		config.ExportTimeout = DefaultExportTimeout
+	}
+
+	t := &Tracer{
+		config: config,
+		queue:  make(chan SpanData, config.QueueSize),
+		flush:  make(chan chan struct{}),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Start starts a span as a child of the span, or remote span context, carried by ctx and
+// returns a copy of ctx carrying the new span. attrs are key-value pairs, as for the logger.
+// The span must be ended with End.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...any) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   kind,
+		start:  time.Now(),
+	}
+	if parent := SpanContextFromContext(ctx); parent.IsValid() {
+		span.sc = SpanContext{TraceID: parent.TraceID, SpanID: newSpanID(), Sampled: parent.Sampled}
+		span.parent = parent.SpanID
+	} else {
+		traceID := newTraceID()
+		span.sc = SpanContext{TraceID: traceID, SpanID: newSpanID(), Sampled: t.sample(traceID)}
+	}
+	span.SetAttributes(attrs...)
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartChild starts a span like Start, but only within a trace: if ctx carries no span or
+// remote span context, no span is started. It suits frequent operations, such as database
+// queries, that are only of interest as part of a larger operation.
+func (t *Tracer) StartChild(ctx context.Context, name string, kind SpanKind, attrs ...any) (context.Context, *Span) {
+	if !SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+	return t.Start(ctx, name, kind, attrs...)
+}
+
+// Dropped returns the number of spans dropped because the export queue was full
+func (t *Tracer) Dropped() int64 {
+	if t == nil {
+		return 0
+	}
+	return t.dropped.Load()
+}
+
+// Flush exports the spans finished so far and waits until they are exported or ctx is done
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	select {
+	case t.flush <- done:
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown exports the remaining spans and stops the tracer. Spans ended afterwards are dropped.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.once.Do(func() { close(t.stop) })
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sample decides whether a new trace is recorded; the decision depends on the trace ID only,
+// so that it is consistent across processes with the same ratio
+func (t *Tracer) sample(traceID TraceID) bool {
+	switch {
+	case t.config.SampleRatio >= 1:
+		return true
+	case t.config.SampleRatio <= 0:
+		return false
+	}
+	bound := uint64(t.config.SampleRatio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < bound
+}
+
+// enqueue queues a finished span for export
+func (t *Tracer) enqueue(data SpanData) {
+	select {
+	case <-t.stop:
+		t.dropped.Add(1)
+		return
+	default:
+	}
+	select {
+	case t.queue <- data:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// run exports the queued spans when a batch is full, the flush interval expires, a flush is
+// requested or the tracer is shut down
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.config.BatchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		t.export(batch)
+		batch = make([]SpanData, 0, t.config.BatchSize)
+	}
+	drain := func() {
+		for {
+			select {
+			case data := <-t.queue:
+				batch = append(batch, data)
+				if len(batch) >= t.config.BatchSize {
+					export()
+				}
+			default:
+				export()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) >= t.config.BatchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case done := <-t.flush:
+			drain()
+			close(done)
+		case <-t.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// export sends a batch to the exporter
+func (t *Tracer) export(batch []SpanData) {
+	if t.config.Exporter == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.config.ExportTimeout)
+	defer cancel()
+	if err := t.config.Exporter.ExportSpans(ctx, batch); err != nil && t.config.OnError != nil {
+		t.config.OnError(fmt.Errorf("failed to export %d spans: %w", len(batch), err))
+	}
+}
+
+// Span is an operation within a trace. The methods of a nil *Span do nothing.
+type Span struct {
+	tracer *Tracer
+	sc     SpanContext
+	parent SpanID
+	name   string
+	kind   SpanKind
+	start  time.Time
+
+	mu            sync.Mutex
+	attrs         []Attribute
+	events        []Event
+	status        StatusCode
+	statusMessage string
+	ended         bool
+}
+
+// SpanContext returns the span context of the span, the zero value for a nil span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// TraceID returns the trace ID of the span as hex digits, empty for a nil span
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.sc.TraceID.String()
+}
+
+// SetAttributes adds attributes given as key-value pairs, as for the logger
+func (s *Span) SetAttributes(attrs ...any) {
+	if s == nil || len(attrs) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, toAttributes(attrs)...)
+}
+
+// RecordError marks the span as failed and adds an exception event; a nil error is ignored
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = StatusError
+	s.statusMessage = err.Error()
+	s.events = append(s.events, Event{
+		Name:       "exception",
+		Time:       time.Now(),
+		Attributes: []Attribute{{Key: "exception.message", Value: err.Error()}},
+	})
+}
+
+// End finishes the span and queues it for export if its trace is sampled.
+// Only the first call has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := SpanData{
+		Name:          s.name,
+		Kind:          s.kind,
+		SpanContext:   s.sc,
+		Parent:        s.parent,
+		Start:         s.start,
+		End:           time.Now(),
+		Attributes:    s.attrs,
+		Events:        s.events,
+		Status:        s.status,
+		StatusMessage: s.statusMessage,
+	}
+	s.mu.Unlock()
+
+	if s.sc.Sampled {
+		s.tracer.enqueue(data)
+	}
+}
+
+// toAttributes converts key-value pairs to attributes; a key without a value gets "!BADKEY"
+// as key, like slog
+func toAttributes(kv []any) []Attribute {
+	attrs := make([]Attribute, 0, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok || i+1 == len(kv) {
+			attrs = append(attrs, Attribute{Key: "!BADKEY", Value: kv[i]})
+			i--
+			continue
+		}
+		attrs = append(attrs, Attribute{Key: key, Value: kv[i+1]})
+	}
+	return attrs
+}
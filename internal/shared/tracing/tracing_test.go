@@ -0,0 +1,223 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingExporter keeps the exported spans
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *recordingExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) exported() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+func TestParseTraceparent(t *testing.T) {
+	const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := ParseTraceparent(header)
+	if err != nil {
+		t.Fatalf("ParseTraceparent failed: %v", err)
+	}
+	if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" || !sc.Sampled {
+		t.Errorf("Unexpected span context %+v", sc)
+	}
+	if sc.Traceparent() != header {
+		t.Errorf("Traceparent() = %q, want %q", sc.Traceparent(), header)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, err := ParseTraceparent(invalid); err == nil {
+			t.Errorf("Expected ParseTraceparent(%q) to fail", invalid)
+		}
+	}
+}
+
+func TestTracer_Start(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{Exporter: exporter, SampleRatio: 1})
+	defer tracer.Shutdown(context.Background())
+
+	// A span in a remote trace continues the trace
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, root := tracer.Start(ContextWithRemoteSpanContext(context.Background(), remote), "message", SpanKindConsumer, "connector", "telegram")
+	if root.SpanContext().TraceID != remote.TraceID {
+		t.Errorf("Expected the remote trace to continue, got %s", root.TraceID())
+	}
+
+	_, child := tracer.StartChild(ctx, "db.GetUser", SpanKindClient)
+	child.RecordError(errors.New("no rows"))
+	child.End()
+	root.End()
+	root.End()
+
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	spans := exporter.exported()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "db.GetUser" || spans[0].Parent != root.SpanContext().SpanID || spans[0].Status != StatusError {
+		t.Errorf("Expected the failed child of the root span, got %+v", spans[0])
+	}
+	if spans[1].Parent != remote.SpanID || spans[1].Attributes[0] != (Attribute{Key: "connector", Value: "telegram"}) {
+		t.Errorf("Expected the root span with its attributes, got %+v", spans[1])
+	}
+
+	// Child spans are only started within a trace
+	if _, span := tracer.StartChild(context.Background(), "db.ListSchedules", SpanKindClient); span != nil {
+		t.Error("Expected no span outside a trace")
+	}
+
+	// A nil tracer and nil spans do nothing
+	var disabled *Tracer
+	ctx, span := disabled.Start(context.Background(), "message", SpanKindConsumer)
+	span.SetAttributes("key", "value")
+	span.RecordError(errors.New("failed"))
+	span.End()
+	if SpanFromContext(ctx) != nil || span.TraceID() != "" {
+		t.Error("Expected a nil tracer to start no span")
+	}
+}
+
+func TestTracer_Sampling(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{Exporter: exporter, SampleRatio: 0})
+	defer tracer.Shutdown(context.Background())
+
+	// Unsampled spans still carry IDs for log correlation, but are not exported
+	ctx, span := tracer.Start(context.Background(), "message", SpanKindConsumer)
+	if !span.SpanContext().IsValid() || span.SpanContext().Sampled {
+		t.Errorf("Expected an unsampled span with IDs, got %+v", span.SpanContext())
+	}
+	_, child := tracer.Start(ctx, "llm.generate", SpanKindClient)
+	child.End()
+	span.End()
+
+	// Sampled remote parents are followed
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, sampled := tracer.Start(ContextWithRemoteSpanContext(context.Background(), remote), "message", SpanKindServer)
+	sampled.End()
+
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if spans := exporter.exported(); len(spans) != 1 || spans[0].SpanContext.TraceID != remote.TraceID {
+		t.Errorf("Expected only the span of the sampled remote trace, got %+v", spans)
+	}
+
+	half := &Tracer{config: Config{SampleRatio: 0.5}}
+	sampledCount := 0
+	for i := 0; i < 1000; i++ {
+		if half.sample(newTraceID()) {
+			sampledCount++
+		}
+	}
+	if sampledCount < 400 || sampledCount > 600 {
+		t.Errorf("Expected about half of the traces to be sampled, got %d of 1000", sampledCount)
+	}
+}
+
+func TestTracer_Shutdown(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(Config{Exporter: exporter, SampleRatio: 1, QueueSize: 2, FlushInterval: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		_, span := tracer.Start(context.Background(), "message", SpanKindConsumer)
+		span.End()
+	}
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if spans := exporter.exported(); len(spans) < 2 || int64(len(spans))+tracer.Dropped() != 3 {
+		t.Errorf("Expected the queued spans to be exported on shutdown, got %d exported and %d dropped", len(spans), tracer.Dropped())
+	}
+
+	_, span := tracer.Start(context.Background(), "message", SpanKindConsumer)
+	span.End()
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Errorf("Expected Flush after Shutdown to return, got %v", err)
+	}
+}
+
+func TestOTLPExporter_ExportSpans(t *testing.T) {
+	var body map[string]any
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		header = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL+"/", "nexflow", map[string]string{"X-Api-Key": "secret"}, server.Client())
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	start := time.Unix(1700000000, 0)
+	err := exporter.ExportSpans(context.Background(), []SpanData{{
+		Name:        "llm.generate",
+		Kind:        SpanKindClient,
+		SpanContext: SpanContext{TraceID: remote.TraceID, SpanID: SpanID{1}, Sampled: true},
+		Parent:      remote.SpanID,
+		Start:       start,
+		End:         start.Add(time.Second),
+		Attributes:  []Attribute{{Key: "llm.provider", Value: "openai"}, {Key: "llm.tokens", Value: 42}},
+		Status:      StatusError,
+	}})
+	if err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	if header.Get("Content-Type") != "application/json" || header.Get("X-Api-Key") != "secret" {
+		t.Errorf("Unexpected request headers %v", header)
+	}
+
+	data, _ := json.Marshal(body)
+	var req otlpTraceRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("Invalid OTLP request: %v", err)
+	}
+	if *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue != "nexflow" {
+		t.Errorf("Expected the service name as resource attribute, got %+v", req.ResourceSpans[0].Resource)
+	}
+	span := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || span.ParentSpanID != "00f067aa0ba902b7" ||
+		span.StartTimeUnixNano != "1700000000000000000" || span.Kind != SpanKindClient || span.Status.Code != StatusError {
+		t.Errorf("Unexpected OTLP span %+v", span)
+	}
+	if *span.Attributes[1].Value.IntValue != "42" {
+		t.Errorf("Expected integer attributes as strings, got %+v", span.Attributes[1])
+	}
+
+	failing := NewOTLPExporter(server.URL+"/other", "nexflow", nil, server.Client())
+	if err := failing.ExportSpans(context.Background(), nil); err == nil {
+		t.Error("Expected an error response to fail the export")
+	}
+}
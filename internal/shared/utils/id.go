@@ -2,7 +2,8 @@ package utils
 
 import "github.com/google/uuid"
 
-// GenerateID generates a new UUID-based ID
+// GenerateID generates a new UUIDv7-based ID, ordered by creation time.
+// Entity IDs are generated with valueobject.GenerateID instead, which tests can make deterministic.
 func GenerateID() string {
-	return uuid.New().String()
+	return uuid.Must(uuid.NewV7()).String()
 }
@@ -0,0 +1,228 @@
+// Package validation validates structs declaratively with `validate` struct tags.
+//
+// A tag lists comma-separated rules, e.g. `validate:"required,max=128"`:
+//   - required: the value must not be empty (zero, empty string, empty slice or map, nil pointer)
+//   - required_without=Field: required unless the sibling field Field is set
+//   - omitempty: skip the remaining rules when the value is empty
+//   - min=N, max=N: bounds for numbers, and for the length of strings, slices and maps
+//   - oneof=a b c: the value must be one of the space-separated words
+//   - rfc3339: the string must be an RFC 3339 time
+//   - cron: the string must be a cron expression (see valueobject.NewCronExpression)
+//
+// Nested structs without a tag are validated recursively. Field names in errors
+// are taken from the json tags, with nested fields joined by dots ("message.content").
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/domain/valueobject"
+)
+
+// FieldError describes a field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`   // JSON name of the field, e.g. "message.content"
+	Rule    string `json:"rule"`    // Rule that failed, e.g. "required"
+	Message string `json:"message"` // Human-readable description, e.g. "message.content is required"
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// Errors is returned by Struct when one or more fields fail validation
+type Errors []FieldError
+
+// Error implements the error interface
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Struct validates a struct or a pointer to a struct.
+// It returns Errors listing every invalid field, or nil if the struct is valid.
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validation: expected a struct, got %s", rv.Kind())
+	}
+
+	var errs Errors
+	validateStruct(rv, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// validateStruct validates the fields of a struct value, appending failures to errs
+func validateStruct(rv reflect.Value, prefix string, errs *Errors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + jsonName(field)
+		value := rv.Field(i)
+		tag := field.Tag.Get("validate")
+
+		if tag == "" {
+			if inner := indirect(value); inner.Kind() == reflect.Struct && inner.Type() != timeType {
+				validateStruct(inner, name+".", errs)
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+
+		validateField(rv, value, name, tag, errs)
+	}
+}
+
+// validateField applies the rules of a tag to a field value
+func validateField(parent, value reflect.Value, name, tag string, errs *Errors) {
+	empty := isEmpty(value)
+
+	for _, rule := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch rule {
+		case "omitempty":
+			if empty {
+				return
+			}
+			continue
+		case "required":
+			if empty {
+				*errs = append(*errs, FieldError{Field: name, Rule: rule, Message: name + " is required"})
+				return
+			}
+			continue
+		case "required_without":
+			other := parent.FieldByName(param)
+			if empty && other.IsValid() && isEmpty(other) {
+				otherName := param
+				if f, ok := parent.Type().FieldByName(param); ok {
+					otherName = jsonName(f)
+				}
+				*errs = append(*errs, FieldError{Field: name, Rule: rule, Message: fmt.Sprintf("%s is required when %s is not set", name, otherName)})
+				return
+			}
+			continue
+		}
+
+		// The remaining rules only apply to values that are set
+		if empty {
+			continue
+		}
+		if msg := checkRule(indirect(value), rule, param); msg != "" {
+			*errs = append(*errs, FieldError{Field: name, Rule: rule, Message: name + " " + msg})
+			return
+		}
+	}
+}
+
+// checkRule checks a value against a rule and returns the failure description, or "" if it passes
+func checkRule(v reflect.Value, rule, param string) string {
+	switch rule {
+	case "min", "max":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validation: invalid %s parameter %q", rule, param))
+		}
+		size, unit := measure(v)
+		if rule == "min" && size < n {
+			return fmt.Sprintf("must be at least %s%s", param, unit)
+		}
+		if rule == "max" && size > n {
+			return fmt.Sprintf("must be at most %s%s", param, unit)
+		}
+	case "oneof":
+		options := strings.Fields(param)
+		s := fmt.Sprint(v.Interface())
+		for _, option := range options {
+			if s == option {
+				return ""
+			}
+		}
+		return "must be one of: " + strings.Join(options, ", ")
+	case "rfc3339":
+		if _, err := time.Parse(time.RFC3339, v.String()); err != nil {
+			return "must be an RFC 3339 time"
+		}
+	case "cron":
+		if _, err := valueobject.NewCronExpression(v.String()); err != nil {
+			return "must be a cron expression: " + strings.TrimPrefix(err.Error(), valueobject.ErrInvalidCronExpression.Error()+": ")
+		}
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", rule))
+	}
+	return ""
+}
+
+// measure returns the number a min or max rule compares: the value of a number,
+// or the length of a string, slice or map, with the unit used in error messages
+func measure(v reflect.Value) (float64, string) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(len([]rune(v.String()))), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), ""
+	default:
+		panic(fmt.Sprintf("validation: min and max do not apply to %s", v.Kind()))
+	}
+}
+
+// isEmpty reports whether a value is unset
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// indirect dereferences pointers down to the underlying value
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// jsonName returns the JSON name of a struct field
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
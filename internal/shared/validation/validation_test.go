@@ -0,0 +1,118 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testMessage struct {
+	Content string `json:"content" validate:"required"`
+}
+
+type testRequest struct {
+	UserID   string      `json:"user_id" validate:"required"`
+	Cron     string      `json:"cron_expression" validate:"required_without=RunAt,cron"`
+	RunAt    string      `json:"run_at,omitempty" validate:"omitempty,rfc3339"`
+	Policy   string      `json:"policy,omitempty" validate:"omitempty,oneof=skip run_once"`
+	Jitter   int         `json:"jitter" validate:"min=0,max=60"`
+	Limit    *int        `json:"limit,omitempty" validate:"omitempty,min=1"`
+	Name     string      `json:"name" validate:"max=5"`
+	Message  testMessage `json:"message"`
+	Metadata map[string]string
+}
+
+func validRequest() testRequest {
+	return testRequest{
+		UserID:  "user-1",
+		Cron:    "* * * * *",
+		Message: testMessage{Content: "hello"},
+	}
+}
+
+func fieldErrors(t *testing.T, err error) Errors {
+	t.Helper()
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected validation.Errors, got %v", err)
+	}
+	return errs
+}
+
+func TestStruct_Valid(t *testing.T) {
+	req := validRequest()
+	if err := Struct(&req); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	req.Cron = ""
+	req.RunAt = "2026-01-02T15:04:05Z"
+	req.Policy = "run_once"
+	if err := Struct(req); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestStruct_Invalid(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name   string
+		modify func(*testRequest)
+		field  string
+		rule   string
+	}{
+		{"required", func(r *testRequest) { r.UserID = "" }, "user_id", "required"},
+		{"required_without", func(r *testRequest) { r.Cron = "" }, "cron_expression", "required_without"},
+		{"rfc3339", func(r *testRequest) { r.RunAt = "tomorrow" }, "run_at", "rfc3339"},
+		{"cron", func(r *testRequest) { r.Cron = "0 24 * * *" }, "cron_expression", "cron"},
+		{"oneof", func(r *testRequest) { r.Policy = "always" }, "policy", "oneof"},
+		{"min", func(r *testRequest) { r.Jitter = -1 }, "jitter", "min"},
+		{"max", func(r *testRequest) { r.Jitter = 61 }, "jitter", "max"},
+		{"pointer", func(r *testRequest) { r.Limit = &zero }, "limit", "min"},
+		{"string length", func(r *testRequest) { r.Name = "abcdef" }, "name", "max"},
+		{"nested", func(r *testRequest) { r.Message.Content = "" }, "message.content", "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.modify(&req)
+
+			errs := fieldErrors(t, Struct(&req))
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %v", errs)
+			}
+			if errs[0].Field != tt.field {
+				t.Errorf("Expected field %q, got %q", tt.field, errs[0].Field)
+			}
+			if errs[0].Rule != tt.rule {
+				t.Errorf("Expected rule %q, got %q", tt.rule, errs[0].Rule)
+			}
+			if !strings.HasPrefix(errs[0].Message, tt.field+" ") {
+				t.Errorf("Expected message to start with the field name, got %q", errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestStruct_ReportsAllFields(t *testing.T) {
+	errs := fieldErrors(t, Struct(&testRequest{}))
+
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", errs)
+	}
+	want := "user_id is required; cron_expression is required when run_at is not set; message.content is required"
+	if errs.Error() != want {
+		t.Errorf("Expected %q, got %q", want, errs.Error())
+	}
+}
+
+func TestStruct_NotAStruct(t *testing.T) {
+	if err := Struct("text"); err == nil {
+		t.Error("Expected an error for a non-struct value")
+	}
+	if err := Struct((*testRequest)(nil)); err != nil {
+		t.Errorf("Expected no error for a nil pointer, got %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+// GitHubAPIURL is the base URL of the GitHub REST API
+const GitHubAPIURL = "https://api.github.com"
+
+// updateCheckTimeout bounds a single request for the latest release
+const updateCheckTimeout = 10 * time.Second
+
+// Release is a published release
+type Release struct {
+	Version     string    `json:"version"` // Without the "v" prefix of the tag
+	URL         string    `json:"url"`     // Release page
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// UpdateStatus is the result of the last update check
+type UpdateStatus struct {
+	Current   string    `json:"current"`
+	Latest    *Release  `json:"latest,omitempty"` // Nil until a check succeeds
+	Available bool      `json:"available"`        // Latest is newer than Current
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+	Error     string    `json:"error,omitempty"` // Error of the last check, if it failed
+}
+
+// CheckerConfig configures a Checker
+type CheckerConfig struct {
+	// Repository is the GitHub repository releases are read from, e.g. "aatumaykin/nexflow"
+	Repository string
+
+	// Interval is how often releases are checked
+	Interval time.Duration
+
+	// APIURL is the base URL of the GitHub API, GitHubAPIURL if empty
+	APIURL string
+}
+
+// Checker periodically checks the GitHub releases of a repository for a version newer than
+// the running one and logs a warning the first time it finds one
+type Checker struct {
+	current string
+	config  CheckerConfig
+	client  *http.Client
+	logger  logging.Logger
+
+	mu       sync.RWMutex
+	status   UpdateStatus
+	notified string // Latest version a warning was logged for
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewChecker creates a Checker for the running version current
+//
+// Parameters:
+//   - current: Version of the running binary, e.g. Get().Version
+//   - config: Repository and interval of the checks
+//   - client: HTTP client, http.DefaultClient if nil
+//   - logger: Logger the checks and available updates are logged to
+//
+// Returns:
+//   - *Checker: Checker, started with Start
+func NewChecker(current string, config CheckerConfig, client *http.Client, logger logging.Logger) *Checker {
+	if config.APIURL == "" {
+		config.APIURL = GitHubAPIURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Checker{
+		current: current,
+		config:  config,
+		client:  client,
+		logger:  logger,
+		status:  UpdateStatus{Current: current},
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start starts checking for updates, the first time right away
+func (c *Checker) Start() error {
+	c.mu.Lock()
+	if c.started {
+		c.mu.Unlock()
+		return fmt.Errorf("update checker already started")
+	}
+	c.started = true
+	c.mu.Unlock()
+
+	c.logger.Info("starting update checker", "repository", c.config.Repository, "interval", c.config.Interval)
+
+	c.wg.Add(1)
+	go c.loop()
+
+	return nil
+}
+
+// Stop stops checking for updates and waits for a running check to finish
+func (c *Checker) Stop() error {
+	c.cancel()
+	c.wg.Wait()
+	return nil
+}
+
+func (c *Checker) loop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Check(c.ctx); err != nil && c.ctx.Err() == nil {
+			c.logger.Warn("update check failed", "error", err)
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check reads the latest release once and returns the updated status. A failed check keeps
+// the release found by the previous one.
+func (c *Checker) Check(ctx context.Context) (UpdateStatus, error) {
+	release, err := LatestRelease(ctx, c.client, c.config.APIURL, c.config.Repository)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.status.CheckedAt = time.Now().UTC()
+	if err != nil {
+		c.status.Error = err.Error()
+		return c.status, err
+	}
+	c.status.Error = ""
+	c.status.Latest = release
+	c.status.Available = IsNewer(release.Version, c.current)
+
+	if c.status.Available && c.notified != release.Version {
+		c.notified = release.Version
+		c.logger.Warn("a newer nexflow version is available", "current", c.current, "latest", release.Version, "url", release.URL)
+	}
+	return c.status, nil
+}
+
+// Status returns the result of the last check
+func (c *Checker) Status() UpdateStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// githubRelease is a release in the GitHub REST API
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// LatestRelease returns the latest release of a GitHub repository; drafts and prereleases
+// are not considered by GitHub
+//
+// Parameters:
+//   - ctx: Context for the request
+//   - client: HTTP client the request is made with
+//   - apiURL: Base URL of the GitHub API, e.g. GitHubAPIURL
+//   - repository: Repository as "owner/name"
+//
+// Returns:
+//   - *Release: Latest release
+//   - error: Error if the request fails or the repository has no release
+func LatestRelease(ctx context.Context, client *http.Client, apiURL, repository string) (*Release, error) {
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+
+	url := strings.TrimSuffix(apiURL, "/") + "/repos/" + repository + "/releases/latest"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read latest release of %s: %w", repository, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("repository %s has no release", repository)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("failed to read latest release of %s: %s: %s", repository, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode latest release of %s: %w", repository, err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("latest release of %s has no tag", repository)
+	}
+	return &Release{
+		Version:     strings.TrimPrefix(release.TagName, "v"),
+		URL:         release.HTMLURL,
+		PublishedAt: release.PublishedAt,
+	}, nil
+}
+
+// IsNewer reports whether the semantic version latest is newer than current, with or without
+// a "v" prefix. A prerelease such as "1.2.0-rc.1" is older than "1.2.0". Versions that are not
+// semantic versions, e.g. "dev", are never newer or older.
+func IsNewer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+
+	for i := range l.core {
+		if l.core[i] != c.core[i] {
+			return l.core[i] > c.core[i]
+		}
+	}
+	// Only a release is newer than its prereleases; prereleases are compared as strings
+	switch {
+	case l.prerelease == c.prerelease:
+		return false
+	case l.prerelease == "":
+		return true
+	case c.prerelease == "":
+		return false
+	default:
+		return l.prerelease > c.prerelease
+	}
+}
+
+// semver is a parsed semantic version
+type semver struct {
+	core       [3]int
+	prerelease string
+}
+
+// parseVersion parses a version like "v1.2.3", "1.2" or "1.2.3-rc.1+build.5"
+func parseVersion(s string) (semver, bool) {
+	var v semver
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, v.prerelease, _ = strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.core[i] = n
+	}
+	return v, true
+}
@@ -0,0 +1,96 @@
+// Package version reports the version of the nexflow binary and checks GitHub releases for
+// newer versions.
+//
+// The version, commit and build date are set at build time, e.g.
+//
+//	go build -ldflags "-X github.com/atumaikin/nexflow/internal/shared/version.Version=1.2.0 \
+//	  -X github.com/atumaikin/nexflow/internal/shared/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/atumaikin/nexflow/internal/shared/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/nexflow
+//
+// (see make build-binary). Without them the commit and build date are taken from the VCS
+// information Go embeds in binaries built from a repository.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Set at build time with -ldflags "-X ..."
+var (
+	// Version is the released version, e.g. "1.2.0"; "dev" for development builds
+	Version = "dev"
+
+	// Commit is the git commit the binary was built from
+	Commit = ""
+
+	// BuildDate is the time the binary was built in RFC 3339, e.g. "2026-05-01T10:00:00Z"
+	BuildDate = ""
+)
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// readBuildInfo returns the build information embedded by the Go toolchain
+var readBuildInfo = debug.ReadBuildInfo
+
+// Get returns the version information of the running binary
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+	// go install ...@v1.2.0 records the module version
+	if info.Version == "dev" && strings.HasPrefix(build.Main.Version, "v") {
+		info.Version = strings.TrimPrefix(build.Main.Version, "v")
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// String returns the version on one line, e.g. "nexflow 1.2.0 (commit 3f2a1b9c, built
+// 2026-05-01T10:00:00Z, go1.25.5)"
+func (i Info) String() string {
+	details := make([]string, 0, 3)
+	if i.Commit != "" {
+		details = append(details, "commit "+shortCommit(i.Commit))
+	}
+	if i.BuildDate != "" {
+		details = append(details, "built "+i.BuildDate)
+	}
+	details = append(details, i.GoVersion)
+	return fmt.Sprintf("nexflow %s (%s)", i.Version, strings.Join(details, ", "))
+}
+
+// shortCommit abbreviates a commit hash to 8 characters
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
@@ -0,0 +1,147 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atumaikin/nexflow/internal/shared/logging"
+)
+
+func TestGet(t *testing.T) {
+	defer func(build func() (*debug.BuildInfo, bool)) { readBuildInfo = build }(readBuildInfo)
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Version: "v1.4.0"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "3f2a1b9c0d4e5f60718293a4b5c6d7e8f9012345"},
+				{Key: "vcs.time", Value: "2026-05-01T10:00:00Z"},
+			},
+		}, true
+	}
+
+	info := Get()
+	if info.Version != "1.4.0" || info.Commit != "3f2a1b9c0d4e5f60718293a4b5c6d7e8f9012345" || info.BuildDate != "2026-05-01T10:00:00Z" {
+		t.Errorf("Expected the build information of the module, got %+v", info)
+	}
+	if got := info.String(); !strings.HasPrefix(got, "nexflow 1.4.0 (commit 3f2a1b9c, built 2026-05-01T10:00:00Z, go") {
+		t.Errorf("Unexpected version string %q", got)
+	}
+
+	// Values set with -ldflags take precedence
+	defer func(v, c string) { Version, Commit = v, c }(Version, Commit)
+	Version, Commit = "1.5.0", "abc"
+	info = Get()
+	if info.Version != "1.5.0" || info.Commit != "abc" || info.BuildDate != "2026-05-01T10:00:00Z" {
+		t.Errorf("Expected the version and commit set at build time, got %+v", info)
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"1.2.0", "1.1.9", true},
+		{"v1.10.0", "1.9.0", true},
+		{"2.0", "1.99.99", true},
+		{"1.2.0", "1.2.0", false},
+		{"v1.2.0", "1.2.0", false},
+		{"1.1.0", "1.2.0", false},
+		{"1.2.0", "1.2.0-rc.1", true},
+		{"1.2.0-rc.2", "1.2.0-rc.1", true},
+		{"1.2.0-rc.1", "1.2.0", false},
+		{"1.2.0+build.5", "1.2.0", false},
+		{"1.2.0", "dev", false},
+		{"latest", "1.2.0", false},
+	}
+	for _, tt := range tests {
+		if got := IsNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	tag := "v1.3.0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/aatumaykin/nexflow/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		if tag == "" {
+			http.Error(w, `{"message":"API rate limit exceeded"}`, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tag_name":"` + tag + `","html_url":"https://github.com/aatumaykin/nexflow/releases/tag/` + tag + `","published_at":"2026-06-01T12:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	checker := NewChecker("1.2.0", CheckerConfig{Repository: "aatumaykin/nexflow", APIURL: server.URL}, server.Client(), logging.NewNoopLogger())
+	if status := checker.Status(); status.Latest != nil || !status.CheckedAt.IsZero() {
+		t.Errorf("Expected no result before the first check, got %+v", status)
+	}
+
+	status, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !status.Available || status.Latest.Version != "1.3.0" || !strings.HasSuffix(status.Latest.URL, "/tag/v1.3.0") || status.CheckedAt.IsZero() {
+		t.Errorf("Expected 1.3.0 to be available, got %+v", status)
+	}
+
+	// A failed check keeps the release found before
+	tag = ""
+	_, err = checker.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden") {
+		t.Fatalf("Expected the API error, got %v", err)
+	}
+	status = checker.Status()
+	if status.Error == "" || status.Latest == nil || status.Latest.Version != "1.3.0" {
+		t.Errorf("Expected the error with the previous release, got %+v", status)
+	}
+
+	// The running version is up to date
+	tag = "v1.2.0"
+	status, err = NewChecker("1.2.0", CheckerConfig{Repository: "aatumaykin/nexflow", APIURL: server.URL}, server.Client(), logging.NewNoopLogger()).Check(context.Background())
+	if err != nil || status.Available {
+		t.Errorf("Expected no update, got %+v, %v", status, err)
+	}
+
+	_, err = LatestRelease(context.Background(), server.Client(), server.URL, "aatumaykin/missing")
+	if err == nil || !strings.Contains(err.Error(), "has no release") {
+		t.Errorf("Expected an error for a repository without releases, got %v", err)
+	}
+}
+
+func TestChecker_StartStop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v9.0.0"}`))
+	}))
+	defer server.Close()
+
+	checker := NewChecker("1.0.0", CheckerConfig{Repository: "aatumaykin/nexflow", Interval: 24 * time.Hour, APIURL: server.URL}, server.Client(), logging.NewNoopLogger())
+	if err := checker.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := checker.Start(); err == nil {
+		t.Error("Expected an error when starting twice")
+	}
+
+	// The first check runs right away
+	deadline := time.Now().Add(5 * time.Second)
+	for checker.Status().CheckedAt.IsZero() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := checker.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if !checker.Status().Available {
+		t.Errorf("Expected 9.0.0 to be available, got %+v", checker.Status())
+	}
+}
@@ -0,0 +1,13 @@
+// Package migrations embeds the versioned SQL migrations of every supported
+// database so that the server binary does not depend on files on disk.
+//
+// Migrations are named NNN_description.{up,down}.sql and live in a directory
+// per database type (sqlite, postgres).
+package migrations
+
+import "embed"
+
+// FS contains the migration files, one directory per database type.
+//
+//go:embed sqlite/*.sql postgres/*.sql
+var FS embed.FS
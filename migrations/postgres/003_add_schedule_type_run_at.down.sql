@@ -0,0 +1,2 @@
+ALTER TABLE schedules DROP COLUMN run_at;
+ALTER TABLE schedules DROP COLUMN schedule_type;
@@ -0,0 +1,3 @@
+-- Schedule type (recurring cron or one-shot) and fire time for one-shot schedules
+ALTER TABLE schedules ADD COLUMN schedule_type TEXT NOT NULL DEFAULT 'cron';
+ALTER TABLE schedules ADD COLUMN run_at TIMESTAMP WITH TIME ZONE;
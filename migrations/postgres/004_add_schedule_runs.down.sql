@@ -0,0 +1,3 @@
+ALTER TABLE schedules DROP COLUMN missed_run_policy;
+DROP INDEX IF EXISTS idx_schedule_runs_schedule_id;
+DROP TABLE IF EXISTS schedule_runs;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_messages_search_vector;
+ALTER TABLE messages DROP COLUMN search_vector;
@@ -0,0 +1,6 @@
+-- Full-text index over message content. The 'simple' configuration does not
+-- stem words, so search behaves the same for every language.
+ALTER TABLE messages ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+CREATE INDEX idx_messages_search_vector ON messages USING GIN (search_vector);
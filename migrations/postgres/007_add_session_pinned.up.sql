@@ -0,0 +1,2 @@
+-- Pinned sessions are excluded from data retention
+ALTER TABLE sessions ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Disabled skills stay registered but cannot be executed
+ALTER TABLE skills ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,11 @@
+-- Persistent event store: every event published on the event bus, in publication order
+CREATE TABLE events (
+    seq BIGSERIAL PRIMARY KEY,
+    id TEXT NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_events_occurred_at ON events(occurred_at);
+CREATE INDEX idx_events_type ON events(type);
@@ -0,0 +1,17 @@
+-- Delivery log of outbound webhooks: one row per event and endpoint, updated after every attempt
+CREATE TABLE webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    endpoint TEXT NOT NULL,
+    url TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_created_at ON webhook_deliveries(created_at);
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint, created_at);
@@ -0,0 +1,8 @@
+-- Incoming connector messages that were already handled, so redelivered duplicates are skipped
+CREATE TABLE processed_messages (
+    key TEXT PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_processed_messages_expires_at ON processed_messages(expires_at);
@@ -0,0 +1,16 @@
+-- Connector messages the router failed to process, kept for inspection and reprocessing
+CREATE TABLE message_dead_letters (
+    id TEXT PRIMARY KEY,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_message_dead_letters_created_at ON message_dead_letters(created_at);
+CREATE INDEX idx_message_dead_letters_connector ON message_dead_letters(connector, created_at);
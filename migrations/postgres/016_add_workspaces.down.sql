@@ -0,0 +1,5 @@
+ALTER TABLE api_keys DROP COLUMN workspace_id;
+ALTER TABLE skills DROP COLUMN workspace_id;
+DROP INDEX IF EXISTS idx_sessions_workspace_id;
+ALTER TABLE sessions DROP COLUMN workspace_id;
+DROP TABLE IF EXISTS workspaces;
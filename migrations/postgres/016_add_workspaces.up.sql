@@ -0,0 +1,17 @@
+-- Workspaces (tenants) of the deployment; connectors are mapped to them in the configuration
+CREATE TABLE workspaces (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    max_tokens INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+INSERT INTO workspaces (id, name, max_tokens, created_at) VALUES ('default', 'Default', 0, CURRENT_TIMESTAMP);
+
+-- Sessions belong to the workspace of the connector they were started through
+ALTER TABLE sessions ADD COLUMN workspace_id TEXT NOT NULL DEFAULT 'default';
+CREATE INDEX idx_sessions_workspace_id ON sessions(workspace_id, created_at);
+
+-- Skills and API keys without a workspace are shared by all workspaces
+ALTER TABLE skills ADD COLUMN workspace_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_keys ADD COLUMN workspace_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+-- Preferences users chose for their replies; empty columns leave the setting to the defaults
+CREATE TABLE user_preferences (
+    user_id TEXT PRIMARY KEY,
+    language TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    timezone TEXT NOT NULL DEFAULT '',
+    verbosity TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
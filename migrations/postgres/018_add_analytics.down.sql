@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS analytics_active_users;
+DROP TABLE IF EXISTS analytics_counters;
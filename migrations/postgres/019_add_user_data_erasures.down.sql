@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_data_erasures_user_id;
+DROP TABLE IF EXISTS user_data_erasures;
@@ -0,0 +1,13 @@
+-- Panics recovered in the router, connectors, skills and HTTP handlers, with their stack traces
+CREATE TABLE crash_reports (
+    id TEXT PRIMARY KEY,
+    component TEXT NOT NULL,
+    operation TEXT NOT NULL DEFAULT '',
+    panic TEXT NOT NULL,
+    stack TEXT NOT NULL DEFAULT '',
+    attributes TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_crash_reports_created_at ON crash_reports(created_at);
+CREATE INDEX idx_crash_reports_component ON crash_reports(component, created_at);
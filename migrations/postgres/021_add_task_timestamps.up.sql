@@ -0,0 +1,3 @@
+-- Times tasks started and finished, used to report queue latency and run duration
+ALTER TABLE tasks ADD COLUMN started_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tasks ADD COLUMN finished_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_document_chunks_session_id;
+DROP TABLE IF EXISTS document_chunks;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_message_jobs_finished_at;
+DROP INDEX IF EXISTS idx_message_jobs_status;
+DROP TABLE IF EXISTS message_jobs;
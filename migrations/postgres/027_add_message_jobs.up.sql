@@ -0,0 +1,18 @@
+-- Messages sent through the asynchronous message API, answered in the background.
+-- Clients poll a job until it is completed with the reply or failed.
+CREATE TABLE message_jobs (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    content TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message_id TEXT NOT NULL DEFAULT '',
+    reply TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_message_jobs_status ON message_jobs(status);
+CREATE INDEX idx_message_jobs_finished_at ON message_jobs(finished_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_message_jobs_batch_id;
+ALTER TABLE message_jobs DROP COLUMN batch_index;
+ALTER TABLE message_jobs DROP COLUMN batch_id;
@@ -0,0 +1,14 @@
+-- Connector messages that did not fit into the incoming buffer of a connector with the spill
+-- overflow policy, fed back to the connector in sequence order once its buffer has room
+CREATE TABLE spilled_messages (
+    id TEXT PRIMARY KEY,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    sequence BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_spilled_messages_connector ON spilled_messages(connector, sequence);
@@ -0,0 +1,2 @@
+-- Titles summarize sessions in listings, generated after the first exchange
+ALTER TABLE sessions ADD COLUMN title TEXT NOT NULL DEFAULT '';
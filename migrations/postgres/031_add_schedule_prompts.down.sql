@@ -0,0 +1,5 @@
+-- Prompt schedules have no skill and cannot be kept
+DELETE FROM schedules WHERE skill IS NULL;
+ALTER TABLE schedules DROP COLUMN session_id;
+ALTER TABLE schedules DROP COLUMN prompt;
+ALTER TABLE schedules ALTER COLUMN skill SET NOT NULL;
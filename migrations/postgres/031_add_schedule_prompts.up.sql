@@ -0,0 +1,4 @@
+-- Schedules run either a skill or an LLM prompt template in a dedicated system session
+ALTER TABLE schedules ALTER COLUMN skill DROP NOT NULL;
+ALTER TABLE schedules ADD COLUMN prompt TEXT NOT NULL DEFAULT '';
+ALTER TABLE schedules ADD COLUMN session_id TEXT NOT NULL DEFAULT '';
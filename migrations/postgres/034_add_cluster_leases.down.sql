@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN leader_only;
+DROP TABLE IF EXISTS leases;
@@ -0,0 +1,4 @@
+-- Full-text search runs on the FTS4 table of SQLite only, so nothing queries the
+-- tsvector column added by 006_add_messages_fts.
+DROP INDEX IF EXISTS idx_messages_search_vector;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;
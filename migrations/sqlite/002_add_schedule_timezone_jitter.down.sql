@@ -0,0 +1,2 @@
+ALTER TABLE schedules DROP COLUMN jitter_seconds;
+ALTER TABLE schedules DROP COLUMN timezone;
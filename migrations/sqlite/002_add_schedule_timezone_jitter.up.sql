@@ -0,0 +1,3 @@
+-- Timezone the cron expression is evaluated in and random start delay
+ALTER TABLE schedules ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+ALTER TABLE schedules ADD COLUMN jitter_seconds INTEGER NOT NULL DEFAULT 0;
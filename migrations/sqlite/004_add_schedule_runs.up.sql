@@ -0,0 +1,17 @@
+-- Run history of schedules, used to detect runs missed while the server was down
+CREATE TABLE schedule_runs (
+    id TEXT PRIMARY KEY,
+    schedule_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    scheduled_at TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    finished_at TEXT,
+    output TEXT,
+    error TEXT,
+    FOREIGN KEY (schedule_id) REFERENCES schedules(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_schedule_runs_schedule_id ON schedule_runs(schedule_id, scheduled_at);
+
+-- What to do with runs missed while the server was down: skip, run_once, run_all
+ALTER TABLE schedules ADD COLUMN missed_run_policy TEXT NOT NULL DEFAULT 'skip';
@@ -0,0 +1,2 @@
+ALTER TABLE schedules DROP COLUMN target_user_id;
+ALTER TABLE schedules DROP COLUMN target_connector;
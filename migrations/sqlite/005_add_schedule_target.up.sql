@@ -0,0 +1,3 @@
+-- Connector and user the output of each scheduled run is delivered to (empty = no delivery)
+ALTER TABLE schedules ADD COLUMN target_connector TEXT NOT NULL DEFAULT '';
+ALTER TABLE schedules ADD COLUMN target_user_id TEXT NOT NULL DEFAULT '';
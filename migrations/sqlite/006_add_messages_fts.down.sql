@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS messages_fts_before_delete;
+DROP TRIGGER IF EXISTS messages_fts_after_update;
+DROP TRIGGER IF EXISTS messages_fts_before_update;
+DROP TRIGGER IF EXISTS messages_fts_after_insert;
+DROP TABLE IF EXISTS messages_fts;
@@ -0,0 +1,23 @@
+-- Full-text index over message content. FTS4 is used because the bundled
+-- go-sqlite3 driver only enables FTS5 with the sqlite_fts5 build tag.
+-- The index stores no copy of the text: it reads content from messages by rowid.
+CREATE VIRTUAL TABLE messages_fts USING fts4(content="messages", content, tokenize=unicode61);
+
+CREATE TRIGGER messages_fts_after_insert AFTER INSERT ON messages BEGIN
+    INSERT INTO messages_fts(docid, content) VALUES (new.rowid, new.content);
+END;
+
+CREATE TRIGGER messages_fts_before_update BEFORE UPDATE ON messages BEGIN
+    DELETE FROM messages_fts WHERE docid = old.rowid;
+END;
+
+CREATE TRIGGER messages_fts_after_update AFTER UPDATE ON messages BEGIN
+    INSERT INTO messages_fts(docid, content) VALUES (new.rowid, new.content);
+END;
+
+CREATE TRIGGER messages_fts_before_delete BEFORE DELETE ON messages BEGIN
+    DELETE FROM messages_fts WHERE docid = old.rowid;
+END;
+
+-- Index messages written before this migration
+INSERT INTO messages_fts(messages_fts) VALUES ('rebuild');
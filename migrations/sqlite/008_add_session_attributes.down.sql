@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_session_attributes_expires_at;
+DROP TABLE IF EXISTS session_attributes;
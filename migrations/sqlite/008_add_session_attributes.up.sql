@@ -0,0 +1,13 @@
+-- Per-session key-value state for skills and the orchestrator, with optional expiry
+CREATE TABLE session_attributes (
+    session_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    expires_at TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (session_id, key),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_session_attributes_expires_at ON session_attributes(expires_at);
@@ -0,0 +1,2 @@
+-- Disabled skills stay registered but cannot be executed
+ALTER TABLE skills ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;
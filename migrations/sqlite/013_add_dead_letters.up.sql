@@ -0,0 +1,15 @@
+-- Events whose subscriber handler kept failing after all retries, kept for inspection and redelivery
+CREATE TABLE dead_letters (
+    id TEXT PRIMARY KEY,
+    subscription TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_dead_letters_created_at ON dead_letters(created_at);
+CREATE INDEX idx_dead_letters_subscription ON dead_letters(subscription, created_at);
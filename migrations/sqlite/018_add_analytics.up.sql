@@ -0,0 +1,16 @@
+-- Daily analytics counters, e.g. the messages a connector received on a UTC day
+CREATE TABLE analytics_counters (
+    day TEXT NOT NULL,
+    metric TEXT NOT NULL,
+    dimension TEXT NOT NULL DEFAULT '',
+    value REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, metric, dimension)
+);
+
+-- Users who sent a message through a connector on a UTC day
+CREATE TABLE analytics_active_users (
+    day TEXT NOT NULL,
+    connector TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (day, connector, user_id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_crash_reports_component;
+DROP INDEX IF EXISTS idx_crash_reports_created_at;
+DROP TABLE IF EXISTS crash_reports;
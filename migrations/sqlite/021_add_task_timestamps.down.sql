@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN finished_at;
+ALTER TABLE tasks DROP COLUMN started_at;
@@ -0,0 +1,2 @@
+-- Custom instructions users pass to the LLM in every session
+ALTER TABLE user_preferences ADD COLUMN instructions TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+-- Text chunks of the documents shared in a session, with their embeddings for retrieval
+CREATE TABLE document_chunks (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    document_id TEXT NOT NULL,
+    document_name TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    embedding TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_document_chunks_session_id ON document_chunks(session_id, created_at, position);
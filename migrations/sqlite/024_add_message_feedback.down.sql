@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_message_feedback_day;
+DROP TABLE IF EXISTS message_feedback;
@@ -0,0 +1,6 @@
+-- Message jobs created together by the batch message API share a batch ID and keep the
+-- position of their prompt in the batch.
+ALTER TABLE message_jobs ADD COLUMN batch_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE message_jobs ADD COLUMN batch_index INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_message_jobs_batch_id ON message_jobs(batch_id, batch_index);
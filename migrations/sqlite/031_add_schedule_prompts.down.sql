@@ -0,0 +1,30 @@
+-- Prompt schedules have no skill and cannot be kept
+DELETE FROM schedules WHERE skill IS NULL;
+
+CREATE TABLE schedules_old (
+    id TEXT PRIMARY KEY,
+    skill TEXT NOT NULL,
+    cron_expression TEXT NOT NULL,
+    input TEXT NOT NULL,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    jitter_seconds INTEGER NOT NULL DEFAULT 0,
+    schedule_type TEXT NOT NULL DEFAULT 'cron',
+    run_at TEXT,
+    missed_run_policy TEXT NOT NULL DEFAULT 'skip',
+    target_connector TEXT NOT NULL DEFAULT '',
+    target_user_id TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (skill) REFERENCES skills(name) ON DELETE CASCADE
+);
+
+INSERT INTO schedules_old (id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id)
+SELECT id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id FROM schedules;
+
+CREATE TABLE schedule_runs_kept AS SELECT * FROM schedule_runs;
+DROP TABLE schedules;
+ALTER TABLE schedules_old RENAME TO schedules;
+INSERT INTO schedule_runs SELECT * FROM schedule_runs_kept;
+DROP TABLE schedule_runs_kept;
+
+CREATE INDEX idx_schedules_skill ON schedules(skill);
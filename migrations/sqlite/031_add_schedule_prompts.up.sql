@@ -0,0 +1,32 @@
+-- Schedules run either a skill or an LLM prompt template in a dedicated system session, so the
+-- skill becomes optional. SQLite cannot drop NOT NULL, so the table is rebuilt; the runs are kept
+-- aside while it is dropped, since dropping it cascades to them.
+CREATE TABLE schedules_new (
+    id TEXT PRIMARY KEY,
+    skill TEXT,
+    cron_expression TEXT NOT NULL,
+    input TEXT NOT NULL,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    jitter_seconds INTEGER NOT NULL DEFAULT 0,
+    schedule_type TEXT NOT NULL DEFAULT 'cron',
+    run_at TEXT,
+    missed_run_policy TEXT NOT NULL DEFAULT 'skip',
+    target_connector TEXT NOT NULL DEFAULT '',
+    target_user_id TEXT NOT NULL DEFAULT '',
+    prompt TEXT NOT NULL DEFAULT '',
+    session_id TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (skill) REFERENCES skills(name) ON DELETE CASCADE
+);
+
+INSERT INTO schedules_new (id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id)
+SELECT id, skill, cron_expression, input, enabled, created_at, timezone, jitter_seconds, schedule_type, run_at, missed_run_policy, target_connector, target_user_id FROM schedules;
+
+CREATE TABLE schedule_runs_kept AS SELECT * FROM schedule_runs;
+DROP TABLE schedules;
+ALTER TABLE schedules_new RENAME TO schedules;
+INSERT INTO schedule_runs SELECT * FROM schedule_runs_kept;
+DROP TABLE schedule_runs_kept;
+
+CREATE INDEX idx_schedules_skill ON schedules(skill);
@@ -0,0 +1,21 @@
+-- Background work of the job queue: schedule runs, asynchronous messages, document ingestion
+-- and webhook deliveries survive restarts. Workers claim due jobs for a lease (locked_until);
+-- jobs whose lease expired are claimed again.
+CREATE TABLE jobs (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT NOT NULL DEFAULT '',
+    run_at TEXT NOT NULL,
+    locked_by TEXT NOT NULL DEFAULT '',
+    locked_until TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    finished_at TEXT
+);
+
+CREATE INDEX idx_jobs_status_run_at ON jobs(status, run_at);
+CREATE INDEX idx_jobs_finished_at ON jobs(finished_at);
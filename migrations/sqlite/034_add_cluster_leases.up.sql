@@ -0,0 +1,11 @@
+-- Leases coordinate the instances sharing the database: the holder of the "leader" lease runs
+-- the scheduler and the connectors that allow a single consumer. A lease is renewed by its
+-- holder before it expires; an expired lease is taken over by another instance.
+CREATE TABLE leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    expires_at TEXT NOT NULL
+);
+
+-- Jobs only the leader can run, e.g. sending through a connector running on the leader
+ALTER TABLE jobs ADD COLUMN leader_only INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,249 @@
+// Package client is a Go client for the nexflow HTTP API.
+//
+// It covers users, sessions, messages, skills and schedules:
+//
+//	c, err := client.New("http://localhost:8080", client.WithAPIKey(os.Getenv("NEXFLOW_API_KEY")))
+//	if err != nil {
+//		return err
+//	}
+//	reply, err := c.SendMessage(ctx, client.SendMessageRequest{UserID: userID, Content: "Hello"})
+//
+// Failed requests return an *APIError with the error code and message of the API.
+// Idempotent requests (GET, PUT, DELETE) are retried on network errors, 429 and 502-504 responses.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the timeout of the default HTTP client
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxRetries is how many times an idempotent request is retried by default
+	DefaultMaxRetries = 2
+
+	// DefaultRetryBackoff is the delay before the first retry; it doubles with every retry
+	DefaultRetryBackoff = 200 * time.Millisecond
+
+	// maxRetryDelay caps the delay between retries, including delays asked for by Retry-After
+	maxRetryDelay = 30 * time.Second
+)
+
+// Client is a client for the nexflow HTTP API. It is safe for concurrent use.
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	apiKey       string
+	bearerToken  string
+	userAgent    string
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIKey authenticates requests with an API key, sent in the X-API-Key header
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithBearerToken authenticates requests with a JWT or API key, sent as "Authorization: Bearer <token>"
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.bearerToken = token
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetries sets how many times idempotent requests are retried and the delay before the first retry.
+// maxRetries 0 disables retries.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// New creates a client for the API served at baseURL, e.g. "http://localhost:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("client: base url must be http or https, got %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      u,
+		httpClient:   &http.Client{Timeout: DefaultTimeout},
+		userAgent:    "nexflow-go-client",
+		maxRetries:   DefaultMaxRetries,
+		retryBackoff: DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	return c, nil
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out (if not nil)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("client: failed to encode request: %w", err)
+		}
+	}
+
+	target := c.baseURL.JoinPath(path)
+	target.RawQuery = query.Encode()
+
+	retries := 0
+	if isIdempotent(method) {
+		retries = c.maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, target.String(), payload)
+		if err == nil && (attempt >= retries || !isRetryableStatus(resp.StatusCode)) {
+			defer resp.Body.Close()
+			return decodeResponse(resp, out)
+		}
+		if err != nil && (attempt >= retries || ctx.Err() != nil) {
+			return err
+		}
+
+		delay := c.retryDelay(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = after
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send sends a single request attempt
+func (c *Client) send(ctx context.Context, method, target string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client: %s %s: %w", method, req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+// retryDelay returns the exponential backoff delay before a retry, with jitter
+func (c *Client) retryDelay(attempt int) time.Duration {
+	delay := c.retryBackoff << attempt
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	// Up to 20% jitter spreads out retries of concurrent clients
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// decodeResponse decodes a successful response into out, or returns an *APIError
+func decodeResponse(resp *http.Response, out any) error {
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newAPIError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("client: failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// isIdempotent reports whether a request with the method can be retried safely
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus reports whether a response status is worth retrying
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay asked for by the Retry-After header of a response, in seconds
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return min(time.Duration(seconds)*time.Second, maxRetryDelay), true
+}
@@ -0,0 +1,308 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithRetries(DefaultMaxRetries, time.Millisecond)}, opts...)
+	c, err := New(server.URL, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	_, err := New("localhost:8080")
+	assert.Error(t, err)
+
+	_, err = New("ftp://localhost")
+	assert.Error(t, err)
+
+	c, err := New("https://nexflow.example.com/api/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://nexflow.example.com/api", c.baseURL.String())
+}
+
+func TestClient_AuthHeaders(t *testing.T) {
+	var apiKey, authorization, userAgent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("X-API-Key")
+		authorization = r.Header.Get("Authorization")
+		userAgent = r.Header.Get("User-Agent")
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "skills": []any{}})
+	}, WithAPIKey("key-1"), WithBearerToken("token-1"), WithUserAgent("test-agent"))
+
+	_, err := c.ListSkills(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", apiKey)
+	assert.Equal(t, "Bearer token-1", authorization)
+	assert.Equal(t, "test-agent", userAgent)
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{
+				"error": map[string]any{"code": CodeUnavailable, "message": "starting up"},
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success": true,
+			"skill":   map[string]any{"id": "skill-1", "name": "echo"},
+		})
+	})
+
+	skill, err := c.GetSkill(context.Background(), "skill-1")
+	require.NoError(t, err)
+	assert.Equal(t, "echo", skill.Name)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}, WithRetries(1, time.Millisecond))
+
+	_, err := c.ListSchedules(context.Background())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, "Bad Gateway", apiErr.Message)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_DoesNotRetryPost(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := c.SendMessage(context.Background(), SendMessageRequest{UserID: "user-1", Content: "hi"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": {"2"}}}
+	delay, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+
+	resp.Header.Set("Retry-After", "3600")
+	delay, ok = retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, maxRetryDelay, delay)
+
+	resp.Header.Del("Retry-After")
+	_, ok = retryAfter(resp)
+	assert.False(t, ok)
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.ListSkills(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient_APIError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": map[string]any{
+				"code":       CodeValidation,
+				"message":    "validation failed",
+				"details":    []map[string]string{{"field": "skill", "rule": "required", "message": "skill is required"}},
+				"request_id": "req-1",
+			},
+		})
+	})
+
+	_, err := c.CreateSchedule(context.Background(), CreateScheduleRequest{CronExpression: "* * * * *"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, CodeValidation, apiErr.Code)
+	assert.Equal(t, "req-1", apiErr.RequestID)
+	assert.Equal(t, "nexflow: 400 validation_failed: validation failed (request req-1)", apiErr.Error())
+	assert.Equal(t, []FieldError{{Field: "skill", Rule: "required", Message: "skill is required"}}, apiErr.FieldErrors())
+}
+
+func TestIsNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"error": map[string]any{"code": CodeNotFound, "message": "skill not found"},
+		})
+	})
+
+	_, err := c.GetSkillByName(context.Background(), "missing")
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsConflict(err))
+	assert.False(t, IsUnauthorized(err))
+	assert.False(t, IsNotFound(errors.New("other")))
+}
+
+func TestClient_ListSessions(t *testing.T) {
+	var path string
+	var query map[string][]string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success": true,
+			"sessions": []map[string]any{{
+				"id":         "session-1",
+				"user_id":    "user/1",
+				"created_at": "2026-01-02T03:04:05Z",
+				"updated_at": "2026-01-02T03:04:05Z",
+				"pinned":     true,
+			}},
+			"next_cursor": "next",
+		})
+	})
+
+	page, err := c.ListUserSessions(context.Background(), "user/1", &ListOptions{
+		Limit: 10,
+		Since: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/users/user%2F1/sessions", path)
+	assert.Equal(t, []string{"10"}, query["limit"])
+	assert.Equal(t, []string{"2026-01-01T00:00:00Z"}, query["since"])
+	assert.NotContains(t, query, "offset")
+
+	require.Len(t, page.Sessions, 1)
+	assert.Equal(t, "session-1", page.Sessions[0].ID)
+	assert.True(t, page.Sessions[0].Pinned)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), page.Sessions[0].CreatedAt)
+	assert.Equal(t, "next", page.NextCursor)
+}
+
+func TestClient_SendMessage(t *testing.T) {
+	var body map[string]any
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/chat/send", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success": true,
+			"message": map[string]any{"id": "msg-2", "role": "assistant", "content": "hello"},
+		})
+	})
+
+	result, err := c.SendMessage(context.Background(), SendMessageRequest{UserID: "user-1", Content: "hi", MaxTokens: 100})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", result.Message.Content)
+	assert.Equal(t, "user-1", body["user_id"])
+	assert.Equal(t, map[string]any{"role": "user", "content": "hi"}, body["message"])
+	assert.Equal(t, map[string]any{"max_tokens": float64(100)}, body["options"])
+}
+
+func TestClient_SearchMessages(t *testing.T) {
+	var query map[string][]string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, map[string]any{"success": true})
+	})
+
+	page, err := c.SearchMessages(context.Background(), "invoice total", &SearchMessagesOptions{SessionID: "session-1"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Messages)
+	assert.Equal(t, []string{"invoice total"}, query["q"])
+	assert.Equal(t, []string{"session-1"}, query["session_id"])
+	assert.NotContains(t, query, "user_id")
+}
+
+func TestClient_ExecuteSkill(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/skills/execute", r.URL.Path)
+		assert.Equal(t, "session-1", r.URL.Query().Get("session_id"))
+		writeJSON(w, http.StatusOK, map[string]any{"success": false, "error": "skill failed"})
+	})
+
+	result, err := c.ExecuteSkill(context.Background(), "session-1", "echo", map[string]any{"text": "hi"})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "skill failed", result.Error)
+}
+
+func TestClient_ScheduleActions(t *testing.T) {
+	var method, path string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success":  true,
+			"schedule": map[string]any{"id": "schedule-1", "type": "once", "run_at": "2026-05-01T10:00:00Z"},
+		})
+	})
+
+	schedule, err := c.PauseSchedule(context.Background(), "schedule-1")
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/schedules/schedule-1/pause", path)
+	require.NotNil(t, schedule.RunAt)
+	assert.Equal(t, time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), *schedule.RunAt)
+
+	enabled := false
+	_, err = c.UpdateSchedule(context.Background(), "schedule-1", UpdateScheduleRequest{Enabled: &enabled})
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/schedules/schedule-1", path)
+}
+
+func TestClient_Users(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users", r.URL.Path)
+		user := map[string]any{"id": "user-1", "channel": "telegram", "channel_id": "42", "created_at": "2026-05-01T10:00:00Z"}
+		if r.Method == http.MethodPost {
+			var req CreateUserRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, CreateUserRequest{Channel: "telegram", ChannelID: "42"}, req)
+			writeJSON(w, http.StatusCreated, map[string]any{"success": true, "user": user})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "users": []any{user}})
+	})
+
+	user, err := c.CreateUser(context.Background(), CreateUserRequest{Channel: "telegram", ChannelID: "42"})
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", user.ID)
+
+	users, err := c.ListUsers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "42", users[0].ChannelID)
+}
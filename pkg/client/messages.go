@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// SendMessage sends a message to the assistant and waits for its reply.
+// The message is added to the latest session of the user, which is created if needed.
+// It is not retried, since a retry could send the message twice.
+func (c *Client) SendMessage(ctx context.Context, req SendMessageRequest) (*SendMessageResult, error) {
+	role := req.Role
+	if role == "" {
+		role = "user"
+	}
+
+	type chatMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type messageOptions struct {
+		Model     string `json:"model,omitempty"`
+		MaxTokens int    `json:"max_tokens,omitempty"`
+	}
+	body := struct {
+		UserID  string         `json:"user_id"`
+		Message chatMessage    `json:"message"`
+		Options messageOptions `json:"options,omitempty"`
+	}{
+		UserID:  req.UserID,
+		Message: chatMessage{Role: role, Content: req.Content},
+		Options: messageOptions{Model: req.Model, MaxTokens: req.MaxTokens},
+	}
+
+	var result SendMessageResult
+	if err := c.do(ctx, http.MethodPost, "/chat/send", nil, body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListMessages returns a page of the messages of a session, oldest first
+func (c *Client) ListMessages(ctx context.Context, sessionID string, opts *ListOptions) (*MessagePage, error) {
+	var page MessagePage
+	if err := c.do(ctx, http.MethodGet, "/sessions/"+url.PathEscape(sessionID)+"/messages", opts.values(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SearchMessages searches the message history for messages containing every word of query
+func (c *Client) SearchMessages(ctx context.Context, query string, opts *SearchMessagesOptions) (*MessagePage, error) {
+	var q url.Values
+	if opts != nil {
+		q = opts.ListOptions.values()
+		if opts.UserID != "" {
+			q.Set("user_id", opts.UserID)
+		}
+		if opts.SessionID != "" {
+			q.Set("session_id", opts.SessionID)
+		}
+	} else {
+		q = url.Values{}
+	}
+	q.Set("q", query)
+
+	var page MessagePage
+	if err := c.do(ctx, http.MethodGet, "/messages/search", q, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
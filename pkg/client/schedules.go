@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type scheduleResponse struct {
+	Schedule *Schedule `json:"schedule"`
+}
+
+// CreateSchedule creates a schedule
+func (c *Client) CreateSchedule(ctx context.Context, req CreateScheduleRequest) (*Schedule, error) {
+	var resp scheduleResponse
+	if err := c.do(ctx, http.MethodPost, "/schedules", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Schedule, nil
+}
+
+// ListSchedules returns all schedules
+func (c *Client) ListSchedules(ctx context.Context) ([]*Schedule, error) {
+	var resp struct {
+		Schedules []*Schedule `json:"schedules"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/schedules", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Schedules, nil
+}
+
+// GetSchedule returns a schedule by ID
+func (c *Client) GetSchedule(ctx context.Context, id string) (*Schedule, error) {
+	var resp scheduleResponse
+	if err := c.do(ctx, http.MethodGet, "/schedules/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Schedule, nil
+}
+
+// UpdateSchedule updates a schedule
+func (c *Client) UpdateSchedule(ctx context.Context, id string, req UpdateScheduleRequest) (*Schedule, error) {
+	var resp scheduleResponse
+	if err := c.do(ctx, http.MethodPut, "/schedules/"+url.PathEscape(id), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Schedule, nil
+}
+
+// EnableSchedule enables a schedule
+func (c *Client) EnableSchedule(ctx context.Context, id string) (*Schedule, error) {
+	return c.scheduleAction(ctx, id, "enable")
+}
+
+// DisableSchedule disables a schedule
+func (c *Client) DisableSchedule(ctx context.Context, id string) (*Schedule, error) {
+	return c.scheduleAction(ctx, id, "disable")
+}
+
+// PauseSchedule stops a schedule from firing without deleting it
+func (c *Client) PauseSchedule(ctx context.Context, id string) (*Schedule, error) {
+	return c.scheduleAction(ctx, id, "pause")
+}
+
+// ResumeSchedule lets a paused schedule fire again from its next activation
+func (c *Client) ResumeSchedule(ctx context.Context, id string) (*Schedule, error) {
+	return c.scheduleAction(ctx, id, "resume")
+}
+
+// RunScheduleNow triggers an immediate run of a schedule, even if it is paused
+func (c *Client) RunScheduleNow(ctx context.Context, id string) (*Schedule, error) {
+	return c.scheduleAction(ctx, id, "run-now")
+}
+
+func (c *Client) scheduleAction(ctx context.Context, id, action string) (*Schedule, error) {
+	var resp scheduleResponse
+	if err := c.do(ctx, http.MethodPost, "/schedules/"+url.PathEscape(id)+"/"+action, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Schedule, nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CreateSession starts a new session for a user
+func (c *Client) CreateSession(ctx context.Context, userID string) (*Session, error) {
+	var resp struct {
+		Session *Session `json:"session"`
+	}
+	body := map[string]string{"user_id": userID}
+	if err := c.do(ctx, http.MethodPost, "/sessions", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
+
+// ListSessions returns a page of the sessions of all users, newest first
+func (c *Client) ListSessions(ctx context.Context, opts *ListOptions) (*SessionPage, error) {
+	var page SessionPage
+	if err := c.do(ctx, http.MethodGet, "/sessions", opts.values(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ListUserSessions returns a page of the sessions of a user, newest first
+func (c *Client) ListUserSessions(ctx context.Context, userID string, opts *ListOptions) (*SessionPage, error) {
+	var page SessionPage
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(userID)+"/sessions", opts.values(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// PinSession protects a session from data retention
+func (c *Client) PinSession(ctx context.Context, sessionID string) (*Session, error) {
+	return c.sessionAction(ctx, sessionID, "pin")
+}
+
+// UnpinSession lets data retention remove the messages of a session again
+func (c *Client) UnpinSession(ctx context.Context, sessionID string) (*Session, error) {
+	return c.sessionAction(ctx, sessionID, "unpin")
+}
+
+func (c *Client) sessionAction(ctx context.Context, sessionID, action string) (*Session, error) {
+	var resp struct {
+		Session *Session `json:"session"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/sessions/"+url.PathEscape(sessionID)+"/"+action, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type skillResponse struct {
+	Skill *Skill `json:"skill"`
+}
+
+// CreateSkill registers a skill
+func (c *Client) CreateSkill(ctx context.Context, req CreateSkillRequest) (*Skill, error) {
+	var resp skillResponse
+	if err := c.do(ctx, http.MethodPost, "/skills", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Skill, nil
+}
+
+// ListSkills returns all registered skills
+func (c *Client) ListSkills(ctx context.Context) ([]*Skill, error) {
+	var resp struct {
+		Skills []*Skill `json:"skills"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/skills", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Skills, nil
+}
+
+// GetSkill returns a skill by ID
+func (c *Client) GetSkill(ctx context.Context, id string) (*Skill, error) {
+	var resp skillResponse
+	if err := c.do(ctx, http.MethodGet, "/skills/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Skill, nil
+}
+
+// GetSkillByName returns a skill by name
+func (c *Client) GetSkillByName(ctx context.Context, name string) (*Skill, error) {
+	var resp skillResponse
+	if err := c.do(ctx, http.MethodGet, "/skills/name/"+url.PathEscape(name), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Skill, nil
+}
+
+// DisableSkill disables a skill: it stays registered but cannot be executed
+func (c *Client) DisableSkill(ctx context.Context, id string) (*Skill, error) {
+	return c.skillAction(ctx, id, "disable")
+}
+
+// EnableSkill enables a disabled skill
+func (c *Client) EnableSkill(ctx context.Context, id string) (*Skill, error) {
+	return c.skillAction(ctx, id, "enable")
+}
+
+func (c *Client) skillAction(ctx context.Context, id, action string) (*Skill, error) {
+	var resp skillResponse
+	if err := c.do(ctx, http.MethodPost, "/skills/"+url.PathEscape(id)+"/"+action, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Skill, nil
+}
+
+// ExecuteSkill runs a skill in a session and waits for its result.
+// A skill that runs but fails returns a result with Success false and no error.
+func (c *Client) ExecuteSkill(ctx context.Context, sessionID, skill string, input map[string]any) (*SkillExecution, error) {
+	body := struct {
+		Skill string         `json:"skill"`
+		Input map[string]any `json:"input"`
+	}{Skill: skill, Input: input}
+
+	var result SkillExecution
+	if err := c.do(ctx, http.MethodPost, "/skills/execute", url.Values{"session_id": {sessionID}}, body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Session is a conversation of a user with the assistant
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Pinned    bool      `json:"pinned"` // Pinned sessions are excluded from data retention
+}
+
+// Message is a message of a session
+type Message struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	Role      string    `json:"role"` // "user", "assistant" or "system"
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// User is a user of a channel
+type User struct {
+	ID        string    `json:"id"`
+	Channel   string    `json:"channel"`    // Channel type: "telegram", "discord", "web", etc.
+	ChannelID string    `json:"channel_id"` // Channel-specific user identifier
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Skill is a registered skill
+type Skill struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Location    string    `json:"location"`    // Path to the skill directory on the server
+	Permissions string    `json:"permissions"` // JSON array of required permissions
+	Metadata    string    `json:"metadata"`    // JSON metadata (timeout, etc.)
+	Disabled    bool      `json:"disabled"`    // Disabled skills cannot be executed
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Schedule runs a skill on a cron schedule or once at a given time
+type Schedule struct {
+	ID              string     `json:"id"`
+	Skill           string     `json:"skill"`
+	Prompt          string     `json:"prompt,omitempty"`     // LLM prompt template sent instead of executing a skill
+	SessionID       string     `json:"session_id,omitempty"` // System session the prompts are sent in
+	CronExpression  string     `json:"cron_expression"`
+	Input           string     `json:"input"` // Input parameters (JSON)
+	Enabled         bool       `json:"enabled"`
+	Timezone        string     `json:"timezone"`
+	JitterSeconds   int        `json:"jitter_seconds"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Type            string     `json:"type"`             // "cron" or "once"
+	RunAt           *time.Time `json:"run_at,omitempty"` // Fire time of a one-shot schedule
+	MissedRunPolicy string     `json:"missed_run_policy"`
+	TargetConnector string     `json:"target_connector,omitempty"`
+	TargetUserID    string     `json:"target_user_id,omitempty"`
+}
+
+// ListOptions pages and filters list requests. The zero value returns the server's default page.
+type ListOptions struct {
+	Limit  int       // Maximum number of items (0 = server default)
+	Offset int       // Number of items to skip
+	Since  time.Time // Only items created at or after this time
+	Until  time.Time // Only items created before this time
+	Cursor string    // NextCursor of the previous page
+}
+
+// values returns the query parameters of the options
+func (o *ListOptions) values() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if !o.Since.IsZero() {
+		q.Set("since", o.Since.UTC().Format(time.RFC3339))
+	}
+	if !o.Until.IsZero() {
+		q.Set("until", o.Until.UTC().Format(time.RFC3339))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	return q
+}
+
+// SessionPage is a page of sessions, newest first
+type SessionPage struct {
+	Sessions   []*Session `json:"sessions"`
+	NextCursor string     `json:"next_cursor"` // Empty on the last page
+}
+
+// MessagePage is a page of messages
+type MessagePage struct {
+	Messages   []*Message `json:"messages"`
+	NextCursor string     `json:"next_cursor"` // Empty on the last page
+}
+
+// SendMessageRequest is a user message sent to the assistant
+type SendMessageRequest struct {
+	UserID    string // User the message is sent by
+	Role      string // Defaults to "user"
+	Content   string
+	Model     string // Overrides the configured LLM model
+	MaxTokens int    // Limits the length of the reply
+}
+
+// SendMessageResult is the reply of the assistant and the conversation it belongs to
+type SendMessageResult struct {
+	Message  *Message   `json:"message"`
+	Messages []*Message `json:"messages"` // Full conversation
+}
+
+// SearchMessagesOptions narrows a message search
+type SearchMessagesOptions struct {
+	UserID    string // Search only the sessions of this user
+	SessionID string // Search only this session
+	ListOptions
+}
+
+// CreateSkillRequest registers a skill
+type CreateSkillRequest struct {
+	Name        string         `json:"name"`
+	Version     string         `json:"version"`
+	Location    string         `json:"location"`
+	Permissions []string       `json:"permissions,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Workspace   string         `json:"workspace,omitempty"` // Limits the skill to a workspace, empty for all workspaces
+}
+
+// CreateUserRequest creates a user
+type CreateUserRequest struct {
+	Channel   string `json:"channel"`
+	ChannelID string `json:"channel_id"`
+}
+
+// SkillExecution is the result of a skill run
+type SkillExecution struct {
+	Success bool   `json:"success"`
+	Output  string `json:"output"`
+	Error   string `json:"error"`
+}
+
+// CreateScheduleRequest creates a schedule. Either CronExpression or RunAt is required.
+type CreateScheduleRequest struct {
+	Skill           string         `json:"skill,omitempty"`
+	Prompt          string         `json:"prompt,omitempty"` // LLM prompt template to send instead of executing a skill
+	CronExpression  string         `json:"cron_expression,omitempty"`
+	RunAt           *time.Time     `json:"run_at,omitempty"`
+	Input           map[string]any `json:"input,omitempty"`
+	Timezone        string         `json:"timezone,omitempty"`
+	JitterSeconds   int            `json:"jitter_seconds,omitempty"`
+	MissedRunPolicy string         `json:"missed_run_policy,omitempty"` // "skip" (default), "run_once" or "run_all"
+	TargetConnector string         `json:"target_connector,omitempty"`
+	TargetUserID    string         `json:"target_user_id,omitempty"`
+}
+
+// UpdateScheduleRequest updates a schedule. Fields left empty or nil are not changed.
+type UpdateScheduleRequest struct {
+	CronExpression  string         `json:"cron_expression,omitempty"`
+	Prompt          string         `json:"prompt,omitempty"` // Only on prompt schedules
+	Input           map[string]any `json:"input,omitempty"`
+	Enabled         *bool          `json:"enabled,omitempty"`
+	Timezone        string         `json:"timezone,omitempty"`
+	JitterSeconds   *int           `json:"jitter_seconds,omitempty"`
+	MissedRunPolicy string         `json:"missed_run_policy,omitempty"`
+	TargetConnector *string        `json:"target_connector,omitempty"`
+	TargetUserID    *string        `json:"target_user_id,omitempty"`
+}
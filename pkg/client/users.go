@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateUser creates a user of a channel
+func (c *Client) CreateUser(ctx context.Context, req CreateUserRequest) (*User, error) {
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// ListUsers returns all users
+func (c *Client) ListUsers(ctx context.Context) ([]*User, error) {
+	var resp struct {
+		Users []*User `json:"users"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/users", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Users, nil
+}